@@ -15,180 +15,310 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/balance": {
+        "/admin/dead-letters": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns balances for all supported currencies",
+                "description": "Returns the transaction events that could not be published to Kafka after all attempts and were not replayed yet, oldest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
+                ],
+                "summary": "List dead-lettered transaction events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of dead letters to return (default 100, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get user balance",
                 "responses": {
                     "200": {
-                        "description": "User balance",
+                        "description": "Dead letters",
                         "schema": {
-                            "$ref": "#/definitions/handlers.BalanceResponse"
+                            "$ref": "#/definitions/handlers.DeadLettersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.BalanceErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.BalanceErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     }
                 }
             }
         },
-        "/exchange": {
+        "/admin/dead-letters/{deadLetterID}/replay": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Exchange funds from one currency to another. Checks user balance and updates it accordingly.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Publishes a dead-lettered transaction event to Kafka again with its event ID so consumers drop duplicates, and marks it replayed. A dead letter is replayed at most once.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "exchange"
+                    "admin"
                 ],
-                "summary": "Exchange currency",
+                "summary": "Replay a dead-lettered transaction event",
                 "parameters": [
                     {
-                        "description": "Exchange Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeRequest"
-                        }
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "deadLetterID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Exchange successful",
+                        "description": "Dead letter replayed",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeResponse"
+                            "$ref": "#/definitions/handlers.DeadLetterResponse"
                         }
                     },
                     "400": {
-                        "description": "Insufficient funds or invalid currencies",
+                        "description": "Invalid dead letter ID",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Dead letter not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Dead letter already replayed",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Kafka unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     }
                 }
             }
         },
-        "/exchange/rates": {
-            "get": {
+        "/admin/impersonate/{userID}": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetches current exchange rates for all supported currencies",
+                "description": "Issues a short-lived token for the user with an act claim identifying the admin. The action is recorded in the audit trail.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "exchange"
+                    "admin"
+                ],
+                "summary": "Impersonate a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get exchange rates",
                 "responses": {
                     "200": {
-                        "description": "Exchange rates",
+                        "description": "Impersonation token issued",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeRatesResponse"
+                            "$ref": "#/definitions/handlers.ImpersonateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeRatesErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve exchange rates",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ExchangeRatesErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     }
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Authenticate user and return JWT token",
-                "consumes": [
-                    "application/json"
+        "/admin/reconciliation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Compares the exchanges executed in a period, with the amounts and rates applied, against the exchanger's records and lists the mismatches for finance. Exchanges not yet delivered to the exchanger are counted as pending. Defaults to the 24 hours ending an hour ago, at most 31 days per request.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "User login",
+                "summary": "Reconcile exchanges with the exchanger",
                 "parameters": [
                     {
-                        "description": "Login Request",
-                        "name": "loginRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Start (RFC 3339), 24 hours before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End (RFC 3339), an hour ago by default",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT token returned",
+                        "description": "Reconciliation report",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginResponse"
+                            "$ref": "#/definitions/handlers.ExchangeReconciliationResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid query parameters",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "401": {
-                        "description": "Invalid username or password",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Reconciliation not configured or exchange service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Exchange service timeout",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     }
                 }
             }
         },
-        "/register": {
+        "/admin/transactions/{transactionID}/reverse": {
             "post": {
-                "description": "Creates a new user account. Ensures unique username and email. Password is hashed before storing.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a compensating transaction that moves the money of the original back and links to it via reversal_of. A deposit is withdrawn, a withdrawal deposited back, and an exchange or a wallet closure with payout exchanged back at the original amounts. A transaction is reversed at most once and reversals cannot be reversed. The reversal is audited and published to Kafka.",
                 "consumes": [
                     "application/json"
                 ],
@@ -196,432 +326,5278 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Reverse a transaction",
                 "parameters": [
                     {
-                        "description": "User registration request",
-                        "name": "registerRequest",
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "transactionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reversal reason",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterRequest"
+                            "$ref": "#/definitions/handlers.ReverseTransactionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "User successfully registered",
+                        "description": "Reversal created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterResponse"
+                            "$ref": "#/definitions/handlers.TransactionEntry"
                         }
                     },
                     "400": {
-                        "description": "Username or email already exists / invalid request",
+                        "description": "Invalid transaction ID, invalid request or insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction already reversed or cannot be reversed",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     }
                 }
             }
         },
-        "/wallet/deposit": {
-            "post": {
+        "/admin/users/{userID}/dormant": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add funds to user wallet. Validates amount and currency. Updates user balance in the database.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Moves the account into cold storage. The override is recorded in the audit trail.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
                 ],
-                "summary": "Deposit funds",
+                "summary": "Flag an account as dormant",
                 "parameters": [
                     {
-                        "description": "Deposit Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.DepositRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Account topped up successfully",
+                        "description": "Account flagged",
                         "schema": {
-                            "$ref": "#/definitions/handlers.DepositResponse"
+                            "$ref": "#/definitions/handlers.DormancyResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid amount or currency",
+                        "description": "Invalid user ID",
                         "schema": {
-                            "$ref": "#/definitions/handlers.DepositErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.DepositErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     }
                 }
-            }
-        },
-        "/wallet/withdraw": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Withdraw funds from user wallet. Validates amount and currency. Checks for sufficient funds.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lifts the cold storage restriction without re-verification. The override is recorded in the audit trail.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
                 ],
-                "summary": "Withdraw funds",
+                "summary": "Clear the dormant flag",
                 "parameters": [
                     {
-                        "description": "Withdraw Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.WithdrawRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Withdrawal successful",
+                        "description": "Account reactivated",
                         "schema": {
-                            "$ref": "#/definitions/handlers.WithdrawResponse"
+                            "$ref": "#/definitions/handlers.DormancyResponse"
                         }
                     },
                     "400": {
-                        "description": "Insufficient funds or invalid amount",
+                        "description": "Invalid user ID",
                         "schema": {
-                            "$ref": "#/definitions/handlers.WithdrawErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.WithdrawErrorResponse"
+                            "$ref": "#/definitions/apperrors.Response"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.BalanceErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Unauthorized",
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.BalanceResponse": {
-            "type": "object",
-            "properties": {
-                "balance": {
-                    "description": "User balances",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/handlers.CurrencyBalance"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
                         }
-                    ]
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
                 }
             }
         },
-        "handlers.CurrencyBalance": {
-            "type": "object",
-            "properties": {
-                "EUR": {
-                    "description": "Balance in EUR\ndefault: 50.0",
-                    "type": "number"
-                },
-                "RUB": {
-                    "description": "Balance in RUB\ndefault: 5000.0",
-                    "type": "number"
-                },
-                "USD": {
-                    "description": "Balance in USD\ndefault: 100.0",
-                    "type": "number"
-                }
-            }
+        "/admin/users/{userID}/limits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the daily and monthly withdrawal and exchange limits of the user with their current usage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get wallet limits of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User limits",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WalletLimitsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{userID}/limits/{currency}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the daily and monthly withdrawal and exchange limits of the user in a currency. A null limit is removed. The change is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set wallet limits of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supported currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetWalletLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User limits after the change",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WalletLimitsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, currency or limit",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{userID}/wallets/{currency}/overdraft": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets how far below zero withdrawals may take the balance of the user's wallet in a currency. Zero removes the overdraft; an overdrawn wallet stays so until it is topped up. Exchanges and holds use only the own funds. The change is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set wallet overdraft limit of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supported currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Overdraft limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetOverdraftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Overdraft limit after the change",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OverdraftResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, currency or limit",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User or wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the webhooks receiving events of all users, without their secrets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List admin webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhooksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers a callback URL notified of the deposits, withdrawals and exchanges of all users. Requests are signed like user webhooks.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Register an admin webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook registered",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or webhook URL",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{webhookID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the admin webhook; events not delivered yet are dropped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete an admin webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns total and available balances for all supported currencies. The available balance excludes funds held by pending holds. Overdraft limits are listed for wallets that have one; their balance may be negative down to minus the limit. Wallets with a label or metadata list them under wallets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get user balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)",
+                        "name": "Api-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User balance",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BalanceResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/balance/total": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Converts the balances of all wallets of the user to the requested currency at current exchange rates, cached ones preferred, and returns the total with the per-currency breakdown and the rates used.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get total balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supported currency code",
+                        "name": "currency",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Total balance",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BalanceTotalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Exchange rate not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Exchange service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Exchange service timeout",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies": {
+            "get": {
+                "description": "Returns the currencies accepted by wallet operations. Balances and exchange rates are keyed by these codes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List supported currencies",
+                "responses": {
+                    "200": {
+                        "description": "Supported currencies",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CurrenciesResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/errors": {
+            "get": {
+                "description": "Returns every machine-readable error code with its HTTP status, the message returned in the \"error\" field and a description. The schema version changes when an entry is removed or altered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "errors"
+                ],
+                "summary": "List API errors",
+                "responses": {
+                    "200": {
+                        "description": "Error catalog",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorCatalogResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opens a server-sent events stream emitting a \"balance\" event with the user's new balances after each deposit, withdrawal or exchange; the event id is the transaction ID. Idle streams receive a comment every 15 seconds. Only the changes made on the instance serving the stream are emitted, and a client that falls behind may miss intermediate events; the latest event always carries the full balances.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Stream balance updates",
+                "responses": {
+                    "200": {
+                        "description": "Stream of balance events",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BalanceEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exchange funds from one currency to another. Checks user balance and updates it accordingly. The fee configured for the currency pair is deducted from the amount before conversion at the rate less the spread. With quote_id, the locked quote is executed at its rate; a quote is used up by the first attempt. With min_expected_amount, the exchange is rejected if it would credit less.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Exchange currency",
+                "parameters": [
+                    {
+                        "description": "Exchange Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExchangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)",
+                        "name": "Api-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exchange successful",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExchangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Insufficient funds, invalid currencies, amount out of range or not covering the fee",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Daily or monthly limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Exchange rate not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Quote expired, rate moved below min_expected_amount or another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Exchange service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Exchange service timeout",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's rate alerts, armed and fired, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "List rate alerts",
+                "responses": {
+                    "200": {
+                        "description": "Rate alerts",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RateAlertsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers an alert firing once the rate of the pair rises above or falls below the threshold. The rates are checked every minute; a fired alert is sent over the notification channels the user enabled and stays fired until it is updated. At most 20 alerts per user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Register a rate alert",
+                "parameters": [
+                    {
+                        "description": "Rate Alert Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateRateAlertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rate alert registered",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RateAlertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, currency pair or alert",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many rate alerts",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/alerts/{alertID}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the direction and threshold of the alert and arms it again, so a fired alert can fire once more.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Update a rate alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rate alert ID",
+                        "name": "alertID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rate Alert Update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateRateAlertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rate alert updated",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RateAlertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, alert ID or alert",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Rate alert not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the rate alert.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Delete a rate alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rate alert ID",
+                        "name": "alertID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Rate alert deleted"
+                    },
+                    "400": {
+                        "description": "Invalid rate alert ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Rate alert not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's past exchanges with the pair, rate applied, fee and resulting balances, newest first. Pass next_cursor from the previous page as cursor to get the next one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Get exchange history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Currency pair, e.g. USD-EUR",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency on either side of the exchange (a supported currency code)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only exchanges at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only exchanges before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of exchanges to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exchange history",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExchangeHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/quote": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the rate, fee and resulting amount of an exchange at the current rate without touching balances, for a confirmation screen before POST /exchange. The rate is locked for a short time: passing quote_id to POST /exchange executes the quote at its rate.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Quote an exchange",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source currency",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target currency",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount to exchange",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exchange quote",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExchangeQuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount or currency, amount out of range or not covering the fee",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Exchange rate not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Exchange service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Exchange service timeout",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetches current exchange rates for all supported currencies with the time they were fetched and their provider. While the exchange service is unavailable or times out, recently fetched rates are returned from the cache with stale_rate set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Get exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "Exchange rates",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExchangeRatesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve exchange rates",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Exchange service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Exchange service timeout",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange/rates/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the open, close, low and high rate of a currency pair per hour or day (UTC), oldest first, for charting. The history holds the rates the wallet fetched from the exchange service; periods without fetched rates are omitted. Defaults to the last 30 days daily or the last 24 hours hourly, at most 366 days or 31 days per request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange"
+                ],
+                "summary": "Get exchange rate history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Currency pair, e.g. USD-EUR",
+                        "name": "pair",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC 3339), 30 days or 24 hours before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End (RFC 3339), now by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "hour or day (default)",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exchange rate history",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExchangeRateHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues an export of the user's ledger. The file is generated in the background; poll GET /exports/{exportID} for the result. Supported formats: accounting_csv (1C-compatible CSV with debit/credit accounts) and user_data_zip (GDPR archive, see POST /me/export).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Request ledger export",
+                "parameters": [
+                    {
+                        "description": "Export Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Export queued",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported export format",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{exportID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the export status. Once the export is completed the generated file is returned as an attachment.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/zip"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Get ledger export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "exportID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export status, or the file when completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid export ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Executes a query or mutation of the wallet schema: balance, rates and transactions queries; deposit, withdraw and exchange mutations. Errors of fields carry the code of the REST API's answer in extensions.code; requests that cannot be parsed or validated are answered with 400 and no data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "Execute a GraphQL request",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result, with the errors of failed fields",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid GraphQL request",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Answers as long as the process serves HTTP, without checking any dependency, so an outage of PostgreSQL, Redis, the exchanger or Kafka does not restart the instance. Readiness is reported by /readyz.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Process is alive",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthzResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login Request",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT token returned",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the status of the latest user data export. Once it is completed the ZIP archive is returned as an attachment.",
+                "produces": [
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Get user data export",
+                "responses": {
+                    "200": {
+                        "description": "Export status, or the archive when completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues a GDPR export of the user's data: a ZIP of JSON files with the profile, wallet balances, all transactions and the login history. The archive is generated in the background; poll GET /me/export for the result.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Request user data export",
+                "responses": {
+                    "202": {
+                        "description": "Export queued",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/logins": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the last successful and failed logins to the account with timestamps, IP addresses, countries and user agents, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get login history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of logins to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login history",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns how the user is notified about security events. Defaults apply until the user saves their own settings.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "Notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NotificationPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces how the user is notified about security events.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "description": "Notification preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NotificationPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification preferences saved",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NotificationPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/reactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-verifies the account password and lifts the cold storage restriction on wallet operations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Reactivate a dormant account",
+                "parameters": [
+                    {
+                        "description": "Re-verification data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account reactivated",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DormancyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid password",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the latest 100 payment requests the user made or was asked to pay, newest first. Pending requests past their expiry are reported as expired.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-requests"
+                ],
+                "summary": "List payment requests",
+                "responses": {
+                    "200": {
+                        "description": "Payment requests",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PaymentRequestsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asks another user, given by username, to pay an amount. The payer is notified with a payment_request.created webhook event and can accept or decline the request until it expires after 7 days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-requests"
+                ],
+                "summary": "Request money",
+                "parameters": [
+                    {
+                        "description": "Payment Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreatePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payment request made",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PaymentRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, currency, payer or note",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{requestID}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Transfers the requested amount from the payer's wallet to the requester's. The payment counts against the payer's limits, is recorded in the transaction history of both users as transfer_out and transfer_in, and the requester is notified with a payment_request.accepted webhook event.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-requests"
+                ],
+                "summary": "Accept a payment request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment request ID",
+                        "name": "requestID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment request accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PaymentRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment request ID or insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Daily or monthly limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment request not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment request is not pending or another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "410": {
+                        "description": "Payment request expired",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{requestID}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Declines the request without paying it. The requester is notified with a payment_request.declined webhook event.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-requests"
+                ],
+                "summary": "Decline a payment request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment request ID",
+                        "name": "requestID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment request declined",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PaymentRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment request ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment request not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment request is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "410": {
+                        "description": "Payment request expired",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks the PostgreSQL and Redis connections, that the gRPC connection to the exchanger is ready and, if events are published to Kafka, that the Kafka cluster answers a metadata request, and reports the status of every dependency. Every check is given its own timeout. Schema drift detected at startup (tables, columns or indexes of the migrations missing from the database) is reported as warnings without failing the probe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Instance is ready",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReadyzResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database, Redis, exchanger or Kafka unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Creates a new user account. Ensures unique username and email. Password is hashed before storing. The email domain may be restricted by block/allow lists and a per-domain registration cap.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration request",
+                        "name": "registerRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User successfully registered",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Username or email already exists / invalid username, password or email",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Email domain is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many registrations from this email domain or too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Open empty wallets in the given currencies. Currencies the user already has a wallet in are skipped, so the request can be repeated. Without it, a wallet is created by the first deposit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Create wallets",
+                "parameters": [
+                    {
+                        "description": "Create Wallet Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWalletRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)",
+                        "name": "Api-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "All wallets already exist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWalletResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Wallets created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWalletResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or currency",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/balance/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's balances at the end of each day, oldest first, for charting. Balances are snapshotted daily (UTC); days before the first snapshot are omitted. Defaults to the last 30 days, at most 366 days per request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get balance history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD), 30 days before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD), today by default",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Balance history",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BalanceHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Close the wallet in the given currency. The remaining balance is converted at the current exchange rate and credited to the to_currency wallet in one atomic operation. An empty wallet can be closed without to_currency.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Close wallet",
+                "parameters": [
+                    {
+                        "description": "Close Wallet Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CloseWalletRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)",
+                        "name": "Api-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallet closed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CloseWalletResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet or exchange rate not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet is not empty, specify to_currency, has pending holds, is overdrawn, or another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Exchange service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Exchange service timeout",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/deposit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add funds to user wallet. Validates amount and currency. Updates user balance in the database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Deposit funds",
+                "parameters": [
+                    {
+                        "description": "Deposit Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DepositRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)",
+                        "name": "Api-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account topped up successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DepositResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, currency or reference",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/holds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserves funds in the wallet until they are captured or released. Held funds reduce the available balance but not the total balance, and count against the user's limits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Place a hold",
+                "parameters": [
+                    {
+                        "description": "Hold Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Hold placed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HoldResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Insufficient funds or invalid amount",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Daily or monthly limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/holds/{holdID}/capture": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraws the held funds from the wallet. The withdrawal is recorded in the transaction history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Capture a hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "holdID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hold captured",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HoldResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hold ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Hold not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold is not pending or another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/holds/{holdID}/release": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels the hold, returning the funds to the available balance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Release a hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold ID",
+                        "name": "holdID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hold released",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HoldResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hold ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Hold not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold is not pending or another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/pots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the pots of all wallets of the user, ordered by currency and creation time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List savings pots",
+                "responses": {
+                    "200": {
+                        "description": "Pots",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PotsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an empty named pot to the user's wallet in the currency. Money moved into a pot stays part of the wallet balance; pots that are not spendable are excluded from the balance withdrawals, exchanges, holds and payments may spend.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Create a savings pot",
+                "parameters": [
+                    {
+                        "description": "Create Pot Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreatePotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Pot created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, currency or pot name",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Pot name already taken",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/pots/move": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the amount between two pots of the user's wallet in the currency. An empty pot ID stands for the unallocated balance: the wallet balance not held and not in any pot. The wallet balance does not change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Move money between pots",
+                "parameters": [
+                    {
+                        "description": "Move Pot Money Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MovePotMoneyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pots after the move",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PotsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, pot ID, pot move, or insufficient funds",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Pot not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/pots/{potID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the pot; its balance returns to the unallocated balance of the wallet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Delete a savings pot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pot ID",
+                        "name": "potID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pot deleted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pot ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Pot not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Includes the pot in or excludes it from the balance withdrawals, exchanges, holds and payments may spend. When spending reaches into spendable pots, they are drawn down, newest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Update a savings pot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pot ID",
+                        "name": "potID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Pot Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdatePotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pot updated",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pot ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Pot not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/receive/qr": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a QR code encoding gwwallet://receive?to=\u003cusername\u003e with the optional amount and currency, so mobile clients can implement scan-to-pay. An amount requires a currency.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get receive QR code",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Amount to pay",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supported currency code",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image format: png (default) or svg",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid amount, currency or format",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's deposits, withdrawals, exchanges, wallet closures and their reversals, newest first. Pass next_cursor from the previous page as cursor to get the next one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get transaction history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only transactions at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only transactions before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency on either side of the operation (a supported currency code)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation type (deposit, withdraw, exchange, close, reversal, transfer_out, transfer_in)",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of transactions to return (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction history",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw funds from user wallet. Validates amount and currency. Checks for sufficient funds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Withdraw funds",
+                "parameters": [
+                    {
+                        "description": "Withdraw Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WithdrawRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)",
+                        "name": "Api-Version",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Withdrawal successful",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WithdrawResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Insufficient funds, invalid amount or invalid reference",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Daily or monthly limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{currency}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the display label and key/value metadata of the user's wallet in a currency. Omitted fields are left as they are; metadata replaces the stored object as a whole. The details are returned with the balance.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Update wallet label and metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supported currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Wallet details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateWalletDetailsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallet details after the change",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WalletDetailsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid currency, request or wallet details",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the user's webhooks without their secrets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhooksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers a callback URL notified of the user's deposits, withdrawals and exchanges. Events are POSTed as JSON with the Webhook-Id, Webhook-Timestamp and Webhook-Signature headers; the signature is \"v1,\" followed by the base64 HMAC-SHA256 of \"id.timestamp.body\" keyed with the returned secret. Failed deliveries are retried with exponential backoff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook registered",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or webhook URL",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhookID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the webhook; events not delivered yet are dropped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apperrors.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apperrors.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code, listed by GET /errors\ndefault: invalid_amount_or_currency",
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Error-specific data, e.g. the status of every dependency of GET /readyz"
+                },
+                "error": {
+                    "description": "Human-readable message of the error, stable within a schema version\ndefault: Invalid amount or currency",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "ID of the request, also returned in the X-Request-ID header\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                }
+            }
+        },
+        "graphql.Error": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "graphql.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Error"
+                    }
+                }
+            }
+        },
+        "handlers.BalanceEvent": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "User balances after the operation, for all supported currencies",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "changed_at": {
+                    "description": "When the balances changed",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "Operation that changed the balances",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.BalanceHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "description": "Balances keyed by currency code",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "date": {
+                    "description": "UTC day\ndefault: 2025-03-14",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.BalanceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "description": "Daily balances, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BalanceHistoryEntry"
+                    }
+                }
+            }
+        },
+        "handlers.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "User balances not held by pending holds",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "balance": {
+                    "description": "User balances",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "overdraft_limits": {
+                    "description": "Overdraft limits of the wallets that have one: withdrawals may take\nthe balance down to minus the limit",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "wallets": {
+                    "description": "Labels and metadata of the wallets that have any, keyed by currency",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.WalletDetailsEntry"
+                    }
+                }
+            }
+        },
+        "handlers.BalanceTotalEntry": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance of the wallet\ndefault: 50.0",
+                    "type": "number"
+                },
+                "converted": {
+                    "description": "Balance in the requested currency\ndefault: 54.0",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency of the wallet\ndefault: EUR",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Rate used for the conversion, 1 for the requested currency itself\ndefault: 1.08",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.BalanceTotalResponse": {
+            "type": "object",
+            "properties": {
+                "breakdown": {
+                    "description": "Non-zero balances with the rates used, ordered by currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BalanceTotalEntry"
+                    }
+                },
+                "currency": {
+                    "description": "Currency of the total\ndefault: USD",
+                    "type": "string"
+                },
+                "stale_rate": {
+                    "description": "Whether a cached rate past its TTL was used because the exchange service was unavailable\ndefault: false",
+                    "type": "boolean"
+                },
+                "total": {
+                    "description": "Sum of the converted balances\ndefault: 154.0",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.CloseWalletRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency of the wallet to close\nrequired: true\ndefault: USD",
+                    "type": "string"
+                },
+                "to_currency": {
+                    "description": "Currency to pay the remaining balance out to; may be omitted if the wallet is empty\ndefault: EUR",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CloseWalletResponse": {
+            "type": "object",
+            "properties": {
+                "credited_amount": {
+                    "description": "Amount credited in to_currency\ndefault: 92.0",
+                    "type": "number"
+                },
+                "message": {
+                    "description": "Success message\ndefault: Wallet closed",
+                    "type": "string"
+                },
+                "new_balance": {
+                    "description": "New balance of the user",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "handlers.CreateExportRequest": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "description": "Export format: accounting_csv or user_data_zip\nrequired: true\ndefault: accounting_csv",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateHoldRequest": {
+            "type": "object",
+            "required": [
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount to hold\nrequired: true\ndefault: 50.0",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency\nrequired: true\ndefault: USD",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreatePaymentRequestRequest": {
+            "type": "object",
+            "required": [
+                "currency",
+                "payer"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Requested amount\nrequired: true\ndefault: 25.0",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency\nrequired: true\ndefault: USD",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "Optional message to the payer, at most 128 characters\ndefault: Dinner on Friday",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "payer": {
+                    "description": "Username of the user asked to pay\nrequired: true\ndefault: bob",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreatePotRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency of the wallet the pot belongs to\nrequired: true\ndefault: USD",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the pot, unique within the wallet, at most 64 characters\nrequired: true\ndefault: Holiday",
+                    "type": "string"
+                },
+                "spendable": {
+                    "description": "Whether withdrawals and exchanges may spend the pot\ndefault: false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.CreateRateAlertRequest": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "description": "Fire when the rate rises above or falls below the threshold\nrequired: true\ndefault: above",
+                    "type": "string"
+                },
+                "pair": {
+                    "description": "Currency pair\nrequired: true\ndefault: USD-EUR",
+                    "type": "string"
+                },
+                "threshold": {
+                    "description": "Rate the alert fires at\nrequired: true\ndefault: 0.95",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.CreateWalletRequest": {
+            "type": "object",
+            "properties": {
+                "currencies": {
+                    "description": "Currencies to open wallets in\nrequired: true\ndefault: [\"USD\",\"EUR\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.CreateWalletResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Currencies of the wallets created by this request; existing wallets are left out",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "description": "Success message\ndefault: Wallets created",
+                    "type": "string"
+                },
+                "new_balance": {
+                    "description": "New balance of the user",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "handlers.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "description": "Callback URL receiving wallet events as signed POST requests\nrequired: true\ndefault: https://example.com/webhooks/wallet",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CurrenciesResponse": {
+            "type": "object",
+            "properties": {
+                "currencies": {
+                    "description": "Supported currencies ordered by code",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.Currency"
+                    }
+                }
+            }
+        },
+        "handlers.Currency": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "ISO 4217 currency code\ndefault: USD",
+                    "type": "string"
+                },
+                "decimals": {
+                    "description": "Decimal places of amounts; exchanges into the currency are rounded to them\ndefault: 2",
+                    "type": "integer"
+                },
+                "min_amount": {
+                    "description": "Smallest amount accepted by wallet operations\ndefault: 0.01",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "Currency name\ndefault: US Dollar",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.DeadLetterResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Failed publishing attempts\ndefault: 3",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Time the event was dead-lettered",
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "ID consumers deduplicate the event by, omitted for events dead-lettered without one\ndefault: 0b7d9c4e-3f1a-5e2b-8c6d-4a9f1e3b7c20",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Dead letter identifier\ndefault: 3b8f1c2d-6a4e-4f7b-9d0c-1e2f3a4b5c6d",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Kafka message key, the user ID\ndefault: 6f1c2a4e-8d0b-4b7a-9c3e-2f5d1a7b9e01",
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "Reason of the last failed attempt\ndefault: dial tcp: connection refused",
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Event as it would have been published: JSON events as is, Avro events as a base64 string",
+                    "type": "object"
+                },
+                "replayed_at": {
+                    "description": "Time of the replay, omitted while pending",
+                    "type": "string"
+                },
+                "topic": {
+                    "description": "Kafka topic the event is published to\ndefault: large-transactions",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.DeadLettersResponse": {
+            "type": "object",
+            "properties": {
+                "dead_letters": {
+                    "description": "Dead letters, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DeadLetterResponse"
+                    }
+                }
+            }
+        },
+        "handlers.DepositRequest": {
+            "type": "object",
+            "required": [
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount to deposit\nrequired: true\ndefault: 100.0",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency\nrequired: true\ndefault: USD",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Client reference for reconciliation, up to 128 characters; returned in the history\ndefault: INV-2024-0042",
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "handlers.DepositResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Success message\ndefault: Account topped up successfully",
+                    "type": "string"
+                },
+                "new_balance": {
+                    "description": "New balance of the user",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "handlers.DormancyResponse": {
+            "type": "object",
+            "properties": {
+                "dormant": {
+                    "description": "Whether the account is now dormant\ndefault: false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ErrorCatalogEntry": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code\ndefault: insufficient_funds_withdraw",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "When the error is returned\ndefault: The balance is lower than the withdrawal amount, or the amount or currency is invalid.",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Value of the \"error\" field of the response\ndefault: Insufficient funds or invalid amount",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "HTTP status of the response\ndefault: 400",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ErrorCatalogResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "Errors ordered by code",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ErrorCatalogEntry"
+                    }
+                },
+                "schema_version": {
+                    "description": "Version of the catalog, incremented on incompatible changes\ndefault: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ExchangeHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Debited amount, including the fee\ndefault: 100.00",
+                    "type": "number"
+                },
+                "fee": {
+                    "description": "Fee in the source currency, omitted for exchanges made before fees were recorded\ndefault: 0.50",
+                    "type": "number"
+                },
+                "from_balance": {
+                    "description": "Balance of the source wallet after the exchange, omitted for older exchanges\ndefault: 400.00",
+                    "type": "number"
+                },
+                "from_currency": {
+                    "description": "Source currency\ndefault: USD",
+                    "type": "string"
+                },
+                "pair": {
+                    "description": "Currency pair\ndefault: USD-EUR",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Rate applied, omitted for exchanges made before rates were recorded\ndefault: 0.92",
+                    "type": "number"
+                },
+                "timestamp": {
+                    "description": "Time of the exchange",
+                    "type": "string"
+                },
+                "to_amount": {
+                    "description": "Credited amount\ndefault: 91.54",
+                    "type": "number"
+                },
+                "to_balance": {
+                    "description": "Balance of the target wallet after the exchange, omitted for older exchanges\ndefault: 191.54",
+                    "type": "number"
+                },
+                "to_currency": {
+                    "description": "Target currency\ndefault: EUR",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "Transaction identifier\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "exchanges": {
+                    "description": "Exchanges, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ExchangeHistoryEntry"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Cursor of the next page, omitted on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeMismatchEntry": {
+            "type": "object",
+            "properties": {
+                "exchanger": {
+                    "description": "Exchanger's record, null if missing at the exchanger",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.ExchangeRecord"
+                        }
+                    ]
+                },
+                "kind": {
+                    "description": "Kind of the mismatch: missing_at_exchanger, unknown_locally, amount or rate\ndefault: rate",
+                    "type": "string"
+                },
+                "local": {
+                    "description": "Local record, null if unknown locally",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.ExchangeRecord"
+                        }
+                    ]
+                },
+                "transaction_id": {
+                    "description": "Transaction identifier\ndefault: 6f1c2a4e-8d0b-4b7a-9c3e-2f5d1a7b9e01",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeQuoteResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount debited in the source currency\ndefault: 100.0",
+                    "type": "number"
+                },
+                "derived_rate": {
+                    "description": "Whether the rate was derived through the pivot currency because the exchange service has no direct rate for the pair\ndefault: false",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "description": "When the quote expires",
+                    "type": "string"
+                },
+                "fee": {
+                    "description": "Fee in the source currency, included in amount and deducted before conversion\ndefault: 0",
+                    "type": "number"
+                },
+                "from_currency": {
+                    "description": "Source currency\ndefault: USD",
+                    "type": "string"
+                },
+                "quote_id": {
+                    "description": "ID to pass to POST /exchange to execute the quote at its rate before it expires",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Rate from the source to the target currency, spread included\ndefault: 0.92",
+                    "type": "number"
+                },
+                "rate_cached": {
+                    "description": "Whether the rate was served from the cache\ndefault: true",
+                    "type": "boolean"
+                },
+                "rate_fetched_at": {
+                    "description": "When the rate was fetched from the provider; for a derived rate, the older of its legs\ndefault: 2025-03-14T09:30:00Z",
+                    "type": "string"
+                },
+                "rate_provider": {
+                    "description": "Rate provider the rate came from: grpc (the exchange service) or http (the fallback rates API)\ndefault: grpc",
+                    "type": "string"
+                },
+                "stale_rate": {
+                    "description": "Whether a cached rate past its TTL was used because the exchange service was unavailable\ndefault: false",
+                    "type": "boolean"
+                },
+                "to_amount": {
+                    "description": "Amount credited in the target currency\ndefault: 92.0",
+                    "type": "number"
+                },
+                "to_currency": {
+                    "description": "Target currency\ndefault: EUR",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeRateHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "description": "Last rate of the period\ndefault: 0.92",
+                    "type": "number"
+                },
+                "high": {
+                    "description": "Highest rate of the period\ndefault: 0.93",
+                    "type": "number"
+                },
+                "low": {
+                    "description": "Lowest rate of the period\ndefault: 0.9",
+                    "type": "number"
+                },
+                "open": {
+                    "description": "First rate of the period\ndefault: 0.91",
+                    "type": "number"
+                },
+                "period": {
+                    "description": "Start of the period, UTC\ndefault: 2025-03-14T00:00:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeRateHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "granularity": {
+                    "description": "Period of the entries: hour or day\ndefault: day",
+                    "type": "string"
+                },
+                "history": {
+                    "description": "Rates per period, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ExchangeRateHistoryEntry"
+                    }
+                },
+                "pair": {
+                    "description": "Currency pair\ndefault: USD-EUR",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "Rates were served from the cache\ndefault: false",
+                    "type": "boolean"
+                },
+                "fetched_at": {
+                    "description": "When the rates were fetched from the provider\ndefault: 2025-03-14T09:30:00Z",
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Rate provider the rates came from: grpc (the exchange service) or http (the fallback rates API)\ndefault: grpc",
+                    "type": "string"
+                },
+                "rates": {
+                    "description": "Exchange rates",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "stale_rate": {
+                    "description": "Rates were served from the cache because the exchange service was unavailable\ndefault: false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ExchangeReconciliationResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "Start of the period, inclusive\ndefault: 2025-03-14T00:00:00Z",
+                    "type": "string"
+                },
+                "matched": {
+                    "description": "Exchanges recorded identically on both sides\ndefault: 120",
+                    "type": "integer"
+                },
+                "mismatches": {
+                    "description": "Exchanges that disagree",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ExchangeMismatchEntry"
+                    }
+                },
+                "pending": {
+                    "description": "Exchanges not yet delivered to the exchanger\ndefault: 2",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "End of the period, exclusive\ndefault: 2025-03-15T00:00:00Z",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeRecord": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Debited amount\ndefault: 100.0",
+                    "type": "number"
+                },
+                "executed_at": {
+                    "description": "Time of the conversion",
+                    "type": "string"
+                },
+                "from_currency": {
+                    "description": "Debited currency\ndefault: USD",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Applied exchange rate\ndefault: 0.9",
+                    "type": "number"
+                },
+                "to_amount": {
+                    "description": "Credited amount\ndefault: 90.0",
+                    "type": "number"
+                },
+                "to_currency": {
+                    "description": "Credited currency\ndefault: EUR",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount to exchange, required without quote_id\ndefault: 100.0",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "from_currency": {
+                    "description": "Source currency, required without quote_id\ndefault: USD",
+                    "type": "string"
+                },
+                "min_expected_amount": {
+                    "description": "Least amount to receive in the target currency, optional. If the rate moved so that\nless would be received, the exchange is rejected with 409 instead of executed.\ndefault: 91.5",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "quote_id": {
+                    "description": "ID of a quote from GET /exchange/quote to execute at its rate. The currencies\nand amount may then be omitted; if set, they must match the quote.",
+                    "type": "string"
+                },
+                "to_currency": {
+                    "description": "Target currency, required without quote_id\ndefault: EUR",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExchangeResponse": {
+            "type": "object",
+            "properties": {
+                "derived_rate": {
+                    "description": "Whether the rate was derived through the pivot currency because the exchange service has no direct rate for the pair\ndefault: false",
+                    "type": "boolean"
+                },
+                "exchanged_amount": {
+                    "description": "Amount received after exchange\ndefault: 85.0",
+                    "type": "number"
+                },
+                "fee": {
+                    "description": "Fee in the source currency, deducted from the amount before conversion\ndefault: 0",
+                    "type": "number"
+                },
+                "message": {
+                    "description": "Success message\ndefault: Exchange successful",
+                    "type": "string"
+                },
+                "new_balance": {
+                    "description": "New balance after exchange",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "rate": {
+                    "description": "Rate the amount less the fee was converted at, spread included\ndefault: 0.85",
+                    "type": "number"
+                },
+                "rate_cached": {
+                    "description": "Whether the rate was served from the cache\ndefault: true",
+                    "type": "boolean"
+                },
+                "rate_fetched_at": {
+                    "description": "When the rate was fetched from the provider; for a derived rate, the older of its legs\ndefault: 2025-03-14T09:30:00Z",
+                    "type": "string"
+                },
+                "rate_provider": {
+                    "description": "Rate provider the rate came from: grpc (the exchange service) or http (the fallback rates API)\ndefault: grpc",
+                    "type": "string"
+                },
+                "stale_rate": {
+                    "description": "Whether a cached rate past its TTL was used because the exchange service was unavailable\ndefault: false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ExportStatusResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Failure reason, set when the export failed",
+                    "type": "string"
+                },
+                "export_id": {
+                    "description": "Export identifier",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Processing status: pending, processing, completed or failed\ndefault: pending",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HealthzResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "Liveness status\ndefault: ok",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HoldResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Held amount\ndefault: 50.0",
+                    "type": "number"
+                },
+                "created_at": {
+                    "description": "Time the hold was placed",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency code\ndefault: USD",
+                    "type": "string"
+                },
+                "hold_id": {
+                    "description": "Hold ID\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Hold status (pending, captured, released)\ndefault: pending",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Time of the last status change",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImpersonateResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "Token lifetime in seconds\ndefault: 900",
+                    "type": "integer"
+                },
+                "token": {
+                    "description": "Short-lived JWT token acting as the user\ndefault: JWT_TOKEN",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginEntry": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "description": "Client country code, omitted if unknown\ndefault: NL",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "Client IP address\ndefault: 203.0.113.7",
+                    "type": "string"
+                },
+                "success": {
+                    "description": "Whether the login succeeded\ndefault: true",
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "description": "Time of the attempt",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "description": "Client User-Agent\ndefault: Mozilla/5.0",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "logins": {
+                    "description": "Logins, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LoginEntry"
+                    }
+                }
+            }
         },
-        "handlers.CurrencyBalanceAfterDeposit": {
+        "handlers.LoginRequest": {
             "type": "object",
             "properties": {
-                "EUR": {
-                    "description": "Balance in EUR\ndefault: 50.0",
-                    "type": "number"
+                "password": {
+                    "description": "Password\nrequired: true\ndefault: secret123",
+                    "type": "string"
                 },
-                "RUB": {
-                    "description": "Balance in RUB\ndefault: 5000.0",
+                "username": {
+                    "description": "Username\nrequired: true\ndefault: john_doe",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "description": "JWT token\ndefault: JWT_TOKEN",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MovePotMoneyRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount to move\nrequired: true\ndefault: 25.0",
                     "type": "number"
                 },
-                "USD": {
-                    "description": "Balance in USD\ndefault: 100.0",
-                    "type": "number"
+                "currency": {
+                    "description": "Currency of the wallet\nrequired: true\ndefault: USD",
+                    "type": "string"
+                },
+                "from_pot_id": {
+                    "description": "Pot to take the money from, empty for the unallocated balance",
+                    "type": "string"
+                },
+                "to_pot_id": {
+                    "description": "Pot to put the money in, empty for the unallocated balance\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
                 }
             }
         },
-        "handlers.CurrencyBalanceAfterWithdraw": {
+        "handlers.NotificationPreferences": {
             "type": "object",
             "properties": {
-                "EUR": {
-                    "description": "Balance in EUR\ndefault: 50.0",
-                    "type": "number"
+                "email_enabled": {
+                    "description": "Deliver notifications by email\ndefault: true",
+                    "type": "boolean"
                 },
-                "RUB": {
-                    "description": "Balance in RUB\ndefault: 5000.0",
-                    "type": "number"
+                "phone": {
+                    "description": "Phone number for SMS\ndefault: +79990000000",
+                    "type": "string"
                 },
-                "USD": {
-                    "description": "Balance in USD\ndefault: 100.0",
-                    "type": "number"
+                "security_alerts": {
+                    "description": "Notify about logins from a new country or device\ndefault: true",
+                    "type": "boolean"
+                },
+                "sms_enabled": {
+                    "description": "Deliver notifications by SMS, requires phone\ndefault: false",
+                    "type": "boolean"
                 }
             }
         },
-        "handlers.DepositErrorResponse": {
+        "handlers.OverdraftResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Invalid amount or currency",
+                "currency": {
+                    "description": "Currency code\ndefault: USD",
                     "type": "string"
+                },
+                "overdraft_limit": {
+                    "description": "How far below zero withdrawals may take the balance\ndefault: 500.0",
+                    "type": "number"
                 }
             }
         },
-        "handlers.DepositRequest": {
+        "handlers.PaymentRequestResponse": {
             "type": "object",
             "properties": {
                 "amount": {
-                    "description": "Amount to deposit\nrequired: true\ndefault: 100.0",
+                    "description": "Requested amount\ndefault: 25.0",
                     "type": "number"
                 },
+                "created_at": {
+                    "description": "Time the request was made",
+                    "type": "string"
+                },
                 "currency": {
-                    "description": "Currency\nrequired: true\ndefault: USD",
+                    "description": "Currency code\ndefault: USD",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Time after which the request can no longer be accepted",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "Message to the payer\ndefault: Dinner on Friday",
+                    "type": "string"
+                },
+                "payer_id": {
+                    "description": "User asked to pay",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "Payment request ID\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                },
+                "requester_id": {
+                    "description": "User asking for the money",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Request status (pending, accepted, declined, expired)\ndefault: pending",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "Transaction of the payment, accepted requests only",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Time of the last status change",
                     "type": "string"
                 }
             }
         },
-        "handlers.DepositResponse": {
+        "handlers.PaymentRequestsResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Success message\ndefault: Account topped up successfully",
+                "payment_requests": {
+                    "description": "Latest payment requests the user made or was asked to pay, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PaymentRequestResponse"
+                    }
+                }
+            }
+        },
+        "handlers.PotResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Amount set aside in the pot\ndefault: 25.0",
+                    "type": "number"
+                },
+                "created_at": {
+                    "description": "Time the pot was created",
                     "type": "string"
                 },
-                "new_balance": {
-                    "description": "New balance of the user",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/handlers.CurrencyBalanceAfterDeposit"
-                        }
-                    ]
+                "currency": {
+                    "description": "Currency of the wallet the pot belongs to\ndefault: USD",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the pot\ndefault: Holiday",
+                    "type": "string"
+                },
+                "pot_id": {
+                    "description": "Pot ID\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                },
+                "spendable": {
+                    "description": "Whether withdrawals and exchanges may spend the pot\ndefault: false",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "description": "Time of the last change",
+                    "type": "string"
                 }
             }
         },
-        "handlers.ExchangeErrorResponse": {
+        "handlers.PotsResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Insufficient funds or invalid currencies",
-                    "type": "string"
+                "pots": {
+                    "description": "Pots ordered by currency and creation time",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PotResponse"
+                    }
                 }
             }
         },
-        "handlers.ExchangeRates": {
+        "handlers.RateAlertResponse": {
             "type": "object",
             "properties": {
-                "EUR": {
-                    "description": "EUR exchange rate\ndefault: 0.85",
-                    "type": "number"
+                "alert_id": {
+                    "description": "Alert ID\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Registration time",
+                    "type": "string"
+                },
+                "direction": {
+                    "description": "above or below\ndefault: above",
+                    "type": "string"
+                },
+                "pair": {
+                    "description": "Currency pair\ndefault: USD-EUR",
+                    "type": "string"
                 },
-                "RUB": {
-                    "description": "RUB exchange rate\ndefault: 90.0",
+                "threshold": {
+                    "description": "Rate the alert fires at\ndefault: 0.95",
                     "type": "number"
                 },
-                "USD": {
-                    "description": "USD exchange rate\ndefault: 1.0",
+                "triggered_at": {
+                    "description": "When the alert fired, absent while it is armed",
+                    "type": "string"
+                },
+                "triggered_rate": {
+                    "description": "Rate the alert fired at\ndefault: 0.9512",
                     "type": "number"
                 }
             }
         },
-        "handlers.ExchangeRatesErrorResponse": {
+        "handlers.RateAlertsResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Failed to retrieve exchange rates",
-                    "type": "string"
+                "alerts": {
+                    "description": "Alerts, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RateAlertResponse"
+                    }
                 }
             }
         },
-        "handlers.ExchangeRatesResponse": {
+        "handlers.ReactivateRequest": {
             "type": "object",
             "properties": {
-                "rates": {
-                    "description": "Exchange rates",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/handlers.ExchangeRates"
-                        }
-                    ]
+                "password": {
+                    "description": "Current account password\nrequired: true\ndefault: strongpassword123",
+                    "type": "string"
                 }
             }
         },
-        "handlers.ExchangeRequest": {
+        "handlers.ReadyzResponse": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "Amount to exchange\nrequired: true\ndefault: 100.0",
-                    "type": "number"
+                "checks": {
+                    "description": "Status of every dependency: ok or unavailable\ndefault: {\"postgres\": \"ok\", \"redis\": \"ok\", \"exchanger\": \"ok\", \"kafka\": \"ok\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "from_currency": {
-                    "description": "Source currency\nrequired: true\ndefault: USD",
+                "status": {
+                    "description": "Readiness status\ndefault: ok",
                     "type": "string"
                 },
-                "to_currency": {
-                    "description": "Target currency\nrequired: true\ndefault: EUR",
-                    "type": "string"
+                "warnings": {
+                    "description": "Differences between the live schema and the migrations, empty if none.\nThey do not make the instance unready.\ndefault: [\"missing index idx_wallet_holds_user_id on wallet_holds\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "handlers.ExchangeResponse": {
+        "handlers.RegisterRequest": {
             "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
             "properties": {
-                "exchanged_amount": {
-                    "description": "Amount received after exchange\ndefault: 85.0",
-                    "type": "number"
+                "email": {
+                    "description": "Email\nrequired: true\ndefault: john@example.com",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
+                    "description": "Password, 6 to 72 characters\nrequired: true\ndefault: secret123",
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 6
                 },
+                "username": {
+                    "description": "Username\nrequired: true\ndefault: john_doe",
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "handlers.RegisterResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
-                    "description": "Success message\ndefault: Exchange successful",
+                    "description": "Success message\ndefault: User registered successfully",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ReverseTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Why the transaction is reversed, recorded in the audit trail\nrequired: true\ndefault: Duplicate payment",
                     "type": "string"
-                },
-                "new_balance": {
-                    "description": "New balance after exchange",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/handlers.ExchangedBalance"
-                        }
-                    ]
                 }
             }
         },
-        "handlers.ExchangedBalance": {
+        "handlers.SetOverdraftRequest": {
             "type": "object",
             "properties": {
-                "EUR": {
-                    "description": "Balance in EUR\ndefault: 50.0",
+                "overdraft_limit": {
+                    "description": "How far below zero withdrawals may take the balance, 0 removes the overdraft\nrequired: true\ndefault: 500.0",
                     "type": "number"
-                },
-                "RUB": {
-                    "description": "Balance in RUB\ndefault: 5000.0",
+                }
+            }
+        },
+        "handlers.SetWalletLimitRequest": {
+            "type": "object",
+            "properties": {
+                "daily_limit": {
+                    "description": "Limit over the last 24 hours, null removes it\ndefault: 1000.0",
                     "type": "number"
                 },
-                "USD": {
-                    "description": "Balance in USD\ndefault: 100.0",
+                "monthly_limit": {
+                    "description": "Limit over the last 30 days, null removes it\ndefault: 10000.0",
                     "type": "number"
                 }
             }
         },
-        "handlers.LoginErrorResponse": {
+        "handlers.TransactionEntry": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Invalid username or password",
+                "amount": {
+                    "description": "Operation amount\ndefault: 100.00",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Currency of the operation, the source currency for exchanges\ndefault: USD",
+                    "type": "string"
+                },
+                "operation": {
+                    "description": "Operation type: deposit, withdraw, exchange, close, reversal, transfer_out or transfer_in\ndefault: deposit",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Client reference, deposits and withdrawals made with one only\ndefault: INV-2024-0042",
+                    "type": "string"
+                },
+                "reversal_of": {
+                    "description": "Reversed transaction, reversals only\ndefault: 1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Time of the operation",
+                    "type": "string"
+                },
+                "to_amount": {
+                    "description": "Credited amount, exchanges and wallet closures with payout only\ndefault: 92.00",
+                    "type": "number"
+                },
+                "to_currency": {
+                    "description": "Target currency, exchanges and wallet closures with payout only\ndefault: EUR",
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "description": "Transaction identifier\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
                     "type": "string"
                 }
             }
         },
-        "handlers.LoginRequest": {
+        "handlers.TransactionsResponse": {
             "type": "object",
             "properties": {
-                "password": {
-                    "description": "Password\nrequired: true\ndefault: secret123",
+                "next_cursor": {
+                    "description": "Cursor of the next page, omitted on the last page",
                     "type": "string"
                 },
-                "username": {
-                    "description": "Username\nrequired: true\ndefault: john_doe",
-                    "type": "string"
+                "transactions": {
+                    "description": "Transactions, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TransactionEntry"
+                    }
                 }
             }
         },
-        "handlers.LoginResponse": {
+        "handlers.UpdatePotRequest": {
             "type": "object",
             "properties": {
-                "token": {
-                    "description": "JWT token\ndefault: JWT_TOKEN",
+                "spendable": {
+                    "description": "Whether withdrawals and exchanges may spend the pot\nrequired: true\ndefault: true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.UpdateRateAlertRequest": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "description": "Fire when the rate rises above or falls below the threshold\nrequired: true\ndefault: below",
                     "type": "string"
+                },
+                "threshold": {
+                    "description": "Rate the alert fires at\nrequired: true\ndefault: 0.9",
+                    "type": "number"
                 }
             }
         },
-        "handlers.RegisterErrorResponse": {
+        "handlers.UpdateWalletDetailsRequest": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Username or email already exists",
+                "label": {
+                    "description": "Display label, at most 64 characters; an empty string removes it\ndefault: travel fund",
                     "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata replacing the stored one, at most 20 keys of up to 40 characters\nwith values of up to 256 characters; an empty object removes it",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "handlers.RegisterRequest": {
+        "handlers.WalletDetailsEntry": {
             "type": "object",
             "properties": {
-                "email": {
-                    "description": "Email\nrequired: true\ndefault: john@example.com",
+                "label": {
+                    "description": "Display label of the wallet\ndefault: travel fund",
                     "type": "string"
                 },
-                "password": {
-                    "description": "Password\nrequired: true\ndefault: secret123",
+                "metadata": {
+                    "description": "Key/value metadata of the wallet",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.WalletDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency of the wallet\ndefault: USD",
                     "type": "string"
                 },
-                "username": {
-                    "description": "Username\nrequired: true\ndefault: john_doe",
+                "label": {
+                    "description": "Display label of the wallet\ndefault: travel fund",
                     "type": "string"
+                },
+                "metadata": {
+                    "description": "Key/value metadata of the wallet",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "handlers.RegisterResponse": {
+        "handlers.WalletLimitEntry": {
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Success message\ndefault: User registered successfully",
+                "currency": {
+                    "description": "Currency code\ndefault: USD",
+                    "type": "string"
+                },
+                "daily_limit": {
+                    "description": "Limit over the last 24 hours, null means unlimited\ndefault: 1000.0",
+                    "type": "number"
+                },
+                "daily_used": {
+                    "description": "Withdrawn and exchanged over the last 24 hours\ndefault: 250.0",
+                    "type": "number"
+                },
+                "monthly_limit": {
+                    "description": "Limit over the last 30 days, null means unlimited\ndefault: 10000.0",
+                    "type": "number"
+                },
+                "monthly_used": {
+                    "description": "Withdrawn and exchanged over the last 30 days\ndefault: 4000.0",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "description": "Time of the last limit change",
                     "type": "string"
                 }
             }
         },
-        "handlers.WithdrawErrorResponse": {
+        "handlers.WalletLimitsResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\ndefault: Insufficient funds or invalid amount",
+                "limits": {
+                    "description": "Limits per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.WalletLimitEntry"
+                    }
+                }
+            }
+        },
+        "handlers.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Registration time",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Signing secret, returned only on registration\ndefault: whsec_5f2b...",
                     "type": "string"
+                },
+                "url": {
+                    "description": "Callback URL\ndefault: https://example.com/webhooks/wallet",
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "description": "Webhook ID\ndefault: 3fa85f64-5717-4562-b3fc-2c963f66afa6",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.WebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "description": "Webhooks, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.WebhookResponse"
+                    }
                 }
             }
         },
         "handlers.WithdrawRequest": {
             "type": "object",
+            "required": [
+                "currency"
+            ],
             "properties": {
                 "amount": {
                     "description": "Amount to withdraw\nrequired: true\ndefault: 50.0",
@@ -630,6 +5606,11 @@ const docTemplate = `{
                 "currency": {
                     "description": "Currency\nrequired: true\ndefault: USD",
                     "type": "string"
+                },
+                "reference": {
+                    "description": "Client reference for reconciliation, up to 128 characters; returned in the history\ndefault: INV-2024-0042",
+                    "type": "string",
+                    "maxLength": 128
                 }
             }
         },
@@ -642,11 +5623,10 @@ const docTemplate = `{
                 },
                 "new_balance": {
                     "description": "New balance of the user",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/handlers.CurrencyBalanceAfterWithdraw"
-                        }
-                    ]
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
                 }
             }
         }
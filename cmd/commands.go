@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/config"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/migrator"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/migrations"
+)
+
+const usage = `Usage:
+  gw-currency-wallet [serve] [flags]
+  gw-currency-wallet migrate [up|down|status] [flags]
+  gw-currency-wallet create-admin -username <name> -email <email> [flags] < password
+  gw-currency-wallet seed [-users 3] [-password <password>] [-amount 1000] [-currencies USD,EUR,RUB] [flags]
+  gw-currency-wallet healthcheck [-ready] [-url <url>] [-timeout 5s] [flags]
+
+Every command accepts -c <config file> and a flag per setting, e.g. -app-port 8080.
+Run a command with -h to list its flags.`
+
+// errUsage is returned for unknown commands and invalid arguments.
+var errUsage = errors.New("invalid usage")
+
+// commands maps the subcommands to their implementations, called with the arguments after
+// the subcommand name.
+var commands = map[string]func(ctx context.Context, args []string, in io.Reader, out io.Writer) error{
+	"serve":        serveCommand,
+	"migrate":      migrateCommand,
+	"create-admin": createAdminCommand,
+	"seed":         seedCommand,
+	"healthcheck":  healthcheckCommand,
+}
+
+// execute runs the subcommand named by the first argument, serve if there is none or the
+// arguments start with a flag, so `gw-currency-wallet -c config.env` still starts the service.
+func execute(ctx context.Context, args []string, in io.Reader, out io.Writer) error {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	command, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, name)
+	}
+	return command(ctx, args, in, out)
+}
+
+// parseCommand parses the flags of a command, defined on fs by the caller, together with the
+// configuration flags, and loads the configuration.
+func parseCommand(fs *flag.FlagSet, args []string) (*config.Config, error) {
+	load := config.Bind(fs)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fmt.Fprintf(os.Stderr, "Flags of %s:\n", fs.Name())
+			fs.PrintDefaults()
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("%w: unexpected arguments %v", errUsage, fs.Args())
+	}
+	return load()
+}
+
+// serveCommand starts the service.
+func serveCommand(ctx context.Context, args []string, _ io.Reader, _ io.Writer) error {
+	cfg, err := parseCommand(flag.NewFlagSet("serve", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	printBuildInfo()
+	return run(ctx, cfg)
+}
+
+// migrateCommand applies the pending migrations, rolls back the latest one or lists them.
+func migrateCommand(ctx context.Context, args []string, _ io.Reader, out io.Writer) error {
+	action := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	if action != "up" && action != "down" && action != "status" {
+		return fmt.Errorf("%w: unknown migrate action %q", errUsage, action)
+	}
+	cfg, err := parseCommand(flag.NewFlagSet("migrate "+action, flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+
+	db, err := connectPostgres(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	m, err := migrator.New(db, migrations.Files)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %s\n", migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+	case "down":
+		rolledBack, err := m.Down(ctx)
+		if rolledBack != nil {
+			fmt.Fprintf(out, "rolled back %s\n", rolledBack.Name)
+		} else if err == nil {
+			fmt.Fprintln(out, "no applied migrations")
+		}
+		return err
+	default:
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%-25s %s\n", applied, status.Name)
+		}
+		return nil
+	}
+}
+
+// userRegistrar defines the user operations of create-admin and seed.
+type userRegistrar interface {
+	Register(ctx context.Context, username, password, email string) error
+	GetByUsernameOrEmail(ctx context.Context, username, email *string) (*models.UserDB, error)
+	SetRole(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+// authRegistrar registers users with the AuthService, hashing passwords as the service does.
+type authRegistrar struct {
+	*services.AuthService
+	*repositories.UserReadRepository
+	*repositories.UserWriteRepository
+}
+
+// newAuthRegistrar returns the userRegistrar of db with the password settings of cfg.
+func newAuthRegistrar(db *sqlx.DB, cfg *config.Config) *authRegistrar {
+	reader := repositories.NewUserReadRepository(db)
+	writer := repositories.NewUserWriteRepository(db)
+	auth := services.NewAuthService(reader, writer, jwt.New(jwt.WithSecretKey(cfg.Auth.JWTSecretKey)),
+		services.WithBcryptCost(cfg.Auth.BcryptCost),
+		services.WithPepper(cfg.Auth.PasswordPepper),
+	)
+	return &authRegistrar{AuthService: auth, UserReadRepository: reader, UserWriteRepository: writer}
+}
+
+// createAdminCommand registers an admin, reading the password from the first line of in.
+func createAdminCommand(ctx context.Context, args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := fs.String("username", "", "Username of the admin")
+	email := fs.String("email", "", "Email of the admin")
+	cfg, err := parseCommand(fs, args)
+	if err != nil {
+		return err
+	}
+	if *username == "" || *email == "" {
+		return fmt.Errorf("%w: -username and -email are required", errUsage)
+	}
+	password, _ := bufio.NewReader(in).ReadString('\n')
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return fmt.Errorf("%w: the password must be given on standard input", errUsage)
+	}
+
+	db, err := connectPostgres(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	user, err := createAdmin(ctx, newAuthRegistrar(db, cfg), *username, password, *email)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "created admin %s <%s> with ID %s\n", user.Username, user.Email, user.UserID)
+	return nil
+}
+
+// createAdmin registers a user and grants the admin role.
+func createAdmin(ctx context.Context, users userRegistrar, username, password, email string) (*models.UserDB, error) {
+	if err := users.Register(ctx, username, password, email); err != nil {
+		return nil, err
+	}
+	user, err := users.GetByUsernameOrEmail(ctx, &username, nil)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("registered user %s not found", username)
+	}
+	if err := users.SetRole(ctx, user.UserID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// depositor defines the wallet operation of seed.
+type depositor interface {
+	SaveDeposit(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error
+}
+
+// seedCommand registers demo users with funded wallets for development environments.
+func seedCommand(ctx context.Context, args []string, _ io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	users := fs.Int("users", 3, "Number of demo users, named demo1, demo2, ...")
+	password := fs.String("password", "password", "Password of the demo users")
+	amount := fs.String("amount", "1000", "Initial balance of every wallet")
+	currencies := fs.String("currencies", "USD,EUR,RUB", "Comma-separated currencies of the wallets")
+	cfg, err := parseCommand(fs, args)
+	if err != nil {
+		return err
+	}
+	if cfg.App.Env == "production" {
+		return errors.New("seed must not be run with APP_ENV=production")
+	}
+	balance, err := money.Parse(*amount)
+	if err != nil || balance < 0 {
+		return fmt.Errorf("%w: invalid -amount %q", errUsage, *amount)
+	}
+
+	db, err := connectPostgres(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return seed(ctx, newAuthRegistrar(db, cfg), repositories.NewWalletWriterRepository(db, nil), out,
+		*users, *password, balance, strings.Split(strings.ToUpper(*currencies), ","))
+}
+
+// seed registers the demo users demo1 to demoN and deposits balance into their wallets of
+// currencies. Existing demo users are left unchanged, so seeding twice is harmless.
+func seed(ctx context.Context, users userRegistrar, wallets depositor, out io.Writer,
+	count int, password string, balance money.Amount, currencies []string) error {
+	for i := 1; i <= count; i++ {
+		username := fmt.Sprintf("demo%d", i)
+		err := users.Register(ctx, username, password, username+"@example.com")
+		if errors.Is(err, services.ErrUserAlreadyExists) {
+			fmt.Fprintf(out, "skipped %s: already exists\n", username)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("register %s: %w", username, err)
+		}
+
+		user, err := users.GetByUsernameOrEmail(ctx, &username, nil)
+		if err != nil || user == nil {
+			return fmt.Errorf("get registered user %s: %v", username, err)
+		}
+		if balance > 0 {
+			for _, currency := range currencies {
+				if err := wallets.SaveDeposit(ctx, uuid.New(), user.UserID, balance, strings.TrimSpace(currency)); err != nil {
+					return fmt.Errorf("fund %s wallet of %s: %w", currency, username, err)
+				}
+			}
+		}
+		fmt.Fprintf(out, "created %s\n", username)
+	}
+	return nil
+}
+
+// healthcheckCommand probes the liveness, or with -ready the readiness, endpoint of the
+// running service and fails unless it answers 200, for container health checks.
+func healthcheckCommand(ctx context.Context, args []string, _ io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	ready := fs.Bool("ready", false, "Probe /readyz instead of /healthz")
+	url := fs.String("url", "", "URL to probe, by default /healthz or /readyz of APP_HOST:APP_PORT")
+	timeout := fs.Duration("timeout", 5*time.Second, "Timeout of the probe")
+	cfg, err := parseCommand(fs, args)
+	if err != nil {
+		return err
+	}
+
+	tlsEnabled := cfg.HTTP.TLSCertFile != "" || len(cfg.HTTP.AutocertDomains) > 0
+	if *url == "" {
+		scheme, path := "http", "/healthz"
+		if tlsEnabled {
+			scheme = "https"
+		}
+		if *ready {
+			path = "/readyz"
+		}
+		*url = scheme + "://" + net.JoinHostPort(cfg.App.Host, cfg.App.Port) + path
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	if tlsEnabled {
+		// The probe targets the local instance, whose certificate is issued for its public name
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return probe(ctx, client, *url, out)
+}
+
+// probe fails unless a GET of url answers 200 OK.
+func probe(ctx context.Context, client *http.Client, url string, out io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	fmt.Fprintf(out, "%s %s\n", resp.Status, strings.TrimSpace(string(body)))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// connectPostgres opens PostgreSQL with the settings of cfg and the secrets from Vault, if
+// configured, and waits for it to answer, logging at the configured level.
+func connectPostgres(ctx context.Context, cfg *config.Config) (*sqlx.DB, error) {
+	if err := logger.Setup(loggerConfig(cfg)); err != nil {
+		return nil, err
+	}
+	if _, err := loadSecrets(ctx, cfg); err != nil {
+		return nil, err
+	}
+	// Commands run migrations and bulk changes, which the query timeout would cut short
+	db, err := openPostgres(postgresDSN(cfg), nil, nil, config.Postgres{})
+	if err != nil {
+		return nil, err
+	}
+	if err := waitFor(ctx, cfg.Startup, "postgres", db.PingContext); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("PostgreSQL ping failed: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUsers registers users in memory and records the calls.
+type fakeUsers struct {
+	users map[string]*models.UserDB
+	err   error
+	calls []string
+}
+
+func (f *fakeUsers) Register(_ context.Context, username, password, email string) error {
+	f.calls = append(f.calls, "register "+username+" "+password+" "+email)
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.users[username]; ok {
+		return services.ErrUserAlreadyExists
+	}
+	f.users[username] = &models.UserDB{UserID: uuid.New(), Username: username, Email: email, Role: models.RoleUser}
+	return nil
+}
+
+func (f *fakeUsers) GetByUsernameOrEmail(_ context.Context, username, _ *string) (*models.UserDB, error) {
+	return f.users[*username], nil
+}
+
+func (f *fakeUsers) SetRole(_ context.Context, userID uuid.UUID, role string) error {
+	for _, user := range f.users {
+		if user.UserID == userID {
+			user.Role = role
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+// fakeDepositor records the deposits.
+type fakeDepositor struct {
+	deposits []string
+}
+
+func (f *fakeDepositor) SaveDeposit(_ context.Context, _, _ uuid.UUID, amount money.Amount, currency string) error {
+	f.deposits = append(f.deposits, amount.String()+" "+currency)
+	return nil
+}
+
+func TestExecute_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		{"deploy"},
+		{"migrate", "redo"},
+		{"healthcheck", "-unknown"},
+		{"healthcheck", "extra"},
+		{"create-admin", "-username", "root"},
+	} {
+		err := execute(context.Background(), args, strings.NewReader(""), &bytes.Buffer{})
+		assert.ErrorIs(t, err, errUsage, args)
+	}
+}
+
+func TestCreateAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("created", func(t *testing.T) {
+		users := &fakeUsers{users: map[string]*models.UserDB{}}
+
+		user, err := createAdmin(ctx, users, "root", "secret", "root@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.RoleAdmin, user.Role)
+		assert.Equal(t, []string{"register root secret root@example.com"}, users.calls)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		users := &fakeUsers{users: map[string]*models.UserDB{"root": {UserID: uuid.New(), Role: models.RoleUser}}}
+
+		_, err := createAdmin(ctx, users, "root", "secret", "root@example.com")
+
+		assert.ErrorIs(t, err, services.ErrUserAlreadyExists)
+		assert.Equal(t, models.RoleUser, users.users["root"].Role)
+	})
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	users := &fakeUsers{users: map[string]*models.UserDB{"demo1": {UserID: uuid.New()}}}
+	wallets := &fakeDepositor{}
+	var out bytes.Buffer
+
+	err := seed(ctx, users, wallets, &out, 2, "password", money.MustParse("100"), []string{"USD", " EUR"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "skipped demo1: already exists\ncreated demo2\n", out.String())
+	assert.Equal(t, []string{"100.00 USD", "100.00 EUR"}, wallets.deposits)
+
+	users.err = errors.New("db down")
+	err = seed(ctx, users, wallets, &out, 3, "password", money.MustParse("100"), []string{"USD"})
+	assert.ErrorContains(t, err, "register demo1: db down")
+}
+
+func TestHealthcheckCommand(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"status":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	err := execute(context.Background(), []string{"healthcheck", "-url", server.URL + "/readyz"}, nil, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "200 OK {\"status\":\"/readyz\"}\n", out.String())
+
+	status = http.StatusServiceUnavailable
+	err = execute(context.Background(), []string{"healthcheck", "-url", server.URL + "/readyz"}, nil, &out)
+	assert.ErrorContains(t, err, "answered 503 Service Unavailable")
+}
@@ -0,0 +1,179 @@
+// Command gw-wallet runs break-glass wallet operations directly on the database, for use
+// when the HTTP API is down. It reads the PostgreSQL settings from the service configuration.
+// Every operation is run on behalf of an admin and recorded in the audit trail.
+//
+// Usage:
+//
+//	gw-wallet [-c config.env] wallet show --user <email> --operator <admin email>
+//	gw-wallet [-c config.env] wallet adjust --user <email> --operator <admin email> --currency USD --amount -10.50 --reason <text>
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+const usage = `Usage:
+  gw-wallet [-c config.env] wallet show --user <email> --operator <admin email>
+  gw-wallet [-c config.env] wallet adjust --user <email> --operator <admin email> --currency <code> --amount <amount> --reason <text>
+
+A positive amount credits the wallet, a negative one debits it.`
+
+// errUsage is returned for unknown commands and missing flags.
+var errUsage = errors.New("invalid usage")
+
+// walletOps defines the operations used by the wallet commands.
+type walletOps interface {
+	Show(ctx context.Context, operatorEmail, email string) (services.WalletReport, error)
+	Adjust(ctx context.Context, operatorEmail, email, currency string, amount money.Amount, reason string) (services.WalletReport, error)
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, usage)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("gw-wallet", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configPath := fs.String("c", "config.env", "Path to configuration file")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	args = fs.Args()
+	if len(args) < 2 || args[0] != "wallet" || !slices.Contains([]string{"show", "adjust"}, args[1]) {
+		return errUsage
+	}
+
+	db, err := openDB(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("PostgreSQL ping failed: %w", err)
+	}
+
+	currencies := services.NewCurrencyService(repositories.NewCurrencyRepository(db), services.CurrencyCacheTTL)
+	ops := services.NewWalletOpsService(
+		repositories.NewUserReadRepository(db),
+		repositories.NewWalletReaderRepository(db),
+		repositories.NewWalletWriterRepository(db, repositories.TxFromContext),
+		repositories.NewWalletHoldRepository(db, nil),
+		currencies,
+		repositories.NewAuditWriteRepository(db),
+		repositories.NewTransactionRepository(db, db, repositories.TxFromContext),
+		repositories.NewTxRunner(db),
+	)
+
+	return runWallet(ctx, ops, args[1], args[2:], out)
+}
+
+// runWallet runs the wallet subcommand cmd with its flags and prints the user's wallets.
+func runWallet(ctx context.Context, ops walletOps, cmd string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("wallet "+cmd, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	user := fs.String("user", "", "Email of the wallet owner")
+	operator := fs.String("operator", "", "Email of the admin running the operation")
+	currency := fs.String("currency", "", "Currency code of the adjusted wallet")
+	amount := fs.String("amount", "", "Amount to credit, or debit if negative")
+	reason := fs.String("reason", "", "Reason recorded in the audit trail")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if *user == "" || *operator == "" {
+		return fmt.Errorf("%w: --user and --operator are required", errUsage)
+	}
+
+	var (
+		report services.WalletReport
+		err    error
+	)
+	switch cmd {
+	case "show":
+		report, err = ops.Show(ctx, *operator, *user)
+	case "adjust":
+		if *currency == "" || *amount == "" || *reason == "" {
+			return fmt.Errorf("%w: --currency, --amount and --reason are required", errUsage)
+		}
+		var value money.Amount
+		if value, err = money.Parse(*amount); err != nil {
+			return err
+		}
+		report, err = ops.Adjust(ctx, *operator, *user, *currency, value, *reason)
+	default:
+		return errUsage
+	}
+	if err != nil {
+		return err
+	}
+
+	return printReport(out, report)
+}
+
+// printReport prints the user and a table of total, held and available balances by currency.
+func printReport(out io.Writer, report services.WalletReport) error {
+	fmt.Fprintf(out, "User:    %s <%s>\n", report.User.Username, report.User.Email)
+	fmt.Fprintf(out, "User ID: %s\n", report.User.UserID)
+	fmt.Fprintf(out, "Dormant: %s\n\n", strconv.FormatBool(report.User.DormantAt != nil))
+
+	currencies := make([]string, 0, len(report.Balances))
+	for currency := range report.Balances {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CURRENCY\tBALANCE\tHELD\tAVAILABLE\t")
+	for _, currency := range currencies {
+		balance, held := report.Balances[currency], report.Held[currency]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", currency, balance, held, balance-held)
+	}
+	return w.Flush()
+}
+
+// openDB loads the configuration file and opens PostgreSQL with the service settings.
+func openDB(configPath string) (*sqlx.DB, error) {
+	_ = godotenv.Load(configPath)
+
+	getEnv := func(key, defaultValue string) string {
+		if val, ok := os.LookupEnv(key); ok && val != "" {
+			return val
+		}
+		return defaultValue
+	}
+
+	if err := logger.Initialize(getEnv("APP_LOG_LEVEL", "info")); err != nil {
+		return nil, err
+	}
+
+	pgPort, err := strconv.Atoi(getEnv("POSTGRES_PORT", "5432"))
+	if err != nil {
+		return nil, err
+	}
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
+		getEnv("POSTGRES_USER", "user"), getEnv("POSTGRES_PASSWORD", "password"),
+		getEnv("POSTGRES_HOST", "localhost"), pgPort, getEnv("POSTGRES_DB", "database"))
+	return sqlx.Open("pgx", dsn)
+}
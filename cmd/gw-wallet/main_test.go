@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeWalletOps records the last call and returns a fixed report or error.
+type fakeWalletOps struct {
+	report services.WalletReport
+	err    error
+	calls  []string
+}
+
+func (f *fakeWalletOps) Show(_ context.Context, operatorEmail, email string) (services.WalletReport, error) {
+	f.calls = append(f.calls, "show "+operatorEmail+" "+email)
+	return f.report, f.err
+}
+
+func (f *fakeWalletOps) Adjust(_ context.Context, operatorEmail, email, currency string, amount money.Amount, reason string) (services.WalletReport, error) {
+	f.calls = append(f.calls, strings.Join([]string{"adjust", operatorEmail, email, currency, amount.String(), reason}, " "))
+	return f.report, f.err
+}
+
+func TestRun_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"wallet"},
+		{"wallet", "delete"},
+		{"users", "show"},
+		{"-unknown"},
+	} {
+		err := run(context.Background(), args, &bytes.Buffer{})
+		assert.ErrorIs(t, err, errUsage, args)
+	}
+}
+
+func TestRunWallet(t *testing.T) {
+	ctx := context.Background()
+	report := services.WalletReport{
+		User: models.UserDB{UserID: uuid.New(), Username: "john", Email: "john@example.com"},
+		Balances: map[string]money.Amount{
+			models.USD: money.MustParse("100"),
+			models.EUR: money.MustParse("7.5"),
+		},
+		Held: map[string]money.Amount{models.USD: money.MustParse("30")},
+	}
+
+	t.Run("show", func(t *testing.T) {
+		ops := &fakeWalletOps{report: report}
+		var out bytes.Buffer
+
+		err := runWallet(ctx, ops, "show", []string{"--user", "john@example.com", "--operator", "admin@example.com"}, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"show admin@example.com john@example.com"}, ops.calls)
+		assert.Contains(t, out.String(), "User:    john <john@example.com>")
+		assert.Contains(t, out.String(), "Dormant: false")
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, []string{"CURRENCY", "BALANCE", "HELD", "AVAILABLE"}, strings.Fields(lines[len(lines)-3]))
+		assert.Equal(t, []string{"EUR", "7.50", "0.00", "7.50"}, strings.Fields(lines[len(lines)-2]))
+		assert.Equal(t, []string{"USD", "100.00", "30.00", "70.00"}, strings.Fields(lines[len(lines)-1]))
+	})
+
+	t.Run("adjust", func(t *testing.T) {
+		ops := &fakeWalletOps{report: report}
+
+		err := runWallet(ctx, ops, "adjust", []string{
+			"--user", "john@example.com", "--operator", "admin@example.com",
+			"--currency", "USD", "--amount", "-10.50", "--reason", "chargeback",
+		}, &bytes.Buffer{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"adjust admin@example.com john@example.com USD -10.50 chargeback"}, ops.calls)
+	})
+
+	t.Run("missing flags", func(t *testing.T) {
+		ops := &fakeWalletOps{}
+
+		err := runWallet(ctx, ops, "show", []string{"--user", "john@example.com"}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, errUsage)
+
+		err = runWallet(ctx, ops, "adjust", []string{"--user", "john@example.com", "--operator", "admin@example.com", "--amount", "10"}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, errUsage)
+		assert.Empty(t, ops.calls)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		err := runWallet(ctx, &fakeWalletOps{}, "adjust", []string{
+			"--user", "john@example.com", "--operator", "admin@example.com",
+			"--currency", "USD", "--amount", "10.505", "--reason", "refund",
+		}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, money.ErrInvalidAmount)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ops := &fakeWalletOps{err: services.ErrOperatorNotAdmin}
+
+		err := runWallet(ctx, ops, "show", []string{"--user", "john@example.com", "--operator", "john@example.com"}, &bytes.Buffer{})
+		assert.True(t, errors.Is(err, services.ErrOperatorNotAdmin))
+	})
+}
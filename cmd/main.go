@@ -2,66 +2,69 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
-	"strings"
+	"slices"
 	"syscall"
 	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
-	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/app"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/brokers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/config"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/consumers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/deployment"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/faults"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/handlers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/httpserver"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jobs"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
-	"github.com/sbilibin2017/gw-currency-wallet/internal/middlewares"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/notifications"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/profiling"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/secrets"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	pb "github.com/sbilibin2017/proto-exchange/exchange"
-	httpSwagger "github.com/swaggo/http-swagger"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 )
 
+// @title gw-currency-wallet API
+// @version 1.0.0
+// @description Microservice for managing user wallets and currency exchange
+// @host localhost:8080
+// @BasePath /api/v1
+// @schemes http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
-	printBuildInfo()
-	configPath := parseFlags()
-
-	appHost, appPort, pgHost, pgPort, pgUser, pgPassword, pgDB,
-		pgMaxOpenConns, pgMaxIdleConns,
-		redisHost, redisPort, redisDB, redisPassword,
-		redisPoolSize, redisMinIdleConns, redisExp,
-		gwHost, gwPort, kafkaBrokers, kafkaTopic, logLevel,
-		jwtSecret, jwtExp,
-		err := parseConfig(configPath)
-	if err != nil {
-		log.Fatalf("failed to parse config: %v", err)
-	}
-
-	if err := run(context.Background(),
-		appHost, appPort,
-		pgHost, pgPort, pgUser, pgPassword, pgDB,
-		pgMaxOpenConns, pgMaxIdleConns,
-		redisHost, redisPort, redisDB, redisPassword,
-		redisPoolSize, redisMinIdleConns, redisExp,
-		gwHost, gwPort,
-		kafkaBrokers, kafkaTopic,
-		logLevel,
-		jwtSecret, jwtExp,
-	); err != nil {
-		log.Fatalf("application stopped with error: %v", err)
+	if err := execute(context.Background(), os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, usage)
+		}
+		log.Fatalf("error: %v", err)
 	}
 }
 
@@ -78,232 +81,421 @@ func printBuildInfo() {
 	fmt.Printf("Build: %s\n", buildDate)
 }
 
-func parseFlags() string {
-	c := flag.String("c", "config.env", "Path to configuration file")
-	flag.Parse()
-	return *c
-}
+// run starts the application configured by cfg and blocks until ctx is canceled or it fails.
+func run(ctx context.Context, cfg *config.Config) error {
 
-// parseConfig loads env and returns all configs including Kafka
-func parseConfig(path string) (
-	appHost, appPort string,
-	pgHost string, pgPort int, pgUser, pgPassword, pgDB string,
-	pgMaxOpenConns, pgMaxIdleConns int,
-	redisHost string, redisPort, redisDB int, redisPassword string,
-	redisPoolSize, redisMinIdleConns, redisExp int,
-	gwHost, gwPort string,
-	kafkaBrokers []string, kafkaTopic string,
-	logLevel string,
-	jwtSecretKey string, jwtExpSecond int,
-	err error,
-) {
-	_ = godotenv.Load(path)
-
-	getEnv := func(key, defaultValue string) string {
-		if val, ok := os.LookupEnv(key); ok && val != "" {
-			return val
-		}
-		return defaultValue
-	}
-
-	// Application
-	appHost = getEnv("APP_HOST", "localhost")
-	appPort = getEnv("APP_PORT", "8080")
-	logLevel = getEnv("APP_LOG_LEVEL", "info")
+	// Deployment metadata tags every log entry, metric and Kafka event
+	deploymentInfo := deployment.Info{Env: cfg.App.Env, Region: cfg.App.Region, InstanceID: cfg.App.InstanceID}
 
-	// PostgreSQL
-	pgHost = getEnv("POSTGRES_HOST", "localhost")
-	pgUser = getEnv("POSTGRES_USER", "user")
-	pgPassword = getEnv("POSTGRES_PASSWORD", "password")
-	pgDB = getEnv("POSTGRES_DB", "database")
-	if pgPort, err = strconv.Atoi(getEnv("POSTGRES_PORT", "5432")); err != nil {
-		return
-	}
-	if pgMaxOpenConns, err = strconv.Atoi(getEnv("POSTGRES_MAX_OPEN_CONNS", "16")); err != nil {
-		return
-	}
-	if pgMaxIdleConns, err = strconv.Atoi(getEnv("POSTGRES_MAX_IDLE_CONNS", "8")); err != nil {
-		return
-	}
-
-	// Redis
-	redisHost = getEnv("REDIS_HOST", "localhost")
-	if redisPort, err = strconv.Atoi(getEnv("REDIS_PORT", "6379")); err != nil {
-		return
-	}
-	if redisDB, err = strconv.Atoi(getEnv("REDIS_DB", "0")); err != nil {
-		return
-	}
-	redisPassword = getEnv("REDIS_PASSWORD", "")
-	if redisPoolSize, err = strconv.Atoi(getEnv("REDIS_POOL_SIZE", "10")); err != nil {
-		return
-	}
-	if redisMinIdleConns, err = strconv.Atoi(getEnv("REDIS_MIN_IDLE_CONNS", "2")); err != nil {
-		return
-	}
-	if redisExp, err = strconv.Atoi(getEnv("REDIS_EXP_SECOND", "60")); err != nil {
-		return
+	// Logger
+	if err := logger.Setup(loggerConfig(cfg), deploymentInfo.LogFields()...); err != nil {
+		fmt.Println("failed to initialize logger:", err)
+		return err
 	}
+	defer logger.Log.Sync()
+	logger.Log.Infof("Logger initialized with level %s", cfg.App.LogLevel)
 
-	// gRPC
-	gwHost = getEnv("GW_EXCHANGER_HOST", "localhost")
-	gwPort = getEnv("GW_EXCHANGER_PORT", "50051")
+	// Metrics
+	metrics.SetConstLabels(deploymentInfo.Labels())
 
-	// Kafka
-	brokersStr := getEnv("KAFKA_BROKERS", "localhost:9092")
-	kafkaBrokers = []string{}
-	for _, b := range strings.Split(brokersStr, ",") {
-		b = strings.TrimSpace(b)
-		if b != "" {
-			kafkaBrokers = append(kafkaBrokers, b)
-		}
+	// Secrets from Vault replace the configured ones and are reloaded while running
+	secretStore, err := loadSecrets(ctx, cfg)
+	if err != nil {
+		logger.Log.Error("Failed to read secrets:", err)
+		return err
 	}
-	kafkaTopic = getEnv("KAFKA_TOPIC", "large-transactions")
-
-	// JWT
-	jwtSecretKey = getEnv("JWT_SECRET_KEY", "my_super_secret_key")
-	if jwtExpSecond, err = strconv.Atoi(getEnv("JWT_EXP_SECOND", "60")); err != nil {
-		return
+	var postgresPassword func() string
+	if secretStore != nil {
+		secretsCtx, stopSecrets := context.WithCancel(ctx)
+		defer stopSecrets()
+		go secretStore.Run(secretsCtx, time.Duration(cfg.Vault.RenewIntervalSecond)*time.Second)
+		postgresPassword = secretStore.Func(secrets.PostgresPassword, cfg.Postgres.Password)
 	}
 
-	return
-}
-
-func run(ctx context.Context,
-	appHost, appPort string,
-	pgHost string, pgPort int, pgUser, pgPassword, pgDB string,
-	pgMaxOpenConns, pgMaxIdleConns int,
-	redisHost string, redisPort, redisDB int, redisPassword string,
-	redisPoolSize, redisMinIdleConns, redisExp int,
-	gwHost, gwPort string,
-	kafkaBrokers []string, kafkaTopic string,
-	logLevel string,
-	jwtSecretKey string, jwtExpSecond int,
-) error {
-
-	// Logger
-	if err := logger.Initialize(logLevel); err != nil {
-		fmt.Println("failed to initialize logger:", err)
-		return err
+	// Fault injection (staging only), nil for targets without faults
+	faultInjector := func(target string) *faults.Injector {
+		if !cfg.Faults.Enabled || !slices.Contains(cfg.Faults.Targets, target) {
+			return nil
+		}
+		logger.Log.Warnw("Fault injection enabled", "target", target, "latency_ms", cfg.Faults.LatencyMs, "error_rate", cfg.Faults.ErrorRate)
+		return faults.NewInjector(target, time.Duration(cfg.Faults.LatencyMs)*time.Millisecond, cfg.Faults.ErrorRate)
 	}
-	defer logger.Log.Sync()
-	logger.Log.Infof("Logger initialized with level %s", logLevel)
 
 	// PostgreSQL
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		pgUser, pgPassword, pgHost, pgPort, pgDB)
-	db, err := sqlx.ConnectContext(ctx, "pgx", dsn)
+	db, err := openPostgres(postgresDSN(cfg), postgresPassword, faultInjector("postgres"), cfg.Postgres)
 	if err != nil {
 		logger.Log.Error("PostgreSQL connection error:", err)
 		return err
 	}
 	defer db.Close()
-	db.SetMaxOpenConns(pgMaxOpenConns)
-	db.SetMaxIdleConns(pgMaxIdleConns)
-	if err := db.PingContext(ctx); err != nil {
+	metrics.RegisterDBStats("postgres", db.DB)
+	configurePool(db, cfg.Postgres)
+	if err := waitFor(ctx, cfg.Startup, "postgres", db.PingContext); err != nil {
 		logger.Log.Error("PostgreSQL ping failed:", err)
 		return err
 	}
 
+	// PostgreSQL read replica; reads fall back to the primary while it is down
+	var replicaDB *sqlx.DB
+	if cfg.Postgres.ReplicaDSN != "" {
+		replicaDB, err = openPostgres(cfg.Postgres.ReplicaDSN, nil, faultInjector("postgres"), cfg.Postgres)
+		if err != nil {
+			logger.Log.Error("PostgreSQL replica connection error:", err)
+			return err
+		}
+		defer replicaDB.Close()
+		metrics.RegisterDBStats("postgres_replica", replicaDB.DB)
+		configurePool(replicaDB, cfg.Postgres)
+		if err := replicaDB.PingContext(ctx); err != nil {
+			logger.Log.Warnw("PostgreSQL replica ping failed, reading from the primary", "error", err)
+		}
+	}
+
 	// Redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%d", redisHost, redisPort),
-		Password:     redisPassword,
-		DB:           redisDB,
-		PoolSize:     redisPoolSize,
-		MinIdleConns: redisMinIdleConns,
-	})
-	if err := rdb.Ping(ctx).Err(); err != nil {
+	redisOpts := &redis.Options{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password:     cfg.Redis.Password,
+		DB:           cfg.Redis.DB,
+		PoolSize:     cfg.Redis.PoolSize,
+		MinIdleConns: cfg.Redis.MinIdleConns,
+	}
+	if secretStore != nil {
+		redisPassword := secretStore.Func(secrets.RedisPassword, cfg.Redis.Password)
+		redisOpts.CredentialsProvider = func() (string, string) { return "", redisPassword() }
+	}
+	rdb := redis.NewClient(redisOpts)
+	defer rdb.Close()
+	if err := waitFor(ctx, cfg.Startup, "redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() }); err != nil {
 		logger.Log.Error("Redis connection error:", err)
 		return err
 	}
-	defer rdb.Close()
+	if injector := faultInjector("redis"); injector != nil {
+		rdb.AddHook(faults.RedisHook(injector))
+	}
 
 	// gRPC client
-	grpcAddr := fmt.Sprintf("%s:%s", gwHost, gwPort)
-	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	grpcAddr := fmt.Sprintf("%s:%s", cfg.Exchanger.Host, cfg.Exchanger.Port)
+	grpcOpts, err := facades.ExchangerSecurity{
+		TLS:        cfg.Exchanger.TLS,
+		CAFile:     cfg.Exchanger.CAFile,
+		CertFile:   cfg.Exchanger.CertFile,
+		KeyFile:    cfg.Exchanger.KeyFile,
+		ServerName: cfg.Exchanger.ServerName,
+		Token:      cfg.Exchanger.Token,
+	}.DialOptions()
+	if err != nil {
+		logger.Log.Error("Failed to configure gRPC credentials:", err)
+		return err
+	}
+	interceptors := []grpc.UnaryClientInterceptor{requestid.UnaryClientInterceptor}
+	if injector := faultInjector("grpc"); injector != nil {
+		interceptors = append(interceptors, faults.UnaryClientInterceptor(injector))
+	}
+	grpcOpts = append(grpcOpts, grpc.WithChainUnaryInterceptor(interceptors...))
+	conn, err := grpc.Dial(grpcAddr, grpcOpts...)
 	if err != nil {
 		logger.Log.Error("Failed to connect to gRPC service at", grpcAddr, ":", err)
 		return err
 	}
 	defer conn.Close()
-	exchangeGRPCClient := pb.NewExchangeServiceClient(conn)
+	// The exchanger is not required: rates fall back to the cache and the HTTP provider, if any
+	if cfg.Startup.WaitSecond > 0 {
+		if err := waitFor(ctx, cfg.Startup, "exchanger", facades.NewExchangerHealthFacade(conn).PingContext); err != nil {
+			logger.Log.Warnw("Exchanger not ready, starting without it", "addr", grpcAddr, "error", err)
+		}
+	}
 
 	// JWT
 	jwtService := jwt.New(
-		jwt.WithSecretKey(jwtSecretKey),
-		jwt.WithExpiration(time.Duration(jwtExpSecond)*time.Second),
+		jwt.WithSecretKey(cfg.Auth.JWTSecretKey),
+		jwt.WithExpiration(time.Duration(cfg.Auth.JWTExpSecond)*time.Second),
 	)
 
-	// Repositories
-	userReadRepo := repositories.NewUserReadRepository(db)
-	userWriteRepo := repositories.NewUserWriteRepository(db)
-	walletReaderRepo := repositories.NewWalletReaderRepository(db)
-	walletWriterRepo := repositories.NewWalletWriterRepository(db, nil)
-	exchangeRateCacheRepo := repositories.NewExchangeRateCacheRepository(rdb, time.Duration(redisExp)*time.Second)
-	exchangeGRPCFacade := facades.NewExchangeRatesGRPCFacade(exchangeGRPCClient)
-
-	// Kafka Writer
-	kafkaWriter := kafka.NewWriter(kafka.WriterConfig{
-		Brokers:  kafkaBrokers,
-		Topic:    kafkaTopic,
-		Balancer: &kafka.LeastBytes{},
-	})
-	defer kafkaWriter.Close()
+	// Event publishers: Kafka writers, or one NATS or RabbitMQ connection shared by all
+	// topics; transaction events carry their topic
+	var transactionWriter, securityAlertWriter, receiptWriter, rateAlertWriter, balanceWriter services.EventPublisher
+	kafkaDialer, err := facades.KafkaSecurity{
+		SASLMechanism: cfg.Kafka.SASLMechanism,
+		Username:      cfg.Kafka.SASLUsername,
+		Password:      cfg.Kafka.SASLPassword,
+		TLS:           cfg.Kafka.TLS,
+		CAFile:        cfg.Kafka.CAFile,
+		CertFile:      cfg.Kafka.CertFile,
+		KeyFile:       cfg.Kafka.KeyFile,
+		ServerName:    cfg.Kafka.ServerName,
+	}.Dialer()
+	if err != nil {
+		logger.Log.Error("Failed to configure Kafka security:", err)
+		return err
+	}
+	if cfg.Events.Broker == "kafka" {
+		// Transaction events carry their topic; all events are partitioned by their key,
+		// the user ID, so the events of a user keep their order
+		kafkaWriter := newKafkaWriter(cfg.Kafka.Brokers, "", kafkaDialer, cfg.Kafka.RequiredAcks)
+		defer kafkaWriter.Close()
+		transactionWriter = kafkaWriter
+		securityAlertKafkaWriter := newKafkaWriter(cfg.Kafka.Brokers, cfg.Kafka.SecurityAlertTopic, kafkaDialer, cfg.Kafka.RequiredAcks)
+		defer securityAlertKafkaWriter.Close()
+		securityAlertWriter = securityAlertKafkaWriter
+		receiptKafkaWriter := newKafkaWriter(cfg.Kafka.Brokers, cfg.Kafka.ExchangeReceiptsTopic, kafkaDialer, cfg.Kafka.RequiredAcks)
+		defer receiptKafkaWriter.Close()
+		receiptWriter = receiptKafkaWriter
+		rateAlertKafkaWriter := newKafkaWriter(cfg.Kafka.Brokers, cfg.Kafka.RateAlertsTopic, kafkaDialer, cfg.Kafka.RequiredAcks)
+		defer rateAlertKafkaWriter.Close()
+		rateAlertWriter = rateAlertKafkaWriter
+		if cfg.Kafka.BalanceTopic != "" {
+			ensureCompactedTopic(ctx, cfg.Kafka.Brokers, cfg.Kafka.BalanceTopic, kafkaDialer)
+			balanceKafkaWriter := newKafkaWriter(cfg.Kafka.Brokers, cfg.Kafka.BalanceTopic, kafkaDialer, cfg.Kafka.RequiredAcks)
+			defer balanceKafkaWriter.Close()
+			balanceWriter = balanceKafkaWriter
+		}
+	} else {
+		publisher, err := newBrokerPublisher(cfg.Events.Broker, cfg.Events.NATSURL, cfg.Events.RabbitMQURL, cfg.Events.RabbitMQExchange)
+		if err != nil {
+			logger.Log.Error("Failed to configure event broker:", err)
+			return err
+		}
+		defer publisher.Close()
+		logger.Log.Infow("Publishing events", "broker", cfg.Events.Broker)
+		transactionWriter = publisher
+		securityAlertWriter = brokers.WithTopic(publisher, cfg.Kafka.SecurityAlertTopic)
+		receiptWriter = brokers.WithTopic(publisher, cfg.Kafka.ExchangeReceiptsTopic)
+		rateAlertWriter = brokers.WithTopic(publisher, cfg.Kafka.RateAlertsTopic)
+		if cfg.Kafka.BalanceTopic != "" {
+			balanceWriter = brokers.WithTopic(publisher, cfg.Kafka.BalanceTopic)
+		}
+	}
+
+	if balanceWriter != nil {
+		balanceWriter = services.NewMeteredPublisher(deployment.NewTaggedKafkaWriter(balanceWriter, deploymentInfo), cfg.Kafka.BalanceTopic)
+	}
+
+	// Transaction event encoding, Avro registers its schema at startup
+	var transactionEncoder services.MessageEncoder
+	if cfg.Kafka.EventFormat == "avro" {
+		registry := facades.NewSchemaRegistryFacade(&http.Client{Timeout: 10 * time.Second},
+			cfg.SchemaRegistry.URL, cfg.SchemaRegistry.Username, cfg.SchemaRegistry.Password)
+		encoder, err := services.NewAvroTransactionEncoder(ctx, registry, cfg.Kafka.Topic+"-value", cfg.Kafka.TransactionsTopic+"-value")
+		if err != nil {
+			logger.Log.Error("Failed to register transaction schema:", err)
+			return err
+		}
+		transactionEncoder = encoder
+	}
 
-	// Services
-	authService := services.NewAuthService(userReadRepo, userWriteRepo, jwtService)
-	walletService := services.NewWalletService(walletWriterRepo, walletReaderRepo, exchangeGRPCFacade, exchangeRateCacheRepo, kafkaWriter)
+	// Readiness requires Kafka if events are published to it or confirmations read from it
+	var kafkaHealth handlers.BrokerPinger
+	if cfg.Events.Broker == "kafka" || cfg.Kafka.PaymentConfirmationsEnabled {
+		kafkaHealth = facades.NewKafkaHealthFacade(cfg.Kafka.Brokers, kafkaDialer, time.Duration(cfg.HTTP.HealthCheckTimeoutMs)*time.Millisecond)
+	}
 
-	// Handlers
-	registerHandler := handlers.NewRegisterHandler(authService)
-	loginHandler := handlers.NewLoginHandler(authService)
-	balanceHandler := handlers.NewGetBalanceHandler(walletService, jwtService)
-	depositHandler := handlers.NewDepositHandler(walletService, jwtService)
-	withdrawHandler := handlers.NewWithdrawHandler(walletService, jwtService)
-	getRatesHandler := handlers.NewGetExchangeRatesHandler(walletService, jwtService)
-	exchangeHandler := handlers.NewExchangeHandler(jwtService, walletService)
+	// Kafka Readers, closed by their consumers
+	var paymentConfirmationReader consumers.MessageReader
+	if cfg.Kafka.PaymentConfirmationsEnabled {
+		paymentConfirmationReader = kafka.NewReader(kafka.ReaderConfig{
+			Brokers: cfg.Kafka.Brokers,
+			Dialer:  kafkaDialer,
+			GroupID: cfg.Kafka.ConsumerGroup,
+			Topic:   cfg.Kafka.PaymentConfirmationsTopic,
+		})
+	}
 
-	// Router
-	r := chi.NewRouter()
-	r.Use(middleware.Recoverer)
-	r.Use(middlewares.LoggingMiddleware)
-
-	// Public routes
-	r.Post("/register", registerHandler)
-	r.Post("/login", loginHandler)
-
-	// Authenticated routes
-	authMiddleware := middlewares.AuthMiddleware(jwtService)
-	txMiddleware := middlewares.TxMiddleware(db)
-	r.Group(func(r chi.Router) {
-		r.Use(authMiddleware)
-
-		r.Get("/balance", balanceHandler)
-		r.With(txMiddleware).Post("/wallet/deposit", depositHandler)
-		r.With(txMiddleware).Post("/wallet/withdraw", withdrawHandler)
-		r.Get("/exchange/rates", getRatesHandler)
-		r.With(txMiddleware).Post("/exchange", exchangeHandler)
+	// Repositories and services
+	container, err := app.NewContainer(app.Infra{
+		DB:                        db,
+		ReplicaDB:                 replicaDB,
+		Redis:                     rdb,
+		Exchanger:                 pb.NewExchangeServiceClient(conn),
+		TransactionWriter:         services.NewMeteredPublisher(deployment.NewTaggedKafkaWriter(transactionWriter, deploymentInfo), ""),
+		TransactionEncoder:        transactionEncoder,
+		SecurityAlertWriter:       services.NewMeteredPublisher(deployment.NewTaggedKafkaWriter(securityAlertWriter, deploymentInfo), cfg.Kafka.SecurityAlertTopic),
+		ReceiptWriter:             services.NewMeteredPublisher(deployment.NewTaggedKafkaWriter(receiptWriter, deploymentInfo), cfg.Kafka.ExchangeReceiptsTopic),
+		RateAlertWriter:           services.NewMeteredPublisher(deployment.NewTaggedKafkaWriter(rateAlertWriter, deploymentInfo), cfg.Kafka.RateAlertsTopic),
+		BalanceWriter:             balanceWriter,
+		PaymentConfirmationReader: paymentConfirmationReader,
+		RedisHealth:               facades.NewRedisHealthFacade(rdb),
+		ExchangerHealth:           facades.NewExchangerHealthFacade(conn),
+		KafkaHealth:               kafkaHealth,
+		JWT:                       jwtService,
+		Notifier:                  notifications.NewLogNotifier(),
+	}, app.Settings{
+		RateCacheTTL:                 time.Duration(cfg.Rates.CacheTTLSecond) * time.Second,
+		RateCacheTTLMin:              time.Duration(cfg.Rates.CacheTTLMinSecond) * time.Second,
+		RateCacheTTLMax:              time.Duration(cfg.Rates.CacheTTLMaxSecond) * time.Second,
+		RateCacheSlowThreshold:       time.Duration(cfg.Rates.CacheSlowMs) * time.Millisecond,
+		BcryptCost:                   cfg.Auth.BcryptCost,
+		PasswordPepper:               cfg.Auth.PasswordPepper,
+		PasswordAllowLegacy:          cfg.Auth.PasswordAllowLegacy,
+		WalletProjectionEnabled:      cfg.Wallet.ProjectionEnabled,
+		WalletProjectionInterval:     time.Duration(cfg.Wallet.ProjectionIntervalSecond) * time.Second,
+		ImpersonationTTL:             time.Duration(cfg.Auth.ImpersonationExpSecond) * time.Second,
+		RegistrationDomainBlocklist:  cfg.Registration.DomainBlocklist,
+		RegistrationDomainAllowlist:  cfg.Registration.DomainAllowlist,
+		RegistrationDomainLimit:      cfg.Registration.DomainRateLimit,
+		RegistrationDomainWindow:     time.Duration(cfg.Registration.DomainRateWindowSecond) * time.Second,
+		DormancyEnabled:              cfg.Dormancy.Enabled,
+		DormancyInactiveMonths:       cfg.Dormancy.InactiveMonths,
+		DormancyCheckInterval:        time.Duration(cfg.Dormancy.CheckIntervalSecond) * time.Second,
+		HistoryPartitionsAhead:       cfg.History.PartitionsAhead,
+		HistoryRetentionMonths:       cfg.History.RetentionMonths,
+		HistoryArchiveDir:            cfg.History.ArchiveDir,
+		GeoIPDatabasePath:            cfg.Auth.GeoIPDatabasePath,
+		RequestTimeout:               time.Duration(cfg.HTTP.RequestTimeoutSecond) * time.Second,
+		TrustedProxies:               cfg.HTTP.TrustedProxies,
+		LegacyBalanceDeprecation:     cfg.HTTP.LegacyBalanceDeprecation,
+		HealthCheckTimeout:           time.Duration(cfg.HTTP.HealthCheckTimeoutMs) * time.Millisecond,
+		ExchangerTimeout:             time.Duration(cfg.Exchanger.TimeoutSecond) * time.Second,
+		ExchangerRetryAttempts:       cfg.Exchanger.RetryAttempts,
+		ExchangerRetryBackoff:        time.Duration(cfg.Exchanger.RetryBackoffMs) * time.Millisecond,
+		ExchangerRetryMaxBackoff:     time.Duration(cfg.Exchanger.RetryMaxBackoffMs) * time.Millisecond,
+		ExchangerAttemptTimeout:      time.Duration(cfg.Exchanger.AttemptTimeoutMs) * time.Millisecond,
+		RateProviderHTTPURL:          cfg.Rates.ProviderHTTPURL,
+		RateProviderHTTPAppID:        cfg.Rates.ProviderHTTPAppID,
+		RateProviderFailureThreshold: cfg.Rates.ProviderFailureThreshold,
+		RateProviderCooldown:         time.Duration(cfg.Rates.ProviderCooldownSecond) * time.Second,
+		RatePrewarmInterval:          time.Duration(cfg.Rates.PrewarmIntervalSecond) * time.Second,
+		ExchangeMinAmount:            cfg.Exchange.MinAmount,
+		ExchangeMaxAmount:            cfg.Exchange.MaxAmount,
+		ExchangerExportURL:           cfg.Exchanger.ExportURL,
+		ExchangerExportToken:         cfg.Exchanger.Token,
+		SchemaDriftCheckEnabled:      cfg.Postgres.SchemaDriftCheckEnabled,
+		TxRetryAttempts:              cfg.Postgres.TxRetryAttempts,
+		TxRetryBackoff:               time.Duration(cfg.Postgres.TxRetryBackoffMs) * time.Millisecond,
+		TxRetryMaxBackoff:            time.Duration(cfg.Postgres.TxRetryMaxBackoffMs) * time.Millisecond,
+		UserLockTTL:                  time.Duration(cfg.Wallet.UserLockTTLSecond) * time.Second,
+		UserLockWait:                 time.Duration(cfg.Wallet.UserLockWaitMs) * time.Millisecond,
+		ExchangeReceiptsEnabled:      cfg.Exchange.ReceiptsEnabled,
+		RateLimitPublic:              cfg.RateLimit.PublicPerMinute,
+		RateLimitRead:                cfg.RateLimit.ReadPerMinute,
+		RateLimitWrite:               cfg.RateLimit.WritePerMinute,
+		GRPCGatewayEnabled:           cfg.GRPC.GatewayEnabled,
+		GraphQLEnabled:               cfg.HTTP.GraphQLEnabled,
+		WalletInitialCurrencies:      cfg.Wallet.InitialCurrencies,
+		ExchangePivotCurrency:        cfg.Exchange.PivotCurrency,
+		RateMaxStaleness:             time.Duration(cfg.Rates.MaxStalenessSecond) * time.Second,
+		KafkaPublishAttempts:         cfg.Kafka.PublishAttempts,
+		KafkaPublishRetryBackoff:     time.Duration(cfg.Kafka.PublishRetryBackoffMs) * time.Millisecond,
+		LargeTransactionsTopic:       cfg.Kafka.Topic,
+		TransactionsTopic:            cfg.Kafka.TransactionsTopic,
+		LargeTransactionThresholds:   cfg.Kafka.LargeTransactionThresholds,
+		EventPublishAsync:            cfg.Events.PublishAsync,
+		EventBufferSize:              cfg.Events.BufferSize,
+		EventBatchSize:               cfg.Events.BatchSize,
+		EventFlushInterval:           time.Duration(cfg.Events.FlushIntervalMs) * time.Millisecond,
 	})
+	if err != nil {
+		logger.Log.Error("Failed to build application:", err)
+		return err
+	}
 
-	// Swagger
-	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL(fmt.Sprintf("http://%s:%s/swagger/doc.json", appHost, appPort)),
-	))
+	// Schema drift, reported by /readyz
+	container.CheckSchema(ctx)
+
+	// Background jobs (lock-guarded, safe to run on multiple replicas)
+	scheduler := jobs.NewScheduler(locks.NewRedisLocker(rdb))
+	container.RegisterJobs(scheduler)
+
+	// HTTPS
+	serverTLS := httpserver.TLS{
+		CertFile:         cfg.HTTP.TLSCertFile,
+		KeyFile:          cfg.HTTP.TLSKeyFile,
+		AutocertDomains:  cfg.HTTP.AutocertDomains,
+		AutocertCacheDir: cfg.HTTP.AutocertCacheDir,
+		AutocertEmail:    cfg.HTTP.AutocertEmail,
+		HTTPSPort:        cfg.App.Port,
+	}
+	tlsConfig, redirectHandler, err := serverTLS.Config()
+	if err != nil {
+		logger.Log.Error("Failed to configure HTTPS:", err)
+		return err
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+
+	// Router
+	r := container.Router(fmt.Sprintf("%s://%s:%s/swagger/doc.json", scheme, cfg.App.Host, cfg.App.Port))
 
 	srv := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", appHost, appPort),
-		Handler: r,
+		Addr:      fmt.Sprintf("%s:%s", cfg.App.Host, cfg.App.Port),
+		Handler:   r,
+		TLSConfig: tlsConfig,
 	}
+	// Open event streams would otherwise keep Shutdown waiting
+	srv.RegisterOnShutdown(container.CloseStreams)
 
 	// Graceful shutdown
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 3)
 	ctxShutdown, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
 	defer stop()
 
+	jobsDone := make(chan struct{})
 	go func() {
-		logger.Log.Infof("HTTP server listening on %s:%s", appHost, appPort)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		scheduler.Run(ctxShutdown)
+		close(jobsDone)
+	}()
+
+	consumersDone := make(chan struct{})
+	go func() {
+		container.RunConsumers(ctxShutdown)
+		close(consumersDone)
+	}()
+
+	// Profiles and goroutine dumps, on their own address so they are never exposed with the API.
+	// The server is not authenticated; a failure to listen does not stop the service.
+	if cfg.HTTP.DebugAddr != "" {
+		debugSrv := &http.Server{Addr: cfg.HTTP.DebugAddr, Handler: profiling.Handler()}
+		defer debugSrv.Close()
+		go func() {
+			logger.Log.Infof("Debug server listening on %s", cfg.HTTP.DebugAddr)
+			if err := debugSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Log.Errorw("Debug server failed", "error", err)
+			}
+		}()
+	}
+
+	// Plain HTTP redirected to HTTPS, also answering Let's Encrypt challenges
+	if redirectHandler != nil && cfg.HTTP.RedirectAddr != "" {
+		redirectSrv := &http.Server{Addr: cfg.HTTP.RedirectAddr, Handler: redirectHandler, ReadHeaderTimeout: 10 * time.Second}
+		defer redirectSrv.Close()
+		go func() {
+			logger.Log.Infof("HTTP redirect server listening on %s", cfg.HTTP.RedirectAddr)
+			if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("HTTP redirect server failed: %w", err)
+			}
+		}()
+	}
+
+	// gRPC wallet API, over TLS if the HTTP server terminates it
+	var grpcSrv *grpc.Server
+	if cfg.GRPC.Addr != "" {
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			logger.Log.Error("Failed to listen for gRPC on", cfg.GRPC.Addr, ":", err)
+			return err
+		}
+		var grpcServerOpts []grpc.ServerOption
+		if tlsConfig != nil {
+			grpcServerOpts = append(grpcServerOpts, grpc.Creds(credentials.NewTLS(tlsConfig.Clone())))
+		}
+		grpcSrv = container.GRPCServer(grpcServerOpts...)
+		go func() {
+			logger.Log.Infof("gRPC server listening on %s", cfg.GRPC.Addr)
+			if err := grpcSrv.Serve(lis); err != nil {
+				errChan <- fmt.Errorf("gRPC server failed: %w", err)
+			}
+		}()
+	}
+
+	go func() {
+		logger.Log.Infof("HTTP server listening on %s://%s:%s", scheme, cfg.App.Host, cfg.App.Port)
+		serve := srv.ListenAndServe
+		if tlsConfig != nil {
+			// Certificates come from TLSConfig
+			serve = func() error { return srv.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil && err != http.ErrServerClosed {
 			errChan <- fmt.Errorf("HTTP server failed: %w", err)
 		}
 	}()
@@ -312,6 +504,13 @@ func run(ctx context.Context,
 	case <-ctxShutdown.Done():
 		logger.Log.Info("Shutdown signal received, stopping HTTP server...")
 	case serveErr := <-errChan:
+		if grpcSrv != nil {
+			grpcSrv.Stop()
+		}
+		stop()
+		<-jobsDone
+		<-consumersDone
+		container.Close()
 		return serveErr
 	}
 
@@ -320,7 +519,182 @@ func run(ctx context.Context,
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Log.Errorw("HTTP server shutdown error", "error", err)
 	}
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop()
+	}
+	<-jobsDone
+	<-consumersDone
+	// Buffered events are published before the writers are closed
+	container.Close()
 
 	logger.Log.Info("HTTP server stopped gracefully")
 	return nil
 }
+
+// newKafkaWriter creates a writer to topic, or to the topic of every message if topic is empty,
+// that partitions messages by key and waits for acks.
+func newKafkaWriter(brokers []string, topic string, dialer *kafka.Dialer, acks kafka.RequiredAcks) *kafka.Writer {
+	w := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:  brokers,
+		Topic:    topic,
+		Dialer:   dialer,
+		Balancer: &kafka.Hash{},
+	})
+	// Set after NewWriter, which turns RequireNone (0) into RequireAll
+	w.RequiredAcks = acks
+	return w
+}
+
+// ensureCompactedTopic creates topic with log compaction, so that it keeps the latest event
+// per key, unless it exists. Brokers that create topics on first write would create it
+// without compaction, so a failure is logged for the operator to create it.
+func ensureCompactedTopic(ctx context.Context, brokers []string, topic string, dialer *kafka.Dialer) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	client := &kafka.Client{
+		Addr:      kafka.TCP(brokers...),
+		Transport: &kafka.Transport{SASL: dialer.SASLMechanism, TLS: dialer.TLS},
+	}
+	resp, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{Topics: []kafka.TopicConfig{{
+		Topic:             topic,
+		NumPartitions:     -1, // Broker defaults
+		ReplicationFactor: -1,
+		ConfigEntries:     []kafka.ConfigEntry{{ConfigName: "cleanup.policy", ConfigValue: "compact"}},
+	}}})
+	if err == nil {
+		err = resp.Errors[topic]
+	}
+	if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		logger.Log.Warnw("failed to create compacted topic", "topic", topic, "error", err)
+	}
+}
+
+// newBrokerPublisher creates the publisher of events for a NATS or RabbitMQ broker.
+func newBrokerPublisher(broker, natsURL, rabbitMQURL, rabbitMQExchange string) (brokers.Publisher, error) {
+	if broker == "nats" {
+		return brokers.NewNATSPublisher(natsURL, 10*time.Second)
+	}
+	return brokers.NewRabbitMQPublisher(rabbitMQURL, rabbitMQExchange, 10*time.Second)
+}
+
+// postgresDSN returns the connection string of the PostgreSQL settings of cfg.
+func postgresDSN(cfg *config.Config) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
+		cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.DB)
+}
+
+// configurePool applies the connection pool limits of cfg to db.
+func configurePool(db *sqlx.DB, cfg config.Postgres) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeSecond) * time.Second)
+	db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTimeSecond) * time.Second)
+}
+
+// loggerConfig returns the configuration of the global logger.
+func loggerConfig(cfg *config.Config) logger.Config {
+	return logger.Config{
+		Level:              cfg.App.LogLevel,
+		Format:             cfg.Log.Format,
+		Output:             cfg.Log.Output,
+		MaxSizeMB:          cfg.Log.FileMaxSizeMB,
+		MaxBackups:         cfg.Log.FileMaxBackups,
+		SamplingInitial:    cfg.Log.SamplingInitial,
+		SamplingThereafter: cfg.Log.SamplingThereafter,
+		PackageLevels:      cfg.Log.PackageLevels,
+	}
+}
+
+// openPostgres opens the database, injecting faults into its connections if injector is set.
+// If password is set, every connection is opened with the password it returns, so that a
+// rotated password is used by new connections. Queries are bounded and slow ones logged as
+// the query settings of cfg say.
+func openPostgres(dsn string, password func() string, injector *faults.Injector, cfg config.Postgres) (*sqlx.DB, error) {
+	timeout := time.Duration(cfg.QueryTimeoutMs) * time.Millisecond
+	slowThreshold := time.Duration(cfg.SlowQueryMs) * time.Millisecond
+	if password == nil && injector == nil && timeout == 0 && slowThreshold == 0 {
+		return sqlx.Open("pgx", dsn)
+	}
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	var opts []stdlib.OptionOpenDB
+	if password != nil {
+		opts = append(opts, stdlib.OptionBeforeConnect(func(_ context.Context, cc *pgx.ConnConfig) error {
+			cc.Password = password()
+			return nil
+		}))
+	}
+	connector := stdlib.GetConnector(*connConfig, opts...)
+	if injector != nil {
+		connector = faults.WrapConnector(connector, injector)
+	}
+	if timeout > 0 || slowThreshold > 0 {
+		connector = repositories.GuardConnector(connector, timeout, slowThreshold)
+	}
+	return sqlx.NewDb(sql.OpenDB(connector), "pgx"), nil
+}
+
+// waitFor calls ping until the dependency name answers, retrying with exponential backoff
+// for at most the startup wait of cfg, each attempt being bounded by the time left. It
+// returns the last error once the wait is over or ctx is done.
+func waitFor(ctx context.Context, cfg config.Startup, name string, ping func(context.Context) error) error {
+	wait := time.Duration(cfg.WaitSecond) * time.Second
+	deadline := time.Now().Add(wait)
+	backoff := time.Duration(cfg.RetryBackoffMs) * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if wait > 0 {
+			attemptCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+		err := ping(attemptCtx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				logger.Log.Infow("Dependency ready", "dependency", name, "attempts", attempt)
+			}
+			return nil
+		}
+
+		left := time.Until(deadline)
+		if left <= 0 {
+			return err
+		}
+		delay := min(backoff, left)
+		logger.Log.Warnw("Dependency not ready, retrying", "dependency", name, "attempt", attempt, "retry_in", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		backoff = min(2*backoff, time.Duration(cfg.RetryMaxBackoffMs)*time.Millisecond)
+	}
+}
+
+// loadSecrets reads the secrets from Vault, if configured, replacing those of cfg.
+// It returns nil without Vault.
+func loadSecrets(ctx context.Context, cfg *config.Config) (*secrets.Store, error) {
+	if cfg.Vault.Addr == "" {
+		return nil, nil
+	}
+	vault := facades.NewVaultFacade(&http.Client{Timeout: 10 * time.Second}, facades.VaultConfig{
+		Addr:                cfg.Vault.Addr,
+		Auth:                cfg.Vault.Auth,
+		Token:               cfg.Vault.Token,
+		KubernetesRole:      cfg.Vault.KubernetesRole,
+		KubernetesMount:     cfg.Vault.KubernetesMount,
+		KubernetesTokenFile: cfg.Vault.KubernetesTokenFile,
+		KVMount:             cfg.Vault.KVMount,
+		SecretPath:          cfg.Vault.SecretPath,
+	})
+	store := secrets.NewStore(vault)
+	if err := store.Load(ctx); err != nil {
+		return nil, err
+	}
+	cfg.Auth.JWTSecretKey = store.Get(secrets.JWTSecretKey, cfg.Auth.JWTSecretKey)
+	cfg.Postgres.Password = store.Get(secrets.PostgresPassword, cfg.Postgres.Password)
+	cfg.Redis.Password = store.Get(secrets.RedisPassword, cfg.Redis.Password)
+	logger.Log.Infow("Secrets read from Vault", "addr", cfg.Vault.Addr, "path", cfg.Vault.SecretPath)
+	return store, nil
+}
@@ -3,160 +3,28 @@ package main
 import (
 	"bytes"
 	"context"
-	"flag"
+	"errors"
 	"fmt"
 	"net"
 	"os"
-	"reflect"
 	"testing"
 	"time"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/config"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
 	pb "github.com/sbilibin2017/proto-exchange/exchange"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 )
 
 // ------------------ Helper functions ------------------
 
-func resetFlags() {
-	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-}
-
-func resetEnv() {
-	os.Clearenv()
-}
-
 func contains(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))
 }
 
-func TestParseConfig_Defaults(t *testing.T) {
-	resetEnv()
-
-	appHost, appPort,
-		pgHost, pgPort, pgUser, pgPassword, pgDB,
-		pgMaxOpenConns, pgMaxIdleConns,
-		redisHost, redisPort, redisDB, redisPassword,
-		redisPoolSize, redisMinIdleConns, redisExp,
-		gwHost, gwPort,
-		kafkaBrokers, kafkaTopic,
-		logLevel,
-		jwtSecretKey, jwtExpSecond, err := parseConfig("nonexistent.env")
-
-	if err != nil {
-		t.Fatalf("parseConfig returned error: %v", err)
-	}
-
-	// Application defaults
-	if appHost != "localhost" || appPort != "8080" || logLevel != "info" {
-		t.Errorf("unexpected app config: %v/%v/%v", appHost, appPort, logLevel)
-	}
-
-	// PostgreSQL defaults
-	if pgHost != "localhost" || pgPort != 5432 || pgUser != "user" || pgPassword != "password" || pgDB != "database" ||
-		pgMaxOpenConns != 16 || pgMaxIdleConns != 8 {
-		t.Errorf("unexpected postgres config")
-	}
-
-	// Redis defaults
-	if redisHost != "localhost" || redisPort != 6379 || redisDB != 0 || redisPassword != "" ||
-		redisPoolSize != 10 || redisMinIdleConns != 2 || redisExp != 60 {
-		t.Errorf("unexpected redis config")
-	}
-
-	// gRPC defaults
-	if gwHost != "localhost" || gwPort != "50051" {
-		t.Errorf("unexpected grpc config")
-	}
-
-	// Kafka defaults
-	if !reflect.DeepEqual(kafkaBrokers, []string{"localhost:9092"}) || kafkaTopic != "large-transactions" {
-		t.Errorf("unexpected kafka config: %v/%v", kafkaBrokers, kafkaTopic)
-	}
-
-	// JWT defaults
-	if jwtSecretKey != "my_super_secret_key" || jwtExpSecond != 60 {
-		t.Errorf("unexpected jwt config")
-	}
-}
-
-func TestParseConfig_CustomEnv(t *testing.T) {
-	resetEnv()
-
-	os.Setenv("APP_HOST", "127.0.0.1")
-	os.Setenv("APP_PORT", "9090")
-	os.Setenv("APP_LOG_LEVEL", "debug")
-
-	os.Setenv("POSTGRES_HOST", "pg.example.com")
-	os.Setenv("POSTGRES_PORT", "5433")
-	os.Setenv("POSTGRES_USER", "admin")
-	os.Setenv("POSTGRES_PASSWORD", "secret")
-	os.Setenv("POSTGRES_DB", "mydb")
-	os.Setenv("POSTGRES_MAX_OPEN_CONNS", "20")
-	os.Setenv("POSTGRES_MAX_IDLE_CONNS", "10")
-
-	os.Setenv("REDIS_HOST", "redis.example.com")
-	os.Setenv("REDIS_PORT", "6380")
-	os.Setenv("REDIS_DB", "2")
-	os.Setenv("REDIS_PASSWORD", "redispass")
-	os.Setenv("REDIS_POOL_SIZE", "15")
-	os.Setenv("REDIS_MIN_IDLE_CONNS", "5")
-	os.Setenv("REDIS_EXP_SECOND", "120")
-
-	os.Setenv("GW_EXCHANGER_HOST", "grpc.example.com")
-	os.Setenv("GW_EXCHANGER_PORT", "50052")
-
-	os.Setenv("KAFKA_BROKERS", "broker1:9092,broker2:9093")
-	os.Setenv("KAFKA_TOPIC", "custom-topic")
-
-	os.Setenv("JWT_SECRET_KEY", "supersecret")
-	os.Setenv("JWT_EXP_SECOND", "300")
-
-	appHost, appPort,
-		pgHost, pgPort, pgUser, pgPassword, pgDB,
-		pgMaxOpenConns, pgMaxIdleConns,
-		redisHost, redisPort, redisDB, redisPassword,
-		redisPoolSize, redisMinIdleConns, redisExp,
-		gwHost, gwPort,
-		kafkaBrokers, kafkaTopic,
-		logLevel,
-		jwtSecretKey, jwtExpSecond, err := parseConfig("nonexistent.env")
-
-	if err != nil {
-		t.Fatalf("parseConfig returned error: %v", err)
-	}
-
-	// Assertions
-	if appHost != "127.0.0.1" || appPort != "9090" || logLevel != "debug" {
-		t.Errorf("unexpected app config")
-	}
-
-	if pgHost != "pg.example.com" || pgPort != 5433 || pgUser != "admin" || pgPassword != "secret" || pgDB != "mydb" ||
-		pgMaxOpenConns != 20 || pgMaxIdleConns != 10 {
-		t.Errorf("unexpected postgres config")
-	}
-
-	if redisHost != "redis.example.com" || redisPort != 6380 || redisDB != 2 || redisPassword != "redispass" ||
-		redisPoolSize != 15 || redisMinIdleConns != 5 || redisExp != 120 {
-		t.Errorf("unexpected redis config")
-	}
-
-	if gwHost != "grpc.example.com" || gwPort != "50052" {
-		t.Errorf("unexpected grpc config")
-	}
-
-	expectedBrokers := []string{"broker1:9092", "broker2:9093"}
-	if !reflect.DeepEqual(kafkaBrokers, expectedBrokers) || kafkaTopic != "custom-topic" {
-		t.Errorf("unexpected kafka config: %v/%v", kafkaBrokers, kafkaTopic)
-	}
-
-	if jwtSecretKey != "supersecret" || jwtExpSecond != 300 {
-		t.Errorf("unexpected jwt config")
-	}
-}
-
 // ------------------ Mock gRPC Server ------------------
 
 type mockExchangeServer struct {
@@ -197,27 +65,14 @@ func startMockGRPCServer(t *testing.T) (addr string, stop func()) {
 
 // ------------------ Unit tests ------------------
 
-func TestParseFlags_Default(t *testing.T) {
-	resetFlags()
-	oldArgs := os.Args
-	defer func() { os.Args = oldArgs }()
-
-	os.Args = []string{"cmd"}
-	configPath := parseFlags()
-	if configPath != "config.env" {
-		t.Errorf("expected config.env, got %s", configPath)
+func TestNewKafkaWriter(t *testing.T) {
+	w := newKafkaWriter([]string{"localhost:9092"}, "", nil, kafka.RequireNone)
+	defer w.Close()
+	if w.RequiredAcks != kafka.RequireNone {
+		t.Errorf("unexpected required acks: %v", w.RequiredAcks)
 	}
-}
-
-func TestParseFlags_Custom(t *testing.T) {
-	resetFlags()
-	oldArgs := os.Args
-	defer func() { os.Args = oldArgs }()
-
-	os.Args = []string{"cmd", "-c", "myconfig.env"}
-	configPath := parseFlags()
-	if configPath != "myconfig.env" {
-		t.Errorf("expected myconfig.env, got %s", configPath)
+	if _, ok := w.Balancer.(*kafka.Hash); !ok {
+		t.Errorf("messages are not partitioned by key: %T", w.Balancer)
 	}
 }
 
@@ -245,6 +100,53 @@ func TestPrintBuildInfo_Output(t *testing.T) {
 	}
 }
 
+func TestWaitFor(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Startup{WaitSecond: 1, RetryBackoffMs: 10, RetryMaxBackoffMs: 40}
+
+	t.Run("dependency comes up", func(t *testing.T) {
+		attempts := 0
+		err := waitFor(ctx, cfg, "test", func(ctx context.Context) error {
+			attempts++
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("attempt without a deadline")
+			}
+			if attempts < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("wait exceeded", func(t *testing.T) {
+		started := time.Now()
+		err := waitFor(ctx, cfg, "test", func(context.Context) error { return errors.New("connection refused") })
+		assert.EqualError(t, err, "connection refused")
+		assert.WithinRange(t, time.Now(), started.Add(time.Second), started.Add(2*time.Second))
+	})
+
+	t.Run("no wait", func(t *testing.T) {
+		attempts := 0
+		err := waitFor(ctx, config.Startup{RetryBackoffMs: 10, RetryMaxBackoffMs: 10}, "test", func(context.Context) error {
+			attempts++
+			return errors.New("connection refused")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		err := waitFor(ctx, config.Startup{WaitSecond: 60, RetryBackoffMs: 1000, RetryMaxBackoffMs: 1000}, "test", func(context.Context) error {
+			return errors.New("connection refused")
+		})
+		assert.EqualError(t, err, "connection refused")
+	})
+}
+
 // ------------------ Full Integration Test ------------------
 
 func TestRun_FullIntegration(t *testing.T) {
@@ -252,37 +154,13 @@ func TestRun_FullIntegration(t *testing.T) {
 	logger.Initialize("debug")
 
 	// ------------------ PostgreSQL ------------------
-	pgReq := testcontainers.ContainerRequest{
-		Image:        "postgres:15",
-		Env:          map[string]string{"POSTGRES_PASSWORD": "password", "POSTGRES_DB": "testdb", "POSTGRES_USER": "user"},
-		ExposedPorts: []string{"5432/tcp"},
-		WaitingFor:   wait.ForListeningPort("5432/tcp").WithStartupTimeout(60 * time.Second),
-	}
-	pgContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{ContainerRequest: pgReq, Started: true})
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer pgContainer.Terminate(ctx)
-
-	pgHost, _ := pgContainer.Host(ctx)
-	pgPortNat, _ := pgContainer.MappedPort(ctx, "5432")
-	pgPort := pgPortNat.Int()
+	pg := testkit.StartPostgres(t)
 
 	// ------------------ Redis ------------------
-	redisReq := testcontainers.ContainerRequest{
-		Image:        "redis:7",
-		ExposedPorts: []string{"6379/tcp"},
-		WaitingFor:   wait.ForListeningPort("6379/tcp").WithStartupTimeout(30 * time.Second),
-	}
-	redisContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{ContainerRequest: redisReq, Started: true})
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer redisContainer.Terminate(ctx)
+	rd := testkit.StartRedis(t)
 
-	redisHost, _ := redisContainer.Host(ctx)
-	redisPortNat, _ := redisContainer.MappedPort(ctx, "6379")
-	redisPort := redisPortNat.Int()
+	// ------------------ Kafka ------------------
+	broker := testkit.Kafka(t)
 
 	// ------------------ Mock gRPC ------------------
 	grpcAddr, stopGRPC := startMockGRPCServer(t)
@@ -294,18 +172,27 @@ func TestRun_FullIntegration(t *testing.T) {
 	runCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
+	os.Clearenv()
+	cfg, err := config.Load([]string{"-c", "nonexistent.env"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.App.Host, cfg.App.Port, cfg.App.LogLevel = "127.0.0.1", "8086", "debug"
+	cfg.App.Env, cfg.App.InstanceID = "test", "wallet-test"
+	cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.DB =
+		pg.Host, pg.Port, pg.User, pg.Password, pg.Database
+	cfg.Postgres.MaxOpenConns, cfg.Postgres.MaxIdleConns = 5, 2
+	cfg.Redis.Host, cfg.Redis.Port = rd.Host, rd.Port
+	cfg.Exchanger.Host, cfg.Exchanger.Port = grpcHost, grpcPort
+	cfg.Kafka.Brokers = []string{broker}
+	cfg.Kafka.SecurityAlertTopic, cfg.Kafka.BalanceTopic = "security.alert", "wallet.balance"
+	cfg.Auth.JWTSecretKey, cfg.Auth.BcryptCost = "testsecret", 4
+	cfg.Exchange.ReceiptsEnabled, cfg.Exchange.PivotCurrency = true, "USD"
+	cfg.RateLimit = config.RateLimit{}
+
 	done := make(chan error, 1)
 	go func() {
-		done <- run(runCtx,
-			"127.0.0.1", "8086", // HTTP
-			pgHost, pgPort, "user", "password", "testdb",
-			5, 2, // Postgres max connections
-			redisHost, redisPort, 0, "", 10, 2, 60, // Redis
-			grpcHost, grpcPort, // gRPC
-			[]string{"localhost:9092"}, "large-transactions", // Kafka (not tested)
-			"debug",
-			"testsecret", 60,
-		)
+		done <- run(runCtx, cfg)
 	}()
 
 	// Wait a few seconds to let the server start
@@ -0,0 +1,434 @@
+// Package app wires repositories, services and handlers into a runnable application.
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/consumers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/geoip"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/handlers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/pubsub"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/migrations"
+	pb "github.com/sbilibin2017/proto-exchange/exchange"
+)
+
+// Infra holds the connections and clients the application is built on.
+// They are opened and closed by the caller.
+type Infra struct {
+	DB                        *sqlx.DB
+	ReplicaDB                 *sqlx.DB // Read replica for balances, users and transaction history; nil reads from DB
+	Redis                     *redis.Client
+	Exchanger                 pb.ExchangeServiceClient
+	TransactionWriter         services.EventPublisher  // Transaction events, routed to a topic per message if TransactionsTopic is set
+	TransactionEncoder        services.MessageEncoder  // Serializes transaction events; nil publishes JSON
+	SecurityAlertWriter       services.EventPublisher  // Suspicious login alerts topic
+	ReceiptWriter             services.EventPublisher  // Exchange receipts topic, read by the exchanger
+	RateAlertWriter           services.EventPublisher  // Fired rate alerts topic
+	BalanceWriter             services.EventPublisher  // Compacted wallet balances topic; nil disables it
+	PaymentConfirmationReader consumers.MessageReader  // Payment confirmations topic, credited to wallets; nil disables crediting
+	RedisHealth               handlers.CachePinger     // Redis check of the readiness probe
+	ExchangerHealth           handlers.ExchangerPinger // Exchanger connection check of the readiness probe
+	KafkaHealth               handlers.BrokerPinger    // Kafka check of the readiness probe; nil if Kafka is not used
+	JWT                       *jwt.JWT
+	Notifier                  services.Notifier
+}
+
+// Settings holds the tunables of the application services.
+type Settings struct {
+	RateCacheTTL           time.Duration // Initial freshness TTL of cached exchange rates
+	RateCacheTTLMin        time.Duration // Shortest TTL, reached while the exchanger is healthy
+	RateCacheTTLMax        time.Duration // Longest TTL
+	RateCacheSlowThreshold time.Duration // Exchanger latency above which the TTL is lengthened
+	RateMaxStaleness       time.Duration // Oldest cached rate served stale while the exchanger is down, 0 disables
+	RatePrewarmInterval    time.Duration // How often the cached rates are refreshed ahead of their TTL, 0 disables
+
+	BcryptCost          int
+	PasswordPepper      string
+	PasswordAllowLegacy bool
+
+	WalletProjectionEnabled  bool
+	WalletProjectionInterval time.Duration
+
+	ImpersonationTTL time.Duration
+
+	RegistrationDomainBlocklist []string
+	RegistrationDomainAllowlist []string
+	RegistrationDomainLimit     int
+	RegistrationDomainWindow    time.Duration
+
+	DormancyEnabled        bool
+	DormancyInactiveMonths int
+	DormancyCheckInterval  time.Duration
+
+	HistoryPartitionsAhead int    // Months after the current one whose history partitions are created in advance
+	HistoryRetentionMonths int    // Months of history kept before the current one, 0 keeps all
+	HistoryArchiveDir      string // Directory archived months of history are written to
+
+	GeoIPDatabasePath string
+
+	HealthCheckTimeout time.Duration // Timeout of every dependency check of the readiness probe, 0 disables it
+
+	RequestTimeout           time.Duration  // Budget of an HTTP request, 0 disables it
+	TrustedProxies           []netip.Prefix // Proxies whose forwarded client IP is trusted, none uses the peer address
+	LegacyBalanceDeprecation time.Time      // Deprecation date of the legacy balance schema, zero sends no Deprecation header
+	ExchangerTimeout         time.Duration  // Deadline of exchanger calls made outside a request
+	ExchangerRetryAttempts   int            // Attempts of an exchanger call failing transiently, 1 disables retries
+	ExchangerRetryBackoff    time.Duration  // Wait before the first retry, doubled for every further one
+	ExchangerRetryMaxBackoff time.Duration  // Longest wait between retries
+	ExchangerAttemptTimeout  time.Duration  // Deadline of every attempt within the call deadline, 0 disables it
+
+	RateProviderHTTPURL          string        // Base URL of the HTTP rates API the exchanger fails over to, empty disables it
+	RateProviderHTTPAppID        string        // App ID of the HTTP rates API
+	RateProviderFailureThreshold int           // Consecutive failures after which a rate provider is skipped
+	RateProviderCooldown         time.Duration // How long an unhealthy rate provider is skipped
+
+	ExchangerExportURL   string // Base URL of the exchanger's receipts export, empty disables reconciliation
+	ExchangerExportToken string // Bearer token of the export
+
+	SchemaDriftCheckEnabled bool // Compare the live schema against the migrations at startup
+
+	TxRetryAttempts   int           // Runs of a transaction aborted by a serialization failure or deadlock, 1 disables retries
+	TxRetryBackoff    time.Duration // Wait before the first retry, doubled for every further one
+	TxRetryMaxBackoff time.Duration // Longest wait between retries
+
+	UserLockTTL  time.Duration // Longest time a user's money operation holds the per-user lock
+	UserLockWait time.Duration // How long a concurrent operation of the same user waits for the lock
+
+	KafkaPublishAttempts     int           // Attempts to publish a transaction event before it is dead-lettered
+	KafkaPublishRetryBackoff time.Duration // Wait before the first retry of a publish, doubled for every further one
+
+	EventPublishAsync  bool          // Buffer transaction events and publish them in batches in the background
+	EventBufferSize    int           // Events buffered before callers publish synchronously
+	EventBatchSize     int           // Largest number of events published at once
+	EventFlushInterval time.Duration // Longest time an event waits in the buffer for its batch to fill
+
+	LargeTransactionsTopic     string                  // Topic of transaction events above the threshold of their currency
+	TransactionsTopic          string                  // Topic of the other transaction events, "" publishes all to the writer's topic
+	LargeTransactionThresholds map[string]money.Amount // Amounts above which a transaction is large, by currency
+
+	ExchangeReceiptsEnabled bool         // Send receipts of executed conversions to the exchanger
+	ExchangePivotCurrency   string       // Currency cross rates are derived through for pairs without a direct rate, "" disables
+	ExchangeMinAmount       money.Amount // Least amount of an exchange in the source currency, 0 disables
+	ExchangeMaxAmount       money.Amount // Largest amount of an exchange in the source currency, 0 disables
+
+	WalletInitialCurrencies []string // Currencies of the empty wallets opened for every registered user
+
+	RateLimitPublic int // Requests per RateLimitWindow per client IP to public endpoints, 0 disables
+	RateLimitRead   int // Authenticated reads per RateLimitWindow per user, 0 disables
+	RateLimitWrite  int // Authenticated changes per RateLimitWindow per user, 0 disables
+
+	GRPCGatewayEnabled bool // Serve the REST gateway generated from proto/wallet under /v2
+	GraphQLEnabled     bool // Serve the GraphQL API at /graphql
+}
+
+// JobRegistrar registers periodic background jobs.
+type JobRegistrar interface {
+	Register(name string, interval time.Duration, run func(ctx context.Context) error)
+}
+
+// Container holds the application services.
+type Container struct {
+	infra    Infra
+	settings Settings
+
+	Auth                    *services.AuthService
+	LoginHistory            *services.LoginHistoryService
+	RegistrationPolicy      *services.RegistrationPolicy
+	Impersonation           *services.ImpersonationService
+	Currencies              *services.CurrencyService
+	Wallet                  *services.WalletService
+	BalanceHistory          *services.BalanceHistoryService
+	RateHistory             *services.RateHistoryService
+	RateAlerts              *services.RateAlertService
+	Export                  *services.ExportService
+	Dormancy                *services.DormancyService
+	TransactionArchive      *services.TransactionArchiveService
+	NotificationPreferences *services.NotificationPreferenceService
+	WalletLimits            *services.WalletLimitService
+	BalanceProjector        *services.BalanceProjector
+	SchemaDrift             *services.SchemaDriftService
+	ExchangeReceipts        *services.ExchangeReceiptService
+	DeadLetters             *services.DeadLetterService
+	EventBatcher            *services.EventBatcher // Nil when events are published synchronously
+	Ledger                  *services.LedgerService
+	ExchangeReconciliation  *services.ExchangeReconciliationService
+	Webhooks                *services.WebhookService
+	ReceiveQR               *services.ReceiveQRService
+	PaymentConfirmations    *consumers.Consumer
+	BalanceUpdates          *pubsub.Broker[models.BalanceUpdate] // Feeds the balance event streams
+}
+
+// NewContainer builds the repositories and services on top of infra.
+func NewContainer(infra Infra, settings Settings) (*Container, error) {
+	db := infra.DB
+	readPool := repositories.NewReadPool(db, infra.ReplicaDB)
+
+	// Repositories
+	userReadRepo := repositories.NewUserReadRepository(readPool)
+	userWriteRepo := repositories.NewUserWriteRepository(db)
+	walletReaderRepo := repositories.NewWalletReaderRepository(db)
+	walletWriterRepo := repositories.NewWalletWriterRepository(db, repositories.TxFromContext)
+	rateCacheExp := max(settings.RateCacheTTLMax, settings.RateMaxStaleness)
+	exchangeRateCacheRepo := repositories.NewLocalRateCache(
+		repositories.NewExchangeRateCacheRepository(infra.Redis, rateCacheExp), repositories.LocalRateCacheSize, rateCacheExp,
+	)
+	exchangeQuoteRepo := repositories.NewExchangeQuoteRepository(infra.Redis)
+	exchangeFeeRepo := repositories.NewExchangeFeeRepository(db)
+	rateHistoryRepo := repositories.NewRateHistoryRepository(db)
+	rateAlertRepo := repositories.NewRateAlertRepository(db)
+	exchangeGRPCFacade := facades.NewExchangeRatesGRPCFacade(infra.Exchanger,
+		facades.WithCallTimeout(settings.ExchangerTimeout),
+		facades.WithRetry(settings.ExchangerRetryAttempts, settings.ExchangerRetryBackoff, settings.ExchangerRetryMaxBackoff),
+		facades.WithAttemptTimeout(settings.ExchangerAttemptTimeout),
+	)
+	rateProviders := []services.RateProvider{exchangeGRPCFacade}
+	if settings.RateProviderHTTPURL != "" {
+		rateProviders = append(rateProviders, facades.NewHTTPRatesFacade(
+			&http.Client{Timeout: settings.ExchangerTimeout}, settings.RateProviderHTTPURL, settings.RateProviderHTTPAppID,
+		))
+	}
+	rateProviderSet := services.NewRateProviders(settings.RateProviderFailureThreshold, settings.RateProviderCooldown, rateProviders...)
+	balanceProjectionRepo := repositories.NewBalanceProjectionRepository(db)
+	auditWriteRepo := repositories.NewAuditWriteRepository(db)
+	exportRepo := repositories.NewExportRepository(db)
+	walletEventReadRepo := repositories.NewWalletEventReadRepository(db)
+	registrationLimitRepo := repositories.NewRegistrationLimitRepository(infra.Redis)
+	authEventRepo := repositories.NewAuthEventRepository(db)
+	dormancyRepo := repositories.NewDormancyRepository(db)
+	notificationPrefRepo := repositories.NewNotificationPreferenceRepository(db)
+	transactionRepo := repositories.NewTransactionRepository(db, readPool, repositories.TxFromContext)
+	walletLimitRepo := repositories.NewWalletLimitRepository(db)
+	walletHoldRepo := repositories.NewWalletHoldRepository(db, repositories.TxFromContext)
+	walletPotRepo := repositories.NewWalletPotRepository(db)
+	walletDetailsRepo := repositories.NewWalletDetailsRepository(db)
+	paymentRequestRepo := repositories.NewPaymentRequestRepository(db, repositories.TxFromContext)
+	currencyRepo := repositories.NewCurrencyRepository(db)
+	schemaRepo := repositories.NewSchemaRepository(db)
+	exchangeReceiptRepo := repositories.NewExchangeReceiptRepository(db, repositories.TxFromContext)
+	balanceHistoryRepo := repositories.NewBalanceHistoryRepository(db)
+	ledgerRepo := repositories.NewLedgerRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	processedMessageRepo := repositories.NewProcessedMessageRepository(db, repositories.TxFromContext)
+	deadLetterRepo := repositories.NewDeadLetterRepository(db)
+	txRunner := repositories.NewTxRunner(db,
+		repositories.WithTxRetry(settings.TxRetryAttempts, settings.TxRetryBackoff, settings.TxRetryMaxBackoff),
+	)
+
+	c := &Container{infra: infra, settings: settings}
+
+	// Services
+	loginAlertService := services.NewLoginAlertService(authEventRepo, notificationPrefRepo, infra.Notifier, infra.SecurityAlertWriter)
+	authOpts := []services.AuthOpt{
+		services.WithBcryptCost(settings.BcryptCost),
+		services.WithPepper(settings.PasswordPepper),
+		services.WithLegacyHashes(settings.PasswordAllowLegacy),
+		services.WithAuthEvents(authEventRepo),
+		services.WithLoginAlerts(loginAlertService),
+		services.WithInitialWallets(walletWriterRepo, settings.WalletInitialCurrencies),
+	}
+	if settings.GeoIPDatabasePath != "" {
+		locator, err := geoip.NewCSVLocator(settings.GeoIPDatabasePath)
+		if err != nil {
+			return nil, err
+		}
+		authOpts = append(authOpts, services.WithGeoLocator(locator))
+	}
+	c.Auth = services.NewAuthService(userReadRepo, userWriteRepo, infra.JWT, authOpts...)
+	c.LoginHistory = services.NewLoginHistoryService(authEventRepo)
+	c.ReceiveQR = services.NewReceiveQRService(userReadRepo)
+	c.RegistrationPolicy = services.NewRegistrationPolicy(registrationLimitRepo,
+		settings.RegistrationDomainBlocklist, settings.RegistrationDomainAllowlist,
+		settings.RegistrationDomainLimit, settings.RegistrationDomainWindow,
+	)
+	c.Impersonation = services.NewImpersonationService(userReadRepo, auditWriteRepo, infra.JWT, settings.ImpersonationTTL)
+
+	c.Currencies = services.NewCurrencyService(currencyRepo, services.CurrencyCacheTTL)
+	// A slow receiver must not hold up the deliveries to the other webhooks
+	c.Webhooks = services.NewWebhookService(webhookRepo, services.NewWebhookClient(10*time.Second))
+	c.BalanceUpdates = pubsub.NewBroker[models.BalanceUpdate](services.BalanceUpdateBuffer)
+	walletOpts := []services.WalletOpt{
+		services.WithCurrencies(c.Currencies),
+		services.WithCurrencyPrecision(c.Currencies),
+		services.WithTransactionHistory(transactionRepo, txRunner),
+		services.WithWalletLocks(walletWriterRepo),
+		services.WithSpendingLimits(walletLimitRepo),
+		services.WithOverdraftLimits(walletReaderRepo),
+		services.WithHolds(walletHoldRepo),
+		services.WithPots(walletPotRepo),
+		services.WithQuoteLocking(exchangeQuoteRepo, services.ExchangeQuoteTTL),
+		services.WithExchangeFees(exchangeFeeRepo),
+		services.WithRateHistory(rateHistoryRepo),
+		services.WithCrossRates(settings.ExchangePivotCurrency),
+		services.WithExchangeAmountLimits(settings.ExchangeMinAmount, settings.ExchangeMaxAmount),
+		services.WithWalletDetails(walletDetailsRepo),
+		services.WithPaymentRequests(paymentRequestRepo, userReadRepo, services.PaymentRequestTTL),
+		services.WithReversals(transactionRepo, txRunner, auditWriteRepo),
+		services.WithWebhooks(c.Webhooks),
+		services.WithRateTTL(services.NewAdaptiveRateTTL(
+			settings.RateCacheTTL, settings.RateCacheTTLMin, settings.RateCacheTTLMax, settings.RateCacheSlowThreshold,
+		)),
+		services.WithMaxRateStaleness(settings.RateMaxStaleness),
+		services.WithBalanceNotifier(c.BalanceUpdates),
+	}
+	if settings.TransactionsTopic != "" {
+		walletOpts = append(walletOpts, services.WithTransactionTopics(
+			settings.LargeTransactionsTopic, settings.TransactionsTopic, settings.LargeTransactionThresholds,
+		))
+	}
+	if settings.WalletProjectionEnabled {
+		c.BalanceProjector = services.NewBalanceProjector(balanceProjectionRepo, 1000)
+		walletOpts = append(walletOpts, services.WithBalanceReadModel(balanceProjectionRepo))
+	} else if infra.ReplicaDB != nil {
+		// Balances returned after money operations stay on the primary, which has them first
+		walletOpts = append(walletOpts, services.WithBalanceReadModel(repositories.NewWalletReaderRepository(readPool)))
+	}
+	if settings.ExchangeReceiptsEnabled {
+		c.ExchangeReceipts = services.NewExchangeReceiptService(exchangeReceiptRepo, infra.ReceiptWriter)
+		walletOpts = append(walletOpts, services.WithExchangeReceipts(c.ExchangeReceipts))
+	}
+	if infra.PaymentConfirmationReader != nil {
+		walletOpts = append(walletOpts, services.WithPaymentConfirmations(processedMessageRepo, txRunner))
+	}
+	if infra.BalanceWriter != nil {
+		walletOpts = append(walletOpts, services.WithBalanceStream(infra.BalanceWriter))
+	}
+	if infra.TransactionEncoder != nil {
+		walletOpts = append(walletOpts, services.WithMessageEncoder(infra.TransactionEncoder))
+	}
+	c.DeadLetters = services.NewDeadLetterService(deadLetterRepo, infra.TransactionWriter,
+		settings.KafkaPublishAttempts, settings.KafkaPublishRetryBackoff,
+	)
+	// Transaction events that cannot be published are dead-lettered rather than dropped
+	var transactionWriter services.EventPublisher
+	if infra.TransactionWriter != nil {
+		transactionWriter = c.DeadLetters
+		if settings.EventPublishAsync {
+			c.EventBatcher = services.NewEventBatcher(c.DeadLetters,
+				settings.EventBufferSize, settings.EventBatchSize, settings.EventFlushInterval,
+			)
+			transactionWriter = c.EventBatcher
+		}
+	}
+	c.Wallet = services.NewWalletService(walletWriterRepo, walletReaderRepo, rateProviderSet, exchangeRateCacheRepo, transactionWriter, walletOpts...)
+	if infra.PaymentConfirmationReader != nil {
+		c.PaymentConfirmations = consumers.NewConsumer(services.PaymentConfirmationConsumer,
+			infra.PaymentConfirmationReader, consumers.NewPaymentConfirmationHandler(c.Wallet),
+		)
+	}
+	c.BalanceHistory = services.NewBalanceHistoryService(balanceHistoryRepo)
+	c.RateHistory = services.NewRateHistoryService(rateHistoryRepo)
+	c.RateAlerts = services.NewRateAlertService(rateAlertRepo, c.Wallet, userReadRepo, notificationPrefRepo,
+		infra.Notifier, infra.RateAlertWriter,
+	)
+	c.Ledger = services.NewLedgerService(ledgerRepo)
+	var exchangeRecords services.ExchangeRecordSource
+	if settings.ExchangerExportURL != "" {
+		exchangeRecords = facades.NewExchangeExportFacade(
+			&http.Client{Timeout: time.Minute}, settings.ExchangerExportURL, settings.ExchangerExportToken,
+		)
+	}
+	c.ExchangeReconciliation = services.NewExchangeReconciliationService(exchangeReceiptRepo, exchangeRecords)
+	c.Export = services.NewExportService(exportRepo, exportRepo, walletEventReadRepo,
+		services.WithUserData(userReadRepo, walletReaderRepo, transactionRepo, authEventRepo),
+	)
+	c.Dormancy = services.NewDormancyService(dormancyRepo, userReadRepo, c.Auth,
+		infra.Notifier, auditWriteRepo, settings.DormancyInactiveMonths,
+	)
+	var transactionArchive services.TransactionArchive
+	if settings.HistoryArchiveDir != "" {
+		transactionArchive = repositories.NewTransactionArchiveRepository(settings.HistoryArchiveDir)
+	}
+	c.TransactionArchive = services.NewTransactionArchiveService(repositories.NewTransactionPartitionRepository(db),
+		transactionArchive, settings.HistoryPartitionsAhead, settings.HistoryRetentionMonths,
+	)
+	c.NotificationPreferences = services.NewNotificationPreferenceService(notificationPrefRepo, notificationPrefRepo)
+	c.WalletLimits = services.NewWalletLimitService(walletLimitRepo, userReadRepo, auditWriteRepo)
+
+	schemaDrift, err := services.NewSchemaDriftService(migrations.Files, schemaRepo)
+	if err != nil {
+		return nil, err
+	}
+	c.SchemaDrift = schemaDrift
+
+	return c, nil
+}
+
+// CheckSchema compares the live schema against the migrations without applying them, if enabled.
+// Drift is logged and reported by /readyz as warnings; it never stops the service.
+func (c *Container) CheckSchema(ctx context.Context) {
+	if !c.settings.SchemaDriftCheckEnabled {
+		return
+	}
+	_, _ = c.SchemaDrift.Check(ctx)
+}
+
+// Close publishes the buffered events. It is called once the HTTP server and the consumers
+// have stopped, before the event writers are closed.
+func (c *Container) Close() {
+	if c.EventBatcher != nil {
+		c.EventBatcher.Close()
+	}
+}
+
+// CloseStreams ends the open event streams, which would otherwise hold up the graceful
+// shutdown of the HTTP server.
+func (c *Container) CloseStreams() {
+	c.BalanceUpdates.Close()
+}
+
+// RegisterJobs registers the enabled background jobs.
+func (c *Container) RegisterJobs(jobs JobRegistrar) {
+	if c.BalanceProjector != nil {
+		jobs.Register("balance-projection", c.settings.WalletProjectionInterval, c.BalanceProjector.Project)
+	}
+	jobs.Register("exports", 5*time.Second, c.Export.ProcessPending)
+	jobs.Register("limit-usage-cleanup", time.Hour, c.WalletLimits.PurgeUsage)
+	// Hourly runs overwrite the day's snapshot, the last one keeps the closing balance
+	jobs.Register("balance-snapshot", time.Hour, c.BalanceHistory.Snapshot)
+	jobs.Register("transaction-partitions", time.Hour, c.TransactionArchive.Maintain)
+	jobs.Register("ledger-reconciliation", time.Hour, c.Ledger.Reconcile)
+	jobs.Register("webhooks", 5*time.Second, c.Webhooks.DeliverPending)
+	jobs.Register("payment-request-expiry", time.Minute, c.Wallet.ExpirePaymentRequests)
+	jobs.Register("rate-alerts", time.Minute, c.RateAlerts.Watch)
+	if c.settings.RatePrewarmInterval > 0 {
+		jobs.Register("rate-prewarm", c.settings.RatePrewarmInterval, c.Wallet.WarmRates)
+	}
+	if c.settings.DormancyEnabled {
+		jobs.Register("dormancy", c.settings.DormancyCheckInterval, c.Dormancy.FlagInactive)
+	}
+	if c.ExchangeReceipts != nil {
+		jobs.Register("exchange-receipts", 5*time.Second, c.ExchangeReceipts.PublishPending)
+	}
+	if c.settings.ExchangerExportURL != "" {
+		jobs.Register("exchange-reconciliation", time.Hour, c.ExchangeReconciliation.Reconcile)
+	}
+}
+
+// RunConsumers consumes the enabled Kafka topics until ctx is done, then waits for the
+// consumers to finish the messages in flight and leave their groups.
+func (c *Container) RunConsumers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, consumer := range []*consumers.Consumer{c.PaymentConfirmations} {
+		if consumer == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			consumer.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,387 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/consumers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/notifications"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type registeredJob struct {
+	name     string
+	interval time.Duration
+}
+
+type fakeRegistrar struct {
+	jobs []registeredJob
+}
+
+func (f *fakeRegistrar) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	f.jobs = append(f.jobs, registeredJob{name: name, interval: interval})
+}
+
+func testInfra() Infra {
+	return Infra{
+		JWT:      jwt.New(jwt.WithSecretKey("secret")),
+		Notifier: notifications.NewLogNotifier(),
+	}
+}
+
+func testSettings() Settings {
+	return Settings{
+		BcryptCost:               bcrypt.MinCost,
+		WalletProjectionInterval: time.Second,
+		DormancyInactiveMonths:   12,
+		DormancyCheckInterval:    time.Hour,
+	}
+}
+
+func TestNewContainer(t *testing.T) {
+	c, err := NewContainer(testInfra(), testSettings())
+	assert.NoError(t, err)
+	assert.NotNil(t, c.Auth)
+	assert.NotNil(t, c.Wallet)
+	assert.NotNil(t, c.Dormancy)
+	assert.Nil(t, c.BalanceProjector)
+	assert.NotNil(t, c.SchemaDrift)
+	c.CheckSchema(context.Background()) // Disabled, so the missing database is not queried
+
+	settings := testSettings()
+	settings.GeoIPDatabasePath = filepath.Join(t.TempDir(), "missing.csv")
+	_, err = NewContainer(testInfra(), settings)
+	assert.Error(t, err)
+}
+
+func TestContainer_RegisterJobs(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c, err := NewContainer(testInfra(), testSettings())
+		assert.NoError(t, err)
+
+		registrar := &fakeRegistrar{}
+		c.RegisterJobs(registrar)
+		assert.Equal(t, []registeredJob{
+			{name: "exports", interval: 5 * time.Second},
+			{name: "limit-usage-cleanup", interval: time.Hour},
+			{name: "balance-snapshot", interval: time.Hour},
+			{name: "transaction-partitions", interval: time.Hour},
+			{name: "ledger-reconciliation", interval: time.Hour},
+			{name: "webhooks", interval: 5 * time.Second},
+			{name: "payment-request-expiry", interval: time.Minute},
+			{name: "rate-alerts", interval: time.Minute},
+		}, registrar.jobs)
+	})
+
+	t.Run("all enabled", func(t *testing.T) {
+		settings := testSettings()
+		settings.WalletProjectionEnabled = true
+		settings.DormancyEnabled = true
+		settings.ExchangeReceiptsEnabled = true
+		settings.RatePrewarmInterval = 5 * time.Second
+		settings.ExchangerExportURL = "http://exchanger:8080"
+		c, err := NewContainer(testInfra(), settings)
+		assert.NoError(t, err)
+
+		registrar := &fakeRegistrar{}
+		c.RegisterJobs(registrar)
+		assert.Equal(t, []registeredJob{
+			{name: "balance-projection", interval: time.Second},
+			{name: "exports", interval: 5 * time.Second},
+			{name: "limit-usage-cleanup", interval: time.Hour},
+			{name: "balance-snapshot", interval: time.Hour},
+			{name: "transaction-partitions", interval: time.Hour},
+			{name: "ledger-reconciliation", interval: time.Hour},
+			{name: "webhooks", interval: 5 * time.Second},
+			{name: "payment-request-expiry", interval: time.Minute},
+			{name: "rate-alerts", interval: time.Minute},
+			{name: "rate-prewarm", interval: 5 * time.Second},
+			{name: "dormancy", interval: time.Hour},
+			{name: "exchange-receipts", interval: 5 * time.Second},
+			{name: "exchange-reconciliation", interval: time.Hour},
+		}, registrar.jobs)
+	})
+}
+
+func TestContainer_RunConsumers(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		c, err := NewContainer(testInfra(), testSettings())
+		assert.NoError(t, err)
+		assert.Nil(t, c.PaymentConfirmations)
+		c.RunConsumers(context.Background()) // Returns at once without consumers
+	})
+
+	t.Run("payment confirmations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := consumers.NewMockMessageReader(ctrl)
+		infra := testInfra()
+		infra.PaymentConfirmationReader = reader
+		c, err := NewContainer(infra, testSettings())
+		assert.NoError(t, err)
+		if assert.NotNil(t, c.PaymentConfirmations) {
+			assert.Equal(t, "payment-confirmations", c.PaymentConfirmations.Name())
+		}
+
+		// Stops at shutdown and leaves the consumer group
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reader.EXPECT().FetchMessage(gomock.Any()).Return(kafka.Message{}, context.Canceled)
+		reader.EXPECT().Close().Return(nil)
+		c.RunConsumers(ctx)
+	})
+}
+
+func TestContainer_Close(t *testing.T) {
+	t.Run("synchronous publishing", func(t *testing.T) {
+		infra := testInfra()
+		infra.TransactionWriter = services.NewMockEventPublisher(gomock.NewController(t))
+		c, err := NewContainer(infra, testSettings())
+		assert.NoError(t, err)
+		assert.Nil(t, c.EventBatcher)
+		c.Close()
+	})
+
+	t.Run("asynchronous publishing", func(t *testing.T) {
+		infra := testInfra()
+		infra.TransactionWriter = services.NewMockEventPublisher(gomock.NewController(t))
+		settings := testSettings()
+		settings.EventPublishAsync = true
+		settings.EventBufferSize = 10
+		settings.EventBatchSize = 5
+		settings.EventFlushInterval = time.Hour
+		c, err := NewContainer(infra, settings)
+		assert.NoError(t, err)
+		assert.NotNil(t, c.EventBatcher)
+		c.Close() // Nothing buffered, so nothing is written
+	})
+}
+
+func TestContainer_Router(t *testing.T) {
+	c, err := NewContainer(testInfra(), testSettings())
+	assert.NoError(t, err)
+
+	handler := c.Router("http://localhost:8080/swagger/doc.json")
+
+	var routes []string
+	err = chi.Walk(handler.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+route)
+		return nil
+	})
+	assert.NoError(t, err)
+	for _, route := range []string{
+		"POST /register",
+		"POST /login",
+		"GET /errors",
+		"GET /currencies",
+		"GET /healthz",
+		"GET /readyz",
+		"GET /balance",
+		"GET /balance/total",
+		"GET /events/balance",
+		"GET /wallet/balance/history",
+		"POST /wallet/deposit",
+		"POST /wallet/withdraw",
+		"GET /wallet/transactions",
+		"POST /wallet",
+		"GET /wallet/receive/qr",
+		"POST /wallet/close",
+		"PATCH /wallet/{currency}",
+		"GET /wallet/pots",
+		"POST /wallet/pots",
+		"POST /wallet/pots/move",
+		"PATCH /wallet/pots/{potID}",
+		"DELETE /wallet/pots/{potID}",
+		"POST /wallet/holds",
+		"POST /wallet/holds/{holdID}/capture",
+		"POST /wallet/holds/{holdID}/release",
+		"GET /exchange/rates",
+		"GET /exchange/rates/history",
+		"POST /exchange/alerts",
+		"GET /exchange/alerts",
+		"PUT /exchange/alerts/{alertID}",
+		"DELETE /exchange/alerts/{alertID}",
+		"GET /exchange/quote",
+		"GET /exchange/history",
+		"POST /exchange",
+		"POST /payment-requests",
+		"GET /payment-requests",
+		"POST /payment-requests/{requestID}/accept",
+		"POST /payment-requests/{requestID}/decline",
+		"POST /exports",
+		"GET /exports/{exportID}",
+		"POST /me/export",
+		"GET /me/export",
+		"GET /me/logins",
+		"POST /me/reactivate",
+		"GET /me/notification-preferences",
+		"PUT /me/notification-preferences",
+		"POST /webhooks",
+		"GET /webhooks",
+		"DELETE /webhooks/{webhookID}",
+		"POST /admin/impersonate/{userID}",
+		"PUT /admin/users/{userID}/dormant",
+		"DELETE /admin/users/{userID}/dormant",
+		"GET /admin/users/{userID}/limits",
+		"PUT /admin/users/{userID}/limits/{currency}",
+		"PUT /admin/users/{userID}/wallets/{currency}/overdraft",
+		"POST /admin/transactions/{transactionID}/reverse",
+		"POST /admin/webhooks",
+		"GET /admin/webhooks",
+		"DELETE /admin/webhooks/{webhookID}",
+		"GET /admin/reconciliation",
+		"GET /admin/dead-letters",
+		"POST /admin/dead-letters/{deadLetterID}/replay",
+		"GET /metrics",
+		"GET /swagger/*",
+	} {
+		assert.Contains(t, routes, route)
+	}
+
+	t.Run("authenticated routes require token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/balance", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("admin routes require admin", func(t *testing.T) {
+		token, err := testInfra().JWT.Generate(context.Background(), uuid.New())
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/users/"+uuid.NewString()+"/limits", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestContainer_RouteDeadline(t *testing.T) {
+	settings := testSettings()
+	settings.RequestTimeout = time.Minute
+	c, err := NewContainer(testInfra(), settings)
+	assert.NoError(t, err)
+
+	hasDeadline := func(rt Route) bool {
+		var ok bool
+		var h http.Handler = http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			_, ok = r.Context().Deadline()
+		})
+		chain := c.routeMiddlewares()(rt)
+		for i := len(chain) - 1; i >= 0; i-- {
+			h = chain[i](h)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+		return ok
+	}
+
+	rt := Route{Name: "test", Method: http.MethodGet, Path: "/test", Auth: AuthPublic, RateLimit: RateLimitUnlimited}
+	assert.True(t, hasDeadline(rt))
+	rt.Stream = true
+	assert.False(t, hasDeadline(rt), "streams stay open until the client goes away")
+}
+
+func TestContainer_GRPCServer(t *testing.T) {
+	c, err := NewContainer(testInfra(), testSettings())
+	assert.NoError(t, err)
+
+	srv := c.GRPCServer()
+	defer srv.Stop()
+
+	var methods []string
+	for _, m := range srv.GetServiceInfo()["wallet.WalletService"].Methods {
+		methods = append(methods, m.Name)
+	}
+	assert.ElementsMatch(t, []string{"Register", "Login", "GetBalance", "Deposit", "Withdraw", "Exchange"}, methods)
+}
+
+func TestContainer_GatewayRoutes(t *testing.T) {
+	walk := func(handler http.Handler) []string {
+		var routes []string
+		err := chi.Walk(handler.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			routes = append(routes, method+" "+route)
+			return nil
+		})
+		assert.NoError(t, err)
+		return routes
+	}
+	gatewayRoutes := []string{
+		"POST /v2/register",
+		"POST /v2/login",
+		"GET /v2/balance",
+		"POST /v2/wallet/deposit",
+		"POST /v2/wallet/withdraw",
+		"POST /v2/exchange",
+	}
+
+	c, err := NewContainer(testInfra(), testSettings())
+	assert.NoError(t, err)
+	assert.NotContains(t, walk(c.Router("")), "GET /v2/balance")
+
+	settings := testSettings()
+	settings.GRPCGatewayEnabled = true
+	c, err = NewContainer(testInfra(), settings)
+	assert.NoError(t, err)
+	handler := c.Router("")
+	assert.Subset(t, walk(handler), gatewayRoutes)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/balance", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestContainer_GraphQLRoute(t *testing.T) {
+	c, err := NewContainer(testInfra(), testSettings())
+	assert.NoError(t, err)
+	rec := httptest.NewRecorder()
+	c.Router("").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	settings := testSettings()
+	settings.GraphQLEnabled = true
+	c, err = NewContainer(testInfra(), settings)
+	assert.NoError(t, err)
+	assert.NoError(t, validateRoutes(c.routes("")))
+
+	rec = httptest.NewRecorder()
+	c.Router("").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRoutes(t *testing.T) {
+	c, err := NewContainer(testInfra(), testSettings())
+	assert.NoError(t, err)
+	assert.NoError(t, validateRoutes(c.routes("")))
+
+	handler := http.NotFoundHandler()
+	valid := Route{Name: "test", Method: http.MethodGet, Path: "/test", Handler: handler, Auth: AuthUser, RateLimit: RateLimitRead}
+	assert.NoError(t, valid.validate())
+
+	for name, rt := range map[string]Route{
+		"no handler":        {Name: "test", Method: http.MethodGet, Path: "/test", Auth: AuthUser, RateLimit: RateLimitRead},
+		"no auth":           {Name: "test", Method: http.MethodGet, Path: "/test", Handler: handler, RateLimit: RateLimitRead},
+		"no rate limit":     {Name: "test", Method: http.MethodGet, Path: "/test", Handler: handler, Auth: AuthUser},
+		"unknown class":     {Name: "test", Method: http.MethodGet, Path: "/test", Handler: handler, Auth: AuthUser, RateLimit: "bulk"},
+		"public money move": {Name: "test", Method: http.MethodPost, Path: "/test", Handler: handler, Auth: AuthPublic, RateLimit: RateLimitPublic, MovesMoney: true},
+	} {
+		assert.Error(t, rt.validate(), name)
+	}
+
+	assert.ErrorContains(t, validateRoutes([]Route{valid, valid}), "duplicate name")
+}
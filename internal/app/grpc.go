@@ -0,0 +1,87 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/graphql"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/grpcserver"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"google.golang.org/grpc"
+)
+
+// GRPCServer builds the gRPC server of the wallet API. Its calls go through the checks of
+// the REST routes: authentication and, for money-moving methods, dormancy and the per-user
+// lock. opts configure the transport, e.g. TLS credentials.
+func (c *Container) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(c.grpcInterceptors()...))
+
+	srv := grpc.NewServer(opts...)
+	pb.RegisterWalletServiceServer(srv, c.walletServer())
+	return srv
+}
+
+// gatewayRoutes returns the routes of the REST gateway generated from proto/wallet. The
+// gateway runs the gRPC interceptors itself, so its money-moving routes leave dormancy and
+// the per-user lock to them; auth and rate limits apply as to the other routes.
+func (c *Container) gatewayRoutes() []Route {
+	gateway := grpcserver.Gateway(c.walletServer(), c.grpcInterceptors()...)
+
+	return []Route{
+		{
+			Name: "v2-register", Method: http.MethodPost, Path: "/v2/register",
+			Handler: gateway, Auth: AuthPublic, RateLimit: RateLimitPublic,
+		},
+		{
+			Name: "v2-login", Method: http.MethodPost, Path: "/v2/login",
+			Handler: gateway, Auth: AuthPublic, RateLimit: RateLimitPublic,
+		},
+		{
+			Name: "v2-balance", Method: http.MethodGet, Path: "/v2/balance",
+			Handler: gateway, Auth: AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "v2-deposit", Method: http.MethodPost, Path: "/v2/wallet/deposit",
+			Handler: gateway, Auth: AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "v2-withdraw", Method: http.MethodPost, Path: "/v2/wallet/withdraw",
+			Handler: gateway, Auth: AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "v2-exchange", Method: http.MethodPost, Path: "/v2/exchange",
+			Handler: gateway, Auth: AuthUser, RateLimit: RateLimitWrite,
+		},
+	}
+}
+
+// graphqlRoute returns the route of the GraphQL API. Its balance query and mutations call
+// the WalletService in-process through the gRPC interceptors, like the gateway, so the route
+// leaves dormancy and the per-user lock to them.
+func (c *Container) graphqlRoute() Route {
+	wallet := grpcserver.Intercept(c.walletServer(), c.grpcInterceptors()...)
+	schema := graphql.NewWalletSchema(wallet, c.Wallet, c.Wallet, c.Currencies)
+
+	return Route{
+		Name: "graphql", Method: http.MethodPost, Path: "/graphql",
+		Handler: graphql.NewHandler(schema, c.infra.JWT),
+		Auth:    AuthUser, RateLimit: RateLimitWrite,
+	}
+}
+
+// walletServer returns the implementation of the WalletService.
+func (c *Container) walletServer() pb.WalletServiceServer {
+	return grpcserver.NewWalletServer(c.Auth, c.RegistrationPolicy, c.Wallet, c.Currencies)
+}
+
+// grpcInterceptors returns the interceptors of the wallet API, outermost first.
+func (c *Container) grpcInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		requestid.UnaryServerInterceptor,
+		grpcserver.LoggingInterceptor,
+		grpcserver.AuthInterceptor(c.infra.JWT),
+		grpcserver.MoneyInterceptor(c.Dormancy, locks.NewRedisLocker(c.infra.Redis),
+			c.settings.UserLockTTL, c.settings.UserLockWait),
+	}
+}
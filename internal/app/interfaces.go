@@ -0,0 +1,69 @@
+package app
+
+import (
+	"github.com/sbilibin2017/gw-currency-wallet/internal/grpcserver"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/handlers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/middlewares"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// Compile-time checks that services satisfy the interfaces consumed by handlers, middlewares
+// and the gRPC server.
+// A new subsystem only needs an entry here, a field in Container and its routes in the route table.
+var (
+	_ handlers.Registerer                     = (*services.AuthService)(nil)
+	_ handlers.Loginer                        = (*services.AuthService)(nil)
+	_ handlers.RegistrationPolicy             = (*services.RegistrationPolicy)(nil)
+	_ handlers.CurrencyChecker                = (*services.CurrencyService)(nil)
+	_ handlers.CurrencyLister                 = (*services.CurrencyService)(nil)
+	_ handlers.Balancer                       = (*services.WalletService)(nil)
+	_ handlers.BalanceTotaler                 = (*services.WalletService)(nil)
+	_ handlers.DepositWriter                  = (*services.WalletService)(nil)
+	_ handlers.WalletWithdrawWriter           = (*services.WalletService)(nil)
+	_ handlers.ExchangeRatesReader            = (*services.WalletService)(nil)
+	_ handlers.ExchangeQuoter                 = (*services.WalletService)(nil)
+	_ handlers.Exchanger                      = (*services.WalletService)(nil)
+	_ handlers.ExchangeHistoryLister          = (*services.WalletService)(nil)
+	_ handlers.TransactionLister              = (*services.WalletService)(nil)
+	_ handlers.WalletCloser                   = (*services.WalletService)(nil)
+	_ handlers.WalletCreator                  = (*services.WalletService)(nil)
+	_ handlers.WalletDetailsUpdater           = (*services.WalletService)(nil)
+	_ handlers.HoldManager                    = (*services.WalletService)(nil)
+	_ handlers.PotManager                     = (*services.WalletService)(nil)
+	_ handlers.TransactionReverser            = (*services.WalletService)(nil)
+	_ handlers.PaymentRequestManager          = (*services.WalletService)(nil)
+	_ handlers.BalanceHistoryGetter           = (*services.BalanceHistoryService)(nil)
+	_ handlers.ExchangeRateHistoryGetter      = (*services.RateHistoryService)(nil)
+	_ handlers.RateAlertManager               = (*services.RateAlertService)(nil)
+	_ handlers.Impersonator                   = (*services.ImpersonationService)(nil)
+	_ handlers.Exporter                       = (*services.ExportService)(nil)
+	_ handlers.UserDataExporter               = (*services.ExportService)(nil)
+	_ handlers.ReceiveQRGenerator             = (*services.ReceiveQRService)(nil)
+	_ handlers.LoginHistoryReader             = (*services.LoginHistoryService)(nil)
+	_ handlers.Reactivator                    = (*services.DormancyService)(nil)
+	_ handlers.DormancyOverrider              = (*services.DormancyService)(nil)
+	_ handlers.NotificationPreferencesManager = (*services.NotificationPreferenceService)(nil)
+	_ handlers.WalletLimitManager             = (*services.WalletLimitService)(nil)
+	_ handlers.SchemaDriftReporter            = (*services.SchemaDriftService)(nil)
+	_ handlers.WebhookManager                 = (*services.WebhookService)(nil)
+	_ handlers.ExchangeReconciler             = (*services.ExchangeReconciliationService)(nil)
+	_ middlewares.DormancyChecker             = (*services.DormancyService)(nil)
+
+	_ handlers.BalanceTokener      = (*jwt.JWT)(nil)
+	_ middlewares.AdminTokener     = (*jwt.JWT)(nil)
+	_ middlewares.DormantTokener   = (*jwt.JWT)(nil)
+	_ middlewares.UserLockTokener  = (*jwt.JWT)(nil)
+	_ middlewares.UserLocker       = (*locks.RedisLocker)(nil)
+	_ middlewares.RateLimitTokener = (*jwt.JWT)(nil)
+	_ middlewares.RateLimitCounter = (*repositories.RateLimitRepository)(nil)
+
+	_ grpcserver.Auth               = (*services.AuthService)(nil)
+	_ grpcserver.RegistrationPolicy = (*services.RegistrationPolicy)(nil)
+	_ grpcserver.Wallet             = (*services.WalletService)(nil)
+	_ grpcserver.CurrencyChecker    = (*services.CurrencyService)(nil)
+	_ grpcserver.DormancyChecker    = (*services.DormancyService)(nil)
+	_ grpcserver.ClaimsParser       = (*jwt.JWT)(nil)
+)
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/middlewares"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+)
+
+// RateLimitWindow is the window the per-class request budgets apply to.
+const RateLimitWindow = time.Minute
+
+// Router builds the HTTP routes from the route table. swaggerURL points the Swagger UI at the
+// generated doc.json. It panics on invalid route metadata, like chi does on invalid patterns.
+func (c *Container) Router(swaggerURL string) http.Handler {
+	routes := c.routes(swaggerURL)
+	if err := validateRoutes(routes); err != nil {
+		panic(err)
+	}
+
+	// Router
+	r := chi.NewRouter()
+	r.Use(middlewares.RealIPMiddleware(c.settings.TrustedProxies))
+	r.Use(middleware.Recoverer)
+	r.Use(middlewares.LoggingMiddleware)
+
+	chain := c.routeMiddlewares()
+	for _, rt := range routes {
+		r.With(chain(rt)...).Method(rt.Method, rt.Path, rt.Handler)
+	}
+
+	return r
+}
+
+// routeMiddlewares returns a function building the middleware chain of a route from its
+// metadata, outermost first: deadline, metrics, auth, rate limit, dormancy and per-user lock.
+func (c *Container) routeMiddlewares() func(rt Route) []func(http.Handler) http.Handler {
+	jwtService := c.infra.JWT
+
+	deadlineMiddleware := middlewares.DeadlineMiddleware(c.settings.RequestTimeout)
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	adminMiddleware := middlewares.AdminMiddleware(jwtService)
+	dormantMiddleware := middlewares.DormantMiddleware(jwtService, c.Dormancy)
+	// Money-moving requests of a user run one at a time across replicas
+	userLockMiddleware := middlewares.UserLockMiddleware(jwtService, locks.NewRedisLocker(c.infra.Redis),
+		c.settings.UserLockTTL, c.settings.UserLockWait)
+
+	rateLimitRepo := repositories.NewRateLimitRepository(c.infra.Redis)
+	rateLimits := map[RateLimitClass]func(http.Handler) http.Handler{}
+	for class, limit := range map[RateLimitClass]int{
+		RateLimitPublic: c.settings.RateLimitPublic,
+		RateLimitRead:   c.settings.RateLimitRead,
+		RateLimitWrite:  c.settings.RateLimitWrite,
+	} {
+		rateLimits[class] = middlewares.RateLimitMiddleware(jwtService, rateLimitRepo, string(class), limit, RateLimitWindow)
+	}
+
+	return func(rt Route) []func(http.Handler) http.Handler {
+		var chain []func(http.Handler) http.Handler
+		if !rt.Stream {
+			chain = append(chain, deadlineMiddleware)
+		}
+		chain = append(chain, middlewares.MetricsMiddleware(rt.Name))
+		switch rt.Auth {
+		case AuthUser:
+			chain = append(chain, authMiddleware)
+		case AuthAdmin:
+			chain = append(chain, authMiddleware, adminMiddleware)
+		}
+		if rateLimit, ok := rateLimits[rt.RateLimit]; ok {
+			chain = append(chain, rateLimit)
+		}
+		if rt.MovesMoney {
+			chain = append(chain, dormantMiddleware, userLockMiddleware)
+		}
+		return chain
+	}
+}
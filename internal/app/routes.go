@@ -0,0 +1,420 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/handlers"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// Auth is the authentication a route requires.
+type Auth int
+
+// Route authentication requirements. The zero value is invalid, so every route declares one.
+const (
+	AuthPublic Auth = iota + 1 // No token
+	AuthUser                   // Valid token
+	AuthAdmin                  // Valid token of an admin
+)
+
+// RateLimitClass names the request budget a route counts against.
+type RateLimitClass string
+
+// Rate limit classes. The zero value is invalid, so every route declares one.
+const (
+	RateLimitUnlimited RateLimitClass = "unlimited" // Probes, metrics and docs
+	RateLimitPublic    RateLimitClass = "public"    // Unauthenticated endpoints, counted per client IP
+	RateLimitRead      RateLimitClass = "read"      // Authenticated reads, counted per user
+	RateLimitWrite     RateLimitClass = "write"     // Authenticated changes, counted per user
+)
+
+// Route describes an HTTP endpoint and the middleware it requires. Router builds the
+// middleware chain from this metadata, so a new endpoint cannot skip auth or limits.
+type Route struct {
+	Name       string
+	Method     string
+	Path       string
+	Handler    http.Handler
+	Auth       Auth
+	RateLimit  RateLimitClass
+	MovesMoney bool // Rejected for dormant accounts and serialized with the user's other money operations
+	Stream     bool // Long-lived response, such as an event stream, exempt from the request deadline
+}
+
+// validate reports incomplete or contradictory route metadata.
+func (rt Route) validate() error {
+	switch {
+	case rt.Name == "" || rt.Method == "" || rt.Path == "" || rt.Handler == nil:
+		return fmt.Errorf("route %q %s %s: name, method, path and handler are required", rt.Name, rt.Method, rt.Path)
+	case rt.Auth < AuthPublic || rt.Auth > AuthAdmin:
+		return fmt.Errorf("route %q: auth requirement is not set", rt.Name)
+	case rt.RateLimit != RateLimitUnlimited && rt.RateLimit != RateLimitPublic &&
+		rt.RateLimit != RateLimitRead && rt.RateLimit != RateLimitWrite:
+		return fmt.Errorf("route %q: unknown rate limit class %q", rt.Name, rt.RateLimit)
+	case rt.MovesMoney && rt.Auth == AuthPublic:
+		return fmt.Errorf("route %q: money operations require authentication", rt.Name)
+	}
+	return nil
+}
+
+// validateRoutes checks every route and that route names are unique.
+func validateRoutes(routes []Route) error {
+	names := make(map[string]struct{}, len(routes))
+	var errs []error
+	for _, rt := range routes {
+		if err := rt.validate(); err != nil {
+			errs = append(errs, err)
+		}
+		if _, ok := names[rt.Name]; ok {
+			errs = append(errs, fmt.Errorf("route %q: duplicate name", rt.Name))
+		}
+		names[rt.Name] = struct{}{}
+	}
+	return errors.Join(errs...)
+}
+
+// routes returns the route table. swaggerURL points the Swagger UI at the generated doc.json.
+func (c *Container) routes(swaggerURL string) []Route {
+	jwtService := c.infra.JWT
+
+	routes := []Route{
+		// Public
+		{
+			Name: "register", Method: http.MethodPost, Path: "/register",
+			Handler: handlers.NewRegisterHandler(c.Auth, c.RegistrationPolicy),
+			Auth:    AuthPublic, RateLimit: RateLimitPublic,
+		},
+		{
+			Name: "login", Method: http.MethodPost, Path: "/login",
+			Handler: handlers.NewLoginHandler(c.Auth),
+			Auth:    AuthPublic, RateLimit: RateLimitPublic,
+		},
+		{
+			Name: "error-catalog", Method: http.MethodGet, Path: "/errors",
+			Handler: handlers.NewErrorCatalogHandler(),
+			Auth:    AuthPublic, RateLimit: RateLimitPublic,
+		},
+		{
+			Name: "currencies", Method: http.MethodGet, Path: "/currencies",
+			Handler: handlers.NewListCurrenciesHandler(c.Currencies),
+			Auth:    AuthPublic, RateLimit: RateLimitPublic,
+		},
+		{
+			Name: "healthz", Method: http.MethodGet, Path: "/healthz",
+			Handler: handlers.NewHealthzHandler(),
+			Auth:    AuthPublic, RateLimit: RateLimitUnlimited,
+		},
+		{
+			Name: "readyz", Method: http.MethodGet, Path: "/readyz",
+			Handler: handlers.NewReadyzHandler(c.infra.DB, c.infra.RedisHealth, c.infra.ExchangerHealth, c.infra.KafkaHealth,
+				c.SchemaDrift, c.settings.HealthCheckTimeout),
+			Auth: AuthPublic, RateLimit: RateLimitUnlimited,
+		},
+		{
+			Name: "metrics", Method: http.MethodGet, Path: "/metrics",
+			Handler: metrics.Handler(),
+			Auth:    AuthPublic, RateLimit: RateLimitUnlimited,
+		},
+		{
+			Name: "swagger", Method: http.MethodGet, Path: "/swagger/*",
+			Handler: httpSwagger.Handler(httpSwagger.URL(swaggerURL)),
+			Auth:    AuthPublic, RateLimit: RateLimitUnlimited,
+		},
+
+		// Wallet
+		{
+			Name: "balance", Method: http.MethodGet, Path: "/balance",
+			Handler: handlers.NewGetBalanceHandler(c.Wallet, jwtService, c.settings.LegacyBalanceDeprecation),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "balance-events", Method: http.MethodGet, Path: "/events/balance",
+			Handler: handlers.NewBalanceEventsHandler(c.BalanceUpdates, jwtService, handlers.BalanceEventsHeartbeat),
+			Auth:    AuthUser, RateLimit: RateLimitRead, Stream: true,
+		},
+		{
+			Name: "balance-total", Method: http.MethodGet, Path: "/balance/total",
+			Handler: handlers.NewGetTotalBalanceHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "balance-history", Method: http.MethodGet, Path: "/wallet/balance/history",
+			Handler: handlers.NewGetBalanceHistoryHandler(c.BalanceHistory, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "create-wallet", Method: http.MethodPost, Path: "/wallet",
+			Handler: handlers.NewCreateWalletHandler(c.Wallet, jwtService, c.Currencies, c.settings.LegacyBalanceDeprecation),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "deposit", Method: http.MethodPost, Path: "/wallet/deposit",
+			Handler: handlers.NewDepositHandler(c.Wallet, jwtService, c.Currencies, c.settings.LegacyBalanceDeprecation),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "withdraw", Method: http.MethodPost, Path: "/wallet/withdraw",
+			Handler: handlers.NewWithdrawHandler(c.Wallet, jwtService, c.Currencies, c.settings.LegacyBalanceDeprecation),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "transactions", Method: http.MethodGet, Path: "/wallet/transactions",
+			Handler: handlers.NewGetTransactionsHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "receive-qr", Method: http.MethodGet, Path: "/wallet/receive/qr",
+			Handler: handlers.NewGetReceiveQRHandler(c.ReceiveQR, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "close-wallet", Method: http.MethodPost, Path: "/wallet/close",
+			Handler: handlers.NewCloseWalletHandler(c.Wallet, jwtService, c.Currencies, c.settings.LegacyBalanceDeprecation),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "update-wallet", Method: http.MethodPatch, Path: "/wallet/{currency}",
+			Handler: handlers.NewUpdateWalletDetailsHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "pots", Method: http.MethodGet, Path: "/wallet/pots",
+			Handler: handlers.NewListPotsHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "create-pot", Method: http.MethodPost, Path: "/wallet/pots",
+			Handler: handlers.NewCreatePotHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "move-pot-money", Method: http.MethodPost, Path: "/wallet/pots/move",
+			Handler: handlers.NewMovePotMoneyHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "update-pot", Method: http.MethodPatch, Path: "/wallet/pots/{potID}",
+			Handler: handlers.NewUpdatePotHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "delete-pot", Method: http.MethodDelete, Path: "/wallet/pots/{potID}",
+			Handler: handlers.NewDeletePotHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "create-hold", Method: http.MethodPost, Path: "/wallet/holds",
+			Handler: handlers.NewCreateHoldHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "capture-hold", Method: http.MethodPost, Path: "/wallet/holds/{holdID}/capture",
+			Handler: handlers.NewCaptureHoldHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "release-hold", Method: http.MethodPost, Path: "/wallet/holds/{holdID}/release",
+			Handler: handlers.NewReleaseHoldHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "exchange-rates", Method: http.MethodGet, Path: "/exchange/rates",
+			Handler: handlers.NewGetExchangeRatesHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "exchange-rate-history", Method: http.MethodGet, Path: "/exchange/rates/history",
+			Handler: handlers.NewGetExchangeRateHistoryHandler(c.RateHistory, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "create-rate-alert", Method: http.MethodPost, Path: "/exchange/alerts",
+			Handler: handlers.NewCreateRateAlertHandler(c.RateAlerts, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "rate-alerts", Method: http.MethodGet, Path: "/exchange/alerts",
+			Handler: handlers.NewListRateAlertsHandler(c.RateAlerts, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "update-rate-alert", Method: http.MethodPut, Path: "/exchange/alerts/{alertID}",
+			Handler: handlers.NewUpdateRateAlertHandler(c.RateAlerts, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "delete-rate-alert", Method: http.MethodDelete, Path: "/exchange/alerts/{alertID}",
+			Handler: handlers.NewDeleteRateAlertHandler(c.RateAlerts, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "exchange-quote", Method: http.MethodGet, Path: "/exchange/quote",
+			Handler: handlers.NewExchangeQuoteHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "exchange-history", Method: http.MethodGet, Path: "/exchange/history",
+			Handler: handlers.NewGetExchangeHistoryHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "exchange", Method: http.MethodPost, Path: "/exchange",
+			Handler: handlers.NewExchangeHandler(jwtService, c.Wallet, c.Currencies, c.settings.LegacyBalanceDeprecation),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "create-payment-request", Method: http.MethodPost, Path: "/payment-requests",
+			Handler: handlers.NewCreatePaymentRequestHandler(c.Wallet, jwtService, c.Currencies),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "payment-requests", Method: http.MethodGet, Path: "/payment-requests",
+			Handler: handlers.NewListPaymentRequestsHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "accept-payment-request", Method: http.MethodPost, Path: "/payment-requests/{requestID}/accept",
+			Handler: handlers.NewAcceptPaymentRequestHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite, MovesMoney: true,
+		},
+		{
+			Name: "decline-payment-request", Method: http.MethodPost, Path: "/payment-requests/{requestID}/decline",
+			Handler: handlers.NewDeclinePaymentRequestHandler(c.Wallet, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+
+		// Exports and account
+		{
+			Name: "create-export", Method: http.MethodPost, Path: "/exports",
+			Handler: handlers.NewCreateExportHandler(c.Export, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "get-export", Method: http.MethodGet, Path: "/exports/{exportID}",
+			Handler: handlers.NewGetExportHandler(c.Export, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "create-user-data-export", Method: http.MethodPost, Path: "/me/export",
+			Handler: handlers.NewCreateUserDataExportHandler(c.Export, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "user-data-export", Method: http.MethodGet, Path: "/me/export",
+			Handler: handlers.NewGetUserDataExportHandler(c.Export, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "login-history", Method: http.MethodGet, Path: "/me/logins",
+			Handler: handlers.NewGetLoginHistoryHandler(c.LoginHistory, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "reactivate", Method: http.MethodPost, Path: "/me/reactivate",
+			Handler: handlers.NewReactivateHandler(jwtService, c.Dormancy),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "get-notification-preferences", Method: http.MethodGet, Path: "/me/notification-preferences",
+			Handler: handlers.NewGetNotificationPreferencesHandler(c.NotificationPreferences, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "update-notification-preferences", Method: http.MethodPut, Path: "/me/notification-preferences",
+			Handler: handlers.NewUpdateNotificationPreferencesHandler(c.NotificationPreferences, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "create-webhook", Method: http.MethodPost, Path: "/webhooks",
+			Handler: handlers.NewCreateWebhookHandler(c.Webhooks, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "list-webhooks", Method: http.MethodGet, Path: "/webhooks",
+			Handler: handlers.NewListWebhooksHandler(c.Webhooks, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "delete-webhook", Method: http.MethodDelete, Path: "/webhooks/{webhookID}",
+			Handler: handlers.NewDeleteWebhookHandler(c.Webhooks, jwtService),
+			Auth:    AuthUser, RateLimit: RateLimitWrite,
+		},
+
+		// Admin
+		{
+			Name: "impersonate", Method: http.MethodPost, Path: "/admin/impersonate/{userID}",
+			Handler: handlers.NewImpersonateHandler(c.Impersonation, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "set-dormant", Method: http.MethodPut, Path: "/admin/users/{userID}/dormant",
+			Handler: handlers.NewSetDormantHandler(c.Dormancy, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "clear-dormant", Method: http.MethodDelete, Path: "/admin/users/{userID}/dormant",
+			Handler: handlers.NewClearDormantHandler(c.Dormancy, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "get-wallet-limits", Method: http.MethodGet, Path: "/admin/users/{userID}/limits",
+			Handler: handlers.NewGetWalletLimitsHandler(c.WalletLimits, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "set-wallet-limit", Method: http.MethodPut, Path: "/admin/users/{userID}/limits/{currency}",
+			Handler: handlers.NewSetWalletLimitHandler(c.WalletLimits, jwtService, c.Currencies),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "set-wallet-overdraft", Method: http.MethodPut, Path: "/admin/users/{userID}/wallets/{currency}/overdraft",
+			Handler: handlers.NewSetOverdraftHandler(c.WalletLimits, jwtService, c.Currencies),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "reverse-transaction", Method: http.MethodPost, Path: "/admin/transactions/{transactionID}/reverse",
+			Handler: handlers.NewReverseTransactionHandler(c.Wallet, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "create-admin-webhook", Method: http.MethodPost, Path: "/admin/webhooks",
+			Handler: handlers.NewCreateAdminWebhookHandler(c.Webhooks, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "list-admin-webhooks", Method: http.MethodGet, Path: "/admin/webhooks",
+			Handler: handlers.NewListAdminWebhooksHandler(c.Webhooks, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "delete-admin-webhook", Method: http.MethodDelete, Path: "/admin/webhooks/{webhookID}",
+			Handler: handlers.NewDeleteAdminWebhookHandler(c.Webhooks, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+		{
+			Name: "exchange-reconciliation", Method: http.MethodGet, Path: "/admin/reconciliation",
+			Handler: handlers.NewGetExchangeReconciliationHandler(c.ExchangeReconciliation, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "dead-letters", Method: http.MethodGet, Path: "/admin/dead-letters",
+			Handler: handlers.NewListDeadLettersHandler(c.DeadLetters, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitRead,
+		},
+		{
+			Name: "replay-dead-letter", Method: http.MethodPost, Path: "/admin/dead-letters/{deadLetterID}/replay",
+			Handler: handlers.NewReplayDeadLetterHandler(c.DeadLetters, jwtService),
+			Auth:    AuthAdmin, RateLimit: RateLimitWrite,
+		},
+	}
+	if c.settings.GRPCGatewayEnabled {
+		routes = append(routes, c.gatewayRoutes()...)
+	}
+	if c.settings.GraphQLEnabled {
+		routes = append(routes, c.graphqlRoute())
+	}
+	return routes
+}
@@ -0,0 +1,667 @@
+// Package apperrors is the catalog of errors returned by the REST API.
+//
+// Every error response is a Response rendered by Write: the message of one catalog entry in
+// its "error" field, the code of the entry in its "code" field, optional details and the
+// request ID. Codes and messages are stable within a schema version, so clients can branch
+// on codes.
+package apperrors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+)
+
+// SchemaVersion is the version of the catalog. It is incremented whenever an entry
+// is removed or its code, status or message changes; adding entries keeps the version.
+const SchemaVersion = 1
+
+// Error describes an error returned by the API.
+type Error struct {
+	Code        string // Machine-readable error code
+	Status      int    // HTTP status of the response
+	Message     string // Value of the "error" field of the response
+	Description string // Human-readable description of when the error is returned
+}
+
+// Error implements error, for transports that return entries as errors, such as GraphQL resolvers.
+func (e Error) Error() string {
+	return e.Message
+}
+
+// Authentication and authorization
+var (
+	Unauthorized = Error{
+		Code:        "unauthorized",
+		Status:      http.StatusUnauthorized,
+		Message:     "Unauthorized",
+		Description: "The request has no valid JWT token.",
+	}
+	Forbidden = Error{
+		Code:        "forbidden",
+		Status:      http.StatusForbidden,
+		Message:     "Forbidden",
+		Description: "The token does not grant access to an admin endpoint.",
+	}
+	InvalidCredentials = Error{
+		Code:        "invalid_credentials",
+		Status:      http.StatusUnauthorized,
+		Message:     "Invalid username or password",
+		Description: "Login failed because the username is unknown or the password is wrong.",
+	}
+	InvalidPassword = Error{
+		Code:        "invalid_password",
+		Status:      http.StatusUnauthorized,
+		Message:     "Invalid password",
+		Description: "Re-verification of a dormant account failed because the password is wrong.",
+	}
+	AccountDormant = Error{
+		Code:        "account_dormant",
+		Status:      http.StatusForbidden,
+		Message:     "Account is dormant, re-verification required",
+		Description: "Wallet operations are blocked until the account is reactivated via POST /me/reactivate.",
+	}
+)
+
+// Registration
+var (
+	UserAlreadyExists = Error{
+		Code:        "user_already_exists",
+		Status:      http.StatusBadRequest,
+		Message:     "Username or email already exists",
+		Description: "Registration failed because the username or email is taken, or the request body is invalid.",
+	}
+	InvalidRegistration = Error{
+		Code:        "invalid_registration",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid username, password or email",
+		Description: "A registration field is missing or malformed; details lists the invalid fields.",
+	}
+	EmailDomainNotAllowed = Error{
+		Code:        "email_domain_not_allowed",
+		Status:      http.StatusForbidden,
+		Message:     "Email domain is not allowed",
+		Description: "The email domain is blocklisted or missing from the allowlist.",
+	}
+	EmailDomainRateLimited = Error{
+		Code:        "email_domain_rate_limited",
+		Status:      http.StatusTooManyRequests,
+		Message:     "Too many registrations from this email domain",
+		Description: "The registration limit of the email domain is reached for the current window.",
+	}
+)
+
+// Request validation
+var (
+	InvalidRequest = Error{
+		Code:        "invalid_request",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid request",
+		Description: "The request body is not valid JSON or misses required fields.",
+	}
+	InvalidRequestBody = Error{
+		Code:        "invalid_request_body",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid request body",
+		Description: "The request body is not valid JSON.",
+	}
+	InvalidAmountOrCurrency = Error{
+		Code:        "invalid_amount_or_currency",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid amount or currency",
+		Description: "The deposit, payment request, receive QR code or exchange quote amount is not positive, below the minimum of the currency or has more decimal places than it, or a currency is not supported.",
+	}
+	InvalidCurrency = Error{
+		Code:        "invalid_currency",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid currency",
+		Description: "The currency is not supported.",
+	}
+	InvalidUserID = Error{
+		Code:        "invalid_user_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid user ID",
+		Description: "The user ID in the path is not a UUID.",
+	}
+	InvalidHoldID = Error{
+		Code:        "invalid_hold_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid hold ID",
+		Description: "The hold ID in the path is not a UUID.",
+	}
+	InvalidTransactionID = Error{
+		Code:        "invalid_transaction_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid transaction ID",
+		Description: "The transaction ID in the path is not a UUID.",
+	}
+	InvalidWebhookID = Error{
+		Code:        "invalid_webhook_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid webhook ID",
+		Description: "The webhook ID in the path is not a UUID.",
+	}
+	InvalidWebhookURL = Error{
+		Code:        "invalid_webhook_url",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid webhook URL",
+		Description: "The callback URL is not an absolute http or https URL.",
+	}
+	InvalidReference = Error{
+		Code:        "invalid_reference",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid reference",
+		Description: "The transaction reference is longer than 128 characters.",
+	}
+	InvalidWalletDetails = Error{
+		Code:        "invalid_wallet_details",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid wallet details",
+		Description: "The wallet label is longer than 64 characters, or the metadata has more than 20 keys, an empty key, a key longer than 40 or a value longer than 256 characters.",
+	}
+	InvalidPotID = Error{
+		Code:        "invalid_pot_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid pot ID",
+		Description: "The pot ID in the path or body is not a UUID.",
+	}
+	InvalidPotName = Error{
+		Code:        "invalid_pot_name",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid pot name",
+		Description: "The pot name is blank or longer than 64 characters.",
+	}
+	InvalidPotMove = Error{
+		Code:        "invalid_pot_move",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid pot move",
+		Description: "The source and target of a pot move are the same pot, or both the unallocated balance.",
+	}
+	InvalidQuoteID = Error{
+		Code:        "invalid_quote_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid quote ID",
+		Description: "The quote_id of an exchange is not a valid UUID.",
+	}
+	InvalidPaymentRequestID = Error{
+		Code:        "invalid_payment_request_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid payment request ID",
+		Description: "The payment request ID in the path is not a UUID.",
+	}
+	InvalidPayer = Error{
+		Code:        "invalid_payer",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid payer",
+		Description: "The payer of a payment request is missing or is the requester.",
+	}
+	InvalidNote = Error{
+		Code:        "invalid_note",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid note",
+		Description: "The payment request note is longer than 128 characters.",
+	}
+	InvalidExportID = Error{
+		Code:        "invalid_export_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid export ID",
+		Description: "The export ID in the path is not a UUID.",
+	}
+	InvalidLimit = Error{
+		Code:        "invalid_limit",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid limit",
+		Description: "The page size is out of range, or a wallet limit is negative or the daily limit exceeds the monthly one.",
+	}
+	InvalidFrom = Error{
+		Code:        "invalid_from",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid from",
+		Description: "The from filter is not an RFC 3339 timestamp, or not a YYYY-MM-DD date for the balance history.",
+	}
+	InvalidTo = Error{
+		Code:        "invalid_to",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid to",
+		Description: "The to filter is not an RFC 3339 timestamp, or not a YYYY-MM-DD date for the balance history.",
+	}
+	InvalidDateRange = Error{
+		Code:        "invalid_date_range",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid date range",
+		Description: "The from filter is not before the to filter, or the balance or daily rate history range exceeds 366 days, or the hourly rate history range exceeds 31 days.",
+	}
+	InvalidCurrencyPair = Error{
+		Code:        "invalid_currency_pair",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid currency pair",
+		Description: "The pair is not two different supported currency codes joined by a hyphen, e.g. USD-EUR.",
+	}
+	InvalidGranularity = Error{
+		Code:        "invalid_granularity",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid granularity",
+		Description: "The rate history granularity is neither hour nor day.",
+	}
+	InvalidRateAlertID = Error{
+		Code:        "invalid_rate_alert_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid rate alert ID",
+		Description: "The rate alert ID in the path is not a UUID.",
+	}
+	InvalidRateAlert = Error{
+		Code:        "invalid_rate_alert",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid rate alert",
+		Description: "The direction is neither above nor below, or the threshold is not positive.",
+	}
+	InvalidOperation = Error{
+		Code:        "invalid_operation",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid operation",
+		Description: "The operation filter is not a known transaction operation.",
+	}
+	InvalidCursor = Error{
+		Code:        "invalid_cursor",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid cursor",
+		Description: "The pagination cursor is malformed or was issued for another query.",
+	}
+	UnsupportedExportFormat = Error{
+		Code:        "unsupported_export_format",
+		Status:      http.StatusBadRequest,
+		Message:     "Unsupported export format",
+		Description: "The requested export format is not supported.",
+	}
+	UnsupportedQRFormat = Error{
+		Code:        "unsupported_qr_format",
+		Status:      http.StatusBadRequest,
+		Message:     "Unsupported QR format",
+		Description: "The receive QR code format is neither png nor svg.",
+	}
+	PhoneRequired = Error{
+		Code:        "phone_required",
+		Status:      http.StatusBadRequest,
+		Message:     "Phone is required for SMS notifications",
+		Description: "SMS notifications are enabled without a phone number.",
+	}
+)
+
+// Wallet operations
+var (
+	InsufficientFundsWithdraw = Error{
+		Code:        "insufficient_funds_withdraw",
+		Status:      http.StatusBadRequest,
+		Message:     "Insufficient funds or invalid amount",
+		Description: "The available balance is lower than the withdrawal, hold, payment request or pot move amount, or the amount or currency is invalid. Money saved in pots excluded from spending is not available.",
+	}
+	InsufficientFundsExchange = Error{
+		Code:        "insufficient_funds_exchange",
+		Status:      http.StatusBadRequest,
+		Message:     "Insufficient funds or invalid currencies",
+		Description: "The balance is lower than the exchange amount, or the amount or currencies are invalid.",
+	}
+	DailyLimitExceeded = Error{
+		Code:        "daily_limit_exceeded",
+		Status:      http.StatusForbidden,
+		Message:     "Daily limit exceeded",
+		Description: "The operation would exceed the user's withdrawal and exchange limit over the last 24 hours.",
+	}
+	MonthlyLimitExceeded = Error{
+		Code:        "monthly_limit_exceeded",
+		Status:      http.StatusForbidden,
+		Message:     "Monthly limit exceeded",
+		Description: "The operation would exceed the user's withdrawal and exchange limit over the last 30 days.",
+	}
+	WalletNotFound = Error{
+		Code:        "wallet_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Wallet not found",
+		Description: "The user has no open wallet in the currency.",
+	}
+	WalletNotEmpty = Error{
+		Code:        "wallet_not_empty",
+		Status:      http.StatusConflict,
+		Message:     "Wallet is not empty, specify to_currency",
+		Description: "A wallet with a balance can only be closed with a currency to pay the balance out to.",
+	}
+	WalletHasHolds = Error{
+		Code:        "wallet_has_holds",
+		Status:      http.StatusConflict,
+		Message:     "Wallet has pending holds",
+		Description: "A wallet can only be closed after its pending holds are captured or released.",
+	}
+	WalletOverdrawn = Error{
+		Code:        "wallet_overdrawn",
+		Status:      http.StatusConflict,
+		Message:     "Wallet is overdrawn",
+		Description: "A wallet with a negative balance can only be closed after it is topped up.",
+	}
+	PotNotFound = Error{
+		Code:        "pot_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Pot not found",
+		Description: "The pot does not exist, belongs to another user or to a wallet in another currency.",
+	}
+	PotNameTaken = Error{
+		Code:        "pot_name_taken",
+		Status:      http.StatusConflict,
+		Message:     "Pot name already taken",
+		Description: "The wallet already has a pot with the name.",
+	}
+	HoldNotFound = Error{
+		Code:        "hold_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Hold not found",
+		Description: "The hold does not exist or belongs to another user.",
+	}
+	HoldNotPending = Error{
+		Code:        "hold_not_pending",
+		Status:      http.StatusConflict,
+		Message:     "Hold is not pending",
+		Description: "The hold is already captured or released.",
+	}
+	InsufficientFundsReversal = Error{
+		Code:        "insufficient_funds_reversal",
+		Status:      http.StatusBadRequest,
+		Message:     "Insufficient funds to reverse transaction",
+		Description: "The user no longer holds the money the reversal has to take back.",
+	}
+	OperationInProgress = Error{
+		Code:        "operation_in_progress",
+		Status:      http.StatusConflict,
+		Message:     "Another operation is in progress",
+		Description: "Another deposit, withdrawal, exchange, closure or hold operation of the user did not finish in time. Retry after it completes.",
+	}
+)
+
+// Exchange rates
+var (
+	ExchangeRateNotFound = Error{
+		Code:        "exchange_rate_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Exchange rate not found",
+		Description: "The exchanger has no rate for the currency pair.",
+	}
+	ExchangerUnavailable = Error{
+		Code:        "exchanger_unavailable",
+		Status:      http.StatusServiceUnavailable,
+		Message:     "Exchange service unavailable",
+		Description: "The exchanger cannot be reached and no cached rate is available. Safe to retry.",
+	}
+	ExchangerTimeout = Error{
+		Code:        "exchanger_timeout",
+		Status:      http.StatusGatewayTimeout,
+		Message:     "Exchange service timeout",
+		Description: "The exchanger did not answer within the request deadline. Safe to retry.",
+	}
+	QuoteExpired = Error{
+		Code:        "quote_expired",
+		Status:      http.StatusConflict,
+		Message:     "Quote expired",
+		Description: "The exchange quote has expired, was already used or was issued to another user. Request a new quote.",
+	}
+	QuoteMismatch = Error{
+		Code:        "quote_mismatch",
+		Status:      http.StatusBadRequest,
+		Message:     "Quote does not match the exchange",
+		Description: "The currencies or amount of an exchange with quote_id differ from the quote. The quote is used up.",
+	}
+	FeeExceedsAmount = Error{
+		Code:        "fee_exceeds_amount",
+		Status:      http.StatusBadRequest,
+		Message:     "Amount does not cover the exchange fee",
+		Description: "The fee configured for the currency pair is not less than the amount to exchange.",
+	}
+	ExchangeAmountOutOfRange = Error{
+		Code:        "exchange_amount_out_of_range",
+		Status:      http.StatusBadRequest,
+		Message:     "Exchange amount out of range",
+		Description: "The amount to exchange is below EXCHANGE_MIN_AMOUNT or above EXCHANGE_MAX_AMOUNT.",
+	}
+	SlippageExceeded = Error{
+		Code:        "slippage_exceeded",
+		Status:      http.StatusConflict,
+		Message:     "Exchange rate moved, amount below min_expected_amount",
+		Description: "The exchange would credit less than min_expected_amount at the current rate and was not executed. Request a new quote.",
+	}
+	ExchangeRatesFailed = Error{
+		Code:        "exchange_rates_failed",
+		Status:      http.StatusInternalServerError,
+		Message:     "Failed to retrieve exchange rates",
+		Description: "The exchange rates could not be retrieved for another reason.",
+	}
+)
+
+// Resources
+var (
+	InvalidDeadLetterID = Error{
+		Code:        "invalid_dead_letter_id",
+		Status:      http.StatusBadRequest,
+		Message:     "Invalid dead letter ID",
+		Description: "The dead letter ID in the path is not a valid UUID.",
+	}
+	DeadLetterNotFound = Error{
+		Code:        "dead_letter_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Dead letter not found",
+		Description: "No dead letter has the given event ID.",
+	}
+	DeadLetterAlreadyReplayed = Error{
+		Code:        "dead_letter_already_replayed",
+		Status:      http.StatusConflict,
+		Message:     "Dead letter already replayed",
+		Description: "The dead letter was already published again; it is replayed at most once.",
+	}
+	UserNotFound = Error{
+		Code:        "user_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "User not found",
+		Description: "The user in the path or the payer of a payment request does not exist.",
+	}
+	AdminImpersonation = Error{
+		Code:        "admin_impersonation",
+		Status:      http.StatusForbidden,
+		Message:     "Admins cannot be impersonated",
+		Description: "Impersonation of another admin is not allowed.",
+	}
+	ExportNotFound = Error{
+		Code:        "export_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Export not found",
+		Description: "The export does not exist or belongs to another user.",
+	}
+	TransactionNotFound = Error{
+		Code:        "transaction_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Transaction not found",
+		Description: "The transaction in the path does not exist.",
+	}
+	TransactionAlreadyReversed = Error{
+		Code:        "transaction_already_reversed",
+		Status:      http.StatusConflict,
+		Message:     "Transaction already reversed",
+		Description: "The transaction has a reversal already; a transaction is reversed at most once.",
+	}
+	TransactionNotReversible = Error{
+		Code:        "transaction_not_reversible",
+		Status:      http.StatusConflict,
+		Message:     "Transaction cannot be reversed",
+		Description: "The transaction is a reversal itself or a wallet closure without payout.",
+	}
+	WebhookNotFound = Error{
+		Code:        "webhook_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Webhook not found",
+		Description: "The webhook does not exist or belongs to another user.",
+	}
+	PaymentRequestNotFound = Error{
+		Code:        "payment_request_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Payment request not found",
+		Description: "The payment request does not exist or the user is not asked to pay it.",
+	}
+	PaymentRequestNotPending = Error{
+		Code:        "payment_request_not_pending",
+		Status:      http.StatusConflict,
+		Message:     "Payment request is not pending",
+		Description: "The payment request is already accepted or declined.",
+	}
+	PaymentRequestExpired = Error{
+		Code:        "payment_request_expired",
+		Status:      http.StatusGone,
+		Message:     "Payment request expired",
+		Description: "The payment request was not answered before its expiry.",
+	}
+	TooManyWebhooks = Error{
+		Code:        "too_many_webhooks",
+		Status:      http.StatusConflict,
+		Message:     "Too many webhooks",
+		Description: "The user, or the admins together, already registered the maximum of 10 webhooks.",
+	}
+	RateAlertNotFound = Error{
+		Code:        "rate_alert_not_found",
+		Status:      http.StatusNotFound,
+		Message:     "Rate alert not found",
+		Description: "The rate alert does not exist or belongs to another user.",
+	}
+	TooManyRateAlerts = Error{
+		Code:        "too_many_rate_alerts",
+		Status:      http.StatusConflict,
+		Message:     "Too many rate alerts",
+		Description: "The user already registered the maximum of 20 rate alerts.",
+	}
+)
+
+// Rate limiting
+var (
+	TooManyRequests = Error{
+		Code:        "too_many_requests",
+		Status:      http.StatusTooManyRequests,
+		Message:     "Too many requests",
+		Description: "The client exceeded the request budget of the endpoint class (public, read or write) for the current minute. Retry after the number of seconds in the Retry-After header.",
+	}
+)
+
+// Service health
+var (
+	DatabaseUnavailable = Error{
+		Code:        "database_unavailable",
+		Status:      http.StatusServiceUnavailable,
+		Message:     "Database unavailable",
+		Description: "Returned by GET /readyz when PostgreSQL does not answer, so the instance should not receive traffic.",
+	}
+	RedisUnavailable = Error{
+		Code:        "redis_unavailable",
+		Status:      http.StatusServiceUnavailable,
+		Message:     "Redis unavailable",
+		Description: "Returned by GET /readyz when Redis does not answer.",
+	}
+	ExchangerNotReady = Error{
+		Code:        "exchanger_not_ready",
+		Status:      http.StatusServiceUnavailable,
+		Message:     "Exchanger unavailable",
+		Description: "Returned by GET /readyz when the gRPC connection to the exchanger is not ready.",
+	}
+	KafkaUnavailable = Error{
+		Code:        "kafka_unavailable",
+		Status:      http.StatusServiceUnavailable,
+		Message:     "Kafka unavailable",
+		Description: "Returned by GET /readyz when the Kafka cluster does not answer, and by POST /admin/dead-letters/{eventID}/replay when the event cannot be published again.",
+	}
+	ExchangeReconciliationDisabled = Error{
+		Code:        "exchange_reconciliation_disabled",
+		Status:      http.StatusServiceUnavailable,
+		Message:     "Exchange reconciliation is not configured",
+		Description: "Returned by GET /admin/reconciliation when GW_EXCHANGER_EXPORT_URL is not set.",
+	}
+)
+
+// Internal is returned for unexpected failures.
+var Internal = Error{
+	Code:        "internal",
+	Status:      http.StatusInternalServerError,
+	Message:     "Internal server error",
+	Description: "An unexpected failure. The details are logged on the server.",
+}
+
+var all = []Error{
+	Unauthorized, Forbidden, InvalidCredentials, InvalidPassword, AccountDormant,
+	UserAlreadyExists, InvalidRegistration, EmailDomainNotAllowed, EmailDomainRateLimited,
+	InvalidRequest, InvalidRequestBody, InvalidAmountOrCurrency, InvalidCurrency, InvalidUserID,
+	InvalidHoldID, InvalidTransactionID, InvalidWebhookID, InvalidWebhookURL, InvalidReference, InvalidWalletDetails, InvalidPotID, InvalidPotName, InvalidPotMove, InvalidQuoteID, InvalidPaymentRequestID, InvalidPayer, InvalidNote, InvalidExportID, InvalidLimit, InvalidFrom, InvalidTo, InvalidDateRange, InvalidCurrencyPair, InvalidGranularity, InvalidRateAlertID, InvalidRateAlert, InvalidOperation,
+	InvalidCursor, UnsupportedExportFormat, UnsupportedQRFormat, PhoneRequired,
+	InsufficientFundsWithdraw, InsufficientFundsExchange, DailyLimitExceeded, MonthlyLimitExceeded,
+	WalletNotFound, WalletNotEmpty, WalletHasHolds, WalletOverdrawn, PotNotFound, PotNameTaken, HoldNotFound, HoldNotPending, InsufficientFundsReversal,
+	OperationInProgress,
+	ExchangeRateNotFound, ExchangerUnavailable, ExchangerTimeout, QuoteExpired, QuoteMismatch, FeeExceedsAmount, ExchangeAmountOutOfRange, SlippageExceeded, ExchangeRatesFailed,
+	UserNotFound, AdminImpersonation, ExportNotFound, TransactionNotFound, TransactionAlreadyReversed, TransactionNotReversible,
+	WebhookNotFound, TooManyWebhooks, PaymentRequestNotFound, PaymentRequestNotPending, PaymentRequestExpired,
+	RateAlertNotFound, TooManyRateAlerts,
+	InvalidDeadLetterID, DeadLetterNotFound, DeadLetterAlreadyReplayed,
+	TooManyRequests,
+	DatabaseUnavailable, RedisUnavailable, ExchangerNotReady, KafkaUnavailable, ExchangeReconciliationDisabled,
+	Internal,
+}
+
+// Catalog returns all errors ordered by code.
+func Catalog() []Error {
+	catalog := make([]Error, len(all))
+	copy(catalog, all)
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Code < catalog[j].Code })
+	return catalog
+}
+
+// ByMessage returns the catalog entry with the message msg, for errors that carry only their
+// message, such as gRPC statuses.
+func ByMessage(msg string) (Error, bool) {
+	for _, e := range all {
+		if e.Message == msg {
+			return e, true
+		}
+	}
+	return Error{}, false
+}
+
+// Response is the body of every error response.
+// swagger:model ErrorResponse
+type Response struct {
+	// Human-readable message of the error, stable within a schema version
+	// default: Invalid amount or currency
+	Error string `json:"error"`
+
+	// Machine-readable error code, listed by GET /errors
+	// default: invalid_amount_or_currency
+	Code string `json:"code"`
+
+	// Error-specific data, e.g. the status of every dependency of GET /readyz
+	Details any `json:"details,omitempty"`
+
+	// ID of the request, also returned in the X-Request-ID header
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Write renders e as the error response, with its status, code and message.
+func Write(w http.ResponseWriter, e Error) {
+	WriteDetails(w, e, nil)
+}
+
+// WriteDetails renders e as the error response with error-specific details. The request ID
+// is the X-Request-ID response header set by the logging middleware.
+func WriteDetails(w http.ResponseWriter, e Error, details any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	resp := Response{
+		Error:     e.Message,
+		Code:      e.Code,
+		Details:   details,
+		RequestID: w.Header().Get(requestid.Header),
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Log.Errorw("failed to write error response", "code", e.Code, "error", err)
+	}
+}
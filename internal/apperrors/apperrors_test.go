@@ -0,0 +1,64 @@
+package apperrors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := Catalog()
+	assert.Len(t, catalog, len(all))
+	assert.True(t, sort.SliceIsSorted(catalog, func(i, j int) bool { return catalog[i].Code < catalog[j].Code }))
+
+	codes := make(map[string]struct{})
+	messages := make(map[string]struct{})
+	for _, e := range catalog {
+		assert.NotEmpty(t, e.Code)
+		assert.NotEmpty(t, e.Message, e.Code)
+		assert.NotEmpty(t, e.Description, e.Code)
+		assert.NotEmpty(t, http.StatusText(e.Status), e.Code)
+		assert.GreaterOrEqual(t, e.Status, http.StatusBadRequest, e.Code)
+
+		assert.NotContains(t, codes, e.Code, "duplicate code")
+		assert.NotContains(t, messages, e.Message, "duplicate message")
+		codes[e.Code] = struct{}{}
+		messages[e.Message] = struct{}{}
+	}
+}
+
+func TestCatalog_ReturnsCopy(t *testing.T) {
+	catalog := Catalog()
+	catalog[0].Code = "changed"
+	assert.NotEqual(t, "changed", Catalog()[0].Code)
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Write(rec, InvalidAmountOrCurrency)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"Invalid amount or currency","code":"invalid_amount_or_currency"}`, rec.Body.String())
+}
+
+func TestWriteDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "req-1")
+	WriteDetails(rec, DatabaseUnavailable, map[string]string{"postgres": "unavailable"})
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"error":"Database unavailable","code":"database_unavailable","details":{"postgres":"unavailable"},"request_id":"req-1"}`, rec.Body.String())
+}
+
+func TestByMessage(t *testing.T) {
+	e, ok := ByMessage("Wallet not found")
+	assert.True(t, ok)
+	assert.Equal(t, WalletNotFound, e)
+
+	_, ok = ByMessage("no such message")
+	assert.False(t, ok)
+}
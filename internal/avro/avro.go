@@ -0,0 +1,86 @@
+// Package avro encodes records in the Apache Avro binary format and frames them in the
+// Confluent Schema Registry wire format. Only the types of the published events are supported.
+package avro
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// magicByte starts every message in the Confluent wire format.
+const magicByte = 0
+
+// Writer appends Avro binary values. Fields of a record are written one after another in
+// the order of the schema.
+type Writer struct {
+	buf []byte
+}
+
+// Long writes an int or long as a zig-zag encoded variable-length integer.
+func (w *Writer) Long(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+// String writes a string as its length followed by its UTF-8 bytes.
+func (w *Writer) String(s string) {
+	w.Long(int64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// Bytes writes bytes as their length followed by the bytes.
+func (w *Writer) Bytes(b []byte) {
+	w.Long(int64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// Decimal writes a decimal logical type backed by bytes: the unscaled value as the
+// shortest big-endian two's complement.
+func (w *Writer) Decimal(unscaled int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(unscaled))
+	// Redundant leading bytes are sign extension: 0x00 before a positive byte, 0xff before a negative one
+	n := 8 - (64-bitsNeeded(unscaled))/8
+	w.Bytes(b[8-n:])
+}
+
+// OptionalString writes a ["null", "string"] union: null for an empty string.
+func (w *Writer) OptionalString(s string) {
+	if s == "" {
+		w.Long(0)
+		return
+	}
+	w.Long(1)
+	w.String(s)
+}
+
+// OptionalDecimal writes a ["null", decimal] union: null for a nil value.
+func (w *Writer) OptionalDecimal(unscaled *int64) {
+	if unscaled == nil {
+		w.Long(0)
+		return
+	}
+	w.Long(1)
+	w.Decimal(*unscaled)
+}
+
+// Data returns the written values.
+func (w *Writer) Data() []byte {
+	return w.buf
+}
+
+// Frame prefixes an encoded record with the magic byte and the big-endian schema ID, so
+// consumers look the writer's schema up in the registry.
+func Frame(schemaID int, record []byte) []byte {
+	msg := make([]byte, 5, 5+len(record))
+	msg[0] = magicByte
+	binary.BigEndian.PutUint32(msg[1:], uint32(schemaID))
+	return append(msg, record...)
+}
+
+// bitsNeeded returns the number of bits of v as a two's complement including the sign bit.
+func bitsNeeded(v int64) int {
+	if v < 0 {
+		v = ^v
+	}
+	return bits.Len64(uint64(v)) + 1
+}
@@ -0,0 +1,51 @@
+package avro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *Writer)
+		want  []byte
+	}{
+		{name: "long zero", write: func(w *Writer) { w.Long(0) }, want: []byte{0x00}},
+		{name: "long negative", write: func(w *Writer) { w.Long(-1) }, want: []byte{0x01}},
+		{name: "long multi-byte", write: func(w *Writer) { w.Long(64) }, want: []byte{0x80, 0x01}},
+		{name: "string", write: func(w *Writer) { w.String("USD") }, want: []byte{0x06, 'U', 'S', 'D'}},
+		{name: "empty string", write: func(w *Writer) { w.String("") }, want: []byte{0x00}},
+		{name: "bytes", write: func(w *Writer) { w.Bytes([]byte{0xca, 0xfe}) }, want: []byte{0x04, 0xca, 0xfe}},
+		{name: "decimal zero", write: func(w *Writer) { w.Decimal(0) }, want: []byte{0x02, 0x00}},
+		{name: "decimal", write: func(w *Writer) { w.Decimal(10050) }, want: []byte{0x04, 0x27, 0x42}},
+		{name: "decimal needs sign byte", write: func(w *Writer) { w.Decimal(255) }, want: []byte{0x04, 0x00, 0xff}},
+		{name: "decimal negative", write: func(w *Writer) { w.Decimal(-129) }, want: []byte{0x04, 0xff, 0x7f}},
+		{name: "decimal negative one byte", write: func(w *Writer) { w.Decimal(-128) }, want: []byte{0x02, 0x80}},
+		{name: "optional string null", write: func(w *Writer) { w.OptionalString("") }, want: []byte{0x00}},
+		{name: "optional string", write: func(w *Writer) { w.OptionalString("A") }, want: []byte{0x02, 0x02, 'A'}},
+		{name: "optional decimal null", write: func(w *Writer) { w.OptionalDecimal(nil) }, want: []byte{0x00}},
+		{name: "optional decimal", write: func(w *Writer) { v := int64(0); w.OptionalDecimal(&v) }, want: []byte{0x02, 0x02, 0x00}},
+		{
+			name: "record fields in order",
+			write: func(w *Writer) {
+				w.String("id")
+				w.Long(1)
+			},
+			want: []byte{0x04, 'i', 'd', 0x02},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w Writer
+			tt.write(&w)
+			assert.Equal(t, tt.want, w.Data())
+		})
+	}
+}
+
+func TestFrame(t *testing.T) {
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x01, 0x02, 0xaa}, Frame(258, []byte{0xaa}))
+}
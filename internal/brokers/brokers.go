@@ -0,0 +1,67 @@
+// Package brokers publishes events to message brokers other than Kafka: NATS JetStream
+// and RabbitMQ. Publishers take events in the kafka.Message envelope, like a kafka.Writer,
+// so they are interchangeable with it. They speak the wire protocol of the broker and
+// support what publishing events needs: plain TCP, password authentication and
+// acknowledged publishes that consumers can deduplicate by the message ID: the event ID
+// header of the event, or its key if it has none.
+package brokers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/segmentio/kafka-go"
+)
+
+// Publisher publishes events.
+type Publisher interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// TopicPublisher publishes every event to one topic, like a kafka.Writer with a Topic.
+// The underlying publisher is shared and closed by its owner, not by Close.
+type TopicPublisher struct {
+	publisher Publisher
+	topic     string
+}
+
+// WithTopic returns a publisher sending events to topic through p.
+func WithTopic(p Publisher, topic string) *TopicPublisher {
+	return &TopicPublisher{publisher: p, topic: topic}
+}
+
+// WriteMessages publishes msgs to the topic of the publisher.
+func (p *TopicPublisher) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	routed := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		msg.Topic = p.topic
+		routed[i] = msg
+	}
+	return p.publisher.WriteMessages(ctx, routed...)
+}
+
+// Close does nothing, the shared publisher stays open.
+func (p *TopicPublisher) Close() error {
+	return nil
+}
+
+// messageID returns the ID the broker deduplicates msg by: its event ID header, or its key.
+func messageID(msg kafka.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == models.EventIDHeader {
+			return string(h.Value)
+		}
+	}
+	return string(msg.Key)
+}
+
+// deadline returns the time timeout from now, or the deadline of ctx if it is earlier.
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(d) {
+		return ctxDeadline
+	}
+	return d
+}
@@ -0,0 +1,45 @@
+package brokers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicPublisher(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	inner := NewMockPublisher(ctrl)
+	p := WithTopic(inner, "security.alerts")
+
+	msg := kafka.Message{Key: []byte("alert-1"), Value: []byte(`{}`)}
+	inner.EXPECT().WriteMessages(ctx, kafka.Message{Topic: "security.alerts", Key: []byte("alert-1"), Value: []byte(`{}`)}).Return(nil)
+
+	assert.NoError(t, p.WriteMessages(ctx, msg))
+	assert.Empty(t, msg.Topic, "the message of the caller is not changed")
+
+	// The shared publisher is not closed
+	assert.NoError(t, p.Close())
+}
+
+func TestDeadline(t *testing.T) {
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline(context.Background(), time.Second), 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ctxDeadline, _ := ctx.Deadline()
+	assert.Equal(t, ctxDeadline, deadline(ctx, time.Second))
+}
+
+func TestMessageID(t *testing.T) {
+	assert.Equal(t, "txn-1", messageID(kafka.Message{Key: []byte("txn-1")}))
+	assert.Equal(t, "event-1", messageID(kafka.Message{
+		Key:     []byte("user-1"),
+		Headers: []kafka.Header{{Key: "env", Value: []byte("production")}, {Key: models.EventIDHeader, Value: []byte("event-1")}},
+	}))
+}
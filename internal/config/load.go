@@ -0,0 +1,335 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/segmentio/kafka-go"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the config file read when -c is not given.
+const DefaultPath = "config.env"
+
+// Sources of settings, lowest precedence first, named in error messages.
+const (
+	sourceDefault = "default"
+	sourceFile    = "config file"
+	sourceEnv     = "environment"
+	sourceFlag    = "flag"
+)
+
+// setting is a configurable field of Config.
+type setting struct {
+	env    string        // Environment variable
+	group  string        // YAML key of the group
+	key    string        // YAML key within the group
+	def    string        // Default
+	secret bool          // Read from the secret store of CONFIG_SOURCE if it has it
+	field  reflect.Value // Settable field of the Config
+}
+
+// flagName returns the command-line flag of the setting, e.g. app-port for APP_PORT.
+func (s setting) flagName() string {
+	return strings.ToLower(strings.ReplaceAll(s.env, "_", "-"))
+}
+
+// settings returns the settings of cfg in declaration order.
+func settings(cfg *Config) []setting {
+	var list []setting
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		group := root.Field(i)
+		groupType := root.Type().Field(i)
+		for j := 0; j < group.NumField(); j++ {
+			field := group.Type().Field(j)
+			list = append(list, setting{
+				env:    field.Tag.Get("env"),
+				group:  groupType.Tag.Get("yaml"),
+				key:    field.Tag.Get("yaml"),
+				def:    field.Tag.Get("default"),
+				secret: field.Tag.Get("secret") == "true",
+				field:  group.Field(j),
+			})
+		}
+	}
+	return list
+}
+
+// Load reads the configuration with args as the command-line arguments, without the program
+// name. Precedence, lowest first: defaults, the config file, the environment, flags. Secret
+// settings found in the store selected by CONFIG_SOURCE override all but flags; the others
+// fall back to the config file and the environment, as when running locally.
+//
+// The config file is named by -c, config.env by default; it is YAML with a section per group
+// if its name ends in .yaml or .yml, dotenv otherwise, and is ignored if missing. Every
+// setting has a flag named after its environment variable, e.g. -app-port for APP_PORT.
+// Empty values in the file and the environment are ignored, as if unset.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("gw-currency-wallet", flag.ContinueOnError)
+	load := Bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return load()
+}
+
+// Bind defines -c and the flags of the settings on fs, for commands with flags of their own,
+// and returns a function loading the configuration as Load does once fs is parsed.
+func Bind(fs *flag.FlagSet) func() (*Config, error) {
+	cfg := &Config{}
+	list := settings(cfg)
+
+	path := fs.String("c", DefaultPath, "Path to the configuration file, YAML (.yaml, .yml) or dotenv")
+	flags := make(map[string]*string, len(list))
+	for _, s := range list {
+		flags[s.env] = fs.String(s.flagName(), "", fmt.Sprintf("Overrides %s (default %q)", s.env, s.def))
+	}
+
+	return func() (*Config, error) {
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		return load(cfg, list, *path, flags, set)
+	}
+}
+
+// load fills cfg from the defaults, the config file at path, the environment and the flags set.
+func load(cfg *Config, list []setting, path string, flags map[string]*string, set map[string]bool) (*Config, error) {
+	file, err := readFile(path, list)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, s := range list {
+		raw, source := s.def, sourceDefault
+		if v := file[s.env]; v != "" {
+			raw, source = v, sourceFile
+		}
+		if v, ok := os.LookupEnv(s.env); ok && v != "" {
+			raw, source = v, sourceEnv
+		}
+		if set[s.flagName()] {
+			raw, source = *flags[s.env], sourceFlag
+		}
+		if err := setValue(s.field, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q (%s): %w", s.env, raw, source, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	secrets, err := readSecrets(cfg.Source)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range list {
+		if v := secrets[s.env]; s.secret && v != "" && !set[s.flagName()] {
+			if err := setValue(s.field, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s (%s): %w", s.env, strings.ToLower(cfg.Source.Kind), err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile returns the settings of the config file by environment variable, none if the
+// file does not exist.
+func readFile(path string, list []setting) (map[string]string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" {
+		values, err := godotenv.Read(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		return values, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	var doc map[string]map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	known := map[string]bool{}
+	for _, s := range list {
+		known[s.group+"."+s.key] = true
+		if v, ok := doc[s.group][s.key]; ok && v != nil {
+			values[s.env] = yamlString(v)
+		}
+	}
+	var unknown []string
+	for group, keys := range doc {
+		for key := range keys {
+			if !known[group+"."+key] {
+				unknown = append(unknown, group+"."+key)
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return nil, fmt.Errorf("config file %s: unknown settings %s", path, strings.Join(unknown, ", "))
+	}
+	return values, nil
+}
+
+// yamlString renders a YAML value in the format of its environment variable: lists are
+// comma separated, mappings CURRENCY:AMOUNT pairs and timestamps dates.
+func yamlString(v any) string {
+	switch v := v.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	case time.Time:
+		return v.Format(time.DateOnly)
+	case map[string]any:
+		items := make([]string, 0, len(v))
+		for key, item := range v {
+			items = append(items, key+":"+fmt.Sprint(item))
+		}
+		slices.Sort(items)
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// setValue parses raw into field.
+func setValue(field reflect.Value, raw string) error {
+	switch ptr := field.Addr().Interface().(type) {
+	case *string:
+		*ptr = raw
+	case *int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New("want an integer")
+		}
+		*ptr = v
+	case *bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("want true or false")
+		}
+		*ptr = v
+	case *float64:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("want a number")
+		}
+		*ptr = v
+	case *[]string:
+		*ptr = splitList(raw)
+	case *[]netip.Prefix:
+		v, err := parsePrefixes(raw)
+		if err != nil {
+			return err
+		}
+		*ptr = v
+	case *money.Amount:
+		v, err := money.Parse(raw)
+		if err != nil {
+			return err
+		}
+		*ptr = v
+	case *map[string]money.Amount:
+		v, err := parseAmounts(raw)
+		if err != nil {
+			return err
+		}
+		*ptr = v
+	case *kafka.RequiredAcks:
+		return ptr.UnmarshalText([]byte(strings.ToLower(raw)))
+	case *time.Time:
+		*ptr = time.Time{}
+		if raw == "" || raw == "none" {
+			return nil
+		}
+		v, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return errors.New(`want a date, e.g. 2026-10-17, or "none"`)
+		}
+		*ptr = v
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(raw string) []string {
+	list := []string{}
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// parsePrefixes parses comma-separated CIDR ranges; a bare IP is a range of one address.
+func parsePrefixes(raw string) ([]netip.Prefix, error) {
+	prefixes := []netip.Prefix{}
+	for _, entry := range splitList(raw) {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				return nil, fmt.Errorf("want CIDR ranges, got %q", entry)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// parseAmounts parses comma-separated CURRENCY:AMOUNT pairs.
+func parseAmounts(raw string) (map[string]money.Amount, error) {
+	amounts := map[string]money.Amount{}
+	for _, entry := range splitList(raw) {
+		code, amount, ok := strings.Cut(entry, ":")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || len(code) != 3 {
+			return nil, fmt.Errorf("want CURRENCY:AMOUNT, got %q", entry)
+		}
+		v, err := money.Parse(strings.TrimSpace(amount))
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid amount of %s %q", code, amount)
+		}
+		amounts[code] = v
+	}
+	return amounts, nil
+}
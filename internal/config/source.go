@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+)
+
+// Kinds of Source.
+const (
+	SourceEnv               = "env"
+	SourceAWSSecretsManager = "aws-secretsmanager"
+	SourceAWSSSM            = "aws-ssm"
+)
+
+// sourceTimeout bounds reading the secret settings from AWS.
+const sourceTimeout = 10 * time.Second
+
+// secretReader returns secret settings by environment variable, e.g. facades.AWSSSMFacade.
+type secretReader interface {
+	Secrets(ctx context.Context) (map[string]string, error)
+}
+
+// readSecrets returns the secret settings by environment variable from the store selected
+// by src, none for SourceEnv.
+func readSecrets(src Source) (map[string]string, error) {
+	kind := strings.ToLower(src.Kind)
+	if kind == SourceEnv {
+		return nil, nil
+	}
+	if kind != SourceAWSSecretsManager && kind != SourceAWSSSM {
+		return nil, fmt.Errorf("CONFIG_SOURCE: must be env, aws-secretsmanager or aws-ssm, got %q", src.Kind)
+	}
+
+	region := src.AWSRegion
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("CONFIG_AWS_REGION or AWS_REGION: required for CONFIG_SOURCE=%s", kind)
+	}
+
+	if kind == SourceAWSSecretsManager && src.AWSSecretID == "" {
+		return nil, errors.New("CONFIG_AWS_SECRET_ID: required for CONFIG_SOURCE=aws-secretsmanager")
+	}
+	if kind == SourceAWSSSM && src.AWSSSMPath == "" {
+		return nil, errors.New("CONFIG_AWS_SSM_PATH: required for CONFIG_SOURCE=aws-ssm")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sourceTimeout)
+	defer cancel()
+	client := &http.Client{Timeout: sourceTimeout}
+	creds, err := facades.LoadAWSCredentials(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("CONFIG_SOURCE=%s: %w", kind, err)
+	}
+
+	var reader secretReader = facades.NewAWSSSMFacade(client, creds, region, src.AWSEndpoint, src.AWSSSMPath)
+	if kind == SourceAWSSecretsManager {
+		reader = facades.NewAWSSecretsManagerFacade(client, creds, region, src.AWSEndpoint, src.AWSSecretID)
+	}
+	values, err := reader.Secrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("CONFIG_SOURCE=%s: %w", kind, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// normalize canonicalizes case-insensitive settings and fills in derived defaults.
+func (c *Config) normalize() {
+	if c.App.InstanceID == "" {
+		c.App.InstanceID, _ = os.Hostname()
+	}
+	for i, code := range c.Wallet.InitialCurrencies {
+		c.Wallet.InitialCurrencies[i] = strings.ToUpper(code)
+	}
+	c.Exchange.PivotCurrency = strings.ToUpper(c.Exchange.PivotCurrency)
+	if c.Exchange.PivotCurrency == "NONE" {
+		c.Exchange.PivotCurrency = ""
+	}
+	c.Kafka.EventFormat = strings.ToLower(c.Kafka.EventFormat)
+	c.Kafka.SASLMechanism = strings.ToUpper(c.Kafka.SASLMechanism)
+	c.SchemaRegistry.URL = strings.TrimRight(c.SchemaRegistry.URL, "/")
+	c.Events.Broker = strings.ToLower(c.Events.Broker)
+	c.Log.Format = strings.ToLower(c.Log.Format)
+	c.Vault.Auth = strings.ToLower(c.Vault.Auth)
+	c.Source.Kind = strings.ToLower(c.Source.Kind)
+}
+
+// Validate reports every invalid or contradictory setting, named by its environment variable.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	// Log
+	_, err := zapcore.ParseLevel(c.App.LogLevel)
+	check(err == nil, "APP_LOG_LEVEL: unknown level %q", c.App.LogLevel)
+	check(c.Log.Format == "json" || c.Log.Format == "console",
+		"LOG_FORMAT: must be json or console, got %q", c.Log.Format)
+	check(c.Log.Output != "", "LOG_OUTPUT: must be stderr, stdout or a file path")
+	check(c.Log.FileMaxSizeMB > 0 && c.Log.FileMaxBackups >= 0,
+		"LOG_FILE_MAX_SIZE_MB must be positive and LOG_FILE_MAX_BACKUPS not negative, got %d/%d",
+		c.Log.FileMaxSizeMB, c.Log.FileMaxBackups)
+	check(c.Log.SamplingInitial >= 0 && c.Log.SamplingThereafter >= 1,
+		"LOG_SAMPLING_INITIAL must not be negative and LOG_SAMPLING_THEREAFTER must be positive, got %d/%d",
+		c.Log.SamplingInitial, c.Log.SamplingThereafter)
+	_, err = logger.ParsePackageLevels(c.Log.PackageLevels)
+	check(err == nil, "LOG_PACKAGE_LEVELS: %v", err)
+
+	// Rates
+	check(c.Rates.CacheTTLMinSecond > 0 && c.Rates.CacheTTLMinSecond <= c.Rates.CacheTTLMaxSecond,
+		"RATE_CACHE_TTL_MIN_SECOND must be positive and not above RATE_CACHE_TTL_MAX_SECOND, got %d/%d",
+		c.Rates.CacheTTLMinSecond, c.Rates.CacheTTLMaxSecond)
+	check(c.Rates.MaxStalenessSecond >= 0,
+		"RATE_MAX_STALENESS_SECOND must not be negative, got %d", c.Rates.MaxStalenessSecond)
+	check(c.Rates.ProviderFailureThreshold >= 1 && c.Rates.ProviderCooldownSecond >= 0,
+		"RATE_PROVIDER_*: need a failure threshold of at least 1 and a non-negative cooldown, got %d/%d",
+		c.Rates.ProviderFailureThreshold, c.Rates.ProviderCooldownSecond)
+	check(c.Rates.PrewarmIntervalSecond >= 0,
+		"RATE_PREWARM_INTERVAL_SECOND must not be negative, got %d", c.Rates.PrewarmIntervalSecond)
+
+	// Postgres
+	check(c.Postgres.ConnMaxLifetimeSecond >= 0 && c.Postgres.ConnMaxIdleTimeSecond >= 0,
+		"POSTGRES_CONN_MAX_LIFETIME_SECOND and POSTGRES_CONN_MAX_IDLE_TIME_SECOND must not be negative, got %d/%d",
+		c.Postgres.ConnMaxLifetimeSecond, c.Postgres.ConnMaxIdleTimeSecond)
+	check(c.Postgres.TxRetryAttempts >= 1 && c.Postgres.TxRetryBackoffMs >= 0 &&
+		c.Postgres.TxRetryMaxBackoffMs >= c.Postgres.TxRetryBackoffMs,
+		"POSTGRES_TX_RETRY_*: need at least 1 attempt and 0 <= backoff <= max backoff, got %d/%d/%d",
+		c.Postgres.TxRetryAttempts, c.Postgres.TxRetryBackoffMs, c.Postgres.TxRetryMaxBackoffMs)
+	check(c.Postgres.QueryTimeoutMs >= 0 && c.Postgres.SlowQueryMs >= 0,
+		"POSTGRES_QUERY_TIMEOUT_MS and POSTGRES_SLOW_QUERY_MS must not be negative, got %d/%d",
+		c.Postgres.QueryTimeoutMs, c.Postgres.SlowQueryMs)
+
+	// History
+	check(c.History.PartitionsAhead >= 0 && c.History.RetentionMonths >= 0,
+		"HISTORY_PARTITIONS_AHEAD and HISTORY_RETENTION_MONTHS must not be negative, got %d/%d",
+		c.History.PartitionsAhead, c.History.RetentionMonths)
+	check(c.History.RetentionMonths == 0 || c.History.ArchiveDir != "",
+		"HISTORY_ARCHIVE_DIR: required with HISTORY_RETENTION_MONTHS")
+
+	// Auth
+	check(c.Auth.BcryptCost >= bcrypt.MinCost && c.Auth.BcryptCost <= bcrypt.MaxCost,
+		"BCRYPT_COST must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, c.Auth.BcryptCost)
+
+	// Startup
+	check(c.Startup.WaitSecond >= 0 && c.Startup.RetryBackoffMs > 0 && c.Startup.RetryMaxBackoffMs >= c.Startup.RetryBackoffMs,
+		"STARTUP_*: need a non-negative wait and 0 < backoff <= max backoff, got %d/%d/%d",
+		c.Startup.WaitSecond, c.Startup.RetryBackoffMs, c.Startup.RetryMaxBackoffMs)
+
+	// Config source
+	check(c.Source.Kind == SourceEnv || c.Source.Kind == SourceAWSSecretsManager || c.Source.Kind == SourceAWSSSM,
+		"CONFIG_SOURCE: must be env, aws-secretsmanager or aws-ssm, got %q", c.Source.Kind)
+
+	// Vault
+	if c.Vault.Addr != "" {
+		check(c.Vault.Auth == "token" || c.Vault.Auth == "kubernetes",
+			"VAULT_AUTH: must be token or kubernetes, got %q", c.Vault.Auth)
+		check(c.Vault.Auth != "token" || c.Vault.Token != "", "VAULT_TOKEN: required for VAULT_AUTH=token")
+		check(c.Vault.Auth != "kubernetes" || c.Vault.KubernetesRole != "", "VAULT_K8S_ROLE: required for VAULT_AUTH=kubernetes")
+		check(c.Vault.SecretPath != "" && c.Vault.KVMount != "", "VAULT_KV_MOUNT and VAULT_SECRET_PATH must be set")
+		check(c.Vault.RenewIntervalSecond > 0,
+			"VAULT_RENEW_INTERVAL_SECOND must be positive, got %d", c.Vault.RenewIntervalSecond)
+	}
+
+	// Faults
+	for _, target := range c.Faults.Targets {
+		check(target == "postgres" || target == "redis" || target == "grpc",
+			"FAULT_INJECTION_TARGETS: unknown target %q, want postgres, redis or grpc", target)
+	}
+	check(c.Faults.ErrorRate >= 0 && c.Faults.ErrorRate <= 1,
+		"FAULT_INJECTION_ERROR_RATE must be between 0 and 1, got %v", c.Faults.ErrorRate)
+	check(!c.Faults.Enabled || c.App.Env != "production",
+		"FAULT_INJECTION_ENABLED must not be set in production")
+
+	// Wallet
+	check(c.Wallet.UserLockTTLSecond > 0 && c.Wallet.UserLockWaitMs >= 0,
+		"USER_LOCK_TTL_SECOND must be positive and USER_LOCK_WAIT_MS not negative, got %d/%d",
+		c.Wallet.UserLockTTLSecond, c.Wallet.UserLockWaitMs)
+	for _, code := range c.Wallet.InitialCurrencies {
+		check(len(code) == 3, "WALLET_INITIAL_CURRENCIES: invalid currency code %q", code)
+	}
+
+	// Exchange
+	check(c.Exchange.PivotCurrency == "" || len(c.Exchange.PivotCurrency) == 3,
+		"EXCHANGE_PIVOT_CURRENCY: invalid currency code %q, want a code or none", c.Exchange.PivotCurrency)
+	check(c.Exchange.MinAmount >= 0 && c.Exchange.MaxAmount >= 0 &&
+		(c.Exchange.MaxAmount == 0 || c.Exchange.MaxAmount >= c.Exchange.MinAmount),
+		"EXCHANGE_MIN_AMOUNT and EXCHANGE_MAX_AMOUNT must not be negative and min must not exceed max, got %s/%s",
+		c.Exchange.MinAmount, c.Exchange.MaxAmount)
+
+	// Exchanger
+	check(c.Exchanger.RetryAttempts >= 1 && c.Exchanger.RetryBackoffMs >= 0 &&
+		c.Exchanger.RetryMaxBackoffMs >= c.Exchanger.RetryBackoffMs && c.Exchanger.AttemptTimeoutMs >= 0,
+		"GW_EXCHANGER_RETRY_*: need at least 1 attempt and 0 <= backoff <= max backoff, got %d/%d/%d, attempt timeout %d",
+		c.Exchanger.RetryAttempts, c.Exchanger.RetryBackoffMs, c.Exchanger.RetryMaxBackoffMs, c.Exchanger.AttemptTimeoutMs)
+	check((c.Exchanger.CertFile == "") == (c.Exchanger.KeyFile == ""),
+		"GW_EXCHANGER_TLS_CERT_FILE and GW_EXCHANGER_TLS_KEY_FILE must be set together")
+	check(c.Exchanger.TLS || c.Exchanger.CAFile == "" && c.Exchanger.CertFile == "" &&
+		c.Exchanger.ServerName == "" && c.Exchanger.Token == "",
+		"GW_EXCHANGER_TLS_* and GW_EXCHANGER_TOKEN require GW_EXCHANGER_TLS_ENABLED=true")
+
+	// Kafka
+	check(c.Kafka.PublishAttempts >= 1 && c.Kafka.PublishRetryBackoffMs >= 0,
+		"KAFKA_PUBLISH_*: need at least 1 attempt and a non-negative backoff, got %d/%d",
+		c.Kafka.PublishAttempts, c.Kafka.PublishRetryBackoffMs)
+	check(c.Kafka.EventFormat == "json" || c.Kafka.EventFormat == "avro",
+		"KAFKA_EVENT_FORMAT: must be json or avro, got %q", c.Kafka.EventFormat)
+	check(c.Kafka.EventFormat != "avro" || c.SchemaRegistry.URL != "",
+		"SCHEMA_REGISTRY_URL: required for KAFKA_EVENT_FORMAT=avro")
+	check(c.Kafka.TransactionsTopic != c.Kafka.Topic,
+		"KAFKA_TRANSACTIONS_TOPIC: must differ from KAFKA_TOPIC %q", c.Kafka.Topic)
+	check(c.Kafka.SASLMechanism == "" || slices.Contains([]string{"PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"}, c.Kafka.SASLMechanism),
+		"KAFKA_SASL_MECHANISM: must be PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512, got %q", c.Kafka.SASLMechanism)
+	check((c.Kafka.SASLMechanism == "") == (c.Kafka.SASLUsername == ""),
+		"KAFKA_SASL_MECHANISM and KAFKA_SASL_USERNAME must be set together")
+	check((c.Kafka.CertFile == "") == (c.Kafka.KeyFile == ""),
+		"KAFKA_TLS_CERT_FILE and KAFKA_TLS_KEY_FILE must be set together")
+	check(c.Kafka.TLS || c.Kafka.CAFile == "" && c.Kafka.CertFile == "" && c.Kafka.ServerName == "",
+		"KAFKA_TLS_* require KAFKA_TLS_ENABLED=true")
+
+	// Events
+	check(slices.Contains([]string{"kafka", "nats", "rabbitmq"}, c.Events.Broker),
+		"EVENT_BROKER: must be kafka, nats or rabbitmq, got %q", c.Events.Broker)
+	check(c.Events.BufferSize >= 1 && c.Events.BatchSize >= 1 && c.Events.FlushIntervalMs >= 1,
+		"EVENT_BUFFER_SIZE, EVENT_BATCH_SIZE and EVENT_FLUSH_INTERVAL_MS must be positive, got %d/%d/%d",
+		c.Events.BufferSize, c.Events.BatchSize, c.Events.FlushIntervalMs)
+
+	return errors.Join(errs...)
+}
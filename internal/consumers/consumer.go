@@ -0,0 +1,173 @@
+// Package consumers reads commands and confirmations other services send over Kafka.
+package consumers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// RetryMin is the delay before the first retry of a message that failed transiently.
+	// It doubles with every failed attempt.
+	RetryMin = time.Second
+	// RetryMax is the longest delay between retries of a message.
+	RetryMax = time.Minute
+)
+
+// MessageReader reads messages of a consumer group, e.g. *kafka.Reader.
+type MessageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)         // Returns the next message without committing it
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error // Commits the offsets of processed messages
+	Close() error                                                    // Leaves the consumer group
+}
+
+// Handler processes a message. Errors wrapped by Permanent are not retried.
+type Handler interface {
+	Handle(ctx context.Context, msg kafka.Message) error
+}
+
+// permanentError marks a failure that retrying cannot fix, e.g. a malformed message.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying: the message is skipped.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked by Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// Consumer reads the messages of a topic one at a time and hands them to a handler. A message
+// is committed once it was processed or skipped; transient failures are retried with
+// exponential backoff, so delivery is at least once and handlers drop redeliveries by key.
+type Consumer struct {
+	name     string
+	reader   MessageReader
+	handler  Handler
+	retryMin time.Duration
+	retryMax time.Duration
+}
+
+// ConsumerOpt defines a functional option for Consumer.
+type ConsumerOpt func(*Consumer)
+
+// WithRetryBackoff sets the delay before the first retry of a message and the longest delay between retries.
+func WithRetryBackoff(minDelay, maxDelay time.Duration) ConsumerOpt {
+	return func(c *Consumer) {
+		c.retryMin = minDelay
+		c.retryMax = maxDelay
+	}
+}
+
+// NewConsumer creates a consumer that hands the messages of reader to handler. The name
+// identifies it in logs and metrics.
+func NewConsumer(name string, reader MessageReader, handler Handler, opts ...ConsumerOpt) *Consumer {
+	c := &Consumer{name: name, reader: reader, handler: handler, retryMin: RetryMin, retryMax: RetryMax}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Name returns the name of the consumer.
+func (c *Consumer) Name() string {
+	return c.name
+}
+
+// Run consumes messages until ctx is done, then leaves the consumer group. A message being
+// processed when ctx is done is finished first, so shutdown does not abandon it halfway.
+func (c *Consumer) Run(ctx context.Context) {
+	defer func() {
+		if err := c.reader.Close(); err != nil {
+			logger.FromContext(ctx).Errorw("failed to close consumer", "consumer", c.name, "error", err)
+		}
+		logger.FromContext(ctx).Infow("consumer stopped", "consumer", c.name)
+	}()
+	logger.FromContext(ctx).Infow("consumer started", "consumer", c.name)
+
+	fetchFailures := 0
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.FromContext(ctx).Errorw("failed to fetch message", "consumer", c.name, "error", err)
+			if !sleep(ctx, c.backoff(fetchFailures)) {
+				return
+			}
+			fetchFailures++
+			continue
+		}
+		fetchFailures = 0
+
+		if !c.process(ctx, msg) {
+			return
+		}
+		// Committed even when ctx is done, the message was processed
+		if err := c.reader.CommitMessages(context.WithoutCancel(ctx), msg); err != nil {
+			// The message is redelivered and dropped by the handler
+			logger.FromContext(ctx).Errorw("failed to commit message", "consumer", c.name, "partition", msg.Partition, "offset", msg.Offset, "error", err)
+		}
+	}
+}
+
+// process handles msg until it is processed or skipped, and returns false if ctx was done
+// while waiting to retry it.
+func (c *Consumer) process(ctx context.Context, msg kafka.Message) bool {
+	for attempt := 0; ; attempt++ {
+		err := c.handler.Handle(context.WithoutCancel(ctx), msg)
+		switch {
+		case err == nil:
+			metrics.ConsumedMessages.WithLabelValues(c.name, metrics.MessageProcessed).Inc()
+			return true
+		case IsPermanent(err):
+			metrics.ConsumedMessages.WithLabelValues(c.name, metrics.MessageSkipped).Inc()
+			logger.FromContext(ctx).Errorw("skipping message", "consumer", c.name, "key", string(msg.Key),
+				"partition", msg.Partition, "offset", msg.Offset, "error", err)
+			return true
+		}
+
+		metrics.ConsumedMessages.WithLabelValues(c.name, metrics.MessageFailed).Inc()
+		delay := c.backoff(attempt)
+		logger.FromContext(ctx).Errorw("failed to process message", "consumer", c.name, "key", string(msg.Key),
+			"partition", msg.Partition, "offset", msg.Offset, "attempts", attempt+1, "retry_in", delay, "error", err)
+		if !sleep(ctx, delay) {
+			return false
+		}
+	}
+}
+
+// backoff returns the delay before the retry following attempt failed attempts.
+func (c *Consumer) backoff(attempt int) time.Duration {
+	delay := c.retryMin
+	for i := 0; i < attempt && delay < c.retryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, c.retryMax)
+}
+
+// sleep waits for d and returns false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,134 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumer_Run(t *testing.T) {
+	msg := kafka.Message{Key: []byte("pay-1"), Value: []byte(`{}`), Offset: 7}
+
+	// fetchOnce returns msg once, then blocks until ctx is done
+	fetchOnce := func(reader *MockMessageReader) {
+		reader.EXPECT().FetchMessage(gomock.Any()).Return(msg, nil)
+		reader.EXPECT().FetchMessage(gomock.Any()).DoAndReturn(func(ctx context.Context) (kafka.Message, error) {
+			<-ctx.Done()
+			return kafka.Message{}, ctx.Err()
+		})
+	}
+
+	tests := []struct {
+		name   string
+		handle func(handler *MockHandler, cancel context.CancelFunc)
+		commit bool
+	}{
+		{
+			name: "processed message is committed",
+			handle: func(handler *MockHandler, _ context.CancelFunc) {
+				handler.EXPECT().Handle(gomock.Any(), msg).Return(nil)
+			},
+			commit: true,
+		},
+		{
+			name: "permanent failure is skipped and committed",
+			handle: func(handler *MockHandler, _ context.CancelFunc) {
+				handler.EXPECT().Handle(gomock.Any(), msg).Return(Permanent(errors.New("malformed")))
+			},
+			commit: true,
+		},
+		{
+			name: "transient failure is retried",
+			handle: func(handler *MockHandler, _ context.CancelFunc) {
+				gomock.InOrder(
+					handler.EXPECT().Handle(gomock.Any(), msg).Return(errors.New("db down")).Times(2),
+					handler.EXPECT().Handle(gomock.Any(), msg).Return(nil),
+				)
+			},
+			commit: true,
+		},
+		{
+			name: "shutdown while retrying leaves the message uncommitted",
+			handle: func(handler *MockHandler, cancel context.CancelFunc) {
+				handler.EXPECT().Handle(gomock.Any(), msg).DoAndReturn(func(context.Context, kafka.Message) error {
+					cancel()
+					return errors.New("db down")
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reader := NewMockMessageReader(ctrl)
+			handler := NewMockHandler(ctrl)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tt.commit {
+				fetchOnce(reader)
+				reader.EXPECT().CommitMessages(gomock.Any(), msg).DoAndReturn(func(context.Context, ...kafka.Message) error {
+					cancel()
+					return nil
+				})
+			} else {
+				reader.EXPECT().FetchMessage(gomock.Any()).Return(msg, nil)
+			}
+			tt.handle(handler, cancel)
+			reader.EXPECT().Close().Return(nil)
+
+			done := make(chan struct{})
+			go func() {
+				NewConsumer("test", reader, handler, WithRetryBackoff(time.Millisecond, time.Millisecond)).Run(ctx)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("consumer did not stop")
+			}
+		})
+	}
+}
+
+func TestConsumer_Run_FetchFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reader := NewMockMessageReader(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The broker is unreachable, the consumer keeps trying until shutdown
+	gomock.InOrder(
+		reader.EXPECT().FetchMessage(gomock.Any()).Return(kafka.Message{}, errors.New("broker unreachable")),
+		reader.EXPECT().FetchMessage(gomock.Any()).DoAndReturn(func(context.Context) (kafka.Message, error) {
+			cancel()
+			return kafka.Message{}, errors.New("broker unreachable")
+		}),
+	)
+	reader.EXPECT().Close().Return(nil)
+
+	NewConsumer("test", reader, NewMockHandler(ctrl), WithRetryBackoff(time.Millisecond, time.Millisecond)).Run(ctx)
+}
+
+func TestConsumer_Backoff(t *testing.T) {
+	c := NewConsumer("test", nil, nil, WithRetryBackoff(time.Second, 5*time.Second))
+	assert.Equal(t, time.Second, c.backoff(0))
+	assert.Equal(t, 2*time.Second, c.backoff(1))
+	assert.Equal(t, 4*time.Second, c.backoff(2))
+	assert.Equal(t, 5*time.Second, c.backoff(3))
+	assert.Equal(t, 5*time.Second, c.backoff(30))
+}
+
+func TestIsPermanent(t *testing.T) {
+	err := errors.New("malformed")
+	assert.True(t, IsPermanent(Permanent(err)))
+	assert.ErrorIs(t, Permanent(err), err)
+	assert.False(t, IsPermanent(err))
+}
@@ -0,0 +1,47 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/segmentio/kafka-go"
+)
+
+// PaymentCreditor credits confirmed external payments to wallets.
+type PaymentCreditor interface {
+	CreditPayment(ctx context.Context, paymentID string, payment models.PaymentConfirmation) (bool, error) // Credits a payment once; false if it already was
+}
+
+// PaymentConfirmationHandler credits the wallets of external payments confirmed by the payment
+// provider. Messages are keyed by the payment ID; a payment is credited once however often its
+// confirmation is delivered.
+type PaymentConfirmationHandler struct {
+	creditor PaymentCreditor
+}
+
+// NewPaymentConfirmationHandler creates a new PaymentConfirmationHandler.
+func NewPaymentConfirmationHandler(creditor PaymentCreditor) *PaymentConfirmationHandler {
+	return &PaymentConfirmationHandler{creditor: creditor}
+}
+
+// Handle credits the payment of a confirmation. Malformed and invalid confirmations are skipped.
+func (h *PaymentConfirmationHandler) Handle(ctx context.Context, msg kafka.Message) error {
+	if len(msg.Key) == 0 {
+		return Permanent(errors.New("payment confirmation without a payment ID key"))
+	}
+
+	var payment models.PaymentConfirmation
+	if err := json.Unmarshal(msg.Value, &payment); err != nil {
+		return Permanent(fmt.Errorf("decode payment confirmation: %w", err))
+	}
+
+	_, err := h.creditor.CreditPayment(ctx, string(msg.Key), payment)
+	if errors.Is(err, services.ErrInvalidPaymentConfirmation) {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/consumers/payment_confirmation.go
+
+// Package consumers is a generated GoMock package.
+package consumers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockPaymentCreditor is a mock of PaymentCreditor interface.
+type MockPaymentCreditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentCreditorMockRecorder
+}
+
+// MockPaymentCreditorMockRecorder is the mock recorder for MockPaymentCreditor.
+type MockPaymentCreditorMockRecorder struct {
+	mock *MockPaymentCreditor
+}
+
+// NewMockPaymentCreditor creates a new mock instance.
+func NewMockPaymentCreditor(ctrl *gomock.Controller) *MockPaymentCreditor {
+	mock := &MockPaymentCreditor{ctrl: ctrl}
+	mock.recorder = &MockPaymentCreditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentCreditor) EXPECT() *MockPaymentCreditorMockRecorder {
+	return m.recorder
+}
+
+// CreditPayment mocks base method.
+func (m *MockPaymentCreditor) CreditPayment(ctx context.Context, paymentID string, payment models.PaymentConfirmation) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreditPayment", ctx, paymentID, payment)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreditPayment indicates an expected call of CreditPayment.
+func (mr *MockPaymentCreditorMockRecorder) CreditPayment(ctx, paymentID, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreditPayment", reflect.TypeOf((*MockPaymentCreditor)(nil).CreditPayment), ctx, paymentID, payment)
+}
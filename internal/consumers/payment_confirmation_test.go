@@ -0,0 +1,88 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentConfirmationHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	payment := models.PaymentConfirmation{UserID: userID, Currency: models.USD, Amount: money.MustParse("100.50"), Reference: "INV-42"}
+	value := []byte(`{"user_id":"` + userID.String() + `","currency":"USD","amount":"100.50","reference":"INV-42"}`)
+
+	tests := []struct {
+		name          string
+		msg           kafka.Message
+		setupMocks    func(creditor *MockPaymentCreditor)
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{
+			name: "credited",
+			msg:  kafka.Message{Key: []byte("pay-1"), Value: value},
+			setupMocks: func(creditor *MockPaymentCreditor) {
+				creditor.EXPECT().CreditPayment(ctx, "pay-1", payment).Return(true, nil)
+			},
+		},
+		{
+			name: "already credited",
+			msg:  kafka.Message{Key: []byte("pay-1"), Value: value},
+			setupMocks: func(creditor *MockPaymentCreditor) {
+				creditor.EXPECT().CreditPayment(ctx, "pay-1", payment).Return(false, nil)
+			},
+		},
+		{
+			name:          "no key",
+			msg:           kafka.Message{Value: value},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:          "malformed",
+			msg:           kafka.Message{Key: []byte("pay-1"), Value: []byte(`{"amount":"abc"}`)},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name: "invalid",
+			msg:  kafka.Message{Key: []byte("pay-1"), Value: value},
+			setupMocks: func(creditor *MockPaymentCreditor) {
+				creditor.EXPECT().CreditPayment(ctx, "pay-1", payment).Return(false, services.ErrInvalidPaymentConfirmation)
+			},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name: "transient failure",
+			msg:  kafka.Message{Key: []byte("pay-1"), Value: value},
+			setupMocks: func(creditor *MockPaymentCreditor) {
+				creditor.EXPECT().CreditPayment(ctx, "pay-1", payment).Return(false, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			creditor := NewMockPaymentCreditor(ctrl)
+			if tt.setupMocks != nil {
+				tt.setupMocks(creditor)
+			}
+
+			err := NewPaymentConfirmationHandler(creditor).Handle(ctx, tt.msg)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantPermanent, IsPermanent(err))
+		})
+	}
+}
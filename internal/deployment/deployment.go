@@ -0,0 +1,84 @@
+// Package deployment describes where the service instance runs and tags logs, metrics
+// and Kafka events with it.
+package deployment
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Info identifies the deployment of the running instance.
+type Info struct {
+	Env        string // Environment, e.g. production or staging
+	Region     string // Region or data center
+	InstanceID string // Instance identifier, the hostname by default
+}
+
+// Labels returns the non-empty metadata as metric labels.
+func (i Info) Labels() map[string]string {
+	labels := map[string]string{}
+	for _, kv := range i.pairs() {
+		labels[kv[0]] = kv[1]
+	}
+	return labels
+}
+
+// LogFields returns the non-empty metadata as key-value pairs for structured logging.
+func (i Info) LogFields() []any {
+	var fields []any
+	for _, kv := range i.pairs() {
+		fields = append(fields, kv[0], kv[1])
+	}
+	return fields
+}
+
+// KafkaHeaders returns the non-empty metadata as Kafka message headers.
+func (i Info) KafkaHeaders() []kafka.Header {
+	var headers []kafka.Header
+	for _, kv := range i.pairs() {
+		headers = append(headers, kafka.Header{Key: kv[0], Value: []byte(kv[1])})
+	}
+	return headers
+}
+
+func (i Info) pairs() [][2]string {
+	var pairs [][2]string
+	for _, kv := range [][2]string{
+		{"env", i.Env},
+		{"region", i.Region},
+		{"instance_id", i.InstanceID},
+	} {
+		if kv[1] != "" {
+			pairs = append(pairs, kv)
+		}
+	}
+	return pairs
+}
+
+// KafkaWriter defines a Kafka writer abstraction.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// TaggedKafkaWriter adds the deployment headers to every message it writes.
+type TaggedKafkaWriter struct {
+	KafkaWriter
+	headers []kafka.Header
+}
+
+// NewTaggedKafkaWriter wraps w so that messages carry the headers of info.
+func NewTaggedKafkaWriter(w KafkaWriter, info Info) *TaggedKafkaWriter {
+	return &TaggedKafkaWriter{KafkaWriter: w, headers: info.KafkaHeaders()}
+}
+
+// WriteMessages appends the deployment headers and writes the messages.
+func (w *TaggedKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	tagged := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		msg.Headers = append(append([]kafka.Header{}, msg.Headers...), w.headers...)
+		tagged[i] = msg
+	}
+	return w.KafkaWriter.WriteMessages(ctx, tagged...)
+}
@@ -0,0 +1,60 @@
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingWriter struct {
+	msgs []kafka.Message
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func (w *recordingWriter) Close() error { return nil }
+
+func TestInfo(t *testing.T) {
+	info := Info{Env: "production", Region: "eu-west", InstanceID: "wallet-1"}
+
+	assert.Equal(t, map[string]string{"env": "production", "region": "eu-west", "instance_id": "wallet-1"}, info.Labels())
+	assert.Equal(t, []any{"env", "production", "region", "eu-west", "instance_id", "wallet-1"}, info.LogFields())
+	assert.Equal(t, []kafka.Header{
+		{Key: "env", Value: []byte("production")},
+		{Key: "region", Value: []byte("eu-west")},
+		{Key: "instance_id", Value: []byte("wallet-1")},
+	}, info.KafkaHeaders())
+}
+
+func TestInfo_SkipsEmpty(t *testing.T) {
+	info := Info{Env: "staging"}
+
+	assert.Equal(t, map[string]string{"env": "staging"}, info.Labels())
+	assert.Equal(t, []any{"env", "staging"}, info.LogFields())
+	assert.Len(t, info.KafkaHeaders(), 1)
+	assert.Empty(t, Info{}.LogFields())
+}
+
+func TestTaggedKafkaWriter(t *testing.T) {
+	inner := &recordingWriter{}
+	w := NewTaggedKafkaWriter(inner, Info{Env: "production", InstanceID: "wallet-1"})
+
+	original := kafka.Message{Key: []byte("k"), Headers: []kafka.Header{{Key: "trace", Value: []byte("abc")}}}
+	assert.NoError(t, w.WriteMessages(context.Background(), original))
+
+	if assert.Len(t, inner.msgs, 1) {
+		assert.Equal(t, []kafka.Header{
+			{Key: "trace", Value: []byte("abc")},
+			{Key: "env", Value: []byte("production")},
+			{Key: "instance_id", Value: []byte("wallet-1")},
+		}, inner.msgs[0].Headers)
+	}
+	// The caller's message is not modified
+	assert.Len(t, original.Headers, 1)
+	assert.NoError(t, w.Close())
+}
@@ -0,0 +1,266 @@
+package facades
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWS endpoints of the credentials of the container (ECS, EKS Pod Identity) and the instance
+// metadata service (EC2).
+const (
+	awsContainerCredentialsHost = "http://169.254.170.2"
+	awsInstanceMetadataEndpoint = "http://169.254.169.254"
+)
+
+// AWSCredentials sign requests to AWS.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// awsCredentialsResponse is the response of the container and instance credentials endpoints.
+type awsCredentialsResponse struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+}
+
+// LoadAWSCredentials returns the credentials of the process as the AWS SDKs find them, first of:
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (with AWS_SESSION_TOKEN); the container credentials
+// at AWS_CONTAINER_CREDENTIALS_FULL_URI or AWS_CONTAINER_CREDENTIALS_RELATIVE_URI, authorized by
+// AWS_CONTAINER_AUTHORIZATION_TOKEN(_FILE); the role of the EC2 instance from IMDSv2, at
+// AWS_EC2_METADATA_SERVICE_ENDPOINT if set.
+func LoadAWSCredentials(ctx context.Context, client *http.Client) (AWSCredentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return AWSCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if relative := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relative != "" {
+		endpoint = awsContainerCredentialsHost + relative
+	}
+	if endpoint != "" {
+		authorization := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+		if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+			token, err := os.ReadFile(file)
+			if err != nil {
+				return AWSCredentials{}, fmt.Errorf("aws: read container authorization token: %w", err)
+			}
+			authorization = strings.TrimSpace(string(token))
+		}
+		header := http.Header{}
+		if authorization != "" {
+			header.Set("Authorization", authorization)
+		}
+		return fetchAWSCredentials(ctx, client, endpoint, header)
+	}
+
+	metadata := os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+	if metadata == "" {
+		metadata = awsInstanceMetadataEndpoint
+	}
+	metadata = strings.TrimRight(metadata, "/")
+	token, err := awsGet(ctx, client, http.MethodPut, metadata+"/latest/api/token", http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"21600"}})
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("aws: no credentials in the environment, the container or the instance metadata: %w", err)
+	}
+	header := http.Header{"X-Aws-Ec2-Metadata-Token": {string(token)}}
+	role, err := awsGet(ctx, client, http.MethodGet, metadata+"/latest/meta-data/iam/security-credentials/", header)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("aws: instance role: %w", err)
+	}
+	name, _, _ := strings.Cut(strings.TrimSpace(string(role)), "\n")
+	return fetchAWSCredentials(ctx, client, metadata+"/latest/meta-data/iam/security-credentials/"+name, header)
+}
+
+// fetchAWSCredentials reads credentials from a container or instance credentials endpoint.
+func fetchAWSCredentials(ctx context.Context, client *http.Client, endpoint string, header http.Header) (AWSCredentials, error) {
+	body, err := awsGet(ctx, client, http.MethodGet, endpoint, header)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("aws: credentials: %w", err)
+	}
+	var creds awsCredentialsResponse
+	if err := json.Unmarshal(body, &creds); err != nil {
+		return AWSCredentials{}, fmt.Errorf("aws: decode credentials: %w", err)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("aws: credentials endpoint returned no keys")
+	}
+	return AWSCredentials{AccessKeyID: creds.AccessKeyID, SecretAccessKey: creds.SecretAccessKey, SessionToken: creds.Token}, nil
+}
+
+// awsGet sends a request without a body and returns the body of a 200 response.
+func awsGet(ctx context.Context, client *http.Client, method, endpoint string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header.Clone()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP %d", endpoint, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// awsJSONClient calls an AWS API of the JSON 1.1 protocol, such as Secrets Manager and SSM:
+// POST / with the operation in the X-Amz-Target header, signed with Signature Version 4.
+// Errors are returned as {"__type": ..., "message": ...}.
+type awsJSONClient struct {
+	client   *http.Client
+	creds    AWSCredentials
+	region   string
+	service  string // Signing name, e.g. secretsmanager
+	target   string // Prefix of the operations, e.g. secretsmanager
+	endpoint string
+}
+
+// newAWSJSONClient returns a client of service in region, calling endpoint if set or the
+// regional endpoint of service.
+func newAWSJSONClient(client *http.Client, creds AWSCredentials, region, service, target, endpoint string) *awsJSONClient {
+	if endpoint == "" {
+		endpoint = "https://" + service + "." + region + ".amazonaws.com"
+	}
+	return &awsJSONClient{client: client, creds: creds, region: region, service: service, target: target, endpoint: strings.TrimRight(endpoint, "/")}
+}
+
+// awsErrorResponse is the body of an error response. Services differ in the case of message.
+type awsErrorResponse struct {
+	Type         string `json:"__type"`
+	Message      string `json:"message"`
+	MessageUpper string `json:"Message"`
+}
+
+// call runs operation with in as the body and decodes the response into out.
+func (c *awsJSONClient) call(ctx context.Context, operation string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", c.target+"."+operation)
+	signAWSRequest(req, body, c.creds, c.region, c.service, time.Now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var awsErr awsErrorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&awsErr)
+		// The type may be prefixed by a namespace, e.g. com.amazonaws...#ResourceNotFoundException
+		_, kind, _ := strings.Cut(awsErr.Type, "#")
+		if kind == "" {
+			kind = awsErr.Type
+		}
+		return fmt.Errorf("%s %s returned HTTP %d: %s: %s", c.service, operation, resp.StatusCode, kind, awsErr.Message+awsErr.MessageUpper)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", c.service, err)
+	}
+	return nil
+}
+
+// signAWSRequest signs req, whose body is body, with Signature Version 4 at t.
+func signAWSRequest(req *http.Request, body []byte, creds AWSCredentials, region, service string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Canonical headers: host and every header set, lower case and sorted
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query sorted by name and value, encoded as Signature Version 4 requires.
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(name)+"="+awsEscape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes s except for unreserved characters.
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
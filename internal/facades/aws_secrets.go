@@ -0,0 +1,111 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// AWSSecretsManagerFacade reads a secret of AWS Secrets Manager whose string is a JSON object
+// of string values, e.g. {"POSTGRES_PASSWORD": "..."}.
+type AWSSecretsManagerFacade struct {
+	api      *awsJSONClient
+	secretID string
+}
+
+// NewAWSSecretsManagerFacade creates a new facade reading secretID, the name or ARN of the
+// secret, in region. endpoint overrides the regional endpoint if set, e.g. for LocalStack.
+func NewAWSSecretsManagerFacade(client *http.Client, creds AWSCredentials, region, endpoint, secretID string) *AWSSecretsManagerFacade {
+	return &AWSSecretsManagerFacade{
+		api:      newAWSJSONClient(client, creds, region, "secretsmanager", "secretsmanager", endpoint),
+		secretID: secretID,
+	}
+}
+
+// getSecretValueRequest is the body of GetSecretValue.
+type getSecretValueRequest struct {
+	SecretID string `json:"SecretId"`
+}
+
+// getSecretValueResponse is the response of GetSecretValue.
+type getSecretValueResponse struct {
+	SecretString string `json:"SecretString"`
+}
+
+// Secrets returns the values of the secret by key.
+func (f *AWSSecretsManagerFacade) Secrets(ctx context.Context) (map[string]string, error) {
+	var resp getSecretValueResponse
+	if err := f.api.call(ctx, "GetSecretValue", getSecretValueRequest{SecretID: f.secretID}, &resp); err != nil {
+		logger.FromContext(ctx).Errorw("failed to read secret from secrets manager", "secret_id", f.secretID, "error", err)
+		return nil, err
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal([]byte(resp.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object: %w", f.secretID, err)
+	}
+	secrets := make(map[string]string, len(values))
+	for key, value := range values {
+		if s, ok := value.(string); ok {
+			secrets[key] = s
+		}
+	}
+	return secrets, nil
+}
+
+// AWSSSMFacade reads the parameters under a path of AWS Systems Manager Parameter Store,
+// decrypting SecureString parameters. Parameters are keyed by their name relative to the
+// path, e.g. POSTGRES_PASSWORD for /gw-currency-wallet/POSTGRES_PASSWORD.
+type AWSSSMFacade struct {
+	api  *awsJSONClient
+	path string
+}
+
+// NewAWSSSMFacade creates a new facade reading the parameters under path in region.
+// endpoint overrides the regional endpoint if set, e.g. for LocalStack.
+func NewAWSSSMFacade(client *http.Client, creds AWSCredentials, region, endpoint, path string) *AWSSSMFacade {
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return &AWSSSMFacade{api: newAWSJSONClient(client, creds, region, "ssm", "AmazonSSM", endpoint), path: path}
+}
+
+// getParametersByPathRequest is the body of GetParametersByPath.
+type getParametersByPathRequest struct {
+	Path           string `json:"Path"`
+	WithDecryption bool   `json:"WithDecryption"`
+	NextToken      string `json:"NextToken,omitempty"`
+}
+
+// getParametersByPathResponse is a page of the response of GetParametersByPath.
+type getParametersByPathResponse struct {
+	Parameters []struct {
+		Name  string `json:"Name"`
+		Value string `json:"Value"`
+	} `json:"Parameters"`
+	NextToken string `json:"NextToken"`
+}
+
+// Secrets returns the values of the parameters directly under the path by name.
+func (f *AWSSSMFacade) Secrets(ctx context.Context) (map[string]string, error) {
+	secrets := map[string]string{}
+	req := getParametersByPathRequest{Path: f.path, WithDecryption: true}
+	for {
+		var page getParametersByPathResponse
+		if err := f.api.call(ctx, "GetParametersByPath", req, &page); err != nil {
+			logger.FromContext(ctx).Errorw("failed to read parameters from ssm", "path", f.path, "error", err)
+			return nil, err
+		}
+		for _, param := range page.Parameters {
+			secrets[strings.TrimPrefix(param.Name, f.path)] = param.Value
+		}
+		if page.NextToken == "" {
+			return secrets, nil
+		}
+		req.NextToken = page.NextToken
+	}
+}
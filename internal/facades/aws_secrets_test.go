@@ -0,0 +1,74 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAWSSecretsManagerFacade(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request"):
+			w.WriteHeader(http.StatusBadRequest)
+		case body["SecretId"] == "wallet":
+			w.Write([]byte(`{"Name": "wallet", "SecretString": "{\"POSTGRES_PASSWORD\": \"pg\", \"POSTGRES_PORT\": 5432}"}`))
+		case body["SecretId"] == "plain":
+			w.Write([]byte(`{"Name": "plain", "SecretString": "password"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "ResourceNotFoundException", "message": "Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+	creds := AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	ctx := context.Background()
+
+	values, err := NewAWSSecretsManagerFacade(server.Client(), creds, "eu-west-1", server.URL, "wallet").Secrets(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"POSTGRES_PASSWORD": "pg"}, values)
+
+	_, err = NewAWSSecretsManagerFacade(server.Client(), creds, "eu-west-1", server.URL, "plain").Secrets(ctx)
+	assert.ErrorContains(t, err, "secret plain is not a JSON object")
+
+	_, err = NewAWSSecretsManagerFacade(server.Client(), creds, "eu-west-1", server.URL, "missing").Secrets(ctx)
+	assert.ErrorContains(t, err, "HTTP 400: ResourceNotFoundException: Secrets Manager can't find the specified secret.")
+}
+
+func TestAWSSSMFacade(t *testing.T) {
+	var pages int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body getParametersByPathRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Header.Get("X-Amz-Target") != "AmazonSSM.GetParametersByPath" || body.Path != "/wallet/" || !body.WithDecryption {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "com.amazonaws.ssm#ValidationException", "Message": "invalid request"}`))
+			return
+		}
+		pages++
+		if body.NextToken == "" {
+			w.Write([]byte(`{"Parameters": [{"Name": "/wallet/POSTGRES_PASSWORD", "Value": "pg"}], "NextToken": "page-2"}`))
+			return
+		}
+		w.Write([]byte(`{"Parameters": [{"Name": "/wallet/REDIS_PASSWORD", "Value": "redis"}]}`))
+	}))
+	defer server.Close()
+	creds := AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}
+	ctx := context.Background()
+
+	values, err := NewAWSSSMFacade(server.Client(), creds, "eu-west-1", server.URL, "/wallet").Secrets(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"POSTGRES_PASSWORD": "pg", "REDIS_PASSWORD": "redis"}, values)
+	assert.Equal(t, 2, pages)
+
+	_, err = NewAWSSSMFacade(server.Client(), creds, "eu-west-1", server.URL, "/other/").Secrets(ctx)
+	assert.ErrorContains(t, err, "HTTP 400: ValidationException: invalid request")
+}
@@ -0,0 +1,103 @@
+package facades
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAWSRequest(t *testing.T) {
+	// get-vanilla of the Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	assert.NoError(t, err)
+	creds := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+	signAWSRequest(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestLoadAWSCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("environment", func(t *testing.T) {
+		clearAWSEnv(t)
+		t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		t.Setenv("AWS_SESSION_TOKEN", "session")
+
+		creds, err := LoadAWSCredentials(ctx, http.DefaultClient)
+		assert.NoError(t, err)
+		assert.Equal(t, AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}, creds)
+	})
+
+	t.Run("container", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/creds" || r.Header.Get("Authorization") != "pod-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"AccessKeyId": "ASIA", "SecretAccessKey": "secret", "Token": "session", "Expiration": "2030-01-01T00:00:00Z"}`))
+		}))
+		defer server.Close()
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		assert.NoError(t, os.WriteFile(tokenFile, []byte("pod-token\n"), 0o600))
+
+		clearAWSEnv(t)
+		t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL+"/creds")
+		t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", tokenFile)
+
+		creds, err := LoadAWSCredentials(ctx, server.Client())
+		assert.NoError(t, err)
+		assert.Equal(t, AWSCredentials{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "session"}, creds)
+
+		t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", "")
+		_, err = LoadAWSCredentials(ctx, server.Client())
+		assert.ErrorContains(t, err, "HTTP 401")
+	})
+
+	t.Run("instance metadata", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+				w.Write([]byte("imds-token"))
+			case r.Header.Get("X-Aws-Ec2-Metadata-Token") != "imds-token":
+				w.WriteHeader(http.StatusUnauthorized)
+			case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+				w.Write([]byte("wallet-role\n"))
+			case r.URL.Path == "/latest/meta-data/iam/security-credentials/wallet-role":
+				w.Write([]byte(`{"Code": "Success", "AccessKeyId": "ASIA", "SecretAccessKey": "secret", "Token": "session"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		clearAWSEnv(t)
+		t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL+"/")
+
+		creds, err := LoadAWSCredentials(ctx, server.Client())
+		assert.NoError(t, err)
+		assert.Equal(t, AWSCredentials{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "session"}, creds)
+	})
+}
+
+// clearAWSEnv unsets the environment variables LoadAWSCredentials reads for the test.
+func clearAWSEnv(t *testing.T) {
+	for _, key := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN", "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", "AWS_EC2_METADATA_SERVICE_ENDPOINT",
+	} {
+		t.Setenv(key, "")
+	}
+}
@@ -0,0 +1,133 @@
+package facades
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ExchangerSecurity configures how the connection to the exchanger is secured.
+type ExchangerSecurity struct {
+	TLS        bool   // Use TLS; without it the connection is plaintext
+	CAFile     string // PEM bundle the server certificate is verified against, the system roots if empty
+	CertFile   string // Client certificate for mutual TLS, optional
+	KeyFile    string // Key of the client certificate
+	ServerName string // Name expected in the server certificate instead of the dialed host
+	Token      string // Bearer token sent with every call, requires TLS
+}
+
+// DialOptions returns the transport and per-call credentials of the connection.
+func (s ExchangerSecurity) DialOptions() ([]grpc.DialOption, error) {
+	if !s.TLS {
+		if s.Token != "" {
+			return nil, errors.New("exchanger token requires TLS")
+		}
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+	}
+
+	cfg, err := tlsConfig("exchanger", s.CAFile, s.CertFile, s.KeyFile, s.ServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(cfg))}
+	if s.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(s.Token)))
+	}
+	return opts, nil
+}
+
+// KafkaSecurity configures how the connections to the Kafka brokers are secured.
+type KafkaSecurity struct {
+	SASLMechanism string // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512; empty disables SASL
+	Username      string // SASL user
+	Password      string // SASL password
+	TLS           bool   // Use TLS; without it the connection is plaintext
+	CAFile        string // PEM bundle the broker certificates are verified against, the system roots if empty
+	CertFile      string // Client certificate for mutual TLS, optional
+	KeyFile       string // Key of the client certificate
+	ServerName    string // Name expected in the broker certificates instead of the dialed host
+}
+
+// Dialer returns the dialer of the Kafka writers and readers.
+func (s KafkaSecurity) Dialer() (*kafka.Dialer, error) {
+	dialer := &kafka.Dialer{Timeout: 10 * time.Second, DualStack: true}
+
+	switch s.SASLMechanism {
+	case "":
+	case "PLAIN":
+		dialer.SASLMechanism = plain.Mechanism{Username: s.Username, Password: s.Password}
+	case "SCRAM-SHA-256", "SCRAM-SHA-512":
+		algo := scram.SHA256
+		if s.SASLMechanism == "SCRAM-SHA-512" {
+			algo = scram.SHA512
+		}
+		mechanism, err := scram.Mechanism(algo, s.Username, s.Password)
+		if err != nil {
+			return nil, fmt.Errorf("configure Kafka SASL: %w", err)
+		}
+		dialer.SASLMechanism = mechanism
+	default:
+		return nil, fmt.Errorf("unsupported Kafka SASL mechanism %q", s.SASLMechanism)
+	}
+
+	if s.TLS {
+		cfg, err := tlsConfig("Kafka", s.CAFile, s.CertFile, s.KeyFile, s.ServerName)
+		if err != nil {
+			return nil, err
+		}
+		dialer.TLS = cfg
+	}
+	return dialer, nil
+}
+
+// tlsConfig returns the TLS configuration of a client of service, trusting caFile and
+// presenting certFile if set.
+func tlsConfig(service, caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read %s CA: %w", service, err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s CA %s", service, caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load %s client certificate: %w", service, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// bearerToken authenticates every call with an authorization header.
+type bearerToken string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity keeps the token off plaintext connections.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,136 @@
+package facades
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self-signed certificate and its key as PEM files into dir.
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must(err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "exchanger"},
+		DNSNames:     []string{"exchanger"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	must(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	must(err)
+
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	must(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	must(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestExchangerSecurity_DialOptions(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir)
+
+	t.Run("plaintext", func(t *testing.T) {
+		opts, err := ExchangerSecurity{}.DialOptions()
+		assert.NoError(t, err)
+		assert.Len(t, opts, 1)
+	})
+
+	t.Run("token requires TLS", func(t *testing.T) {
+		_, err := ExchangerSecurity{Token: "secret"}.DialOptions()
+		assert.Error(t, err)
+	})
+
+	t.Run("mutual TLS with token", func(t *testing.T) {
+		opts, err := ExchangerSecurity{
+			TLS: true, CAFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "exchanger", Token: "secret",
+		}.DialOptions()
+		assert.NoError(t, err)
+		assert.Len(t, opts, 2)
+	})
+
+	t.Run("missing CA", func(t *testing.T) {
+		_, err := ExchangerSecurity{TLS: true, CAFile: filepath.Join(dir, "missing.pem")}.DialOptions()
+		assert.Error(t, err)
+	})
+
+	t.Run("CA without certificates", func(t *testing.T) {
+		_, err := ExchangerSecurity{TLS: true, CAFile: keyFile}.DialOptions()
+		assert.Error(t, err)
+	})
+
+	t.Run("client certificate without key", func(t *testing.T) {
+		_, err := ExchangerSecurity{TLS: true, CertFile: certFile}.DialOptions()
+		assert.Error(t, err)
+	})
+}
+
+func TestKafkaSecurity_Dialer(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir)
+
+	t.Run("plaintext", func(t *testing.T) {
+		dialer, err := KafkaSecurity{}.Dialer()
+		assert.NoError(t, err)
+		assert.Nil(t, dialer.SASLMechanism)
+		assert.Nil(t, dialer.TLS)
+		assert.Equal(t, 10*time.Second, dialer.Timeout)
+	})
+
+	for _, mechanism := range []string{"PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512"} {
+		t.Run(mechanism, func(t *testing.T) {
+			dialer, err := KafkaSecurity{SASLMechanism: mechanism, Username: "wallet", Password: "secret"}.Dialer()
+			assert.NoError(t, err)
+			if assert.NotNil(t, dialer.SASLMechanism) {
+				assert.Equal(t, mechanism, dialer.SASLMechanism.Name())
+			}
+		})
+	}
+
+	t.Run("unsupported mechanism", func(t *testing.T) {
+		_, err := KafkaSecurity{SASLMechanism: "GSSAPI"}.Dialer()
+		assert.Error(t, err)
+	})
+
+	t.Run("mutual TLS", func(t *testing.T) {
+		dialer, err := KafkaSecurity{TLS: true, CAFile: certFile, CertFile: certFile, KeyFile: keyFile, ServerName: "kafka"}.Dialer()
+		assert.NoError(t, err)
+		if assert.NotNil(t, dialer.TLS) {
+			assert.Equal(t, "kafka", dialer.TLS.ServerName)
+			assert.NotNil(t, dialer.TLS.RootCAs)
+			assert.Len(t, dialer.TLS.Certificates, 1)
+		}
+	})
+
+	t.Run("missing CA", func(t *testing.T) {
+		_, err := KafkaSecurity{TLS: true, CAFile: filepath.Join(dir, "missing.pem")}.Dialer()
+		assert.ErrorContains(t, err, "Kafka CA")
+	})
+}
+
+func TestBearerToken(t *testing.T) {
+	md, err := bearerToken("secret").GetRequestMetadata(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer secret"}, md)
+	assert.True(t, bearerToken("secret").RequireTransportSecurity())
+}
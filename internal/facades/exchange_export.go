@@ -0,0 +1,60 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ExchangeExportFacade reads the exchanger's records of the conversions it received receipts for:
+// GET {baseURL}/receipts?from={RFC 3339}&to={RFC 3339} returns {"receipts": [...]} in the
+// format of the published receipts, executed in [from, to).
+type ExchangeExportFacade struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewExchangeExportFacade creates a new facade calling baseURL with client.
+// A non-empty token is sent as a bearer token.
+func NewExchangeExportFacade(client *http.Client, baseURL, token string) *ExchangeExportFacade {
+	return &ExchangeExportFacade{client: client, baseURL: baseURL, token: token}
+}
+
+// exchangeExport is the response of the receipts export endpoint.
+type exchangeExport struct {
+	Receipts []models.ExchangeReceipt `json:"receipts"`
+}
+
+// ListExchanges fetches the exchanger's records of the conversions executed in [from, to).
+func (f *ExchangeExportFacade) ListExchanges(ctx context.Context, from, to time.Time) ([]models.ExchangeReceipt, error) {
+	q := url.Values{}
+	q.Set("from", from.UTC().Format(time.RFC3339))
+	q.Set("to", to.UTC().Format(time.RFC3339))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.baseURL+"/receipts?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.token)
+	}
+
+	resp, err := doHTTP(f.client, req, "exchanger export")
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to fetch exchanger receipts", "from", from, "to", to, "error", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var export exchangeExport
+	if err := json.NewDecoder(resp.Body).Decode(&export); err != nil {
+		return nil, fmt.Errorf("decode exchanger receipts: %w", err)
+	}
+	return export.Receipts, nil
+}
@@ -0,0 +1,44 @@
+package facades
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeExportFacade(t *testing.T) {
+	from := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/receipts", r.URL.Path)
+		assert.Equal(t, "2025-03-14T00:00:00Z", r.URL.Query().Get("from"))
+		assert.Equal(t, "2025-03-15T00:00:00Z", r.URL.Query().Get("to"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"receipts": [{"transaction_id": "6f1c2a4e-8d0b-4b7a-9c3e-2f5d1a7b9e01", "operation": "exchange",
+			"from_currency": "USD", "to_currency": "EUR", "amount": 100, "to_amount": 90, "rate": 0.9,
+			"executed_at": "2025-03-14T10:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	receipts, err := NewExchangeExportFacade(server.Client(), server.URL, "secret").ListExchanges(context.Background(), from, to)
+	assert.NoError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Equal(t, "EUR", receipts[0].ToCurrency)
+	assert.Equal(t, "90.00", receipts[0].ToAmount.String())
+	assert.Equal(t, float32(0.9), receipts[0].Rate)
+}
+
+func TestExchangeExportFacade_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewExchangeExportFacade(server.Client(), server.URL, "").ListExchanges(context.Background(), time.Now(), time.Now())
+	assert.ErrorIs(t, err, ErrExchangerUnavailable)
+}
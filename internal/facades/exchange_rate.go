@@ -2,30 +2,184 @@ package facades
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
 
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
 	pb "github.com/sbilibin2017/proto-exchange/exchange"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// Errors returned by the facade for well-known gRPC status codes.
+var (
+	ErrRateNotFound         = errors.New("exchange rate not found")
+	ErrExchangerUnavailable = errors.New("exchanger unavailable")
+	ErrExchangerTimeout     = errors.New("exchanger deadline exceeded")
+)
+
+// mapGRPCError converts a gRPC status error into a facade error, keeping the original message.
+// Errors with other status codes are returned unchanged.
+func mapGRPCError(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", ErrRateNotFound, status.Convert(err).Message())
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %s", ErrExchangerUnavailable, status.Convert(err).Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", ErrExchangerTimeout, status.Convert(err).Message())
+	default:
+		return err
+	}
+}
+
+// retryable reports whether a failed call may succeed when repeated.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
 // ExchangeRatesGRPCFacade implements currency exchange readers using gRPC.
 type ExchangeRatesGRPCFacade struct {
-	client pb.ExchangeServiceClient
+	client         pb.ExchangeServiceClient
+	callTimeout    time.Duration
+	attemptTimeout time.Duration
+	attempts       int
+	backoff        time.Duration
+	maxBackoff     time.Duration
+}
+
+// ExchangeRatesOpt defines a functional option for ExchangeRatesGRPCFacade.
+type ExchangeRatesOpt func(*ExchangeRatesGRPCFacade)
+
+// WithCallTimeout sets the deadline of calls made without a request deadline,
+// e.g. from background jobs. Calls inside an HTTP request use the remaining request budget.
+func WithCallTimeout(d time.Duration) ExchangeRatesOpt {
+	return func(f *ExchangeRatesGRPCFacade) {
+		f.callTimeout = d
+	}
+}
+
+// WithRetry retries calls failing with a transient status (unavailable, deadline
+// exceeded, resource exhausted, aborted) up to attempts times in total. Retries wait
+// an exponentially growing backoff, starting at backoff and capped at maxBackoff, with
+// jitter; a retry that would not fit into the call deadline is not made.
+func WithRetry(attempts int, backoff, maxBackoff time.Duration) ExchangeRatesOpt {
+	return func(f *ExchangeRatesGRPCFacade) {
+		f.attempts = attempts
+		f.backoff = backoff
+		f.maxBackoff = maxBackoff
+	}
+}
+
+// WithAttemptTimeout bounds every attempt of a call, so that a hung attempt leaves
+// budget for a retry. The call deadline still bounds all attempts together.
+func WithAttemptTimeout(d time.Duration) ExchangeRatesOpt {
+	return func(f *ExchangeRatesGRPCFacade) {
+		f.attemptTimeout = d
+	}
 }
 
 // NewExchangeRatesGRPCFacade creates a new facade with a gRPC client.
-func NewExchangeRatesGRPCFacade(client pb.ExchangeServiceClient) *ExchangeRatesGRPCFacade {
-	return &ExchangeRatesGRPCFacade{client: client}
+func NewExchangeRatesGRPCFacade(client pb.ExchangeServiceClient, opts ...ExchangeRatesOpt) *ExchangeRatesGRPCFacade {
+	f := &ExchangeRatesGRPCFacade{client: client}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Name identifies the gRPC exchanger among the rate providers.
+func (f *ExchangeRatesGRPCFacade) Name() string {
+	return "grpc"
+}
+
+// callContext returns the context for a gRPC call. If ctx already has a deadline
+// (the remaining request budget) it is used as is: gRPC sends it to the exchanger,
+// which stops working on the call once the client has given up. Otherwise the
+// call timeout applies. A spent budget fails fast without calling the exchanger.
+func (f *ExchangeRatesGRPCFacade) callContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if time.Until(deadline) <= 0 {
+			return ctx, nil, fmt.Errorf("%w: request budget exhausted", ErrExchangerTimeout)
+		}
+		return ctx, func() {}, nil
+	}
+	if f.callTimeout > 0 {
+		callCtx, cancel := context.WithTimeout(ctx, f.callTimeout)
+		return callCtx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
+
+// retry calls fn until it succeeds, fails permanently or runs out of attempts or budget.
+func (f *ExchangeRatesGRPCFacade) retry(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	backoff := f.backoff
+	for attempt := 1; ; attempt++ {
+		err := f.attempt(ctx, method, fn)
+		if err == nil || attempt >= f.attempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		// Full jitter on the upper half spreads the retries of concurrent callers
+		wait := backoff/2 + rand.N(backoff/2+1)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+			return err
+		}
+		logger.FromContext(ctx).Warnw("retrying exchanger gRPC call", "method", method, "attempt", attempt, "backoff", wait, "code", status.Code(err))
+		metrics.ExchangerRetries.WithLabelValues(method).Inc()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		backoff = min(2*backoff, f.maxBackoff)
+	}
+}
+
+// attempt calls fn once, within the attempt timeout if one is set, and observes its latency.
+func (f *ExchangeRatesGRPCFacade) attempt(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	if f.attemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.attemptTimeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := fn(ctx)
+	metrics.ExchangerCallDuration.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	return err
 }
 
 // GetExchangeRates fetches all exchange rates and returns them as map[string]float32
 func (f *ExchangeRatesGRPCFacade) GetExchangeRates(
 	ctx context.Context,
 ) (map[string]float32, error) {
-	resp, err := f.client.GetExchangeRates(ctx, &pb.Empty{})
+	ctx, cancel, err := f.callContext(ctx)
 	if err != nil {
-		logger.Log.Errorw("failed to fetch exchange rates via gRPC", "error", err)
+		logger.FromContext(ctx).Errorw("skipping exchange rates gRPC call", "error", err)
 		return nil, err
 	}
+	defer cancel()
+
+	var resp *pb.ExchangeRatesResponse
+	err = f.retry(ctx, "GetExchangeRates", func(ctx context.Context) (err error) {
+		resp, err = f.client.GetExchangeRates(ctx, &pb.Empty{})
+		return err
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to fetch exchange rates via gRPC", "code", status.Code(err), "error", err)
+		return nil, mapGRPCError(err)
+	}
 
 	rates := make(map[string]float32, len(resp.Rates))
 	for currency, rate := range resp.Rates {
@@ -42,12 +196,23 @@ func (f *ExchangeRatesGRPCFacade) GetExchangeRateForCurrency(ctx context.Context
 		ToCurrency:   toCurrency,
 	}
 
-	resp, err := f.client.GetExchangeRateForCurrency(ctx, req)
+	ctx, cancel, err := f.callContext(ctx)
 	if err != nil {
-		logger.Log.Errorw("failed to fetch exchange rate for currency via gRPC",
-			"from", fromCurrency, "to", toCurrency, "error", err)
+		logger.FromContext(ctx).Errorw("skipping exchange rate for currency gRPC call", "from", fromCurrency, "to", toCurrency, "error", err)
 		return 0, err
 	}
+	defer cancel()
+
+	var resp *pb.ExchangeRateResponse
+	err = f.retry(ctx, "GetExchangeRateForCurrency", func(ctx context.Context) (err error) {
+		resp, err = f.client.GetExchangeRateForCurrency(ctx, req)
+		return err
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to fetch exchange rate for currency via gRPC",
+			"from", fromCurrency, "to", toCurrency, "code", status.Code(err), "error", err)
+		return 0, mapGRPCError(err)
+	}
 
 	return resp.Rate, nil
 }
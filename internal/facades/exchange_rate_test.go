@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	pb "github.com/sbilibin2017/proto-exchange/exchange"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // --- Fake gRPC client ---
@@ -15,18 +18,31 @@ type fakeExchangeClient struct {
 	rates           map[string]float32
 	rateForCurrency float32
 	err             error
+	errs            []error // Errors of the first calls, err afterwards
+	calls           int
+	deadline        time.Time // Deadline of the last call's context
+	hasDeadline     bool
+}
+
+func (f *fakeExchangeClient) record(ctx context.Context) error {
+	f.calls++
+	f.deadline, f.hasDeadline = ctx.Deadline()
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return f.err
 }
 
 func (f *fakeExchangeClient) GetExchangeRates(ctx context.Context, _ *pb.Empty, opts ...grpc.CallOption) (*pb.ExchangeRatesResponse, error) {
-	if f.err != nil {
-		return nil, f.err
+	if err := f.record(ctx); err != nil {
+		return nil, err
 	}
 	return &pb.ExchangeRatesResponse{Rates: f.rates}, nil
 }
 
 func (f *fakeExchangeClient) GetExchangeRateForCurrency(ctx context.Context, req *pb.CurrencyRequest, opts ...grpc.CallOption) (*pb.ExchangeRateResponse, error) {
-	if f.err != nil {
-		return nil, f.err
+	if err := f.record(ctx); err != nil {
+		return nil, err
 	}
 	return &pb.ExchangeRateResponse{FromCurrency: req.FromCurrency, ToCurrency: req.ToCurrency, Rate: f.rateForCurrency}, nil
 }
@@ -72,3 +88,140 @@ func TestGetExchangeRateForCurrency_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, float32(0), rate)
 }
+
+func TestGetExchangeRateForCurrency_GRPCErrorMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "not found", err: status.Error(codes.NotFound, "pair not found"), wantErr: ErrRateNotFound},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "connection refused"), wantErr: ErrExchangerUnavailable},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "timeout"), wantErr: ErrExchangerTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facade := NewExchangeRatesGRPCFacade(&fakeExchangeClient{err: tt.err})
+
+			_, err := facade.GetExchangeRateForCurrency(context.Background(), "USD", "EUR")
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			_, err = facade.GetExchangeRates(context.Background())
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("other codes are passed through", func(t *testing.T) {
+		grpcErr := status.Error(codes.Internal, "boom")
+		facade := NewExchangeRatesGRPCFacade(&fakeExchangeClient{err: grpcErr})
+
+		_, err := facade.GetExchangeRateForCurrency(context.Background(), "USD", "EUR")
+		assert.Equal(t, grpcErr, err)
+	})
+}
+
+func TestCallDeadline(t *testing.T) {
+	t.Run("request budget is propagated", func(t *testing.T) {
+		client := &fakeExchangeClient{rateForCurrency: 1.2}
+		facade := NewExchangeRatesGRPCFacade(client, WithCallTimeout(time.Minute))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		want, _ := ctx.Deadline()
+
+		_, err := facade.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.NoError(t, err)
+		assert.True(t, client.hasDeadline)
+		assert.Equal(t, want, client.deadline)
+	})
+
+	t.Run("call timeout without request deadline", func(t *testing.T) {
+		client := &fakeExchangeClient{}
+		facade := NewExchangeRatesGRPCFacade(client, WithCallTimeout(3*time.Second))
+
+		start := time.Now()
+		_, err := facade.GetExchangeRates(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, client.hasDeadline)
+		assert.WithinDuration(t, start.Add(3*time.Second), client.deadline, time.Second)
+	})
+
+	t.Run("no deadline by default", func(t *testing.T) {
+		client := &fakeExchangeClient{}
+		facade := NewExchangeRatesGRPCFacade(client)
+
+		_, err := facade.GetExchangeRates(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, client.hasDeadline)
+	})
+
+	t.Run("spent budget skips the call", func(t *testing.T) {
+		client := &fakeExchangeClient{}
+		facade := NewExchangeRatesGRPCFacade(client, WithCallTimeout(time.Minute))
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, err := facade.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.ErrorIs(t, err, ErrExchangerTimeout)
+		_, err = facade.GetExchangeRates(ctx)
+		assert.ErrorIs(t, err, ErrExchangerTimeout)
+		assert.Equal(t, 0, client.calls)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	t.Run("transient failures are retried", func(t *testing.T) {
+		client := &fakeExchangeClient{rateForCurrency: 1.2, errs: []error{unavailable, status.Error(codes.DeadlineExceeded, "slow")}}
+		facade := NewExchangeRatesGRPCFacade(client, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+		rate, err := facade.GetExchangeRateForCurrency(context.Background(), "USD", "EUR")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(1.2), rate)
+		assert.Equal(t, 3, client.calls)
+	})
+
+	t.Run("attempts are limited", func(t *testing.T) {
+		client := &fakeExchangeClient{err: unavailable}
+		facade := NewExchangeRatesGRPCFacade(client, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+		_, err := facade.GetExchangeRates(context.Background())
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+		assert.Equal(t, 3, client.calls)
+	})
+
+	t.Run("permanent failures are not retried", func(t *testing.T) {
+		client := &fakeExchangeClient{err: status.Error(codes.NotFound, "pair not found")}
+		facade := NewExchangeRatesGRPCFacade(client, WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+		_, err := facade.GetExchangeRateForCurrency(context.Background(), "USD", "EUR")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+		assert.Equal(t, 1, client.calls)
+	})
+
+	t.Run("no retry past the call deadline", func(t *testing.T) {
+		client := &fakeExchangeClient{err: unavailable}
+		facade := NewExchangeRatesGRPCFacade(client, WithRetry(3, time.Minute, time.Minute))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := facade.GetExchangeRates(ctx)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+		assert.Equal(t, 1, client.calls)
+	})
+
+	t.Run("attempts are bounded by the attempt timeout", func(t *testing.T) {
+		client := &fakeExchangeClient{}
+		facade := NewExchangeRatesGRPCFacade(client, WithCallTimeout(time.Minute), WithAttemptTimeout(2*time.Second))
+
+		start := time.Now()
+		_, err := facade.GetExchangeRates(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, client.hasDeadline)
+		assert.WithinDuration(t, start.Add(2*time.Second), client.deadline, time.Second)
+	})
+}
@@ -0,0 +1,39 @@
+package facades
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// ExchangerHealthFacade checks that the gRPC connection to the exchanger is ready to carry calls.
+type ExchangerHealthFacade struct {
+	conn *grpc.ClientConn
+}
+
+// NewExchangerHealthFacade creates a new facade watching conn.
+func NewExchangerHealthFacade(conn *grpc.ClientConn) *ExchangerHealthFacade {
+	return &ExchangerHealthFacade{conn: conn}
+}
+
+// PingContext waits for the connection to become ready, connecting it if idle, and fails
+// with the last state if ctx is done first.
+func (f *ExchangerHealthFacade) PingContext(ctx context.Context) error {
+	for {
+		state := f.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			f.conn.Connect()
+		case connectivity.Shutdown:
+			return errors.New("exchanger: connection is shut down")
+		}
+		if !f.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("exchanger: connection %s: %w", state, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package facades
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestExchangerHealthFacade_PingContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	go server.Serve(ln)
+	defer server.Stop()
+
+	t.Run("ready", func(t *testing.T) {
+		conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assert.NoError(t, NewExchangerHealthFacade(conn).PingContext(ctx))
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		// A port nobody listens on
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := closed.Addr().String()
+		closed.Close()
+
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		assert.Error(t, NewExchangerHealthFacade(conn).PingContext(ctx))
+	})
+
+	t.Run("shut_down", func(t *testing.T) {
+		conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		assert.EqualError(t, NewExchangerHealthFacade(conn).PingContext(context.Background()), "exchanger: connection is shut down")
+	})
+}
@@ -0,0 +1,116 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// HTTPRatesFacade reads exchange rates from an HTTP API in the openexchangerates.org format:
+// GET {baseURL}/latest.json?app_id={appID} returns {"base": "USD", "rates": {"EUR": 0.92, ...}}.
+// Rates of pairs are crossed through the base currency.
+type HTTPRatesFacade struct {
+	client  *http.Client
+	baseURL string
+	appID   string
+}
+
+// NewHTTPRatesFacade creates a new facade calling baseURL with client.
+func NewHTTPRatesFacade(client *http.Client, baseURL, appID string) *HTTPRatesFacade {
+	return &HTTPRatesFacade{client: client, baseURL: baseURL, appID: appID}
+}
+
+// Name identifies the HTTP API among the rate providers.
+func (f *HTTPRatesFacade) Name() string {
+	return "http"
+}
+
+// latestRates is the response of the latest rates endpoint.
+type latestRates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float32 `json:"rates"`
+}
+
+// GetExchangeRates fetches the rates of all currencies against the base currency.
+func (f *HTTPRatesFacade) GetExchangeRates(ctx context.Context) (map[string]float32, error) {
+	latest, err := f.latest(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to fetch exchange rates via HTTP", "error", err)
+		return nil, err
+	}
+	return latest.Rates, nil
+}
+
+// GetExchangeRateForCurrency fetches the rate of a currency pair, crossed through the base currency.
+func (f *HTTPRatesFacade) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error) {
+	latest, err := f.latest(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to fetch exchange rate for currency via HTTP", "from", fromCurrency, "to", toCurrency, "error", err)
+		return 0, err
+	}
+
+	from, ok := latest.Rates[fromCurrency]
+	if !ok || from <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrRateNotFound, fromCurrency)
+	}
+	to, ok := latest.Rates[toCurrency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrRateNotFound, toCurrency)
+	}
+	return to / from, nil
+}
+
+// latest fetches the latest rates, mapping transport failures and server errors to
+// ErrExchangerUnavailable and ErrExchangerTimeout.
+func (f *HTTPRatesFacade) latest(ctx context.Context) (latestRates, error) {
+	u := f.baseURL + "/latest.json"
+	if f.appID != "" {
+		u += "?app_id=" + url.QueryEscape(f.appID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return latestRates{}, err
+	}
+
+	resp, err := doHTTP(f.client, req, "rates API")
+	if err != nil {
+		return latestRates{}, err
+	}
+	defer resp.Body.Close()
+
+	var latest latestRates
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return latestRates{}, fmt.Errorf("decode rates: %w", err)
+	}
+	return latest, nil
+}
+
+// doHTTP sends req, mapping transport failures, server errors and throttling to
+// ErrExchangerUnavailable and ErrExchangerTimeout. Any other status but 200 is a plain
+// error naming api. The caller closes the body of the returned response.
+func doHTTP(client *http.Client, req *http.Request, api string) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, fmt.Errorf("%w: %v", ErrExchangerTimeout, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrExchangerUnavailable, err)
+	}
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: HTTP %d", ErrExchangerUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned HTTP %d", api, resp.StatusCode)
+	}
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package facades
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRatesFacade(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/latest.json", r.URL.Path)
+		assert.Equal(t, "key", r.URL.Query().Get("app_id"))
+		w.Write([]byte(`{"base": "USD", "rates": {"USD": 1, "EUR": 0.5, "RUB": 90}}`))
+	}))
+	defer server.Close()
+
+	facade := NewHTTPRatesFacade(server.Client(), server.URL, "key")
+	assert.Equal(t, "http", facade.Name())
+
+	t.Run("all rates", func(t *testing.T) {
+		rates, err := facade.GetExchangeRates(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]float32{"USD": 1, "EUR": 0.5, "RUB": 90}, rates)
+	})
+
+	t.Run("rate crossed through the base", func(t *testing.T) {
+		rate, err := facade.GetExchangeRateForCurrency(context.Background(), "EUR", "RUB")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(180), rate)
+	})
+
+	t.Run("missing currency", func(t *testing.T) {
+		_, err := facade.GetExchangeRateForCurrency(context.Background(), "USD", "GBP")
+		assert.ErrorIs(t, err, ErrRateNotFound)
+	})
+}
+
+func TestHTTPRatesFacade_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "server error", status: http.StatusBadGateway, wantErr: ErrExchangerUnavailable},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantErr: ErrExchangerUnavailable},
+		{name: "unauthorized", status: http.StatusUnauthorized},
+		{name: "malformed body", status: http.StatusOK, body: "{"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewHTTPRatesFacade(server.Client(), server.URL, "").GetExchangeRates(context.Background())
+			assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NotErrorIs(t, err, ErrExchangerUnavailable)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := NewHTTPRatesFacade(http.DefaultClient, server.URL, "").GetExchangeRates(context.Background())
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := NewHTTPRatesFacade(server.Client(), server.URL, "").GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.ErrorIs(t, err, ErrExchangerTimeout)
+	})
+}
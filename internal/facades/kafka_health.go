@@ -0,0 +1,37 @@
+package facades
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaHealthFacade checks that the Kafka cluster answers a metadata request, which is the
+// first request of every write and read.
+type KafkaHealthFacade struct {
+	client *kafka.Client
+}
+
+// NewKafkaHealthFacade creates a new facade asking brokers for metadata through the SASL and
+// TLS settings of dialer. A check gives up after timeout.
+func NewKafkaHealthFacade(brokers []string, dialer *kafka.Dialer, timeout time.Duration) *KafkaHealthFacade {
+	return &KafkaHealthFacade{client: &kafka.Client{
+		Addr:      kafka.TCP(brokers...),
+		Timeout:   timeout,
+		Transport: &kafka.Transport{SASL: dialer.SASLMechanism, TLS: dialer.TLS, DialTimeout: timeout},
+	}}
+}
+
+// PingContext requests the list of brokers, without topics, and fails if it is empty.
+func (f *KafkaHealthFacade) PingContext(ctx context.Context) error {
+	resp, err := f.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{}})
+	if err != nil {
+		return err
+	}
+	if len(resp.Brokers) == 0 {
+		return errors.New("kafka: metadata lists no brokers")
+	}
+	return nil
+}
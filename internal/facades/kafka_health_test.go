@@ -0,0 +1,24 @@
+package facades
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaHealthFacade_PingContext(t *testing.T) {
+	// A port nobody listens on
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	f := NewKafkaHealthFacade([]string{addr}, &kafka.Dialer{}, time.Second)
+	assert.Error(t, f.PingContext(context.Background()))
+}
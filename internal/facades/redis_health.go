@@ -0,0 +1,22 @@
+package facades
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHealthFacade checks that Redis answers a PING.
+type RedisHealthFacade struct {
+	client *redis.Client
+}
+
+// NewRedisHealthFacade creates a new facade pinging client.
+func NewRedisHealthFacade(client *redis.Client) *RedisHealthFacade {
+	return &RedisHealthFacade{client: client}
+}
+
+// PingContext sends a PING and returns its error.
+func (f *RedisHealthFacade) PingContext(ctx context.Context) error {
+	return f.client.Ping(ctx).Err()
+}
@@ -0,0 +1,84 @@
+package facades
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// schemaRegistryContentType is the media type of the Confluent Schema Registry API.
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// SchemaRegistryFacade registers schemas with a Confluent compatible schema registry:
+// POST {baseURL}/subjects/{subject}/versions with {"schemaType": ..., "schema": ...} returns
+// {"id": ...}. Registering a schema that is already registered returns its existing ID, and
+// a schema incompatible with the subject's compatibility policy is rejected.
+type SchemaRegistryFacade struct {
+	client   *http.Client
+	baseURL  string
+	username string
+	password string
+}
+
+// NewSchemaRegistryFacade creates a new facade calling baseURL with client.
+// A non-empty username is sent with password as basic auth.
+func NewSchemaRegistryFacade(client *http.Client, baseURL, username, password string) *SchemaRegistryFacade {
+	return &SchemaRegistryFacade{client: client, baseURL: baseURL, username: username, password: password}
+}
+
+// registerSchemaRequest is the body of a schema registration.
+type registerSchemaRequest struct {
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+// registerSchemaResponse is the response of a schema registration.
+type registerSchemaResponse struct {
+	ID int `json:"id"`
+}
+
+// Register registers the schema of schemaType (AVRO, PROTOBUF or JSON) under subject and
+// returns its ID.
+func (f *SchemaRegistryFacade) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	body, err := json.Marshal(registerSchemaRequest{SchemaType: schemaType, Schema: schema})
+	if err != nil {
+		return 0, err
+	}
+	endpoint := f.baseURL + "/subjects/" + url.PathEscape(subject) + "/versions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", schemaRegistryContentType)
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if f.username != "" {
+		req.SetBasicAuth(f.username, f.password)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to register schema", "subject", subject, "error", err)
+		return 0, fmt.Errorf("schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// The registry explains rejections, e.g. incompatible schemas, in the body
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		logger.FromContext(ctx).Errorw("schema registry rejected schema", "subject", subject, "status", resp.StatusCode, "response", string(msg))
+		return 0, fmt.Errorf("schema registry returned HTTP %d: %s", resp.StatusCode, msg)
+	}
+
+	var registered registerSchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&registered); err != nil {
+		return 0, fmt.Errorf("decode schema registry response: %w", err)
+	}
+	logger.FromContext(ctx).Infow("schema registered", "subject", subject, "schema_id", registered.ID)
+	return registered.ID, nil
+}
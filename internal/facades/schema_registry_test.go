@@ -0,0 +1,61 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRegistryFacade_Register(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/subjects/wallet.transactions-value/versions", r.URL.Path)
+		assert.Equal(t, "application/vnd.schemaregistry.v1+json", r.Header.Get("Content-Type"))
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "wallet", username)
+		assert.Equal(t, "secret", password)
+
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "AVRO", body["schemaType"])
+		assert.Equal(t, `{"type":"string"}`, body["schema"])
+		w.Write([]byte(`{"id": 42}`))
+	}))
+	defer server.Close()
+
+	id, err := NewSchemaRegistryFacade(server.Client(), server.URL, "wallet", "secret").
+		Register(context.Background(), "wallet.transactions-value", "AVRO", `{"type":"string"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func TestSchemaRegistryFacade_Register_Errors(t *testing.T) {
+	t.Run("incompatible schema", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, ok := r.BasicAuth()
+			assert.False(t, ok)
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_code": 409, "message": "Schema being registered is incompatible"}`))
+		}))
+		defer server.Close()
+
+		_, err := NewSchemaRegistryFacade(server.Client(), server.URL, "", "").
+			Register(context.Background(), "wallet.transactions-value", "AVRO", `{"type":"string"}`)
+		assert.ErrorContains(t, err, "HTTP 409")
+		assert.ErrorContains(t, err, "incompatible")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := NewSchemaRegistryFacade(server.Client(), server.URL, "", "").
+			Register(context.Background(), "wallet.transactions-value", "AVRO", `{"type":"string"}`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,203 @@
+package facades
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// Vault auth methods.
+const (
+	VaultAuthToken      = "token"      // A token given in the configuration
+	VaultAuthKubernetes = "kubernetes" // A token obtained with the service account token of the pod
+)
+
+// VaultConfig configures VaultFacade.
+type VaultConfig struct {
+	Addr                string // e.g. https://vault:8200
+	Auth                string // VaultAuthToken or VaultAuthKubernetes
+	Token               string // For VaultAuthToken
+	KubernetesRole      string // For VaultAuthKubernetes
+	KubernetesMount     string // Mount of the Kubernetes auth method
+	KubernetesTokenFile string // Service account token of the pod
+	KVMount             string // Mount of the KV version 2 secrets engine
+	SecretPath          string // Path of the secret within KVMount
+}
+
+// VaultFacade reads a secret of the KV version 2 secrets engine of HashiCorp Vault:
+// GET {addr}/v1/{mount}/data/{path} with the X-Vault-Token header returns
+// {"data": {"data": {...}}}. With Kubernetes auth the token is obtained by logging in:
+// POST {addr}/v1/auth/{mount}/login with {"role": ..., "jwt": ...} returns
+// {"auth": {"client_token": ...}}. Errors are returned as {"errors": [...]}.
+type VaultFacade struct {
+	client *http.Client
+	cfg    VaultConfig
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewVaultFacade creates a new facade calling Vault with client.
+func NewVaultFacade(client *http.Client, cfg VaultConfig) *VaultFacade {
+	cfg.Addr = strings.TrimRight(cfg.Addr, "/")
+	f := &VaultFacade{client: client, cfg: cfg}
+	if cfg.Auth != VaultAuthKubernetes {
+		f.token = cfg.Token
+	}
+	return f
+}
+
+// vaultSecretResponse is the response of a KV version 2 read.
+type vaultSecretResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+// vaultLoginRequest is the body of a Kubernetes login.
+type vaultLoginRequest struct {
+	Role string `json:"role"`
+	JWT  string `json:"jwt"`
+}
+
+// vaultAuthResponse is the response of a login or token renewal.
+type vaultAuthResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+// vaultErrorResponse is the body of an error response.
+type vaultErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// Secrets returns the string values of the secret by key, logging in first without a token.
+func (f *VaultFacade) Secrets(ctx context.Context) (map[string]string, error) {
+	token, err := f.currentToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret vaultSecretResponse
+	if err := f.call(ctx, http.MethodGet, "/v1/"+f.cfg.KVMount+"/data/"+f.cfg.SecretPath, token, nil, &secret); err != nil {
+		logger.FromContext(ctx).Errorw("failed to read vault secret", "path", f.cfg.SecretPath, "error", err)
+		return nil, err
+	}
+	values := make(map[string]string, len(secret.Data.Data))
+	for key, value := range secret.Data.Data {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	return values, nil
+}
+
+// Renew extends the lease of the token. A Kubernetes token that can no longer be renewed is
+// replaced by logging in again.
+func (f *VaultFacade) Renew(ctx context.Context) error {
+	token, err := f.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var renewed vaultAuthResponse
+	err = f.call(ctx, http.MethodPost, "/v1/auth/token/renew-self", token, struct{}{}, &renewed)
+	if err == nil {
+		logger.FromContext(ctx).Debugw("vault token renewed", "lease_duration", renewed.Auth.LeaseDuration)
+		return nil
+	}
+	if f.cfg.Auth != VaultAuthKubernetes {
+		return err
+	}
+	logger.FromContext(ctx).Warnw("failed to renew vault token, logging in again", "error", err)
+	_, err = f.login(ctx)
+	return err
+}
+
+// currentToken returns the token, logging in if there is none.
+func (f *VaultFacade) currentToken(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	token := f.token
+	f.mu.Unlock()
+	if token != "" {
+		return token, nil
+	}
+	return f.login(ctx)
+}
+
+// login obtains a token with the service account token of the pod.
+func (f *VaultFacade) login(ctx context.Context) (string, error) {
+	if f.cfg.Auth != VaultAuthKubernetes {
+		return "", errors.New("vault: no token")
+	}
+	jwt, err := os.ReadFile(f.cfg.KubernetesTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("vault: read service account token: %w", err)
+	}
+
+	var auth vaultAuthResponse
+	body := vaultLoginRequest{Role: f.cfg.KubernetesRole, JWT: strings.TrimSpace(string(jwt))}
+	if err := f.call(ctx, http.MethodPost, "/v1/auth/"+f.cfg.KubernetesMount+"/login", "", body, &auth); err != nil {
+		logger.FromContext(ctx).Errorw("failed to log in to vault", "role", f.cfg.KubernetesRole, "error", err)
+		return "", err
+	}
+	if auth.Auth.ClientToken == "" {
+		return "", errors.New("vault: login returned no token")
+	}
+
+	f.mu.Lock()
+	f.token = auth.Auth.ClientToken
+	f.mu.Unlock()
+	logger.FromContext(ctx).Infow("logged in to vault", "role", f.cfg.KubernetesRole, "lease_duration", auth.Auth.LeaseDuration)
+	return auth.Auth.ClientToken, nil
+}
+
+// call sends body, if any, as JSON to path and decodes the response into out.
+func (f *VaultFacade) call(ctx context.Context, method, path, token string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, f.cfg.Addr+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var vaultErr vaultErrorResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&vaultErr)
+		return fmt.Errorf("vault returned HTTP %d for %s: %s", resp.StatusCode, path, strings.Join(vaultErr.Errors, "; "))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode vault response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVault serves a KV v2 secret to the token it issues on Kubernetes login or to a static token.
+type fakeVault struct {
+	token       string // Accepted token
+	logins      int
+	renewals    int
+	renewFailed bool
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/kubernetes/login":
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["role"] != "wallet" || body["jwt"] != "sa-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": ["invalid role or service account"]}`))
+			return
+		}
+		v.logins++
+		v.token = "k8s-token"
+		w.Write([]byte(`{"auth": {"client_token": "k8s-token", "lease_duration": 3600, "renewable": true}}`))
+	case r.Header.Get("X-Vault-Token") != v.token:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": ["permission denied"]}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/token/renew-self":
+		v.renewals++
+		if v.renewFailed {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": ["lease is not renewable"]}`))
+			return
+		}
+		w.Write([]byte(`{"auth": {"client_token": "` + v.token + `", "lease_duration": 3600}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/secret/data/gw-currency-wallet":
+		w.Write([]byte(`{"data": {"data": {"jwt_secret_key": "jwt", "postgres_password": "pg", "port": 5432}, "metadata": {"version": 3}}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": []}`))
+	}
+}
+
+func TestVaultFacade(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("sa-token\n"), 0o600))
+	ctx := context.Background()
+
+	t.Run("token auth", func(t *testing.T) {
+		vault := &fakeVault{token: "static-token"}
+		server := httptest.NewServer(vault)
+		defer server.Close()
+
+		f := NewVaultFacade(server.Client(), VaultConfig{Addr: server.URL + "/", Auth: VaultAuthToken, Token: "static-token", KVMount: "secret", SecretPath: "gw-currency-wallet"})
+		values, err := f.Secrets(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"jwt_secret_key": "jwt", "postgres_password": "pg"}, values)
+
+		assert.NoError(t, f.Renew(ctx))
+		vault.renewFailed = true
+		assert.ErrorContains(t, f.Renew(ctx), "lease is not renewable")
+		assert.Equal(t, 2, vault.renewals)
+	})
+
+	t.Run("kubernetes auth", func(t *testing.T) {
+		vault := &fakeVault{}
+		server := httptest.NewServer(vault)
+		defer server.Close()
+
+		f := NewVaultFacade(server.Client(), VaultConfig{
+			Addr: server.URL, Auth: VaultAuthKubernetes, KubernetesRole: "wallet", KubernetesMount: "kubernetes",
+			KubernetesTokenFile: tokenFile, KVMount: "secret", SecretPath: "gw-currency-wallet",
+		})
+		values, err := f.Secrets(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "pg", values["postgres_password"])
+		assert.Equal(t, 1, vault.logins)
+
+		// A token that can no longer be renewed is replaced
+		vault.renewFailed = true
+		assert.NoError(t, f.Renew(ctx))
+		assert.Equal(t, 2, vault.logins)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		server := httptest.NewServer(&fakeVault{token: "static-token"})
+		defer server.Close()
+
+		_, err := NewVaultFacade(server.Client(), VaultConfig{Addr: server.URL, Auth: VaultAuthToken, Token: "wrong", KVMount: "secret", SecretPath: "gw-currency-wallet"}).Secrets(ctx)
+		assert.ErrorContains(t, err, "HTTP 403")
+		assert.ErrorContains(t, err, "permission denied")
+
+		_, err = NewVaultFacade(server.Client(), VaultConfig{
+			Addr: server.URL, Auth: VaultAuthKubernetes, KubernetesRole: "other", KubernetesMount: "kubernetes",
+			KubernetesTokenFile: tokenFile, KVMount: "secret", SecretPath: "gw-currency-wallet",
+		}).Secrets(ctx)
+		assert.ErrorContains(t, err, "invalid role or service account")
+
+		_, err = NewVaultFacade(server.Client(), VaultConfig{
+			Addr: server.URL, Auth: VaultAuthKubernetes, KubernetesTokenFile: filepath.Join(t.TempDir(), "missing"),
+		}).Secrets(ctx)
+		assert.ErrorContains(t, err, "read service account token")
+	})
+}
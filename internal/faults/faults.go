@@ -0,0 +1,54 @@
+// Package faults injects latency and errors into calls to dependencies,
+// to exercise timeouts, fallbacks and circuit breakers in staging.
+package faults
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// ErrInjected is returned by calls failed on purpose.
+var ErrInjected = errors.New("injected fault")
+
+// Injector delays calls and fails a share of them.
+// A nil *Injector injects nothing.
+type Injector struct {
+	target    string
+	latency   time.Duration
+	errorRate float64
+	random    func() float64
+}
+
+// NewInjector creates an injector for target (e.g. "postgres") that delays every call
+// by latency and fails errorRate (0..1) of them.
+func NewInjector(target string, latency time.Duration, errorRate float64) *Injector {
+	return &Injector{target: target, latency: latency, errorRate: errorRate, random: rand.Float64}
+}
+
+// Inject delays the call and decides whether it fails. It returns ErrInjected for
+// a failed call and the context error if ctx is done while waiting.
+func (i *Injector) Inject(ctx context.Context) error {
+	if i == nil {
+		return nil
+	}
+
+	if i.latency > 0 {
+		timer := time.NewTimer(i.latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if i.errorRate > 0 && i.random() < i.errorRate {
+		logger.FromContext(ctx).Debugw("injecting fault", "target", i.target)
+		return ErrInjected
+	}
+	return nil
+}
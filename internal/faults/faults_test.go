@@ -0,0 +1,45 @@
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixed returns an injector whose random draws always return r.
+func fixed(latency time.Duration, errorRate, r float64) *Injector {
+	i := NewInjector("test", latency, errorRate)
+	i.random = func() float64 { return r }
+	return i
+}
+
+func TestInjector_Inject(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil injector", func(t *testing.T) {
+		var i *Injector
+		assert.NoError(t, i.Inject(ctx))
+	})
+
+	t.Run("error rate", func(t *testing.T) {
+		assert.ErrorIs(t, fixed(0, 0.3, 0.1).Inject(ctx), ErrInjected)
+		assert.NoError(t, fixed(0, 0.3, 0.5).Inject(ctx))
+		assert.NoError(t, fixed(0, 0, 0).Inject(ctx))
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, fixed(50*time.Millisecond, 0, 0).Inject(ctx))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("latency respects context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		err := fixed(time.Minute, 0, 0).Inject(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
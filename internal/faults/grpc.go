@@ -0,0 +1,24 @@
+package faults
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor returns a gRPC client interceptor injecting faults into every unary call.
+// Injected errors are reported as codes.Unavailable, the way a failing server would be.
+func UnaryClientInterceptor(injector *Injector) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := injector.Inject(ctx); err != nil {
+			if errors.Is(err, ErrInjected) {
+				return status.Error(codes.Unavailable, err.Error())
+			}
+			return status.FromContextError(err).Err()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,44 @@
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		return nil
+	}
+
+	t.Run("passes through", func(t *testing.T) {
+		interceptor := UnaryClientInterceptor(fixed(0, 0.5, 0.9))
+		err := interceptor(context.Background(), "/exchange.ExchangeService/GetExchangeRates", nil, nil, nil, invoker)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("injected error is unavailable", func(t *testing.T) {
+		interceptor := UnaryClientInterceptor(fixed(0, 0.5, 0.1))
+		err := interceptor(context.Background(), "/exchange.ExchangeService/GetExchangeRates", nil, nil, nil, invoker)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("latency beyond deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		interceptor := UnaryClientInterceptor(fixed(time.Minute, 0, 0))
+		err := interceptor(ctx, "/exchange.ExchangeService/GetExchangeRates", nil, nil, nil, invoker)
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+}
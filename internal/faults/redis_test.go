@@ -0,0 +1,33 @@
+package faults
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisHook(t *testing.T) {
+	// Nothing listens on this address: commands that pass the hook fail to dial.
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+
+	injector := fixed(0, 0.5, 0.1)
+	rdb.AddHook(RedisHook(injector))
+	ctx := context.Background()
+
+	err := rdb.Get(ctx, "key").Err()
+	assert.ErrorIs(t, err, ErrInjected)
+
+	pipe := rdb.Pipeline()
+	get := pipe.Get(ctx, "key")
+	_, err = pipe.Exec(ctx)
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.ErrorIs(t, get.Err(), ErrInjected)
+
+	injector.random = func() float64 { return 0.9 }
+	err = rdb.Get(ctx, "key").Err()
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInjected)
+}
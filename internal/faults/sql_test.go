@@ -0,0 +1,44 @@
+package faults
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnector(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("faults-test")
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	injector := fixed(0, 0.5, 0.9)
+	db := sql.OpenDB(Connector(mockDB.Driver(), "faults-test", injector))
+	defer db.Close()
+	ctx := context.Background()
+
+	mock.ExpectExec("UPDATE wallets").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = db.ExecContext(ctx, "UPDATE wallets SET balance = 0")
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT balance").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(1.5))
+	var balance float64
+	assert.NoError(t, db.QueryRowContext(ctx, "SELECT balance FROM wallets").Scan(&balance))
+	assert.Equal(t, 1.5, balance)
+
+	// Every call fails from now on
+	injector.random = func() float64 { return 0.1 }
+
+	_, err = db.ExecContext(ctx, "UPDATE wallets SET balance = 0")
+	assert.ErrorIs(t, err, ErrInjected)
+
+	err = db.QueryRowContext(ctx, "SELECT balance FROM wallets").Scan(&balance)
+	assert.ErrorIs(t, err, ErrInjected)
+
+	_, err = db.BeginTx(ctx, nil)
+	assert.ErrorIs(t, err, ErrInjected)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
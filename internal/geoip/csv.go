@@ -0,0 +1,77 @@
+package geoip
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// network maps an IP prefix to an ISO country code.
+type network struct {
+	prefix  netip.Prefix
+	country string
+}
+
+// CSVLocator resolves countries from a table of "network,country" rows,
+// e.g. "203.0.113.0/24,NL". Lines starting with # are ignored.
+type CSVLocator struct {
+	networks []network
+}
+
+// NewCSVLocator loads the lookup table from the file at path.
+func NewCSVLocator(path string) (*CSVLocator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseCSV(f)
+}
+
+// ParseCSV reads the lookup table from r.
+func ParseCSV(r io.Reader) (*CSVLocator, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	locator := &CSVLocator{networks: make([]network, 0, len(records))}
+	for i, record := range records {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("geoip: line %d: %w", i+1, err)
+		}
+		locator.networks = append(locator.networks, network{
+			prefix:  prefix.Masked(),
+			country: strings.ToUpper(strings.TrimSpace(record[1])),
+		})
+	}
+	return locator, nil
+}
+
+// Country returns the country code of the most specific network containing ip.
+// Returns an empty string for unknown or unparsable addresses.
+func (l *CSVLocator) Country(ctx context.Context, ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	country, bits := "", -1
+	for _, n := range l.networks {
+		if n.prefix.Bits() > bits && n.prefix.Contains(addr) {
+			country, bits = n.country, n.prefix.Bits()
+		}
+	}
+	return country
+}
@@ -0,0 +1,58 @@
+package geoip
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVLocator_Country(t *testing.T) {
+	locator, err := ParseCSV(strings.NewReader(`# network,country
+203.0.0.0/8,au
+203.0.113.0/24,NL
+2001:db8::/32,DE
+`))
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "203.0.113.7", want: "NL"},
+		{ip: "203.1.2.3", want: "AU"},
+		{ip: "::ffff:203.0.113.7", want: "NL"},
+		{ip: "2001:db8::1", want: "DE"},
+		{ip: "198.51.100.1", want: ""},
+		{ip: "not-an-ip", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, locator.Country(ctx, tt.ip))
+		})
+	}
+}
+
+func TestParseCSV_Invalid(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("not-a-network,NL\n"))
+	assert.Error(t, err)
+
+	_, err = ParseCSV(strings.NewReader("203.0.113.0/24\n"))
+	assert.Error(t, err)
+}
+
+func TestNewCSVLocator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("203.0.113.0/24,NL\n"), 0o600))
+
+	locator, err := NewCSVLocator(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "NL", locator.Country(context.Background(), "203.0.113.7"))
+
+	_, err = NewCSVLocator(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,543 @@
+// Package graphql executes GraphQL requests against a Schema of resolvers.
+//
+// It serves the subset of the language the wallet API needs: queries and mutations with
+// variables, aliases, fragments and the @include and @skip directives. Subscriptions and
+// introspection other than __typename are not supported.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Error codes of requests that cannot be executed, in the extensions of their errors.
+const (
+	CodeParseFailed      = "graphql_parse_failed"
+	CodeValidationFailed = "graphql_validation_failed"
+)
+
+// Resolver resolves a field from its coerced arguments. Objects are returned as
+// map[string]any, lists as slices and scalars as JSON values.
+type Resolver func(ctx context.Context, args map[string]any) (any, error)
+
+// Field is a field of an object type.
+type Field struct {
+	// Type of the field, e.g. [Balance!]!. Names of the schema's objects are object types,
+	// any other name is a scalar: String, ID, Int, Float and Boolean are checked,
+	// custom scalars are passed through.
+	Type string
+
+	// Types of the arguments by name, e.g. {"amount": "Decimal!"}
+	Args map[string]string
+
+	// Resolve resolves the field; fields without one are read from the map their parent
+	// resolved to.
+	Resolve Resolver
+}
+
+// Object is the fields of an object type by name.
+type Object map[string]Field
+
+// Schema is a GraphQL API: its object types, with the Query and Mutation root types.
+type Schema struct {
+	Types map[string]Object
+
+	// FormatError converts an error returned by a resolver. If nil, its text is the message.
+	FormatError func(err error) Error
+}
+
+// Request is a GraphQL request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the result of a request. Data is nil if the request could not be executed.
+type Response struct {
+	Data   any     `json:"data,omitempty"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error is an error of a response.
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Error implements error.
+func (e Error) Error() string {
+	return e.Message
+}
+
+// requestError returns the response of a request that cannot be executed.
+func requestError(code, format string, args ...any) Response {
+	return Response{Errors: []Error{{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": code},
+	}}}
+}
+
+// Execute parses, validates and executes req. Root fields are resolved one at a time, in order.
+func (s *Schema) Execute(ctx context.Context, req Request) Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return requestError(CodeParseFailed, "%s", err)
+	}
+
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return requestError(CodeValidationFailed, "%s", err)
+	}
+	root := map[string]string{"query": "Query", "mutation": "Mutation"}[op.kind]
+	if _, ok := s.Types[root]; !ok {
+		return requestError(CodeValidationFailed, "%s operations are not supported", op.kind)
+	}
+
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return requestError(CodeValidationFailed, "%s", err)
+	}
+	v := &validator{schema: s, doc: doc, declared: map[string]bool{}, visiting: map[string]bool{}}
+	for _, def := range op.variables {
+		v.declared[def.name] = true
+	}
+	v.selections(root, op.selections)
+	if v.err != nil {
+		return requestError(CodeValidationFailed, "%s", v.err)
+	}
+
+	e := &executor{schema: s, doc: doc, vars: vars}
+	data := e.selections(ctx, root, nil, op.selections, nil)
+	return Response{Data: data, Errors: e.errors}
+}
+
+// operation returns the operation named name, or the only operation if name is empty.
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required for documents with several operations")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// coerceVariables returns the values of the variables of op, with defaults applied.
+func coerceVariables(op *operation, values map[string]any) (map[string]any, error) {
+	vars := make(map[string]any, len(op.variables))
+	for _, def := range op.variables {
+		v, ok := values[def.name]
+		if !ok && def.defaultVal != nil {
+			v, ok = def.defaultVal.resolve(nil), true
+		}
+		if !ok {
+			if strings.HasSuffix(def.typ, "!") {
+				return nil, fmt.Errorf("variable $%s of required type %s was not provided", def.name, def.typ)
+			}
+			continue
+		}
+		coerced, err := coerce(def.typ, v)
+		if err != nil {
+			return nil, fmt.Errorf("variable $%s: %w", def.name, err)
+		}
+		vars[def.name] = coerced
+	}
+	return vars, nil
+}
+
+// coerce converts an input value to typ.
+func coerce(typ string, v any) (any, error) {
+	nonNull := strings.HasSuffix(typ, "!")
+	typ = strings.TrimSuffix(typ, "!")
+	if v == nil {
+		if nonNull {
+			return nil, fmt.Errorf("expected a non-null %s", typ)
+		}
+		return nil, nil
+	}
+
+	if strings.HasPrefix(typ, "[") {
+		item := typ[1 : len(typ)-1]
+		list, ok := v.([]any)
+		if !ok {
+			list = []any{v}
+		}
+		out := make([]any, len(list))
+		for i, value := range list {
+			coerced, err := coerce(item, value)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	}
+
+	switch typ {
+	case "Int":
+		if n, ok := integer(v); ok && n >= math.MinInt32 && n <= math.MaxInt32 {
+			return n, nil
+		}
+	case "Float":
+		switch n := v.(type) {
+		case int:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+	case "String":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case "ID":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		if n, ok := integer(v); ok {
+			return strconv.Itoa(n), nil
+		}
+	case "Boolean":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	default:
+		return v, nil
+	}
+	return nil, fmt.Errorf("expected %s, got %s", typ, describe(v))
+}
+
+// integer returns v as an int if it is an integral number.
+func integer(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) <= 1<<53 {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// describe returns v as it appears in error messages.
+func describe(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// namedType returns the name of typ without list and non-null wrappers.
+func namedType(typ string) string {
+	return strings.Trim(typ, "[]!")
+}
+
+// validator checks the selections of an operation against a schema.
+type validator struct {
+	schema   *Schema
+	doc      *document
+	declared map[string]bool // Variables of the operation
+	visiting map[string]bool // Fragments being validated, to reject cycles
+	err      error
+}
+
+// fail records the first validation error.
+func (v *validator) fail(format string, args ...any) {
+	if v.err == nil {
+		v.err = fmt.Errorf(format, args...)
+	}
+}
+
+// selections validates sels against the object type typeName.
+func (v *validator) selections(typeName string, sels []selection) {
+	for _, sel := range sels {
+		v.directives(sel.directives)
+		switch {
+		case sel.spread != "":
+			frag, ok := v.doc.fragments[sel.spread]
+			switch {
+			case !ok:
+				v.fail("unknown fragment %q", sel.spread)
+			case v.visiting[sel.spread]:
+				v.fail("fragment %q spreads itself", sel.spread)
+			case frag.typeCondition != typeName:
+				v.fail("fragment %q on %s cannot be spread on %s", sel.spread, frag.typeCondition, typeName)
+			default:
+				v.visiting[sel.spread] = true
+				v.selections(typeName, frag.selections)
+				delete(v.visiting, sel.spread)
+			}
+		case sel.inline:
+			if sel.typeCondition != "" && sel.typeCondition != typeName {
+				v.fail("fragment on %s cannot be spread on %s", sel.typeCondition, typeName)
+			}
+			v.selections(typeName, sel.selections)
+		default:
+			v.field(typeName, sel)
+		}
+	}
+}
+
+// field validates a field selection of the object type typeName.
+func (v *validator) field(typeName string, sel selection) {
+	if sel.name == "__typename" {
+		if len(sel.arguments) > 0 || len(sel.selections) > 0 {
+			v.fail("field __typename takes no arguments or selections")
+		}
+		return
+	}
+	def, ok := v.schema.Types[typeName][sel.name]
+	if !ok {
+		v.fail("cannot query field %q on type %s", sel.name, typeName)
+		return
+	}
+
+	given := map[string]bool{}
+	for _, arg := range sel.arguments {
+		if _, ok := def.Args[arg.name]; !ok {
+			v.fail("unknown argument %q on field %s.%s", arg.name, typeName, sel.name)
+		}
+		given[arg.name] = true
+		v.variables(arg.value)
+	}
+	for name, typ := range def.Args {
+		if strings.HasSuffix(typ, "!") && !given[name] {
+			v.fail("argument %q of type %s is required on field %s.%s", name, typ, typeName, sel.name)
+		}
+	}
+
+	fieldType := namedType(def.Type)
+	if _, object := v.schema.Types[fieldType]; object {
+		if len(sel.selections) == 0 {
+			v.fail("field %s.%s of type %s must have a selection of subfields", typeName, sel.name, def.Type)
+			return
+		}
+		v.selections(fieldType, sel.selections)
+	} else if len(sel.selections) > 0 {
+		v.fail("field %s.%s of type %s cannot have a selection of subfields", typeName, sel.name, def.Type)
+	}
+}
+
+// directives validates the directives of a selection.
+func (v *validator) directives(dirs []directive) {
+	for _, dir := range dirs {
+		if dir.name != "include" && dir.name != "skip" {
+			v.fail("unknown directive @%s", dir.name)
+			continue
+		}
+		if len(dir.arguments) != 1 || dir.arguments[0].name != "if" {
+			v.fail("directive @%s takes a single argument if", dir.name)
+			continue
+		}
+		v.variables(dir.arguments[0].value)
+	}
+}
+
+// variables checks that the variables val refers to are declared.
+func (v *validator) variables(val value) {
+	for _, name := range val.variables() {
+		if !v.declared[name] {
+			v.fail("variable $%s is not defined", name)
+		}
+	}
+}
+
+// executor resolves the selections of a validated operation.
+type executor struct {
+	schema *Schema
+	doc    *document
+	vars   map[string]any
+	errors []Error
+}
+
+// fieldGroup is the selections of a field with the same response key.
+type fieldGroup struct {
+	key        string
+	selections []selection
+}
+
+// collect returns the fields of sels grouped by response key, in order, applying fragments and directives.
+func (e *executor) collect(sels []selection, groups []fieldGroup) []fieldGroup {
+	for _, sel := range sels {
+		if !e.included(sel.directives) {
+			continue
+		}
+		switch {
+		case sel.spread != "":
+			groups = e.collect(e.doc.fragments[sel.spread].selections, groups)
+		case sel.inline:
+			groups = e.collect(sel.selections, groups)
+		default:
+			found := false
+			for i := range groups {
+				if groups[i].key == sel.responseKey() {
+					groups[i].selections = append(groups[i].selections, sel)
+					found = true
+					break
+				}
+			}
+			if !found {
+				groups = append(groups, fieldGroup{key: sel.responseKey(), selections: []selection{sel}})
+			}
+		}
+	}
+	return groups
+}
+
+// included applies the @include and @skip directives.
+func (e *executor) included(dirs []directive) bool {
+	for _, dir := range dirs {
+		cond, _ := dir.arguments[0].value.resolve(e.vars).(bool)
+		if dir.name == "include" && !cond || dir.name == "skip" && cond {
+			return false
+		}
+	}
+	return true
+}
+
+// selections resolves sels on source, an object of the type typeName.
+func (e *executor) selections(ctx context.Context, typeName string, source map[string]any, sels []selection, path []any) object {
+	groups := e.collect(sels, nil)
+	out := make(object, 0, len(groups))
+	for _, group := range groups {
+		sel := group.selections[0]
+		fieldPath := append(append([]any{}, path...), group.key)
+		if sel.name == "__typename" {
+			out = append(out, objectField{group.key, typeName})
+			continue
+		}
+
+		def := e.schema.Types[typeName][sel.name]
+		value := source[sel.name]
+		if def.Resolve != nil {
+			args, err := e.arguments(def, sel)
+			if err == nil {
+				value, err = def.Resolve(ctx, args)
+			}
+			if err != nil {
+				e.fail(err, fieldPath)
+				out = append(out, objectField{group.key, nil})
+				continue
+			}
+		}
+
+		var subselections []selection
+		for _, s := range group.selections {
+			subselections = append(subselections, s.selections...)
+		}
+		out = append(out, objectField{group.key, e.complete(ctx, def.Type, value, subselections, fieldPath)})
+	}
+	return out
+}
+
+// arguments returns the coerced arguments of a field selection.
+func (e *executor) arguments(def Field, sel selection) (map[string]any, error) {
+	args := make(map[string]any, len(sel.arguments))
+	for _, arg := range sel.arguments {
+		v, err := coerce(def.Args[arg.name], arg.value.resolve(e.vars))
+		if err != nil {
+			return nil, Error{
+				Message:    fmt.Sprintf("argument %q: %s", arg.name, err),
+				Extensions: map[string]any{"code": CodeValidationFailed},
+			}
+		}
+		if v != nil {
+			args[arg.name] = v
+		}
+	}
+	return args, nil
+}
+
+// complete converts the resolved value of a field to its response value.
+func (e *executor) complete(ctx context.Context, typ string, value any, sels []selection, path []any) any {
+	nonNull := strings.HasSuffix(typ, "!")
+	typ = strings.TrimSuffix(typ, "!")
+	rv := reflect.ValueOf(value)
+	if value == nil || (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice || rv.Kind() == reflect.Pointer) && rv.IsNil() {
+		if nonNull {
+			e.fail(fmt.Errorf("cannot return null for non-nullable field"), path)
+		}
+		return nil
+	}
+
+	if strings.HasPrefix(typ, "[") {
+		if rv.Kind() != reflect.Slice {
+			e.fail(fmt.Errorf("expected a list, got %T", value), path)
+			return nil
+		}
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = e.complete(ctx, typ[1:len(typ)-1], rv.Index(i).Interface(), sels, append(append([]any{}, path...), i))
+		}
+		return list
+	}
+
+	if _, ok := e.schema.Types[typ]; ok {
+		source, ok := value.(map[string]any)
+		if !ok {
+			e.fail(fmt.Errorf("expected an object, got %T", value), path)
+			return nil
+		}
+		return e.selections(ctx, typ, source, sels, path)
+	}
+	return value
+}
+
+// fail records a field error.
+func (e *executor) fail(err error, path []any) {
+	var gqlErr Error
+	switch {
+	case errors.As(err, &gqlErr):
+	case e.schema.FormatError != nil:
+		gqlErr = e.schema.FormatError(err)
+	default:
+		gqlErr = Error{Message: err.Error()}
+	}
+	gqlErr.Path = path
+	e.errors = append(e.errors, gqlErr)
+}
+
+// object is a response object; its fields are encoded in selection order.
+type object []objectField
+
+// objectField is a field of a response object.
+type objectField struct {
+	key   string
+	value any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSchema is a schema of a user with a list of accounts.
+func testSchema() *Schema {
+	return &Schema{
+		Types: map[string]Object{
+			"Query": {
+				"user": {Type: "User!", Args: map[string]string{"id": "ID!", "verbose": "Boolean"}, Resolve: func(_ context.Context, args map[string]any) (any, error) {
+					return map[string]any{
+						"id":   args["id"],
+						"name": "alice",
+						"accounts": []map[string]any{
+							{"currency": "USD", "amount": "10.00"},
+							{"currency": "EUR", "amount": "5.00"},
+						},
+						"verbose": args["verbose"],
+					}, nil
+				}},
+				"fail": {Type: "String", Resolve: func(context.Context, map[string]any) (any, error) {
+					return nil, errors.New("boom")
+				}},
+				"missing": {Type: "String!", Resolve: func(context.Context, map[string]any) (any, error) {
+					return nil, nil
+				}},
+				"sum": {Type: "Float!", Args: map[string]string{"values": "[Float!]!"}, Resolve: func(_ context.Context, args map[string]any) (any, error) {
+					var sum float64
+					for _, v := range args["values"].([]any) {
+						sum += v.(float64)
+					}
+					return sum, nil
+				}},
+			},
+			"User": {
+				"id":       {Type: "ID!"},
+				"name":     {Type: "String!"},
+				"accounts": {Type: "[Account!]!"},
+				"verbose":  {Type: "Boolean"},
+			},
+			"Account": {
+				"currency": {Type: "String!"},
+				"amount":   {Type: "String!"},
+			},
+		},
+	}
+}
+
+func execute(t *testing.T, schema *Schema, req Request) string {
+	t.Helper()
+	b, err := json.Marshal(schema.Execute(context.Background(), req))
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestExecute(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req  Request
+		want string
+	}{
+		"fields in selection order": {
+			req:  Request{Query: `{ user(id: 1) { name id accounts { currency } } }`},
+			want: `{"data":{"user":{"name":"alice","id":"1","accounts":[{"currency":"USD"},{"currency":"EUR"}]}}}`,
+		},
+		"aliases and typename": {
+			req:  Request{Query: `{ a: user(id: "a") { __typename login: name } b: user(id: "b") { id } }`},
+			want: `{"data":{"a":{"__typename":"User","login":"alice"},"b":{"id":"b"}}}`,
+		},
+		"fragments merged": {
+			req: Request{Query: `
+				query { user(id: 1) { ...Names ... on User { accounts { amount } } accounts { currency } } }
+				fragment Names on User { name }`},
+			want: `{"data":{"user":{"name":"alice","accounts":[{"amount":"10.00","currency":"USD"},{"amount":"5.00","currency":"EUR"}]}}}`,
+		},
+		"variables and directives": {
+			req: Request{
+				Query:     `query Q($id: ID!, $withName: Boolean = false, $verbose: Boolean) { user(id: $id, verbose: $verbose) { id name @include(if: $withName) verbose @skip(if: true) } }`,
+				Variables: map[string]any{"id": 7.0},
+			},
+			want: `{"data":{"user":{"id":"7"}}}`,
+		},
+		"operation by name": {
+			req:  Request{Query: `query A { fail } query B { user(id: 1) { id } }`, OperationName: "B"},
+			want: `{"data":{"user":{"id":"1"}}}`,
+		},
+		"list coercion": {
+			req:  Request{Query: `query($v: [Float!]!) { one: sum(values: 2) many: sum(values: $v) }`, Variables: map[string]any{"v": []any{1.5, 2.0}}},
+			want: `{"data":{"one":2,"many":3.5}}`,
+		},
+		"field errors keep other fields": {
+			req:  Request{Query: `{ fail user(id: 1) { id } }`},
+			want: `{"data":{"fail":null,"user":{"id":"1"}},"errors":[{"message":"boom","path":["fail"]}]}`,
+		},
+		"null for non-null field": {
+			req:  Request{Query: `{ missing }`},
+			want: `{"data":{"missing":null},"errors":[{"message":"cannot return null for non-nullable field","path":["missing"]}]}`,
+		},
+		"invalid argument value": {
+			req:  Request{Query: `{ user(id: true) { id } }`},
+			want: `{"data":{"user":null},"errors":[{"message":"argument \"id\": expected ID, got true","path":["user"],"extensions":{"code":"graphql_validation_failed"}}]}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.JSONEq(t, tc.want, execute(t, testSchema(), tc.req))
+		})
+	}
+}
+
+func TestExecute_FormatError(t *testing.T) {
+	schema := testSchema()
+	schema.FormatError = func(err error) Error {
+		return Error{Message: "formatted " + err.Error(), Extensions: map[string]any{"code": "internal"}}
+	}
+
+	got := execute(t, schema, Request{Query: `{ fail }`})
+	assert.JSONEq(t, `{"data":{"fail":null},"errors":[{"message":"formatted boom","path":["fail"],"extensions":{"code":"internal"}}]}`, got)
+}
+
+func TestExecute_RequestErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req     Request
+		code    string
+		message string
+	}{
+		"syntax":                 {req: Request{Query: `{ user(`}, code: CodeParseFailed, message: "syntax error at 1:8: unexpected end of document"},
+		"several operations":     {req: Request{Query: `query A { fail } query B { fail }`}, code: CodeValidationFailed, message: "operationName is required for documents with several operations"},
+		"unknown operation":      {req: Request{Query: `{ fail }`, OperationName: "X"}, code: CodeValidationFailed, message: `unknown operation "X"`},
+		"mutation not supported": {req: Request{Query: `mutation { fail }`}, code: CodeValidationFailed, message: "mutation operations are not supported"},
+		"subscription":           {req: Request{Query: `subscription { fail }`}, code: CodeValidationFailed, message: "subscription operations are not supported"},
+		"unknown field":          {req: Request{Query: `{ user(id: 1) { email } }`}, code: CodeValidationFailed, message: `cannot query field "email" on type User`},
+		"unknown argument":       {req: Request{Query: `{ fail(x: 1) }`}, code: CodeValidationFailed, message: `unknown argument "x" on field Query.fail`},
+		"missing argument":       {req: Request{Query: `{ user { id } }`}, code: CodeValidationFailed, message: `argument "id" of type ID! is required on field Query.user`},
+		"missing subselection":   {req: Request{Query: `{ user(id: 1) }`}, code: CodeValidationFailed, message: "field Query.user of type User! must have a selection of subfields"},
+		"scalar subselection":    {req: Request{Query: `{ fail { id } }`}, code: CodeValidationFailed, message: "field Query.fail of type String cannot have a selection of subfields"},
+		"undefined variable":     {req: Request{Query: `{ user(id: $id) { id } }`}, code: CodeValidationFailed, message: "variable $id is not defined"},
+		"missing variable":       {req: Request{Query: `query($id: ID!) { user(id: $id) { id } }`}, code: CodeValidationFailed, message: "variable $id of required type ID! was not provided"},
+		"invalid variable":       {req: Request{Query: `query($id: ID!) { user(id: $id) { id } }`, Variables: map[string]any{"id": 1.5}}, code: CodeValidationFailed, message: "variable $id: expected ID, got 1.5"},
+		"unknown fragment":       {req: Request{Query: `{ ...F }`}, code: CodeValidationFailed, message: `unknown fragment "F"`},
+		"fragment cycle":         {req: Request{Query: `{ ...F } fragment F on Query { ...F }`}, code: CodeValidationFailed, message: `fragment "F" spreads itself`},
+		"fragment on other type": {req: Request{Query: `{ ...F } fragment F on User { id }`}, code: CodeValidationFailed, message: `fragment "F" on User cannot be spread on Query`},
+		"unknown directive":      {req: Request{Query: `{ fail @defer }`}, code: CodeValidationFailed, message: "unknown directive @defer"},
+		"introspection":          {req: Request{Query: `{ __schema { types { name } } }`}, code: CodeValidationFailed, message: `cannot query field "__schema" on type Query`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := testSchema().Execute(context.Background(), tc.req)
+			assert.Nil(t, resp.Data)
+			assert.Equal(t, []Error{{Message: tc.message, Extensions: map[string]any{"code": tc.code}}}, resp.Errors)
+		})
+	}
+}
@@ -0,0 +1,743 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed GraphQL request document.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query or mutation of a document.
+type operation struct {
+	kind       string // query or mutation
+	name       string
+	variables  []variableDefinition
+	selections []selection
+}
+
+// variableDefinition declares a variable of an operation.
+type variableDefinition struct {
+	name       string
+	typ        string // e.g. [String!]!
+	defaultVal *value
+}
+
+// fragment is a named fragment of a document.
+type fragment struct {
+	typeCondition string
+	selections    []selection
+}
+
+// selection is a field, a fragment spread or an inline fragment of a selection set.
+type selection struct {
+	// Field
+	alias      string
+	name       string
+	arguments  []argument
+	selections []selection
+
+	spread        string // Name of the spread fragment
+	inline        bool   // Inline fragment, with selections
+	typeCondition string // Type condition of an inline fragment, if any
+	directives    []directive
+}
+
+// responseKey returns the key of the field in the response.
+func (s selection) responseKey() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+// argument is an argument of a field or directive.
+type argument struct {
+	name  string
+	value value
+}
+
+// directive is a directive of a selection, e.g. @include(if: $flag).
+type directive struct {
+	name      string
+	arguments []argument
+}
+
+// Kinds of values.
+const (
+	valueVariable = iota
+	valueInt
+	valueFloat
+	valueString
+	valueBoolean
+	valueNull
+	valueEnum
+	valueList
+	valueObject
+)
+
+// value is a literal or variable of a document.
+type value struct {
+	kind   int
+	raw    string     // Variable name, or the text of scalars and enums
+	list   []value    // Items of lists
+	fields []argument // Fields of objects
+}
+
+// resolve returns the Go value of v: string, int, float64, bool, nil, []any or map[string]any.
+func (v value) resolve(vars map[string]any) any {
+	switch v.kind {
+	case valueVariable:
+		return vars[v.raw]
+	case valueInt:
+		n, _ := strconv.Atoi(v.raw)
+		return n
+	case valueFloat:
+		n, _ := strconv.ParseFloat(v.raw, 64)
+		return n
+	case valueBoolean:
+		return v.raw == "true"
+	case valueNull:
+		return nil
+	case valueList:
+		list := make([]any, len(v.list))
+		for i, item := range v.list {
+			list[i] = item.resolve(vars)
+		}
+		return list
+	case valueObject:
+		obj := make(map[string]any, len(v.fields))
+		for _, f := range v.fields {
+			obj[f.name] = f.value.resolve(vars)
+		}
+		return obj
+	default: // string, enum
+		return v.raw
+	}
+}
+
+// variables returns the names of the variables v refers to.
+func (v value) variables() []string {
+	switch v.kind {
+	case valueVariable:
+		return []string{v.raw}
+	case valueList:
+		var names []string
+		for _, item := range v.list {
+			names = append(names, item.variables()...)
+		}
+		return names
+	case valueObject:
+		var names []string
+		for _, f := range v.fields {
+			names = append(names, f.value.variables()...)
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// Kinds of tokens.
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token of a document.
+type token struct {
+	kind int
+	text string // Punctuator, name, number or the decoded string
+	pos  int    // Byte offset in the document
+}
+
+// lexer splits a document into tokens.
+type lexer struct {
+	src string
+	pos int
+}
+
+// next returns the next token, skipping whitespace, commas and comments.
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return l.token()
+		}
+	}
+	return token{kind: tokenEOF, pos: l.pos}, nil
+}
+
+// token reads the token at the current position.
+func (l *lexer) token() (token, error) {
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, text: "...", pos: start}, nil
+	case strings.ContainsRune("!$&()*:=@[]{}|", rune(c)):
+		l.pos++
+		return token{kind: tokenPunct, text: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, text: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.blockString()
+		}
+		return l.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return token{}, l.errorf(start, "unexpected character %q", r)
+	}
+}
+
+// number reads an Int or Float token.
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return token{}, l.errorf(start, "invalid number")
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if digits() == 0 {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if digits() == 0 {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || l.src[l.pos] == '.') {
+		return token{}, l.errorf(start, "invalid number")
+	}
+	return token{kind: kind, text: l.src[start:l.pos], pos: start}, nil
+}
+
+// string reads a quoted string token and decodes its escapes.
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenString, text: b.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, l.errorf(start, "unterminated string")
+		case c == '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, l.errorf(start, "unterminated string")
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				l.pos += 4
+			default:
+				return token{}, l.errorf(start, "invalid escape \\%c", esc)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+// blockString reads a """block string""" token. Its indentation is kept as written.
+func (l *lexer) blockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+	end := strings.Index(l.src[l.pos:], `"""`)
+	if end < 0 {
+		return token{}, l.errorf(start, "unterminated string")
+	}
+	text := strings.ReplaceAll(l.src[l.pos:l.pos+end], `\"""`, `"""`)
+	l.pos += end + 3
+	return token{kind: tokenString, text: strings.TrimSpace(text), pos: start}, nil
+}
+
+// errorf returns a syntax error at the byte offset pos.
+func (l *lexer) errorf(pos int, format string, args ...any) error {
+	line, col := 1, 1
+	for _, r := range l.src[:pos] {
+		if r == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return fmt.Errorf("syntax error at %d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// parser builds a document from the tokens of a lexer.
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// parse parses a GraphQL request document.
+func parse(src string) (*document, error) {
+	p := &parser{lex: &lexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"), p.tok.kind == tokenName && (p.tok.text == "query" || p.tok.text == "mutation" || p.tok.text == "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.tok.kind == tokenName && p.tok.text == "fragment":
+			name, frag, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[name]; ok {
+				return nil, fmt.Errorf("fragment %q is defined more than once", name)
+			}
+			doc.fragments[name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document has no operation")
+	}
+	return doc, nil
+}
+
+// advance reads the next token.
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// peek reports whether the current token is the punctuator punct.
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == punct
+}
+
+// skip consumes the punctuator punct if it is the current token and reports whether it was.
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+// expect consumes the punctuator punct.
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// name consumes a name.
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.text
+	return name, p.advance()
+}
+
+// unexpected returns a syntax error for the current token.
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return p.lex.errorf(p.tok.pos, "unexpected end of document")
+	}
+	return p.lex.errorf(p.tok.pos, "unexpected %q", p.tok.text)
+}
+
+// operation parses an operation definition.
+func (p *parser) operation() (*operation, error) {
+	op := &operation{kind: "query"}
+	if p.tok.kind == tokenName {
+		op.kind = p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokenName {
+			op.name = p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if ok, err := p.skip("("); err != nil {
+			return nil, err
+		} else if ok {
+			for !p.peek(")") {
+				def, err := p.variableDefinition()
+				if err != nil {
+					return nil, err
+				}
+				op.variables = append(op.variables, def)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.directives(); err != nil {
+			return nil, err
+		}
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+// variableDefinition parses $name: Type = default.
+func (p *parser) variableDefinition() (variableDefinition, error) {
+	if err := p.expect("$"); err != nil {
+		return variableDefinition{}, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return variableDefinition{}, err
+	}
+	if err := p.expect(":"); err != nil {
+		return variableDefinition{}, err
+	}
+	typ, err := p.typeRef()
+	if err != nil {
+		return variableDefinition{}, err
+	}
+	def := variableDefinition{name: name, typ: typ}
+	if ok, err := p.skip("="); err != nil {
+		return def, err
+	} else if ok {
+		v, err := p.value(true)
+		if err != nil {
+			return def, err
+		}
+		def.defaultVal = &v
+	}
+	if _, err := p.directives(); err != nil {
+		return def, err
+	}
+	return def, nil
+}
+
+// typeRef parses a type reference such as [String!]!.
+func (p *parser) typeRef() (string, error) {
+	var typ string
+	if ok, err := p.skip("["); err != nil {
+		return "", err
+	} else if ok {
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+	if ok, err := p.skip("!"); err != nil {
+		return "", err
+	} else if ok {
+		typ += "!"
+	}
+	return typ, nil
+}
+
+// fragment parses a fragment definition.
+func (p *parser) fragment() (string, *fragment, error) {
+	if err := p.advance(); err != nil {
+		return "", nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return "", nil, err
+	}
+	if p.tok.kind != tokenName || p.tok.text != "on" {
+		return "", nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return "", nil, err
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return "", nil, err
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return "", nil, err
+	}
+	return name, &fragment{typeCondition: typeCondition, selections: selections}, nil
+}
+
+// selectionSet parses { selection... }.
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []selection
+	for !p.peek("}") {
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+	return selections, p.advance()
+}
+
+// selection parses a field, a fragment spread or an inline fragment.
+func (p *parser) selection() (selection, error) {
+	var sel selection
+	if ok, err := p.skip("..."); err != nil {
+		return sel, err
+	} else if ok {
+		if p.tok.kind == tokenName && p.tok.text != "on" {
+			sel.spread = p.tok.text
+			if err := p.advance(); err != nil {
+				return sel, err
+			}
+			sel.directives, err = p.directives()
+			return sel, err
+		}
+		sel.inline = true
+		if p.tok.kind == tokenName {
+			if err := p.advance(); err != nil {
+				return sel, err
+			}
+			if sel.typeCondition, err = p.name(); err != nil {
+				return sel, err
+			}
+		}
+		if sel.directives, err = p.directives(); err != nil {
+			return sel, err
+		}
+		sel.selections, err = p.selectionSet()
+		return sel, err
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return sel, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return sel, err
+	} else if ok {
+		sel.alias = name
+		if name, err = p.name(); err != nil {
+			return sel, err
+		}
+	}
+	sel.name = name
+	if sel.arguments, err = p.arguments(false); err != nil {
+		return sel, err
+	}
+	if sel.directives, err = p.directives(); err != nil {
+		return sel, err
+	}
+	if p.peek("{") {
+		sel.selections, err = p.selectionSet()
+	}
+	return sel, err
+}
+
+// arguments parses (name: value, ...) if present.
+func (p *parser) arguments(constant bool) ([]argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+	var args []argument
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, argument{name: name, value: v})
+	}
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+	return args, p.advance()
+}
+
+// directives parses @name(arguments) directives if present.
+func (p *parser) directives() ([]directive, error) {
+	var dirs []directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments(false)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, directive{name: name, arguments: args})
+	}
+	return dirs, nil
+}
+
+// value parses a value; constant values may not contain variables.
+func (p *parser) value(constant bool) (value, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		return value{kind: valueInt, raw: tok.text}, p.advance()
+	case tokenFloat:
+		return value{kind: valueFloat, raw: tok.text}, p.advance()
+	case tokenString:
+		return value{kind: valueString, raw: tok.text}, p.advance()
+	case tokenName:
+		kind := valueEnum
+		switch tok.text {
+		case "true", "false":
+			kind = valueBoolean
+		case "null":
+			kind = valueNull
+		}
+		return value{kind: kind, raw: tok.text}, p.advance()
+	}
+
+	switch {
+	case p.peek("$") && !constant:
+		if err := p.advance(); err != nil {
+			return value{}, err
+		}
+		name, err := p.name()
+		return value{kind: valueVariable, raw: name}, err
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return value{}, err
+		}
+		v := value{kind: valueList, list: []value{}}
+		for !p.peek("]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return value{}, err
+			}
+			v.list = append(v.list, item)
+		}
+		return v, p.advance()
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return value{}, err
+		}
+		v := value{kind: valueObject}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return value{}, err
+			}
+			if err := p.expect(":"); err != nil {
+				return value{}, err
+			}
+			field, err := p.value(constant)
+			if err != nil {
+				return value{}, err
+			}
+			v.fields = append(v.fields, argument{name: name, value: field})
+		}
+		return v, p.advance()
+	default:
+		return value{}, p.unexpected()
+	}
+}
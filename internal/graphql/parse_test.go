@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := parse(`
+		# Balances and a deposit
+		query Wallet($limit: Int = 10, $currencies: [String!]!) @cached {
+			balance { currency amount }
+			recent: transactions(limit: $limit, currency: "US\"D!", tags: [1, 2.5e1, true, null, RED], page: {size: 3}) {
+				...Entry @include(if: true)
+				... on TransactionPage { nextCursor }
+			}
+		}
+
+		fragment Entry on TransactionPage { transactions { transactionId } }
+
+		mutation { deposit(amount: """ 100.50 """, currency: "USD") { amount } }
+	`)
+	assert.NoError(t, err)
+	assert.Len(t, doc.operations, 2)
+	assert.Contains(t, doc.fragments, "Entry")
+	assert.Equal(t, "TransactionPage", doc.fragments["Entry"].typeCondition)
+
+	query := doc.operations[0]
+	assert.Equal(t, "query", query.kind)
+	assert.Equal(t, "Wallet", query.name)
+	assert.Len(t, query.variables, 2)
+	assert.Equal(t, "Int", query.variables[0].typ)
+	assert.Equal(t, 10, query.variables[0].defaultVal.resolve(nil))
+	assert.Equal(t, "[String!]!", query.variables[1].typ)
+
+	recent := query.selections[1]
+	assert.Equal(t, "recent", recent.responseKey())
+	assert.Equal(t, "transactions", recent.name)
+	args := map[string]any{}
+	for _, arg := range recent.arguments {
+		args[arg.name] = arg.value.resolve(map[string]any{"limit": 5})
+	}
+	assert.Equal(t, map[string]any{
+		"limit":    5,
+		"currency": `US"D!`,
+		"tags":     []any{1, 25.0, true, nil, "RED"},
+		"page":     map[string]any{"size": 3},
+	}, args)
+	assert.Equal(t, "Entry", recent.selections[0].spread)
+	assert.Equal(t, "include", recent.selections[0].directives[0].name)
+	assert.True(t, recent.selections[1].inline)
+	assert.Equal(t, "TransactionPage", recent.selections[1].typeCondition)
+
+	mutation := doc.operations[1]
+	assert.Equal(t, "mutation", mutation.kind)
+	assert.Equal(t, "100.50", mutation.selections[0].arguments[0].value.raw)
+}
+
+func TestParse_Shorthand(t *testing.T) {
+	doc, err := parse(`{ balance { currency } }`)
+	assert.NoError(t, err)
+	assert.Equal(t, "query", doc.operations[0].kind)
+	assert.Equal(t, "", doc.operations[0].name)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		src string
+		err string
+	}{
+		"empty":                {src: ``, err: "document has no operation"},
+		"only fragment":        {src: `fragment F on Query { balance }`, err: "document has no operation"},
+		"unclosed selection":   {src: `{ balance`, err: "syntax error at 1:10: unexpected end of document"},
+		"empty selection":      {src: `{ }`, err: `syntax error at 1:3: unexpected "}"`},
+		"bad character":        {src: `{ balance % }`, err: `syntax error at 1:11: unexpected character '%'`},
+		"unterminated string":  {src: "{ a(b: \"x\n\") }", err: "syntax error at 1:8: unterminated string"},
+		"bad escape":           {src: `{ a(b: "\q") }`, err: `syntax error at 1:8: invalid escape \q`},
+		"bad number":           {src: `{ a(b: 1.) }`, err: "syntax error at 1:8: invalid number"},
+		"number followed":      {src: `{ a(b: 12abc) }`, err: "syntax error at 1:8: invalid number"},
+		"variable in default":  {src: `query($a: Int = $b) { a }`, err: `syntax error at 1:17: unexpected "$"`},
+		"empty arguments":      {src: `{ a() }`, err: `syntax error at 1:5: unexpected ")"`},
+		"duplicate fragment":   {src: `{ a } fragment F on Query { a } fragment F on Query { a }`, err: `fragment "F" is defined more than once`},
+		"missing type":         {src: `query($a) { a }`, err: `syntax error at 1:9: unexpected ")"`},
+		"second line position": {src: "{\n  a(b: ) }", err: `syntax error at 2:8: unexpected ")"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parse(tc.src)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
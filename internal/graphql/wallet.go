@@ -0,0 +1,445 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// WalletSDL is the schema of the wallet API in the GraphQL schema definition language.
+// Decimal amounts are strings, times are RFC 3339 strings.
+const WalletSDL = `scalar Decimal
+scalar Time
+
+type Query {
+  balance: [Balance!]!
+  rates: Rates!
+  transactions(from: Time, to: Time, currency: String, operation: String, limit: Int, cursor: String): TransactionPage!
+}
+
+type Mutation {
+  deposit(amount: Decimal!, currency: String!, reference: String): [Balance!]!
+  withdraw(amount: Decimal!, currency: String!, reference: String): [Balance!]!
+  exchange(fromCurrency: String, toCurrency: String, amount: Decimal, minExpectedAmount: Decimal, quoteId: ID): ExchangeResult!
+}
+
+type Balance {
+  currency: String!
+  amount: Decimal!
+}
+
+type Rates {
+  rates: [Rate!]!
+  staleRate: Boolean!
+  fetchedAt: Time!
+  cached: Boolean!
+  provider: String!
+}
+
+type Rate {
+  currency: String!
+  rate: Float!
+}
+
+type TransactionPage {
+  transactions: [Transaction!]!
+  nextCursor: String
+}
+
+type Transaction {
+  transactionId: ID!
+  operation: String!
+  currency: String!
+  amount: Decimal!
+  toCurrency: String
+  toAmount: Decimal
+  reversalOf: ID
+  reference: String
+  timestamp: Time!
+}
+
+type ExchangeResult {
+  exchangedAmount: Decimal!
+  fee: Decimal!
+  rate: Float!
+  balances: [Balance!]!
+  staleRate: Boolean!
+  derivedRate: Boolean!
+}
+`
+
+// WalletService is the part of the WalletService of proto/wallet the schema resolves against.
+// It is called in-process through the gRPC interceptors, so balances and money-moving
+// mutations get the validation, dormancy check and per-user lock of the other APIs.
+type WalletService interface {
+	GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (*pb.BalanceResponse, error)
+	Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.BalanceResponse, error)
+	Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.BalanceResponse, error)
+	Exchange(ctx context.Context, req *pb.ExchangeRequest) (*pb.ExchangeResponse, error)
+}
+
+// ExchangeRatesReader defines the interface for fetching exchange rates.
+type ExchangeRatesReader interface {
+	GetExchangeRates(ctx context.Context) (rates map[string]float32, src models.RateSource, err error)
+}
+
+// TransactionLister lists the transaction history of a user.
+type TransactionLister interface {
+	ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error)
+}
+
+// CurrencyChecker validates currency codes against the supported currencies.
+type CurrencyChecker interface {
+	IsSupported(ctx context.Context, code string) bool
+}
+
+// Tokener defines only the methods needed by the handler.
+type Tokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// claimsKey is the context key of the claims of the request.
+type claimsKey struct{}
+
+// NewWalletSchema returns the schema of WalletSDL.
+func NewWalletSchema(wallet WalletService, rates ExchangeRatesReader, history TransactionLister, currencies CurrencyChecker) *Schema {
+	moneyArgs := map[string]string{"amount": "Decimal!", "currency": "String!", "reference": "String"}
+
+	return &Schema{
+		Types: map[string]Object{
+			"Query": {
+				"balance": {Type: "[Balance!]!", Resolve: func(ctx context.Context, _ map[string]any) (any, error) {
+					resp, err := wallet.GetBalance(ctx, &pb.GetBalanceRequest{})
+					if err != nil {
+						return nil, err
+					}
+					return renderBalances(resp.GetBalances()), nil
+				}},
+				"rates": {Type: "Rates!", Resolve: func(ctx context.Context, _ map[string]any) (any, error) {
+					return resolveRates(ctx, rates)
+				}},
+				"transactions": {
+					Type: "TransactionPage!",
+					Args: map[string]string{
+						"from": "Time", "to": "Time", "currency": "String", "operation": "String", "limit": "Int", "cursor": "String",
+					},
+					Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+						return resolveTransactions(ctx, history, currencies, args)
+					},
+				},
+			},
+			"Mutation": {
+				"deposit": {Type: "[Balance!]!", Args: moneyArgs, Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+					resp, err := wallet.Deposit(ctx, &pb.DepositRequest{
+						Amount: decimal(args["amount"]), Currency: str(args["currency"]), Reference: str(args["reference"]),
+					})
+					if err != nil {
+						return nil, err
+					}
+					return renderBalances(resp.GetBalances()), nil
+				}},
+				"withdraw": {Type: "[Balance!]!", Args: moneyArgs, Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+					resp, err := wallet.Withdraw(ctx, &pb.WithdrawRequest{
+						Amount: decimal(args["amount"]), Currency: str(args["currency"]), Reference: str(args["reference"]),
+					})
+					if err != nil {
+						return nil, err
+					}
+					return renderBalances(resp.GetBalances()), nil
+				}},
+				"exchange": {
+					Type: "ExchangeResult!",
+					Args: map[string]string{
+						"fromCurrency": "String", "toCurrency": "String", "amount": "Decimal", "minExpectedAmount": "Decimal", "quoteId": "ID",
+					},
+					Resolve: func(ctx context.Context, args map[string]any) (any, error) {
+						resp, err := wallet.Exchange(ctx, &pb.ExchangeRequest{
+							FromCurrency:      str(args["fromCurrency"]),
+							ToCurrency:        str(args["toCurrency"]),
+							Amount:            decimal(args["amount"]),
+							MinExpectedAmount: decimal(args["minExpectedAmount"]),
+							QuoteId:           str(args["quoteId"]),
+						})
+						if err != nil {
+							return nil, err
+						}
+						return map[string]any{
+							"exchangedAmount": resp.GetExchangedAmount(),
+							"fee":             resp.GetFee(),
+							"rate":            resp.GetRate(),
+							"balances":        renderBalances(resp.GetBalances()),
+							"staleRate":       resp.GetStaleRate(),
+							"derivedRate":     resp.GetDerivedRate(),
+						}, nil
+					},
+				},
+			},
+			"Balance": {"currency": {Type: "String!"}, "amount": {Type: "Decimal!"}},
+			"Rates": {
+				"rates":     {Type: "[Rate!]!"},
+				"staleRate": {Type: "Boolean!"},
+				"fetchedAt": {Type: "Time!"},
+				"cached":    {Type: "Boolean!"},
+				"provider":  {Type: "String!"},
+			},
+			"Rate": {"currency": {Type: "String!"}, "rate": {Type: "Float!"}},
+			"TransactionPage": {
+				"transactions": {Type: "[Transaction!]!"},
+				"nextCursor":   {Type: "String"},
+			},
+			"Transaction": {
+				"transactionId": {Type: "ID!"},
+				"operation":     {Type: "String!"},
+				"currency":      {Type: "String!"},
+				"amount":        {Type: "Decimal!"},
+				"toCurrency":    {Type: "String"},
+				"toAmount":      {Type: "Decimal"},
+				"reversalOf":    {Type: "ID"},
+				"reference":     {Type: "String"},
+				"timestamp":     {Type: "Time!"},
+			},
+			"ExchangeResult": {
+				"exchangedAmount": {Type: "Decimal!"},
+				"fee":             {Type: "Decimal!"},
+				"rate":            {Type: "Float!"},
+				"balances":        {Type: "[Balance!]!"},
+				"staleRate":       {Type: "Boolean!"},
+				"derivedRate":     {Type: "Boolean!"},
+			},
+		},
+		FormatError: walletError,
+	}
+}
+
+// NewHandler returns an HTTP handler executing GraphQL requests against schema.
+// @Summary Execute a GraphQL request
+// @Description Executes a query or mutation of the wallet schema: balance, rates and transactions queries; deposit, withdraw and exchange mutations. Errors of fields carry the code of the REST API's answer in extensions.code; requests that cannot be parsed or validated are answered with 400 and no data.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request true "GraphQL request"
+// @Success 200 {object} graphql.Response "Result, with the errors of failed fields"
+// @Failure 400 {object} graphql.Response "Invalid GraphQL request"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Router /graphql [post]
+// @Security BearerAuth
+func NewHandler(schema *Schema, tokenGetter Tokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		// The wallet service authenticates its calls by their metadata, as the gateway's
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+			"authorization", "Bearer "+tokenStr,
+			"user-agent", r.UserAgent(),
+		))
+		ctx = context.WithValue(ctx, claimsKey{}, claims)
+
+		resp := schema.Execute(ctx, req)
+		w.Header().Set("Content-Type", "application/json")
+		if resp.Data == nil {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// resolveRates resolves the rates query.
+func resolveRates(ctx context.Context, reader ExchangeRatesReader) (any, error) {
+	rates, src, err := reader.GetExchangeRates(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to fetch exchange rates", "error", err)
+		switch {
+		case errors.Is(err, services.ErrExchangerUnavailable):
+			return nil, apperrors.ExchangerUnavailable
+		case errors.Is(err, services.ErrExchangerTimeout):
+			return nil, apperrors.ExchangerTimeout
+		default:
+			return nil, apperrors.ExchangeRatesFailed
+		}
+	}
+
+	list := make([]map[string]any, 0, len(rates))
+	for currency, rate := range rates {
+		list = append(list, map[string]any{"currency": currency, "rate": rate})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i]["currency"].(string) < list[j]["currency"].(string) })
+
+	return map[string]any{
+		"rates":     list,
+		"staleRate": src.Stale,
+		"fetchedAt": src.FetchedAt.Format(time.RFC3339Nano),
+		"cached":    src.Cached,
+		"provider":  src.Provider,
+	}, nil
+}
+
+// resolveTransactions resolves the transactions query, with the filters of GET /wallet/transactions.
+func resolveTransactions(ctx context.Context, history TransactionLister, currencies CurrencyChecker, args map[string]any) (any, error) {
+	claims, _ := ctx.Value(claimsKey{}).(*jwt.Claims)
+	if claims == nil {
+		return nil, apperrors.Unauthorized
+	}
+
+	filter := models.TransactionFilter{
+		UserID:    claims.UserID,
+		Currency:  str(args["currency"]),
+		Operation: str(args["operation"]),
+	}
+	var err error
+	if v := str(args["from"]); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, apperrors.InvalidFrom
+		}
+	}
+	if v := str(args["to"]); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, apperrors.InvalidTo
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, apperrors.InvalidDateRange
+	}
+	if filter.Currency != "" && !currencies.IsSupported(ctx, filter.Currency) {
+		return nil, apperrors.InvalidCurrency
+	}
+	switch filter.Operation {
+	case "", models.OperationDeposit, models.OperationWithdraw, models.OperationExchange, models.OperationClose,
+		models.OperationReversal, models.OperationTransferOut, models.OperationTransferIn:
+	default:
+		return nil, apperrors.InvalidOperation
+	}
+	if limit, ok := args["limit"].(int); ok {
+		if limit < 1 {
+			return nil, apperrors.InvalidLimit
+		}
+		filter.Limit = limit
+	}
+
+	txns, next, err := history.ListTransactions(ctx, filter, str(args["cursor"]))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			return nil, apperrors.InvalidCursor
+		}
+		return nil, err
+	}
+
+	list := make([]map[string]any, 0, len(txns))
+	for _, t := range txns {
+		entry := map[string]any{
+			"transactionId": t.TransactionID.String(),
+			"operation":     t.Operation,
+			"currency":      t.Currency,
+			"amount":        t.Amount.String(),
+			"timestamp":     t.CreatedAt.Format(time.RFC3339Nano),
+		}
+		if t.ToCurrency != nil {
+			entry["toCurrency"] = *t.ToCurrency
+		}
+		if t.ToAmount != nil {
+			entry["toAmount"] = t.ToAmount.String()
+		}
+		if t.ReversalOf != nil {
+			entry["reversalOf"] = t.ReversalOf.String()
+		}
+		if t.Reference != nil {
+			entry["reference"] = *t.Reference
+		}
+		list = append(list, entry)
+	}
+
+	page := map[string]any{"transactions": list}
+	if next != "" {
+		page["nextCursor"] = next
+	}
+	return page, nil
+}
+
+// walletError converts an error of a resolver to a GraphQL error with the message and code of
+// its catalog entry. Statuses of the wallet service are looked up by message, as by the
+// gateway; other errors are internal.
+func walletError(err error) Error {
+	var e apperrors.Error
+	if st, ok := status.FromError(err); ok {
+		if e, ok = apperrors.ByMessage(st.Message()); !ok {
+			e = apperrors.Internal
+			e.Message = st.Message()
+		}
+	} else if !errors.As(err, &e) {
+		logger.Log.Errorw("graphql resolver failed", "error", err)
+		e = apperrors.Internal
+	}
+	return Error{Message: e.Message, Extensions: map[string]any{"code": e.Code}}
+}
+
+// renderBalances returns balances as Balance objects ordered by currency.
+func renderBalances(balances map[string]string) []map[string]any {
+	currencies := make([]string, 0, len(balances))
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	list := make([]map[string]any, len(currencies))
+	for i, currency := range currencies {
+		list[i] = map[string]any{"currency": currency, "amount": balances[currency]}
+	}
+	return list
+}
+
+// str returns an optional String argument, empty if absent.
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// decimal returns an optional Decimal argument as a decimal string, empty if absent.
+// Decimals may be sent as strings or JSON numbers.
+func decimal(v any) string {
+	switch n := v.(type) {
+	case string:
+		return n
+	case int:
+		return strconv.Itoa(n)
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,267 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/graphql/wallet.go
+
+// Package graphql is a generated GoMock package.
+package graphql
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	wallet "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+)
+
+// MockWalletService is a mock of WalletService interface.
+type MockWalletService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletServiceMockRecorder
+}
+
+// MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
+type MockWalletServiceMockRecorder struct {
+	mock *MockWalletService
+}
+
+// NewMockWalletService creates a new mock instance.
+func NewMockWalletService(ctrl *gomock.Controller) *MockWalletService {
+	mock := &MockWalletService{ctrl: ctrl}
+	mock.recorder = &MockWalletServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
+	return m.recorder
+}
+
+// Deposit mocks base method.
+func (m *MockWalletService) Deposit(ctx context.Context, req *wallet.DepositRequest) (*wallet.BalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deposit", ctx, req)
+	ret0, _ := ret[0].(*wallet.BalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deposit indicates an expected call of Deposit.
+func (mr *MockWalletServiceMockRecorder) Deposit(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockWalletService)(nil).Deposit), ctx, req)
+}
+
+// Exchange mocks base method.
+func (m *MockWalletService) Exchange(ctx context.Context, req *wallet.ExchangeRequest) (*wallet.ExchangeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, req)
+	ret0, _ := ret[0].(*wallet.ExchangeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockWalletServiceMockRecorder) Exchange(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockWalletService)(nil).Exchange), ctx, req)
+}
+
+// GetBalance mocks base method.
+func (m *MockWalletService) GetBalance(ctx context.Context, req *wallet.GetBalanceRequest) (*wallet.BalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, req)
+	ret0, _ := ret[0].(*wallet.BalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockWalletServiceMockRecorder) GetBalance(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockWalletService)(nil).GetBalance), ctx, req)
+}
+
+// Withdraw mocks base method.
+func (m *MockWalletService) Withdraw(ctx context.Context, req *wallet.WithdrawRequest) (*wallet.BalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, req)
+	ret0, _ := ret[0].(*wallet.BalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockWalletServiceMockRecorder) Withdraw(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockWalletService)(nil).Withdraw), ctx, req)
+}
+
+// MockExchangeRatesReader is a mock of ExchangeRatesReader interface.
+type MockExchangeRatesReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRatesReaderMockRecorder
+}
+
+// MockExchangeRatesReaderMockRecorder is the mock recorder for MockExchangeRatesReader.
+type MockExchangeRatesReaderMockRecorder struct {
+	mock *MockExchangeRatesReader
+}
+
+// NewMockExchangeRatesReader creates a new mock instance.
+func NewMockExchangeRatesReader(ctrl *gomock.Controller) *MockExchangeRatesReader {
+	mock := &MockExchangeRatesReader{ctrl: ctrl}
+	mock.recorder = &MockExchangeRatesReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRatesReader) EXPECT() *MockExchangeRatesReaderMockRecorder {
+	return m.recorder
+}
+
+// GetExchangeRates mocks base method.
+func (m *MockExchangeRatesReader) GetExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRates", ctx)
+	ret0, _ := ret[0].(map[string]float32)
+	ret1, _ := ret[1].(models.RateSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetExchangeRates indicates an expected call of GetExchangeRates.
+func (mr *MockExchangeRatesReaderMockRecorder) GetExchangeRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRates", reflect.TypeOf((*MockExchangeRatesReader)(nil).GetExchangeRates), ctx)
+}
+
+// MockTransactionLister is a mock of TransactionLister interface.
+type MockTransactionLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionListerMockRecorder
+}
+
+// MockTransactionListerMockRecorder is the mock recorder for MockTransactionLister.
+type MockTransactionListerMockRecorder struct {
+	mock *MockTransactionLister
+}
+
+// NewMockTransactionLister creates a new mock instance.
+func NewMockTransactionLister(ctrl *gomock.Controller) *MockTransactionLister {
+	mock := &MockTransactionLister{ctrl: ctrl}
+	mock.recorder = &MockTransactionListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionLister) EXPECT() *MockTransactionListerMockRecorder {
+	return m.recorder
+}
+
+// ListTransactions mocks base method.
+func (m *MockTransactionLister) ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, filter, cursor)
+	ret0, _ := ret[0].([]models.TransactionDB)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionListerMockRecorder) ListTransactions(ctx, filter, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransactionLister)(nil).ListTransactions), ctx, filter, cursor)
+}
+
+// MockCurrencyChecker is a mock of CurrencyChecker interface.
+type MockCurrencyChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyCheckerMockRecorder
+}
+
+// MockCurrencyCheckerMockRecorder is the mock recorder for MockCurrencyChecker.
+type MockCurrencyCheckerMockRecorder struct {
+	mock *MockCurrencyChecker
+}
+
+// NewMockCurrencyChecker creates a new mock instance.
+func NewMockCurrencyChecker(ctrl *gomock.Controller) *MockCurrencyChecker {
+	mock := &MockCurrencyChecker{ctrl: ctrl}
+	mock.recorder = &MockCurrencyCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyChecker) EXPECT() *MockCurrencyCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsSupported mocks base method.
+func (m *MockCurrencyChecker) IsSupported(ctx context.Context, code string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSupported", ctx, code)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSupported indicates an expected call of IsSupported.
+func (mr *MockCurrencyCheckerMockRecorder) IsSupported(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSupported", reflect.TypeOf((*MockCurrencyChecker)(nil).IsSupported), ctx, code)
+}
+
+// MockTokener is a mock of Tokener interface.
+type MockTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenerMockRecorder
+}
+
+// MockTokenerMockRecorder is the mock recorder for MockTokener.
+type MockTokenerMockRecorder struct {
+	mock *MockTokener
+}
+
+// NewMockTokener creates a new mock instance.
+func NewMockTokener(ctrl *gomock.Controller) *MockTokener {
+	mock := &MockTokener{ctrl: ctrl}
+	mock.recorder = &MockTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokener) EXPECT() *MockTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockTokener)(nil).GetTokenFromRequest), ctx, r)
+}
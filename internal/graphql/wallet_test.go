@@ -0,0 +1,244 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+)
+
+type walletMocks struct {
+	wallet     *MockWalletService
+	rates      *MockExchangeRatesReader
+	history    *MockTransactionLister
+	currencies *MockCurrencyChecker
+}
+
+func TestWalletHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	txID := uuid.New()
+	validToken := "valid-token"
+	fetchedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	eur := "EUR"
+	toAmount := money.Amount(9200)
+
+	tests := []struct {
+		name               string
+		body               string
+		setupMocks         func(m walletMocks)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "balance",
+			body: `{"query":"{ balance { currency amount } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.wallet.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ *pb.GetBalanceRequest) (*pb.BalanceResponse, error) {
+						assert.Equal(t, []string{"Bearer " + validToken}, metadata.ValueFromIncomingContext(ctx, "authorization"))
+						return &pb.BalanceResponse{Balances: map[string]string{"USD": "10.00", "EUR": "5.50"}}, nil
+					})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"balance":[{"currency":"EUR","amount":"5.50"},{"currency":"USD","amount":"10.00"}]}}`,
+		},
+		{
+			name: "rates",
+			body: `{"query":"{ rates { rates { currency rate } staleRate fetchedAt provider } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.rates.EXPECT().
+					GetExchangeRates(gomock.Any()).
+					Return(map[string]float32{"USD": 1, "EUR": 0.5}, models.RateSource{Provider: "grpc", FetchedAt: fetchedAt, Stale: true}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"rates":{"rates":[{"currency":"EUR","rate":0.5},{"currency":"USD","rate":1}],"staleRate":true,"fetchedAt":"2024-01-02T03:04:05Z","provider":"grpc"}}}`,
+		},
+		{
+			name: "rates unavailable",
+			body: `{"query":"{ rates { provider } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.rates.EXPECT().
+					GetExchangeRates(gomock.Any()).
+					Return(nil, models.RateSource{}, services.ErrExchangerUnavailable)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"rates":null},"errors":[{"message":"Exchange service unavailable","path":["rates"],"extensions":{"code":"exchanger_unavailable"}}]}`,
+		},
+		{
+			name: "transactions",
+			body: `{"query":"query($limit: Int) { transactions(currency: \"USD\", operation: \"exchange\", from: \"2024-01-01T00:00:00Z\", limit: $limit) { transactions { transactionId amount toCurrency toAmount reference } nextCursor } }","variables":{"limit":2}}`,
+			setupMocks: func(m walletMocks) {
+				m.currencies.EXPECT().IsSupported(gomock.Any(), "USD").Return(true)
+				m.history.EXPECT().
+					ListTransactions(gomock.Any(), models.TransactionFilter{
+						UserID:    userID,
+						Currency:  "USD",
+						Operation: models.OperationExchange,
+						From:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						Limit:     2,
+					}, "").
+					Return([]models.TransactionDB{{
+						TransactionID: txID,
+						Operation:     models.OperationExchange,
+						Currency:      "USD",
+						Amount:        money.Amount(10000),
+						ToCurrency:    &eur,
+						ToAmount:      &toAmount,
+						CreatedAt:     fetchedAt,
+					}}, "next", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"transactions":{"transactions":[{"transactionId":"` + txID.String() + `","amount":"100.00","toCurrency":"EUR","toAmount":"92.00","reference":null}],"nextCursor":"next"}}}`,
+		},
+		{
+			name:               "transactions invalid operation",
+			body:               `{"query":"{ transactions(operation: \"gift\") { nextCursor } }"}`,
+			setupMocks:         func(m walletMocks) {},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"transactions":null},"errors":[{"message":"Invalid operation","path":["transactions"],"extensions":{"code":"invalid_operation"}}]}`,
+		},
+		{
+			name: "transactions invalid cursor",
+			body: `{"query":"{ transactions(cursor: \"bad\") { nextCursor } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.history.EXPECT().
+					ListTransactions(gomock.Any(), models.TransactionFilter{UserID: userID}, "bad").
+					Return(nil, "", services.ErrInvalidCursor)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"transactions":null},"errors":[{"message":"Invalid cursor","path":["transactions"],"extensions":{"code":"invalid_cursor"}}]}`,
+		},
+		{
+			name: "deposit",
+			body: `{"query":"mutation($amount: Decimal!) { deposit(amount: $amount, currency: \"USD\", reference: \"INV-1\") { currency amount } }","variables":{"amount":100.5}}`,
+			setupMocks: func(m walletMocks) {
+				m.wallet.EXPECT().
+					Deposit(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *pb.DepositRequest) (*pb.BalanceResponse, error) {
+						assert.Equal(t, "100.5", req.GetAmount())
+						assert.Equal(t, "USD", req.GetCurrency())
+						assert.Equal(t, "INV-1", req.GetReference())
+						return &pb.BalanceResponse{Balances: map[string]string{"USD": "100.50"}}, nil
+					})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"deposit":[{"currency":"USD","amount":"100.50"}]}}`,
+		},
+		{
+			name: "withdraw declined",
+			body: `{"query":"mutation { withdraw(amount: \"500\", currency: \"USD\") { amount } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.wallet.EXPECT().
+					Withdraw(gomock.Any(), gomock.Any()).
+					Return(nil, status.Error(codes.PermissionDenied, "Daily limit exceeded"))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"withdraw":null},"errors":[{"message":"Daily limit exceeded","path":["withdraw"],"extensions":{"code":"daily_limit_exceeded"}}]}`,
+		},
+		{
+			name: "exchange",
+			body: `{"query":"mutation { exchange(fromCurrency: \"USD\", toCurrency: \"EUR\", amount: \"100\", minExpectedAmount: 90) { exchangedAmount fee rate balances { currency } } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.wallet.EXPECT().
+					Exchange(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, req *pb.ExchangeRequest) (*pb.ExchangeResponse, error) {
+						assert.Equal(t, "USD", req.GetFromCurrency())
+						assert.Equal(t, "EUR", req.GetToCurrency())
+						assert.Equal(t, "100", req.GetAmount())
+						assert.Equal(t, "90", req.GetMinExpectedAmount())
+						assert.Equal(t, "", req.GetQuoteId())
+						return &pb.ExchangeResponse{
+							ExchangedAmount: "92.00", Fee: "0.50", Rate: 0.92,
+							Balances: map[string]string{"USD": "0.00", "EUR": "92.00"},
+						}, nil
+					})
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"exchange":{"exchangedAmount":"92.00","fee":"0.50","rate":0.92,"balances":[{"currency":"EUR"},{"currency":"USD"}]}}}`,
+		},
+		{
+			name: "unknown error",
+			body: `{"query":"{ balance { amount } }"}`,
+			setupMocks: func(m walletMocks) {
+				m.wallet.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("connection reset"))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"data":{"balance":null},"errors":[{"message":"Internal server error","path":["balance"],"extensions":{"code":"internal"}}]}`,
+		},
+		{
+			name:               "invalid query",
+			body:               `{"query":"{ balance { owner } }"}`,
+			setupMocks:         func(m walletMocks) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"errors":[{"message":"cannot query field \"owner\" on type Balance","extensions":{"code":"graphql_validation_failed"}}]}`,
+		},
+		{
+			name:               "invalid body",
+			body:               `{"query":`,
+			setupMocks:         func(m walletMocks) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Invalid request body","code":"invalid_request_body"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := walletMocks{
+				wallet:     NewMockWalletService(ctrl),
+				rates:      NewMockExchangeRatesReader(ctrl),
+				history:    NewMockTransactionLister(ctrl),
+				currencies: NewMockCurrencyChecker(ctrl),
+			}
+			tokener := NewMockTokener(ctrl)
+			tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(validToken, nil)
+			tokener.EXPECT().GetClaims(gomock.Any(), validToken).Return(&jwt.Claims{UserID: userID}, nil)
+			tt.setupMocks(m)
+
+			handler := NewHandler(NewWalletSchema(m.wallet, m.rates, m.history, m.currencies), tokener)
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatusCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestWalletHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tokener := NewMockTokener(ctrl)
+	tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+
+	schema := NewWalletSchema(NewMockWalletService(ctrl), NewMockExchangeRatesReader(ctrl), NewMockTransactionLister(ctrl), NewMockCurrencyChecker(ctrl))
+	rec := httptest.NewRecorder()
+	NewHandler(schema, tokener).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ balance { amount } }"}`)))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"error":"Unauthorized","code":"unauthorized"}`, rec.Body.String())
+}
@@ -0,0 +1,117 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Gateway returns the REST gateway of the wallet API, generated from the google.api.http
+// rules of proto/wallet. It calls srv in-process through interceptors, in order, as the gRPC
+// server does. Fields keep their proto names and errors are rendered as in the REST API,
+// with the code of the catalog entry of the message; the HTTP status is the counterpart of the gRPC code.
+func Gateway(srv pb.WalletServiceServer, interceptors ...grpc.UnaryServerInterceptor) http.Handler {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(gatewayError),
+	)
+	// Registration of a server fails only on an invalid mux, like chi on an invalid pattern
+	if err := pb.RegisterWalletServiceHandlerServer(context.Background(), mux, Intercept(srv, interceptors...)); err != nil {
+		panic(err)
+	}
+	return mux
+}
+
+// gatewayError renders the status of a failed call as the error body of the REST API.
+func gatewayError(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	st := status.Convert(err)
+	e, ok := apperrors.ByMessage(st.Message())
+	if !ok {
+		e = apperrors.Internal
+		e.Message = st.Message()
+	}
+	// The status follows the gRPC code, which may be coarser than the one of the entry
+	e.Status = runtime.HTTPStatusFromCode(st.Code())
+	apperrors.Write(w, e)
+}
+
+// interceptedServer calls the methods of a WalletServiceServer through a unary interceptor.
+type interceptedServer struct {
+	pb.UnimplementedWalletServiceServer
+
+	srv         pb.WalletServiceServer
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// Intercept returns srv with its methods called through interceptors, outermost first, for
+// callers that bypass the gRPC server, such as the gateway.
+func Intercept(srv pb.WalletServiceServer, interceptors ...grpc.UnaryServerInterceptor) pb.WalletServiceServer {
+	return &interceptedServer{srv: srv, interceptor: chainInterceptors(interceptors)}
+}
+
+// chainInterceptors combines interceptors into one, the first being the outermost.
+func chainInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// invoke calls method of the server through the interceptor.
+func invoke[Req, Resp any](s *interceptedServer, ctx context.Context, method string, req Req,
+	call func(context.Context, Req) (Resp, error)) (Resp, error) {
+	info := &grpc.UnaryServerInfo{Server: s.srv, FullMethod: method}
+	resp, err := s.interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return call(ctx, req.(Req))
+	})
+	if err != nil {
+		var zero Resp
+		return zero, err
+	}
+	return resp.(Resp), nil
+}
+
+// Register creates a user.
+func (s *interceptedServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	return invoke(s, ctx, pb.WalletService_Register_FullMethodName, req, s.srv.Register)
+}
+
+// Login returns a JWT of the user.
+func (s *interceptedServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	return invoke(s, ctx, pb.WalletService_Login_FullMethodName, req, s.srv.Login)
+}
+
+// GetBalance returns the balances of the user.
+func (s *interceptedServer) GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (*pb.BalanceResponse, error) {
+	return invoke(s, ctx, pb.WalletService_GetBalance_FullMethodName, req, s.srv.GetBalance)
+}
+
+// Deposit credits the wallet of the currency.
+func (s *interceptedServer) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.BalanceResponse, error) {
+	return invoke(s, ctx, pb.WalletService_Deposit_FullMethodName, req, s.srv.Deposit)
+}
+
+// Withdraw debits the wallet of the currency.
+func (s *interceptedServer) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.BalanceResponse, error) {
+	return invoke(s, ctx, pb.WalletService_Withdraw_FullMethodName, req, s.srv.Withdraw)
+}
+
+// Exchange converts an amount between two wallets of the user.
+func (s *interceptedServer) Exchange(ctx context.Context, req *pb.ExchangeRequest) (*pb.ExchangeResponse, error) {
+	return invoke(s, ctx, pb.WalletService_Exchange_FullMethodName, req, s.srv.Exchange)
+}
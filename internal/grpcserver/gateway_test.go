@@ -0,0 +1,125 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestGateway(t *testing.T) {
+	userID := uuid.New()
+	amount := money.MustParse("50")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		token      string
+		mockSetup  func(m walletMocks, parser *MockClaimsParser)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "login",
+			method: http.MethodPost,
+			path:   "/v2/login",
+			body:   `{"username":"alice","password":"secret"}`,
+			mockSetup: func(m walletMocks, parser *MockClaimsParser) {
+				client := models.ClientInfo{IP: "203.0.113.7", UserAgent: "test-agent"}
+				m.auth.EXPECT().Login(gomock.Any(), "alice", "secret", client).Return("token", nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"token":"token"}`,
+		},
+		{
+			name:   "deposit",
+			method: http.MethodPost,
+			path:   "/v2/wallet/deposit",
+			body:   `{"amount":"50","currency":"USD","reference":"INV-1"}`,
+			token:  "valid",
+			mockSetup: func(m walletMocks, parser *MockClaimsParser) {
+				parser.EXPECT().GetClaims(gomock.Any(), "valid").Return(&jwt.Claims{UserID: userID}, nil)
+				m.currencies.EXPECT().IsSupported(gomock.Any(), "USD").Return(true)
+				m.currencies.EXPECT().ValidAmount(gomock.Any(), "USD", amount).Return(true)
+				m.wallet.EXPECT().Deposit(gomock.Any(), userID, amount, "USD", "INV-1").
+					Return(map[string]money.Amount{"USD": amount}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"balances":{"USD":"` + amount.String() + `"}}`,
+		},
+		{
+			name:       "unauthenticated",
+			method:     http.MethodGet,
+			path:       "/v2/balance",
+			mockSetup:  func(m walletMocks, parser *MockClaimsParser) {},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `{"error":"Unauthorized","code":"unauthorized"}`,
+		},
+		{
+			name:   "service_error",
+			method: http.MethodGet,
+			path:   "/v2/balance",
+			token:  "valid",
+			mockSetup: func(m walletMocks, parser *MockClaimsParser) {
+				parser.EXPECT().GetClaims(gomock.Any(), "valid").Return(&jwt.Claims{UserID: userID}, nil)
+				m.wallet.EXPECT().GetUserBalance(gomock.Any(), userID).Return(nil, errors.New("db down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"Internal server error","code":"internal"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newTestWalletServer(t)
+			parser := NewMockClaimsParser(gomock.NewController(t))
+			tt.mockSetup(m, parser)
+			gateway := Gateway(s, AuthInterceptor(parser))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.RemoteAddr = "203.0.113.7:5555"
+			req.Header.Set("User-Agent", "test-agent")
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			gateway.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestIntercept_Order(t *testing.T) {
+	s, m := newTestWalletServer(t)
+	m.wallet.EXPECT().GetUserBalance(gomock.Any(), gomock.Any()).Return(map[string]money.Amount{}, nil)
+
+	var calls []string
+	record := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			calls = append(calls, name+" "+info.FullMethod)
+			return handler(ctx, req)
+		}
+	}
+
+	_, err := Intercept(s, record("outer"), record("inner")).GetBalance(authenticated(uuid.New()), &pb.GetBalanceRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"outer " + pb.WalletService_GetBalance_FullMethodName,
+		"inner " + pb.WalletService_GetBalance_FullMethodName,
+	}, calls)
+}
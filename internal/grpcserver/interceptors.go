@@ -0,0 +1,148 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/middlewares"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods are callable without a token.
+var publicMethods = map[string]bool{
+	pb.WalletService_Register_FullMethodName: true,
+	pb.WalletService_Login_FullMethodName:    true,
+}
+
+// moneyMethods move money: they are refused to dormant accounts and run one at a time per user.
+var moneyMethods = map[string]bool{
+	pb.WalletService_Deposit_FullMethodName:  true,
+	pb.WalletService_Withdraw_FullMethodName: true,
+	pb.WalletService_Exchange_FullMethodName: true,
+}
+
+// ClaimsParser defines the interface for reading the claims of a token.
+type ClaimsParser interface {
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// DormancyChecker reports whether an account is in cold storage.
+type DormancyChecker interface {
+	IsDormant(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// claimsKey is the context key of the claims of the authenticated call.
+type claimsKey struct{}
+
+// claimsFromContext returns the claims stored by AuthInterceptor, nil for public methods.
+func claimsFromContext(ctx context.Context) *jwt.Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*jwt.Claims)
+	return claims
+}
+
+// LoggingInterceptor logs every call with its code and duration and turns a panic of the
+// handler into Internal, like the HTTP logging and recoverer middlewares.
+func LoggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.FromContext(ctx).Errorw("gRPC handler panicked", "method", info.FullMethod, "panic", rec)
+			resp, err = nil, status.Error(codes.Internal, "Internal server error")
+		}
+		logger.FromContext(ctx).Infow("gRPC call",
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"duration", time.Since(start),
+		)
+	}()
+	return handler(ctx, req)
+}
+
+// AuthInterceptor returns an interceptor requiring a valid token in the "authorization: Bearer"
+// metadata of every method except the public ones, and storing its claims in the context.
+func AuthInterceptor(parser ClaimsParser) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		tokenString, err := tokenFromMetadata(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("authorization failed", "method", info.FullMethod, "err", err)
+			return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+		}
+		claims, err := parser.GetClaims(ctx, tokenString)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("authorization failed", "method", info.FullMethod, "err", err)
+			return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+		}
+
+		return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+	}
+}
+
+// MoneyInterceptor returns an interceptor that refuses money-moving methods to dormant accounts
+// and serializes them per user through locker, as the dormant and user lock middlewares do.
+// It must run after AuthInterceptor.
+func MoneyInterceptor(checker DormancyChecker, locker middlewares.UserLocker, ttl, wait time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !moneyMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		claims := claimsFromContext(ctx)
+		if claims == nil {
+			return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+		}
+
+		dormant, err := checker.IsDormant(ctx, claims.UserID)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("dormancy check failed", "userID", claims.UserID, "err", err)
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+		if dormant {
+			logger.FromContext(ctx).Warnw("dormant account access denied", "userID", claims.UserID)
+			return nil, status.Error(codes.PermissionDenied, "Account is dormant, re-verification required")
+		}
+
+		release, err := middlewares.AcquireUserLock(ctx, locker, "user:"+claims.UserID.String(), ttl, wait)
+		if errors.Is(err, locks.ErrNotAcquired) {
+			logger.FromContext(ctx).Warnw("concurrent money operation rejected", "userID", claims.UserID)
+			return nil, status.Error(codes.Aborted, "Another operation is in progress")
+		}
+		if err != nil {
+			logger.FromContext(ctx).Errorw("user lock failed", "userID", claims.UserID, "err", err)
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+		defer func() {
+			// The call context may be cancelled by now, the lock must be released anyway
+			if err := release(context.WithoutCancel(ctx)); err != nil {
+				logger.FromContext(ctx).Errorw("failed to release user lock", "userID", claims.UserID, "err", err)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// tokenFromMetadata extracts the token from the authorization metadata of the call.
+func tokenFromMetadata(ctx context.Context) (string, error) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", errors.New("authorization metadata missing")
+	}
+	parts := strings.Fields(values[0])
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", errors.New("invalid authorization metadata format")
+	}
+	return parts[1], nil
+}
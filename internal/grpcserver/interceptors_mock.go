@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/grpcserver/interceptors.go
+
+// Package grpcserver is a generated GoMock package.
+package grpcserver
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+)
+
+// MockClaimsParser is a mock of ClaimsParser interface.
+type MockClaimsParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockClaimsParserMockRecorder
+}
+
+// MockClaimsParserMockRecorder is the mock recorder for MockClaimsParser.
+type MockClaimsParserMockRecorder struct {
+	mock *MockClaimsParser
+}
+
+// NewMockClaimsParser creates a new mock instance.
+func NewMockClaimsParser(ctrl *gomock.Controller) *MockClaimsParser {
+	mock := &MockClaimsParser{ctrl: ctrl}
+	mock.recorder = &MockClaimsParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClaimsParser) EXPECT() *MockClaimsParserMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockClaimsParser) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockClaimsParserMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockClaimsParser)(nil).GetClaims), ctx, tokenString)
+}
+
+// MockDormancyChecker is a mock of DormancyChecker interface.
+type MockDormancyChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockDormancyCheckerMockRecorder
+}
+
+// MockDormancyCheckerMockRecorder is the mock recorder for MockDormancyChecker.
+type MockDormancyCheckerMockRecorder struct {
+	mock *MockDormancyChecker
+}
+
+// NewMockDormancyChecker creates a new mock instance.
+func NewMockDormancyChecker(ctrl *gomock.Controller) *MockDormancyChecker {
+	mock := &MockDormancyChecker{ctrl: ctrl}
+	mock.recorder = &MockDormancyCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDormancyChecker) EXPECT() *MockDormancyCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsDormant mocks base method.
+func (m *MockDormancyChecker) IsDormant(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDormant", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDormant indicates an expected call of IsDormant.
+func (mr *MockDormancyCheckerMockRecorder) IsDormant(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDormant", reflect.TypeOf((*MockDormancyChecker)(nil).IsDormant), ctx, userID)
+}
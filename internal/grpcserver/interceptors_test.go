@@ -0,0 +1,151 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/middlewares"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func info(method string) *grpc.UnaryServerInfo {
+	return &grpc.UnaryServerInfo{FullMethod: method}
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	resp, err := LoggingInterceptor(context.Background(), nil, info(pb.WalletService_GetBalance_FullMethodName),
+		func(ctx context.Context, req any) (any, error) { return "ok", nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = LoggingInterceptor(context.Background(), nil, info(pb.WalletService_GetBalance_FullMethodName),
+		func(ctx context.Context, req any) (any, error) { panic("boom") })
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parser := NewMockClaimsParser(ctrl)
+	interceptor := AuthInterceptor(parser)
+	userID := uuid.New()
+
+	var seen *jwt.Claims
+	handler := func(ctx context.Context, req any) (any, error) {
+		seen = claimsFromContext(ctx)
+		return nil, nil
+	}
+	withToken := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", value))
+	}
+
+	t.Run("public_method", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, info(pb.WalletService_Login_FullMethodName), handler)
+		assert.NoError(t, err)
+		assert.Nil(t, seen)
+	})
+
+	t.Run("missing_token", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, info(pb.WalletService_GetBalance_FullMethodName), handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("malformed_token", func(t *testing.T) {
+		_, err := interceptor(withToken("Basic abc"), nil, info(pb.WalletService_GetBalance_FullMethodName), handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		ctx := withToken("Bearer expired")
+		parser.EXPECT().GetClaims(ctx, "expired").Return(nil, errors.New("token is expired"))
+		_, err := interceptor(ctx, nil, info(pb.WalletService_GetBalance_FullMethodName), handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("valid_token", func(t *testing.T) {
+		ctx := withToken("Bearer good")
+		parser.EXPECT().GetClaims(ctx, "good").Return(&jwt.Claims{UserID: userID}, nil)
+		_, err := interceptor(ctx, nil, info(pb.WalletService_GetBalance_FullMethodName), handler)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, seen.UserID)
+	})
+}
+
+func TestMoneyInterceptor(t *testing.T) {
+	userID := uuid.New()
+	ctx := authenticated(userID)
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		mockSetup func(checker *MockDormancyChecker, locker *middlewares.MockUserLocker)
+		code      codes.Code
+		called    bool
+	}{
+		{
+			name:      "not_money_method",
+			method:    pb.WalletService_GetBalance_FullMethodName,
+			mockSetup: func(checker *MockDormancyChecker, locker *middlewares.MockUserLocker) {},
+			code:      codes.OK,
+			called:    true,
+		},
+		{
+			name:   "locked_and_released",
+			method: pb.WalletService_Deposit_FullMethodName,
+			mockSetup: func(checker *MockDormancyChecker, locker *middlewares.MockUserLocker) {
+				checker.EXPECT().IsDormant(ctx, userID).Return(false, nil)
+				locker.EXPECT().TryLock(ctx, "user:"+userID.String(), time.Second).
+					Return(func(context.Context) error { return nil }, nil)
+			},
+			code:   codes.OK,
+			called: true,
+		},
+		{
+			name:   "dormant",
+			method: pb.WalletService_Withdraw_FullMethodName,
+			mockSetup: func(checker *MockDormancyChecker, locker *middlewares.MockUserLocker) {
+				checker.EXPECT().IsDormant(ctx, userID).Return(true, nil)
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name:   "busy",
+			method: pb.WalletService_Exchange_FullMethodName,
+			mockSetup: func(checker *MockDormancyChecker, locker *middlewares.MockUserLocker) {
+				checker.EXPECT().IsDormant(ctx, userID).Return(false, nil)
+				locker.EXPECT().TryLock(ctx, "user:"+userID.String(), time.Second).Return(nil, locks.ErrNotAcquired)
+			},
+			code: codes.Aborted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			checker := NewMockDormancyChecker(ctrl)
+			locker := middlewares.NewMockUserLocker(ctrl)
+			tt.mockSetup(checker, locker)
+			called = false
+
+			// No wait, so a busy lock is rejected at once
+			_, err := MoneyInterceptor(checker, locker, time.Second, 0)(ctx, nil, info(tt.method), handler)
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.called, called)
+		})
+	}
+}
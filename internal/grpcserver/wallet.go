@@ -0,0 +1,283 @@
+// Package grpcserver serves the wallet API over gRPC to internal services, backed by the
+// same services as the REST API.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Auth defines the registration and login methods of the server.
+type Auth interface {
+	Register(ctx context.Context, username, password, email string) error
+	Login(ctx context.Context, username, password string, client models.ClientInfo) (string, error)
+}
+
+// RegistrationPolicy decides whether a registration with the given email is allowed.
+type RegistrationPolicy interface {
+	Check(ctx context.Context, email string) error
+}
+
+// Wallet defines the wallet methods of the server.
+type Wallet interface {
+	GetUserBalance(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error)
+	Deposit(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error)
+	Withdraw(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error)
+	Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (executed models.ExchangeQuote, balances map[string]money.Amount, err error)
+	ExchangeQuoted(ctx context.Context, userID, quoteID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (executed models.ExchangeQuote, balances map[string]money.Amount, err error)
+}
+
+// CurrencyChecker validates currency codes and amounts against the supported currencies.
+type CurrencyChecker interface {
+	IsSupported(ctx context.Context, code string) bool
+	ValidAmount(ctx context.Context, code string, amount money.Amount) bool
+}
+
+// WalletServer implements the WalletService of proto/wallet. Error messages match the
+// REST API; the status codes are the gRPC counterparts of its HTTP statuses.
+type WalletServer struct {
+	pb.UnimplementedWalletServiceServer
+
+	auth       Auth
+	policy     RegistrationPolicy
+	wallet     Wallet
+	currencies CurrencyChecker
+}
+
+// NewWalletServer creates a new WalletServer. A nil policy allows every registration.
+func NewWalletServer(auth Auth, policy RegistrationPolicy, wallet Wallet, currencies CurrencyChecker) *WalletServer {
+	return &WalletServer{auth: auth, policy: policy, wallet: wallet, currencies: currencies}
+}
+
+// Register creates a user.
+func (s *WalletServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	if s.policy != nil {
+		if err := s.policy.Check(ctx, req.GetEmail()); err != nil {
+			switch {
+			case errors.Is(err, services.ErrEmailDomainNotAllowed):
+				return nil, status.Error(codes.PermissionDenied, "Email domain is not allowed")
+			case errors.Is(err, services.ErrRegistrationRateLimited):
+				return nil, status.Error(codes.ResourceExhausted, "Too many registrations from this email domain")
+			default:
+				logger.FromContext(ctx).Errorw("failed to check registration policy", "email", req.GetEmail(), "error", err)
+				return nil, status.Error(codes.Internal, "Internal server error")
+			}
+		}
+	}
+
+	if err := s.auth.Register(ctx, req.GetUsername(), req.GetPassword(), req.GetEmail()); err != nil {
+		if errors.Is(err, services.ErrUserAlreadyExists) {
+			return nil, status.Error(codes.AlreadyExists, "Username or email already exists")
+		}
+		logger.FromContext(ctx).Errorw("internal server error during registration", "username", req.GetUsername(), "error", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+	return &pb.RegisterResponse{}, nil
+}
+
+// Login returns a JWT of the user.
+func (s *WalletServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	token, err := s.auth.Login(ctx, req.GetUsername(), req.GetPassword(), clientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrUserDoesNotExist) {
+			return nil, status.Error(codes.Unauthenticated, "Invalid username or password")
+		}
+		logger.FromContext(ctx).Errorw("internal server error during login", "username", req.GetUsername(), "error", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+	return &pb.LoginResponse{Token: token}, nil
+}
+
+// GetBalance returns the balances of the user.
+func (s *WalletServer) GetBalance(ctx context.Context, _ *pb.GetBalanceRequest) (*pb.BalanceResponse, error) {
+	userID := claimsFromContext(ctx).UserID
+	balances, err := s.wallet.GetUserBalance(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balance", "userID", userID, "error", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+	return &pb.BalanceResponse{Balances: renderBalances(balances)}, nil
+}
+
+// Deposit credits the wallet of the currency.
+func (s *WalletServer) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.BalanceResponse, error) {
+	userID := claimsFromContext(ctx).UserID
+	amount, err := s.validAmount(ctx, req.GetAmount(), req.GetCurrency())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid amount or currency")
+	}
+	if utf8.RuneCountInString(req.GetReference()) > models.MaxReferenceLength {
+		return nil, status.Error(codes.InvalidArgument, "Invalid reference")
+	}
+
+	balances, err := s.wallet.Deposit(ctx, userID, amount, req.GetCurrency(), req.GetReference())
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to deposit funds", "userID", userID, "amount", amount, "currency", req.GetCurrency(), "error", err)
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+	return &pb.BalanceResponse{Balances: renderBalances(balances)}, nil
+}
+
+// Withdraw debits the wallet of the currency.
+func (s *WalletServer) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.BalanceResponse, error) {
+	userID := claimsFromContext(ctx).UserID
+	amount, err := s.validAmount(ctx, req.GetAmount(), req.GetCurrency())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Insufficient funds or invalid amount")
+	}
+	if utf8.RuneCountInString(req.GetReference()) > models.MaxReferenceLength {
+		return nil, status.Error(codes.InvalidArgument, "Invalid reference")
+	}
+
+	balances, err := s.wallet.Withdraw(ctx, userID, amount, req.GetCurrency(), req.GetReference())
+	if err != nil {
+		return nil, walletError(ctx, err, "withdraw", userID)
+	}
+	return &pb.BalanceResponse{Balances: renderBalances(balances)}, nil
+}
+
+// Exchange converts an amount between two wallets of the user, at the current rate or at
+// the rate of a quote.
+func (s *WalletServer) Exchange(ctx context.Context, req *pb.ExchangeRequest) (*pb.ExchangeResponse, error) {
+	userID := claimsFromContext(ctx).UserID
+	invalid := status.Error(codes.InvalidArgument, "Insufficient funds or invalid currencies")
+
+	var amount, minToAmount money.Amount
+	if req.GetAmount() != "" {
+		var err error
+		if amount, err = money.Parse(req.GetAmount()); err != nil {
+			return nil, invalid
+		}
+	}
+	if req.GetMinExpectedAmount() != "" {
+		var err error
+		if minToAmount, err = money.Parse(req.GetMinExpectedAmount()); err != nil || minToAmount < 0 {
+			return nil, invalid
+		}
+	}
+
+	var (
+		executed models.ExchangeQuote
+		balances map[string]money.Amount
+		err      error
+	)
+	if req.GetQuoteId() != "" {
+		quoteID, parseErr := uuid.Parse(req.GetQuoteId())
+		if parseErr != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid quote ID")
+		}
+		executed, balances, err = s.wallet.ExchangeQuoted(ctx, userID, quoteID, req.GetFromCurrency(), req.GetToCurrency(), amount, minToAmount)
+	} else {
+		if !amount.IsPositive() || req.GetFromCurrency() == req.GetToCurrency() ||
+			!s.currencies.IsSupported(ctx, req.GetFromCurrency()) || !s.currencies.IsSupported(ctx, req.GetToCurrency()) ||
+			!s.currencies.ValidAmount(ctx, req.GetFromCurrency(), amount) {
+			return nil, invalid
+		}
+		executed, balances, err = s.wallet.Exchange(ctx, userID, req.GetFromCurrency(), req.GetToCurrency(), amount, minToAmount)
+	}
+	if err != nil {
+		return nil, walletError(ctx, err, "exchange", userID)
+	}
+
+	return &pb.ExchangeResponse{
+		ExchangedAmount: executed.ToAmount.String(),
+		Fee:             executed.Fee.String(),
+		Rate:            executed.Rate,
+		Balances:        renderBalances(balances),
+		StaleRate:       executed.StaleRate,
+		DerivedRate:     executed.DerivedRate,
+	}, nil
+}
+
+// validAmount parses a positive amount valid for a supported currency.
+func (s *WalletServer) validAmount(ctx context.Context, value, currency string) (money.Amount, error) {
+	amount, err := money.Parse(value)
+	if err != nil {
+		return 0, err
+	}
+	if !amount.IsPositive() || !s.currencies.IsSupported(ctx, currency) || !s.currencies.ValidAmount(ctx, currency, amount) {
+		return 0, errors.New("invalid amount or currency")
+	}
+	return amount, nil
+}
+
+// walletError maps an error of a withdrawal or exchange to the status of the REST API's answer.
+func walletError(ctx context.Context, err error, operation string, userID uuid.UUID) error {
+	switch {
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return status.Error(codes.FailedPrecondition, "Insufficient funds")
+	case errors.Is(err, services.ErrDailyLimitExceeded):
+		return status.Error(codes.PermissionDenied, "Daily limit exceeded")
+	case errors.Is(err, services.ErrMonthlyLimitExceeded):
+		return status.Error(codes.PermissionDenied, "Monthly limit exceeded")
+	case errors.Is(err, services.ErrQuoteExpired):
+		return status.Error(codes.FailedPrecondition, "Quote expired")
+	case errors.Is(err, services.ErrQuoteMismatch):
+		return status.Error(codes.InvalidArgument, "Quote does not match the exchange")
+	case errors.Is(err, services.ErrFeeExceedsAmount):
+		return status.Error(codes.InvalidArgument, "Amount does not cover the exchange fee")
+	case errors.Is(err, services.ErrExchangeAmountOutOfRange):
+		return status.Error(codes.InvalidArgument, "Exchange amount out of range")
+	case errors.Is(err, services.ErrSlippageExceeded):
+		return status.Error(codes.Aborted, "Exchange rate moved, amount below min_expected_amount")
+	case errors.Is(err, services.ErrExchangeRateNotFound):
+		return status.Error(codes.NotFound, "Exchange rate not found")
+	case errors.Is(err, services.ErrExchangerUnavailable):
+		return status.Error(codes.Unavailable, "Exchange service unavailable")
+	case errors.Is(err, services.ErrExchangerTimeout):
+		return status.Error(codes.DeadlineExceeded, "Exchange service timeout")
+	default:
+		logger.FromContext(ctx).Errorw("internal server error during "+operation, "userID", userID, "error", err)
+		return status.Error(codes.Internal, "Internal server error")
+	}
+}
+
+// renderBalances formats balances as decimal strings.
+func renderBalances(balances map[string]money.Amount) map[string]string {
+	out := make(map[string]string, len(balances))
+	for currency, amount := range balances {
+		out[currency] = amount.String()
+	}
+	return out
+}
+
+// clientInfo returns the peer IP and the user agent of the call.
+func clientInfo(ctx context.Context) models.ClientInfo {
+	var info models.ClientInfo
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(info.IP); err == nil {
+			info.IP = host
+		}
+	}
+	if ua := metadata.ValueFromIncomingContext(ctx, "user-agent"); len(ua) > 0 {
+		info.UserAgent = ua[0]
+	}
+	// Calls of the gateway have no peer; it forwards the client address and user agent.
+	// The gateway appends the address the router resolved to the client's X-Forwarded-For,
+	// so only the last one is trusted.
+	if info.IP == "" {
+		if xff := metadata.ValueFromIncomingContext(ctx, "x-forwarded-for"); len(xff) > 0 {
+			hops := strings.Split(xff[len(xff)-1], ",")
+			info.IP = strings.TrimSpace(hops[len(hops)-1])
+		}
+		if ua := metadata.ValueFromIncomingContext(ctx, "grpcgateway-user-agent"); len(ua) > 0 {
+			info.UserAgent = ua[0]
+		}
+	}
+	return info
+}
@@ -0,0 +1,255 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/grpcserver/wallet.go
+
+// Package grpcserver is a generated GoMock package.
+package grpcserver
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// Login mocks base method.
+func (m *MockAuth) Login(ctx context.Context, username, password string, client models.ClientInfo) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, username, password, client)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockAuthMockRecorder) Login(ctx, username, password, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuth)(nil).Login), ctx, username, password, client)
+}
+
+// Register mocks base method.
+func (m *MockAuth) Register(ctx context.Context, username, password, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, username, password, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockAuthMockRecorder) Register(ctx, username, password, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuth)(nil).Register), ctx, username, password, email)
+}
+
+// MockRegistrationPolicy is a mock of RegistrationPolicy interface.
+type MockRegistrationPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistrationPolicyMockRecorder
+}
+
+// MockRegistrationPolicyMockRecorder is the mock recorder for MockRegistrationPolicy.
+type MockRegistrationPolicyMockRecorder struct {
+	mock *MockRegistrationPolicy
+}
+
+// NewMockRegistrationPolicy creates a new mock instance.
+func NewMockRegistrationPolicy(ctrl *gomock.Controller) *MockRegistrationPolicy {
+	mock := &MockRegistrationPolicy{ctrl: ctrl}
+	mock.recorder = &MockRegistrationPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistrationPolicy) EXPECT() *MockRegistrationPolicyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockRegistrationPolicy) Check(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockRegistrationPolicyMockRecorder) Check(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockRegistrationPolicy)(nil).Check), ctx, email)
+}
+
+// MockWallet is a mock of Wallet interface.
+type MockWallet struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletMockRecorder
+}
+
+// MockWalletMockRecorder is the mock recorder for MockWallet.
+type MockWalletMockRecorder struct {
+	mock *MockWallet
+}
+
+// NewMockWallet creates a new mock instance.
+func NewMockWallet(ctrl *gomock.Controller) *MockWallet {
+	mock := &MockWallet{ctrl: ctrl}
+	mock.recorder = &MockWalletMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWallet) EXPECT() *MockWalletMockRecorder {
+	return m.recorder
+}
+
+// Deposit mocks base method.
+func (m *MockWallet) Deposit(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount, currency, reference)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deposit indicates an expected call of Deposit.
+func (mr *MockWalletMockRecorder) Deposit(ctx, userID, amount, currency, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockWallet)(nil).Deposit), ctx, userID, amount, currency, reference)
+}
+
+// Exchange mocks base method.
+func (m *MockWallet) Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (models.ExchangeQuote, map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, userID, fromCurrency, toCurrency, amount, minToAmount)
+	ret0, _ := ret[0].(models.ExchangeQuote)
+	ret1, _ := ret[1].(map[string]money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockWalletMockRecorder) Exchange(ctx, userID, fromCurrency, toCurrency, amount, minToAmount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockWallet)(nil).Exchange), ctx, userID, fromCurrency, toCurrency, amount, minToAmount)
+}
+
+// ExchangeQuoted mocks base method.
+func (m *MockWallet) ExchangeQuoted(ctx context.Context, userID, quoteID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (models.ExchangeQuote, map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeQuoted", ctx, userID, quoteID, fromCurrency, toCurrency, amount, minToAmount)
+	ret0, _ := ret[0].(models.ExchangeQuote)
+	ret1, _ := ret[1].(map[string]money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExchangeQuoted indicates an expected call of ExchangeQuoted.
+func (mr *MockWalletMockRecorder) ExchangeQuoted(ctx, userID, quoteID, fromCurrency, toCurrency, amount, minToAmount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeQuoted", reflect.TypeOf((*MockWallet)(nil).ExchangeQuoted), ctx, userID, quoteID, fromCurrency, toCurrency, amount, minToAmount)
+}
+
+// GetUserBalance mocks base method.
+func (m *MockWallet) GetUserBalance(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBalance", ctx, userID)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBalance indicates an expected call of GetUserBalance.
+func (mr *MockWalletMockRecorder) GetUserBalance(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBalance", reflect.TypeOf((*MockWallet)(nil).GetUserBalance), ctx, userID)
+}
+
+// Withdraw mocks base method.
+func (m *MockWallet) Withdraw(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, amount, currency, reference)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockWalletMockRecorder) Withdraw(ctx, userID, amount, currency, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockWallet)(nil).Withdraw), ctx, userID, amount, currency, reference)
+}
+
+// MockCurrencyChecker is a mock of CurrencyChecker interface.
+type MockCurrencyChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyCheckerMockRecorder
+}
+
+// MockCurrencyCheckerMockRecorder is the mock recorder for MockCurrencyChecker.
+type MockCurrencyCheckerMockRecorder struct {
+	mock *MockCurrencyChecker
+}
+
+// NewMockCurrencyChecker creates a new mock instance.
+func NewMockCurrencyChecker(ctrl *gomock.Controller) *MockCurrencyChecker {
+	mock := &MockCurrencyChecker{ctrl: ctrl}
+	mock.recorder = &MockCurrencyCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyChecker) EXPECT() *MockCurrencyCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsSupported mocks base method.
+func (m *MockCurrencyChecker) IsSupported(ctx context.Context, code string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSupported", ctx, code)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSupported indicates an expected call of IsSupported.
+func (mr *MockCurrencyCheckerMockRecorder) IsSupported(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSupported", reflect.TypeOf((*MockCurrencyChecker)(nil).IsSupported), ctx, code)
+}
+
+// ValidAmount mocks base method.
+func (m *MockCurrencyChecker) ValidAmount(ctx context.Context, code string, amount money.Amount) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidAmount", ctx, code, amount)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ValidAmount indicates an expected call of ValidAmount.
+func (mr *MockCurrencyCheckerMockRecorder) ValidAmount(ctx, code, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidAmount", reflect.TypeOf((*MockCurrencyChecker)(nil).ValidAmount), ctx, code, amount)
+}
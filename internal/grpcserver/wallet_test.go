@@ -0,0 +1,256 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	pb "github.com/sbilibin2017/gw-currency-wallet/proto/wallet"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type walletMocks struct {
+	auth       *MockAuth
+	policy     *MockRegistrationPolicy
+	wallet     *MockWallet
+	currencies *MockCurrencyChecker
+}
+
+func newTestWalletServer(t *testing.T) (*WalletServer, walletMocks) {
+	ctrl := gomock.NewController(t)
+	m := walletMocks{
+		auth:       NewMockAuth(ctrl),
+		policy:     NewMockRegistrationPolicy(ctrl),
+		wallet:     NewMockWallet(ctrl),
+		currencies: NewMockCurrencyChecker(ctrl),
+	}
+	return NewWalletServer(m.auth, m.policy, m.wallet, m.currencies), m
+}
+
+// authenticated returns a context carrying the claims AuthInterceptor stores.
+func authenticated(userID uuid.UUID) context.Context {
+	return context.WithValue(context.Background(), claimsKey{}, &jwt.Claims{UserID: userID})
+}
+
+func TestWalletServer_Register(t *testing.T) {
+	ctx := context.Background()
+	req := &pb.RegisterRequest{Username: "alice", Password: "secret", Email: "alice@example.com"}
+
+	tests := []struct {
+		name      string
+		mockSetup func(m walletMocks)
+		code      codes.Code
+	}{
+		{
+			name: "success",
+			mockSetup: func(m walletMocks) {
+				m.policy.EXPECT().Check(ctx, "alice@example.com").Return(nil)
+				m.auth.EXPECT().Register(ctx, "alice", "secret", "alice@example.com").Return(nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name: "domain_not_allowed",
+			mockSetup: func(m walletMocks) {
+				m.policy.EXPECT().Check(ctx, "alice@example.com").Return(services.ErrEmailDomainNotAllowed)
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "user_exists",
+			mockSetup: func(m walletMocks) {
+				m.policy.EXPECT().Check(ctx, "alice@example.com").Return(nil)
+				m.auth.EXPECT().Register(ctx, "alice", "secret", "alice@example.com").Return(services.ErrUserAlreadyExists)
+			},
+			code: codes.AlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newTestWalletServer(t)
+			tt.mockSetup(m)
+
+			_, err := s.Register(ctx, req)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestWalletServer_Login(t *testing.T) {
+	ctx := context.Background()
+	s, m := newTestWalletServer(t)
+
+	m.auth.EXPECT().Login(ctx, "alice", "secret", models.ClientInfo{}).Return("token", nil)
+	resp, err := s.Login(ctx, &pb.LoginRequest{Username: "alice", Password: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, "token", resp.GetToken())
+
+	m.auth.EXPECT().Login(ctx, "alice", "wrong", models.ClientInfo{}).Return("", services.ErrUserDoesNotExist)
+	_, err = s.Login(ctx, &pb.LoginRequest{Username: "alice", Password: "wrong"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Through the gateway, the client's own X-Forwarded-For comes before the resolved address
+	gwCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "198.51.100.1, 203.0.113.7"))
+	m.auth.EXPECT().Login(gwCtx, "alice", "secret", models.ClientInfo{IP: "203.0.113.7"}).Return("token", nil)
+	_, err = s.Login(gwCtx, &pb.LoginRequest{Username: "alice", Password: "secret"})
+	assert.NoError(t, err)
+}
+
+func TestWalletServer_GetBalance(t *testing.T) {
+	userID := uuid.New()
+	ctx := authenticated(userID)
+	s, m := newTestWalletServer(t)
+
+	m.wallet.EXPECT().GetUserBalance(ctx, userID).Return(map[string]money.Amount{"USD": money.MustParse("100.5")}, nil)
+	resp, err := s.GetBalance(ctx, &pb.GetBalanceRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"USD": money.MustParse("100.5").String()}, resp.GetBalances())
+}
+
+func TestWalletServer_Deposit(t *testing.T) {
+	userID := uuid.New()
+	ctx := authenticated(userID)
+	amount := money.MustParse("50")
+
+	tests := []struct {
+		name      string
+		req       *pb.DepositRequest
+		mockSetup func(m walletMocks)
+		code      codes.Code
+	}{
+		{
+			name: "success",
+			req:  &pb.DepositRequest{Amount: "50", Currency: "USD", Reference: "INV-1"},
+			mockSetup: func(m walletMocks) {
+				m.currencies.EXPECT().IsSupported(ctx, "USD").Return(true)
+				m.currencies.EXPECT().ValidAmount(ctx, "USD", amount).Return(true)
+				m.wallet.EXPECT().Deposit(ctx, userID, amount, "USD", "INV-1").Return(map[string]money.Amount{"USD": amount}, nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name:      "invalid_amount",
+			req:       &pb.DepositRequest{Amount: "-5", Currency: "USD"},
+			mockSetup: func(m walletMocks) {},
+			code:      codes.InvalidArgument,
+		},
+		{
+			name: "unsupported_currency",
+			req:  &pb.DepositRequest{Amount: "50", Currency: "XXX"},
+			mockSetup: func(m walletMocks) {
+				m.currencies.EXPECT().IsSupported(ctx, "XXX").Return(false)
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "service_error",
+			req:  &pb.DepositRequest{Amount: "50", Currency: "USD"},
+			mockSetup: func(m walletMocks) {
+				m.currencies.EXPECT().IsSupported(ctx, "USD").Return(true)
+				m.currencies.EXPECT().ValidAmount(ctx, "USD", amount).Return(true)
+				m.wallet.EXPECT().Deposit(ctx, userID, amount, "USD", "").Return(nil, errors.New("db down"))
+			},
+			code: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newTestWalletServer(t)
+			tt.mockSetup(m)
+
+			resp, err := s.Deposit(ctx, tt.req)
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.OK {
+				assert.Equal(t, map[string]string{"USD": amount.String()}, resp.GetBalances())
+			}
+		})
+	}
+}
+
+func TestWalletServer_Withdraw(t *testing.T) {
+	userID := uuid.New()
+	ctx := authenticated(userID)
+	amount := money.MustParse("50")
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "success", code: codes.OK},
+		{name: "insufficient_funds", err: services.ErrInsufficientFunds, code: codes.FailedPrecondition},
+		{name: "daily_limit", err: services.ErrDailyLimitExceeded, code: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, m := newTestWalletServer(t)
+			m.currencies.EXPECT().IsSupported(ctx, "USD").Return(true)
+			m.currencies.EXPECT().ValidAmount(ctx, "USD", amount).Return(true)
+			m.wallet.EXPECT().Withdraw(ctx, userID, amount, "USD", "").Return(map[string]money.Amount{"USD": 0}, tt.err)
+
+			_, err := s.Withdraw(ctx, &pb.WithdrawRequest{Amount: "50", Currency: "USD"})
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestWalletServer_Exchange(t *testing.T) {
+	userID := uuid.New()
+	ctx := authenticated(userID)
+	amount := money.MustParse("100")
+	executed := models.ExchangeQuote{ToAmount: money.MustParse("90"), Fee: money.MustParse("1"), Rate: 0.9}
+	balances := map[string]money.Amount{"USD": 0, "EUR": money.MustParse("90")}
+
+	t.Run("success", func(t *testing.T) {
+		s, m := newTestWalletServer(t)
+		m.currencies.EXPECT().IsSupported(ctx, "USD").Return(true)
+		m.currencies.EXPECT().IsSupported(ctx, "EUR").Return(true)
+		m.currencies.EXPECT().ValidAmount(ctx, "USD", amount).Return(true)
+		m.wallet.EXPECT().Exchange(ctx, userID, "USD", "EUR", amount, money.Amount(0)).Return(executed, balances, nil)
+
+		resp, err := s.Exchange(ctx, &pb.ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: "100"})
+		assert.NoError(t, err)
+		assert.Equal(t, executed.ToAmount.String(), resp.GetExchangedAmount())
+		assert.Equal(t, executed.Fee.String(), resp.GetFee())
+		assert.Equal(t, float32(0.9), resp.GetRate())
+		assert.Equal(t, renderBalances(balances), resp.GetBalances())
+	})
+
+	t.Run("quoted", func(t *testing.T) {
+		s, m := newTestWalletServer(t)
+		quoteID := uuid.New()
+		m.wallet.EXPECT().ExchangeQuoted(ctx, userID, quoteID, "", "", money.Amount(0), money.Amount(0)).Return(models.ExchangeQuote{}, nil, services.ErrQuoteExpired)
+
+		_, err := s.Exchange(ctx, &pb.ExchangeRequest{QuoteId: quoteID.String()})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("same_currency", func(t *testing.T) {
+		s, _ := newTestWalletServer(t)
+		_, err := s.Exchange(ctx, &pb.ExchangeRequest{FromCurrency: "USD", ToCurrency: "USD", Amount: "100"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("exchanger_unavailable", func(t *testing.T) {
+		s, m := newTestWalletServer(t)
+		m.currencies.EXPECT().IsSupported(ctx, "USD").Return(true)
+		m.currencies.EXPECT().IsSupported(ctx, "EUR").Return(true)
+		m.currencies.EXPECT().ValidAmount(ctx, "USD", amount).Return(true)
+		m.wallet.EXPECT().Exchange(ctx, userID, "USD", "EUR", amount, money.Amount(0)).Return(models.ExchangeQuote{}, nil, services.ErrExchangerUnavailable)
+
+		_, err := s.Exchange(ctx, &pb.ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: "100"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
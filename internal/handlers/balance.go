@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 )
 
 // BalanceTokener defines only the methods needed by this handler.
@@ -21,95 +25,115 @@ type Balancer interface {
 	GetUserBalance(
 		ctx context.Context,
 		userID uuid.UUID,
-	) (usd, rub, eur float64, err error)
+	) (map[string]money.Amount, error)
+	GetUserAvailableBalance(
+		ctx context.Context,
+		userID uuid.UUID,
+	) (map[string]money.Amount, error)
+	GetOverdraftLimits(
+		ctx context.Context,
+		userID uuid.UUID,
+	) (map[string]money.Amount, error)
+	GetWalletDetails(
+		ctx context.Context,
+		userID uuid.UUID,
+	) (map[string]models.WalletDetails, error)
 }
 
-// CurrencyBalance represents balances for different currencies
+// CurrencyBalance represents balances keyed by currency code
 // swagger:model CurrencyBalance
-type CurrencyBalance struct {
-	// Balance in USD
-	// default: 100.0
-	USD float64 `json:"USD"`
-
-	// Balance in RUB
-	// default: 5000.0
-	RUB float64 `json:"RUB"`
-
-	// Balance in EUR
-	// default: 50.0
-	EUR float64 `json:"EUR"`
-}
+type CurrencyBalance map[string]money.Amount
 
 // BalanceResponse represents a successful response with user balances
 // swagger:model BalanceResponse
 type BalanceResponse struct {
 	// User balances
-	Balance *CurrencyBalance `json:"balance"`
-}
+	Balance CurrencyBalance `json:"balance" swaggertype:"object,number"`
+
+	// User balances not held by pending holds
+	Available CurrencyBalance `json:"available" swaggertype:"object,number"`
 
-// BalanceErrorResponse represents an error response when fetching balance
-// swagger:model BalanceErrorResponse
-type BalanceErrorResponse struct {
-	// Error message
-	// default: Unauthorized
-	Error string `json:"error"`
+	// Overdraft limits of the wallets that have one: withdrawals may take
+	// the balance down to minus the limit
+	OverdraftLimits CurrencyBalance `json:"overdraft_limits" swaggertype:"object,number"`
+
+	// Labels and metadata of the wallets that have any, keyed by currency
+	Wallets map[string]WalletDetailsEntry `json:"wallets"`
 }
 
 // NewGetBalanceHandler returns an HTTP handler for fetching user balances.
 // @Summary Get user balance
-// @Description Returns balances for all supported currencies
+// @Description Returns total and available balances for all supported currencies. The available balance excludes funds held by pending holds. Overdraft limits are listed for wallets that have one; their balance may be negative down to minus the limit. Wallets with a label or metadata list them under wallets.
 // @Tags wallet
 // @Produce json
+// @Param Api-Version header string false "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)"
 // @Success 200 {object} handlers.BalanceResponse "User balance"
-// @Failure 401 {object} handlers.BalanceErrorResponse "Unauthorized"
-// @Failure 500 {object} handlers.BalanceErrorResponse "Internal server error"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
 // @Router /balance [get]
 // @Security BearerAuth
 func NewGetBalanceHandler(
 	balancer Balancer,
 	tokenGetter BalanceTokener,
+	legacyDeprecation time.Time,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
 		if err != nil {
-			logger.Log.Error("unauthorized balance request: missing or invalid token")
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(BalanceErrorResponse{
-				Error: "Unauthorized",
-			})
+			logger.FromContext(ctx).Error("unauthorized balance request: missing or invalid token")
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
 		if err != nil {
-			logger.Log.Errorw("failed to parse token claims", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(BalanceErrorResponse{
-				Error: "Unauthorized",
-			})
+			logger.FromContext(ctx).Errorw("failed to parse token claims", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
-		usd, rub, eur, err := balancer.GetUserBalance(ctx, claims.UserID)
+		balances, err := balancer.GetUserBalance(ctx, claims.UserID)
 		if err != nil {
-			logger.Log.Errorw("failed to get balance", "userID", claims.UserID, "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(BalanceErrorResponse{
-				Error: "Internal server error",
-			})
+			logger.FromContext(ctx).Errorw("failed to get balance", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		available, err := balancer.GetUserAvailableBalance(ctx, claims.UserID)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get available balance", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		overdrafts, err := balancer.GetOverdraftLimits(ctx, claims.UserID)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get overdraft limits", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		details, err := balancer.GetWalletDetails(ctx, claims.UserID)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get wallet details", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
 			return
 		}
 
 		resp := BalanceResponse{
-			Balance: &CurrencyBalance{
-				USD: usd,
-				RUB: rub,
-				EUR: eur,
-			},
+			Balance:         renderBalances(r, balances),
+			Available:       renderBalances(r, available),
+			OverdraftLimits: overdrafts,
+			Wallets:         make(map[string]WalletDetailsEntry, len(details)),
+		}
+		for currency, d := range details {
+			resp.Wallets[currency] = WalletDetailsEntry(d)
 		}
 
+		setBalanceSchemaHeaders(w, r, legacyDeprecation)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(resp)
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// BalanceEventsHeartbeat is how often an idle balance stream sends a comment, so proxies
+// keep the connection open.
+const BalanceEventsHeartbeat = 15 * time.Second
+
+// BalanceEventsTokener defines only the methods needed by this handler.
+type BalanceEventsTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// BalanceSubscriber subscribes to the balance updates of a user, keyed by user ID.
+type BalanceSubscriber interface {
+	Subscribe(topic string) (<-chan models.BalanceUpdate, func())
+}
+
+// BalanceEvent is the data of a balance event
+// swagger:model BalanceEvent
+type BalanceEvent struct {
+	// Operation that changed the balances
+	TransactionID string `json:"transaction_id"`
+
+	// User balances after the operation, for all supported currencies
+	Balance CurrencyBalance `json:"balance" swaggertype:"object,number"`
+
+	// When the balances changed
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// NewBalanceEventsHandler returns an HTTP handler streaming balance updates as server-sent events.
+// @Summary Stream balance updates
+// @Description Opens a server-sent events stream emitting a "balance" event with the user's new balances after each deposit, withdrawal or exchange; the event id is the transaction ID. Idle streams receive a comment every 15 seconds. Only the changes made on the instance serving the stream are emitted, and a client that falls behind may miss intermediate events; the latest event always carries the full balances.
+// @Tags wallet
+// @Produce text/event-stream
+// @Success 200 {object} handlers.BalanceEvent "Stream of balance events"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Router /events/balance [get]
+// @Security BearerAuth
+func NewBalanceEventsHandler(
+	subscriber BalanceSubscriber,
+	tokenGetter BalanceEventsTokener,
+	heartbeat time.Duration,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Error("unauthorized balance events request: missing or invalid token")
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to parse token claims", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		updates, cancel := subscriber.Subscribe(claims.UserID.String())
+		defer cancel()
+
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			logger.FromContext(ctx).Errorw("failed to flush balance events", "userID", claims.UserID, "error", err)
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-updates:
+				if !ok {
+					// The server is shutting down
+					return
+				}
+				data, err := json.Marshal(BalanceEvent{
+					TransactionID: update.TransactionID.String(),
+					Balance:       update.Balances,
+					ChangedAt:     update.ChangedAt,
+				})
+				if err != nil {
+					logger.FromContext(ctx).Errorw("failed to encode balance event", "userID", claims.UserID, "error", err)
+					return
+				}
+				if _, err := fmt.Fprintf(w, "event: balance\nid: %s\ndata: %s\n\n", update.TransactionID, data); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
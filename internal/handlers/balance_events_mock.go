@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/balance_events.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockBalanceEventsTokener is a mock of BalanceEventsTokener interface.
+type MockBalanceEventsTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceEventsTokenerMockRecorder
+}
+
+// MockBalanceEventsTokenerMockRecorder is the mock recorder for MockBalanceEventsTokener.
+type MockBalanceEventsTokenerMockRecorder struct {
+	mock *MockBalanceEventsTokener
+}
+
+// NewMockBalanceEventsTokener creates a new mock instance.
+func NewMockBalanceEventsTokener(ctrl *gomock.Controller) *MockBalanceEventsTokener {
+	mock := &MockBalanceEventsTokener{ctrl: ctrl}
+	mock.recorder = &MockBalanceEventsTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceEventsTokener) EXPECT() *MockBalanceEventsTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockBalanceEventsTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockBalanceEventsTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockBalanceEventsTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockBalanceEventsTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockBalanceEventsTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockBalanceEventsTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockBalanceSubscriber is a mock of BalanceSubscriber interface.
+type MockBalanceSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceSubscriberMockRecorder
+}
+
+// MockBalanceSubscriberMockRecorder is the mock recorder for MockBalanceSubscriber.
+type MockBalanceSubscriberMockRecorder struct {
+	mock *MockBalanceSubscriber
+}
+
+// NewMockBalanceSubscriber creates a new mock instance.
+func NewMockBalanceSubscriber(ctrl *gomock.Controller) *MockBalanceSubscriber {
+	mock := &MockBalanceSubscriber{ctrl: ctrl}
+	mock.recorder = &MockBalanceSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceSubscriber) EXPECT() *MockBalanceSubscriberMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockBalanceSubscriber) Subscribe(topic string) (<-chan models.BalanceUpdate, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", topic)
+	ret0, _ := ret[0].(<-chan models.BalanceUpdate)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockBalanceSubscriberMockRecorder) Subscribe(topic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockBalanceSubscriber)(nil).Subscribe), topic)
+}
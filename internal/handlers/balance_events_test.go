@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceEventsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	txID := uuid.New()
+	token := "valid-token"
+	changedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mockTokenGetter := NewMockBalanceEventsTokener(ctrl)
+	mockSubscriber := NewMockBalanceSubscriber(ctrl)
+
+	updates := make(chan models.BalanceUpdate, 1)
+	updates <- models.BalanceUpdate{
+		TransactionID: txID,
+		Balances:      map[string]money.Amount{"USD": money.Amount(10050)},
+		ChangedAt:     changedAt,
+	}
+	close(updates)
+	canceled := false
+
+	mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(token, nil)
+	mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).Return(&jwt.Claims{UserID: userID}, nil)
+	mockSubscriber.EXPECT().Subscribe(userID.String()).Return((<-chan models.BalanceUpdate)(updates), func() { canceled = true })
+
+	req := httptest.NewRequest(http.MethodGet, "/events/balance", nil)
+	rec := httptest.NewRecorder()
+	NewBalanceEventsHandler(mockSubscriber, mockTokenGetter, time.Hour).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, ": connected\n\n"+
+		"event: balance\nid: "+txID.String()+"\n"+
+		`data: {"transaction_id":"`+txID.String()+`","balance":{"USD":100.50},"changed_at":"2024-01-02T03:04:05Z"}`+"\n\n",
+		rec.Body.String())
+	assert.True(t, canceled)
+}
+
+func TestBalanceEventsHandler_Heartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	mockTokenGetter := NewMockBalanceEventsTokener(ctrl)
+	mockSubscriber := NewMockBalanceSubscriber(ctrl)
+
+	mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+	mockTokenGetter.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+	mockSubscriber.EXPECT().Subscribe(userID.String()).Return(make(<-chan models.BalanceUpdate), func() {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/events/balance", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	NewBalanceEventsHandler(mockSubscriber, mockTokenGetter, 10*time.Millisecond).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), ": connected\n\n: ping\n\n"))
+}
+
+func TestBalanceEventsHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenGetter := NewMockBalanceEventsTokener(ctrl)
+	mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+
+	rec := httptest.NewRecorder()
+	NewBalanceEventsHandler(NewMockBalanceSubscriber(ctrl), mockTokenGetter, time.Hour).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events/balance", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"error":"Unauthorized","code":"unauthorized"}`, rec.Body.String())
+}
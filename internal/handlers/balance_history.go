@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// BalanceHistoryTokener defines only the methods needed by this handler.
+type BalanceHistoryTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// BalanceHistoryGetter defines the interface that the service must implement.
+type BalanceHistoryGetter interface {
+	History(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.DailyBalance, error)
+}
+
+// BalanceHistoryEntry represents the user's balances at the end of a day
+// swagger:model BalanceHistoryEntry
+type BalanceHistoryEntry struct {
+	// UTC day
+	// default: 2025-03-14
+	Date string `json:"date"`
+
+	// Balances keyed by currency code
+	Balances CurrencyBalance `json:"balances" swaggertype:"object,number"`
+}
+
+// BalanceHistoryResponse represents the daily balance history
+// swagger:model BalanceHistoryResponse
+type BalanceHistoryResponse struct {
+	// Daily balances, oldest first
+	History []BalanceHistoryEntry `json:"history"`
+}
+
+// NewGetBalanceHistoryHandler returns an HTTP handler listing the user's daily balances.
+// @Summary Get balance history
+// @Description Returns the user's balances at the end of each day, oldest first, for charting. Balances are snapshotted daily (UTC); days before the first snapshot are omitted. Defaults to the last 30 days, at most 366 days per request.
+// @Tags wallet
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Success 200 {object} handlers.BalanceHistoryResponse "Balance history"
+// @Failure 400 {object} apperrors.Response "Invalid query parameters"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/balance/history [get]
+// @Security BearerAuth
+func NewGetBalanceHistoryHandler(
+	svc BalanceHistoryGetter,
+	tokenGetter BalanceHistoryTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		invalid := func(e apperrors.Error) {
+			apperrors.Write(w, e)
+		}
+
+		q := r.URL.Query()
+		var from, to time.Time
+		if v := q.Get("from"); v != "" {
+			if from, err = time.Parse(time.DateOnly, v); err != nil {
+				invalid(apperrors.InvalidFrom)
+				return
+			}
+		}
+		if v := q.Get("to"); v != "" {
+			if to, err = time.Parse(time.DateOnly, v); err != nil {
+				invalid(apperrors.InvalidTo)
+				return
+			}
+		}
+
+		history, err := svc.History(ctx, claims.UserID, from, to)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidDateRange) {
+				invalid(apperrors.InvalidDateRange)
+				return
+			}
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := BalanceHistoryResponse{History: make([]BalanceHistoryEntry, 0, len(history))}
+		for _, day := range history {
+			resp.History = append(resp.History, BalanceHistoryEntry{
+				Date:     day.Date.Format(time.DateOnly),
+				Balances: day.Balances,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/balance_history.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockBalanceHistoryTokener is a mock of BalanceHistoryTokener interface.
+type MockBalanceHistoryTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceHistoryTokenerMockRecorder
+}
+
+// MockBalanceHistoryTokenerMockRecorder is the mock recorder for MockBalanceHistoryTokener.
+type MockBalanceHistoryTokenerMockRecorder struct {
+	mock *MockBalanceHistoryTokener
+}
+
+// NewMockBalanceHistoryTokener creates a new mock instance.
+func NewMockBalanceHistoryTokener(ctrl *gomock.Controller) *MockBalanceHistoryTokener {
+	mock := &MockBalanceHistoryTokener{ctrl: ctrl}
+	mock.recorder = &MockBalanceHistoryTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceHistoryTokener) EXPECT() *MockBalanceHistoryTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockBalanceHistoryTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockBalanceHistoryTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockBalanceHistoryTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockBalanceHistoryTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockBalanceHistoryTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockBalanceHistoryTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockBalanceHistoryGetter is a mock of BalanceHistoryGetter interface.
+type MockBalanceHistoryGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceHistoryGetterMockRecorder
+}
+
+// MockBalanceHistoryGetterMockRecorder is the mock recorder for MockBalanceHistoryGetter.
+type MockBalanceHistoryGetterMockRecorder struct {
+	mock *MockBalanceHistoryGetter
+}
+
+// NewMockBalanceHistoryGetter creates a new mock instance.
+func NewMockBalanceHistoryGetter(ctrl *gomock.Controller) *MockBalanceHistoryGetter {
+	mock := &MockBalanceHistoryGetter{ctrl: ctrl}
+	mock.recorder = &MockBalanceHistoryGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceHistoryGetter) EXPECT() *MockBalanceHistoryGetterMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockBalanceHistoryGetter) History(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.DailyBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, userID, from, to)
+	ret0, _ := ret[0].([]models.DailyBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockBalanceHistoryGetterMockRecorder) History(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockBalanceHistoryGetter)(nil).History), ctx, userID, from, to)
+}
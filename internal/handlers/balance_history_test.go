@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBalanceHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockBalanceHistoryTokener(ctrl)
+	mockSvc := NewMockBalanceHistoryGetter(ctrl)
+
+	userID := uuid.New()
+	day1 := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	handler := NewGetBalanceHistoryHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success_with_range",
+			query: "?from=2025-03-13&to=2025-03-14",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), userID, day1, day2).
+					Return([]models.DailyBalance{
+						{Date: day1, Balances: map[string]money.Amount{models.USD: money.MustParse("100")}},
+						{Date: day2, Balances: map[string]money.Amount{models.USD: money.MustParse("70"), models.EUR: money.MustParse("50")}},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: BalanceHistoryResponse{
+				History: []BalanceHistoryEntry{
+					{Date: "2025-03-13", Balances: CurrencyBalance{models.USD: money.MustParse("100")}},
+					{Date: "2025-03-14", Balances: CurrencyBalance{models.USD: money.MustParse("70"), models.EUR: money.MustParse("50")}},
+				},
+			},
+		},
+		{
+			name: "empty_history",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), userID, time.Time{}, time.Time{}).
+					Return([]models.DailyBalance{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   BalanceHistoryResponse{History: []BalanceHistoryEntry{}},
+		},
+		{
+			name:           "invalid_from",
+			query:          "?from=2025-03-13T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid from", Code: "invalid_from"},
+		},
+		{
+			name:           "invalid_to",
+			query:          "?to=today",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid to", Code: "invalid_to"},
+		},
+		{
+			name:  "invalid_date_range",
+			query: "?from=2025-03-14&to=2025-03-13",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), userID, day2, day1).
+					Return(nil, services.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid date range", Code: "invalid_date_range"},
+		},
+		{
+			name: "internal_error",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/wallet/balance/history"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case BalanceHistoryResponse:
+				var got BalanceHistoryResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetBalanceHistoryHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockBalanceHistoryTokener(ctrl)
+	mockSvc := NewMockBalanceHistoryGetter(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewGetBalanceHistoryHandler(mockSvc, mockTokener)
+
+	req := httptest.NewRequest(http.MethodGet, "/wallet/balance/history", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
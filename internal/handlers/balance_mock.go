@@ -12,6 +12,8 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
 )
 
 // MockBalanceTokener is a mock of BalanceTokener interface.
@@ -90,15 +92,43 @@ func (m *MockBalancer) EXPECT() *MockBalancerMockRecorder {
 	return m.recorder
 }
 
+// GetOverdraftLimits mocks base method.
+func (m *MockBalancer) GetOverdraftLimits(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdraftLimits", ctx, userID)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdraftLimits indicates an expected call of GetOverdraftLimits.
+func (mr *MockBalancerMockRecorder) GetOverdraftLimits(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdraftLimits", reflect.TypeOf((*MockBalancer)(nil).GetOverdraftLimits), ctx, userID)
+}
+
+// GetUserAvailableBalance mocks base method.
+func (m *MockBalancer) GetUserAvailableBalance(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAvailableBalance", ctx, userID)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAvailableBalance indicates an expected call of GetUserAvailableBalance.
+func (mr *MockBalancerMockRecorder) GetUserAvailableBalance(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAvailableBalance", reflect.TypeOf((*MockBalancer)(nil).GetUserAvailableBalance), ctx, userID)
+}
+
 // GetUserBalance mocks base method.
-func (m *MockBalancer) GetUserBalance(ctx context.Context, userID uuid.UUID) (float64, float64, float64, error) {
+func (m *MockBalancer) GetUserBalance(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserBalance", ctx, userID)
-	ret0, _ := ret[0].(float64)
-	ret1, _ := ret[1].(float64)
-	ret2, _ := ret[2].(float64)
-	ret3, _ := ret[3].(error)
-	return ret0, ret1, ret2, ret3
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetUserBalance indicates an expected call of GetUserBalance.
@@ -106,3 +136,18 @@ func (mr *MockBalancerMockRecorder) GetUserBalance(ctx, userID interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBalance", reflect.TypeOf((*MockBalancer)(nil).GetUserBalance), ctx, userID)
 }
+
+// GetWalletDetails mocks base method.
+func (m *MockBalancer) GetWalletDetails(ctx context.Context, userID uuid.UUID) (map[string]models.WalletDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletDetails", ctx, userID)
+	ret0, _ := ret[0].(map[string]models.WalletDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletDetails indicates an expected call of GetWalletDetails.
+func (mr *MockBalancerMockRecorder) GetWalletDetails(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletDetails", reflect.TypeOf((*MockBalancer)(nil).GetWalletDetails), ctx, userID)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// APIVersionHeader selects the schema of balances in wallet responses.
+const APIVersionHeader = "Api-Version"
+
+// Balance response schemas
+const (
+	// BalanceSchemaLegacy is an object with exactly the USD, RUB and EUR keys. It is
+	// the default, so clients that send no version keep working.
+	BalanceSchemaLegacy = "1"
+	// BalanceSchemaMap is a map of every supported currency.
+	BalanceSchemaMap = "2"
+)
+
+// legacyBalanceCurrencies are the keys of the legacy balance object.
+var legacyBalanceCurrencies = []string{models.USD, models.RUB, models.EUR}
+
+// balanceSchema returns the balance schema requested by r. Unknown versions get
+// the legacy schema.
+func balanceSchema(r *http.Request) string {
+	if r.Header.Get(APIVersionHeader) == BalanceSchemaMap {
+		return BalanceSchemaMap
+	}
+	return BalanceSchemaLegacy
+}
+
+// setBalanceSchemaHeaders marks the response as varying by API version and flags legacy
+// responses as deprecated since deprecation in the Deprecation header (RFC 9745), unless it
+// is zero. It must be called before the status is written.
+func setBalanceSchemaHeaders(w http.ResponseWriter, r *http.Request, deprecation time.Time) {
+	w.Header().Add("Vary", APIVersionHeader)
+	if balanceSchema(r) != BalanceSchemaLegacy {
+		return
+	}
+	if !deprecation.IsZero() {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecation.Unix()))
+	}
+	metrics.LegacyBalanceResponses.Inc()
+}
+
+// renderBalances returns balances in the schema requested by r. The legacy schema
+// has a zero for each of its currencies without a balance and drops the others.
+func renderBalances(r *http.Request, balances map[string]money.Amount) map[string]money.Amount {
+	if balanceSchema(r) != BalanceSchemaLegacy {
+		return balances
+	}
+	legacy := make(map[string]money.Amount, len(legacyBalanceCurrencies))
+	for _, code := range legacyBalanceCurrencies {
+		legacy[code] = balances[code]
+	}
+	return legacy
+}
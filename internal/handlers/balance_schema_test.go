@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenGetter := NewMockBalanceTokener(ctrl)
+	mockBalancer := NewMockBalancer(ctrl)
+	userID := uuid.New()
+
+	balances := map[string]money.Amount{"USD": money.MustParse("100"), "GBP": money.MustParse("7")}
+	deprecation := time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		version            string
+		deprecation        time.Time
+		expectedBalance    map[string]money.Amount
+		expectedDeprecated bool
+	}{
+		{
+			name:               "no version gets the legacy object",
+			deprecation:        deprecation,
+			expectedBalance:    map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero},
+			expectedDeprecated: true,
+		},
+		{
+			name:               "version 1 gets the legacy object",
+			version:            BalanceSchemaLegacy,
+			deprecation:        deprecation,
+			expectedBalance:    map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero},
+			expectedDeprecated: true,
+		},
+		{
+			name:               "unknown version gets the legacy object",
+			version:            "3",
+			deprecation:        deprecation,
+			expectedBalance:    map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero},
+			expectedDeprecated: true,
+		},
+		{
+			name:            "legacy object without a deprecation date",
+			expectedBalance: map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero},
+		},
+		{
+			name:            "version 2 gets the map",
+			version:         BalanceSchemaMap,
+			deprecation:     deprecation,
+			expectedBalance: balances,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+			mockTokenGetter.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+			mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).Return(balances, nil)
+			mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).Return(balances, nil)
+			mockBalancer.EXPECT().GetOverdraftLimits(gomock.Any(), userID).Return(map[string]money.Amount{}, nil)
+			mockBalancer.EXPECT().GetWalletDetails(gomock.Any(), userID).Return(map[string]models.WalletDetails{}, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/balance", nil)
+			if tt.version != "" {
+				req.Header.Set(APIVersionHeader, tt.version)
+			}
+			rr := httptest.NewRecorder()
+			NewGetBalanceHandler(mockBalancer, mockTokenGetter, tt.deprecation).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, APIVersionHeader, rr.Header().Get("Vary"))
+			if tt.expectedDeprecated {
+				assert.Equal(t, "@1803859200", rr.Header().Get("Deprecation"))
+			} else {
+				assert.Empty(t, rr.Header().Get("Deprecation"))
+			}
+
+			var resp BalanceResponse
+			assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+			assert.Equal(t, CurrencyBalance(tt.expectedBalance), resp.Balance)
+			assert.Equal(t, CurrencyBalance(tt.expectedBalance), resp.Available)
+		})
+	}
+}
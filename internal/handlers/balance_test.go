@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,10 +40,54 @@ func TestGetBalanceHandler(t *testing.T) {
 				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
 					Return(&jwt.Claims{UserID: userID}, nil)
 				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
-					Return(100.0, 5000.0, 50.0, nil)
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.MustParse("5000"), "EUR": money.MustParse("50")}, nil)
+				mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("70"), "RUB": money.MustParse("5000"), "EUR": money.MustParse("50")}, nil)
+				mockBalancer.EXPECT().GetOverdraftLimits(gomock.Any(), userID).
+					Return(map[string]money.Amount{}, nil)
+				mockBalancer.EXPECT().GetWalletDetails(gomock.Any(), userID).
+					Return(map[string]models.WalletDetails{}, nil)
 			},
 			expectedStatus:      http.StatusOK,
-			expectedResponseKey: "balance",
+			expectedResponseKey: "available",
+		},
+		{
+			name: "overdraft limits are listed",
+			setupMocks: func() {
+				mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(token, nil)
+				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("-100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("-100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetOverdraftLimits(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("500")}, nil)
+				mockBalancer.EXPECT().GetWalletDetails(gomock.Any(), userID).
+					Return(map[string]models.WalletDetails{}, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedResponseKey: "overdraft_limits",
+		},
+		{
+			name: "wallet details are listed",
+			setupMocks: func() {
+				mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(token, nil)
+				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetOverdraftLimits(gomock.Any(), userID).
+					Return(map[string]money.Amount{}, nil)
+				mockBalancer.EXPECT().GetWalletDetails(gomock.Any(), userID).
+					Return(map[string]models.WalletDetails{"USD": {Label: "travel fund", Metadata: map[string]string{"trip": "japan"}}}, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedResponseKey: "wallets",
 		},
 		{
 			name: "unauthorized missing token",
@@ -70,7 +117,58 @@ func TestGetBalanceHandler(t *testing.T) {
 				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
 					Return(&jwt.Claims{UserID: userID}, nil)
 				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
-					Return(0.0, 0.0, 0.0, errors.New("db error"))
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedResponseKey: "error",
+		},
+		{
+			name: "internal server error from available balance",
+			setupMocks: func() {
+				mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(token, nil)
+				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedResponseKey: "error",
+		},
+		{
+			name: "internal server error from overdraft limits",
+			setupMocks: func() {
+				mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(token, nil)
+				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetOverdraftLimits(gomock.Any(), userID).
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedResponseKey: "error",
+		},
+		{
+			name: "internal server error from wallet details",
+			setupMocks: func() {
+				mockTokenGetter.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(token, nil)
+				mockTokenGetter.EXPECT().GetClaims(gomock.Any(), token).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				mockBalancer.EXPECT().GetUserBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetUserAvailableBalance(gomock.Any(), userID).
+					Return(map[string]money.Amount{"USD": money.MustParse("100"), "RUB": money.Zero, "EUR": money.Zero}, nil)
+				mockBalancer.EXPECT().GetOverdraftLimits(gomock.Any(), userID).
+					Return(map[string]money.Amount{}, nil)
+				mockBalancer.EXPECT().GetWalletDetails(gomock.Any(), userID).
+					Return(nil, errors.New("db error"))
 			},
 			expectedStatus:      http.StatusInternalServerError,
 			expectedResponseKey: "error",
@@ -80,7 +178,7 @@ func TestGetBalanceHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
-			handler := NewGetBalanceHandler(mockBalancer, mockTokenGetter)
+			handler := NewGetBalanceHandler(mockBalancer, mockTokenGetter, time.Time{})
 
 			req := httptest.NewRequest(http.MethodGet, "/balance", nil)
 			rr := httptest.NewRecorder()
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// BalanceTotalTokener defines only the methods needed by this handler.
+type BalanceTotalTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// BalanceTotaler defines the interface that the service must implement.
+type BalanceTotaler interface {
+	GetTotalBalance(ctx context.Context, userID uuid.UUID, currency string) (models.BalanceTotal, error)
+}
+
+// BalanceTotalEntry represents a wallet balance converted to the requested currency
+// swagger:model BalanceTotalEntry
+type BalanceTotalEntry struct {
+	// Currency of the wallet
+	// default: EUR
+	Currency string `json:"currency"`
+
+	// Balance of the wallet
+	// default: 50.0
+	Balance money.Amount `json:"balance" swaggertype:"number"`
+
+	// Rate used for the conversion, 1 for the requested currency itself
+	// default: 1.08
+	Rate float32 `json:"rate"`
+
+	// Balance in the requested currency
+	// default: 54.0
+	Converted money.Amount `json:"converted" swaggertype:"number"`
+}
+
+// BalanceTotalResponse represents the user's holdings in one currency
+// swagger:model BalanceTotalResponse
+type BalanceTotalResponse struct {
+	// Currency of the total
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Sum of the converted balances
+	// default: 154.0
+	Total money.Amount `json:"total" swaggertype:"number"`
+
+	// Non-zero balances with the rates used, ordered by currency
+	Breakdown []BalanceTotalEntry `json:"breakdown"`
+
+	// Whether a cached rate past its TTL was used because the exchange service was unavailable
+	// default: false
+	StaleRate bool `json:"stale_rate"`
+}
+
+// NewGetTotalBalanceHandler returns an HTTP handler converting all holdings of the user to one currency.
+// @Summary Get total balance
+// @Description Converts the balances of all wallets of the user to the requested currency at current exchange rates, cached ones preferred, and returns the total with the per-currency breakdown and the rates used.
+// @Tags wallet
+// @Produce json
+// @Param currency query string true "Supported currency code"
+// @Success 200 {object} handlers.BalanceTotalResponse "Total balance"
+// @Failure 400 {object} apperrors.Response "Invalid currency"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Exchange rate not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Failure 503 {object} apperrors.Response "Exchange service unavailable"
+// @Failure 504 {object} apperrors.Response "Exchange service timeout"
+// @Router /balance/total [get]
+// @Security BearerAuth
+func NewGetTotalBalanceHandler(
+	svc BalanceTotaler,
+	tokenGetter BalanceTotalTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		currency := r.URL.Query().Get("currency")
+		if !currencies.IsSupported(ctx, currency) {
+			logger.FromContext(ctx).Warnw("invalid total balance currency", "currency", currency)
+			apperrors.Write(w, apperrors.InvalidCurrency)
+			return
+		}
+
+		total, err := svc.GetTotalBalance(ctx, claims.UserID, currency)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrExchangeRateNotFound):
+				apperrors.Write(w, apperrors.ExchangeRateNotFound)
+			case errors.Is(err, services.ErrExchangerUnavailable):
+				apperrors.Write(w, apperrors.ExchangerUnavailable)
+			case errors.Is(err, services.ErrExchangerTimeout):
+				apperrors.Write(w, apperrors.ExchangerTimeout)
+			default:
+				logger.FromContext(ctx).Errorw("failed to get total balance", "userID", claims.UserID, "currency", currency, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := BalanceTotalResponse{
+			Currency:  total.Currency,
+			Total:     total.Total,
+			Breakdown: make([]BalanceTotalEntry, 0, len(total.Holdings)),
+			StaleRate: total.StaleRate,
+		}
+		for _, h := range total.Holdings {
+			resp.Breakdown = append(resp.Breakdown, BalanceTotalEntry{
+				Currency:  h.Currency,
+				Balance:   h.Balance,
+				Rate:      h.Rate,
+				Converted: h.Converted,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/balance_total.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockBalanceTotalTokener is a mock of BalanceTotalTokener interface.
+type MockBalanceTotalTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceTotalTokenerMockRecorder
+}
+
+// MockBalanceTotalTokenerMockRecorder is the mock recorder for MockBalanceTotalTokener.
+type MockBalanceTotalTokenerMockRecorder struct {
+	mock *MockBalanceTotalTokener
+}
+
+// NewMockBalanceTotalTokener creates a new mock instance.
+func NewMockBalanceTotalTokener(ctrl *gomock.Controller) *MockBalanceTotalTokener {
+	mock := &MockBalanceTotalTokener{ctrl: ctrl}
+	mock.recorder = &MockBalanceTotalTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceTotalTokener) EXPECT() *MockBalanceTotalTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockBalanceTotalTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockBalanceTotalTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockBalanceTotalTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockBalanceTotalTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockBalanceTotalTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockBalanceTotalTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockBalanceTotaler is a mock of BalanceTotaler interface.
+type MockBalanceTotaler struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceTotalerMockRecorder
+}
+
+// MockBalanceTotalerMockRecorder is the mock recorder for MockBalanceTotaler.
+type MockBalanceTotalerMockRecorder struct {
+	mock *MockBalanceTotaler
+}
+
+// NewMockBalanceTotaler creates a new mock instance.
+func NewMockBalanceTotaler(ctrl *gomock.Controller) *MockBalanceTotaler {
+	mock := &MockBalanceTotaler{ctrl: ctrl}
+	mock.recorder = &MockBalanceTotalerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceTotaler) EXPECT() *MockBalanceTotalerMockRecorder {
+	return m.recorder
+}
+
+// GetTotalBalance mocks base method.
+func (m *MockBalanceTotaler) GetTotalBalance(ctx context.Context, userID uuid.UUID, currency string) (models.BalanceTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalBalance", ctx, userID, currency)
+	ret0, _ := ret[0].(models.BalanceTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalBalance indicates an expected call of GetTotalBalance.
+func (mr *MockBalanceTotalerMockRecorder) GetTotalBalance(ctx, userID, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalBalance", reflect.TypeOf((*MockBalanceTotaler)(nil).GetTotalBalance), ctx, userID, currency)
+}
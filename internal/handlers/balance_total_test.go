@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTotalBalanceHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockBalanceTotalTokener(ctrl)
+	mockSvc := NewMockBalanceTotaler(ctrl)
+
+	userID := uuid.New()
+
+	handler := NewGetTotalBalanceHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success",
+			query: "?currency=USD",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetTotalBalance(gomock.Any(), userID, models.USD).Return(models.BalanceTotal{
+					Currency: models.USD,
+					Total:    money.MustParse("154"),
+					Holdings: []models.ConvertedBalance{
+						{Currency: models.EUR, Balance: money.MustParse("50"), Rate: 1.08, Converted: money.MustParse("54")},
+						{Currency: models.USD, Balance: money.MustParse("100"), Rate: 1, Converted: money.MustParse("100")},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: BalanceTotalResponse{
+				Currency: models.USD,
+				Total:    money.MustParse("154"),
+				Breakdown: []BalanceTotalEntry{
+					{Currency: models.EUR, Balance: money.MustParse("50"), Rate: 1.08, Converted: money.MustParse("54")},
+					{Currency: models.USD, Balance: money.MustParse("100"), Rate: 1, Converted: money.MustParse("100")},
+				},
+			},
+		},
+		{
+			name:  "no holdings",
+			query: "?currency=EUR",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetTotalBalance(gomock.Any(), userID, models.EUR).Return(models.BalanceTotal{Currency: models.EUR}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   BalanceTotalResponse{Currency: models.EUR, Breakdown: []BalanceTotalEntry{}},
+		},
+		{
+			name:           "missing currency",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "unsupported currency",
+			query:          "?currency=BTC",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:  "rate not found",
+			query: "?currency=USD",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetTotalBalance(gomock.Any(), userID, models.USD).Return(models.BalanceTotal{}, services.ErrExchangeRateNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Exchange rate not found", Code: "exchange_rate_not_found"},
+		},
+		{
+			name:  "exchanger unavailable",
+			query: "?currency=USD",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetTotalBalance(gomock.Any(), userID, models.USD).Return(models.BalanceTotal{}, services.ErrExchangerUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Exchange service unavailable", Code: "exchanger_unavailable"},
+		},
+		{
+			name:  "exchanger timeout",
+			query: "?currency=USD",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetTotalBalance(gomock.Any(), userID, models.USD).Return(models.BalanceTotal{}, services.ErrExchangerTimeout)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   apperrors.Response{Error: "Exchange service timeout", Code: "exchanger_timeout"},
+		},
+		{
+			name:  "internal error",
+			query: "?currency=USD",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetTotalBalance(gomock.Any(), userID, models.USD).Return(models.BalanceTotal{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/balance/total"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			switch expected := tt.expectedBody.(type) {
+			case BalanceTotalResponse:
+				var got BalanceTotalResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetTotalBalanceHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockBalanceTotalTokener(ctrl)
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+
+	handler := NewGetTotalBalanceHandler(NewMockBalanceTotaler(ctrl), mockTokener, newMockCurrencies(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/balance/total?currency=USD", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
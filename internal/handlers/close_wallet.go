@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// CloseWalletTokener defines only the methods needed by this handler.
+type CloseWalletTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// WalletCloser defines the interface that the service must implement.
+type WalletCloser interface {
+	CloseWallet(ctx context.Context, userID uuid.UUID, currency, toCurrency string) (credited money.Amount, balances map[string]money.Amount, err error)
+}
+
+// CurrencyBalanceAfterClose represents balances keyed by currency code
+// swagger:model CurrencyBalanceAfterClose
+type CurrencyBalanceAfterClose map[string]money.Amount
+
+// CloseWalletRequest represents the JSON body for closing a wallet
+// swagger:model CloseWalletRequest
+type CloseWalletRequest struct {
+	// Currency of the wallet to close
+	// required: true
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Currency to pay the remaining balance out to; may be omitted if the wallet is empty
+	// default: EUR
+	ToCurrency string `json:"to_currency,omitempty"`
+}
+
+// CloseWalletResponse represents a successful wallet closure response
+// swagger:model CloseWalletResponse
+type CloseWalletResponse struct {
+	// Success message
+	// default: Wallet closed
+	Message string `json:"message"`
+
+	// Amount credited in to_currency
+	// default: 92.0
+	CreditedAmount money.Amount `json:"credited_amount" swaggertype:"number"`
+
+	// New balance of the user
+	NewBalance CurrencyBalanceAfterClose `json:"new_balance" swaggertype:"object,number"`
+}
+
+// NewCloseWalletHandler returns an HTTP handler closing a wallet of the user.
+// @Summary Close wallet
+// @Description Close the wallet in the given currency. The remaining balance is converted at the current exchange rate and credited to the to_currency wallet in one atomic operation. An empty wallet can be closed without to_currency.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body handlers.CloseWalletRequest true "Close Wallet Request"
+// @Param Api-Version header string false "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)"
+// @Success 200 {object} handlers.CloseWalletResponse "Wallet closed"
+// @Failure 400 {object} apperrors.Response "Invalid currency"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Wallet or exchange rate not found"
+// @Failure 409 {object} apperrors.Response "Wallet is not empty, specify to_currency, has pending holds, is overdrawn, or another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Failure 503 {object} apperrors.Response "Exchange service unavailable"
+// @Failure 504 {object} apperrors.Response "Exchange service timeout"
+// @Router /wallet/close [post]
+// @Security BearerAuth
+func NewCloseWalletHandler(
+	svc WalletCloser,
+	tokenGetter CloseWalletTokener,
+	currencies CurrencyChecker,
+	legacyDeprecation time.Time,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		var req CloseWalletRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Errorw("failed to decode close wallet request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		validFrom := currencies.IsSupported(ctx, req.Currency)
+		validTo := req.ToCurrency == "" || (req.ToCurrency != req.Currency && currencies.IsSupported(ctx, req.ToCurrency))
+		if !validFrom || !validTo {
+			logger.FromContext(ctx).Warnw("invalid close wallet currencies", "currency", req.Currency, "to", req.ToCurrency)
+			apperrors.Write(w, apperrors.InvalidCurrency)
+			return
+		}
+
+		credited, balances, err := svc.CloseWallet(ctx, claims.UserID, req.Currency, req.ToCurrency)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrWalletNotFound):
+				apperrors.Write(w, apperrors.WalletNotFound)
+			case errors.Is(err, services.ErrWalletNotEmpty):
+				apperrors.Write(w, apperrors.WalletNotEmpty)
+			case errors.Is(err, services.ErrWalletHasHolds):
+				apperrors.Write(w, apperrors.WalletHasHolds)
+			case errors.Is(err, services.ErrWalletOverdrawn):
+				apperrors.Write(w, apperrors.WalletOverdrawn)
+			case errors.Is(err, services.ErrExchangeRateNotFound):
+				apperrors.Write(w, apperrors.ExchangeRateNotFound)
+			case errors.Is(err, services.ErrExchangerUnavailable):
+				apperrors.Write(w, apperrors.ExchangerUnavailable)
+			case errors.Is(err, services.ErrExchangerTimeout):
+				apperrors.Write(w, apperrors.ExchangerTimeout)
+			default:
+				logger.FromContext(ctx).Errorw("internal server error during wallet closure", "error", err, "userID", claims.UserID)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := CloseWalletResponse{
+			Message:        "Wallet closed",
+			CreditedAmount: credited,
+			NewBalance:     renderBalances(r, balances),
+		}
+
+		setBalanceSchemaHeaders(w, r, legacyDeprecation)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/close_wallet.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockCloseWalletTokener is a mock of CloseWalletTokener interface.
+type MockCloseWalletTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloseWalletTokenerMockRecorder
+}
+
+// MockCloseWalletTokenerMockRecorder is the mock recorder for MockCloseWalletTokener.
+type MockCloseWalletTokenerMockRecorder struct {
+	mock *MockCloseWalletTokener
+}
+
+// NewMockCloseWalletTokener creates a new mock instance.
+func NewMockCloseWalletTokener(ctrl *gomock.Controller) *MockCloseWalletTokener {
+	mock := &MockCloseWalletTokener{ctrl: ctrl}
+	mock.recorder = &MockCloseWalletTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloseWalletTokener) EXPECT() *MockCloseWalletTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockCloseWalletTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockCloseWalletTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockCloseWalletTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockCloseWalletTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockCloseWalletTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockCloseWalletTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockWalletCloser is a mock of WalletCloser interface.
+type MockWalletCloser struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletCloserMockRecorder
+}
+
+// MockWalletCloserMockRecorder is the mock recorder for MockWalletCloser.
+type MockWalletCloserMockRecorder struct {
+	mock *MockWalletCloser
+}
+
+// NewMockWalletCloser creates a new mock instance.
+func NewMockWalletCloser(ctrl *gomock.Controller) *MockWalletCloser {
+	mock := &MockWalletCloser{ctrl: ctrl}
+	mock.recorder = &MockWalletCloserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletCloser) EXPECT() *MockWalletCloserMockRecorder {
+	return m.recorder
+}
+
+// CloseWallet mocks base method.
+func (m *MockWalletCloser) CloseWallet(ctx context.Context, userID uuid.UUID, currency, toCurrency string) (money.Amount, map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseWallet", ctx, userID, currency, toCurrency)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(map[string]money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CloseWallet indicates an expected call of CloseWallet.
+func (mr *MockWalletCloserMockRecorder) CloseWallet(ctx, userID, currency, toCurrency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseWallet", reflect.TypeOf((*MockWalletCloser)(nil).CloseWallet), ctx, userID, currency, toCurrency)
+}
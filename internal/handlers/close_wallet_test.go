@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseWalletHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockCloseWalletTokener(ctrl)
+	mockSvc := NewMockWalletCloser(ctrl)
+
+	userID := uuid.New()
+
+	handler := NewCloseWalletHandler(mockSvc, mockTokener, newMockCurrencies(ctrl), time.Time{})
+
+	// Allow token extraction for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        interface{}
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success_with_payout",
+			reqBody: CloseWalletRequest{Currency: "USD", ToCurrency: "EUR"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "USD", "EUR").
+					Return(money.MustParse("92"), map[string]money.Amount{"USD": money.Zero, "RUB": money.MustParse("5000"), "EUR": money.MustParse("142")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: CloseWalletResponse{
+				Message:        "Wallet closed",
+				CreditedAmount: money.MustParse("92"),
+				NewBalance:     CurrencyBalanceAfterClose{"USD": money.Zero, "RUB": money.MustParse("5000"), "EUR": money.MustParse("142")},
+			},
+		},
+		{
+			name:    "success_empty_wallet",
+			reqBody: CloseWalletRequest{Currency: "RUB"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "RUB", "").
+					Return(money.Zero, map[string]money.Amount{"USD": money.MustParse("10")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: CloseWalletResponse{
+				Message:    "Wallet closed",
+				NewBalance: CurrencyBalanceAfterClose{"USD": money.MustParse("10"), "RUB": money.Zero, "EUR": money.Zero},
+			},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
+		},
+		{
+			name:           "invalid_currency",
+			reqBody:        CloseWalletRequest{Currency: "ABC", ToCurrency: "EUR"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "invalid_to_currency",
+			reqBody:        CloseWalletRequest{Currency: "USD", ToCurrency: "ABC"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "same_currency",
+			reqBody:        CloseWalletRequest{Currency: "USD", ToCurrency: "USD"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:    "not_found",
+			reqBody: CloseWalletRequest{Currency: "EUR"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "EUR", "").
+					Return(money.Zero, nil, services.ErrWalletNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Wallet not found", Code: "wallet_not_found"},
+		},
+		{
+			name:    "not_empty",
+			reqBody: CloseWalletRequest{Currency: "EUR"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "EUR", "").
+					Return(money.Zero, nil, services.ErrWalletNotEmpty)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Wallet is not empty, specify to_currency", Code: "wallet_not_empty"},
+		},
+		{
+			name:    "pending_holds",
+			reqBody: CloseWalletRequest{Currency: "USD", ToCurrency: "RUB"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "USD", "RUB").
+					Return(money.Zero, nil, services.ErrWalletHasHolds)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Wallet has pending holds", Code: "wallet_has_holds"},
+		},
+		{
+			name:    "overdrawn",
+			reqBody: CloseWalletRequest{Currency: "USD", ToCurrency: "RUB"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "USD", "RUB").
+					Return(money.Zero, nil, services.ErrWalletOverdrawn)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Wallet is overdrawn", Code: "wallet_overdrawn"},
+		},
+		{
+			name:    "exchanger_timeout",
+			reqBody: CloseWalletRequest{Currency: "USD", ToCurrency: "RUB"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "USD", "RUB").
+					Return(money.Zero, nil, services.ErrExchangerTimeout)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   apperrors.Response{Error: "Exchange service timeout", Code: "exchanger_timeout"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: CloseWalletRequest{Currency: "USD", ToCurrency: "RUB"},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CloseWallet(gomock.Any(), userID, "USD", "RUB").
+					Return(money.Zero, nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			var bodyBytes []byte
+			switch v := tt.reqBody.(type) {
+			case string:
+				bodyBytes = []byte(v)
+			default:
+				bodyBytes, _ = json.Marshal(v)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/close", bytes.NewReader(bodyBytes))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case CloseWalletResponse:
+				var got CloseWalletResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
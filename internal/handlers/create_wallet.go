@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// CreateWalletTokener defines only the methods needed by this handler.
+type CreateWalletTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// WalletCreator defines the interface that the service must implement.
+type WalletCreator interface {
+	CreateWallets(ctx context.Context, userID uuid.UUID, currencies []string) (created []string, balances map[string]money.Amount, err error)
+}
+
+// CurrencyBalanceAfterCreate represents balances keyed by currency code
+// swagger:model CurrencyBalanceAfterCreate
+type CurrencyBalanceAfterCreate map[string]money.Amount
+
+// CreateWalletRequest represents the JSON body for creating wallets
+// swagger:model CreateWalletRequest
+type CreateWalletRequest struct {
+	// Currencies to open wallets in
+	// required: true
+	// default: ["USD","EUR"]
+	Currencies []string `json:"currencies"`
+}
+
+// CreateWalletResponse represents a successful wallet creation response
+// swagger:model CreateWalletResponse
+type CreateWalletResponse struct {
+	// Success message
+	// default: Wallets created
+	Message string `json:"message"`
+
+	// Currencies of the wallets created by this request; existing wallets are left out
+	Created []string `json:"created"`
+
+	// New balance of the user
+	NewBalance CurrencyBalanceAfterCreate `json:"new_balance" swaggertype:"object,number"`
+}
+
+// NewCreateWalletHandler returns an HTTP handler opening empty wallets of the user.
+// @Summary Create wallets
+// @Description Open empty wallets in the given currencies. Currencies the user already has a wallet in are skipped, so the request can be repeated. Without it, a wallet is created by the first deposit.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateWalletRequest true "Create Wallet Request"
+// @Param Api-Version header string false "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)"
+// @Success 201 {object} handlers.CreateWalletResponse "Wallets created"
+// @Success 200 {object} handlers.CreateWalletResponse "All wallets already exist"
+// @Failure 400 {object} apperrors.Response "Invalid request or currency"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet [post]
+// @Security BearerAuth
+func NewCreateWalletHandler(
+	svc WalletCreator,
+	tokenGetter CreateWalletTokener,
+	currencies CurrencyChecker,
+	legacyDeprecation time.Time,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		var req CreateWalletRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Currencies) == 0 {
+			logger.FromContext(ctx).Warnw("invalid create wallet request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		for _, currency := range req.Currencies {
+			if !currencies.IsSupported(ctx, currency) {
+				logger.FromContext(ctx).Warnw("invalid create wallet currency", "currency", currency)
+				apperrors.Write(w, apperrors.InvalidCurrency)
+				return
+			}
+		}
+
+		created, balances, err := svc.CreateWallets(ctx, claims.UserID, req.Currencies)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("internal server error during wallet creation", "error", err, "userID", claims.UserID)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		status, message := http.StatusCreated, "Wallets created"
+		if len(created) == 0 {
+			status, message = http.StatusOK, "Wallets already exist"
+		}
+		resp := CreateWalletResponse{
+			Message:    message,
+			Created:    created,
+			NewBalance: renderBalances(r, balances),
+		}
+
+		setBalanceSchemaHeaders(w, r, legacyDeprecation)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/create_wallet.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockCreateWalletTokener is a mock of CreateWalletTokener interface.
+type MockCreateWalletTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateWalletTokenerMockRecorder
+}
+
+// MockCreateWalletTokenerMockRecorder is the mock recorder for MockCreateWalletTokener.
+type MockCreateWalletTokenerMockRecorder struct {
+	mock *MockCreateWalletTokener
+}
+
+// NewMockCreateWalletTokener creates a new mock instance.
+func NewMockCreateWalletTokener(ctrl *gomock.Controller) *MockCreateWalletTokener {
+	mock := &MockCreateWalletTokener{ctrl: ctrl}
+	mock.recorder = &MockCreateWalletTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateWalletTokener) EXPECT() *MockCreateWalletTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockCreateWalletTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockCreateWalletTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockCreateWalletTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockCreateWalletTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockCreateWalletTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockCreateWalletTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockWalletCreator is a mock of WalletCreator interface.
+type MockWalletCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletCreatorMockRecorder
+}
+
+// MockWalletCreatorMockRecorder is the mock recorder for MockWalletCreator.
+type MockWalletCreatorMockRecorder struct {
+	mock *MockWalletCreator
+}
+
+// NewMockWalletCreator creates a new mock instance.
+func NewMockWalletCreator(ctrl *gomock.Controller) *MockWalletCreator {
+	mock := &MockWalletCreator{ctrl: ctrl}
+	mock.recorder = &MockWalletCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletCreator) EXPECT() *MockWalletCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateWallets mocks base method.
+func (m *MockWalletCreator) CreateWallets(ctx context.Context, userID uuid.UUID, currencies []string) ([]string, map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallets", ctx, userID, currencies)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(map[string]money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateWallets indicates an expected call of CreateWallets.
+func (mr *MockWalletCreatorMockRecorder) CreateWallets(ctx, userID, currencies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallets", reflect.TypeOf((*MockWalletCreator)(nil).CreateWallets), ctx, userID, currencies)
+}
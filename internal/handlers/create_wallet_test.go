@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWalletHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockCreateWalletTokener(ctrl)
+	mockSvc := NewMockWalletCreator(ctrl)
+
+	userID := uuid.New()
+
+	handler := NewCreateWalletHandler(mockSvc, mockTokener, newMockCurrencies(ctrl), time.Time{})
+
+	// Allow token extraction for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        interface{}
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: CreateWalletRequest{Currencies: []string{"USD", "EUR"}},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CreateWallets(gomock.Any(), userID, []string{"USD", "EUR"}).
+					Return([]string{"EUR", "USD"}, map[string]money.Amount{"USD": money.Zero, "EUR": money.Zero}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: CreateWalletResponse{
+				Message:    "Wallets created",
+				Created:    []string{"EUR", "USD"},
+				NewBalance: CurrencyBalanceAfterCreate{"USD": money.Zero, "RUB": money.Zero, "EUR": money.Zero},
+			},
+		},
+		{
+			name:    "already_exist",
+			reqBody: CreateWalletRequest{Currencies: []string{"RUB"}},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CreateWallets(gomock.Any(), userID, []string{"RUB"}).
+					Return([]string{}, map[string]money.Amount{"RUB": money.MustParse("5000")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: CreateWalletResponse{
+				Message:    "Wallets already exist",
+				Created:    []string{},
+				NewBalance: CurrencyBalanceAfterCreate{"USD": money.Zero, "RUB": money.MustParse("5000"), "EUR": money.Zero},
+			},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:           "no_currencies",
+			reqBody:        CreateWalletRequest{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:           "invalid_currency",
+			reqBody:        CreateWalletRequest{Currencies: []string{"USD", "ABC"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: CreateWalletRequest{Currencies: []string{"USD"}},
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					CreateWallets(gomock.Any(), userID, []string{"USD"}).
+					Return(nil, nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			var bodyBytes []byte
+			switch v := tt.reqBody.(type) {
+			case string:
+				bodyBytes = []byte(v)
+			default:
+				bodyBytes, _ = json.Marshal(v)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet", bytes.NewReader(bodyBytes))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case CreateWalletResponse:
+				var got CreateWalletResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// CurrencyChecker reports whether a currency is supported and whether an amount
+// meets its minimum and precision. It is shared by all handlers that accept a currency code.
+type CurrencyChecker interface {
+	IsSupported(ctx context.Context, code string) bool
+	ValidAmount(ctx context.Context, code string, amount money.Amount) bool
+}
+
+// CurrencyLister defines the interface for listing supported currencies.
+type CurrencyLister interface {
+	List(ctx context.Context) ([]models.CurrencyDB, error)
+}
+
+// Currency describes a supported currency
+// swagger:model Currency
+type Currency struct {
+	// ISO 4217 currency code
+	// default: USD
+	Code string `json:"code"`
+
+	// Currency name
+	// default: US Dollar
+	Name string `json:"name"`
+
+	// Decimal places of amounts; exchanges into the currency are rounded to them
+	// default: 2
+	Decimals int `json:"decimals"`
+
+	// Smallest amount accepted by wallet operations
+	// default: 0.01
+	MinAmount money.Amount `json:"min_amount" swaggertype:"number"`
+}
+
+// CurrenciesResponse represents the list of supported currencies
+// swagger:model CurrenciesResponse
+type CurrenciesResponse struct {
+	// Supported currencies ordered by code
+	Currencies []Currency `json:"currencies"`
+}
+
+// NewListCurrenciesHandler returns an HTTP handler that lists the supported currencies.
+// @Summary List supported currencies
+// @Description Returns the currencies accepted by wallet operations. Balances and exchange rates are keyed by these codes.
+// @Tags wallet
+// @Produce json
+// @Success 200 {object} handlers.CurrenciesResponse "Supported currencies"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /currencies [get]
+func NewListCurrenciesHandler(lister CurrencyLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		currencies, err := lister.List(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to list currencies", "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := CurrenciesResponse{Currencies: make([]Currency, 0, len(currencies))}
+		for _, c := range currencies {
+			resp.Currencies = append(resp.Currencies, Currency{Code: c.Code, Name: c.Name, Decimals: c.Decimals, MinAmount: c.MinAmount})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/currency.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockCurrencyChecker is a mock of CurrencyChecker interface.
+type MockCurrencyChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyCheckerMockRecorder
+}
+
+// MockCurrencyCheckerMockRecorder is the mock recorder for MockCurrencyChecker.
+type MockCurrencyCheckerMockRecorder struct {
+	mock *MockCurrencyChecker
+}
+
+// NewMockCurrencyChecker creates a new mock instance.
+func NewMockCurrencyChecker(ctrl *gomock.Controller) *MockCurrencyChecker {
+	mock := &MockCurrencyChecker{ctrl: ctrl}
+	mock.recorder = &MockCurrencyCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyChecker) EXPECT() *MockCurrencyCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsSupported mocks base method.
+func (m *MockCurrencyChecker) IsSupported(ctx context.Context, code string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSupported", ctx, code)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSupported indicates an expected call of IsSupported.
+func (mr *MockCurrencyCheckerMockRecorder) IsSupported(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSupported", reflect.TypeOf((*MockCurrencyChecker)(nil).IsSupported), ctx, code)
+}
+
+// ValidAmount mocks base method.
+func (m *MockCurrencyChecker) ValidAmount(ctx context.Context, code string, amount money.Amount) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidAmount", ctx, code, amount)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ValidAmount indicates an expected call of ValidAmount.
+func (mr *MockCurrencyCheckerMockRecorder) ValidAmount(ctx, code, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidAmount", reflect.TypeOf((*MockCurrencyChecker)(nil).ValidAmount), ctx, code, amount)
+}
+
+// MockCurrencyLister is a mock of CurrencyLister interface.
+type MockCurrencyLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyListerMockRecorder
+}
+
+// MockCurrencyListerMockRecorder is the mock recorder for MockCurrencyLister.
+type MockCurrencyListerMockRecorder struct {
+	mock *MockCurrencyLister
+}
+
+// NewMockCurrencyLister creates a new mock instance.
+func NewMockCurrencyLister(ctrl *gomock.Controller) *MockCurrencyLister {
+	mock := &MockCurrencyLister{ctrl: ctrl}
+	mock.recorder = &MockCurrencyListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyLister) EXPECT() *MockCurrencyListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockCurrencyLister) List(ctx context.Context) ([]models.CurrencyDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.CurrencyDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCurrencyListerMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCurrencyLister)(nil).List), ctx)
+}
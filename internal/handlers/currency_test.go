@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMockCurrencies returns a CurrencyChecker accepting USD, RUB and EUR with two
+// decimal places and the minimum amount of 0.01.
+func newMockCurrencies(ctrl *gomock.Controller) *MockCurrencyChecker {
+	currencies := NewMockCurrencyChecker(ctrl)
+	currencies.EXPECT().
+		IsSupported(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, code string) bool {
+			return code == models.USD || code == models.RUB || code == models.EUR
+		})
+	currencies.EXPECT().
+		ValidAmount(gomock.Any(), gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, code string, amount money.Amount) bool {
+			return currencies.IsSupported(ctx, code) && amount.IsPositive()
+		})
+	return currencies
+}
+
+func TestListCurrenciesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockCurrencyLister(ctrl)
+	handler := NewListCurrenciesHandler(mockLister)
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockLister.EXPECT().List(gomock.Any()).Return([]models.CurrencyDB{
+					{Code: "JPY", Name: "Japanese Yen", Enabled: true, MinAmount: money.MustParse("1")},
+					{Code: models.USD, Name: "US Dollar", Enabled: true, Decimals: 2, MinAmount: money.MustParse("0.01")},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: CurrenciesResponse{Currencies: []Currency{
+				{Code: "JPY", Name: "Japanese Yen", MinAmount: money.MustParse("1")},
+				{Code: models.USD, Name: "US Dollar", Decimals: 2, MinAmount: money.MustParse("0.01")},
+			}},
+		},
+		{
+			name: "internal_error",
+			mockSetup: func() {
+				mockLister.EXPECT().List(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/currencies", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			switch expected := tt.expectedBody.(type) {
+			case CurrenciesResponse:
+				var got CurrenciesResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
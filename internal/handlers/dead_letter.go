@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// DeadLetterTokener defines only the methods needed by these handlers.
+type DeadLetterTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// DeadLetterManager defines the interface that the service must implement.
+type DeadLetterManager interface {
+	ListPending(ctx context.Context, limit int) ([]models.DeadLetterEvent, error)
+	Replay(ctx context.Context, id uuid.UUID) (models.DeadLetterEvent, error)
+}
+
+// DeadLetterResponse represents a transaction event that could not be published
+// swagger:model DeadLetterResponse
+type DeadLetterResponse struct {
+	// Dead letter identifier
+	// default: 3b8f1c2d-6a4e-4f7b-9d0c-1e2f3a4b5c6d
+	ID uuid.UUID `json:"id"`
+
+	// Kafka topic the event is published to
+	// default: large-transactions
+	Topic string `json:"topic"`
+
+	// Kafka message key, the user ID
+	// default: 6f1c2a4e-8d0b-4b7a-9c3e-2f5d1a7b9e01
+	Key string `json:"key"`
+
+	// ID consumers deduplicate the event by, omitted for events dead-lettered without one
+	// default: 0b7d9c4e-3f1a-5e2b-8c6d-4a9f1e3b7c20
+	EventID string `json:"event_id,omitempty"`
+
+	// Event as it would have been published: JSON events as is, Avro events as a base64 string
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+
+	// Failed publishing attempts
+	// default: 3
+	Attempts int `json:"attempts"`
+
+	// Reason of the last failed attempt
+	// default: dial tcp: connection refused
+	LastError string `json:"last_error"`
+
+	// Time the event was dead-lettered
+	CreatedAt time.Time `json:"created_at"`
+
+	// Time of the replay, omitted while pending
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+}
+
+// DeadLettersResponse represents the dead letters waiting for a replay
+// swagger:model DeadLettersResponse
+type DeadLettersResponse struct {
+	// Dead letters, oldest first
+	DeadLetters []DeadLetterResponse `json:"dead_letters"`
+}
+
+// NewListDeadLettersHandler returns an HTTP handler listing the dead letters not replayed yet.
+// @Summary List dead-lettered transaction events
+// @Description Returns the transaction events that could not be published to Kafka after all attempts and were not replayed yet, oldest first.
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of dead letters to return (default 100, max 100)"
+// @Success 200 {object} handlers.DeadLettersResponse "Dead letters"
+// @Failure 400 {object} apperrors.Response "Invalid limit"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/dead-letters [get]
+// @Security BearerAuth
+func NewListDeadLettersHandler(svc DeadLetterManager, tokenGetter DeadLetterTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !deadLetterClaims(w, r, tokenGetter) {
+			return
+		}
+
+		var limit int
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+				apperrors.Write(w, apperrors.InvalidLimit)
+				return
+			}
+		}
+
+		events, err := svc.ListPending(ctx, limit)
+		if err != nil {
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := DeadLettersResponse{DeadLetters: make([]DeadLetterResponse, 0, len(events))}
+		for _, event := range events {
+			resp.DeadLetters = append(resp.DeadLetters, toDeadLetterResponse(event))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// NewReplayDeadLetterHandler returns an HTTP handler publishing a dead letter again.
+// @Summary Replay a dead-lettered transaction event
+// @Description Publishes a dead-lettered transaction event to Kafka again with its event ID so consumers drop duplicates, and marks it replayed. A dead letter is replayed at most once.
+// @Tags admin
+// @Produce json
+// @Param deadLetterID path string true "Dead letter ID"
+// @Success 200 {object} handlers.DeadLetterResponse "Dead letter replayed"
+// @Failure 400 {object} apperrors.Response "Invalid dead letter ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "Dead letter not found"
+// @Failure 409 {object} apperrors.Response "Dead letter already replayed"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Failure 503 {object} apperrors.Response "Kafka unavailable"
+// @Router /admin/dead-letters/{deadLetterID}/replay [post]
+// @Security BearerAuth
+func NewReplayDeadLetterHandler(svc DeadLetterManager, tokenGetter DeadLetterTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !deadLetterClaims(w, r, tokenGetter) {
+			return
+		}
+
+		id, err := uuid.Parse(chi.URLParam(r, "deadLetterID"))
+		if err != nil {
+			logger.FromContext(ctx).Warnw("invalid dead letter ID", "dead_letter_id", chi.URLParam(r, "deadLetterID"), "error", err)
+			apperrors.Write(w, apperrors.InvalidDeadLetterID)
+			return
+		}
+
+		event, err := svc.Replay(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrDeadLetterNotFound):
+				apperrors.Write(w, apperrors.DeadLetterNotFound)
+			case errors.Is(err, services.ErrDeadLetterReplayed):
+				apperrors.Write(w, apperrors.DeadLetterAlreadyReplayed)
+			case errors.Is(err, services.ErrDeadLetterReplayFailed):
+				apperrors.Write(w, apperrors.KafkaUnavailable)
+			default:
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(toDeadLetterResponse(event))
+	}
+}
+
+func toDeadLetterResponse(event models.DeadLetterEvent) DeadLetterResponse {
+	payload := json.RawMessage(event.Payload)
+	if !json.Valid(payload) {
+		// Binary events, e.g. Avro, are marshaled as base64
+		payload, _ = json.Marshal(event.Payload)
+	}
+	return DeadLetterResponse{
+		ID:         event.ID,
+		Topic:      event.Topic,
+		Key:        event.Key,
+		EventID:    event.EventID,
+		Payload:    payload,
+		Attempts:   event.Attempts,
+		LastError:  event.LastError,
+		CreatedAt:  event.CreatedAt,
+		ReplayedAt: event.ReplayedAt,
+	}
+}
+
+// deadLetterClaims checks the token of the request and writes 401 if it is missing or invalid.
+func deadLetterClaims(w http.ResponseWriter, r *http.Request, tokenGetter DeadLetterTokener) bool {
+	ctx := r.Context()
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return false
+	}
+	if _, err := tokenGetter.GetClaims(ctx, tokenStr); err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return false
+	}
+	return true
+}
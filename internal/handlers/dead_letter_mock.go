@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/dead_letter.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockDeadLetterTokener is a mock of DeadLetterTokener interface.
+type MockDeadLetterTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterTokenerMockRecorder
+}
+
+// MockDeadLetterTokenerMockRecorder is the mock recorder for MockDeadLetterTokener.
+type MockDeadLetterTokenerMockRecorder struct {
+	mock *MockDeadLetterTokener
+}
+
+// NewMockDeadLetterTokener creates a new mock instance.
+func NewMockDeadLetterTokener(ctrl *gomock.Controller) *MockDeadLetterTokener {
+	mock := &MockDeadLetterTokener{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterTokener) EXPECT() *MockDeadLetterTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockDeadLetterTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockDeadLetterTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockDeadLetterTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockDeadLetterTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockDeadLetterTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockDeadLetterTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockDeadLetterManager is a mock of DeadLetterManager interface.
+type MockDeadLetterManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterManagerMockRecorder
+}
+
+// MockDeadLetterManagerMockRecorder is the mock recorder for MockDeadLetterManager.
+type MockDeadLetterManagerMockRecorder struct {
+	mock *MockDeadLetterManager
+}
+
+// NewMockDeadLetterManager creates a new mock instance.
+func NewMockDeadLetterManager(ctrl *gomock.Controller) *MockDeadLetterManager {
+	mock := &MockDeadLetterManager{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterManager) EXPECT() *MockDeadLetterManagerMockRecorder {
+	return m.recorder
+}
+
+// ListPending mocks base method.
+func (m *MockDeadLetterManager) ListPending(ctx context.Context, limit int) ([]models.DeadLetterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, limit)
+	ret0, _ := ret[0].([]models.DeadLetterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockDeadLetterManagerMockRecorder) ListPending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockDeadLetterManager)(nil).ListPending), ctx, limit)
+}
+
+// Replay mocks base method.
+func (m *MockDeadLetterManager) Replay(ctx context.Context, id uuid.UUID) (models.DeadLetterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, id)
+	ret0, _ := ret[0].(models.DeadLetterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockDeadLetterManagerMockRecorder) Replay(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockDeadLetterManager)(nil).Replay), ctx, id)
+}
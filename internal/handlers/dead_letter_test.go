@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListDeadLettersHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockDeadLetterTokener(ctrl)
+	mockSvc := NewMockDeadLetterManager(ctrl)
+	handler := NewListDeadLettersHandler(mockSvc, mockTokener)
+
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).AnyTimes().Return("admin-token", nil)
+	mockTokener.EXPECT().GetClaims(gomock.Any(), "admin-token").AnyTimes().Return(&jwt.Claims{UserID: uuid.New(), Role: "admin"}, nil)
+
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	event := models.DeadLetterEvent{
+		ID:        uuid.New(),
+		Topic:     "transactions",
+		Key:       "txn-1",
+		Payload:   []byte(`{"transaction_id":"txn-1"}`),
+		Attempts:  3,
+		LastError: "broker down",
+		CreatedAt: createdAt,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "success",
+			mockSvc: func() {
+				mockSvc.EXPECT().ListPending(gomock.Any(), 0).Return([]models.DeadLetterEvent{event}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: DeadLettersResponse{DeadLetters: []DeadLetterResponse{{
+				ID:        event.ID,
+				Topic:     "transactions",
+				Key:       "txn-1",
+				Payload:   json.RawMessage(`{"transaction_id":"txn-1"}`),
+				Attempts:  3,
+				LastError: "broker down",
+				CreatedAt: createdAt,
+			}}},
+		},
+		{
+			name:  "binary_payload",
+			query: "?limit=1",
+			mockSvc: func() {
+				avroEvent := event
+				avroEvent.Payload = []byte{0x00, 0x00, 0x00, 0x00, 0x07}
+				mockSvc.EXPECT().ListPending(gomock.Any(), 1).Return([]models.DeadLetterEvent{avroEvent}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: DeadLettersResponse{DeadLetters: []DeadLetterResponse{{
+				ID:        event.ID,
+				Topic:     "transactions",
+				Key:       "txn-1",
+				Payload:   json.RawMessage(`"AAAAAAc="`),
+				Attempts:  3,
+				LastError: "broker down",
+				CreatedAt: createdAt,
+			}}},
+		},
+		{
+			name:  "with_limit",
+			query: "?limit=10",
+			mockSvc: func() {
+				mockSvc.EXPECT().ListPending(gomock.Any(), 10).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   DeadLettersResponse{DeadLetters: []DeadLetterResponse{}},
+		},
+		{
+			name:           "invalid_limit",
+			query:          "?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid limit", Code: "invalid_limit"},
+		},
+		{
+			name: "internal_error",
+			mockSvc: func() {
+				mockSvc.EXPECT().ListPending(gomock.Any(), 0).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/dead-letters"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			switch expected := tt.expectedBody.(type) {
+			case DeadLettersResponse:
+				var got DeadLettersResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestReplayDeadLetterHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockDeadLetterTokener(ctrl)
+	mockSvc := NewMockDeadLetterManager(ctrl)
+	handler := NewReplayDeadLetterHandler(mockSvc, mockTokener)
+
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).AnyTimes().Return("admin-token", nil)
+	mockTokener.EXPECT().GetClaims(gomock.Any(), "admin-token").AnyTimes().Return(&jwt.Claims{UserID: uuid.New(), Role: "admin"}, nil)
+
+	id := uuid.New()
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	replayedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		deadLetterID   string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:         "success",
+			deadLetterID: id.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Replay(gomock.Any(), id).Return(models.DeadLetterEvent{
+					ID: id, Key: "txn-1", Payload: []byte(`{}`), Attempts: 3, LastError: "broker down",
+					CreatedAt: createdAt, ReplayedAt: &replayedAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: DeadLetterResponse{
+				ID: id, Key: "txn-1", Payload: json.RawMessage(`{}`), Attempts: 3, LastError: "broker down",
+				CreatedAt: createdAt, ReplayedAt: &replayedAt,
+			},
+		},
+		{
+			name:           "invalid_id",
+			deadLetterID:   "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid dead letter ID", Code: "invalid_dead_letter_id"},
+		},
+		{
+			name:         "not_found",
+			deadLetterID: id.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Replay(gomock.Any(), id).Return(models.DeadLetterEvent{}, services.ErrDeadLetterNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Dead letter not found", Code: "dead_letter_not_found"},
+		},
+		{
+			name:         "already_replayed",
+			deadLetterID: id.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Replay(gomock.Any(), id).Return(models.DeadLetterEvent{}, services.ErrDeadLetterReplayed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Dead letter already replayed", Code: "dead_letter_already_replayed"},
+		},
+		{
+			name:         "kafka_unavailable",
+			deadLetterID: id.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Replay(gomock.Any(), id).Return(models.DeadLetterEvent{}, services.ErrDeadLetterReplayFailed)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Kafka unavailable", Code: "kafka_unavailable"},
+		},
+		{
+			name:         "internal_error",
+			deadLetterID: id.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Replay(gomock.Any(), id).Return(models.DeadLetterEvent{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/dead-letters/"+tt.deadLetterID+"/replay", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("deadLetterID", tt.deadLetterID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			switch expected := tt.expectedBody.(type) {
+			case DeadLetterResponse:
+				var got DeadLetterResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected.ID, got.ID)
+				assert.JSONEq(t, string(expected.Payload), string(got.Payload))
+				assert.True(t, expected.ReplayedAt.Equal(*got.ReplayedAt))
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
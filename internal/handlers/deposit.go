@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
 )
 
 // DepositTokener defines only the methods needed by this handler.
@@ -18,24 +22,12 @@ type DepositTokener interface {
 
 // DepositWriter defines the interface that the service must implement.
 type DepositWriter interface {
-	Deposit(ctx context.Context, userID uuid.UUID, amount float64, currency string) (usd, rub, eur float64, err error)
+	Deposit(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error)
 }
 
-// CurrencyBalanceAfterDeposit represents balances for different currencies
+// CurrencyBalanceAfterDeposit represents balances keyed by currency code
 // swagger:model CurrencyDeposit
-type CurrencyBalanceAfterDeposit struct {
-	// Balance in USD
-	// default: 100.0
-	USD float64 `json:"USD"`
-
-	// Balance in RUB
-	// default: 5000.0
-	RUB float64 `json:"RUB"`
-
-	// Balance in EUR
-	// default: 50.0
-	EUR float64 `json:"EUR"`
-}
+type CurrencyBalanceAfterDeposit map[string]money.Amount
 
 // DepositRequest represents the JSON body for depositing funds
 // swagger:model DepositRequest
@@ -43,12 +35,16 @@ type DepositRequest struct {
 	// Amount to deposit
 	// required: true
 	// default: 100.0
-	Amount float64 `json:"amount"`
+	Amount money.Amount `json:"amount" swaggertype:"number" validate:"gt=0"`
 
 	// Currency
 	// required: true
 	// default: USD
-	Currency string `json:"currency"`
+	Currency string `json:"currency" validate:"required"`
+
+	// Client reference for reconciliation, up to 128 characters; returned in the history
+	// default: INV-2024-0042
+	Reference string `json:"reference,omitempty" validate:"max=128"`
 }
 
 // DepositResponse represents a successful deposit response
@@ -59,15 +55,7 @@ type DepositResponse struct {
 	Message string `json:"message"`
 
 	// New balance of the user
-	NewBalance CurrencyBalanceAfterDeposit `json:"new_balance"`
-}
-
-// DepositErrorResponse represents an error response for deposit
-// swagger:model DepositErrorResponse
-type DepositErrorResponse struct {
-	// Error message
-	// default: Invalid amount or currency
-	Error string `json:"error"`
+	NewBalance CurrencyBalanceAfterDeposit `json:"new_balance" swaggertype:"object,number"`
 }
 
 // NewDepositHandler returns an HTTP handler for depositing funds into user wallet.
@@ -77,80 +65,68 @@ type DepositErrorResponse struct {
 // @Accept json
 // @Produce json
 // @Param request body handlers.DepositRequest true "Deposit Request"
+// @Param Api-Version header string false "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)"
 // @Success 200 {object} handlers.DepositResponse "Account topped up successfully"
-// @Failure 400 {object} handlers.DepositErrorResponse "Invalid amount or currency"
-// @Failure 401 {object} handlers.DepositErrorResponse "Unauthorized"
+// @Failure 400 {object} apperrors.Response "Invalid amount, currency or reference"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 409 {object} apperrors.Response "Another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
 // @Router /wallet/deposit [post]
 // @Security BearerAuth
 func NewDepositHandler(
 	svc DepositWriter,
 	tokenGetter DepositTokener,
+	currencies CurrencyChecker,
+	legacyDeprecation time.Time,
 ) http.HandlerFunc {
-	validCurrencies := map[string]struct{}{
-		"USD": {},
-		"RUB": {},
-		"EUR": {},
-	}
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
 		if err != nil {
-			logger.Log.Errorw("failed to get token from request", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(DepositErrorResponse{Error: "Unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
 		if err != nil {
-			logger.Log.Errorw("failed to get claims from token", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(DepositErrorResponse{Error: "Unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		var req DepositRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			logger.Log.Errorw("failed to decode deposit request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(DepositErrorResponse{Error: "Invalid request body"})
+			logger.FromContext(ctx).Errorw("failed to decode deposit request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
 			return
 		}
 
-		if req.Amount <= 0 {
-			logger.Log.Warnw("invalid deposit amount", "amount", req.Amount)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(DepositErrorResponse{Error: "Invalid amount or currency"})
+		if errs := validation.Struct(req); errs != nil {
+			logger.FromContext(ctx).Warnw("invalid deposit request", "error", errs)
+			writeInvalid(w, errs, apperrors.InvalidAmountOrCurrency, map[string]apperrors.Error{"reference": apperrors.InvalidReference})
 			return
 		}
-		if _, ok := validCurrencies[req.Currency]; !ok {
-			logger.Log.Warnw("invalid deposit currency", "currency", req.Currency)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(DepositErrorResponse{Error: "Invalid amount or currency"})
+		if !currencies.IsSupported(ctx, req.Currency) || !currencies.ValidAmount(ctx, req.Currency, req.Amount) {
+			logger.FromContext(ctx).Warnw("invalid deposit currency", "currency", req.Currency)
+			apperrors.Write(w, apperrors.InvalidAmountOrCurrency)
 			return
 		}
 
-		usd, rub, eur, err := svc.Deposit(ctx, claims.UserID, req.Amount, req.Currency)
+		balances, err := svc.Deposit(ctx, claims.UserID, req.Amount, req.Currency, req.Reference)
 		if err != nil {
-			logger.Log.Errorw("failed to deposit funds", "userID", claims.UserID, "amount", req.Amount, "currency", req.Currency, "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(DepositErrorResponse{Error: "Internal server error"})
+			logger.FromContext(ctx).Errorw("failed to deposit funds", "userID", claims.UserID, "amount", req.Amount, "currency", req.Currency, "error", err)
+			apperrors.Write(w, apperrors.Internal)
 			return
 		}
 
-		newBalance := CurrencyBalanceAfterDeposit{
-			USD: usd,
-			RUB: rub,
-			EUR: eur,
-		}
-
 		resp := DepositResponse{
 			Message:    "Account topped up successfully",
-			NewBalance: newBalance,
+			NewBalance: renderBalances(r, balances),
 		}
 
+		setBalanceSchemaHeaders(w, r, legacyDeprecation)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(resp)
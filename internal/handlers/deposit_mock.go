@@ -12,6 +12,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
 )
 
 // MockDepositTokener is a mock of DepositTokener interface.
@@ -91,18 +92,16 @@ func (m *MockDepositWriter) EXPECT() *MockDepositWriterMockRecorder {
 }
 
 // Deposit mocks base method.
-func (m *MockDepositWriter) Deposit(ctx context.Context, userID uuid.UUID, amount float64, currency string) (float64, float64, float64, error) {
+func (m *MockDepositWriter) Deposit(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount, currency)
-	ret0, _ := ret[0].(float64)
-	ret1, _ := ret[1].(float64)
-	ret2, _ := ret[2].(float64)
-	ret3, _ := ret[3].(error)
-	return ret0, ret1, ret2, ret3
+	ret := m.ctrl.Call(m, "Deposit", ctx, userID, amount, currency, reference)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Deposit indicates an expected call of Deposit.
-func (mr *MockDepositWriterMockRecorder) Deposit(ctx, userID, amount, currency interface{}) *gomock.Call {
+func (mr *MockDepositWriterMockRecorder) Deposit(ctx, userID, amount, currency, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockDepositWriter)(nil).Deposit), ctx, userID, amount, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockDepositWriter)(nil).Deposit), ctx, userID, amount, currency, reference)
 }
@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,17 +31,46 @@ func TestDepositHandler(t *testing.T) {
 		{
 			name: "successful deposit",
 			requestBody: DepositRequest{
-				Amount:   100.0,
+				Amount:   money.MustParse("100"),
 				Currency: "USD",
 			},
 			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
 				mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(validToken, nil)
 				mockTokener.EXPECT().GetClaims(gomock.Any(), validToken).Return(&jwt.Claims{UserID: userID}, nil)
-				mockWriter.EXPECT().Deposit(gomock.Any(), userID, 100.0, "USD").Return(200.0, 5000.0, 50.0, nil)
+				mockWriter.EXPECT().Deposit(gomock.Any(), userID, money.MustParse("100"), "USD", "").Return(map[string]money.Amount{"USD": money.MustParse("200"), "RUB": money.MustParse("5000"), "EUR": money.MustParse("50")}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedKey:        "message",
 		},
+		{
+			name: "deposit with reference",
+			requestBody: DepositRequest{
+				Amount:    money.MustParse("100"),
+				Currency:  "USD",
+				Reference: strings.Repeat("я", models.MaxReferenceLength),
+			},
+			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
+				mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(validToken, nil)
+				mockTokener.EXPECT().GetClaims(gomock.Any(), validToken).Return(&jwt.Claims{UserID: userID}, nil)
+				mockWriter.EXPECT().Deposit(gomock.Any(), userID, money.MustParse("100"), "USD", strings.Repeat("я", models.MaxReferenceLength)).Return(map[string]money.Amount{"USD": money.MustParse("100")}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedKey:        "message",
+		},
+		{
+			name: "reference too long",
+			requestBody: DepositRequest{
+				Amount:    money.MustParse("100"),
+				Currency:  "USD",
+				Reference: strings.Repeat("a", models.MaxReferenceLength+1),
+			},
+			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
+				mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(validToken, nil)
+				mockTokener.EXPECT().GetClaims(gomock.Any(), validToken).Return(&jwt.Claims{UserID: userID}, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedKey:        "error",
+		},
 		{
 			name:        "invalid request body",
 			requestBody: "invalid-json",
@@ -52,10 +85,20 @@ func TestDepositHandler(t *testing.T) {
 			expectedStatusCode: http.StatusBadRequest,
 			expectedKey:        "error",
 		},
+		{
+			name:        "amount with more than two decimals",
+			requestBody: `{"amount": 10.001, "currency": "USD"}`,
+			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
+				mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(validToken, nil)
+				mockTokener.EXPECT().GetClaims(gomock.Any(), validToken).Return(&jwt.Claims{UserID: userID}, nil)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedKey:        "error",
+		},
 		{
 			name: "unauthorized missing token",
 			requestBody: DepositRequest{
-				Amount:   100.0,
+				Amount:   money.MustParse("100"),
 				Currency: "USD",
 			},
 			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
@@ -67,7 +110,7 @@ func TestDepositHandler(t *testing.T) {
 		{
 			name: "unauthorized invalid token",
 			requestBody: DepositRequest{
-				Amount:   100.0,
+				Amount:   money.MustParse("100"),
 				Currency: "USD",
 			},
 			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
@@ -80,7 +123,7 @@ func TestDepositHandler(t *testing.T) {
 		{
 			name: "invalid amount",
 			requestBody: DepositRequest{
-				Amount:   -10.0,
+				Amount:   money.MustParse("-10"),
 				Currency: "USD",
 			},
 			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
@@ -93,7 +136,7 @@ func TestDepositHandler(t *testing.T) {
 		{
 			name: "invalid currency",
 			requestBody: DepositRequest{
-				Amount:   100.0,
+				Amount:   money.MustParse("100"),
 				Currency: "BTC",
 			},
 			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
@@ -106,13 +149,13 @@ func TestDepositHandler(t *testing.T) {
 		{
 			name: "internal server error from writer",
 			requestBody: DepositRequest{
-				Amount:   100.0,
+				Amount:   money.MustParse("100"),
 				Currency: "USD",
 			},
 			setupMocks: func(mockWriter *MockDepositWriter, mockTokener *MockDepositTokener) {
 				mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return(validToken, nil)
 				mockTokener.EXPECT().GetClaims(gomock.Any(), validToken).Return(&jwt.Claims{UserID: userID}, nil)
-				mockWriter.EXPECT().Deposit(gomock.Any(), userID, 100.0, "USD").Return(0.0, 0.0, 0.0, assert.AnError)
+				mockWriter.EXPECT().Deposit(gomock.Any(), userID, money.MustParse("100"), "USD", "").Return(nil, assert.AnError)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedKey:        "error",
@@ -140,7 +183,7 @@ func TestDepositHandler(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, "/wallet/deposit", bytes.NewReader(bodyBytes))
 			rr := httptest.NewRecorder()
 
-			handler := NewDepositHandler(mockWriter, mockTokener)
+			handler := NewDepositHandler(mockWriter, mockTokener, newMockCurrencies(ctrl), time.Time{})
 			handler.ServeHTTP(rr, req)
 
 			assert.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -154,3 +197,26 @@ func TestDepositHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestDepositHandler_CurrencyAmountRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockDepositTokener(ctrl)
+	mockWriter := NewMockDepositWriter(ctrl)
+	currencies := NewMockCurrencyChecker(ctrl)
+
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("valid-token", nil)
+	mockTokener.EXPECT().GetClaims(gomock.Any(), "valid-token").Return(&jwt.Claims{UserID: uuid.New()}, nil)
+	currencies.EXPECT().IsSupported(gomock.Any(), "JPY").Return(true)
+	currencies.EXPECT().ValidAmount(gomock.Any(), "JPY", money.MustParse("100.50")).Return(false)
+
+	body, _ := json.Marshal(DepositRequest{Amount: money.MustParse("100.50"), Currency: "JPY"})
+	req := httptest.NewRequest(http.MethodPost, "/wallet/deposit", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	NewDepositHandler(mockWriter, mockTokener, currencies, time.Time{}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":"Invalid amount or currency","code":"invalid_amount_or_currency"}`, rr.Body.String())
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// DormancyTokener defines only the methods needed by the dormancy handlers.
+type DormancyTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// Reactivator defines the interface for lifting the dormant flag after re-verification.
+type Reactivator interface {
+	Reactivate(ctx context.Context, userID uuid.UUID, password string) error
+}
+
+// DormancyOverrider defines the interface for admin dormancy overrides.
+type DormancyOverrider interface {
+	SetDormant(ctx context.Context, adminID, userID uuid.UUID, dormant bool) error
+}
+
+// ReactivateRequest represents the re-verification request body
+// swagger:model ReactivateRequest
+type ReactivateRequest struct {
+	// Current account password
+	// required: true
+	// default: strongpassword123
+	Password string `json:"password"`
+}
+
+// DormancyResponse represents a successful dormancy change
+// swagger:model DormancyResponse
+type DormancyResponse struct {
+	// Whether the account is now dormant
+	// default: false
+	Dormant bool `json:"dormant"`
+}
+
+// NewReactivateHandler returns an HTTP handler that lets a dormant account re-verify itself.
+// @Summary Reactivate a dormant account
+// @Description Re-verifies the account password and lifts the cold storage restriction on wallet operations.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body handlers.ReactivateRequest true "Re-verification data"
+// @Success 200 {object} handlers.DormancyResponse "Account reactivated"
+// @Failure 400 {object} apperrors.Response "Invalid request"
+// @Failure 401 {object} apperrors.Response "Unauthorized or invalid password"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /me/reactivate [post]
+// @Security BearerAuth
+func NewReactivateHandler(
+	tokenGetter DormancyTokener,
+	svc Reactivator,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		var req ReactivateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		if err := svc.Reactivate(ctx, claims.UserID, req.Password); err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidCredentials), errors.Is(err, services.ErrUserDoesNotExist):
+				apperrors.Write(w, apperrors.InvalidPassword)
+			default:
+				logger.FromContext(ctx).Errorw("failed to reactivate account", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(DormancyResponse{Dormant: false})
+	}
+}
+
+// NewSetDormantHandler returns an HTTP handler that lets an admin flag an account as dormant.
+// @Summary Flag an account as dormant
+// @Description Moves the account into cold storage. The override is recorded in the audit trail.
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} handlers.DormancyResponse "Account flagged"
+// @Failure 400 {object} apperrors.Response "Invalid user ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "User not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/users/{userID}/dormant [put]
+// @Security BearerAuth
+func NewSetDormantHandler(svc DormancyOverrider, tokenGetter DormancyTokener) http.HandlerFunc {
+	return newDormancyOverrideHandler(svc, tokenGetter, true)
+}
+
+// NewClearDormantHandler returns an HTTP handler that lets an admin lift the dormant flag.
+// @Summary Clear the dormant flag
+// @Description Lifts the cold storage restriction without re-verification. The override is recorded in the audit trail.
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} handlers.DormancyResponse "Account reactivated"
+// @Failure 400 {object} apperrors.Response "Invalid user ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "User not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/users/{userID}/dormant [delete]
+// @Security BearerAuth
+func NewClearDormantHandler(svc DormancyOverrider, tokenGetter DormancyTokener) http.HandlerFunc {
+	return newDormancyOverrideHandler(svc, tokenGetter, false)
+}
+
+func newDormancyOverrideHandler(svc DormancyOverrider, tokenGetter DormancyTokener, dormant bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+		if err != nil {
+			logger.FromContext(ctx).Warnw("invalid dormancy user ID", "userID", chi.URLParam(r, "userID"), "error", err)
+			apperrors.Write(w, apperrors.InvalidUserID)
+			return
+		}
+
+		if err := svc.SetDormant(ctx, claims.UserID, userID, dormant); err != nil {
+			if errors.Is(err, services.ErrUserDoesNotExist) {
+				apperrors.Write(w, apperrors.UserNotFound)
+				return
+			}
+			logger.FromContext(ctx).Errorw("failed to override dormancy", "adminID", claims.UserID, "userID", userID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(DormancyResponse{Dormant: dormant})
+	}
+}
@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/dormancy.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+)
+
+// MockDormancyTokener is a mock of DormancyTokener interface.
+type MockDormancyTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockDormancyTokenerMockRecorder
+}
+
+// MockDormancyTokenerMockRecorder is the mock recorder for MockDormancyTokener.
+type MockDormancyTokenerMockRecorder struct {
+	mock *MockDormancyTokener
+}
+
+// NewMockDormancyTokener creates a new mock instance.
+func NewMockDormancyTokener(ctrl *gomock.Controller) *MockDormancyTokener {
+	mock := &MockDormancyTokener{ctrl: ctrl}
+	mock.recorder = &MockDormancyTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDormancyTokener) EXPECT() *MockDormancyTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockDormancyTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockDormancyTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockDormancyTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockDormancyTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockDormancyTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockDormancyTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockReactivator is a mock of Reactivator interface.
+type MockReactivator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReactivatorMockRecorder
+}
+
+// MockReactivatorMockRecorder is the mock recorder for MockReactivator.
+type MockReactivatorMockRecorder struct {
+	mock *MockReactivator
+}
+
+// NewMockReactivator creates a new mock instance.
+func NewMockReactivator(ctrl *gomock.Controller) *MockReactivator {
+	mock := &MockReactivator{ctrl: ctrl}
+	mock.recorder = &MockReactivatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReactivator) EXPECT() *MockReactivatorMockRecorder {
+	return m.recorder
+}
+
+// Reactivate mocks base method.
+func (m *MockReactivator) Reactivate(ctx context.Context, userID uuid.UUID, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reactivate", ctx, userID, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reactivate indicates an expected call of Reactivate.
+func (mr *MockReactivatorMockRecorder) Reactivate(ctx, userID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reactivate", reflect.TypeOf((*MockReactivator)(nil).Reactivate), ctx, userID, password)
+}
+
+// MockDormancyOverrider is a mock of DormancyOverrider interface.
+type MockDormancyOverrider struct {
+	ctrl     *gomock.Controller
+	recorder *MockDormancyOverriderMockRecorder
+}
+
+// MockDormancyOverriderMockRecorder is the mock recorder for MockDormancyOverrider.
+type MockDormancyOverriderMockRecorder struct {
+	mock *MockDormancyOverrider
+}
+
+// NewMockDormancyOverrider creates a new mock instance.
+func NewMockDormancyOverrider(ctrl *gomock.Controller) *MockDormancyOverrider {
+	mock := &MockDormancyOverrider{ctrl: ctrl}
+	mock.recorder = &MockDormancyOverriderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDormancyOverrider) EXPECT() *MockDormancyOverriderMockRecorder {
+	return m.recorder
+}
+
+// SetDormant mocks base method.
+func (m *MockDormancyOverrider) SetDormant(ctx context.Context, adminID, userID uuid.UUID, dormant bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDormant", ctx, adminID, userID, dormant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDormant indicates an expected call of SetDormant.
+func (mr *MockDormancyOverriderMockRecorder) SetDormant(ctx, adminID, userID, dormant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDormant", reflect.TypeOf((*MockDormancyOverrider)(nil).SetDormant), ctx, adminID, userID, dormant)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReactivateHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockDormancyTokener(ctrl)
+	mockSvc := NewMockReactivator(ctrl)
+
+	userID := uuid.New()
+
+	handler := NewReactivateHandler(mockTokener, mockSvc)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: `{"password":"secret"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().Reactivate(gomock.Any(), userID, "secret").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   DormancyResponse{Dormant: false},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:           "empty_password",
+			reqBody:        `{"password":""}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:    "invalid_password",
+			reqBody: `{"password":"wrong"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().Reactivate(gomock.Any(), userID, "wrong").Return(services.ErrInvalidCredentials)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   apperrors.Response{Error: "Invalid password", Code: "invalid_password"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: `{"password":"secret"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().Reactivate(gomock.Any(), userID, "secret").Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/me/reactivate", bytes.NewBufferString(tt.reqBody))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertDormancyBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestDormancyOverrideHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockDormancyTokener(ctrl)
+	mockSvc := NewMockDormancyOverrider(ctrl)
+
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		userID         string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "set_success",
+			handler: NewSetDormantHandler(mockSvc, mockTokener),
+			userID:  userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().SetDormant(gomock.Any(), adminID, userID, true).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   DormancyResponse{Dormant: true},
+		},
+		{
+			name:    "clear_success",
+			handler: NewClearDormantHandler(mockSvc, mockTokener),
+			userID:  userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().SetDormant(gomock.Any(), adminID, userID, false).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   DormancyResponse{Dormant: false},
+		},
+		{
+			name:           "invalid_user_id",
+			handler:        NewSetDormantHandler(mockSvc, mockTokener),
+			userID:         "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid user ID", Code: "invalid_user_id"},
+		},
+		{
+			name:    "user_not_found",
+			handler: NewSetDormantHandler(mockSvc, mockTokener),
+			userID:  userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().SetDormant(gomock.Any(), adminID, userID, true).Return(services.ErrUserDoesNotExist)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "User not found", Code: "user_not_found"},
+		},
+		{
+			name:    "internal_error",
+			handler: NewClearDormantHandler(mockSvc, mockTokener),
+			userID:  userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().SetDormant(gomock.Any(), adminID, userID, false).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/"+tt.userID+"/dormant", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userID", tt.userID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertDormancyBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestDormancyHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockDormancyTokener(ctrl)
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("", errors.New("no token"))
+
+	handlers := map[string]http.HandlerFunc{
+		"reactivate": NewReactivateHandler(mockTokener, NewMockReactivator(ctrl)),
+		"set":        NewSetDormantHandler(NewMockDormancyOverrider(ctrl), mockTokener),
+		"clear":      NewClearDormantHandler(NewMockDormancyOverrider(ctrl), mockTokener),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assertDormancyBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Unauthorized", Code: "unauthorized"})
+		})
+	}
+}
+
+func assertDormancyBody(t *testing.T, body []byte, expectedBody interface{}) {
+	t.Helper()
+	switch expected := expectedBody.(type) {
+	case DormancyResponse:
+		var got DormancyResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	case apperrors.Response:
+		var got apperrors.Response
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	}
+}
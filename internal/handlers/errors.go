@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
+)
+
+// ErrorCatalogEntry describes an error the API can return
+// swagger:model ErrorCatalogEntry
+type ErrorCatalogEntry struct {
+	// Machine-readable error code
+	// default: insufficient_funds_withdraw
+	Code string `json:"code"`
+
+	// HTTP status of the response
+	// default: 400
+	Status int `json:"status"`
+
+	// Value of the "error" field of the response
+	// default: Insufficient funds or invalid amount
+	Message string `json:"message"`
+
+	// When the error is returned
+	// default: The balance is lower than the withdrawal amount, or the amount or currency is invalid.
+	Description string `json:"description"`
+}
+
+// ErrorCatalogResponse represents the catalog of API errors
+// swagger:model ErrorCatalogResponse
+type ErrorCatalogResponse struct {
+	// Version of the catalog, incremented on incompatible changes
+	// default: 1
+	SchemaVersion int `json:"schema_version"`
+
+	// Errors ordered by code
+	Errors []ErrorCatalogEntry `json:"errors"`
+}
+
+// NewErrorCatalogHandler returns an HTTP handler that lists every error the API can return.
+// @Summary List API errors
+// @Description Returns every machine-readable error code with its HTTP status, the message returned in the "error" field and a description. The schema version changes when an entry is removed or altered.
+// @Tags errors
+// @Produce json
+// @Success 200 {object} handlers.ErrorCatalogResponse "Error catalog"
+// @Router /errors [get]
+func NewErrorCatalogHandler() http.HandlerFunc {
+	catalog := apperrors.Catalog()
+	resp := ErrorCatalogResponse{
+		SchemaVersion: apperrors.SchemaVersion,
+		Errors:        make([]ErrorCatalogEntry, 0, len(catalog)),
+	}
+	for _, e := range catalog {
+		resp.Errors = append(resp.Errors, ErrorCatalogEntry{
+			Code:        e.Code,
+			Status:      e.Status,
+			Message:     e.Message,
+			Description: e.Description,
+		})
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// writeInvalid renders the invalid fields of a request as details of the error of the first
+// invalid field in byField, or of fallback if it is not there.
+func writeInvalid(w http.ResponseWriter, errs validation.Errors, fallback apperrors.Error, byField map[string]apperrors.Error) {
+	e, ok := byField[errs[0].Field]
+	if !ok {
+		e = fallback
+	}
+	apperrors.WriteDetails(w, e, errs)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCatalogHandler(t *testing.T) {
+	handler := NewErrorCatalogHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/errors", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var got ErrorCatalogResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, apperrors.SchemaVersion, got.SchemaVersion)
+	assert.Len(t, got.Errors, len(apperrors.Catalog()))
+	assert.Contains(t, got.Errors, ErrorCatalogEntry{
+		Code:        apperrors.DailyLimitExceeded.Code,
+		Status:      http.StatusForbidden,
+		Message:     "Daily limit exceeded",
+		Description: apperrors.DailyLimitExceeded.Description,
+	})
+}
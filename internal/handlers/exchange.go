@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
 )
 
 // ExchangeRateForCurrencyTokener is responsible for extracting and validating JWT tokens
@@ -19,45 +24,40 @@ type ExchangeRateForCurrencyTokener interface {
 	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
 }
 
-// Exchanger
+// Exchanger executes exchanges at the current rate or at the rate of a locked quote.
 type Exchanger interface {
-	Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount float64) (exchangedAmount float32, usd, rub, eur float64, err error)
+	Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (executed models.ExchangeQuote, balances map[string]money.Amount, err error)
+	ExchangeQuoted(ctx context.Context, userID, quoteID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (executed models.ExchangeQuote, balances map[string]money.Amount, err error)
 }
 
 // ExchangeRequest represents the JSON body for currency exchange
 // swagger:model ExchangeRequest
 type ExchangeRequest struct {
-	// Source currency
-	// required: true
+	// ID of a quote from GET /exchange/quote to execute at its rate. The currencies
+	// and amount may then be omitted; if set, they must match the quote.
+	QuoteID string `json:"quote_id,omitempty" validate:"omitempty,uuid"`
+
+	// Source currency, required without quote_id
 	// default: USD
-	FromCurrency string `json:"from_currency"`
+	FromCurrency string `json:"from_currency" validate:"required_without=QuoteID"`
 
-	// Target currency
-	// required: true
+	// Target currency, required without quote_id
 	// default: EUR
-	ToCurrency string `json:"to_currency"`
+	ToCurrency string `json:"to_currency" validate:"required_without=QuoteID"`
 
-	// Amount to exchange
-	// required: true
+	// Amount to exchange, required without quote_id
 	// default: 100.0
-	Amount float64 `json:"amount"`
+	Amount money.Amount `json:"amount" swaggertype:"number" validate:"required_without=QuoteID,gte=0"`
+
+	// Least amount to receive in the target currency, optional. If the rate moved so that
+	// less would be received, the exchange is rejected with 409 instead of executed.
+	// default: 91.5
+	MinExpectedAmount money.Amount `json:"min_expected_amount,omitempty" swaggertype:"number" validate:"gte=0"`
 }
 
-// ExchangedBalance represents balances for different currencies
+// ExchangedBalance represents balances keyed by currency code
 // swagger:model ExchangedBalance
-type ExchangedBalance struct {
-	// Balance in USD
-	// default: 100.0
-	USD float64 `json:"USD"`
-
-	// Balance in RUB
-	// default: 5000.0
-	RUB float64 `json:"RUB"`
-
-	// Balance in EUR
-	// default: 50.0
-	EUR float64 `json:"EUR"`
-}
+type ExchangedBalance map[string]money.Amount
 
 // ExchangeResponse represents a successful currency exchange response
 // swagger:model ExchangeResponse
@@ -68,90 +68,158 @@ type ExchangeResponse struct {
 
 	// Amount received after exchange
 	// default: 85.0
-	ExchangedAmount float64 `json:"exchanged_amount"`
+	ExchangedAmount money.Amount `json:"exchanged_amount" swaggertype:"number"`
+
+	// Fee in the source currency, deducted from the amount before conversion
+	// default: 0
+	Fee money.Amount `json:"fee" swaggertype:"number"`
+
+	// Rate the amount less the fee was converted at, spread included
+	// default: 0.85
+	Rate float32 `json:"rate"`
 
 	// New balance after exchange
-	NewBalance ExchangedBalance `json:"new_balance"`
-}
+	NewBalance ExchangedBalance `json:"new_balance" swaggertype:"object,number"`
+
+	// Whether a cached rate past its TTL was used because the exchange service was unavailable
+	// default: false
+	StaleRate bool `json:"stale_rate"`
 
-// ExchangeErrorResponse represents an error response for currency exchange
-// swagger:model ExchangeErrorResponse
-type ExchangeErrorResponse struct {
-	// Error message
-	// default: Insufficient funds or invalid currencies
-	Error string `json:"error"`
+	// Whether the rate was derived through the pivot currency because the exchange service has no direct rate for the pair
+	// default: false
+	DerivedRate bool `json:"derived_rate"`
+
+	// When the rate was fetched from the provider; for a derived rate, the older of its legs
+	// default: 2025-03-14T09:30:00Z
+	RateFetchedAt time.Time `json:"rate_fetched_at"`
+
+	// Whether the rate was served from the cache
+	// default: true
+	RateCached bool `json:"rate_cached"`
+
+	// Rate provider the rate came from: grpc (the exchange service) or http (the fallback rates API)
+	// default: grpc
+	RateProvider string `json:"rate_provider"`
 }
 
 // NewExchangeHandler handles currency exchange requests.
 // @Summary Exchange currency
-// @Description Exchange funds from one currency to another. Checks user balance and updates it accordingly.
+// @Description Exchange funds from one currency to another. Checks user balance and updates it accordingly. The fee configured for the currency pair is deducted from the amount before conversion at the rate less the spread. With quote_id, the locked quote is executed at its rate; a quote is used up by the first attempt. With min_expected_amount, the exchange is rejected if it would credit less.
 // @Tags exchange
 // @Accept json
 // @Produce json
 // @Param request body handlers.ExchangeRequest true "Exchange Request"
+// @Param Api-Version header string false "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)"
 // @Success 200 {object} handlers.ExchangeResponse "Exchange successful"
-// @Failure 400 {object} handlers.ExchangeErrorResponse "Insufficient funds or invalid currencies"
-// @Failure 401 {object} handlers.ExchangeErrorResponse "Unauthorized"
+// @Failure 400 {object} apperrors.Response "Insufficient funds, invalid currencies, amount out of range or not covering the fee"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Daily or monthly limit exceeded"
+// @Failure 404 {object} apperrors.Response "Exchange rate not found"
+// @Failure 409 {object} apperrors.Response "Quote expired, rate moved below min_expected_amount or another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Failure 503 {object} apperrors.Response "Exchange service unavailable"
+// @Failure 504 {object} apperrors.Response "Exchange service timeout"
 // @Router /exchange [post]
 // @Security BearerAuth
 func NewExchangeHandler(
 	tokener ExchangeRateForCurrencyTokener,
 	exchanger Exchanger,
+	currencies CurrencyChecker,
+	legacyDeprecation time.Time,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		tokenStr, err := tokener.GetTokenFromRequest(ctx, r)
 		if err != nil {
-			logger.Log.Errorw("failed to get token from request", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(ExchangeErrorResponse{Error: "unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		claims, err := tokener.GetClaims(ctx, tokenStr)
 		if err != nil {
-			logger.Log.Errorw("failed to get claims from token", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(ExchangeErrorResponse{Error: "unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 		userID := claims.UserID
 
 		var req ExchangeRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 {
-			logger.Log.Errorw("invalid exchange request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(ExchangeErrorResponse{Error: "Insufficient funds or invalid currencies"})
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Errorw("failed to decode exchange request", "error", err)
+			apperrors.Write(w, apperrors.InsufficientFundsExchange)
+			return
+		}
+		if errs := validation.Struct(req); errs != nil {
+			logger.FromContext(ctx).Warnw("invalid exchange request", "error", errs, "userID", userID)
+			writeInvalid(w, errs, apperrors.InsufficientFundsExchange, map[string]apperrors.Error{"quote_id": apperrors.InvalidQuoteID})
 			return
 		}
 
-		exchangedAmount, usd, rub, eur, err := exchanger.Exchange(ctx, userID, req.FromCurrency, req.ToCurrency, req.Amount)
+		var (
+			executed models.ExchangeQuote
+			balances map[string]money.Amount
+		)
+		if req.QuoteID != "" {
+			quoteID := uuid.MustParse(req.QuoteID)
+			executed, balances, err = exchanger.ExchangeQuoted(ctx, userID, quoteID, req.FromCurrency, req.ToCurrency, req.Amount, req.MinExpectedAmount)
+		} else {
+			if req.FromCurrency == req.ToCurrency ||
+				!currencies.IsSupported(ctx, req.FromCurrency) || !currencies.IsSupported(ctx, req.ToCurrency) ||
+				!currencies.ValidAmount(ctx, req.FromCurrency, req.Amount) {
+				logger.FromContext(ctx).Warnw("invalid exchange currencies", "from", req.FromCurrency, "to", req.ToCurrency, "userID", userID)
+				apperrors.Write(w, apperrors.InsufficientFundsExchange)
+				return
+			}
+			executed, balances, err = exchanger.Exchange(ctx, userID, req.FromCurrency, req.ToCurrency, req.Amount, req.MinExpectedAmount)
+		}
 		if err != nil {
-			logger.Log.Error(err)
+			logger.FromContext(ctx).Error(err)
 			switch {
+			case errors.Is(err, services.ErrQuoteExpired):
+				apperrors.Write(w, apperrors.QuoteExpired)
+			case errors.Is(err, services.ErrQuoteMismatch):
+				apperrors.Write(w, apperrors.QuoteMismatch)
+			case errors.Is(err, services.ErrFeeExceedsAmount):
+				apperrors.Write(w, apperrors.FeeExceedsAmount)
+			case errors.Is(err, services.ErrExchangeAmountOutOfRange):
+				apperrors.Write(w, apperrors.ExchangeAmountOutOfRange)
+			case errors.Is(err, services.ErrSlippageExceeded):
+				apperrors.Write(w, apperrors.SlippageExceeded)
 			case errors.Is(err, services.ErrInsufficientFunds):
-				w.WriteHeader(http.StatusBadRequest)
-				json.NewEncoder(w).Encode(ExchangeErrorResponse{Error: "Insufficient funds or invalid currencies"})
+				apperrors.Write(w, apperrors.InsufficientFundsExchange)
+			case errors.Is(err, services.ErrDailyLimitExceeded):
+				apperrors.Write(w, apperrors.DailyLimitExceeded)
+			case errors.Is(err, services.ErrMonthlyLimitExceeded):
+				apperrors.Write(w, apperrors.MonthlyLimitExceeded)
+			case errors.Is(err, services.ErrExchangeRateNotFound):
+				apperrors.Write(w, apperrors.ExchangeRateNotFound)
+			case errors.Is(err, services.ErrExchangerUnavailable):
+				apperrors.Write(w, apperrors.ExchangerUnavailable)
+			case errors.Is(err, services.ErrExchangerTimeout):
+				apperrors.Write(w, apperrors.ExchangerTimeout)
 			default:
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(ExchangeErrorResponse{Error: "Internal server error"})
+				apperrors.Write(w, apperrors.Internal)
 			}
 			return
 		}
 
-		newBalance := ExchangedBalance{
-			USD: usd,
-			RUB: rub,
-			EUR: eur,
-		}
-
 		resp := ExchangeResponse{
 			Message:         "Exchange successful",
-			ExchangedAmount: float64(exchangedAmount),
-			NewBalance:      newBalance,
+			ExchangedAmount: executed.ToAmount,
+			Fee:             executed.Fee,
+			Rate:            executed.Rate,
+			NewBalance:      renderBalances(r, balances),
+			StaleRate:       executed.StaleRate,
+			DerivedRate:     executed.DerivedRate,
+			RateFetchedAt:   executed.RateFetchedAt,
+			RateCached:      executed.RateCached,
+			RateProvider:    executed.RateProvider,
 		}
 
+		setBalanceSchemaHeaders(w, r, legacyDeprecation)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(resp)
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ExchangeHistoryTokener defines only the methods needed by this handler.
+type ExchangeHistoryTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// ExchangeHistoryLister defines the interface that the service must implement.
+type ExchangeHistoryLister interface {
+	ListExchanges(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error)
+}
+
+// ExchangeHistoryEntry represents a single past exchange
+// swagger:model ExchangeHistoryEntry
+type ExchangeHistoryEntry struct {
+	// Transaction identifier
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	TransactionID string `json:"transaction_id"`
+
+	// Currency pair
+	// default: USD-EUR
+	Pair string `json:"pair"`
+
+	// Source currency
+	// default: USD
+	FromCurrency string `json:"from_currency"`
+
+	// Target currency
+	// default: EUR
+	ToCurrency string `json:"to_currency"`
+
+	// Debited amount, including the fee
+	// default: 100.00
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+
+	// Fee in the source currency, omitted for exchanges made before fees were recorded
+	// default: 0.50
+	Fee *money.Amount `json:"fee,omitempty" swaggertype:"number"`
+
+	// Rate applied, omitted for exchanges made before rates were recorded
+	// default: 0.92
+	Rate *float32 `json:"rate,omitempty"`
+
+	// Credited amount
+	// default: 91.54
+	ToAmount money.Amount `json:"to_amount" swaggertype:"number"`
+
+	// Balance of the source wallet after the exchange, omitted for older exchanges
+	// default: 400.00
+	FromBalance *money.Amount `json:"from_balance,omitempty" swaggertype:"number"`
+
+	// Balance of the target wallet after the exchange, omitted for older exchanges
+	// default: 191.54
+	ToBalance *money.Amount `json:"to_balance,omitempty" swaggertype:"number"`
+
+	// Time of the exchange
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ExchangeHistoryResponse represents a page of the exchange history
+// swagger:model ExchangeHistoryResponse
+type ExchangeHistoryResponse struct {
+	// Exchanges, newest first
+	Exchanges []ExchangeHistoryEntry `json:"exchanges"`
+
+	// Cursor of the next page, omitted on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewGetExchangeHistoryHandler returns an HTTP handler listing the user's exchanges.
+// @Summary Get exchange history
+// @Description Returns the user's past exchanges with the pair, rate applied, fee and resulting balances, newest first. Pass next_cursor from the previous page as cursor to get the next one.
+// @Tags exchange
+// @Produce json
+// @Param pair query string false "Currency pair, e.g. USD-EUR"
+// @Param currency query string false "Currency on either side of the exchange (a supported currency code)"
+// @Param from query string false "Only exchanges at or after this time (RFC 3339)"
+// @Param to query string false "Only exchanges before this time (RFC 3339)"
+// @Param limit query int false "Number of exchanges to return (default 20, max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {object} handlers.ExchangeHistoryResponse "Exchange history"
+// @Failure 400 {object} apperrors.Response "Invalid query parameters"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exchange/history [get]
+// @Security BearerAuth
+func NewGetExchangeHistoryHandler(
+	svc ExchangeHistoryLister,
+	tokenGetter ExchangeHistoryTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		invalid := func(e apperrors.Error) {
+			apperrors.Write(w, e)
+		}
+
+		q := r.URL.Query()
+		filter := models.TransactionFilter{
+			UserID:   claims.UserID,
+			Currency: q.Get("currency"),
+		}
+
+		if v := q.Get("pair"); v != "" {
+			fromCurrency, toCurrency, ok := strings.Cut(v, "-")
+			if !ok || fromCurrency == toCurrency ||
+				!currencies.IsSupported(ctx, fromCurrency) || !currencies.IsSupported(ctx, toCurrency) {
+				invalid(apperrors.InvalidCurrencyPair)
+				return
+			}
+			filter.FromCurrency, filter.ToCurrency = fromCurrency, toCurrency
+		}
+		if filter.Currency != "" && !currencies.IsSupported(ctx, filter.Currency) {
+			invalid(apperrors.InvalidCurrency)
+			return
+		}
+		if v := q.Get("from"); v != "" {
+			if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+				invalid(apperrors.InvalidFrom)
+				return
+			}
+		}
+		if v := q.Get("to"); v != "" {
+			if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+				invalid(apperrors.InvalidTo)
+				return
+			}
+		}
+		if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+			invalid(apperrors.InvalidDateRange)
+			return
+		}
+		if v := q.Get("limit"); v != "" {
+			if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 1 {
+				invalid(apperrors.InvalidLimit)
+				return
+			}
+		}
+
+		txns, next, err := svc.ListExchanges(ctx, filter, q.Get("cursor"))
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCursor) {
+				invalid(apperrors.InvalidCursor)
+				return
+			}
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := ExchangeHistoryResponse{
+			Exchanges:  make([]ExchangeHistoryEntry, 0, len(txns)),
+			NextCursor: next,
+		}
+		for _, t := range txns {
+			resp.Exchanges = append(resp.Exchanges, newExchangeHistoryEntry(t))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// newExchangeHistoryEntry converts an exchange row into its API representation.
+func newExchangeHistoryEntry(t models.TransactionDB) ExchangeHistoryEntry {
+	entry := ExchangeHistoryEntry{
+		TransactionID: t.TransactionID.String(),
+		FromCurrency:  t.Currency,
+		Amount:        t.Amount,
+		Fee:           t.Fee,
+		Rate:          t.Rate,
+		FromBalance:   t.Balance,
+		ToBalance:     t.ToBalance,
+		Timestamp:     t.CreatedAt,
+	}
+	if t.ToCurrency != nil {
+		entry.ToCurrency = *t.ToCurrency
+	}
+	if t.ToAmount != nil {
+		entry.ToAmount = *t.ToAmount
+	}
+	entry.Pair = entry.FromCurrency + "-" + entry.ToCurrency
+	return entry
+}
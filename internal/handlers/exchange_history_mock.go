@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/exchange_history.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExchangeHistoryTokener is a mock of ExchangeHistoryTokener interface.
+type MockExchangeHistoryTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeHistoryTokenerMockRecorder
+}
+
+// MockExchangeHistoryTokenerMockRecorder is the mock recorder for MockExchangeHistoryTokener.
+type MockExchangeHistoryTokenerMockRecorder struct {
+	mock *MockExchangeHistoryTokener
+}
+
+// NewMockExchangeHistoryTokener creates a new mock instance.
+func NewMockExchangeHistoryTokener(ctrl *gomock.Controller) *MockExchangeHistoryTokener {
+	mock := &MockExchangeHistoryTokener{ctrl: ctrl}
+	mock.recorder = &MockExchangeHistoryTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeHistoryTokener) EXPECT() *MockExchangeHistoryTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockExchangeHistoryTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockExchangeHistoryTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockExchangeHistoryTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockExchangeHistoryTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockExchangeHistoryTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockExchangeHistoryTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockExchangeHistoryLister is a mock of ExchangeHistoryLister interface.
+type MockExchangeHistoryLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeHistoryListerMockRecorder
+}
+
+// MockExchangeHistoryListerMockRecorder is the mock recorder for MockExchangeHistoryLister.
+type MockExchangeHistoryListerMockRecorder struct {
+	mock *MockExchangeHistoryLister
+}
+
+// NewMockExchangeHistoryLister creates a new mock instance.
+func NewMockExchangeHistoryLister(ctrl *gomock.Controller) *MockExchangeHistoryLister {
+	mock := &MockExchangeHistoryLister{ctrl: ctrl}
+	mock.recorder = &MockExchangeHistoryListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeHistoryLister) EXPECT() *MockExchangeHistoryListerMockRecorder {
+	return m.recorder
+}
+
+// ListExchanges mocks base method.
+func (m *MockExchangeHistoryLister) ListExchanges(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchanges", ctx, filter, cursor)
+	ret0, _ := ret[0].([]models.TransactionDB)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListExchanges indicates an expected call of ListExchanges.
+func (mr *MockExchangeHistoryListerMockRecorder) ListExchanges(ctx, filter, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchanges", reflect.TypeOf((*MockExchangeHistoryLister)(nil).ListExchanges), ctx, filter, cursor)
+}
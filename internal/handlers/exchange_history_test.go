@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExchangeHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeHistoryTokener(ctrl)
+	mockSvc := NewMockExchangeHistoryLister(ctrl)
+
+	userID := uuid.New()
+	txnID, oldTxnID := uuid.New(), uuid.New()
+	at := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+	eur := models.EUR
+	toAmount := money.MustParse("91.54")
+	rate := float32(0.92)
+	fee := money.MustParse("0.5")
+	balance, toBalance := money.MustParse("400"), money.MustParse("191.54")
+
+	handler := NewGetExchangeHistoryHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success_with_filters",
+			query: "?pair=USD-EUR&from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z&limit=2&cursor=abc",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListExchanges(gomock.Any(), models.TransactionFilter{
+						UserID:       userID,
+						From:         time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+						To:           time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+						FromCurrency: models.USD,
+						ToCurrency:   models.EUR,
+						Limit:        2,
+					}, "abc").
+					Return([]models.TransactionDB{
+						{ID: 9, TransactionID: txnID, UserID: userID, Operation: models.OperationExchange, Currency: models.USD, Amount: money.MustParse("100"), ToCurrency: &eur, ToAmount: &toAmount,
+							Rate: &rate, Fee: &fee, Balance: &balance, ToBalance: &toBalance, CreatedAt: at},
+						{ID: 3, TransactionID: oldTxnID, UserID: userID, Operation: models.OperationExchange, Currency: models.USD, Amount: money.MustParse("100"), ToCurrency: &eur, ToAmount: &toAmount, CreatedAt: at},
+					}, "next", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeHistoryResponse{
+				Exchanges: []ExchangeHistoryEntry{
+					{TransactionID: txnID.String(), Pair: "USD-EUR", FromCurrency: models.USD, ToCurrency: models.EUR, Amount: money.MustParse("100"), Fee: &fee, Rate: &rate, ToAmount: toAmount,
+						FromBalance: &balance, ToBalance: &toBalance, Timestamp: at},
+					{TransactionID: oldTxnID.String(), Pair: "USD-EUR", FromCurrency: models.USD, ToCurrency: models.EUR, Amount: money.MustParse("100"), ToAmount: toAmount, Timestamp: at},
+				},
+				NextCursor: "next",
+			},
+		},
+		{
+			name:  "currency_filter",
+			query: "?currency=RUB",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListExchanges(gomock.Any(), models.TransactionFilter{UserID: userID, Currency: models.RUB}, "").
+					Return(nil, "", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ExchangeHistoryResponse{Exchanges: []ExchangeHistoryEntry{}},
+		},
+		{
+			name:           "invalid_pair",
+			query:          "?pair=USD-USD",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency pair", Code: "invalid_currency_pair"},
+		},
+		{
+			name:           "unsupported_pair",
+			query:          "?pair=USD-GBP",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency pair", Code: "invalid_currency_pair"},
+		},
+		{
+			name:           "invalid_currency",
+			query:          "?currency=GBP",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "invalid_to",
+			query:          "?to=tomorrow",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid to", Code: "invalid_to"},
+		},
+		{
+			name:           "invalid_date_range",
+			query:          "?from=2025-04-01T00:00:00Z&to=2025-03-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid date range", Code: "invalid_date_range"},
+		},
+		{
+			name:           "invalid_limit",
+			query:          "?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid limit", Code: "invalid_limit"},
+		},
+		{
+			name:  "invalid_cursor",
+			query: "?cursor=bogus",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListExchanges(gomock.Any(), gomock.Any(), "bogus").
+					Return(nil, "", services.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid cursor", Code: "invalid_cursor"},
+		},
+		{
+			name: "internal_error",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListExchanges(gomock.Any(), gomock.Any(), "").
+					Return(nil, "", errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/exchange/history"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case ExchangeHistoryResponse:
+				var got ExchangeHistoryResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetExchangeHistoryHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeHistoryTokener(ctrl)
+	mockSvc := NewMockExchangeHistoryLister(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewGetExchangeHistoryHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/exchange/history", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
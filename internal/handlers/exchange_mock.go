@@ -12,6 +12,8 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
 )
 
 // MockExchangeRateForCurrencyTokener is a mock of ExchangeRateForCurrencyTokener interface.
@@ -91,19 +93,33 @@ func (m *MockExchanger) EXPECT() *MockExchangerMockRecorder {
 }
 
 // Exchange mocks base method.
-func (m *MockExchanger) Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount float64) (float32, float64, float64, float64, error) {
+func (m *MockExchanger) Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (models.ExchangeQuote, map[string]money.Amount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Exchange", ctx, userID, fromCurrency, toCurrency, amount)
-	ret0, _ := ret[0].(float32)
-	ret1, _ := ret[1].(float64)
-	ret2, _ := ret[2].(float64)
-	ret3, _ := ret[3].(float64)
-	ret4, _ := ret[4].(error)
-	return ret0, ret1, ret2, ret3, ret4
+	ret := m.ctrl.Call(m, "Exchange", ctx, userID, fromCurrency, toCurrency, amount, minToAmount)
+	ret0, _ := ret[0].(models.ExchangeQuote)
+	ret1, _ := ret[1].(map[string]money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Exchange indicates an expected call of Exchange.
-func (mr *MockExchangerMockRecorder) Exchange(ctx, userID, fromCurrency, toCurrency, amount interface{}) *gomock.Call {
+func (mr *MockExchangerMockRecorder) Exchange(ctx, userID, fromCurrency, toCurrency, amount, minToAmount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockExchanger)(nil).Exchange), ctx, userID, fromCurrency, toCurrency, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockExchanger)(nil).Exchange), ctx, userID, fromCurrency, toCurrency, amount, minToAmount)
+}
+
+// ExchangeQuoted mocks base method.
+func (m *MockExchanger) ExchangeQuoted(ctx context.Context, userID, quoteID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (models.ExchangeQuote, map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeQuoted", ctx, userID, quoteID, fromCurrency, toCurrency, amount, minToAmount)
+	ret0, _ := ret[0].(models.ExchangeQuote)
+	ret1, _ := ret[1].(map[string]money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExchangeQuoted indicates an expected call of ExchangeQuoted.
+func (mr *MockExchangerMockRecorder) ExchangeQuoted(ctx, userID, quoteID, fromCurrency, toCurrency, amount, minToAmount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeQuoted", reflect.TypeOf((*MockExchanger)(nil).ExchangeQuoted), ctx, userID, quoteID, fromCurrency, toCurrency, amount, minToAmount)
 }
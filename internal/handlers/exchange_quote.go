@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ExchangeQuoteTokener defines only the methods needed by this handler.
+type ExchangeQuoteTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// ExchangeQuoter previews exchanges without executing them.
+type ExchangeQuoter interface {
+	QuoteExchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount money.Amount) (models.ExchangeQuote, error)
+}
+
+// ExchangeQuoteResponse represents the preview of an exchange
+// swagger:model ExchangeQuoteResponse
+type ExchangeQuoteResponse struct {
+	// ID to pass to POST /exchange to execute the quote at its rate before it expires
+	QuoteID string `json:"quote_id,omitempty"`
+
+	// When the quote expires
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Source currency
+	// default: USD
+	FromCurrency string `json:"from_currency"`
+
+	// Target currency
+	// default: EUR
+	ToCurrency string `json:"to_currency"`
+
+	// Amount debited in the source currency
+	// default: 100.0
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+
+	// Rate from the source to the target currency, spread included
+	// default: 0.92
+	Rate float32 `json:"rate"`
+
+	// Fee in the source currency, included in amount and deducted before conversion
+	// default: 0
+	Fee money.Amount `json:"fee" swaggertype:"number"`
+
+	// Amount credited in the target currency
+	// default: 92.0
+	ToAmount money.Amount `json:"to_amount" swaggertype:"number"`
+
+	// Whether a cached rate past its TTL was used because the exchange service was unavailable
+	// default: false
+	StaleRate bool `json:"stale_rate"`
+
+	// Whether the rate was derived through the pivot currency because the exchange service has no direct rate for the pair
+	// default: false
+	DerivedRate bool `json:"derived_rate"`
+
+	// When the rate was fetched from the provider; for a derived rate, the older of its legs
+	// default: 2025-03-14T09:30:00Z
+	RateFetchedAt time.Time `json:"rate_fetched_at"`
+
+	// Whether the rate was served from the cache
+	// default: true
+	RateCached bool `json:"rate_cached"`
+
+	// Rate provider the rate came from: grpc (the exchange service) or http (the fallback rates API)
+	// default: grpc
+	RateProvider string `json:"rate_provider"`
+}
+
+// NewExchangeQuoteHandler returns an HTTP handler previewing an exchange.
+// @Summary Quote an exchange
+// @Description Returns the rate, fee and resulting amount of an exchange at the current rate without touching balances, for a confirmation screen before POST /exchange. The rate is locked for a short time: passing quote_id to POST /exchange executes the quote at its rate.
+// @Tags exchange
+// @Produce json
+// @Param from query string true "Source currency"
+// @Param to query string true "Target currency"
+// @Param amount query number true "Amount to exchange"
+// @Success 200 {object} handlers.ExchangeQuoteResponse "Exchange quote"
+// @Failure 400 {object} apperrors.Response "Invalid amount or currency, amount out of range or not covering the fee"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Exchange rate not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Failure 503 {object} apperrors.Response "Exchange service unavailable"
+// @Failure 504 {object} apperrors.Response "Exchange service timeout"
+// @Router /exchange/quote [get]
+// @Security BearerAuth
+func NewExchangeQuoteHandler(
+	quoter ExchangeQuoter,
+	tokenGetter ExchangeQuoteTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		from, to := query.Get("from"), query.Get("to")
+		amount, err := money.Parse(query.Get("amount"))
+		if err != nil || from == to || !currencies.IsSupported(ctx, from) || !currencies.IsSupported(ctx, to) ||
+			!currencies.ValidAmount(ctx, from, amount) {
+			logger.FromContext(ctx).Warnw("invalid exchange quote request", "from", from, "to", to, "amount", query.Get("amount"))
+			apperrors.Write(w, apperrors.InvalidAmountOrCurrency)
+			return
+		}
+
+		quote, err := quoter.QuoteExchange(ctx, claims.UserID, from, to, amount)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrFeeExceedsAmount):
+				apperrors.Write(w, apperrors.FeeExceedsAmount)
+			case errors.Is(err, services.ErrExchangeAmountOutOfRange):
+				apperrors.Write(w, apperrors.ExchangeAmountOutOfRange)
+			case errors.Is(err, services.ErrExchangeRateNotFound):
+				apperrors.Write(w, apperrors.ExchangeRateNotFound)
+			case errors.Is(err, services.ErrExchangerUnavailable):
+				apperrors.Write(w, apperrors.ExchangerUnavailable)
+			case errors.Is(err, services.ErrExchangerTimeout):
+				apperrors.Write(w, apperrors.ExchangerTimeout)
+			default:
+				logger.FromContext(ctx).Errorw("failed to quote exchange", "from", from, "to", to, "amount", amount, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := ExchangeQuoteResponse{
+			FromCurrency:  quote.FromCurrency,
+			ToCurrency:    quote.ToCurrency,
+			Amount:        quote.Amount,
+			Rate:          quote.Rate,
+			Fee:           quote.Fee,
+			ToAmount:      quote.ToAmount,
+			StaleRate:     quote.StaleRate,
+			DerivedRate:   quote.DerivedRate,
+			RateFetchedAt: quote.RateFetchedAt,
+			RateCached:    quote.RateCached,
+			RateProvider:  quote.RateProvider,
+		}
+		if quote.QuoteID != uuid.Nil {
+			resp.QuoteID = quote.QuoteID.String()
+			resp.ExpiresAt = &quote.ExpiresAt
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
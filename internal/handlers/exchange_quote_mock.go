@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/exchange_quote.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockExchangeQuoteTokener is a mock of ExchangeQuoteTokener interface.
+type MockExchangeQuoteTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeQuoteTokenerMockRecorder
+}
+
+// MockExchangeQuoteTokenerMockRecorder is the mock recorder for MockExchangeQuoteTokener.
+type MockExchangeQuoteTokenerMockRecorder struct {
+	mock *MockExchangeQuoteTokener
+}
+
+// NewMockExchangeQuoteTokener creates a new mock instance.
+func NewMockExchangeQuoteTokener(ctrl *gomock.Controller) *MockExchangeQuoteTokener {
+	mock := &MockExchangeQuoteTokener{ctrl: ctrl}
+	mock.recorder = &MockExchangeQuoteTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeQuoteTokener) EXPECT() *MockExchangeQuoteTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockExchangeQuoteTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockExchangeQuoteTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockExchangeQuoteTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockExchangeQuoteTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockExchangeQuoteTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockExchangeQuoteTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockExchangeQuoter is a mock of ExchangeQuoter interface.
+type MockExchangeQuoter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeQuoterMockRecorder
+}
+
+// MockExchangeQuoterMockRecorder is the mock recorder for MockExchangeQuoter.
+type MockExchangeQuoterMockRecorder struct {
+	mock *MockExchangeQuoter
+}
+
+// NewMockExchangeQuoter creates a new mock instance.
+func NewMockExchangeQuoter(ctrl *gomock.Controller) *MockExchangeQuoter {
+	mock := &MockExchangeQuoter{ctrl: ctrl}
+	mock.recorder = &MockExchangeQuoterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeQuoter) EXPECT() *MockExchangeQuoterMockRecorder {
+	return m.recorder
+}
+
+// QuoteExchange mocks base method.
+func (m *MockExchangeQuoter) QuoteExchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount money.Amount) (models.ExchangeQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteExchange", ctx, userID, fromCurrency, toCurrency, amount)
+	ret0, _ := ret[0].(models.ExchangeQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteExchange indicates an expected call of QuoteExchange.
+func (mr *MockExchangeQuoterMockRecorder) QuoteExchange(ctx, userID, fromCurrency, toCurrency, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteExchange", reflect.TypeOf((*MockExchangeQuoter)(nil).QuoteExchange), ctx, userID, fromCurrency, toCurrency, amount)
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeQuoteHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeQuoteTokener(ctrl)
+	mockQuoter := NewMockExchangeQuoter(ctrl)
+	userID := uuid.New()
+
+	handler := NewExchangeQuoteHandler(mockQuoter, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	amount := money.MustParse("100")
+	quoteID := uuid.New()
+	expiresAt := time.Date(2025, 3, 14, 9, 30, 30, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockQuoter     func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{
+					FromCurrency:  models.USD,
+					ToCurrency:    models.EUR,
+					Amount:        amount,
+					Rate:          0.92,
+					ToAmount:      money.MustParse("92"),
+					StaleRate:     true,
+					RateProvider:  "grpc",
+					RateFetchedAt: expiresAt.Add(-time.Minute),
+					RateCached:    true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeQuoteResponse{
+				FromCurrency:  models.USD,
+				ToCurrency:    models.EUR,
+				Amount:        amount,
+				Rate:          0.92,
+				ToAmount:      money.MustParse("92"),
+				StaleRate:     true,
+				RateFetchedAt: expiresAt.Add(-time.Minute),
+				RateCached:    true,
+				RateProvider:  "grpc",
+			},
+		},
+		{
+			name:  "derived rate",
+			query: "?from=RUB&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.RUB, models.EUR, amount).Return(models.ExchangeQuote{
+					FromCurrency: models.RUB,
+					ToCurrency:   models.EUR,
+					Amount:       amount,
+					Rate:         0.01,
+					ToAmount:     money.MustParse("1"),
+					DerivedRate:  true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeQuoteResponse{
+				FromCurrency: models.RUB,
+				ToCurrency:   models.EUR,
+				Amount:       amount,
+				Rate:         0.01,
+				ToAmount:     money.MustParse("1"),
+				DerivedRate:  true,
+			},
+		},
+		{
+			name:  "locked",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{
+					QuoteID:      quoteID,
+					UserID:       userID,
+					FromCurrency: models.USD,
+					ToCurrency:   models.EUR,
+					Amount:       amount,
+					Rate:         0.92,
+					ToAmount:     money.MustParse("92"),
+					ExpiresAt:    expiresAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeQuoteResponse{
+				QuoteID:      quoteID.String(),
+				ExpiresAt:    &expiresAt,
+				FromCurrency: models.USD,
+				ToCurrency:   models.EUR,
+				Amount:       amount,
+				Rate:         0.92,
+				ToAmount:     money.MustParse("92"),
+			},
+		},
+		{
+			name:           "missing amount",
+			query:          "?from=USD&to=EUR",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid amount or currency", Code: "invalid_amount_or_currency"},
+		},
+		{
+			name:           "amount not positive",
+			query:          "?from=USD&to=EUR&amount=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid amount or currency", Code: "invalid_amount_or_currency"},
+		},
+		{
+			name:           "same currency",
+			query:          "?from=USD&to=USD&amount=100",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid amount or currency", Code: "invalid_amount_or_currency"},
+		},
+		{
+			name:           "unsupported currency",
+			query:          "?from=USD&to=BTC&amount=100",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid amount or currency", Code: "invalid_amount_or_currency"},
+		},
+		{
+			name:  "fee exceeds amount",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{}, services.ErrFeeExceedsAmount)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Amount does not cover the exchange fee", Code: "fee_exceeds_amount"},
+		},
+		{
+			name:  "rate not found",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{}, services.ErrExchangeRateNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Exchange rate not found", Code: "exchange_rate_not_found"},
+		},
+		{
+			name:  "exchanger unavailable",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{}, services.ErrExchangerUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Exchange service unavailable", Code: "exchanger_unavailable"},
+		},
+		{
+			name:  "exchanger timeout",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{}, services.ErrExchangerTimeout)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   apperrors.Response{Error: "Exchange service timeout", Code: "exchanger_timeout"},
+		},
+		{
+			name:  "internal error",
+			query: "?from=USD&to=EUR&amount=100",
+			mockQuoter: func() {
+				mockQuoter.EXPECT().QuoteExchange(gomock.Any(), userID, models.USD, models.EUR, amount).Return(models.ExchangeQuote{}, errors.New("cache error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockQuoter != nil {
+				tt.mockQuoter()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/exchange/quote"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			switch expected := tt.expectedBody.(type) {
+			case ExchangeQuoteResponse:
+				var got ExchangeQuoteResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestExchangeQuoteHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeQuoteTokener(ctrl)
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+
+	handler := NewExchangeQuoteHandler(NewMockExchangeQuoter(ctrl), mockTokener, newMockCurrencies(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/exchange/quote?from=USD&to=EUR&amount=100", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"error":"Unauthorized","code":"unauthorized"}`, rec.Body.String())
+}
@@ -3,10 +3,15 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 )
 
 // ExchangeRatesTokener defines only the methods needed by this handler.
@@ -17,48 +22,47 @@ type ExchangeRatesTokener interface {
 
 // ExchangeRatesReader defines the interface for fetching exchange rates.
 type ExchangeRatesReader interface {
-	GetExchangeRates(ctx context.Context) (usd, rub, eur float32, err error)
+	GetExchangeRates(ctx context.Context) (rates map[string]float32, src models.RateSource, err error)
 }
 
-// ExchangeRates represents exchange rates for supported currencies
+// ExchangeRates represents exchange rates keyed by currency code
 // swagger:model ExchangeRates
-type ExchangeRates struct {
-	// USD exchange rate
-	// default: 1.0
-	USD float32 `json:"USD"`
-
-	// RUB exchange rate
-	// default: 90.0
-	RUB float32 `json:"RUB"`
-
-	// EUR exchange rate
-	// default: 0.85
-	EUR float32 `json:"EUR"`
-}
+type ExchangeRates map[string]float32
 
 // ExchangeRatesResponse represents a successful response with exchange rates
 // swagger:model ExchangeRatesResponse
 type ExchangeRatesResponse struct {
 	// Exchange rates
-	Rates ExchangeRates `json:"rates"`
-}
+	Rates ExchangeRates `json:"rates" swaggertype:"object,number"`
+
+	// Rates were served from the cache because the exchange service was unavailable
+	// default: false
+	StaleRate bool `json:"stale_rate"`
+
+	// When the rates were fetched from the provider
+	// default: 2025-03-14T09:30:00Z
+	FetchedAt time.Time `json:"fetched_at"`
+
+	// Rates were served from the cache
+	// default: false
+	Cached bool `json:"cached"`
 
-// ExchangeRatesErrorResponse represents an error response when fetching exchange rates
-// swagger:model ExchangeRatesErrorResponse
-type ExchangeRatesErrorResponse struct {
-	// Error message
-	// default: Failed to retrieve exchange rates
-	Error string `json:"error"`
+	// Rate provider the rates came from: grpc (the exchange service) or http (the fallback rates API)
+	// default: grpc
+	Provider string `json:"provider"`
 }
 
 // NewGetExchangeRatesHandler returns an HTTP handler for fetching currency exchange rates.
 // @Summary Get exchange rates
-// @Description Fetches current exchange rates for all supported currencies
+// @Description Fetches current exchange rates for all supported currencies with the time they were fetched and their provider. While the exchange service is unavailable or times out, recently fetched rates are returned from the cache with stale_rate set.
 // @Tags exchange
 // @Produce json
 // @Success 200 {object} ExchangeRatesResponse "Exchange rates"
-// @Failure 500 {object} ExchangeRatesErrorResponse "Failed to retrieve exchange rates"
-// @Failure 401 {object} ExchangeRatesErrorResponse "Unauthorized"
+// @Failure 500 {object} apperrors.Response "Failed to retrieve exchange rates"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 503 {object} apperrors.Response "Exchange service unavailable"
+// @Failure 504 {object} apperrors.Response "Exchange service timeout"
 // @Router /exchange/rates [get]
 // @Security BearerAuth
 func NewGetExchangeRatesHandler(
@@ -70,34 +74,38 @@ func NewGetExchangeRatesHandler(
 
 		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
 		if err != nil {
-			logger.Log.Errorw("failed to get token from request", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(ExchangeRatesErrorResponse{Error: "Unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		_, err = tokenGetter.GetClaims(ctx, tokenStr)
 		if err != nil {
-			logger.Log.Errorw("failed to get claims from token", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(ExchangeRatesErrorResponse{Error: "Unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
-		usd, rub, eur, err := reader.GetExchangeRates(ctx)
+		rates, src, err := reader.GetExchangeRates(ctx)
 		if err != nil {
-			logger.Log.Errorw("failed to fetch exchange rates", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(ExchangeRatesErrorResponse{Error: "Failed to retrieve exchange rates"})
+			logger.FromContext(ctx).Errorw("failed to fetch exchange rates", "error", err)
+			switch {
+			case errors.Is(err, services.ErrExchangerUnavailable):
+				apperrors.Write(w, apperrors.ExchangerUnavailable)
+			case errors.Is(err, services.ErrExchangerTimeout):
+				apperrors.Write(w, apperrors.ExchangerTimeout)
+			default:
+				apperrors.Write(w, apperrors.ExchangeRatesFailed)
+			}
 			return
 		}
 
 		resp := ExchangeRatesResponse{
-			Rates: ExchangeRates{
-				USD: usd,
-				RUB: rub,
-				EUR: eur,
-			},
+			Rates:     rates,
+			StaleRate: src.Stale,
+			FetchedAt: src.FetchedAt,
+			Cached:    src.Cached,
+			Provider:  src.Provider,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
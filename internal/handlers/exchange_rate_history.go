@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ExchangeRateHistoryTokener defines only the methods needed by this handler.
+type ExchangeRateHistoryTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// ExchangeRateHistoryGetter defines the interface that the service must implement.
+type ExchangeRateHistoryGetter interface {
+	History(ctx context.Context, fromCurrency, toCurrency string, from, to time.Time, granularity string) ([]models.ExchangeRatePoint, error)
+}
+
+// ExchangeRateHistoryEntry represents the rate of a currency pair over one period
+// swagger:model ExchangeRateHistoryEntry
+type ExchangeRateHistoryEntry struct {
+	// Start of the period, UTC
+	// default: 2025-03-14T00:00:00Z
+	Period time.Time `json:"period"`
+
+	// First rate of the period
+	// default: 0.91
+	Open float32 `json:"open"`
+
+	// Last rate of the period
+	// default: 0.92
+	Close float32 `json:"close"`
+
+	// Lowest rate of the period
+	// default: 0.9
+	Low float32 `json:"low"`
+
+	// Highest rate of the period
+	// default: 0.93
+	High float32 `json:"high"`
+}
+
+// ExchangeRateHistoryResponse represents the rate history of a currency pair
+// swagger:model ExchangeRateHistoryResponse
+type ExchangeRateHistoryResponse struct {
+	// Currency pair
+	// default: USD-EUR
+	Pair string `json:"pair"`
+
+	// Period of the entries: hour or day
+	// default: day
+	Granularity string `json:"granularity"`
+
+	// Rates per period, oldest first
+	History []ExchangeRateHistoryEntry `json:"history"`
+}
+
+// NewGetExchangeRateHistoryHandler returns an HTTP handler listing the history of a rate.
+// @Summary Get exchange rate history
+// @Description Returns the open, close, low and high rate of a currency pair per hour or day (UTC), oldest first, for charting. The history holds the rates the wallet fetched from the exchange service; periods without fetched rates are omitted. Defaults to the last 30 days daily or the last 24 hours hourly, at most 366 days or 31 days per request.
+// @Tags exchange
+// @Produce json
+// @Param pair query string true "Currency pair, e.g. USD-EUR"
+// @Param from query string false "Start (RFC 3339), 30 days or 24 hours before to by default"
+// @Param to query string false "End (RFC 3339), now by default"
+// @Param granularity query string false "hour or day (default)"
+// @Success 200 {object} handlers.ExchangeRateHistoryResponse "Exchange rate history"
+// @Failure 400 {object} apperrors.Response "Invalid query parameters"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exchange/rates/history [get]
+// @Security BearerAuth
+func NewGetExchangeRateHistoryHandler(
+	svc ExchangeRateHistoryGetter,
+	tokenGetter ExchangeRateHistoryTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		if _, err := tokenGetter.GetClaims(ctx, tokenStr); err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		invalid := func(e apperrors.Error) {
+			apperrors.Write(w, e)
+		}
+
+		q := r.URL.Query()
+		fromCurrency, toCurrency, ok := strings.Cut(q.Get("pair"), "-")
+		if !ok || fromCurrency == toCurrency ||
+			!currencies.IsSupported(ctx, fromCurrency) || !currencies.IsSupported(ctx, toCurrency) {
+			invalid(apperrors.InvalidCurrencyPair)
+			return
+		}
+
+		var from, to time.Time
+		if v := q.Get("from"); v != "" {
+			if from, err = time.Parse(time.RFC3339, v); err != nil {
+				invalid(apperrors.InvalidFrom)
+				return
+			}
+		}
+		if v := q.Get("to"); v != "" {
+			if to, err = time.Parse(time.RFC3339, v); err != nil {
+				invalid(apperrors.InvalidTo)
+				return
+			}
+		}
+		granularity := q.Get("granularity")
+		if granularity == "" {
+			granularity = models.RateGranularityDay
+		}
+
+		history, err := svc.History(ctx, fromCurrency, toCurrency, from, to, granularity)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidGranularity):
+				invalid(apperrors.InvalidGranularity)
+			case errors.Is(err, services.ErrInvalidDateRange):
+				invalid(apperrors.InvalidDateRange)
+			default:
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := ExchangeRateHistoryResponse{
+			Pair:        fromCurrency + "-" + toCurrency,
+			Granularity: granularity,
+			History:     make([]ExchangeRateHistoryEntry, 0, len(history)),
+		}
+		for _, point := range history {
+			resp.History = append(resp.History, ExchangeRateHistoryEntry{
+				Period: point.Period,
+				Open:   point.Open,
+				Close:  point.Close,
+				Low:    point.Low,
+				High:   point.High,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
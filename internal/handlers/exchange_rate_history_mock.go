@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/exchange_rate_history.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExchangeRateHistoryTokener is a mock of ExchangeRateHistoryTokener interface.
+type MockExchangeRateHistoryTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateHistoryTokenerMockRecorder
+}
+
+// MockExchangeRateHistoryTokenerMockRecorder is the mock recorder for MockExchangeRateHistoryTokener.
+type MockExchangeRateHistoryTokenerMockRecorder struct {
+	mock *MockExchangeRateHistoryTokener
+}
+
+// NewMockExchangeRateHistoryTokener creates a new mock instance.
+func NewMockExchangeRateHistoryTokener(ctrl *gomock.Controller) *MockExchangeRateHistoryTokener {
+	mock := &MockExchangeRateHistoryTokener{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateHistoryTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateHistoryTokener) EXPECT() *MockExchangeRateHistoryTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockExchangeRateHistoryTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockExchangeRateHistoryTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockExchangeRateHistoryTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockExchangeRateHistoryTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockExchangeRateHistoryTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockExchangeRateHistoryTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockExchangeRateHistoryGetter is a mock of ExchangeRateHistoryGetter interface.
+type MockExchangeRateHistoryGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateHistoryGetterMockRecorder
+}
+
+// MockExchangeRateHistoryGetterMockRecorder is the mock recorder for MockExchangeRateHistoryGetter.
+type MockExchangeRateHistoryGetterMockRecorder struct {
+	mock *MockExchangeRateHistoryGetter
+}
+
+// NewMockExchangeRateHistoryGetter creates a new mock instance.
+func NewMockExchangeRateHistoryGetter(ctrl *gomock.Controller) *MockExchangeRateHistoryGetter {
+	mock := &MockExchangeRateHistoryGetter{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateHistoryGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateHistoryGetter) EXPECT() *MockExchangeRateHistoryGetterMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockExchangeRateHistoryGetter) History(ctx context.Context, fromCurrency, toCurrency string, from, to time.Time, granularity string) ([]models.ExchangeRatePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, fromCurrency, toCurrency, from, to, granularity)
+	ret0, _ := ret[0].([]models.ExchangeRatePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockExchangeRateHistoryGetterMockRecorder) History(ctx, fromCurrency, toCurrency, from, to, granularity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockExchangeRateHistoryGetter)(nil).History), ctx, fromCurrency, toCurrency, from, to, granularity)
+}
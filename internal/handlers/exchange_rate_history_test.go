@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExchangeRateHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeRateHistoryTokener(ctrl)
+	mockSvc := NewMockExchangeRateHistoryGetter(ctrl)
+
+	userID := uuid.New()
+	from := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	handler := NewGetExchangeRateHistoryHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success_hourly",
+			query: "?pair=USD-EUR&from=2025-03-13T00:00:00Z&to=2025-03-14T12:00:00Z&granularity=hour",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), models.USD, models.EUR, from, to, models.RateGranularityHour).
+					Return([]models.ExchangeRatePoint{{Period: from, Open: 0.9, Close: 0.92, Low: 0.89, High: 0.93}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeRateHistoryResponse{
+				Pair:        "USD-EUR",
+				Granularity: models.RateGranularityHour,
+				History:     []ExchangeRateHistoryEntry{{Period: from, Open: 0.9, Close: 0.92, Low: 0.89, High: 0.93}},
+			},
+		},
+		{
+			name:  "daily_by_default",
+			query: "?pair=EUR-RUB",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), models.EUR, models.RUB, time.Time{}, time.Time{}, models.RateGranularityDay).
+					Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeRateHistoryResponse{
+				Pair:        "EUR-RUB",
+				Granularity: models.RateGranularityDay,
+				History:     []ExchangeRateHistoryEntry{},
+			},
+		},
+		{
+			name:           "invalid_pair",
+			query:          "?pair=USDEUR",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency pair", Code: "invalid_currency_pair"},
+		},
+		{
+			name:           "unsupported_currency",
+			query:          "?pair=USD-BTC",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency pair", Code: "invalid_currency_pair"},
+		},
+		{
+			name:           "invalid_from",
+			query:          "?pair=USD-EUR&from=2025-03-13",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid from", Code: "invalid_from"},
+		},
+		{
+			name:  "invalid_granularity",
+			query: "?pair=USD-EUR&granularity=week",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), models.USD, models.EUR, time.Time{}, time.Time{}, "week").
+					Return(nil, services.ErrInvalidGranularity)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid granularity", Code: "invalid_granularity"},
+		},
+		{
+			name:  "invalid_date_range",
+			query: "?pair=USD-EUR&from=2025-03-14T12:00:00Z&to=2025-03-13T00:00:00Z",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), models.USD, models.EUR, to, from, models.RateGranularityDay).
+					Return(nil, services.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid date range", Code: "invalid_date_range"},
+		},
+		{
+			name:  "internal_error",
+			query: "?pair=USD-EUR",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					History(gomock.Any(), models.USD, models.EUR, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/exchange/rates/history"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case ExchangeRateHistoryResponse:
+				var got ExchangeRateHistoryResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetExchangeRateHistoryHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeRateHistoryTokener(ctrl)
+	mockSvc := NewMockExchangeRateHistoryGetter(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewGetExchangeRateHistoryHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/exchange/rates/history?pair=USD-EUR", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
 )
 
 // MockExchangeRatesTokener is a mock of ExchangeRatesTokener interface.
@@ -90,14 +91,13 @@ func (m *MockExchangeRatesReader) EXPECT() *MockExchangeRatesReaderMockRecorder
 }
 
 // GetExchangeRates mocks base method.
-func (m *MockExchangeRatesReader) GetExchangeRates(ctx context.Context) (float32, float32, float32, error) {
+func (m *MockExchangeRatesReader) GetExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetExchangeRates", ctx)
-	ret0, _ := ret[0].(float32)
-	ret1, _ := ret[1].(float32)
-	ret2, _ := ret[2].(float32)
-	ret3, _ := ret[3].(error)
-	return ret0, ret1, ret2, ret3
+	ret0, _ := ret[0].(map[string]float32)
+	ret1, _ := ret[1].(models.RateSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetExchangeRates indicates an expected call of GetExchangeRates.
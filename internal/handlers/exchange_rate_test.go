@@ -6,12 +6,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 )
 
 func TestGetExchangeRatesHandler(t *testing.T) {
@@ -20,6 +24,7 @@ func TestGetExchangeRatesHandler(t *testing.T) {
 
 	userID := uuid.New()
 	validToken := "valid-token"
+	fetchedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
 
 	tests := []struct {
 		name               string
@@ -38,15 +43,40 @@ func TestGetExchangeRatesHandler(t *testing.T) {
 					Return(&jwt.Claims{UserID: userID}, nil)
 				reader.EXPECT().
 					GetExchangeRates(gomock.Any()).
-					Return(float32(1.0), float32(90.0), float32(0.85), nil)
+					Return(map[string]float32{"USD": float32(1.0), "RUB": float32(90.0), "EUR": float32(0.85)}, models.RateSource{Provider: "grpc", FetchedAt: fetchedAt, Cached: true}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse: ExchangeRatesResponse{
 				Rates: ExchangeRates{
-					USD: 1.0,
-					RUB: 90.0,
-					EUR: 0.85,
+					"USD": 1.0,
+					"RUB": 90.0,
+					"EUR": 0.85,
 				},
+				FetchedAt: fetchedAt,
+				Cached:    true,
+				Provider:  "grpc",
+			},
+		},
+		{
+			name: "stale_rates",
+			setupMocks: func(reader *MockExchangeRatesReader, tokener *MockExchangeRatesTokener) {
+				tokener.EXPECT().
+					GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(validToken, nil)
+				tokener.EXPECT().
+					GetClaims(gomock.Any(), validToken).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				reader.EXPECT().
+					GetExchangeRates(gomock.Any()).
+					Return(map[string]float32{"USD": float32(1.0), "EUR": float32(0.85)}, models.RateSource{Provider: "grpc", FetchedAt: fetchedAt, Cached: true, Stale: true}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: ExchangeRatesResponse{
+				Rates:     ExchangeRates{"USD": 1.0, "EUR": 0.85},
+				StaleRate: true,
+				FetchedAt: fetchedAt,
+				Cached:    true,
+				Provider:  "grpc",
 			},
 		},
 		{
@@ -57,7 +87,7 @@ func TestGetExchangeRatesHandler(t *testing.T) {
 					Return("", errors.New("no token"))
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   ExchangeRatesErrorResponse{Error: "Unauthorized"},
+			expectedResponse:   apperrors.Response{Error: "Unauthorized", Code: "unauthorized"},
 		},
 		{
 			name: "unauthorized_claims_error",
@@ -70,7 +100,39 @@ func TestGetExchangeRatesHandler(t *testing.T) {
 					Return(nil, errors.New("invalid claims"))
 			},
 			expectedStatusCode: http.StatusUnauthorized,
-			expectedResponse:   ExchangeRatesErrorResponse{Error: "Unauthorized"},
+			expectedResponse:   apperrors.Response{Error: "Unauthorized", Code: "unauthorized"},
+		},
+		{
+			name: "exchanger_unavailable",
+			setupMocks: func(reader *MockExchangeRatesReader, tokener *MockExchangeRatesTokener) {
+				tokener.EXPECT().
+					GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(validToken, nil)
+				tokener.EXPECT().
+					GetClaims(gomock.Any(), validToken).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				reader.EXPECT().
+					GetExchangeRates(gomock.Any()).
+					Return(nil, models.RateSource{}, services.ErrExchangerUnavailable)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   apperrors.Response{Error: "Exchange service unavailable", Code: "exchanger_unavailable"},
+		},
+		{
+			name: "exchanger_timeout",
+			setupMocks: func(reader *MockExchangeRatesReader, tokener *MockExchangeRatesTokener) {
+				tokener.EXPECT().
+					GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return(validToken, nil)
+				tokener.EXPECT().
+					GetClaims(gomock.Any(), validToken).
+					Return(&jwt.Claims{UserID: userID}, nil)
+				reader.EXPECT().
+					GetExchangeRates(gomock.Any()).
+					Return(nil, models.RateSource{}, services.ErrExchangerTimeout)
+			},
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedResponse:   apperrors.Response{Error: "Exchange service timeout", Code: "exchanger_timeout"},
 		},
 		{
 			name: "internal_server_error",
@@ -83,10 +145,10 @@ func TestGetExchangeRatesHandler(t *testing.T) {
 					Return(&jwt.Claims{UserID: userID}, nil)
 				reader.EXPECT().
 					GetExchangeRates(gomock.Any()).
-					Return(float32(0), float32(0), float32(0), errors.New("db error"))
+					Return(nil, models.RateSource{}, errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   ExchangeRatesErrorResponse{Error: "Failed to retrieve exchange rates"},
+			expectedResponse:   apperrors.Response{Error: "Failed to retrieve exchange rates", Code: "exchange_rates_failed"},
 		},
 	}
 
@@ -114,7 +176,7 @@ func TestGetExchangeRatesHandler(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedResponse, got)
 			} else {
-				var got ExchangeRatesErrorResponse
+				var got apperrors.Response
 				err := json.NewDecoder(rec.Body).Decode(&got)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedResponse, got)
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ExchangeReconciliationTokener defines only the methods needed by this handler.
+type ExchangeReconciliationTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// ExchangeReconciler defines the interface that the service must implement.
+type ExchangeReconciler interface {
+	Report(ctx context.Context, from, to time.Time) (*models.ExchangeReconciliationReport, error)
+}
+
+// ExchangeRecord represents one side of a reconciled exchange
+// swagger:model ExchangeRecord
+type ExchangeRecord struct {
+	// Debited currency
+	// default: USD
+	FromCurrency string `json:"from_currency"`
+
+	// Credited currency
+	// default: EUR
+	ToCurrency string `json:"to_currency"`
+
+	// Debited amount
+	// default: 100.0
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+
+	// Credited amount
+	// default: 90.0
+	ToAmount money.Amount `json:"to_amount" swaggertype:"number"`
+
+	// Applied exchange rate
+	// default: 0.9
+	Rate float32 `json:"rate"`
+
+	// Time of the conversion
+	ExecutedAt time.Time `json:"executed_at"`
+}
+
+// ExchangeMismatchEntry represents an exchange that disagrees with the exchanger's records
+// swagger:model ExchangeMismatchEntry
+type ExchangeMismatchEntry struct {
+	// Transaction identifier
+	// default: 6f1c2a4e-8d0b-4b7a-9c3e-2f5d1a7b9e01
+	TransactionID uuid.UUID `json:"transaction_id"`
+
+	// Kind of the mismatch: missing_at_exchanger, unknown_locally, amount or rate
+	// default: rate
+	Kind string `json:"kind"`
+
+	// Local record, null if unknown locally
+	Local *ExchangeRecord `json:"local"`
+
+	// Exchanger's record, null if missing at the exchanger
+	Exchanger *ExchangeRecord `json:"exchanger"`
+}
+
+// ExchangeReconciliationResponse represents the reconciliation of a period
+// swagger:model ExchangeReconciliationResponse
+type ExchangeReconciliationResponse struct {
+	// Start of the period, inclusive
+	// default: 2025-03-14T00:00:00Z
+	From time.Time `json:"from"`
+
+	// End of the period, exclusive
+	// default: 2025-03-15T00:00:00Z
+	To time.Time `json:"to"`
+
+	// Exchanges recorded identically on both sides
+	// default: 120
+	Matched int `json:"matched"`
+
+	// Exchanges not yet delivered to the exchanger
+	// default: 2
+	Pending int `json:"pending"`
+
+	// Exchanges that disagree
+	Mismatches []ExchangeMismatchEntry `json:"mismatches"`
+}
+
+// NewGetExchangeReconciliationHandler returns an HTTP handler that lets an admin reconcile exchanges.
+// @Summary Reconcile exchanges with the exchanger
+// @Description Compares the exchanges executed in a period, with the amounts and rates applied, against the exchanger's records and lists the mismatches for finance. Exchanges not yet delivered to the exchanger are counted as pending. Defaults to the 24 hours ending an hour ago, at most 31 days per request.
+// @Tags admin
+// @Produce json
+// @Param from query string false "Start (RFC 3339), 24 hours before to by default"
+// @Param to query string false "End (RFC 3339), an hour ago by default"
+// @Success 200 {object} handlers.ExchangeReconciliationResponse "Reconciliation report"
+// @Failure 400 {object} apperrors.Response "Invalid query parameters"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Failure 503 {object} apperrors.Response "Reconciliation not configured or exchange service unavailable"
+// @Failure 504 {object} apperrors.Response "Exchange service timeout"
+// @Router /admin/reconciliation [get]
+// @Security BearerAuth
+func NewGetExchangeReconciliationHandler(svc ExchangeReconciler, tokenGetter ExchangeReconciliationTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		if _, err := tokenGetter.GetClaims(ctx, tokenStr); err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		q := r.URL.Query()
+		var from, to time.Time
+		if v := q.Get("from"); v != "" {
+			if from, err = time.Parse(time.RFC3339, v); err != nil {
+				apperrors.Write(w, apperrors.InvalidFrom)
+				return
+			}
+		}
+		if v := q.Get("to"); v != "" {
+			if to, err = time.Parse(time.RFC3339, v); err != nil {
+				apperrors.Write(w, apperrors.InvalidTo)
+				return
+			}
+		}
+
+		report, err := svc.Report(ctx, from, to)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidDateRange):
+				apperrors.Write(w, apperrors.InvalidDateRange)
+			case errors.Is(err, services.ErrExchangeReconciliationDisabled):
+				apperrors.Write(w, apperrors.ExchangeReconciliationDisabled)
+			case errors.Is(err, services.ErrExchangerUnavailable):
+				apperrors.Write(w, apperrors.ExchangerUnavailable)
+			case errors.Is(err, services.ErrExchangerTimeout):
+				apperrors.Write(w, apperrors.ExchangerTimeout)
+			default:
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := ExchangeReconciliationResponse{
+			From:       report.From,
+			To:         report.To,
+			Matched:    report.Matched,
+			Pending:    report.Pending,
+			Mismatches: make([]ExchangeMismatchEntry, 0, len(report.Mismatches)),
+		}
+		for _, m := range report.Mismatches {
+			resp.Mismatches = append(resp.Mismatches, ExchangeMismatchEntry{
+				TransactionID: m.TransactionID,
+				Kind:          m.Kind,
+				Local:         newExchangeRecord(m.Local),
+				Exchanger:     newExchangeRecord(m.Remote),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// newExchangeRecord converts a receipt to its response, keeping nil.
+func newExchangeRecord(receipt *models.ExchangeReceipt) *ExchangeRecord {
+	if receipt == nil {
+		return nil
+	}
+	return &ExchangeRecord{
+		FromCurrency: receipt.FromCurrency,
+		ToCurrency:   receipt.ToCurrency,
+		Amount:       receipt.Amount,
+		ToAmount:     receipt.ToAmount,
+		Rate:         receipt.Rate,
+		ExecutedAt:   receipt.ExecutedAt,
+	}
+}
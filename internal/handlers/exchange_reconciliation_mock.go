@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/exchange_reconciliation.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExchangeReconciliationTokener is a mock of ExchangeReconciliationTokener interface.
+type MockExchangeReconciliationTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeReconciliationTokenerMockRecorder
+}
+
+// MockExchangeReconciliationTokenerMockRecorder is the mock recorder for MockExchangeReconciliationTokener.
+type MockExchangeReconciliationTokenerMockRecorder struct {
+	mock *MockExchangeReconciliationTokener
+}
+
+// NewMockExchangeReconciliationTokener creates a new mock instance.
+func NewMockExchangeReconciliationTokener(ctrl *gomock.Controller) *MockExchangeReconciliationTokener {
+	mock := &MockExchangeReconciliationTokener{ctrl: ctrl}
+	mock.recorder = &MockExchangeReconciliationTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeReconciliationTokener) EXPECT() *MockExchangeReconciliationTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockExchangeReconciliationTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockExchangeReconciliationTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockExchangeReconciliationTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockExchangeReconciliationTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockExchangeReconciliationTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockExchangeReconciliationTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockExchangeReconciler is a mock of ExchangeReconciler interface.
+type MockExchangeReconciler struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeReconcilerMockRecorder
+}
+
+// MockExchangeReconcilerMockRecorder is the mock recorder for MockExchangeReconciler.
+type MockExchangeReconcilerMockRecorder struct {
+	mock *MockExchangeReconciler
+}
+
+// NewMockExchangeReconciler creates a new mock instance.
+func NewMockExchangeReconciler(ctrl *gomock.Controller) *MockExchangeReconciler {
+	mock := &MockExchangeReconciler{ctrl: ctrl}
+	mock.recorder = &MockExchangeReconcilerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeReconciler) EXPECT() *MockExchangeReconcilerMockRecorder {
+	return m.recorder
+}
+
+// Report mocks base method.
+func (m *MockExchangeReconciler) Report(ctx context.Context, from, to time.Time) (*models.ExchangeReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, from, to)
+	ret0, _ := ret[0].(*models.ExchangeReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockExchangeReconcilerMockRecorder) Report(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockExchangeReconciler)(nil).Report), ctx, from, to)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExchangeReconciliationHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeReconciliationTokener(ctrl)
+	mockSvc := NewMockExchangeReconciler(ctrl)
+
+	adminID := uuid.New()
+	transactionID := uuid.New()
+	from := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	local := models.ExchangeReceipt{
+		TransactionID: transactionID,
+		FromCurrency:  models.USD,
+		ToCurrency:    models.EUR,
+		Amount:        money.MustParse("100"),
+		ToAmount:      money.MustParse("90"),
+		Rate:          0.9,
+		ExecutedAt:    from.Add(10 * time.Hour),
+	}
+	remote := local
+	remote.Rate = 0.91
+
+	handler := NewGetExchangeReconciliationHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success",
+			query: "?from=2025-03-14T00:00:00Z&to=2025-03-15T00:00:00Z",
+			mockSvc: func() {
+				mockSvc.EXPECT().Report(gomock.Any(), from, to).Return(&models.ExchangeReconciliationReport{
+					From: from, To: to, Matched: 3, Pending: 1,
+					Mismatches: []models.ExchangeMismatch{
+						{TransactionID: transactionID, Kind: models.ExchangeMismatchRate, Local: &local, Remote: &remote},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeReconciliationResponse{
+				From: from, To: to, Matched: 3, Pending: 1,
+				Mismatches: []ExchangeMismatchEntry{{
+					TransactionID: transactionID,
+					Kind:          models.ExchangeMismatchRate,
+					Local:         newExchangeRecord(&local),
+					Exchanger:     newExchangeRecord(&remote),
+				}},
+			},
+		},
+		{
+			name:  "default_period",
+			query: "",
+			mockSvc: func() {
+				mockSvc.EXPECT().Report(gomock.Any(), time.Time{}, time.Time{}).
+					Return(&models.ExchangeReconciliationReport{From: from, To: to}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ExchangeReconciliationResponse{From: from, To: to, Mismatches: []ExchangeMismatchEntry{}},
+		},
+		{
+			name:           "invalid_to",
+			query:          "?to=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid to", Code: "invalid_to"},
+		},
+		{
+			name:  "invalid_date_range",
+			query: "?from=2025-03-15T00:00:00Z&to=2025-03-14T00:00:00Z",
+			mockSvc: func() {
+				mockSvc.EXPECT().Report(gomock.Any(), to, from).Return(nil, services.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid date range", Code: "invalid_date_range"},
+		},
+		{
+			name:  "not_configured",
+			query: "",
+			mockSvc: func() {
+				mockSvc.EXPECT().Report(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, services.ErrExchangeReconciliationDisabled)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Exchange reconciliation is not configured", Code: "exchange_reconciliation_disabled"},
+		},
+		{
+			name:  "exchanger_timeout",
+			query: "",
+			mockSvc: func() {
+				mockSvc.EXPECT().Report(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, services.ErrExchangerTimeout)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   apperrors.Response{Error: "Exchange service timeout", Code: "exchanger_timeout"},
+		},
+		{
+			name:  "internal_error",
+			query: "",
+			mockSvc: func() {
+				mockSvc.EXPECT().Report(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/reconciliation"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case ExchangeReconciliationResponse:
+				var got ExchangeReconciliationResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetExchangeReconciliationHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExchangeReconciliationTokener(ctrl)
+	mockSvc := NewMockExchangeReconciler(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewGetExchangeReconciliationHandler(mockSvc, mockTokener)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/reconciliation", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
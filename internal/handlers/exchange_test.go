@@ -6,10 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,8 +26,10 @@ func TestExchangeHandler(t *testing.T) {
 	mockExchanger := NewMockExchanger(ctrl)
 
 	userID := uuid.New()
+	quoteID := uuid.New()
+	fetchedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
 
-	handler := NewExchangeHandler(mockTokener, mockExchanger)
+	handler := NewExchangeHandler(mockTokener, mockExchanger, newMockCurrencies(ctrl), time.Time{})
 
 	// Allow token calls for all subtests
 	mockTokener.EXPECT().
@@ -47,67 +53,287 @@ func TestExchangeHandler(t *testing.T) {
 			reqBody: ExchangeRequest{
 				FromCurrency: "USD",
 				ToCurrency:   "EUR",
-				Amount:       100,
+				Amount:       money.MustParse("100"),
 			},
 			mockExchange: func() {
 				mockExchanger.EXPECT().
-					Exchange(gomock.Any(), userID, "USD", "EUR", 100.0).
-					Return(float32(85.0), 200.0, 5000.0, 50.0, nil)
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{ToAmount: money.MustParse("85"), Fee: money.MustParse("1"), Rate: 0.86, RateProvider: "grpc", RateFetchedAt: fetchedAt, RateCached: true}, map[string]money.Amount{"USD": money.MustParse("200"), "RUB": money.MustParse("5000"), "EUR": money.MustParse("50")}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: ExchangeResponse{
 				Message:         "Exchange successful",
-				ExchangedAmount: 85.0,
+				ExchangedAmount: money.MustParse("85"),
+				Fee:             money.MustParse("1"),
+				Rate:            0.86,
 				NewBalance: ExchangedBalance{
-					USD: 200.0,
-					RUB: 5000.0,
-					EUR: 50.0,
+					"USD": money.MustParse("200"),
+					"RUB": money.MustParse("5000"),
+					"EUR": money.MustParse("50"),
 				},
+				RateFetchedAt: fetchedAt,
+				RateCached:    true,
+				RateProvider:  "grpc",
 			},
 		},
+		{
+			name: "success_with_stale_rate",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{ToAmount: money.MustParse("85"), Rate: 0.85, StaleRate: true}, map[string]money.Amount{"USD": money.MustParse("200"), "RUB": money.MustParse("5000"), "EUR": money.MustParse("50")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeResponse{
+				Message:         "Exchange successful",
+				ExchangedAmount: money.MustParse("85"),
+				Rate:            0.85,
+				NewBalance: ExchangedBalance{
+					"USD": money.MustParse("200"),
+					"RUB": money.MustParse("5000"),
+					"EUR": money.MustParse("50"),
+				},
+				StaleRate: true,
+			},
+		},
+		{
+			name:    "success_with_quote",
+			reqBody: ExchangeRequest{QuoteID: quoteID.String()},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					ExchangeQuoted(gomock.Any(), userID, quoteID, "", "", money.Zero, money.Zero).
+					Return(models.ExchangeQuote{ToAmount: money.MustParse("92"), Rate: 0.92}, map[string]money.Amount{"USD": money.MustParse("100"), "EUR": money.MustParse("92")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: ExchangeResponse{
+				Message:         "Exchange successful",
+				ExchangedAmount: money.MustParse("92"),
+				Rate:            0.92,
+				NewBalance: ExchangedBalance{
+					"USD": money.MustParse("100"),
+					"RUB": money.Zero,
+					"EUR": money.MustParse("92"),
+				},
+			},
+		},
+		{
+			name:    "quote_expired",
+			reqBody: ExchangeRequest{QuoteID: quoteID.String(), FromCurrency: "USD"},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					ExchangeQuoted(gomock.Any(), userID, quoteID, "USD", "", money.Zero, money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrQuoteExpired)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Quote expired", Code: "quote_expired"},
+		},
+		{
+			name:    "quote_mismatch",
+			reqBody: ExchangeRequest{QuoteID: quoteID.String(), Amount: money.MustParse("200")},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					ExchangeQuoted(gomock.Any(), userID, quoteID, "", "", money.MustParse("200"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrQuoteMismatch)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Quote does not match the exchange", Code: "quote_mismatch"},
+		},
+		{
+			name:           "bad_request_invalid_quote_id",
+			reqBody:        ExchangeRequest{QuoteID: "not-a-uuid"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid quote ID", Code: "invalid_quote_id", Details: []any{map[string]any{"field": "quote_id", "rule": "uuid", "message": "must be a UUID"}}},
+		},
 		{
 			name:           "bad_request_invalid_amount",
-			reqBody:        ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: -10},
+			reqBody:        ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: money.MustParse("-10")},
+			mockExchange:   nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid currencies", Code: "insufficient_funds_exchange", Details: []any{map[string]any{"field": "amount", "rule": "gte", "param": "0", "message": "must not be less than 0"}}},
+		},
+		{
+			name:           "bad_request_negative_min_expected_amount",
+			reqBody:        ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", Amount: money.MustParse("100"), MinExpectedAmount: money.MustParse("-1")},
 			mockExchange:   nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   ExchangeErrorResponse{Error: "Insufficient funds or invalid currencies"},
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid currencies", Code: "insufficient_funds_exchange", Details: []any{map[string]any{"field": "min_expected_amount", "rule": "gte", "param": "0", "message": "must not be less than 0"}}},
+		},
+		{
+			name:           "bad_request_unsupported_currency",
+			reqBody:        ExchangeRequest{FromCurrency: "USD", ToCurrency: "BTC", Amount: money.MustParse("100")},
+			mockExchange:   nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid currencies", Code: "insufficient_funds_exchange"},
+		},
+		{
+			name:           "bad_request_same_currency",
+			reqBody:        ExchangeRequest{FromCurrency: "USD", ToCurrency: "USD", Amount: money.MustParse("100")},
+			mockExchange:   nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid currencies", Code: "insufficient_funds_exchange"},
 		},
 		{
 			name:           "bad_request_invalid_json",
 			reqBody:        `invalid-json`,
 			mockExchange:   nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   ExchangeErrorResponse{Error: "Insufficient funds or invalid currencies"},
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid currencies", Code: "insufficient_funds_exchange"},
 		},
 		{
 			name: "insufficient_funds",
 			reqBody: ExchangeRequest{
 				FromCurrency: "USD",
 				ToCurrency:   "EUR",
-				Amount:       100,
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid currencies", Code: "insufficient_funds_exchange"},
+		},
+		{
+			name: "fee_exceeds_amount",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
 			},
 			mockExchange: func() {
 				mockExchanger.EXPECT().
-					Exchange(gomock.Any(), userID, "USD", "EUR", 100.0).
-					Return(float32(0), 100.0, 5000.0, 50.0, services.ErrInsufficientFunds)
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrFeeExceedsAmount)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   ExchangeErrorResponse{Error: "Insufficient funds or invalid currencies"},
+			expectedBody:   apperrors.Response{Error: "Amount does not cover the exchange fee", Code: "fee_exceeds_amount"},
+		},
+		{
+			name: "amount_out_of_range",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrExchangeAmountOutOfRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Exchange amount out of range", Code: "exchange_amount_out_of_range"},
+		},
+		{
+			name: "slippage_exceeded",
+			reqBody: ExchangeRequest{
+				FromCurrency:      "USD",
+				ToCurrency:        "EUR",
+				Amount:            money.MustParse("100"),
+				MinExpectedAmount: money.MustParse("92"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.MustParse("92")).
+					Return(models.ExchangeQuote{}, nil, services.ErrSlippageExceeded)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Exchange rate moved, amount below min_expected_amount", Code: "slippage_exceeded"},
+		},
+		{
+			name: "rate_not_found",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrExchangeRateNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Exchange rate not found", Code: "exchange_rate_not_found"},
+		},
+		{
+			name: "daily_limit_exceeded",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrDailyLimitExceeded)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Daily limit exceeded", Code: "daily_limit_exceeded"},
+		},
+		{
+			name: "monthly_limit_exceeded",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrMonthlyLimitExceeded)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Monthly limit exceeded", Code: "monthly_limit_exceeded"},
+		},
+		{
+			name: "exchanger_unavailable",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrExchangerUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Exchange service unavailable", Code: "exchanger_unavailable"},
+		},
+		{
+			name: "exchanger_timeout",
+			reqBody: ExchangeRequest{
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				Amount:       money.MustParse("100"),
+			},
+			mockExchange: func() {
+				mockExchanger.EXPECT().
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, services.ErrExchangerTimeout)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   apperrors.Response{Error: "Exchange service timeout", Code: "exchanger_timeout"},
 		},
 		{
 			name: "internal_server_error",
 			reqBody: ExchangeRequest{
 				FromCurrency: "USD",
 				ToCurrency:   "EUR",
-				Amount:       100,
+				Amount:       money.MustParse("100"),
 			},
 			mockExchange: func() {
 				mockExchanger.EXPECT().
-					Exchange(gomock.Any(), userID, "USD", "EUR", 100.0).
-					Return(float32(0), 100.0, 5000.0, 50.0, assert.AnError)
+					Exchange(gomock.Any(), userID, "USD", "EUR", money.MustParse("100"), money.Zero).
+					Return(models.ExchangeQuote{}, nil, assert.AnError)
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   ExchangeErrorResponse{Error: "Internal server error"},
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
 		},
 	}
 
@@ -139,8 +365,8 @@ func TestExchangeHandler(t *testing.T) {
 				err := json.Unmarshal(respBody, &got)
 				assert.NoError(t, err)
 				assert.Equal(t, expected, got)
-			case ExchangeErrorResponse:
-				var got ExchangeErrorResponse
+			case apperrors.Response:
+				var got apperrors.Response
 				err := json.Unmarshal(respBody, &got)
 				assert.NoError(t, err)
 				assert.Equal(t, expected, got)
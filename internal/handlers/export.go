@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ExportTokener defines only the methods needed by the export handlers.
+type ExportTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// Exporter defines the interface that the service must implement.
+type Exporter interface {
+	RequestExport(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error)
+	GetExport(ctx context.Context, userID, exportID uuid.UUID) (*models.ExportDB, error)
+}
+
+// CreateExportRequest represents a request to export the user's ledger
+// swagger:model CreateExportRequest
+type CreateExportRequest struct {
+	// Export format: accounting_csv or user_data_zip
+	// required: true
+	// default: accounting_csv
+	Format string `json:"format"`
+}
+
+// ExportStatusResponse represents the state of an export
+// swagger:model ExportStatusResponse
+type ExportStatusResponse struct {
+	// Export identifier
+	ExportID string `json:"export_id"`
+
+	// Processing status: pending, processing, completed or failed
+	// default: pending
+	Status string `json:"status"`
+
+	// Failure reason, set when the export failed
+	Error string `json:"error,omitempty"`
+}
+
+// NewCreateExportHandler returns an HTTP handler that queues a ledger export.
+// @Summary Request ledger export
+// @Description Queues an export of the user's ledger. The file is generated in the background; poll GET /exports/{exportID} for the result. Supported formats: accounting_csv (1C-compatible CSV with debit/credit accounts) and user_data_zip (GDPR archive, see POST /me/export).
+// @Tags export
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateExportRequest true "Export Request"
+// @Success 202 {object} handlers.ExportStatusResponse "Export queued"
+// @Failure 400 {object} apperrors.Response "Unsupported export format"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exports [post]
+// @Security BearerAuth
+func NewCreateExportHandler(
+	svc Exporter,
+	tokenGetter ExportTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		var req CreateExportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Warnw("invalid export request", "error", err)
+			apperrors.Write(w, apperrors.UnsupportedExportFormat)
+			return
+		}
+
+		exportID, err := svc.RequestExport(ctx, claims.UserID, req.Format)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrUnsupportedExportFormat):
+				apperrors.Write(w, apperrors.UnsupportedExportFormat)
+			default:
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(ExportStatusResponse{
+			ExportID: exportID.String(),
+			Status:   models.ExportStatusPending,
+		})
+	}
+}
+
+// NewGetExportHandler returns an HTTP handler that reports export status or downloads the file.
+// @Summary Get ledger export
+// @Description Returns the export status. Once the export is completed the generated file is returned as an attachment.
+// @Tags export
+// @Produce json
+// @Produce text/csv
+// @Produce application/zip
+// @Param exportID path string true "Export ID"
+// @Success 200 {object} handlers.ExportStatusResponse "Export status, or the file when completed"
+// @Failure 400 {object} apperrors.Response "Invalid export ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Export not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exports/{exportID} [get]
+// @Security BearerAuth
+func NewGetExportHandler(
+	svc Exporter,
+	tokenGetter ExportTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		exportID, err := uuid.Parse(chi.URLParam(r, "exportID"))
+		if err != nil {
+			logger.FromContext(ctx).Warnw("invalid export ID", "exportID", chi.URLParam(r, "exportID"), "error", err)
+			apperrors.Write(w, apperrors.InvalidExportID)
+			return
+		}
+
+		export, err := svc.GetExport(ctx, claims.UserID, exportID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrExportNotFound):
+				apperrors.Write(w, apperrors.ExportNotFound)
+			default:
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		writeExport(w, export)
+	}
+}
+
+// writeExport writes the file of a completed export as an attachment, or the export status.
+func writeExport(w http.ResponseWriter, export *models.ExportDB) {
+	if export.Status == models.ExportStatusCompleted {
+		contentType, ext := "text/csv; charset=utf-8", ".csv"
+		if export.Format == models.ExportFormatUserDataZIP {
+			contentType, ext = "application/zip", ".zip"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.ExportID.String()+ext))
+		w.WriteHeader(http.StatusOK)
+		w.Write(export.Content)
+		return
+	}
+
+	resp := ExportStatusResponse{
+		ExportID: export.ExportID.String(),
+		Status:   export.Status,
+	}
+	if export.Error != nil {
+		resp.Error = *export.Error
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
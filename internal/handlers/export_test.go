@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateExportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExportTokener(ctrl)
+	mockSvc := NewMockExporter(ctrl)
+
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	handler := NewCreateExportHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "success",
+			body: `{"format":"accounting_csv"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					RequestExport(gomock.Any(), userID, models.ExportFormatAccountingCSV).
+					Return(exportID, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   ExportStatusResponse{ExportID: exportID.String(), Status: models.ExportStatusPending},
+		},
+		{
+			name:           "invalid_json",
+			body:           `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Unsupported export format", Code: "unsupported_export_format"},
+		},
+		{
+			name: "unsupported_format",
+			body: `{"format":"xlsx"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					RequestExport(gomock.Any(), userID, "xlsx").
+					Return(uuid.Nil, services.ErrUnsupportedExportFormat)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Unsupported export format", Code: "unsupported_export_format"},
+		},
+		{
+			name: "internal_error",
+			body: `{"format":"accounting_csv"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					RequestExport(gomock.Any(), userID, models.ExportFormatAccountingCSV).
+					Return(uuid.Nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/exports", bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case ExportStatusResponse:
+				var got ExportStatusResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetExportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExportTokener(ctrl)
+	mockSvc := NewMockExporter(ctrl)
+
+	userID := uuid.New()
+	exportID := uuid.New()
+	failure := "read error"
+
+	handler := NewGetExportHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		exportID       string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:     "pending",
+			exportID: exportID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetExport(gomock.Any(), userID, exportID).
+					Return(&models.ExportDB{ExportID: exportID, Status: models.ExportStatusPending}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ExportStatusResponse{ExportID: exportID.String(), Status: models.ExportStatusPending},
+		},
+		{
+			name:     "failed",
+			exportID: exportID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetExport(gomock.Any(), userID, exportID).
+					Return(&models.ExportDB{ExportID: exportID, Status: models.ExportStatusFailed, Error: &failure}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ExportStatusResponse{ExportID: exportID.String(), Status: models.ExportStatusFailed, Error: failure},
+		},
+		{
+			name:     "completed",
+			exportID: exportID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetExport(gomock.Any(), userID, exportID).
+					Return(&models.ExportDB{ExportID: exportID, Status: models.ExportStatusCompleted, Content: []byte("a;b\n")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "a;b\n",
+		},
+		{
+			name:           "invalid_export_id",
+			exportID:       "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid export ID", Code: "invalid_export_id"},
+		},
+		{
+			name:     "not_found",
+			exportID: exportID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetExport(gomock.Any(), userID, exportID).
+					Return(nil, services.ErrExportNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Export not found", Code: "export_not_found"},
+		},
+		{
+			name:     "internal_error",
+			exportID: exportID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetExport(gomock.Any(), userID, exportID).
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/exports/"+tt.exportID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("exportID", tt.exportID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case string:
+				assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.Equal(t, expected, string(respBody))
+			case ExportStatusResponse:
+				var got ExportStatusResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
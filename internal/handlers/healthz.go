@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HealthzResponse represents the liveness of the process
+// swagger:model HealthzResponse
+type HealthzResponse struct {
+	// Liveness status
+	// default: ok
+	Status string `json:"status"`
+}
+
+// NewHealthzHandler returns an HTTP handler that reports that the process is alive.
+// @Summary Liveness probe
+// @Description Answers as long as the process serves HTTP, without checking any dependency, so an outage of PostgreSQL, Redis, the exchanger or Kafka does not restart the instance. Readiness is reported by /readyz.
+// @Tags health
+// @Produce json
+// @Success 200 {object} handlers.HealthzResponse "Process is alive"
+// @Router /healthz [get]
+func NewHealthzHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(HealthzResponse{Status: "ok"})
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
+)
+
+// HoldTokener defines only the methods needed by the hold handlers.
+type HoldTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// HoldManager defines the interface for placing, capturing and releasing holds.
+type HoldManager interface {
+	CreateHold(ctx context.Context, userID uuid.UUID, amount money.Amount, currency string) (models.WalletHoldDB, error)
+	CaptureHold(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error)
+	ReleaseHold(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error)
+}
+
+// CreateHoldRequest represents the JSON body for placing a hold
+// swagger:model CreateHoldRequest
+type CreateHoldRequest struct {
+	// Amount to hold
+	// required: true
+	// default: 50.0
+	Amount money.Amount `json:"amount" swaggertype:"number" validate:"gt=0"`
+
+	// Currency
+	// required: true
+	// default: USD
+	Currency string `json:"currency" validate:"required"`
+}
+
+// HoldResponse represents a hold
+// swagger:model HoldResponse
+type HoldResponse struct {
+	// Hold ID
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	HoldID string `json:"hold_id"`
+
+	// Currency code
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Held amount
+	// default: 50.0
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+
+	// Hold status (pending, captured, released)
+	// default: pending
+	Status string `json:"status"`
+
+	// Time the hold was placed
+	CreatedAt time.Time `json:"created_at"`
+
+	// Time of the last status change
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCreateHoldHandler returns an HTTP handler placing a hold on the user's funds.
+// @Summary Place a hold
+// @Description Reserves funds in the wallet until they are captured or released. Held funds reduce the available balance but not the total balance, and count against the user's limits.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateHoldRequest true "Hold Request"
+// @Success 201 {object} handlers.HoldResponse "Hold placed"
+// @Failure 400 {object} apperrors.Response "Insufficient funds or invalid amount"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Daily or monthly limit exceeded"
+// @Failure 409 {object} apperrors.Response "Another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/holds [post]
+// @Security BearerAuth
+func NewCreateHoldHandler(svc HoldManager, tokenGetter HoldTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := holdClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req CreateHoldRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Errorw("failed to decode hold request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		if errs := validation.Struct(req); errs != nil {
+			logger.FromContext(ctx).Warnw("invalid hold request", "error", errs, "userID", claims.UserID)
+			apperrors.WriteDetails(w, apperrors.InsufficientFundsWithdraw, errs)
+			return
+		}
+		if !currencies.IsSupported(ctx, req.Currency) || !currencies.ValidAmount(ctx, req.Currency, req.Amount) {
+			logger.FromContext(ctx).Warnw("invalid hold request", "amount", req.Amount, "currency", req.Currency, "userID", claims.UserID)
+			apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
+			return
+		}
+
+		hold, err := svc.CreateHold(ctx, claims.UserID, req.Amount, req.Currency)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInsufficientFunds):
+				apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
+			case errors.Is(err, services.ErrDailyLimitExceeded):
+				apperrors.Write(w, apperrors.DailyLimitExceeded)
+			case errors.Is(err, services.ErrMonthlyLimitExceeded):
+				apperrors.Write(w, apperrors.MonthlyLimitExceeded)
+			default:
+				logger.FromContext(ctx).Errorw("failed to create hold", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		writeHold(w, http.StatusCreated, hold)
+	}
+}
+
+// NewCaptureHoldHandler returns an HTTP handler capturing a pending hold of the user.
+// @Summary Capture a hold
+// @Description Withdraws the held funds from the wallet. The withdrawal is recorded in the transaction history.
+// @Tags wallet
+// @Produce json
+// @Param holdID path string true "Hold ID"
+// @Success 200 {object} handlers.HoldResponse "Hold captured"
+// @Failure 400 {object} apperrors.Response "Invalid hold ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Hold not found"
+// @Failure 409 {object} apperrors.Response "Hold is not pending or another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/holds/{holdID}/capture [post]
+// @Security BearerAuth
+func NewCaptureHoldHandler(svc HoldManager, tokenGetter HoldTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		finishHold(w, r, tokenGetter, svc.CaptureHold)
+	}
+}
+
+// NewReleaseHoldHandler returns an HTTP handler releasing a pending hold of the user.
+// @Summary Release a hold
+// @Description Cancels the hold, returning the funds to the available balance.
+// @Tags wallet
+// @Produce json
+// @Param holdID path string true "Hold ID"
+// @Success 200 {object} handlers.HoldResponse "Hold released"
+// @Failure 400 {object} apperrors.Response "Invalid hold ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Hold not found"
+// @Failure 409 {object} apperrors.Response "Hold is not pending or another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/holds/{holdID}/release [post]
+// @Security BearerAuth
+func NewReleaseHoldHandler(svc HoldManager, tokenGetter HoldTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		finishHold(w, r, tokenGetter, svc.ReleaseHold)
+	}
+}
+
+// finishHold captures or releases the hold in the path with finish.
+func finishHold(
+	w http.ResponseWriter,
+	r *http.Request,
+	tokenGetter HoldTokener,
+	finish func(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error),
+) {
+	claims, ok := holdClaims(w, r, tokenGetter)
+	if !ok {
+		return
+	}
+
+	holdID, err := uuid.Parse(chi.URLParam(r, "holdID"))
+	if err != nil {
+		logger.FromContext(r.Context()).Warnw("invalid hold ID", "holdID", chi.URLParam(r, "holdID"), "error", err)
+		apperrors.Write(w, apperrors.InvalidHoldID)
+		return
+	}
+
+	hold, err := finish(r.Context(), claims.UserID, holdID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrHoldNotFound):
+			apperrors.Write(w, apperrors.HoldNotFound)
+		case errors.Is(err, services.ErrHoldNotPending):
+			apperrors.Write(w, apperrors.HoldNotPending)
+		default:
+			logger.FromContext(r.Context()).Errorw("failed to finish hold", "holdID", holdID, "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+		}
+		return
+	}
+
+	writeHold(w, http.StatusOK, hold)
+}
+
+// writeHold responds with the hold.
+func writeHold(w http.ResponseWriter, status int, hold models.WalletHoldDB) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(HoldResponse{
+		HoldID:    hold.HoldID.String(),
+		Currency:  hold.Currency,
+		Amount:    hold.Amount,
+		Status:    hold.Status,
+		CreatedAt: hold.CreatedAt,
+		UpdatedAt: hold.UpdatedAt,
+	})
+}
+
+// holdClaims authenticates the request, writing 401 on failure.
+func holdClaims(w http.ResponseWriter, r *http.Request, tokenGetter HoldTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/hold.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockHoldTokener is a mock of HoldTokener interface.
+type MockHoldTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldTokenerMockRecorder
+}
+
+// MockHoldTokenerMockRecorder is the mock recorder for MockHoldTokener.
+type MockHoldTokenerMockRecorder struct {
+	mock *MockHoldTokener
+}
+
+// NewMockHoldTokener creates a new mock instance.
+func NewMockHoldTokener(ctrl *gomock.Controller) *MockHoldTokener {
+	mock := &MockHoldTokener{ctrl: ctrl}
+	mock.recorder = &MockHoldTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldTokener) EXPECT() *MockHoldTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockHoldTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockHoldTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockHoldTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockHoldTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockHoldTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockHoldTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockHoldManager is a mock of HoldManager interface.
+type MockHoldManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldManagerMockRecorder
+}
+
+// MockHoldManagerMockRecorder is the mock recorder for MockHoldManager.
+type MockHoldManagerMockRecorder struct {
+	mock *MockHoldManager
+}
+
+// NewMockHoldManager creates a new mock instance.
+func NewMockHoldManager(ctrl *gomock.Controller) *MockHoldManager {
+	mock := &MockHoldManager{ctrl: ctrl}
+	mock.recorder = &MockHoldManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldManager) EXPECT() *MockHoldManagerMockRecorder {
+	return m.recorder
+}
+
+// CaptureHold mocks base method.
+func (m *MockHoldManager) CaptureHold(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, userID, holdID)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockHoldManagerMockRecorder) CaptureHold(ctx, userID, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockHoldManager)(nil).CaptureHold), ctx, userID, holdID)
+}
+
+// CreateHold mocks base method.
+func (m *MockHoldManager) CreateHold(ctx context.Context, userID uuid.UUID, amount money.Amount, currency string) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, userID, amount, currency)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockHoldManagerMockRecorder) CreateHold(ctx, userID, amount, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockHoldManager)(nil).CreateHold), ctx, userID, amount, currency)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockHoldManager) ReleaseHold(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, userID, holdID)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockHoldManagerMockRecorder) ReleaseHold(ctx, userID, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockHoldManager)(nil).ReleaseHold), ctx, userID, holdID)
+}
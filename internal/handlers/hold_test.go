@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateHoldHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockHoldTokener(ctrl)
+	mockSvc := NewMockHoldManager(ctrl)
+
+	userID := uuid.New()
+	holdID := uuid.New()
+	amount := money.MustParse("50")
+
+	handler := NewCreateHoldHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: `{"amount":50,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreateHold(gomock.Any(), userID, amount, models.USD).Return(models.WalletHoldDB{
+					HoldID: holdID, UserID: userID, Currency: models.USD, Amount: amount, Status: models.HoldStatusPending,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: HoldResponse{
+				HoldID: holdID.String(), Currency: models.USD, Amount: amount, Status: models.HoldStatusPending,
+			},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
+		},
+		{
+			name:           "invalid_amount",
+			reqBody:        `{"amount":0,"currency":"USD"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw", Details: []any{map[string]any{"field": "amount", "rule": "gt", "param": "0", "message": "must be greater than 0"}}},
+		},
+		{
+			name:           "invalid_currency",
+			reqBody:        `{"amount":50,"currency":"BTC"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
+		},
+		{
+			name:    "insufficient_funds",
+			reqBody: `{"amount":50,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreateHold(gomock.Any(), userID, amount, models.USD).Return(models.WalletHoldDB{}, services.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
+		},
+		{
+			name:    "daily_limit_exceeded",
+			reqBody: `{"amount":50,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreateHold(gomock.Any(), userID, amount, models.USD).Return(models.WalletHoldDB{}, services.ErrDailyLimitExceeded)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Daily limit exceeded", Code: "daily_limit_exceeded"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: `{"amount":50,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreateHold(gomock.Any(), userID, amount, models.USD).Return(models.WalletHoldDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/holds", bytes.NewBufferString(tt.reqBody))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertHoldBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestFinishHoldHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockHoldTokener(ctrl)
+	mockSvc := NewMockHoldManager(ctrl)
+
+	userID := uuid.New()
+	holdID := uuid.New()
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		holdID         string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "capture",
+			handler: NewCaptureHoldHandler(mockSvc, mockTokener),
+			holdID:  holdID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().CaptureHold(gomock.Any(), userID, holdID).Return(models.WalletHoldDB{
+					HoldID: holdID, Currency: models.EUR, Amount: money.MustParse("10"), Status: models.HoldStatusCaptured,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: HoldResponse{
+				HoldID: holdID.String(), Currency: models.EUR, Amount: money.MustParse("10"), Status: models.HoldStatusCaptured,
+			},
+		},
+		{
+			name:    "release",
+			handler: NewReleaseHoldHandler(mockSvc, mockTokener),
+			holdID:  holdID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().ReleaseHold(gomock.Any(), userID, holdID).Return(models.WalletHoldDB{
+					HoldID: holdID, Currency: models.EUR, Amount: money.MustParse("10"), Status: models.HoldStatusReleased,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: HoldResponse{
+				HoldID: holdID.String(), Currency: models.EUR, Amount: money.MustParse("10"), Status: models.HoldStatusReleased,
+			},
+		},
+		{
+			name:           "invalid_hold_id",
+			handler:        NewCaptureHoldHandler(mockSvc, mockTokener),
+			holdID:         "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid hold ID", Code: "invalid_hold_id"},
+		},
+		{
+			name:    "not_found",
+			handler: NewCaptureHoldHandler(mockSvc, mockTokener),
+			holdID:  holdID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().CaptureHold(gomock.Any(), userID, holdID).Return(models.WalletHoldDB{}, services.ErrHoldNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Hold not found", Code: "hold_not_found"},
+		},
+		{
+			name:    "not_pending",
+			handler: NewReleaseHoldHandler(mockSvc, mockTokener),
+			holdID:  holdID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().ReleaseHold(gomock.Any(), userID, holdID).Return(models.WalletHoldDB{}, services.ErrHoldNotPending)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Hold is not pending", Code: "hold_not_pending"},
+		},
+		{
+			name:    "internal_error",
+			handler: NewReleaseHoldHandler(mockSvc, mockTokener),
+			holdID:  holdID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().ReleaseHold(gomock.Any(), userID, holdID).Return(models.WalletHoldDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/holds/"+tt.holdID+"/capture", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("holdID", tt.holdID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertHoldBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestHoldHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockHoldTokener(ctrl)
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("", errors.New("no token"))
+
+	handlers := map[string]http.HandlerFunc{
+		"create":  NewCreateHoldHandler(NewMockHoldManager(ctrl), mockTokener, newMockCurrencies(ctrl)),
+		"capture": NewCaptureHoldHandler(NewMockHoldManager(ctrl), mockTokener),
+		"release": NewReleaseHoldHandler(NewMockHoldManager(ctrl), mockTokener),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assertHoldBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Unauthorized", Code: "unauthorized"})
+		})
+	}
+}
+
+func assertHoldBody(t *testing.T, body []byte, expectedBody interface{}) {
+	t.Helper()
+	switch expected := expectedBody.(type) {
+	case HoldResponse:
+		var got HoldResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		got.CreatedAt, got.UpdatedAt = expected.CreatedAt, expected.UpdatedAt
+		assert.Equal(t, expected, got)
+	case apperrors.Response:
+		var got apperrors.Response
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ImpersonateTokener defines only the methods needed by this handler.
+type ImpersonateTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// Impersonator defines the interface that the service must implement.
+type Impersonator interface {
+	Impersonate(ctx context.Context, adminID, userID uuid.UUID) (token string, expiresIn time.Duration, err error)
+}
+
+// ImpersonateResponse represents a successful impersonation response
+// swagger:model ImpersonateResponse
+type ImpersonateResponse struct {
+	// Short-lived JWT token acting as the user
+	// default: JWT_TOKEN
+	Token string `json:"token"`
+
+	// Token lifetime in seconds
+	// default: 900
+	ExpiresIn int `json:"expires_in"`
+}
+
+// NewImpersonateHandler returns an HTTP handler that lets support staff act as a user.
+// @Summary Impersonate a user
+// @Description Issues a short-lived token for the user with an act claim identifying the admin. The action is recorded in the audit trail.
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} handlers.ImpersonateResponse "Impersonation token issued"
+// @Failure 400 {object} apperrors.Response "Invalid user ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "User not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/impersonate/{userID} [post]
+// @Security BearerAuth
+func NewImpersonateHandler(
+	svc Impersonator,
+	tokenGetter ImpersonateTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+		if err != nil {
+			logger.FromContext(ctx).Warnw("invalid impersonation user ID", "userID", chi.URLParam(r, "userID"), "error", err)
+			apperrors.Write(w, apperrors.InvalidUserID)
+			return
+		}
+
+		token, expiresIn, err := svc.Impersonate(ctx, claims.UserID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrUserDoesNotExist):
+				apperrors.Write(w, apperrors.UserNotFound)
+			case errors.Is(err, services.ErrCannotImpersonateAdmin):
+				apperrors.Write(w, apperrors.AdminImpersonation)
+			default:
+				logger.FromContext(ctx).Errorw("failed to impersonate user", "adminID", claims.UserID, "userID", userID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := ImpersonateResponse{
+			Token:     token,
+			ExpiresIn: int(expiresIn.Seconds()),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/impersonate.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+)
+
+// MockImpersonateTokener is a mock of ImpersonateTokener interface.
+type MockImpersonateTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonateTokenerMockRecorder
+}
+
+// MockImpersonateTokenerMockRecorder is the mock recorder for MockImpersonateTokener.
+type MockImpersonateTokenerMockRecorder struct {
+	mock *MockImpersonateTokener
+}
+
+// NewMockImpersonateTokener creates a new mock instance.
+func NewMockImpersonateTokener(ctrl *gomock.Controller) *MockImpersonateTokener {
+	mock := &MockImpersonateTokener{ctrl: ctrl}
+	mock.recorder = &MockImpersonateTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImpersonateTokener) EXPECT() *MockImpersonateTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockImpersonateTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockImpersonateTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockImpersonateTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockImpersonateTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockImpersonateTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockImpersonateTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockImpersonator is a mock of Impersonator interface.
+type MockImpersonator struct {
+	ctrl     *gomock.Controller
+	recorder *MockImpersonatorMockRecorder
+}
+
+// MockImpersonatorMockRecorder is the mock recorder for MockImpersonator.
+type MockImpersonatorMockRecorder struct {
+	mock *MockImpersonator
+}
+
+// NewMockImpersonator creates a new mock instance.
+func NewMockImpersonator(ctrl *gomock.Controller) *MockImpersonator {
+	mock := &MockImpersonator{ctrl: ctrl}
+	mock.recorder = &MockImpersonatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImpersonator) EXPECT() *MockImpersonatorMockRecorder {
+	return m.recorder
+}
+
+// Impersonate mocks base method.
+func (m *MockImpersonator) Impersonate(ctx context.Context, adminID, userID uuid.UUID) (string, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", ctx, adminID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockImpersonatorMockRecorder) Impersonate(ctx, adminID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockImpersonator)(nil).Impersonate), ctx, adminID, userID)
+}
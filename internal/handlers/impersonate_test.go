@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonateHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockImpersonateTokener(ctrl)
+	mockSvc := NewMockImpersonator(ctrl)
+
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	handler := NewImpersonateHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:   "success",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Impersonate(gomock.Any(), adminID, userID).
+					Return("imp-token", 15*time.Minute, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ImpersonateResponse{Token: "imp-token", ExpiresIn: 900},
+		},
+		{
+			name:           "invalid_user_id",
+			userID:         "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid user ID", Code: "invalid_user_id"},
+		},
+		{
+			name:   "user_not_found",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Impersonate(gomock.Any(), adminID, userID).
+					Return("", time.Duration(0), services.ErrUserDoesNotExist)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "User not found", Code: "user_not_found"},
+		},
+		{
+			name:   "target_is_admin",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Impersonate(gomock.Any(), adminID, userID).
+					Return("", time.Duration(0), services.ErrCannotImpersonateAdmin)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Admins cannot be impersonated", Code: "admin_impersonation"},
+		},
+		{
+			name:   "internal_error",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Impersonate(gomock.Any(), adminID, userID).
+					Return("", time.Duration(0), errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/impersonate/"+tt.userID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("userID", tt.userID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case ImpersonateResponse:
+				var got ImpersonateResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestImpersonateHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockImpersonateTokener(ctrl)
+	mockSvc := NewMockImpersonator(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewImpersonateHandler(mockSvc, mockTokener)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/impersonate/"+uuid.NewString(), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
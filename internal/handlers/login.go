@@ -4,15 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 )
 
 // Loginer defines the interface that the login service must implement.
 type Loginer interface {
-	Login(ctx context.Context, username, password string) (string, error)
+	Login(ctx context.Context, username, password string, client models.ClientInfo) (string, error)
 }
 
 // LoginRequest represents the JSON body for user login
@@ -37,14 +40,6 @@ type LoginResponse struct {
 	Token string `json:"token"`
 }
 
-// LoginErrorResponse represents an error response for login
-// swagger:model LoginErrorResponse
-type LoginErrorResponse struct {
-	// Error message
-	// default: Invalid username or password
-	Error string `json:"error"`
-}
-
 // NewLoginHandler returns an HTTP handler for user login.
 // @Summary User login
 // @Description Authenticate user and return JWT token
@@ -53,37 +48,29 @@ type LoginErrorResponse struct {
 // @Produce json
 // @Param loginRequest body handlers.LoginRequest true "Login Request"
 // @Success 200 {object} handlers.LoginResponse "JWT token returned"
-// @Failure 400 {object} handlers.LoginErrorResponse "Invalid request body"
-// @Failure 401 {object} handlers.LoginErrorResponse "Invalid username or password"
+// @Failure 400 {object} apperrors.Response "Invalid request body"
+// @Failure 401 {object} apperrors.Response "Invalid username or password"
+// @Failure 429 {object} apperrors.Response "Too many requests"
 // @Router /login [post]
 func NewLoginHandler(svc Loginer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req LoginRequest
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			logger.Log.Errorw("failed to decode login request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(LoginErrorResponse{
-				Error: "invalid request body",
-			})
+			logger.FromContext(r.Context()).Errorw("failed to decode login request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
 			return
 		}
 
-		token, err := svc.Login(r.Context(), req.Username, req.Password)
+		token, err := svc.Login(r.Context(), req.Username, req.Password, clientInfo(r))
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrUserDoesNotExist):
-				logger.Log.Warnw("login failed for user", "username", req.Username, "error", err)
-				w.WriteHeader(http.StatusUnauthorized)
-				json.NewEncoder(w).Encode(LoginErrorResponse{
-					Error: "Invalid username or password",
-				})
+				logger.FromContext(r.Context()).Warnw("login failed for user", "username", req.Username, "error", err)
+				apperrors.Write(w, apperrors.InvalidCredentials)
 			default:
-				logger.Log.Errorw("internal server error during login", "username", req.Username, "error", err)
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(LoginErrorResponse{
-					Error: "Internal server error",
-				})
+				logger.FromContext(r.Context()).Errorw("internal server error during login", "username", req.Username, "error", err)
+				apperrors.Write(w, apperrors.Internal)
 			}
 			return
 		}
@@ -94,3 +81,16 @@ func NewLoginHandler(svc Loginer) http.HandlerFunc {
 		})
 	}
 }
+
+// clientInfo extracts the client IP and User-Agent from the request.
+// The IP is taken from RemoteAddr, which the router rewrites from the headers of trusted proxies.
+func clientInfo(r *http.Request) models.ClientInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return models.ClientInfo{
+		IP:        ip,
+		UserAgent: r.UserAgent(),
+	}
+}
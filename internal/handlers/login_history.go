@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// LoginHistoryTokener defines only the methods needed by this handler.
+type LoginHistoryTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// LoginHistoryReader defines the interface that the service must implement.
+type LoginHistoryReader interface {
+	GetLogins(ctx context.Context, userID uuid.UUID, limit int) ([]models.AuthEventDB, error)
+}
+
+// LoginEntry represents a single login attempt
+// swagger:model LoginEntry
+type LoginEntry struct {
+	// Whether the login succeeded
+	// default: true
+	Success bool `json:"success"`
+
+	// Client IP address
+	// default: 203.0.113.7
+	IP string `json:"ip"`
+
+	// Client User-Agent
+	// default: Mozilla/5.0
+	UserAgent string `json:"user_agent"`
+
+	// Client country code, omitted if unknown
+	// default: NL
+	Country string `json:"country,omitempty"`
+
+	// Time of the attempt
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// LoginHistoryResponse represents the user's recent logins
+// swagger:model LoginHistoryResponse
+type LoginHistoryResponse struct {
+	// Logins, newest first
+	Logins []LoginEntry `json:"logins"`
+}
+
+// NewGetLoginHistoryHandler returns an HTTP handler listing the user's recent logins.
+// @Summary Get login history
+// @Description Returns the last successful and failed logins to the account with timestamps, IP addresses, countries and user agents, newest first.
+// @Tags auth
+// @Produce json
+// @Param limit query int false "Number of logins to return (default 20, max 100)"
+// @Success 200 {object} handlers.LoginHistoryResponse "Login history"
+// @Failure 400 {object} apperrors.Response "Invalid limit"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /me/logins [get]
+// @Security BearerAuth
+func NewGetLoginHistoryHandler(
+	svc LoginHistoryReader,
+	tokenGetter LoginHistoryTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		limit := 0
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+				apperrors.Write(w, apperrors.InvalidLimit)
+				return
+			}
+		}
+
+		events, err := svc.GetLogins(ctx, claims.UserID, limit)
+		if err != nil {
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := LoginHistoryResponse{Logins: make([]LoginEntry, 0, len(events))}
+		for _, e := range events {
+			resp.Logins = append(resp.Logins, LoginEntry{
+				Success:   e.EventType == models.AuthEventLoginSuccess,
+				IP:        e.IP,
+				UserAgent: e.UserAgent,
+				Country:   e.Country,
+				Timestamp: e.CreatedAt,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/login_history.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockLoginHistoryTokener is a mock of LoginHistoryTokener interface.
+type MockLoginHistoryTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginHistoryTokenerMockRecorder
+}
+
+// MockLoginHistoryTokenerMockRecorder is the mock recorder for MockLoginHistoryTokener.
+type MockLoginHistoryTokenerMockRecorder struct {
+	mock *MockLoginHistoryTokener
+}
+
+// NewMockLoginHistoryTokener creates a new mock instance.
+func NewMockLoginHistoryTokener(ctrl *gomock.Controller) *MockLoginHistoryTokener {
+	mock := &MockLoginHistoryTokener{ctrl: ctrl}
+	mock.recorder = &MockLoginHistoryTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginHistoryTokener) EXPECT() *MockLoginHistoryTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockLoginHistoryTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockLoginHistoryTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockLoginHistoryTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockLoginHistoryTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockLoginHistoryTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockLoginHistoryTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockLoginHistoryReader is a mock of LoginHistoryReader interface.
+type MockLoginHistoryReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginHistoryReaderMockRecorder
+}
+
+// MockLoginHistoryReaderMockRecorder is the mock recorder for MockLoginHistoryReader.
+type MockLoginHistoryReaderMockRecorder struct {
+	mock *MockLoginHistoryReader
+}
+
+// NewMockLoginHistoryReader creates a new mock instance.
+func NewMockLoginHistoryReader(ctrl *gomock.Controller) *MockLoginHistoryReader {
+	mock := &MockLoginHistoryReader{ctrl: ctrl}
+	mock.recorder = &MockLoginHistoryReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginHistoryReader) EXPECT() *MockLoginHistoryReaderMockRecorder {
+	return m.recorder
+}
+
+// GetLogins mocks base method.
+func (m *MockLoginHistoryReader) GetLogins(ctx context.Context, userID uuid.UUID, limit int) ([]models.AuthEventDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogins", ctx, userID, limit)
+	ret0, _ := ret[0].([]models.AuthEventDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogins indicates an expected call of GetLogins.
+func (mr *MockLoginHistoryReaderMockRecorder) GetLogins(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogins", reflect.TypeOf((*MockLoginHistoryReader)(nil).GetLogins), ctx, userID, limit)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLoginHistoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockLoginHistoryTokener(ctrl)
+	mockSvc := NewMockLoginHistoryReader(ctrl)
+
+	userID := uuid.New()
+	at := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	handler := NewGetLoginHistoryHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success",
+			query: "?limit=2",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetLogins(gomock.Any(), userID, 2).
+					Return([]models.AuthEventDB{
+						{EventType: models.AuthEventLoginSuccess, IP: "203.0.113.7", UserAgent: "curl/8.0", Country: "NL", CreatedAt: at},
+						{EventType: models.AuthEventLoginFailure, IP: "198.51.100.1", UserAgent: "bot", CreatedAt: at.Add(-time.Hour)},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: LoginHistoryResponse{Logins: []LoginEntry{
+				{Success: true, IP: "203.0.113.7", UserAgent: "curl/8.0", Country: "NL", Timestamp: at},
+				{Success: false, IP: "198.51.100.1", UserAgent: "bot", Timestamp: at.Add(-time.Hour)},
+			}},
+		},
+		{
+			name: "empty_history_default_limit",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetLogins(gomock.Any(), userID, 0).
+					Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   LoginHistoryResponse{Logins: []LoginEntry{}},
+		},
+		{
+			name:           "invalid_limit",
+			query:          "?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid limit", Code: "invalid_limit"},
+		},
+		{
+			name: "internal_error",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					GetLogins(gomock.Any(), userID, 0).
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/me/logins"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case LoginHistoryResponse:
+				var got LoginHistoryResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetLoginHistoryHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockLoginHistoryTokener(ctrl)
+	mockSvc := NewMockLoginHistoryReader(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewGetLoginHistoryHandler(mockSvc, mockTokener)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/logins", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
 )
 
 // MockLoginer is a mock of Loginer interface.
@@ -35,16 +36,16 @@ func (m *MockLoginer) EXPECT() *MockLoginerMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockLoginer) Login(ctx context.Context, username, password string) (string, error) {
+func (m *MockLoginer) Login(ctx context.Context, username, password string, client models.ClientInfo) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, username, password)
+	ret := m.ctrl.Call(m, "Login", ctx, username, password, client)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockLoginerMockRecorder) Login(ctx, username, password interface{}) *gomock.Call {
+func (mr *MockLoginerMockRecorder) Login(ctx, username, password, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockLoginer)(nil).Login), ctx, username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockLoginer)(nil).Login), ctx, username, password, client)
 }
@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,7 +36,7 @@ func TestLoginHandler(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockSvc.EXPECT().
-					Login(gomock.Any(), "john", "pass123").
+					Login(gomock.Any(), "john", "pass123", gomock.Any()).
 					Return("JWT_TOKEN", nil)
 			},
 			expectedCode: http.StatusOK,
@@ -47,9 +49,7 @@ func TestLoginHandler(t *testing.T) {
 			inputBody:    "{invalid json}",
 			mockSetup:    func() {},
 			expectedCode: http.StatusBadRequest,
-			expectedBody: &LoginErrorResponse{
-				Error: "invalid request body",
-			},
+			expectedBody: &apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
 		},
 		{
 			name: "user does not exist / wrong credentials",
@@ -59,13 +59,11 @@ func TestLoginHandler(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockSvc.EXPECT().
-					Login(gomock.Any(), "wronguser", "wrongpass").
+					Login(gomock.Any(), "wronguser", "wrongpass", gomock.Any()).
 					Return("", services.ErrUserDoesNotExist)
 			},
 			expectedCode: http.StatusUnauthorized,
-			expectedBody: &LoginErrorResponse{
-				Error: "Invalid username or password",
-			},
+			expectedBody: &apperrors.Response{Error: "Invalid username or password", Code: "invalid_credentials"},
 		},
 		{
 			name: "internal error",
@@ -75,13 +73,11 @@ func TestLoginHandler(t *testing.T) {
 			},
 			mockSetup: func() {
 				mockSvc.EXPECT().
-					Login(gomock.Any(), "john", "pass123").
+					Login(gomock.Any(), "john", "pass123", gomock.Any()).
 					Return("", errors.New("database error"))
 			},
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: &LoginErrorResponse{
-				Error: "Internal server error",
-			},
+			expectedBody: &apperrors.Response{Error: "Internal server error", Code: "internal"},
 		},
 	}
 
@@ -111,7 +107,7 @@ func TestLoginHandler(t *testing.T) {
 				case http.StatusOK:
 					respBody = &LoginResponse{}
 				default:
-					respBody = &LoginErrorResponse{}
+					respBody = &apperrors.Response{}
 				}
 				err := json.Unmarshal(w.Body.Bytes(), respBody)
 				assert.NoError(t, err)
@@ -120,3 +116,23 @@ func TestLoginHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginHandler_PassesClientInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockLoginer(ctrl)
+	mockSvc.EXPECT().
+		Login(gomock.Any(), "john", "pass123", models.ClientInfo{IP: "203.0.113.7", UserAgent: "curl/8.0"}).
+		Return("token", nil)
+
+	body, _ := json.Marshal(LoginRequest{Username: "john", Password: "pass123"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	req.RemoteAddr = "203.0.113.7:54321"
+	req.Header.Set("User-Agent", "curl/8.0")
+	w := httptest.NewRecorder()
+
+	NewLoginHandler(mockSvc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
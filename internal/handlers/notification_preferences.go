@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// NotificationPreferencesTokener defines only the methods needed by these handlers.
+type NotificationPreferencesTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// NotificationPreferencesManager defines the interface that the service must implement.
+type NotificationPreferencesManager interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesDB, error)
+	UpdatePreferences(ctx context.Context, prefs models.NotificationPreferencesDB) error
+}
+
+// NotificationPreferences represents the user's notification settings
+// swagger:model NotificationPreferences
+type NotificationPreferences struct {
+	// Deliver notifications by email
+	// default: true
+	EmailEnabled bool `json:"email_enabled"`
+
+	// Deliver notifications by SMS, requires phone
+	// default: false
+	SMSEnabled bool `json:"sms_enabled"`
+
+	// Phone number for SMS
+	// default: +79990000000
+	Phone *string `json:"phone,omitempty"`
+
+	// Notify about logins from a new country or device
+	// default: true
+	SecurityAlerts bool `json:"security_alerts"`
+}
+
+// NewGetNotificationPreferencesHandler returns an HTTP handler with the user's notification settings.
+// @Summary Get notification preferences
+// @Description Returns how the user is notified about security events. Defaults apply until the user saves their own settings.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} handlers.NotificationPreferences "Notification preferences"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /me/notification-preferences [get]
+// @Security BearerAuth
+func NewGetNotificationPreferencesHandler(
+	svc NotificationPreferencesManager,
+	tokenGetter NotificationPreferencesTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := notificationPreferencesClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		prefs, err := svc.GetPreferences(ctx, claims.UserID)
+		if err != nil {
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(NotificationPreferences{
+			EmailEnabled:   prefs.EmailEnabled,
+			SMSEnabled:     prefs.SMSEnabled,
+			Phone:          prefs.Phone,
+			SecurityAlerts: prefs.SecurityAlerts,
+		})
+	}
+}
+
+// NewUpdateNotificationPreferencesHandler returns an HTTP handler that replaces the user's notification settings.
+// @Summary Update notification preferences
+// @Description Replaces how the user is notified about security events.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body handlers.NotificationPreferences true "Notification preferences"
+// @Success 200 {object} handlers.NotificationPreferences "Notification preferences saved"
+// @Failure 400 {object} apperrors.Response "Invalid request"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /me/notification-preferences [put]
+// @Security BearerAuth
+func NewUpdateNotificationPreferencesHandler(
+	svc NotificationPreferencesManager,
+	tokenGetter NotificationPreferencesTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := notificationPreferencesClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req NotificationPreferences
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		err := svc.UpdatePreferences(ctx, models.NotificationPreferencesDB{
+			UserID:         claims.UserID,
+			EmailEnabled:   req.EmailEnabled,
+			SMSEnabled:     req.SMSEnabled,
+			Phone:          req.Phone,
+			SecurityAlerts: req.SecurityAlerts,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrPhoneRequired) {
+				apperrors.Write(w, apperrors.PhoneRequired)
+				return
+			}
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(req)
+	}
+}
+
+// notificationPreferencesClaims extracts the caller's claims, writing 401 on failure.
+func notificationPreferencesClaims(w http.ResponseWriter, r *http.Request, tokenGetter NotificationPreferencesTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/notification_preferences.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockNotificationPreferencesTokener is a mock of NotificationPreferencesTokener interface.
+type MockNotificationPreferencesTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPreferencesTokenerMockRecorder
+}
+
+// MockNotificationPreferencesTokenerMockRecorder is the mock recorder for MockNotificationPreferencesTokener.
+type MockNotificationPreferencesTokenerMockRecorder struct {
+	mock *MockNotificationPreferencesTokener
+}
+
+// NewMockNotificationPreferencesTokener creates a new mock instance.
+func NewMockNotificationPreferencesTokener(ctrl *gomock.Controller) *MockNotificationPreferencesTokener {
+	mock := &MockNotificationPreferencesTokener{ctrl: ctrl}
+	mock.recorder = &MockNotificationPreferencesTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPreferencesTokener) EXPECT() *MockNotificationPreferencesTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockNotificationPreferencesTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockNotificationPreferencesTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockNotificationPreferencesTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockNotificationPreferencesTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockNotificationPreferencesTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockNotificationPreferencesTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockNotificationPreferencesManager is a mock of NotificationPreferencesManager interface.
+type MockNotificationPreferencesManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPreferencesManagerMockRecorder
+}
+
+// MockNotificationPreferencesManagerMockRecorder is the mock recorder for MockNotificationPreferencesManager.
+type MockNotificationPreferencesManagerMockRecorder struct {
+	mock *MockNotificationPreferencesManager
+}
+
+// NewMockNotificationPreferencesManager creates a new mock instance.
+func NewMockNotificationPreferencesManager(ctrl *gomock.Controller) *MockNotificationPreferencesManager {
+	mock := &MockNotificationPreferencesManager{ctrl: ctrl}
+	mock.recorder = &MockNotificationPreferencesManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPreferencesManager) EXPECT() *MockNotificationPreferencesManagerMockRecorder {
+	return m.recorder
+}
+
+// GetPreferences mocks base method.
+func (m *MockNotificationPreferencesManager) GetPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userID)
+	ret0, _ := ret[0].(models.NotificationPreferencesDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockNotificationPreferencesManagerMockRecorder) GetPreferences(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockNotificationPreferencesManager)(nil).GetPreferences), ctx, userID)
+}
+
+// UpdatePreferences mocks base method.
+func (m *MockNotificationPreferencesManager) UpdatePreferences(ctx context.Context, prefs models.NotificationPreferencesDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", ctx, prefs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences.
+func (mr *MockNotificationPreferencesManagerMockRecorder) UpdatePreferences(ctx, prefs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockNotificationPreferencesManager)(nil).UpdatePreferences), ctx, prefs)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotificationPreferencesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockNotificationPreferencesTokener(ctrl)
+	mockSvc := NewMockNotificationPreferencesManager(ctrl)
+
+	userID := uuid.New()
+	phone := "+79990000000"
+
+	handler := NewGetNotificationPreferencesHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "success",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetPreferences(gomock.Any(), userID).Return(models.NotificationPreferencesDB{
+					UserID: userID, EmailEnabled: true, SMSEnabled: true, Phone: &phone, SecurityAlerts: true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   NotificationPreferences{EmailEnabled: true, SMSEnabled: true, Phone: &phone, SecurityAlerts: true},
+		},
+		{
+			name: "internal_error",
+			mockSvc: func() {
+				mockSvc.EXPECT().GetPreferences(gomock.Any(), userID).Return(models.NotificationPreferencesDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSvc()
+
+			req := httptest.NewRequest(http.MethodGet, "/me/notification-preferences", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertNotificationPreferencesBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestUpdateNotificationPreferencesHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockNotificationPreferencesTokener(ctrl)
+	mockSvc := NewMockNotificationPreferencesManager(ctrl)
+
+	userID := uuid.New()
+	phone := "+79990000000"
+
+	handler := NewUpdateNotificationPreferencesHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: `{"email_enabled":false,"sms_enabled":true,"phone":"+79990000000","security_alerts":true}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().UpdatePreferences(gomock.Any(), models.NotificationPreferencesDB{
+					UserID: userID, SMSEnabled: true, Phone: &phone, SecurityAlerts: true,
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   NotificationPreferences{SMSEnabled: true, Phone: &phone, SecurityAlerts: true},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:    "phone_required",
+			reqBody: `{"sms_enabled":true}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().UpdatePreferences(gomock.Any(), gomock.Any()).Return(services.ErrPhoneRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Phone is required for SMS notifications", Code: "phone_required"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: `{"email_enabled":true}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().UpdatePreferences(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/me/notification-preferences", bytes.NewBufferString(tt.reqBody))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertNotificationPreferencesBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestNotificationPreferencesHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockNotificationPreferencesTokener(ctrl)
+	mockSvc := NewMockNotificationPreferencesManager(ctrl)
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		Return(nil, errors.New("invalid token"))
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("no token"))
+
+	for _, handler := range []http.HandlerFunc{
+		NewGetNotificationPreferencesHandler(mockSvc, mockTokener),
+		NewUpdateNotificationPreferencesHandler(mockSvc, mockTokener),
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/me/notification-preferences", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assertNotificationPreferencesBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Unauthorized", Code: "unauthorized"})
+	}
+}
+
+func assertNotificationPreferencesBody(t *testing.T, body []byte, expectedBody interface{}) {
+	t.Helper()
+	switch expected := expectedBody.(type) {
+	case NotificationPreferences:
+		var got NotificationPreferences
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	case apperrors.Response:
+		var got apperrors.Response
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	}
+}
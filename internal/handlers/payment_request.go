@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
+)
+
+// PaymentRequestTokener defines only the methods needed by the payment request handlers.
+type PaymentRequestTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// PaymentRequestManager defines the interface for making and answering payment requests.
+type PaymentRequestManager interface {
+	CreatePaymentRequest(ctx context.Context, requesterID uuid.UUID, payerUsername string, amount money.Amount, currency, note string) (models.PaymentRequestDB, error)
+	ListPaymentRequests(ctx context.Context, userID uuid.UUID) ([]models.PaymentRequestDB, error)
+	AcceptPaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error)
+	DeclinePaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error)
+}
+
+// CreatePaymentRequestRequest represents the JSON body for requesting money from another user
+// swagger:model CreatePaymentRequestRequest
+type CreatePaymentRequestRequest struct {
+	// Username of the user asked to pay
+	// required: true
+	// default: bob
+	Payer string `json:"payer" validate:"required"`
+
+	// Requested amount
+	// required: true
+	// default: 25.0
+	Amount money.Amount `json:"amount" swaggertype:"number" validate:"gt=0"`
+
+	// Currency
+	// required: true
+	// default: USD
+	Currency string `json:"currency" validate:"required"`
+
+	// Optional message to the payer, at most 128 characters
+	// default: Dinner on Friday
+	Note string `json:"note,omitempty" validate:"max=128"`
+}
+
+// PaymentRequestResponse represents a payment request
+// swagger:model PaymentRequestResponse
+type PaymentRequestResponse struct {
+	// Payment request ID
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	RequestID string `json:"request_id"`
+
+	// User asking for the money
+	RequesterID string `json:"requester_id"`
+
+	// User asked to pay
+	PayerID string `json:"payer_id"`
+
+	// Currency code
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Requested amount
+	// default: 25.0
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+
+	// Message to the payer
+	// default: Dinner on Friday
+	Note string `json:"note,omitempty"`
+
+	// Request status (pending, accepted, declined, expired)
+	// default: pending
+	Status string `json:"status"`
+
+	// Transaction of the payment, accepted requests only
+	TransactionID string `json:"transaction_id,omitempty"`
+
+	// Time after which the request can no longer be accepted
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Time the request was made
+	CreatedAt time.Time `json:"created_at"`
+
+	// Time of the last status change
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PaymentRequestsResponse represents the payment requests of the user
+// swagger:model PaymentRequestsResponse
+type PaymentRequestsResponse struct {
+	// Latest payment requests the user made or was asked to pay, newest first
+	PaymentRequests []PaymentRequestResponse `json:"payment_requests"`
+}
+
+// NewCreatePaymentRequestHandler returns an HTTP handler requesting money from another user.
+// @Summary Request money
+// @Description Asks another user, given by username, to pay an amount. The payer is notified with a payment_request.created webhook event and can accept or decline the request until it expires after 7 days.
+// @Tags payment-requests
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreatePaymentRequestRequest true "Payment Request"
+// @Success 201 {object} handlers.PaymentRequestResponse "Payment request made"
+// @Failure 400 {object} apperrors.Response "Invalid amount, currency, payer or note"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "User not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /payment-requests [post]
+// @Security BearerAuth
+func NewCreatePaymentRequestHandler(svc PaymentRequestManager, tokenGetter PaymentRequestTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := paymentRequestClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req CreatePaymentRequestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Errorw("failed to decode payment request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		if errs := validation.Struct(req); errs != nil {
+			logger.FromContext(ctx).Warnw("invalid payment request", "error", errs, "userID", claims.UserID)
+			writeInvalid(w, errs, apperrors.InvalidAmountOrCurrency, map[string]apperrors.Error{
+				"payer": apperrors.InvalidPayer,
+				"note":  apperrors.InvalidNote,
+			})
+			return
+		}
+		if !currencies.IsSupported(ctx, req.Currency) || !currencies.ValidAmount(ctx, req.Currency, req.Amount) {
+			logger.FromContext(ctx).Warnw("invalid payment request", "amount", req.Amount, "currency", req.Currency, "userID", claims.UserID)
+			apperrors.Write(w, apperrors.InvalidAmountOrCurrency)
+			return
+		}
+
+		request, err := svc.CreatePaymentRequest(ctx, claims.UserID, req.Payer, req.Amount, req.Currency, req.Note)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidPayer):
+				apperrors.Write(w, apperrors.InvalidPayer)
+			case errors.Is(err, services.ErrUserDoesNotExist):
+				apperrors.Write(w, apperrors.UserNotFound)
+			default:
+				logger.FromContext(ctx).Errorw("failed to create payment request", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(newPaymentRequestResponse(request))
+	}
+}
+
+// NewListPaymentRequestsHandler returns an HTTP handler listing the payment requests of the user.
+// @Summary List payment requests
+// @Description Returns the latest 100 payment requests the user made or was asked to pay, newest first. Pending requests past their expiry are reported as expired.
+// @Tags payment-requests
+// @Produce json
+// @Success 200 {object} handlers.PaymentRequestsResponse "Payment requests"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /payment-requests [get]
+// @Security BearerAuth
+func NewListPaymentRequestsHandler(svc PaymentRequestManager, tokenGetter PaymentRequestTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := paymentRequestClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		requests, err := svc.ListPaymentRequests(r.Context(), claims.UserID)
+		if err != nil {
+			logger.FromContext(r.Context()).Errorw("failed to list payment requests", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := PaymentRequestsResponse{PaymentRequests: make([]PaymentRequestResponse, 0, len(requests))}
+		for _, request := range requests {
+			resp.PaymentRequests = append(resp.PaymentRequests, newPaymentRequestResponse(request))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// NewAcceptPaymentRequestHandler returns an HTTP handler paying a payment request.
+// @Summary Accept a payment request
+// @Description Transfers the requested amount from the payer's wallet to the requester's. The payment counts against the payer's limits, is recorded in the transaction history of both users as transfer_out and transfer_in, and the requester is notified with a payment_request.accepted webhook event.
+// @Tags payment-requests
+// @Produce json
+// @Param requestID path string true "Payment request ID"
+// @Success 200 {object} handlers.PaymentRequestResponse "Payment request accepted"
+// @Failure 400 {object} apperrors.Response "Invalid payment request ID or insufficient funds"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Daily or monthly limit exceeded"
+// @Failure 404 {object} apperrors.Response "Payment request not found"
+// @Failure 409 {object} apperrors.Response "Payment request is not pending or another operation is in progress"
+// @Failure 410 {object} apperrors.Response "Payment request expired"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /payment-requests/{requestID}/accept [post]
+// @Security BearerAuth
+func NewAcceptPaymentRequestHandler(svc PaymentRequestManager, tokenGetter PaymentRequestTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		answerPaymentRequest(w, r, tokenGetter, svc.AcceptPaymentRequest)
+	}
+}
+
+// NewDeclinePaymentRequestHandler returns an HTTP handler declining a payment request.
+// @Summary Decline a payment request
+// @Description Declines the request without paying it. The requester is notified with a payment_request.declined webhook event.
+// @Tags payment-requests
+// @Produce json
+// @Param requestID path string true "Payment request ID"
+// @Success 200 {object} handlers.PaymentRequestResponse "Payment request declined"
+// @Failure 400 {object} apperrors.Response "Invalid payment request ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Payment request not found"
+// @Failure 409 {object} apperrors.Response "Payment request is not pending"
+// @Failure 410 {object} apperrors.Response "Payment request expired"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /payment-requests/{requestID}/decline [post]
+// @Security BearerAuth
+func NewDeclinePaymentRequestHandler(svc PaymentRequestManager, tokenGetter PaymentRequestTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		answerPaymentRequest(w, r, tokenGetter, svc.DeclinePaymentRequest)
+	}
+}
+
+// answerPaymentRequest accepts or declines the payment request in the path with answer.
+func answerPaymentRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	tokenGetter PaymentRequestTokener,
+	answer func(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error),
+) {
+	claims, ok := paymentRequestClaims(w, r, tokenGetter)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(chi.URLParam(r, "requestID"))
+	if err != nil {
+		logger.FromContext(r.Context()).Warnw("invalid payment request ID", "requestID", chi.URLParam(r, "requestID"), "error", err)
+		apperrors.Write(w, apperrors.InvalidPaymentRequestID)
+		return
+	}
+
+	request, err := answer(r.Context(), claims.UserID, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentRequestNotFound):
+			apperrors.Write(w, apperrors.PaymentRequestNotFound)
+		case errors.Is(err, services.ErrPaymentRequestNotPending):
+			apperrors.Write(w, apperrors.PaymentRequestNotPending)
+		case errors.Is(err, services.ErrPaymentRequestExpired):
+			apperrors.Write(w, apperrors.PaymentRequestExpired)
+		case errors.Is(err, services.ErrInsufficientFunds):
+			apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
+		case errors.Is(err, services.ErrDailyLimitExceeded):
+			apperrors.Write(w, apperrors.DailyLimitExceeded)
+		case errors.Is(err, services.ErrMonthlyLimitExceeded):
+			apperrors.Write(w, apperrors.MonthlyLimitExceeded)
+		default:
+			logger.FromContext(r.Context()).Errorw("failed to answer payment request", "requestID", requestID, "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(newPaymentRequestResponse(request))
+}
+
+// newPaymentRequestResponse converts a payment request to its response.
+func newPaymentRequestResponse(request models.PaymentRequestDB) PaymentRequestResponse {
+	resp := PaymentRequestResponse{
+		RequestID:   request.RequestID.String(),
+		RequesterID: request.RequesterID.String(),
+		PayerID:     request.PayerID.String(),
+		Currency:    request.Currency,
+		Amount:      request.Amount,
+		Status:      request.Status,
+		ExpiresAt:   request.ExpiresAt,
+		CreatedAt:   request.CreatedAt,
+		UpdatedAt:   request.UpdatedAt,
+	}
+	if request.Note != nil {
+		resp.Note = *request.Note
+	}
+	if request.TransactionID != nil {
+		resp.TransactionID = request.TransactionID.String()
+	}
+	return resp
+}
+
+// paymentRequestClaims authenticates the request, writing 401 on failure.
+func paymentRequestClaims(w http.ResponseWriter, r *http.Request, tokenGetter PaymentRequestTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
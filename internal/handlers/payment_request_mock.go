@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/payment_request.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockPaymentRequestTokener is a mock of PaymentRequestTokener interface.
+type MockPaymentRequestTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRequestTokenerMockRecorder
+}
+
+// MockPaymentRequestTokenerMockRecorder is the mock recorder for MockPaymentRequestTokener.
+type MockPaymentRequestTokenerMockRecorder struct {
+	mock *MockPaymentRequestTokener
+}
+
+// NewMockPaymentRequestTokener creates a new mock instance.
+func NewMockPaymentRequestTokener(ctrl *gomock.Controller) *MockPaymentRequestTokener {
+	mock := &MockPaymentRequestTokener{ctrl: ctrl}
+	mock.recorder = &MockPaymentRequestTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRequestTokener) EXPECT() *MockPaymentRequestTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockPaymentRequestTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockPaymentRequestTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockPaymentRequestTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockPaymentRequestTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockPaymentRequestTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockPaymentRequestTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockPaymentRequestManager is a mock of PaymentRequestManager interface.
+type MockPaymentRequestManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRequestManagerMockRecorder
+}
+
+// MockPaymentRequestManagerMockRecorder is the mock recorder for MockPaymentRequestManager.
+type MockPaymentRequestManagerMockRecorder struct {
+	mock *MockPaymentRequestManager
+}
+
+// NewMockPaymentRequestManager creates a new mock instance.
+func NewMockPaymentRequestManager(ctrl *gomock.Controller) *MockPaymentRequestManager {
+	mock := &MockPaymentRequestManager{ctrl: ctrl}
+	mock.recorder = &MockPaymentRequestManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRequestManager) EXPECT() *MockPaymentRequestManagerMockRecorder {
+	return m.recorder
+}
+
+// AcceptPaymentRequest mocks base method.
+func (m *MockPaymentRequestManager) AcceptPaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequest", ctx, payerID, requestID)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptPaymentRequest indicates an expected call of AcceptPaymentRequest.
+func (mr *MockPaymentRequestManagerMockRecorder) AcceptPaymentRequest(ctx, payerID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequest", reflect.TypeOf((*MockPaymentRequestManager)(nil).AcceptPaymentRequest), ctx, payerID, requestID)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockPaymentRequestManager) CreatePaymentRequest(ctx context.Context, requesterID uuid.UUID, payerUsername string, amount money.Amount, currency, note string) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", ctx, requesterID, payerUsername, amount, currency, note)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockPaymentRequestManagerMockRecorder) CreatePaymentRequest(ctx, requesterID, payerUsername, amount, currency, note interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockPaymentRequestManager)(nil).CreatePaymentRequest), ctx, requesterID, payerUsername, amount, currency, note)
+}
+
+// DeclinePaymentRequest mocks base method.
+func (m *MockPaymentRequestManager) DeclinePaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclinePaymentRequest", ctx, payerID, requestID)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclinePaymentRequest indicates an expected call of DeclinePaymentRequest.
+func (mr *MockPaymentRequestManagerMockRecorder) DeclinePaymentRequest(ctx, payerID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclinePaymentRequest", reflect.TypeOf((*MockPaymentRequestManager)(nil).DeclinePaymentRequest), ctx, payerID, requestID)
+}
+
+// ListPaymentRequests mocks base method.
+func (m *MockPaymentRequestManager) ListPaymentRequests(ctx context.Context, userID uuid.UUID) ([]models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentRequests", ctx, userID)
+	ret0, _ := ret[0].([]models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentRequests indicates an expected call of ListPaymentRequests.
+func (mr *MockPaymentRequestManagerMockRecorder) ListPaymentRequests(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentRequests", reflect.TypeOf((*MockPaymentRequestManager)(nil).ListPaymentRequests), ctx, userID)
+}
@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePaymentRequestHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPaymentRequestTokener(ctrl)
+	mockSvc := NewMockPaymentRequestManager(ctrl)
+
+	userID := uuid.New()
+	payerID := uuid.New()
+	requestID := uuid.New()
+	amount := money.MustParse("25")
+	note := "dinner"
+	expiresAt := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	handler := NewCreatePaymentRequestHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: `{"payer":"bob","amount":25,"currency":"USD","note":"dinner"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePaymentRequest(gomock.Any(), userID, "bob", amount, models.USD, note).Return(models.PaymentRequestDB{
+					RequestID: requestID, RequesterID: userID, PayerID: payerID, Currency: models.USD, Amount: amount,
+					Note: &note, Status: models.PaymentRequestStatusPending, ExpiresAt: expiresAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: PaymentRequestResponse{
+				RequestID: requestID.String(), RequesterID: userID.String(), PayerID: payerID.String(), Currency: models.USD,
+				Amount: amount, Note: note, Status: models.PaymentRequestStatusPending, ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
+		},
+		{
+			name:           "invalid_amount",
+			reqBody:        `{"payer":"bob","amount":0,"currency":"USD"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid amount or currency", Code: "invalid_amount_or_currency", Details: []any{map[string]any{"field": "amount", "rule": "gt", "param": "0", "message": "must be greater than 0"}}},
+		},
+		{
+			name:           "invalid_currency",
+			reqBody:        `{"payer":"bob","amount":25,"currency":"BTC"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid amount or currency", Code: "invalid_amount_or_currency"},
+		},
+		{
+			name:           "missing_payer",
+			reqBody:        `{"amount":25,"currency":"USD"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid payer", Code: "invalid_payer", Details: []any{map[string]any{"field": "payer", "rule": "required", "message": "is required"}}},
+		},
+		{
+			name:           "note_too_long",
+			reqBody:        `{"payer":"bob","amount":25,"currency":"USD","note":"` + strings.Repeat("a", models.MaxPaymentRequestNoteLength+1) + `"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid note", Code: "invalid_note", Details: []any{map[string]any{"field": "note", "rule": "max", "param": "128", "message": "must be at most 128 long"}}},
+		},
+		{
+			name:    "self_request",
+			reqBody: `{"payer":"alice","amount":25,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePaymentRequest(gomock.Any(), userID, "alice", amount, models.USD, "").Return(models.PaymentRequestDB{}, services.ErrInvalidPayer)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid payer", Code: "invalid_payer"},
+		},
+		{
+			name:    "unknown_payer",
+			reqBody: `{"payer":"nobody","amount":25,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePaymentRequest(gomock.Any(), userID, "nobody", amount, models.USD, "").Return(models.PaymentRequestDB{}, services.ErrUserDoesNotExist)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "User not found", Code: "user_not_found"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: `{"payer":"bob","amount":25,"currency":"USD"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePaymentRequest(gomock.Any(), userID, "bob", amount, models.USD, "").Return(models.PaymentRequestDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/payment-requests", bytes.NewBufferString(tt.reqBody))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertPaymentRequestBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestListPaymentRequestsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPaymentRequestTokener(ctrl)
+	mockSvc := NewMockPaymentRequestManager(ctrl)
+
+	userID := uuid.New()
+	payerID := uuid.New()
+	requestID := uuid.New()
+	txnID := uuid.New()
+
+	handler := NewListPaymentRequestsHandler(mockSvc, mockTokener)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc.EXPECT().ListPaymentRequests(gomock.Any(), userID).Return([]models.PaymentRequestDB{{
+			RequestID: requestID, RequesterID: userID, PayerID: payerID, Currency: models.EUR, Amount: money.MustParse("10"),
+			Status: models.PaymentRequestStatusAccepted, TransactionID: &txnID,
+		}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/payment-requests", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var got PaymentRequestsResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		if assert.Len(t, got.PaymentRequests, 1) {
+			assert.Equal(t, requestID.String(), got.PaymentRequests[0].RequestID)
+			assert.Equal(t, txnID.String(), got.PaymentRequests[0].TransactionID)
+			assert.Equal(t, models.PaymentRequestStatusAccepted, got.PaymentRequests[0].Status)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mockSvc.EXPECT().ListPaymentRequests(gomock.Any(), userID).Return([]models.PaymentRequestDB{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/payment-requests", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"payment_requests":[]}`, rec.Body.String())
+	})
+
+	t.Run("internal_error", func(t *testing.T) {
+		mockSvc.EXPECT().ListPaymentRequests(gomock.Any(), userID).Return(nil, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/payment-requests", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assertPaymentRequestBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Internal server error", Code: "internal"})
+	})
+}
+
+func TestAnswerPaymentRequestHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPaymentRequestTokener(ctrl)
+	mockSvc := NewMockPaymentRequestManager(ctrl)
+
+	userID := uuid.New()
+	requesterID := uuid.New()
+	requestID := uuid.New()
+	txnID := uuid.New()
+	amount := money.MustParse("25")
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		requestID      string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:      "accept",
+			handler:   NewAcceptPaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().AcceptPaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{
+					RequestID: requestID, RequesterID: requesterID, PayerID: userID, Currency: models.USD, Amount: amount,
+					Status: models.PaymentRequestStatusAccepted, TransactionID: &txnID,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: PaymentRequestResponse{
+				RequestID: requestID.String(), RequesterID: requesterID.String(), PayerID: userID.String(), Currency: models.USD,
+				Amount: amount, Status: models.PaymentRequestStatusAccepted, TransactionID: txnID.String(),
+			},
+		},
+		{
+			name:      "decline",
+			handler:   NewDeclinePaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().DeclinePaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{
+					RequestID: requestID, RequesterID: requesterID, PayerID: userID, Currency: models.USD, Amount: amount,
+					Status: models.PaymentRequestStatusDeclined,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: PaymentRequestResponse{
+				RequestID: requestID.String(), RequesterID: requesterID.String(), PayerID: userID.String(), Currency: models.USD,
+				Amount: amount, Status: models.PaymentRequestStatusDeclined,
+			},
+		},
+		{
+			name:           "invalid_request_id",
+			handler:        NewAcceptPaymentRequestHandler(mockSvc, mockTokener),
+			requestID:      "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid payment request ID", Code: "invalid_payment_request_id"},
+		},
+		{
+			name:      "not_found",
+			handler:   NewAcceptPaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().AcceptPaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{}, services.ErrPaymentRequestNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Payment request not found", Code: "payment_request_not_found"},
+		},
+		{
+			name:      "not_pending",
+			handler:   NewDeclinePaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().DeclinePaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{}, services.ErrPaymentRequestNotPending)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Payment request is not pending", Code: "payment_request_not_pending"},
+		},
+		{
+			name:      "expired",
+			handler:   NewAcceptPaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().AcceptPaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{}, services.ErrPaymentRequestExpired)
+			},
+			expectedStatus: http.StatusGone,
+			expectedBody:   apperrors.Response{Error: "Payment request expired", Code: "payment_request_expired"},
+		},
+		{
+			name:      "insufficient_funds",
+			handler:   NewAcceptPaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().AcceptPaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{}, services.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
+		},
+		{
+			name:      "monthly_limit_exceeded",
+			handler:   NewAcceptPaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().AcceptPaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{}, services.ErrMonthlyLimitExceeded)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Monthly limit exceeded", Code: "monthly_limit_exceeded"},
+		},
+		{
+			name:      "internal_error",
+			handler:   NewDeclinePaymentRequestHandler(mockSvc, mockTokener),
+			requestID: requestID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().DeclinePaymentRequest(gomock.Any(), userID, requestID).Return(models.PaymentRequestDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/payment-requests/"+tt.requestID+"/accept", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("requestID", tt.requestID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertPaymentRequestBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestPaymentRequestHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPaymentRequestTokener(ctrl)
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("", errors.New("no token"))
+
+	handlers := map[string]http.HandlerFunc{
+		"create":  NewCreatePaymentRequestHandler(NewMockPaymentRequestManager(ctrl), mockTokener, newMockCurrencies(ctrl)),
+		"list":    NewListPaymentRequestsHandler(NewMockPaymentRequestManager(ctrl), mockTokener),
+		"accept":  NewAcceptPaymentRequestHandler(NewMockPaymentRequestManager(ctrl), mockTokener),
+		"decline": NewDeclinePaymentRequestHandler(NewMockPaymentRequestManager(ctrl), mockTokener),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assertPaymentRequestBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Unauthorized", Code: "unauthorized"})
+		})
+	}
+}
+
+func assertPaymentRequestBody(t *testing.T, body []byte, expectedBody interface{}) {
+	t.Helper()
+	switch expected := expectedBody.(type) {
+	case PaymentRequestResponse:
+		var got PaymentRequestResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		got.CreatedAt, got.UpdatedAt = expected.CreatedAt, expected.UpdatedAt
+		assert.Equal(t, expected, got)
+	case apperrors.Response:
+		var got apperrors.Response
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	}
+}
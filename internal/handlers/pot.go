@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// PotTokener defines only the methods needed by the pot handlers.
+type PotTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// PotManager defines the interface for managing savings pots.
+type PotManager interface {
+	CreatePot(ctx context.Context, userID uuid.UUID, currency, name string, spendable bool) (models.WalletPotDB, error)
+	ListPots(ctx context.Context, userID uuid.UUID) ([]models.WalletPotDB, error)
+	SetPotSpendable(ctx context.Context, userID, potID uuid.UUID, spendable bool) (models.WalletPotDB, error)
+	DeletePot(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error)
+	MovePotMoney(ctx context.Context, userID uuid.UUID, currency string, fromPotID, toPotID *uuid.UUID, amount money.Amount) ([]models.WalletPotDB, error)
+}
+
+// CreatePotRequest represents the JSON body for creating a pot
+// swagger:model CreatePotRequest
+type CreatePotRequest struct {
+	// Currency of the wallet the pot belongs to
+	// required: true
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Name of the pot, unique within the wallet, at most 64 characters
+	// required: true
+	// default: Holiday
+	Name string `json:"name"`
+
+	// Whether withdrawals and exchanges may spend the pot
+	// default: false
+	Spendable bool `json:"spendable"`
+}
+
+// UpdatePotRequest represents the JSON body for updating a pot
+// swagger:model UpdatePotRequest
+type UpdatePotRequest struct {
+	// Whether withdrawals and exchanges may spend the pot
+	// required: true
+	// default: true
+	Spendable *bool `json:"spendable"`
+}
+
+// MovePotMoneyRequest represents the JSON body for moving money between pots
+// swagger:model MovePotMoneyRequest
+type MovePotMoneyRequest struct {
+	// Currency of the wallet
+	// required: true
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Pot to take the money from, empty for the unallocated balance
+	FromPotID string `json:"from_pot_id,omitempty"`
+
+	// Pot to put the money in, empty for the unallocated balance
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	ToPotID string `json:"to_pot_id,omitempty"`
+
+	// Amount to move
+	// required: true
+	// default: 25.0
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+}
+
+// PotResponse represents a savings pot
+// swagger:model PotResponse
+type PotResponse struct {
+	// Pot ID
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	PotID string `json:"pot_id"`
+
+	// Currency of the wallet the pot belongs to
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Name of the pot
+	// default: Holiday
+	Name string `json:"name"`
+
+	// Amount set aside in the pot
+	// default: 25.0
+	Balance money.Amount `json:"balance" swaggertype:"number"`
+
+	// Whether withdrawals and exchanges may spend the pot
+	// default: false
+	Spendable bool `json:"spendable"`
+
+	// Time the pot was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// Time of the last change
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PotsResponse represents the pots of the user
+// swagger:model PotsResponse
+type PotsResponse struct {
+	// Pots ordered by currency and creation time
+	Pots []PotResponse `json:"pots"`
+}
+
+// NewCreatePotHandler returns an HTTP handler adding a pot to a wallet of the user.
+// @Summary Create a savings pot
+// @Description Adds an empty named pot to the user's wallet in the currency. Money moved into a pot stays part of the wallet balance; pots that are not spendable are excluded from the balance withdrawals, exchanges, holds and payments may spend.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreatePotRequest true "Create Pot Request"
+// @Success 201 {object} handlers.PotResponse "Pot created"
+// @Failure 400 {object} apperrors.Response "Invalid request body, currency or pot name"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Wallet not found"
+// @Failure 409 {object} apperrors.Response "Pot name already taken"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/pots [post]
+// @Security BearerAuth
+func NewCreatePotHandler(svc PotManager, tokenGetter PotTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := potClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req CreatePotRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Errorw("failed to decode create pot request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		if !currencies.IsSupported(ctx, req.Currency) {
+			apperrors.Write(w, apperrors.InvalidCurrency)
+			return
+		}
+
+		pot, err := svc.CreatePot(ctx, claims.UserID, req.Currency, req.Name, req.Spendable)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidPotName):
+				apperrors.Write(w, apperrors.InvalidPotName)
+			case errors.Is(err, services.ErrWalletNotFound):
+				apperrors.Write(w, apperrors.WalletNotFound)
+			case errors.Is(err, services.ErrPotNameTaken):
+				apperrors.Write(w, apperrors.PotNameTaken)
+			default:
+				logger.FromContext(ctx).Errorw("failed to create pot", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(newPotResponse(pot))
+	}
+}
+
+// NewListPotsHandler returns an HTTP handler listing the pots of the user.
+// @Summary List savings pots
+// @Description Returns the pots of all wallets of the user, ordered by currency and creation time.
+// @Tags wallet
+// @Produce json
+// @Success 200 {object} handlers.PotsResponse "Pots"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/pots [get]
+// @Security BearerAuth
+func NewListPotsHandler(svc PotManager, tokenGetter PotTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := potClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		pots, err := svc.ListPots(r.Context(), claims.UserID)
+		if err != nil {
+			logger.FromContext(r.Context()).Errorw("failed to list pots", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		writePots(w, pots)
+	}
+}
+
+// NewUpdatePotHandler returns an HTTP handler including a pot in or excluding it from the spendable balance.
+// @Summary Update a savings pot
+// @Description Includes the pot in or excludes it from the balance withdrawals, exchanges, holds and payments may spend. When spending reaches into spendable pots, they are drawn down, newest first.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param potID path string true "Pot ID"
+// @Param request body handlers.UpdatePotRequest true "Update Pot Request"
+// @Success 200 {object} handlers.PotResponse "Pot updated"
+// @Failure 400 {object} apperrors.Response "Invalid pot ID or request body"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Pot not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/pots/{potID} [patch]
+// @Security BearerAuth
+func NewUpdatePotHandler(svc PotManager, tokenGetter PotTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := potClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		potID, ok := potIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		var req UpdatePotRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Spendable == nil {
+			logger.FromContext(r.Context()).Errorw("failed to decode update pot request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		pot, err := svc.SetPotSpendable(r.Context(), claims.UserID, potID, *req.Spendable)
+		if err != nil {
+			writePotError(w, r, claims.UserID, potID, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(newPotResponse(pot))
+	}
+}
+
+// NewDeletePotHandler returns an HTTP handler removing a pot of the user.
+// @Summary Delete a savings pot
+// @Description Removes the pot; its balance returns to the unallocated balance of the wallet.
+// @Tags wallet
+// @Produce json
+// @Param potID path string true "Pot ID"
+// @Success 200 {object} handlers.PotResponse "Pot deleted"
+// @Failure 400 {object} apperrors.Response "Invalid pot ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Pot not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/pots/{potID} [delete]
+// @Security BearerAuth
+func NewDeletePotHandler(svc PotManager, tokenGetter PotTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := potClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		potID, ok := potIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		pot, err := svc.DeletePot(r.Context(), claims.UserID, potID)
+		if err != nil {
+			writePotError(w, r, claims.UserID, potID, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(newPotResponse(pot))
+	}
+}
+
+// NewMovePotMoneyHandler returns an HTTP handler moving money between pots of a wallet.
+// @Summary Move money between pots
+// @Description Moves the amount between two pots of the user's wallet in the currency. An empty pot ID stands for the unallocated balance: the wallet balance not held and not in any pot. The wallet balance does not change.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param request body handlers.MovePotMoneyRequest true "Move Pot Money Request"
+// @Success 200 {object} handlers.PotsResponse "Pots after the move"
+// @Failure 400 {object} apperrors.Response "Invalid request body, pot ID, pot move, or insufficient funds"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Pot not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/pots/move [post]
+// @Security BearerAuth
+func NewMovePotMoneyHandler(svc PotManager, tokenGetter PotTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := potClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req MovePotMoneyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Errorw("failed to decode move pot money request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
+			return
+		}
+
+		if !currencies.IsSupported(ctx, req.Currency) || !currencies.ValidAmount(ctx, req.Currency, req.Amount) {
+			apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
+			return
+		}
+
+		fromPotID, err := parseOptionalPotID(req.FromPotID)
+		if err != nil {
+			apperrors.Write(w, apperrors.InvalidPotID)
+			return
+		}
+		toPotID, err := parseOptionalPotID(req.ToPotID)
+		if err != nil {
+			apperrors.Write(w, apperrors.InvalidPotID)
+			return
+		}
+
+		pots, err := svc.MovePotMoney(ctx, claims.UserID, req.Currency, fromPotID, toPotID, req.Amount)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidPotMove):
+				apperrors.Write(w, apperrors.InvalidPotMove)
+			case errors.Is(err, services.ErrInsufficientFunds):
+				apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
+			case errors.Is(err, services.ErrPotNotFound):
+				apperrors.Write(w, apperrors.PotNotFound)
+			default:
+				logger.FromContext(ctx).Errorw("failed to move pot money", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		writePots(w, pots)
+	}
+}
+
+// parseOptionalPotID parses a pot ID from a request body, nil for an empty one.
+func parseOptionalPotID(raw string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	potID, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &potID, nil
+}
+
+// potIDParam parses the pot ID in the path, writing 400 on failure.
+func potIDParam(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	potID, err := uuid.Parse(chi.URLParam(r, "potID"))
+	if err != nil {
+		logger.FromContext(r.Context()).Warnw("invalid pot ID", "potID", chi.URLParam(r, "potID"), "error", err)
+		apperrors.Write(w, apperrors.InvalidPotID)
+		return uuid.Nil, false
+	}
+	return potID, true
+}
+
+// writePotError responds to a failed update or deletion of a pot.
+func writePotError(w http.ResponseWriter, r *http.Request, userID, potID uuid.UUID, err error) {
+	if errors.Is(err, services.ErrPotNotFound) {
+		apperrors.Write(w, apperrors.PotNotFound)
+		return
+	}
+	logger.FromContext(r.Context()).Errorw("failed to change pot", "potID", potID, "userID", userID, "error", err)
+	apperrors.Write(w, apperrors.Internal)
+}
+
+// writePots responds with the pots.
+func writePots(w http.ResponseWriter, pots []models.WalletPotDB) {
+	resp := PotsResponse{Pots: make([]PotResponse, 0, len(pots))}
+	for _, pot := range pots {
+		resp.Pots = append(resp.Pots, newPotResponse(pot))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newPotResponse(pot models.WalletPotDB) PotResponse {
+	return PotResponse{
+		PotID:     pot.PotID.String(),
+		Currency:  pot.Currency,
+		Name:      pot.Name,
+		Balance:   pot.Balance,
+		Spendable: pot.Spendable,
+		CreatedAt: pot.CreatedAt,
+		UpdatedAt: pot.UpdatedAt,
+	}
+}
+
+// potClaims authenticates the request, writing 401 on failure.
+func potClaims(w http.ResponseWriter, r *http.Request, tokenGetter PotTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
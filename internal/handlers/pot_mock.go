@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/pot.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockPotTokener is a mock of PotTokener interface.
+type MockPotTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockPotTokenerMockRecorder
+}
+
+// MockPotTokenerMockRecorder is the mock recorder for MockPotTokener.
+type MockPotTokenerMockRecorder struct {
+	mock *MockPotTokener
+}
+
+// NewMockPotTokener creates a new mock instance.
+func NewMockPotTokener(ctrl *gomock.Controller) *MockPotTokener {
+	mock := &MockPotTokener{ctrl: ctrl}
+	mock.recorder = &MockPotTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPotTokener) EXPECT() *MockPotTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockPotTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockPotTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockPotTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockPotTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockPotTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockPotTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockPotManager is a mock of PotManager interface.
+type MockPotManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockPotManagerMockRecorder
+}
+
+// MockPotManagerMockRecorder is the mock recorder for MockPotManager.
+type MockPotManagerMockRecorder struct {
+	mock *MockPotManager
+}
+
+// NewMockPotManager creates a new mock instance.
+func NewMockPotManager(ctrl *gomock.Controller) *MockPotManager {
+	mock := &MockPotManager{ctrl: ctrl}
+	mock.recorder = &MockPotManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPotManager) EXPECT() *MockPotManagerMockRecorder {
+	return m.recorder
+}
+
+// CreatePot mocks base method.
+func (m *MockPotManager) CreatePot(ctx context.Context, userID uuid.UUID, currency, name string, spendable bool) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePot", ctx, userID, currency, name, spendable)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePot indicates an expected call of CreatePot.
+func (mr *MockPotManagerMockRecorder) CreatePot(ctx, userID, currency, name, spendable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePot", reflect.TypeOf((*MockPotManager)(nil).CreatePot), ctx, userID, currency, name, spendable)
+}
+
+// DeletePot mocks base method.
+func (m *MockPotManager) DeletePot(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePot", ctx, userID, potID)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePot indicates an expected call of DeletePot.
+func (mr *MockPotManagerMockRecorder) DeletePot(ctx, userID, potID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePot", reflect.TypeOf((*MockPotManager)(nil).DeletePot), ctx, userID, potID)
+}
+
+// ListPots mocks base method.
+func (m *MockPotManager) ListPots(ctx context.Context, userID uuid.UUID) ([]models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPots", ctx, userID)
+	ret0, _ := ret[0].([]models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPots indicates an expected call of ListPots.
+func (mr *MockPotManagerMockRecorder) ListPots(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPots", reflect.TypeOf((*MockPotManager)(nil).ListPots), ctx, userID)
+}
+
+// MovePotMoney mocks base method.
+func (m *MockPotManager) MovePotMoney(ctx context.Context, userID uuid.UUID, currency string, fromPotID, toPotID *uuid.UUID, amount money.Amount) ([]models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MovePotMoney", ctx, userID, currency, fromPotID, toPotID, amount)
+	ret0, _ := ret[0].([]models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MovePotMoney indicates an expected call of MovePotMoney.
+func (mr *MockPotManagerMockRecorder) MovePotMoney(ctx, userID, currency, fromPotID, toPotID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovePotMoney", reflect.TypeOf((*MockPotManager)(nil).MovePotMoney), ctx, userID, currency, fromPotID, toPotID, amount)
+}
+
+// SetPotSpendable mocks base method.
+func (m *MockPotManager) SetPotSpendable(ctx context.Context, userID, potID uuid.UUID, spendable bool) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPotSpendable", ctx, userID, potID, spendable)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPotSpendable indicates an expected call of SetPotSpendable.
+func (mr *MockPotManagerMockRecorder) SetPotSpendable(ctx, userID, potID, spendable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPotSpendable", reflect.TypeOf((*MockPotManager)(nil).SetPotSpendable), ctx, userID, potID, spendable)
+}
@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePotHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPotTokener(ctrl)
+	mockSvc := NewMockPotManager(ctrl)
+
+	userID := uuid.New()
+	potID := uuid.New()
+
+	handler := NewCreatePotHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: `{"currency":"USD","name":"Holiday"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePot(gomock.Any(), userID, models.USD, "Holiday", false).Return(models.WalletPotDB{
+					PotID: potID, UserID: userID, Currency: models.USD, Name: "Holiday",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   PotResponse{PotID: potID.String(), Currency: models.USD, Name: "Holiday"},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
+		},
+		{
+			name:           "invalid_currency",
+			reqBody:        `{"currency":"BTC","name":"Holiday"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:    "invalid_name",
+			reqBody: `{"currency":"USD","name":""}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePot(gomock.Any(), userID, models.USD, "", false).Return(models.WalletPotDB{}, services.ErrInvalidPotName)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid pot name", Code: "invalid_pot_name"},
+		},
+		{
+			name:    "wallet_not_found",
+			reqBody: `{"currency":"EUR","name":"Holiday","spendable":true}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePot(gomock.Any(), userID, models.EUR, "Holiday", true).Return(models.WalletPotDB{}, services.ErrWalletNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Wallet not found", Code: "wallet_not_found"},
+		},
+		{
+			name:    "name_taken",
+			reqBody: `{"currency":"USD","name":"Holiday"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePot(gomock.Any(), userID, models.USD, "Holiday", false).Return(models.WalletPotDB{}, services.ErrPotNameTaken)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Pot name already taken", Code: "pot_name_taken"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: `{"currency":"USD","name":"Holiday"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().CreatePot(gomock.Any(), userID, models.USD, "Holiday", false).Return(models.WalletPotDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/pots", bytes.NewBufferString(tt.reqBody))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertPotBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestListPotsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPotTokener(ctrl)
+	mockSvc := NewMockPotManager(ctrl)
+
+	userID := uuid.New()
+	potID := uuid.New()
+
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).AnyTimes().Return("valid-token", nil)
+	mockTokener.EXPECT().GetClaims(gomock.Any(), "valid-token").AnyTimes().Return(&jwt.Claims{UserID: userID}, nil)
+
+	handler := NewListPotsHandler(mockSvc, mockTokener)
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc.EXPECT().ListPots(gomock.Any(), userID).Return([]models.WalletPotDB{
+			{PotID: potID, Currency: models.USD, Name: "Holiday", Balance: money.MustParse("40")},
+		}, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallet/pots", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assertPotBody(t, rec.Body.Bytes(), PotsResponse{Pots: []PotResponse{
+			{PotID: potID.String(), Currency: models.USD, Name: "Holiday", Balance: money.MustParse("40")},
+		}})
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mockSvc.EXPECT().ListPots(gomock.Any(), userID).Return([]models.WalletPotDB{}, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallet/pots", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"pots":[]}`, rec.Body.String())
+	})
+
+	t.Run("internal_error", func(t *testing.T) {
+		mockSvc.EXPECT().ListPots(gomock.Any(), userID).Return(nil, errors.New("db error"))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallet/pots", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assertPotBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Internal server error", Code: "internal"})
+	})
+}
+
+func TestChangePotHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPotTokener(ctrl)
+	mockSvc := NewMockPotManager(ctrl)
+
+	userID := uuid.New()
+	potID := uuid.New()
+
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).AnyTimes().Return("valid-token", nil)
+	mockTokener.EXPECT().GetClaims(gomock.Any(), "valid-token").AnyTimes().Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		potID          string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "update",
+			handler: NewUpdatePotHandler(mockSvc, mockTokener),
+			potID:   potID.String(),
+			reqBody: `{"spendable":true}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetPotSpendable(gomock.Any(), userID, potID, true).Return(models.WalletPotDB{
+					PotID: potID, Currency: models.USD, Name: "Holiday", Spendable: true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   PotResponse{PotID: potID.String(), Currency: models.USD, Name: "Holiday", Spendable: true},
+		},
+		{
+			name:           "update_missing_spendable",
+			handler:        NewUpdatePotHandler(mockSvc, mockTokener),
+			potID:          potID.String(),
+			reqBody:        `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
+		},
+		{
+			name:    "update_not_found",
+			handler: NewUpdatePotHandler(mockSvc, mockTokener),
+			potID:   potID.String(),
+			reqBody: `{"spendable":false}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetPotSpendable(gomock.Any(), userID, potID, false).Return(models.WalletPotDB{}, services.ErrPotNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Pot not found", Code: "pot_not_found"},
+		},
+		{
+			name:    "delete",
+			handler: NewDeletePotHandler(mockSvc, mockTokener),
+			potID:   potID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().DeletePot(gomock.Any(), userID, potID).Return(models.WalletPotDB{
+					PotID: potID, Currency: models.USD, Name: "Holiday", Balance: money.MustParse("40"),
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   PotResponse{PotID: potID.String(), Currency: models.USD, Name: "Holiday", Balance: money.MustParse("40")},
+		},
+		{
+			name:           "delete_invalid_pot_id",
+			handler:        NewDeletePotHandler(mockSvc, mockTokener),
+			potID:          "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid pot ID", Code: "invalid_pot_id"},
+		},
+		{
+			name:    "delete_internal_error",
+			handler: NewDeletePotHandler(mockSvc, mockTokener),
+			potID:   potID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().DeletePot(gomock.Any(), userID, potID).Return(models.WalletPotDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/wallet/pots/"+tt.potID, bytes.NewBufferString(tt.reqBody))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("potID", tt.potID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertPotBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestMovePotMoneyHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockPotTokener(ctrl)
+	mockSvc := NewMockPotManager(ctrl)
+
+	userID := uuid.New()
+	potID := uuid.New()
+	amount := money.MustParse("25")
+
+	handler := NewMovePotMoneyHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).AnyTimes().Return("valid-token", nil)
+	mockTokener.EXPECT().GetClaims(gomock.Any(), "valid-token").AnyTimes().Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			reqBody: `{"currency":"USD","to_pot_id":"` + potID.String() + `","amount":25}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().MovePotMoney(gomock.Any(), userID, models.USD, nil, &potID, amount).Return([]models.WalletPotDB{
+					{PotID: potID, Currency: models.USD, Name: "Holiday", Balance: amount},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: PotsResponse{Pots: []PotResponse{
+				{PotID: potID.String(), Currency: models.USD, Name: "Holiday", Balance: amount},
+			}},
+		},
+		{
+			name:           "invalid_json",
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
+		},
+		{
+			name:           "invalid_amount",
+			reqBody:        `{"currency":"USD","to_pot_id":"` + potID.String() + `","amount":0}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
+		},
+		{
+			name:           "invalid_pot_id",
+			reqBody:        `{"currency":"USD","from_pot_id":"not-a-uuid","amount":25}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid pot ID", Code: "invalid_pot_id"},
+		},
+		{
+			name:    "invalid_move",
+			reqBody: `{"currency":"USD","amount":25}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().MovePotMoney(gomock.Any(), userID, models.USD, nil, nil, amount).Return(nil, services.ErrInvalidPotMove)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid pot move", Code: "invalid_pot_move"},
+		},
+		{
+			name:    "insufficient_funds",
+			reqBody: `{"currency":"USD","to_pot_id":"` + potID.String() + `","amount":25}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().MovePotMoney(gomock.Any(), userID, models.USD, nil, &potID, amount).Return(nil, services.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
+		},
+		{
+			name:    "pot_not_found",
+			reqBody: `{"currency":"USD","from_pot_id":"` + potID.String() + `","amount":25}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().MovePotMoney(gomock.Any(), userID, models.USD, &potID, nil, amount).Return(nil, services.ErrPotNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Pot not found", Code: "pot_not_found"},
+		},
+		{
+			name:    "internal_error",
+			reqBody: `{"currency":"USD","to_pot_id":"` + potID.String() + `","amount":25}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().MovePotMoney(gomock.Any(), userID, models.USD, nil, &potID, amount).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/pots/move", bytes.NewBufferString(tt.reqBody))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertPotBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func assertPotBody(t *testing.T, body []byte, expectedBody interface{}) {
+	t.Helper()
+	switch expected := expectedBody.(type) {
+	case PotResponse:
+		var got PotResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	case PotsResponse:
+		var got PotsResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	case apperrors.Response:
+		var got apperrors.Response
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	}
+}
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// RateAlertTokener defines only the methods needed by the rate alert handlers.
+type RateAlertTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// RateAlertManager defines the interface for managing the rate alerts of a user.
+type RateAlertManager interface {
+	Create(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency, direction string, threshold float32) (models.RateAlertDB, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.RateAlertDB, error)
+	Update(ctx context.Context, userID, alertID uuid.UUID, direction string, threshold float32) (models.RateAlertDB, error)
+	Delete(ctx context.Context, userID, alertID uuid.UUID) error
+}
+
+// CreateRateAlertRequest represents the JSON body for registering a rate alert
+// swagger:model CreateRateAlertRequest
+type CreateRateAlertRequest struct {
+	// Currency pair
+	// required: true
+	// default: USD-EUR
+	Pair string `json:"pair"`
+
+	// Fire when the rate rises above or falls below the threshold
+	// required: true
+	// default: above
+	Direction string `json:"direction"`
+
+	// Rate the alert fires at
+	// required: true
+	// default: 0.95
+	Threshold float32 `json:"threshold"`
+}
+
+// UpdateRateAlertRequest represents the JSON body for changing a rate alert
+// swagger:model UpdateRateAlertRequest
+type UpdateRateAlertRequest struct {
+	// Fire when the rate rises above or falls below the threshold
+	// required: true
+	// default: below
+	Direction string `json:"direction"`
+
+	// Rate the alert fires at
+	// required: true
+	// default: 0.9
+	Threshold float32 `json:"threshold"`
+}
+
+// RateAlertResponse represents a rate alert
+// swagger:model RateAlertResponse
+type RateAlertResponse struct {
+	// Alert ID
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	AlertID string `json:"alert_id"`
+
+	// Currency pair
+	// default: USD-EUR
+	Pair string `json:"pair"`
+
+	// above or below
+	// default: above
+	Direction string `json:"direction"`
+
+	// Rate the alert fires at
+	// default: 0.95
+	Threshold float32 `json:"threshold"`
+
+	// When the alert fired, absent while it is armed
+	TriggeredAt *time.Time `json:"triggered_at,omitempty"`
+
+	// Rate the alert fired at
+	// default: 0.9512
+	TriggeredRate *float32 `json:"triggered_rate,omitempty"`
+
+	// Registration time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RateAlertsResponse represents the list of the user's rate alerts
+// swagger:model RateAlertsResponse
+type RateAlertsResponse struct {
+	// Alerts, oldest first
+	Alerts []RateAlertResponse `json:"alerts"`
+}
+
+// NewCreateRateAlertHandler returns an HTTP handler registering a rate alert of the user.
+// @Summary Register a rate alert
+// @Description Registers an alert firing once the rate of the pair rises above or falls below the threshold. The rates are checked every minute; a fired alert is sent over the notification channels the user enabled and stays fired until it is updated. At most 20 alerts per user.
+// @Tags exchange
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateRateAlertRequest true "Rate Alert Request"
+// @Success 201 {object} handlers.RateAlertResponse "Rate alert registered"
+// @Failure 400 {object} apperrors.Response "Invalid request, currency pair or alert"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 409 {object} apperrors.Response "Too many rate alerts"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exchange/alerts [post]
+// @Security BearerAuth
+func NewCreateRateAlertHandler(svc RateAlertManager, tokenGetter RateAlertTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		claims, ok := rateAlertClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req CreateRateAlertRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Warnw("invalid rate alert request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		fromCurrency, toCurrency, ok := strings.Cut(req.Pair, "-")
+		if !ok || fromCurrency == toCurrency ||
+			!currencies.IsSupported(ctx, fromCurrency) || !currencies.IsSupported(ctx, toCurrency) {
+			apperrors.Write(w, apperrors.InvalidCurrencyPair)
+			return
+		}
+
+		alert, err := svc.Create(ctx, claims.UserID, fromCurrency, toCurrency, req.Direction, req.Threshold)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidRateAlert):
+				apperrors.Write(w, apperrors.InvalidRateAlert)
+			case errors.Is(err, services.ErrTooManyRateAlerts):
+				apperrors.Write(w, apperrors.TooManyRateAlerts)
+			default:
+				logger.FromContext(ctx).Errorw("failed to create rate alert", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(toRateAlertResponse(alert))
+	}
+}
+
+// NewListRateAlertsHandler returns an HTTP handler listing the rate alerts of the user.
+// @Summary List rate alerts
+// @Description Returns the user's rate alerts, armed and fired, oldest first.
+// @Tags exchange
+// @Produce json
+// @Success 200 {object} handlers.RateAlertsResponse "Rate alerts"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exchange/alerts [get]
+// @Security BearerAuth
+func NewListRateAlertsHandler(svc RateAlertManager, tokenGetter RateAlertTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := rateAlertClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		alerts, err := svc.List(r.Context(), claims.UserID)
+		if err != nil {
+			logger.FromContext(r.Context()).Errorw("failed to list rate alerts", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := RateAlertsResponse{Alerts: make([]RateAlertResponse, 0, len(alerts))}
+		for _, alert := range alerts {
+			resp.Alerts = append(resp.Alerts, toRateAlertResponse(alert))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// NewUpdateRateAlertHandler returns an HTTP handler changing a rate alert of the user.
+// @Summary Update a rate alert
+// @Description Changes the direction and threshold of the alert and arms it again, so a fired alert can fire once more.
+// @Tags exchange
+// @Accept json
+// @Produce json
+// @Param alertID path string true "Rate alert ID"
+// @Param request body handlers.UpdateRateAlertRequest true "Rate Alert Update"
+// @Success 200 {object} handlers.RateAlertResponse "Rate alert updated"
+// @Failure 400 {object} apperrors.Response "Invalid request, alert ID or alert"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Rate alert not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exchange/alerts/{alertID} [put]
+// @Security BearerAuth
+func NewUpdateRateAlertHandler(svc RateAlertManager, tokenGetter RateAlertTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := rateAlertClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		alertID, ok := rateAlertID(w, r)
+		if !ok {
+			return
+		}
+
+		var req UpdateRateAlertRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(r.Context()).Warnw("invalid rate alert request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		alert, err := svc.Update(r.Context(), claims.UserID, alertID, req.Direction, req.Threshold)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidRateAlert):
+				apperrors.Write(w, apperrors.InvalidRateAlert)
+			case errors.Is(err, services.ErrRateAlertNotFound):
+				apperrors.Write(w, apperrors.RateAlertNotFound)
+			default:
+				logger.FromContext(r.Context()).Errorw("failed to update rate alert", "alertID", alertID, "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(toRateAlertResponse(alert))
+	}
+}
+
+// NewDeleteRateAlertHandler returns an HTTP handler removing a rate alert of the user.
+// @Summary Delete a rate alert
+// @Description Removes the rate alert.
+// @Tags exchange
+// @Produce json
+// @Param alertID path string true "Rate alert ID"
+// @Success 204 "Rate alert deleted"
+// @Failure 400 {object} apperrors.Response "Invalid rate alert ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Rate alert not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /exchange/alerts/{alertID} [delete]
+// @Security BearerAuth
+func NewDeleteRateAlertHandler(svc RateAlertManager, tokenGetter RateAlertTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := rateAlertClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		alertID, ok := rateAlertID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := svc.Delete(r.Context(), claims.UserID, alertID); err != nil {
+			if errors.Is(err, services.ErrRateAlertNotFound) {
+				apperrors.Write(w, apperrors.RateAlertNotFound)
+				return
+			}
+			logger.FromContext(r.Context()).Errorw("failed to delete rate alert", "alertID", alertID, "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// toRateAlertResponse converts a rate alert.
+func toRateAlertResponse(alert models.RateAlertDB) RateAlertResponse {
+	return RateAlertResponse{
+		AlertID:       alert.AlertID.String(),
+		Pair:          alert.FromCurrency + "-" + alert.ToCurrency,
+		Direction:     alert.Direction,
+		Threshold:     alert.Threshold,
+		TriggeredAt:   alert.TriggeredAt,
+		TriggeredRate: alert.TriggeredRate,
+		CreatedAt:     alert.CreatedAt,
+	}
+}
+
+// rateAlertID parses the alert ID path parameter, writing 400 on failure.
+func rateAlertID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	alertID, err := uuid.Parse(chi.URLParam(r, "alertID"))
+	if err != nil {
+		logger.FromContext(r.Context()).Warnw("invalid rate alert ID", "alertID", chi.URLParam(r, "alertID"), "error", err)
+		apperrors.Write(w, apperrors.InvalidRateAlertID)
+		return uuid.Nil, false
+	}
+	return alertID, true
+}
+
+// rateAlertClaims authenticates the request, writing 401 on failure.
+func rateAlertClaims(w http.ResponseWriter, r *http.Request, tokenGetter RateAlertTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
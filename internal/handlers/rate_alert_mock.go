@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/rate_alert.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockRateAlertTokener is a mock of RateAlertTokener interface.
+type MockRateAlertTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateAlertTokenerMockRecorder
+}
+
+// MockRateAlertTokenerMockRecorder is the mock recorder for MockRateAlertTokener.
+type MockRateAlertTokenerMockRecorder struct {
+	mock *MockRateAlertTokener
+}
+
+// NewMockRateAlertTokener creates a new mock instance.
+func NewMockRateAlertTokener(ctrl *gomock.Controller) *MockRateAlertTokener {
+	mock := &MockRateAlertTokener{ctrl: ctrl}
+	mock.recorder = &MockRateAlertTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateAlertTokener) EXPECT() *MockRateAlertTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockRateAlertTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockRateAlertTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockRateAlertTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockRateAlertTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockRateAlertTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockRateAlertTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockRateAlertManager is a mock of RateAlertManager interface.
+type MockRateAlertManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateAlertManagerMockRecorder
+}
+
+// MockRateAlertManagerMockRecorder is the mock recorder for MockRateAlertManager.
+type MockRateAlertManagerMockRecorder struct {
+	mock *MockRateAlertManager
+}
+
+// NewMockRateAlertManager creates a new mock instance.
+func NewMockRateAlertManager(ctrl *gomock.Controller) *MockRateAlertManager {
+	mock := &MockRateAlertManager{ctrl: ctrl}
+	mock.recorder = &MockRateAlertManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateAlertManager) EXPECT() *MockRateAlertManagerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRateAlertManager) Create(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency, direction string, threshold float32) (models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, fromCurrency, toCurrency, direction, threshold)
+	ret0, _ := ret[0].(models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRateAlertManagerMockRecorder) Create(ctx, userID, fromCurrency, toCurrency, direction, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRateAlertManager)(nil).Create), ctx, userID, fromCurrency, toCurrency, direction, threshold)
+}
+
+// Delete mocks base method.
+func (m *MockRateAlertManager) Delete(ctx context.Context, userID, alertID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, alertID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRateAlertManagerMockRecorder) Delete(ctx, userID, alertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRateAlertManager)(nil).Delete), ctx, userID, alertID)
+}
+
+// List mocks base method.
+func (m *MockRateAlertManager) List(ctx context.Context, userID uuid.UUID) ([]models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRateAlertManagerMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRateAlertManager)(nil).List), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockRateAlertManager) Update(ctx context.Context, userID, alertID uuid.UUID, direction string, threshold float32) (models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, alertID, direction, threshold)
+	ret0, _ := ret[0].(models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRateAlertManagerMockRecorder) Update(ctx, userID, alertID, direction, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRateAlertManager)(nil).Update), ctx, userID, alertID, direction, threshold)
+}
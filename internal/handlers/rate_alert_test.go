@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRateAlertHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockRateAlertTokener(ctrl)
+	mockSvc := NewMockRateAlertManager(ctrl)
+	handler := NewCreateRateAlertHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	userID := uuid.New()
+	alertID := uuid.New()
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "success",
+			body: `{"pair":"USD-EUR","direction":"above","threshold":0.95}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), userID, models.USD, models.EUR, models.RateAlertAbove, float32(0.95)).
+					Return(models.RateAlertDB{AlertID: alertID, UserID: userID, FromCurrency: models.USD, ToCurrency: models.EUR,
+						Direction: models.RateAlertAbove, Threshold: 0.95, CreatedAt: createdAt}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: RateAlertResponse{
+				AlertID:   alertID.String(),
+				Pair:      "USD-EUR",
+				Direction: models.RateAlertAbove,
+				Threshold: 0.95,
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:           "invalid_body",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:           "invalid_pair",
+			body:           `{"pair":"USD-GBP","direction":"above","threshold":0.95}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency pair", Code: "invalid_currency_pair"},
+		},
+		{
+			name:           "same_currency",
+			body:           `{"pair":"USD-USD","direction":"above","threshold":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency pair", Code: "invalid_currency_pair"},
+		},
+		{
+			name: "invalid_alert",
+			body: `{"pair":"USD-EUR","direction":"sideways","threshold":0.95}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), userID, models.USD, models.EUR, "sideways", float32(0.95)).
+					Return(models.RateAlertDB{}, services.ErrInvalidRateAlert)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid rate alert", Code: "invalid_rate_alert"},
+		},
+		{
+			name: "too_many",
+			body: `{"pair":"USD-EUR","direction":"above","threshold":0.95}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), userID, models.USD, models.EUR, models.RateAlertAbove, float32(0.95)).
+					Return(models.RateAlertDB{}, services.ErrTooManyRateAlerts)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Too many rate alerts", Code: "too_many_rate_alerts"},
+		},
+		{
+			name: "internal_error",
+			body: `{"pair":"USD-EUR","direction":"above","threshold":0.95}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), userID, models.USD, models.EUR, models.RateAlertAbove, float32(0.95)).
+					Return(models.RateAlertDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/exchange/alerts", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case RateAlertResponse:
+				var got RateAlertResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestListRateAlertsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockRateAlertTokener(ctrl)
+	mockSvc := NewMockRateAlertManager(ctrl)
+	handler := NewListRateAlertsHandler(mockSvc, mockTokener)
+
+	userID := uuid.New()
+	alertID := uuid.New()
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	triggeredAt := createdAt.Add(time.Hour)
+	triggeredRate := float32(0.96)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	t.Run("fired alert", func(t *testing.T) {
+		mockSvc.EXPECT().List(gomock.Any(), userID).Return([]models.RateAlertDB{
+			{AlertID: alertID, UserID: userID, FromCurrency: models.USD, ToCurrency: models.EUR, Direction: models.RateAlertAbove,
+				Threshold: 0.95, TriggeredAt: &triggeredAt, TriggeredRate: &triggeredRate, CreatedAt: createdAt},
+		}, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exchange/alerts", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var got RateAlertsResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, RateAlertsResponse{Alerts: []RateAlertResponse{
+			{AlertID: alertID.String(), Pair: "USD-EUR", Direction: models.RateAlertAbove, Threshold: 0.95,
+				TriggeredAt: &triggeredAt, TriggeredRate: &triggeredRate, CreatedAt: createdAt},
+		}}, got)
+	})
+
+	t.Run("no alerts", func(t *testing.T) {
+		mockSvc.EXPECT().List(gomock.Any(), userID).Return(nil, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exchange/alerts", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"alerts":[]}`, rec.Body.String())
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockSvc.EXPECT().List(gomock.Any(), userID).Return(nil, errors.New("db error"))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exchange/alerts", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestUpdateDeleteRateAlertHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockRateAlertTokener(ctrl)
+	mockSvc := NewMockRateAlertManager(ctrl)
+	update := NewUpdateRateAlertHandler(mockSvc, mockTokener)
+	del := NewDeleteRateAlertHandler(mockSvc, mockTokener)
+
+	userID := uuid.New()
+	alertID := uuid.New()
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		method         string
+		alertID        string
+		body           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   *apperrors.Response
+	}{
+		{
+			name:    "update_success",
+			handler: update,
+			method:  http.MethodPut,
+			alertID: alertID.String(),
+			body:    `{"direction":"below","threshold":0.9}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().Update(gomock.Any(), userID, alertID, models.RateAlertBelow, float32(0.9)).
+					Return(models.RateAlertDB{AlertID: alertID, Direction: models.RateAlertBelow, Threshold: 0.9}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "update_invalid_id",
+			handler:        update,
+			method:         http.MethodPut,
+			alertID:        "not-a-uuid",
+			body:           `{"direction":"below","threshold":0.9}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   &apperrors.Response{Error: "Invalid rate alert ID", Code: "invalid_rate_alert_id"},
+		},
+		{
+			name:           "update_invalid_body",
+			handler:        update,
+			method:         http.MethodPut,
+			alertID:        alertID.String(),
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   &apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:    "update_invalid_alert",
+			handler: update,
+			method:  http.MethodPut,
+			alertID: alertID.String(),
+			body:    `{"direction":"below","threshold":-1}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().Update(gomock.Any(), userID, alertID, models.RateAlertBelow, float32(-1)).
+					Return(models.RateAlertDB{}, services.ErrInvalidRateAlert)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   &apperrors.Response{Error: "Invalid rate alert", Code: "invalid_rate_alert"},
+		},
+		{
+			name:    "update_not_found",
+			handler: update,
+			method:  http.MethodPut,
+			alertID: alertID.String(),
+			body:    `{"direction":"below","threshold":0.9}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().Update(gomock.Any(), userID, alertID, models.RateAlertBelow, float32(0.9)).
+					Return(models.RateAlertDB{}, services.ErrRateAlertNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   &apperrors.Response{Error: "Rate alert not found", Code: "rate_alert_not_found"},
+		},
+		{
+			name:    "delete_success",
+			handler: del,
+			method:  http.MethodDelete,
+			alertID: alertID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), userID, alertID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "delete_invalid_id",
+			handler:        del,
+			method:         http.MethodDelete,
+			alertID:        "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   &apperrors.Response{Error: "Invalid rate alert ID", Code: "invalid_rate_alert_id"},
+		},
+		{
+			name:    "delete_not_found",
+			handler: del,
+			method:  http.MethodDelete,
+			alertID: alertID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), userID, alertID).Return(services.ErrRateAlertNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   &apperrors.Response{Error: "Rate alert not found", Code: "rate_alert_not_found"},
+		},
+		{
+			name:    "delete_internal_error",
+			handler: del,
+			method:  http.MethodDelete,
+			alertID: alertID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), userID, alertID).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   &apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(tt.method, "/exchange/alerts/"+tt.alertID, strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alertID", tt.alertID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			if tt.expectedBody != nil {
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, *tt.expectedBody, got)
+			}
+		})
+	}
+}
+
+func TestRateAlertHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockRateAlertTokener(ctrl)
+	mockSvc := NewMockRateAlertManager(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Times(4).
+		Return("", errors.New("missing token"))
+
+	for _, handler := range []http.HandlerFunc{
+		NewCreateRateAlertHandler(mockSvc, mockTokener, newMockCurrencies(ctrl)),
+		NewListRateAlertsHandler(mockSvc, mockTokener),
+		NewUpdateRateAlertHandler(mockSvc, mockTokener),
+		NewDeleteRateAlertHandler(mockSvc, mockTokener),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exchange/alerts", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// DBPinger defines the interface for checking the database connection.
+type DBPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// CachePinger defines the interface for checking the cache connection.
+type CachePinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// ExchangerPinger defines the interface for checking the connection to the exchanger.
+type ExchangerPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// BrokerPinger defines the interface for checking the event broker connection.
+type BrokerPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SchemaDriftReporter defines the interface for reading the last detected schema drift.
+type SchemaDriftReporter interface {
+	Drift() []string
+}
+
+// ReadyzResponse represents the readiness of the instance
+// swagger:model ReadyzResponse
+type ReadyzResponse struct {
+	// Readiness status
+	// default: ok
+	Status string `json:"status"`
+
+	// Status of every dependency: ok or unavailable
+	// default: {"postgres": "ok", "redis": "ok", "exchanger": "ok", "kafka": "ok"}
+	Checks map[string]string `json:"checks"`
+
+	// Differences between the live schema and the migrations, empty if none.
+	// They do not make the instance unready.
+	// default: ["missing index idx_wallet_holds_user_id on wallet_holds"]
+	Warnings []string `json:"warnings"`
+}
+
+// readyzCheck is a dependency the instance cannot serve traffic without.
+type readyzCheck struct {
+	name   string          // Key in the checks of the response
+	err    apperrors.Error // Error of the response if the dependency is the first one unavailable
+	pinger interface {
+		PingContext(ctx context.Context) error
+	}
+}
+
+// NewReadyzHandler returns an HTTP handler that reports whether the instance can serve traffic.
+// @Summary Readiness probe
+// @Description Checks the PostgreSQL and Redis connections, that the gRPC connection to the exchanger is ready and, if events are published to Kafka, that the Kafka cluster answers a metadata request, and reports the status of every dependency. Every check is given its own timeout. Schema drift detected at startup (tables, columns or indexes of the migrations missing from the database) is reported as warnings without failing the probe.
+// @Tags health
+// @Produce json
+// @Success 200 {object} handlers.ReadyzResponse "Instance is ready"
+// @Failure 503 {object} apperrors.Response "Database, Redis, exchanger or Kafka unavailable"
+// @Router /readyz [get]
+// A nil cache, exchanger or kafka skips its check; a zero timeout leaves the checks to the request deadline.
+func NewReadyzHandler(db DBPinger, cache CachePinger, exchanger ExchangerPinger, kafka BrokerPinger, drift SchemaDriftReporter, timeout time.Duration) http.HandlerFunc {
+	checks := []readyzCheck{{name: "postgres", err: apperrors.DatabaseUnavailable, pinger: db}}
+	if cache != nil {
+		checks = append(checks, readyzCheck{name: "redis", err: apperrors.RedisUnavailable, pinger: cache})
+	}
+	if exchanger != nil {
+		checks = append(checks, readyzCheck{name: "exchanger", err: apperrors.ExchangerNotReady, pinger: exchanger})
+	}
+	if kafka != nil {
+		checks = append(checks, readyzCheck{name: "kafka", err: apperrors.KafkaUnavailable, pinger: kafka})
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Every dependency is checked at once, so the response shows all that are down
+		// and a slow one does not delay the others
+		errs := make([]error, len(checks))
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				checkCtx := ctx
+				if timeout > 0 {
+					var cancel context.CancelFunc
+					checkCtx, cancel = context.WithTimeout(ctx, timeout)
+					defer cancel()
+				}
+				errs[i] = check.pinger.PingContext(checkCtx)
+			}()
+		}
+		wg.Wait()
+
+		statuses := make(map[string]string, len(checks))
+		var failed *apperrors.Error
+		for i, check := range checks {
+			if errs[i] != nil {
+				logger.FromContext(ctx).Errorw("readiness check failed", "dependency", check.name, "error", errs[i])
+				statuses[check.name] = "unavailable"
+				if failed == nil {
+					failed = &check.err
+				}
+				continue
+			}
+			statuses[check.name] = "ok"
+		}
+		if failed != nil {
+			apperrors.WriteDetails(w, *failed, statuses)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ReadyzResponse{Status: "ok", Checks: statuses, Warnings: append([]string{}, drift.Drift()...)})
+	}
+}
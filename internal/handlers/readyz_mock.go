@@ -0,0 +1,197 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/readyz.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDBPinger is a mock of DBPinger interface.
+type MockDBPinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockDBPingerMockRecorder
+}
+
+// MockDBPingerMockRecorder is the mock recorder for MockDBPinger.
+type MockDBPingerMockRecorder struct {
+	mock *MockDBPinger
+}
+
+// NewMockDBPinger creates a new mock instance.
+func NewMockDBPinger(ctrl *gomock.Controller) *MockDBPinger {
+	mock := &MockDBPinger{ctrl: ctrl}
+	mock.recorder = &MockDBPingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDBPinger) EXPECT() *MockDBPingerMockRecorder {
+	return m.recorder
+}
+
+// PingContext mocks base method.
+func (m *MockDBPinger) PingContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingContext indicates an expected call of PingContext.
+func (mr *MockDBPingerMockRecorder) PingContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingContext", reflect.TypeOf((*MockDBPinger)(nil).PingContext), ctx)
+}
+
+// MockCachePinger is a mock of CachePinger interface.
+type MockCachePinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockCachePingerMockRecorder
+}
+
+// MockCachePingerMockRecorder is the mock recorder for MockCachePinger.
+type MockCachePingerMockRecorder struct {
+	mock *MockCachePinger
+}
+
+// NewMockCachePinger creates a new mock instance.
+func NewMockCachePinger(ctrl *gomock.Controller) *MockCachePinger {
+	mock := &MockCachePinger{ctrl: ctrl}
+	mock.recorder = &MockCachePingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCachePinger) EXPECT() *MockCachePingerMockRecorder {
+	return m.recorder
+}
+
+// PingContext mocks base method.
+func (m *MockCachePinger) PingContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingContext indicates an expected call of PingContext.
+func (mr *MockCachePingerMockRecorder) PingContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingContext", reflect.TypeOf((*MockCachePinger)(nil).PingContext), ctx)
+}
+
+// MockExchangerPinger is a mock of ExchangerPinger interface.
+type MockExchangerPinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangerPingerMockRecorder
+}
+
+// MockExchangerPingerMockRecorder is the mock recorder for MockExchangerPinger.
+type MockExchangerPingerMockRecorder struct {
+	mock *MockExchangerPinger
+}
+
+// NewMockExchangerPinger creates a new mock instance.
+func NewMockExchangerPinger(ctrl *gomock.Controller) *MockExchangerPinger {
+	mock := &MockExchangerPinger{ctrl: ctrl}
+	mock.recorder = &MockExchangerPingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangerPinger) EXPECT() *MockExchangerPingerMockRecorder {
+	return m.recorder
+}
+
+// PingContext mocks base method.
+func (m *MockExchangerPinger) PingContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingContext indicates an expected call of PingContext.
+func (mr *MockExchangerPingerMockRecorder) PingContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingContext", reflect.TypeOf((*MockExchangerPinger)(nil).PingContext), ctx)
+}
+
+// MockBrokerPinger is a mock of BrokerPinger interface.
+type MockBrokerPinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrokerPingerMockRecorder
+}
+
+// MockBrokerPingerMockRecorder is the mock recorder for MockBrokerPinger.
+type MockBrokerPingerMockRecorder struct {
+	mock *MockBrokerPinger
+}
+
+// NewMockBrokerPinger creates a new mock instance.
+func NewMockBrokerPinger(ctrl *gomock.Controller) *MockBrokerPinger {
+	mock := &MockBrokerPinger{ctrl: ctrl}
+	mock.recorder = &MockBrokerPingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBrokerPinger) EXPECT() *MockBrokerPingerMockRecorder {
+	return m.recorder
+}
+
+// PingContext mocks base method.
+func (m *MockBrokerPinger) PingContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingContext indicates an expected call of PingContext.
+func (mr *MockBrokerPingerMockRecorder) PingContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingContext", reflect.TypeOf((*MockBrokerPinger)(nil).PingContext), ctx)
+}
+
+// MockSchemaDriftReporter is a mock of SchemaDriftReporter interface.
+type MockSchemaDriftReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaDriftReporterMockRecorder
+}
+
+// MockSchemaDriftReporterMockRecorder is the mock recorder for MockSchemaDriftReporter.
+type MockSchemaDriftReporterMockRecorder struct {
+	mock *MockSchemaDriftReporter
+}
+
+// NewMockSchemaDriftReporter creates a new mock instance.
+func NewMockSchemaDriftReporter(ctrl *gomock.Controller) *MockSchemaDriftReporter {
+	mock := &MockSchemaDriftReporter{ctrl: ctrl}
+	mock.recorder = &MockSchemaDriftReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaDriftReporter) EXPECT() *MockSchemaDriftReporterMockRecorder {
+	return m.recorder
+}
+
+// Drift mocks base method.
+func (m *MockSchemaDriftReporter) Drift() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drift")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Drift indicates an expected call of Drift.
+func (mr *MockSchemaDriftReporterMockRecorder) Drift() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drift", reflect.TypeOf((*MockSchemaDriftReporter)(nil).Drift))
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadyzHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockDBPinger(ctrl)
+	mockDrift := NewMockSchemaDriftReporter(ctrl)
+	mockCache := NewMockCachePinger(ctrl)
+	mockExchanger := NewMockExchangerPinger(ctrl)
+	mockKafka := NewMockBrokerPinger(ctrl)
+	handler := NewReadyzHandler(mockDB, mockCache, mockExchanger, mockKafka, mockDrift, time.Second)
+
+	allOK := map[string]string{"postgres": "ok", "redis": "ok", "exchanger": "ok", "kafka": "ok"}
+
+	tests := []struct {
+		name           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "ready",
+			mockSetup: func() {
+				mockDB.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockCache.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockExchanger.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockKafka.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockDrift.EXPECT().Drift().Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ReadyzResponse{Status: "ok", Checks: allOK, Warnings: []string{}},
+		},
+		{
+			name: "ready_with_schema_drift",
+			mockSetup: func() {
+				mockDB.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockCache.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockExchanger.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockKafka.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockDrift.EXPECT().Drift().Return([]string{"missing column wallets.held"})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   ReadyzResponse{Status: "ok", Checks: allOK, Warnings: []string{"missing column wallets.held"}},
+		},
+		{
+			name: "database_unavailable",
+			mockSetup: func() {
+				mockDB.EXPECT().PingContext(gomock.Any()).Return(errors.New("connection refused"))
+				mockCache.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockExchanger.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockKafka.EXPECT().PingContext(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Database unavailable", Code: "database_unavailable", Details: map[string]any{"postgres": "unavailable", "redis": "ok", "exchanger": "ok", "kafka": "ok"}},
+		},
+		{
+			name: "redis_unavailable",
+			mockSetup: func() {
+				mockDB.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockCache.EXPECT().PingContext(gomock.Any()).Return(errors.New("connection refused"))
+				mockExchanger.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockKafka.EXPECT().PingContext(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Redis unavailable", Code: "redis_unavailable", Details: map[string]any{"postgres": "ok", "redis": "unavailable", "exchanger": "ok", "kafka": "ok"}},
+		},
+		{
+			name: "exchanger_and_kafka_unavailable",
+			mockSetup: func() {
+				mockDB.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockCache.EXPECT().PingContext(gomock.Any()).Return(nil)
+				mockExchanger.EXPECT().PingContext(gomock.Any()).Return(errors.New("connection TRANSIENT_FAILURE"))
+				mockKafka.EXPECT().PingContext(gomock.Any()).Return(errors.New("no brokers"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   apperrors.Response{Error: "Exchanger unavailable", Code: "exchanger_not_ready", Details: map[string]any{"postgres": "ok", "redis": "ok", "exchanger": "unavailable", "kafka": "unavailable"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			switch expected := tt.expectedBody.(type) {
+			case ReadyzResponse:
+				var got ReadyzResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestReadyzHandler_WithoutOptionalDependencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockDBPinger(ctrl)
+	mockDrift := NewMockSchemaDriftReporter(ctrl)
+	handler := NewReadyzHandler(mockDB, nil, nil, nil, mockDrift, 0)
+
+	mockDB.EXPECT().PingContext(gomock.Any()).Return(nil)
+	mockDrift.EXPECT().Drift().Return(nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got ReadyzResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, map[string]string{"postgres": "ok"}, got.Checks)
+}
+
+func TestReadyzHandler_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := NewMockDBPinger(ctrl)
+	mockCache := NewMockCachePinger(ctrl)
+	mockDrift := NewMockSchemaDriftReporter(ctrl)
+	handler := NewReadyzHandler(mockDB, mockCache, nil, nil, mockDrift, 10*time.Millisecond)
+
+	// A hanging dependency is reported unavailable once its timeout expires
+	mockDB.EXPECT().PingContext(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	mockCache.EXPECT().PingContext(gomock.Any()).Return(nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var got apperrors.Response
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, apperrors.Response{Error: "Database unavailable", Code: "database_unavailable", Details: map[string]any{"postgres": "unavailable", "redis": "ok"}}, got)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ReceiveQRTokener defines only the methods needed by this handler.
+type ReceiveQRTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// ReceiveQRGenerator defines the interface that the service must implement.
+type ReceiveQRGenerator interface {
+	ReceiveQR(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, format string) ([]byte, error)
+}
+
+// receiveQRContentTypes maps the QR code formats to their content types.
+var receiveQRContentTypes = map[string]string{
+	services.QRFormatPNG: "image/png",
+	services.QRFormatSVG: "image/svg+xml",
+}
+
+// NewGetReceiveQRHandler returns an HTTP handler rendering the QR code other users scan to pay the user.
+// @Summary Get receive QR code
+// @Description Returns a QR code encoding gwwallet://receive?to=<username> with the optional amount and currency, so mobile clients can implement scan-to-pay. An amount requires a currency.
+// @Tags wallet
+// @Produce image/png
+// @Produce image/svg+xml
+// @Produce json
+// @Param amount query number false "Amount to pay"
+// @Param currency query string false "Supported currency code"
+// @Param format query string false "Image format: png (default) or svg"
+// @Success 200 {file} file "QR code image"
+// @Failure 400 {object} apperrors.Response "Invalid amount, currency or format"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/receive/qr [get]
+// @Security BearerAuth
+func NewGetReceiveQRHandler(
+	svc ReceiveQRGenerator,
+	tokenGetter ReceiveQRTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = services.QRFormatPNG
+		}
+		contentType, ok := receiveQRContentTypes[format]
+		if !ok {
+			apperrors.Write(w, apperrors.UnsupportedQRFormat)
+			return
+		}
+
+		var amount money.Amount
+		currency := query.Get("currency")
+		if raw := query.Get("amount"); raw != "" {
+			amount, err = money.Parse(raw)
+			if err != nil || !amount.IsPositive() || currency == "" {
+				logger.FromContext(ctx).Warnw("invalid receive QR amount", "amount", raw, "currency", currency)
+				apperrors.Write(w, apperrors.InvalidAmountOrCurrency)
+				return
+			}
+		}
+		if currency != "" && (!currencies.IsSupported(ctx, currency) || amount != 0 && !currencies.ValidAmount(ctx, currency, amount)) {
+			logger.FromContext(ctx).Warnw("invalid receive QR currency", "currency", currency)
+			apperrors.Write(w, apperrors.InvalidAmountOrCurrency)
+			return
+		}
+
+		content, err := svc.ReceiveQR(ctx, claims.UserID, amount, currency, format)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrUnsupportedQRFormat):
+				apperrors.Write(w, apperrors.UnsupportedQRFormat)
+			default:
+				logger.FromContext(ctx).Errorw("failed to render receive QR", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		w.Write(content)
+	}
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/receive_qr.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockReceiveQRTokener is a mock of ReceiveQRTokener interface.
+type MockReceiveQRTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiveQRTokenerMockRecorder
+}
+
+// MockReceiveQRTokenerMockRecorder is the mock recorder for MockReceiveQRTokener.
+type MockReceiveQRTokenerMockRecorder struct {
+	mock *MockReceiveQRTokener
+}
+
+// NewMockReceiveQRTokener creates a new mock instance.
+func NewMockReceiveQRTokener(ctrl *gomock.Controller) *MockReceiveQRTokener {
+	mock := &MockReceiveQRTokener{ctrl: ctrl}
+	mock.recorder = &MockReceiveQRTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceiveQRTokener) EXPECT() *MockReceiveQRTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockReceiveQRTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockReceiveQRTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockReceiveQRTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockReceiveQRTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockReceiveQRTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockReceiveQRTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockReceiveQRGenerator is a mock of ReceiveQRGenerator interface.
+type MockReceiveQRGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiveQRGeneratorMockRecorder
+}
+
+// MockReceiveQRGeneratorMockRecorder is the mock recorder for MockReceiveQRGenerator.
+type MockReceiveQRGeneratorMockRecorder struct {
+	mock *MockReceiveQRGenerator
+}
+
+// NewMockReceiveQRGenerator creates a new mock instance.
+func NewMockReceiveQRGenerator(ctrl *gomock.Controller) *MockReceiveQRGenerator {
+	mock := &MockReceiveQRGenerator{ctrl: ctrl}
+	mock.recorder = &MockReceiveQRGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceiveQRGenerator) EXPECT() *MockReceiveQRGeneratorMockRecorder {
+	return m.recorder
+}
+
+// ReceiveQR mocks base method.
+func (m *MockReceiveQRGenerator) ReceiveQR(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, format string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveQR", ctx, userID, amount, currency, format)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveQR indicates an expected call of ReceiveQR.
+func (mr *MockReceiveQRGeneratorMockRecorder) ReceiveQR(ctx, userID, amount, currency, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveQR", reflect.TypeOf((*MockReceiveQRGenerator)(nil).ReceiveQR), ctx, userID, amount, currency, format)
+}
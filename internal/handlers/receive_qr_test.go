@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReceiveQRHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockReceiveQRTokener(ctrl)
+	mockSvc := NewMockReceiveQRGenerator(ctrl)
+
+	userID := uuid.New()
+	png := []byte("\x89PNG")
+	svg := []byte("<svg></svg>")
+
+	handler := NewGetReceiveQRHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name                string
+		query               string
+		mockSvc             func()
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        []byte
+		expectedError       string
+	}{
+		{
+			name:  "png_default",
+			query: "",
+			mockSvc: func() {
+				mockSvc.EXPECT().ReceiveQR(gomock.Any(), userID, money.Amount(0), "", services.QRFormatPNG).Return(png, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        png,
+		},
+		{
+			name:  "svg_with_amount",
+			query: "?amount=25.50&currency=USD&format=svg",
+			mockSvc: func() {
+				mockSvc.EXPECT().ReceiveQR(gomock.Any(), userID, money.MustParse("25.50"), models.USD, services.QRFormatSVG).Return(svg, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/svg+xml",
+			expectedBody:        svg,
+		},
+		{
+			name:  "currency_only",
+			query: "?currency=EUR",
+			mockSvc: func() {
+				mockSvc.EXPECT().ReceiveQR(gomock.Any(), userID, money.Amount(0), models.EUR, services.QRFormatPNG).Return(png, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        png,
+		},
+		{
+			name:           "unsupported_format",
+			query:          "?format=gif",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Unsupported QR format",
+		},
+		{
+			name:           "invalid_amount",
+			query:          "?amount=-5&currency=USD",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid amount or currency",
+		},
+		{
+			name:           "amount_without_currency",
+			query:          "?amount=5",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid amount or currency",
+		},
+		{
+			name:           "unsupported_currency",
+			query:          "?amount=5&currency=BTC",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid amount or currency",
+		},
+		{
+			name:  "internal_error",
+			query: "",
+			mockSvc: func() {
+				mockSvc.EXPECT().ReceiveQR(gomock.Any(), userID, money.Amount(0), "", services.QRFormatPNG).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/wallet/receive/qr"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedError != "" {
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, tt.expectedError, got.Error)
+				return
+			}
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestGetReceiveQRHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockReceiveQRTokener(ctrl)
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("no token"))
+
+	handler := NewGetReceiveQRHandler(NewMockReceiveQRGenerator(ctrl), mockTokener, newMockCurrencies(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/wallet/receive/qr", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"error":"Unauthorized","code":"unauthorized"}`, rec.Body.String())
+}
@@ -3,10 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
 )
 
 // Registerer defines the interface that the service must implement.
@@ -14,23 +18,28 @@ type Registerer interface {
 	Register(ctx context.Context, username, password, email string) error
 }
 
+// RegistrationPolicy decides whether a registration with the given email is allowed.
+type RegistrationPolicy interface {
+	Check(ctx context.Context, email string) error
+}
+
 // RegisterRequest represents the JSON body for user registration
 // swagger:model RegisterRequest
 type RegisterRequest struct {
 	// Username
 	// required: true
 	// default: john_doe
-	Username string `json:"username"`
+	Username string `json:"username" validate:"required,max=50"`
 
-	// Password
+	// Password, 6 to 72 characters
 	// required: true
 	// default: secret123
-	Password string `json:"password"`
+	Password string `json:"password" validate:"required,min=6,max=72"`
 
 	// Email
 	// required: true
 	// default: john@example.com
-	Email string `json:"email"`
+	Email string `json:"email" validate:"required,email,max=100"`
 }
 
 // RegisterResponse represents a successful registration response
@@ -41,52 +50,61 @@ type RegisterResponse struct {
 	Message string `json:"message"`
 }
 
-// RegisterErrorResponse represents an error response for registration
-// swagger:model RegisterErrorResponse
-type RegisterErrorResponse struct {
-	// Error message
-	// default: Username or email already exists
-	Error string `json:"error"`
-}
-
 // NewRegisterHandler returns an HTTP handler for user registration.
 // @Summary Register a new user
-// @Description Creates a new user account. Ensures unique username and email. Password is hashed before storing.
+// @Description Creates a new user account. Ensures unique username and email. Password is hashed before storing. The email domain may be restricted by block/allow lists and a per-domain registration cap.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param registerRequest body handlers.RegisterRequest true "User registration request"
 // @Success 201 {object} handlers.RegisterResponse "User successfully registered"
-// @Failure 400 {object} handlers.RegisterErrorResponse "Username or email already exists / invalid request"
+// @Failure 400 {object} apperrors.Response "Username or email already exists / invalid username, password or email"
+// @Failure 403 {object} apperrors.Response "Email domain is not allowed"
+// @Failure 429 {object} apperrors.Response "Too many registrations from this email domain or too many requests"
 // @Router /register [post]
-func NewRegisterHandler(svc Registerer) http.HandlerFunc {
+func NewRegisterHandler(svc Registerer, policy RegistrationPolicy) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req RegisterRequest
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			logger.Log.Errorw("failed to decode register request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(RegisterErrorResponse{
-				Error: "Username or email already exists",
-			})
+			logger.FromContext(r.Context()).Errorw("failed to decode register request", "error", err)
+			apperrors.Write(w, apperrors.UserAlreadyExists)
 			return
 		}
+		if errs := validation.Struct(req); errs != nil {
+			logger.FromContext(r.Context()).Warnw("invalid register request", "error", errs)
+			apperrors.WriteDetails(w, apperrors.InvalidRegistration, errs)
+			return
+		}
+
+		if policy != nil {
+			if err := policy.Check(r.Context(), req.Email); err != nil {
+				switch {
+				case errors.Is(err, services.ErrEmailDomainNotAllowed):
+					logger.FromContext(r.Context()).Warnw("register attempt rejected: email domain not allowed", "email", req.Email)
+					metrics.RegistrationRejections.WithLabelValues(metrics.ReasonDomainNotAllowed).Inc()
+					apperrors.Write(w, apperrors.EmailDomainNotAllowed)
+				case errors.Is(err, services.ErrRegistrationRateLimited):
+					logger.FromContext(r.Context()).Warnw("register attempt rejected: email domain rate limited", "email", req.Email)
+					metrics.RegistrationRejections.WithLabelValues(metrics.ReasonDomainRateLimit).Inc()
+					apperrors.Write(w, apperrors.EmailDomainRateLimited)
+				default:
+					logger.FromContext(r.Context()).Errorw("failed to check registration policy", "email", req.Email, "error", err)
+					apperrors.Write(w, apperrors.Internal)
+				}
+				return
+			}
+		}
 
 		err := svc.Register(r.Context(), req.Username, req.Password, req.Email)
 		if err != nil {
 			switch err {
 			case services.ErrUserAlreadyExists:
-				logger.Log.Warnw("register attempt failed: user already exists", "username", req.Username, "email", req.Email)
-				w.WriteHeader(http.StatusBadRequest)
-				json.NewEncoder(w).Encode(RegisterErrorResponse{
-					Error: "Username or email already exists",
-				})
+				logger.FromContext(r.Context()).Warnw("register attempt failed: user already exists", "username", req.Username, "email", req.Email)
+				apperrors.Write(w, apperrors.UserAlreadyExists)
 			default:
-				logger.Log.Errorw("internal server error during registration", "username", req.Username, "email", req.Email, "error", err)
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(RegisterErrorResponse{
-					Error: "Internal server error",
-				})
+				logger.FromContext(r.Context()).Errorw("internal server error during registration", "username", req.Username, "email", req.Email, "error", err)
+				apperrors.Write(w, apperrors.Internal)
 			}
 			return
 		}
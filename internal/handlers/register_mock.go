@@ -47,3 +47,40 @@ func (mr *MockRegistererMockRecorder) Register(ctx, username, password, email in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockRegisterer)(nil).Register), ctx, username, password, email)
 }
+
+// MockRegistrationPolicy is a mock of RegistrationPolicy interface.
+type MockRegistrationPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistrationPolicyMockRecorder
+}
+
+// MockRegistrationPolicyMockRecorder is the mock recorder for MockRegistrationPolicy.
+type MockRegistrationPolicyMockRecorder struct {
+	mock *MockRegistrationPolicy
+}
+
+// NewMockRegistrationPolicy creates a new mock instance.
+func NewMockRegistrationPolicy(ctrl *gomock.Controller) *MockRegistrationPolicy {
+	mock := &MockRegistrationPolicy{ctrl: ctrl}
+	mock.recorder = &MockRegistrationPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistrationPolicy) EXPECT() *MockRegistrationPolicyMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockRegistrationPolicy) Check(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockRegistrationPolicyMockRecorder) Check(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockRegistrationPolicy)(nil).Check), ctx, email)
+}
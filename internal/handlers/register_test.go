@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,18 +59,28 @@ func TestRegisterHandler(t *testing.T) {
 					Return(services.ErrUserAlreadyExists)
 			},
 			expectedCode: http.StatusBadRequest,
-			expectedBody: &RegisterErrorResponse{
-				Error: "Username or email already exists",
-			},
+			expectedBody: &apperrors.Response{Error: "Username or email already exists", Code: "user_already_exists"},
 		},
 		{
 			name:         "invalid JSON",
 			inputBody:    "{invalid json}",
 			mockSetup:    func() {},
 			expectedCode: http.StatusBadRequest,
-			expectedBody: &RegisterErrorResponse{
-				Error: "Username or email already exists",
+			expectedBody: &apperrors.Response{Error: "Username or email already exists", Code: "user_already_exists"},
+		},
+		{
+			name: "invalid fields",
+			inputBody: RegisterRequest{
+				Username: "john",
+				Password: "123",
+				Email:    "not-an-email",
 			},
+			mockSetup:    func() {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: &apperrors.Response{Error: "Invalid username, password or email", Code: "invalid_registration", Details: []any{
+				map[string]any{"field": "password", "rule": "min", "param": "6", "message": "must be at least 6 long"},
+				map[string]any{"field": "email", "rule": "email", "message": "must be an email address"},
+			}},
 		},
 		{
 			name: "internal error",
@@ -82,9 +95,7 @@ func TestRegisterHandler(t *testing.T) {
 					Return(errors.New("database error"))
 			},
 			expectedCode: http.StatusInternalServerError,
-			expectedBody: &RegisterErrorResponse{
-				Error: "Internal server error",
-			},
+			expectedBody: &apperrors.Response{Error: "Internal server error", Code: "internal"},
 		},
 	}
 
@@ -103,7 +114,7 @@ func TestRegisterHandler(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(bodyBytes))
 			w := httptest.NewRecorder()
 
-			handler := NewRegisterHandler(mockSvc)
+			handler := NewRegisterHandler(mockSvc, nil)
 			handler.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedCode, w.Code)
@@ -114,7 +125,7 @@ func TestRegisterHandler(t *testing.T) {
 				case http.StatusCreated:
 					respBody = &RegisterResponse{}
 				default:
-					respBody = &RegisterErrorResponse{}
+					respBody = &apperrors.Response{}
 				}
 				err := json.Unmarshal(w.Body.Bytes(), respBody)
 				assert.NoError(t, err)
@@ -124,3 +135,74 @@ func TestRegisterHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterHandler_RegistrationPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockRegisterer(ctrl)
+	mockPolicy := NewMockRegistrationPolicy(ctrl)
+
+	handler := NewRegisterHandler(mockSvc, mockPolicy)
+
+	tests := []struct {
+		name         string
+		email        string
+		policyErr    error
+		reason       string
+		expectedCode int
+		expectedBody apperrors.Response
+	}{
+		{
+			name:         "domain not allowed",
+			email:        "bot@spam.com",
+			policyErr:    services.ErrEmailDomainNotAllowed,
+			reason:       metrics.ReasonDomainNotAllowed,
+			expectedCode: http.StatusForbidden,
+			expectedBody: apperrors.Response{Error: "Email domain is not allowed", Code: "email_domain_not_allowed"},
+		},
+		{
+			name:         "domain rate limited",
+			email:        "bot@example.com",
+			policyErr:    services.ErrRegistrationRateLimited,
+			reason:       metrics.ReasonDomainRateLimit,
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: apperrors.Response{Error: "Too many registrations from this email domain", Code: "email_domain_rate_limited"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := testutil.ToFloat64(metrics.RegistrationRejections.WithLabelValues(tt.reason))
+
+			mockPolicy.EXPECT().Check(gomock.Any(), tt.email).Return(tt.policyErr)
+
+			body, _ := json.Marshal(RegisterRequest{Username: "bot", Password: "pass123", Email: tt.email})
+			req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			var got apperrors.Response
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.expectedBody, got)
+
+			assert.Equal(t, before+1, testutil.ToFloat64(metrics.RegistrationRejections.WithLabelValues(tt.reason)))
+		})
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		mockPolicy.EXPECT().Check(gomock.Any(), "john@example.com").Return(nil)
+		mockSvc.EXPECT().Register(gomock.Any(), "john", "pass123", "john@example.com").Return(nil)
+
+		body, _ := json.Marshal(RegisterRequest{Username: "john", Password: "pass123", Email: "john@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
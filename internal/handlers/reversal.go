@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// ReversalTokener defines only the methods needed by this handler.
+type ReversalTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// TransactionReverser defines the interface that the service must implement.
+type TransactionReverser interface {
+	Reverse(ctx context.Context, adminID, transactionID uuid.UUID, reason string) (models.TransactionDB, error)
+}
+
+// ReverseTransactionRequest represents a transaction reversal request
+// swagger:model ReverseTransactionRequest
+type ReverseTransactionRequest struct {
+	// Why the transaction is reversed, recorded in the audit trail
+	// required: true
+	// default: Duplicate payment
+	Reason string `json:"reason"`
+}
+
+// NewReverseTransactionHandler returns an HTTP handler that reverses a transaction.
+// @Summary Reverse a transaction
+// @Description Creates a compensating transaction that moves the money of the original back and links to it via reversal_of. A deposit is withdrawn, a withdrawal deposited back, and an exchange or a wallet closure with payout exchanged back at the original amounts. A transaction is reversed at most once and reversals cannot be reversed. The reversal is audited and published to Kafka.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param transactionID path string true "Transaction ID"
+// @Param request body handlers.ReverseTransactionRequest true "Reversal reason"
+// @Success 201 {object} handlers.TransactionEntry "Reversal created"
+// @Failure 400 {object} apperrors.Response "Invalid transaction ID, invalid request or insufficient funds"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "Transaction not found"
+// @Failure 409 {object} apperrors.Response "Transaction already reversed or cannot be reversed"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/transactions/{transactionID}/reverse [post]
+// @Security BearerAuth
+func NewReverseTransactionHandler(
+	svc TransactionReverser,
+	tokenGetter ReversalTokener,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		transactionID, err := uuid.Parse(chi.URLParam(r, "transactionID"))
+		if err != nil {
+			logger.FromContext(ctx).Warnw("invalid transaction ID", "transaction_id", chi.URLParam(r, "transactionID"), "error", err)
+			apperrors.Write(w, apperrors.InvalidTransactionID)
+			return
+		}
+
+		var req ReverseTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Warnw("invalid reversal request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		reversal, err := svc.Reverse(ctx, claims.UserID, transactionID, req.Reason)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidReversal):
+				apperrors.Write(w, apperrors.InvalidRequest)
+			case errors.Is(err, services.ErrInsufficientFunds):
+				apperrors.Write(w, apperrors.InsufficientFundsReversal)
+			case errors.Is(err, services.ErrTransactionNotFound):
+				apperrors.Write(w, apperrors.TransactionNotFound)
+			case errors.Is(err, services.ErrTransactionAlreadyReversed):
+				apperrors.Write(w, apperrors.TransactionAlreadyReversed)
+			case errors.Is(err, services.ErrTransactionNotReversible):
+				apperrors.Write(w, apperrors.TransactionNotReversible)
+			default:
+				logger.FromContext(ctx).Errorw("failed to reverse transaction", "adminID", claims.UserID, "transaction_id", transactionID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(newTransactionEntry(reversal))
+	}
+}
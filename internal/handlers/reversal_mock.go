@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/reversal.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockReversalTokener is a mock of ReversalTokener interface.
+type MockReversalTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockReversalTokenerMockRecorder
+}
+
+// MockReversalTokenerMockRecorder is the mock recorder for MockReversalTokener.
+type MockReversalTokenerMockRecorder struct {
+	mock *MockReversalTokener
+}
+
+// NewMockReversalTokener creates a new mock instance.
+func NewMockReversalTokener(ctrl *gomock.Controller) *MockReversalTokener {
+	mock := &MockReversalTokener{ctrl: ctrl}
+	mock.recorder = &MockReversalTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReversalTokener) EXPECT() *MockReversalTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockReversalTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockReversalTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockReversalTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockReversalTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockReversalTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockReversalTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockTransactionReverser is a mock of TransactionReverser interface.
+type MockTransactionReverser struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionReverserMockRecorder
+}
+
+// MockTransactionReverserMockRecorder is the mock recorder for MockTransactionReverser.
+type MockTransactionReverserMockRecorder struct {
+	mock *MockTransactionReverser
+}
+
+// NewMockTransactionReverser creates a new mock instance.
+func NewMockTransactionReverser(ctrl *gomock.Controller) *MockTransactionReverser {
+	mock := &MockTransactionReverser{ctrl: ctrl}
+	mock.recorder = &MockTransactionReverserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionReverser) EXPECT() *MockTransactionReverserMockRecorder {
+	return m.recorder
+}
+
+// Reverse mocks base method.
+func (m *MockTransactionReverser) Reverse(ctx context.Context, adminID, transactionID uuid.UUID, reason string) (models.TransactionDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, adminID, transactionID, reason)
+	ret0, _ := ret[0].(models.TransactionDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockTransactionReverserMockRecorder) Reverse(ctx, adminID, transactionID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockTransactionReverser)(nil).Reverse), ctx, adminID, transactionID, reason)
+}
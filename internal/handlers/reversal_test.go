@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseTransactionHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockReversalTokener(ctrl)
+	mockSvc := NewMockTransactionReverser(ctrl)
+
+	adminID := uuid.New()
+	transactionID := uuid.New()
+	reversalID := uuid.New()
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	handler := NewReverseTransactionHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	reversalOf := transactionID.String()
+
+	tests := []struct {
+		name           string
+		transactionID  string
+		body           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:          "success",
+			transactionID: transactionID.String(),
+			body:          `{"reason":"Duplicate payment"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "Duplicate payment").
+					Return(models.TransactionDB{
+						TransactionID: reversalID,
+						Operation:     models.OperationReversal,
+						Currency:      models.USD,
+						Amount:        money.MustParse("100"),
+						ReversalOf:    &transactionID,
+						CreatedAt:     createdAt,
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: TransactionEntry{
+				TransactionID: reversalID.String(),
+				Operation:     models.OperationReversal,
+				Currency:      models.USD,
+				Amount:        money.MustParse("100"),
+				ReversalOf:    &reversalOf,
+				Timestamp:     createdAt,
+			},
+		},
+		{
+			name:           "invalid_transaction_id",
+			transactionID:  "not-a-uuid",
+			body:           `{"reason":"Duplicate payment"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid transaction ID", Code: "invalid_transaction_id"},
+		},
+		{
+			name:           "invalid_body",
+			transactionID:  transactionID.String(),
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:          "missing_reason",
+			transactionID: transactionID.String(),
+			body:          `{}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "").
+					Return(models.TransactionDB{}, services.ErrInvalidReversal)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:          "insufficient_funds",
+			transactionID: transactionID.String(),
+			body:          `{"reason":"Chargeback"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "Chargeback").
+					Return(models.TransactionDB{}, services.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Insufficient funds to reverse transaction", Code: "insufficient_funds_reversal"},
+		},
+		{
+			name:          "not_found",
+			transactionID: transactionID.String(),
+			body:          `{"reason":"Chargeback"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "Chargeback").
+					Return(models.TransactionDB{}, services.ErrTransactionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Transaction not found", Code: "transaction_not_found"},
+		},
+		{
+			name:          "already_reversed",
+			transactionID: transactionID.String(),
+			body:          `{"reason":"Chargeback"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "Chargeback").
+					Return(models.TransactionDB{}, services.ErrTransactionAlreadyReversed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Transaction already reversed", Code: "transaction_already_reversed"},
+		},
+		{
+			name:          "not_reversible",
+			transactionID: transactionID.String(),
+			body:          `{"reason":"Chargeback"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "Chargeback").
+					Return(models.TransactionDB{}, services.ErrTransactionNotReversible)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Transaction cannot be reversed", Code: "transaction_not_reversible"},
+		},
+		{
+			name:          "internal_error",
+			transactionID: transactionID.String(),
+			body:          `{"reason":"Chargeback"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Reverse(gomock.Any(), adminID, transactionID, "Chargeback").
+					Return(models.TransactionDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/transactions/"+tt.transactionID+"/reverse", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("transactionID", tt.transactionID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case TransactionEntry:
+				var got TransactionEntry
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestReverseTransactionHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockReversalTokener(ctrl)
+	mockSvc := NewMockTransactionReverser(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewReverseTransactionHandler(mockSvc, mockTokener)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/transactions/"+uuid.NewString()+"/reverse", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// TransactionsTokener defines only the methods needed by this handler.
+type TransactionsTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// TransactionLister defines the interface that the service must implement.
+type TransactionLister interface {
+	ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error)
+}
+
+// TransactionEntry represents a single deposit, withdrawal, exchange, wallet closure or reversal
+// swagger:model TransactionEntry
+type TransactionEntry struct {
+	// Transaction identifier
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	TransactionID string `json:"transaction_id"`
+
+	// Operation type: deposit, withdraw, exchange, close, reversal, transfer_out or transfer_in
+	// default: deposit
+	Operation string `json:"operation"`
+
+	// Currency of the operation, the source currency for exchanges
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Operation amount
+	// default: 100.00
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+
+	// Target currency, exchanges and wallet closures with payout only
+	// default: EUR
+	ToCurrency *string `json:"to_currency,omitempty"`
+
+	// Credited amount, exchanges and wallet closures with payout only
+	// default: 92.00
+	ToAmount *money.Amount `json:"to_amount,omitempty" swaggertype:"number"`
+
+	// Reversed transaction, reversals only
+	// default: 1b4e28ba-2fa1-11d2-883f-0016d3cca427
+	ReversalOf *string `json:"reversal_of,omitempty"`
+
+	// Client reference, deposits and withdrawals made with one only
+	// default: INV-2024-0042
+	Reference *string `json:"reference,omitempty"`
+
+	// Time of the operation
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TransactionsResponse represents a page of the transaction history
+// swagger:model TransactionsResponse
+type TransactionsResponse struct {
+	// Transactions, newest first
+	Transactions []TransactionEntry `json:"transactions"`
+
+	// Cursor of the next page, omitted on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewGetTransactionsHandler returns an HTTP handler listing the user's transactions.
+// @Summary Get transaction history
+// @Description Returns the user's deposits, withdrawals, exchanges, wallet closures and their reversals, newest first. Pass next_cursor from the previous page as cursor to get the next one.
+// @Tags wallet
+// @Produce json
+// @Param from query string false "Only transactions at or after this time (RFC 3339)"
+// @Param to query string false "Only transactions before this time (RFC 3339)"
+// @Param currency query string false "Currency on either side of the operation (a supported currency code)"
+// @Param operation query string false "Operation type (deposit, withdraw, exchange, close, reversal, transfer_out, transfer_in)"
+// @Param limit query int false "Number of transactions to return (default 20, max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {object} handlers.TransactionsResponse "Transaction history"
+// @Failure 400 {object} apperrors.Response "Invalid query parameters"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/transactions [get]
+// @Security BearerAuth
+func NewGetTransactionsHandler(
+	svc TransactionLister,
+	tokenGetter TransactionsTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	validOperations := map[string]struct{}{
+		models.OperationDeposit:     {},
+		models.OperationWithdraw:    {},
+		models.OperationExchange:    {},
+		models.OperationClose:       {},
+		models.OperationReversal:    {},
+		models.OperationTransferOut: {},
+		models.OperationTransferIn:  {},
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		q := r.URL.Query()
+		filter := models.TransactionFilter{
+			UserID:    claims.UserID,
+			Currency:  q.Get("currency"),
+			Operation: q.Get("operation"),
+		}
+
+		invalid := func(e apperrors.Error) {
+			apperrors.Write(w, e)
+		}
+
+		if v := q.Get("from"); v != "" {
+			if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+				invalid(apperrors.InvalidFrom)
+				return
+			}
+		}
+		if v := q.Get("to"); v != "" {
+			if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+				invalid(apperrors.InvalidTo)
+				return
+			}
+		}
+		if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+			invalid(apperrors.InvalidDateRange)
+			return
+		}
+		if filter.Currency != "" && !currencies.IsSupported(ctx, filter.Currency) {
+			invalid(apperrors.InvalidCurrency)
+			return
+		}
+		if _, ok := validOperations[filter.Operation]; filter.Operation != "" && !ok {
+			invalid(apperrors.InvalidOperation)
+			return
+		}
+		if v := q.Get("limit"); v != "" {
+			if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 1 {
+				invalid(apperrors.InvalidLimit)
+				return
+			}
+		}
+
+		txns, next, err := svc.ListTransactions(ctx, filter, q.Get("cursor"))
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCursor) {
+				invalid(apperrors.InvalidCursor)
+				return
+			}
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := TransactionsResponse{
+			Transactions: make([]TransactionEntry, 0, len(txns)),
+			NextCursor:   next,
+		}
+		for _, t := range txns {
+			resp.Transactions = append(resp.Transactions, newTransactionEntry(t))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// newTransactionEntry converts a history row into its API representation.
+func newTransactionEntry(t models.TransactionDB) TransactionEntry {
+	entry := TransactionEntry{
+		TransactionID: t.TransactionID.String(),
+		Operation:     t.Operation,
+		Currency:      t.Currency,
+		Amount:        t.Amount,
+		ToCurrency:    t.ToCurrency,
+		ToAmount:      t.ToAmount,
+		Reference:     t.Reference,
+		Timestamp:     t.CreatedAt,
+	}
+	if t.ReversalOf != nil {
+		reversalOf := t.ReversalOf.String()
+		entry.ReversalOf = &reversalOf
+	}
+	return entry
+}
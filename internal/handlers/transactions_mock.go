@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/transactions.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockTransactionsTokener is a mock of TransactionsTokener interface.
+type MockTransactionsTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionsTokenerMockRecorder
+}
+
+// MockTransactionsTokenerMockRecorder is the mock recorder for MockTransactionsTokener.
+type MockTransactionsTokenerMockRecorder struct {
+	mock *MockTransactionsTokener
+}
+
+// NewMockTransactionsTokener creates a new mock instance.
+func NewMockTransactionsTokener(ctrl *gomock.Controller) *MockTransactionsTokener {
+	mock := &MockTransactionsTokener{ctrl: ctrl}
+	mock.recorder = &MockTransactionsTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionsTokener) EXPECT() *MockTransactionsTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockTransactionsTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockTransactionsTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockTransactionsTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockTransactionsTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockTransactionsTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockTransactionsTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockTransactionLister is a mock of TransactionLister interface.
+type MockTransactionLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionListerMockRecorder
+}
+
+// MockTransactionListerMockRecorder is the mock recorder for MockTransactionLister.
+type MockTransactionListerMockRecorder struct {
+	mock *MockTransactionLister
+}
+
+// NewMockTransactionLister creates a new mock instance.
+func NewMockTransactionLister(ctrl *gomock.Controller) *MockTransactionLister {
+	mock := &MockTransactionLister{ctrl: ctrl}
+	mock.recorder = &MockTransactionListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionLister) EXPECT() *MockTransactionListerMockRecorder {
+	return m.recorder
+}
+
+// ListTransactions mocks base method.
+func (m *MockTransactionLister) ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, filter, cursor)
+	ret0, _ := ret[0].([]models.TransactionDB)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockTransactionListerMockRecorder) ListTransactions(ctx, filter, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockTransactionLister)(nil).ListTransactions), ctx, filter, cursor)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransactionsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockTransactionsTokener(ctrl)
+	mockSvc := NewMockTransactionLister(ctrl)
+
+	userID := uuid.New()
+	txnID := uuid.New()
+	at := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+	eur := models.EUR
+	toAmount := money.MustParse("46")
+
+	handler := NewGetTransactionsHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "success_with_filters",
+			query: "?from=2025-03-01T00:00:00Z&to=2025-04-01T00:00:00Z&currency=USD&operation=exchange&limit=1&cursor=abc",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListTransactions(gomock.Any(), models.TransactionFilter{
+						UserID:    userID,
+						From:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+						To:        time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+						Currency:  models.USD,
+						Operation: models.OperationExchange,
+						Limit:     1,
+					}, "abc").
+					Return([]models.TransactionDB{
+						{ID: 7, TransactionID: txnID, UserID: userID, Operation: models.OperationExchange, Currency: models.USD, Amount: money.MustParse("50"), ToCurrency: &eur, ToAmount: &toAmount, CreatedAt: at},
+					}, "next", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: TransactionsResponse{
+				Transactions: []TransactionEntry{
+					{TransactionID: txnID.String(), Operation: models.OperationExchange, Currency: models.USD, Amount: money.MustParse("50"), ToCurrency: &eur, ToAmount: &toAmount, Timestamp: at},
+				},
+				NextCursor: "next",
+			},
+		},
+		{
+			name: "empty_history",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListTransactions(gomock.Any(), models.TransactionFilter{UserID: userID}, "").
+					Return(nil, "", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   TransactionsResponse{Transactions: []TransactionEntry{}},
+		},
+		{
+			name:           "invalid_from",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid from", Code: "invalid_from"},
+		},
+		{
+			name:           "invalid_date_range",
+			query:          "?from=2025-04-01T00:00:00Z&to=2025-03-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid date range", Code: "invalid_date_range"},
+		},
+		{
+			name:           "invalid_currency",
+			query:          "?currency=GBP",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "invalid_operation",
+			query:          "?operation=transfer",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid operation", Code: "invalid_operation"},
+		},
+		{
+			name:           "invalid_limit",
+			query:          "?limit=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid limit", Code: "invalid_limit"},
+		},
+		{
+			name:  "invalid_cursor",
+			query: "?cursor=bogus",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListTransactions(gomock.Any(), gomock.Any(), "bogus").
+					Return(nil, "", services.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid cursor", Code: "invalid_cursor"},
+		},
+		{
+			name: "internal_error",
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					ListTransactions(gomock.Any(), gomock.Any(), "").
+					Return(nil, "", errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/wallet/transactions"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case TransactionsResponse:
+				var got TransactionsResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestGetTransactionsHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockTransactionsTokener(ctrl)
+	mockSvc := NewMockTransactionLister(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Return("", errors.New("missing token"))
+
+	handler := NewGetTransactionsHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/wallet/transactions", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+}
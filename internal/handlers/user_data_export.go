@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// UserDataExporter defines the interface that the service must implement.
+type UserDataExporter interface {
+	RequestExport(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error)
+	GetLatestExport(ctx context.Context, userID uuid.UUID, format string) (*models.ExportDB, error)
+}
+
+// NewCreateUserDataExportHandler returns an HTTP handler that queues an export of all data stored about the user.
+// @Summary Request user data export
+// @Description Queues a GDPR export of the user's data: a ZIP of JSON files with the profile, wallet balances, all transactions and the login history. The archive is generated in the background; poll GET /me/export for the result.
+// @Tags account
+// @Produce json
+// @Success 202 {object} handlers.ExportStatusResponse "Export queued"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /me/export [post]
+// @Security BearerAuth
+func NewCreateUserDataExportHandler(svc UserDataExporter, tokenGetter ExportTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		exportID, err := svc.RequestExport(ctx, claims.UserID, models.ExportFormatUserDataZIP)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to request user data export", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(ExportStatusResponse{
+			ExportID: exportID.String(),
+			Status:   models.ExportStatusPending,
+		})
+	}
+}
+
+// NewGetUserDataExportHandler returns an HTTP handler that reports the latest user data export or downloads it.
+// @Summary Get user data export
+// @Description Returns the status of the latest user data export. Once it is completed the ZIP archive is returned as an attachment.
+// @Tags account
+// @Produce json
+// @Produce application/zip
+// @Success 200 {object} handlers.ExportStatusResponse "Export status, or the archive when completed"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Export not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /me/export [get]
+// @Security BearerAuth
+func NewGetUserDataExportHandler(svc UserDataExporter, tokenGetter ExportTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		export, err := svc.GetLatestExport(ctx, claims.UserID, models.ExportFormatUserDataZIP)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrExportNotFound):
+				apperrors.Write(w, apperrors.ExportNotFound)
+			default:
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		writeExport(w, export)
+	}
+}
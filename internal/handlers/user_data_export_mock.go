@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/user_data_export.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockUserDataExporter is a mock of UserDataExporter interface.
+type MockUserDataExporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserDataExporterMockRecorder
+}
+
+// MockUserDataExporterMockRecorder is the mock recorder for MockUserDataExporter.
+type MockUserDataExporterMockRecorder struct {
+	mock *MockUserDataExporter
+}
+
+// NewMockUserDataExporter creates a new mock instance.
+func NewMockUserDataExporter(ctrl *gomock.Controller) *MockUserDataExporter {
+	mock := &MockUserDataExporter{ctrl: ctrl}
+	mock.recorder = &MockUserDataExporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserDataExporter) EXPECT() *MockUserDataExporterMockRecorder {
+	return m.recorder
+}
+
+// GetLatestExport mocks base method.
+func (m *MockUserDataExporter) GetLatestExport(ctx context.Context, userID uuid.UUID, format string) (*models.ExportDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestExport", ctx, userID, format)
+	ret0, _ := ret[0].(*models.ExportDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestExport indicates an expected call of GetLatestExport.
+func (mr *MockUserDataExporterMockRecorder) GetLatestExport(ctx, userID, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestExport", reflect.TypeOf((*MockUserDataExporter)(nil).GetLatestExport), ctx, userID, format)
+}
+
+// RequestExport mocks base method.
+func (m *MockUserDataExporter) RequestExport(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", ctx, userID, format)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockUserDataExporterMockRecorder) RequestExport(ctx, userID, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockUserDataExporter)(nil).RequestExport), ctx, userID, format)
+}
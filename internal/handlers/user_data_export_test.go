@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUserDataExportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExportTokener(ctrl)
+	mockSvc := NewMockUserDataExporter(ctrl)
+
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	handler := NewCreateUserDataExportHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	t.Run("queued", func(t *testing.T) {
+		mockSvc.EXPECT().RequestExport(gomock.Any(), userID, models.ExportFormatUserDataZIP).Return(exportID, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/me/export", nil))
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		var got ExportStatusResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, ExportStatusResponse{ExportID: exportID.String(), Status: models.ExportStatusPending}, got)
+	})
+
+	t.Run("internal_error", func(t *testing.T) {
+		mockSvc.EXPECT().RequestExport(gomock.Any(), userID, models.ExportFormatUserDataZIP).Return(uuid.Nil, errors.New("db error"))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/me/export", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"error":"Internal server error","code":"internal"}`, rec.Body.String())
+	})
+}
+
+func TestGetUserDataExportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockExportTokener(ctrl)
+	mockSvc := NewMockUserDataExporter(ctrl)
+
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	handler := NewGetUserDataExportHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name                string
+		export              *models.ExportDB
+		err                 error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "processing",
+			export:              &models.ExportDB{ExportID: exportID, Format: models.ExportFormatUserDataZIP, Status: models.ExportStatusProcessing},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `{"export_id":"` + exportID.String() + `","status":"processing"}`,
+		},
+		{
+			name:                "completed",
+			export:              &models.ExportDB{ExportID: exportID, Format: models.ExportFormatUserDataZIP, Status: models.ExportStatusCompleted, Content: []byte("PK")},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/zip",
+			expectedBody:        "PK",
+		},
+		{
+			name:           "not_found",
+			err:            services.ErrExportNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Export not found","code":"export_not_found"}`,
+		},
+		{
+			name:           "internal_error",
+			err:            errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"Internal server error","code":"internal"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc.EXPECT().GetLatestExport(gomock.Any(), userID, models.ExportFormatUserDataZIP).Return(tt.export, tt.err)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me/export", nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedContentType != "application/zip" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+				return
+			}
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="`+exportID.String()+`.zip"`, rec.Header().Get("Content-Disposition"))
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
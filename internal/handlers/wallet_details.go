@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// WalletDetailsTokener defines only the methods needed by this handler.
+type WalletDetailsTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// WalletDetailsUpdater defines the interface that the service must implement.
+type WalletDetailsUpdater interface {
+	UpdateWalletDetails(ctx context.Context, userID uuid.UUID, currency string, label *string, metadata map[string]string) (models.WalletDetails, error)
+}
+
+// WalletDetailsEntry represents the label and metadata of a wallet
+// swagger:model WalletDetailsEntry
+type WalletDetailsEntry struct {
+	// Display label of the wallet
+	// default: travel fund
+	Label string `json:"label,omitempty"`
+
+	// Key/value metadata of the wallet
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// UpdateWalletDetailsRequest represents the JSON body for editing a wallet.
+// Omitted fields are left as they are.
+// swagger:model UpdateWalletDetailsRequest
+type UpdateWalletDetailsRequest struct {
+	// Display label, at most 64 characters; an empty string removes it
+	// default: travel fund
+	Label *string `json:"label"`
+
+	// Metadata replacing the stored one, at most 20 keys of up to 40 characters
+	// with values of up to 256 characters; an empty object removes it
+	Metadata map[string]string `json:"metadata"`
+}
+
+// WalletDetailsResponse represents the wallet details after the change
+// swagger:model WalletDetailsResponse
+type WalletDetailsResponse struct {
+	// Currency of the wallet
+	// default: USD
+	Currency string `json:"currency"`
+
+	WalletDetailsEntry
+}
+
+// NewUpdateWalletDetailsHandler returns an HTTP handler editing the label and metadata of a wallet.
+// @Summary Update wallet label and metadata
+// @Description Sets the display label and key/value metadata of the user's wallet in a currency. Omitted fields are left as they are; metadata replaces the stored object as a whole. The details are returned with the balance.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param currency path string true "Supported currency code"
+// @Param request body handlers.UpdateWalletDetailsRequest true "Wallet details"
+// @Success 200 {object} handlers.WalletDetailsResponse "Wallet details after the change"
+// @Failure 400 {object} apperrors.Response "Invalid currency, request or wallet details"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Wallet not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /wallet/{currency} [patch]
+// @Security BearerAuth
+func NewUpdateWalletDetailsHandler(
+	svc WalletDetailsUpdater,
+	tokenGetter WalletDetailsTokener,
+	currencies CurrencyChecker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
+			return
+		}
+
+		currency := chi.URLParam(r, "currency")
+		if !currencies.IsSupported(ctx, currency) {
+			logger.FromContext(ctx).Warnw("invalid wallet details currency", "currency", currency)
+			apperrors.Write(w, apperrors.InvalidCurrency)
+			return
+		}
+
+		var req UpdateWalletDetailsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.FromContext(ctx).Warnw("invalid wallet details request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		details, err := svc.UpdateWalletDetails(ctx, claims.UserID, currency, req.Label, req.Metadata)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidWalletDetails):
+				apperrors.Write(w, apperrors.InvalidWalletDetails)
+			case errors.Is(err, services.ErrWalletNotFound):
+				apperrors.Write(w, apperrors.WalletNotFound)
+			default:
+				logger.FromContext(ctx).Errorw("failed to update wallet details", "userID", claims.UserID, "currency", currency, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(WalletDetailsResponse{
+			Currency:           currency,
+			WalletDetailsEntry: WalletDetailsEntry(details),
+		})
+	}
+}
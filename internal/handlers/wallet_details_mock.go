@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/wallet_details.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockWalletDetailsTokener is a mock of WalletDetailsTokener interface.
+type MockWalletDetailsTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletDetailsTokenerMockRecorder
+}
+
+// MockWalletDetailsTokenerMockRecorder is the mock recorder for MockWalletDetailsTokener.
+type MockWalletDetailsTokenerMockRecorder struct {
+	mock *MockWalletDetailsTokener
+}
+
+// NewMockWalletDetailsTokener creates a new mock instance.
+func NewMockWalletDetailsTokener(ctrl *gomock.Controller) *MockWalletDetailsTokener {
+	mock := &MockWalletDetailsTokener{ctrl: ctrl}
+	mock.recorder = &MockWalletDetailsTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletDetailsTokener) EXPECT() *MockWalletDetailsTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockWalletDetailsTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockWalletDetailsTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockWalletDetailsTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockWalletDetailsTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockWalletDetailsTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockWalletDetailsTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockWalletDetailsUpdater is a mock of WalletDetailsUpdater interface.
+type MockWalletDetailsUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletDetailsUpdaterMockRecorder
+}
+
+// MockWalletDetailsUpdaterMockRecorder is the mock recorder for MockWalletDetailsUpdater.
+type MockWalletDetailsUpdaterMockRecorder struct {
+	mock *MockWalletDetailsUpdater
+}
+
+// NewMockWalletDetailsUpdater creates a new mock instance.
+func NewMockWalletDetailsUpdater(ctrl *gomock.Controller) *MockWalletDetailsUpdater {
+	mock := &MockWalletDetailsUpdater{ctrl: ctrl}
+	mock.recorder = &MockWalletDetailsUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletDetailsUpdater) EXPECT() *MockWalletDetailsUpdaterMockRecorder {
+	return m.recorder
+}
+
+// UpdateWalletDetails mocks base method.
+func (m *MockWalletDetailsUpdater) UpdateWalletDetails(ctx context.Context, userID uuid.UUID, currency string, label *string, metadata map[string]string) (models.WalletDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWalletDetails", ctx, userID, currency, label, metadata)
+	ret0, _ := ret[0].(models.WalletDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWalletDetails indicates an expected call of UpdateWalletDetails.
+func (mr *MockWalletDetailsUpdaterMockRecorder) UpdateWalletDetails(ctx, userID, currency, label, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWalletDetails", reflect.TypeOf((*MockWalletDetailsUpdater)(nil).UpdateWalletDetails), ctx, userID, currency, label, metadata)
+}
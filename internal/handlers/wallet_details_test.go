@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateWalletDetailsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWalletDetailsTokener(ctrl)
+	mockSvc := NewMockWalletDetailsUpdater(ctrl)
+
+	userID := uuid.New()
+	label := "travel fund"
+
+	handler := NewUpdateWalletDetailsHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		currency       string
+		body           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:     "success",
+			currency: models.USD,
+			body:     `{"label":"travel fund","metadata":{"trip":"japan"}}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					UpdateWalletDetails(gomock.Any(), userID, models.USD, &label, map[string]string{"trip": "japan"}).
+					Return(models.WalletDetails{Label: label, Metadata: map[string]string{"trip": "japan"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: WalletDetailsResponse{
+				Currency:           models.USD,
+				WalletDetailsEntry: WalletDetailsEntry{Label: label, Metadata: map[string]string{"trip": "japan"}},
+			},
+		},
+		{
+			name:     "omitted fields are passed as nil",
+			currency: models.EUR,
+			body:     `{}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					UpdateWalletDetails(gomock.Any(), userID, models.EUR, nil, nil).
+					Return(models.WalletDetails{Label: label}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   WalletDetailsResponse{Currency: models.EUR, WalletDetailsEntry: WalletDetailsEntry{Label: label}},
+		},
+		{
+			name:           "unsupported currency",
+			currency:       "BTC",
+			body:           `{"label":"travel fund"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "invalid body",
+			currency:       models.USD,
+			body:           `{"metadata":{"trip":1}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:     "invalid details",
+			currency: models.USD,
+			body:     `{"metadata":{"":"japan"}}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					UpdateWalletDetails(gomock.Any(), userID, models.USD, nil, map[string]string{"": "japan"}).
+					Return(models.WalletDetails{}, services.ErrInvalidWalletDetails)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid wallet details", Code: "invalid_wallet_details"},
+		},
+		{
+			name:     "wallet not found",
+			currency: models.RUB,
+			body:     `{"label":"travel fund"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					UpdateWalletDetails(gomock.Any(), userID, models.RUB, &label, nil).
+					Return(models.WalletDetails{}, services.ErrWalletNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Wallet not found", Code: "wallet_not_found"},
+		},
+		{
+			name:     "internal error",
+			currency: models.USD,
+			body:     `{"label":"travel fund"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					UpdateWalletDetails(gomock.Any(), userID, models.USD, &label, nil).
+					Return(models.WalletDetails{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newWalletDetailsRequest(tt.currency, tt.body))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			switch expected := tt.expectedBody.(type) {
+			case WalletDetailsResponse:
+				var got WalletDetailsResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestUpdateWalletDetailsHandler_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWalletDetailsTokener(ctrl)
+	mockTokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+
+	handler := NewUpdateWalletDetailsHandler(NewMockWalletDetailsUpdater(ctrl), mockTokener, newMockCurrencies(ctrl))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newWalletDetailsRequest(models.USD, `{"label":"travel fund"}`))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func newWalletDetailsRequest(currency, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPatch, "/wallet/"+currency, bytes.NewBufferString(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("currency", currency)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// WalletLimitsTokener defines only the methods needed by the wallet limit handlers.
+type WalletLimitsTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// WalletLimitManager defines the interface for reading and changing a user's wallet limits.
+type WalletLimitManager interface {
+	GetLimits(ctx context.Context, userID uuid.UUID) ([]models.WalletLimitDB, error)
+	SetLimit(ctx context.Context, adminID uuid.UUID, limit models.WalletLimitDB) error
+	SetOverdraft(ctx context.Context, adminID, userID uuid.UUID, currency string, limit money.Amount) error
+}
+
+// WalletLimitEntry represents the limits of a user in one currency
+// swagger:model WalletLimitEntry
+type WalletLimitEntry struct {
+	// Currency code
+	// default: USD
+	Currency string `json:"currency"`
+
+	// Limit over the last 24 hours, null means unlimited
+	// default: 1000.0
+	DailyLimit *money.Amount `json:"daily_limit" swaggertype:"number"`
+
+	// Limit over the last 30 days, null means unlimited
+	// default: 10000.0
+	MonthlyLimit *money.Amount `json:"monthly_limit" swaggertype:"number"`
+
+	// Withdrawn and exchanged over the last 24 hours
+	// default: 250.0
+	DailyUsed money.Amount `json:"daily_used" swaggertype:"number"`
+
+	// Withdrawn and exchanged over the last 30 days
+	// default: 4000.0
+	MonthlyUsed money.Amount `json:"monthly_used" swaggertype:"number"`
+
+	// Time of the last limit change
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WalletLimitsResponse represents the limits of a user
+// swagger:model WalletLimitsResponse
+type WalletLimitsResponse struct {
+	// Limits per currency
+	Limits []WalletLimitEntry `json:"limits"`
+}
+
+// SetWalletLimitRequest represents the limits to set in one currency
+// swagger:model SetWalletLimitRequest
+type SetWalletLimitRequest struct {
+	// Limit over the last 24 hours, null removes it
+	// default: 1000.0
+	DailyLimit *money.Amount `json:"daily_limit" swaggertype:"number"`
+
+	// Limit over the last 30 days, null removes it
+	// default: 10000.0
+	MonthlyLimit *money.Amount `json:"monthly_limit" swaggertype:"number"`
+}
+
+// SetOverdraftRequest represents the overdraft limit to set on a wallet
+// swagger:model SetOverdraftRequest
+type SetOverdraftRequest struct {
+	// How far below zero withdrawals may take the balance, 0 removes the overdraft
+	// required: true
+	// default: 500.0
+	OverdraftLimit *money.Amount `json:"overdraft_limit" swaggertype:"number"`
+}
+
+// OverdraftResponse represents the overdraft limit of a wallet
+// swagger:model OverdraftResponse
+type OverdraftResponse struct {
+	// Currency code
+	// default: USD
+	Currency string `json:"currency"`
+
+	// How far below zero withdrawals may take the balance
+	// default: 500.0
+	OverdraftLimit money.Amount `json:"overdraft_limit" swaggertype:"number"`
+}
+
+// NewGetWalletLimitsHandler returns an HTTP handler that lets an admin view a user's limits.
+// @Summary Get wallet limits of a user
+// @Description Returns the daily and monthly withdrawal and exchange limits of the user with their current usage.
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User ID"
+// @Success 200 {object} handlers.WalletLimitsResponse "User limits"
+// @Failure 400 {object} apperrors.Response "Invalid user ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "User not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/users/{userID}/limits [get]
+// @Security BearerAuth
+func NewGetWalletLimitsHandler(svc WalletLimitManager, tokenGetter WalletLimitsTokener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := walletLimitsClaims(w, r, tokenGetter); !ok {
+			return
+		}
+
+		userID, ok := walletLimitsUserID(w, r)
+		if !ok {
+			return
+		}
+
+		writeWalletLimits(w, r, svc, userID)
+	}
+}
+
+// NewSetWalletLimitHandler returns an HTTP handler that lets an admin set a user's limits in a currency.
+// @Summary Set wallet limits of a user
+// @Description Sets the daily and monthly withdrawal and exchange limits of the user in a currency. A null limit is removed. The change is recorded in the audit trail.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param currency path string true "Supported currency code"
+// @Param request body handlers.SetWalletLimitRequest true "Limits"
+// @Success 200 {object} handlers.WalletLimitsResponse "User limits after the change"
+// @Failure 400 {object} apperrors.Response "Invalid user ID, currency or limit"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "User not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/users/{userID}/limits/{currency} [put]
+// @Security BearerAuth
+func NewSetWalletLimitHandler(svc WalletLimitManager, tokenGetter WalletLimitsTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := walletLimitsClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		userID, ok := walletLimitsUserID(w, r)
+		if !ok {
+			return
+		}
+
+		currency := chi.URLParam(r, "currency")
+		if !currencies.IsSupported(ctx, currency) {
+			apperrors.Write(w, apperrors.InvalidCurrency)
+			return
+		}
+
+		var req SetWalletLimitRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		err := svc.SetLimit(ctx, claims.UserID, models.WalletLimitDB{
+			UserID:       userID,
+			Currency:     currency,
+			DailyLimit:   req.DailyLimit,
+			MonthlyLimit: req.MonthlyLimit,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidLimit):
+				apperrors.Write(w, apperrors.InvalidLimit)
+			case errors.Is(err, services.ErrUserDoesNotExist):
+				apperrors.Write(w, apperrors.UserNotFound)
+			default:
+				logger.FromContext(ctx).Errorw("failed to set wallet limit", "adminID", claims.UserID, "userID", userID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		writeWalletLimits(w, r, svc, userID)
+	}
+}
+
+// NewSetOverdraftHandler returns an HTTP handler that lets an admin set the overdraft limit of a user's wallet.
+// @Summary Set wallet overdraft limit of a user
+// @Description Sets how far below zero withdrawals may take the balance of the user's wallet in a currency. Zero removes the overdraft; an overdrawn wallet stays so until it is topped up. Exchanges and holds use only the own funds. The change is recorded in the audit trail.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID"
+// @Param currency path string true "Supported currency code"
+// @Param request body handlers.SetOverdraftRequest true "Overdraft limit"
+// @Success 200 {object} handlers.OverdraftResponse "Overdraft limit after the change"
+// @Failure 400 {object} apperrors.Response "Invalid user ID, currency or limit"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "User or wallet not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/users/{userID}/wallets/{currency}/overdraft [put]
+// @Security BearerAuth
+func NewSetOverdraftHandler(svc WalletLimitManager, tokenGetter WalletLimitsTokener, currencies CurrencyChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		claims, ok := walletLimitsClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		userID, ok := walletLimitsUserID(w, r)
+		if !ok {
+			return
+		}
+
+		currency := chi.URLParam(r, "currency")
+		if !currencies.IsSupported(ctx, currency) {
+			apperrors.Write(w, apperrors.InvalidCurrency)
+			return
+		}
+
+		var req SetOverdraftRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.OverdraftLimit == nil {
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		if err := svc.SetOverdraft(ctx, claims.UserID, userID, currency, *req.OverdraftLimit); err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidLimit):
+				apperrors.Write(w, apperrors.InvalidLimit)
+			case errors.Is(err, services.ErrUserDoesNotExist):
+				apperrors.Write(w, apperrors.UserNotFound)
+			case errors.Is(err, services.ErrWalletNotFound):
+				apperrors.Write(w, apperrors.WalletNotFound)
+			default:
+				logger.FromContext(ctx).Errorw("failed to set overdraft limit", "adminID", claims.UserID, "userID", userID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(OverdraftResponse{Currency: currency, OverdraftLimit: *req.OverdraftLimit})
+	}
+}
+
+// writeWalletLimits responds with the current limits of the user.
+func writeWalletLimits(w http.ResponseWriter, r *http.Request, svc WalletLimitManager, userID uuid.UUID) {
+	limits, err := svc.GetLimits(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserDoesNotExist) {
+			apperrors.Write(w, apperrors.UserNotFound)
+			return
+		}
+		logger.FromContext(r.Context()).Errorw("failed to get wallet limits", "userID", userID, "error", err)
+		apperrors.Write(w, apperrors.Internal)
+		return
+	}
+
+	resp := WalletLimitsResponse{Limits: make([]WalletLimitEntry, 0, len(limits))}
+	for _, l := range limits {
+		resp.Limits = append(resp.Limits, WalletLimitEntry{
+			Currency:     l.Currency,
+			DailyLimit:   l.DailyLimit,
+			MonthlyLimit: l.MonthlyLimit,
+			DailyUsed:    l.DailyUsed,
+			MonthlyUsed:  l.MonthlyUsed,
+			UpdatedAt:    l.UpdatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// walletLimitsClaims authenticates the request, writing 401 on failure.
+func walletLimitsClaims(w http.ResponseWriter, r *http.Request, tokenGetter WalletLimitsTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// walletLimitsUserID parses the target user from the path, writing 400 on failure.
+func walletLimitsUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		logger.FromContext(r.Context()).Warnw("invalid wallet limits user ID", "userID", chi.URLParam(r, "userID"), "error", err)
+		apperrors.Write(w, apperrors.InvalidUserID)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/wallet_limit.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockWalletLimitsTokener is a mock of WalletLimitsTokener interface.
+type MockWalletLimitsTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletLimitsTokenerMockRecorder
+}
+
+// MockWalletLimitsTokenerMockRecorder is the mock recorder for MockWalletLimitsTokener.
+type MockWalletLimitsTokenerMockRecorder struct {
+	mock *MockWalletLimitsTokener
+}
+
+// NewMockWalletLimitsTokener creates a new mock instance.
+func NewMockWalletLimitsTokener(ctrl *gomock.Controller) *MockWalletLimitsTokener {
+	mock := &MockWalletLimitsTokener{ctrl: ctrl}
+	mock.recorder = &MockWalletLimitsTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletLimitsTokener) EXPECT() *MockWalletLimitsTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockWalletLimitsTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockWalletLimitsTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockWalletLimitsTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockWalletLimitsTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockWalletLimitsTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockWalletLimitsTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockWalletLimitManager is a mock of WalletLimitManager interface.
+type MockWalletLimitManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletLimitManagerMockRecorder
+}
+
+// MockWalletLimitManagerMockRecorder is the mock recorder for MockWalletLimitManager.
+type MockWalletLimitManagerMockRecorder struct {
+	mock *MockWalletLimitManager
+}
+
+// NewMockWalletLimitManager creates a new mock instance.
+func NewMockWalletLimitManager(ctrl *gomock.Controller) *MockWalletLimitManager {
+	mock := &MockWalletLimitManager{ctrl: ctrl}
+	mock.recorder = &MockWalletLimitManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletLimitManager) EXPECT() *MockWalletLimitManagerMockRecorder {
+	return m.recorder
+}
+
+// GetLimits mocks base method.
+func (m *MockWalletLimitManager) GetLimits(ctx context.Context, userID uuid.UUID) ([]models.WalletLimitDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, userID)
+	ret0, _ := ret[0].([]models.WalletLimitDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockWalletLimitManagerMockRecorder) GetLimits(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockWalletLimitManager)(nil).GetLimits), ctx, userID)
+}
+
+// SetLimit mocks base method.
+func (m *MockWalletLimitManager) SetLimit(ctx context.Context, adminID uuid.UUID, limit models.WalletLimitDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimit", ctx, adminID, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MockWalletLimitManagerMockRecorder) SetLimit(ctx, adminID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockWalletLimitManager)(nil).SetLimit), ctx, adminID, limit)
+}
+
+// SetOverdraft mocks base method.
+func (m *MockWalletLimitManager) SetOverdraft(ctx context.Context, adminID, userID uuid.UUID, currency string, limit money.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraft", ctx, adminID, userID, currency, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverdraft indicates an expected call of SetOverdraft.
+func (mr *MockWalletLimitManagerMockRecorder) SetOverdraft(ctx, adminID, userID, currency, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraft", reflect.TypeOf((*MockWalletLimitManager)(nil).SetOverdraft), ctx, adminID, userID, currency, limit)
+}
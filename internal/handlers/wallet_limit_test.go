@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWalletLimitsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWalletLimitsTokener(ctrl)
+	mockSvc := NewMockWalletLimitManager(ctrl)
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	daily := money.MustParse("1000")
+
+	handler := NewGetWalletLimitsHandler(mockSvc, mockTokener)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:   "success",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().GetLimits(gomock.Any(), userID).Return([]models.WalletLimitDB{
+					{UserID: userID, Currency: models.USD, DailyLimit: &daily, DailyUsed: money.MustParse("250"), MonthlyUsed: money.MustParse("4000")},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: WalletLimitsResponse{Limits: []WalletLimitEntry{
+				{Currency: models.USD, DailyLimit: &daily, DailyUsed: money.MustParse("250"), MonthlyUsed: money.MustParse("4000")},
+			}},
+		},
+		{
+			name:   "no_limits",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().GetLimits(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   WalletLimitsResponse{Limits: []WalletLimitEntry{}},
+		},
+		{
+			name:           "invalid_user_id",
+			userID:         "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid user ID", Code: "invalid_user_id"},
+		},
+		{
+			name:   "user_not_found",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().GetLimits(gomock.Any(), userID).Return(nil, services.ErrUserDoesNotExist)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "User not found", Code: "user_not_found"},
+		},
+		{
+			name:   "internal_error",
+			userID: userID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().GetLimits(gomock.Any(), userID).Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := newWalletLimitsRequest(http.MethodGet, tt.userID, "", "")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertWalletLimitsBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestSetWalletLimitHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWalletLimitsTokener(ctrl)
+	mockSvc := NewMockWalletLimitManager(ctrl)
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	daily := money.MustParse("100")
+	monthly := money.MustParse("1000")
+
+	handler := NewSetWalletLimitHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	tests := []struct {
+		name           string
+		userID         string
+		currency       string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:     "success",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"daily_limit":100,"monthly_limit":1000}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetLimit(gomock.Any(), adminID, models.WalletLimitDB{
+					UserID: userID, Currency: models.USD, DailyLimit: &daily, MonthlyLimit: &monthly,
+				}).Return(nil)
+				mockSvc.EXPECT().GetLimits(gomock.Any(), userID).Return([]models.WalletLimitDB{
+					{UserID: userID, Currency: models.USD, DailyLimit: &daily, MonthlyLimit: &monthly},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: WalletLimitsResponse{Limits: []WalletLimitEntry{
+				{Currency: models.USD, DailyLimit: &daily, MonthlyLimit: &monthly},
+			}},
+		},
+		{
+			name:     "remove_limits",
+			userID:   userID.String(),
+			currency: models.EUR,
+			reqBody:  `{"daily_limit":null,"monthly_limit":null}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetLimit(gomock.Any(), adminID, models.WalletLimitDB{UserID: userID, Currency: models.EUR}).Return(nil)
+				mockSvc.EXPECT().GetLimits(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   WalletLimitsResponse{Limits: []WalletLimitEntry{}},
+		},
+		{
+			name:           "invalid_user_id",
+			userID:         "not-a-uuid",
+			currency:       models.USD,
+			reqBody:        `{"daily_limit":100}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid user ID", Code: "invalid_user_id"},
+		},
+		{
+			name:           "invalid_currency",
+			userID:         userID.String(),
+			currency:       "BTC",
+			reqBody:        `{"daily_limit":100}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "invalid_json",
+			userID:         userID.String(),
+			currency:       models.USD,
+			reqBody:        `invalid-json`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:     "invalid_limit",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"daily_limit":1000,"monthly_limit":100}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetLimit(gomock.Any(), adminID, gomock.Any()).Return(services.ErrInvalidLimit)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid limit", Code: "invalid_limit"},
+		},
+		{
+			name:     "user_not_found",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"daily_limit":100}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetLimit(gomock.Any(), adminID, gomock.Any()).Return(services.ErrUserDoesNotExist)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "User not found", Code: "user_not_found"},
+		},
+		{
+			name:     "internal_error",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"daily_limit":100}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetLimit(gomock.Any(), adminID, gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := newWalletLimitsRequest(http.MethodPut, tt.userID, tt.currency, tt.reqBody)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertWalletLimitsBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestSetOverdraftHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWalletLimitsTokener(ctrl)
+	mockSvc := NewMockWalletLimitManager(ctrl)
+
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	handler := NewSetOverdraftHandler(mockSvc, mockTokener, newMockCurrencies(ctrl))
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("admin-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "admin-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: adminID, Role: "admin"}, nil)
+
+	tests := []struct {
+		name           string
+		userID         string
+		currency       string
+		reqBody        string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:     "success",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"overdraft_limit":500}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetOverdraft(gomock.Any(), adminID, userID, models.USD, money.MustParse("500")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   OverdraftResponse{Currency: models.USD, OverdraftLimit: money.MustParse("500")},
+		},
+		{
+			name:           "invalid_user_id",
+			userID:         "not-a-uuid",
+			currency:       models.USD,
+			reqBody:        `{"overdraft_limit":500}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid user ID", Code: "invalid_user_id"},
+		},
+		{
+			name:           "invalid_currency",
+			userID:         userID.String(),
+			currency:       "BTC",
+			reqBody:        `{"overdraft_limit":500}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid currency", Code: "invalid_currency"},
+		},
+		{
+			name:           "missing_limit",
+			userID:         userID.String(),
+			currency:       models.USD,
+			reqBody:        `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:     "invalid_limit",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"overdraft_limit":-1}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetOverdraft(gomock.Any(), adminID, userID, models.USD, money.MustParse("-1")).Return(services.ErrInvalidLimit)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid limit", Code: "invalid_limit"},
+		},
+		{
+			name:     "user_not_found",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"overdraft_limit":500}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetOverdraft(gomock.Any(), adminID, userID, models.USD, gomock.Any()).Return(services.ErrUserDoesNotExist)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "User not found", Code: "user_not_found"},
+		},
+		{
+			name:     "wallet_not_found",
+			userID:   userID.String(),
+			currency: models.EUR,
+			reqBody:  `{"overdraft_limit":500}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetOverdraft(gomock.Any(), adminID, userID, models.EUR, gomock.Any()).Return(services.ErrWalletNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   apperrors.Response{Error: "Wallet not found", Code: "wallet_not_found"},
+		},
+		{
+			name:     "internal_error",
+			userID:   userID.String(),
+			currency: models.USD,
+			reqBody:  `{"overdraft_limit":500}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().SetOverdraft(gomock.Any(), adminID, userID, models.USD, gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := newWalletLimitsRequest(http.MethodPut, tt.userID, tt.currency, tt.reqBody)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			assertWalletLimitsBody(t, rec.Body.Bytes(), tt.expectedBody)
+		})
+	}
+}
+
+func TestWalletLimitHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWalletLimitsTokener(ctrl)
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("", errors.New("no token"))
+
+	handlers := map[string]http.HandlerFunc{
+		"get":       NewGetWalletLimitsHandler(NewMockWalletLimitManager(ctrl), mockTokener),
+		"set":       NewSetWalletLimitHandler(NewMockWalletLimitManager(ctrl), mockTokener, newMockCurrencies(ctrl)),
+		"overdraft": NewSetOverdraftHandler(NewMockWalletLimitManager(ctrl), mockTokener, newMockCurrencies(ctrl)),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assertWalletLimitsBody(t, rec.Body.Bytes(), apperrors.Response{Error: "Unauthorized", Code: "unauthorized"})
+		})
+	}
+}
+
+func newWalletLimitsRequest(method, userID, currency, body string) *http.Request {
+	req := httptest.NewRequest(method, "/admin/users/"+userID+"/limits/"+currency, bytes.NewBufferString(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("userID", userID)
+	rctx.URLParams.Add("currency", currency)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func assertWalletLimitsBody(t *testing.T, body []byte, expectedBody interface{}) {
+	t.Helper()
+	switch expected := expectedBody.(type) {
+	case WalletLimitsResponse:
+		var got WalletLimitsResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	case OverdraftResponse:
+		var got OverdraftResponse
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	case apperrors.Response:
+		var got apperrors.Response
+		assert.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, expected, got)
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+)
+
+// WebhookTokener defines only the methods needed by the webhook handlers.
+type WebhookTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// WebhookManager defines the interface for registering, listing and removing webhooks.
+// A nil owner stands for the admin webhooks receiving events of all users.
+type WebhookManager interface {
+	Create(ctx context.Context, ownerID *uuid.UUID, url string) (models.WebhookDB, error)
+	List(ctx context.Context, ownerID *uuid.UUID) ([]models.WebhookDB, error)
+	Delete(ctx context.Context, ownerID *uuid.UUID, webhookID uuid.UUID) error
+}
+
+// CreateWebhookRequest represents the JSON body for registering a webhook
+// swagger:model CreateWebhookRequest
+type CreateWebhookRequest struct {
+	// Callback URL receiving wallet events as signed POST requests
+	// required: true
+	// default: https://example.com/webhooks/wallet
+	URL string `json:"url"`
+}
+
+// WebhookResponse represents a registered webhook
+// swagger:model WebhookResponse
+type WebhookResponse struct {
+	// Webhook ID
+	// default: 3fa85f64-5717-4562-b3fc-2c963f66afa6
+	WebhookID string `json:"webhook_id"`
+
+	// Callback URL
+	// default: https://example.com/webhooks/wallet
+	URL string `json:"url"`
+
+	// Signing secret, returned only on registration
+	// default: whsec_5f2b...
+	Secret string `json:"secret,omitempty"`
+
+	// Registration time
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhooksResponse represents the list of registered webhooks
+// swagger:model WebhooksResponse
+type WebhooksResponse struct {
+	// Webhooks, oldest first
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// NewCreateWebhookHandler returns an HTTP handler registering a webhook of the user.
+// @Summary Register a webhook
+// @Description Registers a callback URL notified of the user's deposits, withdrawals and exchanges. Events are POSTed as JSON with the Webhook-Id, Webhook-Timestamp and Webhook-Signature headers; the signature is "v1," followed by the base64 HMAC-SHA256 of "id.timestamp.body" keyed with the returned secret. Failed deliveries are retried with exponential backoff.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateWebhookRequest true "Webhook Request"
+// @Success 201 {object} handlers.WebhookResponse "Webhook registered"
+// @Failure 400 {object} apperrors.Response "Invalid request or webhook URL"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 409 {object} apperrors.Response "Too many webhooks"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /webhooks [post]
+// @Security BearerAuth
+func NewCreateWebhookHandler(svc WebhookManager, tokenGetter WebhookTokener) http.HandlerFunc {
+	return newCreateWebhookHandler(svc, tokenGetter, userWebhookOwner)
+}
+
+// NewCreateAdminWebhookHandler returns an HTTP handler registering an admin webhook.
+// @Summary Register an admin webhook
+// @Description Registers a callback URL notified of the deposits, withdrawals and exchanges of all users. Requests are signed like user webhooks.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body handlers.CreateWebhookRequest true "Webhook Request"
+// @Success 201 {object} handlers.WebhookResponse "Webhook registered"
+// @Failure 400 {object} apperrors.Response "Invalid request or webhook URL"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 409 {object} apperrors.Response "Too many webhooks"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/webhooks [post]
+// @Security BearerAuth
+func NewCreateAdminWebhookHandler(svc WebhookManager, tokenGetter WebhookTokener) http.HandlerFunc {
+	return newCreateWebhookHandler(svc, tokenGetter, adminWebhookOwner)
+}
+
+// NewListWebhooksHandler returns an HTTP handler listing the webhooks of the user.
+// @Summary List webhooks
+// @Description Returns the user's webhooks without their secrets.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} handlers.WebhooksResponse "Webhooks"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /webhooks [get]
+// @Security BearerAuth
+func NewListWebhooksHandler(svc WebhookManager, tokenGetter WebhookTokener) http.HandlerFunc {
+	return newListWebhooksHandler(svc, tokenGetter, userWebhookOwner)
+}
+
+// NewListAdminWebhooksHandler returns an HTTP handler listing the admin webhooks.
+// @Summary List admin webhooks
+// @Description Returns the webhooks receiving events of all users, without their secrets.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} handlers.WebhooksResponse "Webhooks"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/webhooks [get]
+// @Security BearerAuth
+func NewListAdminWebhooksHandler(svc WebhookManager, tokenGetter WebhookTokener) http.HandlerFunc {
+	return newListWebhooksHandler(svc, tokenGetter, adminWebhookOwner)
+}
+
+// NewDeleteWebhookHandler returns an HTTP handler removing a webhook of the user.
+// @Summary Delete a webhook
+// @Description Removes the webhook; events not delivered yet are dropped.
+// @Tags webhooks
+// @Produce json
+// @Param webhookID path string true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} apperrors.Response "Invalid webhook ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 404 {object} apperrors.Response "Webhook not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /webhooks/{webhookID} [delete]
+// @Security BearerAuth
+func NewDeleteWebhookHandler(svc WebhookManager, tokenGetter WebhookTokener) http.HandlerFunc {
+	return newDeleteWebhookHandler(svc, tokenGetter, userWebhookOwner)
+}
+
+// NewDeleteAdminWebhookHandler returns an HTTP handler removing an admin webhook.
+// @Summary Delete an admin webhook
+// @Description Removes the admin webhook; events not delivered yet are dropped.
+// @Tags admin
+// @Produce json
+// @Param webhookID path string true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} apperrors.Response "Invalid webhook ID"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Forbidden"
+// @Failure 404 {object} apperrors.Response "Webhook not found"
+// @Failure 429 {object} apperrors.Response "Too many requests"
+// @Failure 500 {object} apperrors.Response "Internal server error"
+// @Router /admin/webhooks/{webhookID} [delete]
+// @Security BearerAuth
+func NewDeleteAdminWebhookHandler(svc WebhookManager, tokenGetter WebhookTokener) http.HandlerFunc {
+	return newDeleteWebhookHandler(svc, tokenGetter, adminWebhookOwner)
+}
+
+// webhookOwner returns the owner of the webhooks managed by the caller.
+type webhookOwner func(claims *jwt.Claims) *uuid.UUID
+
+// userWebhookOwner scopes the webhooks to the calling user.
+func userWebhookOwner(claims *jwt.Claims) *uuid.UUID {
+	return &claims.UserID
+}
+
+// adminWebhookOwner scopes the webhooks to the admin ones; the route requires an admin token.
+func adminWebhookOwner(*jwt.Claims) *uuid.UUID {
+	return nil
+}
+
+func newCreateWebhookHandler(svc WebhookManager, tokenGetter WebhookTokener, owner webhookOwner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := webhookClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		var req CreateWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.URL == "" {
+			logger.FromContext(r.Context()).Warnw("invalid webhook request", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequest)
+			return
+		}
+
+		webhook, err := svc.Create(r.Context(), owner(claims), req.URL)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidWebhookURL):
+				apperrors.Write(w, apperrors.InvalidWebhookURL)
+			case errors.Is(err, services.ErrTooManyWebhooks):
+				apperrors.Write(w, apperrors.TooManyWebhooks)
+			default:
+				logger.FromContext(r.Context()).Errorw("failed to create webhook", "userID", claims.UserID, "error", err)
+				apperrors.Write(w, apperrors.Internal)
+			}
+			return
+		}
+
+		resp := toWebhookResponse(webhook)
+		resp.Secret = webhook.Secret
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func newListWebhooksHandler(svc WebhookManager, tokenGetter WebhookTokener, owner webhookOwner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := webhookClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		webhooks, err := svc.List(r.Context(), owner(claims))
+		if err != nil {
+			logger.FromContext(r.Context()).Errorw("failed to list webhooks", "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		resp := WebhooksResponse{Webhooks: make([]WebhookResponse, 0, len(webhooks))}
+		for _, webhook := range webhooks {
+			resp.Webhooks = append(resp.Webhooks, toWebhookResponse(webhook))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func newDeleteWebhookHandler(svc WebhookManager, tokenGetter WebhookTokener, owner webhookOwner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := webhookClaims(w, r, tokenGetter)
+		if !ok {
+			return
+		}
+
+		webhookID, err := uuid.Parse(chi.URLParam(r, "webhookID"))
+		if err != nil {
+			logger.FromContext(r.Context()).Warnw("invalid webhook ID", "webhookID", chi.URLParam(r, "webhookID"), "error", err)
+			apperrors.Write(w, apperrors.InvalidWebhookID)
+			return
+		}
+
+		if err := svc.Delete(r.Context(), owner(claims), webhookID); err != nil {
+			if errors.Is(err, services.ErrWebhookNotFound) {
+				apperrors.Write(w, apperrors.WebhookNotFound)
+				return
+			}
+			logger.FromContext(r.Context()).Errorw("failed to delete webhook", "webhookID", webhookID, "userID", claims.UserID, "error", err)
+			apperrors.Write(w, apperrors.Internal)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// toWebhookResponse converts a webhook without its secret.
+func toWebhookResponse(webhook models.WebhookDB) WebhookResponse {
+	return WebhookResponse{
+		WebhookID: webhook.WebhookID.String(),
+		URL:       webhook.URL,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// webhookClaims authenticates the request, writing 401 on failure.
+func webhookClaims(w http.ResponseWriter, r *http.Request, tokenGetter WebhookTokener) (*jwt.Claims, bool) {
+	ctx := r.Context()
+
+	tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	claims, err := tokenGetter.GetClaims(ctx, tokenStr)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+		apperrors.Write(w, apperrors.Unauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
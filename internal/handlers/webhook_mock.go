@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/handlers/webhook.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockWebhookTokener is a mock of WebhookTokener interface.
+type MockWebhookTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookTokenerMockRecorder
+}
+
+// MockWebhookTokenerMockRecorder is the mock recorder for MockWebhookTokener.
+type MockWebhookTokenerMockRecorder struct {
+	mock *MockWebhookTokener
+}
+
+// NewMockWebhookTokener creates a new mock instance.
+func NewMockWebhookTokener(ctrl *gomock.Controller) *MockWebhookTokener {
+	mock := &MockWebhookTokener{ctrl: ctrl}
+	mock.recorder = &MockWebhookTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookTokener) EXPECT() *MockWebhookTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockWebhookTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockWebhookTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockWebhookTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockWebhookTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockWebhookTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockWebhookTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockWebhookManager is a mock of WebhookManager interface.
+type MockWebhookManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookManagerMockRecorder
+}
+
+// MockWebhookManagerMockRecorder is the mock recorder for MockWebhookManager.
+type MockWebhookManagerMockRecorder struct {
+	mock *MockWebhookManager
+}
+
+// NewMockWebhookManager creates a new mock instance.
+func NewMockWebhookManager(ctrl *gomock.Controller) *MockWebhookManager {
+	mock := &MockWebhookManager{ctrl: ctrl}
+	mock.recorder = &MockWebhookManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookManager) EXPECT() *MockWebhookManagerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookManager) Create(ctx context.Context, ownerID *uuid.UUID, url string) (models.WebhookDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, ownerID, url)
+	ret0, _ := ret[0].(models.WebhookDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookManagerMockRecorder) Create(ctx, ownerID, url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookManager)(nil).Create), ctx, ownerID, url)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookManager) Delete(ctx context.Context, ownerID *uuid.UUID, webhookID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ownerID, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookManagerMockRecorder) Delete(ctx, ownerID, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookManager)(nil).Delete), ctx, ownerID, webhookID)
+}
+
+// List mocks base method.
+func (m *MockWebhookManager) List(ctx context.Context, ownerID *uuid.UUID) ([]models.WebhookDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, ownerID)
+	ret0, _ := ret[0].([]models.WebhookDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookManagerMockRecorder) List(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookManager)(nil).List), ctx, ownerID)
+}
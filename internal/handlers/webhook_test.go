@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWebhookTokener(ctrl)
+	mockSvc := NewMockWebhookManager(ctrl)
+
+	userID := uuid.New()
+	webhookID := uuid.New()
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// Allow token calls for all subtests
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		body           string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "success",
+			handler: NewCreateWebhookHandler(mockSvc, mockTokener),
+			body:    `{"url":"https://example.com/hook"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), &userID, "https://example.com/hook").
+					Return(models.WebhookDB{WebhookID: webhookID, UserID: &userID, URL: "https://example.com/hook", Secret: "whsec_1", CreatedAt: createdAt}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: WebhookResponse{
+				WebhookID: webhookID.String(),
+				URL:       "https://example.com/hook",
+				Secret:    "whsec_1",
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:    "admin_success",
+			handler: NewCreateAdminWebhookHandler(mockSvc, mockTokener),
+			body:    `{"url":"https://ops.example.com/hook"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), (*uuid.UUID)(nil), "https://ops.example.com/hook").
+					Return(models.WebhookDB{WebhookID: webhookID, URL: "https://ops.example.com/hook", Secret: "whsec_2", CreatedAt: createdAt}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: WebhookResponse{
+				WebhookID: webhookID.String(),
+				URL:       "https://ops.example.com/hook",
+				Secret:    "whsec_2",
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name:           "invalid_body",
+			handler:        NewCreateWebhookHandler(mockSvc, mockTokener),
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:           "missing_url",
+			handler:        NewCreateWebhookHandler(mockSvc, mockTokener),
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid request", Code: "invalid_request"},
+		},
+		{
+			name:    "invalid_url",
+			handler: NewCreateWebhookHandler(mockSvc, mockTokener),
+			body:    `{"url":"ftp://example.com"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), &userID, "ftp://example.com").
+					Return(models.WebhookDB{}, services.ErrInvalidWebhookURL)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid webhook URL", Code: "invalid_webhook_url"},
+		},
+		{
+			name:    "too_many",
+			handler: NewCreateWebhookHandler(mockSvc, mockTokener),
+			body:    `{"url":"https://example.com/hook"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), &userID, "https://example.com/hook").
+					Return(models.WebhookDB{}, services.ErrTooManyWebhooks)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   apperrors.Response{Error: "Too many webhooks", Code: "too_many_webhooks"},
+		},
+		{
+			name:    "internal_error",
+			handler: NewCreateWebhookHandler(mockSvc, mockTokener),
+			body:    `{"url":"https://example.com/hook"}`,
+			mockSvc: func() {
+				mockSvc.EXPECT().
+					Create(gomock.Any(), &userID, "https://example.com/hook").
+					Return(models.WebhookDB{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+
+			respBody := rec.Body.Bytes()
+			switch expected := tt.expectedBody.(type) {
+			case WebhookResponse:
+				var got WebhookResponse
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			case apperrors.Response:
+				var got apperrors.Response
+				err := json.Unmarshal(respBody, &got)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, got)
+			}
+		})
+	}
+}
+
+func TestListWebhooksHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWebhookTokener(ctrl)
+	mockSvc := NewMockWebhookManager(ctrl)
+
+	userID := uuid.New()
+	webhookID := uuid.New()
+	createdAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	t.Run("secrets are not listed", func(t *testing.T) {
+		mockSvc.EXPECT().List(gomock.Any(), &userID).Return([]models.WebhookDB{
+			{WebhookID: webhookID, UserID: &userID, URL: "https://example.com/hook", Secret: "whsec_1", CreatedAt: createdAt},
+		}, nil)
+
+		rec := httptest.NewRecorder()
+		NewListWebhooksHandler(mockSvc, mockTokener).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "whsec_1")
+		var got WebhooksResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, WebhooksResponse{Webhooks: []WebhookResponse{
+			{WebhookID: webhookID.String(), URL: "https://example.com/hook", CreatedAt: createdAt},
+		}}, got)
+	})
+
+	t.Run("admin webhooks", func(t *testing.T) {
+		mockSvc.EXPECT().List(gomock.Any(), (*uuid.UUID)(nil)).Return(nil, nil)
+
+		rec := httptest.NewRecorder()
+		NewListAdminWebhooksHandler(mockSvc, mockTokener).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"webhooks":[]}`, rec.Body.String())
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockSvc.EXPECT().List(gomock.Any(), &userID).Return(nil, errors.New("db error"))
+
+		rec := httptest.NewRecorder()
+		NewListWebhooksHandler(mockSvc, mockTokener).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestDeleteWebhookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWebhookTokener(ctrl)
+	mockSvc := NewMockWebhookManager(ctrl)
+
+	userID := uuid.New()
+	webhookID := uuid.New()
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return("valid-token", nil)
+	mockTokener.EXPECT().
+		GetClaims(gomock.Any(), "valid-token").
+		AnyTimes().
+		Return(&jwt.Claims{UserID: userID}, nil)
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		webhookID      string
+		mockSvc        func()
+		expectedStatus int
+		expectedBody   *apperrors.Response
+	}{
+		{
+			name:      "success",
+			handler:   NewDeleteWebhookHandler(mockSvc, mockTokener),
+			webhookID: webhookID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), &userID, webhookID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "admin_success",
+			handler:   NewDeleteAdminWebhookHandler(mockSvc, mockTokener),
+			webhookID: webhookID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), (*uuid.UUID)(nil), webhookID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid_webhook_id",
+			handler:        NewDeleteWebhookHandler(mockSvc, mockTokener),
+			webhookID:      "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   &apperrors.Response{Error: "Invalid webhook ID", Code: "invalid_webhook_id"},
+		},
+		{
+			name:      "not_found",
+			handler:   NewDeleteWebhookHandler(mockSvc, mockTokener),
+			webhookID: webhookID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), &userID, webhookID).Return(services.ErrWebhookNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   &apperrors.Response{Error: "Webhook not found", Code: "webhook_not_found"},
+		},
+		{
+			name:      "internal_error",
+			handler:   NewDeleteWebhookHandler(mockSvc, mockTokener),
+			webhookID: webhookID.String(),
+			mockSvc: func() {
+				mockSvc.EXPECT().Delete(gomock.Any(), &userID, webhookID).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   &apperrors.Response{Error: "Internal server error", Code: "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockSvc != nil {
+				tt.mockSvc()
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+tt.webhookID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("webhookID", tt.webhookID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Result().StatusCode)
+			if tt.expectedBody != nil {
+				var got apperrors.Response
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.Equal(t, *tt.expectedBody, got)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlers_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokener := NewMockWebhookTokener(ctrl)
+	mockSvc := NewMockWebhookManager(ctrl)
+
+	mockTokener.EXPECT().
+		GetTokenFromRequest(gomock.Any(), gomock.Any()).
+		Times(3).
+		Return("", errors.New("missing token"))
+
+	for _, handler := range []http.HandlerFunc{
+		NewCreateWebhookHandler(mockSvc, mockTokener),
+		NewListWebhooksHandler(mockSvc, mockTokener),
+		NewDeleteWebhookHandler(mockSvc, mockTokener),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+	}
+}
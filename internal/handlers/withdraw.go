@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/validation"
 )
 
 // WithdrawTokener defines only the methods needed by this handler.
@@ -19,24 +23,12 @@ type WithdrawTokener interface {
 
 // WalletWithdrawWriter defines the interface that the service must implement.
 type WalletWithdrawWriter interface {
-	Withdraw(ctx context.Context, userID uuid.UUID, amount float64, currency string) (usd, rub, eur float64, err error)
+	Withdraw(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error)
 }
 
-// CurrencyBalanceAfterWithdraw represents balances for different currencies
+// CurrencyBalanceAfterWithdraw represents balances keyed by currency code
 // swagger:model CurrencyBalanceAfterWithdraw
-type CurrencyBalanceAfterWithdraw struct {
-	// Balance in USD
-	// default: 100.0
-	USD float64 `json:"USD"`
-
-	// Balance in RUB
-	// default: 5000.0
-	RUB float64 `json:"RUB"`
-
-	// Balance in EUR
-	// default: 50.0
-	EUR float64 `json:"EUR"`
-}
+type CurrencyBalanceAfterWithdraw map[string]money.Amount
 
 // WithdrawRequest represents the JSON body for withdrawing funds
 // swagger:model WithdrawRequest
@@ -44,12 +36,16 @@ type WithdrawRequest struct {
 	// Amount to withdraw
 	// required: true
 	// default: 50.0
-	Amount float64 `json:"amount"`
+	Amount money.Amount `json:"amount" swaggertype:"number" validate:"gt=0"`
 
 	// Currency
 	// required: true
 	// default: USD
-	Currency string `json:"currency"`
+	Currency string `json:"currency" validate:"required"`
+
+	// Client reference for reconciliation, up to 128 characters; returned in the history
+	// default: INV-2024-0042
+	Reference string `json:"reference,omitempty" validate:"max=128"`
 }
 
 // WithdrawResponse represents a successful withdrawal response
@@ -60,15 +56,7 @@ type WithdrawResponse struct {
 	Message string `json:"message"`
 
 	// New balance of the user
-	NewBalance CurrencyBalanceAfterWithdraw `json:"new_balance"`
-}
-
-// WithdrawErrorResponse represents an error response for withdrawal
-// swagger:model WithdrawErrorResponse
-type WithdrawErrorResponse struct {
-	// Error message
-	// default: Insufficient funds or invalid amount
-	Error string `json:"error"`
+	NewBalance CurrencyBalanceAfterWithdraw `json:"new_balance" swaggertype:"object,number"`
 }
 
 // NewWithdrawHandler returns an HTTP handler for withdrawing funds from user wallet.
@@ -78,87 +66,79 @@ type WithdrawErrorResponse struct {
 // @Accept json
 // @Produce json
 // @Param request body handlers.WithdrawRequest true "Withdraw Request"
+// @Param Api-Version header string false "Balance schema: 1 (legacy USD/RUB/EUR object, default, deprecated) or 2 (map of all supported currencies)"
 // @Success 200 {object} handlers.WithdrawResponse "Withdrawal successful"
-// @Failure 400 {object} handlers.WithdrawErrorResponse "Insufficient funds or invalid amount"
-// @Failure 401 {object} handlers.WithdrawErrorResponse "Unauthorized"
+// @Failure 400 {object} apperrors.Response "Insufficient funds, invalid amount or invalid reference"
+// @Failure 401 {object} apperrors.Response "Unauthorized"
+// @Failure 403 {object} apperrors.Response "Daily or monthly limit exceeded"
+// @Failure 409 {object} apperrors.Response "Another operation is in progress"
+// @Failure 429 {object} apperrors.Response "Too many requests"
 // @Router /wallet/withdraw [post]
 // @Security BearerAuth
 func NewWithdrawHandler(
 	svc WalletWithdrawWriter,
 	tokenGetter WithdrawTokener,
+	currencies CurrencyChecker,
+	legacyDeprecation time.Time,
 ) http.HandlerFunc {
-	validCurrencies := map[string]struct{}{
-		"USD": {},
-		"RUB": {},
-		"EUR": {},
-	}
-
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		tokenStr, err := tokenGetter.GetTokenFromRequest(ctx, r)
 		if err != nil {
-			logger.Log.Errorw("failed to get token from request", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "Unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get token from request", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		claims, err := tokenGetter.GetClaims(ctx, tokenStr)
 		if err != nil {
-			logger.Log.Errorw("failed to get claims from token", "error", err)
-			w.WriteHeader(http.StatusUnauthorized)
-			json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "Unauthorized"})
+			logger.FromContext(ctx).Errorw("failed to get claims from token", "error", err)
+			apperrors.Write(w, apperrors.Unauthorized)
 			return
 		}
 
 		var req WithdrawRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			logger.Log.Errorw("failed to decode withdraw request body", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "invalid request body"})
+			logger.FromContext(ctx).Errorw("failed to decode withdraw request body", "error", err)
+			apperrors.Write(w, apperrors.InvalidRequestBody)
 			return
 		}
 
-		if req.Amount <= 0 {
-			logger.Log.Warnw("invalid withdraw amount", "amount", req.Amount, "userID", claims.UserID)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "Insufficient funds or invalid amount"})
+		if errs := validation.Struct(req); errs != nil {
+			logger.FromContext(ctx).Warnw("invalid withdraw request", "error", errs, "userID", claims.UserID)
+			writeInvalid(w, errs, apperrors.InsufficientFundsWithdraw, map[string]apperrors.Error{"reference": apperrors.InvalidReference})
 			return
 		}
-		if _, ok := validCurrencies[req.Currency]; !ok {
-			logger.Log.Warnw("invalid withdraw currency", "currency", req.Currency, "userID", claims.UserID)
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "Insufficient funds or invalid amount"})
+		if !currencies.IsSupported(ctx, req.Currency) || !currencies.ValidAmount(ctx, req.Currency, req.Amount) {
+			logger.FromContext(ctx).Warnw("invalid withdraw currency", "currency", req.Currency, "userID", claims.UserID)
+			apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
 			return
 		}
 
-		usd, rub, eur, err := svc.Withdraw(ctx, claims.UserID, req.Amount, req.Currency)
+		balances, err := svc.Withdraw(ctx, claims.UserID, req.Amount, req.Currency, req.Reference)
 		if err != nil {
 			switch err {
 			case services.ErrInsufficientFunds:
-				logger.Log.Warnw("withdraw failed due to insufficient funds", "amount", req.Amount, "currency", req.Currency, "userID", claims.UserID)
-				w.WriteHeader(http.StatusBadRequest)
-				json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "Insufficient funds or invalid amount"})
+				logger.FromContext(ctx).Warnw("withdraw failed due to insufficient funds", "amount", req.Amount, "currency", req.Currency, "userID", claims.UserID)
+				apperrors.Write(w, apperrors.InsufficientFundsWithdraw)
+			case services.ErrDailyLimitExceeded:
+				apperrors.Write(w, apperrors.DailyLimitExceeded)
+			case services.ErrMonthlyLimitExceeded:
+				apperrors.Write(w, apperrors.MonthlyLimitExceeded)
 			default:
-				logger.Log.Errorw("internal server error during withdraw", "error", err, "userID", claims.UserID)
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(WithdrawErrorResponse{Error: "Internal server error"})
+				logger.FromContext(ctx).Errorw("internal server error during withdraw", "error", err, "userID", claims.UserID)
+				apperrors.Write(w, apperrors.Internal)
 			}
 			return
 		}
 
-		newBalance := CurrencyBalanceAfterWithdraw{
-			USD: usd,
-			RUB: rub,
-			EUR: eur,
-		}
-
 		resp := WithdrawResponse{
 			Message:    "Withdrawal successful",
-			NewBalance: newBalance,
+			NewBalance: renderBalances(r, balances),
 		}
 
+		setBalanceSchemaHeaders(w, r, legacyDeprecation)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(resp)
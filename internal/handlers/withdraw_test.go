@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/services"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,7 +28,7 @@ func TestWithdrawHandler(t *testing.T) {
 
 	userID := uuid.New()
 
-	handler := NewWithdrawHandler(mockWriter, mockTokener)
+	handler := NewWithdrawHandler(mockWriter, mockTokener, newMockCurrencies(ctrl), time.Time{})
 
 	// Allow token extraction for all subtests
 	mockTokener.EXPECT().
@@ -45,61 +50,110 @@ func TestWithdrawHandler(t *testing.T) {
 		{
 			name: "success",
 			reqBody: WithdrawRequest{
-				Amount:   50,
+				Amount:   money.MustParse("50"),
 				Currency: "USD",
 			},
 			mockWithdraw: func() {
 				mockWriter.EXPECT().
-					Withdraw(gomock.Any(), userID, 50.0, "USD").
-					Return(200.0, 5000.0, 50.0, nil)
+					Withdraw(gomock.Any(), userID, money.MustParse("50"), "USD", "").
+					Return(map[string]money.Amount{"USD": money.MustParse("200"), "RUB": money.MustParse("5000"), "EUR": money.MustParse("50")}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: WithdrawResponse{
 				Message: "Withdrawal successful",
 				NewBalance: CurrencyBalanceAfterWithdraw{
-					USD: 200.0,
-					RUB: 5000.0,
-					EUR: 50.0,
+					"USD": money.MustParse("200"),
+					"RUB": money.MustParse("5000"),
+					"EUR": money.MustParse("50"),
 				},
 			},
 		},
 		{
 			name:           "bad_request_invalid_amount",
-			reqBody:        WithdrawRequest{Amount: -10, Currency: "USD"},
+			reqBody:        WithdrawRequest{Amount: money.MustParse("-10"), Currency: "USD"},
 			mockWithdraw:   nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   WithdrawErrorResponse{Error: "Insufficient funds or invalid amount"},
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw", Details: []any{map[string]any{"field": "amount", "rule": "gt", "param": "0", "message": "must be greater than 0"}}},
+		},
+		{
+			name:    "success_with_reference",
+			reqBody: WithdrawRequest{Amount: money.MustParse("50"), Currency: "USD", Reference: "PAYOUT-7"},
+			mockWithdraw: func() {
+				mockWriter.EXPECT().
+					Withdraw(gomock.Any(), userID, money.MustParse("50"), "USD", "PAYOUT-7").
+					Return(map[string]money.Amount{"USD": money.MustParse("150")}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: WithdrawResponse{
+				Message:    "Withdrawal successful",
+				NewBalance: CurrencyBalanceAfterWithdraw{"USD": money.MustParse("150"), "RUB": money.Zero, "EUR": money.Zero},
+			},
+		},
+		{
+			name:           "bad_request_reference_too_long",
+			reqBody:        WithdrawRequest{Amount: money.MustParse("50"), Currency: "USD", Reference: strings.Repeat("я", models.MaxReferenceLength+1)},
+			mockWithdraw:   nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   apperrors.Response{Error: "Invalid reference", Code: "invalid_reference", Details: []any{map[string]any{"field": "reference", "rule": "max", "param": "128", "message": "must be at most 128 long"}}},
 		},
 		{
 			name:           "bad_request_invalid_json",
 			reqBody:        `invalid-json`,
 			mockWithdraw:   nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   WithdrawErrorResponse{Error: "invalid request body"},
+			expectedBody:   apperrors.Response{Error: "Invalid request body", Code: "invalid_request_body"},
 		},
 		{
 			name: "insufficient_funds",
 			reqBody: WithdrawRequest{
-				Amount:   100,
+				Amount:   money.MustParse("100"),
 				Currency: "USD",
 			},
 			mockWithdraw: func() {
 				mockWriter.EXPECT().
-					Withdraw(gomock.Any(), userID, 100.0, "USD").
-					Return(100.0, 5000.0, 50.0, services.ErrInsufficientFunds)
+					Withdraw(gomock.Any(), userID, money.MustParse("100"), "USD", "").
+					Return(nil, services.ErrInsufficientFunds)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   WithdrawErrorResponse{Error: "Insufficient funds or invalid amount"},
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
+		},
+		{
+			name: "daily_limit_exceeded",
+			reqBody: WithdrawRequest{
+				Amount:   money.MustParse("100"),
+				Currency: "USD",
+			},
+			mockWithdraw: func() {
+				mockWriter.EXPECT().
+					Withdraw(gomock.Any(), userID, money.MustParse("100"), "USD", "").
+					Return(nil, services.ErrDailyLimitExceeded)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Daily limit exceeded", Code: "daily_limit_exceeded"},
+		},
+		{
+			name: "monthly_limit_exceeded",
+			reqBody: WithdrawRequest{
+				Amount:   money.MustParse("100"),
+				Currency: "USD",
+			},
+			mockWithdraw: func() {
+				mockWriter.EXPECT().
+					Withdraw(gomock.Any(), userID, money.MustParse("100"), "USD", "").
+					Return(nil, services.ErrMonthlyLimitExceeded)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   apperrors.Response{Error: "Monthly limit exceeded", Code: "monthly_limit_exceeded"},
 		},
 		{
 			name: "invalid_currency",
 			reqBody: WithdrawRequest{
-				Amount:   50,
+				Amount:   money.MustParse("50"),
 				Currency: "ABC",
 			},
 			mockWithdraw:   nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   WithdrawErrorResponse{Error: "Insufficient funds or invalid amount"},
+			expectedBody:   apperrors.Response{Error: "Insufficient funds or invalid amount", Code: "insufficient_funds_withdraw"},
 		},
 	}
 
@@ -131,8 +185,8 @@ func TestWithdrawHandler(t *testing.T) {
 				err := json.Unmarshal(respBody, &got)
 				assert.NoError(t, err)
 				assert.Equal(t, expected, got)
-			case WithdrawErrorResponse:
-				var got WithdrawErrorResponse
+			case apperrors.Response:
+				var got apperrors.Response
 				err := json.Unmarshal(respBody, &got)
 				assert.NoError(t, err)
 				assert.Equal(t, expected, got)
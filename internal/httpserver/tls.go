@@ -0,0 +1,92 @@
+// Package httpserver configures how the HTTP server terminates HTTPS.
+package httpserver
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLS configures HTTPS on the server, from a certificate file or from Let's Encrypt.
+type TLS struct {
+	CertFile         string   // Server certificate, PEM; exclusive with AutocertDomains
+	KeyFile          string   // Key of the server certificate
+	AutocertDomains  []string // Domains certificates are obtained for from Let's Encrypt
+	AutocertCacheDir string   // Directory the obtained certificates are kept in across restarts
+	AutocertEmail    string   // Contact of the Let's Encrypt account, optional
+	HTTPSPort        string   // Port HTTP requests are redirected to, omitted from the URL if 443
+}
+
+// Enabled reports whether the server terminates HTTPS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != "" || len(t.AutocertDomains) > 0
+}
+
+// Config returns the TLS configuration of the server and the handler of the plain HTTP
+// listener, which redirects to HTTPS and answers Let's Encrypt HTTP-01 challenges. Both are
+// nil if TLS is not enabled.
+func (t TLS) Config() (*tls.Config, http.Handler, error) {
+	if !t.Enabled() {
+		return nil, nil, nil
+	}
+
+	// Modern defaults: TLS 1.2 and up, forward-secret AEAD suites only
+	cfg := &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		},
+	}
+	redirect := redirectHandler(t.HTTPSPort)
+
+	if len(t.AutocertDomains) == 0 {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load HTTP server certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+		return cfg, redirect, nil
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		return nil, nil, errors.New("HTTP server certificate and autocert domains are exclusive")
+	}
+	if t.AutocertCacheDir == "" {
+		return nil, nil, errors.New("autocert requires a cache directory")
+	}
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(t.AutocertDomains...),
+		Cache:      autocert.DirCache(t.AutocertCacheDir),
+		Email:      t.AutocertEmail,
+	}
+	cfg.GetCertificate = m.GetCertificate
+	// TLS-ALPN-01 challenges are answered on the HTTPS port itself
+	cfg.NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+	return cfg, m.HTTPHandler(redirect), nil
+}
+
+// redirectHandler permanently redirects every request to the same URL over HTTPS on httpsPort.
+func redirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
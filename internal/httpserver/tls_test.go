@@ -0,0 +1,119 @@
+package httpserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self-signed certificate and its key as PEM files into dir.
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	must(err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "wallet.example.com"},
+		DNSNames:     []string{"wallet.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	must(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	must(err)
+
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	must(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	must(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestTLS_Config(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir)
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg, redirect, err := TLS{HTTPSPort: "8443"}.Config()
+		assert.NoError(t, err)
+		assert.Nil(t, cfg)
+		assert.Nil(t, redirect)
+	})
+
+	t.Run("certificate", func(t *testing.T) {
+		cfg, redirect, err := TLS{CertFile: certFile, KeyFile: keyFile}.Config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, cfg.Certificates, 1)
+		assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+		assert.NotNil(t, redirect)
+	})
+
+	t.Run("missing_key", func(t *testing.T) {
+		_, _, err := TLS{CertFile: certFile}.Config()
+		assert.Error(t, err)
+	})
+
+	t.Run("autocert", func(t *testing.T) {
+		cfg, redirect, err := TLS{AutocertDomains: []string{"wallet.example.com"}, AutocertCacheDir: dir}.Config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotNil(t, cfg.GetCertificate)
+		assert.Contains(t, cfg.NextProtos, "acme-tls/1")
+		assert.NotNil(t, redirect)
+	})
+
+	t.Run("certificate_and_autocert", func(t *testing.T) {
+		_, _, err := TLS{CertFile: certFile, KeyFile: keyFile, AutocertDomains: []string{"wallet.example.com"}, AutocertCacheDir: dir}.Config()
+		assert.EqualError(t, err, "HTTP server certificate and autocert domains are exclusive")
+	})
+
+	t.Run("autocert_without_cache", func(t *testing.T) {
+		_, _, err := TLS{AutocertDomains: []string{"wallet.example.com"}}.Config()
+		assert.EqualError(t, err, "autocert requires a cache directory")
+	})
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		target    string
+		location  string
+	}{
+		{name: "custom_port", httpsPort: "8443", target: "http://wallet.example.com:8080/api/v1/balance?currency=USD", location: "https://wallet.example.com:8443/api/v1/balance?currency=USD"},
+		{name: "default_port", httpsPort: "443", target: "http://wallet.example.com/api/v1/balance", location: "https://wallet.example.com/api/v1/balance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			redirectHandler(tt.httpsPort).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.target, nil))
+
+			// 308 keeps the method and body of the request
+			assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+			assert.Equal(t, tt.location, rec.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// lockTTL is how long a job lock outlives a replica that stopped renewing it, the
+// longest a run stays locked without renewal. Running jobs renew it every third of it.
+const lockTTL = 30 * time.Second
+
+// Locker defines a distributed lock used to guard job runs across replicas.
+type Locker interface {
+	TryLockExtendable(ctx context.Context, key string, ttl time.Duration) (
+		extend func(ctx context.Context) error, release func(ctx context.Context) error, err error,
+	)
+}
+
+// Job is a named unit of background work executed periodically.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs on their intervals.
+// Each run is guarded by a distributed lock held until the run returns,
+// so a job never runs on two replicas at the same time.
+type Scheduler struct {
+	locker  Locker
+	lockTTL time.Duration
+	jobs    []Job
+}
+
+// NewScheduler creates a new Scheduler instance.
+func NewScheduler(locker Locker) *Scheduler {
+	return &Scheduler{locker: locker, lockTTL: lockTTL}
+}
+
+// Register adds a job to the scheduler. It must be called before Run.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: run})
+}
+
+// Run starts all registered jobs and blocks until ctx is cancelled
+// and every job has returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	logger.FromContext(ctx).Infow("job scheduled", "job", job.Name, "interval", job.Interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Infow("job stopped", "job", job.Name)
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+// runOnce executes the job if this replica acquires its lock. The lock is renewed while
+// the job runs, however long that takes, and released when it returns. If the lock is
+// lost, e.g. Redis was unreachable for longer than the lock TTL, the run is cancelled.
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	extend, release, err := s.locker.TryLockExtendable(ctx, "jobs:"+job.Name, s.lockTTL)
+	if err != nil {
+		if errors.Is(err, locks.ErrNotAcquired) {
+			logger.FromContext(ctx).Debugw("job skipped, lock held by another replica", "job", job.Name)
+			return
+		}
+		logger.FromContext(ctx).Errorw("failed to acquire job lock", "job", job.Name, "error", err)
+		return
+	}
+	defer func() {
+		if err := release(context.WithoutCancel(ctx)); err != nil {
+			logger.FromContext(ctx).Errorw("failed to release job lock", "job", job.Name, "error", err)
+		}
+	}()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		s.renewLock(runCtx, job, extend, cancel)
+	}()
+	defer func() {
+		cancel()
+		<-renewed
+	}()
+
+	start := time.Now()
+	if err := job.Run(runCtx); err != nil {
+		logger.FromContext(ctx).Errorw("job failed", "job", job.Name, "duration_ms", time.Since(start).Milliseconds(), "error", err)
+		return
+	}
+	logger.FromContext(ctx).Infow("job completed", "job", job.Name, "duration_ms", time.Since(start).Milliseconds())
+}
+
+// renewLock extends the job lock every third of its TTL until ctx is done. A failed
+// renewal is retried on the next tick; once the lock is lost, the run is cancelled.
+func (s *Scheduler) renewLock(ctx context.Context, job Job, extend func(ctx context.Context) error, cancel context.CancelFunc) {
+	ticker := time.NewTicker(s.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := extend(ctx)
+			if err == nil || ctx.Err() != nil {
+				continue
+			}
+			if errors.Is(err, locks.ErrLockLost) {
+				logger.FromContext(ctx).Errorw("job lock lost, cancelling the run", "job", job.Name)
+				cancel()
+				return
+			}
+			logger.FromContext(ctx).Warnw("failed to extend job lock", "job", job.Name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/jobs/scheduler.go
+
+// Package jobs is a generated GoMock package.
+package jobs
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLocker is a mock of Locker interface.
+type MockLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockerMockRecorder
+}
+
+// MockLockerMockRecorder is the mock recorder for MockLocker.
+type MockLockerMockRecorder struct {
+	mock *MockLocker
+}
+
+// NewMockLocker creates a new mock instance.
+func NewMockLocker(ctrl *gomock.Controller) *MockLocker {
+	mock := &MockLocker{ctrl: ctrl}
+	mock.recorder = &MockLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocker) EXPECT() *MockLockerMockRecorder {
+	return m.recorder
+}
+
+// TryLockExtendable mocks base method.
+func (m *MockLocker) TryLockExtendable(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, func(context.Context) error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLockExtendable", ctx, key, ttl)
+	ret0, _ := ret[0].(func(context.Context) error)
+	ret1, _ := ret[1].(func(context.Context) error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TryLockExtendable indicates an expected call of TryLockExtendable.
+func (mr *MockLockerMockRecorder) TryLockExtendable(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLockExtendable", reflect.TypeOf((*MockLocker)(nil).TryLockExtendable), ctx, key, ttl)
+}
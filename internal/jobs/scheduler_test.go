@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_RunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocker := NewMockLocker(ctrl)
+	s := NewScheduler(mockLocker)
+
+	tests := []struct {
+		name      string
+		lockErr   error
+		jobErr    error
+		expectRun bool
+	}{
+		{name: "lock acquired", expectRun: true},
+		{name: "lock acquired, job fails", jobErr: errors.New("job error"), expectRun: true},
+		{name: "lock held by another replica", lockErr: locks.ErrNotAcquired},
+		{name: "lock backend error", lockErr: errors.New("redis down")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var extend, release func(ctx context.Context) error
+			released := false
+			if tt.lockErr == nil {
+				extend = func(ctx context.Context) error { return nil }
+				release = func(ctx context.Context) error {
+					released = true
+					return nil
+				}
+			}
+			mockLocker.EXPECT().
+				TryLockExtendable(gomock.Any(), "jobs:test", lockTTL).
+				Return(extend, release, tt.lockErr)
+
+			ran := false
+			s.runOnce(context.Background(), Job{
+				Name:     "test",
+				Interval: time.Minute,
+				Run: func(ctx context.Context) error {
+					ran = true
+					return tt.jobErr
+				},
+			})
+			assert.Equal(t, tt.expectRun, ran)
+			assert.Equal(t, tt.expectRun, released)
+		})
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLocker := NewMockLocker(ctrl)
+	noop := func(ctx context.Context) error { return nil }
+	mockLocker.EXPECT().
+		TryLockExtendable(gomock.Any(), "jobs:tick", lockTTL).
+		AnyTimes().
+		Return(noop, noop, nil)
+
+	s := NewScheduler(mockLocker)
+
+	var runs int32
+	s.Register("tick", 10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after context cancellation")
+	}
+	assert.Greater(t, atomic.LoadInt32(&runs), int32(0))
+}
+
+// memLocker is an in-memory Locker whose locks expire after their TTL, like Redis ones
+type memLocker struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	owners  map[string]int
+	next    int
+}
+
+func (l *memLocker) TryLockExtendable(_ context.Context, key string, ttl time.Duration) (
+	func(ctx context.Context) error, func(ctx context.Context) error, error,
+) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.expires == nil {
+		l.expires, l.owners = map[string]time.Time{}, map[string]int{}
+	}
+	if time.Now().Before(l.expires[key]) {
+		return nil, nil, locks.ErrNotAcquired
+	}
+	l.next++
+	owner := l.next
+	l.expires[key], l.owners[key] = time.Now().Add(ttl), owner
+
+	extend := func(context.Context) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.owners[key] != owner || !time.Now().Before(l.expires[key]) {
+			return locks.ErrLockLost
+		}
+		l.expires[key] = time.Now().Add(ttl)
+		return nil
+	}
+	release := func(context.Context) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.owners[key] == owner {
+			delete(l.expires, key)
+		}
+		return nil
+	}
+	return extend, release, nil
+}
+
+func TestScheduler_RunOutlastingLockTTL(t *testing.T) {
+	locker := &memLocker{}
+	first, second := NewScheduler(locker), NewScheduler(locker)
+	first.lockTTL, second.lockTTL = 30*time.Millisecond, 30*time.Millisecond
+
+	var running, overlaps, runs int32
+	job := Job{
+		Name:     "slow",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			defer atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&runs, 1)
+			select {
+			case <-time.After(150 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		first.runOnce(context.Background(), job)
+		close(done)
+	}()
+
+	// The first run outlasts both the interval and the lock TTL
+	time.Sleep(100 * time.Millisecond)
+	second.runOnce(context.Background(), job)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs), "second scheduler started the job while it was running")
+
+	<-done
+	assert.Zero(t, atomic.LoadInt32(&overlaps))
+
+	// The lock is released once the run returns
+	job.Run = func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}
+	second.runOnce(context.Background(), job)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
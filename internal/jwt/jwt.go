@@ -21,9 +21,40 @@ type JWT struct {
 // Claims represents the JWT claims structure with UUID UserID.
 type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
+	Role   string    `json:"role,omitempty"`
+	Act    *Actor    `json:"act,omitempty"` // Set when the token was issued on behalf of another user (RFC 8693)
 	jwt.RegisteredClaims
 }
 
+// Actor identifies the party acting on behalf of the token subject.
+type Actor struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// ClaimOpt customizes the claims of a generated token.
+type ClaimOpt func(*Claims)
+
+// WithRole sets the role claim.
+func WithRole(role string) ClaimOpt {
+	return func(c *Claims) {
+		c.Role = role
+	}
+}
+
+// WithActor sets the act claim identifying who acts on behalf of the subject.
+func WithActor(actorID uuid.UUID) ClaimOpt {
+	return func(c *Claims) {
+		c.Act = &Actor{UserID: actorID}
+	}
+}
+
+// WithTTL overrides the default token expiration for a single token.
+func WithTTL(d time.Duration) ClaimOpt {
+	return func(c *Claims) {
+		c.ExpiresAt = jwt.NewNumericDate(c.IssuedAt.Add(d))
+	}
+}
+
 // Opt defines a functional option for JWT.
 type Opt func(*JWT)
 
@@ -54,19 +85,23 @@ func New(opts ...Opt) *JWT {
 }
 
 // Generate creates a JWT token for a given userID.
-func (j *JWT) Generate(ctx context.Context, userID uuid.UUID) (string, error) {
+func (j *JWT) Generate(ctx context.Context, userID uuid.UUID, opts ...ClaimOpt) (string, error) {
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.exp)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.exp)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	for _, opt := range opts {
+		opt(claims)
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signed, err := token.SignedString([]byte(j.secretKey))
 	if err != nil {
-		logger.Log.Errorw("failed to generate JWT token", "err", err, "userID", userID)
+		logger.FromContext(ctx).Errorw("failed to generate JWT token", "err", err, "userID", userID)
 		return "", err
 	}
 	return signed, nil
@@ -82,12 +117,12 @@ func (j *JWT) Validate(ctx context.Context, tokenString string) error {
 		return []byte(j.secretKey), nil
 	})
 	if err != nil {
-		logger.Log.Errorw("JWT validation failed", "err", err)
+		logger.FromContext(ctx).Errorw("JWT validation failed", "err", err)
 		return err
 	}
 
 	if !token.Valid {
-		logger.Log.Error("JWT validation failed: token invalid")
+		logger.FromContext(ctx).Error("JWT validation failed: token invalid")
 		return errors.New("invalid token")
 	}
 
@@ -104,12 +139,12 @@ func (j *JWT) GetClaims(ctx context.Context, tokenString string) (*Claims, error
 		return []byte(j.secretKey), nil
 	})
 	if err != nil {
-		logger.Log.Errorw("failed to parse JWT", "err", err)
+		logger.FromContext(ctx).Errorw("failed to parse JWT", "err", err)
 		return nil, err
 	}
 
 	if !token.Valid {
-		logger.Log.Error("invalid JWT token")
+		logger.FromContext(ctx).Error("invalid JWT token")
 		return nil, errors.New("invalid token")
 	}
 
@@ -121,14 +156,14 @@ func (j *JWT) GetTokenFromRequest(ctx context.Context, r *http.Request) (string,
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		err := errors.New("authorization header missing")
-		logger.Log.Warn(err.Error())
+		logger.FromContext(ctx).Warn(err.Error())
 		return "", err
 	}
 
 	parts := strings.Fields(authHeader)
 	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
 		err := errors.New("invalid authorization header format")
-		logger.Log.Warn(err.Error())
+		logger.FromContext(ctx).Warn(err.Error())
 		return "", err
 	}
 
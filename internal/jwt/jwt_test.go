@@ -114,3 +114,23 @@ func TestJWT_Validate_WrongSecret(t *testing.T) {
 	err = j2.Validate(ctx, token)
 	assert.Error(t, err)
 }
+
+func TestJWT_GenerateWithClaimOpts(t *testing.T) {
+	j := New(WithSecretKey("test-secret"), WithExpiration(time.Hour))
+
+	userID := uuid.New()
+	adminID := uuid.New()
+	ctx := context.Background()
+
+	token, err := j.Generate(ctx, userID, WithRole("user"), WithActor(adminID), WithTTL(time.Minute))
+	assert.NoError(t, err)
+
+	claims, err := j.GetClaims(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, "user", claims.Role)
+	if assert.NotNil(t, claims.Act) {
+		assert.Equal(t, adminID, claims.Act.UserID)
+	}
+	assert.WithinDuration(t, time.Now().Add(time.Minute), claims.ExpiresAt.Time, 5*time.Second)
+}
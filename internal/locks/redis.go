@@ -0,0 +1,97 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+var (
+	// ErrNotAcquired is returned when the lock is already held by another owner.
+	ErrNotAcquired = errors.New("lock not acquired")
+	// ErrLockLost is returned when extending a lock that expired and may be held by another owner.
+	ErrLockLost = errors.New("lock lost")
+)
+
+// releaseScript deletes the key only if it still holds the owner's token,
+// so an expired lock re-acquired by another replica is never released by mistake.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendScript resets the TTL of the key only if it still holds the owner's token.
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RedisLocker provides distributed locks backed by Redis SET NX.
+type RedisLocker struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLocker creates a new RedisLocker instance.
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{
+		client: client,
+		prefix: "lock:",
+	}
+}
+
+// TryLock acquires the lock for key with the given TTL without blocking.
+// It returns ErrNotAcquired if the lock is held elsewhere, otherwise a release function.
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(ctx context.Context) error, error) {
+	_, release, err := l.TryLockExtendable(ctx, key, ttl)
+	return release, err
+}
+
+// TryLockExtendable acquires the lock for key like TryLock and also returns a function
+// resetting its TTL, so that a long-running owner can keep the lock. Extending fails with
+// ErrLockLost once the lock has expired.
+func (l *RedisLocker) TryLockExtendable(ctx context.Context, key string, ttl time.Duration) (
+	extend func(ctx context.Context) error, release func(ctx context.Context) error, err error,
+) {
+	lockKey := l.prefix + key
+	token := uuid.NewString()
+
+	ok, err := l.client.SetNX(ctx, lockKey, token, ttl).Result()
+
+	logger.FromContext(ctx).Debugw(
+		"key", lockKey,
+		"ttl", ttl,
+		"result", ok,
+		"error", err,
+	)
+
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, ErrNotAcquired
+	}
+
+	extend = func(ctx context.Context) error {
+		extended, err := extendScript.Run(ctx, l.client, []string{lockKey}, token, ttl.Milliseconds()).Int()
+		if err != nil {
+			return err
+		}
+		if extended == 0 {
+			return ErrLockLost
+		}
+		return nil
+	}
+	release = func(ctx context.Context) error {
+		return releaseScript.Run(ctx, l.client, []string{lockKey}, token).Err()
+	}
+	return extend, release, nil
+}
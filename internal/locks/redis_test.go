@@ -0,0 +1,71 @@
+package locks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisLocker(t *testing.T) {
+	ctx := context.Background()
+
+	rdb := testkit.Redis(t)
+
+	locker := NewRedisLocker(rdb)
+
+	t.Run("Second TryLock fails while held", func(t *testing.T) {
+		release, err := locker.TryLock(ctx, "held", time.Minute)
+		assert.NoError(t, err)
+
+		_, err = locker.TryLock(ctx, "held", time.Minute)
+		assert.ErrorIs(t, err, ErrNotAcquired)
+
+		assert.NoError(t, release(ctx))
+
+		_, err = locker.TryLock(ctx, "held", time.Minute)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Lock expires after TTL", func(t *testing.T) {
+		_, err := locker.TryLock(ctx, "expiring", time.Second)
+		assert.NoError(t, err)
+
+		time.Sleep(2 * time.Second)
+
+		_, err = locker.TryLock(ctx, "expiring", time.Minute)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Stale release does not drop new owner", func(t *testing.T) {
+		staleRelease, err := locker.TryLock(ctx, "stale", time.Second)
+		assert.NoError(t, err)
+
+		time.Sleep(2 * time.Second)
+
+		_, err = locker.TryLock(ctx, "stale", time.Minute)
+		assert.NoError(t, err)
+
+		assert.NoError(t, staleRelease(ctx))
+
+		_, err = locker.TryLock(ctx, "stale", time.Minute)
+		assert.ErrorIs(t, err, ErrNotAcquired)
+	})
+
+	t.Run("Extend keeps the lock past its TTL", func(t *testing.T) {
+		extend, release, err := locker.TryLockExtendable(ctx, "extended", time.Second)
+		assert.NoError(t, err)
+
+		time.Sleep(600 * time.Millisecond)
+		assert.NoError(t, extend(ctx))
+		time.Sleep(600 * time.Millisecond)
+
+		_, err = locker.TryLock(ctx, "extended", time.Minute)
+		assert.ErrorIs(t, err, ErrNotAcquired)
+
+		assert.NoError(t, release(ctx))
+		assert.ErrorIs(t, extend(ctx), ErrLockLost)
+	})
+}
@@ -1,6 +1,15 @@
 package logger
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -9,21 +18,195 @@ import (
 // Initialized with a no-op logger until Initialize is called.
 var Log *zap.SugaredLogger = zap.NewNop().Sugar()
 
+// Config configures the global logger. The zero value of every setting but Level keeps the
+// default of zap's production logger.
+type Config struct {
+	Level  string // Level of packages without their own level
+	Format string // json (default) or console
+	Output string // stderr (default), stdout or the path of a file
+
+	// Rotation of the log file, Output being a file path
+	MaxSizeMB  int // Size at which the file is rotated, 0 for 100 MB
+	MaxBackups int // Rotated files kept, 0 keeps all
+
+	// Sampling of debug and info entries: per second, the first SamplingInitial entries with
+	// the same message are logged, then every SamplingThereafter-th. Warnings and errors are
+	// never sampled. SamplingInitial 0 disables sampling.
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// Levels of packages, "name=level" with the name of the package, e.g. repositories=warn
+	PackageLevels []string
+}
+
+// output is the log file of Log, closed once Log is replaced.
+var (
+	outputMu sync.Mutex
+	output   io.Closer
+)
+
 // Initialize sets up the global logger with the given log level.
-func Initialize(level string) error {
-	lvl, err := zapcore.ParseLevel(level)
+// The optional key-value fields are added to every log entry.
+func Initialize(level string, fields ...any) error {
+	return Setup(Config{Level: level}, fields...)
+}
+
+// Setup sets up the global logger configured by cfg.
+// The optional key-value fields are added to every log entry.
+func Setup(cfg Config, fields ...any) error {
+	logger, closer, err := build(cfg)
 	if err != nil {
 		return err
 	}
 
-	cfg := zap.NewProductionConfig()
-	cfg.Level = zap.NewAtomicLevelAt(lvl)
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	previous := output
+	Log, output = logger.Sugar().With(fields...), closer
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// FromContext returns Log with the ID of the request of ctx added to every entry, or Log
+// outside of a request.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if id := requestid.FromContext(ctx); id != "" {
+		return Log.With("request_id", id)
+	}
+	return Log
+}
 
-	logger, err := cfg.Build()
+// build creates the logger configured by cfg, with the log file to close, if any.
+func build(cfg Config) (*zap.Logger, io.Closer, error) {
+	level, err := zapcore.ParseLevel(cfg.Level)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	packages, err := ParsePackageLevels(cfg.PackageLevels)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	Log = logger.Sugar()
-	return nil
+	encoderCfg := zap.NewProductionEncoderConfig()
+	var encoder zapcore.Encoder
+	switch cfg.Format {
+	case "", "json":
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	case "console":
+		encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q, want json or console", cfg.Format)
+	}
+
+	var (
+		sink   zapcore.WriteSyncer
+		closer io.Closer
+	)
+	switch cfg.Output {
+	case "", "stderr":
+		sink = zapcore.Lock(os.Stderr)
+	case "stdout":
+		sink = zapcore.Lock(os.Stdout)
+	default:
+		file, err := OpenRotatingFile(cfg.Output, cfg.MaxSizeMB, cfg.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink, closer = file, file
+	}
+
+	// The core logs at the lowest level of any package, packageCore filters by package
+	minLevel := level
+	for _, l := range packages {
+		minLevel = min(minLevel, l)
+	}
+	core := zapcore.NewCore(encoder, sink, minLevel)
+	if len(packages) > 0 {
+		core = &packageCore{Core: core, level: level, packages: packages}
+	}
+	if cfg.SamplingInitial > 0 {
+		core = &infoSampler{
+			Core:    core,
+			sampled: zapcore.NewSamplerWithOptions(core, time.Second, cfg.SamplingInitial, max(cfg.SamplingThereafter, 1)),
+		}
+	}
+
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel), zap.ErrorOutput(zapcore.Lock(os.Stderr))), closer, nil
+}
+
+// ParsePackageLevels parses the "name=level" levels of packages.
+func ParsePackageLevels(list []string) (map[string]zapcore.Level, error) {
+	levels := make(map[string]zapcore.Level, len(list))
+	for _, item := range list {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid package log level %q, want name=level", item)
+		}
+		level, err := zapcore.ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid package log level %q: %w", item, err)
+		}
+		levels[name] = level
+	}
+	return levels, nil
+}
+
+// packageCore logs the entries of a package at or above the level of the package, and those
+// of other packages at or above the default level. The package is that of the caller.
+type packageCore struct {
+	zapcore.Core
+	level    zapcore.Level
+	packages map[string]zapcore.Level
+}
+
+func (c *packageCore) With(fields []zapcore.Field) zapcore.Core {
+	return &packageCore{Core: c.Core.With(fields), level: c.level, packages: c.packages}
+}
+
+func (c *packageCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write is called with the caller of the entry, which Check is not.
+func (c *packageCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	level, ok := c.packages[packageName(ent.Caller.Function)]
+	if !ok {
+		level = c.level
+	}
+	if ent.Level < level {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// packageName returns the name of the package of a function, e.g. repositories for
+// github.com/sbilibin2017/gw-currency-wallet/internal/repositories.(*AuditWriteRepository).Save.
+func packageName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+// infoSampler samples debug and info entries, leaving warnings and errors to Core.
+type infoSampler struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+func (c *infoSampler) With(fields []zapcore.Field) zapcore.Core {
+	return &infoSampler{Core: c.Core.With(fields), sampled: c.sampled.With(fields)}
+}
+
+func (c *infoSampler) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < zapcore.WarnLevel {
+		return c.sampled.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
 }
@@ -1,10 +1,16 @@
 package logger
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestInitialize_ValidLevels(t *testing.T) {
@@ -52,3 +58,103 @@ func TestLog_NopBeforeInitialize(t *testing.T) {
 		Log.Infow("nop logger test")
 	})
 }
+
+func TestInitialize_WithFields(t *testing.T) {
+	// Save original Log and restore after test
+	originalLog := Log
+	defer func() { Log = originalLog }()
+
+	err := Initialize("info", "env", "test", "instance_id", "wallet-1")
+	assert.NoError(t, err)
+	assert.NotPanics(t, func() {
+		Log.Infow("test log")
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	originalLog := Log
+	defer func() { Log = originalLog }()
+
+	core, logs := observer.New(zap.InfoLevel)
+	Log = zap.New(core).Sugar()
+
+	FromContext(requestid.NewContext(context.Background(), "req-1")).Infow("in request")
+	FromContext(context.Background()).Infow("outside of request")
+
+	entries := logs.AllUntimed()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, map[string]any{"request_id": "req-1"}, entries[0].ContextMap())
+		assert.Empty(t, entries[1].ContextMap())
+	}
+}
+
+func TestSetup(t *testing.T) {
+	originalLog := Log
+	defer func() { Log = originalLog }()
+
+	// lines logs with cfg to a file and returns the lines written
+	lines := func(t *testing.T, cfg Config, log func()) []string {
+		cfg.Output = filepath.Join(t.TempDir(), "logs", "wallet.log")
+		assert.NoError(t, Setup(cfg, "env", "test"))
+		log()
+		assert.NoError(t, Log.Sync())
+		data, err := os.ReadFile(cfg.Output)
+		assert.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	t.Run("json", func(t *testing.T) {
+		got := lines(t, Config{Level: "info"}, func() { Log.Infow("hello", "key", 1) })
+		if assert.Len(t, got, 1) {
+			assert.Contains(t, got[0], `"msg":"hello"`)
+			assert.Contains(t, got[0], `"env":"test"`)
+		}
+	})
+
+	t.Run("console", func(t *testing.T) {
+		got := lines(t, Config{Level: "info", Format: "console"}, func() { Log.Infow("hello", "key", 1) })
+		if assert.Len(t, got, 1) {
+			assert.Contains(t, got[0], "INFO")
+			assert.Contains(t, got[0], "hello")
+			assert.NotContains(t, got[0], `"msg"`)
+		}
+	})
+
+	t.Run("package levels", func(t *testing.T) {
+		got := lines(t, Config{Level: "warn", PackageLevels: []string{"logger=debug", "repositories=error"}}, func() {
+			Log.Debugw("debug of logger")
+		})
+		assert.Len(t, got, 1)
+
+		got = lines(t, Config{Level: "debug", PackageLevels: []string{"logger=warn"}}, func() {
+			Log.Infow("info of logger")
+			Log.Warnw("warning of logger")
+		})
+		if assert.Len(t, got, 1) {
+			assert.Contains(t, got[0], "warning of logger")
+		}
+	})
+
+	t.Run("sampling leaves warnings", func(t *testing.T) {
+		got := lines(t, Config{Level: "info", SamplingInitial: 2, SamplingThereafter: 1000}, func() {
+			for range 5 {
+				Log.Infow("busy")
+				Log.Warnw("alarm")
+			}
+		})
+		assert.Equal(t, 2, strings.Count(strings.Join(got, "\n"), "busy"))
+		assert.Equal(t, 5, strings.Count(strings.Join(got, "\n"), "alarm"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Error(t, Setup(Config{Level: "info", Format: "xml"}))
+		assert.Error(t, Setup(Config{Level: "info", PackageLevels: []string{"repositories"}}))
+		assert.Error(t, Setup(Config{Level: "info", PackageLevels: []string{"repositories=loud"}}))
+	})
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "repositories", packageName("github.com/sbilibin2017/gw-currency-wallet/internal/repositories.(*AuditWriteRepository).Save"))
+	assert.Equal(t, "main", packageName("main.run"))
+	assert.Equal(t, "logger", packageName("github.com/sbilibin2017/gw-currency-wallet/internal/logger.TestSetup.func1"))
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSizeMB is the size at which a RotatingFile is rotated if none is configured.
+const DefaultMaxSizeMB = 100
+
+// backupTimeFormat names the rotated files, so that they sort by age.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// RotatingFile is a log file that is renamed to <path>.<time> once writing to it would make it
+// larger than its maximum size, the oldest rotated files being removed beyond a maximum count.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	rotatedAt time.Time // Of the last rotation, each rotated file being named after a later time
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it and its directory if
+// needed. maxSizeMB 0 rotates at DefaultMaxSizeMB, maxBackups 0 keeps all rotated files.
+func OpenRotatingFile(path string, maxSizeMB, maxBackups int) (*RotatingFile, error) {
+	if maxSizeMB <= 0 {
+		maxSizeMB = DefaultMaxSizeMB
+	}
+	f := &RotatingFile{path: path, maxSize: int64(maxSizeMB) << 20, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p does not fit.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the file to disk.
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// open opens the file for appending.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("open log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate renames the file, opens a new one and removes the rotated files beyond maxBackups.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	rotatedAt := time.Now().UTC()
+	if !rotatedAt.After(f.rotatedAt) {
+		rotatedAt = f.rotatedAt.Add(time.Nanosecond)
+	}
+	f.rotatedAt = rotatedAt
+	renameErr := os.Rename(f.path, f.path+"."+rotatedAt.Format(backupTimeFormat))
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return fmt.Errorf("rotate log file: %w", renameErr)
+	}
+	if f.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(f.path + ".[0-9]*")
+	if err != nil || len(backups) <= f.maxBackups {
+		return nil
+	}
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-f.maxBackups] {
+		os.Remove(backup)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.log")
+	assert.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	f, err := OpenRotatingFile(path, 1, 2)
+	assert.NoError(t, err)
+	defer f.Close()
+	f.maxSize = 10 // Bytes
+
+	// The existing file is appended to until a line no longer fits
+	write := func(line string) {
+		_, err := f.Write([]byte(line + "\n"))
+		assert.NoError(t, err)
+	}
+	write("first")
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "old\nfirst\n", string(data))
+
+	write("second")
+	write("third")
+	write("fourth")
+	write("fifth")
+	data, _ = os.ReadFile(path)
+	assert.Equal(t, "fifth\n", string(data))
+
+	// Only the two newest rotated files are kept
+	backups, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	if assert.Len(t, backups, 2) {
+		first, _ := os.ReadFile(backups[0])
+		second, _ := os.ReadFile(backups[1])
+		assert.Equal(t, "third\n", string(first))
+		assert.Equal(t, "fourth\n", string(second))
+	}
+	assert.False(t, strings.HasSuffix(backups[0], ".log"))
+}
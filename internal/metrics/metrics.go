@@ -0,0 +1,329 @@
+// Package metrics declares the Prometheus metrics exported by the service.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gw_currency_wallet"
+
+// Registration rejection reasons
+const (
+	ReasonDomainNotAllowed = "domain_not_allowed"
+	ReasonDomainRateLimit  = "domain_rate_limited"
+)
+
+// RegistrationRejections counts registrations rejected by the email domain policy.
+var RegistrationRejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registration_rejections_total",
+		Help:      "Number of registrations rejected by the email domain policy.",
+	},
+	[]string{"reason"},
+)
+
+// RateCacheTTL is the current freshness TTL of cached exchange rates, adapted to the exchanger health.
+var RateCacheTTL = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rate_cache_ttl_seconds",
+		Help:      "Current freshness TTL of cached exchange rates in seconds.",
+	},
+)
+
+// StaleRatesServed counts cached exchange rates served past their TTL because the exchanger failed.
+var StaleRatesServed = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stale_rates_served_total",
+		Help:      "Number of stale cached exchange rates served while the exchanger was unavailable.",
+	},
+)
+
+// CrossRatesServed counts exchange rates derived through the pivot currency because the exchanger had no direct rate.
+var CrossRatesServed = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cross_rates_served_total",
+		Help:      "Number of exchange rates derived through the pivot currency for pairs without a direct rate.",
+	},
+)
+
+// TxRetries counts database transactions run again after a serialization failure or a deadlock.
+var TxRetries = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tx_retries_total",
+		Help:      "Number of database transactions run again after a serialization failure or a deadlock.",
+	},
+)
+
+// ExchangerRetries counts exchanger gRPC calls retried after a transient failure, by method.
+var ExchangerRetries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "exchanger_retries_total",
+		Help:      "Number of exchanger gRPC calls retried after a transient failure, by method.",
+	},
+	[]string{"method"},
+)
+
+// RateProviderHealthy reports by provider whether a rate provider is used (1) or skipped after repeated failures (0).
+var RateProviderHealthy = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rate_provider_healthy",
+		Help:      "Whether a rate provider is used (1) or skipped after repeated failures (0), by provider.",
+	},
+	[]string{"provider"},
+)
+
+// RateFetchesShared counts callers that shared the exchanger call of a concurrent fetch of the same pair.
+var RateFetchesShared = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_fetches_shared_total",
+		Help:      "Number of exchange rate fetches served by a concurrent exchanger call for the same pair.",
+	},
+)
+
+// RatesPrewarmed counts currency-pair rates refreshed in the cache by the pre-warming job.
+var RatesPrewarmed = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rates_prewarmed_total",
+		Help:      "Number of currency-pair exchange rates refreshed in the cache ahead of their TTL.",
+	},
+)
+
+// TransactionsArchived counts transactions moved from the database to the archive with their monthly partitions.
+var TransactionsArchived = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_archived_total",
+		Help:      "Number of transactions moved from the database to the archive with their monthly partitions.",
+	},
+)
+
+// LedgerMismatches is the number of wallets whose balance disagreed with the ledger at the last reconciliation.
+var LedgerMismatches = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ledger_mismatches",
+		Help:      "Number of wallets whose balance disagreed with the ledger at the last reconciliation.",
+	},
+)
+
+// ExchangeMismatches is the number of exchanges that disagreed with the exchanger's records at the last reconciliation.
+var ExchangeMismatches = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exchange_mismatches",
+		Help:      "Number of exchanges that disagreed with the exchanger's records at the last reconciliation.",
+	},
+)
+
+// LegacyBalanceResponses counts wallet responses rendered with the deprecated USD/RUB/EUR balance object.
+var LegacyBalanceResponses = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "legacy_balance_responses_total",
+		Help:      "Number of wallet responses rendered with the deprecated USD/RUB/EUR balance object.",
+	},
+)
+
+// Webhook delivery results
+const (
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+	WebhookAbandoned = "abandoned"
+)
+
+// WebhookDeliveries counts attempts to deliver wallet events to webhooks by result.
+var WebhookDeliveries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Number of attempts to deliver wallet events to webhooks by result.",
+	},
+	[]string{"result"},
+)
+
+// Consumed message results
+const (
+	MessageProcessed = "processed"
+	MessageSkipped   = "skipped"
+	MessageFailed    = "failed"
+)
+
+// ConsumedMessages counts attempts to process Kafka messages by consumer and result.
+var ConsumedMessages = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "consumed_messages_total",
+		Help:      "Number of attempts to process Kafka messages by consumer and result.",
+	},
+	[]string{"consumer", "result"},
+)
+
+// DeadLetteredEvents counts Kafka events kept as dead letters because every publishing attempt failed.
+var DeadLetteredEvents = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_lettered_events_total",
+		Help:      "Number of Kafka events kept as dead letters after every publishing attempt failed.",
+	},
+)
+
+// BufferedEvents is the number of events buffered for asynchronous publishing.
+var BufferedEvents = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "buffered_events",
+		Help:      "Number of events buffered for asynchronous publishing.",
+	},
+)
+
+// BackpressuredEvents counts events published synchronously because the event buffer was full.
+var BackpressuredEvents = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "backpressured_events_total",
+		Help:      "Number of events published synchronously because the event buffer was full.",
+	},
+)
+
+// HTTPRequests counts HTTP requests by route name, method and status code.
+var HTTPRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route, method and status code.",
+	},
+	[]string{"route", "method", "status"},
+)
+
+// HTTPRequestDuration observes the latency of HTTP requests by route name, method and status code.
+var HTTPRequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests in seconds by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"route", "method", "status"},
+)
+
+// Cache lookup results
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// CacheRequests counts lookups of the Redis caches by cache and result.
+var CacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Number of Redis cache lookups by cache and result.",
+	},
+	[]string{"cache", "result"},
+)
+
+// ExchangerCallDuration observes the latency of exchanger gRPC call attempts by method and status code.
+var ExchangerCallDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "exchanger_call_duration_seconds",
+		Help:      "Latency of exchanger gRPC call attempts in seconds by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"method", "code"},
+)
+
+// Event publishing results
+const (
+	EventPublished = "published"
+	EventFailed    = "failed"
+)
+
+// PublishedEvents counts attempts to publish events to the broker by topic and result.
+var PublishedEvents = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "published_events_total",
+		Help:      "Number of attempts to publish events to the broker by topic and result.",
+	},
+	[]string{"topic", "result"},
+)
+
+// Registry holds all service metrics together with the Go runtime and process collectors.
+var Registry = newRegistry(nil)
+
+var (
+	constLabels prometheus.Labels                   // Labels of every series, set by SetConstLabels
+	dbStats     = map[string]prometheus.Collector{} // Connection pool stats by database name, set by RegisterDBStats
+)
+
+// newRegistry registers the collectors with the constant labels added to every series.
+func newRegistry(labels prometheus.Labels) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registerer := prometheus.WrapRegistererWith(labels, registry)
+	for _, stats := range dbStats {
+		registerer.MustRegister(stats)
+	}
+	registerer.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		RegistrationRejections,
+		RateCacheTTL,
+		StaleRatesServed,
+		CrossRatesServed,
+		ExchangerRetries,
+		TxRetries,
+		RateProviderHealthy,
+		RatesPrewarmed,
+		RateFetchesShared,
+		TransactionsArchived,
+		LedgerMismatches,
+		ExchangeMismatches,
+		LegacyBalanceResponses,
+		WebhookDeliveries,
+		ConsumedMessages,
+		DeadLetteredEvents,
+		BufferedEvents,
+		BackpressuredEvents,
+		HTTPRequests,
+		HTTPRequestDuration,
+		CacheRequests,
+		ExchangerCallDuration,
+		PublishedEvents,
+	)
+	return registry
+}
+
+// SetConstLabels rebuilds the Registry so that every series carries the labels,
+// e.g. the deployment environment. It must be called before Handler.
+func SetConstLabels(labels map[string]string) {
+	constLabels = labels
+	Registry = newRegistry(labels)
+}
+
+// RegisterDBStats adds the connection pool stats of db, e.g. open and in-use connections,
+// waits for a connection and connections closed by the lifetime limits, to the Registry
+// under the db_name label name. It must be called before Handler.
+func RegisterDBStats(name string, db *sql.DB) {
+	dbStats[name] = collectors.NewDBStatsCollector(db, name)
+	Registry = newRegistry(constLabels)
+}
+
+// Handler returns an HTTP handler exposing the metrics in the Prometheus format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConstLabels(t *testing.T) {
+	original := Registry
+	defer func() { Registry = original }()
+
+	RegistrationRejections.WithLabelValues(ReasonDomainNotAllowed).Inc()
+	SetConstLabels(map[string]string{"env": "production", "instance_id": "wallet-1"})
+
+	families, err := Registry.Gather()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, families)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, "production", labels["env"], family.GetName())
+			assert.Equal(t, "wallet-1", labels["instance_id"], family.GetName())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), `gw_currency_wallet_registration_rejections_total{env="production",instance_id="wallet-1",reason="domain_not_allowed"}`))
+}
+
+func TestRegisterDBStats(t *testing.T) {
+	original := Registry
+	defer func() { Registry = original; delete(dbStats, "test_db") }()
+
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+
+	RegisterDBStats("test_db", db)
+	RegisterDBStats("test_db", db) // Registering again replaces the stats
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Regexp(t, `go_sql_max_open_connections\{db_name="test_db"[^}]*\} 7`, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `go_sql_max_lifetime_closed_total{db_name="test_db"`)
+}
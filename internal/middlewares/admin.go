@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// AdminTokener defines the minimal interface needed by the admin middleware
+type AdminTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// AdminMiddleware returns a middleware that allows only admin tokens.
+// Impersonation tokens never grant admin access, even if issued by an admin.
+func AdminMiddleware(tokener AdminTokener) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			tokenString, err := tokener.GetTokenFromRequest(ctx, r)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("admin authorization failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
+				return
+			}
+
+			claims, err := tokener.GetClaims(ctx, tokenString)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("admin authorization failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
+				return
+			}
+
+			if claims.Role != models.RoleAdmin || claims.Act != nil {
+				logger.FromContext(ctx).Warnw("admin access denied", "userID", claims.UserID, "role", claims.Role)
+				apperrors.Write(w, apperrors.Forbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name             string
+		mockSetup        func(m *MockAdminTokener)
+		expectedStatus   int
+		expectNextCalled bool
+	}{
+		{
+			name: "NoToken",
+			mockSetup: func(m *MockAdminTokener) {
+				m.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return("", errors.New("no token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "InvalidToken",
+			mockSetup: func(m *MockAdminTokener) {
+				m.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("badtoken", nil)
+				m.EXPECT().GetClaims(gomock.Any(), "badtoken").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "NotAdmin",
+			mockSetup: func(m *MockAdminTokener) {
+				m.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("usertoken", nil)
+				m.EXPECT().GetClaims(gomock.Any(), "usertoken").
+					Return(&jwt.Claims{UserID: uuid.New(), Role: models.RoleUser}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "ImpersonatedToken",
+			mockSetup: func(m *MockAdminTokener) {
+				m.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("imptoken", nil)
+				m.EXPECT().GetClaims(gomock.Any(), "imptoken").
+					Return(&jwt.Claims{UserID: uuid.New(), Role: models.RoleAdmin, Act: &jwt.Actor{UserID: uuid.New()}}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Admin",
+			mockSetup: func(m *MockAdminTokener) {
+				m.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("admintoken", nil)
+				m.EXPECT().GetClaims(gomock.Any(), "admintoken").
+					Return(&jwt.Claims{UserID: uuid.New(), Role: models.RoleAdmin}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTokener := NewMockAdminTokener(ctrl)
+			tt.mockSetup(mockTokener)
+
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/admin", nil)
+			rr := httptest.NewRecorder()
+
+			AdminMiddleware(mockTokener)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCalled, nextCalled)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
 )
 
@@ -21,14 +22,14 @@ func AuthMiddleware(tokener Tokener) func(http.Handler) http.Handler {
 
 			tokenString, err := tokener.GetTokenFromRequest(ctx, r)
 			if err != nil {
-				logger.Log.Errorw("authorization failed", "err", err)
-				w.WriteHeader(http.StatusUnauthorized)
+				logger.FromContext(ctx).Errorw("authorization failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
 				return
 			}
 
 			if err := tokener.Validate(ctx, tokenString); err != nil {
-				logger.Log.Errorw("authorization failed", "err", err)
-				w.WriteHeader(http.StatusUnauthorized)
+				logger.FromContext(ctx).Errorw("authorization failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
 				return
 			}
 
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DeadlineMiddleware gives every request a time budget. Downstream calls derive
+// their deadlines from the request context, so work stops once the budget is spent
+// or the client goes away. A non-positive timeout disables the budget.
+func DeadlineMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
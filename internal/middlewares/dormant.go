@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// DormantTokener defines the minimal interface needed by the dormant middleware
+type DormantTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// DormancyChecker reports whether an account is in cold storage
+type DormancyChecker interface {
+	IsDormant(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// DormantMiddleware returns a middleware that blocks dormant accounts until they are re-verified.
+func DormantMiddleware(tokener DormantTokener, checker DormancyChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			tokenString, err := tokener.GetTokenFromRequest(ctx, r)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("dormancy check failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
+				return
+			}
+
+			claims, err := tokener.GetClaims(ctx, tokenString)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("dormancy check failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
+				return
+			}
+
+			dormant, err := checker.IsDormant(ctx, claims.UserID)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("dormancy check failed", "userID", claims.UserID, "err", err)
+				apperrors.Write(w, apperrors.Internal)
+				return
+			}
+
+			if dormant {
+				logger.FromContext(ctx).Warnw("dormant account access denied", "userID", claims.UserID)
+				w.Header().Set("Content-Type", "application/json")
+				apperrors.Write(w, apperrors.AccountDormant)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
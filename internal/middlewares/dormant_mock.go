@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/middlewares/dormant.go
+
+// Package middlewares is a generated GoMock package.
+package middlewares
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+)
+
+// MockDormantTokener is a mock of DormantTokener interface.
+type MockDormantTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockDormantTokenerMockRecorder
+}
+
+// MockDormantTokenerMockRecorder is the mock recorder for MockDormantTokener.
+type MockDormantTokenerMockRecorder struct {
+	mock *MockDormantTokener
+}
+
+// NewMockDormantTokener creates a new mock instance.
+func NewMockDormantTokener(ctrl *gomock.Controller) *MockDormantTokener {
+	mock := &MockDormantTokener{ctrl: ctrl}
+	mock.recorder = &MockDormantTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDormantTokener) EXPECT() *MockDormantTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockDormantTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockDormantTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockDormantTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockDormantTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockDormantTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockDormantTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockDormancyChecker is a mock of DormancyChecker interface.
+type MockDormancyChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockDormancyCheckerMockRecorder
+}
+
+// MockDormancyCheckerMockRecorder is the mock recorder for MockDormancyChecker.
+type MockDormancyCheckerMockRecorder struct {
+	mock *MockDormancyChecker
+}
+
+// NewMockDormancyChecker creates a new mock instance.
+func NewMockDormancyChecker(ctrl *gomock.Controller) *MockDormancyChecker {
+	mock := &MockDormancyChecker{ctrl: ctrl}
+	mock.recorder = &MockDormancyCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDormancyChecker) EXPECT() *MockDormancyCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsDormant mocks base method.
+func (m *MockDormancyChecker) IsDormant(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDormant", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDormant indicates an expected call of IsDormant.
+func (mr *MockDormancyCheckerMockRecorder) IsDormant(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDormant", reflect.TypeOf((*MockDormancyChecker)(nil).IsDormant), ctx, userID)
+}
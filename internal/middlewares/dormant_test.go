@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDormantMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name             string
+		mockSetup        func(tokener *MockDormantTokener, checker *MockDormancyChecker)
+		expectedStatus   int
+		expectNextCalled bool
+	}{
+		{
+			name: "NoToken",
+			mockSetup: func(tokener *MockDormantTokener, checker *MockDormancyChecker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return("", errors.New("no token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "InvalidToken",
+			mockSetup: func(tokener *MockDormantTokener, checker *MockDormancyChecker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("badtoken", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "badtoken").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "CheckError",
+			mockSetup: func(tokener *MockDormantTokener, checker *MockDormancyChecker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				checker.EXPECT().IsDormant(gomock.Any(), userID).Return(false, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Dormant",
+			mockSetup: func(tokener *MockDormantTokener, checker *MockDormancyChecker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				checker.EXPECT().IsDormant(gomock.Any(), userID).Return(true, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "Active",
+			mockSetup: func(tokener *MockDormantTokener, checker *MockDormancyChecker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				checker.EXPECT().IsDormant(gomock.Any(), userID).Return(false, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTokener := NewMockDormantTokener(ctrl)
+			mockChecker := NewMockDormancyChecker(ctrl)
+			tt.mockSetup(mockTokener, mockChecker)
+
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/deposit", nil)
+			rr := httptest.NewRecorder()
+
+			DormantMiddleware(mockTokener, mockChecker)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCalled, nextCalled)
+		})
+	}
+}
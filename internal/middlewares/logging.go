@@ -4,15 +4,21 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
 	"go.uber.org/zap"
 )
 
-// LoggingMiddleware logs requests and responses, generating a unique request ID for each request.
+// LoggingMiddleware logs requests and responses, identifying each request by the X-Request-ID
+// it was sent with, or by a new unique ID. The ID is stored in the request context, so that it
+// is logged, audited and passed on to the exchanger and Kafka.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		reqID := uuid.New().String()
+		reqID := r.Header.Get(requestid.Header)
+		if !requestid.Valid(reqID) {
+			reqID = requestid.New()
+		}
+		r = r.WithContext(requestid.NewContext(r.Context(), reqID))
 		start := time.Now()
 
 		rw := &responseWriter{
@@ -20,7 +26,7 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 			statusCode:     http.StatusOK,
 		}
 
-		w.Header().Set("X-Request-ID", reqID)
+		w.Header().Set(requestid.Header, reqID)
 
 		// Call the next handler
 		next.ServeHTTP(rw, r)
@@ -61,3 +67,8 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	rw.size += size
 	return size, err
 }
+
+// Unwrap returns the wrapped writer, so http.ResponseController can flush streamed responses.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +64,39 @@ func TestLoggingMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingMiddleware_RequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "incoming ID is kept", incoming: "req-123", keep: true},
+		{name: "missing ID is generated"},
+		{name: "ID forging log lines is replaced", incoming: "req\nlevel=error"},
+		{name: "overlong ID is replaced", incoming: strings.Repeat("a", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID string
+			handler := LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = requestid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(requestid.Header, tt.incoming)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			reqID := rr.Header().Get(requestid.Header)
+			assert.Equal(t, reqID, ctxID)
+			if tt.keep {
+				assert.Equal(t, tt.incoming, reqID)
+			} else {
+				assert.NotEqual(t, tt.incoming, reqID)
+				assert.True(t, requestid.Valid(reqID))
+			}
+		})
+	}
+}
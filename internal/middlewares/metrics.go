@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+)
+
+// MetricsMiddleware counts the requests of the route named route and observes their latency
+// by method and status code. Routes are labelled by name rather than path, so path
+// parameters do not create a series per value.
+func MetricsMiddleware(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			status := strconv.Itoa(rw.statusCode)
+			metrics.HTTPRequests.WithLabelValues(route, r.Method, status).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
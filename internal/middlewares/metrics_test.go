@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	handler := MetricsMiddleware("deposit")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	requests := metrics.HTTPRequests.WithLabelValues("deposit", http.MethodPost, "400")
+	before := testutil.ToFloat64(requests)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/wallet/deposit", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(requests))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestDuration, "gw_currency_wallet_http_request_duration_seconds"))
+}
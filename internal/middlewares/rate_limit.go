@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// RateLimitTokener defines the minimal interface needed by the rate limit middleware
+type RateLimitTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// RateLimitCounter counts requests in fixed windows shared by all replicas
+type RateLimitCounter interface {
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// RateLimitMiddleware returns a middleware allowing at most limit requests of a class per window.
+// Requests are counted per user when they carry a valid token and per client IP otherwise,
+// so it must run after RealIPMiddleware. A non-positive limit disables the middleware. The limit fails
+// open: if the counter is unavailable the request is let through.
+func RateLimitMiddleware(tokener RateLimitTokener, counter RateLimitCounter, class string, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			subject := "ip:" + clientIP(r)
+			if tokenString, err := tokener.GetTokenFromRequest(ctx, r); err == nil {
+				if claims, err := tokener.GetClaims(ctx, tokenString); err == nil {
+					subject = "user:" + claims.UserID.String()
+				}
+			}
+
+			count, err := counter.Increment(ctx, class+":"+subject, window)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("rate limit check failed", "class", class, "subject", subject, "err", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if count > int64(limit) {
+				logger.FromContext(ctx).Warnw("rate limit exceeded", "class", class, "subject", subject, "count", count)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", strconv.Itoa(int(window.Seconds())))
+				apperrors.Write(w, apperrors.TooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the host part of the request's remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
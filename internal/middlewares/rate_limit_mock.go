@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/middlewares/rate_limit.go
+
+// Package middlewares is a generated GoMock package.
+package middlewares
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+)
+
+// MockRateLimitTokener is a mock of RateLimitTokener interface.
+type MockRateLimitTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitTokenerMockRecorder
+}
+
+// MockRateLimitTokenerMockRecorder is the mock recorder for MockRateLimitTokener.
+type MockRateLimitTokenerMockRecorder struct {
+	mock *MockRateLimitTokener
+}
+
+// NewMockRateLimitTokener creates a new mock instance.
+func NewMockRateLimitTokener(ctrl *gomock.Controller) *MockRateLimitTokener {
+	mock := &MockRateLimitTokener{ctrl: ctrl}
+	mock.recorder = &MockRateLimitTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitTokener) EXPECT() *MockRateLimitTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockRateLimitTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockRateLimitTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockRateLimitTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockRateLimitTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockRateLimitTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockRateLimitTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockRateLimitCounter is a mock of RateLimitCounter interface.
+type MockRateLimitCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitCounterMockRecorder
+}
+
+// MockRateLimitCounterMockRecorder is the mock recorder for MockRateLimitCounter.
+type MockRateLimitCounterMockRecorder struct {
+	mock *MockRateLimitCounter
+}
+
+// NewMockRateLimitCounter creates a new mock instance.
+func NewMockRateLimitCounter(ctrl *gomock.Controller) *MockRateLimitCounter {
+	mock := &MockRateLimitCounter{ctrl: ctrl}
+	mock.recorder = &MockRateLimitCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitCounter) EXPECT() *MockRateLimitCounterMockRecorder {
+	return m.recorder
+}
+
+// Increment mocks base method.
+func (m *MockRateLimitCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockRateLimitCounterMockRecorder) Increment(ctx, key, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockRateLimitCounter)(nil).Increment), ctx, key, window)
+}
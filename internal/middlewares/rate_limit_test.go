@@ -0,0 +1,116 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name             string
+		limit            int
+		mockSetup        func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter)
+		expectedStatus   int
+		expectNextCalled bool
+	}{
+		{
+			name:  "PerUserWithinLimit",
+			limit: 2,
+			mockSetup: func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				counter.EXPECT().Increment(gomock.Any(), "write:user:"+userID.String(), time.Minute).Return(int64(2), nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+		{
+			name:  "PerIPWithoutToken",
+			limit: 2,
+			mockSetup: func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+				counter.EXPECT().Increment(gomock.Any(), "write:ip:192.0.2.1", time.Minute).Return(int64(1), nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+		{
+			name:  "PerIPWithInvalidToken",
+			limit: 2,
+			mockSetup: func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("badtoken", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "badtoken").Return(nil, errors.New("invalid token"))
+				counter.EXPECT().Increment(gomock.Any(), "write:ip:192.0.2.1", time.Minute).Return(int64(1), nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+		{
+			name:  "Exceeded",
+			limit: 2,
+			mockSetup: func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				counter.EXPECT().Increment(gomock.Any(), "write:user:"+userID.String(), time.Minute).Return(int64(3), nil)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:  "CounterErrorFailsOpen",
+			limit: 2,
+			mockSetup: func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("", errors.New("no token"))
+				counter.EXPECT().Increment(gomock.Any(), gomock.Any(), time.Minute).Return(int64(0), errors.New("redis down"))
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+		{
+			name:             "Disabled",
+			limit:            0,
+			mockSetup:        func(tokener *MockRateLimitTokener, counter *MockRateLimitCounter) {},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTokener := NewMockRateLimitTokener(ctrl)
+			mockCounter := NewMockRateLimitCounter(ctrl)
+			tt.mockSetup(mockTokener, mockCounter)
+
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/deposit", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			rr := httptest.NewRecorder()
+
+			RateLimitMiddleware(mockTokener, mockCounter, "write", tt.limit, time.Minute)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCalled, nextCalled)
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+				assert.JSONEq(t, `{"error":"Too many requests","code":"too_many_requests"}`, rr.Body.String())
+			}
+		})
+	}
+}
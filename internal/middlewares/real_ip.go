@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIPMiddleware sets the request's RemoteAddr to the client IP forwarded by a trusted
+// proxy. Proxy headers are honoured only when the peer is in one of the trusted ranges: the
+// client is the rightmost X-Forwarded-For address outside of them, or else X-Real-IP.
+// Requests from other peers keep their RemoteAddr, so clients cannot forge their IP.
+// Without trusted ranges the middleware does nothing.
+func RealIPMiddleware(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, err := netip.ParseAddr(clientIP(r)); err == nil && isTrusted(trusted, peer) {
+				if ip, ok := forwardedIP(r, trusted); ok {
+					r.RemoteAddr = ip.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP returns the client IP of the proxy headers of r, skipping trusted proxies.
+func forwardedIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip, err := netip.ParseAddr(hop)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		if !isTrusted(trusted, ip) {
+			return ip, true
+		}
+	}
+	if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return ip, true
+	}
+	return netip.Addr{}, false
+}
+
+// isTrusted reports whether ip is in one of the trusted ranges.
+func isTrusted(trusted []netip.Prefix, ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		trusted    []netip.Prefix
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "untrusted peer keeps its address",
+			trusted:    trusted,
+			remoteAddr: "203.0.113.7:51000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "198.51.100.2"},
+			want:       "203.0.113.7:51000",
+		},
+		{
+			name:       "no trusted proxies",
+			remoteAddr: "10.0.0.2:51000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "10.0.0.2:51000",
+		},
+		{
+			name:       "rightmost untrusted forwarded address",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.2:51000",
+			headers:    map[string]string{"X-Forwarded-For": "192.0.2.99, 198.51.100.1, 10.0.0.3"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "real IP header",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.2:51000",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.2"},
+			want:       "198.51.100.2",
+		},
+		{
+			name:       "invalid forwarded address",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.2:51000",
+			headers:    map[string]string{"X-Forwarded-For": "unknown"},
+			want:       "10.0.0.2:51000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := RealIPMiddleware(tt.trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/apperrors"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// userLockRetryInterval is how often a busy user lock is retried while waiting.
+const userLockRetryInterval = 25 * time.Millisecond
+
+// UserLockTokener defines the minimal interface needed by the user lock middleware
+type UserLockTokener interface {
+	GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error)
+	GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error)
+}
+
+// UserLocker defines a distributed lock shared by all replicas
+type UserLocker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (func(ctx context.Context) error, error)
+}
+
+// UserLockMiddleware returns a middleware that serializes money-moving requests of the same user
+// across replicas. A request waits up to wait for the user's lock, held for at most ttl, and is
+// rejected with 409 Conflict if it is still busy, so a retry storm cannot interleave operations.
+func UserLockMiddleware(tokener UserLockTokener, locker UserLocker, ttl, wait time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			tokenString, err := tokener.GetTokenFromRequest(ctx, r)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("user lock failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
+				return
+			}
+
+			claims, err := tokener.GetClaims(ctx, tokenString)
+			if err != nil {
+				logger.FromContext(ctx).Errorw("user lock failed", "err", err)
+				apperrors.Write(w, apperrors.Unauthorized)
+				return
+			}
+
+			release, err := AcquireUserLock(ctx, locker, "user:"+claims.UserID.String(), ttl, wait)
+			if errors.Is(err, locks.ErrNotAcquired) {
+				logger.FromContext(ctx).Warnw("concurrent money operation rejected", "userID", claims.UserID)
+				w.Header().Set("Content-Type", "application/json")
+				apperrors.Write(w, apperrors.OperationInProgress)
+				return
+			}
+			if err != nil {
+				logger.FromContext(ctx).Errorw("user lock failed", "userID", claims.UserID, "err", err)
+				apperrors.Write(w, apperrors.Internal)
+				return
+			}
+			defer func() {
+				// The request context may be cancelled by now, the lock must be released anyway
+				if err := release(context.WithoutCancel(ctx)); err != nil {
+					logger.FromContext(ctx).Errorw("failed to release user lock", "userID", claims.UserID, "err", err)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AcquireUserLock retries the lock until it is acquired, wait elapses or ctx is done.
+// It returns locks.ErrNotAcquired if the lock stays busy.
+func AcquireUserLock(ctx context.Context, locker UserLocker, key string, ttl, wait time.Duration) (func(ctx context.Context) error, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		release, err := locker.TryLock(ctx, key, ttl)
+		if !errors.Is(err, locks.ErrNotAcquired) || time.Now().Add(userLockRetryInterval).After(deadline) {
+			return release, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, locks.ErrNotAcquired
+		case <-time.After(userLockRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/middlewares/user_lock.go
+
+// Package middlewares is a generated GoMock package.
+package middlewares
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+)
+
+// MockUserLockTokener is a mock of UserLockTokener interface.
+type MockUserLockTokener struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserLockTokenerMockRecorder
+}
+
+// MockUserLockTokenerMockRecorder is the mock recorder for MockUserLockTokener.
+type MockUserLockTokenerMockRecorder struct {
+	mock *MockUserLockTokener
+}
+
+// NewMockUserLockTokener creates a new mock instance.
+func NewMockUserLockTokener(ctrl *gomock.Controller) *MockUserLockTokener {
+	mock := &MockUserLockTokener{ctrl: ctrl}
+	mock.recorder = &MockUserLockTokenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserLockTokener) EXPECT() *MockUserLockTokenerMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockUserLockTokener) GetClaims(ctx context.Context, tokenString string) (*jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, tokenString)
+	ret0, _ := ret[0].(*jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockUserLockTokenerMockRecorder) GetClaims(ctx, tokenString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockUserLockTokener)(nil).GetClaims), ctx, tokenString)
+}
+
+// GetTokenFromRequest mocks base method.
+func (m *MockUserLockTokener) GetTokenFromRequest(ctx context.Context, r *http.Request) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenFromRequest", ctx, r)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenFromRequest indicates an expected call of GetTokenFromRequest.
+func (mr *MockUserLockTokenerMockRecorder) GetTokenFromRequest(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRequest", reflect.TypeOf((*MockUserLockTokener)(nil).GetTokenFromRequest), ctx, r)
+}
+
+// MockUserLocker is a mock of UserLocker interface.
+type MockUserLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserLockerMockRecorder
+}
+
+// MockUserLockerMockRecorder is the mock recorder for MockUserLocker.
+type MockUserLockerMockRecorder struct {
+	mock *MockUserLocker
+}
+
+// NewMockUserLocker creates a new mock instance.
+func NewMockUserLocker(ctrl *gomock.Controller) *MockUserLocker {
+	mock := &MockUserLocker{ctrl: ctrl}
+	mock.recorder = &MockUserLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserLocker) EXPECT() *MockUserLockerMockRecorder {
+	return m.recorder
+}
+
+// TryLock mocks base method.
+func (m *MockUserLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(context.Context) error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryLock", ctx, key, ttl)
+	ret0, _ := ret[0].(func(context.Context) error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryLock indicates an expected call of TryLock.
+func (mr *MockUserLockerMockRecorder) TryLock(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryLock", reflect.TypeOf((*MockUserLocker)(nil).TryLock), ctx, key, ttl)
+}
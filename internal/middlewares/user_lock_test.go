@@ -0,0 +1,111 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/locks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserLockMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	key := "user:" + userID.String()
+	released := 0
+	release := func(ctx context.Context) error {
+		released++
+		return nil
+	}
+
+	tests := []struct {
+		name             string
+		mockSetup        func(tokener *MockUserLockTokener, locker *MockUserLocker)
+		expectedStatus   int
+		expectNextCalled bool
+		expectReleased   int
+	}{
+		{
+			name: "NoToken",
+			mockSetup: func(tokener *MockUserLockTokener, locker *MockUserLocker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).
+					Return("", errors.New("no token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "InvalidToken",
+			mockSetup: func(tokener *MockUserLockTokener, locker *MockUserLocker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("badtoken", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "badtoken").Return(nil, errors.New("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "LockError",
+			mockSetup: func(tokener *MockUserLockTokener, locker *MockUserLocker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				locker.EXPECT().TryLock(gomock.Any(), key, time.Minute).Return(nil, errors.New("redis down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Busy",
+			mockSetup: func(tokener *MockUserLockTokener, locker *MockUserLocker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				locker.EXPECT().TryLock(gomock.Any(), key, time.Minute).Return(nil, locks.ErrNotAcquired).MinTimes(2)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "AcquiredAfterWaiting",
+			mockSetup: func(tokener *MockUserLockTokener, locker *MockUserLocker) {
+				tokener.EXPECT().GetTokenFromRequest(gomock.Any(), gomock.Any()).Return("token", nil)
+				tokener.EXPECT().GetClaims(gomock.Any(), "token").Return(&jwt.Claims{UserID: userID}, nil)
+				gomock.InOrder(
+					locker.EXPECT().TryLock(gomock.Any(), key, time.Minute).Return(nil, locks.ErrNotAcquired),
+					locker.EXPECT().TryLock(gomock.Any(), key, time.Minute).Return(release, nil),
+				)
+			},
+			expectedStatus:   http.StatusOK,
+			expectNextCalled: true,
+			expectReleased:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			released = 0
+			mockTokener := NewMockUserLockTokener(ctrl)
+			mockLocker := NewMockUserLocker(ctrl)
+			tt.mockSetup(mockTokener, mockLocker)
+
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				assert.Zero(t, released, "lock released before the handler finished")
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/wallet/withdraw", nil)
+			rr := httptest.NewRecorder()
+
+			UserLockMiddleware(mockTokener, mockLocker, time.Minute, 200*time.Millisecond)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectNextCalled, nextCalled)
+			assert.Equal(t, tt.expectReleased, released)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Package migrator applies and rolls back the goose SQL migrations. Applied versions are
+// recorded in the goose_db_version table, like the goose CLI does, so a database can be
+// migrated by either.
+package migrator
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// lockID is the PostgreSQL advisory lock held while migrating, so instances started at the
+// same time do not apply a migration twice.
+const lockID = 7_391_204_556
+
+var (
+	upSection   = regexp.MustCompile(`(?is)--\s*\+goose\s+Up(.*?)(?:--\s*\+goose\s+Down|$)`)
+	downSection = regexp.MustCompile(`(?is)--\s*\+goose\s+Down(.*)$`)
+)
+
+// Migration is a SQL migration file.
+type Migration struct {
+	Version int64  // Numeric prefix of the file name
+	Name    string // File name
+	Up      string // SQL of the goose Up section
+	Down    string // SQL of the goose Down section, empty if none
+}
+
+// Status is a migration and when it was applied, nil if it is pending.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// New returns a Migrator of the *.sql migrations in fsys, ordered by version.
+func New(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		m, err := parse(path.Base(name), string(data))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", migrations[i-1].Name, migrations[i].Name)
+		}
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// parse splits a goose migration file into its sections.
+func parse(name, data string) (Migration, error) {
+	prefix, _, ok := strings.Cut(name, "_")
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if !ok || err != nil || version <= 0 {
+		return Migration{}, fmt.Errorf("migration %s: file name must start with a positive version and _", name)
+	}
+	up := upSection.FindStringSubmatch(data)
+	if up == nil {
+		return Migration{}, fmt.Errorf("migration %s: no goose Up section", name)
+	}
+	m := Migration{Version: version, Name: name, Up: strings.TrimSpace(up[1])}
+	if down := downSection.FindStringSubmatch(data); down != nil {
+		m.Down = strings.TrimSpace(down[1])
+	}
+	return m, nil
+}
+
+// Up applies the pending migrations in version order, each in its own transaction, and
+// returns the applied ones. It stops at the first failing migration.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sqlx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+			if err := apply(ctx, conn, migration.Name, migration.Up,
+				`INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, TRUE)`, migration.Version); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the latest applied migration and returns it, nil if none is applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var rolledBack *Migration
+	err := m.locked(ctx, func(conn *sqlx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+			if err := apply(ctx, conn, migration.Name, migration.Down,
+				`DELETE FROM goose_db_version WHERE version_id = $1`, migration.Version); err != nil {
+				return err
+			}
+			rolledBack = &migration
+			return nil
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status returns every migration in version order with when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *sqlx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		statuses = make([]Status, len(m.migrations))
+		for i, migration := range m.migrations {
+			statuses[i] = Status{Migration: migration}
+			if appliedAt, ok := versions[migration.Version]; ok {
+				statuses[i].AppliedAt = &appliedAt
+			}
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// locked runs fn on a connection holding the migration lock, after creating the version
+// table if needed.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn) error) (err error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// The lock outlives a canceled ctx, so it is released without it
+		if _, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("release migration lock: %w", unlockErr))
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS goose_db_version (
+			id SERIAL PRIMARY KEY,
+			version_id BIGINT NOT NULL,
+			is_applied BOOLEAN NOT NULL,
+			tstamp TIMESTAMP DEFAULT NOW()
+		)`); err != nil {
+		return fmt.Errorf("create version table: %w", err)
+	}
+	return fn(conn)
+}
+
+// appliedVersions returns the applied versions and when they were applied. The latest
+// record of a version wins, as goose records rollbacks of old versions with is_applied false.
+func appliedVersions(ctx context.Context, conn *sqlx.Conn) (map[int64]time.Time, error) {
+	var rows []struct {
+		Version   int64     `db:"version_id"`
+		IsApplied bool      `db:"is_applied"`
+		AppliedAt time.Time `db:"tstamp"`
+	}
+	if err := conn.SelectContext(ctx, &rows,
+		`SELECT version_id, is_applied, COALESCE(tstamp, NOW()) AS tstamp FROM goose_db_version ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("read version table: %w", err)
+	}
+
+	versions := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		if row.IsApplied {
+			versions[row.Version] = row.AppliedAt
+		} else {
+			delete(versions, row.Version)
+		}
+	}
+	// goose records version 0 when it creates the table
+	delete(versions, 0)
+	return versions, nil
+}
+
+// apply runs the SQL of a migration and records it with record in one transaction.
+func apply(ctx context.Context, conn *sqlx.Conn, name, sql, record string, version int64) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if sql != "" {
+		// No arguments, so pgx sends the section with the simple query protocol, which
+		// accepts several statements at once
+		if _, err := tx.ExecContext(ctx, sql); err != nil {
+			return fmt.Errorf("migration %s: %w", name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, version); err != nil {
+		return fmt.Errorf("migration %s: record version: %w", name, err)
+	}
+	return tx.Commit()
+}
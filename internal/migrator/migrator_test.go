@@ -0,0 +1,140 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFiles = fstest.MapFS{
+	"000002_create_wallets.sql": {Data: []byte("-- +goose Up\nCREATE TABLE wallets (id INT);\n\n-- +goose Down\nDROP TABLE wallets;\n")},
+	"000001_create_users.sql":   {Data: []byte("-- +goose Up\nCREATE TABLE users (id INT);\n\n-- +goose Down\nDROP TABLE users;\n")},
+	"README.md":                 {Data: []byte("not a migration")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mockDB.Close() })
+
+	m, err := New(sqlx.NewDb(mockDB, "pgx"), testFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, mock
+}
+
+func expectLocked(mock sqlmock.Sqlmock, versions *sqlmock.Rows) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS goose_db_version").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version_id, is_applied").WillReturnRows(versions)
+}
+
+func versionRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"version_id", "is_applied", "tstamp"})
+}
+
+func TestNew(t *testing.T) {
+	m, _ := newTestMigrator(t)
+
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "000001_create_users.sql", Up: "CREATE TABLE users (id INT);", Down: "DROP TABLE users;"},
+		{Version: 2, Name: "000002_create_wallets.sql", Up: "CREATE TABLE wallets (id INT);", Down: "DROP TABLE wallets;"},
+	}, m.migrations)
+
+	_, err := New(nil, fstest.MapFS{"create_users.sql": {Data: []byte("-- +goose Up\nSELECT 1;")}})
+	assert.ErrorContains(t, err, "must start with a positive version")
+
+	_, err = New(nil, fstest.MapFS{"000001_users.sql": {Data: []byte("SELECT 1;")}})
+	assert.ErrorContains(t, err, "no goose Up section")
+
+	_, err = New(nil, fstest.MapFS{
+		"000001_users.sql":   {Data: []byte("-- +goose Up\nSELECT 1;")},
+		"000001_wallets.sql": {Data: []byte("-- +goose Up\nSELECT 1;")},
+	})
+	assert.ErrorContains(t, err, "have the same version")
+}
+
+func TestMigrator_Up(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	// Version 0 is recorded by goose, version 2 was rolled back
+	expectLocked(mock, versionRows().
+		AddRow(0, true, time.Now()).
+		AddRow(1, true, time.Now()).
+		AddRow(2, true, time.Now()).
+		AddRow(2, false, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE wallets").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO goose_db_version").WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := m.Up(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, int64(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_Failure(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLocked(mock, versionRows())
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE users").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := m.Up(context.Background())
+
+	assert.ErrorContains(t, err, "migration 000001_create_users.sql: syntax error")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	m, mock := newTestMigrator(t)
+
+	expectLocked(mock, versionRows().AddRow(1, true, time.Now()).AddRow(2, true, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE wallets").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM goose_db_version").WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rolledBack, err := m.Down(context.Background())
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, rolledBack) {
+		assert.Equal(t, "000002_create_wallets.sql", rolledBack.Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	appliedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	expectLocked(mock, versionRows().AddRow(1, true, appliedAt))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	statuses, err := m.Status(context.Background())
+
+	assert.NoError(t, err)
+	if assert.Len(t, statuses, 2) {
+		assert.Equal(t, &appliedAt, statuses[0].AppliedAt)
+		assert.Nil(t, statuses[1].AppliedAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions
+const (
+	AuditActionImpersonate        = "impersonate"
+	AuditActionDormancySet        = "dormancy_set"
+	AuditActionDormancyClear      = "dormancy_clear"
+	AuditActionLimitsSet          = "limits_set"
+	AuditActionOverdraftSet       = "overdraft_set"
+	AuditActionWalletShow         = "wallet_show"
+	AuditActionWalletAdjust       = "wallet_adjust"
+	AuditActionTransactionReverse = "transaction_reverse"
+)
+
+// AuditLogDB represents an audit trail record in the database
+type AuditLogDB struct {
+	AuditID   uuid.UUID  `json:"audit_id" db:"audit_id"`     // Unique audit record identifier
+	ActorID   uuid.UUID  `json:"actor_id" db:"actor_id"`     // User who performed the action
+	Action    string     `json:"action" db:"action"`         // Action name (e.g., impersonate)
+	TargetID  *uuid.UUID `json:"target_id" db:"target_id"`   // Affected user, if any
+	Details   []byte     `json:"details" db:"details"`       // Action details as JSON
+	RequestID *string    `json:"request_id" db:"request_id"` // Request that performed the action, if any
+	CreatedAt time.Time  `json:"created_at" db:"created_at"` // Timestamp of the action
+}
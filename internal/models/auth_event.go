@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Authentication event types
+const (
+	AuthEventLoginSuccess = "login_success"
+	AuthEventLoginFailure = "login_failure"
+)
+
+// ClientInfo describes the client that sent a request
+type ClientInfo struct {
+	IP        string // Client IP address
+	UserAgent string // User-Agent header
+	Country   string // ISO country code resolved from the IP, empty if unknown
+}
+
+// AuthEventDB represents an authentication event in the database
+type AuthEventDB struct {
+	EventID   uuid.UUID `json:"event_id" db:"event_id"`     // Unique event identifier
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`       // User the event belongs to
+	EventType string    `json:"event_type" db:"event_type"` // Event type (e.g., login_success)
+	IP        string    `json:"ip" db:"ip"`                 // Client IP address
+	UserAgent string    `json:"user_agent" db:"user_agent"` // Client User-Agent
+	Country   string    `json:"country" db:"country"`       // Client country code, empty if unknown
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // Timestamp of the event
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// BalanceSnapshotDB represents the balance of a wallet at the end of a day
+type BalanceSnapshotDB struct {
+	UserID   uuid.UUID    `json:"user_id" db:"user_id"`    // Identifier of the wallet owner
+	Currency string       `json:"currency" db:"currency"`  // Currency code (e.g., USD, RUB, EUR)
+	Date     time.Time    `json:"date" db:"snapshot_date"` // UTC day of the snapshot
+	Balance  money.Amount `json:"balance" db:"balance"`    // Last balance seen that day
+}
+
+// DailyBalance represents the balances of a user at the end of a day
+type DailyBalance struct {
+	Date     time.Time               `json:"date"`     // UTC day
+	Balances map[string]money.Amount `json:"balances"` // Balances keyed by currency code
+}
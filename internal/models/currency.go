@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// CurrencyDB represents a currency in the database
+type CurrencyDB struct {
+	Code      string       `json:"code" db:"code"`             // ISO currency code (e.g., USD, RUB, EUR)
+	Name      string       `json:"name" db:"name"`             // Human-readable currency name
+	Enabled   bool         `json:"enabled" db:"enabled"`       // Whether the currency is accepted by the API
+	Decimals  int          `json:"decimals" db:"decimals"`     // Decimal places of amounts, at most money.Scale
+	MinAmount money.Amount `json:"min_amount" db:"min_amount"` // Smallest amount accepted by wallet operations
+	CreatedAt time.Time    `json:"created_at" db:"created_at"` // Timestamp when the currency was added
+}
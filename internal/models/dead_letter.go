@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetterEvent is a Kafka event that could not be published after all attempts.
+// It is kept until an admin replays it
+type DeadLetterEvent struct {
+	ID         uuid.UUID  `db:"id"`          // Dead letter identifier
+	Topic      string     `db:"topic"`       // Kafka topic
+	Key        string     `db:"message_key"` // Kafka message key
+	EventID    string     `db:"event_id"`    // Event ID header, empty for events without one
+	Payload    []byte     `db:"payload"`     // Message value, JSON or Avro
+	Attempts   int        `db:"attempts"`    // Failed publishing attempts
+	LastError  string     `db:"last_error"`  // Reason of the last failed attempt
+	CreatedAt  time.Time  `db:"created_at"`  // Time the event was dead-lettered
+	ReplayedAt *time.Time `db:"replayed_at"` // Time of the replay, nil while pending
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// ExchangeReceipt is published to the exchanger for every executed conversion,
+// so the provider can reconcile the exchanged volume
+type ExchangeReceipt struct {
+	TransactionID uuid.UUID    `json:"transaction_id" db:"transaction_id"` // Transaction identifier, the idempotency key of the receipt
+	Operation     string       `json:"operation" db:"operation"`           // Operation that converted the funds (exchange, close)
+	FromCurrency  string       `json:"from_currency" db:"from_currency"`   // Debited currency
+	ToCurrency    string       `json:"to_currency" db:"to_currency"`       // Credited currency
+	Amount        money.Amount `json:"amount" db:"amount"`                 // Debited amount
+	ToAmount      money.Amount `json:"to_amount" db:"to_amount"`           // Credited amount
+	Rate          float32      `json:"rate" db:"rate"`                     // Applied exchange rate
+	ExecutedAt    time.Time    `json:"executed_at" db:"executed_at"`       // Time of the conversion
+}
+
+// ExchangeReceiptDB represents a receipt waiting for delivery in the database
+type ExchangeReceiptDB struct {
+	ExchangeReceipt
+	Attempts      int        `db:"attempts"`        // Failed delivery attempts
+	NextAttemptAt time.Time  `db:"next_attempt_at"` // Earliest time of the next delivery attempt
+	LastError     *string    `db:"last_error"`      // Reason of the last failed delivery
+	PublishedAt   *time.Time `db:"published_at"`    // Delivery time, nil while pending
+}
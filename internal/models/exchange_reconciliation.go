@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of exchange reconciliation mismatches
+const (
+	ExchangeMismatchMissingAtExchanger = "missing_at_exchanger" // Executed locally, unknown to the exchanger
+	ExchangeMismatchUnknownLocally     = "unknown_locally"      // Recorded by the exchanger, not executed locally
+	ExchangeMismatchAmount             = "amount"               // Currencies or amounts differ
+	ExchangeMismatchRate               = "rate"                 // Applied rates differ
+)
+
+// ExchangeMismatch is an exchange whose local record disagrees with the exchanger's
+type ExchangeMismatch struct {
+	TransactionID uuid.UUID        // Transaction identifier
+	Kind          string           // Kind of the mismatch
+	Local         *ExchangeReceipt // Local record, nil if unknown locally
+	Remote        *ExchangeReceipt // Exchanger's record, nil if missing at the exchanger
+}
+
+// ExchangeReconciliationReport compares the exchanges executed in a period with the exchanger's records
+type ExchangeReconciliationReport struct {
+	From       time.Time          // Start of the period, inclusive
+	To         time.Time          // End of the period, exclusive
+	Matched    int                // Exchanges recorded identically on both sides
+	Pending    int                // Local exchanges not yet delivered to the exchanger
+	Mismatches []ExchangeMismatch // Exchanges that disagree
+}
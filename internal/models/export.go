@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Supported export formats
+const (
+	ExportFormatAccountingCSV = "accounting_csv"
+	ExportFormatUserDataZIP   = "user_data_zip" // GDPR archive of the user's data
+)
+
+// Export statuses
+const (
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusCompleted  = "completed"
+	ExportStatusFailed     = "failed"
+)
+
+// ExportDB represents an asynchronous export job in the database
+type ExportDB struct {
+	ExportID    uuid.UUID  `json:"export_id" db:"export_id"`       // Unique export identifier
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`           // User who requested the export
+	Format      string     `json:"format" db:"format"`             // Export format (e.g., accounting_csv)
+	Status      string     `json:"status" db:"status"`             // Processing status
+	Content     []byte     `json:"content" db:"content"`           // Generated file, set when completed
+	Error       *string    `json:"error" db:"error"`               // Failure reason, set when failed
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`     // Timestamp when the export was requested
+	CompletedAt *time.Time `json:"completed_at" db:"completed_at"` // Timestamp when the export finished
+}
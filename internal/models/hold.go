@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// Hold statuses
+const (
+	HoldStatusPending  = "pending"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+)
+
+// WalletHoldDB represents funds reserved in a wallet until they are captured or released
+type WalletHoldDB struct {
+	HoldID       uuid.UUID    `json:"hold_id" db:"hold_id"`               // Unique hold identifier
+	UserID       uuid.UUID    `json:"user_id" db:"user_id"`               // Identifier of the wallet's owner
+	Currency     string       `json:"currency" db:"currency"`             // Currency code (e.g., USD, RUB, EUR)
+	Amount       money.Amount `json:"amount" db:"amount"`                 // Reserved amount
+	Status       string       `json:"status" db:"status"`                 // Hold status (pending, captured, released)
+	LimitUsageID *int64       `json:"limit_usage_id" db:"limit_usage_id"` // Limit usage entry of the hold, if limits are tracked
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`         // Timestamp when the hold was placed
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`         // Timestamp of the last status change
+}
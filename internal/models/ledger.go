@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// Ledger accounts. Every wallet movement is balanced by an entry on another account.
+const (
+	LedgerAccountWallet   = "wallet"   // A user's wallet in a currency
+	LedgerAccountExternal = "external" // Money entering or leaving the service
+	LedgerAccountExchange = "exchange" // Clearing account of currency conversions
+	LedgerAccountFee      = "fee"      // Exchange fees earned by the service
+)
+
+// LedgerEntryDB represents a posting of a ledger transaction; debits are positive, credits negative
+type LedgerEntryDB struct {
+	EntryID       int64        `json:"entry_id" db:"entry_id"`             // Sequential entry identifier
+	TransactionID uuid.UUID    `json:"transaction_id" db:"transaction_id"` // Transaction the entry belongs to
+	Account       string       `json:"account" db:"account"`               // wallet, external or exchange
+	UserID        *uuid.UUID   `json:"user_id,omitempty" db:"user_id"`     // Owner of a wallet entry
+	Currency      string       `json:"currency" db:"currency"`             // Currency code (e.g., USD, RUB, EUR)
+	Amount        money.Amount `json:"amount" db:"amount"`                 // Signed amount
+}
+
+// LedgerMismatch represents a wallet whose stored balance differs from the sum of its ledger entries
+type LedgerMismatch struct {
+	UserID        uuid.UUID    `json:"user_id" db:"user_id"`               // Identifier of the wallet owner
+	Currency      string       `json:"currency" db:"currency"`             // Currency code (e.g., USD, RUB, EUR)
+	Balance       money.Amount `json:"balance" db:"balance"`               // Balance stored in wallets, zero for a closed wallet
+	LedgerBalance money.Amount `json:"ledger_balance" db:"ledger_balance"` // Sum of the wallet entries in the ledger
+}
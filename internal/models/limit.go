@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// Limit periods
+const (
+	LimitPeriodDaily   = "daily"
+	LimitPeriodMonthly = "monthly"
+)
+
+// Rolling windows of the limit periods, ending at the time of the operation
+const (
+	LimitDailyWindow   = 24 * time.Hour
+	LimitMonthlyWindow = 30 * 24 * time.Hour
+)
+
+// WalletLimitDB represents the withdrawal and exchange limits of a user in one currency
+type WalletLimitDB struct {
+	UserID       uuid.UUID     `json:"user_id" db:"user_id"`             // Identifier of the limited user
+	Currency     string        `json:"currency" db:"currency"`           // Currency code (e.g., USD, RUB, EUR)
+	DailyLimit   *money.Amount `json:"daily_limit" db:"daily_limit"`     // Limit over the daily window, nil means unlimited
+	MonthlyLimit *money.Amount `json:"monthly_limit" db:"monthly_limit"` // Limit over the monthly window, nil means unlimited
+	DailyUsed    money.Amount  `json:"daily_used" db:"daily_used"`       // Withdrawn and exchanged within the daily window
+	MonthlyUsed  money.Amount  `json:"monthly_used" db:"monthly_used"`   // Withdrawn and exchanged within the monthly window
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`       // Timestamp of the last limit change
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification channels
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+)
+
+// Notification is a message delivered to a user outside of the API
+type Notification struct {
+	UserID  uuid.UUID `json:"user_id"` // Recipient user
+	Channel string    `json:"channel"` // Delivery channel (email, sms)
+	Email   string    `json:"email"`   // Recipient email
+	Phone   string    `json:"phone"`   // Recipient phone, set for SMS
+	Subject string    `json:"subject"` // Short subject line
+	Body    string    `json:"body"`    // Message text
+}
+
+// NotificationPreferencesDB represents the user's notification settings in the database
+type NotificationPreferencesDB struct {
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`                 // User the settings belong to
+	EmailEnabled   bool      `json:"email_enabled" db:"email_enabled"`     // Deliver notifications by email
+	SMSEnabled     bool      `json:"sms_enabled" db:"sms_enabled"`         // Deliver notifications by SMS
+	Phone          *string   `json:"phone" db:"phone"`                     // Phone number for SMS
+	SecurityAlerts bool      `json:"security_alerts" db:"security_alerts"` // Notify about suspicious logins
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`           // Last update timestamp
+}
+
+// DefaultNotificationPreferences returns the settings used until the user saves their own
+func DefaultNotificationPreferences(userID uuid.UUID) NotificationPreferencesDB {
+	return NotificationPreferencesDB{
+		UserID:         userID,
+		EmailEnabled:   true,
+		SecurityAlerts: true,
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// PaymentConfirmation is an external payment into a wallet, confirmed by the payment provider
+// over Kafka. The message key is the provider's payment ID, which makes redeliveries idempotent.
+type PaymentConfirmation struct {
+	UserID    uuid.UUID    `json:"user_id"`             // User whose wallet is credited
+	Currency  string       `json:"currency"`            // Currency of the payment
+	Amount    money.Amount `json:"amount"`              // Amount credited, positive
+	Reference string       `json:"reference,omitempty"` // Provider's reference, kept in the history
+}
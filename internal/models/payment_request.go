@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// Payment request statuses
+const (
+	PaymentRequestStatusPending  = "pending"
+	PaymentRequestStatusAccepted = "accepted"
+	PaymentRequestStatusDeclined = "declined"
+	PaymentRequestStatusExpired  = "expired"
+)
+
+// MaxPaymentRequestNoteLength is the maximum length of a payment request note in characters
+const MaxPaymentRequestNoteLength = 128
+
+// PaymentRequestDB represents a request of one user to be paid an amount by another
+type PaymentRequestDB struct {
+	RequestID     uuid.UUID    `json:"request_id" db:"request_id"`         // Unique request identifier
+	RequesterID   uuid.UUID    `json:"requester_id" db:"requester_id"`     // User asking for the money
+	PayerID       uuid.UUID    `json:"payer_id" db:"payer_id"`             // User asked to pay
+	Currency      string       `json:"currency" db:"currency"`             // Currency code (e.g., USD, RUB, EUR)
+	Amount        money.Amount `json:"amount" db:"amount"`                 // Requested amount
+	Note          *string      `json:"note" db:"note"`                     // Message of the requester, if given
+	Status        string       `json:"status" db:"status"`                 // Request status (pending, accepted, declined, expired)
+	TransactionID *uuid.UUID   `json:"transaction_id" db:"transaction_id"` // Transfer of an accepted request
+	ExpiresAt     time.Time    `json:"expires_at" db:"expires_at"`         // Time after which a pending request can no longer be accepted
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`         // Timestamp when the request was made
+	UpdatedAt     time.Time    `json:"updated_at" db:"updated_at"`         // Timestamp of the last status change
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MaxPotNameLength is the longest pot name in characters
+const MaxPotNameLength = 64
+
+// WalletPotDB represents a named savings pot: a part of a wallet's balance set aside by the user
+type WalletPotDB struct {
+	PotID     uuid.UUID    `json:"pot_id" db:"pot_id"`         // Unique pot identifier
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`       // Identifier of the wallet's owner
+	Currency  string       `json:"currency" db:"currency"`     // Currency of the wallet the pot belongs to
+	Name      string       `json:"name" db:"name"`             // Name of the pot, unique within the wallet
+	Balance   money.Amount `json:"balance" db:"balance"`       // Amount set aside in the pot
+	Spendable bool         `json:"spendable" db:"spendable"`   // Whether withdrawals and exchanges may spend the pot
+	CreatedAt time.Time    `json:"created_at" db:"created_at"` // Timestamp when the pot was created
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"` // Timestamp of the last pot update
+}
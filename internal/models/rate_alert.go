@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Directions a rate alert fires in
+const (
+	RateAlertAbove = "above" // The rate rises above the threshold
+	RateAlertBelow = "below" // The rate falls below the threshold
+)
+
+// RateAlertDB represents a user's exchange rate alert in the database
+type RateAlertDB struct {
+	AlertID       uuid.UUID  `json:"alert_id" db:"alert_id"`             // Unique alert identifier
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`               // User notified by the alert
+	FromCurrency  string     `json:"from_currency" db:"from_currency"`   // Currency the rate converts from
+	ToCurrency    string     `json:"to_currency" db:"to_currency"`       // Currency the rate converts to
+	Direction     string     `json:"direction" db:"direction"`           // RateAlertAbove or RateAlertBelow
+	Threshold     float32    `json:"threshold" db:"threshold"`           // Rate the alert fires at
+	TriggeredAt   *time.Time `json:"triggered_at" db:"triggered_at"`     // When the alert fired, nil while armed
+	TriggeredRate *float32   `json:"triggered_rate" db:"triggered_rate"` // Rate the alert fired at
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`         // Creation timestamp
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`         // Last update timestamp
+}
+
+// Crossed reports whether rate is past the threshold of the alert.
+func (a RateAlertDB) Crossed(rate float32) bool {
+	if a.Direction == RateAlertBelow {
+		return rate < a.Threshold
+	}
+	return rate > a.Threshold
+}
+
+// RateAlertEvent is published when an exchange rate crosses the threshold of an alert
+type RateAlertEvent struct {
+	AlertID      uuid.UUID `json:"alert_id"`      // Alert that fired
+	UserID       uuid.UUID `json:"user_id"`       // User to notify
+	FromCurrency string    `json:"from_currency"` // Currency the rate converts from
+	ToCurrency   string    `json:"to_currency"`   // Currency the rate converts to
+	Direction    string    `json:"direction"`     // RateAlertAbove or RateAlertBelow
+	Threshold    float32   `json:"threshold"`     // Rate the alert fires at
+	Rate         float32   `json:"rate"`          // Rate that crossed the threshold
+	TriggeredAt  time.Time `json:"triggered_at"`  // When the alert fired
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Granularities of the exchange rate history
+const (
+	RateGranularityHour = "hour"
+	RateGranularityDay  = "day"
+)
+
+// ExchangeRatePoint is the rate of a currency pair over one period of its history
+type ExchangeRatePoint struct {
+	Period time.Time `db:"period"` // Start of the period, UTC
+	Open   float32   `db:"open"`   // First rate fetched in the period
+	Close  float32   `db:"close"`  // Last rate fetched in the period
+	Low    float32   `db:"low"`    // Lowest rate fetched in the period
+	High   float32   `db:"high"`   // Highest rate fetched in the period
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Security alert reasons
+const (
+	SecurityAlertNewCountry = "new_country"
+	SecurityAlertNewDevice  = "new_device"
+)
+
+// SecurityAlert is published to Kafka when a login looks suspicious
+type SecurityAlert struct {
+	AlertID   uuid.UUID `json:"alert_id"`   // Unique alert identifier
+	UserID    uuid.UUID `json:"user_id"`    // User that logged in
+	Reasons   []string  `json:"reasons"`    // Why the login is suspicious (new_country, new_device)
+	IP        string    `json:"ip"`         // Client IP address
+	Country   string    `json:"country"`    // Client country code, empty if unknown
+	UserAgent string    `json:"user_agent"` // Client User-Agent
+	CreatedAt time.Time `json:"created_at"` // Time of the login
+}
@@ -1,10 +1,104 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
 // Transaction represents a financial transaction, including amount, user, timestamp, and operation type.
+// It is the transaction event of the current version, TransactionEventVersion.
 type Transaction struct {
-	TransactionID string  `json:"transaction_id" bson:"transaction_id"` // TransactionID is a unique identifier for the transaction.
-	Timestamp     int64   `json:"timestamp" bson:"timestamp"`           // Timestamp is the Unix timestamp (in seconds) when the transaction occurred.
-	Amount        float64 `json:"amount" bson:"amount"`                 // Amount is the monetary value of the transaction.
-	UserID        string  `json:"user_id" bson:"user_id"`               // UserID is the identifier of the user who initiated the transaction.
-	Operation     string  `json:"operation" bson:"operation"`           // Operation describes the type of transaction, e.g., "deposit", "withdrawal", or "transfer".
+	Version       int           `json:"version" bson:"version"`                             // Version is the schema version of the event.
+	TransactionID string        `json:"transaction_id" bson:"transaction_id"`               // TransactionID is a unique identifier for the transaction.
+	Timestamp     int64         `json:"timestamp" bson:"timestamp"`                         // Timestamp is the Unix timestamp (in seconds) when the transaction occurred.
+	Amount        money.Amount  `json:"amount" bson:"amount"`                               // Amount is the monetary value of the transaction.
+	UserID        string        `json:"user_id" bson:"user_id"`                             // UserID is the identifier of the user who initiated the transaction.
+	Operation     string        `json:"operation" bson:"operation"`                         // Operation describes the type of transaction, e.g., "deposit", "withdrawal", or "transfer".
+	ReversalOf    string        `json:"reversal_of,omitempty" bson:"reversal_of,omitempty"` // ReversalOf is the identifier of the reversed transaction, reversals only.
+	Reference     string        `json:"reference,omitempty" bson:"reference,omitempty"`     // Reference is the client's reference of a deposit or withdrawal, if given.
+	EventID       string        `json:"event_id" bson:"event_id"`                           // EventID is derived from the transaction, so consumers drop redelivered events by it.
+	Currency      string        `json:"currency" bson:"currency"`                           // Currency is the currency of the amount; the source currency for exchanges.
+	ToCurrency    string        `json:"to_currency,omitempty" bson:"to_currency,omitempty"` // ToCurrency is the target currency of exchanges and payouts on closure.
+	Balance       *money.Amount `json:"balance,omitempty" bson:"balance,omitempty"`         // Balance is the balance of the Currency wallet after the operation, nil if unknown.
+	ToBalance     *money.Amount `json:"to_balance,omitempty" bson:"to_balance,omitempty"`   // ToBalance is the balance of the ToCurrency wallet after the operation, nil if unknown.
+}
+
+// TransactionEventVersion is the schema version of the transaction events published now.
+// Events without a version are version 1.
+const TransactionEventVersion = 3
+
+// TransactionV1 is a transaction event of version 1, published before events were versioned.
+// Events published before event IDs were introduced have no event_id.
+type TransactionV1 struct {
+	TransactionID string       `json:"transaction_id"`
+	Timestamp     int64        `json:"timestamp"`
+	Amount        money.Amount `json:"amount"`
+	UserID        string       `json:"user_id"`
+	Operation     string       `json:"operation"`
+	ReversalOf    string       `json:"reversal_of,omitempty"`
+	Reference     string       `json:"reference,omitempty"`
+	EventID       string       `json:"event_id,omitempty"`
+}
+
+// TransactionV2 is a transaction event of version 2, without the currency and balances.
+type TransactionV2 struct {
+	Version       int          `json:"version"`
+	TransactionID string       `json:"transaction_id"`
+	Timestamp     int64        `json:"timestamp"`
+	Amount        money.Amount `json:"amount"`
+	UserID        string       `json:"user_id"`
+	Operation     string       `json:"operation"`
+	ReversalOf    string       `json:"reversal_of,omitempty"`
+	Reference     string       `json:"reference,omitempty"`
+	EventID       string       `json:"event_id"`
+}
+
+// EventIDHeader is the message header carrying the event ID, which brokers deduplicate by
+const EventIDHeader = "event_id"
+
+// MaxReferenceLength is the maximum length of a transaction reference in characters
+const MaxReferenceLength = 128
+
+// Transaction history operations in addition to deposit and withdraw
+const (
+	OperationExchange    = "exchange"     // Currency exchange
+	OperationClose       = "close"        // Wallet closure, optionally paid out to another currency
+	OperationReversal    = "reversal"     // Compensation of an earlier transaction by an admin
+	OperationTransferOut = "transfer_out" // Payment to another user, e.g. of an accepted payment request
+	OperationTransferIn  = "transfer_in"  // Payment from another user
+)
+
+// TransactionDB represents a row of the user's transaction history
+type TransactionDB struct {
+	ID            int64         `json:"id" db:"id"`                         // Sequential identifier, used as the pagination cursor
+	TransactionID uuid.UUID     `json:"transaction_id" db:"transaction_id"` // Unique transaction identifier, shared with the Kafka event
+	UserID        uuid.UUID     `json:"user_id" db:"user_id"`               // Identifier of the wallet's owner
+	Operation     string        `json:"operation" db:"operation"`           // Operation type (deposit, withdraw, exchange, close, reversal, transfer_out, transfer_in)
+	Currency      string        `json:"currency" db:"currency"`             // Currency code; the source currency for exchanges
+	Amount        money.Amount  `json:"amount" db:"amount"`                 // Operation amount
+	ToCurrency    *string       `json:"to_currency" db:"to_currency"`       // Target currency, exchanges and payouts on closure only
+	ToAmount      *money.Amount `json:"to_amount" db:"to_amount"`           // Credited amount, exchanges and payouts on closure only
+	ReversalOf    *uuid.UUID    `json:"reversal_of" db:"reversal_of"`       // Reversed transaction, reversals only
+	Reference     *string       `json:"reference" db:"reference"`           // Client reference, deposits and withdrawals only
+	Rate          *float32      `json:"rate" db:"rate"`                     // Rate applied, exchanges only
+	Fee           *money.Amount `json:"fee" db:"fee"`                       // Fee in the source currency, exchanges only
+	Balance       *money.Amount `json:"balance" db:"balance"`               // Source wallet balance after the exchange
+	ToBalance     *money.Amount `json:"to_balance" db:"to_balance"`         // Target wallet balance after the exchange
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`         // Timestamp of the operation
+}
+
+// TransactionFilter narrows down the transaction history. Zero values disable a filter.
+type TransactionFilter struct {
+	UserID   uuid.UUID
+	From     time.Time // Inclusive lower bound of created_at
+	To       time.Time // Exclusive upper bound of created_at
+	Currency string    // Matches either side of an exchange
+	// Source and target currencies of exchanges
+	FromCurrency string
+	ToCurrency   string
+	Operation    string
+	BeforeID     int64 // Cursor: only rows with a smaller ID
+	Limit        int
 }
@@ -6,12 +6,20 @@ import (
 	"github.com/google/uuid"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // UserDB represents a user record in the database
 type UserDB struct {
-	UserID       uuid.UUID `json:"user_id" db:"user_id"`             // Primary key
-	Username     string    `json:"username" db:"username"`           // Unique username
-	Email        string    `json:"email" db:"email"`                 // User email
-	PasswordHash string    `json:"password_hash" db:"password_hash"` // Hashed password
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`       // Creation timestamp
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`       // Last update timestamp
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`             // Primary key
+	Username     string     `json:"username" db:"username"`           // Unique username
+	Email        string     `json:"email" db:"email"`                 // User email
+	PasswordHash string     `json:"password_hash" db:"password_hash"` // Hashed password
+	Role         string     `json:"role" db:"role"`                   // User role (user, admin)
+	DormantAt    *time.Time `json:"dormant_at" db:"dormant_at"`       // Set while the account is flagged as dormant
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`       // Creation timestamp
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`       // Last update timestamp
 }
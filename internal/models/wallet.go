@@ -4,9 +4,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 )
 
-// Supported currency codes
+// Currency codes seeded into the currencies table
 const (
 	USD = "USD"
 	RUB = "RUB"
@@ -15,10 +16,131 @@ const (
 
 // WalletDB represents a wallet row in the database
 type WalletDB struct {
-	WalletID  uuid.UUID `json:"wallet_id" db:"wallet_id"`   // Unique wallet identifier
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`       // Identifier of the wallet's owner
-	Currency  string    `json:"currency" db:"currency"`     // Currency code (e.g., USD, RUB, EUR)
-	Balance   float64   `json:"balance" db:"balance"`       // Current balance in the wallet
-	CreatedAt time.Time `json:"created_at" db:"created_at"` // Timestamp when the wallet was created
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // Timestamp of the last wallet update
+	WalletID  uuid.UUID    `json:"wallet_id" db:"wallet_id"`   // Unique wallet identifier
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`       // Identifier of the wallet's owner
+	Currency  string       `json:"currency" db:"currency"`     // Currency code (e.g., USD, RUB, EUR)
+	Balance   money.Amount `json:"balance" db:"balance"`       // Current balance in the wallet
+	CreatedAt time.Time    `json:"created_at" db:"created_at"` // Timestamp when the wallet was created
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"` // Timestamp of the last wallet update
+}
+
+// WalletKey identifies a wallet by its owner and currency
+type WalletKey struct {
+	UserID   uuid.UUID // Owner of the wallet
+	Currency string    // Currency of the wallet
+}
+
+// BalanceChangeEvent is published to the compacted balance topic when an operation changes
+// a balance. It is keyed by the user and currency, so the topic keeps the latest balance of
+// every wallet
+type BalanceChangeEvent struct {
+	UserID        uuid.UUID    `json:"user_id"`        // Owner of the wallet
+	Currency      string       `json:"currency"`       // Currency of the wallet
+	Balance       money.Amount `json:"balance"`        // Balance after the operation
+	TransactionID uuid.UUID    `json:"transaction_id"` // Operation that changed the balance
+	ChangedAt     time.Time    `json:"changed_at"`     // When the change was published
+}
+
+// BalanceUpdate is the balances of a user after an operation changed them, streamed to the
+// user's open balance event streams
+type BalanceUpdate struct {
+	TransactionID uuid.UUID               // Operation that changed the balances
+	Balances      map[string]money.Amount // All balances of the user after the operation
+	ChangedAt     time.Time               // When the update was published
+}
+
+// BalanceTotal is the user's holdings converted to one currency at current rates
+type BalanceTotal struct {
+	Currency  string             // Currency the holdings are converted to
+	Total     money.Amount       // Sum of the converted balances
+	Holdings  []ConvertedBalance // Non-zero balances, ordered by currency
+	StaleRate bool               // Whether a cached rate past its TTL was used
+}
+
+// ExchangeQuote is the outcome of an exchange at the current rate, computed without executing it.
+// A locked quote has an ID and can be executed at its rate by its user until it expires.
+type ExchangeQuote struct {
+	QuoteID       uuid.UUID    `json:"quote_id"`        // ID of a locked quote, uuid.Nil if not locked
+	UserID        uuid.UUID    `json:"user_id"`         // User the locked quote was issued to
+	FromCurrency  string       `json:"from_currency"`   // Currency the amount is exchanged from
+	ToCurrency    string       `json:"to_currency"`     // Currency the amount is exchanged to
+	Amount        money.Amount `json:"amount"`          // Amount debited in FromCurrency
+	Rate          float32      `json:"rate"`            // Rate from FromCurrency to ToCurrency
+	Fee           money.Amount `json:"fee"`             // Fee in FromCurrency, included in Amount
+	ToAmount      money.Amount `json:"to_amount"`       // Amount credited in ToCurrency
+	StaleRate     bool         `json:"stale_rate"`      // Whether a cached rate past its TTL was used
+	DerivedRate   bool         `json:"derived_rate"`    // Whether the rate was derived through the pivot currency
+	RateProvider  string       `json:"rate_provider"`   // Rate provider the rate came from
+	RateFetchedAt time.Time    `json:"rate_fetched_at"` // When the rate was fetched from the provider
+	RateCached    bool         `json:"rate_cached"`     // Whether the rate was served from the cache
+	ExpiresAt     time.Time    `json:"expires_at"`      // When a locked quote expires
+}
+
+// RateSource describes where an exchange rate came from
+type RateSource struct {
+	Provider  string    // Rate provider that returned the rate, e.g. grpc or http
+	FetchedAt time.Time // When the rate was fetched from the provider
+	Cached    bool      // Whether the rate was served from the cache
+	Stale     bool      // Whether the cached rate was past its TTL, served because the provider failed
+}
+
+// CurrencyPair is an ordered pair of currencies a rate converts between
+type CurrencyPair struct {
+	From string // Currency converted from
+	To   string // Currency converted to
+}
+
+// PairRate is the rate of a currency pair with its source
+type PairRate struct {
+	Rate   float32    // Rate from the pair's From to its To currency
+	Source RateSource // Where the rate came from
+}
+
+// ExchangeFee is the fee charged for exchanges of a currency pair
+type ExchangeFee struct {
+	FromCurrency string       `db:"from_currency"` // Currency the amount is exchanged from
+	ToCurrency   string       `db:"to_currency"`   // Currency the amount is exchanged to
+	Percent      float64      `db:"percent"`       // Percentage of the amount charged as a fee
+	Fixed        money.Amount `db:"fixed"`         // Fixed fee in FromCurrency, added to the percentage
+	Spread       float64      `db:"spread"`        // Percentage the market rate is lowered by
+}
+
+// ConvertedBalance is the balance of a wallet converted to another currency
+type ConvertedBalance struct {
+	Currency  string       // Currency of the wallet
+	Balance   money.Amount // Balance of the wallet
+	Rate      float32      // Rate used for the conversion, 1 for the target currency itself
+	Converted money.Amount // Balance in the target currency
+}
+
+// WalletDetails is the client-managed display label and key/value metadata of a wallet
+type WalletDetails struct {
+	Label    string            `json:"label,omitempty"`    // Display label, e.g. "travel fund"
+	Metadata map[string]string `json:"metadata,omitempty"` // Arbitrary key/value metadata
+}
+
+// Limits of wallet details in characters
+const (
+	MaxWalletLabelLength         = 64
+	MaxWalletMetadataKeys        = 20
+	MaxWalletMetadataKeyLength   = 40
+	MaxWalletMetadataValueLength = 256
+)
+
+// Wallet event operations
+const (
+	OperationDeposit  = "deposit"
+	OperationWithdraw = "withdraw"
+	OperationOpen     = "open" // Creation of an empty wallet
+)
+
+// WalletEventDB represents a ledger entry in wallet_events
+type WalletEventDB struct {
+	EventID   int64        `json:"event_id" db:"event_id"`     // Sequential event identifier
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`       // Identifier of the wallet's owner
+	Currency  string       `json:"currency" db:"currency"`     // Currency code (e.g., USD, RUB, EUR)
+	Operation string       `json:"operation" db:"operation"`   // Operation type (deposit, withdraw, open)
+	Amount    money.Amount `json:"amount" db:"amount"`         // Operation amount
+	Balance   money.Amount `json:"balance" db:"balance"`       // Balance after the operation
+	CreatedAt time.Time    `json:"created_at" db:"created_at"` // Timestamp of the operation
 }
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// Webhook event types
+const (
+	WebhookEventDeposit  = "wallet.deposit"  // Funds were deposited
+	WebhookEventWithdraw = "wallet.withdraw" // Funds were withdrawn
+	WebhookEventExchange = "wallet.exchange" // Funds were exchanged to another currency
+
+	WebhookEventPaymentRequestCreated  = "payment_request.created"  // The user was asked to pay, sent to the payer
+	WebhookEventPaymentRequestAccepted = "payment_request.accepted" // The payer paid the request, sent to the requester
+	WebhookEventPaymentRequestDeclined = "payment_request.declined" // The payer declined the request, sent to the requester
+	WebhookEventPaymentRequestExpired  = "payment_request.expired"  // The request expired unanswered, sent to the requester
+)
+
+// WebhookDB represents a registered callback URL in the database
+type WebhookDB struct {
+	WebhookID uuid.UUID  `json:"webhook_id" db:"webhook_id"` // Unique webhook identifier
+	UserID    *uuid.UUID `json:"user_id" db:"user_id"`       // Owner, nil for admin webhooks receiving events of all users
+	URL       string     `json:"url" db:"url"`               // Callback URL
+	Secret    string     `json:"-" db:"secret"`              // Key of the payload signature, shown only on creation
+	CreatedAt time.Time  `json:"created_at" db:"created_at"` // Registration time
+}
+
+// WebhookEvent is the payload sent to webhooks for a wallet operation or payment request
+type WebhookEvent struct {
+	Type             string        `json:"type"`                         // Event type, e.g. wallet.deposit
+	TransactionID    uuid.UUID     `json:"transaction_id,omitzero"`      // Transaction of the operation; payment requests only once accepted
+	PaymentRequestID *uuid.UUID    `json:"payment_request_id,omitempty"` // Payment request, its events only
+	CounterpartyID   *uuid.UUID    `json:"counterparty_id,omitempty"`    // Other user of a payment request
+	UserID           uuid.UUID     `json:"user_id"`                      // Owner of the wallet, or the user notified of a payment request
+	Currency         string        `json:"currency"`                     // Currency code; the source currency for exchanges
+	Amount           money.Amount  `json:"amount"`                       // Operation amount
+	ToCurrency       *string       `json:"to_currency,omitempty"`        // Target currency, exchanges only
+	ToAmount         *money.Amount `json:"to_amount,omitempty"`          // Credited amount, exchanges only
+	Reference        *string       `json:"reference,omitempty"`          // Client reference, deposits and withdrawals only
+	OccurredAt       time.Time     `json:"occurred_at"`                  // Time of the operation
+}
+
+// WebhookDeliveryDB represents a pending delivery of an event to a webhook
+type WebhookDeliveryDB struct {
+	DeliveryID    uuid.UUID `db:"delivery_id"`     // Unique delivery identifier, sent as Webhook-Id
+	WebhookID     uuid.UUID `db:"webhook_id"`      // Target webhook
+	URL           string    `db:"url"`             // Callback URL of the webhook
+	Secret        string    `db:"secret"`          // Signing key of the webhook
+	EventType     string    `db:"event_type"`      // Event type
+	Payload       string    `db:"payload"`         // JSON-encoded WebhookEvent
+	Attempts      int       `db:"attempts"`        // Failed delivery attempts
+	NextAttemptAt time.Time `db:"next_attempt_at"` // Earliest time of the next delivery attempt
+	LastError     *string   `db:"last_error"`      // Reason of the last failed delivery
+}
@@ -0,0 +1,206 @@
+// Package money provides an exact monetary amount type.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places of an amount, matching NUMERIC(20, 2) columns.
+const Scale = 2
+
+// minorUnits is the number of minor units (cents, kopecks) in a major unit.
+const minorUnits = 100
+
+var (
+	// ErrInvalidAmount is returned for text that is not a decimal number with at most Scale decimal places.
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrAmountOverflow is returned when the result of a conversion does not fit in an Amount.
+	ErrAmountOverflow = errors.New("amount overflow")
+)
+
+// Amount is a monetary amount in minor units (cents, kopecks).
+// It is stored as NUMERIC and serialized to JSON as a decimal number, e.g. 100.50.
+type Amount int64
+
+// Zero is the zero amount.
+const Zero Amount = 0
+
+// Parse parses a decimal number such as "100", "-0.5" or "100.50".
+// More than Scale decimal places are rejected rather than rounded.
+func Parse(s string) (Amount, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || len(frac) > Scale || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", Scale-len(frac))
+
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if neg {
+		units = -units
+	}
+	return Amount(units), nil
+}
+
+// MustParse is like Parse but panics on invalid input. Intended for constants and tests.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount with exactly Scale decimal places, e.g. "100.50".
+func (a Amount) String() string {
+	// The magnitude is unsigned, as negating the smallest int64 overflows
+	units := uint64(a)
+	sign := ""
+	if a < 0 {
+		sign, units = "-", -units
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units/minorUnits, units%minorUnits)
+}
+
+// Float64 returns the nearest float64, for metrics and logs only.
+func (a Amount) Float64() float64 {
+	return float64(a) / minorUnits
+}
+
+// IsPositive reports whether the amount is greater than zero.
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// Convert returns the amount multiplied by an exchange rate, rounded half away from
+// zero to minor units. The rate is taken at its shortest decimal representation,
+// so a float32 rate of 0.92 multiplies by exactly 0.92. ErrAmountOverflow is returned
+// if the result does not fit in an Amount.
+func (a Amount) Convert(rate float32) (Amount, error) {
+	return a.ConvertRound(rate, Scale)
+}
+
+// ConvertRound is like Convert but rounds the result to decimals decimal places,
+// for currencies with fewer decimal places than Scale. The product is rounded
+// once, so rounding to minor units first cannot shift the result.
+func (a Amount) ConvertRound(rate float32, decimals int) (Amount, error) {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(float64(rate), 'g', -1, 32))
+	if !ok {
+		return 0, nil
+	}
+	unit := roundingUnit(decimals)
+	product := r.Mul(r, new(big.Rat).SetFrac64(int64(a), unit))
+
+	// Round half away from zero: trunc(num/den ± 1/2)
+	num, den := product.Num(), product.Denom()
+	twice := new(big.Int).Mul(num, big.NewInt(2))
+	if twice.Sign() >= 0 {
+		twice.Add(twice, den)
+	} else {
+		twice.Sub(twice, den)
+	}
+	rounded := twice.Quo(twice, new(big.Int).Mul(den, big.NewInt(2)))
+	rounded.Mul(rounded, big.NewInt(unit))
+	if !rounded.IsInt64() {
+		return 0, fmt.Errorf("%w: %s at rate %v", ErrAmountOverflow, a, rate)
+	}
+	return Amount(rounded.Int64()), nil
+}
+
+// Round returns the amount rounded half away from zero to decimals decimal places.
+// Decimals of Scale or more leave the amount unchanged.
+func (a Amount) Round(decimals int) Amount {
+	unit := roundingUnit(decimals)
+	units := int64(a)
+	if units < 0 {
+		return Amount(-((-units + unit/2) / unit * unit))
+	}
+	return Amount((units + unit/2) / unit * unit)
+}
+
+// roundingUnit returns the number of minor units in the last of decimals decimal places.
+func roundingUnit(decimals int) int64 {
+	unit := int64(1)
+	for i := max(decimals, 0); i < Scale; i++ {
+		unit *= 10
+	}
+	return unit
+}
+
+// MarshalJSON encodes the amount as a JSON number with Scale decimal places.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding a decimal number.
+// Exponents and more than Scale decimal places are rejected.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		return nil
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value implements driver.Valuer, passing the amount to the database as decimal text.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case string:
+		return a.scanText(v)
+	case []byte:
+		return a.scanText(string(v))
+	case int64:
+		*a = Amount(v * minorUnits)
+		return nil
+	case float64:
+		return a.scanText(strconv.FormatFloat(v, 'f', Scale, 64))
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+}
+
+// scanText parses NUMERIC text, which may carry trailing zeros beyond Scale.
+func (a *Amount) scanText(s string) error {
+	if whole, frac, ok := strings.Cut(s, "."); ok && len(frac) > Scale {
+		s = whole + "." + strings.TrimRight(frac, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
@@ -0,0 +1,200 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Amount
+		wantErr bool
+	}{
+		{in: "100", want: 10000},
+		{in: "100.5", want: 10050},
+		{in: "100.05", want: 10005},
+		{in: "0.01", want: 1},
+		{in: "-2.50", want: -250},
+		{in: "0.1", want: 10},
+		{in: "1.005", wantErr: true},
+		{in: "1e2", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "1.", wantErr: true},
+		{in: ".5", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{amount: 10050, want: "100.50"},
+		{amount: 5, want: "0.05"},
+		{amount: -5, want: "-0.05"},
+		{amount: Zero, want: "0.00"},
+		{amount: math.MaxInt64, want: "92233720368547758.07"},
+		{amount: math.MinInt64, want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.amount.String())
+		})
+	}
+}
+
+func TestAmount_Convert(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Amount
+		rate    float32
+		want    Amount
+		wantErr bool
+	}{
+		{name: "exact rate", amount: MustParse("100"), rate: 0.92, want: MustParse("92")},
+		{name: "float32 rate has no binary error", amount: MustParse("0.10"), rate: 0.7, want: MustParse("0.07")},
+		{name: "rounds half up", amount: MustParse("0.05"), rate: 0.5, want: MustParse("0.03")},
+		{name: "rounds down", amount: MustParse("0.04"), rate: 0.5, want: MustParse("0.02")},
+		{name: "large rate", amount: MustParse("1.10"), rate: 95.5, want: MustParse("105.05")},
+		{name: "negative rounds away from zero", amount: MustParse("-0.05"), rate: 0.5, want: MustParse("-0.03")},
+		{name: "largest amount at rate 1", amount: math.MaxInt64, rate: 1, want: math.MaxInt64},
+		{name: "smallest amount at rate 1", amount: math.MinInt64, rate: 1, want: math.MinInt64},
+		{name: "largest amount at a large rate overflows", amount: math.MaxInt64, rate: 95.5, wantErr: true},
+		{name: "smallest amount at a large rate overflows", amount: math.MinInt64, rate: 95.5, wantErr: true},
+		{name: "result just above the largest amount overflows", amount: math.MaxInt64 / 2, rate: 2.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.Convert(tt.rate)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrAmountOverflow)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAmount_ConvertRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Amount
+		rate     float32
+		decimals int
+		want     Amount
+		wantErr  bool
+	}{
+		{name: "rounds down to units", amount: MustParse("1.00"), rate: 92.49, decimals: 0, want: MustParse("92")},
+		{name: "rounds half up to units", amount: MustParse("1.00"), rate: 92.5, decimals: 0, want: MustParse("93")},
+		{name: "rounds to one decimal", amount: MustParse("1.00"), rate: 9.25, decimals: 1, want: MustParse("9.3")},
+		{name: "rounds to minor units", amount: MustParse("1.49"), rate: 0.335, decimals: 2, want: MustParse("0.50")},
+		// Rounded once: 0.49915 is 0, although 0.50 rounded to whole units would be 1
+		{name: "rounds once", amount: MustParse("1.49"), rate: 0.335, decimals: 0, want: Zero},
+		{name: "negative rounds away from zero", amount: MustParse("-1.00"), rate: 92.5, decimals: 0, want: MustParse("-93")},
+		{name: "largest amount", amount: math.MaxInt64, rate: 1, decimals: 0, want: math.MaxInt64 - 7},
+		{name: "smallest amount", amount: math.MinInt64, rate: 1, decimals: 0, want: math.MinInt64 + 8},
+		{name: "large amount at a large rate overflows", amount: math.MaxInt64 / 2, rate: 2.5, decimals: 0, wantErr: true},
+		{name: "small amount at a large rate overflows", amount: math.MinInt64 / 2, rate: 2.5, decimals: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.ConvertRound(tt.rate, tt.decimals)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrAmountOverflow)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAmount_Round(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		decimals int
+		want     Amount
+	}{
+		{amount: MustParse("1.49"), decimals: 0, want: MustParse("1")},
+		{amount: MustParse("1.50"), decimals: 0, want: MustParse("2")},
+		{amount: MustParse("-1.50"), decimals: 0, want: MustParse("-2")},
+		{amount: MustParse("1.25"), decimals: 1, want: MustParse("1.3")},
+		{amount: MustParse("1.25"), decimals: Scale, want: MustParse("1.25")},
+		{amount: MustParse("1.25"), decimals: Scale + 1, want: MustParse("1.25")},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.amount, tt.decimals), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.amount.Round(tt.decimals))
+		})
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var v struct {
+		Amount Amount `json:"amount"`
+	}
+
+	data, err := json.Marshal(struct {
+		Amount Amount `json:"amount"`
+	}{Amount: MustParse("0.30")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":0.30}`, string(data))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":0.1}`), &v))
+	assert.Equal(t, Amount(10), v.Amount)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"12.34"}`), &v))
+	assert.Equal(t, Amount(1234), v.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":0.001}`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":true}`), &v))
+}
+
+func TestAmount_SQL(t *testing.T) {
+	value, err := MustParse("100.5").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "100.50", value)
+
+	var a Amount
+	assert.NoError(t, a.Scan("100.50"))
+	assert.Equal(t, Amount(10050), a)
+
+	assert.NoError(t, a.Scan([]byte("7.1000")))
+	assert.Equal(t, Amount(710), a)
+
+	assert.NoError(t, a.Scan(int64(3)))
+	assert.Equal(t, Amount(300), a)
+
+	assert.NoError(t, a.Scan(12.34))
+	assert.Equal(t, Amount(1234), a)
+
+	assert.NoError(t, a.Scan(nil))
+	assert.Equal(t, Zero, a)
+
+	assert.Error(t, a.Scan(true))
+}
@@ -0,0 +1,30 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// LogNotifier delivers notifications by writing them to the application log.
+// It is used until a mail or push delivery channel is configured.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new LogNotifier.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the notification.
+func (n *LogNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	logger.FromContext(ctx).Infow("notification",
+		"userID", notification.UserID,
+		"channel", notification.Channel,
+		"email", notification.Email,
+		"phone", notification.Phone,
+		"subject", notification.Subject,
+		"body", notification.Body,
+	)
+	return nil
+}
@@ -0,0 +1,21 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNotifier_Notify(t *testing.T) {
+
+	err := NewLogNotifier().Notify(context.Background(), models.Notification{
+		UserID:  uuid.New(),
+		Email:   "user@example.com",
+		Subject: "subject",
+		Body:    "body",
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,22 @@
+// Package profiling serves the runtime profiles and debug variables of the process.
+package profiling
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+)
+
+// Handler returns the pprof handlers under /debug/pprof/ and the expvar variables under
+// /debug/vars. It has no authentication and is meant for a port reachable only by operators.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	// Index also serves the named profiles: heap, goroutine, allocs, block, mutex, threadcreate
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
@@ -0,0 +1,38 @@
+package profiling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		contains string
+	}{
+		{name: "index", path: "/debug/pprof/", contains: "goroutine"},
+		{name: "goroutine_dump", path: "/debug/pprof/goroutine?debug=2", contains: "goroutine 1 ["},
+		{name: "heap", path: "/debug/pprof/heap?debug=1", contains: "heap profile"},
+		{name: "cmdline", path: "/debug/pprof/cmdline", contains: "profiling.test"},
+		{name: "vars", path: "/debug/vars", contains: `"memstats"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, strings.Contains(rec.Body.String(), tt.contains), rec.Body.String())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/balance", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
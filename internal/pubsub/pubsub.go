@@ -0,0 +1,87 @@
+// Package pubsub fans out messages to subscribers within the process, such as the open
+// event streams of a user.
+package pubsub
+
+import "sync"
+
+// Broker delivers the messages published to a topic to its current subscribers. Publishing
+// never blocks: a subscriber that falls behind loses its oldest undelivered message.
+type Broker[T any] struct {
+	mu     sync.Mutex
+	buffer int
+	topics map[string]map[chan T]struct{}
+	closed bool
+}
+
+// NewBroker creates a Broker buffering up to buffer messages per subscriber, at least one.
+func NewBroker[T any](buffer int) *Broker[T] {
+	return &Broker[T]{buffer: max(buffer, 1), topics: map[string]map[chan T]struct{}{}}
+}
+
+// Subscribe returns a channel receiving the messages published to topic from now on, and a
+// function ending the subscription. The channel is closed when the subscription ends or the
+// broker is closed.
+func (b *Broker[T]) Subscribe(topic string) (<-chan T, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan T, b.buffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.topics[topic] == nil {
+		b.topics[topic] = map[chan T]struct{}{}
+	}
+	b.topics[topic][ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.topics[topic][ch]; !ok {
+			return
+		}
+		delete(b.topics[topic], ch)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+		close(ch)
+	}
+}
+
+// Publish delivers msg to the subscribers of topic.
+func (b *Broker[T]) Publish(topic string, msg T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.topics[topic] {
+		select {
+		case ch <- msg:
+		default:
+			// Only publishers send, under the lock, so dropping the oldest makes room
+			<-ch
+			ch <- msg
+		}
+	}
+}
+
+// Subscribers returns the number of subscriptions to topic.
+func (b *Broker[T]) Subscribers(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.topics[topic])
+}
+
+// Close ends every subscription; later subscriptions are closed at once.
+func (b *Broker[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.topics {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+	b.topics = map[string]map[chan T]struct{}{}
+}
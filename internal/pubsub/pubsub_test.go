@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drain returns the messages buffered in ch.
+func drain(ch <-chan int) []int {
+	var msgs []int
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return msgs
+			}
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	b := NewBroker[int](4)
+	alice1, cancel1 := b.Subscribe("alice")
+	defer cancel1()
+	alice2, cancel2 := b.Subscribe("alice")
+	defer cancel2()
+	bob, cancel3 := b.Subscribe("bob")
+	defer cancel3()
+
+	b.Publish("alice", 1)
+	b.Publish("alice", 2)
+	b.Publish("carol", 3)
+
+	assert.Equal(t, []int{1, 2}, drain(alice1))
+	assert.Equal(t, []int{1, 2}, drain(alice2))
+	assert.Empty(t, drain(bob))
+	assert.Equal(t, 2, b.Subscribers("alice"))
+}
+
+func TestBroker_SlowSubscriberLosesOldest(t *testing.T) {
+	b := NewBroker[int](2)
+	ch, cancel := b.Subscribe("alice")
+	defer cancel()
+
+	for i := 1; i <= 5; i++ {
+		b.Publish("alice", i)
+	}
+
+	assert.Equal(t, []int{4, 5}, drain(ch))
+}
+
+func TestBroker_Unsubscribe(t *testing.T) {
+	b := NewBroker[int](1)
+	ch, cancel := b.Subscribe("alice")
+
+	cancel()
+	cancel()
+	b.Publish("alice", 1)
+
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.Equal(t, 0, b.Subscribers("alice"))
+}
+
+func TestBroker_Close(t *testing.T) {
+	b := NewBroker[int](1)
+	ch, cancel := b.Subscribe("alice")
+
+	b.Close()
+	_, ok := <-ch
+	assert.False(t, ok)
+	cancel()
+
+	late, _ := b.Subscribe("alice")
+	_, ok = <-late
+	assert.False(t, ok)
+	b.Publish("alice", 1)
+}
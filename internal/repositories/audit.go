@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+)
+
+// AuditWriteRepository appends records to the audit trail
+type AuditWriteRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditWriteRepository(db *sqlx.DB) *AuditWriteRepository {
+	return &AuditWriteRepository{db: db}
+}
+
+// Save appends an audit record with the ID of the request of ctx, if any. targetID may be nil
+// for actions without a target user.
+func (r *AuditWriteRepository) Save(ctx context.Context, actorID uuid.UUID, action string, targetID *uuid.UUID, details map[string]any) error {
+	query := `
+		INSERT INTO audit_log (actor_id, action, target_id, details, request_id, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NOW())
+	`
+
+	if details == nil {
+		details = map[string]any{}
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	reqID := requestid.FromContext(ctx)
+	args := []any{actorID, action, targetID, payload, reqID}
+	_, err = r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{actorID, action, targetID, details, reqID},
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditWriteRepository_Save(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	repo := NewAuditWriteRepository(db)
+
+	actorID := uuid.New()
+	targetID := uuid.New()
+
+	err := repo.Save(ctx, actorID, models.AuditActionImpersonate, &targetID, map[string]any{"ttl_seconds": 900})
+	assert.NoError(t, err)
+
+	err = repo.Save(context.Background(), actorID, "noop", nil, nil)
+	assert.NoError(t, err)
+
+	var records []models.AuditLogDB
+	err = db.Select(&records, `SELECT audit_id, actor_id, action, target_id, details, request_id, created_at FROM audit_log WHERE actor_id=$1 ORDER BY created_at`, actorID)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	assert.Equal(t, models.AuditActionImpersonate, records[0].Action)
+	if assert.NotNil(t, records[0].TargetID) {
+		assert.Equal(t, targetID, *records[0].TargetID)
+	}
+	assert.JSONEq(t, `{"ttl_seconds": 900}`, string(records[0].Details))
+	if assert.NotNil(t, records[0].RequestID) {
+		assert.Equal(t, "req-1", *records[0].RequestID)
+	}
+	assert.Nil(t, records[1].TargetID)
+	assert.Nil(t, records[1].RequestID)
+}
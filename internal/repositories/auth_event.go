@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// AuthEventRepository stores authentication events such as logins
+type AuthEventRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuthEventRepository(db *sqlx.DB) *AuthEventRepository {
+	return &AuthEventRepository{db: db}
+}
+
+// Save appends an authentication event for the user
+func (r *AuthEventRepository) Save(ctx context.Context, userID uuid.UUID, eventType string, client models.ClientInfo) error {
+	query := `
+		INSERT INTO auth_events (user_id, event_type, ip, user_agent, country, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`
+	args := []any{userID, eventType, client.IP, client.UserAgent, client.Country}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// ListByUserID returns the user's most recent events of the given types, newest first
+func (r *AuthEventRepository) ListByUserID(ctx context.Context, userID uuid.UUID, eventTypes []string, limit int) ([]models.AuthEventDB, error) {
+	query, args, err := sqlx.In(`
+		SELECT event_id, user_id, event_type, ip, user_agent, country, created_at
+		FROM auth_events
+		WHERE user_id = ? AND event_type IN (?)
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, userID, eventTypes, limit)
+	if err != nil {
+		return nil, err
+	}
+	query = r.db.Rebind(query)
+
+	var events []models.AuthEventDB
+	err = r.db.SelectContext(ctx, &events, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(events),
+		"error", err,
+	)
+
+	return events, err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthEventRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	repo := NewAuthEventRepository(db)
+	client := models.ClientInfo{IP: "203.0.113.7", UserAgent: "curl/8.0", Country: "NL"}
+
+	assert.NoError(t, repo.Save(ctx, userID, models.AuthEventLoginFailure, client))
+	assert.NoError(t, repo.Save(ctx, userID, models.AuthEventLoginSuccess, client))
+	assert.NoError(t, repo.Save(ctx, userID, "password_change", client))
+
+	loginTypes := []string{models.AuthEventLoginSuccess, models.AuthEventLoginFailure}
+
+	t.Run("newest first, filtered by type", func(t *testing.T) {
+		events, err := repo.ListByUserID(ctx, userID, loginTypes, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, models.AuthEventLoginSuccess, events[0].EventType)
+			assert.Equal(t, models.AuthEventLoginFailure, events[1].EventType)
+			assert.Equal(t, "203.0.113.7", events[0].IP)
+			assert.Equal(t, "curl/8.0", events[0].UserAgent)
+			assert.Equal(t, "NL", events[0].Country)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		events, err := repo.ListByUserID(ctx, userID, loginTypes, 1)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		events, err := repo.ListByUserID(ctx, uuid.New(), loginTypes, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// BalanceHistoryRepository stores daily snapshots of wallet balances
+type BalanceHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewBalanceHistoryRepository(db *sqlx.DB) *BalanceHistoryRepository {
+	return &BalanceHistoryRepository{db: db}
+}
+
+// Snapshot records the current balance of every wallet for the given day. Repeated snapshots
+// of the same day overwrite the earlier ones, so the day keeps the last balance seen.
+func (r *BalanceHistoryRepository) Snapshot(ctx context.Context, date time.Time) (int64, error) {
+	query := `
+		INSERT INTO balance_history (user_id, currency, snapshot_date, balance)
+		SELECT user_id, currency, $1::date, balance
+		FROM wallets
+		ON CONFLICT (user_id, snapshot_date, currency)
+		DO UPDATE SET balance = EXCLUDED.balance, created_at = NOW()
+	`
+	args := []any{date}
+
+	var affected int64
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		affected, err = res.RowsAffected()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", affected,
+		"error", err,
+	)
+
+	return affected, err
+}
+
+// ListByUserID returns the user's snapshots between from and to inclusive, ordered by day and currency
+func (r *BalanceHistoryRepository) ListByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.BalanceSnapshotDB, error) {
+	query := `
+		SELECT user_id, currency, snapshot_date, balance
+		FROM balance_history
+		WHERE user_id = $1 AND snapshot_date BETWEEN $2::date AND $3::date
+		ORDER BY snapshot_date, currency
+	`
+	args := []any{userID, from, to}
+
+	var snapshots []models.BalanceSnapshotDB
+	err := r.db.SelectContext(ctx, &snapshots, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(snapshots),
+		"error", err,
+	)
+
+	return snapshots, err
+}
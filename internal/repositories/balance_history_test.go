@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceHistoryRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+	testkit.CreateWallet(t, db, userID, "USD", money.MustParse("100"))
+	testkit.CreateWallet(t, db, userID, "EUR", money.MustParse("50"))
+
+	repo := NewBalanceHistoryRepository(db)
+	yesterday := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+
+	affected, err := repo.Snapshot(ctx, yesterday)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	// A later snapshot of the same day keeps the last balance
+	_, err = db.Exec(`UPDATE wallets SET balance = 80 WHERE user_id = $1 AND currency = 'USD'`, userID)
+	assert.NoError(t, err)
+	_, err = repo.Snapshot(ctx, today)
+	assert.NoError(t, err)
+	_, err = db.Exec(`UPDATE wallets SET balance = 70 WHERE user_id = $1 AND currency = 'USD'`, userID)
+	assert.NoError(t, err)
+	_, err = repo.Snapshot(ctx, today)
+	assert.NoError(t, err)
+
+	snapshots, err := repo.ListByUserID(ctx, userID, yesterday, today)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BalanceSnapshotDB{
+		{UserID: userID, Currency: models.EUR, Date: yesterday, Balance: money.MustParse("50")},
+		{UserID: userID, Currency: models.USD, Date: yesterday, Balance: money.MustParse("100")},
+		{UserID: userID, Currency: models.EUR, Date: today, Balance: money.MustParse("50")},
+		{UserID: userID, Currency: models.USD, Date: today, Balance: money.MustParse("70")},
+	}, snapshots)
+
+	// The range is inclusive and limited to the user
+	snapshots, err = repo.ListByUserID(ctx, userID, today, today)
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	snapshots, err = repo.ListByUserID(ctx, uuid.New(), yesterday, today)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
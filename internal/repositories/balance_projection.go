@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// balancesCheckpoint is the projection_checkpoints row tracking balance projection progress.
+const balancesCheckpoint = "balances"
+
+// BalanceProjectionRepository maintains and reads the materialized balance read model
+// projected from wallet_events.
+type BalanceProjectionRepository struct {
+	db *sqlx.DB
+}
+
+func NewBalanceProjectionRepository(db *sqlx.DB) *BalanceProjectionRepository {
+	return &BalanceProjectionRepository{db: db}
+}
+
+// ApplyPending projects up to limit unprocessed events into balance_projections
+// and advances the checkpoint, returning the number of events applied.
+// Events are taken in the order of their inserting transaction and only from transactions
+// older than every running one, which have all committed or rolled back: an event whose
+// event_id was allocated earlier but committed later is applied once it commits, not skipped.
+func (r *BalanceProjectionRepository) ApplyPending(ctx context.Context, limit int) (int, error) {
+	query := `
+		WITH checkpoint AS (
+			SELECT last_xid, last_event_id FROM projection_checkpoints WHERE name = $1 FOR UPDATE
+		),
+		batch AS (
+			SELECT e.xid, e.event_id, e.user_id, e.currency, e.balance
+			FROM wallet_events e, checkpoint c
+			WHERE (e.xid, e.event_id) > (c.last_xid, c.last_event_id)
+			  AND e.xid < pg_snapshot_xmin(pg_current_snapshot())
+			ORDER BY e.xid, e.event_id
+			LIMIT $2
+		),
+		last AS (
+			SELECT xid, event_id FROM batch ORDER BY xid DESC, event_id DESC LIMIT 1
+		),
+		latest AS (
+			SELECT DISTINCT ON (user_id, currency) user_id, currency, balance, event_id
+			FROM batch
+			ORDER BY user_id, currency, event_id DESC
+		),
+		upserted AS (
+			INSERT INTO balance_projections (user_id, currency, balance, last_event_id, updated_at)
+			SELECT user_id, currency, balance, event_id, NOW() FROM latest
+			ON CONFLICT (user_id, currency)
+			DO UPDATE SET balance = EXCLUDED.balance, last_event_id = EXCLUDED.last_event_id, updated_at = NOW()
+			WHERE balance_projections.last_event_id < EXCLUDED.last_event_id
+		)
+		UPDATE projection_checkpoints
+		SET last_xid = COALESCE((SELECT xid FROM last), last_xid),
+			last_event_id = COALESCE((SELECT event_id FROM last), last_event_id),
+			updated_at = NOW()
+		WHERE name = $1
+		RETURNING (SELECT COUNT(*) FROM batch)
+	`
+	args := []any{balancesCheckpoint, limit}
+
+	var applied int
+	err := r.db.GetContext(ctx, &applied, query, args...)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", applied,
+		"error", err,
+	)
+
+	return applied, err
+}
+
+// GetByUserID retrieves projected balances for a given user as a map[currency]balance
+func (r *BalanceProjectionRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	const query = `
+		SELECT currency, balance
+		FROM balance_projections
+		WHERE user_id = $1
+	`
+
+	var rows []struct {
+		Currency string       `db:"currency"`
+		Balance  money.Amount `db:"balance"`
+	}
+
+	err := r.db.SelectContext(ctx, &rows, query, userID)
+
+	balances := make(map[string]money.Amount, len(rows))
+	for _, row := range rows {
+		balances[row.Currency] = row.Balance
+	}
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", balances,
+		"error", err,
+	)
+
+	return balances, err
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceProjectionRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("projected")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	projection := NewBalanceProjectionRepository(db)
+
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), "USD"))
+	assert.NoError(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("30"), "USD"))
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("10"), "EUR"))
+
+	t.Run("Projection is empty before apply", func(t *testing.T) {
+		balances, err := projection.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, balances)
+	})
+
+	t.Run("ApplyPending projects latest balances", func(t *testing.T) {
+		applied, err := projection.ApplyPending(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, applied)
+
+		applied, err = projection.ApplyPending(ctx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, applied)
+
+		balances, err := projection.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("70"), balances["USD"])
+		assert.Equal(t, money.MustParse("10"), balances["EUR"])
+	})
+
+	t.Run("ApplyPending is a no-op when caught up", func(t *testing.T) {
+		applied, err := projection.ApplyPending(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, applied)
+	})
+
+	t.Run("ApplyPending waits for events committed out of order", func(t *testing.T) {
+		// The open transaction allocates its event_id before the deposit but commits after it
+		tx, err := db.BeginTxx(ctx, nil)
+		assert.NoError(t, err)
+		_, err = tx.ExecContext(ctx, `INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			VALUES ($1, 'RUB', 'deposit', 5, 5)`, userID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("20"), "EUR"))
+
+		applied, err := projection.ApplyPending(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, applied)
+
+		assert.NoError(t, tx.Commit())
+
+		applied, err = projection.ApplyPending(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, applied)
+
+		balances, err := projection.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("5"), balances["RUB"])
+		assert.Equal(t, money.MustParse("30"), balances["EUR"])
+	})
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// CurrencyRepository reads the supported currencies
+type CurrencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewCurrencyRepository(db *sqlx.DB) *CurrencyRepository {
+	return &CurrencyRepository{db: db}
+}
+
+// ListEnabled returns the enabled currencies ordered by code
+func (r *CurrencyRepository) ListEnabled(ctx context.Context) ([]models.CurrencyDB, error) {
+	const query = `
+		SELECT code, name, enabled, decimals, min_amount, created_at
+		FROM currencies
+		WHERE enabled
+		ORDER BY code
+	`
+
+	var currencies []models.CurrencyDB
+	err := r.db.SelectContext(ctx, &currencies, query)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{},
+		"result", len(currencies),
+		"error", err,
+	)
+
+	return currencies, err
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyRepository_ListEnabled(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO currencies (code, name, enabled) VALUES ('GBP', 'Pound Sterling', FALSE), ('CNY', 'Yuan', TRUE)`)
+	assert.NoError(t, err)
+
+	repo := NewCurrencyRepository(db)
+	currencies, err := repo.ListEnabled(ctx)
+	assert.NoError(t, err)
+
+	codes := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		codes = append(codes, c.Code)
+	}
+	assert.Equal(t, []string{"CNY", models.EUR, models.RUB, models.USD}, codes)
+}
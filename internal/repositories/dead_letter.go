@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// DeadLetterRepository keeps the Kafka events that could not be published until they are replayed
+type DeadLetterRepository struct {
+	db *sqlx.DB
+}
+
+func NewDeadLetterRepository(db *sqlx.DB) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+// Save stores a dead-lettered event
+func (r *DeadLetterRepository) Save(ctx context.Context, event models.DeadLetterEvent) error {
+	query := `
+		INSERT INTO dead_letter_events (id, topic, message_key, event_id, payload, attempts, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+	`
+	args := []any{event.ID, event.Topic, event.Key, event.EventID, event.Payload, event.Attempts, event.LastError}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// ListPending returns up to limit events that were not replayed, oldest first
+func (r *DeadLetterRepository) ListPending(ctx context.Context, limit int) ([]models.DeadLetterEvent, error) {
+	query := `
+		SELECT id, topic, message_key, event_id, payload, attempts, last_error, created_at, replayed_at
+		FROM dead_letter_events
+		WHERE replayed_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`
+	args := []any{limit}
+
+	var events []models.DeadLetterEvent
+	err := r.db.SelectContext(ctx, &events, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(events),
+		"error", err,
+	)
+
+	return events, err
+}
+
+// GetByID returns a dead-lettered event, or sql.ErrNoRows if it does not exist
+func (r *DeadLetterRepository) GetByID(ctx context.Context, id uuid.UUID) (models.DeadLetterEvent, error) {
+	query := `
+		SELECT id, topic, message_key, event_id, payload, attempts, last_error, created_at, replayed_at
+		FROM dead_letter_events
+		WHERE id = $1
+	`
+	args := []any{id}
+
+	var event models.DeadLetterEvent
+	err := r.db.GetContext(ctx, &event, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", event.ID,
+		"error", err,
+	)
+
+	return event, err
+}
+
+// MarkReplayed records the replay of an event. It returns false if the event was already replayed
+func (r *DeadLetterRepository) MarkReplayed(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE dead_letter_events
+		SET replayed_at = NOW()
+		WHERE id = $1 AND replayed_at IS NULL
+	`
+	args := []any{id}
+	res, err := r.db.ExecContext(ctx, query, args...)
+	var updated int64
+	if err == nil {
+		updated, err = res.RowsAffected()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", updated,
+		"error", err,
+	)
+
+	return updated == 1, err
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	repo := NewDeadLetterRepository(db)
+
+	first := models.DeadLetterEvent{ID: uuid.New(), Topic: "transactions", Key: "user-1", EventID: "event-1", Payload: []byte(`{"transaction_id":"txn-1"}`), Attempts: 3, LastError: "broker unreachable"}
+	second := models.DeadLetterEvent{ID: uuid.New(), Topic: "transactions", Key: "txn-2", Payload: []byte(`{"transaction_id":"txn-2"}`), Attempts: 3, LastError: "broker unreachable"}
+	assert.NoError(t, repo.Save(ctx, first))
+	assert.NoError(t, repo.Save(ctx, second))
+
+	t.Run("list pending oldest first", func(t *testing.T) {
+		events, err := repo.ListPending(ctx, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, first.ID, events[0].ID)
+			assert.Equal(t, "transactions", events[0].Topic)
+			assert.Equal(t, "user-1", events[0].Key)
+			assert.Equal(t, "event-1", events[0].EventID)
+			assert.JSONEq(t, `{"transaction_id":"txn-1"}`, string(events[0].Payload))
+			assert.Equal(t, 3, events[0].Attempts)
+			assert.Equal(t, "broker unreachable", events[0].LastError)
+			assert.Nil(t, events[0].ReplayedAt)
+		}
+	})
+
+	t.Run("replay once", func(t *testing.T) {
+		replayed, err := repo.MarkReplayed(ctx, first.ID)
+		assert.NoError(t, err)
+		assert.True(t, replayed)
+
+		replayed, err = repo.MarkReplayed(ctx, first.ID)
+		assert.NoError(t, err)
+		assert.False(t, replayed)
+
+		event, err := repo.GetByID(ctx, first.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, event.ReplayedAt)
+
+		events, err := repo.ListPending(ctx, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, second.ID, events[0].ID)
+		}
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, uuid.New())
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// DormancyRepository flags and unflags dormant accounts
+type DormancyRepository struct {
+	db *sqlx.DB
+}
+
+func NewDormancyRepository(db *sqlx.DB) *DormancyRepository {
+	return &DormancyRepository{db: db}
+}
+
+// MarkInactive flags non-admin accounts with no successful login and no wallet operation
+// since inactiveSince, and returns the newly flagged users.
+func (r *DormancyRepository) MarkInactive(ctx context.Context, inactiveSince time.Time) ([]models.UserDB, error) {
+	query := `
+		UPDATE users u
+		SET dormant_at = NOW(), updated_at = NOW()
+		WHERE u.dormant_at IS NULL
+		  AND u.role <> $2
+		  AND u.created_at < $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM auth_events a
+			WHERE a.user_id = u.user_id AND a.event_type = $3 AND a.created_at >= $1
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM wallet_events e
+			WHERE e.user_id = u.user_id AND e.created_at >= $1
+		  )
+		RETURNING u.user_id, u.username, u.email, u.password_hash, u.role, u.dormant_at, u.created_at, u.updated_at
+	`
+	args := []any{inactiveSince, models.RoleAdmin, models.AuthEventLoginSuccess}
+
+	var users []models.UserDB
+	err := r.db.SelectContext(ctx, &users, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(users),
+		"error", err,
+	)
+
+	return users, err
+}
+
+// SetDormant flags or unflags the user as dormant. Returns sql.ErrNoRows if the user does not exist.
+func (r *DormancyRepository) SetDormant(ctx context.Context, userID uuid.UUID, dormant bool) error {
+	query := `
+		UPDATE users
+		SET dormant_at = CASE WHEN $2 THEN COALESCE(dormant_at, NOW()) END, updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING user_id
+	`
+	args := []any{userID, dormant}
+
+	var updated uuid.UUID
+	err := r.db.GetContext(ctx, &updated, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", updated,
+		"error", err,
+	)
+
+	return err
+}
+
+// IsDormant reports whether the user is flagged as dormant. Unknown users are not dormant.
+func (r *DormancyRepository) IsDormant(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1 AND dormant_at IS NOT NULL)
+	`
+
+	var dormant bool
+	err := r.db.GetContext(ctx, &dormant, query, userID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", dormant,
+		"error", err,
+	)
+
+	return dormant, err
+}
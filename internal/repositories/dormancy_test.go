@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDormancyRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	longAgo := time.Now().AddDate(-2, 0, 0)
+	insertUser := func(username, role string) uuid.UUID {
+		user := testkit.CreateUser(t, db, testkit.WithUsername(username), testkit.WithRole(role), testkit.WithCreatedAt(longAgo))
+		return user.UserID
+	}
+
+	idle := insertUser("idle", models.RoleUser)
+	loggedIn := insertUser("logged_in", models.RoleUser)
+	transacting := insertUser("transacting", models.RoleUser)
+	admin := insertUser("admin", models.RoleAdmin)
+
+	assert.NoError(t, NewAuthEventRepository(db).Save(ctx, loggedIn, models.AuthEventLoginSuccess, models.ClientInfo{}))
+	assert.NoError(t, NewWalletWriterRepository(db, nil).SaveDeposit(ctx, uuid.New(), transacting, 10, models.USD))
+
+	repo := NewDormancyRepository(db)
+
+	t.Run("MarkInactive", func(t *testing.T) {
+		flagged, err := repo.MarkInactive(ctx, time.Now().AddDate(-1, 0, 0))
+		assert.NoError(t, err)
+		if assert.Len(t, flagged, 1) {
+			assert.Equal(t, idle, flagged[0].UserID)
+			assert.NotNil(t, flagged[0].DormantAt)
+		}
+
+		// Already flagged users are not returned again
+		flagged, err = repo.MarkInactive(ctx, time.Now().AddDate(-1, 0, 0))
+		assert.NoError(t, err)
+		assert.Empty(t, flagged)
+
+		for _, id := range []uuid.UUID{loggedIn, transacting, admin} {
+			dormant, err := repo.IsDormant(ctx, id)
+			assert.NoError(t, err)
+			assert.False(t, dormant)
+		}
+	})
+
+	t.Run("SetDormant", func(t *testing.T) {
+		assert.NoError(t, repo.SetDormant(ctx, idle, false))
+		dormant, err := repo.IsDormant(ctx, idle)
+		assert.NoError(t, err)
+		assert.False(t, dormant)
+
+		assert.NoError(t, repo.SetDormant(ctx, loggedIn, true))
+		dormant, err = repo.IsDormant(ctx, loggedIn)
+		assert.NoError(t, err)
+		assert.True(t, dormant)
+
+		assert.ErrorIs(t, repo.SetDormant(ctx, uuid.New(), true), sql.ErrNoRows)
+	})
+
+	t.Run("IsDormant unknown user", func(t *testing.T) {
+		dormant, err := repo.IsDormant(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.False(t, dormant)
+	})
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ExchangeFeeRepository reads the fees charged for exchanges
+type ExchangeFeeRepository struct {
+	db *sqlx.DB
+}
+
+func NewExchangeFeeRepository(db *sqlx.DB) *ExchangeFeeRepository {
+	return &ExchangeFeeRepository{db: db}
+}
+
+// Get returns the fee of a currency pair, or sql.ErrNoRows if the pair has none
+func (r *ExchangeFeeRepository) Get(ctx context.Context, fromCurrency, toCurrency string) (models.ExchangeFee, error) {
+	const query = `
+		SELECT from_currency, to_currency, percent, fixed, spread
+		FROM exchange_fees
+		WHERE from_currency = $1 AND to_currency = $2
+	`
+
+	var fee models.ExchangeFee
+	err := r.db.GetContext(ctx, &fee, query, fromCurrency, toCurrency)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{fromCurrency, toCurrency},
+		"result", fee,
+		"error", err,
+	)
+
+	return fee, err
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeFeeRepository_Get(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO exchange_fees (from_currency, to_currency, percent, fixed, spread) VALUES ('USD', 'EUR', 1.5, 0.30, 0.25)`)
+	assert.NoError(t, err)
+
+	repo := NewExchangeFeeRepository(db)
+	fee, err := repo.Get(ctx, models.USD, models.EUR)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExchangeFee{
+		FromCurrency: models.USD,
+		ToCurrency:   models.EUR,
+		Percent:      1.5,
+		Fixed:        money.MustParse("0.30"),
+		Spread:       0.25,
+	}, fee)
+
+	_, err = repo.Get(ctx, models.EUR, models.USD)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ExchangeQuoteRepository keeps locked exchange quotes in Redis until they are used or expire
+type ExchangeQuoteRepository struct {
+	client *redis.Client
+}
+
+// NewExchangeQuoteRepository creates a new repository instance
+func NewExchangeQuoteRepository(client *redis.Client) *ExchangeQuoteRepository {
+	return &ExchangeQuoteRepository{client: client}
+}
+
+// Save stores the quote under its ID for ttl
+func (r *ExchangeQuoteRepository) Save(ctx context.Context, quote models.ExchangeQuote, ttl time.Duration) error {
+	key := "exchange_quote:" + quote.QuoteID.String()
+
+	value, err := json.Marshal(quote)
+	if err == nil {
+		err = r.client.Set(ctx, key, value, ttl).Err()
+	}
+
+	logger.FromContext(ctx).Infow(
+		"key", key,
+		"ttl", ttl,
+		"result", "ok",
+		"error", err,
+	)
+
+	return err
+}
+
+// Take removes the quote and returns it, reporting false if it has expired or was already taken.
+// Removal and read are a single GETDEL, so a quote is taken at most once.
+func (r *ExchangeQuoteRepository) Take(ctx context.Context, quoteID uuid.UUID) (models.ExchangeQuote, bool, error) {
+	key := "exchange_quote:" + quoteID.String()
+
+	var quote models.ExchangeQuote
+	value, err := r.client.GetDel(ctx, key).Bytes()
+	if err == nil {
+		err = json.Unmarshal(value, &quote)
+	}
+
+	logger.FromContext(ctx).Infow(
+		"key", key,
+		"result", string(value),
+		"error", err,
+	)
+
+	if err == redis.Nil {
+		return models.ExchangeQuote{}, false, nil
+	}
+	if err != nil {
+		return models.ExchangeQuote{}, false, err
+	}
+	return quote, true, nil
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeQuoteRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := NewExchangeQuoteRepository(testkit.Redis(t))
+
+	quote := models.ExchangeQuote{
+		QuoteID:      uuid.New(),
+		UserID:       uuid.New(),
+		FromCurrency: models.USD,
+		ToCurrency:   models.EUR,
+		Amount:       money.MustParse("100"),
+		Rate:         0.92,
+		ToAmount:     money.MustParse("92"),
+		ExpiresAt:    time.Now().UTC().Add(time.Minute).Truncate(time.Millisecond),
+	}
+
+	t.Run("taken once", func(t *testing.T) {
+		assert.NoError(t, repo.Save(ctx, quote, time.Minute))
+
+		got, ok, err := repo.Take(ctx, quote.QuoteID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, quote.ExpiresAt.Equal(got.ExpiresAt))
+		got.ExpiresAt = quote.ExpiresAt
+		assert.Equal(t, quote, got)
+
+		_, ok, err = repo.Take(ctx, quote.QuoteID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		assert.NoError(t, repo.Save(ctx, quote, time.Second))
+		time.Sleep(1500 * time.Millisecond)
+
+		_, ok, err := repo.Take(ctx, quote.QuoteID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -2,18 +2,34 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
 )
 
-// ExchangeRateCacheRepository provides cached exchange rates using Redis
+// Cache names of the cache lookup metrics
+const (
+	pairRateCache = "exchange_rate"  // Rates of single currency pairs
+	allRatesCache = "exchange_rates" // Rates of all currencies
+)
+
+// ErrRateNotCached is returned when a rate is not in the cache.
+var ErrRateNotCached = errors.New("exchange rate not found in cache")
+
+// ExchangeRateCacheRepository provides cached exchange rates using Redis.
+// Rates are stored with the time they were fetched and the provider that returned them, so callers
+// can tell fresh rates from stale ones and report where a rate came from.
 type ExchangeRateCacheRepository struct {
 	client *redis.Client
-	exp    time.Duration // expiration duration for cached rates
+	exp    time.Duration // expiration duration for cached rates, the longest a stale rate can be served
 }
 
 // NewExchangeRateCacheRepository creates a new repository instance with optional TTL
@@ -24,50 +40,126 @@ func NewExchangeRateCacheRepository(client *redis.Client, expiration time.Durati
 	}
 }
 
-// GetExchangeRateForCurrency fetches a cached exchange rate between two currencies
-func (r *ExchangeRateCacheRepository) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error) {
+// GetExchangeRateForCurrency fetches a cached exchange rate between two currencies with its source.
+// Values cached without a fetch time are reported as fetched at the zero time, and values cached
+// without a provider with an empty provider.
+func (r *ExchangeRateCacheRepository) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
 	key := fmt.Sprintf("exchange_rate:%s:%s", fromCurrency, toCurrency)
 
 	val, err := r.client.Get(ctx, key).Result()
 	if err != nil {
-		logger.Log.Infow(
+		logger.FromContext(ctx).Infow(
 			"key", key,
 			"result", val,
 			"error", err,
 		)
 		if err == redis.Nil {
-			return 0, fmt.Errorf("exchange rate not found in cache for %s->%s", fromCurrency, toCurrency)
+			metrics.CacheRequests.WithLabelValues(pairRateCache, metrics.CacheMiss).Inc()
+			return 0, models.RateSource{}, fmt.Errorf("%w for %s->%s", ErrRateNotCached, fromCurrency, toCurrency)
 		}
-		return 0, err
+		return 0, models.RateSource{}, err
 	}
+	metrics.CacheRequests.WithLabelValues(pairRateCache, metrics.CacheHit).Inc()
 
-	rate, err := strconv.ParseFloat(val, 32)
+	rate, src, err := parseCachedRate(val)
 	if err != nil {
-		logger.Log.Infow(
+		logger.FromContext(ctx).Infow(
 			"key", key,
 			"value", val,
 			"result", 0,
 			"error", err,
 		)
-		return 0, err
+		return 0, models.RateSource{}, err
 	}
 
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"key", key,
 		"value", val,
 		"result", rate,
 		"error", nil,
 	)
 
-	return float32(rate), nil
+	return rate, src, nil
+}
+
+// GetExchangeRatesForCurrencies fetches the cached rates of several currency pairs with
+// their sources in a single MGET round trip. Pairs that are not cached, or whose value
+// cannot be parsed, are left out of the result.
+func (r *ExchangeRateCacheRepository) GetExchangeRatesForCurrencies(ctx context.Context, pairs []models.CurrencyPair) (map[models.CurrencyPair]models.PairRate, error) {
+	rates := make(map[models.CurrencyPair]models.PairRate, len(pairs))
+	if len(pairs) == 0 {
+		return rates, nil
+	}
+
+	keys := make([]string, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = fmt.Sprintf("exchange_rate:%s:%s", pair.From, pair.To)
+	}
+
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		logger.FromContext(ctx).Infow(
+			"keys", keys,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	for i, val := range vals {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		rate, src, err := parseCachedRate(str)
+		if err != nil {
+			logger.FromContext(ctx).Infow(
+				"key", keys[i],
+				"value", str,
+				"error", err,
+			)
+			continue
+		}
+		rates[pairs[i]] = models.PairRate{Rate: rate, Source: src}
+	}
+
+	logger.FromContext(ctx).Infow(
+		"keys", keys,
+		"hits", len(rates),
+		"error", nil,
+	)
+	metrics.CacheRequests.WithLabelValues(pairRateCache, metrics.CacheHit).Add(float64(len(rates)))
+	metrics.CacheRequests.WithLabelValues(pairRateCache, metrics.CacheMiss).Add(float64(len(pairs) - len(rates)))
+
+	return rates, nil
 }
 
-// SetExchangeRateForCurrency caches a new exchange rate in Redis with expiration
-func (r *ExchangeRateCacheRepository) SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32) error {
+// parseCachedRate parses a cached pair value: rate|fetched at (Unix milliseconds)|provider.
+// Older values lack the trailing fields.
+func parseCachedRate(val string) (float32, models.RateSource, error) {
+	rateStr, rest, hasFetched := strings.Cut(val, "|")
+	rate, err := strconv.ParseFloat(rateStr, 32)
+	if err != nil {
+		return 0, models.RateSource{}, err
+	}
+	src := models.RateSource{Cached: true}
+	if hasFetched {
+		fetchedStr, provider, _ := strings.Cut(rest, "|")
+		fetchedMs, err := strconv.ParseInt(fetchedStr, 10, 64)
+		if err != nil {
+			return 0, models.RateSource{}, err
+		}
+		src.FetchedAt = time.UnixMilli(fetchedMs)
+		src.Provider = provider
+	}
+	return float32(rate), src, nil
+}
+
+// SetExchangeRateForCurrency caches a new exchange rate with the time it was fetched and its provider in Redis with expiration
+func (r *ExchangeRateCacheRepository) SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32, src models.RateSource) error {
 	key := fmt.Sprintf("exchange_rate:%s:%s", fromCurrency, toCurrency)
-	err := r.client.Set(ctx, key, fmt.Sprintf("%f", rate), r.exp).Err()
+	err := r.client.Set(ctx, key, fmt.Sprintf("%f|%d|%s", rate, src.FetchedAt.UnixMilli(), src.Provider), r.exp).Err()
 
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"key", key,
 		"rate", rate,
 		"result", "ok",
@@ -76,3 +168,62 @@ func (r *ExchangeRateCacheRepository) SetExchangeRateForCurrency(ctx context.Con
 
 	return err
 }
+
+// exchangeRatesKey holds the rates of all currencies, as last returned by the exchanger.
+const exchangeRatesKey = "exchange_rates"
+
+// cachedExchangeRates is the cached value of exchangeRatesKey.
+type cachedExchangeRates struct {
+	Rates     map[string]float32 `json:"rates"`
+	FetchedAt int64              `json:"fetched_at"` // Unix milliseconds
+	Provider  string             `json:"provider,omitempty"`
+}
+
+// GetExchangeRates fetches the cached rates of all currencies with their source.
+func (r *ExchangeRateCacheRepository) GetExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error) {
+	val, err := r.client.Get(ctx, exchangeRatesKey).Result()
+	if err != nil {
+		logger.FromContext(ctx).Infow(
+			"key", exchangeRatesKey,
+			"result", val,
+			"error", err,
+		)
+		if err == redis.Nil {
+			metrics.CacheRequests.WithLabelValues(allRatesCache, metrics.CacheMiss).Inc()
+			return nil, models.RateSource{}, fmt.Errorf("%w for all currencies", ErrRateNotCached)
+		}
+		return nil, models.RateSource{}, err
+	}
+	metrics.CacheRequests.WithLabelValues(allRatesCache, metrics.CacheHit).Inc()
+
+	var cached cachedExchangeRates
+	err = json.Unmarshal([]byte(val), &cached)
+
+	logger.FromContext(ctx).Infow(
+		"key", exchangeRatesKey,
+		"value", val,
+		"error", err,
+	)
+	if err != nil {
+		return nil, models.RateSource{}, err
+	}
+
+	return cached.Rates, models.RateSource{Provider: cached.Provider, FetchedAt: time.UnixMilli(cached.FetchedAt), Cached: true}, nil
+}
+
+// SetExchangeRates caches the rates of all currencies with the time they were fetched and their provider in Redis with expiration
+func (r *ExchangeRateCacheRepository) SetExchangeRates(ctx context.Context, rates map[string]float32, src models.RateSource) error {
+	data, err := json.Marshal(cachedExchangeRates{Rates: rates, FetchedAt: src.FetchedAt.UnixMilli(), Provider: src.Provider})
+	if err == nil {
+		err = r.client.Set(ctx, exchangeRatesKey, data, r.exp).Err()
+	}
+
+	logger.FromContext(ctx).Infow(
+		"key", exchangeRatesKey,
+		"rates", rates,
+		"result", "ok",
+		"error", err,
+	)
+
+	return err
+}
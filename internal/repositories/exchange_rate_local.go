@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// localRateCache is the cache name of the lookup metrics of LocalRateCache.
+const localRateCache = "exchange_rate_local"
+
+// LocalRateCacheSize is how many rates LocalRateCache keeps: pairs, plus the rates of all currencies.
+const LocalRateCacheSize = 1024
+
+// RedisRetryInterval is how long LocalRateCache serves rates from memory alone after Redis failed.
+const RedisRetryInterval = 5 * time.Second
+
+// RateCache caches exchange rates with their source, e.g. ExchangeRateCacheRepository.
+type RateCache interface {
+	GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error)
+	SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32, src models.RateSource) error
+	GetExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error)
+	GetExchangeRatesForCurrencies(ctx context.Context, pairs []models.CurrencyPair) (map[models.CurrencyPair]models.PairRate, error)
+	SetExchangeRates(ctx context.Context, rates map[string]float32, src models.RateSource) error
+}
+
+// LocalRateCache keeps the rates written to and read from a shared cache (Redis) in an in-memory
+// LRU, which serves them while the shared cache is unavailable, so exchanges keep using recent
+// rates rather than all falling through to the exchanger. Writes then succeed in memory alone.
+// The shared cache is skipped for RedisRetryInterval after it failed.
+type LocalRateCache struct {
+	shared RateCache
+	size   int
+	exp    time.Duration // How long a rate is kept, as in the shared cache
+	retry  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Of *localRate, most recently used first
+
+	downUntil atomic.Int64 // Unix nanoseconds until which the shared cache is skipped
+}
+
+// localRate is a rate of LocalRateCache: a models.PairRate or allRates.
+type localRate struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// allRates is the value of the rates of all currencies.
+type allRates struct {
+	rates map[string]float32
+	src   models.RateSource
+}
+
+// NewLocalRateCache creates a LocalRateCache of size rates in front of shared, keeping rates for expiration.
+func NewLocalRateCache(shared RateCache, size int, expiration time.Duration) *LocalRateCache {
+	return &LocalRateCache{
+		shared:  shared,
+		size:    max(size, 1),
+		exp:     expiration,
+		retry:   RedisRetryInterval,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// GetExchangeRateForCurrency returns the rate between two currencies with its source.
+func (c *LocalRateCache) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
+	key := fmt.Sprintf("exchange_rate:%s:%s", fromCurrency, toCurrency)
+	if c.sharedUp() {
+		rate, src, err := c.shared.GetExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
+		if err == nil {
+			c.put(key, models.PairRate{Rate: rate, Source: src})
+			return rate, src, nil
+		}
+		if !c.failed(ctx, err) {
+			return 0, models.RateSource{}, err
+		}
+	}
+
+	if value, ok := c.get(key); ok {
+		pr := value.(models.PairRate)
+		return pr.Rate, pr.Source, nil
+	}
+	return 0, models.RateSource{}, fmt.Errorf("%w for %s->%s", ErrRateNotCached, fromCurrency, toCurrency)
+}
+
+// GetExchangeRatesForCurrencies returns the rates of several currency pairs with their sources,
+// leaving out the pairs that are not cached.
+func (c *LocalRateCache) GetExchangeRatesForCurrencies(ctx context.Context, pairs []models.CurrencyPair) (map[models.CurrencyPair]models.PairRate, error) {
+	if c.sharedUp() {
+		rates, err := c.shared.GetExchangeRatesForCurrencies(ctx, pairs)
+		if err == nil {
+			for pair, pr := range rates {
+				c.put(fmt.Sprintf("exchange_rate:%s:%s", pair.From, pair.To), pr)
+			}
+			return rates, nil
+		}
+		if !c.failed(ctx, err) {
+			return nil, err
+		}
+	}
+
+	rates := make(map[models.CurrencyPair]models.PairRate, len(pairs))
+	for _, pair := range pairs {
+		if value, ok := c.get(fmt.Sprintf("exchange_rate:%s:%s", pair.From, pair.To)); ok {
+			rates[pair] = value.(models.PairRate)
+		}
+	}
+	return rates, nil
+}
+
+// SetExchangeRateForCurrency caches the rate between two currencies with its source.
+func (c *LocalRateCache) SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32, src models.RateSource) error {
+	cached := src
+	cached.Cached = true
+	c.put(fmt.Sprintf("exchange_rate:%s:%s", fromCurrency, toCurrency), models.PairRate{Rate: rate, Source: cached})
+	if !c.sharedUp() {
+		return nil
+	}
+	if err := c.shared.SetExchangeRateForCurrency(ctx, fromCurrency, toCurrency, rate, src); err != nil && !c.failed(ctx, err) {
+		return err
+	}
+	return nil
+}
+
+// GetExchangeRates returns the rates of all currencies with their source.
+func (c *LocalRateCache) GetExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error) {
+	if c.sharedUp() {
+		rates, src, err := c.shared.GetExchangeRates(ctx)
+		if err == nil {
+			c.put(exchangeRatesKey, allRates{rates: maps.Clone(rates), src: src})
+			return rates, src, nil
+		}
+		if !c.failed(ctx, err) {
+			return nil, models.RateSource{}, err
+		}
+	}
+
+	if value, ok := c.get(exchangeRatesKey); ok {
+		all := value.(allRates)
+		return maps.Clone(all.rates), all.src, nil
+	}
+	return nil, models.RateSource{}, fmt.Errorf("%w for all currencies", ErrRateNotCached)
+}
+
+// SetExchangeRates caches the rates of all currencies with their source.
+func (c *LocalRateCache) SetExchangeRates(ctx context.Context, rates map[string]float32, src models.RateSource) error {
+	cached := src
+	cached.Cached = true
+	c.put(exchangeRatesKey, allRates{rates: maps.Clone(rates), src: cached})
+	if !c.sharedUp() {
+		return nil
+	}
+	if err := c.shared.SetExchangeRates(ctx, rates, src); err != nil && !c.failed(ctx, err) {
+		return err
+	}
+	return nil
+}
+
+// sharedUp reports whether the shared cache is to be used.
+func (c *LocalRateCache) sharedUp() bool {
+	return time.Now().UnixNano() >= c.downUntil.Load()
+}
+
+// failed reports whether err means the shared cache is unavailable, skipping it for a while if so.
+// Misses and the caller giving up do not.
+func (c *LocalRateCache) failed(ctx context.Context, err error) bool {
+	if errors.Is(err, ErrRateNotCached) || ctx.Err() != nil {
+		return false
+	}
+	logger.FromContext(ctx).Warnw("rate cache unavailable, serving rates from memory", "retry_in", c.retry, "error", err)
+	c.downUntil.Store(time.Now().Add(c.retry).UnixNano())
+	return true
+}
+
+// get returns the unexpired value of key, counting the lookup in the cache metrics.
+func (c *LocalRateCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if ok && time.Now().After(elem.Value.(*localRate).expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		metrics.CacheRequests.WithLabelValues(localRateCache, metrics.CacheMiss).Inc()
+		return nil, false
+	}
+	metrics.CacheRequests.WithLabelValues(localRateCache, metrics.CacheHit).Inc()
+	c.order.MoveToFront(elem)
+	return elem.Value.(*localRate).value, true
+}
+
+// put stores value under key, evicting the least recently used rate if the cache is full.
+func (c *LocalRateCache) put(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.exp)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*localRate)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&localRate{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*localRate).key)
+	}
+}
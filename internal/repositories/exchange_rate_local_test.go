@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRateCache is a shared rate cache that can be taken down.
+type fakeRateCache struct {
+	pairs map[string]models.PairRate
+	all   *allRates
+	err   error // Returned by every call while set
+	calls int
+}
+
+func newFakeRateCache() *fakeRateCache {
+	return &fakeRateCache{pairs: map[string]models.PairRate{}}
+}
+
+func (f *fakeRateCache) GetExchangeRateForCurrency(_ context.Context, from, to string) (float32, models.RateSource, error) {
+	f.calls++
+	if f.err != nil {
+		return 0, models.RateSource{}, f.err
+	}
+	pr, ok := f.pairs[from+to]
+	if !ok {
+		return 0, models.RateSource{}, fmt.Errorf("%w for %s->%s", ErrRateNotCached, from, to)
+	}
+	return pr.Rate, pr.Source, nil
+}
+
+func (f *fakeRateCache) SetExchangeRateForCurrency(_ context.Context, from, to string, rate float32, src models.RateSource) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	f.pairs[from+to] = models.PairRate{Rate: rate, Source: src}
+	return nil
+}
+
+func (f *fakeRateCache) GetExchangeRates(context.Context) (map[string]float32, models.RateSource, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, models.RateSource{}, f.err
+	}
+	if f.all == nil {
+		return nil, models.RateSource{}, ErrRateNotCached
+	}
+	return f.all.rates, f.all.src, nil
+}
+
+func (f *fakeRateCache) GetExchangeRatesForCurrencies(_ context.Context, pairs []models.CurrencyPair) (map[models.CurrencyPair]models.PairRate, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	rates := map[models.CurrencyPair]models.PairRate{}
+	for _, pair := range pairs {
+		if pr, ok := f.pairs[pair.From+pair.To]; ok {
+			rates[pair] = pr
+		}
+	}
+	return rates, nil
+}
+
+func (f *fakeRateCache) SetExchangeRates(_ context.Context, rates map[string]float32, src models.RateSource) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	f.all = &allRates{rates: rates, src: src}
+	return nil
+}
+
+func TestLocalRateCache(t *testing.T) {
+	ctx := context.Background()
+	fetchedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	src := models.RateSource{Provider: "grpc", FetchedAt: fetchedAt}
+	cached := models.RateSource{Provider: "grpc", FetchedAt: fetchedAt, Cached: true}
+	redisDown := errors.New("dial tcp: connection refused")
+
+	t.Run("serves rates from memory while redis is down", func(t *testing.T) {
+		shared := newFakeRateCache()
+		cache := NewLocalRateCache(shared, 10, time.Minute)
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "USD", "EUR", 0.92, src))
+		assert.NoError(t, cache.SetExchangeRates(ctx, map[string]float32{"USD": 1, "EUR": 0.92}, src))
+
+		shared.err = redisDown
+		rate, got, err := cache.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.92), rate)
+		assert.Equal(t, cached, got)
+
+		rates, got, err := cache.GetExchangeRates(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]float32{"USD": 1, "EUR": 0.92}, rates)
+		assert.Equal(t, cached, got)
+
+		pairs, err := cache.GetExchangeRatesForCurrencies(ctx, []models.CurrencyPair{{From: "USD", To: "EUR"}, {From: "USD", To: "RUB"}})
+		assert.NoError(t, err)
+		assert.Equal(t, map[models.CurrencyPair]models.PairRate{{From: "USD", To: "EUR"}: {Rate: 0.92, Source: cached}}, pairs)
+
+		_, _, err = cache.GetExchangeRateForCurrency(ctx, "USD", "RUB")
+		assert.ErrorIs(t, err, ErrRateNotCached)
+
+		// Redis was asked once, then skipped; writes succeed in memory
+		assert.Equal(t, 3, shared.calls)
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "USD", "RUB", 90, src))
+		rate, _, err = cache.GetExchangeRateForCurrency(ctx, "USD", "RUB")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(90), rate)
+		assert.Equal(t, 3, shared.calls)
+	})
+
+	t.Run("keeps rates read from redis", func(t *testing.T) {
+		shared := newFakeRateCache()
+		shared.pairs["USDEUR"] = models.PairRate{Rate: 0.9, Source: cached}
+		cache := NewLocalRateCache(shared, 10, time.Minute)
+
+		rate, _, err := cache.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.9), rate)
+
+		shared.err = redisDown
+		rate, _, err = cache.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.9), rate)
+	})
+
+	t.Run("misses do not take redis down", func(t *testing.T) {
+		shared := newFakeRateCache()
+		cache := NewLocalRateCache(shared, 10, time.Minute)
+
+		_, _, err := cache.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.ErrorIs(t, err, ErrRateNotCached)
+		_, _, err = cache.GetExchangeRates(ctx)
+		assert.ErrorIs(t, err, ErrRateNotCached)
+		assert.True(t, cache.sharedUp())
+	})
+
+	t.Run("redis is retried after the interval", func(t *testing.T) {
+		shared := newFakeRateCache()
+		cache := NewLocalRateCache(shared, 10, time.Minute)
+		cache.retry = 0
+
+		shared.err = redisDown
+		_, _, err := cache.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+		assert.ErrorIs(t, err, ErrRateNotCached)
+
+		shared.err = nil
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "USD", "EUR", 0.92, src))
+		assert.Equal(t, models.PairRate{Rate: 0.92, Source: src}, shared.pairs["USDEUR"])
+	})
+
+	t.Run("least recently used rate is evicted", func(t *testing.T) {
+		shared := newFakeRateCache()
+		cache := NewLocalRateCache(shared, 2, time.Minute)
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "USD", "EUR", 0.92, src))
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "USD", "RUB", 90, src))
+		_, ok := cache.get("exchange_rate:USD:EUR")
+		assert.True(t, ok)
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "EUR", "RUB", 98, src))
+
+		_, ok = cache.get("exchange_rate:USD:RUB")
+		assert.False(t, ok)
+		_, ok = cache.get("exchange_rate:USD:EUR")
+		assert.True(t, ok)
+	})
+
+	t.Run("expired rates are dropped", func(t *testing.T) {
+		cache := NewLocalRateCache(newFakeRateCache(), 10, 0)
+		assert.NoError(t, cache.SetExchangeRateForCurrency(ctx, "USD", "EUR", 0.92, src))
+		time.Sleep(time.Millisecond)
+
+		_, ok := cache.get("exchange_rate:USD:EUR")
+		assert.False(t, ok)
+	})
+}
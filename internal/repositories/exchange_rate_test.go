@@ -2,46 +2,23 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"testing"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
 	"github.com/stretchr/testify/assert"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 func TestExchangeRateCacheRepository(t *testing.T) {
 	ctx := context.Background()
 
-	// Start Redis container
-	req := testcontainers.ContainerRequest{
-		Image:        "redis:7.0-alpine",
-		ExposedPorts: []string{"6379/tcp"},
-		WaitingFor:   wait.ForListeningPort("6379/tcp"),
-	}
-	redisC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	assert.NoError(t, err)
-	defer redisC.Terminate(ctx)
-
-	// Get container host and port
-	host, err := redisC.Host(ctx)
-	assert.NoError(t, err)
-	port, err := redisC.MappedPort(ctx, "6379")
-	assert.NoError(t, err)
-
-	// Connect to Redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", host, port.Port()),
-	})
-	defer rdb.Close()
+	rdb := testkit.Redis(t)
 
 	// Ping to ensure connection
-	err = rdb.Ping(ctx).Err()
+	err := rdb.Ping(ctx).Err()
 	assert.NoError(t, err)
 
 	repo := NewExchangeRateCacheRepository(rdb, 2*time.Second)
@@ -49,17 +26,90 @@ func TestExchangeRateCacheRepository(t *testing.T) {
 	t.Run("Set and Get exchange rate", func(t *testing.T) {
 		from, to := "USD", "EUR"
 		rate := float32(1.23)
+		fetchedAt := time.UnixMilli(time.Now().UnixMilli())
 
-		err := repo.SetExchangeRateForCurrency(ctx, from, to, rate)
+		err := repo.SetExchangeRateForCurrency(ctx, from, to, rate, models.RateSource{Provider: "grpc", FetchedAt: fetchedAt})
 		assert.NoError(t, err)
 
-		got, err := repo.GetExchangeRateForCurrency(ctx, from, to)
+		got, src, err := repo.GetExchangeRateForCurrency(ctx, from, to)
 		assert.NoError(t, err)
 		assert.Equal(t, rate, got)
+		assert.True(t, fetchedAt.Equal(src.FetchedAt))
+		assert.Equal(t, "grpc", src.Provider)
+		assert.True(t, src.Cached)
+	})
+
+	t.Run("Value without fetch time is reported as fetched at zero time", func(t *testing.T) {
+		err := rdb.Set(ctx, "exchange_rate:USD:RUB", "90.000000", time.Minute).Err()
+		assert.NoError(t, err)
+
+		got, src, err := repo.GetExchangeRateForCurrency(ctx, "USD", "RUB")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(90), got)
+		assert.True(t, src.FetchedAt.IsZero())
+	})
+
+	t.Run("Value without provider is reported with an empty provider", func(t *testing.T) {
+		err := rdb.Set(ctx, "exchange_rate:EUR:RUB", "98.000000|1700000000000", time.Minute).Err()
+		assert.NoError(t, err)
+
+		got, src, err := repo.GetExchangeRateForCurrency(ctx, "EUR", "RUB")
+		assert.NoError(t, err)
+		assert.Equal(t, float32(98), got)
+		assert.Equal(t, models.RateSource{FetchedAt: time.UnixMilli(1700000000000), Cached: true}, src)
+	})
+
+	t.Run("Lookups are counted as cache hits and misses", func(t *testing.T) {
+		hits := metrics.CacheRequests.WithLabelValues(pairRateCache, metrics.CacheHit)
+		misses := metrics.CacheRequests.WithLabelValues(pairRateCache, metrics.CacheMiss)
+		hitsBefore, missesBefore := testutil.ToFloat64(hits), testutil.ToFloat64(misses)
+
+		_, _, err := repo.GetExchangeRateForCurrency(ctx, "EUR", "RUB")
+		assert.NoError(t, err)
+		_, _, err = repo.GetExchangeRateForCurrency(ctx, "RUB", "USD")
+		assert.Error(t, err)
+
+		assert.Equal(t, hitsBefore+1, testutil.ToFloat64(hits))
+		assert.Equal(t, missesBefore+1, testutil.ToFloat64(misses))
+	})
+
+	t.Run("Set and Get all exchange rates", func(t *testing.T) {
+		rates := map[string]float32{"USD": 1, "EUR": 0.92, "RUB": 95}
+		fetchedAt := time.UnixMilli(time.Now().UnixMilli())
+
+		err := repo.SetExchangeRates(ctx, rates, models.RateSource{Provider: "http", FetchedAt: fetchedAt})
+		assert.NoError(t, err)
+
+		got, src, err := repo.GetExchangeRates(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, rates, got)
+		assert.True(t, fetchedAt.Equal(src.FetchedAt))
+		assert.Equal(t, "http", src.Provider)
+	})
+
+	t.Run("Get several exchange rates at once", func(t *testing.T) {
+		fetchedAt := time.UnixMilli(time.Now().UnixMilli())
+		err := repo.SetExchangeRateForCurrency(ctx, "RUB", "USD", 0.011, models.RateSource{Provider: "grpc", FetchedAt: fetchedAt})
+		assert.NoError(t, err)
+		err = rdb.Set(ctx, "exchange_rate:RUB:GBP", "not a rate", time.Minute).Err()
+		assert.NoError(t, err)
+
+		usd := models.CurrencyPair{From: "RUB", To: "USD"}
+		rub := models.CurrencyPair{From: "EUR", To: "RUB"}
+		got, err := repo.GetExchangeRatesForCurrencies(ctx, []models.CurrencyPair{usd, rub, {From: "RUB", To: "GBP"}, {From: "ABC", To: "XYZ"}})
+		assert.NoError(t, err)
+		assert.Equal(t, map[models.CurrencyPair]models.PairRate{
+			usd: {Rate: 0.011, Source: models.RateSource{Provider: "grpc", FetchedAt: fetchedAt, Cached: true}},
+			rub: {Rate: 98, Source: models.RateSource{FetchedAt: time.UnixMilli(1700000000000), Cached: true}},
+		}, got)
+
+		got, err = repo.GetExchangeRatesForCurrencies(ctx, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
 	})
 
 	t.Run("Get missing key returns error", func(t *testing.T) {
-		_, err := repo.GetExchangeRateForCurrency(ctx, "ABC", "XYZ")
+		_, _, err := repo.GetExchangeRateForCurrency(ctx, "ABC", "XYZ")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "exchange rate not found")
 	})
@@ -68,13 +118,13 @@ func TestExchangeRateCacheRepository(t *testing.T) {
 		from, to := "GBP", "USD"
 		rate := float32(1.5)
 
-		err := repo.SetExchangeRateForCurrency(ctx, from, to, rate)
+		err := repo.SetExchangeRateForCurrency(ctx, from, to, rate, models.RateSource{FetchedAt: time.Now()})
 		assert.NoError(t, err)
 
 		// Wait for expiration (2s)
 		time.Sleep(3 * time.Second)
 
-		_, err = repo.GetExchangeRateForCurrency(ctx, from, to)
+		_, _, err = repo.GetExchangeRateForCurrency(ctx, from, to)
 		assert.Error(t, err)
 	})
 }
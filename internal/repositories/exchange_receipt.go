@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ExchangeReceiptRepository stores exchange receipts until they are delivered to the exchanger
+type ExchangeReceiptRepository struct {
+	db       *sqlx.DB
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewExchangeReceiptRepository(db *sqlx.DB, txGetter func(ctx context.Context) *sqlx.Tx) *ExchangeReceiptRepository {
+	return &ExchangeReceiptRepository{db: db, txGetter: txGetter}
+}
+
+// Save queues a receipt for delivery. A receipt with the same transaction ID is kept as is.
+func (r *ExchangeReceiptRepository) Save(ctx context.Context, receipt models.ExchangeReceipt) error {
+	query := `
+		INSERT INTO exchange_receipts (transaction_id, operation, from_currency, to_currency, amount, to_amount, rate, executed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (transaction_id) DO NOTHING
+	`
+	args := []any{
+		receipt.TransactionID, receipt.Operation, receipt.FromCurrency, receipt.ToCurrency,
+		receipt.Amount, receipt.ToAmount, receipt.Rate, receipt.ExecutedAt,
+	}
+	_, err := r.executor(ctx).ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// ListDue returns up to limit undelivered receipts whose next attempt is due, oldest first
+func (r *ExchangeReceiptRepository) ListDue(ctx context.Context, limit int) ([]models.ExchangeReceiptDB, error) {
+	query := `
+		SELECT transaction_id, operation, from_currency, to_currency, amount, to_amount, rate, executed_at,
+			attempts, next_attempt_at, last_error, published_at
+		FROM exchange_receipts
+		WHERE published_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY next_attempt_at
+		LIMIT $1
+	`
+	args := []any{limit}
+
+	var receipts []models.ExchangeReceiptDB
+	err := r.db.SelectContext(ctx, &receipts, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(receipts),
+		"error", err,
+	)
+
+	return receipts, err
+}
+
+// ListExecuted returns the receipts of the conversions executed in [from, to), oldest first
+func (r *ExchangeReceiptRepository) ListExecuted(ctx context.Context, from, to time.Time) ([]models.ExchangeReceiptDB, error) {
+	query := `
+		SELECT transaction_id, operation, from_currency, to_currency, amount, to_amount, rate, executed_at,
+			attempts, next_attempt_at, last_error, published_at
+		FROM exchange_receipts
+		WHERE executed_at >= $1 AND executed_at < $2
+		ORDER BY executed_at
+	`
+	args := []any{from, to}
+
+	var receipts []models.ExchangeReceiptDB
+	err := r.db.SelectContext(ctx, &receipts, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(receipts),
+		"error", err,
+	)
+
+	return receipts, err
+}
+
+// MarkPublished records the delivery of a receipt
+func (r *ExchangeReceiptRepository) MarkPublished(ctx context.Context, transactionID uuid.UUID) error {
+	query := `
+		UPDATE exchange_receipts
+		SET published_at = NOW(), last_error = NULL
+		WHERE transaction_id = $1
+	`
+	args := []any{transactionID}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// MarkFailed records a failed delivery and postpones the next attempt to nextAttemptAt
+func (r *ExchangeReceiptRepository) MarkFailed(ctx context.Context, transactionID uuid.UUID, nextAttemptAt time.Time, reason string) error {
+	query := `
+		UPDATE exchange_receipts
+		SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+		WHERE transaction_id = $1
+	`
+	args := []any{transactionID, nextAttemptAt, reason}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// executor returns the transaction of the request if there is one, otherwise the database
+func (r *ExchangeReceiptRepository) executor(ctx context.Context) sqlx.ExtContext {
+	if r.txGetter != nil {
+		if tx := r.txGetter(ctx); tx != nil {
+			return tx
+		}
+	}
+	return r.db
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeReceiptRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	repo := NewExchangeReceiptRepository(db, nil)
+	receipt := models.ExchangeReceipt{
+		TransactionID: uuid.New(),
+		Operation:     models.OperationExchange,
+		FromCurrency:  models.USD,
+		ToCurrency:    models.EUR,
+		Amount:        money.MustParse("100"),
+		ToAmount:      money.MustParse("90"),
+		Rate:          0.9,
+		ExecutedAt:    time.Now().UTC().Truncate(time.Second),
+	}
+
+	// Saving twice keeps a single receipt
+	assert.NoError(t, repo.Save(ctx, receipt))
+	assert.NoError(t, repo.Save(ctx, receipt))
+
+	due, err := repo.ListDue(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, receipt, due[0].ExchangeReceipt)
+	assert.Zero(t, due[0].Attempts)
+
+	// A failed delivery is retried once the next attempt is due
+	assert.NoError(t, repo.MarkFailed(ctx, receipt.TransactionID, time.Now().Add(time.Hour), "broker down"))
+	due, err = repo.ListDue(ctx, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+
+	assert.NoError(t, repo.MarkFailed(ctx, receipt.TransactionID, time.Now().Add(-time.Second), "broker down"))
+	due, err = repo.ListDue(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, 2, due[0].Attempts)
+	assert.Equal(t, "broker down", *due[0].LastError)
+
+	// Published receipts are not delivered again
+	assert.NoError(t, repo.MarkPublished(ctx, receipt.TransactionID))
+	due, err = repo.ListDue(ctx, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+
+	// Receipts are listed by execution time for reconciliation
+	executed, err := repo.ListExecuted(ctx, receipt.ExecutedAt, receipt.ExecutedAt.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, executed, 1)
+	assert.NotNil(t, executed[0].PublishedAt)
+
+	executed, err = repo.ListExecuted(ctx, receipt.ExecutedAt.Add(time.Second), receipt.ExecutedAt.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, executed)
+}
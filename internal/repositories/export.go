@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ExportRepository stores asynchronous export jobs and their results
+type ExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewExportRepository(db *sqlx.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// Create registers a pending export and returns its identifier
+func (r *ExportRepository) Create(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error) {
+	query := `
+		INSERT INTO exports (user_id, format, status, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING export_id
+	`
+	args := []any{userID, format, models.ExportStatusPending}
+
+	var exportID uuid.UUID
+	err := r.db.GetContext(ctx, &exportID, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", exportID,
+		"error", err,
+	)
+
+	return exportID, err
+}
+
+// GetByID returns the user's export. Returns sql.ErrNoRows if it does not exist or belongs to another user.
+func (r *ExportRepository) GetByID(ctx context.Context, userID, exportID uuid.UUID) (*models.ExportDB, error) {
+	query := `
+		SELECT export_id, user_id, format, status, content, error, created_at, completed_at
+		FROM exports
+		WHERE export_id = $1 AND user_id = $2
+	`
+	args := []any{exportID, userID}
+
+	var export models.ExportDB
+	err := r.db.GetContext(ctx, &export, query, args...)
+
+	// Content is omitted from the log
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", export.Status,
+		"error", err,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetLatest returns the user's most recent export in the given format.
+// Returns sql.ErrNoRows if the user has none.
+func (r *ExportRepository) GetLatest(ctx context.Context, userID uuid.UUID, format string) (*models.ExportDB, error) {
+	query := `
+		SELECT export_id, user_id, format, status, content, error, created_at, completed_at
+		FROM exports
+		WHERE user_id = $1 AND format = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	args := []any{userID, format}
+
+	var export models.ExportDB
+	err := r.db.GetContext(ctx, &export, query, args...)
+
+	// Content is omitted from the log
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", export.Status,
+		"error", err,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ClaimPending marks the oldest pending export as processing and returns it. An export
+// left in processing for longer than staleAfter, by a worker that stopped mid-run, is
+// claimed again. Returns sql.ErrNoRows if there is nothing to process.
+func (r *ExportRepository) ClaimPending(ctx context.Context, staleAfter time.Duration) (*models.ExportDB, error) {
+	query := `
+		UPDATE exports
+		SET status = $1, updated_at = NOW()
+		WHERE export_id = (
+			SELECT export_id FROM exports
+			WHERE status = $2
+				OR (status = $1 AND updated_at < NOW() - make_interval(secs => $3))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING export_id, user_id, format, status, content, error, created_at, completed_at
+	`
+	args := []any{models.ExportStatusProcessing, models.ExportStatusPending, staleAfter.Seconds()}
+
+	var export models.ExportDB
+	err := r.db.GetContext(ctx, &export, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", export.ExportID,
+		"error", err,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Complete stores the generated file and marks the export as completed
+func (r *ExportRepository) Complete(ctx context.Context, exportID uuid.UUID, content []byte) error {
+	query := `
+		UPDATE exports
+		SET status = $2, content = $3, completed_at = NOW(), updated_at = NOW()
+		WHERE export_id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, exportID, models.ExportStatusCompleted, content)
+
+	// Content is omitted from the log
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{exportID, models.ExportStatusCompleted, len(content)},
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// Fail marks the export as failed with the given reason
+func (r *ExportRepository) Fail(ctx context.Context, exportID uuid.UUID, reason string) error {
+	query := `
+		UPDATE exports
+		SET status = $2, error = $3, completed_at = NOW(), updated_at = NOW()
+		WHERE export_id = $1
+	`
+	args := []any{exportID, models.ExportStatusFailed, reason}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRepository_Lifecycle(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	repo := NewExportRepository(db)
+
+	first, err := repo.Create(ctx, userID, models.ExportFormatAccountingCSV)
+	assert.NoError(t, err)
+	second, err := repo.Create(ctx, userID, models.ExportFormatAccountingCSV)
+	assert.NoError(t, err)
+
+	t.Run("GetByID pending", func(t *testing.T) {
+		export, err := repo.GetByID(ctx, userID, first)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ExportStatusPending, export.Status)
+		assert.Nil(t, export.CompletedAt)
+	})
+
+	t.Run("GetByID other user", func(t *testing.T) {
+		export, err := repo.GetByID(ctx, uuid.New(), first)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Nil(t, export)
+	})
+
+	t.Run("GetLatest", func(t *testing.T) {
+		export, err := repo.GetLatest(ctx, userID, models.ExportFormatAccountingCSV)
+		assert.NoError(t, err)
+		assert.Equal(t, second, export.ExportID)
+
+		_, err = repo.GetLatest(ctx, userID, models.ExportFormatUserDataZIP)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Claim and complete", func(t *testing.T) {
+		claimed, err := repo.ClaimPending(ctx, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, first, claimed.ExportID)
+		assert.Equal(t, models.ExportStatusProcessing, claimed.Status)
+
+		assert.NoError(t, repo.Complete(ctx, claimed.ExportID, []byte("csv")))
+
+		export, err := repo.GetByID(ctx, userID, first)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ExportStatusCompleted, export.Status)
+		assert.Equal(t, []byte("csv"), export.Content)
+		assert.NotNil(t, export.CompletedAt)
+	})
+
+	t.Run("Claim and fail", func(t *testing.T) {
+		claimed, err := repo.ClaimPending(ctx, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, second, claimed.ExportID)
+
+		assert.NoError(t, repo.Fail(ctx, claimed.ExportID, "boom"))
+
+		export, err := repo.GetByID(ctx, userID, second)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ExportStatusFailed, export.Status)
+		if assert.NotNil(t, export.Error) {
+			assert.Equal(t, "boom", *export.Error)
+		}
+	})
+
+	t.Run("Nothing to claim", func(t *testing.T) {
+		claimed, err := repo.ClaimPending(ctx, time.Minute)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Nil(t, claimed)
+	})
+
+	t.Run("Reclaim stuck processing", func(t *testing.T) {
+		stuck, err := repo.Create(ctx, userID, models.ExportFormatAccountingCSV)
+		assert.NoError(t, err)
+		claimed, err := repo.ClaimPending(ctx, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, stuck, claimed.ExportID)
+
+		// Still within the timeout
+		_, err = repo.ClaimPending(ctx, time.Minute)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = db.ExecContext(ctx, `UPDATE exports SET updated_at = NOW() - INTERVAL '2 minutes' WHERE export_id = $1`, stuck)
+		assert.NoError(t, err)
+		claimed, err = repo.ClaimPending(ctx, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, stuck, claimed.ExportID)
+		assert.Equal(t, models.ExportStatusProcessing, claimed.Status)
+	})
+}
+
+func TestWalletEventReadRepository_ListByUserID(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("bob")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), models.USD))
+	assert.NoError(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("40"), models.USD))
+
+	repo := NewWalletEventReadRepository(db)
+
+	events, err := repo.ListByUserID(ctx, userID)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, models.OperationDeposit, events[0].Operation)
+		assert.Equal(t, money.MustParse("100"), events[0].Amount)
+		assert.Equal(t, models.OperationWithdraw, events[1].Operation)
+		assert.Equal(t, money.MustParse("60"), events[1].Balance)
+	}
+
+	events, err = repo.ListByUserID(ctx, uuid.New())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
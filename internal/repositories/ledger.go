@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// LedgerRepository reads the double-entry ledger. Entries are posted by the wallet
+// repositories in the same statements that change the balances.
+type LedgerRepository struct {
+	db *sqlx.DB
+}
+
+func NewLedgerRepository(db *sqlx.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Entries returns the entries of a ledger transaction in posting order
+func (r *LedgerRepository) Entries(ctx context.Context, transactionID uuid.UUID) ([]models.LedgerEntryDB, error) {
+	query := `
+		SELECT entry_id, transaction_id, account, user_id, currency, amount
+		FROM ledger_entries
+		WHERE transaction_id = $1
+		ORDER BY entry_id
+	`
+	args := []any{transactionID}
+
+	var entries []models.LedgerEntryDB
+	err := r.db.SelectContext(ctx, &entries, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(entries),
+		"error", err,
+	)
+
+	return entries, err
+}
+
+// Mismatches returns the wallets whose stored balance differs from the sum of their ledger
+// entries. Closed wallets are included when their entries do not sum to zero.
+func (r *LedgerRepository) Mismatches(ctx context.Context) ([]models.LedgerMismatch, error) {
+	query := `
+		WITH ledger AS (
+			SELECT user_id, currency, SUM(amount) AS balance
+			FROM ledger_entries
+			WHERE account = 'wallet'
+			GROUP BY user_id, currency
+		)
+		SELECT COALESCE(w.user_id, l.user_id) AS user_id, COALESCE(w.currency, l.currency) AS currency,
+			COALESCE(w.balance, 0) AS balance, COALESCE(l.balance, 0) AS ledger_balance
+		FROM wallets w
+		FULL JOIN ledger l ON l.user_id = w.user_id AND l.currency = w.currency
+		WHERE COALESCE(w.balance, 0) <> COALESCE(l.balance, 0)
+		ORDER BY 1, 2
+	`
+
+	var mismatches []models.LedgerMismatch
+	err := r.db.SelectContext(ctx, &mismatches, query)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"result", len(mismatches),
+		"error", err,
+	)
+
+	return mismatches, err
+}
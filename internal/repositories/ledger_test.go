@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	repo := NewLedgerRepository(db)
+
+	depositID, withdrawID, closeID := uuid.New(), uuid.New(), uuid.New()
+	assert.NoError(t, writer.SaveDeposit(ctx, depositID, userID, money.MustParse("100"), models.USD))
+	assert.NoError(t, writer.SaveWithdraw(ctx, withdrawID, userID, money.MustParse("30"), models.USD))
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("10"), models.EUR))
+	_, _, err := writer.Close(ctx, closeID, userID, models.EUR, models.USD, 2)
+	assert.NoError(t, err)
+
+	t.Run("entries", func(t *testing.T) {
+		entries, err := repo.Entries(ctx, depositID)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, models.LedgerAccountWallet, entries[0].Account)
+			assert.Equal(t, &userID, entries[0].UserID)
+			assert.Equal(t, money.MustParse("100"), entries[0].Amount)
+			assert.Equal(t, models.LedgerAccountExternal, entries[1].Account)
+			assert.Nil(t, entries[1].UserID)
+			assert.Equal(t, money.MustParse("-100"), entries[1].Amount)
+		}
+
+		entries, err = repo.Entries(ctx, withdrawID)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, money.MustParse("-30"), entries[0].Amount)
+			assert.Equal(t, money.MustParse("30"), entries[1].Amount)
+		}
+
+		entries, err = repo.Entries(ctx, closeID)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 4)
+	})
+
+	t.Run("balances match the ledger", func(t *testing.T) {
+		mismatches, err := repo.Mismatches(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("unbalanced transaction is rejected", func(t *testing.T) {
+		txnID := uuid.New()
+		_, err := db.Exec(`
+			WITH posted AS (INSERT INTO ledger_transactions (transaction_id, operation) VALUES ($1, 'deposit'))
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			VALUES ($1, 'wallet', $2, 'USD', 5)`, txnID, userID)
+		assert.ErrorContains(t, err, "is not balanced")
+	})
+
+	t.Run("entries are append-only", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE ledger_entries SET amount = 0 WHERE transaction_id = $1`, depositID)
+		assert.ErrorContains(t, err, "append-only")
+		_, err = db.Exec(`DELETE FROM ledger_entries WHERE transaction_id = $1`, depositID)
+		assert.ErrorContains(t, err, "append-only")
+	})
+
+	t.Run("balance changed outside the ledger", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE wallets SET balance = balance + 1 WHERE user_id = $1 AND currency = 'USD'`, userID)
+		assert.NoError(t, err)
+
+		mismatches, err := repo.Mismatches(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.LedgerMismatch{
+			{UserID: userID, Currency: models.USD, Balance: money.MustParse("91"), LedgerBalance: money.MustParse("90")},
+		}, mismatches)
+	})
+}
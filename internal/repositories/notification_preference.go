@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// NotificationPreferenceRepository stores per-user notification settings
+type NotificationPreferenceRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationPreferenceRepository(db *sqlx.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// GetByUserID returns the user's settings. Returns sql.ErrNoRows if the user has not saved any.
+func (r *NotificationPreferenceRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferencesDB, error) {
+	const query = `
+		SELECT user_id, email_enabled, sms_enabled, phone, security_alerts, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+
+	var prefs models.NotificationPreferencesDB
+	err := r.db.GetContext(ctx, &prefs, query, userID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", prefs,
+		"error", err,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// Save creates or replaces the user's settings
+func (r *NotificationPreferenceRepository) Save(ctx context.Context, prefs models.NotificationPreferencesDB) error {
+	const query = `
+		INSERT INTO notification_preferences (user_id, email_enabled, sms_enabled, phone, security_alerts, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_enabled = EXCLUDED.email_enabled,
+			sms_enabled = EXCLUDED.sms_enabled,
+			phone = EXCLUDED.phone,
+			security_alerts = EXCLUDED.security_alerts,
+			updated_at = NOW()
+	`
+	args := []any{prefs.UserID, prefs.EmailEnabled, prefs.SMSEnabled, prefs.Phone, prefs.SecurityAlerts}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferenceRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	repo := NewNotificationPreferenceRepository(db)
+
+	t.Run("not saved", func(t *testing.T) {
+		_, err := repo.GetByUserID(ctx, userID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("save and update", func(t *testing.T) {
+		phone := "+79990000000"
+		prefs := models.NotificationPreferencesDB{UserID: userID, EmailEnabled: true, SMSEnabled: true, Phone: &phone, SecurityAlerts: true}
+		assert.NoError(t, repo.Save(ctx, prefs))
+
+		got, err := repo.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.True(t, got.SMSEnabled)
+		if assert.NotNil(t, got.Phone) {
+			assert.Equal(t, phone, *got.Phone)
+		}
+
+		prefs.SMSEnabled = false
+		prefs.Phone = nil
+		prefs.SecurityAlerts = false
+		assert.NoError(t, repo.Save(ctx, prefs))
+
+		got, err = repo.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.False(t, got.SMSEnabled)
+		assert.Nil(t, got.Phone)
+		assert.False(t, got.SecurityAlerts)
+	})
+}
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// PaymentRequestRepository stores payment requests and transfers the money of accepted ones
+type PaymentRequestRepository struct {
+	db       *sqlx.DB
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewPaymentRequestRepository(db *sqlx.DB, txGetter func(ctx context.Context) *sqlx.Tx) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db: db, txGetter: txGetter}
+}
+
+const paymentRequestColumns = `request_id, requester_id, payer_id, currency, amount, note, status, transaction_id, expires_at, created_at, updated_at`
+
+// paymentRequestReadColumns reports pending requests past their expiry as expired before
+// the expiry job gets to them
+const paymentRequestReadColumns = `request_id, requester_id, payer_id, currency, amount, note,
+	CASE WHEN status = 'pending' AND expires_at <= NOW() THEN 'expired' ELSE status END AS status,
+	transaction_id, expires_at, created_at, updated_at`
+
+// Create saves a pending payment request
+func (r *PaymentRequestRepository) Create(ctx context.Context, request models.PaymentRequestDB) (models.PaymentRequestDB, error) {
+	query := `
+		INSERT INTO payment_requests (request_id, requester_id, payer_id, currency, amount, note, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 'pending', $7, NOW(), NOW())
+		RETURNING ` + paymentRequestColumns
+	args := []any{request.RequestID, request.RequesterID, request.PayerID, request.Currency, request.Amount, request.Note, request.ExpiresAt}
+
+	var created models.PaymentRequestDB
+	err := r.db.GetContext(ctx, &created, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", created.RequestID,
+		"error", err,
+	)
+
+	return created, err
+}
+
+// Get returns a payment request the user made or was asked to pay, or sql.ErrNoRows if there is none
+func (r *PaymentRequestRepository) Get(ctx context.Context, userID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	query := `
+		SELECT ` + paymentRequestReadColumns + `
+		FROM payment_requests
+		WHERE request_id = $1 AND (requester_id = $2 OR payer_id = $2)
+	`
+
+	var request models.PaymentRequestDB
+	err := r.db.GetContext(ctx, &request, query, requestID, userID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{requestID, userID},
+		"result", request.Status,
+		"error", err,
+	)
+
+	return request, err
+}
+
+// ListByUserID returns the latest payment requests the user made or was asked to pay, newest first
+func (r *PaymentRequestRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]models.PaymentRequestDB, error) {
+	query := `
+		SELECT ` + paymentRequestReadColumns + `
+		FROM payment_requests
+		WHERE requester_id = $1 OR payer_id = $1
+		ORDER BY created_at DESC, request_id
+		LIMIT $2
+	`
+
+	requests := []models.PaymentRequestDB{}
+	err := r.db.SelectContext(ctx, &requests, query, userID, limit)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID, limit},
+		"result", len(requests),
+		"error", err,
+	)
+
+	return requests, err
+}
+
+// Accept pays a pending, unexpired payment request: the amount leaves the payer's wallet,
+// is credited to the requester's wallet, created if missing, and both legs are appended to
+// wallet_events and posted to the ledger under transactionID, all in a single statement.
+// As with withdrawals, the payer can spend the available balance plus the overdraft limit.
+// Returns sql.ErrNoRows if the payer has no such pending request or too little money.
+func (r *PaymentRequestRepository) Accept(ctx context.Context, transactionID, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	query := `
+		WITH request AS (
+			SELECT request_id, requester_id, payer_id, currency, amount
+			FROM payment_requests
+			WHERE request_id = $1 AND payer_id = $2 AND status = 'pending' AND expires_at > NOW()
+			FOR UPDATE
+		),
+		debited AS (
+			UPDATE wallets w SET balance = w.balance - r.amount, updated_at = NOW()
+			FROM request r
+			WHERE w.user_id = r.payer_id AND w.currency = r.currency
+			  AND w.balance - w.held - w.saved + w.overdraft_limit >= r.amount
+			RETURNING w.user_id, w.currency, w.balance, r.amount
+		),
+		accepted AS (
+			UPDATE payment_requests p SET status = 'accepted', transaction_id = $3, updated_at = NOW()
+			FROM debited d
+			WHERE p.request_id = $1
+			RETURNING p.*
+		),
+		credited AS (
+			INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
+			SELECT $4, requester_id, currency, amount, NOW(), NOW() FROM accepted
+			ON CONFLICT (user_id, currency)
+			DO UPDATE SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+			RETURNING user_id, currency, balance
+		),
+		events AS (
+			INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			SELECT user_id, currency, 'withdraw', amount, balance FROM debited
+			UNION ALL
+			SELECT c.user_id, c.currency, 'deposit', a.amount, c.balance FROM credited c, accepted a
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT $3, 'transfer' FROM accepted
+		),
+		entries AS (
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			SELECT $3, 'wallet', user_id, currency, -amount FROM debited
+			UNION ALL
+			SELECT $3, 'wallet', c.user_id, c.currency, a.amount FROM credited c, accepted a
+		)
+		SELECT ` + paymentRequestColumns + ` FROM accepted
+	`
+	args := []any{requestID, payerID, transactionID, uuid.New()}
+
+	var request models.PaymentRequestDB
+	err := sqlx.GetContext(ctx, r.executor(ctx), &request, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", request.Status,
+		"error", err,
+	)
+
+	return request, err
+}
+
+// Decline marks a pending, unexpired payment request of the payer as declined.
+// Returns sql.ErrNoRows if the payer has no such pending request.
+func (r *PaymentRequestRepository) Decline(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	query := `
+		UPDATE payment_requests SET status = 'declined', updated_at = NOW()
+		WHERE request_id = $1 AND payer_id = $2 AND status = 'pending' AND expires_at > NOW()
+		RETURNING ` + paymentRequestColumns
+
+	var request models.PaymentRequestDB
+	err := r.db.GetContext(ctx, &request, query, requestID, payerID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{requestID, payerID},
+		"result", request.Status,
+		"error", err,
+	)
+
+	return request, err
+}
+
+// Expire marks the pending payment requests past their expiry as expired and returns them
+func (r *PaymentRequestRepository) Expire(ctx context.Context) ([]models.PaymentRequestDB, error) {
+	query := `
+		UPDATE payment_requests SET status = 'expired', updated_at = NOW()
+		WHERE status = 'pending' AND expires_at <= NOW()
+		RETURNING ` + paymentRequestColumns
+
+	expired := []models.PaymentRequestDB{}
+	err := r.db.SelectContext(ctx, &expired, query)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", nil,
+		"result", len(expired),
+		"error", err,
+	)
+
+	return expired, err
+}
+
+// executor returns the transaction of the request if there is one, otherwise the database
+func (r *PaymentRequestRepository) executor(ctx context.Context) sqlx.ExtContext {
+	if r.txGetter != nil {
+		if tx := r.txGetter(ctx); tx != nil {
+			return tx
+		}
+	}
+	return r.db
+}
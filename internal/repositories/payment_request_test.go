@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentRequestRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	requesterID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+	payerID := testkit.CreateUser(t, db, testkit.WithUsername("bob")).UserID
+	testkit.CreateWallet(t, db, payerID, models.USD, money.MustParse("100"))
+
+	repo := NewPaymentRequestRepository(db, nil)
+
+	newRequest := func(amount string, expiresAt time.Time) models.PaymentRequestDB {
+		note := "dinner"
+		request, err := repo.Create(ctx, models.PaymentRequestDB{
+			RequestID:   uuid.New(),
+			RequesterID: requesterID,
+			PayerID:     payerID,
+			Currency:    models.USD,
+			Amount:      money.MustParse(amount),
+			Note:        &note,
+			ExpiresAt:   expiresAt,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusPending, request.Status)
+		return request
+	}
+	future := time.Now().Add(time.Hour)
+
+	t.Run("accept transfers the amount", func(t *testing.T) {
+		request := newRequest("40", future)
+
+		txnID := uuid.New()
+		accepted, err := repo.Accept(ctx, txnID, payerID, request.RequestID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusAccepted, accepted.Status)
+		if assert.NotNil(t, accepted.TransactionID) {
+			assert.Equal(t, txnID, *accepted.TransactionID)
+		}
+		assert.Equal(t, money.MustParse("60"), getBalance(t, db, payerID, models.USD))
+		assert.Equal(t, money.MustParse("40"), getBalance(t, db, requesterID, models.USD))
+
+		var events int
+		assert.NoError(t, db.Get(&events, `SELECT COUNT(*) FROM wallet_events WHERE user_id IN ($1, $2) AND amount = 40`, payerID, requesterID))
+		assert.Equal(t, 2, events)
+
+		entries, err := NewLedgerRepository(db).Entries(ctx, txnID)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, money.MustParse("-40"), entries[0].Amount)
+			assert.Equal(t, money.MustParse("40"), entries[1].Amount)
+		}
+
+		_, err = repo.Accept(ctx, uuid.New(), payerID, request.RequestID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.Decline(ctx, payerID, request.RequestID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("insufficient funds leave the request pending", func(t *testing.T) {
+		request := newRequest("60.01", future)
+
+		_, err := repo.Accept(ctx, uuid.New(), payerID, request.RequestID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Equal(t, money.MustParse("60"), getBalance(t, db, payerID, models.USD))
+
+		got, err := repo.Get(ctx, requesterID, request.RequestID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusPending, got.Status)
+
+		declined, err := repo.Decline(ctx, payerID, request.RequestID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusDeclined, declined.Status)
+	})
+
+	t.Run("only the payer can accept", func(t *testing.T) {
+		request := newRequest("10", future)
+
+		_, err := repo.Accept(ctx, uuid.New(), requesterID, request.RequestID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.Get(ctx, uuid.New(), request.RequestID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = repo.Decline(ctx, payerID, request.RequestID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired requests", func(t *testing.T) {
+		request := newRequest("10", time.Now().Add(-time.Minute))
+
+		got, err := repo.Get(ctx, payerID, request.RequestID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusExpired, got.Status)
+
+		_, err = repo.Accept(ctx, uuid.New(), payerID, request.RequestID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		expired, err := repo.Expire(ctx)
+		assert.NoError(t, err)
+		if assert.Len(t, expired, 1) {
+			assert.Equal(t, request.RequestID, expired[0].RequestID)
+			assert.Equal(t, models.PaymentRequestStatusExpired, expired[0].Status)
+		}
+
+		expired, err = repo.Expire(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, expired)
+	})
+
+	t.Run("list returns both directions", func(t *testing.T) {
+		requests, err := repo.ListByUserID(ctx, requesterID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 4)
+
+		requests, err = repo.ListByUserID(ctx, payerID, 2)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 2)
+	})
+}
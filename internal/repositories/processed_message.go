@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// ProcessedMessageRepository records the Kafka messages consumers have processed, so
+// redelivered messages can be dropped by their key
+type ProcessedMessageRepository struct {
+	db       *sqlx.DB
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewProcessedMessageRepository(db *sqlx.DB, txGetter func(ctx context.Context) *sqlx.Tx) *ProcessedMessageRepository {
+	return &ProcessedMessageRepository{db: db, txGetter: txGetter}
+}
+
+// MarkProcessed records the message with key as processed by consumer. It returns false if
+// the message was already recorded. Run in the transaction of the message's effects, the
+// record is rolled back with them.
+func (r *ProcessedMessageRepository) MarkProcessed(ctx context.Context, consumer, key string) (bool, error) {
+	const query = `
+		INSERT INTO processed_messages (consumer, message_key, processed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (consumer, message_key) DO NOTHING
+	`
+
+	args := []any{consumer, key}
+	res, err := r.executor(ctx).ExecContext(ctx, query, args...)
+	var inserted int64
+	if err == nil {
+		inserted, err = res.RowsAffected()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", inserted,
+		"error", err,
+	)
+
+	return inserted == 1, err
+}
+
+func (r *ProcessedMessageRepository) executor(ctx context.Context) sqlx.ExtContext {
+	if r.txGetter != nil {
+		if tx := r.txGetter(ctx); tx != nil {
+			return tx
+		}
+	}
+	return r.db
+}
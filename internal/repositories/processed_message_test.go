@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessedMessageRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	repo := NewProcessedMessageRepository(db, nil)
+
+	marked, err := repo.MarkProcessed(ctx, "payment-confirmations", "pay-1")
+	assert.NoError(t, err)
+	assert.True(t, marked)
+
+	// Redelivery of the same message
+	marked, err = repo.MarkProcessed(ctx, "payment-confirmations", "pay-1")
+	assert.NoError(t, err)
+	assert.False(t, marked)
+
+	// Keys of different consumers do not collide
+	marked, err = repo.MarkProcessed(ctx, "other", "pay-1")
+	assert.NoError(t, err)
+	assert.True(t, marked)
+}
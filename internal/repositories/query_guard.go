@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// queryTimeoutKey is the context key of the query timeout set by WithQueryTimeout
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout returns a copy of ctx whose queries are bounded by timeout instead of the
+// timeout of GuardConnector, e.g. for a job scanning a whole table. 0 leaves them unbounded.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, timeout)
+}
+
+// GuardConnector returns a database/sql connector opening connections with inner that bounds
+// every query by timeout through its context and logs the queries taking at least
+// slowThreshold, with their duration and redacted arguments. A query's time runs until its
+// rows are closed. 0 disables either; prepared statements are not guarded.
+func GuardConnector(inner driver.Connector, timeout, slowThreshold time.Duration) driver.Connector {
+	return &guardConnector{inner: inner, guard: queryGuard{timeout: timeout, slowThreshold: slowThreshold}}
+}
+
+// queryGuard holds the limits applied to the queries of a connector
+type queryGuard struct {
+	timeout       time.Duration
+	slowThreshold time.Duration
+}
+
+// bound returns ctx bounded by the query timeout, which WithQueryTimeout overrides.
+func (g queryGuard) bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := g.timeout
+	if t, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		timeout = t
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// observe logs query if it ran for at least the slow threshold or exceeded the timeout.
+func (g queryGuard) observe(ctx context.Context, query string, args []driver.NamedValue, start time.Time, err error) {
+	duration := time.Since(start)
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	if !timedOut && (g.slowThreshold <= 0 || duration < g.slowThreshold) {
+		return
+	}
+
+	msg := "slow query"
+	if timedOut {
+		msg = "query timed out"
+	}
+	logger.FromContext(ctx).Warnw(msg,
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", redactArgs(args),
+		"duration", duration,
+		"error", err,
+	)
+}
+
+// redactArgs returns the arguments of a query fit for logs: numbers, booleans, times and
+// NULLs are kept, text and bytes, which may hold personal data or secrets, are replaced.
+func redactArgs(args []driver.NamedValue) []any {
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.Value.(type) {
+		case nil, int64, float64, bool, time.Time:
+			redacted[i] = v
+		default:
+			redacted[i] = "[redacted]"
+		}
+	}
+	return redacted
+}
+
+type guardConnector struct {
+	inner driver.Connector
+	guard queryGuard
+}
+
+func (c *guardConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.inner.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &guardConn{Conn: inner, guard: c.guard}, nil
+}
+
+func (c *guardConnector) Driver() driver.Driver {
+	return c.inner.Driver()
+}
+
+// guardConn forwards to the driver connection, bounding and observing its queries.
+type guardConn struct {
+	driver.Conn
+	guard queryGuard
+}
+
+var (
+	_ driver.QueryerContext     = (*guardConn)(nil)
+	_ driver.ExecerContext      = (*guardConn)(nil)
+	_ driver.ConnPrepareContext = (*guardConn)(nil)
+	_ driver.ConnBeginTx        = (*guardConn)(nil)
+	_ driver.Pinger             = (*guardConn)(nil)
+	_ driver.NamedValueChecker  = (*guardConn)(nil)
+	_ driver.SessionResetter    = (*guardConn)(nil)
+)
+
+func (c *guardConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, cancel := c.guard.bound(ctx)
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		c.guard.observe(ctx, query, args, start, err)
+		cancel()
+		return nil, err
+	}
+	// The rows are read under ctx, so it is released when they are closed
+	return &guardRows{Rows: rows, done: func(err error) {
+		c.guard.observe(ctx, query, args, start, err)
+		cancel()
+	}}, nil
+}
+
+func (c *guardConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, cancel := c.guard.bound(ctx)
+	defer cancel()
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.guard.observe(ctx, query, args, start, err)
+	return result, err
+}
+
+func (c *guardConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *guardConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *guardConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *guardConn) CheckNamedValue(v *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+func (c *guardConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// guardRows forwards to the driver rows and calls done once when they are closed.
+type guardRows struct {
+	driver.Rows
+	done  func(err error)
+	close sync.Once
+}
+
+func (r *guardRows) Close() error {
+	err := r.Rows.Close()
+	r.close.Do(func() { r.done(err) })
+	return err
+}
+
+func (r *guardRows) ColumnTypeDatabaseTypeName(index int) string {
+	if rows, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return rows.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *guardRows) ColumnTypeScanType(index int) reflect.Type {
+	if rows, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return rows.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// mockConnector opens connections to a sqlmock database
+type mockConnector struct {
+	db  *sql.DB
+	dsn string
+}
+
+func (c mockConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.db.Driver().Open(c.dsn)
+}
+
+func (c mockConnector) Driver() driver.Driver {
+	return c.db.Driver()
+}
+
+func TestGuardConnector(t *testing.T) {
+	originalLog := logger.Log
+	defer func() { logger.Log = originalLog }()
+	core, logs := observer.New(zap.WarnLevel)
+	logger.Log = zap.New(core).Sugar()
+
+	mockDB, mock, err := sqlmock.NewWithDSN("query-guard-test")
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	db := sql.OpenDB(GuardConnector(mockConnector{db: mockDB, dsn: "query-guard-test"}, 100*time.Millisecond, 20*time.Millisecond))
+	defer db.Close()
+	ctx := context.Background()
+
+	t.Run("fast queries are not logged", func(t *testing.T) {
+		mock.ExpectExec("UPDATE wallets").WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err := db.ExecContext(ctx, "UPDATE wallets SET balance = 0")
+		assert.NoError(t, err)
+
+		mock.ExpectQuery("SELECT balance").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(1.5))
+		var balance float64
+		assert.NoError(t, db.QueryRowContext(ctx, "SELECT balance FROM wallets").Scan(&balance))
+		assert.Equal(t, 1.5, balance)
+
+		assert.Zero(t, logs.TakeAll())
+	})
+
+	t.Run("slow query is logged with redacted arguments", func(t *testing.T) {
+		mock.ExpectQuery("SELECT balance").WithArgs("alice@example.com", int64(42)).
+			WillDelayFor(30 * time.Millisecond).
+			WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(1.5))
+		var balance float64
+		err := db.QueryRowContext(ctx, "SELECT balance\n\t\tFROM wallets WHERE email = $1 AND id = $2", "alice@example.com", 42).Scan(&balance)
+		assert.NoError(t, err)
+
+		entries := logs.TakeAll()
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "slow query", entries[0].Message)
+			fields := entries[0].ContextMap()
+			assert.Equal(t, "SELECT balance FROM wallets WHERE email = $1 AND id = $2", fields["query"])
+			assert.Equal(t, []any{"[redacted]", int64(42)}, fields["args"])
+			assert.GreaterOrEqual(t, fields["duration"], 30*time.Millisecond)
+		}
+	})
+
+	t.Run("query exceeding the timeout is canceled", func(t *testing.T) {
+		mock.ExpectExec("UPDATE wallets").WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 1))
+		start := time.Now()
+		_, err := db.ExecContext(ctx, "UPDATE wallets SET balance = 0")
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+
+		entries := logs.TakeAll()
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "query timed out", entries[0].Message)
+		}
+	})
+
+	t.Run("context overrides the timeout", func(t *testing.T) {
+		mock.ExpectExec("UPDATE wallets").WillDelayFor(150 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err := db.ExecContext(WithQueryTimeout(ctx, 0), "UPDATE wallets SET balance = 0")
+		assert.NoError(t, err)
+
+		entries := logs.TakeAll()
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "slow query", entries[0].Message)
+		}
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// RateAlertRepository stores the exchange rate alerts of users
+type RateAlertRepository struct {
+	db *sqlx.DB
+}
+
+func NewRateAlertRepository(db *sqlx.DB) *RateAlertRepository {
+	return &RateAlertRepository{db: db}
+}
+
+const rateAlertColumns = `alert_id, user_id, from_currency, to_currency, direction, threshold, triggered_at, triggered_rate, created_at, updated_at`
+
+// Create saves an armed alert
+func (r *RateAlertRepository) Create(ctx context.Context, alert models.RateAlertDB) (models.RateAlertDB, error) {
+	query := `
+		INSERT INTO rate_alerts (alert_id, user_id, from_currency, to_currency, direction, threshold, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING ` + rateAlertColumns
+	args := []any{alert.AlertID, alert.UserID, alert.FromCurrency, alert.ToCurrency, alert.Direction, alert.Threshold}
+
+	var created models.RateAlertDB
+	err := r.db.GetContext(ctx, &created, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", created.AlertID,
+		"error", err,
+	)
+
+	return created, err
+}
+
+// ListByUserID returns the user's alerts, oldest first
+func (r *RateAlertRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.RateAlertDB, error) {
+	query := `
+		SELECT ` + rateAlertColumns + `
+		FROM rate_alerts
+		WHERE user_id = $1
+		ORDER BY created_at, alert_id
+	`
+	args := []any{userID}
+
+	alerts := []models.RateAlertDB{}
+	err := r.db.SelectContext(ctx, &alerts, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(alerts),
+		"error", err,
+	)
+
+	return alerts, err
+}
+
+// Update changes the direction and threshold of the user's alert and arms it again.
+// Returns sql.ErrNoRows if the user has no such alert.
+func (r *RateAlertRepository) Update(ctx context.Context, userID, alertID uuid.UUID, direction string, threshold float32) (models.RateAlertDB, error) {
+	query := `
+		UPDATE rate_alerts
+		SET direction = $3, threshold = $4, triggered_at = NULL, triggered_rate = NULL, updated_at = NOW()
+		WHERE alert_id = $1 AND user_id = $2
+		RETURNING ` + rateAlertColumns
+	args := []any{alertID, userID, direction, threshold}
+
+	var updated models.RateAlertDB
+	err := r.db.GetContext(ctx, &updated, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", updated.AlertID,
+		"error", err,
+	)
+
+	return updated, err
+}
+
+// Delete removes the user's alert. Returns sql.ErrNoRows if the user has no such alert.
+func (r *RateAlertRepository) Delete(ctx context.Context, userID, alertID uuid.UUID) error {
+	const query = `
+		DELETE FROM rate_alerts
+		WHERE alert_id = $1 AND user_id = $2
+		RETURNING alert_id
+	`
+	args := []any{alertID, userID}
+
+	var deleted uuid.UUID
+	err := r.db.GetContext(ctx, &deleted, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", deleted,
+		"error", err,
+	)
+
+	return err
+}
+
+// ListArmed returns the alerts that have not fired yet, ordered by currency pair
+func (r *RateAlertRepository) ListArmed(ctx context.Context) ([]models.RateAlertDB, error) {
+	query := `
+		SELECT ` + rateAlertColumns + `
+		FROM rate_alerts
+		WHERE triggered_at IS NULL
+		ORDER BY from_currency, to_currency, created_at
+	`
+
+	alerts := []models.RateAlertDB{}
+	err := r.db.SelectContext(ctx, &alerts, query)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{},
+		"result", len(alerts),
+		"error", err,
+	)
+
+	return alerts, err
+}
+
+// MarkTriggered records that the alert fired at rate. Returns false if the alert was
+// removed, changed or has already fired, so every firing is reported once.
+func (r *RateAlertRepository) MarkTriggered(ctx context.Context, alert models.RateAlertDB, rate float32, at time.Time) (bool, error) {
+	const query = `
+		UPDATE rate_alerts
+		SET triggered_at = $2, triggered_rate = $3
+		WHERE alert_id = $1 AND triggered_at IS NULL AND updated_at = $4
+	`
+	args := []any{alert.AlertID, at, rate, alert.UpdatedAt}
+
+	var affected int64
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		affected, err = res.RowsAffected()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", affected,
+		"error", err,
+	)
+
+	return affected == 1, err
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateAlertRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+	repo := NewRateAlertRepository(db)
+
+	userID := testkit.CreateUser(t, db).UserID
+	otherID := testkit.CreateUser(t, db, testkit.WithUsername("other")).UserID
+
+	alert, err := repo.Create(ctx, models.RateAlertDB{
+		AlertID:      uuid.New(),
+		UserID:       userID,
+		FromCurrency: models.USD,
+		ToCurrency:   models.EUR,
+		Direction:    models.RateAlertAbove,
+		Threshold:    0.95,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, alert.TriggeredAt)
+
+	alerts, err := repo.ListByUserID(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+
+	armed, err := repo.ListArmed(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, armed, 1) {
+		assert.Equal(t, alert.AlertID, armed[0].AlertID)
+	}
+
+	// Fires once
+	triggered, err := repo.MarkTriggered(ctx, armed[0], 0.96, time.Now().UTC())
+	assert.NoError(t, err)
+	assert.True(t, triggered)
+	triggered, err = repo.MarkTriggered(ctx, armed[0], 0.97, time.Now().UTC())
+	assert.NoError(t, err)
+	assert.False(t, triggered)
+
+	armed, err = repo.ListArmed(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, armed)
+
+	// Updating arms it again
+	updated, err := repo.Update(ctx, userID, alert.AlertID, models.RateAlertBelow, 0.9)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RateAlertBelow, updated.Direction)
+	assert.Nil(t, updated.TriggeredAt)
+	_, err = repo.Update(ctx, otherID, alert.AlertID, models.RateAlertBelow, 0.9)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.ErrorIs(t, repo.Delete(ctx, otherID, alert.AlertID), sql.ErrNoRows)
+	assert.NoError(t, repo.Delete(ctx, userID, alert.AlertID))
+	alerts, err = repo.ListByUserID(ctx, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+}
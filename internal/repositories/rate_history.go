@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// RateHistoryRepository stores the exchange rates fetched from the exchanger
+type RateHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewRateHistoryRepository(db *sqlx.DB) *RateHistoryRepository {
+	return &RateHistoryRepository{db: db}
+}
+
+// Save appends a rate of a currency pair fetched at fetchedAt
+func (r *RateHistoryRepository) Save(ctx context.Context, fromCurrency, toCurrency string, rate float32, fetchedAt time.Time) error {
+	query := `
+		INSERT INTO rates_history (from_currency, to_currency, rate, fetched_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	args := []any{fromCurrency, toCurrency, rate, fetchedAt}
+
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", "ok",
+		"error", err,
+	)
+
+	return err
+}
+
+// List returns the rates of a currency pair fetched in [from, to) aggregated per hour or
+// day, oldest first. Periods without rates are omitted.
+func (r *RateHistoryRepository) List(ctx context.Context, fromCurrency, toCurrency string, from, to time.Time, granularity string) ([]models.ExchangeRatePoint, error) {
+	query := `
+		SELECT date_trunc($5, fetched_at) AS period,
+			(array_agg(rate ORDER BY fetched_at, rate_id))[1] AS open,
+			(array_agg(rate ORDER BY fetched_at DESC, rate_id DESC))[1] AS close,
+			MIN(rate) AS low,
+			MAX(rate) AS high
+		FROM rates_history
+		WHERE from_currency = $1 AND to_currency = $2 AND fetched_at >= $3 AND fetched_at < $4
+		GROUP BY period
+		ORDER BY period
+	`
+	args := []any{fromCurrency, toCurrency, from, to, granularity}
+
+	var points []models.ExchangeRatePoint
+	err := r.db.SelectContext(ctx, &points, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(points),
+		"error", err,
+	)
+
+	return points, err
+}
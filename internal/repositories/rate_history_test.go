@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateHistoryRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+	repo := NewRateHistoryRepository(db)
+
+	day := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	for _, saved := range []struct {
+		rate float32
+		at   time.Time
+	}{
+		{0.9, day.Add(9 * time.Hour)},
+		{0.95, day.Add(10 * time.Hour)},
+		{0.85, day.Add(11 * time.Hour)},
+		{0.92, day.Add(12 * time.Hour)},
+		{0.8, day.Add(33 * time.Hour)},
+	} {
+		assert.NoError(t, repo.Save(ctx, models.USD, models.EUR, saved.rate, saved.at))
+	}
+	assert.NoError(t, repo.Save(ctx, models.EUR, models.USD, 1.1, day.Add(9*time.Hour)))
+
+	t.Run("daily", func(t *testing.T) {
+		points, err := repo.List(ctx, models.USD, models.EUR, day, day.AddDate(0, 0, 2), models.RateGranularityDay)
+		assert.NoError(t, err)
+		if assert.Len(t, points, 2) {
+			assert.True(t, day.Equal(points[0].Period))
+			assert.Equal(t, models.ExchangeRatePoint{Period: points[0].Period, Open: 0.9, Close: 0.92, Low: 0.85, High: 0.95}, points[0])
+			assert.Equal(t, float32(0.8), points[1].Close)
+		}
+	})
+
+	t.Run("hourly", func(t *testing.T) {
+		points, err := repo.List(ctx, models.USD, models.EUR, day.Add(10*time.Hour), day.Add(12*time.Hour), models.RateGranularityHour)
+		assert.NoError(t, err)
+		if assert.Len(t, points, 2) {
+			assert.True(t, day.Add(10*time.Hour).Equal(points[0].Period))
+			assert.Equal(t, float32(0.95), points[0].Open)
+			assert.Equal(t, float32(0.85), points[1].Open)
+		}
+	})
+}
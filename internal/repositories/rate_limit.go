@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// RateLimitRepository counts requests per rate limit key using Redis
+type RateLimitRepository struct {
+	client *redis.Client
+}
+
+// NewRateLimitRepository creates a new repository instance
+func NewRateLimitRepository(client *redis.Client) *RateLimitRepository {
+	return &RateLimitRepository{client: client}
+}
+
+// Increment counts a request under key and returns the number of requests in the current
+// fixed window. The window starts with the first request.
+func (r *RateLimitRepository) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	key = "ratelimit:" + key
+
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, window)
+		return nil
+	})
+
+	var count int64
+	if err == nil {
+		count = incr.Val()
+	}
+
+	logger.FromContext(ctx).Infow(
+		"key", key,
+		"window", window,
+		"result", count,
+		"error", err,
+	)
+
+	return count, err
+}
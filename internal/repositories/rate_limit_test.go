@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitRepository_Increment(t *testing.T) {
+	ctx := context.Background()
+
+	rdb := testkit.Redis(t)
+
+	repo := NewRateLimitRepository(rdb)
+
+	t.Run("counts requests per key", func(t *testing.T) {
+		for i := int64(1); i <= 3; i++ {
+			count, err := repo.Increment(ctx, "write:alice", time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, i, count)
+		}
+
+		count, err := repo.Increment(ctx, "read:alice", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("window expires", func(t *testing.T) {
+		_, err := repo.Increment(ctx, "public:10.0.0.1", time.Second)
+		assert.NoError(t, err)
+
+		ttl, err := rdb.TTL(ctx, "ratelimit:public:10.0.0.1").Result()
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, ttl, time.Second)
+
+		time.Sleep(1500 * time.Millisecond)
+
+		count, err := repo.Increment(ctx, "public:10.0.0.1", time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
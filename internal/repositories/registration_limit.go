@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// RegistrationLimitRepository counts registration attempts per email domain using Redis
+type RegistrationLimitRepository struct {
+	client *redis.Client
+}
+
+// NewRegistrationLimitRepository creates a new repository instance
+func NewRegistrationLimitRepository(client *redis.Client) *RegistrationLimitRepository {
+	return &RegistrationLimitRepository{client: client}
+}
+
+// Increment counts a registration attempt for the domain and returns the number of attempts
+// in the current fixed window. The window starts with the first attempt.
+func (r *RegistrationLimitRepository) Increment(ctx context.Context, domain string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("registrations:domain:%s", domain)
+
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, window)
+		return nil
+	})
+
+	var count int64
+	if err == nil {
+		count = incr.Val()
+	}
+
+	logger.FromContext(ctx).Infow(
+		"key", key,
+		"window", window,
+		"result", count,
+		"error", err,
+	)
+
+	return count, err
+}
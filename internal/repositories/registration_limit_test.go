@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrationLimitRepository_Increment(t *testing.T) {
+	ctx := context.Background()
+
+	rdb := testkit.Redis(t)
+
+	repo := NewRegistrationLimitRepository(rdb)
+
+	t.Run("counts attempts per domain", func(t *testing.T) {
+		for i := int64(1); i <= 3; i++ {
+			count, err := repo.Increment(ctx, "example.com", time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, i, count)
+		}
+
+		count, err := repo.Increment(ctx, "other.com", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("window is not extended by later attempts", func(t *testing.T) {
+		_, err := repo.Increment(ctx, "short.com", time.Second)
+		assert.NoError(t, err)
+		_, err = repo.Increment(ctx, "short.com", time.Hour)
+		assert.NoError(t, err)
+
+		ttl, err := rdb.TTL(ctx, "registrations:domain:short.com").Result()
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, ttl, time.Second)
+
+		time.Sleep(1500 * time.Millisecond)
+
+		count, err := repo.Increment(ctx, "short.com", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// ReplicaRetryInterval is how long reads stay on the primary after the replica became unreachable.
+const ReplicaRetryInterval = 10 * time.Second
+
+// Reader runs read-only queries. *sqlx.DB and ReadPool implement it.
+type Reader interface {
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// ReadPool sends read-only queries to a read replica, falling back to the primary while the
+// replica is unreachable. A row missing on the replica is looked up on the primary, since it
+// may not have been replicated yet.
+type ReadPool struct {
+	primary   *sqlx.DB
+	replica   *sqlx.DB
+	downUntil atomic.Int64 // Unix nanoseconds until which the replica is skipped
+}
+
+// NewReadPool creates a ReadPool. Without a replica every query runs on the primary.
+func NewReadPool(primary, replica *sqlx.DB) *ReadPool {
+	return &ReadPool{primary: primary, replica: replica}
+}
+
+func (p *ReadPool) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return p.read(ctx, dest, func(db *sqlx.DB) error {
+		return db.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (p *ReadPool) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return p.read(ctx, dest, func(db *sqlx.DB) error {
+		return db.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// read runs query on the replica if it is up, and again on the primary if it fails.
+func (p *ReadPool) read(ctx context.Context, dest any, query func(db *sqlx.DB) error) error {
+	if p.replica == nil || time.Now().UnixNano() < p.downUntil.Load() {
+		return query(p.primary)
+	}
+
+	err := query(p.replica)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	// Errors reported by the server, such as a conflict with recovery, leave the replica up
+	var pgErr *pgconn.PgError
+	if !errors.Is(err, sql.ErrNoRows) && !errors.As(err, &pgErr) {
+		logger.FromContext(ctx).Warnw("read replica unavailable, reading from the primary", "retry_in", ReplicaRetryInterval, "error", err)
+		p.downUntil.Store(time.Now().Add(ReplicaRetryInterval).UnixNano())
+	}
+
+	// Select appends to the slice, so the rows scanned from the replica are dropped
+	if v := reflect.ValueOf(dest); v.Kind() == reflect.Pointer && !v.IsNil() {
+		v.Elem().SetZero()
+	}
+	return query(p.primary)
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return sqlx.NewDb(db, "sqlmock"), mock
+}
+
+func TestReadPool(t *testing.T) {
+	const query = "SELECT currency FROM wallets"
+	rows := func(currencies ...string) *sqlmock.Rows {
+		r := sqlmock.NewRows([]string{"currency"})
+		for _, c := range currencies {
+			r.AddRow(c)
+		}
+		return r
+	}
+
+	t.Run("reads from the replica", func(t *testing.T) {
+		primary, _ := newMockDB(t)
+		replica, replicaMock := newMockDB(t)
+		replicaMock.ExpectQuery(query).WillReturnRows(rows("USD", "EUR"))
+
+		var got []string
+		err := NewReadPool(primary, replica).SelectContext(context.Background(), &got, query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"USD", "EUR"}, got)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+
+	t.Run("without a replica reads from the primary", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		primaryMock.ExpectQuery(query).WillReturnRows(rows("USD"))
+
+		var got []string
+		err := NewReadPool(primary, nil).SelectContext(context.Background(), &got, query)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"USD"}, got)
+	})
+
+	t.Run("replica down falls back to the primary", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, replicaMock := newMockDB(t)
+		replicaMock.ExpectQuery(query).WillReturnError(errors.New("connection refused"))
+		primaryMock.ExpectQuery(query).WillReturnRows(rows("USD"))
+		primaryMock.ExpectQuery(query).WillReturnRows(rows("EUR"))
+		pool := NewReadPool(primary, replica)
+
+		var got []string
+		assert.NoError(t, pool.SelectContext(context.Background(), &got, query))
+		assert.Equal(t, []string{"USD"}, got)
+
+		// The replica is skipped until ReplicaRetryInterval has passed
+		assert.NoError(t, pool.SelectContext(context.Background(), &got, query))
+		assert.Equal(t, []string{"EUR"}, got)
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		assert.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("row not replicated yet is read from the primary", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, replicaMock := newMockDB(t)
+		replicaMock.ExpectQuery(query).WillReturnRows(rows())
+		primaryMock.ExpectQuery(query).WillReturnRows(rows("USD"))
+		replicaMock.ExpectQuery(query).WillReturnRows(rows("EUR"))
+		pool := NewReadPool(primary, replica)
+
+		var got string
+		assert.NoError(t, pool.GetContext(context.Background(), &got, query))
+		assert.Equal(t, "USD", got)
+
+		// The replica stays up
+		assert.NoError(t, pool.GetContext(context.Background(), &got, query))
+		assert.Equal(t, "EUR", got)
+	})
+
+	t.Run("server error keeps the replica up", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, replicaMock := newMockDB(t)
+		replicaMock.ExpectQuery(query).WillReturnError(&pgconn.PgError{Code: "40001", Message: "canceling statement due to conflict with recovery"})
+		primaryMock.ExpectQuery(query).WillReturnRows(rows("USD"))
+		replicaMock.ExpectQuery(query).WillReturnRows(rows("EUR"))
+		pool := NewReadPool(primary, replica)
+
+		var got []string
+		assert.NoError(t, pool.SelectContext(context.Background(), &got, query))
+		assert.Equal(t, []string{"USD"}, got)
+		assert.NoError(t, pool.SelectContext(context.Background(), &got, query))
+		assert.Equal(t, []string{"EUR"}, got)
+	})
+
+	t.Run("missing everywhere", func(t *testing.T) {
+		primary, primaryMock := newMockDB(t)
+		replica, replicaMock := newMockDB(t)
+		replicaMock.ExpectQuery(query).WillReturnRows(rows())
+		primaryMock.ExpectQuery(query).WillReturnRows(rows())
+
+		var got string
+		err := NewReadPool(primary, replica).GetContext(context.Background(), &got, query)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/schema"
+)
+
+// SchemaRepository reads the live database schema
+type SchemaRepository struct {
+	db *sqlx.DB
+}
+
+func NewSchemaRepository(db *sqlx.DB) *SchemaRepository {
+	return &SchemaRepository{db: db}
+}
+
+// Load returns the tables, columns and indexes of the current schema
+func (r *SchemaRepository) Load(ctx context.Context) (schema.Schema, error) {
+	const columnsQuery = `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+		ORDER BY table_name, ordinal_position
+	`
+	const indexesQuery = `
+		SELECT indexname, tablename
+		FROM pg_indexes
+		WHERE schemaname = current_schema()
+	`
+
+	var columns []struct {
+		Table  string `db:"table_name"`
+		Column string `db:"column_name"`
+	}
+	err := r.db.SelectContext(ctx, &columns, columnsQuery)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(columnsQuery), " "),
+		"args", []any{},
+		"result", len(columns),
+		"error", err,
+	)
+	if err != nil {
+		return schema.Schema{}, err
+	}
+
+	var indexes []struct {
+		Index string `db:"indexname"`
+		Table string `db:"tablename"`
+	}
+	err = r.db.SelectContext(ctx, &indexes, indexesQuery)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(indexesQuery), " "),
+		"args", []any{},
+		"result", len(indexes),
+		"error", err,
+	)
+	if err != nil {
+		return schema.Schema{}, err
+	}
+
+	s := schema.Schema{Columns: map[string][]string{}, Indexes: map[string]string{}}
+	for _, c := range columns {
+		s.Columns[c.Table] = append(s.Columns[c.Table], c.Column)
+	}
+	for _, i := range indexes {
+		s.Indexes[i.Index] = i.Table
+	}
+	return s, nil
+}
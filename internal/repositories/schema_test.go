@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRepository_Load(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE INDEX idx_drift_wallets_currency ON wallets (currency)`)
+	assert.NoError(t, err)
+
+	repo := NewSchemaRepository(db)
+	s, err := repo.Load(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, s.Columns["wallets"], "held")
+	assert.Contains(t, s.Columns["users"], "dormant_at")
+	assert.Equal(t, "wallets", s.Indexes["idx_drift_wallets_currency"])
+}
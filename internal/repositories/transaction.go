@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// TransactionRepository stores the user's transaction history
+type TransactionRepository struct {
+	db       *sqlx.DB
+	reader   Reader // Serves the history listing, e.g. from a read replica
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewTransactionRepository(db *sqlx.DB, reader Reader, txGetter func(ctx context.Context) *sqlx.Tx) *TransactionRepository {
+	return &TransactionRepository{db: db, reader: reader, txGetter: txGetter}
+}
+
+// Save appends a transaction to the history. Balances not set are taken from the wallets,
+// so that, saved in the transaction of the balance change, they are the balances after it.
+func (r *TransactionRepository) Save(ctx context.Context, txn models.TransactionDB) error {
+	const query = `
+		INSERT INTO transactions (transaction_id, user_id, operation, currency, amount, to_currency, to_amount, reversal_of, reference,
+			rate, fee, balance, to_balance, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+			COALESCE($12, (SELECT balance FROM wallets WHERE user_id = $2 AND currency = $4)),
+			COALESCE($13, (SELECT balance FROM wallets WHERE user_id = $2 AND currency = $6)),
+			NOW())
+	`
+
+	args := []any{txn.TransactionID, txn.UserID, txn.Operation, txn.Currency, txn.Amount, txn.ToCurrency, txn.ToAmount, txn.ReversalOf, txn.Reference,
+		txn.Rate, txn.Fee, txn.Balance, txn.ToBalance}
+	_, err := r.executor(ctx).ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// List returns the user's transactions matching the filter, newest first. The date bounds
+// are compared with created_at directly, so only the monthly partitions they cover are scanned.
+func (r *TransactionRepository) List(ctx context.Context, filter models.TransactionFilter) ([]models.TransactionDB, error) {
+	const query = `
+		SELECT id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, reversal_of, reference,
+			rate, fee, balance, to_balance, created_at
+		FROM transactions
+		WHERE user_id = $1
+		  AND ($2::BIGINT = 0 OR id < $2)
+		  AND created_at >= COALESCE($3::TIMESTAMP, '-infinity')
+		  AND created_at < COALESCE($4::TIMESTAMP, 'infinity')
+		  AND ($5::TEXT = '' OR currency = $5 OR to_currency = $5)
+		  AND ($6::TEXT = '' OR operation = $6)
+		  AND ($7::TEXT = '' OR currency = $7)
+		  AND ($8::TEXT = '' OR to_currency = $8)
+		ORDER BY id DESC
+		LIMIT $9
+	`
+
+	// Zero bounds are passed as NULL to disable the date filters
+	var from, to *time.Time
+	if !filter.From.IsZero() {
+		from = &filter.From
+	}
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+
+	args := []any{filter.UserID, filter.BeforeID, from, to, filter.Currency, filter.Operation, filter.FromCurrency, filter.ToCurrency, filter.Limit}
+
+	var txns []models.TransactionDB
+	err := r.reader.SelectContext(ctx, &txns, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(txns),
+		"error", err,
+	)
+
+	return txns, err
+}
+
+// Get returns the transaction with transactionID, or sql.ErrNoRows if there is none
+func (r *TransactionRepository) Get(ctx context.Context, transactionID uuid.UUID) (models.TransactionDB, error) {
+	const query = `
+		SELECT id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, reversal_of, reference,
+			rate, fee, balance, to_balance, created_at
+		FROM transactions
+		WHERE transaction_id = $1
+	`
+
+	var txn models.TransactionDB
+	err := sqlx.GetContext(ctx, r.executor(ctx), &txn, query, transactionID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{transactionID},
+		"result", txn.ID,
+		"error", err,
+	)
+
+	return txn, err
+}
+
+// SaveReversal appends a reversal to the history. Returns sql.ErrNoRows if the
+// transaction in ReversalOf has already been reversed, which transaction_reversals
+// enforces across the monthly partitions.
+func (r *TransactionRepository) SaveReversal(ctx context.Context, txn models.TransactionDB) error {
+	const query = `
+		WITH claimed AS (
+			INSERT INTO transaction_reversals (reversal_of, transaction_id)
+			VALUES ($8, $1)
+			ON CONFLICT (reversal_of) DO NOTHING
+			RETURNING reversal_of
+		)
+		INSERT INTO transactions (transaction_id, user_id, operation, currency, amount, to_currency, to_amount, reversal_of, reference,
+			rate, fee, balance, to_balance, created_at)
+		SELECT $1::UUID, $2::UUID, $3::TEXT, $4::TEXT, $5::NUMERIC, $6::TEXT, $7::NUMERIC, reversal_of, $9::TEXT,
+			$10::REAL, $11::NUMERIC, $12::NUMERIC, $13::NUMERIC, NOW()
+		FROM claimed
+		RETURNING id
+	`
+
+	args := []any{txn.TransactionID, txn.UserID, txn.Operation, txn.Currency, txn.Amount, txn.ToCurrency, txn.ToAmount, txn.ReversalOf, txn.Reference,
+		txn.Rate, txn.Fee, txn.Balance, txn.ToBalance}
+	var id int64
+	err := sqlx.GetContext(ctx, r.executor(ctx), &id, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", id,
+		"error", err,
+	)
+
+	return err
+}
+
+// executor returns the transaction of the request if there is one, otherwise the database
+func (r *TransactionRepository) executor(ctx context.Context) sqlx.ExtContext {
+	if r.txGetter != nil {
+		if tx := r.txGetter(ctx); tx != nil {
+			return tx
+		}
+	}
+	return r.db
+}
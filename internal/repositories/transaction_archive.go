@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// TransactionArchiveRepository keeps archived months of the transaction history as gzipped
+// files in a directory, e.g. a mounted bucket or volume backed by cold storage
+type TransactionArchiveRepository struct {
+	dir string
+}
+
+func NewTransactionArchiveRepository(dir string) *TransactionArchiveRepository {
+	return &TransactionArchiveRepository{dir: dir}
+}
+
+// Save stores what write writes as <name>.jsonl.gz. The file is written under a temporary
+// name, synced and renamed, so it appears complete or not at all; saving a name again
+// replaces the earlier file.
+func (r *TransactionArchiveRepository) Save(ctx context.Context, name string, write func(w io.Writer) error) error {
+	path := filepath.Join(r.dir, name+".jsonl.gz")
+	err := r.save(path, write)
+
+	logger.FromContext(ctx).Infow("archive saved", "path", path, "error", err)
+
+	return err
+}
+
+func (r *TransactionArchiveRepository) save(path string, write func(w io.Writer) error) error {
+	if err := os.MkdirAll(r.dir, 0o750); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(r.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	if err := write(gz); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionArchiveRepository_Save(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "archive")
+	repo := NewTransactionArchiveRepository(dir)
+
+	t.Run("writes a gzipped file", func(t *testing.T) {
+		err := repo.Save(ctx, "transactions_2024_01", func(w io.Writer) error {
+			_, err := io.WriteString(w, "{\"id\":1}\n")
+			return err
+		})
+		assert.NoError(t, err)
+
+		f, err := os.Open(filepath.Join(dir, "transactions_2024_01.jsonl.gz"))
+		assert.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		assert.NoError(t, err)
+		data, err := io.ReadAll(gz)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"id\":1}\n", string(data))
+	})
+
+	t.Run("leaves nothing behind if write fails", func(t *testing.T) {
+		err := repo.Save(ctx, "transactions_2024_02", func(w io.Writer) error {
+			return errors.New("scan failed")
+		})
+		assert.EqualError(t, err, "scan failed")
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
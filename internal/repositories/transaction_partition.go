@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// transactionPartitionLayout names the partition of the transaction history of a month
+const transactionPartitionLayout = "transactions_2006_01"
+
+// TransactionPartitionRepository manages the monthly partitions of the transactions table
+type TransactionPartitionRepository struct {
+	db *sqlx.DB
+}
+
+func NewTransactionPartitionRepository(db *sqlx.DB) *TransactionPartitionRepository {
+	return &TransactionPartitionRepository{db: db}
+}
+
+// Months returns the months that have a partition, oldest first. The default partition,
+// which catches rows outside of all months, is not listed.
+func (r *TransactionPartitionRepository) Months(ctx context.Context) ([]time.Time, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'transactions'::regclass
+		ORDER BY c.relname
+	`
+
+	var names []string
+	err := r.db.SelectContext(ctx, &names, query)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{},
+		"result", names,
+		"error", err,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	months := []time.Time{}
+	for _, name := range names {
+		if month, err := time.Parse(transactionPartitionLayout, name); err == nil {
+			months = append(months, month)
+		}
+	}
+	return months, nil
+}
+
+// Create creates the partition of month if it does not exist yet. It fails if the default
+// partition already holds rows of the month.
+func (r *TransactionPartitionRepository) Create(ctx context.Context, month time.Time) error {
+	from := startOfMonth(month)
+	// DDL takes no parameters; the name and bounds are formatted from the month only
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF transactions FOR VALUES FROM ('%s') TO ('%s')`,
+		from.Format(transactionPartitionLayout), from.Format(time.DateOnly), from.AddDate(0, 1, 0).Format(time.DateOnly))
+
+	_, err := r.db.ExecContext(ctx, query)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", query,
+		"args", []any{},
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// Scan calls fn for every transaction in the partition of month, in ID order, and stops at
+// the first error fn returns. A month can take long to scan, so the query timeout does not apply.
+func (r *TransactionPartitionRepository) Scan(ctx context.Context, month time.Time, fn func(txn models.TransactionDB) error) error {
+	query := fmt.Sprintf(`
+		SELECT id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, reversal_of, reference,
+			rate, fee, balance, to_balance, created_at
+		FROM %s
+		ORDER BY id
+	`, startOfMonth(month).Format(transactionPartitionLayout))
+
+	scanned := 0
+	err := func() error {
+		rows, err := r.db.QueryxContext(WithQueryTimeout(ctx, 0), query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var txn models.TransactionDB
+			if err := rows.StructScan(&txn); err != nil {
+				return err
+			}
+			if err := fn(txn); err != nil {
+				return err
+			}
+			scanned++
+		}
+		return rows.Err()
+	}()
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{},
+		"result", scanned,
+		"error", err,
+	)
+
+	return err
+}
+
+// Drop detaches the partition of month from the transactions table and drops it with its
+// rows, in one implicit transaction.
+func (r *TransactionPartitionRepository) Drop(ctx context.Context, month time.Time) error {
+	name := startOfMonth(month).Format(transactionPartitionLayout)
+	query := fmt.Sprintf(`ALTER TABLE transactions DETACH PARTITION %s; DROP TABLE %s`, name, name)
+
+	_, err := r.db.ExecContext(ctx, query)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", query,
+		"args", []any{},
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// startOfMonth returns the first instant of the UTC month of t
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	repo := NewTransactionRepository(db, db, nil)
+
+	eur := models.EUR
+	toAmount := money.MustParse("45")
+	reference := "INV-42"
+	assert.NoError(t, repo.Save(ctx, models.TransactionDB{TransactionID: uuid.New(), UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("100"), Reference: &reference}))
+	assert.NoError(t, repo.Save(ctx, models.TransactionDB{TransactionID: uuid.New(), UserID: userID, Operation: models.OperationWithdraw, Currency: models.RUB, Amount: money.MustParse("20")}))
+	rate, fee, balance, toBalance := float32(0.9), money.MustParse("0.5"), money.MustParse("50"), money.MustParse("45")
+	assert.NoError(t, repo.Save(ctx, models.TransactionDB{TransactionID: uuid.New(), UserID: userID, Operation: models.OperationExchange, Currency: models.USD, Amount: money.MustParse("50"), ToCurrency: &eur, ToAmount: &toAmount,
+		Rate: &rate, Fee: &fee, Balance: &balance, ToBalance: &toBalance}))
+
+	t.Run("newest first", func(t *testing.T) {
+		txns, err := repo.List(ctx, models.TransactionFilter{UserID: userID, Limit: 10})
+		assert.NoError(t, err)
+		if assert.Len(t, txns, 3) {
+			assert.Equal(t, models.OperationExchange, txns[0].Operation)
+			assert.Equal(t, models.EUR, *txns[0].ToCurrency)
+			assert.Equal(t, money.MustParse("45"), *txns[0].ToAmount)
+			assert.Nil(t, txns[0].Reference)
+			assert.Equal(t, &rate, txns[0].Rate)
+			assert.Equal(t, &fee, txns[0].Fee)
+			assert.Equal(t, &balance, txns[0].Balance)
+			assert.Equal(t, &toBalance, txns[0].ToBalance)
+			assert.Equal(t, models.OperationDeposit, txns[2].Operation)
+			assert.Nil(t, txns[2].ToCurrency)
+			if assert.NotNil(t, txns[2].Reference) {
+				assert.Equal(t, reference, *txns[2].Reference)
+			}
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		page, err := repo.List(ctx, models.TransactionFilter{UserID: userID, Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, page, 2)
+
+		rest, err := repo.List(ctx, models.TransactionFilter{UserID: userID, BeforeID: page[1].ID, Limit: 2})
+		assert.NoError(t, err)
+		if assert.Len(t, rest, 1) {
+			assert.Equal(t, models.OperationDeposit, rest[0].Operation)
+		}
+	})
+
+	t.Run("currency matches both sides of an exchange", func(t *testing.T) {
+		txns, err := repo.List(ctx, models.TransactionFilter{UserID: userID, Currency: models.EUR, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, txns, 1)
+
+		txns, err = repo.List(ctx, models.TransactionFilter{UserID: userID, Currency: models.USD, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, txns, 2)
+	})
+
+	t.Run("exchange pair", func(t *testing.T) {
+		txns, err := repo.List(ctx, models.TransactionFilter{UserID: userID, FromCurrency: models.USD, ToCurrency: models.EUR, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, txns, 1)
+
+		txns, err = repo.List(ctx, models.TransactionFilter{UserID: userID, FromCurrency: models.EUR, ToCurrency: models.USD, Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, txns)
+	})
+
+	t.Run("operation", func(t *testing.T) {
+		txns, err := repo.List(ctx, models.TransactionFilter{UserID: userID, Operation: models.OperationWithdraw, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, txns, 1)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		txns, err := repo.List(ctx, models.TransactionFilter{UserID: userID, From: time.Now().Add(time.Hour), Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, txns)
+
+		txns, err = repo.List(ctx, models.TransactionFilter{UserID: userID, To: time.Now().Add(-time.Hour), Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, txns)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		txns, err := repo.List(ctx, models.TransactionFilter{UserID: uuid.New(), Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, txns)
+	})
+}
+
+func TestTransactionRepository_Reversal(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db).UserID
+	repo := NewTransactionRepository(db, db, nil)
+
+	original := models.TransactionDB{TransactionID: uuid.New(), UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("100")}
+	assert.NoError(t, repo.Save(ctx, original))
+
+	t.Run("get", func(t *testing.T) {
+		txn, err := repo.Get(ctx, original.TransactionID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.OperationDeposit, txn.Operation)
+		assert.Equal(t, money.MustParse("100"), txn.Amount)
+		assert.Nil(t, txn.ReversalOf)
+
+		_, err = repo.Get(ctx, uuid.New())
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("reversed once", func(t *testing.T) {
+		reversal := models.TransactionDB{TransactionID: uuid.New(), UserID: userID, Operation: models.OperationReversal, Currency: models.USD, Amount: money.MustParse("100"), ReversalOf: &original.TransactionID}
+		assert.NoError(t, repo.SaveReversal(ctx, reversal))
+
+		txn, err := repo.Get(ctx, reversal.TransactionID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, txn.ReversalOf) {
+			assert.Equal(t, original.TransactionID, *txn.ReversalOf)
+		}
+
+		again := reversal
+		again.TransactionID = uuid.New()
+		assert.ErrorIs(t, repo.SaveReversal(ctx, again), sql.ErrNoRows)
+	})
+}
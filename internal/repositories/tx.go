@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+)
+
+// SQLSTATE codes of transactions that failed only because of concurrent ones and can be
+// run again as a whole
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// txKey is the context key of the transaction started by TxRunner
+type txKey struct{}
+
+// TxRunner runs several repository calls in one database transaction. Repositories
+// take part in it when they are created with TxFromContext as their txGetter.
+type TxRunner struct {
+	db         *sqlx.DB
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// TxRunnerOpt defines a functional option for TxRunner.
+type TxRunnerOpt func(*TxRunner)
+
+// WithTxRetry runs a transaction again, up to attempts times in total, when Postgres aborts
+// it with a serialization failure or a deadlock (SQLSTATE 40001/40P01). Retries wait an
+// exponentially growing backoff, starting at backoff and capped at maxBackoff, with jitter.
+func WithTxRetry(attempts int, backoff, maxBackoff time.Duration) TxRunnerOpt {
+	return func(r *TxRunner) {
+		r.attempts = attempts
+		r.backoff = backoff
+		r.maxBackoff = maxBackoff
+	}
+}
+
+func NewTxRunner(db *sqlx.DB, opts ...TxRunnerOpt) *TxRunner {
+	r := &TxRunner{db: db, attempts: 1}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// InTx runs fn in a transaction that is committed if fn returns nil and rolled back
+// otherwise. Nested calls join the transaction of the outer one. With WithTxRetry, the
+// outermost call runs fn again in a new transaction after a serialization failure or a
+// deadlock, so fn must not have effects outside the transaction that cannot be repeated.
+func (r *TxRunner) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	backoff := r.backoff
+	for attempt := 1; ; attempt++ {
+		err := r.run(ctx, fn)
+		if err == nil || attempt >= r.attempts || !retryableTx(err) || ctx.Err() != nil {
+			return err
+		}
+
+		// Full jitter on the upper half spreads the retries of the conflicting transactions
+		wait := backoff/2 + rand.N(backoff/2+1)
+		logger.FromContext(ctx).Warnw("retrying transaction", "attempt", attempt, "backoff", wait, "error", err)
+		metrics.TxRetries.Inc()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		backoff = min(2*backoff, r.maxBackoff)
+	}
+}
+
+// run runs fn once in a new transaction.
+func (r *TxRunner) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			tx.Rollback()
+			panic(rec)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logger.FromContext(ctx).Errorw("failed to roll back transaction", "error", rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// TxFromContext returns the transaction started by TxRunner.InTx, or nil outside of one.
+func TxFromContext(ctx context.Context) *sqlx.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx
+}
+
+// retryableTx reports whether err aborted a transaction only because of a concurrent one.
+func retryableTx(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxRunner(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db).UserID
+	runner := NewTxRunner(db)
+	repo := NewTransactionRepository(db, db, TxFromContext)
+
+	save := func(ctx context.Context) (uuid.UUID, error) {
+		id := uuid.New()
+		return id, repo.Save(ctx, models.TransactionDB{TransactionID: id, UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("1")})
+	}
+
+	t.Run("commits", func(t *testing.T) {
+		var id uuid.UUID
+		err := runner.InTx(ctx, func(ctx context.Context) error {
+			assert.NotNil(t, TxFromContext(ctx))
+			var err error
+			id, err = save(ctx)
+			return err
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.Get(ctx, id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		failure := errors.New("failure")
+		var id uuid.UUID
+		err := runner.InTx(ctx, func(ctx context.Context) error {
+			id, _ = save(ctx)
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		_, err = repo.Get(ctx, id)
+		assert.Error(t, err)
+	})
+
+	t.Run("nested calls join the outer transaction", func(t *testing.T) {
+		err := runner.InTx(ctx, func(ctx context.Context) error {
+			outer := TxFromContext(ctx)
+			return runner.InTx(ctx, func(ctx context.Context) error {
+				assert.Same(t, outer, TxFromContext(ctx))
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		retrying := NewTxRunner(db, WithTxRetry(3, time.Millisecond, 2*time.Millisecond))
+		runs := 0
+		var id uuid.UUID
+		err := retrying.InTx(ctx, func(ctx context.Context) error {
+			runs++
+			var err error
+			id, err = save(ctx)
+			if err == nil && runs == 1 {
+				return &pgconn.PgError{Code: sqlStateSerializationFailure}
+			}
+			return err
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, runs)
+
+		_, err = repo.Get(ctx, id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		retrying := NewTxRunner(db, WithTxRetry(3, time.Millisecond, 2*time.Millisecond))
+		runs := 0
+		err := retrying.InTx(ctx, func(ctx context.Context) error {
+			runs++
+			return &pgconn.PgError{Code: sqlStateDeadlockDetected}
+		})
+		assert.True(t, retryableTx(err))
+		assert.Equal(t, 3, runs)
+	})
+}
+
+func TestRetryableTx(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, expected: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, expected: true},
+		{name: "wrapped", err: fmt.Errorf("save exchange: %w", &pgconn.PgError{Code: "40001"}), expected: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}},
+		{name: "other error", err: errors.New("connection refused")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, retryableTx(tc.err))
+		})
+	}
+}
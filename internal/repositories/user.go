@@ -2,24 +2,26 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
 )
 
 type UserReadRepository struct {
-	db *sqlx.DB
+	db Reader
 }
 
-func NewUserReadRepository(db *sqlx.DB) *UserReadRepository {
+func NewUserReadRepository(db Reader) *UserReadRepository {
 	return &UserReadRepository{db: db}
 }
 
 func (r *UserReadRepository) GetByUsernameOrEmail(ctx context.Context, username, email *string) (*models.UserDB, error) {
 	const query = `
-		SELECT user_id, username, email, password_hash, created_at, updated_at
+		SELECT user_id, username, email, password_hash, role, dormant_at, created_at, updated_at
 		FROM users
 		WHERE ($1::VARCHAR IS NULL OR username = $1)
 		  AND ($2::VARCHAR IS NULL OR email = $2)
@@ -30,7 +32,7 @@ func (r *UserReadRepository) GetByUsernameOrEmail(ctx context.Context, username,
 	err := r.db.GetContext(ctx, &user, query, username, email)
 
 	// Log with query in single line
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"query", strings.Join(strings.Fields(query), " "),
 		"args", []any{username, email},
 		"result", user,
@@ -44,6 +46,31 @@ func (r *UserReadRepository) GetByUsernameOrEmail(ctx context.Context, username,
 	return &user, nil
 }
 
+func (r *UserReadRepository) GetByID(ctx context.Context, userID uuid.UUID) (*models.UserDB, error) {
+	const query = `
+		SELECT user_id, username, email, password_hash, role, dormant_at, created_at, updated_at
+		FROM users
+		WHERE user_id = $1
+	`
+
+	var user models.UserDB
+	err := r.db.GetContext(ctx, &user, query, userID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", user,
+		"error", err,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
 type UserWriteRepository struct {
 	db *sqlx.DB
 }
@@ -70,7 +97,7 @@ func (r *UserWriteRepository) Save(ctx context.Context, username, password, emai
 	}
 
 	// Log with query in single line
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"query", strings.Join(strings.Fields(query), " "),
 		"args", args,
 		"result", rowsAffected,
@@ -79,3 +106,31 @@ func (r *UserWriteRepository) Save(ctx context.Context, username, password, emai
 
 	return err
 }
+
+// SetRole sets the role of the user, e.g. models.RoleAdmin.
+// Returns sql.ErrNoRows if the user does not exist.
+func (r *UserWriteRepository) SetRole(ctx context.Context, userID uuid.UUID, role string) error {
+	query := `UPDATE users SET role = $2, updated_at = NOW() WHERE user_id = $1`
+	args := []any{userID, role}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	var rowsAffected int64
+	if res != nil {
+		rowsAffected, _ = res.RowsAffected()
+	}
+
+	logger.FromContext(ctx).Infow(
+		"query", query,
+		"args", args,
+		"result", rowsAffected,
+		"error", err,
+	)
+
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
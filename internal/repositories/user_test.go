@@ -2,74 +2,18 @@ package repositories
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
 	"testing"
-	"time"
 
+	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
 	"github.com/stretchr/testify/assert"
-	tc "github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-func setupUserPostgresContainer(t *testing.T) (*sqlx.DB, func()) {
-	t.Helper()
-
-	req := tc.ContainerRequest{
-		Image:        "postgres:15-alpine",
-		Env:          map[string]string{"POSTGRES_PASSWORD": "password", "POSTGRES_DB": "testdb", "POSTGRES_USER": "postgres"},
-		ExposedPorts: []string{"5432/tcp"},
-		WaitingFor:   wait.ForListeningPort("5432/tcp"),
-	}
-
-	container, err := tc.GenericContainer(context.Background(), tc.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	assert.NoError(t, err)
-
-	host, _ := container.Host(context.Background())
-	port, _ := container.MappedPort(context.Background(), "5432")
-
-	dsn := fmt.Sprintf("postgres://postgres:password@%s:%d/testdb?sslmode=disable", host, port.Int())
-
-	var db *sqlx.DB
-	for i := 0; i < 10; i++ {
-		db, err = sqlx.Connect("pgx", dsn)
-		if err == nil {
-			break
-		}
-		time.Sleep(time.Second)
-	}
-	assert.NoError(t, err)
-
-	schema := `
-	CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-	CREATE TABLE IF NOT EXISTS users (
-		user_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		username VARCHAR(50) NOT NULL UNIQUE,
-		email VARCHAR(100) NOT NULL UNIQUE,
-		password_hash VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-	);
-	`
-	_, err = db.Exec(schema)
-	assert.NoError(t, err)
-
-	teardown := func() {
-		db.Close()
-		container.Terminate(context.Background())
-	}
-
-	return db, teardown
-}
-
 func TestUserWriteRepository_Save(t *testing.T) {
-	db, teardown := setupUserPostgresContainer(t)
-	defer teardown()
+	db := testkit.Postgres(t)
 
 	repo := NewUserWriteRepository(db)
 	ctx := context.Background()
@@ -91,8 +35,7 @@ func TestUserWriteRepository_Save(t *testing.T) {
 }
 
 func TestUserReadRepository_GetByUsernameOrEmail(t *testing.T) {
-	db, teardown := setupUserPostgresContainer(t)
-	defer teardown()
+	db := testkit.Postgres(t)
 
 	writeRepo := NewUserWriteRepository(db)
 	readRepo := NewUserReadRepository(db)
@@ -133,3 +76,50 @@ func TestUserReadRepository_GetByUsernameOrEmail(t *testing.T) {
 		assert.Nil(t, user)
 	})
 }
+
+func TestUserReadRepository_GetByID(t *testing.T) {
+	db := testkit.Postgres(t)
+
+	writeRepo := NewUserWriteRepository(db)
+	readRepo := NewUserReadRepository(db)
+	ctx := context.Background()
+
+	writeRepo.Save(ctx, "frank", "secret", "frank@example.com")
+
+	username := "frank"
+	saved, err := readRepo.GetByUsernameOrEmail(ctx, &username, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", saved.Role)
+
+	t.Run("Found", func(t *testing.T) {
+		user, err := readRepo.GetByID(ctx, saved.UserID)
+		assert.NoError(t, err)
+		assert.Equal(t, "frank", user.Username)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		user, err := readRepo.GetByID(ctx, uuid.New())
+		assert.Error(t, err) // sql.ErrNoRows
+		assert.Nil(t, user)
+	})
+}
+
+func TestUserWriteRepository_SetRole(t *testing.T) {
+	db := testkit.Postgres(t)
+
+	writeRepo := NewUserWriteRepository(db)
+	readRepo := NewUserReadRepository(db)
+	ctx := context.Background()
+
+	writeRepo.Save(ctx, "grace", "secret", "grace@example.com")
+	username := "grace"
+	saved, err := readRepo.GetByUsernameOrEmail(ctx, &username, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, writeRepo.SetRole(ctx, saved.UserID, models.RoleAdmin))
+	user, err := readRepo.GetByID(ctx, saved.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, user.Role)
+
+	assert.ErrorIs(t, writeRepo.SetRole(ctx, uuid.New(), models.RoleAdmin), sql.ErrNoRows)
+}
@@ -3,13 +3,24 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
 )
 
+// ErrInsufficientFunds is returned when a debit updates no wallet because the amount exceeds
+// the available balance or the wallet does not exist.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrNoTransaction is returned when wallets are locked outside of a transaction.
+var ErrNoTransaction = errors.New("wallet locks need a transaction")
+
 // WalletWriterRepository handles wallet write operations
 type WalletWriterRepository struct {
 	db       *sqlx.DB
@@ -21,85 +32,319 @@ func NewWalletWriterRepository(db *sqlx.DB, txGetter func(ctx context.Context) *
 }
 
 // SaveDeposit performs an UPSERT: creates wallet if not exists, otherwise increases balance.
-func (r *WalletWriterRepository) SaveDeposit(ctx context.Context, userID uuid.UUID, amount float64, currency string) error {
+// The change is appended to wallet_events and posted to the ledger against the external
+// account under transactionID, all in the same statement.
+func (r *WalletWriterRepository) SaveDeposit(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error {
 	query := `
-		INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		ON CONFLICT (user_id, currency)
-		DO UPDATE SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+		WITH updated AS (
+			INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, NOW(), NOW())
+			ON CONFLICT (user_id, currency)
+			DO UPDATE SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+			RETURNING user_id, currency, balance
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT $5, 'deposit' FROM updated
+		),
+		entries AS (
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			SELECT $5, 'wallet', user_id, currency, $4::NUMERIC FROM updated
+			UNION ALL
+			SELECT $5, 'external', NULL, currency, -$4::NUMERIC FROM updated
+		)
+		INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+		SELECT user_id, currency, 'deposit', $4, balance FROM updated
 		RETURNING balance
 	`
 
-	var executor sqlx.ExtContext = r.db
-	if r.txGetter != nil {
-		if tx := r.txGetter(ctx); tx != nil {
-			executor = tx
-		}
+	var balance money.Amount
+	err := sqlx.GetContext(ctx, r.executor(ctx), &balance, query, uuid.New(), userID, currency, amount, transactionID)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{transactionID, userID, currency, amount},
+		"result", balance,
+		"error", err,
+	)
+
+	return err
+}
+
+// SaveWithdraw decreases the balance in a single query. Only the available balance, not
+// held by pending holds or saved in pots excluded from spending, plus the overdraft limit
+// of the wallet can be withdrawn, so the balance goes down to -overdraft_limit at most.
+// Returns ErrInsufficientFunds if the wallet does not exist or the amount exceeds the
+// available balance and the overdraft limit.
+// The change is appended to wallet_events and posted to the ledger against the external
+// account under transactionID, all in the same statement.
+func (r *WalletWriterRepository) SaveWithdraw(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error {
+	query := `
+		WITH updated AS (
+			UPDATE wallets SET balance = balance - $3, updated_at = NOW()
+			WHERE user_id = $1 AND currency = $2 AND balance - held - saved + overdraft_limit >= $3
+			RETURNING user_id, currency, balance
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT $4, 'withdraw' FROM updated
+		),
+		entries AS (
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			SELECT $4, 'wallet', user_id, currency, -$3::NUMERIC FROM updated
+			UNION ALL
+			SELECT $4, 'external', NULL, currency, $3::NUMERIC FROM updated
+		)
+		INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+		SELECT user_id, currency, 'withdraw', $3, balance FROM updated
+		RETURNING balance
+	`
+
+	var balance money.Amount
+	err := sqlx.GetContext(ctx, r.executor(ctx), &balance, query, userID, currency, amount, transactionID)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{transactionID, userID, currency, amount},
+		"result", balance,
+		"error", err,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInsufficientFunds
 	}
+	return err
+}
 
-	var balance float64
-	err := sqlx.GetContext(ctx, executor, &balance, query, uuid.New(), userID, currency, amount)
+// SaveExchange debits amount from the fromCurrency wallet and credits toAmount to the
+// toCurrency wallet in a single statement, so an exchange is never half applied. Both legs
+// are appended to wallet_events and posted to the ledger as one transaction through the
+// exchange account; fee, the part of amount that is not exchanged, is posted to the fee
+// account as a separate entry. The overdraft limit does not apply to exchanges.
+// Returns ErrInsufficientFunds if the available balance is lower than the amount.
+func (r *WalletWriterRepository) SaveExchange(ctx context.Context, transactionID, userID uuid.UUID, fromCurrency string, amount, fee money.Amount, toCurrency string, toAmount money.Amount) error {
+	query := `
+		WITH debited AS (
+			UPDATE wallets SET balance = balance - $3, updated_at = NOW()
+			WHERE user_id = $1 AND currency = $2 AND balance - held - saved >= $3
+			RETURNING user_id, currency, balance
+		),
+		credited AS (
+			INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
+			SELECT $6, user_id, $4, $5, NOW(), NOW() FROM debited
+			ON CONFLICT (user_id, currency)
+			DO UPDATE SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+			RETURNING user_id, currency, balance
+		),
+		events AS (
+			INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			SELECT user_id, currency, 'withdraw', $3::NUMERIC, balance FROM debited
+			UNION ALL
+			SELECT user_id, currency, 'deposit', $5::NUMERIC, balance FROM credited
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT $7, 'exchange' FROM debited
+		),
+		entries AS (
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			SELECT $7, 'wallet', user_id, currency, -$3::NUMERIC FROM debited
+			UNION ALL
+			SELECT $7, 'exchange', NULL, currency, $3::NUMERIC - $8::NUMERIC FROM debited
+			UNION ALL
+			SELECT $7, 'fee', NULL, currency, $8::NUMERIC FROM debited WHERE $8::NUMERIC > 0
+			UNION ALL
+			SELECT $7, 'exchange', NULL, currency, -$5::NUMERIC FROM credited
+			UNION ALL
+			SELECT $7, 'wallet', user_id, currency, $5::NUMERIC FROM credited
+		)
+		SELECT balance FROM debited
+	`
+
+	args := []any{userID, fromCurrency, amount, toCurrency, toAmount, uuid.New(), transactionID, fee}
+	var balance money.Amount
+	err := sqlx.GetContext(ctx, r.executor(ctx), &balance, query, args...)
 
 	// Log query, args, result, error
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"query", strings.Join(strings.Fields(query), " "),
-		"args", []any{userID, currency, amount},
+		"args", args,
 		"result", balance,
 		"error", err,
 	)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInsufficientFunds
+	}
 	return err
 }
 
-// SaveWithdraw performs an UPSERT-like withdrawal in a single query.
-func (r *WalletWriterRepository) SaveWithdraw(ctx context.Context, userID uuid.UUID, amount float64, currency string) error {
+// Close removes the user's wallet in currency and, if toCurrency is set, credits its
+// balance converted at rate to the toCurrency wallet, all in a single statement.
+// Both movements are appended to wallet_events and posted to the ledger under
+// transactionID through the exchange account. A non-empty wallet is only closed
+// when toCurrency is set, and a wallet with pending holds or a negative balance is never closed.
+// Returns the closed balance and the credited amount, or sql.ErrNoRows if there
+// is no such wallet to close.
+// The credited amount is rounded half away from zero to the decimal places of
+// toCurrency, as money.Amount.ConvertRound does.
+func (r *WalletWriterRepository) Close(ctx context.Context, transactionID, userID uuid.UUID, currency, toCurrency string, rate float32) (balance, credited money.Amount, err error) {
 	query := `
-		INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, 0, NOW(), NOW())
-		ON CONFLICT (user_id, currency)
-		DO UPDATE SET balance = wallets.balance - $4, updated_at = NOW()
-		WHERE wallets.balance >= $4
-		RETURNING balance
+		WITH closed AS (
+			DELETE FROM wallets
+			WHERE user_id = $1 AND currency = $2 AND ($3 <> '' OR balance = 0) AND held = 0 AND balance >= 0
+			RETURNING user_id, balance,
+				ROUND(balance * $4::NUMERIC, COALESCE((SELECT decimals FROM currencies WHERE code = $3), 2)) AS credited
+		),
+		closed_event AS (
+			INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			SELECT user_id, $2, 'withdraw', balance, 0 FROM closed WHERE balance > 0
+		),
+		payout AS (
+			INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
+			SELECT $5, user_id, $3, credited, NOW(), NOW() FROM closed WHERE $3 <> '' AND credited > 0
+			ON CONFLICT (user_id, currency)
+			DO UPDATE SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+			RETURNING user_id, currency, balance
+		),
+		payout_event AS (
+			INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			SELECT p.user_id, p.currency, 'deposit', c.credited, p.balance FROM payout p, closed c
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT $6, 'close' FROM closed WHERE balance > 0
+		),
+		entries AS (
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			SELECT $6, 'wallet', user_id, $2, -balance FROM closed WHERE balance > 0
+			UNION ALL
+			SELECT $6, 'exchange', NULL, $2, balance FROM closed WHERE balance > 0
+			UNION ALL
+			SELECT $6, 'exchange', NULL, p.currency, -c.credited FROM payout p, closed c
+			UNION ALL
+			SELECT $6, 'wallet', p.user_id, p.currency, c.credited FROM payout p, closed c
+		)
+		SELECT balance, CASE WHEN $3 <> '' THEN credited ELSE 0 END FROM closed
 	`
 
-	var executor sqlx.ExtContext = r.db
+	// The rate is passed as its shortest decimal form to keep NUMERIC math exact
+	args := []any{userID, currency, toCurrency, strconv.FormatFloat(float64(rate), 'f', -1, 32), uuid.New(), transactionID}
+	err = r.executor(ctx).QueryRowxContext(ctx, query, args...).Scan(&balance, &credited)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", []money.Amount{balance, credited},
+		"error", err,
+	)
+
+	return balance, credited, err
+}
+
+// Create opens empty wallets of the user in the given currencies. Currencies the user
+// already has a wallet in are skipped. An "open" event with a zero balance is appended to
+// wallet_events for every created wallet, so read models projected from the events list it.
+// Returns the currencies of the created wallets.
+func (r *WalletWriterRepository) Create(ctx context.Context, userID uuid.UUID, currencies []string) ([]string, error) {
+	query := `
+		WITH created AS (
+			INSERT INTO wallets (wallet_id, user_id, currency, balance, created_at, updated_at)
+			SELECT gen_random_uuid(), $1, currency, 0, NOW(), NOW() FROM unnest($2::TEXT[]) AS currency
+			ON CONFLICT (user_id, currency) DO NOTHING
+			RETURNING user_id, currency
+		),
+		opened AS (
+			INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			SELECT user_id, currency, 'open', 0, 0 FROM created
+		)
+		SELECT currency FROM created ORDER BY currency
+	`
+
+	created := []string{}
+	err := sqlx.SelectContext(ctx, r.executor(ctx), &created, query, userID, currencies)
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID, currencies},
+		"result", created,
+		"error", err,
+	)
+
+	return created, err
+}
+
+// Lock locks the rows of the given wallets with SELECT ... FOR UPDATE until the transaction
+// of the request ends, so a multi-step flow reads and changes them without concurrent writes
+// in between. The rows are always locked in (user_id, currency) order, whatever the order of
+// wallets, so two transfers between the same users in opposite directions wait for each other
+// instead of deadlocking. Wallets that do not exist yet are not locked.
+// Returns ErrNoTransaction if the request has no transaction, as the locks would be released
+// at once.
+func (r *WalletWriterRepository) Lock(ctx context.Context, wallets []models.WalletKey) error {
+	var tx *sqlx.Tx
 	if r.txGetter != nil {
-		if tx := r.txGetter(ctx); tx != nil {
-			executor = tx
-		}
+		tx = r.txGetter(ctx)
+	}
+	if tx == nil {
+		return ErrNoTransaction
+	}
+
+	query := `
+		SELECT w.wallet_id
+		FROM wallets w
+		JOIN unnest($1::UUID[], $2::TEXT[]) AS k(user_id, currency)
+		  ON w.user_id = k.user_id AND w.currency = k.currency
+		ORDER BY w.user_id, w.currency
+		FOR UPDATE OF w
+	`
+
+	userIDs := make([]string, len(wallets))
+	currencies := make([]string, len(wallets))
+	for i, wallet := range wallets {
+		userIDs[i] = wallet.UserID.String()
+		currencies[i] = wallet.Currency
 	}
 
-	var balance float64
-	err := sqlx.GetContext(ctx, executor, &balance, query, uuid.New(), userID, currency, amount)
+	locked := []uuid.UUID{}
+	err := sqlx.SelectContext(ctx, tx, &locked, query, userIDs, currencies)
 
 	// Log query, args, result, error
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"query", strings.Join(strings.Fields(query), " "),
-		"args", []any{userID, currency, amount},
-		"result", balance,
+		"args", []any{userIDs, currencies},
+		"result", locked,
 		"error", err,
 	)
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return sql.ErrNoRows
+	return err
+}
+
+// executor returns the transaction of the request if there is one, otherwise the database
+func (r *WalletWriterRepository) executor(ctx context.Context) sqlx.ExtContext {
+	if r.txGetter != nil {
+		if tx := r.txGetter(ctx); tx != nil {
+			return tx
 		}
-		return err
 	}
-	return nil
+	return r.db
 }
 
 // WalletReaderRepository handles wallet read operations
 type WalletReaderRepository struct {
-	db *sqlx.DB
+	db Reader
 }
 
-func NewWalletReaderRepository(db *sqlx.DB) *WalletReaderRepository {
+func NewWalletReaderRepository(db Reader) *WalletReaderRepository {
 	return &WalletReaderRepository{db: db}
 }
 
 // GetByUserID retrieves all wallets for a given user as a map[currency]balance
-func (r *WalletReaderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]float64, error) {
+func (r *WalletReaderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
 	const query = `
 		SELECT currency, balance
 		FROM wallets
@@ -107,20 +352,20 @@ func (r *WalletReaderRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	`
 
 	var wallets []struct {
-		Currency string  `db:"currency"`
-		Balance  float64 `db:"balance"`
+		Currency string       `db:"currency"`
+		Balance  money.Amount `db:"balance"`
 	}
 
 	err := r.db.SelectContext(ctx, &wallets, query, userID)
 
 	// Convert to map
-	balances := make(map[string]float64, len(wallets))
+	balances := make(map[string]money.Amount, len(wallets))
 	for _, w := range wallets {
 		balances[w.Currency] = w.Balance
 	}
 
 	// Log query, args, result, error
-	logger.Log.Infow(
+	logger.FromContext(ctx).Infow(
 		"query", strings.Join(strings.Fields(query), " "),
 		"args", []any{userID},
 		"result", balances,
@@ -129,3 +374,34 @@ func (r *WalletReaderRepository) GetByUserID(ctx context.Context, userID uuid.UU
 
 	return balances, err
 }
+
+// GetOverdraftLimits returns the overdraft limits of the user's wallets that have one, by currency
+func (r *WalletReaderRepository) GetOverdraftLimits(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	const query = `
+		SELECT currency, overdraft_limit
+		FROM wallets
+		WHERE user_id = $1 AND overdraft_limit > 0
+	`
+
+	var rows []struct {
+		Currency       string       `db:"currency"`
+		OverdraftLimit money.Amount `db:"overdraft_limit"`
+	}
+
+	err := r.db.SelectContext(ctx, &rows, query, userID)
+
+	limits := make(map[string]money.Amount, len(rows))
+	for _, row := range rows {
+		limits[row.Currency] = row.OverdraftLimit
+	}
+
+	// Log query, args, result, error
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", limits,
+		"error", err,
+	)
+
+	return limits, err
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// WalletDetailsRepository stores the client-managed labels and metadata of wallets
+type WalletDetailsRepository struct {
+	db *sqlx.DB
+}
+
+func NewWalletDetailsRepository(db *sqlx.DB) *WalletDetailsRepository {
+	return &WalletDetailsRepository{db: db}
+}
+
+type walletDetailsRow struct {
+	Currency string  `db:"currency"`
+	Label    *string `db:"label"`
+	Metadata []byte  `db:"metadata"`
+}
+
+func (row walletDetailsRow) details() (models.WalletDetails, error) {
+	var details models.WalletDetails
+	if row.Label != nil {
+		details.Label = *row.Label
+	}
+	if err := json.Unmarshal(row.Metadata, &details.Metadata); err != nil {
+		return models.WalletDetails{}, err
+	}
+	if len(details.Metadata) == 0 {
+		details.Metadata = nil
+	}
+	return details, nil
+}
+
+// GetByUserID returns the details of the user's wallets that have a label or metadata, by currency
+func (r *WalletDetailsRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]models.WalletDetails, error) {
+	const query = `
+		SELECT currency, label, metadata
+		FROM wallets
+		WHERE user_id = $1 AND (label IS NOT NULL OR metadata <> '{}'::jsonb)
+	`
+
+	var rows []walletDetailsRow
+	err := r.db.SelectContext(ctx, &rows, query, userID)
+
+	details := make(map[string]models.WalletDetails, len(rows))
+	for i := 0; err == nil && i < len(rows); i++ {
+		details[rows[i].Currency], err = rows[i].details()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", details,
+		"error", err,
+	)
+
+	return details, err
+}
+
+// Update changes the details of the user's wallet in a currency and returns them.
+// A nil label or metadata is left as is, an empty label removes it and a metadata
+// map replaces the stored one. Returns sql.ErrNoRows if the user has no wallet in the currency.
+func (r *WalletDetailsRepository) Update(ctx context.Context, userID uuid.UUID, currency string, label *string, metadata map[string]string) (models.WalletDetails, error) {
+	const query = `
+		UPDATE wallets
+		SET label = NULLIF(COALESCE($3, label), ''),
+		    metadata = COALESCE($4::jsonb, metadata),
+		    updated_at = NOW()
+		WHERE user_id = $1 AND currency = $2
+		RETURNING currency, label, metadata
+	`
+
+	var payload *string
+	if metadata != nil {
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return models.WalletDetails{}, err
+		}
+		s := string(encoded)
+		payload = &s
+	}
+
+	var row walletDetailsRow
+	err := r.db.GetContext(ctx, &row, query, userID, currency, label, payload)
+
+	var details models.WalletDetails
+	if err == nil {
+		details, err = row.details()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID, currency, label, metadata},
+		"result", details,
+		"error", err,
+	)
+
+	return details, err
+}
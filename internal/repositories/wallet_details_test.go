@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletDetailsRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+	testkit.CreateWallet(t, db, userID, models.USD, money.MustParse("100"))
+	testkit.CreateWallet(t, db, userID, models.EUR, money.MustParse("50"))
+
+	repo := NewWalletDetailsRepository(db)
+
+	t.Run("no details", func(t *testing.T) {
+		details, err := repo.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, details)
+	})
+
+	t.Run("set label and metadata", func(t *testing.T) {
+		label := "travel fund"
+		details, err := repo.Update(ctx, userID, models.USD, &label, map[string]string{"trip": "japan"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.WalletDetails{Label: "travel fund", Metadata: map[string]string{"trip": "japan"}}, details)
+	})
+
+	t.Run("nil fields are kept", func(t *testing.T) {
+		details, err := repo.Update(ctx, userID, models.USD, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, models.WalletDetails{Label: "travel fund", Metadata: map[string]string{"trip": "japan"}}, details)
+
+		all, err := repo.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]models.WalletDetails{models.USD: details}, all)
+	})
+
+	t.Run("empty values clear", func(t *testing.T) {
+		empty := ""
+		details, err := repo.Update(ctx, userID, models.USD, &empty, map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, models.WalletDetails{}, details)
+
+		all, err := repo.GetByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("missing wallet", func(t *testing.T) {
+		label := "savings"
+		_, err := repo.Update(ctx, userID, models.RUB, &label, nil)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = repo.Update(ctx, uuid.New(), models.USD, &label, nil)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// WalletEventReadRepository reads the wallet ledger
+type WalletEventReadRepository struct {
+	db *sqlx.DB
+}
+
+func NewWalletEventReadRepository(db *sqlx.DB) *WalletEventReadRepository {
+	return &WalletEventReadRepository{db: db}
+}
+
+// ListByUserID returns all ledger entries of a user in the order they were recorded
+func (r *WalletEventReadRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletEventDB, error) {
+	query := `
+		SELECT event_id, user_id, currency, operation, amount, balance, created_at
+		FROM wallet_events
+		WHERE user_id = $1
+		ORDER BY event_id
+	`
+
+	var events []models.WalletEventDB
+	err := r.db.SelectContext(ctx, &events, query, userID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", len(events),
+		"error", err,
+	)
+
+	return events, err
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// WalletHoldRepository stores holds and keeps the held amount of the wallets in sync with them
+type WalletHoldRepository struct {
+	db       *sqlx.DB
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewWalletHoldRepository(db *sqlx.DB, txGetter func(ctx context.Context) *sqlx.Tx) *WalletHoldRepository {
+	return &WalletHoldRepository{db: db, txGetter: txGetter}
+}
+
+const walletHoldColumns = `hold_id, user_id, currency, amount, status, limit_usage_id, created_at, updated_at`
+
+// Create places a pending hold, adding its amount to the held amount of the wallet in the
+// same statement. Returns sql.ErrNoRows if the available balance is lower than the amount.
+func (r *WalletHoldRepository) Create(ctx context.Context, hold models.WalletHoldDB) (models.WalletHoldDB, error) {
+	query := `
+		WITH reserved AS (
+			UPDATE wallets SET held = held + $4, updated_at = NOW()
+			WHERE user_id = $2 AND currency = $3 AND balance - held - saved >= $4
+			RETURNING user_id, currency
+		)
+		INSERT INTO wallet_holds (hold_id, user_id, currency, amount, status, limit_usage_id, created_at, updated_at)
+		SELECT $1, user_id, currency, $4, 'pending', $5, NOW(), NOW() FROM reserved
+		RETURNING ` + walletHoldColumns
+	args := []any{hold.HoldID, hold.UserID, hold.Currency, hold.Amount, hold.LimitUsageID}
+
+	var created models.WalletHoldDB
+	err := r.db.GetContext(ctx, &created, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", created.HoldID,
+		"error", err,
+	)
+
+	return created, err
+}
+
+// Capture completes a pending hold: its amount leaves both the balance and the held amount
+// of the wallet, the withdrawal is appended to wallet_events and posted to the ledger under
+// transactionID, all in a single statement.
+// Returns sql.ErrNoRows if the user has no pending hold with the ID.
+func (r *WalletHoldRepository) Capture(ctx context.Context, transactionID, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	query := `
+		WITH captured AS (
+			UPDATE wallet_holds SET status = 'captured', updated_at = NOW()
+			WHERE hold_id = $1 AND user_id = $2 AND status = 'pending'
+			RETURNING ` + walletHoldColumns + `
+		),
+		updated AS (
+			UPDATE wallets w SET balance = w.balance - c.amount, held = w.held - c.amount, updated_at = NOW()
+			FROM captured c
+			WHERE w.user_id = c.user_id AND w.currency = c.currency
+			RETURNING w.user_id, w.currency, w.balance
+		),
+		captured_event AS (
+			INSERT INTO wallet_events (user_id, currency, operation, amount, balance)
+			SELECT u.user_id, u.currency, 'withdraw', c.amount, u.balance FROM updated u, captured c
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT $3, 'withdraw' FROM updated
+		),
+		entries AS (
+			INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+			SELECT $3, 'wallet', u.user_id, u.currency, -c.amount FROM updated u, captured c
+			UNION ALL
+			SELECT $3, 'external', NULL, u.currency, c.amount FROM updated u, captured c
+		)
+		SELECT ` + walletHoldColumns + ` FROM captured
+	`
+	return r.finish(ctx, query, userID, holdID, transactionID)
+}
+
+// Release cancels a pending hold, returning its amount to the available balance.
+// Returns sql.ErrNoRows if the user has no pending hold with the ID.
+func (r *WalletHoldRepository) Release(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	query := `
+		WITH released AS (
+			UPDATE wallet_holds SET status = 'released', updated_at = NOW()
+			WHERE hold_id = $1 AND user_id = $2 AND status = 'pending'
+			RETURNING ` + walletHoldColumns + `
+		),
+		updated AS (
+			UPDATE wallets w SET held = w.held - r.amount, updated_at = NOW()
+			FROM released r
+			WHERE w.user_id = r.user_id AND w.currency = r.currency
+		)
+		SELECT ` + walletHoldColumns + ` FROM released
+	`
+	return r.finish(ctx, query, userID, holdID)
+}
+
+// finish runs a capture or release query for the user's hold. extra are the query
+// arguments after the hold and user IDs.
+func (r *WalletHoldRepository) finish(ctx context.Context, query string, userID, holdID uuid.UUID, extra ...any) (models.WalletHoldDB, error) {
+	args := append([]any{holdID, userID}, extra...)
+
+	var hold models.WalletHoldDB
+	err := sqlx.GetContext(ctx, r.executor(ctx), &hold, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", hold.Status,
+		"error", err,
+	)
+
+	return hold, err
+}
+
+// Get returns the user's hold by ID, or sql.ErrNoRows if there is none
+func (r *WalletHoldRepository) Get(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	query := `
+		SELECT ` + walletHoldColumns + `
+		FROM wallet_holds
+		WHERE hold_id = $1 AND user_id = $2
+	`
+	args := []any{holdID, userID}
+
+	var hold models.WalletHoldDB
+	err := sqlx.GetContext(ctx, r.executor(ctx), &hold, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", hold.Status,
+		"error", err,
+	)
+
+	return hold, err
+}
+
+// HeldByUserID returns the amounts held by pending holds per currency
+func (r *WalletHoldRepository) HeldByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	const query = `
+		SELECT currency, held
+		FROM wallets
+		WHERE user_id = $1 AND held > 0
+	`
+
+	var rows []struct {
+		Currency string       `db:"currency"`
+		Held     money.Amount `db:"held"`
+	}
+	err := r.db.SelectContext(ctx, &rows, query, userID)
+
+	held := make(map[string]money.Amount, len(rows))
+	for _, row := range rows {
+		held[row.Currency] = row.Held
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", held,
+		"error", err,
+	)
+
+	return held, err
+}
+
+// executor returns the transaction of the request if there is one, otherwise the database
+func (r *WalletHoldRepository) executor(ctx context.Context) sqlx.ExtContext {
+	if r.txGetter != nil {
+		if tx := r.txGetter(ctx); tx != nil {
+			return tx
+		}
+	}
+	return r.db
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletHoldRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	repo := NewWalletHoldRepository(db, nil)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), models.USD))
+
+	getHeld := func() money.Amount {
+		var held money.Amount
+		assert.NoError(t, db.Get(&held, `SELECT held FROM wallets WHERE user_id = $1 AND currency = $2`, userID, models.USD))
+		return held
+	}
+
+	newHold := func(amount string) (models.WalletHoldDB, error) {
+		return repo.Create(ctx, models.WalletHoldDB{HoldID: uuid.New(), UserID: userID, Currency: models.USD, Amount: money.MustParse(amount)})
+	}
+
+	t.Run("create reserves available funds", func(t *testing.T) {
+		hold, err := newHold("60")
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusPending, hold.Status)
+		assert.Equal(t, money.MustParse("60"), getHeld())
+		assert.Equal(t, money.MustParse("100"), getBalance(t, db, userID, models.USD))
+
+		_, err = newHold("40.01")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		held, err := repo.HeldByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]money.Amount{models.USD: money.MustParse("60")}, held)
+
+		_, err = repo.Release(ctx, userID, hold.HoldID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("withdraw and close respect held funds", func(t *testing.T) {
+		hold, err := newHold("70")
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("30.01"), models.USD), ErrInsufficientFunds)
+		_, _, err = writer.Close(ctx, uuid.New(), userID, models.USD, models.EUR, 1)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = repo.Release(ctx, userID, hold.HoldID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("capture withdraws the held amount", func(t *testing.T) {
+		hold, err := newHold("25")
+		assert.NoError(t, err)
+
+		txnID := uuid.New()
+		captured, err := repo.Capture(ctx, txnID, userID, hold.HoldID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusCaptured, captured.Status)
+		assert.Equal(t, money.MustParse("75"), getBalance(t, db, userID, models.USD))
+		assert.Equal(t, money.Amount(0), getHeld())
+
+		var events int
+		assert.NoError(t, db.Get(&events, `SELECT COUNT(*) FROM wallet_events WHERE user_id = $1 AND operation = 'withdraw' AND amount = 25`, userID))
+		assert.Equal(t, 1, events)
+
+		entries, err := NewLedgerRepository(db).Entries(ctx, txnID)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, money.MustParse("-25"), entries[0].Amount)
+			assert.Equal(t, money.MustParse("25"), entries[1].Amount)
+		}
+
+		_, err = repo.Capture(ctx, uuid.New(), userID, hold.HoldID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.Release(ctx, userID, hold.HoldID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("release frees the held amount", func(t *testing.T) {
+		hold, err := newHold("75")
+		assert.NoError(t, err)
+
+		released, err := repo.Release(ctx, userID, hold.HoldID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusReleased, released.Status)
+		assert.Equal(t, money.MustParse("75"), getBalance(t, db, userID, models.USD))
+		assert.Equal(t, money.Amount(0), getHeld())
+
+		got, err := repo.Get(ctx, userID, hold.HoldID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusReleased, got.Status)
+	})
+
+	t.Run("holds of other users are not found", func(t *testing.T) {
+		hold, err := newHold("10")
+		assert.NoError(t, err)
+
+		_, err = repo.Get(ctx, uuid.New(), hold.HoldID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.Capture(ctx, uuid.New(), uuid.New(), hold.HoldID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// WalletLimitRepository stores per-user withdrawal and exchange limits and tracks their usage
+type WalletLimitRepository struct {
+	db *sqlx.DB
+}
+
+func NewWalletLimitRepository(db *sqlx.DB) *WalletLimitRepository {
+	return &WalletLimitRepository{db: db}
+}
+
+// ListByUserID returns the user's limits with the usage within their windows, ordered by currency
+func (r *WalletLimitRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletLimitDB, error) {
+	const query = `
+		SELECT l.user_id, l.currency, l.daily_limit, l.monthly_limit, l.updated_at,
+		       COALESCE(SUM(u.amount) FILTER (WHERE u.created_at > NOW() - make_interval(secs => $2)), 0) AS daily_used,
+		       COALESCE(SUM(u.amount), 0) AS monthly_used
+		FROM wallet_limits l
+		LEFT JOIN wallet_limit_usage u
+		  ON u.user_id = l.user_id AND u.currency = l.currency
+		 AND u.created_at > NOW() - make_interval(secs => $3)
+		WHERE l.user_id = $1
+		GROUP BY l.user_id, l.currency
+		ORDER BY l.currency
+	`
+	args := []any{userID, models.LimitDailyWindow.Seconds(), models.LimitMonthlyWindow.Seconds()}
+
+	var limits []models.WalletLimitDB
+	err := r.db.SelectContext(ctx, &limits, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(limits),
+		"error", err,
+	)
+
+	return limits, err
+}
+
+// Set creates or replaces the user's limits in a currency. Removing both limits deletes the row.
+func (r *WalletLimitRepository) Set(ctx context.Context, limit models.WalletLimitDB) error {
+	query := `
+		INSERT INTO wallet_limits (user_id, currency, daily_limit, monthly_limit, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, currency)
+		DO UPDATE SET daily_limit = EXCLUDED.daily_limit, monthly_limit = EXCLUDED.monthly_limit, updated_at = NOW()
+	`
+	args := []any{limit.UserID, limit.Currency, limit.DailyLimit, limit.MonthlyLimit}
+	if limit.DailyLimit == nil && limit.MonthlyLimit == nil {
+		query = `
+			DELETE FROM wallet_limits
+			WHERE user_id = $1 AND currency = $2
+		`
+		args = args[:2]
+	}
+
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// Reserve records amount against the user's usage in currency unless it would exceed
+// one of the user's limits. Reservations for the same user and currency are serialized
+// on the limits row, so concurrent operations cannot overrun a limit together.
+// Returns the usage entry ID, or the period whose limit would be exceeded.
+func (r *WalletLimitRepository) Reserve(ctx context.Context, userID uuid.UUID, currency string, amount money.Amount) (usageID int64, exceeded string, err error) {
+	const lockQuery = `
+		SELECT daily_limit, monthly_limit
+		FROM wallet_limits
+		WHERE user_id = $1 AND currency = $2
+		FOR UPDATE
+	`
+	const usageQuery = `
+		SELECT COALESCE(SUM(amount) FILTER (WHERE created_at > NOW() - make_interval(secs => $3)), 0) AS daily,
+		       COALESCE(SUM(amount), 0) AS monthly
+		FROM wallet_limit_usage
+		WHERE user_id = $1 AND currency = $2 AND created_at > NOW() - make_interval(secs => $4)
+	`
+	const insertQuery = `
+		INSERT INTO wallet_limit_usage (user_id, currency, amount, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING usage_id
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	defer tx.Rollback()
+
+	var limit struct {
+		Daily   *money.Amount `db:"daily_limit"`
+		Monthly *money.Amount `db:"monthly_limit"`
+	}
+	args := []any{userID, currency}
+	err = tx.GetContext(ctx, &limit, lockQuery, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(lockQuery), " "),
+		"args", args,
+		"result", limit,
+		"error", err,
+	)
+
+	// Without a limits row the usage is still tracked, for limits set later
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, "", err
+	}
+
+	var used struct {
+		Daily   money.Amount `db:"daily"`
+		Monthly money.Amount `db:"monthly"`
+	}
+	args = []any{userID, currency, models.LimitDailyWindow.Seconds(), models.LimitMonthlyWindow.Seconds()}
+	err = tx.GetContext(ctx, &used, usageQuery, args...)
+
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(usageQuery), " "),
+		"args", args,
+		"result", used,
+		"error", err,
+	)
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	switch {
+	case limit.Daily != nil && used.Daily+amount > *limit.Daily:
+		return 0, models.LimitPeriodDaily, nil
+	case limit.Monthly != nil && used.Monthly+amount > *limit.Monthly:
+		return 0, models.LimitPeriodMonthly, nil
+	}
+
+	args = []any{userID, currency, amount}
+	err = tx.GetContext(ctx, &usageID, insertQuery, args...)
+
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(insertQuery), " "),
+		"args", args,
+		"result", usageID,
+		"error", err,
+	)
+
+	if err != nil {
+		return 0, "", err
+	}
+	return usageID, "", tx.Commit()
+}
+
+// Release removes a usage entry of an operation that did not go through
+func (r *WalletLimitRepository) Release(ctx context.Context, usageID int64) error {
+	const query = `
+		DELETE FROM wallet_limit_usage
+		WHERE usage_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, usageID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{usageID},
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// PurgeUsage deletes usage entries older than olderThan and returns how many were deleted
+func (r *WalletLimitRepository) PurgeUsage(ctx context.Context, olderThan time.Duration) (int64, error) {
+	const query = `
+		DELETE FROM wallet_limit_usage
+		WHERE created_at < NOW() - make_interval(secs => $1)
+	`
+	args := []any{olderThan.Seconds()}
+
+	var deleted int64
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		deleted, err = res.RowsAffected()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", deleted,
+		"error", err,
+	)
+
+	return deleted, err
+}
+
+// SetOverdraft sets the overdraft limit of the user's wallet in a currency.
+// Returns sql.ErrNoRows if the user has no wallet in the currency.
+func (r *WalletLimitRepository) SetOverdraft(ctx context.Context, userID uuid.UUID, currency string, limit money.Amount) error {
+	const query = `
+		UPDATE wallets SET overdraft_limit = $3, updated_at = NOW()
+		WHERE user_id = $1 AND currency = $2
+		RETURNING overdraft_limit
+	`
+	args := []any{userID, currency, limit}
+
+	var updated money.Amount
+	err := r.db.GetContext(ctx, &updated, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", updated,
+		"error", err,
+	)
+
+	return err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletLimitRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	repo := NewWalletLimitRepository(db)
+
+	t.Run("usage is tracked without limits", func(t *testing.T) {
+		usageID, exceeded, err := repo.Reserve(ctx, userID, models.USD, money.MustParse("500"))
+		assert.NoError(t, err)
+		assert.Empty(t, exceeded)
+		assert.NotZero(t, usageID)
+	})
+
+	daily, monthly := money.MustParse("600"), money.MustParse("1000")
+	assert.NoError(t, repo.Set(ctx, models.WalletLimitDB{UserID: userID, Currency: models.USD, DailyLimit: &daily, MonthlyLimit: &monthly}))
+
+	t.Run("daily limit", func(t *testing.T) {
+		_, exceeded, err := repo.Reserve(ctx, userID, models.USD, money.MustParse("100.01"))
+		assert.NoError(t, err)
+		assert.Equal(t, models.LimitPeriodDaily, exceeded)
+
+		_, exceeded, err = repo.Reserve(ctx, userID, models.USD, money.MustParse("100"))
+		assert.NoError(t, err)
+		assert.Empty(t, exceeded)
+	})
+
+	t.Run("monthly limit counts older usage", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE wallet_limit_usage SET created_at = NOW() - INTERVAL '2 days' WHERE user_id = $1`, userID)
+		assert.NoError(t, err)
+
+		_, exceeded, err := repo.Reserve(ctx, userID, models.USD, money.MustParse("500"))
+		assert.NoError(t, err)
+		assert.Equal(t, models.LimitPeriodMonthly, exceeded)
+	})
+
+	t.Run("release frees the usage", func(t *testing.T) {
+		usageID, exceeded, err := repo.Reserve(ctx, userID, models.USD, money.MustParse("400"))
+		assert.NoError(t, err)
+		assert.Empty(t, exceeded)
+		assert.NoError(t, repo.Release(ctx, usageID))
+
+		limits, err := repo.ListByUserID(ctx, userID)
+		assert.NoError(t, err)
+		if assert.Len(t, limits, 1) {
+			assert.Equal(t, daily, *limits[0].DailyLimit)
+			assert.Equal(t, money.Zero, limits[0].DailyUsed)
+			assert.Equal(t, money.MustParse("600"), limits[0].MonthlyUsed)
+		}
+	})
+
+	t.Run("concurrent reservations do not overrun the limit", func(t *testing.T) {
+		other := testkit.CreateUser(t, db, testkit.WithUsername("bob")).UserID
+
+		limit := money.MustParse("10")
+		assert.NoError(t, repo.Set(ctx, models.WalletLimitDB{UserID: other, Currency: models.EUR, DailyLimit: &limit}))
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			reserved int
+		)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, exceeded, err := repo.Reserve(ctx, other, models.EUR, money.MustParse("1"))
+				if err == nil && exceeded == "" {
+					mu.Lock()
+					reserved++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 10, reserved)
+	})
+
+	t.Run("removing both limits deletes the row", func(t *testing.T) {
+		assert.NoError(t, repo.Set(ctx, models.WalletLimitDB{UserID: userID, Currency: models.USD}))
+
+		limits, err := repo.ListByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, limits)
+	})
+
+	t.Run("purge old usage", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE wallet_limit_usage SET created_at = NOW() - INTERVAL '40 days' WHERE user_id = $1`, userID)
+		assert.NoError(t, err)
+
+		deleted, err := repo.PurgeUsage(ctx, models.LimitMonthlyWindow+24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+	})
+}
@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// WalletPotRepository stores savings pots and keeps the pocketed and saved amounts of the
+// wallets in sync with them
+type WalletPotRepository struct {
+	db *sqlx.DB
+}
+
+func NewWalletPotRepository(db *sqlx.DB) *WalletPotRepository {
+	return &WalletPotRepository{db: db}
+}
+
+const walletPotColumns = `pot_id, user_id, currency, name, balance, spendable, created_at, updated_at`
+
+// Create adds an empty pot to the user's wallet in the pot's currency.
+// Returns sql.ErrNoRows if there is no such wallet or it already has a pot with the name.
+func (r *WalletPotRepository) Create(ctx context.Context, pot models.WalletPotDB) (models.WalletPotDB, error) {
+	query := `
+		INSERT INTO wallet_pots (pot_id, user_id, currency, name, balance, spendable, created_at, updated_at)
+		SELECT $1, user_id, currency, $4, 0, $5, NOW(), NOW()
+		FROM wallets
+		WHERE user_id = $2 AND currency = $3
+		ON CONFLICT (user_id, currency, name) DO NOTHING
+		RETURNING ` + walletPotColumns
+	args := []any{pot.PotID, pot.UserID, pot.Currency, pot.Name, pot.Spendable}
+
+	var created models.WalletPotDB
+	err := r.db.GetContext(ctx, &created, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", created.PotID,
+		"error", err,
+	)
+
+	return created, err
+}
+
+// ListByUserID returns the pots of all wallets of the user, ordered by currency and creation time
+func (r *WalletPotRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletPotDB, error) {
+	query := `
+		SELECT ` + walletPotColumns + `
+		FROM wallet_pots
+		WHERE user_id = $1
+		ORDER BY currency, created_at, pot_id
+	`
+
+	var pots []models.WalletPotDB
+	err := r.db.SelectContext(ctx, &pots, query, userID)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", len(pots),
+		"error", err,
+	)
+
+	return pots, err
+}
+
+// Get returns the user's pot by ID, or sql.ErrNoRows if there is none
+func (r *WalletPotRepository) Get(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error) {
+	query := `
+		SELECT ` + walletPotColumns + `
+		FROM wallet_pots
+		WHERE pot_id = $1 AND user_id = $2
+	`
+	args := []any{potID, userID}
+
+	var pot models.WalletPotDB
+	err := r.db.GetContext(ctx, &pot, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", pot.Name,
+		"error", err,
+	)
+
+	return pot, err
+}
+
+// SetSpendable includes the pot in or excludes it from the spendable balance, moving its
+// balance out of or into the saved amount of the wallet in the same statement.
+// Returns sql.ErrNoRows if the user has no pot with the ID.
+func (r *WalletPotRepository) SetSpendable(ctx context.Context, userID, potID uuid.UUID, spendable bool) (models.WalletPotDB, error) {
+	query := `
+		WITH changed AS (
+			UPDATE wallet_pots SET spendable = $3, updated_at = NOW()
+			WHERE pot_id = $1 AND user_id = $2 AND spendable <> $3
+			RETURNING ` + walletPotColumns + `
+		),
+		updated AS (
+			UPDATE wallets w SET saved = w.saved + CASE WHEN c.spendable THEN -c.balance ELSE c.balance END, updated_at = NOW()
+			FROM changed c
+			WHERE w.user_id = c.user_id AND w.currency = c.currency
+		)
+		SELECT ` + walletPotColumns + ` FROM changed
+		UNION ALL
+		SELECT ` + walletPotColumns + ` FROM wallet_pots
+		WHERE pot_id = $1 AND user_id = $2 AND spendable = $3
+	`
+	args := []any{potID, userID, spendable}
+
+	var pot models.WalletPotDB
+	err := r.db.GetContext(ctx, &pot, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", pot.Spendable,
+		"error", err,
+	)
+
+	return pot, err
+}
+
+// Move moves amount between two pots of the user's currency wallet in a single statement.
+// A nil pot ID stands for the wallet's unallocated balance: its balance not held by pending
+// holds and not set aside in pots. The wallet balance itself does not change, only the
+// pocketed and saved amounts follow the pots.
+// Returns sql.ErrNoRows if a pot is missing or the source has less than the amount.
+// A concurrent move out of the same pot fails the balance check of wallet_pots instead.
+func (r *WalletPotRepository) Move(ctx context.Context, userID uuid.UUID, currency string, fromPotID, toPotID *uuid.UUID, amount money.Amount) error {
+	query := `
+		WITH pots AS (
+			SELECT pot_id, balance, spendable FROM wallet_pots
+			WHERE user_id = $1 AND currency = $2 AND pot_id IN ($3, $4)
+		),
+		moved AS (
+			UPDATE wallets w SET pocketed = w.pocketed + d.pocketed, saved = w.saved + d.saved, updated_at = NOW()
+			FROM (
+				SELECT
+					COALESCE(SUM(CASE WHEN pot_id = $4 THEN $5::NUMERIC ELSE -$5::NUMERIC END), 0) AS pocketed,
+					COALESCE(SUM(CASE WHEN spendable THEN 0 WHEN pot_id = $4 THEN $5::NUMERIC ELSE -$5::NUMERIC END), 0) AS saved,
+					COUNT(*) AS found
+				FROM pots
+			) d
+			WHERE w.user_id = $1 AND w.currency = $2
+			  AND d.found = ($3::UUID IS NOT NULL)::INT + ($4::UUID IS NOT NULL)::INT
+			  AND ($3::UUID IS NOT NULL OR w.balance - w.held - w.pocketed >= $5)
+			  AND NOT EXISTS (SELECT 1 FROM pots WHERE pot_id = $3 AND balance < $5)
+			RETURNING w.user_id
+		),
+		debited AS (
+			UPDATE wallet_pots p SET balance = p.balance - $5, updated_at = NOW()
+			FROM moved m
+			WHERE p.pot_id = $3 AND p.user_id = m.user_id
+		),
+		credited AS (
+			UPDATE wallet_pots p SET balance = p.balance + $5, updated_at = NOW()
+			FROM moved m
+			WHERE p.pot_id = $4 AND p.user_id = m.user_id
+		)
+		SELECT user_id FROM moved
+	`
+	args := []any{userID, currency, fromPotID, toPotID, amount}
+
+	var moved uuid.UUID
+	err := r.db.GetContext(ctx, &moved, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", moved,
+		"error", err,
+	)
+
+	return err
+}
+
+// Delete removes the user's pot, returning its balance to the unallocated balance of the
+// wallet in the same statement. Returns sql.ErrNoRows if the user has no pot with the ID.
+func (r *WalletPotRepository) Delete(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error) {
+	query := `
+		WITH deleted AS (
+			DELETE FROM wallet_pots
+			WHERE pot_id = $1 AND user_id = $2
+			RETURNING ` + walletPotColumns + `
+		),
+		updated AS (
+			UPDATE wallets w SET
+				pocketed = w.pocketed - d.balance,
+				saved = w.saved - CASE WHEN d.spendable THEN 0 ELSE d.balance END,
+				updated_at = NOW()
+			FROM deleted d
+			WHERE w.user_id = d.user_id AND w.currency = d.currency
+		)
+		SELECT ` + walletPotColumns + ` FROM deleted
+	`
+	args := []any{potID, userID}
+
+	var pot models.WalletPotDB
+	err := r.db.GetContext(ctx, &pot, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", pot.Balance,
+		"error", err,
+	)
+
+	return pot, err
+}
+
+// SavedByUserID returns the amounts set aside in pots excluded from the spendable balance per currency
+func (r *WalletPotRepository) SavedByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	const query = `
+		SELECT currency, saved
+		FROM wallets
+		WHERE user_id = $1 AND saved > 0
+	`
+
+	var rows []struct {
+		Currency string       `db:"currency"`
+		Saved    money.Amount `db:"saved"`
+	}
+	err := r.db.SelectContext(ctx, &rows, query, userID)
+
+	saved := make(map[string]money.Amount, len(rows))
+	for _, row := range rows {
+		saved[row.Currency] = row.Saved
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{userID},
+		"result", saved,
+		"error", err,
+	)
+
+	return saved, err
+}
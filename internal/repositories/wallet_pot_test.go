@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletPotRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	repo := NewWalletPotRepository(db)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), models.USD))
+
+	getPocketed := func() (pocketed, saved money.Amount) {
+		row := db.QueryRow(`SELECT pocketed, saved FROM wallets WHERE user_id = $1 AND currency = $2`, userID, models.USD)
+		assert.NoError(t, row.Scan(&pocketed, &saved))
+		return pocketed, saved
+	}
+
+	newPot := func(name string, spendable bool) (models.WalletPotDB, error) {
+		return repo.Create(ctx, models.WalletPotDB{PotID: uuid.New(), UserID: userID, Currency: models.USD, Name: name, Spendable: spendable})
+	}
+
+	holiday, err := newPot("holiday", false)
+	assert.NoError(t, err)
+	groceries, err := newPot("groceries", true)
+	assert.NoError(t, err)
+
+	t.Run("create", func(t *testing.T) {
+		_, err := newPot("holiday", true)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = repo.Create(ctx, models.WalletPotDB{PotID: uuid.New(), UserID: userID, Currency: models.EUR, Name: "holiday"})
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		pots, err := repo.ListByUserID(ctx, userID)
+		assert.NoError(t, err)
+		if assert.Len(t, pots, 2) {
+			assert.Equal(t, holiday.PotID, pots[0].PotID)
+			assert.Equal(t, groceries.PotID, pots[1].PotID)
+		}
+	})
+
+	t.Run("move", func(t *testing.T) {
+		assert.NoError(t, repo.Move(ctx, userID, models.USD, nil, &holiday.PotID, money.MustParse("50")))
+		assert.NoError(t, repo.Move(ctx, userID, models.USD, nil, &groceries.PotID, money.MustParse("30")))
+		assert.NoError(t, repo.Move(ctx, userID, models.USD, &holiday.PotID, &groceries.PotID, money.MustParse("10")))
+
+		pocketed, saved := getPocketed()
+		assert.Equal(t, money.MustParse("80"), pocketed)
+		assert.Equal(t, money.MustParse("40"), saved)
+		assert.Equal(t, money.MustParse("100"), getBalance(t, db, userID, models.USD))
+
+		// Only 20 is unallocated
+		assert.ErrorIs(t, repo.Move(ctx, userID, models.USD, nil, &holiday.PotID, money.MustParse("20.01")), sql.ErrNoRows)
+		assert.ErrorIs(t, repo.Move(ctx, userID, models.USD, &holiday.PotID, nil, money.MustParse("40.01")), sql.ErrNoRows)
+		other := uuid.New()
+		assert.ErrorIs(t, repo.Move(ctx, userID, models.USD, &other, nil, money.MustParse("1")), sql.ErrNoRows)
+		assert.ErrorIs(t, repo.Move(ctx, uuid.New(), models.USD, &holiday.PotID, nil, money.MustParse("1")), sql.ErrNoRows)
+
+		saved2, err := repo.SavedByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]money.Amount{models.USD: money.MustParse("40")}, saved2)
+	})
+
+	t.Run("withdraw spends spendable pots but not saved ones", func(t *testing.T) {
+		assert.ErrorIs(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("60.01"), models.USD), ErrInsufficientFunds)
+		assert.NoError(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("45"), models.USD))
+
+		// 20 unallocated is spent first, the remaining 25 comes out of groceries
+		pot, err := repo.Get(ctx, userID, groceries.PotID)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("15"), pot.Balance)
+
+		pocketed, saved := getPocketed()
+		assert.Equal(t, money.MustParse("55"), pocketed)
+		assert.Equal(t, money.MustParse("40"), saved)
+	})
+
+	t.Run("set spendable", func(t *testing.T) {
+		pot, err := repo.SetSpendable(ctx, userID, holiday.PotID, true)
+		assert.NoError(t, err)
+		assert.True(t, pot.Spendable)
+		_, saved := getPocketed()
+		assert.Equal(t, money.Amount(0), saved)
+
+		pot, err = repo.SetSpendable(ctx, userID, holiday.PotID, true)
+		assert.NoError(t, err)
+		assert.True(t, pot.Spendable)
+
+		_, err = repo.SetSpendable(ctx, userID, holiday.PotID, false)
+		assert.NoError(t, err)
+		_, saved = getPocketed()
+		assert.Equal(t, money.MustParse("40"), saved)
+
+		_, err = repo.SetSpendable(ctx, uuid.New(), holiday.PotID, true)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("delete returns the balance to the wallet", func(t *testing.T) {
+		deleted, err := repo.Delete(ctx, userID, holiday.PotID)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("40"), deleted.Balance)
+
+		pocketed, saved := getPocketed()
+		assert.Equal(t, money.MustParse("15"), pocketed)
+		assert.Equal(t, money.Amount(0), saved)
+
+		_, err = repo.Delete(ctx, userID, holiday.PotID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		_, err = repo.Get(ctx, userID, holiday.PotID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
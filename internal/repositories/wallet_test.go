@@ -3,86 +3,22 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"sync"
 	"testing"
-	"time"
 
 	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
-	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
 	"github.com/stretchr/testify/assert"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-// --- Setup Postgres ---
-func setupPostgres(t *testing.T) (*sqlx.DB, func()) {
-	logger.Initialize("debug")
-	ctx := context.Background()
-
-	req := testcontainers.ContainerRequest{
-		Image:        "postgres:15-alpine",
-		Env:          map[string]string{"POSTGRES_PASSWORD": "secret", "POSTGRES_DB": "testdb", "POSTGRES_USER": "postgres"},
-		ExposedPorts: []string{"5432/tcp"},
-		WaitingFor:   wait.ForListeningPort("5432/tcp").WithStartupTimeout(30 * time.Second),
-	}
-
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	assert.NoError(t, err)
-
-	host, err := container.Host(ctx)
-	assert.NoError(t, err)
-	port, err := container.MappedPort(ctx, "5432")
-	assert.NoError(t, err)
-
-	dsn := fmt.Sprintf("postgres://postgres:secret@%s:%s/testdb?sslmode=disable", host, port.Port())
-	db, err := sqlx.Connect("pgx", dsn)
-	assert.NoError(t, err)
-
-	db.SetMaxOpenConns(20)
-	db.SetMaxIdleConns(10)
-	db.SetConnMaxLifetime(5 * time.Minute)
-
-	migrations := []string{
-		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`,
-		`CREATE TABLE IF NOT EXISTS users (
-			user_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-			username VARCHAR(50) NOT NULL UNIQUE,
-			email VARCHAR(100) NOT NULL UNIQUE,
-			password_hash VARCHAR(255) NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-		);`,
-		`CREATE TABLE IF NOT EXISTS wallets (
-			wallet_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-			user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
-			currency CHAR(3) NOT NULL,
-			balance NUMERIC(20,2) NOT NULL DEFAULT 0.0,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			UNIQUE (user_id, currency)
-		);`,
-	}
-
-	for _, m := range migrations {
-		_, err = db.Exec(m)
-		assert.NoError(t, err)
-	}
-
-	return db, func() {
-		db.Close()
-		container.Terminate(ctx)
-	}
-}
-
 // --- Helper ---
-func getBalance(t *testing.T, db *sqlx.DB, userID uuid.UUID, currency string) float64 {
-	var balance float64
+func getBalance(t *testing.T, db *sqlx.DB, userID uuid.UUID, currency string) money.Amount {
+	var balance money.Amount
 	err := db.Get(&balance, `SELECT balance FROM wallets WHERE user_id=$1 AND currency=$2`, userID, currency)
 	assert.NoError(t, err)
 	return balance
@@ -90,77 +26,104 @@ func getBalance(t *testing.T, db *sqlx.DB, userID uuid.UUID, currency string) fl
 
 // --- Deposit Tests ---
 func TestSaveDeposit(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+	db := testkit.Postgres(t)
 	ctx := context.Background()
 
-	userID := uuid.New()
-	_, err := db.Exec(`INSERT INTO users (user_id, username, email, password_hash) VALUES ($1, $2, $3, $4)`,
-		userID, "alice", "alice@example.com", "password123")
-	assert.NoError(t, err)
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
 
 	writer := NewWalletWriterRepository(db, nil)
 
-	err = writer.SaveDeposit(ctx, userID, 100, "USD")
+	err := writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("100"), getBalance(t, db, userID, "USD"))
+
+	err = writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("50"), "USD")
 	assert.NoError(t, err)
-	assert.Equal(t, 100.0, getBalance(t, db, userID, "USD"))
+	assert.Equal(t, money.MustParse("150"), getBalance(t, db, userID, "USD"))
 
-	err = writer.SaveDeposit(ctx, userID, 50, "USD")
+	var events int
+	err = db.Get(&events, `SELECT COUNT(*) FROM wallet_events WHERE user_id=$1 AND operation='deposit'`, userID)
 	assert.NoError(t, err)
-	assert.Equal(t, 150.0, getBalance(t, db, userID, "USD"))
+	assert.Equal(t, 2, events)
 }
 
 // --- Withdraw Tests ---
 func TestSaveWithdraw(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+	db := testkit.Postgres(t)
 	ctx := context.Background()
 
-	userID := uuid.New()
-	_, err := db.Exec(`INSERT INTO users (user_id, username, email, password_hash) VALUES ($1, $2, $3, $4)`,
-		userID, "bob", "bob@example.com", "password123")
-	assert.NoError(t, err)
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("bob")).UserID
 
 	writer := NewWalletWriterRepository(db, nil)
 
 	// Deposit first
-	err = writer.SaveDeposit(ctx, userID, 200, "USD")
+	err := writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("200"), "USD")
+	assert.NoError(t, err)
+
+	err = writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("80"), "USD")
 	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("120"), getBalance(t, db, userID, "USD"))
 
-	err = writer.SaveWithdraw(ctx, userID, 80, "USD")
+	err = writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("50"), "USD")
 	assert.NoError(t, err)
-	assert.Equal(t, 120.0, getBalance(t, db, userID, "USD"))
+	assert.Equal(t, money.MustParse("70"), getBalance(t, db, userID, "USD"))
+
+	err = writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("100"), "USD")
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Equal(t, money.MustParse("70"), getBalance(t, db, userID, "USD"))
+}
+
+func TestSaveWithdrawOverdraft(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
 
-	err = writer.SaveWithdraw(ctx, userID, 50, "USD")
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("dave")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	limits := NewWalletLimitRepository(db)
+
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("50"), "USD"))
+	assert.NoError(t, limits.SetOverdraft(ctx, userID, "USD", money.MustParse("100")))
+
+	err := writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("120"), "USD")
 	assert.NoError(t, err)
-	assert.Equal(t, 70.0, getBalance(t, db, userID, "USD"))
+	assert.Equal(t, money.MustParse("-70"), getBalance(t, db, userID, "USD"))
 
-	err = writer.SaveWithdraw(ctx, userID, 100, "USD")
+	// The balance never goes below -overdraft_limit
+	err = writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("30.01"), "USD")
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.NoError(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("30"), "USD"))
+	assert.Equal(t, money.MustParse("-100"), getBalance(t, db, userID, "USD"))
+
+	// An overdrawn wallet is not closed
+	_, _, err = writer.Close(ctx, uuid.New(), userID, "USD", "EUR", 0.9)
 	assert.ErrorIs(t, err, sql.ErrNoRows)
-	assert.Equal(t, 70.0, getBalance(t, db, userID, "USD"))
+
+	overdrafts, err := NewWalletReaderRepository(db).GetOverdraftLimits(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]money.Amount{"USD": money.MustParse("100")}, overdrafts)
+
+	assert.ErrorIs(t, limits.SetOverdraft(ctx, userID, "EUR", money.MustParse("10")), sql.ErrNoRows)
 }
 
 // --- Concurrency Tests ---
 func TestSaveDepositConcurrency(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+	db := testkit.Postgres(t)
 	ctx := context.Background()
 
-	userID := uuid.New()
-	_, _ = db.Exec(`INSERT INTO users (user_id, username, email, password_hash) VALUES ($1, $2, $3, $4)`,
-		userID, "concurrent", "concurrent@example.com", "pass")
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("concurrent")).UserID
 
 	writer := NewWalletWriterRepository(db, nil)
 
 	const numGoroutines = 1000
-	const amount = 1.0
+	amount := money.MustParse("1")
 	var wg sync.WaitGroup
 	wg.Add(numGoroutines)
 
 	for i := 0; i < numGoroutines; i++ {
 		go func() {
 			defer wg.Done()
-			err := writer.SaveDeposit(ctx, userID, amount, "USD")
+			err := writer.SaveDeposit(ctx, uuid.New(), userID, amount, "USD")
 			if err != nil {
 				t.Errorf("SaveDeposit failed: %v", err)
 			}
@@ -168,71 +131,286 @@ func TestSaveDepositConcurrency(t *testing.T) {
 	}
 	wg.Wait()
 
-	assert.Equal(t, float64(numGoroutines)*amount, getBalance(t, db, userID, "USD"))
+	assert.Equal(t, money.Amount(numGoroutines)*amount, getBalance(t, db, userID, "USD"))
 }
 
 func TestSaveWithdrawConcurrency(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+	db := testkit.Postgres(t)
 	ctx := context.Background()
 
-	userID := uuid.New()
-	initial := 1000.0
-	_, _ = db.Exec(`INSERT INTO users (user_id, username, email, password_hash) VALUES ($1, $2, $3, $4)`,
-		userID, "concurrent2", "concurrent2@example.com", "pass")
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("concurrent2")).UserID
+	initial := money.MustParse("1000")
 
 	writer := NewWalletWriterRepository(db, nil)
 
 	// Deposit first
-	err := writer.SaveDeposit(ctx, userID, initial, "USD")
+	err := writer.SaveDeposit(ctx, uuid.New(), userID, initial, "USD")
 	assert.NoError(t, err)
 
 	const numGoroutines = 1000
-	const amount = 1.0
+	amount := money.MustParse("1")
 	var wg sync.WaitGroup
 	wg.Add(numGoroutines)
 
 	for i := 0; i < numGoroutines; i++ {
 		go func() {
 			defer wg.Done()
-			err := writer.SaveWithdraw(ctx, userID, amount, "USD")
-			if err != nil && err != sql.ErrNoRows {
+			err := writer.SaveWithdraw(ctx, uuid.New(), userID, amount, "USD")
+			if err != nil && !errors.Is(err, ErrInsufficientFunds) {
 				t.Errorf("SaveWithdraw failed: %v", err)
 			}
 		}()
 	}
 	wg.Wait()
 
-	assert.Equal(t, initial-float64(numGoroutines)*amount, getBalance(t, db, userID, "USD"))
+	assert.Equal(t, initial-money.Amount(numGoroutines)*amount, getBalance(t, db, userID, "USD"))
 }
 
-// --- WalletReaderRepository Tests ---
-func TestWalletReaderRepository_GetByUserID(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+// --- Exchange Tests ---
+func TestSaveExchange(t *testing.T) {
+	db := testkit.Postgres(t)
 	ctx := context.Background()
 
-	userID := uuid.New()
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("dave")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), "USD"))
+
+	t.Run("both legs in one transaction", func(t *testing.T) {
+		txnID := uuid.New()
+		err := writer.SaveExchange(ctx, txnID, userID, "USD", money.MustParse("40"), money.Zero, "EUR", money.MustParse("36"))
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("60"), getBalance(t, db, userID, "USD"))
+		assert.Equal(t, money.MustParse("36"), getBalance(t, db, userID, "EUR"))
+
+		entries, err := NewLedgerRepository(db).Entries(ctx, txnID)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 4) {
+			assert.Equal(t, models.LedgerAccountWallet, entries[0].Account)
+			assert.Equal(t, money.MustParse("-40"), entries[0].Amount)
+			assert.Equal(t, models.LedgerAccountExchange, entries[1].Account)
+			assert.Equal(t, money.MustParse("40"), entries[1].Amount)
+			assert.Equal(t, models.LedgerAccountExchange, entries[2].Account)
+			assert.Equal(t, money.MustParse("-36"), entries[2].Amount)
+			assert.Equal(t, models.LedgerAccountWallet, entries[3].Account)
+			assert.Equal(t, "EUR", entries[3].Currency)
+			assert.Equal(t, money.MustParse("36"), entries[3].Amount)
+		}
+	})
+
+	t.Run("fee posted as a separate entry", func(t *testing.T) {
+		txnID := uuid.New()
+		err := writer.SaveExchange(ctx, txnID, userID, "USD", money.MustParse("10"), money.MustParse("0.50"), "EUR", money.MustParse("8.55"))
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("50"), getBalance(t, db, userID, "USD"))
+		assert.Equal(t, money.MustParse("44.55"), getBalance(t, db, userID, "EUR"))
+
+		entries, err := NewLedgerRepository(db).Entries(ctx, txnID)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 5) {
+			assert.Equal(t, money.MustParse("-10"), entries[0].Amount)
+			assert.Equal(t, models.LedgerAccountExchange, entries[1].Account)
+			assert.Equal(t, money.MustParse("9.50"), entries[1].Amount)
+			assert.Equal(t, models.LedgerAccountFee, entries[2].Account)
+			assert.Equal(t, "USD", entries[2].Currency)
+			assert.Equal(t, money.MustParse("0.50"), entries[2].Amount)
+		}
+	})
+
+	t.Run("insufficient funds change nothing", func(t *testing.T) {
+		txnID := uuid.New()
+		err := writer.SaveExchange(ctx, txnID, userID, "USD", money.MustParse("50.01"), money.Zero, "RUB", money.MustParse("5000"))
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+		assert.Equal(t, money.MustParse("50"), getBalance(t, db, userID, "USD"))
+
+		var wallets int
+		assert.NoError(t, db.Get(&wallets, `SELECT COUNT(*) FROM wallets WHERE user_id=$1 AND currency='RUB'`, userID))
+		assert.Equal(t, 0, wallets)
+
+		entries, err := NewLedgerRepository(db).Entries(ctx, txnID)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
 
-	// Insert user
-	_, err := db.Exec(`INSERT INTO users (user_id, username, email, password_hash) VALUES ($1, $2, $3, $4)`,
-		userID, "alice", "alice@example.com", "password123")
+// --- Create Tests ---
+func TestWalletCreate(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("dave")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), "USD"))
+
+	created, err := writer.Create(ctx, userID, []string{"USD", "EUR", "RUB"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "RUB"}, created)
+	assert.Equal(t, money.MustParse("100"), getBalance(t, db, userID, "USD"))
+	assert.Equal(t, money.Zero, getBalance(t, db, userID, "EUR"))
+
+	var events []models.WalletEventDB
+	err = db.Select(&events, `SELECT event_id, user_id, currency, operation, amount, balance, created_at
+		FROM wallet_events WHERE user_id=$1 AND operation=$2 ORDER BY currency`, userID, models.OperationOpen)
 	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "EUR", events[0].Currency)
+		assert.Equal(t, money.Zero, events[0].Balance)
+	}
+
+	created, err = writer.Create(ctx, userID, []string{"EUR"})
+	assert.NoError(t, err)
+	assert.Empty(t, created)
+}
+
+// --- WalletReaderRepository Tests ---
+// --- Close Tests ---
+func TestWalletClose(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("carol")).UserID
+
+	writer := NewWalletWriterRepository(db, nil)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("100"), "USD"))
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("10"), "EUR"))
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), userID, money.MustParse("30"), "RUB"))
+
+	countWallets := func(currency string) int {
+		var n int
+		err := db.Get(&n, `SELECT COUNT(*) FROM wallets WHERE user_id=$1 AND currency=$2`, userID, currency)
+		assert.NoError(t, err)
+		return n
+	}
+
+	t.Run("non-empty wallet requires a target currency", func(t *testing.T) {
+		_, _, err := writer.Close(ctx, uuid.New(), userID, "RUB", "", 0)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Equal(t, 1, countWallets("RUB"))
+	})
+
+	t.Run("payout to existing wallet", func(t *testing.T) {
+		balance, credited, err := writer.Close(ctx, uuid.New(), userID, "USD", "EUR", 0.9)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("100"), balance)
+		assert.Equal(t, money.MustParse("90"), credited)
+		assert.Equal(t, 0, countWallets("USD"))
+		assert.Equal(t, money.MustParse("100"), getBalance(t, db, userID, "EUR"))
+
+		var events []models.WalletEventDB
+		err = db.Select(&events, `SELECT event_id, user_id, currency, operation, amount, balance, created_at
+			FROM wallet_events WHERE user_id=$1 ORDER BY event_id DESC LIMIT 2`, userID)
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, "EUR", events[0].Currency)
+			assert.Equal(t, models.OperationDeposit, events[0].Operation)
+			assert.Equal(t, money.MustParse("90"), events[0].Amount)
+			assert.Equal(t, "USD", events[1].Currency)
+			assert.Equal(t, models.OperationWithdraw, events[1].Operation)
+			assert.Equal(t, money.Zero, events[1].Balance)
+		}
+	})
+
+	t.Run("empty wallet without target", func(t *testing.T) {
+		assert.NoError(t, writer.SaveWithdraw(ctx, uuid.New(), userID, money.MustParse("30"), "RUB"))
+
+		balance, credited, err := writer.Close(ctx, uuid.New(), userID, "RUB", "", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, money.Zero, balance)
+		assert.Equal(t, money.Zero, credited)
+		assert.Equal(t, 0, countWallets("RUB"))
+	})
+
+	t.Run("missing wallet", func(t *testing.T) {
+		_, _, err := writer.Close(ctx, uuid.New(), userID, "USD", "EUR", 0.9)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
+
+func TestWalletWriterRepository_Lock(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	alice := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
+	bob := testkit.CreateUser(t, db, testkit.WithUsername("bob")).UserID
+
+	writer := NewWalletWriterRepository(db, TxFromContext)
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), alice, money.MustParse("100"), "USD"))
+	assert.NoError(t, writer.SaveDeposit(ctx, uuid.New(), bob, money.MustParse("100"), "USD"))
+
+	wallets := []models.WalletKey{{UserID: bob, Currency: "USD"}, {UserID: alice, Currency: "USD"}, {UserID: alice, Currency: "EUR"}}
+
+	t.Run("outside of a transaction", func(t *testing.T) {
+		assert.ErrorIs(t, writer.Lock(ctx, wallets), ErrNoTransaction)
+	})
+
+	t.Run("locks until the transaction ends", func(t *testing.T) {
+		err := NewTxRunner(db).InTx(ctx, func(ctx context.Context) error {
+			if err := writer.Lock(ctx, wallets); err != nil {
+				return err
+			}
+
+			for _, userID := range []uuid.UUID{alice, bob} {
+				var walletID uuid.UUID
+				err := db.Get(&walletID, `SELECT wallet_id FROM wallets WHERE user_id=$1 AND currency='USD' FOR UPDATE NOWAIT`, userID)
+				assert.Error(t, err)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+
+		// Released on commit
+		assert.NoError(t, writer.SaveWithdraw(ctx, uuid.New(), alice, money.MustParse("10"), "USD"))
+	})
+
+	t.Run("opposite orders do not deadlock", func(t *testing.T) {
+		runner := NewTxRunner(db)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			from, to := alice, bob
+			if i%2 == 1 {
+				from, to = bob, alice
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := runner.InTx(ctx, func(ctx context.Context) error {
+					if err := writer.Lock(ctx, []models.WalletKey{{UserID: from, Currency: "USD"}, {UserID: to, Currency: "USD"}}); err != nil {
+						return err
+					}
+					if err := writer.SaveWithdraw(ctx, uuid.New(), from, money.MustParse("1"), "USD"); err != nil {
+						return err
+					}
+					return writer.SaveDeposit(ctx, uuid.New(), to, money.MustParse("1"), "USD")
+				})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, money.MustParse("90"), getBalance(t, db, alice, "USD"))
+		assert.Equal(t, money.MustParse("100"), getBalance(t, db, bob, "USD"))
+	})
+}
+
+func TestWalletReaderRepository_GetByUserID(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+
+	userID := testkit.CreateUser(t, db, testkit.WithUsername("alice")).UserID
 
 	// Insert wallets
 	walletsData := []struct {
 		currency string
-		balance  float64
+		balance  money.Amount
 	}{
-		{"USD", 100.0},
-		{"EUR", 50.0},
-		{"RUB", 5000.0},
+		{"USD", money.MustParse("100")},
+		{"EUR", money.MustParse("50")},
+		{"RUB", money.MustParse("5000")},
 	}
 
 	for _, w := range walletsData {
-		_, err := db.Exec(`INSERT INTO wallets (user_id, currency, balance) VALUES ($1, $2, $3)`,
-			userID, w.currency, w.balance)
-		assert.NoError(t, err)
+		testkit.CreateWallet(t, db, userID, w.currency, w.balance)
 	}
 
 	reader := NewWalletReaderRepository(db)
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// WebhookRepository stores webhooks and the deliveries of wallet events to them.
+// A webhook without an owner is an admin webhook receiving events of all users.
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create registers a webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook models.WebhookDB) error {
+	const query = `
+		INSERT INTO webhooks (webhook_id, user_id, url, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	args := []any{webhook.WebhookID, webhook.UserID, webhook.URL, webhook.Secret, webhook.CreatedAt}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line; the secret is left out
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", []any{webhook.WebhookID, webhook.UserID, webhook.URL},
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// List returns the webhooks of the owner, or the admin webhooks if ownerID is nil, oldest first
+func (r *WebhookRepository) List(ctx context.Context, ownerID *uuid.UUID) ([]models.WebhookDB, error) {
+	const query = `
+		SELECT webhook_id, user_id, url, secret, created_at
+		FROM webhooks
+		WHERE user_id IS NOT DISTINCT FROM $1::UUID
+		ORDER BY created_at, webhook_id
+	`
+	args := []any{ownerID}
+
+	webhooks := []models.WebhookDB{}
+	err := r.db.SelectContext(ctx, &webhooks, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(webhooks),
+		"error", err,
+	)
+
+	return webhooks, err
+}
+
+// Delete removes a webhook of the owner together with its pending deliveries.
+// Returns sql.ErrNoRows if the owner has no such webhook.
+func (r *WebhookRepository) Delete(ctx context.Context, ownerID *uuid.UUID, webhookID uuid.UUID) error {
+	const query = `
+		DELETE FROM webhooks
+		WHERE webhook_id = $1 AND user_id IS NOT DISTINCT FROM $2::UUID
+		RETURNING webhook_id
+	`
+	args := []any{webhookID, ownerID}
+
+	var deleted uuid.UUID
+	err := r.db.GetContext(ctx, &deleted, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", deleted,
+		"error", err,
+	)
+
+	return err
+}
+
+// Enqueue queues an event of the user for every webhook of the user and every admin webhook.
+// Returns the number of queued deliveries.
+func (r *WebhookRepository) Enqueue(ctx context.Context, userID uuid.UUID, eventType string, payload []byte) (int64, error) {
+	const query = `
+		INSERT INTO webhook_deliveries (delivery_id, webhook_id, event_type, payload)
+		SELECT gen_random_uuid(), webhook_id, $2, $3
+		FROM webhooks
+		WHERE user_id = $1 OR user_id IS NULL
+	`
+	args := []any{userID, eventType, string(payload)}
+
+	var queued int64
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		queued, err = res.RowsAffected()
+	}
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", queued,
+		"error", err,
+	)
+
+	return queued, err
+}
+
+// ClaimDue claims up to limit pending deliveries whose next attempt is due, oldest first.
+// Their next attempt is postponed by lease, so concurrent workers skip them while they are
+// being sent; a delivery left by a worker that stopped is due again once the lease is over.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDeliveryDB, error) {
+	const query = `
+		WITH due AS (
+			SELECT delivery_id, next_attempt_at AS due_at
+			FROM webhook_deliveries
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = NOW() + make_interval(secs => $2)
+			FROM due
+			WHERE d.delivery_id = due.delivery_id
+			RETURNING d.delivery_id, d.webhook_id, d.event_type, d.payload, d.attempts,
+				d.next_attempt_at, d.last_error, due.due_at
+		)
+		SELECT c.delivery_id, c.webhook_id, w.url, w.secret, c.event_type, c.payload,
+			c.attempts, c.next_attempt_at, c.last_error
+		FROM claimed c
+		JOIN webhooks w ON w.webhook_id = c.webhook_id
+		ORDER BY c.due_at
+	`
+	args := []any{limit, lease.Seconds()}
+
+	var deliveries []models.WebhookDeliveryDB
+	err := r.db.SelectContext(ctx, &deliveries, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", len(deliveries),
+		"error", err,
+	)
+
+	return deliveries, err
+}
+
+// MarkDelivered records the delivery
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, deliveryID uuid.UUID) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, delivered_at = NOW(), last_error = NULL
+		WHERE delivery_id = $1
+	`
+	args := []any{deliveryID}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// MarkFailed records a failed delivery and postpones the next attempt to nextAttemptAt
+func (r *WebhookRepository) MarkFailed(ctx context.Context, deliveryID uuid.UUID, nextAttemptAt time.Time, reason string) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+		WHERE delivery_id = $1
+	`
+	args := []any{deliveryID, nextAttemptAt, reason}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
+
+// MarkAbandoned records the last failed delivery and gives the delivery up
+func (r *WebhookRepository) MarkAbandoned(ctx context.Context, deliveryID uuid.UUID, reason string) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, failed_at = NOW(), last_error = $2
+		WHERE delivery_id = $1
+	`
+	args := []any{deliveryID, reason}
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	// Log with query in single line
+	logger.FromContext(ctx).Infow(
+		"query", strings.Join(strings.Fields(query), " "),
+		"args", args,
+		"result", nil,
+		"error", err,
+	)
+
+	return err
+}
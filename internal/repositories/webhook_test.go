@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/testkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	db := testkit.Postgres(t)
+	ctx := context.Background()
+	repo := NewWebhookRepository(db)
+
+	alice := testkit.CreateUser(t, db, testkit.WithUsername("webhook-alice"))
+	bob := testkit.CreateUser(t, db, testkit.WithUsername("webhook-bob"))
+
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	userHook := models.WebhookDB{WebhookID: uuid.New(), UserID: &alice.UserID, URL: "https://alice.example/hook", Secret: "s1", CreatedAt: createdAt}
+	adminHook := models.WebhookDB{WebhookID: uuid.New(), URL: "https://ops.example/hook", Secret: "s2", CreatedAt: createdAt}
+	assert.NoError(t, repo.Create(ctx, userHook))
+	assert.NoError(t, repo.Create(ctx, adminHook))
+
+	// Owners see only their own webhooks, admins only the global ones
+	hooks, err := repo.List(ctx, &alice.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WebhookDB{userHook}, hooks)
+	hooks, err = repo.List(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WebhookDB{adminHook}, hooks)
+	hooks, err = repo.List(ctx, &bob.UserID)
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	// Events of a user go to the user's webhooks and the admin webhooks
+	queued, err := repo.Enqueue(ctx, alice.UserID, models.WebhookEventDeposit, []byte(`{"type":"wallet.deposit"}`))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, queued)
+	queued, err = repo.Enqueue(ctx, bob.UserID, models.WebhookEventWithdraw, []byte(`{"type":"wallet.withdraw"}`))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, queued)
+
+	due, err := repo.ClaimDue(ctx, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, due, 3)
+
+	// Claimed deliveries are not handed to another worker during their lease
+	claimed, err := repo.ClaimDue(ctx, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	var delivery models.WebhookDeliveryDB
+	for _, d := range due {
+		if d.WebhookID == userHook.WebhookID {
+			delivery = d
+		}
+	}
+	assert.Equal(t, userHook.URL, delivery.URL)
+	assert.Equal(t, userHook.Secret, delivery.Secret)
+	assert.Equal(t, models.WebhookEventDeposit, delivery.EventType)
+	assert.JSONEq(t, `{"type":"wallet.deposit"}`, delivery.Payload)
+
+	// A failed delivery is retried once the next attempt is due; the others, left by a
+	// stopped worker, are claimed again once their lease is over
+	assert.NoError(t, repo.MarkFailed(ctx, delivery.DeliveryID, time.Now().Add(time.Hour), "503 Service Unavailable"))
+	_, err = db.ExecContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = NOW() - INTERVAL '1 second' WHERE delivery_id <> $1`, delivery.DeliveryID)
+	assert.NoError(t, err)
+	due, err = repo.ClaimDue(ctx, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, due, 2)
+
+	// Delivered and abandoned deliveries are not attempted again
+	for _, d := range due {
+		if d.WebhookID == adminHook.WebhookID && d.EventType == models.WebhookEventDeposit {
+			assert.NoError(t, repo.MarkDelivered(ctx, d.DeliveryID))
+		} else {
+			assert.NoError(t, repo.MarkAbandoned(ctx, d.DeliveryID, "connection refused"))
+		}
+	}
+	_, err = db.ExecContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = NOW() - INTERVAL '1 second'`)
+	assert.NoError(t, err)
+	due, err = repo.ClaimDue(ctx, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, delivery.DeliveryID, due[0].DeliveryID)
+
+	// Only the owner deletes a webhook; pending deliveries go with it
+	assert.ErrorIs(t, repo.Delete(ctx, &bob.UserID, userHook.WebhookID), sql.ErrNoRows)
+	assert.ErrorIs(t, repo.Delete(ctx, nil, userHook.WebhookID), sql.ErrNoRows)
+	assert.NoError(t, repo.Delete(ctx, &alice.UserID, userHook.WebhookID))
+	assert.NoError(t, repo.Delete(ctx, nil, adminHook.WebhookID))
+
+	var left int
+	assert.NoError(t, db.GetContext(ctx, &left, `SELECT COUNT(*) FROM webhook_deliveries`))
+	assert.Zero(t, left)
+}
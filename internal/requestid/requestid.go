@@ -0,0 +1,84 @@
+// Package requestid carries the ID of the request being served through the context, so that
+// logs, audit records, calls to the exchanger and published events can be correlated.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	Header      = "X-Request-ID" // HTTP header of the ID, on requests and responses
+	MetadataKey = "x-request-id" // gRPC metadata key of the ID
+	KafkaHeader = "request_id"   // Kafka message header of the ID
+)
+
+// MaxLength is the longest request ID accepted from a client.
+const MaxLength = 128
+
+// key is the context key of the request ID.
+type key struct{}
+
+// New returns a new request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether id, received from a client, can be used as the request ID: 1 to
+// MaxLength printable ASCII characters other than space, so it cannot forge log lines.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, key{}, id)
+}
+
+// FromContext returns the request ID of ctx, or "" outside of a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(key{}).(string)
+	return id
+}
+
+// KafkaHeaders returns the header carrying the request ID of ctx, or nil outside of a request.
+func KafkaHeaders(ctx context.Context) []kafka.Header {
+	id := FromContext(ctx)
+	if id == "" {
+		return nil
+	}
+	return []kafka.Header{{Key: KafkaHeader, Value: []byte(id)}}
+}
+
+// UnaryClientInterceptor sends the request ID of the context as metadata of every call.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := FromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// UnaryServerInterceptor stores the request ID of every call in its context: the one of the
+// context for calls made in process, otherwise the one sent as metadata, or a new one.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if FromContext(ctx) != "" {
+		return handler(ctx, req)
+	}
+	id := New()
+	if ids := metadata.ValueFromIncomingContext(ctx, MetadataKey); len(ids) > 0 && Valid(ids[0]) {
+		id = ids[0]
+	}
+	return handler(NewContext(ctx, id), req)
+}
@@ -0,0 +1,102 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{id: "3fa85f64-5717-4562-b3fc-2c963f66afa6", valid: true},
+		{id: "req_1:a/b", valid: true},
+		{id: strings.Repeat("a", MaxLength), valid: true},
+		{id: ""},
+		{id: strings.Repeat("a", MaxLength+1)},
+		{id: "req 1"},
+		{id: "req\nlevel=error"},
+		{id: "req\x7f"},
+		{id: "запрос"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, Valid(tt.id), "%q", tt.id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Nil(t, KafkaHeaders(context.Background()))
+
+	ctx := NewContext(context.Background(), "req-1")
+	assert.Equal(t, "req-1", FromContext(ctx))
+	if headers := KafkaHeaders(ctx); assert.Len(t, headers, 1) {
+		assert.Equal(t, KafkaHeader, headers[0].Key)
+		assert.Equal(t, "req-1", string(headers[0].Value))
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var md metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	assert.NoError(t, UnaryClientInterceptor(NewContext(context.Background(), "req-1"), "/m", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"req-1"}, md.Get(MetadataKey))
+
+	assert.NoError(t, UnaryClientInterceptor(context.Background(), "/m", nil, nil, nil, invoker))
+	assert.Empty(t, md.Get(MetadataKey))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string // "" for a new ID
+	}{
+		{
+			name: "ID of an in-process call is kept",
+			ctx:  NewContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req-2")), "req-1"),
+			want: "req-1",
+		},
+		{
+			name: "ID from metadata",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req-2")),
+			want: "req-2",
+		},
+		{
+			name: "invalid ID from metadata is replaced",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req 2")),
+		},
+		{
+			name: "without metadata",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			_, err := UnaryServerInterceptor(tt.ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				got = FromContext(ctx)
+				return nil, nil
+			})
+			assert.NoError(t, err)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, got)
+			} else {
+				assert.True(t, Valid(got))
+				assert.NotEqual(t, "req 2", got)
+			}
+		})
+	}
+}
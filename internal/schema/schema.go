@@ -0,0 +1,176 @@
+// Package schema derives the expected database schema from the SQL migrations and
+// reports how a live schema drifts from it.
+package schema
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Schema describes the tables, columns and indexes of a database.
+type Schema struct {
+	Columns map[string][]string // Column names by table
+	Indexes map[string]string   // Table by index name
+}
+
+var (
+	upSection    = regexp.MustCompile(`(?is)--\s*\+goose\s+Up(.*?)(?:--\s*\+goose\s+Down|$)`)
+	lineComment  = regexp.MustCompile(`--[^\n]*`)
+	createTable  = regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)\s*\((.*?)\)(?:\s*PARTITION\s+BY\s+\w+\s*\([^)]*\))?$`)
+	dropTable    = regexp.MustCompile(`(?i)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(\w+)`)
+	renameTable  = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(\w+)\s+RENAME\s+TO\s+(\w+)$`)
+	addColumn    = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(\w+)\s+ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	dropColumn   = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(\w+)\s+DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?(\w+)`)
+	createIndex  = regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(\w+)\s+ON\s+(?:ONLY\s+)?(\w+)`)
+	dropIndex    = regexp.MustCompile(`(?i)^DROP\s+INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+EXISTS\s+)?(\w+)`)
+	constraintKw = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE"}
+)
+
+// FromMigrations replays the Up sections of the *.sql migrations in fsys in file name
+// order and returns the schema they produce. Only named indexes are tracked; table
+// constraints and the indexes backing them are left to PostgreSQL.
+func FromMigrations(fsys fs.FS) (Schema, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return Schema{}, err
+	}
+	slices.Sort(names)
+
+	s := Schema{Columns: map[string][]string{}, Indexes: map[string]string{}}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return Schema{}, err
+		}
+		m := upSection.FindStringSubmatch(string(data))
+		if m == nil {
+			return Schema{}, fmt.Errorf("migration %s: no goose Up section", path.Base(name))
+		}
+		for _, stmt := range strings.Split(lineComment.ReplaceAllString(m[1], ""), ";") {
+			s.apply(strings.TrimSpace(stmt))
+		}
+	}
+	return s, nil
+}
+
+// apply updates the schema with a single statement. Statements that do not
+// change tables, columns or indexes are ignored.
+func (s Schema) apply(stmt string) {
+	if m := createTable.FindStringSubmatch(stmt); m != nil {
+		table := strings.ToLower(m[1])
+		if _, ok := s.Columns[table]; ok {
+			return
+		}
+		columns := []string{}
+		for _, def := range splitTopLevel(m[2]) {
+			fields := strings.Fields(def)
+			if len(fields) == 0 || slices.Contains(constraintKw, strings.ToUpper(fields[0])) {
+				continue
+			}
+			columns = append(columns, strings.ToLower(fields[0]))
+		}
+		s.Columns[table] = columns
+		return
+	}
+	if m := dropTable.FindStringSubmatch(stmt); m != nil {
+		table := strings.ToLower(m[1])
+		delete(s.Columns, table)
+		for index, t := range s.Indexes {
+			if t == table {
+				delete(s.Indexes, index)
+			}
+		}
+		return
+	}
+	if m := renameTable.FindStringSubmatch(stmt); m != nil {
+		from, to := strings.ToLower(m[1]), strings.ToLower(m[2])
+		if columns, ok := s.Columns[from]; ok {
+			delete(s.Columns, from)
+			s.Columns[to] = columns
+		}
+		// Indexes keep their names
+		for index, t := range s.Indexes {
+			if t == from {
+				s.Indexes[index] = to
+			}
+		}
+		return
+	}
+	if m := addColumn.FindStringSubmatch(stmt); m != nil {
+		table, column := strings.ToLower(m[1]), strings.ToLower(m[2])
+		if slices.Contains(constraintKw, strings.ToUpper(column)) {
+			return
+		}
+		if !slices.Contains(s.Columns[table], column) {
+			s.Columns[table] = append(s.Columns[table], column)
+		}
+		return
+	}
+	if m := dropColumn.FindStringSubmatch(stmt); m != nil {
+		table, column := strings.ToLower(m[1]), strings.ToLower(m[2])
+		if strings.EqualFold(column, "CONSTRAINT") {
+			return
+		}
+		s.Columns[table] = slices.DeleteFunc(s.Columns[table], func(c string) bool { return c == column })
+		return
+	}
+	if m := createIndex.FindStringSubmatch(stmt); m != nil {
+		s.Indexes[strings.ToLower(m[1])] = strings.ToLower(m[2])
+		return
+	}
+	if m := dropIndex.FindStringSubmatch(stmt); m != nil {
+		delete(s.Indexes, strings.ToLower(m[1]))
+	}
+}
+
+// splitTopLevel splits a table body on the commas outside parentheses.
+func splitTopLevel(body string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range body {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, body[start:])
+}
+
+// Drift returns the tables, columns and indexes of expected that are missing from live,
+// sorted for stable reporting. Objects only present in live are not reported.
+func Drift(expected, live Schema) []string {
+	drift := []string{}
+	for table, columns := range expected.Columns {
+		liveColumns, ok := live.Columns[table]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("missing table %s", table))
+			continue
+		}
+		for _, column := range columns {
+			if !slices.Contains(liveColumns, column) {
+				drift = append(drift, fmt.Sprintf("missing column %s.%s", table, column))
+			}
+		}
+	}
+	for index, table := range expected.Indexes {
+		if _, ok := live.Indexes[index]; !ok {
+			drift = append(drift, fmt.Sprintf("missing index %s on %s", index, table))
+		}
+	}
+	slices.Sort(drift)
+	return drift
+}
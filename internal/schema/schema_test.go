@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/sbilibin2017/gw-currency-wallet/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_users.sql": {Data: []byte(`-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    user_id UUID PRIMARY KEY,
+    email VARCHAR(100) NOT NULL, -- login, unique
+    balance NUMERIC(20, 2) NOT NULL DEFAULT 0.0,
+    UNIQUE (email),
+    CONSTRAINT users_balance_check CHECK (balance >= 0)
+);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+-- +goose Down
+DROP TABLE IF EXISTS users;
+`)},
+		"000002_alter.sql": {Data: []byte(`-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role <> '');
+ALTER TABLE users DROP COLUMN IF EXISTS balance;
+CREATE TABLE old (id INT);
+CREATE UNIQUE INDEX idx_old_id ON old (id);
+DROP TABLE old;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE INDEX idx_users_role ON users (role);
+INSERT INTO users (user_id, email) VALUES ('00000000-0000-0000-0000-000000000000', 'a;b');
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+`)},
+		"000003_partition.sql": {Data: []byte(`-- +goose Up
+CREATE TABLE events (id BIGINT, created_at TIMESTAMP);
+CREATE INDEX idx_events_id ON events (id);
+ALTER TABLE events RENAME TO events_old;
+ALTER TABLE events_old RENAME CONSTRAINT events_pkey TO events_old_pkey;
+DROP INDEX idx_events_id;
+CREATE TABLE events (
+    id BIGINT NOT NULL,
+    kind TEXT,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+CREATE TABLE events_default PARTITION OF events DEFAULT;
+CREATE INDEX idx_events_kind ON events (kind);
+CREATE TABLE audit (id BIGINT);
+CREATE INDEX idx_audit_id ON audit (id);
+ALTER TABLE audit RENAME TO audit_log;
+DROP TABLE events_old;
+`)},
+	}
+
+	s, err := FromMigrations(fsys)
+	assert.NoError(t, err)
+	assert.Equal(t, Schema{
+		Columns: map[string][]string{
+			"users":     {"user_id", "email", "role"},
+			"events":    {"id", "kind", "created_at"},
+			"audit_log": {"id"},
+		},
+		Indexes: map[string]string{"idx_users_role": "users", "idx_events_kind": "events", "idx_audit_id": "audit_log"},
+	}, s)
+
+	_, err = FromMigrations(fstest.MapFS{"000001_bad.sql": {Data: []byte("CREATE TABLE t (id INT);")}})
+	assert.Error(t, err)
+}
+
+func TestFromMigrations_Embedded(t *testing.T) {
+	s, err := FromMigrations(migrations.Files)
+	assert.NoError(t, err)
+	assert.Contains(t, s.Columns["wallets"], "held")
+	assert.Contains(t, s.Columns["users"], "dormant_at")
+	assert.Equal(t, "wallet_holds", s.Indexes["idx_wallet_holds_user_id"])
+	assert.Contains(t, s.Columns["transactions"], "to_balance")
+	assert.Equal(t, "transactions", s.Indexes["idx_transactions_transaction_id"])
+	assert.NotContains(t, s.Columns, "transactions_unpartitioned")
+}
+
+func TestDrift(t *testing.T) {
+	expected := Schema{
+		Columns: map[string][]string{
+			"users":   {"user_id", "email", "role"},
+			"wallets": {"wallet_id", "held"},
+		},
+		Indexes: map[string]string{
+			"idx_users_role":   "users",
+			"idx_wallets_user": "wallets",
+		},
+	}
+
+	assert.Empty(t, Drift(expected, expected))
+
+	live := Schema{
+		Columns: map[string][]string{
+			"users":            {"user_id", "email", "legacy"},
+			"goose_db_version": {"id"},
+		},
+		Indexes: map[string]string{"idx_users_role": "users", "users_pkey": "users"},
+	}
+	assert.Equal(t, []string{
+		"missing column users.role",
+		"missing index idx_wallets_user on wallets",
+		"missing table wallets",
+	}, Drift(expected, live))
+}
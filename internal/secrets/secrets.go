@@ -0,0 +1,123 @@
+// Package secrets keeps the secrets of the service read from a secret manager, replacing
+// the configured ones, and refreshes them while the service runs, so that rotated
+// passwords are used by new connections.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// Keys of the secrets used by the service. Other keys of a source are ignored.
+const (
+	JWTSecretKey     = "jwt_secret_key"
+	PostgresPassword = "postgres_password"
+	RedisPassword    = "redis_password"
+)
+
+// ErrNoSecrets is returned when a source has none of the secrets of the service.
+var ErrNoSecrets = errors.New("secret source has none of jwt_secret_key, postgres_password, redis_password")
+
+// Source returns secrets by key, e.g. facades.VaultFacade.
+type Source interface {
+	Secrets(ctx context.Context) (map[string]string, error)
+}
+
+// Renewer is implemented by sources whose credentials expire unless renewed.
+type Renewer interface {
+	Renew(ctx context.Context) error
+}
+
+// Store keeps the secrets of a Source.
+type Store struct {
+	source Source
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewStore creates a Store of the secrets of source. Call Load before using it.
+func NewStore(source Source) *Store {
+	return &Store{source: source, values: map[string]string{}}
+}
+
+// Load reads the secrets of the source, keeping those of the service.
+func (s *Store) Load(ctx context.Context) error {
+	read, err := s.source.Secrets(ctx)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string, 3)
+	for _, key := range []string{JWTSecretKey, PostgresPassword, RedisPassword} {
+		if value, ok := read[key]; ok && value != "" {
+			values[key] = value
+		}
+	}
+	if len(values) == 0 {
+		return ErrNoSecrets
+	}
+
+	s.mu.Lock()
+	previous := s.values
+	s.values = values
+	s.mu.Unlock()
+
+	if len(previous) > 0 && !maps.Equal(previous, values) {
+		var changed []string
+		for key, value := range values {
+			if previous[key] != value {
+				changed = append(changed, key)
+			}
+		}
+		slices.Sort(changed)
+		logger.FromContext(ctx).Infow("secrets rotated", "keys", changed)
+	}
+	return nil
+}
+
+// Get returns the secret of key, or fallback if the source does not have it.
+func (s *Store) Get(key, fallback string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if value, ok := s.values[key]; ok {
+		return value
+	}
+	return fallback
+}
+
+// Func returns a function returning the current secret of key, or fallback.
+func (s *Store) Func(key, fallback string) func() string {
+	return func() string {
+		return s.Get(key, fallback)
+	}
+}
+
+// Run renews the credentials of the source and reloads the secrets every interval until ctx
+// is canceled. Failures are logged, keeping the secrets read last.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if renewer, ok := s.source.(Renewer); ok {
+			if err := renewer.Renew(ctx); err != nil {
+				logger.FromContext(ctx).Errorw("failed to renew secret source credentials", "error", err)
+			}
+		}
+		if err := s.Load(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Errorw("failed to reload secrets, keeping the current ones", "error", err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSource returns its values, counting renewals.
+type fakeSource struct {
+	values   map[string]string
+	err      error
+	renewals int
+}
+
+func (s *fakeSource) Secrets(context.Context) (map[string]string, error) {
+	return s.values, s.err
+}
+
+func (s *fakeSource) Renew(context.Context) error {
+	s.renewals++
+	return nil
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps the secrets of the service", func(t *testing.T) {
+		store := NewStore(&fakeSource{values: map[string]string{PostgresPassword: "pg", RedisPassword: "", "other": "x"}})
+		assert.NoError(t, store.Load(ctx))
+
+		assert.Equal(t, "pg", store.Get(PostgresPassword, "configured"))
+		assert.Equal(t, "configured", store.Get(RedisPassword, "configured"), "empty secrets are ignored")
+		assert.Equal(t, "configured", store.Get("other", "configured"))
+	})
+
+	t.Run("none of the secrets", func(t *testing.T) {
+		store := NewStore(&fakeSource{values: map[string]string{"other": "x"}})
+		assert.ErrorIs(t, store.Load(ctx), ErrNoSecrets)
+	})
+
+	t.Run("failed reload keeps the secrets", func(t *testing.T) {
+		source := &fakeSource{values: map[string]string{PostgresPassword: "pg"}}
+		store := NewStore(source)
+		assert.NoError(t, store.Load(ctx))
+		password := store.Func(PostgresPassword, "configured")
+
+		source.values, source.err = nil, errors.New("vault unavailable")
+		assert.Error(t, store.Load(ctx))
+		assert.Equal(t, "pg", password())
+
+		source.values, source.err = map[string]string{PostgresPassword: "rotated"}, nil
+		assert.NoError(t, store.Load(ctx))
+		assert.Equal(t, "rotated", password())
+	})
+
+	t.Run("run renews and reloads", func(t *testing.T) {
+		source := &fakeSource{values: map[string]string{PostgresPassword: "pg"}}
+		store := NewStore(source)
+		assert.NoError(t, store.Load(ctx))
+		source.values = map[string]string{PostgresPassword: "rotated"}
+
+		runCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		store.Run(runCtx, 5*time.Millisecond)
+
+		assert.Positive(t, source.renewals)
+		assert.Equal(t, "rotated", store.Get(PostgresPassword, ""))
+	})
+}
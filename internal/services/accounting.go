@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// Chart of accounts used in the accounting export (Russian chart of accounts, as used by 1C).
+const (
+	AccountRUBSettlement      = "51"    // Ruble settlement account
+	AccountCurrencySettlement = "52"    // Foreign currency account
+	AccountClientFunds        = "76.09" // Settlements with clients for wallet funds
+)
+
+// accountingCSVHeader lists the columns of the accounting export.
+var accountingCSVHeader = []string{"Date", "Document", "Debit", "Credit", "Amount", "Currency", "Description"}
+
+// settlementAccount returns the cash account a currency is held on.
+func settlementAccount(currency string) string {
+	if currency == models.RUB {
+		return AccountRUBSettlement
+	}
+	return AccountCurrencySettlement
+}
+
+// BuildAccountingCSV renders ledger entries as a semicolon-separated CSV with debit/credit
+// accounts, suitable for import into 1C. Amounts use a decimal comma.
+// A deposit debits the settlement account and credits client funds; a withdrawal does the reverse.
+// Opening an empty wallet moves no money and is left out.
+func BuildAccountingCSV(events []models.WalletEventDB) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	if err := w.Write(accountingCSVHeader); err != nil {
+		return nil, err
+	}
+
+	for _, e := range events {
+		if e.Operation == models.OperationOpen && e.Amount == money.Zero {
+			continue
+		}
+		var debit, credit, description string
+		switch e.Operation {
+		case models.OperationDeposit:
+			debit, credit = settlementAccount(e.Currency), AccountClientFunds
+			description = "Wallet deposit"
+		case models.OperationWithdraw:
+			debit, credit = AccountClientFunds, settlementAccount(e.Currency)
+			description = "Wallet withdrawal"
+		default:
+			return nil, fmt.Errorf("unsupported ledger operation %q in event %d", e.Operation, e.EventID)
+		}
+
+		record := []string{
+			e.CreatedAt.Format("02.01.2006 15:04:05"),
+			strconv.FormatInt(e.EventID, 10),
+			debit,
+			credit,
+			strings.Replace(e.Amount.String(), ".", ",", 1),
+			e.Currency,
+			fmt.Sprintf("%s, user %s", description, e.UserID),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
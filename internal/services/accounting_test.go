@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAccountingCSV(t *testing.T) {
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	at := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	events := []models.WalletEventDB{
+		{EventID: 0, UserID: userID, Currency: models.RUB, Operation: models.OperationOpen, Amount: money.Zero, CreatedAt: at},
+		{EventID: 1, UserID: userID, Currency: models.RUB, Operation: models.OperationDeposit, Amount: money.MustParse("1500.5"), CreatedAt: at},
+		{EventID: 2, UserID: userID, Currency: models.USD, Operation: models.OperationWithdraw, Amount: money.MustParse("20"), CreatedAt: at},
+	}
+
+	got, err := BuildAccountingCSV(events)
+	assert.NoError(t, err)
+
+	want := "Date;Document;Debit;Credit;Amount;Currency;Description\n" +
+		"14.03.2025 09:30:00;1;51;76.09;1500,50;RUB;Wallet deposit, user 11111111-1111-1111-1111-111111111111\n" +
+		"14.03.2025 09:30:00;2;76.09;52;20,00;USD;Wallet withdrawal, user 11111111-1111-1111-1111-111111111111\n"
+	assert.Equal(t, want, string(got))
+}
+
+func TestBuildAccountingCSV_UnknownOperation(t *testing.T) {
+	_, err := BuildAccountingCSV([]models.WalletEventDB{{EventID: 7, Operation: "refund"}})
+	assert.Error(t, err)
+}
@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
 	"golang.org/x/crypto/bcrypt"
@@ -29,73 +33,264 @@ type UserWriter interface {
 
 // JWTGenerator defines an interface for generating JWT tokens.
 type JWTGenerator interface {
-	Generate(ctx context.Context, userID uuid.UUID) (string, error)
+	Generate(ctx context.Context, userID uuid.UUID, opts ...jwt.ClaimOpt) (string, error)
+}
+
+// AuthEventWriter records authentication events.
+type AuthEventWriter interface {
+	Save(ctx context.Context, userID uuid.UUID, eventType string, client models.ClientInfo) error
+}
+
+// GeoLocator resolves the country of an IP address.
+type GeoLocator interface {
+	Country(ctx context.Context, ip string) string
+}
+
+// LoginAlerter inspects successful logins before they are recorded.
+type LoginAlerter interface {
+	Check(ctx context.Context, user *models.UserDB, client models.ClientInfo) error
+}
+
+// WalletCreator opens empty wallets.
+type WalletCreator interface {
+	Create(ctx context.Context, userID uuid.UUID, currencies []string) ([]string, error)
 }
 
 // AuthService handles registration and login.
 type AuthService struct {
-	reader UserReader
-	writer UserWriter
-	jwt    JWTGenerator
+	reader         UserReader
+	writer         UserWriter
+	jwt            JWTGenerator
+	events         AuthEventWriter
+	geo            GeoLocator
+	alerts         LoginAlerter
+	wallets        WalletCreator
+	initialWallets []string
+	bcryptCost     int
+	pepper         string
+	allowLegacy    bool
+}
+
+// AuthOpt defines a functional option for AuthService.
+type AuthOpt func(*AuthService)
+
+// WithBcryptCost sets the bcrypt cost used when hashing passwords.
+func WithBcryptCost(cost int) AuthOpt {
+	return func(svc *AuthService) {
+		svc.bcryptCost = cost
+	}
+}
+
+// WithPepper sets the secret pepper mixed into passwords before hashing.
+func WithPepper(pepper string) AuthOpt {
+	return func(svc *AuthService) {
+		svc.pepper = pepper
+	}
+}
+
+// WithLegacyHashes enables verification of hashes created without the pepper.
+// Matching legacy hashes are re-hashed with the pepper on successful login.
+func WithLegacyHashes(allow bool) AuthOpt {
+	return func(svc *AuthService) {
+		svc.allowLegacy = allow
+	}
+}
+
+// WithAuthEvents enables recording of successful and failed logins.
+func WithAuthEvents(events AuthEventWriter) AuthOpt {
+	return func(svc *AuthService) {
+		svc.events = events
+	}
+}
+
+// WithGeoLocator enables resolving the client country for recorded logins.
+func WithGeoLocator(geo GeoLocator) AuthOpt {
+	return func(svc *AuthService) {
+		svc.geo = geo
+	}
+}
+
+// WithLoginAlerts enables alerts for logins from a new country or device.
+func WithLoginAlerts(alerts LoginAlerter) AuthOpt {
+	return func(svc *AuthService) {
+		svc.alerts = alerts
+	}
+}
+
+// WithInitialWallets opens empty wallets in currencies for every registered user, so
+// their balances list the currencies before the first deposit.
+func WithInitialWallets(wallets WalletCreator, currencies []string) AuthOpt {
+	return func(svc *AuthService) {
+		svc.wallets = wallets
+		svc.initialWallets = currencies
+	}
 }
 
 // NewAuthService creates a new AuthService instance.
-func NewAuthService(reader UserReader, writer UserWriter, jwt JWTGenerator) *AuthService {
-	return &AuthService{
-		reader: reader,
-		writer: writer,
-		jwt:    jwt,
+func NewAuthService(reader UserReader, writer UserWriter, jwt JWTGenerator, opts ...AuthOpt) *AuthService {
+	svc := &AuthService{
+		reader:      reader,
+		writer:      writer,
+		jwt:         jwt,
+		bcryptCost:  bcrypt.DefaultCost,
+		allowLegacy: true,
+	}
+	for _, opt := range opts {
+		opt(svc)
 	}
+	return svc
+}
+
+// pepperPassword applies the pepper to the password as HMAC-SHA256.
+// The hex digest stays within the 72-byte bcrypt input limit.
+func (svc *AuthService) pepperPassword(password string) []byte {
+	if svc.pepper == "" {
+		return []byte(password)
+	}
+	mac := hmac.New(sha256.New, []byte(svc.pepper))
+	mac.Write([]byte(password))
+	return []byte(hex.EncodeToString(mac.Sum(nil)))
+}
+
+// hashPassword hashes the peppered password with the configured cost.
+func (svc *AuthService) hashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword(svc.pepperPassword(password), svc.bcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// verifyPassword checks the password against the stored hash.
+// It reports whether the hash is a legacy (unpeppered) one.
+func (svc *AuthService) verifyPassword(hash, password string) (legacy bool, err error) {
+	err = bcrypt.CompareHashAndPassword([]byte(hash), svc.pepperPassword(password))
+	if err == nil || svc.pepper == "" || !svc.allowLegacy {
+		return false, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// VerifyPassword checks the password against the stored hash, accepting legacy hashes when enabled.
+func (svc *AuthService) VerifyPassword(hash, password string) error {
+	if _, err := svc.verifyPassword(hash, password); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
 }
 
 // Register registers a new user.
 func (svc *AuthService) Register(ctx context.Context, username, password, email string) error {
 	user, err := svc.reader.GetByUsernameOrEmail(ctx, &username, &email)
 	if err != nil {
-		logger.Log.Errorw("failed to check user exists", "err", err)
+		logger.FromContext(ctx).Errorw("failed to check user exists", "err", err)
 		return err
 	}
 	if user != nil {
-		logger.Log.Errorw("user already exists", "username", username, "email", email)
+		logger.FromContext(ctx).Errorw("user already exists", "username", username, "email", email)
 		return ErrUserAlreadyExists
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := svc.hashPassword(password)
 	if err != nil {
-		logger.Log.Errorw("failed to hash password", "err", err)
+		logger.FromContext(ctx).Errorw("failed to hash password", "err", err)
 		return err
 	}
 
-	if err := svc.writer.Save(ctx, username, string(hashedPassword), email); err != nil {
-		logger.Log.Errorw("failed to save user", "err", err)
+	if err := svc.writer.Save(ctx, username, hashedPassword, email); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save user", "err", err)
 		return err
 	}
 
+	svc.createInitialWallets(ctx, username)
+
 	return nil
 }
 
+// createInitialWallets opens the initial wallets of a registered user. The user is already
+// saved, so failures are logged rather than returned; the wallets can be created later.
+func (svc *AuthService) createInitialWallets(ctx context.Context, username string) {
+	if svc.wallets == nil || len(svc.initialWallets) == 0 {
+		return
+	}
+
+	user, err := svc.reader.GetByUsernameOrEmail(ctx, &username, nil)
+	if err != nil || user == nil {
+		logger.FromContext(ctx).Errorw("failed to get registered user for initial wallets", "username", username, "err", err)
+		return
+	}
+	if _, err := svc.wallets.Create(ctx, user.UserID, svc.initialWallets); err != nil {
+		logger.FromContext(ctx).Errorw("failed to create initial wallets", "userID", user.UserID, "currencies", svc.initialWallets, "err", err)
+	}
+}
+
 // Login authenticates a user and returns a JWT token.
-func (svc *AuthService) Login(ctx context.Context, username, password string) (string, error) {
+// Attempts for existing users are recorded in the login history together with the client info.
+func (svc *AuthService) Login(ctx context.Context, username, password string, client models.ClientInfo) (string, error) {
 	user, err := svc.reader.GetByUsernameOrEmail(ctx, &username, nil)
 	if err != nil {
-		logger.Log.Errorw("failed to get user", "err", err)
+		logger.FromContext(ctx).Errorw("failed to get user", "err", err)
 		return "", err
 	}
 	if user == nil {
-		logger.Log.Errorw("user does not exist", "username", username)
+		logger.FromContext(ctx).Errorw("user does not exist", "username", username)
 		return "", ErrUserDoesNotExist
 	}
+	if svc.geo != nil {
+		client.Country = svc.geo.Country(ctx, client.IP)
+	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		logger.Log.Errorw("invalid credentials", "username", username)
+	legacy, err := svc.verifyPassword(user.PasswordHash, password)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("invalid credentials", "username", username)
+		svc.recordAuthEvent(ctx, user.UserID, models.AuthEventLoginFailure, client)
 		return "", ErrInvalidCredentials
 	}
+	if legacy {
+		svc.upgradeLegacyHash(ctx, user, password)
+	}
 
-	token, err := svc.jwt.Generate(ctx, user.UserID)
+	token, err := svc.jwt.Generate(ctx, user.UserID, jwt.WithRole(user.Role))
 	if err != nil {
-		logger.Log.Errorw("failed to generate JWT", "err", err)
+		logger.FromContext(ctx).Errorw("failed to generate JWT", "err", err)
 		return "", err
 	}
+	if svc.alerts != nil {
+		if err := svc.alerts.Check(ctx, user, client); err != nil {
+			logger.FromContext(ctx).Errorw("failed to check login for alerts", "userID", user.UserID, "err", err)
+		}
+	}
+	svc.recordAuthEvent(ctx, user.UserID, models.AuthEventLoginSuccess, client)
 
 	return token, nil
 }
+
+// recordAuthEvent saves an authentication event if recording is enabled.
+// Failures are logged and do not affect the login result.
+func (svc *AuthService) recordAuthEvent(ctx context.Context, userID uuid.UUID, eventType string, client models.ClientInfo) {
+	if svc.events == nil {
+		return
+	}
+	if err := svc.events.Save(ctx, userID, eventType, client); err != nil {
+		logger.FromContext(ctx).Errorw("failed to record auth event", "userID", userID, "eventType", eventType, "err", err)
+	}
+}
+
+// upgradeLegacyHash re-hashes a legacy password hash with the pepper.
+// Failures are logged and do not affect the login result.
+func (svc *AuthService) upgradeLegacyHash(ctx context.Context, user *models.UserDB, password string) {
+	hashedPassword, err := svc.hashPassword(password)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to re-hash legacy password", "username", user.Username, "err", err)
+		return
+	}
+	if err := svc.writer.Save(ctx, user.Username, hashedPassword, user.Email); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save re-hashed password", "username", user.Username, "err", err)
+		return
+	}
+	logger.FromContext(ctx).Infow("legacy password hash upgraded", "username", user.Username)
+}
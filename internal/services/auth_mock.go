@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	jwt "github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
 	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
 )
 
@@ -112,16 +113,170 @@ func (m *MockJWTGenerator) EXPECT() *MockJWTGeneratorMockRecorder {
 }
 
 // Generate mocks base method.
-func (m *MockJWTGenerator) Generate(ctx context.Context, userID uuid.UUID) (string, error) {
+func (m *MockJWTGenerator) Generate(ctx context.Context, userID uuid.UUID, opts ...jwt.ClaimOpt) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Generate", ctx, userID)
+	varargs := []interface{}{ctx, userID}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Generate", varargs...)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Generate indicates an expected call of Generate.
-func (mr *MockJWTGeneratorMockRecorder) Generate(ctx, userID interface{}) *gomock.Call {
+func (mr *MockJWTGeneratorMockRecorder) Generate(ctx, userID interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockJWTGenerator)(nil).Generate), ctx, userID)
+	varargs := append([]interface{}{ctx, userID}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockJWTGenerator)(nil).Generate), varargs...)
+}
+
+// MockAuthEventWriter is a mock of AuthEventWriter interface.
+type MockAuthEventWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthEventWriterMockRecorder
+}
+
+// MockAuthEventWriterMockRecorder is the mock recorder for MockAuthEventWriter.
+type MockAuthEventWriterMockRecorder struct {
+	mock *MockAuthEventWriter
+}
+
+// NewMockAuthEventWriter creates a new mock instance.
+func NewMockAuthEventWriter(ctrl *gomock.Controller) *MockAuthEventWriter {
+	mock := &MockAuthEventWriter{ctrl: ctrl}
+	mock.recorder = &MockAuthEventWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthEventWriter) EXPECT() *MockAuthEventWriterMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockAuthEventWriter) Save(ctx context.Context, userID uuid.UUID, eventType string, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, userID, eventType, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAuthEventWriterMockRecorder) Save(ctx, userID, eventType, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAuthEventWriter)(nil).Save), ctx, userID, eventType, client)
+}
+
+// MockGeoLocator is a mock of GeoLocator interface.
+type MockGeoLocator struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeoLocatorMockRecorder
+}
+
+// MockGeoLocatorMockRecorder is the mock recorder for MockGeoLocator.
+type MockGeoLocatorMockRecorder struct {
+	mock *MockGeoLocator
+}
+
+// NewMockGeoLocator creates a new mock instance.
+func NewMockGeoLocator(ctrl *gomock.Controller) *MockGeoLocator {
+	mock := &MockGeoLocator{ctrl: ctrl}
+	mock.recorder = &MockGeoLocatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeoLocator) EXPECT() *MockGeoLocatorMockRecorder {
+	return m.recorder
+}
+
+// Country mocks base method.
+func (m *MockGeoLocator) Country(ctx context.Context, ip string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Country", ctx, ip)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Country indicates an expected call of Country.
+func (mr *MockGeoLocatorMockRecorder) Country(ctx, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Country", reflect.TypeOf((*MockGeoLocator)(nil).Country), ctx, ip)
+}
+
+// MockLoginAlerter is a mock of LoginAlerter interface.
+type MockLoginAlerter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAlerterMockRecorder
+}
+
+// MockLoginAlerterMockRecorder is the mock recorder for MockLoginAlerter.
+type MockLoginAlerterMockRecorder struct {
+	mock *MockLoginAlerter
+}
+
+// NewMockLoginAlerter creates a new mock instance.
+func NewMockLoginAlerter(ctrl *gomock.Controller) *MockLoginAlerter {
+	mock := &MockLoginAlerter{ctrl: ctrl}
+	mock.recorder = &MockLoginAlerterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAlerter) EXPECT() *MockLoginAlerterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginAlerter) Check(ctx context.Context, user *models.UserDB, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, user, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginAlerterMockRecorder) Check(ctx, user, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginAlerter)(nil).Check), ctx, user, client)
+}
+
+// MockWalletCreator is a mock of WalletCreator interface.
+type MockWalletCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletCreatorMockRecorder
+}
+
+// MockWalletCreatorMockRecorder is the mock recorder for MockWalletCreator.
+type MockWalletCreatorMockRecorder struct {
+	mock *MockWalletCreator
+}
+
+// NewMockWalletCreator creates a new mock instance.
+func NewMockWalletCreator(ctrl *gomock.Controller) *MockWalletCreator {
+	mock := &MockWalletCreator{ctrl: ctrl}
+	mock.recorder = &MockWalletCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletCreator) EXPECT() *MockWalletCreatorMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWalletCreator) Create(ctx context.Context, userID uuid.UUID, currencies []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, currencies)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletCreatorMockRecorder) Create(ctx, userID, currencies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletCreator)(nil).Create), ctx, userID, currencies)
 }
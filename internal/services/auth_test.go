@@ -160,11 +160,11 @@ func TestAuthService_Login(t *testing.T) {
 
 			if tt.user != nil && tt.readerErr == nil && tt.loginPass == password {
 				mockJWT.EXPECT().
-					Generate(gomock.Any(), tt.user.UserID).
+					Generate(gomock.Any(), tt.user.UserID, gomock.Any()).
 					Return(tt.expectJWT, tt.jwtErr)
 			}
 
-			token, err := svc.Login(context.Background(), tt.username, tt.loginPass)
+			token, err := svc.Login(context.Background(), tt.username, tt.loginPass, models.ClientInfo{})
 			if tt.wantErr != nil {
 				assert.EqualError(t, err, tt.wantErr.Error())
 				assert.Empty(t, token)
@@ -175,3 +175,291 @@ func TestAuthService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_RegisterWithInitialWallets(t *testing.T) {
+	username, email := "alice", "alice@example.com"
+	userID := uuid.New()
+	currencies := []string{models.USD, models.EUR}
+
+	for _, createErr := range []error{nil, errors.New("db error")} {
+		ctrl := gomock.NewController(t)
+		mockReader := services.NewMockUserReader(ctrl)
+		mockWriter := services.NewMockUserWriter(ctrl)
+		mockWallets := services.NewMockWalletCreator(ctrl)
+
+		svc := services.NewAuthService(mockReader, mockWriter, services.NewMockJWTGenerator(ctrl),
+			services.WithBcryptCost(bcrypt.MinCost),
+			services.WithInitialWallets(mockWallets, currencies),
+		)
+
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, &email).Return(nil, nil)
+		mockWriter.EXPECT().Save(gomock.Any(), username, gomock.Any(), email).Return(nil)
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, nil).Return(&models.UserDB{UserID: userID}, nil)
+		mockWallets.EXPECT().Create(gomock.Any(), userID, currencies).Return(currencies, createErr)
+
+		// The user is registered even if the wallets cannot be created
+		assert.NoError(t, svc.Register(context.Background(), username, "pass123", email))
+		ctrl.Finish()
+	}
+}
+
+func TestAuthService_RegisterWithPepper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReader := services.NewMockUserReader(ctrl)
+	mockWriter := services.NewMockUserWriter(ctrl)
+	mockJWT := services.NewMockJWTGenerator(ctrl)
+
+	svc := services.NewAuthService(mockReader, mockWriter, mockJWT,
+		services.WithBcryptCost(bcrypt.MinCost),
+		services.WithPepper("pepper"),
+	)
+
+	username, email, password := "alice", "alice@example.com", "pass123"
+
+	mockReader.EXPECT().
+		GetByUsernameOrEmail(gomock.Any(), &username, &email).
+		Return(nil, nil)
+
+	var savedHash string
+	mockWriter.EXPECT().
+		Save(gomock.Any(), username, gomock.Any(), email).
+		DoAndReturn(func(_ context.Context, _ string, hash string, _ string) error {
+			savedHash = hash
+			return nil
+		})
+
+	err := svc.Register(context.Background(), username, password, email)
+	assert.NoError(t, err)
+
+	cost, err := bcrypt.Cost([]byte(savedHash))
+	assert.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost, cost)
+
+	// The raw password must not match a peppered hash.
+	assert.Error(t, bcrypt.CompareHashAndPassword([]byte(savedHash), []byte(password)))
+}
+
+func TestAuthService_LoginWithPepper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReader := services.NewMockUserReader(ctrl)
+	mockWriter := services.NewMockUserWriter(ctrl)
+	mockJWT := services.NewMockJWTGenerator(ctrl)
+
+	password := "secret"
+	legacyHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+
+	peppered := services.NewAuthService(mockReader, mockWriter, mockJWT,
+		services.WithBcryptCost(bcrypt.MinCost),
+		services.WithPepper("pepper"),
+	)
+
+	// Register through the service to obtain a peppered hash.
+	regUser, regEmail := "peppered", "peppered@example.com"
+	var pepperedHash string
+	mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &regUser, &regEmail).Return(nil, nil)
+	mockWriter.EXPECT().
+		Save(gomock.Any(), regUser, gomock.Any(), regEmail).
+		DoAndReturn(func(_ context.Context, _ string, hash string, _ string) error {
+			pepperedHash = hash
+			return nil
+		})
+	assert.NoError(t, peppered.Register(context.Background(), regUser, password, regEmail))
+
+	t.Run("peppered hash", func(t *testing.T) {
+		username := "peppered"
+		user := &models.UserDB{UserID: uuid.New(), Username: username, PasswordHash: pepperedHash}
+
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockJWT.EXPECT().Generate(gomock.Any(), user.UserID, gomock.Any()).Return("token", nil)
+
+		token, err := peppered.Login(context.Background(), username, password, models.ClientInfo{})
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("legacy hash is accepted and upgraded", func(t *testing.T) {
+		username := "legacy"
+		user := &models.UserDB{UserID: uuid.New(), Username: username, Email: "legacy@example.com", PasswordHash: string(legacyHash)}
+
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockWriter.EXPECT().Save(gomock.Any(), username, gomock.Any(), user.Email).Return(nil)
+		mockJWT.EXPECT().Generate(gomock.Any(), user.UserID, gomock.Any()).Return("token", nil)
+
+		token, err := peppered.Login(context.Background(), username, password, models.ClientInfo{})
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("legacy hash upgrade failure does not fail login", func(t *testing.T) {
+		username := "legacy"
+		user := &models.UserDB{UserID: uuid.New(), Username: username, Email: "legacy@example.com", PasswordHash: string(legacyHash)}
+
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockWriter.EXPECT().Save(gomock.Any(), username, gomock.Any(), user.Email).Return(errors.New("save error"))
+		mockJWT.EXPECT().Generate(gomock.Any(), user.UserID, gomock.Any()).Return("token", nil)
+
+		token, err := peppered.Login(context.Background(), username, password, models.ClientInfo{})
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("legacy hash rejected when fallback disabled", func(t *testing.T) {
+		strict := services.NewAuthService(mockReader, mockWriter, mockJWT,
+			services.WithPepper("pepper"),
+			services.WithLegacyHashes(false),
+		)
+		username := "legacy"
+		user := &models.UserDB{UserID: uuid.New(), Username: username, PasswordHash: string(legacyHash)}
+
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+
+		token, err := strict.Login(context.Background(), username, password, models.ClientInfo{})
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		assert.Empty(t, token)
+	})
+}
+
+func TestAuthService_LoginRecordsAuthEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReader := services.NewMockUserReader(ctrl)
+	mockWriter := services.NewMockUserWriter(ctrl)
+	mockJWT := services.NewMockJWTGenerator(ctrl)
+	mockEvents := services.NewMockAuthEventWriter(ctrl)
+
+	svc := services.NewAuthService(mockReader, mockWriter, mockJWT,
+		services.WithBcryptCost(bcrypt.MinCost),
+		services.WithAuthEvents(mockEvents),
+	)
+
+	password := "secret"
+	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	username := "alice"
+	user := &models.UserDB{UserID: uuid.New(), Username: username, PasswordHash: string(hashed)}
+	client := models.ClientInfo{IP: "203.0.113.7", UserAgent: "curl/8.0"}
+
+	t.Run("success", func(t *testing.T) {
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockJWT.EXPECT().Generate(gomock.Any(), user.UserID, gomock.Any()).Return("token", nil)
+		mockEvents.EXPECT().Save(gomock.Any(), user.UserID, models.AuthEventLoginSuccess, client).Return(nil)
+
+		token, err := svc.Login(context.Background(), username, password, client)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockEvents.EXPECT().Save(gomock.Any(), user.UserID, models.AuthEventLoginFailure, client).Return(nil)
+
+		_, err := svc.Login(context.Background(), username, "wrong", client)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+	})
+
+	t.Run("recording failure does not fail login", func(t *testing.T) {
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockJWT.EXPECT().Generate(gomock.Any(), user.UserID, gomock.Any()).Return("token", nil)
+		mockEvents.EXPECT().Save(gomock.Any(), user.UserID, models.AuthEventLoginSuccess, client).Return(errors.New("db error"))
+
+		token, err := svc.Login(context.Background(), username, password, client)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("unknown user is not recorded", func(t *testing.T) {
+		unknown := "nobody"
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &unknown, (*string)(nil)).Return(nil, nil)
+
+		_, err := svc.Login(context.Background(), unknown, password, client)
+		assert.ErrorIs(t, err, services.ErrUserDoesNotExist)
+	})
+}
+
+func TestAuthService_VerifyPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := services.NewAuthService(
+		services.NewMockUserReader(ctrl),
+		services.NewMockUserWriter(ctrl),
+		services.NewMockJWTGenerator(ctrl),
+		services.WithPepper("pepper"),
+		services.WithLegacyHashes(false),
+	)
+
+	var savedHash string
+	mockReader := services.NewMockUserReader(ctrl)
+	mockWriter := services.NewMockUserWriter(ctrl)
+	mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockWriter.EXPECT().
+		Save(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, hash string, _ string) error {
+			savedHash = hash
+			return nil
+		})
+	registrar := services.NewAuthService(mockReader, mockWriter, services.NewMockJWTGenerator(ctrl),
+		services.WithBcryptCost(bcrypt.MinCost),
+		services.WithPepper("pepper"),
+	)
+	assert.NoError(t, registrar.Register(context.Background(), "alice", "pass123", "alice@example.com"))
+
+	assert.NoError(t, svc.VerifyPassword(savedHash, "pass123"))
+	assert.ErrorIs(t, svc.VerifyPassword(savedHash, "wrong"), services.ErrInvalidCredentials)
+
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte("pass123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, svc.VerifyPassword(string(legacyHash), "pass123"), services.ErrInvalidCredentials)
+}
+
+func TestAuthService_LoginGeoAndAlerts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReader := services.NewMockUserReader(ctrl)
+	mockJWT := services.NewMockJWTGenerator(ctrl)
+	mockEvents := services.NewMockAuthEventWriter(ctrl)
+	mockGeo := services.NewMockGeoLocator(ctrl)
+	mockAlerts := services.NewMockLoginAlerter(ctrl)
+
+	svc := services.NewAuthService(mockReader, services.NewMockUserWriter(ctrl), mockJWT,
+		services.WithBcryptCost(bcrypt.MinCost),
+		services.WithAuthEvents(mockEvents),
+		services.WithGeoLocator(mockGeo),
+		services.WithLoginAlerts(mockAlerts),
+	)
+
+	password := "secret"
+	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	username := "alice"
+	user := &models.UserDB{UserID: uuid.New(), Username: username, PasswordHash: string(hashed)}
+	client := models.ClientInfo{IP: "203.0.113.7", UserAgent: "curl/8.0"}
+	located := models.ClientInfo{IP: "203.0.113.7", UserAgent: "curl/8.0", Country: "NL"}
+
+	t.Run("success checks alerts before recording", func(t *testing.T) {
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockGeo.EXPECT().Country(gomock.Any(), "203.0.113.7").Return("NL")
+		mockJWT.EXPECT().Generate(gomock.Any(), user.UserID, gomock.Any()).Return("token", nil)
+		gomock.InOrder(
+			mockAlerts.EXPECT().Check(gomock.Any(), user, located).Return(errors.New("db error")),
+			mockEvents.EXPECT().Save(gomock.Any(), user.UserID, models.AuthEventLoginSuccess, located).Return(nil),
+		)
+
+		token, err := svc.Login(context.Background(), username, password, client)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("failure is located but not checked", func(t *testing.T) {
+		mockReader.EXPECT().GetByUsernameOrEmail(gomock.Any(), &username, (*string)(nil)).Return(user, nil)
+		mockGeo.EXPECT().Country(gomock.Any(), "203.0.113.7").Return("NL")
+		mockEvents.EXPECT().Save(gomock.Any(), user.UserID, models.AuthEventLoginFailure, located).Return(nil)
+
+		_, err := svc.Login(context.Background(), username, "wrong", client)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+	})
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+const (
+	// BalanceHistoryDefaultDays is how many days of history are returned when from is not set.
+	BalanceHistoryDefaultDays = 30
+	// BalanceHistoryMaxDays is the longest history that can be requested at once.
+	BalanceHistoryMaxDays = 366
+)
+
+// ErrInvalidDateRange is returned when the history range is reversed or too long.
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// BalanceHistoryStore stores daily balance snapshots.
+type BalanceHistoryStore interface {
+	Snapshot(ctx context.Context, date time.Time) (int64, error)                                                // Records the current balances for a day
+	ListByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.BalanceSnapshotDB, error) // Returns the user's snapshots in a range
+}
+
+// BalanceHistoryService snapshots wallet balances daily so users can chart them over time.
+type BalanceHistoryService struct {
+	store BalanceHistoryStore
+}
+
+// NewBalanceHistoryService creates a new BalanceHistoryService.
+func NewBalanceHistoryService(store BalanceHistoryStore) *BalanceHistoryService {
+	return &BalanceHistoryService{store: store}
+}
+
+// Snapshot records the current balance of every wallet for today (UTC). It is meant to run
+// several times a day: each run overwrites the day's snapshot, so the last run of the day
+// keeps its closing balance and a missed run does not leave a gap.
+func (s *BalanceHistoryService) Snapshot(ctx context.Context) error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	affected, err := s.store.Snapshot(ctx, today)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to snapshot balances", "date", today.Format(time.DateOnly), "error", err)
+		return err
+	}
+	logger.FromContext(ctx).Infow("balances snapshotted", "date", today.Format(time.DateOnly), "wallets", affected)
+	return nil
+}
+
+// History returns the user's daily balances between from and to inclusive, oldest first.
+// A zero to means today and a zero from means BalanceHistoryDefaultDays before to.
+// Days without snapshots are omitted.
+func (s *BalanceHistoryService) History(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.DailyBalance, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	to = to.UTC().Truncate(24 * time.Hour)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(BalanceHistoryDefaultDays - 1))
+	}
+	from = from.UTC().Truncate(24 * time.Hour)
+	if from.After(to) || to.Sub(from) >= BalanceHistoryMaxDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	snapshots, err := s.store.ListByUserID(ctx, userID, from, to)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list balance history", "userID", userID, "error", err)
+		return nil, err
+	}
+
+	history := []models.DailyBalance{}
+	for _, snapshot := range snapshots {
+		if n := len(history); n == 0 || !history[n-1].Date.Equal(snapshot.Date) {
+			history = append(history, models.DailyBalance{Date: snapshot.Date, Balances: map[string]money.Amount{}})
+		}
+		history[len(history)-1].Balances[snapshot.Currency] = snapshot.Balance
+	}
+	return history, nil
+}
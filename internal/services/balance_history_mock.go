@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/balance_history.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockBalanceHistoryStore is a mock of BalanceHistoryStore interface.
+type MockBalanceHistoryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceHistoryStoreMockRecorder
+}
+
+// MockBalanceHistoryStoreMockRecorder is the mock recorder for MockBalanceHistoryStore.
+type MockBalanceHistoryStoreMockRecorder struct {
+	mock *MockBalanceHistoryStore
+}
+
+// NewMockBalanceHistoryStore creates a new mock instance.
+func NewMockBalanceHistoryStore(ctrl *gomock.Controller) *MockBalanceHistoryStore {
+	mock := &MockBalanceHistoryStore{ctrl: ctrl}
+	mock.recorder = &MockBalanceHistoryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceHistoryStore) EXPECT() *MockBalanceHistoryStoreMockRecorder {
+	return m.recorder
+}
+
+// ListByUserID mocks base method.
+func (m *MockBalanceHistoryStore) ListByUserID(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.BalanceSnapshotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID, from, to)
+	ret0, _ := ret[0].([]models.BalanceSnapshotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockBalanceHistoryStoreMockRecorder) ListByUserID(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockBalanceHistoryStore)(nil).ListByUserID), ctx, userID, from, to)
+}
+
+// Snapshot mocks base method.
+func (m *MockBalanceHistoryStore) Snapshot(ctx context.Context, date time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx, date)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockBalanceHistoryStoreMockRecorder) Snapshot(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockBalanceHistoryStore)(nil).Snapshot), ctx, date)
+}
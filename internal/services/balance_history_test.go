@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceHistoryService_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockBalanceHistoryStore(ctrl)
+	svc := NewBalanceHistoryService(store)
+
+	store.EXPECT().Snapshot(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, date time.Time) (int64, error) {
+		assert.Equal(t, time.Now().UTC().Truncate(24*time.Hour), date)
+		return 3, nil
+	})
+	assert.NoError(t, svc.Snapshot(ctx))
+
+	store.EXPECT().Snapshot(ctx, gomock.Any()).Return(int64(0), errors.New("db error"))
+	assert.EqualError(t, svc.Snapshot(ctx), "db error")
+}
+
+func TestBalanceHistoryService_History(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockBalanceHistoryStore(ctrl)
+	svc := NewBalanceHistoryService(store)
+
+	userID := uuid.New()
+	day1 := time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	t.Run("grouped by day", func(t *testing.T) {
+		store.EXPECT().ListByUserID(ctx, userID, day1, day2).Return([]models.BalanceSnapshotDB{
+			{UserID: userID, Currency: models.EUR, Date: day1, Balance: money.MustParse("50")},
+			{UserID: userID, Currency: models.USD, Date: day1, Balance: money.MustParse("100")},
+			{UserID: userID, Currency: models.USD, Date: day2, Balance: money.MustParse("70")},
+		}, nil)
+
+		history, err := svc.History(ctx, userID, day1.Add(5*time.Hour), day2.Add(23*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, []models.DailyBalance{
+			{Date: day1, Balances: map[string]money.Amount{models.EUR: money.MustParse("50"), models.USD: money.MustParse("100")}},
+			{Date: day2, Balances: map[string]money.Amount{models.USD: money.MustParse("70")}},
+		}, history)
+	})
+
+	t.Run("defaults to the last days", func(t *testing.T) {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		store.EXPECT().ListByUserID(ctx, userID, today.AddDate(0, 0, -(BalanceHistoryDefaultDays-1)), today).Return(nil, nil)
+
+		history, err := svc.History(ctx, userID, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Empty(t, history)
+		assert.NotNil(t, history)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := svc.History(ctx, userID, day2, day1)
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+		_, err = svc.History(ctx, userID, day1, day1.AddDate(0, 0, BalanceHistoryMaxDays))
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store.EXPECT().ListByUserID(ctx, userID, day1, day1).Return(nil, errors.New("db error"))
+
+		_, err := svc.History(ctx, userID, day1, day1)
+		assert.EqualError(t, err, "db error")
+	})
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// WithCrossRates derives the rate of a pair the exchanger has no direct rate for through
+// the pivot currency, e.g. RUB→EUR as RUB→USD × USD→EUR, instead of failing the operation.
+// The legs are cached, recorded and served stale like direct rates.
+func WithCrossRates(pivot string) WalletOpt {
+	return func(s *WalletService) {
+		s.pivot = pivot
+	}
+}
+
+// pairRate returns the rate for a currency pair like getExchangeRate, falling back to the
+// cross rate through the pivot currency if the exchanger has no direct rate. derived
+// reports a cross rate, whose source combines the legs: it is as old as the older leg,
+// cached if both legs were and stale if either was.
+func (s *WalletService) pairRate(ctx context.Context, fromCurrency, toCurrency string) (rate float32, src models.RateSource, derived bool, err error) {
+	rate, src, err = s.getExchangeRate(ctx, fromCurrency, toCurrency)
+	if !errors.Is(err, ErrExchangeRateNotFound) || s.pivot == "" || fromCurrency == s.pivot || toCurrency == s.pivot {
+		return rate, src, false, err
+	}
+
+	toPivot, srcTo, legErr := s.getExchangeRate(ctx, fromCurrency, s.pivot)
+	if legErr != nil {
+		return 0, models.RateSource{}, false, legErr
+	}
+	fromPivot, srcFrom, legErr := s.getExchangeRate(ctx, s.pivot, toCurrency)
+	if legErr != nil {
+		return 0, models.RateSource{}, false, legErr
+	}
+
+	rate = toPivot * fromPivot
+	logger.FromContext(ctx).Infow("derived cross exchange rate", "from", fromCurrency, "to", toCurrency, "pivot", s.pivot, "rate", rate)
+	metrics.CrossRatesServed.Inc()
+	return rate, combineRateSources(srcTo, srcFrom), true, nil
+}
+
+// combineRateSources returns the source of a rate derived from the rates of a and b.
+func combineRateSources(a, b models.RateSource) models.RateSource {
+	src := models.RateSource{
+		Provider:  a.Provider,
+		FetchedAt: a.FetchedAt,
+		Cached:    a.Cached && b.Cached,
+		Stale:     a.Stale || b.Stale,
+	}
+	if b.FetchedAt.Before(a.FetchedAt) {
+		src.FetchedAt = b.FetchedAt
+	}
+	if b.Provider != a.Provider {
+		src.Provider = a.Provider + "+" + b.Provider
+	}
+	return src
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_CrossRates(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("1000")
+	cacheMiss := errors.New("cache miss")
+
+	t.Run("derived through pivot", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		svc := NewWalletService(nil, nil, rates, cache, nil, WithCrossRates(models.USD))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.EUR).Return(float32(0), models.RateSource{}, cacheMiss)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.EUR).Return(float32(0), ErrExchangeRateNotFound)
+		older := time.Now().Add(-2 * time.Minute)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0.0125), models.RateSource{Provider: "grpc", FetchedAt: older, Cached: true}, nil)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.8), models.RateSource{Provider: "http", FetchedAt: time.Now(), Cached: true}, nil)
+
+		quote, err := svc.QuoteExchange(ctx, userID, models.RUB, models.EUR, amount)
+		assert.NoError(t, err)
+		assert.True(t, quote.DerivedRate)
+		assert.False(t, quote.StaleRate)
+		assert.InDelta(t, 0.01, quote.Rate, 1e-6)
+		assert.Equal(t, money.MustParse("10"), quote.ToAmount)
+		assert.Equal(t, "grpc+http", quote.RateProvider)
+		assert.Equal(t, older, quote.RateFetchedAt)
+		assert.True(t, quote.RateCached)
+	})
+
+	t.Run("missing leg", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		svc := NewWalletService(nil, nil, rates, cache, nil, WithCrossRates(models.USD))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.EUR).Return(float32(0), models.RateSource{}, cacheMiss)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.EUR).Return(float32(0), ErrExchangeRateNotFound)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0), models.RateSource{}, cacheMiss)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0), ErrExchangeRateNotFound)
+
+		_, err := svc.QuoteExchange(ctx, userID, models.RUB, models.EUR, amount)
+		assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+	})
+
+	t.Run("pivot pair is not derived", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		svc := NewWalletService(nil, nil, rates, cache, nil, WithCrossRates(models.USD))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(0), models.RateSource{}, cacheMiss)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(0), ErrExchangeRateNotFound)
+
+		_, err := svc.QuoteExchange(ctx, userID, models.USD, models.RUB, amount)
+		assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+	})
+
+	t.Run("other errors are not derived", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		svc := NewWalletService(nil, nil, rates, cache, nil, WithCrossRates(models.USD))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.EUR).Return(float32(0), models.RateSource{}, cacheMiss)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.EUR).Return(float32(0), ErrExchangerUnavailable)
+
+		_, err := svc.QuoteExchange(ctx, userID, models.RUB, models.EUR, amount)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// CurrencyCacheTTL is how long the list of supported currencies is cached,
+// so a currency added to the table is accepted after at most this long.
+const CurrencyCacheTTL = time.Minute
+
+// CurrencyStore reads the supported currencies.
+type CurrencyStore interface {
+	ListEnabled(ctx context.Context) ([]models.CurrencyDB, error) // Returns enabled currencies ordered by code
+}
+
+// CurrencyService serves the supported currencies from an in-memory copy of the
+// currencies table, reloaded once it is older than the TTL.
+type CurrencyService struct {
+	store CurrencyStore
+	ttl   time.Duration
+
+	mu         sync.Mutex
+	currencies []models.CurrencyDB
+	codes      map[string]models.CurrencyDB
+	loadedAt   time.Time
+}
+
+// NewCurrencyService creates a new CurrencyService caching the list for ttl.
+func NewCurrencyService(store CurrencyStore, ttl time.Duration) *CurrencyService {
+	return &CurrencyService{store: store, ttl: ttl}
+}
+
+// load reloads the currencies if the cached copy is stale. If reloading fails,
+// the last loaded copy is kept and the error is returned only if there is none.
+func (s *CurrencyService) load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.codes != nil && time.Since(s.loadedAt) < s.ttl {
+		return nil
+	}
+
+	currencies, err := s.store.ListEnabled(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to load currencies", "error", err)
+		if s.codes != nil {
+			return nil
+		}
+		return err
+	}
+
+	s.currencies = currencies
+	s.codes = make(map[string]models.CurrencyDB, len(currencies))
+	for _, c := range currencies {
+		s.codes[c.Code] = c
+	}
+	s.loadedAt = time.Now()
+	return nil
+}
+
+// List returns the supported currencies ordered by code.
+func (s *CurrencyService) List(ctx context.Context) ([]models.CurrencyDB, error) {
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.CurrencyDB(nil), s.currencies...), nil
+}
+
+// Codes returns the codes of the supported currencies ordered by code.
+// It is empty if the currencies could not be loaded.
+func (s *CurrencyService) Codes(ctx context.Context) []string {
+	currencies, _ := s.List(ctx)
+	codes := make([]string, 0, len(currencies))
+	for _, c := range currencies {
+		codes = append(codes, c.Code)
+	}
+	return codes
+}
+
+// IsSupported reports whether code is a supported currency.
+// No currency is supported if the currencies could not be loaded.
+func (s *CurrencyService) IsSupported(ctx context.Context, code string) bool {
+	_, ok := s.get(ctx, code)
+	return ok
+}
+
+// Decimals returns the number of decimal places of amounts in code.
+// Currencies that are not supported have money.Scale decimal places.
+func (s *CurrencyService) Decimals(ctx context.Context, code string) int {
+	currency, ok := s.get(ctx, code)
+	if !ok {
+		return money.Scale
+	}
+	return currency.Decimals
+}
+
+// ValidAmount reports whether amount may be moved in code: the currency is supported,
+// the amount is at least its minimum and has no more decimal places than the currency.
+func (s *CurrencyService) ValidAmount(ctx context.Context, code string, amount money.Amount) bool {
+	currency, ok := s.get(ctx, code)
+	return ok && amount.IsPositive() && amount >= currency.MinAmount && amount.Round(currency.Decimals) == amount
+}
+
+// get returns the supported currency with the given code.
+func (s *CurrencyService) get(ctx context.Context, code string) (models.CurrencyDB, bool) {
+	if err := s.load(ctx); err != nil {
+		return models.CurrencyDB{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	currency, ok := s.codes[code]
+	return currency, ok
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/currency.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockCurrencyStore is a mock of CurrencyStore interface.
+type MockCurrencyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyStoreMockRecorder
+}
+
+// MockCurrencyStoreMockRecorder is the mock recorder for MockCurrencyStore.
+type MockCurrencyStoreMockRecorder struct {
+	mock *MockCurrencyStore
+}
+
+// NewMockCurrencyStore creates a new mock instance.
+func NewMockCurrencyStore(ctrl *gomock.Controller) *MockCurrencyStore {
+	mock := &MockCurrencyStore{ctrl: ctrl}
+	mock.recorder = &MockCurrencyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyStore) EXPECT() *MockCurrencyStoreMockRecorder {
+	return m.recorder
+}
+
+// ListEnabled mocks base method.
+func (m *MockCurrencyStore) ListEnabled(ctx context.Context) ([]models.CurrencyDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnabled", ctx)
+	ret0, _ := ret[0].([]models.CurrencyDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnabled indicates an expected call of ListEnabled.
+func (mr *MockCurrencyStoreMockRecorder) ListEnabled(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnabled", reflect.TypeOf((*MockCurrencyStore)(nil).ListEnabled), ctx)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyService(t *testing.T) {
+	ctx := context.Background()
+	currencies := []models.CurrencyDB{{Code: models.EUR}, {Code: models.USD}}
+
+	t.Run("caches the list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockCurrencyStore(ctrl)
+		store.EXPECT().ListEnabled(ctx).Return(currencies, nil).Times(1)
+
+		svc := NewCurrencyService(store, time.Hour)
+		assert.True(t, svc.IsSupported(ctx, models.USD))
+		assert.False(t, svc.IsSupported(ctx, models.RUB))
+		assert.Equal(t, []string{models.EUR, models.USD}, svc.Codes(ctx))
+	})
+
+	t.Run("reloads a stale list", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockCurrencyStore(ctrl)
+		store.EXPECT().ListEnabled(ctx).Return(currencies, nil)
+		store.EXPECT().ListEnabled(ctx).Return(append(currencies, models.CurrencyDB{Code: "GBP"}), nil)
+
+		svc := NewCurrencyService(store, 0)
+		assert.False(t, svc.IsSupported(ctx, "GBP"))
+		assert.True(t, svc.IsSupported(ctx, "GBP"))
+	})
+
+	t.Run("keeps the last list on errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockCurrencyStore(ctrl)
+		store.EXPECT().ListEnabled(ctx).Return(currencies, nil)
+		store.EXPECT().ListEnabled(ctx).Return(nil, errors.New("db error"))
+
+		svc := NewCurrencyService(store, 0)
+		assert.True(t, svc.IsSupported(ctx, models.USD))
+		assert.True(t, svc.IsSupported(ctx, models.USD))
+	})
+
+	t.Run("nothing is supported before the first load", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockCurrencyStore(ctrl)
+		store.EXPECT().ListEnabled(ctx).Return(nil, errors.New("db error")).Times(2)
+
+		svc := NewCurrencyService(store, time.Hour)
+		assert.False(t, svc.IsSupported(ctx, models.USD))
+		_, err := svc.List(ctx)
+		assert.EqualError(t, err, "db error")
+	})
+
+	t.Run("amount rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockCurrencyStore(ctrl)
+		store.EXPECT().ListEnabled(ctx).Return([]models.CurrencyDB{
+			{Code: models.USD, Decimals: 2, MinAmount: money.MustParse("0.01")},
+			{Code: "JPY", Decimals: 0, MinAmount: money.MustParse("100")},
+		}, nil)
+
+		svc := NewCurrencyService(store, time.Hour)
+		assert.Equal(t, 2, svc.Decimals(ctx, models.USD))
+		assert.Equal(t, 0, svc.Decimals(ctx, "JPY"))
+		assert.Equal(t, money.Scale, svc.Decimals(ctx, models.RUB))
+
+		assert.True(t, svc.ValidAmount(ctx, models.USD, money.MustParse("0.01")))
+		assert.False(t, svc.ValidAmount(ctx, models.USD, money.Zero))
+		assert.True(t, svc.ValidAmount(ctx, "JPY", money.MustParse("100")))
+		assert.False(t, svc.ValidAmount(ctx, "JPY", money.MustParse("99")))
+		assert.False(t, svc.ValidAmount(ctx, "JPY", money.MustParse("100.50")))
+		assert.False(t, svc.ValidAmount(ctx, models.RUB, money.MustParse("100")))
+	})
+}
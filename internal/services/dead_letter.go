@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/segmentio/kafka-go"
+)
+
+// DeadLetterListLimit is the default and largest number of dead letters listed at once.
+const DeadLetterListLimit = 100
+
+var (
+	// ErrEventDeadLettered is returned by WriteMessages when the events could not be published
+	// and were kept as dead letters instead.
+	ErrEventDeadLettered = errors.New("event dead-lettered")
+	// ErrDeadLetterNotFound is returned when a dead letter does not exist.
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrDeadLetterReplayed is returned when replaying a dead letter that was already replayed.
+	ErrDeadLetterReplayed = errors.New("dead letter already replayed")
+	// ErrDeadLetterReplayFailed is returned when a replayed dead letter could not be published.
+	ErrDeadLetterReplayFailed = errors.New("dead letter replay failed")
+)
+
+// DeadLetterStore keeps the events that could not be published.
+type DeadLetterStore interface {
+	Save(ctx context.Context, event models.DeadLetterEvent) error                 // Stores a dead letter
+	ListPending(ctx context.Context, limit int) ([]models.DeadLetterEvent, error) // Returns dead letters not replayed yet, oldest first
+	GetByID(ctx context.Context, id uuid.UUID) (models.DeadLetterEvent, error)    // Returns a dead letter or sql.ErrNoRows
+	MarkReplayed(ctx context.Context, id uuid.UUID) (bool, error)                 // Records the replay; false if already replayed
+}
+
+// DeadLetterService publishes events to Kafka, retrying a failed write a bounded number of
+// times with exponential backoff. Events that still fail are kept in the database as dead
+// letters instead of being dropped, and are published again when an admin replays them.
+// Consumers drop replayed duplicates by the event ID.
+type DeadLetterService struct {
+	store      DeadLetterStore
+	writer     EventPublisher
+	attempts   int
+	retryDelay time.Duration
+}
+
+// NewDeadLetterService creates a DeadLetterService writing to writer. A write is attempted
+// up to attempts times, waiting retryDelay before the first retry and doubling it after.
+func NewDeadLetterService(store DeadLetterStore, writer EventPublisher, attempts int, retryDelay time.Duration) *DeadLetterService {
+	return &DeadLetterService{store: store, writer: writer, attempts: max(attempts, 1), retryDelay: retryDelay}
+}
+
+// WriteMessages writes msgs to Kafka. If every attempt fails, msgs are dead-lettered and
+// ErrEventDeadLettered is returned; the error is only lost if storing them fails too.
+func (s *DeadLetterService) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	err := s.write(ctx, msgs)
+	if err == nil {
+		return nil
+	}
+
+	// The operation behind the events is done, they are kept even if the request was cancelled
+	ctx = context.WithoutCancel(ctx)
+	for _, msg := range msgs {
+		event := models.DeadLetterEvent{
+			ID:        uuid.New(),
+			Topic:     msg.Topic,
+			Key:       string(msg.Key),
+			EventID:   eventID(msg),
+			Payload:   msg.Value,
+			Attempts:  s.attempts,
+			LastError: err.Error(),
+		}
+		if saveErr := s.store.Save(ctx, event); saveErr != nil {
+			logger.FromContext(ctx).Errorw("failed to dead-letter event", "key", event.Key, "error", saveErr)
+			return errors.Join(err, saveErr)
+		}
+		metrics.DeadLetteredEvents.Inc()
+		logger.FromContext(ctx).Warnw("event dead-lettered", "dead_letter_id", event.ID, "topic", event.Topic, "key", event.Key, "attempts", s.attempts, "error", err)
+	}
+	return fmt.Errorf("%w: %w", ErrEventDeadLettered, err)
+}
+
+// write attempts to write msgs until it succeeds, runs out of attempts or ctx is done.
+func (s *DeadLetterService) write(ctx context.Context, msgs []kafka.Message) error {
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := s.writer.WriteMessages(ctx, msgs...)
+		if err == nil || attempt >= s.attempts {
+			return err
+		}
+		logger.FromContext(ctx).Warnw("retrying Kafka write", "attempt", attempt, "backoff", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// Close closes the underlying writer.
+func (s *DeadLetterService) Close() error {
+	return s.writer.Close()
+}
+
+// ListPending returns up to limit dead letters that were not replayed, oldest first.
+// A limit outside 1..DeadLetterListLimit lists DeadLetterListLimit.
+func (s *DeadLetterService) ListPending(ctx context.Context, limit int) ([]models.DeadLetterEvent, error) {
+	if limit < 1 || limit > DeadLetterListLimit {
+		limit = DeadLetterListLimit
+	}
+	events, err := s.store.ListPending(ctx, limit)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list dead letters", "error", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// Replay publishes a dead letter again, without retries, and marks it replayed.
+func (s *DeadLetterService) Replay(ctx context.Context, id uuid.UUID) (models.DeadLetterEvent, error) {
+	event, err := s.store.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeadLetterEvent{}, ErrDeadLetterNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to get dead letter", "dead_letter_id", id, "error", err)
+		return models.DeadLetterEvent{}, err
+	}
+	if event.ReplayedAt != nil {
+		return models.DeadLetterEvent{}, ErrDeadLetterReplayed
+	}
+
+	msg := kafka.Message{Topic: event.Topic, Key: []byte(event.Key), Value: event.Payload}
+	if event.EventID != "" {
+		msg.Headers = []kafka.Header{{Key: models.EventIDHeader, Value: []byte(event.EventID)}}
+	}
+	if err := s.writer.WriteMessages(ctx, msg); err != nil {
+		logger.FromContext(ctx).Errorw("failed to replay dead letter", "dead_letter_id", id, "key", event.Key, "error", err)
+		return models.DeadLetterEvent{}, fmt.Errorf("%w: %w", ErrDeadLetterReplayFailed, err)
+	}
+
+	replayed, err := s.store.MarkReplayed(ctx, id)
+	if err != nil {
+		// The event was published; it stays listed and a second replay is dropped by the event ID
+		logger.FromContext(ctx).Errorw("failed to mark dead letter as replayed", "dead_letter_id", id, "error", err)
+		return models.DeadLetterEvent{}, err
+	}
+	if !replayed {
+		return models.DeadLetterEvent{}, ErrDeadLetterReplayed
+	}
+	logger.FromContext(ctx).Infow("dead letter replayed", "dead_letter_id", id, "key", event.Key)
+
+	now := time.Now()
+	event.ReplayedAt = &now
+	return event, nil
+}
+
+// eventID returns the event ID header of msg, empty if it has none.
+func eventID(msg kafka.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == models.EventIDHeader {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
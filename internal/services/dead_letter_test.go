@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterService_WriteMessages(t *testing.T) {
+	ctx := context.Background()
+	msg := kafka.Message{
+		Topic:   "transactions",
+		Key:     []byte("user-1"),
+		Value:   []byte(`{"transaction_id":"txn-1"}`),
+		Headers: []kafka.Header{{Key: models.EventIDHeader, Value: []byte("event-1")}},
+	}
+
+	t.Run("published on the first attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockDeadLetterStore(ctrl)
+		writer := NewMockEventPublisher(ctrl)
+		writer.EXPECT().WriteMessages(ctx, msg).Return(nil)
+
+		assert.NoError(t, NewDeadLetterService(store, writer, 3, time.Millisecond).WriteMessages(ctx, msg))
+	})
+
+	t.Run("published after a retry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockDeadLetterStore(ctrl)
+		writer := NewMockEventPublisher(ctrl)
+		gomock.InOrder(
+			writer.EXPECT().WriteMessages(ctx, msg).Return(errors.New("broker down")),
+			writer.EXPECT().WriteMessages(ctx, msg).Return(nil),
+		)
+
+		assert.NoError(t, NewDeadLetterService(store, writer, 3, time.Millisecond).WriteMessages(ctx, msg))
+	})
+
+	t.Run("dead-lettered after all attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockDeadLetterStore(ctrl)
+		writer := NewMockEventPublisher(ctrl)
+		writer.EXPECT().WriteMessages(ctx, msg).Return(errors.New("broker down")).Times(3)
+		store.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event models.DeadLetterEvent) error {
+			assert.NotEqual(t, uuid.Nil, event.ID)
+			assert.Equal(t, "transactions", event.Topic)
+			assert.Equal(t, "user-1", event.Key)
+			assert.Equal(t, "event-1", event.EventID)
+			assert.Equal(t, msg.Value, event.Payload)
+			assert.Equal(t, 3, event.Attempts)
+			assert.Equal(t, "broker down", event.LastError)
+			return nil
+		})
+
+		err := NewDeadLetterService(store, writer, 3, time.Millisecond).WriteMessages(ctx, msg)
+		assert.ErrorIs(t, err, ErrEventDeadLettered)
+	})
+
+	t.Run("dead letter not stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockDeadLetterStore(ctrl)
+		writer := NewMockEventPublisher(ctrl)
+		writer.EXPECT().WriteMessages(ctx, msg).Return(errors.New("broker down"))
+		store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+
+		err := NewDeadLetterService(store, writer, 1, time.Millisecond).WriteMessages(ctx, msg)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrEventDeadLettered)
+	})
+
+	t.Run("cancelled request stops retrying", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockDeadLetterStore(ctrl)
+		writer := NewMockEventPublisher(ctrl)
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		writer.EXPECT().WriteMessages(cctx, msg).Return(errors.New("broker down"))
+		store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := NewDeadLetterService(store, writer, 3, time.Hour).WriteMessages(cctx, msg)
+		assert.ErrorIs(t, err, ErrEventDeadLettered)
+	})
+}
+
+func TestDeadLetterService_ListPending(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	store := NewMockDeadLetterStore(ctrl)
+	svc := NewDeadLetterService(store, NewMockEventPublisher(ctrl), 3, time.Millisecond)
+
+	events := []models.DeadLetterEvent{{ID: uuid.New(), Key: "txn-1"}}
+	store.EXPECT().ListPending(ctx, 10).Return(events, nil)
+	got, err := svc.ListPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, events, got)
+
+	// Out of range limits list the default number
+	store.EXPECT().ListPending(ctx, DeadLetterListLimit).Return(nil, nil).Times(2)
+	_, err = svc.ListPending(ctx, 0)
+	assert.NoError(t, err)
+	_, err = svc.ListPending(ctx, 1000)
+	assert.NoError(t, err)
+}
+
+func TestDeadLetterService_Replay(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	replayedAt := time.Now()
+	event := models.DeadLetterEvent{ID: id, Topic: "transactions", Key: "user-1", EventID: "event-1", Payload: []byte(`{"transaction_id":"txn-1"}`), Attempts: 3}
+	msg := kafka.Message{
+		Topic:   "transactions",
+		Key:     []byte("user-1"),
+		Value:   event.Payload,
+		Headers: []kafka.Header{{Key: models.EventIDHeader, Value: []byte("event-1")}},
+	}
+	// Dead-lettered before events had an ID
+	legacy := models.DeadLetterEvent{ID: id, Topic: "transactions", Key: "txn-1", Payload: event.Payload, Attempts: 3}
+
+	tests := []struct {
+		name       string
+		setupMocks func(store *MockDeadLetterStore, writer *MockEventPublisher)
+		wantErr    error
+	}{
+		{
+			name: "replayed",
+			setupMocks: func(store *MockDeadLetterStore, writer *MockEventPublisher) {
+				store.EXPECT().GetByID(ctx, id).Return(event, nil)
+				writer.EXPECT().WriteMessages(ctx, msg).Return(nil)
+				store.EXPECT().MarkReplayed(ctx, id).Return(true, nil)
+			},
+		},
+		{
+			name: "replayed without event ID",
+			setupMocks: func(store *MockDeadLetterStore, writer *MockEventPublisher) {
+				store.EXPECT().GetByID(ctx, id).Return(legacy, nil)
+				writer.EXPECT().WriteMessages(ctx, kafka.Message{Topic: "transactions", Key: []byte("txn-1"), Value: event.Payload}).Return(nil)
+				store.EXPECT().MarkReplayed(ctx, id).Return(true, nil)
+			},
+		},
+		{
+			name: "not found",
+			setupMocks: func(store *MockDeadLetterStore, writer *MockEventPublisher) {
+				store.EXPECT().GetByID(ctx, id).Return(models.DeadLetterEvent{}, sql.ErrNoRows)
+			},
+			wantErr: ErrDeadLetterNotFound,
+		},
+		{
+			name: "already replayed",
+			setupMocks: func(store *MockDeadLetterStore, writer *MockEventPublisher) {
+				replayed := event
+				replayed.ReplayedAt = &replayedAt
+				store.EXPECT().GetByID(ctx, id).Return(replayed, nil)
+			},
+			wantErr: ErrDeadLetterReplayed,
+		},
+		{
+			name: "replayed concurrently",
+			setupMocks: func(store *MockDeadLetterStore, writer *MockEventPublisher) {
+				store.EXPECT().GetByID(ctx, id).Return(event, nil)
+				writer.EXPECT().WriteMessages(ctx, msg).Return(nil)
+				store.EXPECT().MarkReplayed(ctx, id).Return(false, nil)
+			},
+			wantErr: ErrDeadLetterReplayed,
+		},
+		{
+			name: "broker still down",
+			setupMocks: func(store *MockDeadLetterStore, writer *MockEventPublisher) {
+				store.EXPECT().GetByID(ctx, id).Return(event, nil)
+				writer.EXPECT().WriteMessages(ctx, msg).Return(errors.New("broker down"))
+			},
+			wantErr: ErrDeadLetterReplayFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := NewMockDeadLetterStore(ctrl)
+			writer := NewMockEventPublisher(ctrl)
+			tt.setupMocks(store, writer)
+
+			got, err := NewDeadLetterService(store, writer, 3, time.Millisecond).Replay(ctx, id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, id, got.ID)
+			assert.NotNil(t, got.ReplayedAt)
+		})
+	}
+}
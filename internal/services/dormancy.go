@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ErrAccountDormant is returned when a dormant account attempts a restricted operation.
+var ErrAccountDormant = errors.New("account is dormant, re-verification required")
+
+// Dormancy notification texts
+const (
+	dormancySubject = "Your wallet has been moved to cold storage"
+	dormancyBody    = "Your account has been inactive for a long time. " +
+		"Deposits, withdrawals and exchanges are disabled until you re-verify your password."
+)
+
+// DormancyStore flags and unflags dormant accounts.
+type DormancyStore interface {
+	MarkInactive(ctx context.Context, inactiveSince time.Time) ([]models.UserDB, error)
+	SetDormant(ctx context.Context, userID uuid.UUID, dormant bool) error
+	IsDormant(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, notification models.Notification) error
+}
+
+// PasswordVerifier checks a password against a stored hash.
+type PasswordVerifier interface {
+	VerifyPassword(hash, password string) error
+}
+
+// DormancyService moves inactive accounts into cold storage and back.
+type DormancyService struct {
+	store          DormancyStore
+	users          UserByIDReader
+	passwords      PasswordVerifier
+	notifier       Notifier
+	audit          AuditWriter
+	inactiveMonths int
+}
+
+// NewDormancyService creates a new DormancyService.
+// Accounts without logins or wallet operations for inactiveMonths are flagged as dormant.
+func NewDormancyService(
+	store DormancyStore,
+	users UserByIDReader,
+	passwords PasswordVerifier,
+	notifier Notifier,
+	audit AuditWriter,
+	inactiveMonths int,
+) *DormancyService {
+	return &DormancyService{
+		store:          store,
+		users:          users,
+		passwords:      passwords,
+		notifier:       notifier,
+		audit:          audit,
+		inactiveMonths: inactiveMonths,
+	}
+}
+
+// FlagInactive flags accounts inactive for the configured period and notifies their owners.
+// Notification failures are logged and do not undo the flag.
+func (s *DormancyService) FlagInactive(ctx context.Context) error {
+	inactiveSince := time.Now().AddDate(0, -s.inactiveMonths, 0)
+
+	users, err := s.store.MarkInactive(ctx, inactiveSince)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to flag dormant accounts", "inactiveSince", inactiveSince, "error", err)
+		return err
+	}
+
+	for _, user := range users {
+		notification := models.Notification{
+			UserID:  user.UserID,
+			Email:   user.Email,
+			Channel: models.NotificationChannelEmail,
+			Subject: dormancySubject,
+			Body:    dormancyBody,
+		}
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			logger.FromContext(ctx).Errorw("failed to notify dormant account", "userID", user.UserID, "error", err)
+		}
+	}
+
+	if len(users) > 0 {
+		logger.FromContext(ctx).Infow("dormant accounts flagged", "count", len(users), "inactiveSince", inactiveSince)
+	}
+	return nil
+}
+
+// IsDormant reports whether the user's account is in cold storage.
+func (s *DormancyService) IsDormant(ctx context.Context, userID uuid.UUID) (bool, error) {
+	dormant, err := s.store.IsDormant(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to check dormancy", "userID", userID, "error", err)
+		return false, err
+	}
+	return dormant, nil
+}
+
+// Reactivate lifts the dormant flag after the user re-verifies their password.
+func (s *DormancyService) Reactivate(ctx context.Context, userID uuid.UUID, password string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserDoesNotExist
+		}
+		logger.FromContext(ctx).Errorw("failed to get user for reactivation", "userID", userID, "error", err)
+		return err
+	}
+
+	if err := s.passwords.VerifyPassword(user.PasswordHash, password); err != nil {
+		logger.FromContext(ctx).Warnw("reactivation re-verification failed", "userID", userID)
+		return ErrInvalidCredentials
+	}
+
+	if err := s.store.SetDormant(ctx, userID, false); err != nil {
+		logger.FromContext(ctx).Errorw("failed to reactivate account", "userID", userID, "error", err)
+		return err
+	}
+
+	logger.FromContext(ctx).Infow("dormant account reactivated", "userID", userID)
+	return nil
+}
+
+// SetDormant lets an admin flag or unflag an account. The override is recorded in the audit trail.
+func (s *DormancyService) SetDormant(ctx context.Context, adminID, userID uuid.UUID, dormant bool) error {
+	if err := s.store.SetDormant(ctx, userID, dormant); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Warnw("dormancy override target does not exist", "adminID", adminID, "userID", userID)
+			return ErrUserDoesNotExist
+		}
+		logger.FromContext(ctx).Errorw("failed to override dormancy", "adminID", adminID, "userID", userID, "error", err)
+		return err
+	}
+
+	action := models.AuditActionDormancyClear
+	if dormant {
+		action = models.AuditActionDormancySet
+	}
+	if err := s.audit.Save(ctx, adminID, action, &userID, nil); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit dormancy override", "adminID", adminID, "userID", userID, "error", err)
+		return err
+	}
+
+	logger.FromContext(ctx).Infow("dormancy overridden", "adminID", adminID, "userID", userID, "dormant", dormant)
+	return nil
+}
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/dormancy.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockDormancyStore is a mock of DormancyStore interface.
+type MockDormancyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDormancyStoreMockRecorder
+}
+
+// MockDormancyStoreMockRecorder is the mock recorder for MockDormancyStore.
+type MockDormancyStoreMockRecorder struct {
+	mock *MockDormancyStore
+}
+
+// NewMockDormancyStore creates a new mock instance.
+func NewMockDormancyStore(ctrl *gomock.Controller) *MockDormancyStore {
+	mock := &MockDormancyStore{ctrl: ctrl}
+	mock.recorder = &MockDormancyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDormancyStore) EXPECT() *MockDormancyStoreMockRecorder {
+	return m.recorder
+}
+
+// IsDormant mocks base method.
+func (m *MockDormancyStore) IsDormant(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDormant", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDormant indicates an expected call of IsDormant.
+func (mr *MockDormancyStoreMockRecorder) IsDormant(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDormant", reflect.TypeOf((*MockDormancyStore)(nil).IsDormant), ctx, userID)
+}
+
+// MarkInactive mocks base method.
+func (m *MockDormancyStore) MarkInactive(ctx context.Context, inactiveSince time.Time) ([]models.UserDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInactive", ctx, inactiveSince)
+	ret0, _ := ret[0].([]models.UserDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkInactive indicates an expected call of MarkInactive.
+func (mr *MockDormancyStoreMockRecorder) MarkInactive(ctx, inactiveSince interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInactive", reflect.TypeOf((*MockDormancyStore)(nil).MarkInactive), ctx, inactiveSince)
+}
+
+// SetDormant mocks base method.
+func (m *MockDormancyStore) SetDormant(ctx context.Context, userID uuid.UUID, dormant bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDormant", ctx, userID, dormant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDormant indicates an expected call of SetDormant.
+func (mr *MockDormancyStoreMockRecorder) SetDormant(ctx, userID, dormant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDormant", reflect.TypeOf((*MockDormancyStore)(nil).SetDormant), ctx, userID, dormant)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, notification models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, notification)
+}
+
+// MockPasswordVerifier is a mock of PasswordVerifier interface.
+type MockPasswordVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordVerifierMockRecorder
+}
+
+// MockPasswordVerifierMockRecorder is the mock recorder for MockPasswordVerifier.
+type MockPasswordVerifierMockRecorder struct {
+	mock *MockPasswordVerifier
+}
+
+// NewMockPasswordVerifier creates a new mock instance.
+func NewMockPasswordVerifier(ctrl *gomock.Controller) *MockPasswordVerifier {
+	mock := &MockPasswordVerifier{ctrl: ctrl}
+	mock.recorder = &MockPasswordVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordVerifier) EXPECT() *MockPasswordVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyPassword mocks base method.
+func (m *MockPasswordVerifier) VerifyPassword(hash, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", hash, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockPasswordVerifierMockRecorder) VerifyPassword(hash, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockPasswordVerifier)(nil).VerifyPassword), hash, password)
+}
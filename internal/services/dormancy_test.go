@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type dormancyMocks struct {
+	store     *MockDormancyStore
+	users     *MockUserByIDReader
+	passwords *MockPasswordVerifier
+	notifier  *MockNotifier
+	audit     *MockAuditWriter
+}
+
+func newDormancyService(t *testing.T) (*DormancyService, dormancyMocks) {
+	ctrl := gomock.NewController(t)
+	m := dormancyMocks{
+		store:     NewMockDormancyStore(ctrl),
+		users:     NewMockUserByIDReader(ctrl),
+		passwords: NewMockPasswordVerifier(ctrl),
+		notifier:  NewMockNotifier(ctrl),
+		audit:     NewMockAuditWriter(ctrl),
+	}
+	return NewDormancyService(m.store, m.users, m.passwords, m.notifier, m.audit, 12), m
+}
+
+func TestDormancyService_FlagInactive(t *testing.T) {
+	ctx := context.Background()
+	first := models.UserDB{UserID: uuid.New(), Email: "first@example.com"}
+	second := models.UserDB{UserID: uuid.New(), Email: "second@example.com"}
+
+	t.Run("notifies flagged users", func(t *testing.T) {
+		svc, m := newDormancyService(t)
+		m.store.EXPECT().MarkInactive(ctx, gomock.Any()).Return([]models.UserDB{first, second}, nil)
+		m.notifier.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n models.Notification) error {
+			assert.Equal(t, first.UserID, n.UserID)
+			assert.Equal(t, first.Email, n.Email)
+			return errors.New("smtp down")
+		})
+		m.notifier.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n models.Notification) error {
+			assert.Equal(t, second.UserID, n.UserID)
+			return nil
+		})
+
+		assert.NoError(t, svc.FlagInactive(ctx))
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		svc, m := newDormancyService(t)
+		m.store.EXPECT().MarkInactive(ctx, gomock.Any()).Return(nil, errors.New("db error"))
+
+		assert.EqualError(t, svc.FlagInactive(ctx), "db error")
+	})
+}
+
+func TestDormancyService_Reactivate(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := &models.UserDB{UserID: userID, PasswordHash: "hash"}
+
+	tests := []struct {
+		name    string
+		setup   func(m dormancyMocks)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(m dormancyMocks) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil)
+				m.passwords.EXPECT().VerifyPassword("hash", "secret").Return(nil)
+				m.store.EXPECT().SetDormant(ctx, userID, false).Return(nil)
+			},
+		},
+		{
+			name: "user not found",
+			setup: func(m dormancyMocks) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(nil, sql.ErrNoRows)
+			},
+			wantErr: ErrUserDoesNotExist,
+		},
+		{
+			name: "wrong password",
+			setup: func(m dormancyMocks) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil)
+				m.passwords.EXPECT().VerifyPassword("hash", "secret").Return(ErrInvalidCredentials)
+			},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name: "store error",
+			setup: func(m dormancyMocks) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(user, nil)
+				m.passwords.EXPECT().VerifyPassword("hash", "secret").Return(nil)
+				m.store.EXPECT().SetDormant(ctx, userID, false).Return(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newDormancyService(t)
+			tt.setup(m)
+
+			err := svc.Reactivate(ctx, userID, "secret")
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDormancyService_SetDormant(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		dormant bool
+		setup   func(m dormancyMocks)
+		wantErr error
+	}{
+		{
+			name:    "set",
+			dormant: true,
+			setup: func(m dormancyMocks) {
+				m.store.EXPECT().SetDormant(ctx, userID, true).Return(nil)
+				m.audit.EXPECT().Save(ctx, adminID, models.AuditActionDormancySet, &userID, nil).Return(nil)
+			},
+		},
+		{
+			name:    "clear",
+			dormant: false,
+			setup: func(m dormancyMocks) {
+				m.store.EXPECT().SetDormant(ctx, userID, false).Return(nil)
+				m.audit.EXPECT().Save(ctx, adminID, models.AuditActionDormancyClear, &userID, nil).Return(nil)
+			},
+		},
+		{
+			name:    "user not found",
+			dormant: true,
+			setup: func(m dormancyMocks) {
+				m.store.EXPECT().SetDormant(ctx, userID, true).Return(sql.ErrNoRows)
+			},
+			wantErr: ErrUserDoesNotExist,
+		},
+		{
+			name:    "audit error",
+			dormant: true,
+			setup: func(m dormancyMocks) {
+				m.store.EXPECT().SetDormant(ctx, userID, true).Return(nil)
+				m.audit.EXPECT().Save(ctx, adminID, models.AuditActionDormancySet, &userID, nil).Return(errors.New("audit error"))
+			},
+			wantErr: errors.New("audit error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newDormancyService(t)
+			tt.setup(m)
+
+			err := svc.SetDormant(ctx, adminID, userID, tt.dormant)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDormancyService_IsDormant(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	svc, m := newDormancyService(t)
+	m.store.EXPECT().IsDormant(ctx, userID).Return(true, nil)
+	dormant, err := svc.IsDormant(ctx, userID)
+	assert.NoError(t, err)
+	assert.True(t, dormant)
+
+	m.store.EXPECT().IsDormant(ctx, userID).Return(false, errors.New("db error"))
+	_, err = svc.IsDormant(ctx, userID)
+	assert.Error(t, err)
+}
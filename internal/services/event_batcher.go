@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/segmentio/kafka-go"
+)
+
+// EventBatcher publishes events in the background so that money operations do not wait for
+// the broker. Events are buffered and written in batches of up to batchSize, at the latest
+// flushInterval after the first one of a batch was buffered. When the buffer is full the
+// caller writes its events itself, which slows callers down to the pace of the broker instead
+// of dropping events or growing memory. Events still buffered when the process crashes are lost.
+type EventBatcher struct {
+	writer        EventPublisher
+	batchSize     int
+	flushInterval time.Duration
+
+	mu     sync.RWMutex // Guards closed against buffering into a closed queue
+	closed bool
+	queue  chan kafka.Message
+	done   chan struct{} // Closed when the buffered events are written after Close
+}
+
+// NewEventBatcher creates an EventBatcher buffering up to bufferSize events for writer and
+// starts writing them.
+func NewEventBatcher(writer EventPublisher, bufferSize, batchSize int, flushInterval time.Duration) *EventBatcher {
+	b := &EventBatcher{
+		writer:        writer,
+		batchSize:     max(batchSize, 1),
+		flushInterval: flushInterval,
+		queue:         make(chan kafka.Message, max(bufferSize, 1)),
+		done:          make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// WriteMessages buffers msgs. Events that do not fit into the buffer, or arrive after Close,
+// are written synchronously and their error is returned.
+func (b *EventBatcher) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return b.writer.WriteMessages(ctx, msgs...)
+	}
+	for i, msg := range msgs {
+		select {
+		case b.queue <- msg:
+			metrics.BufferedEvents.Inc()
+		default:
+			metrics.BackpressuredEvents.Add(float64(len(msgs) - i))
+			logger.FromContext(ctx).Warnw("event buffer full, publishing synchronously", "events", len(msgs)-i)
+			return b.writer.WriteMessages(ctx, msgs[i:]...)
+		}
+	}
+	return nil
+}
+
+// Close stops buffering and waits until the buffered events are written. The writer is left
+// open: it is shared with the replays of dead letters and closed by its owner.
+func (b *EventBatcher) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+	<-b.done
+	return nil
+}
+
+// run writes the buffered events until the queue is closed and drained.
+func (b *EventBatcher) run() {
+	defer close(b.done)
+
+	timer := time.NewTimer(b.flushInterval)
+	timer.Stop()
+	var batch []kafka.Message
+	flush := func() {
+		timer.Stop()
+		if len(batch) == 0 {
+			return
+		}
+		b.write(batch)
+		batch = nil
+	}
+
+	for {
+		select {
+		case msg, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(b.flushInterval)
+			}
+			batch = append(batch, msg)
+			if len(batch) >= b.batchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// write writes a batch. The operations behind the events are done, so a failure is only logged;
+// a dead-lettering writer keeps the events.
+func (b *EventBatcher) write(batch []kafka.Message) {
+	metrics.BufferedEvents.Sub(float64(len(batch)))
+	if err := b.writer.WriteMessages(context.Background(), batch...); err != nil {
+		logger.Log.Errorw("failed to publish event batch", "events", len(batch), "error", err)
+	}
+}
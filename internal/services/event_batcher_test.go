@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBatcher(t *testing.T) {
+	ctx := context.Background()
+	msg1 := kafka.Message{Topic: "transactions", Key: []byte("txn-1")}
+	msg2 := kafka.Message{Topic: "transactions", Key: []byte("txn-2")}
+	msg3 := kafka.Message{Topic: "large-transactions", Key: []byte("txn-3")}
+
+	t.Run("full batch written at once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockEventPublisher(ctrl)
+		written := make(chan struct{})
+		writer.EXPECT().WriteMessages(gomock.Any(), msg1, msg2).DoAndReturn(func(context.Context, ...kafka.Message) error {
+			close(written)
+			return nil
+		})
+
+		b := NewEventBatcher(writer, 10, 2, time.Hour)
+		defer b.Close()
+		assert.NoError(t, b.WriteMessages(ctx, msg1, msg2))
+		<-written
+	})
+
+	t.Run("partial batch written after the flush interval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockEventPublisher(ctrl)
+		written := make(chan struct{})
+		writer.EXPECT().WriteMessages(gomock.Any(), msg1).DoAndReturn(func(context.Context, ...kafka.Message) error {
+			close(written)
+			return nil
+		})
+
+		b := NewEventBatcher(writer, 10, 100, 10*time.Millisecond)
+		defer b.Close()
+		assert.NoError(t, b.WriteMessages(ctx, msg1))
+		select {
+		case <-written:
+		case <-time.After(time.Second):
+			t.Fatal("batch not flushed")
+		}
+	})
+
+	t.Run("close drains the buffer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockEventPublisher(ctrl)
+		writer.EXPECT().WriteMessages(gomock.Any(), msg1, msg2).Return(errors.New("broker down"))
+
+		b := NewEventBatcher(writer, 10, 100, time.Hour)
+		assert.NoError(t, b.WriteMessages(ctx, msg1, msg2))
+		assert.NoError(t, b.Close())
+
+		// Events after Close are written synchronously
+		writer.EXPECT().WriteMessages(ctx, msg3).Return(nil)
+		assert.NoError(t, b.WriteMessages(ctx, msg3))
+		assert.NoError(t, b.Close())
+	})
+
+	t.Run("full buffer publishes synchronously", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockEventPublisher(ctrl)
+		started, release := make(chan struct{}), make(chan struct{})
+		gomock.InOrder(
+			writer.EXPECT().WriteMessages(gomock.Any(), msg1).DoAndReturn(func(context.Context, ...kafka.Message) error {
+				close(started)
+				<-release
+				return nil
+			}),
+			writer.EXPECT().WriteMessages(ctx, msg3).Return(errors.New("broker down")),
+			writer.EXPECT().WriteMessages(gomock.Any(), msg2).Return(nil),
+		)
+
+		b := NewEventBatcher(writer, 1, 1, time.Hour)
+		assert.NoError(t, b.WriteMessages(ctx, msg1))
+		<-started // The batch of msg1 blocks the writer, msg2 fills the buffer
+		assert.NoError(t, b.WriteMessages(ctx, msg2))
+		assert.EqualError(t, b.WriteMessages(ctx, msg3), "broker down")
+		close(release)
+		assert.NoError(t, b.Close())
+	})
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// exchangeReceiptBatchSize is how many due receipts are loaded at once.
+	exchangeReceiptBatchSize = 100
+	// ExchangeReceiptRetryMin is the delay before the first redelivery of a receipt.
+	// It doubles with every failed attempt.
+	ExchangeReceiptRetryMin = 5 * time.Second
+	// ExchangeReceiptRetryMax is the longest delay between redeliveries of a receipt.
+	ExchangeReceiptRetryMax = time.Hour
+)
+
+// ExchangeReceiptStore keeps exchange receipts until they are delivered.
+type ExchangeReceiptStore interface {
+	Save(ctx context.Context, receipt models.ExchangeReceipt) error                                        // Queues a receipt, ignoring duplicates
+	ListDue(ctx context.Context, limit int) ([]models.ExchangeReceiptDB, error)                            // Returns undelivered receipts due for an attempt
+	MarkPublished(ctx context.Context, transactionID uuid.UUID) error                                      // Records the delivery
+	MarkFailed(ctx context.Context, transactionID uuid.UUID, nextAttemptAt time.Time, reason string) error // Records a failed attempt
+}
+
+// ExchangeReceiptService delivers receipts of executed conversions to the exchanger over Kafka,
+// so the provider can reconcile the exchanged volume. Receipts are queued in the database next
+// to the operation and published by a background job with exponential backoff. Every message is
+// keyed by the transaction ID, which the exchanger uses to drop redeliveries.
+type ExchangeReceiptService struct {
+	store  ExchangeReceiptStore
+	writer EventPublisher
+}
+
+// NewExchangeReceiptService creates a new ExchangeReceiptService.
+func NewExchangeReceiptService(store ExchangeReceiptStore, writer EventPublisher) *ExchangeReceiptService {
+	return &ExchangeReceiptService{store: store, writer: writer}
+}
+
+// Record queues the receipt of a conversion for delivery.
+func (s *ExchangeReceiptService) Record(ctx context.Context, receipt models.ExchangeReceipt) error {
+	return s.store.Save(ctx, receipt)
+}
+
+// PublishPending publishes the due receipts. It stops at the first failed delivery,
+// since the broker is most likely unavailable; the failed receipt is postponed and
+// the others are retried on the next run.
+func (s *ExchangeReceiptService) PublishPending(ctx context.Context) error {
+	for {
+		receipts, err := s.store.ListDue(ctx, exchangeReceiptBatchSize)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to list due exchange receipts", "error", err)
+			return err
+		}
+
+		for _, receipt := range receipts {
+			if err := s.publish(ctx, receipt.ExchangeReceipt); err != nil {
+				next := time.Now().Add(exchangeReceiptBackoff(receipt.Attempts))
+				logger.FromContext(ctx).Errorw("failed to publish exchange receipt", "transaction_id", receipt.TransactionID,
+					"attempts", receipt.Attempts+1, "next_attempt_at", next, "error", err)
+				if markErr := s.store.MarkFailed(ctx, receipt.TransactionID, next, err.Error()); markErr != nil {
+					return markErr
+				}
+				return err
+			}
+
+			if err := s.store.MarkPublished(ctx, receipt.TransactionID); err != nil {
+				// The receipt will be published again; the exchanger drops it by transaction ID
+				logger.FromContext(ctx).Errorw("failed to mark exchange receipt as published", "transaction_id", receipt.TransactionID, "error", err)
+				return err
+			}
+			logger.FromContext(ctx).Infow("exchange receipt published", "transaction_id", receipt.TransactionID, "attempts", receipt.Attempts+1)
+		}
+
+		if len(receipts) < exchangeReceiptBatchSize {
+			return nil
+		}
+	}
+}
+
+// publish writes a receipt to Kafka keyed by its transaction ID.
+func (s *ExchangeReceiptService) publish(ctx context.Context, receipt models.ExchangeReceipt) error {
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return err
+	}
+
+	key := []byte(receipt.TransactionID.String())
+	return s.writer.WriteMessages(ctx, kafka.Message{
+		Key:     key,
+		Value:   data,
+		Headers: append([]kafka.Header{{Key: "idempotency-key", Value: key}}, requestid.KafkaHeaders(ctx)...),
+	})
+}
+
+// exchangeReceiptBackoff returns the delay before the next attempt after attempts earlier failures.
+func exchangeReceiptBackoff(attempts int) time.Duration {
+	delay := ExchangeReceiptRetryMin
+	for i := 0; i < attempts && delay < ExchangeReceiptRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, ExchangeReceiptRetryMax)
+}
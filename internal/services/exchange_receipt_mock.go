@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/exchange_receipt.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExchangeReceiptStore is a mock of ExchangeReceiptStore interface.
+type MockExchangeReceiptStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeReceiptStoreMockRecorder
+}
+
+// MockExchangeReceiptStoreMockRecorder is the mock recorder for MockExchangeReceiptStore.
+type MockExchangeReceiptStoreMockRecorder struct {
+	mock *MockExchangeReceiptStore
+}
+
+// NewMockExchangeReceiptStore creates a new mock instance.
+func NewMockExchangeReceiptStore(ctrl *gomock.Controller) *MockExchangeReceiptStore {
+	mock := &MockExchangeReceiptStore{ctrl: ctrl}
+	mock.recorder = &MockExchangeReceiptStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeReceiptStore) EXPECT() *MockExchangeReceiptStoreMockRecorder {
+	return m.recorder
+}
+
+// ListDue mocks base method.
+func (m *MockExchangeReceiptStore) ListDue(ctx context.Context, limit int) ([]models.ExchangeReceiptDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, limit)
+	ret0, _ := ret[0].([]models.ExchangeReceiptDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockExchangeReceiptStoreMockRecorder) ListDue(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockExchangeReceiptStore)(nil).ListDue), ctx, limit)
+}
+
+// MarkFailed mocks base method.
+func (m *MockExchangeReceiptStore) MarkFailed(ctx context.Context, transactionID uuid.UUID, nextAttemptAt time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, transactionID, nextAttemptAt, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockExchangeReceiptStoreMockRecorder) MarkFailed(ctx, transactionID, nextAttemptAt, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockExchangeReceiptStore)(nil).MarkFailed), ctx, transactionID, nextAttemptAt, reason)
+}
+
+// MarkPublished mocks base method.
+func (m *MockExchangeReceiptStore) MarkPublished(ctx context.Context, transactionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockExchangeReceiptStoreMockRecorder) MarkPublished(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockExchangeReceiptStore)(nil).MarkPublished), ctx, transactionID)
+}
+
+// Save mocks base method.
+func (m *MockExchangeReceiptStore) Save(ctx context.Context, receipt models.ExchangeReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, receipt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockExchangeReceiptStoreMockRecorder) Save(ctx, receipt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockExchangeReceiptStore)(nil).Save), ctx, receipt)
+}
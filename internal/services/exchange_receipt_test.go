@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeReceiptService(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockExchangeReceiptStore(ctrl)
+	writer := NewMockEventPublisher(ctrl)
+	svc := NewExchangeReceiptService(store, writer)
+
+	newReceipt := func(attempts int) models.ExchangeReceiptDB {
+		return models.ExchangeReceiptDB{
+			ExchangeReceipt: models.ExchangeReceipt{
+				TransactionID: uuid.New(),
+				Operation:     models.OperationExchange,
+				FromCurrency:  models.USD,
+				ToCurrency:    models.EUR,
+				Amount:        money.MustParse("100"),
+				ToAmount:      money.MustParse("90"),
+				Rate:          0.9,
+				ExecutedAt:    time.Now().UTC(),
+			},
+			Attempts: attempts,
+		}
+	}
+
+	t.Run("record", func(t *testing.T) {
+		receipt := newReceipt(0).ExchangeReceipt
+		store.EXPECT().Save(ctx, receipt).Return(nil)
+
+		assert.NoError(t, svc.Record(ctx, receipt))
+	})
+
+	t.Run("publish keyed by transaction ID", func(t *testing.T) {
+		receipt := newReceipt(0)
+		store.EXPECT().ListDue(ctx, exchangeReceiptBatchSize).Return([]models.ExchangeReceiptDB{receipt}, nil)
+		writer.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+			key := receipt.TransactionID.String()
+			assert.Len(t, msgs, 1)
+			assert.Equal(t, key, string(msgs[0].Key))
+			assert.Equal(t, []kafka.Header{{Key: "idempotency-key", Value: []byte(key)}}, msgs[0].Headers)
+
+			var got models.ExchangeReceipt
+			assert.NoError(t, json.Unmarshal(msgs[0].Value, &got))
+			assert.Equal(t, receipt.TransactionID, got.TransactionID)
+			assert.Equal(t, receipt.ToAmount, got.ToAmount)
+			return nil
+		})
+		store.EXPECT().MarkPublished(ctx, receipt.TransactionID).Return(nil)
+
+		assert.NoError(t, svc.PublishPending(ctx))
+	})
+
+	t.Run("failed delivery is postponed", func(t *testing.T) {
+		failed, next := newReceipt(2), newReceipt(0)
+		store.EXPECT().ListDue(ctx, exchangeReceiptBatchSize).Return([]models.ExchangeReceiptDB{failed, next}, nil)
+		writer.EXPECT().WriteMessages(ctx, gomock.Any()).Return(errors.New("broker down"))
+		store.EXPECT().MarkFailed(ctx, failed.TransactionID, gomock.Any(), "broker down").
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, nextAttemptAt time.Time, _ string) error {
+				assert.WithinDuration(t, time.Now().Add(20*time.Second), nextAttemptAt, time.Second)
+				return nil
+			})
+
+		assert.EqualError(t, svc.PublishPending(ctx), "broker down")
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		store.EXPECT().ListDue(ctx, exchangeReceiptBatchSize).Return(nil, errors.New("db error"))
+
+		assert.EqualError(t, svc.PublishPending(ctx), "db error")
+	})
+}
+
+func TestExchangeReceiptBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, exchangeReceiptBackoff(0))
+	assert.Equal(t, 10*time.Second, exchangeReceiptBackoff(1))
+	assert.Equal(t, 40*time.Second, exchangeReceiptBackoff(3))
+	assert.Equal(t, time.Hour, exchangeReceiptBackoff(20))
+}
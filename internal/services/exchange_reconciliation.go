@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+const (
+	// ExchangeReconciliationWindow is the period reconciled by default and by the background job.
+	ExchangeReconciliationWindow = 24 * time.Hour
+	// ExchangeReconciliationSettle is how long before now reconciliation stops by default,
+	// so receipts still being delivered to the exchanger are not reported as missing.
+	ExchangeReconciliationSettle = time.Hour
+	// ExchangeReconciliationMaxDays is the longest period of one report.
+	ExchangeReconciliationMaxDays = 31
+	// exchangeRateTolerance is the relative difference of rates treated as equal,
+	// absorbing float32 rounding on the way to and from the exchanger.
+	exchangeRateTolerance = 1e-6
+)
+
+var (
+	// ErrExchangeReconciliationDisabled is returned when no exchanger export is configured.
+	ErrExchangeReconciliationDisabled = errors.New("exchange reconciliation is not configured")
+	// ErrExchangeMismatch is returned when executed exchanges disagree with the exchanger's records.
+	ErrExchangeMismatch = errors.New("exchanges do not match the exchanger's records")
+)
+
+// ExchangeReceiptLister reads the receipts of executed conversions.
+type ExchangeReceiptLister interface {
+	ListExecuted(ctx context.Context, from, to time.Time) ([]models.ExchangeReceiptDB, error) // Returns the receipts executed in [from, to)
+}
+
+// ExchangeRecordSource reads the exchanger's records of conversions.
+type ExchangeRecordSource interface {
+	ListExchanges(ctx context.Context, from, to time.Time) ([]models.ExchangeReceipt, error) // Returns the exchanger's records executed in [from, to)
+}
+
+// ExchangeReconciliationService compares the conversions executed by the wallet, with the
+// amounts and rates applied, against the exchanger's records of the published receipts,
+// so finance can settle the exchanged volume with the provider.
+type ExchangeReconciliationService struct {
+	receipts ExchangeReceiptLister
+	source   ExchangeRecordSource
+}
+
+// NewExchangeReconciliationService creates a new ExchangeReconciliationService.
+// A nil source disables reconciliation.
+func NewExchangeReconciliationService(receipts ExchangeReceiptLister, source ExchangeRecordSource) *ExchangeReconciliationService {
+	return &ExchangeReconciliationService{receipts: receipts, source: source}
+}
+
+// Report compares the conversions executed in [from, to) with the exchanger's records.
+// A zero to means ExchangeReconciliationSettle before now; a zero from means
+// ExchangeReconciliationWindow before to. Local receipts not yet delivered and unknown
+// to the exchanger are counted as pending rather than reported as missing.
+func (s *ExchangeReconciliationService) Report(ctx context.Context, from, to time.Time) (*models.ExchangeReconciliationReport, error) {
+	if s.source == nil {
+		return nil, ErrExchangeReconciliationDisabled
+	}
+
+	if to.IsZero() {
+		to = time.Now().Add(-ExchangeReconciliationSettle)
+	}
+	to = to.UTC()
+	if from.IsZero() {
+		from = to.Add(-ExchangeReconciliationWindow)
+	}
+	from = from.UTC()
+	if !from.Before(to) || to.Sub(from) > ExchangeReconciliationMaxDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	local, err := s.receipts.ListExecuted(ctx, from, to)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list executed exchanges", "from", from, "to", to, "error", err)
+		return nil, err
+	}
+	remote, err := s.source.ListExchanges(ctx, from, to)
+	if err != nil {
+		return nil, mapExchangerError(err)
+	}
+
+	remoteByID := make(map[uuid.UUID]*models.ExchangeReceipt, len(remote))
+	for i := range remote {
+		remoteByID[remote[i].TransactionID] = &remote[i]
+	}
+
+	report := &models.ExchangeReconciliationReport{From: from, To: to}
+	for i := range local {
+		l := &local[i].ExchangeReceipt
+		r, ok := remoteByID[l.TransactionID]
+		delete(remoteByID, l.TransactionID)
+
+		switch {
+		case !ok && local[i].PublishedAt == nil:
+			report.Pending++
+		case !ok:
+			report.Mismatches = append(report.Mismatches, models.ExchangeMismatch{
+				TransactionID: l.TransactionID, Kind: models.ExchangeMismatchMissingAtExchanger, Local: l,
+			})
+		case l.FromCurrency != r.FromCurrency || l.ToCurrency != r.ToCurrency || l.Amount != r.Amount || l.ToAmount != r.ToAmount:
+			report.Mismatches = append(report.Mismatches, models.ExchangeMismatch{
+				TransactionID: l.TransactionID, Kind: models.ExchangeMismatchAmount, Local: l, Remote: r,
+			})
+		case !ratesEqual(l.Rate, r.Rate):
+			report.Mismatches = append(report.Mismatches, models.ExchangeMismatch{
+				TransactionID: l.TransactionID, Kind: models.ExchangeMismatchRate, Local: l, Remote: r,
+			})
+		default:
+			report.Matched++
+		}
+	}
+	// Remaining records are unknown locally, reported in the exchanger's order
+	for i := range remote {
+		if r, ok := remoteByID[remote[i].TransactionID]; ok {
+			report.Mismatches = append(report.Mismatches, models.ExchangeMismatch{
+				TransactionID: r.TransactionID, Kind: models.ExchangeMismatchUnknownLocally, Remote: r,
+			})
+			delete(remoteByID, r.TransactionID)
+		}
+	}
+	return report, nil
+}
+
+// Reconcile reports the last ExchangeReconciliationWindow, logging every mismatch, and returns
+// ErrExchangeMismatch if there are any. Nothing is corrected automatically: mismatches are
+// settled by finance with the provider.
+func (s *ExchangeReconciliationService) Reconcile(ctx context.Context) error {
+	report, err := s.Report(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to reconcile exchanges", "error", err)
+		return err
+	}
+
+	metrics.ExchangeMismatches.Set(float64(len(report.Mismatches)))
+	for _, m := range report.Mismatches {
+		logger.FromContext(ctx).Errorw("exchange does not match the exchanger's records",
+			"transaction_id", m.TransactionID, "kind", m.Kind, "local", m.Local, "remote", m.Remote)
+	}
+	logger.FromContext(ctx).Infow("exchanges reconciled", "from", report.From, "to", report.To,
+		"matched", report.Matched, "pending", report.Pending, "mismatches", len(report.Mismatches))
+	if len(report.Mismatches) > 0 {
+		return ErrExchangeMismatch
+	}
+	return nil
+}
+
+// ratesEqual reports whether two rates differ by at most exchangeRateTolerance relative to the larger one.
+func ratesEqual(a, b float32) bool {
+	x, y := float64(a), float64(b)
+	return math.Abs(x-y) <= exchangeRateTolerance*math.Max(math.Abs(x), math.Abs(y))
+}
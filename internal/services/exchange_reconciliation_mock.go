@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/exchange_reconciliation.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExchangeReceiptLister is a mock of ExchangeReceiptLister interface.
+type MockExchangeReceiptLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeReceiptListerMockRecorder
+}
+
+// MockExchangeReceiptListerMockRecorder is the mock recorder for MockExchangeReceiptLister.
+type MockExchangeReceiptListerMockRecorder struct {
+	mock *MockExchangeReceiptLister
+}
+
+// NewMockExchangeReceiptLister creates a new mock instance.
+func NewMockExchangeReceiptLister(ctrl *gomock.Controller) *MockExchangeReceiptLister {
+	mock := &MockExchangeReceiptLister{ctrl: ctrl}
+	mock.recorder = &MockExchangeReceiptListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeReceiptLister) EXPECT() *MockExchangeReceiptListerMockRecorder {
+	return m.recorder
+}
+
+// ListExecuted mocks base method.
+func (m *MockExchangeReceiptLister) ListExecuted(ctx context.Context, from, to time.Time) ([]models.ExchangeReceiptDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExecuted", ctx, from, to)
+	ret0, _ := ret[0].([]models.ExchangeReceiptDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExecuted indicates an expected call of ListExecuted.
+func (mr *MockExchangeReceiptListerMockRecorder) ListExecuted(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExecuted", reflect.TypeOf((*MockExchangeReceiptLister)(nil).ListExecuted), ctx, from, to)
+}
+
+// MockExchangeRecordSource is a mock of ExchangeRecordSource interface.
+type MockExchangeRecordSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRecordSourceMockRecorder
+}
+
+// MockExchangeRecordSourceMockRecorder is the mock recorder for MockExchangeRecordSource.
+type MockExchangeRecordSourceMockRecorder struct {
+	mock *MockExchangeRecordSource
+}
+
+// NewMockExchangeRecordSource creates a new mock instance.
+func NewMockExchangeRecordSource(ctrl *gomock.Controller) *MockExchangeRecordSource {
+	mock := &MockExchangeRecordSource{ctrl: ctrl}
+	mock.recorder = &MockExchangeRecordSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRecordSource) EXPECT() *MockExchangeRecordSourceMockRecorder {
+	return m.recorder
+}
+
+// ListExchanges mocks base method.
+func (m *MockExchangeRecordSource) ListExchanges(ctx context.Context, from, to time.Time) ([]models.ExchangeReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchanges", ctx, from, to)
+	ret0, _ := ret[0].([]models.ExchangeReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchanges indicates an expected call of ListExchanges.
+func (mr *MockExchangeRecordSourceMockRecorder) ListExchanges(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchanges", reflect.TypeOf((*MockExchangeRecordSource)(nil).ListExchanges), ctx, from, to)
+}
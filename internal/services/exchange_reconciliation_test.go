@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// newReconciledReceipt creates a USD to EUR receipt, delivered to the exchanger if published.
+func newReconciledReceipt(published bool) models.ExchangeReceiptDB {
+	receipt := models.ExchangeReceiptDB{ExchangeReceipt: models.ExchangeReceipt{
+		TransactionID: uuid.New(),
+		Operation:     models.OperationExchange,
+		FromCurrency:  models.USD,
+		ToCurrency:    models.EUR,
+		Amount:        money.MustParse("100"),
+		ToAmount:      money.MustParse("90"),
+		Rate:          0.9,
+		ExecutedAt:    time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC),
+	}}
+	if published {
+		receipt.PublishedAt = &receipt.ExecutedAt
+	}
+	return receipt
+}
+
+func TestExchangeReconciliationService_Report(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	receipts := NewMockExchangeReceiptLister(ctrl)
+	source := NewMockExchangeRecordSource(ctrl)
+	svc := NewExchangeReconciliationService(receipts, source)
+
+	t.Run("every kind of mismatch", func(t *testing.T) {
+		matched, pending, missing := newReconciledReceipt(true), newReconciledReceipt(false), newReconciledReceipt(true)
+		amount, rate, unknown := newReconciledReceipt(true), newReconciledReceipt(true), newReconciledReceipt(true)
+
+		remoteAmount, remoteRate, remoteRounded := amount.ExchangeReceipt, rate.ExchangeReceipt, matched.ExchangeReceipt
+		remoteAmount.ToAmount = money.MustParse("89.99")
+		remoteRate.Rate = 0.91
+		remoteRounded.Rate = 0.9000001
+
+		receipts.EXPECT().ListExecuted(ctx, from, to).Return([]models.ExchangeReceiptDB{matched, pending, missing, amount, rate}, nil)
+		source.EXPECT().ListExchanges(ctx, from, to).Return([]models.ExchangeReceipt{
+			unknown.ExchangeReceipt, remoteRate, remoteRounded, remoteAmount,
+		}, nil)
+
+		report, err := svc.Report(ctx, from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, from, report.From)
+		assert.Equal(t, to, report.To)
+		assert.Equal(t, 1, report.Matched)
+		assert.Equal(t, 1, report.Pending)
+
+		kinds := map[uuid.UUID]string{}
+		for _, m := range report.Mismatches {
+			kinds[m.TransactionID] = m.Kind
+		}
+		assert.Equal(t, map[uuid.UUID]string{
+			missing.TransactionID: models.ExchangeMismatchMissingAtExchanger,
+			amount.TransactionID:  models.ExchangeMismatchAmount,
+			rate.TransactionID:    models.ExchangeMismatchRate,
+			unknown.TransactionID: models.ExchangeMismatchUnknownLocally,
+		}, kinds)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := svc.Report(ctx, to, from)
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+
+		_, err = svc.Report(ctx, from, from.Add(32*24*time.Hour))
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+	})
+
+	t.Run("exchanger unavailable", func(t *testing.T) {
+		receipts.EXPECT().ListExecuted(ctx, from, to).Return(nil, nil)
+		source.EXPECT().ListExchanges(ctx, from, to).Return(nil, facades.ErrExchangerUnavailable)
+
+		_, err := svc.Report(ctx, from, to)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := NewExchangeReconciliationService(receipts, nil).Report(ctx, from, to)
+		assert.ErrorIs(t, err, ErrExchangeReconciliationDisabled)
+	})
+}
+
+func TestExchangeReconciliationService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	receipts := NewMockExchangeReceiptLister(ctrl)
+	source := NewMockExchangeRecordSource(ctrl)
+	svc := NewExchangeReconciliationService(receipts, source)
+
+	t.Run("matched", func(t *testing.T) {
+		receipt := newReconciledReceipt(true)
+		receipts.EXPECT().ListExecuted(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, from, to time.Time) ([]models.ExchangeReceiptDB, error) {
+				assert.Equal(t, ExchangeReconciliationWindow, to.Sub(from))
+				assert.WithinDuration(t, time.Now().Add(-ExchangeReconciliationSettle), to, time.Minute)
+				return []models.ExchangeReceiptDB{receipt}, nil
+			})
+		source.EXPECT().ListExchanges(ctx, gomock.Any(), gomock.Any()).Return([]models.ExchangeReceipt{receipt.ExchangeReceipt}, nil)
+
+		assert.NoError(t, svc.Reconcile(ctx))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.ExchangeMismatches))
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		receipts.EXPECT().ListExecuted(ctx, gomock.Any(), gomock.Any()).Return([]models.ExchangeReceiptDB{newReconciledReceipt(true)}, nil)
+		source.EXPECT().ListExchanges(ctx, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		assert.ErrorIs(t, svc.Reconcile(ctx), ErrExchangeMismatch)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ExchangeMismatches))
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		receipts.EXPECT().ListExecuted(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+		assert.EqualError(t, svc.Reconcile(ctx), "db error")
+	})
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+var (
+	// ErrExportNotFound is returned when the export does not exist or belongs to another user.
+	ErrExportNotFound = errors.New("export not found")
+	// ErrUnsupportedExportFormat is returned when an unknown export format is requested.
+	ErrUnsupportedExportFormat = errors.New("unsupported export format")
+)
+
+// exportProcessingTimeout is how long an export may stay in processing before it is
+// claimed again, as the worker building it is then assumed to have stopped.
+const exportProcessingTimeout = 15 * time.Minute
+
+// ExportWriter defines methods for creating and processing export jobs.
+type ExportWriter interface {
+	Create(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error)
+	ClaimPending(ctx context.Context, staleAfter time.Duration) (*models.ExportDB, error)
+	Complete(ctx context.Context, exportID uuid.UUID, content []byte) error
+	Fail(ctx context.Context, exportID uuid.UUID, reason string) error
+}
+
+// ExportReader defines lookup of a user's export.
+type ExportReader interface {
+	GetByID(ctx context.Context, userID, exportID uuid.UUID) (*models.ExportDB, error)
+	GetLatest(ctx context.Context, userID uuid.UUID, format string) (*models.ExportDB, error)
+}
+
+// WalletEventReader defines methods for reading the wallet ledger.
+type WalletEventReader interface {
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletEventDB, error)
+}
+
+// ExportService accepts export requests and generates the files in the background.
+type ExportService struct {
+	writer   ExportWriter
+	reader   ExportReader
+	events   WalletEventReader
+	userData *userDataSources
+}
+
+// ExportOpt configures optional dependencies of an ExportService.
+type ExportOpt func(*ExportService)
+
+// WithUserData enables user_data_zip exports, the GDPR archive of the user's profile,
+// wallets, transactions and login history.
+func WithUserData(users UserByIDReader, wallets WalletReader, transactions TransactionStore, authEvents AuthEventReader) ExportOpt {
+	return func(s *ExportService) {
+		s.userData = &userDataSources{users: users, wallets: wallets, transactions: transactions, authEvents: authEvents}
+	}
+}
+
+// NewExportService creates a new ExportService.
+func NewExportService(writer ExportWriter, reader ExportReader, events WalletEventReader, opts ...ExportOpt) *ExportService {
+	s := &ExportService{
+		writer: writer,
+		reader: reader,
+		events: events,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RequestExport queues an export of the user's data in the given format.
+func (s *ExportService) RequestExport(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error) {
+	if format != models.ExportFormatAccountingCSV && (format != models.ExportFormatUserDataZIP || s.userData == nil) {
+		return uuid.Nil, ErrUnsupportedExportFormat
+	}
+
+	exportID, err := s.writer.Create(ctx, userID, format)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to create export", "userID", userID, "format", format, "error", err)
+		return uuid.Nil, err
+	}
+	return exportID, nil
+}
+
+// GetExport returns the user's export, including the file once it is completed.
+func (s *ExportService) GetExport(ctx context.Context, userID, exportID uuid.UUID) (*models.ExportDB, error) {
+	export, err := s.reader.GetByID(ctx, userID, exportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExportNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to get export", "userID", userID, "exportID", exportID, "error", err)
+		return nil, err
+	}
+	return export, nil
+}
+
+// GetLatestExport returns the user's most recent export in the given format, including
+// the file once it is completed.
+func (s *ExportService) GetLatestExport(ctx context.Context, userID uuid.UUID, format string) (*models.ExportDB, error) {
+	export, err := s.reader.GetLatest(ctx, userID, format)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExportNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to get latest export", "userID", userID, "format", format, "error", err)
+		return nil, err
+	}
+	return export, nil
+}
+
+// ProcessPending generates files for pending exports until none are left. Exports left in
+// processing for longer than exportProcessingTimeout are generated again.
+// A failure to build one export marks it as failed and does not stop the others.
+func (s *ExportService) ProcessPending(ctx context.Context) error {
+	for {
+		export, err := s.writer.ClaimPending(ctx, exportProcessingTimeout)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to claim pending export", "error", err)
+			return err
+		}
+
+		content, err := s.build(ctx, export)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to build export", "exportID", export.ExportID, "format", export.Format, "error", err)
+			if err := s.writer.Fail(ctx, export.ExportID, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.writer.Complete(ctx, export.ExportID, content); err != nil {
+			logger.FromContext(ctx).Errorw("failed to store export", "exportID", export.ExportID, "error", err)
+			return err
+		}
+		logger.FromContext(ctx).Infow("export completed", "exportID", export.ExportID, "format", export.Format, "size", len(content))
+	}
+}
+
+// build generates the file for an export.
+func (s *ExportService) build(ctx context.Context, export *models.ExportDB) ([]byte, error) {
+	switch export.Format {
+	case models.ExportFormatAccountingCSV:
+		events, err := s.events.ListByUserID(ctx, export.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return BuildAccountingCSV(events)
+	case models.ExportFormatUserDataZIP:
+		if s.userData == nil {
+			return nil, ErrUnsupportedExportFormat
+		}
+		data, err := s.userData.collect(ctx, export.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return BuildUserDataArchive(data)
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+}
@@ -0,0 +1,187 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/export.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExportWriter is a mock of ExportWriter interface.
+type MockExportWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportWriterMockRecorder
+}
+
+// MockExportWriterMockRecorder is the mock recorder for MockExportWriter.
+type MockExportWriterMockRecorder struct {
+	mock *MockExportWriter
+}
+
+// NewMockExportWriter creates a new mock instance.
+func NewMockExportWriter(ctrl *gomock.Controller) *MockExportWriter {
+	mock := &MockExportWriter{ctrl: ctrl}
+	mock.recorder = &MockExportWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportWriter) EXPECT() *MockExportWriterMockRecorder {
+	return m.recorder
+}
+
+// ClaimPending mocks base method.
+func (m *MockExportWriter) ClaimPending(ctx context.Context, staleAfter time.Duration) (*models.ExportDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPending", ctx, staleAfter)
+	ret0, _ := ret[0].(*models.ExportDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPending indicates an expected call of ClaimPending.
+func (mr *MockExportWriterMockRecorder) ClaimPending(ctx, staleAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPending", reflect.TypeOf((*MockExportWriter)(nil).ClaimPending), ctx, staleAfter)
+}
+
+// Complete mocks base method.
+func (m *MockExportWriter) Complete(ctx context.Context, exportID uuid.UUID, content []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, exportID, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockExportWriterMockRecorder) Complete(ctx, exportID, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockExportWriter)(nil).Complete), ctx, exportID, content)
+}
+
+// Create mocks base method.
+func (m *MockExportWriter) Create(ctx context.Context, userID uuid.UUID, format string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, format)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExportWriterMockRecorder) Create(ctx, userID, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExportWriter)(nil).Create), ctx, userID, format)
+}
+
+// Fail mocks base method.
+func (m *MockExportWriter) Fail(ctx context.Context, exportID uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, exportID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockExportWriterMockRecorder) Fail(ctx, exportID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockExportWriter)(nil).Fail), ctx, exportID, reason)
+}
+
+// MockExportReader is a mock of ExportReader interface.
+type MockExportReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportReaderMockRecorder
+}
+
+// MockExportReaderMockRecorder is the mock recorder for MockExportReader.
+type MockExportReaderMockRecorder struct {
+	mock *MockExportReader
+}
+
+// NewMockExportReader creates a new mock instance.
+func NewMockExportReader(ctrl *gomock.Controller) *MockExportReader {
+	mock := &MockExportReader{ctrl: ctrl}
+	mock.recorder = &MockExportReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportReader) EXPECT() *MockExportReaderMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockExportReader) GetByID(ctx context.Context, userID, exportID uuid.UUID) (*models.ExportDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID, exportID)
+	ret0, _ := ret[0].(*models.ExportDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockExportReaderMockRecorder) GetByID(ctx, userID, exportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockExportReader)(nil).GetByID), ctx, userID, exportID)
+}
+
+// GetLatest mocks base method.
+func (m *MockExportReader) GetLatest(ctx context.Context, userID uuid.UUID, format string) (*models.ExportDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatest", ctx, userID, format)
+	ret0, _ := ret[0].(*models.ExportDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockExportReaderMockRecorder) GetLatest(ctx, userID, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockExportReader)(nil).GetLatest), ctx, userID, format)
+}
+
+// MockWalletEventReader is a mock of WalletEventReader interface.
+type MockWalletEventReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletEventReaderMockRecorder
+}
+
+// MockWalletEventReaderMockRecorder is the mock recorder for MockWalletEventReader.
+type MockWalletEventReaderMockRecorder struct {
+	mock *MockWalletEventReader
+}
+
+// NewMockWalletEventReader creates a new mock instance.
+func NewMockWalletEventReader(ctrl *gomock.Controller) *MockWalletEventReader {
+	mock := &MockWalletEventReader{ctrl: ctrl}
+	mock.recorder = &MockWalletEventReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletEventReader) EXPECT() *MockWalletEventReaderMockRecorder {
+	return m.recorder
+}
+
+// ListByUserID mocks base method.
+func (m *MockWalletEventReader) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletEventDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.WalletEventDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockWalletEventReaderMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockWalletEventReader)(nil).ListByUserID), ctx, userID)
+}
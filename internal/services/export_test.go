@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportService_RequestExport(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockExportWriter(ctrl)
+	svc := NewExportService(writer, nil, nil)
+
+	writer.EXPECT().Create(ctx, userID, models.ExportFormatAccountingCSV).Return(exportID, nil)
+	got, err := svc.RequestExport(ctx, userID, models.ExportFormatAccountingCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, exportID, got)
+
+	_, err = svc.RequestExport(ctx, userID, "xlsx")
+	assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+
+	writer.EXPECT().Create(ctx, userID, models.ExportFormatAccountingCSV).Return(uuid.Nil, errors.New("db error"))
+	_, err = svc.RequestExport(ctx, userID, models.ExportFormatAccountingCSV)
+	assert.EqualError(t, err, "db error")
+}
+
+func TestExportService_GetExport(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reader := NewMockExportReader(ctrl)
+	svc := NewExportService(nil, reader, nil)
+
+	reader.EXPECT().GetByID(ctx, userID, exportID).Return(&models.ExportDB{ExportID: exportID}, nil)
+	export, err := svc.GetExport(ctx, userID, exportID)
+	assert.NoError(t, err)
+	assert.Equal(t, exportID, export.ExportID)
+
+	reader.EXPECT().GetByID(ctx, userID, exportID).Return(nil, sql.ErrNoRows)
+	_, err = svc.GetExport(ctx, userID, exportID)
+	assert.ErrorIs(t, err, ErrExportNotFound)
+
+	reader.EXPECT().GetByID(ctx, userID, exportID).Return(nil, errors.New("db error"))
+	_, err = svc.GetExport(ctx, userID, exportID)
+	assert.EqualError(t, err, "db error")
+}
+
+func TestExportService_ProcessPending(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockExportWriter(ctrl)
+	events := NewMockWalletEventReader(ctrl)
+	svc := NewExportService(writer, nil, events)
+
+	ok := &models.ExportDB{ExportID: uuid.New(), UserID: userID, Format: models.ExportFormatAccountingCSV}
+	broken := &models.ExportDB{ExportID: uuid.New(), UserID: userID, Format: models.ExportFormatAccountingCSV}
+
+	gomock.InOrder(
+		writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(ok, nil),
+		events.EXPECT().ListByUserID(ctx, userID).Return([]models.WalletEventDB{
+			{EventID: 1, UserID: userID, Currency: models.USD, Operation: models.OperationDeposit, Amount: money.MustParse("10")},
+		}, nil),
+		writer.EXPECT().Complete(ctx, ok.ExportID, gomock.Any()).Return(nil),
+		writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(broken, nil),
+		events.EXPECT().ListByUserID(ctx, userID).Return(nil, errors.New("read error")),
+		writer.EXPECT().Fail(ctx, broken.ExportID, "read error").Return(nil),
+		writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(nil, sql.ErrNoRows),
+	)
+
+	assert.NoError(t, svc.ProcessPending(ctx))
+}
+
+func TestExportService_ProcessPending_ClaimError(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockExportWriter(ctrl)
+	svc := NewExportService(writer, nil, nil)
+
+	writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(nil, errors.New("db error"))
+	assert.EqualError(t, svc.ProcessPending(ctx), "db error")
+}
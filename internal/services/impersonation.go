@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/jwt"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ErrCannotImpersonateAdmin is returned when the impersonation target is an admin.
+var ErrCannotImpersonateAdmin = errors.New("admins cannot be impersonated")
+
+// UserByIDReader defines lookup of a user by ID.
+type UserByIDReader interface {
+	GetByID(ctx context.Context, userID uuid.UUID) (*models.UserDB, error)
+}
+
+// AuditWriter appends records to the audit trail.
+type AuditWriter interface {
+	Save(ctx context.Context, actorID uuid.UUID, action string, targetID *uuid.UUID, details map[string]any) error
+}
+
+// ImpersonationService issues short-lived tokens that let support staff act as a user.
+type ImpersonationService struct {
+	users UserByIDReader
+	audit AuditWriter
+	jwt   JWTGenerator
+	ttl   time.Duration
+}
+
+// NewImpersonationService creates a new ImpersonationService.
+func NewImpersonationService(users UserByIDReader, audit AuditWriter, jwt JWTGenerator, ttl time.Duration) *ImpersonationService {
+	return &ImpersonationService{
+		users: users,
+		audit: audit,
+		jwt:   jwt,
+		ttl:   ttl,
+	}
+}
+
+// Impersonate issues a token for userID carrying an act claim that identifies adminID.
+// The token is only returned once the action is recorded in the audit trail.
+func (s *ImpersonationService) Impersonate(ctx context.Context, adminID, userID uuid.UUID) (token string, expiresIn time.Duration, err error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Warnw("impersonation target does not exist", "adminID", adminID, "userID", userID)
+			return "", 0, ErrUserDoesNotExist
+		}
+		logger.FromContext(ctx).Errorw("failed to get impersonation target", "userID", userID, "error", err)
+		return "", 0, err
+	}
+	if user.Role == models.RoleAdmin {
+		logger.FromContext(ctx).Warnw("attempt to impersonate admin", "adminID", adminID, "userID", userID)
+		return "", 0, ErrCannotImpersonateAdmin
+	}
+
+	token, err = s.jwt.Generate(ctx, user.UserID,
+		jwt.WithRole(user.Role),
+		jwt.WithActor(adminID),
+		jwt.WithTTL(s.ttl),
+	)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to generate impersonation token", "adminID", adminID, "userID", userID, "error", err)
+		return "", 0, err
+	}
+
+	details := map[string]any{"ttl_seconds": int(s.ttl.Seconds())}
+	if err := s.audit.Save(ctx, adminID, models.AuditActionImpersonate, &userID, details); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit impersonation", "adminID", adminID, "userID", userID, "error", err)
+		return "", 0, err
+	}
+
+	logger.FromContext(ctx).Infow("impersonation token issued", "adminID", adminID, "userID", userID, "ttl", s.ttl)
+	return token, s.ttl, nil
+}
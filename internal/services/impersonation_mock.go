@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/impersonation.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockUserByIDReader is a mock of UserByIDReader interface.
+type MockUserByIDReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserByIDReaderMockRecorder
+}
+
+// MockUserByIDReaderMockRecorder is the mock recorder for MockUserByIDReader.
+type MockUserByIDReaderMockRecorder struct {
+	mock *MockUserByIDReader
+}
+
+// NewMockUserByIDReader creates a new mock instance.
+func NewMockUserByIDReader(ctrl *gomock.Controller) *MockUserByIDReader {
+	mock := &MockUserByIDReader{ctrl: ctrl}
+	mock.recorder = &MockUserByIDReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserByIDReader) EXPECT() *MockUserByIDReaderMockRecorder {
+	return m.recorder
+}
+
+// GetByID mocks base method.
+func (m *MockUserByIDReader) GetByID(ctx context.Context, userID uuid.UUID) (*models.UserDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID)
+	ret0, _ := ret[0].(*models.UserDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserByIDReaderMockRecorder) GetByID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserByIDReader)(nil).GetByID), ctx, userID)
+}
+
+// MockAuditWriter is a mock of AuditWriter interface.
+type MockAuditWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditWriterMockRecorder
+}
+
+// MockAuditWriterMockRecorder is the mock recorder for MockAuditWriter.
+type MockAuditWriterMockRecorder struct {
+	mock *MockAuditWriter
+}
+
+// NewMockAuditWriter creates a new mock instance.
+func NewMockAuditWriter(ctrl *gomock.Controller) *MockAuditWriter {
+	mock := &MockAuditWriter{ctrl: ctrl}
+	mock.recorder = &MockAuditWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditWriter) EXPECT() *MockAuditWriterMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockAuditWriter) Save(ctx context.Context, actorID uuid.UUID, action string, targetID *uuid.UUID, details map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, actorID, action, targetID, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAuditWriterMockRecorder) Save(ctx, actorID, action, targetID, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAuditWriter)(nil).Save), ctx, actorID, action, targetID, details)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	userID := uuid.New()
+	ttl := 15 * time.Minute
+
+	tests := []struct {
+		name      string
+		setup     func(users *MockUserByIDReader, audit *MockAuditWriter, jwt *MockJWTGenerator)
+		wantToken string
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setup: func(users *MockUserByIDReader, audit *MockAuditWriter, jwt *MockJWTGenerator) {
+				users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Role: models.RoleUser}, nil)
+				jwt.EXPECT().Generate(ctx, userID, gomock.Any(), gomock.Any(), gomock.Any()).Return("imp-token", nil)
+				audit.EXPECT().Save(ctx, adminID, models.AuditActionImpersonate, &userID, gomock.Any()).Return(nil)
+			},
+			wantToken: "imp-token",
+		},
+		{
+			name: "user not found",
+			setup: func(users *MockUserByIDReader, audit *MockAuditWriter, jwt *MockJWTGenerator) {
+				users.EXPECT().GetByID(ctx, userID).Return(nil, sql.ErrNoRows)
+			},
+			wantErr: ErrUserDoesNotExist,
+		},
+		{
+			name: "target is admin",
+			setup: func(users *MockUserByIDReader, audit *MockAuditWriter, jwt *MockJWTGenerator) {
+				users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Role: models.RoleAdmin}, nil)
+			},
+			wantErr: ErrCannotImpersonateAdmin,
+		},
+		{
+			name: "jwt error",
+			setup: func(users *MockUserByIDReader, audit *MockAuditWriter, jwt *MockJWTGenerator) {
+				users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Role: models.RoleUser}, nil)
+				jwt.EXPECT().Generate(ctx, userID, gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("jwt error"))
+			},
+			wantErr: errors.New("jwt error"),
+		},
+		{
+			name: "audit error",
+			setup: func(users *MockUserByIDReader, audit *MockAuditWriter, jwt *MockJWTGenerator) {
+				users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Role: models.RoleUser}, nil)
+				jwt.EXPECT().Generate(ctx, userID, gomock.Any(), gomock.Any(), gomock.Any()).Return("imp-token", nil)
+				audit.EXPECT().Save(ctx, adminID, models.AuditActionImpersonate, &userID, gomock.Any()).Return(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := NewMockUserByIDReader(ctrl)
+			audit := NewMockAuditWriter(ctrl)
+			jwt := NewMockJWTGenerator(ctrl)
+			tt.setup(users, audit, jwt)
+
+			svc := NewImpersonationService(users, audit, jwt, ttl)
+			token, expiresIn, err := svc.Impersonate(ctx, adminID, userID)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				assert.Empty(t, token)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+			assert.Equal(t, ttl, expiresIn)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ErrLedgerMismatch is returned when wallet balances disagree with the ledger.
+var ErrLedgerMismatch = errors.New("wallet balances do not match the ledger")
+
+// LedgerStore reads the ledger for reconciliation.
+type LedgerStore interface {
+	Mismatches(ctx context.Context) ([]models.LedgerMismatch, error) // Returns the wallets whose balance differs from their entries
+}
+
+// LedgerService reconciles the balances stored in wallets with the double-entry ledger.
+// Both are written in the same statements, so any mismatch points to a change made
+// outside the wallet repositories, e.g. a manual fix in the database.
+type LedgerService struct {
+	store LedgerStore
+}
+
+// NewLedgerService creates a new LedgerService.
+func NewLedgerService(store LedgerStore) *LedgerService {
+	return &LedgerService{store: store}
+}
+
+// Reconcile logs every wallet whose balance differs from the sum of its ledger entries
+// and returns ErrLedgerMismatch if there are any. Balances are never corrected
+// automatically: the ledger is the audit trail, and a fix is a new ledger transaction.
+func (s *LedgerService) Reconcile(ctx context.Context) error {
+	mismatches, err := s.store.Mismatches(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to reconcile ledger", "error", err)
+		return err
+	}
+
+	metrics.LedgerMismatches.Set(float64(len(mismatches)))
+	for _, m := range mismatches {
+		logger.FromContext(ctx).Errorw("wallet balance does not match the ledger",
+			"userID", m.UserID, "currency", m.Currency, "balance", m.Balance, "ledger_balance", m.LedgerBalance)
+	}
+	if len(mismatches) > 0 {
+		return ErrLedgerMismatch
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/ledger.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockLedgerStore is a mock of LedgerStore interface.
+type MockLedgerStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerStoreMockRecorder
+}
+
+// MockLedgerStoreMockRecorder is the mock recorder for MockLedgerStore.
+type MockLedgerStoreMockRecorder struct {
+	mock *MockLedgerStore
+}
+
+// NewMockLedgerStore creates a new mock instance.
+func NewMockLedgerStore(ctrl *gomock.Controller) *MockLedgerStore {
+	mock := &MockLedgerStore{ctrl: ctrl}
+	mock.recorder = &MockLedgerStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerStore) EXPECT() *MockLedgerStoreMockRecorder {
+	return m.recorder
+}
+
+// Mismatches mocks base method.
+func (m *MockLedgerStore) Mismatches(ctx context.Context) ([]models.LedgerMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mismatches", ctx)
+	ret0, _ := ret[0].([]models.LedgerMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Mismatches indicates an expected call of Mismatches.
+func (mr *MockLedgerStoreMockRecorder) Mismatches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mismatches", reflect.TypeOf((*MockLedgerStore)(nil).Mismatches), ctx)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockLedgerStore(ctrl)
+	svc := NewLedgerService(store)
+
+	t.Run("balanced", func(t *testing.T) {
+		store.EXPECT().Mismatches(ctx).Return(nil, nil)
+		assert.NoError(t, svc.Reconcile(ctx))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.LedgerMismatches))
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		store.EXPECT().Mismatches(ctx).Return([]models.LedgerMismatch{
+			{UserID: uuid.New(), Currency: models.USD, Balance: money.MustParse("100"), LedgerBalance: money.MustParse("90")},
+		}, nil)
+		assert.ErrorIs(t, svc.Reconcile(ctx), ErrLedgerMismatch)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.LedgerMismatches))
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store.EXPECT().Mismatches(ctx).Return(nil, errors.New("db error"))
+		assert.EqualError(t, svc.Reconcile(ctx), "db error")
+	})
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/segmentio/kafka-go"
+)
+
+// loginAlertHistoryLimit is the number of previous logins a new login is compared with.
+const loginAlertHistoryLimit = 100
+
+// LoginAlertService detects logins from a new country or device,
+// publishes a security alert and notifies the user.
+type LoginAlertService struct {
+	events      AuthEventReader
+	prefs       NotificationPreferenceReader
+	notifier    Notifier
+	kafkaWriter EventPublisher
+}
+
+// NewLoginAlertService creates a new LoginAlertService.
+// A nil kafkaWriter disables publishing of security alerts.
+func NewLoginAlertService(events AuthEventReader, prefs NotificationPreferenceReader, notifier Notifier, kafkaWriter EventPublisher) *LoginAlertService {
+	return &LoginAlertService{
+		events:      events,
+		prefs:       prefs,
+		notifier:    notifier,
+		kafkaWriter: kafkaWriter,
+	}
+}
+
+// Check compares a successful login with the user's previous ones and raises an alert
+// if it comes from a new country or device. It must run before the login is recorded.
+// The first login of a user never raises an alert.
+func (s *LoginAlertService) Check(ctx context.Context, user *models.UserDB, client models.ClientInfo) error {
+	history, err := s.events.ListByUserID(ctx, user.UserID, []string{models.AuthEventLoginSuccess}, loginAlertHistoryLimit)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get login history for alerts", "userID", user.UserID, "error", err)
+		return err
+	}
+
+	reasons := suspiciousLoginReasons(history, client)
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	alert := models.SecurityAlert{
+		AlertID:   uuid.New(),
+		UserID:    user.UserID,
+		Reasons:   reasons,
+		IP:        client.IP,
+		Country:   client.Country,
+		UserAgent: client.UserAgent,
+		CreatedAt: time.Now().UTC(),
+	}
+	logger.FromContext(ctx).Warnw("suspicious login", "userID", user.UserID, "reasons", reasons, "ip", client.IP, "country", client.Country)
+
+	s.publishAlert(ctx, alert)
+	s.notifyUser(ctx, user, alert)
+	return nil
+}
+
+// suspiciousLoginReasons returns why the login differs from the previous ones.
+// The country is only compared once a previous login has a known country.
+func suspiciousLoginReasons(history []models.AuthEventDB, client models.ClientInfo) []string {
+	if len(history) == 0 {
+		return nil
+	}
+
+	knownDevice, knownCountry, countryTracked := false, false, false
+	for _, event := range history {
+		if event.UserAgent == client.UserAgent {
+			knownDevice = true
+		}
+		if event.Country != "" {
+			countryTracked = true
+			if event.Country == client.Country {
+				knownCountry = true
+			}
+		}
+	}
+
+	var reasons []string
+	if client.Country != "" && countryTracked && !knownCountry {
+		reasons = append(reasons, models.SecurityAlertNewCountry)
+	}
+	if !knownDevice {
+		reasons = append(reasons, models.SecurityAlertNewDevice)
+	}
+	return reasons
+}
+
+// publishAlert publishes the alert to Kafka. Failures are logged.
+func (s *LoginAlertService) publishAlert(ctx context.Context, alert models.SecurityAlert) {
+	if s.kafkaWriter == nil {
+		logger.FromContext(ctx).Warnw("Kafka writer not configured, skipping security alert", "alert_id", alert.AlertID)
+		return
+	}
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("Failed to marshal security alert for Kafka", "alert_id", alert.AlertID, "error", err)
+		return
+	}
+
+	msg := kafka.Message{
+		Key:     []byte(alert.UserID.String()),
+		Value:   data,
+		Headers: requestid.KafkaHeaders(ctx),
+	}
+
+	if err := s.kafkaWriter.WriteMessages(ctx, msg); err != nil {
+		logger.FromContext(ctx).Errorw("Failed to publish security alert to Kafka", "alert_id", alert.AlertID, "error", err)
+	} else {
+		logger.FromContext(ctx).Infow("Security alert published to Kafka", "alert_id", alert.AlertID, "userID", alert.UserID)
+	}
+}
+
+// notifyUser sends the alert over the channels the user enabled. Failures are logged.
+func (s *LoginAlertService) notifyUser(ctx context.Context, user *models.UserDB, alert models.SecurityAlert) {
+	prefs, err := getNotificationPreferences(ctx, s.prefs, user.UserID)
+	if err != nil || !prefs.SecurityAlerts {
+		return
+	}
+
+	notification := models.Notification{
+		UserID:  user.UserID,
+		Email:   user.Email,
+		Subject: "New sign-in to your wallet",
+		Body:    loginAlertBody(alert),
+	}
+
+	sendNotification(ctx, s.notifier, prefs, notification)
+}
+
+// loginAlertBody describes the login for the user.
+func loginAlertBody(alert models.SecurityAlert) string {
+	var details []string
+	if slices.Contains(alert.Reasons, models.SecurityAlertNewCountry) {
+		details = append(details, "country "+alert.Country)
+	}
+	if slices.Contains(alert.Reasons, models.SecurityAlertNewDevice) {
+		details = append(details, "device "+alert.UserAgent)
+	}
+	return fmt.Sprintf("Your account was signed in to from a new %s (IP %s) at %s. "+
+		"If this was not you, change your password immediately.",
+		strings.Join(details, " and "), alert.IP, alert.CreatedAt.Format(time.RFC1123))
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuspiciousLoginReasons(t *testing.T) {
+	history := []models.AuthEventDB{
+		{UserAgent: "Firefox", Country: "NL"},
+		{UserAgent: "curl/8.0", Country: ""},
+	}
+
+	tests := []struct {
+		name    string
+		history []models.AuthEventDB
+		client  models.ClientInfo
+		want    []string
+	}{
+		{name: "first login", history: nil, client: models.ClientInfo{UserAgent: "Chrome", Country: "US"}},
+		{name: "known device and country", history: history, client: models.ClientInfo{UserAgent: "Firefox", Country: "NL"}},
+		{name: "unknown country is ignored", history: history, client: models.ClientInfo{UserAgent: "curl/8.0"}},
+		{
+			name:    "new country",
+			history: history,
+			client:  models.ClientInfo{UserAgent: "Firefox", Country: "US"},
+			want:    []string{models.SecurityAlertNewCountry},
+		},
+		{
+			name:    "new device",
+			history: history,
+			client:  models.ClientInfo{UserAgent: "Chrome", Country: "NL"},
+			want:    []string{models.SecurityAlertNewDevice},
+		},
+		{
+			name:    "country not tracked yet",
+			history: []models.AuthEventDB{{UserAgent: "Firefox"}},
+			client:  models.ClientInfo{UserAgent: "Firefox", Country: "US"},
+		},
+		{
+			name:    "new country and device",
+			history: history,
+			client:  models.ClientInfo{UserAgent: "Chrome", Country: "US"},
+			want:    []string{models.SecurityAlertNewCountry, models.SecurityAlertNewDevice},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, suspiciousLoginReasons(tt.history, tt.client))
+		})
+	}
+}
+
+func TestLoginAlertService_Check(t *testing.T) {
+	ctx := context.Background()
+	user := &models.UserDB{UserID: uuid.New(), Email: "alice@example.com"}
+	phone := "+79990000000"
+	history := []models.AuthEventDB{{UserAgent: "Firefox", Country: "NL"}}
+	newDevice := models.ClientInfo{IP: "198.51.100.1", UserAgent: "Chrome", Country: "NL"}
+
+	type mocks struct {
+		events   *MockAuthEventReader
+		prefs    *MockNotificationPreferenceReader
+		notifier *MockNotifier
+		kafka    *MockEventPublisher
+	}
+
+	tests := []struct {
+		name    string
+		client  models.ClientInfo
+		setup   func(m mocks)
+		wantErr bool
+	}{
+		{
+			name:   "known login",
+			client: models.ClientInfo{UserAgent: "Firefox", Country: "NL"},
+			setup: func(m mocks) {
+				m.events.EXPECT().ListByUserID(ctx, user.UserID, []string{models.AuthEventLoginSuccess}, loginAlertHistoryLimit).Return(history, nil)
+			},
+		},
+		{
+			name:   "history error",
+			client: newDevice,
+			setup: func(m mocks) {
+				m.events.EXPECT().ListByUserID(ctx, user.UserID, gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name:   "publishes and notifies by default preferences",
+			client: newDevice,
+			setup: func(m mocks) {
+				m.events.EXPECT().ListByUserID(ctx, user.UserID, gomock.Any(), gomock.Any()).Return(history, nil)
+				m.kafka.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+					var alert models.SecurityAlert
+					assert.NoError(t, json.Unmarshal(msgs[0].Value, &alert))
+					assert.Equal(t, user.UserID, alert.UserID)
+					assert.Equal(t, []string{models.SecurityAlertNewDevice}, alert.Reasons)
+					assert.Equal(t, "198.51.100.1", alert.IP)
+					return nil
+				})
+				m.prefs.EXPECT().GetByUserID(ctx, user.UserID).Return(nil, sql.ErrNoRows)
+				m.notifier.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n models.Notification) error {
+					assert.Equal(t, models.NotificationChannelEmail, n.Channel)
+					assert.Equal(t, user.Email, n.Email)
+					return nil
+				})
+			},
+		},
+		{
+			name:   "email and sms",
+			client: newDevice,
+			setup: func(m mocks) {
+				m.events.EXPECT().ListByUserID(ctx, user.UserID, gomock.Any(), gomock.Any()).Return(history, nil)
+				m.kafka.EXPECT().WriteMessages(ctx, gomock.Any()).Return(errors.New("kafka down"))
+				m.prefs.EXPECT().GetByUserID(ctx, user.UserID).Return(&models.NotificationPreferencesDB{
+					UserID: user.UserID, EmailEnabled: true, SMSEnabled: true, Phone: &phone, SecurityAlerts: true,
+				}, nil)
+				m.notifier.EXPECT().Notify(ctx, gomock.Any()).Return(errors.New("smtp down"))
+				m.notifier.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n models.Notification) error {
+					assert.Equal(t, models.NotificationChannelSMS, n.Channel)
+					assert.Equal(t, phone, n.Phone)
+					return nil
+				})
+			},
+		},
+		{
+			name:   "security alerts disabled",
+			client: newDevice,
+			setup: func(m mocks) {
+				m.events.EXPECT().ListByUserID(ctx, user.UserID, gomock.Any(), gomock.Any()).Return(history, nil)
+				m.kafka.EXPECT().WriteMessages(ctx, gomock.Any()).Return(nil)
+				m.prefs.EXPECT().GetByUserID(ctx, user.UserID).Return(&models.NotificationPreferencesDB{
+					UserID: user.UserID, EmailEnabled: true,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mocks{
+				events:   NewMockAuthEventReader(ctrl),
+				prefs:    NewMockNotificationPreferenceReader(ctrl),
+				notifier: NewMockNotifier(ctrl),
+				kafka:    NewMockEventPublisher(ctrl),
+			}
+			tt.setup(m)
+
+			svc := NewLoginAlertService(m.events, m.prefs, m.notifier, m.kafka)
+			err := svc.Check(ctx, user, tt.client)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// Limits for the number of logins returned by the login history.
+const (
+	DefaultLoginHistoryLimit = 20
+	MaxLoginHistoryLimit     = 100
+)
+
+// loginEventTypes are the auth event types shown in the login history.
+var loginEventTypes = []string{models.AuthEventLoginSuccess, models.AuthEventLoginFailure}
+
+// AuthEventReader reads authentication events.
+type AuthEventReader interface {
+	ListByUserID(ctx context.Context, userID uuid.UUID, eventTypes []string, limit int) ([]models.AuthEventDB, error)
+}
+
+// LoginHistoryService lets users review recent logins to their account.
+type LoginHistoryService struct {
+	events AuthEventReader
+}
+
+// NewLoginHistoryService creates a new LoginHistoryService.
+func NewLoginHistoryService(events AuthEventReader) *LoginHistoryService {
+	return &LoginHistoryService{events: events}
+}
+
+// GetLogins returns the user's last successful and failed logins, newest first.
+// A non-positive limit selects the default; larger limits are capped.
+func (s *LoginHistoryService) GetLogins(ctx context.Context, userID uuid.UUID, limit int) ([]models.AuthEventDB, error) {
+	if limit <= 0 {
+		limit = DefaultLoginHistoryLimit
+	}
+	if limit > MaxLoginHistoryLimit {
+		limit = MaxLoginHistoryLimit
+	}
+
+	events, err := s.events.ListByUserID(ctx, userID, loginEventTypes, limit)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get login history", "userID", userID, "error", err)
+		return nil, err
+	}
+	return events, nil
+}
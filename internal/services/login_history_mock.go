@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/login_history.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockAuthEventReader is a mock of AuthEventReader interface.
+type MockAuthEventReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthEventReaderMockRecorder
+}
+
+// MockAuthEventReaderMockRecorder is the mock recorder for MockAuthEventReader.
+type MockAuthEventReaderMockRecorder struct {
+	mock *MockAuthEventReader
+}
+
+// NewMockAuthEventReader creates a new mock instance.
+func NewMockAuthEventReader(ctrl *gomock.Controller) *MockAuthEventReader {
+	mock := &MockAuthEventReader{ctrl: ctrl}
+	mock.recorder = &MockAuthEventReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthEventReader) EXPECT() *MockAuthEventReaderMockRecorder {
+	return m.recorder
+}
+
+// ListByUserID mocks base method.
+func (m *MockAuthEventReader) ListByUserID(ctx context.Context, userID uuid.UUID, eventTypes []string, limit int) ([]models.AuthEventDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID, eventTypes, limit)
+	ret0, _ := ret[0].([]models.AuthEventDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockAuthEventReaderMockRecorder) ListByUserID(ctx, userID, eventTypes, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockAuthEventReader)(nil).ListByUserID), ctx, userID, eventTypes, limit)
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginHistoryService_GetLogins(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := NewMockAuthEventReader(ctrl)
+	svc := NewLoginHistoryService(events)
+
+	logins := []models.AuthEventDB{{UserID: userID, EventType: models.AuthEventLoginSuccess}}
+
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "explicit limit", limit: 5, wantLimit: 5},
+		{name: "default limit", limit: 0, wantLimit: DefaultLoginHistoryLimit},
+		{name: "capped limit", limit: 1000, wantLimit: MaxLoginHistoryLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events.EXPECT().ListByUserID(ctx, userID, loginEventTypes, tt.wantLimit).Return(logins, nil)
+
+			got, err := svc.GetLogins(ctx, userID, tt.limit)
+			assert.NoError(t, err)
+			assert.Equal(t, logins, got)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		events.EXPECT().ListByUserID(ctx, userID, loginEventTypes, 5).Return(nil, errors.New("db error"))
+
+		_, err := svc.GetLogins(ctx, userID, 5)
+		assert.EqualError(t, err, "db error")
+	})
+}
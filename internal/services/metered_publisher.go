@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/segmentio/kafka-go"
+)
+
+// MeteredPublisher counts the events written through a publisher by topic and result.
+type MeteredPublisher struct {
+	EventPublisher
+	topic string // Topic of events without one, the topic of a writer publishing to one topic
+}
+
+// NewMeteredPublisher creates a MeteredPublisher for writer. topic labels the events that
+// carry no topic of their own.
+func NewMeteredPublisher(writer EventPublisher, topic string) *MeteredPublisher {
+	return &MeteredPublisher{EventPublisher: writer, topic: topic}
+}
+
+// WriteMessages writes msgs and counts them as published or failed. A failed write counts
+// all of its events as failed, as writers do not report which of them were written.
+func (p *MeteredPublisher) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	err := p.EventPublisher.WriteMessages(ctx, msgs...)
+	result := metrics.EventPublished
+	if err != nil {
+		result = metrics.EventFailed
+	}
+	for _, msg := range msgs {
+		topic := msg.Topic
+		if topic == "" {
+			topic = p.topic
+		}
+		metrics.PublishedEvents.WithLabelValues(topic, result).Inc()
+	}
+	return err
+}
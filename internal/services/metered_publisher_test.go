@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeteredPublisher(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	writer := NewMockEventPublisher(ctrl)
+	p := NewMeteredPublisher(writer, "security.alerts")
+
+	alertsPublished := metrics.PublishedEvents.WithLabelValues("security.alerts", metrics.EventPublished)
+	txnsFailed := metrics.PublishedEvents.WithLabelValues("transactions", metrics.EventFailed)
+	publishedBefore, failedBefore := testutil.ToFloat64(alertsPublished), testutil.ToFloat64(txnsFailed)
+
+	alert := kafka.Message{Key: []byte("alert-1")}
+	writer.EXPECT().WriteMessages(ctx, alert).Return(nil)
+	assert.NoError(t, p.WriteMessages(ctx, alert))
+
+	// Events carrying a topic are counted under it
+	txns := []kafka.Message{{Topic: "transactions", Key: []byte("txn-1")}, {Topic: "transactions", Key: []byte("txn-2")}}
+	writer.EXPECT().WriteMessages(ctx, txns[0], txns[1]).Return(errors.New("broker down"))
+	assert.EqualError(t, p.WriteMessages(ctx, txns...), "broker down")
+
+	assert.Equal(t, publishedBefore+1, testutil.ToFloat64(alertsPublished))
+	assert.Equal(t, failedBefore+2, testutil.ToFloat64(txnsFailed))
+}
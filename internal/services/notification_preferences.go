@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// ErrPhoneRequired is returned when SMS notifications are enabled without a phone number.
+var ErrPhoneRequired = errors.New("phone is required for sms notifications")
+
+// NotificationPreferenceReader reads per-user notification settings.
+type NotificationPreferenceReader interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferencesDB, error)
+}
+
+// NotificationPreferenceWriter saves per-user notification settings.
+type NotificationPreferenceWriter interface {
+	Save(ctx context.Context, prefs models.NotificationPreferencesDB) error
+}
+
+// NotificationPreferenceService manages how users want to be notified.
+type NotificationPreferenceService struct {
+	reader NotificationPreferenceReader
+	writer NotificationPreferenceWriter
+}
+
+// NewNotificationPreferenceService creates a new NotificationPreferenceService.
+func NewNotificationPreferenceService(reader NotificationPreferenceReader, writer NotificationPreferenceWriter) *NotificationPreferenceService {
+	return &NotificationPreferenceService{reader: reader, writer: writer}
+}
+
+// GetPreferences returns the user's settings, or the defaults if none are saved.
+func (s *NotificationPreferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesDB, error) {
+	return getNotificationPreferences(ctx, s.reader, userID)
+}
+
+// UpdatePreferences replaces the user's settings.
+func (s *NotificationPreferenceService) UpdatePreferences(ctx context.Context, prefs models.NotificationPreferencesDB) error {
+	if prefs.SMSEnabled && (prefs.Phone == nil || *prefs.Phone == "") {
+		return ErrPhoneRequired
+	}
+
+	if err := s.writer.Save(ctx, prefs); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save notification preferences", "userID", prefs.UserID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// getNotificationPreferences loads the user's settings, falling back to the defaults.
+func getNotificationPreferences(ctx context.Context, reader NotificationPreferenceReader, userID uuid.UUID) (models.NotificationPreferencesDB, error) {
+	prefs, err := reader.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DefaultNotificationPreferences(userID), nil
+		}
+		logger.FromContext(ctx).Errorw("failed to get notification preferences", "userID", userID, "error", err)
+		return models.NotificationPreferencesDB{}, err
+	}
+	return *prefs, nil
+}
+
+// sendNotification sends the notification over the channels enabled in prefs: email, and SMS
+// if a phone is set. Failures are logged, so one channel failing does not stop the others.
+func sendNotification(ctx context.Context, notifier Notifier, prefs models.NotificationPreferencesDB, notification models.Notification) {
+	var channels []string
+	if prefs.EmailEnabled {
+		channels = append(channels, models.NotificationChannelEmail)
+	}
+	if prefs.SMSEnabled && prefs.Phone != nil {
+		notification.Phone = *prefs.Phone
+		channels = append(channels, models.NotificationChannelSMS)
+	}
+
+	for _, channel := range channels {
+		notification.Channel = channel
+		if err := notifier.Notify(ctx, notification); err != nil {
+			logger.FromContext(ctx).Errorw("failed to send notification", "userID", notification.UserID, "subject", notification.Subject,
+				"channel", channel, "error", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/notification_preferences.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockNotificationPreferenceReader is a mock of NotificationPreferenceReader interface.
+type MockNotificationPreferenceReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPreferenceReaderMockRecorder
+}
+
+// MockNotificationPreferenceReaderMockRecorder is the mock recorder for MockNotificationPreferenceReader.
+type MockNotificationPreferenceReaderMockRecorder struct {
+	mock *MockNotificationPreferenceReader
+}
+
+// NewMockNotificationPreferenceReader creates a new mock instance.
+func NewMockNotificationPreferenceReader(ctrl *gomock.Controller) *MockNotificationPreferenceReader {
+	mock := &MockNotificationPreferenceReader{ctrl: ctrl}
+	mock.recorder = &MockNotificationPreferenceReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPreferenceReader) EXPECT() *MockNotificationPreferenceReaderMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockNotificationPreferenceReader) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferencesDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].(*models.NotificationPreferencesDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockNotificationPreferenceReaderMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockNotificationPreferenceReader)(nil).GetByUserID), ctx, userID)
+}
+
+// MockNotificationPreferenceWriter is a mock of NotificationPreferenceWriter interface.
+type MockNotificationPreferenceWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPreferenceWriterMockRecorder
+}
+
+// MockNotificationPreferenceWriterMockRecorder is the mock recorder for MockNotificationPreferenceWriter.
+type MockNotificationPreferenceWriterMockRecorder struct {
+	mock *MockNotificationPreferenceWriter
+}
+
+// NewMockNotificationPreferenceWriter creates a new mock instance.
+func NewMockNotificationPreferenceWriter(ctrl *gomock.Controller) *MockNotificationPreferenceWriter {
+	mock := &MockNotificationPreferenceWriter{ctrl: ctrl}
+	mock.recorder = &MockNotificationPreferenceWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPreferenceWriter) EXPECT() *MockNotificationPreferenceWriterMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockNotificationPreferenceWriter) Save(ctx context.Context, prefs models.NotificationPreferencesDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, prefs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockNotificationPreferenceWriterMockRecorder) Save(ctx, prefs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockNotificationPreferenceWriter)(nil).Save), ctx, prefs)
+}
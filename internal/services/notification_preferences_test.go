@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferenceService_GetPreferences(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	reader := NewMockNotificationPreferenceReader(ctrl)
+	svc := NewNotificationPreferenceService(reader, NewMockNotificationPreferenceWriter(ctrl))
+
+	t.Run("defaults", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(nil, sql.ErrNoRows)
+		prefs, err := svc.GetPreferences(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DefaultNotificationPreferences(userID), prefs)
+	})
+
+	t.Run("saved", func(t *testing.T) {
+		saved := &models.NotificationPreferencesDB{UserID: userID, SecurityAlerts: false}
+		reader.EXPECT().GetByUserID(ctx, userID).Return(saved, nil)
+		prefs, err := svc.GetPreferences(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, *saved, prefs)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(nil, errors.New("db error"))
+		_, err := svc.GetPreferences(ctx, userID)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestNotificationPreferenceService_UpdatePreferences(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	phone := "+79990000000"
+	empty := ""
+
+	tests := []struct {
+		name    string
+		prefs   models.NotificationPreferencesDB
+		saveErr error
+		save    bool
+		wantErr error
+	}{
+		{
+			name:  "success",
+			prefs: models.NotificationPreferencesDB{UserID: userID, SMSEnabled: true, Phone: &phone},
+			save:  true,
+		},
+		{
+			name:    "sms without phone",
+			prefs:   models.NotificationPreferencesDB{UserID: userID, SMSEnabled: true},
+			wantErr: ErrPhoneRequired,
+		},
+		{
+			name:    "sms with empty phone",
+			prefs:   models.NotificationPreferencesDB{UserID: userID, SMSEnabled: true, Phone: &empty},
+			wantErr: ErrPhoneRequired,
+		},
+		{
+			name:    "save error",
+			prefs:   models.NotificationPreferencesDB{UserID: userID, EmailEnabled: true},
+			save:    true,
+			saveErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			writer := NewMockNotificationPreferenceWriter(ctrl)
+			if tt.save {
+				writer.EXPECT().Save(ctx, tt.prefs).Return(tt.saveErr)
+			}
+
+			err := NewNotificationPreferenceService(NewMockNotificationPreferenceReader(ctrl), writer).UpdatePreferences(ctx, tt.prefs)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+// BalanceProjectionWriter applies pending wallet events to the balance read model.
+type BalanceProjectionWriter interface {
+	ApplyPending(ctx context.Context, limit int) (int, error)
+}
+
+// BalanceProjector projects wallet events into materialized per-user balances.
+type BalanceProjector struct {
+	repo      BalanceProjectionWriter
+	batchSize int
+}
+
+// NewBalanceProjector creates a new BalanceProjector.
+func NewBalanceProjector(repo BalanceProjectionWriter, batchSize int) *BalanceProjector {
+	return &BalanceProjector{
+		repo:      repo,
+		batchSize: batchSize,
+	}
+}
+
+// Project applies batches of pending events until the backlog is drained.
+func (p *BalanceProjector) Project(ctx context.Context) error {
+	total := 0
+	for {
+		applied, err := p.repo.ApplyPending(ctx, p.batchSize)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to project wallet events", "applied", total, "error", err)
+			return err
+		}
+		total += applied
+		if applied < p.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.FromContext(ctx).Infow("wallet events projected", "applied", total)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/projection.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBalanceProjectionWriter is a mock of BalanceProjectionWriter interface.
+type MockBalanceProjectionWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceProjectionWriterMockRecorder
+}
+
+// MockBalanceProjectionWriterMockRecorder is the mock recorder for MockBalanceProjectionWriter.
+type MockBalanceProjectionWriterMockRecorder struct {
+	mock *MockBalanceProjectionWriter
+}
+
+// NewMockBalanceProjectionWriter creates a new mock instance.
+func NewMockBalanceProjectionWriter(ctrl *gomock.Controller) *MockBalanceProjectionWriter {
+	mock := &MockBalanceProjectionWriter{ctrl: ctrl}
+	mock.recorder = &MockBalanceProjectionWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceProjectionWriter) EXPECT() *MockBalanceProjectionWriterMockRecorder {
+	return m.recorder
+}
+
+// ApplyPending mocks base method.
+func (m *MockBalanceProjectionWriter) ApplyPending(ctx context.Context, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPending", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyPending indicates an expected call of ApplyPending.
+func (mr *MockBalanceProjectionWriterMockRecorder) ApplyPending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPending", reflect.TypeOf((*MockBalanceProjectionWriter)(nil).ApplyPending), ctx, limit)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceProjector_Project(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		setup   func(repo *MockBalanceProjectionWriter)
+		wantErr bool
+	}{
+		{
+			name: "nothing to project",
+			setup: func(repo *MockBalanceProjectionWriter) {
+				repo.EXPECT().ApplyPending(ctx, 10).Return(0, nil)
+			},
+		},
+		{
+			name: "drains full batches",
+			setup: func(repo *MockBalanceProjectionWriter) {
+				gomock.InOrder(
+					repo.EXPECT().ApplyPending(ctx, 10).Return(10, nil),
+					repo.EXPECT().ApplyPending(ctx, 10).Return(10, nil),
+					repo.EXPECT().ApplyPending(ctx, 10).Return(3, nil),
+				)
+			},
+		},
+		{
+			name: "repository error",
+			setup: func(repo *MockBalanceProjectionWriter) {
+				gomock.InOrder(
+					repo.EXPECT().ApplyPending(ctx, 10).Return(10, nil),
+					repo.EXPECT().ApplyPending(ctx, 10).Return(0, errors.New("db error")),
+				)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockBalanceProjectionWriter(ctrl)
+			tt.setup(repo)
+
+			err := NewBalanceProjector(repo, 10).Project(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
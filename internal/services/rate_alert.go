@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	// ErrInvalidRateAlert is returned for alerts with an unknown direction or a non-positive threshold.
+	ErrInvalidRateAlert = errors.New("invalid rate alert")
+	// ErrTooManyRateAlerts is returned when the user already has MaxRateAlertsPerUser alerts.
+	ErrTooManyRateAlerts = errors.New("too many rate alerts")
+	// ErrRateAlertNotFound is returned when the user has no alert with the given ID.
+	ErrRateAlertNotFound = errors.New("rate alert not found")
+)
+
+// MaxRateAlertsPerUser is how many rate alerts a user may register.
+const MaxRateAlertsPerUser = 20
+
+// RateAlertStore keeps the exchange rate alerts of users.
+type RateAlertStore interface {
+	Create(ctx context.Context, alert models.RateAlertDB) (models.RateAlertDB, error) // Saves an armed alert
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.RateAlertDB, error) // Returns the user's alerts
+	// Changes the user's alert and arms it again, sql.ErrNoRows if missing
+	Update(ctx context.Context, userID, alertID uuid.UUID, direction string, threshold float32) (models.RateAlertDB, error)
+	Delete(ctx context.Context, userID, alertID uuid.UUID) error                                           // Removes the user's alert, sql.ErrNoRows if missing
+	ListArmed(ctx context.Context) ([]models.RateAlertDB, error)                                           // Returns the alerts that have not fired
+	MarkTriggered(ctx context.Context, alert models.RateAlertDB, rate float32, at time.Time) (bool, error) // Fires the alert, false if it already fired or changed
+}
+
+// RateQuoter returns the current exchange rate of a currency pair, e.g. *WalletService.
+type RateQuoter interface {
+	ExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (rate float32, stale bool, err error)
+}
+
+// ExchangeRate returns the current rate of a currency pair, preferring the cache.
+// stale reports a cached rate past its TTL, served because the exchanger failed.
+func (s *WalletService) ExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (rate float32, stale bool, err error) {
+	rate, src, err := s.getExchangeRate(ctx, fromCurrency, toCurrency)
+	return rate, src.Stale, err
+}
+
+// RateAlertService lets users register alerts on exchange rates ("notify me when USD→EUR
+// rises above 0.95") and fires them from a background job. A fired alert is published to
+// Kafka and sent to the user over the notification channels they enabled, once; updating
+// the alert arms it again.
+type RateAlertService struct {
+	store       RateAlertStore
+	rates       RateQuoter
+	users       UserByIDReader
+	prefs       NotificationPreferenceReader
+	notifier    Notifier
+	kafkaWriter EventPublisher
+}
+
+// NewRateAlertService creates a new RateAlertService.
+// A nil kafkaWriter disables publishing of fired alerts.
+func NewRateAlertService(
+	store RateAlertStore,
+	rates RateQuoter,
+	users UserByIDReader,
+	prefs NotificationPreferenceReader,
+	notifier Notifier,
+	kafkaWriter EventPublisher,
+) *RateAlertService {
+	return &RateAlertService{
+		store:       store,
+		rates:       rates,
+		users:       users,
+		prefs:       prefs,
+		notifier:    notifier,
+		kafkaWriter: kafkaWriter,
+	}
+}
+
+// Create registers an armed alert of the user on the rate of fromCurrency to toCurrency.
+// The currencies are expected to be validated by the caller.
+func (s *RateAlertService) Create(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency, direction string, threshold float32) (models.RateAlertDB, error) {
+	if !validRateAlert(direction, threshold) {
+		return models.RateAlertDB{}, ErrInvalidRateAlert
+	}
+
+	existing, err := s.store.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list rate alerts", "userID", userID, "error", err)
+		return models.RateAlertDB{}, err
+	}
+	if len(existing) >= MaxRateAlertsPerUser {
+		return models.RateAlertDB{}, ErrTooManyRateAlerts
+	}
+
+	alert, err := s.store.Create(ctx, models.RateAlertDB{
+		AlertID:      uuid.New(),
+		UserID:       userID,
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Direction:    direction,
+		Threshold:    threshold,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to create rate alert", "userID", userID, "error", err)
+		return models.RateAlertDB{}, err
+	}
+	return alert, nil
+}
+
+// List returns the alerts of the user, oldest first.
+func (s *RateAlertService) List(ctx context.Context, userID uuid.UUID) ([]models.RateAlertDB, error) {
+	return s.store.ListByUserID(ctx, userID)
+}
+
+// Update changes the direction and threshold of the user's alert and arms it again.
+func (s *RateAlertService) Update(ctx context.Context, userID, alertID uuid.UUID, direction string, threshold float32) (models.RateAlertDB, error) {
+	if !validRateAlert(direction, threshold) {
+		return models.RateAlertDB{}, ErrInvalidRateAlert
+	}
+
+	alert, err := s.store.Update(ctx, userID, alertID, direction, threshold)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.RateAlertDB{}, ErrRateAlertNotFound
+	}
+	return alert, err
+}
+
+// Delete removes the user's alert.
+func (s *RateAlertService) Delete(ctx context.Context, userID, alertID uuid.UUID) error {
+	err := s.store.Delete(ctx, userID, alertID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRateAlertNotFound
+	}
+	return err
+}
+
+// Watch compares the armed alerts with the current rates and fires the crossed ones.
+// The rate of every pair is read once, from the cache while it is fresh. Stale rates are
+// skipped, and a pair whose rate is unavailable does not hold up the other pairs.
+func (s *RateAlertService) Watch(ctx context.Context) error {
+	alerts, err := s.store.ListArmed(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list armed rate alerts", "error", err)
+		return err
+	}
+
+	type pair struct{ from, to string }
+	rates := make(map[pair]float32)
+	for _, alert := range alerts {
+		p := pair{alert.FromCurrency, alert.ToCurrency}
+		rate, ok := rates[p]
+		if !ok {
+			var stale bool
+			rate, stale, err = s.rates.ExchangeRate(ctx, p.from, p.to)
+			if err != nil || stale {
+				rate = 0
+			}
+			rates[p] = rate
+		}
+		if rate == 0 || !alert.Crossed(rate) {
+			continue
+		}
+
+		if err := s.fire(ctx, alert, rate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fire marks the alert as triggered, then publishes it and notifies the user.
+func (s *RateAlertService) fire(ctx context.Context, alert models.RateAlertDB, rate float32) error {
+	now := time.Now().UTC()
+	triggered, err := s.store.MarkTriggered(ctx, alert, rate, now)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to mark rate alert as triggered", "alert_id", alert.AlertID, "error", err)
+		return err
+	}
+	if !triggered {
+		return nil
+	}
+
+	event := models.RateAlertEvent{
+		AlertID:      alert.AlertID,
+		UserID:       alert.UserID,
+		FromCurrency: alert.FromCurrency,
+		ToCurrency:   alert.ToCurrency,
+		Direction:    alert.Direction,
+		Threshold:    alert.Threshold,
+		Rate:         rate,
+		TriggeredAt:  now,
+	}
+	logger.FromContext(ctx).Infow("rate alert triggered", "alert_id", alert.AlertID, "userID", alert.UserID,
+		"from", alert.FromCurrency, "to", alert.ToCurrency, "threshold", alert.Threshold, "rate", rate)
+
+	s.publishAlert(ctx, event)
+	s.notifyUser(ctx, event)
+	return nil
+}
+
+// publishAlert publishes the fired alert to Kafka. Failures are logged.
+func (s *RateAlertService) publishAlert(ctx context.Context, event models.RateAlertEvent) {
+	if s.kafkaWriter == nil {
+		logger.FromContext(ctx).Warnw("Kafka writer not configured, skipping rate alert", "alert_id", event.AlertID)
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("Failed to marshal rate alert for Kafka", "alert_id", event.AlertID, "error", err)
+		return
+	}
+
+	msg := kafka.Message{
+		Key:     []byte(event.UserID.String()),
+		Value:   data,
+		Headers: requestid.KafkaHeaders(ctx),
+	}
+
+	if err := s.kafkaWriter.WriteMessages(ctx, msg); err != nil {
+		logger.FromContext(ctx).Errorw("Failed to publish rate alert to Kafka", "alert_id", event.AlertID, "error", err)
+	} else {
+		logger.FromContext(ctx).Infow("Rate alert published to Kafka", "alert_id", event.AlertID, "userID", event.UserID)
+	}
+}
+
+// notifyUser sends the fired alert over the channels the user enabled. Failures are logged.
+func (s *RateAlertService) notifyUser(ctx context.Context, event models.RateAlertEvent) {
+	user, err := s.users.GetByID(ctx, event.UserID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get user for rate alert", "userID", event.UserID, "error", err)
+		return
+	}
+	prefs, err := getNotificationPreferences(ctx, s.prefs, event.UserID)
+	if err != nil {
+		return
+	}
+
+	sendNotification(ctx, s.notifier, prefs, models.Notification{
+		UserID:  user.UserID,
+		Email:   user.Email,
+		Subject: fmt.Sprintf("%s→%s rate alert", event.FromCurrency, event.ToCurrency),
+		Body:    rateAlertBody(event),
+	})
+}
+
+// rateAlertBody describes the fired alert for the user.
+func rateAlertBody(event models.RateAlertEvent) string {
+	return fmt.Sprintf("The %s→%s exchange rate is %g, %s your alert threshold of %g (%s).",
+		event.FromCurrency, event.ToCurrency, event.Rate, event.Direction, event.Threshold,
+		event.TriggeredAt.Format(time.RFC1123))
+}
+
+// validRateAlert reports whether the alert fires in a known direction at a positive rate.
+func validRateAlert(direction string, threshold float32) bool {
+	return (direction == models.RateAlertAbove || direction == models.RateAlertBelow) && threshold > 0
+}
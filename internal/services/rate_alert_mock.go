@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/rate_alert.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockRateAlertStore is a mock of RateAlertStore interface.
+type MockRateAlertStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateAlertStoreMockRecorder
+}
+
+// MockRateAlertStoreMockRecorder is the mock recorder for MockRateAlertStore.
+type MockRateAlertStoreMockRecorder struct {
+	mock *MockRateAlertStore
+}
+
+// NewMockRateAlertStore creates a new mock instance.
+func NewMockRateAlertStore(ctrl *gomock.Controller) *MockRateAlertStore {
+	mock := &MockRateAlertStore{ctrl: ctrl}
+	mock.recorder = &MockRateAlertStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateAlertStore) EXPECT() *MockRateAlertStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRateAlertStore) Create(ctx context.Context, alert models.RateAlertDB) (models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, alert)
+	ret0, _ := ret[0].(models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRateAlertStoreMockRecorder) Create(ctx, alert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRateAlertStore)(nil).Create), ctx, alert)
+}
+
+// Delete mocks base method.
+func (m *MockRateAlertStore) Delete(ctx context.Context, userID, alertID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, alertID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRateAlertStoreMockRecorder) Delete(ctx, userID, alertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRateAlertStore)(nil).Delete), ctx, userID, alertID)
+}
+
+// ListArmed mocks base method.
+func (m *MockRateAlertStore) ListArmed(ctx context.Context) ([]models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListArmed", ctx)
+	ret0, _ := ret[0].([]models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListArmed indicates an expected call of ListArmed.
+func (mr *MockRateAlertStoreMockRecorder) ListArmed(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListArmed", reflect.TypeOf((*MockRateAlertStore)(nil).ListArmed), ctx)
+}
+
+// ListByUserID mocks base method.
+func (m *MockRateAlertStore) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockRateAlertStoreMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockRateAlertStore)(nil).ListByUserID), ctx, userID)
+}
+
+// MarkTriggered mocks base method.
+func (m *MockRateAlertStore) MarkTriggered(ctx context.Context, alert models.RateAlertDB, rate float32, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTriggered", ctx, alert, rate, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkTriggered indicates an expected call of MarkTriggered.
+func (mr *MockRateAlertStoreMockRecorder) MarkTriggered(ctx, alert, rate, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTriggered", reflect.TypeOf((*MockRateAlertStore)(nil).MarkTriggered), ctx, alert, rate, at)
+}
+
+// Update mocks base method.
+func (m *MockRateAlertStore) Update(ctx context.Context, userID, alertID uuid.UUID, direction string, threshold float32) (models.RateAlertDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, alertID, direction, threshold)
+	ret0, _ := ret[0].(models.RateAlertDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRateAlertStoreMockRecorder) Update(ctx, userID, alertID, direction, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRateAlertStore)(nil).Update), ctx, userID, alertID, direction, threshold)
+}
+
+// MockRateQuoter is a mock of RateQuoter interface.
+type MockRateQuoter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateQuoterMockRecorder
+}
+
+// MockRateQuoterMockRecorder is the mock recorder for MockRateQuoter.
+type MockRateQuoterMockRecorder struct {
+	mock *MockRateQuoter
+}
+
+// NewMockRateQuoter creates a new mock instance.
+func NewMockRateQuoter(ctrl *gomock.Controller) *MockRateQuoter {
+	mock := &MockRateQuoter{ctrl: ctrl}
+	mock.recorder = &MockRateQuoterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateQuoter) EXPECT() *MockRateQuoterMockRecorder {
+	return m.recorder
+}
+
+// ExchangeRate mocks base method.
+func (m *MockRateQuoter) ExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float32, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeRate", ctx, fromCurrency, toCurrency)
+	ret0, _ := ret[0].(float32)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExchangeRate indicates an expected call of ExchangeRate.
+func (mr *MockRateQuoterMockRecorder) ExchangeRate(ctx, fromCurrency, toCurrency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeRate", reflect.TypeOf((*MockRateQuoter)(nil).ExchangeRate), ctx, fromCurrency, toCurrency)
+}
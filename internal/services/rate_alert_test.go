@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateAlertDB_Crossed(t *testing.T) {
+	above := models.RateAlertDB{Direction: models.RateAlertAbove, Threshold: 0.95}
+	below := models.RateAlertDB{Direction: models.RateAlertBelow, Threshold: 0.9}
+
+	assert.True(t, above.Crossed(0.96))
+	assert.False(t, above.Crossed(0.95))
+	assert.True(t, below.Crossed(0.89))
+	assert.False(t, below.Crossed(0.9))
+}
+
+func TestRateAlertService_Create(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	tests := []struct {
+		name      string
+		direction string
+		threshold float32
+		setup     func(store *MockRateAlertStore)
+		wantErr   error
+	}{
+		{name: "unknown direction", direction: "sideways", threshold: 0.95, wantErr: ErrInvalidRateAlert},
+		{name: "non-positive threshold", direction: models.RateAlertAbove, threshold: 0, wantErr: ErrInvalidRateAlert},
+		{
+			name:      "too many alerts",
+			direction: models.RateAlertAbove,
+			threshold: 0.95,
+			setup: func(store *MockRateAlertStore) {
+				store.EXPECT().ListByUserID(ctx, userID).Return(make([]models.RateAlertDB, MaxRateAlertsPerUser), nil)
+			},
+			wantErr: ErrTooManyRateAlerts,
+		},
+		{
+			name:      "created",
+			direction: models.RateAlertBelow,
+			threshold: 0.9,
+			setup: func(store *MockRateAlertStore) {
+				store.EXPECT().ListByUserID(ctx, userID).Return(nil, nil)
+				store.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, alert models.RateAlertDB) (models.RateAlertDB, error) {
+					assert.NotEqual(t, uuid.Nil, alert.AlertID)
+					assert.Equal(t, userID, alert.UserID)
+					assert.Equal(t, models.RateAlertBelow, alert.Direction)
+					return alert, nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := NewMockRateAlertStore(ctrl)
+			if tt.setup != nil {
+				tt.setup(store)
+			}
+
+			svc := NewRateAlertService(store, nil, nil, nil, nil, nil)
+			alert, err := svc.Create(ctx, userID, models.USD, models.EUR, tt.direction, tt.threshold)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.threshold, alert.Threshold)
+		})
+	}
+}
+
+func TestRateAlertService_UpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	userID, alertID := uuid.New(), uuid.New()
+	ctrl := gomock.NewController(t)
+	store := NewMockRateAlertStore(ctrl)
+	svc := NewRateAlertService(store, nil, nil, nil, nil, nil)
+
+	_, err := svc.Update(ctx, userID, alertID, models.RateAlertAbove, -1)
+	assert.ErrorIs(t, err, ErrInvalidRateAlert)
+
+	store.EXPECT().Update(ctx, userID, alertID, models.RateAlertAbove, float32(1.1)).Return(models.RateAlertDB{}, sql.ErrNoRows)
+	_, err = svc.Update(ctx, userID, alertID, models.RateAlertAbove, 1.1)
+	assert.ErrorIs(t, err, ErrRateAlertNotFound)
+
+	store.EXPECT().Delete(ctx, userID, alertID).Return(sql.ErrNoRows)
+	assert.ErrorIs(t, svc.Delete(ctx, userID, alertID), ErrRateAlertNotFound)
+
+	store.EXPECT().Delete(ctx, userID, alertID).Return(nil)
+	assert.NoError(t, svc.Delete(ctx, userID, alertID))
+}
+
+func TestRateAlertService_Watch(t *testing.T) {
+	ctx := context.Background()
+	user := &models.UserDB{UserID: uuid.New(), Email: "alice@example.com"}
+	usdEUR := models.RateAlertDB{AlertID: uuid.New(), UserID: user.UserID, FromCurrency: models.USD, ToCurrency: models.EUR,
+		Direction: models.RateAlertAbove, Threshold: 0.95}
+	usdEURBelow := models.RateAlertDB{AlertID: uuid.New(), UserID: user.UserID, FromCurrency: models.USD, ToCurrency: models.EUR,
+		Direction: models.RateAlertBelow, Threshold: 0.9}
+	usdRUB := models.RateAlertDB{AlertID: uuid.New(), UserID: user.UserID, FromCurrency: models.USD, ToCurrency: models.RUB,
+		Direction: models.RateAlertAbove, Threshold: 90}
+
+	type mocks struct {
+		store    *MockRateAlertStore
+		rates    *MockRateQuoter
+		users    *MockUserByIDReader
+		prefs    *MockNotificationPreferenceReader
+		notifier *MockNotifier
+		kafka    *MockEventPublisher
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m mocks)
+		wantErr bool
+	}{
+		{
+			name: "list error",
+			setup: func(m mocks) {
+				m.store.EXPECT().ListArmed(ctx).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "fires crossed alerts once per pair rate",
+			setup: func(m mocks) {
+				m.store.EXPECT().ListArmed(ctx).Return([]models.RateAlertDB{usdEUR, usdEURBelow, usdRUB}, nil)
+				m.rates.EXPECT().ExchangeRate(ctx, models.USD, models.EUR).Return(float32(0.96), false, nil)
+				m.rates.EXPECT().ExchangeRate(ctx, models.USD, models.RUB).Return(float32(0), false, errors.New("exchanger down"))
+				m.store.EXPECT().MarkTriggered(ctx, usdEUR, float32(0.96), gomock.Any()).Return(true, nil)
+				m.kafka.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+					var event models.RateAlertEvent
+					assert.NoError(t, json.Unmarshal(msgs[0].Value, &event))
+					assert.Equal(t, usdEUR.AlertID, event.AlertID)
+					assert.Equal(t, float32(0.96), event.Rate)
+					assert.Equal(t, []byte(user.UserID.String()), msgs[0].Key)
+					return nil
+				})
+				m.users.EXPECT().GetByID(ctx, user.UserID).Return(user, nil)
+				m.prefs.EXPECT().GetByUserID(ctx, user.UserID).Return(nil, sql.ErrNoRows)
+				m.notifier.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, n models.Notification) error {
+					assert.Equal(t, models.NotificationChannelEmail, n.Channel)
+					assert.Equal(t, user.Email, n.Email)
+					assert.Contains(t, n.Body, "0.96")
+					return nil
+				})
+			},
+		},
+		{
+			name: "stale rate is skipped",
+			setup: func(m mocks) {
+				m.store.EXPECT().ListArmed(ctx).Return([]models.RateAlertDB{usdEUR}, nil)
+				m.rates.EXPECT().ExchangeRate(ctx, models.USD, models.EUR).Return(float32(0.96), true, nil)
+			},
+		},
+		{
+			name: "already fired",
+			setup: func(m mocks) {
+				m.store.EXPECT().ListArmed(ctx).Return([]models.RateAlertDB{usdEURBelow}, nil)
+				m.rates.EXPECT().ExchangeRate(ctx, models.USD, models.EUR).Return(float32(0.8), false, nil)
+				m.store.EXPECT().MarkTriggered(ctx, usdEURBelow, float32(0.8), gomock.Any()).Return(false, nil)
+			},
+		},
+		{
+			name: "mark error",
+			setup: func(m mocks) {
+				m.store.EXPECT().ListArmed(ctx).Return([]models.RateAlertDB{usdEURBelow}, nil)
+				m.rates.EXPECT().ExchangeRate(ctx, models.USD, models.EUR).Return(float32(0.8), false, nil)
+				m.store.EXPECT().MarkTriggered(ctx, usdEURBelow, float32(0.8), gomock.Any()).Return(false, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mocks{
+				store:    NewMockRateAlertStore(ctrl),
+				rates:    NewMockRateQuoter(ctrl),
+				users:    NewMockUserByIDReader(ctrl),
+				prefs:    NewMockNotificationPreferenceReader(ctrl),
+				notifier: NewMockNotifier(ctrl),
+				kafka:    NewMockEventPublisher(ctrl),
+			}
+			tt.setup(m)
+
+			svc := NewRateAlertService(m.store, m.rates, m.users, m.prefs, m.notifier, m.kafka)
+			err := svc.Watch(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
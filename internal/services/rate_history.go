@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+const (
+	// RateHistoryDefaultDays is how many days of daily rates are returned when from is not set.
+	RateHistoryDefaultDays = 30
+	// RateHistoryMaxDays is the longest daily rate history that can be requested at once.
+	RateHistoryMaxDays = 366
+	// RateHistoryMaxHours is the longest hourly rate history that can be requested at once.
+	RateHistoryMaxHours = 31 * 24
+)
+
+// ErrInvalidGranularity is returned when the rate history is requested per an unknown period.
+var ErrInvalidGranularity = errors.New("invalid granularity")
+
+// RateHistoryStore stores the exchange rates fetched from the exchanger.
+type RateHistoryStore interface {
+	// Appends a rate of a currency pair
+	Save(ctx context.Context, fromCurrency, toCurrency string, rate float32, fetchedAt time.Time) error
+	// Returns the rates of a pair in [from, to) aggregated per granularity, oldest first
+	List(ctx context.Context, fromCurrency, toCurrency string, from, to time.Time, granularity string) ([]models.ExchangeRatePoint, error)
+}
+
+// WithRateHistory records every rate fetched from the exchanger in store, so the rate
+// history can be charted. Rates served from the cache are not recorded again.
+func WithRateHistory(store RateHistoryStore) WalletOpt {
+	return func(s *WalletService) {
+		s.rateHistory = store
+	}
+}
+
+// recordRate appends a fetched rate to the history. Failures are logged and do not fail
+// the operation that fetched the rate.
+func (s *WalletService) recordRate(ctx context.Context, fromCurrency, toCurrency string, rate float32, fetchedAt time.Time) {
+	if s.rateHistory == nil {
+		return
+	}
+	if err := s.rateHistory.Save(ctx, fromCurrency, toCurrency, rate, fetchedAt.UTC()); err != nil {
+		logger.FromContext(ctx).Errorw("failed to record exchange rate", "from", fromCurrency, "to", toCurrency, "rate", rate, "error", err)
+	}
+}
+
+// RateHistoryService serves the history of exchange rates recorded by WithRateHistory.
+type RateHistoryService struct {
+	store RateHistoryStore
+}
+
+// NewRateHistoryService creates a new RateHistoryService.
+func NewRateHistoryService(store RateHistoryStore) *RateHistoryService {
+	return &RateHistoryService{store: store}
+}
+
+// History returns the rates of a currency pair between from and to per hour or day, oldest
+// first. An empty granularity means daily. A zero to means now; a zero from means
+// RateHistoryDefaultDays days or, hourly, one day before to. Periods are truncated to UTC
+// hours or days and include to; periods without recorded rates are omitted.
+func (s *RateHistoryService) History(ctx context.Context, fromCurrency, toCurrency string, from, to time.Time, granularity string) ([]models.ExchangeRatePoint, error) {
+	var period time.Duration
+	var maxPeriods, defaultPeriods int
+	switch granularity {
+	case models.RateGranularityDay, "":
+		granularity = models.RateGranularityDay
+		period, maxPeriods, defaultPeriods = 24*time.Hour, RateHistoryMaxDays, RateHistoryDefaultDays
+	case models.RateGranularityHour:
+		period, maxPeriods, defaultPeriods = time.Hour, RateHistoryMaxHours, 24
+	default:
+		return nil, ErrInvalidGranularity
+	}
+
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	to = to.UTC().Truncate(period).Add(period)
+	if from.IsZero() {
+		from = to.Add(-time.Duration(defaultPeriods) * period)
+	}
+	from = from.UTC().Truncate(period)
+	if !from.Before(to) || to.Sub(from) > time.Duration(maxPeriods)*period {
+		return nil, ErrInvalidDateRange
+	}
+
+	points, err := s.store.List(ctx, fromCurrency, toCurrency, from, to, granularity)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list exchange rate history", "from", fromCurrency, "to", toCurrency, "error", err)
+		return nil, err
+	}
+	for i := range points {
+		points[i].Period = points[i].Period.UTC()
+	}
+	return points, nil
+}
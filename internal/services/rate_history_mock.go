@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/rate_history.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockRateHistoryStore is a mock of RateHistoryStore interface.
+type MockRateHistoryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateHistoryStoreMockRecorder
+}
+
+// MockRateHistoryStoreMockRecorder is the mock recorder for MockRateHistoryStore.
+type MockRateHistoryStoreMockRecorder struct {
+	mock *MockRateHistoryStore
+}
+
+// NewMockRateHistoryStore creates a new mock instance.
+func NewMockRateHistoryStore(ctrl *gomock.Controller) *MockRateHistoryStore {
+	mock := &MockRateHistoryStore{ctrl: ctrl}
+	mock.recorder = &MockRateHistoryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateHistoryStore) EXPECT() *MockRateHistoryStoreMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockRateHistoryStore) List(ctx context.Context, fromCurrency, toCurrency string, from, to time.Time, granularity string) ([]models.ExchangeRatePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, fromCurrency, toCurrency, from, to, granularity)
+	ret0, _ := ret[0].([]models.ExchangeRatePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRateHistoryStoreMockRecorder) List(ctx, fromCurrency, toCurrency, from, to, granularity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRateHistoryStore)(nil).List), ctx, fromCurrency, toCurrency, from, to, granularity)
+}
+
+// Save mocks base method.
+func (m *MockRateHistoryStore) Save(ctx context.Context, fromCurrency, toCurrency string, rate float32, fetchedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, fromCurrency, toCurrency, rate, fetchedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRateHistoryStoreMockRecorder) Save(ctx, fromCurrency, toCurrency, rate, fetchedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRateHistoryStore)(nil).Save), ctx, fromCurrency, toCurrency, rate, fetchedAt)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateHistoryService_History(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewMockRateHistoryStore(ctrl)
+	svc := NewRateHistoryService(store)
+
+	t.Run("daily by default", func(t *testing.T) {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		points := []models.ExchangeRatePoint{{Period: today, Open: 0.9, Close: 0.92, Low: 0.9, High: 0.93}}
+		store.EXPECT().List(ctx, models.USD, models.EUR, today.AddDate(0, 0, -(RateHistoryDefaultDays-1)), today.AddDate(0, 0, 1), models.RateGranularityDay).
+			Return(points, nil)
+
+		got, err := svc.History(ctx, models.USD, models.EUR, time.Time{}, time.Time{}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, points, got)
+	})
+
+	t.Run("hourly", func(t *testing.T) {
+		from := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+		to := time.Date(2025, 3, 14, 11, 15, 0, 0, time.UTC)
+		store.EXPECT().List(ctx, models.USD, models.EUR, from.Truncate(time.Hour), time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC), models.RateGranularityHour).
+			Return(nil, nil)
+
+		got, err := svc.History(ctx, models.USD, models.EUR, from, to, models.RateGranularityHour)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("invalid range or granularity", func(t *testing.T) {
+		from := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+		_, err := svc.History(ctx, models.USD, models.EUR, from, from.AddDate(0, 0, -1), "")
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+		_, err = svc.History(ctx, models.USD, models.EUR, from, from.AddDate(0, 0, RateHistoryMaxDays), "")
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+		_, err = svc.History(ctx, models.USD, models.EUR, from, from.AddDate(0, 0, 31), models.RateGranularityHour)
+		assert.ErrorIs(t, err, ErrInvalidDateRange)
+		_, err = svc.History(ctx, models.USD, models.EUR, from, time.Time{}, "week")
+		assert.ErrorIs(t, err, ErrInvalidGranularity)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store.EXPECT().List(ctx, models.USD, models.EUR, gomock.Any(), gomock.Any(), models.RateGranularityDay).Return(nil, errors.New("db error"))
+		_, err := svc.History(ctx, models.USD, models.EUR, time.Time{}, time.Time{}, models.RateGranularityDay)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestWalletService_Exchange_RecordsFetchedRate(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	rates := NewMockExchangeRateReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	history := NewMockRateHistoryStore(ctrl)
+
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), models.RateSource{}, errors.New("cache miss"))
+	rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), nil)
+	cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.5), gomock.Any()).Return(nil)
+	history.EXPECT().Save(ctx, models.USD, models.EUR, float32(0.5), gomock.Any()).Return(errors.New("db error"))
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("10"), money.Zero, models.EUR, money.MustParse("5")).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("5")}, nil)
+
+	svc := NewWalletService(writer, reader, rates, cache, nil, WithRateHistory(history))
+	executed, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("10"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("5"), executed.ToAmount)
+}
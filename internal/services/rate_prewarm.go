@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+)
+
+// WarmRates refreshes the cached rates of every pair of supported currencies, and the
+// cached rates of all currencies, from the exchanger. Run more often than the rate TTL,
+// it keeps user-facing requests from hitting a cold cache and waiting for the exchanger.
+// Without WithCurrencies, the currencies the exchanger returns rates for are warmed.
+// Pairs the exchanger has no rate for are skipped; cross rates are derived from the
+// warmed legs on demand. The first failure is returned after every pair was tried.
+func (s *WalletService) WarmRates(ctx context.Context) error {
+	start := time.Now()
+
+	rates, src, err := s.readExchangeRates(ctx)
+	if err != nil {
+		err = mapExchangerError(err)
+		logger.FromContext(ctx).Errorw("failed to pre-warm exchange rates", "error", err)
+		return err
+	}
+	if err := s.cacheRepo.SetExchangeRates(ctx, rates, src); err != nil {
+		logger.FromContext(ctx).Errorw("failed to cache exchange rates", "error", err)
+	}
+
+	var codes []string
+	if s.currencies != nil {
+		codes = s.currencies.Codes(ctx)
+	} else {
+		for code := range rates {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+	}
+
+	var firstErr error
+	warmed := 0
+	for _, from := range codes {
+		for _, to := range codes {
+			if from == to {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			_, _, err := s.fetchExchangeRate(ctx, from, to)
+			switch {
+			case err == nil:
+				warmed++
+			case errors.Is(err, ErrExchangeRateNotFound):
+			case firstErr == nil:
+				firstErr = err
+			}
+		}
+	}
+
+	metrics.RatesPrewarmed.Add(float64(warmed))
+	logger.FromContext(ctx).Infow("exchange rates pre-warmed", "pairs", warmed, "duration", time.Since(start), "error", firstErr)
+	return firstErr
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_WarmRates(t *testing.T) {
+	ctx := context.Background()
+	all := map[string]float32{models.USD: 1.0, models.EUR: 0.92}
+
+	t.Run("every pair of exchanger currencies is cached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+
+		rates.EXPECT().GetExchangeRates(ctx).Return(all, nil)
+		cache.EXPECT().SetExchangeRates(ctx, all, gomock.Any()).Return(nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.EUR, models.USD).Return(float32(1.09), nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.92), nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.EUR, models.USD, float32(1.09), gomock.Any()).Return(nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.92), gomock.Any()).Return(nil)
+
+		err := NewWalletService(nil, nil, rates, cache, nil).WarmRates(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("supported currencies without a rate are skipped", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		currencies := NewMockCurrencyLister(ctrl)
+		currencies.EXPECT().Codes(ctx).Return([]string{models.USD, models.RUB})
+
+		rates.EXPECT().GetExchangeRates(ctx).Return(all, nil)
+		cache.EXPECT().SetExchangeRates(ctx, all, gomock.Any()).Return(nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(95), nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0), facades.ErrRateNotFound)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.RUB, float32(95), gomock.Any()).Return(nil)
+
+		err := NewWalletService(nil, nil, rates, cache, nil, WithCurrencies(currencies)).WarmRates(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a failing pair does not stop the others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+
+		rates.EXPECT().GetExchangeRates(ctx).Return(all, nil)
+		cache.EXPECT().SetExchangeRates(ctx, all, gomock.Any()).Return(nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.EUR, models.USD).Return(float32(0), facades.ErrExchangerTimeout)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.92), nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.92), gomock.Any()).Return(nil)
+
+		err := NewWalletService(nil, nil, rates, cache, nil).WarmRates(ctx)
+		assert.ErrorIs(t, err, ErrExchangerTimeout)
+	})
+
+	t.Run("exchanger unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		rates.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerUnavailable)
+
+		err := NewWalletService(nil, nil, rates, NewMockExchangeRateCacheReader(ctrl), nil).WarmRates(ctx)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+}
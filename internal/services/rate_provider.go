@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+)
+
+// RateProvider is a source of exchange rates, e.g. the gRPC exchanger or an HTTP rates API.
+type RateProvider interface {
+	GetExchangeRates(ctx context.Context) (map[string]float32, error)                                 // Returns current exchange rates
+	GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error) // Returns exchange rate for a currency pair
+	Name() string                                                                                     // Identifies the provider in logs and metrics
+}
+
+// RateProviders reads exchange rates from several providers in order of preference and
+// fails over to the next provider when one fails or has no rate for the pair. A provider
+// that is unavailable or times out threshold times in a row is skipped for the cooldown,
+// unless every provider is skipped; its next success makes it healthy again.
+type RateProviders struct {
+	providers []RateProvider
+	threshold int
+	cooldown  time.Duration
+
+	mu     sync.Mutex
+	health []providerHealth
+}
+
+// providerHealth tracks the consecutive failures of a provider.
+type providerHealth struct {
+	failures  int
+	downUntil time.Time
+}
+
+// NewRateProviders creates a reader over providers, the most preferred first. A threshold
+// below 1 marks a provider unhealthy on its first failure.
+func NewRateProviders(threshold int, cooldown time.Duration, providers ...RateProvider) *RateProviders {
+	for _, p := range providers {
+		metrics.RateProviderHealthy.WithLabelValues(p.Name()).Set(1)
+	}
+	return &RateProviders{
+		providers: providers,
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		health:    make([]providerHealth, len(providers)),
+	}
+}
+
+// GetExchangeRates returns the current exchange rates of the first provider that has them.
+func (r *RateProviders) GetExchangeRates(ctx context.Context) (map[string]float32, error) {
+	rates, _, err := r.GetSourcedExchangeRates(ctx)
+	return rates, err
+}
+
+// GetExchangeRateForCurrency returns the rate of a currency pair from the first provider that has it.
+func (r *RateProviders) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error) {
+	rate, _, err := r.GetSourcedExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
+	return rate, err
+}
+
+// GetSourcedExchangeRates is GetExchangeRates reporting the name of the provider that answered.
+func (r *RateProviders) GetSourcedExchangeRates(ctx context.Context) (rates map[string]float32, provider string, err error) {
+	provider, err = r.each(ctx, func(p RateProvider) (err error) {
+		rates, err = p.GetExchangeRates(ctx)
+		return err
+	})
+	return rates, provider, err
+}
+
+// GetSourcedExchangeRateForCurrency is GetExchangeRateForCurrency reporting the name of the provider that answered.
+func (r *RateProviders) GetSourcedExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (rate float32, provider string, err error) {
+	provider, err = r.each(ctx, func(p RateProvider) (err error) {
+		rate, err = p.GetExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
+		return err
+	})
+	return rate, provider, err
+}
+
+// each calls the providers in order until one succeeds and returns its name. It returns the
+// error of the most preferred provider called, so an unavailable primary is reported as unavailable.
+func (r *RateProviders) each(ctx context.Context, call func(p RateProvider) error) (string, error) {
+	var firstErr error
+	for _, i := range r.order() {
+		p := r.providers[i]
+		err := call(p)
+		r.observe(i, err)
+		if err == nil {
+			return p.Name(), nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		logger.FromContext(ctx).Warnw("rate provider failed, failing over", "provider", p.Name(), "error", err)
+	}
+	return "", firstErr
+}
+
+// order returns the indexes of the providers to call: the healthy ones in order of
+// preference, or all of them if none is healthy.
+func (r *RateProviders) order() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]int, 0, len(r.providers))
+	for i := range r.providers {
+		if !now.Before(r.health[i].downUntil) {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) > 0 {
+		return healthy
+	}
+
+	all := make([]int, len(r.providers))
+	for i := range all {
+		all[i] = i
+	}
+	return all
+}
+
+// observe records the outcome of a call to the i-th provider. Only unavailability and
+// timeouts count against its health; a missing rate is a healthy response.
+func (r *RateProviders) observe(i int, err error) {
+	unhealthy := errors.Is(err, facades.ErrExchangerUnavailable) || errors.Is(err, facades.ErrExchangerTimeout)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := &r.health[i]
+	name := r.providers[i].Name()
+	if !unhealthy {
+		if h.failures >= r.threshold {
+			logger.Log.Infow("rate provider recovered", "provider", name)
+		}
+		*h = providerHealth{}
+		metrics.RateProviderHealthy.WithLabelValues(name).Set(1)
+		return
+	}
+
+	h.failures++
+	if h.failures >= r.threshold {
+		h.downUntil = time.Now().Add(r.cooldown)
+		logger.Log.Warnw("rate provider marked unhealthy", "provider", name, "failures", h.failures, "until", h.downUntil)
+		metrics.RateProviderHealthy.WithLabelValues(name).Set(0)
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMockProvider creates a provider mock with a name.
+func newMockProvider(ctrl *gomock.Controller, name string) *MockRateProvider {
+	p := NewMockRateProvider(ctrl)
+	p.EXPECT().Name().Return(name).AnyTimes()
+	return p
+}
+
+func TestRateProviders_Failover(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primary := newMockProvider(ctrl, "primary")
+	secondary := newMockProvider(ctrl, "secondary")
+	providers := NewRateProviders(3, time.Minute, primary, secondary)
+
+	// Основной провайдер недоступен — курс берётся у резервного
+	primary.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), facades.ErrExchangerUnavailable)
+	secondary.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0.9), nil)
+	rate, err := providers.GetExchangeRateForCurrency(ctx, "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.9), rate)
+
+	// Основной провайдер отвечает — резервный не вызывается
+	primary.EXPECT().GetExchangeRates(ctx).Return(map[string]float32{"USD": 1}, nil)
+	rates, err := providers.GetExchangeRates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float32{"USD": 1}, rates)
+
+	// Ошибка самого предпочтительного провайдера возвращается, если не ответил никто
+	primary.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "GBP").Return(float32(0), facades.ErrRateNotFound)
+	secondary.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "GBP").Return(float32(0), facades.ErrExchangerTimeout)
+	_, err = providers.GetExchangeRateForCurrency(ctx, "USD", "GBP")
+	assert.ErrorIs(t, err, facades.ErrRateNotFound)
+}
+
+func TestRateProviders_Sourced(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primary := newMockProvider(ctrl, "primary")
+	secondary := newMockProvider(ctrl, "secondary")
+	providers := NewRateProviders(3, time.Minute, primary, secondary)
+
+	// Сообщается имя провайдера, который ответил
+	primary.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), facades.ErrExchangerUnavailable)
+	secondary.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0.9), nil)
+	rate, provider, err := providers.GetSourcedExchangeRateForCurrency(ctx, "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.9), rate)
+	assert.Equal(t, "secondary", provider)
+
+	primary.EXPECT().GetExchangeRates(ctx).Return(map[string]float32{"USD": 1}, nil)
+	rates, provider, err := providers.GetSourcedExchangeRates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float32{"USD": 1}, rates)
+	assert.Equal(t, "primary", provider)
+}
+
+func TestRateProviders_Health(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primary := newMockProvider(ctrl, "primary")
+	secondary := newMockProvider(ctrl, "secondary")
+	providers := NewRateProviders(2, time.Minute, primary, secondary)
+
+	// Отсутствие курса не считается сбоем
+	primary.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrRateNotFound)
+	secondary.EXPECT().GetExchangeRates(ctx).Return(map[string]float32{"USD": 1}, nil)
+	_, err := providers.GetExchangeRates(ctx)
+	assert.NoError(t, err)
+
+	// Два сбоя подряд выводят основной провайдер из ротации
+	primary.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerUnavailable).Times(2)
+	secondary.EXPECT().GetExchangeRates(ctx).Return(map[string]float32{"USD": 1}, nil).Times(3)
+	for range 3 {
+		_, err = providers.GetExchangeRates(ctx)
+		assert.NoError(t, err)
+	}
+
+	// Когда нездоровы все провайдеры, опрашиваются все
+	secondary.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerTimeout).Times(3)
+	primary.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerUnavailable)
+	for range 3 {
+		_, err = providers.GetExchangeRates(ctx)
+		assert.Error(t, err)
+	}
+
+	// По истечении паузы основной провайдер снова опрашивается первым
+	providers.health[0].downUntil = time.Now().Add(-time.Second)
+	primary.EXPECT().GetExchangeRates(ctx).Return(map[string]float32{"EUR": 1}, nil)
+	rates, err := providers.GetExchangeRates(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float32{"EUR": 1}, rates)
+	assert.Equal(t, providerHealth{}, providers.health[0])
+}
+
+func TestRateProviders_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	primary := newMockProvider(ctrl, "primary")
+	secondary := newMockProvider(ctrl, "secondary")
+	providers := NewRateProviders(3, time.Minute, primary, secondary)
+
+	// Отменённый запрос не переходит к резервному провайдеру
+	primary.EXPECT().GetExchangeRates(ctx).Return(nil, errors.New("canceled"))
+	_, err := providers.GetExchangeRates(ctx)
+	assert.Error(t, err)
+}
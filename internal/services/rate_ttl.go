@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+)
+
+// AdaptiveRateTTL is the freshness TTL of cached exchange rates adapted to the exchanger health.
+// Slow or failed exchanger calls double the TTL, so cached rates are served longer instead of
+// waiting on the exchanger; healthy calls halve it back. The TTL stays within [min, max].
+type AdaptiveRateTTL struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	min  time.Duration
+	max  time.Duration
+	slow time.Duration // latency above which a call counts as slow
+}
+
+// NewAdaptiveRateTTL creates a TTL starting at base, clamped to [min, max].
+func NewAdaptiveRateTTL(base, min, max, slow time.Duration) *AdaptiveRateTTL {
+	a := &AdaptiveRateTTL{min: min, max: max, slow: slow}
+	a.set(base)
+	return a
+}
+
+// TTL returns the current freshness TTL.
+func (a *AdaptiveRateTTL) TTL() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.ttl
+}
+
+// Observe adapts the TTL to the outcome of an exchanger call. err is the mapped exchanger
+// error; errors other than unavailability and timeouts count as healthy responses.
+func (a *AdaptiveRateTTL) Observe(latency time.Duration, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	unhealthy := errors.Is(err, ErrExchangerUnavailable) || errors.Is(err, ErrExchangerTimeout)
+	if unhealthy || latency > a.slow {
+		a.set(a.ttl * 2)
+	} else {
+		a.set(a.ttl / 2)
+	}
+}
+
+// set stores ttl clamped to the bounds. The caller holds mu unless a is not shared yet.
+func (a *AdaptiveRateTTL) set(ttl time.Duration) {
+	a.ttl = min(max(ttl, a.min), a.max)
+	metrics.RateCacheTTL.Set(a.ttl.Seconds())
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveRateTTL(t *testing.T) {
+	t.Run("base is clamped to bounds", func(t *testing.T) {
+		assert.Equal(t, 10*time.Second, NewAdaptiveRateTTL(time.Second, 10*time.Second, time.Minute, time.Second).TTL())
+		assert.Equal(t, time.Minute, NewAdaptiveRateTTL(time.Hour, 10*time.Second, time.Minute, time.Second).TTL())
+	})
+
+	tests := []struct {
+		name    string
+		latency time.Duration
+		err     error
+		want    time.Duration
+	}{
+		{name: "healthy call shortens", latency: 100 * time.Millisecond, want: 20 * time.Second},
+		{name: "slow call lengthens", latency: 2 * time.Second, want: 80 * time.Second},
+		{name: "unavailable lengthens", err: ErrExchangerUnavailable, want: 80 * time.Second},
+		{name: "timeout lengthens", err: ErrExchangerTimeout, want: 80 * time.Second},
+		{name: "missing rate is a healthy response", err: ErrExchangeRateNotFound, want: 20 * time.Second},
+		{name: "other error is a healthy response", err: errors.New("boom"), want: 20 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttl := NewAdaptiveRateTTL(40*time.Second, 5*time.Second, 5*time.Minute, time.Second)
+			ttl.Observe(tt.latency, tt.err)
+			assert.Equal(t, tt.want, ttl.TTL())
+		})
+	}
+
+	t.Run("stays within bounds", func(t *testing.T) {
+		ttl := NewAdaptiveRateTTL(40*time.Second, 5*time.Second, 5*time.Minute, time.Second)
+		for range 10 {
+			ttl.Observe(0, ErrExchangerUnavailable)
+		}
+		assert.Equal(t, 5*time.Minute, ttl.TTL())
+		for range 10 {
+			ttl.Observe(0, nil)
+		}
+		assert.Equal(t, 5*time.Second, ttl.TTL())
+	})
+}
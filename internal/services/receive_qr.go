@@ -0,0 +1,95 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"rsc.io/qr"
+)
+
+// QR code image formats.
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// ReceiveURIPrefix starts the payload of receive QR codes, followed by the query
+// to=<username>[&amount=<amount>&currency=<code>].
+const ReceiveURIPrefix = "gwwallet://receive?"
+
+// receiveQRScale is the number of image pixels per QR module.
+const receiveQRScale = 8
+
+// ErrUnsupportedQRFormat is returned for a QR code format other than png or svg.
+var ErrUnsupportedQRFormat = errors.New("unsupported QR format")
+
+// ReceiveQRService renders the QR codes users show to get paid.
+type ReceiveQRService struct {
+	users UserByIDReader
+}
+
+// NewReceiveQRService creates a new ReceiveQRService.
+func NewReceiveQRService(users UserByIDReader) *ReceiveQRService {
+	return &ReceiveQRService{users: users}
+}
+
+// ReceiveQR returns a QR code image in format encoding the user's transfer handle,
+// their username, and the optional amount and currency to pay.
+// A zero amount or empty currency is left out of the payload.
+func (s *ReceiveQRService) ReceiveQR(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, format string) ([]byte, error) {
+	if format != QRFormatPNG && format != QRFormatSVG {
+		return nil, ErrUnsupportedQRFormat
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get user for receive QR", "userID", userID, "error", err)
+		return nil, err
+	}
+
+	code, err := qr.Encode(ReceiveURI(user.Username, amount, currency), qr.M)
+	if err != nil {
+		return nil, err
+	}
+	code.Scale = receiveQRScale
+
+	if format == QRFormatSVG {
+		return qrSVG(code), nil
+	}
+	return code.PNG(), nil
+}
+
+// ReceiveURI builds the payload of a receive QR code.
+func ReceiveURI(username string, amount money.Amount, currency string) string {
+	query := url.Values{"to": {username}}
+	if amount.IsPositive() {
+		query.Set("amount", amount.String())
+	}
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+	return ReceiveURIPrefix + query.Encode()
+}
+
+// qrSVG renders the code as an SVG path of its dark modules, one unit per module.
+func qrSVG(code *qr.Code) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">`,
+		code.Size, code.Size, code.Size*code.Scale, code.Size*code.Scale)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, code.Size, code.Size)
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Black(x, y) {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
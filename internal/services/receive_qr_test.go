@@ -0,0 +1,66 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveURI(t *testing.T) {
+	assert.Equal(t, "gwwallet://receive?to=alice", ReceiveURI("alice", money.Amount(0), ""))
+	assert.Equal(t, "gwwallet://receive?currency=EUR&to=alice", ReceiveURI("alice", money.Amount(0), models.EUR))
+	assert.Equal(t, "gwwallet://receive?amount=25.50&currency=USD&to=al+ice%26", ReceiveURI("al ice&", money.MustParse("25.5"), models.USD))
+}
+
+func TestReceiveQRService_ReceiveQR(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := NewMockUserByIDReader(ctrl)
+	svc := NewReceiveQRService(users)
+
+	t.Run("png", func(t *testing.T) {
+		users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Username: "alice"}, nil)
+
+		content, err := svc.ReceiveQR(ctx, userID, money.MustParse("10"), models.USD, QRFormatPNG)
+		assert.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(content))
+		assert.NoError(t, err)
+		assert.Equal(t, img.Bounds().Dx(), img.Bounds().Dy())
+		assert.Zero(t, img.Bounds().Dx()%receiveQRScale)
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Username: "alice"}, nil)
+
+		content, err := svc.ReceiveQR(ctx, userID, money.Amount(0), "", QRFormatSVG)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "<svg "))
+		assert.True(t, strings.HasSuffix(string(content), "</svg>"))
+		assert.Contains(t, string(content), "h1v1h-1z")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := svc.ReceiveQR(ctx, userID, money.Amount(0), "", "gif")
+		assert.ErrorIs(t, err, ErrUnsupportedQRFormat)
+	})
+
+	t.Run("user error", func(t *testing.T) {
+		users.EXPECT().GetByID(ctx, userID).Return(nil, errors.New("db error"))
+
+		_, err := svc.ReceiveQR(ctx, userID, money.Amount(0), "", QRFormatPNG)
+		assert.EqualError(t, err, "db error")
+	})
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+)
+
+var (
+	// ErrEmailDomainNotAllowed is returned when the email domain is blocklisted or not allowlisted.
+	ErrEmailDomainNotAllowed = errors.New("email domain not allowed")
+	// ErrRegistrationRateLimited is returned when the email domain exceeded its registration cap.
+	ErrRegistrationRateLimited = errors.New("too many registrations for email domain")
+)
+
+// DomainCounter counts registration attempts per email domain within a fixed window.
+type DomainCounter interface {
+	Increment(ctx context.Context, domain string, window time.Duration) (int64, error)
+}
+
+// RegistrationPolicy restricts registrations by email domain.
+type RegistrationPolicy struct {
+	counter   DomainCounter
+	blocklist []string
+	allowlist []string
+	limit     int64
+	window    time.Duration
+}
+
+// NewRegistrationPolicy creates a new RegistrationPolicy.
+// An empty allowlist allows every domain that is not blocklisted.
+// Entries match the domain itself and its subdomains. A limit of 0 disables the per-domain cap.
+func NewRegistrationPolicy(counter DomainCounter, blocklist, allowlist []string, limit int, window time.Duration) *RegistrationPolicy {
+	normalize := func(domains []string) []string {
+		out := make([]string, 0, len(domains))
+		for _, d := range domains {
+			if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+
+	return &RegistrationPolicy{
+		counter:   counter,
+		blocklist: normalize(blocklist),
+		allowlist: normalize(allowlist),
+		limit:     int64(limit),
+		window:    window,
+	}
+}
+
+// Check returns an error if a registration with the given email must be rejected.
+// If the attempt counter is unavailable the cap is not enforced.
+func (p *RegistrationPolicy) Check(ctx context.Context, email string) error {
+	domain := emailDomain(email)
+
+	if matchDomain(domain, p.blocklist) {
+		return ErrEmailDomainNotAllowed
+	}
+	if len(p.allowlist) > 0 && !matchDomain(domain, p.allowlist) {
+		return ErrEmailDomainNotAllowed
+	}
+
+	if p.limit <= 0 || domain == "" {
+		return nil
+	}
+
+	count, err := p.counter.Increment(ctx, domain, p.window)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to count registration attempt", "domain", domain, "error", err)
+		return nil
+	}
+	if count > p.limit {
+		return ErrRegistrationRateLimited
+	}
+	return nil
+}
+
+// emailDomain returns the lowercased part of the email after the last "@".
+func emailDomain(email string) string {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[i+1:]))
+}
+
+// matchDomain reports whether domain equals one of the entries or is their subdomain.
+func matchDomain(domain string, entries []string) bool {
+	if domain == "" {
+		return false
+	}
+	for _, e := range entries {
+		if domain == e || strings.HasSuffix(domain, "."+e) {
+			return true
+		}
+	}
+	return false
+}
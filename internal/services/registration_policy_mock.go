@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/registration_policy.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDomainCounter is a mock of DomainCounter interface.
+type MockDomainCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainCounterMockRecorder
+}
+
+// MockDomainCounterMockRecorder is the mock recorder for MockDomainCounter.
+type MockDomainCounterMockRecorder struct {
+	mock *MockDomainCounter
+}
+
+// NewMockDomainCounter creates a new mock instance.
+func NewMockDomainCounter(ctrl *gomock.Controller) *MockDomainCounter {
+	mock := &MockDomainCounter{ctrl: ctrl}
+	mock.recorder = &MockDomainCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainCounter) EXPECT() *MockDomainCounterMockRecorder {
+	return m.recorder
+}
+
+// Increment mocks base method.
+func (m *MockDomainCounter) Increment(ctx context.Context, domain string, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, domain, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockDomainCounterMockRecorder) Increment(ctx, domain, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockDomainCounter)(nil).Increment), ctx, domain, window)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrationPolicy_Check(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	counter := NewMockDomainCounter(ctrl)
+
+	t.Run("blocklist", func(t *testing.T) {
+		policy := NewRegistrationPolicy(counter, []string{"Spam.com", " "}, nil, 0, time.Hour)
+
+		assert.ErrorIs(t, policy.Check(ctx, "bot@spam.com"), ErrEmailDomainNotAllowed)
+		assert.ErrorIs(t, policy.Check(ctx, "bot@mail.SPAM.com"), ErrEmailDomainNotAllowed)
+		assert.NoError(t, policy.Check(ctx, "alice@notspam.com"))
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		policy := NewRegistrationPolicy(counter, nil, []string{"corp.com"}, 0, time.Hour)
+
+		assert.NoError(t, policy.Check(ctx, "alice@corp.com"))
+		assert.NoError(t, policy.Check(ctx, "bob@eu.corp.com"))
+		assert.ErrorIs(t, policy.Check(ctx, "eve@gmail.com"), ErrEmailDomainNotAllowed)
+		assert.ErrorIs(t, policy.Check(ctx, "no-domain"), ErrEmailDomainNotAllowed)
+	})
+
+	t.Run("blocklist wins over allowlist", func(t *testing.T) {
+		policy := NewRegistrationPolicy(counter, []string{"bad.corp.com"}, []string{"corp.com"}, 0, time.Hour)
+
+		assert.ErrorIs(t, policy.Check(ctx, "x@bad.corp.com"), ErrEmailDomainNotAllowed)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		policy := NewRegistrationPolicy(counter, nil, nil, 2, time.Hour)
+
+		counter.EXPECT().Increment(ctx, "example.com", time.Hour).Return(int64(2), nil)
+		assert.NoError(t, policy.Check(ctx, "a@Example.com"))
+
+		counter.EXPECT().Increment(ctx, "example.com", time.Hour).Return(int64(3), nil)
+		assert.ErrorIs(t, policy.Check(ctx, "b@example.com"), ErrRegistrationRateLimited)
+	})
+
+	t.Run("counter error fails open", func(t *testing.T) {
+		policy := NewRegistrationPolicy(counter, nil, nil, 2, time.Hour)
+
+		counter.EXPECT().Increment(ctx, "example.com", time.Hour).Return(int64(0), errors.New("redis down"))
+		assert.NoError(t, policy.Check(ctx, "a@example.com"))
+	})
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"io/fs"
+	"sync"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/schema"
+)
+
+// SchemaLoader reads the live database schema.
+type SchemaLoader interface {
+	Load(ctx context.Context) (schema.Schema, error)
+}
+
+// SchemaDriftService compares the live database schema against the migrations
+// in dry-run mode: it reports missing tables, columns and indexes and never applies changes.
+type SchemaDriftService struct {
+	expected schema.Schema
+	loader   SchemaLoader
+
+	mu    sync.RWMutex
+	drift []string
+}
+
+// NewSchemaDriftService creates a new SchemaDriftService expecting the schema produced by migrations.
+func NewSchemaDriftService(migrations fs.FS, loader SchemaLoader) (*SchemaDriftService, error) {
+	expected, err := schema.FromMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaDriftService{expected: expected, loader: loader}, nil
+}
+
+// Check loads the live schema and returns how it drifts from the migrations.
+// Each drift is logged as a warning and kept for Drift. If the live schema cannot be read,
+// the result of the previous check is kept.
+func (s *SchemaDriftService) Check(ctx context.Context) ([]string, error) {
+	live, err := s.loader.Load(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to load live schema", "error", err)
+		return nil, err
+	}
+
+	drift := schema.Drift(s.expected, live)
+	for _, d := range drift {
+		logger.FromContext(ctx).Warnw("schema drift detected", "drift", d)
+	}
+	if len(drift) == 0 {
+		logger.FromContext(ctx).Info("schema matches migrations")
+	}
+
+	s.mu.Lock()
+	s.drift = drift
+	s.mu.Unlock()
+	return drift, nil
+}
+
+// Drift returns the drift found by the last successful check, empty if none ran.
+func (s *SchemaDriftService) Drift() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.drift...)
+}
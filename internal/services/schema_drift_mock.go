@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/schema_drift.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	schema "github.com/sbilibin2017/gw-currency-wallet/internal/schema"
+)
+
+// MockSchemaLoader is a mock of SchemaLoader interface.
+type MockSchemaLoader struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaLoaderMockRecorder
+}
+
+// MockSchemaLoaderMockRecorder is the mock recorder for MockSchemaLoader.
+type MockSchemaLoaderMockRecorder struct {
+	mock *MockSchemaLoader
+}
+
+// NewMockSchemaLoader creates a new mock instance.
+func NewMockSchemaLoader(ctrl *gomock.Controller) *MockSchemaLoader {
+	mock := &MockSchemaLoader{ctrl: ctrl}
+	mock.recorder = &MockSchemaLoaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaLoader) EXPECT() *MockSchemaLoaderMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method.
+func (m *MockSchemaLoader) Load(ctx context.Context) (schema.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx)
+	ret0, _ := ret[0].(schema.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockSchemaLoaderMockRecorder) Load(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockSchemaLoader)(nil).Load), ctx)
+}
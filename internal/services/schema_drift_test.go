@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaDriftService(t *testing.T) {
+	ctx := context.Background()
+	migrations := fstest.MapFS{
+		"000001_users.sql": {Data: []byte(`-- +goose Up
+CREATE TABLE users (user_id UUID PRIMARY KEY, email TEXT);
+CREATE INDEX idx_users_email ON users (email);
+-- +goose Down
+DROP TABLE users;
+`)},
+	}
+	upToDate := schema.Schema{
+		Columns: map[string][]string{"users": {"user_id", "email"}},
+		Indexes: map[string]string{"idx_users_email": "users", "users_pkey": "users"},
+	}
+
+	t.Run("reports and keeps drift", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		loader := NewMockSchemaLoader(ctrl)
+		loader.EXPECT().Load(ctx).Return(schema.Schema{
+			Columns: map[string][]string{"users": {"user_id"}},
+			Indexes: map[string]string{},
+		}, nil)
+		loader.EXPECT().Load(ctx).Return(schema.Schema{}, errors.New("db error"))
+		loader.EXPECT().Load(ctx).Return(upToDate, nil)
+
+		svc, err := NewSchemaDriftService(migrations, loader)
+		assert.NoError(t, err)
+		assert.Empty(t, svc.Drift())
+
+		want := []string{"missing column users.email", "missing index idx_users_email on users"}
+		drift, err := svc.Check(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, want, drift)
+		assert.Equal(t, want, svc.Drift())
+
+		_, err = svc.Check(ctx)
+		assert.EqualError(t, err, "db error")
+		assert.Equal(t, want, svc.Drift())
+
+		drift, err = svc.Check(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, drift)
+		assert.Empty(t, svc.Drift())
+	})
+
+	t.Run("invalid migrations", func(t *testing.T) {
+		_, err := NewSchemaDriftService(fstest.MapFS{"000001_bad.sql": {Data: []byte("SELECT 1;")}}, nil)
+		assert.Error(t, err)
+	})
+}
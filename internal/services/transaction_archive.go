@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// TransactionPartitionStore manages the monthly partitions of the transaction history.
+type TransactionPartitionStore interface {
+	Months(ctx context.Context) ([]time.Time, error)                                          // Returns the months that have a partition, oldest first
+	Create(ctx context.Context, month time.Time) error                                        // Creates the partition of a month if it is missing
+	Scan(ctx context.Context, month time.Time, fn func(txn models.TransactionDB) error) error // Calls fn for every transaction of a month
+	Drop(ctx context.Context, month time.Time) error                                          // Detaches and drops the partition of a month
+}
+
+// TransactionArchive keeps archived months of the transaction history in cold storage.
+type TransactionArchive interface {
+	Save(ctx context.Context, name string, write func(w io.Writer) error) error // Stores what write writes under name; nothing is stored if write fails
+}
+
+// TransactionArchiveService keeps the transaction history partitioned by month: it creates
+// the partitions of the coming months and moves the months past the retention to the archive.
+type TransactionArchiveService struct {
+	partitions      TransactionPartitionStore
+	archive         TransactionArchive
+	partitionsAhead int
+	retentionMonths int
+}
+
+// NewTransactionArchiveService creates a new TransactionArchiveService. Partitions are
+// created for partitionsAhead months after the current one. Months ending more than
+// retentionMonths before the current one are archived; 0 keeps all months in the database.
+func NewTransactionArchiveService(partitions TransactionPartitionStore, archive TransactionArchive, partitionsAhead, retentionMonths int) *TransactionArchiveService {
+	return &TransactionArchiveService{
+		partitions:      partitions,
+		archive:         archive,
+		partitionsAhead: partitionsAhead,
+		retentionMonths: retentionMonths,
+	}
+}
+
+// Maintain creates the missing partitions of the current and the coming months, then
+// archives and drops the partitions of the months past the retention, oldest first.
+// A month is dropped only after its archive is saved, so a failed run is repeated by the next.
+func (s *TransactionArchiveService) Maintain(ctx context.Context) error {
+	now := time.Now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i <= s.partitionsAhead; i++ {
+		month := current.AddDate(0, i, 0)
+		if err := s.partitions.Create(ctx, month); err != nil {
+			logger.FromContext(ctx).Errorw("failed to create transactions partition", "month", month.Format("2006-01"), "error", err)
+			return err
+		}
+	}
+
+	if s.retentionMonths == 0 {
+		return nil
+	}
+	months, err := s.partitions.Months(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list transactions partitions", "error", err)
+		return err
+	}
+	cutoff := current.AddDate(0, -s.retentionMonths, 0)
+	for _, month := range months {
+		if !month.Before(cutoff) {
+			break
+		}
+		if err := s.archiveMonth(ctx, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveMonth saves the transactions of a month as JSON lines to the archive and drops
+// its partition.
+func (s *TransactionArchiveService) archiveMonth(ctx context.Context, month time.Time) error {
+	name := month.Format("transactions_2006_01")
+	archived := 0
+	err := s.archive.Save(ctx, name, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		return s.partitions.Scan(ctx, month, func(txn models.TransactionDB) error {
+			archived++
+			return enc.Encode(txn)
+		})
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to archive transactions", "month", month.Format("2006-01"), "error", err)
+		return err
+	}
+
+	if err := s.partitions.Drop(ctx, month); err != nil {
+		logger.FromContext(ctx).Errorw("failed to drop archived transactions partition", "month", month.Format("2006-01"), "error", err)
+		return err
+	}
+	metrics.TransactionsArchived.Add(float64(archived))
+	logger.FromContext(ctx).Infow("transactions archived", "month", month.Format("2006-01"), "archive", name, "transactions", archived)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/transaction_archive.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockTransactionPartitionStore is a mock of TransactionPartitionStore interface.
+type MockTransactionPartitionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionPartitionStoreMockRecorder
+}
+
+// MockTransactionPartitionStoreMockRecorder is the mock recorder for MockTransactionPartitionStore.
+type MockTransactionPartitionStoreMockRecorder struct {
+	mock *MockTransactionPartitionStore
+}
+
+// NewMockTransactionPartitionStore creates a new mock instance.
+func NewMockTransactionPartitionStore(ctrl *gomock.Controller) *MockTransactionPartitionStore {
+	mock := &MockTransactionPartitionStore{ctrl: ctrl}
+	mock.recorder = &MockTransactionPartitionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionPartitionStore) EXPECT() *MockTransactionPartitionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransactionPartitionStore) Create(ctx context.Context, month time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, month)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionPartitionStoreMockRecorder) Create(ctx, month interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionPartitionStore)(nil).Create), ctx, month)
+}
+
+// Drop mocks base method.
+func (m *MockTransactionPartitionStore) Drop(ctx context.Context, month time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drop", ctx, month)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drop indicates an expected call of Drop.
+func (mr *MockTransactionPartitionStoreMockRecorder) Drop(ctx, month interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drop", reflect.TypeOf((*MockTransactionPartitionStore)(nil).Drop), ctx, month)
+}
+
+// Months mocks base method.
+func (m *MockTransactionPartitionStore) Months(ctx context.Context) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Months", ctx)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Months indicates an expected call of Months.
+func (mr *MockTransactionPartitionStoreMockRecorder) Months(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Months", reflect.TypeOf((*MockTransactionPartitionStore)(nil).Months), ctx)
+}
+
+// Scan mocks base method.
+func (m *MockTransactionPartitionStore) Scan(ctx context.Context, month time.Time, fn func(models.TransactionDB) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Scan", ctx, month, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockTransactionPartitionStoreMockRecorder) Scan(ctx, month, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockTransactionPartitionStore)(nil).Scan), ctx, month, fn)
+}
+
+// MockTransactionArchive is a mock of TransactionArchive interface.
+type MockTransactionArchive struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionArchiveMockRecorder
+}
+
+// MockTransactionArchiveMockRecorder is the mock recorder for MockTransactionArchive.
+type MockTransactionArchiveMockRecorder struct {
+	mock *MockTransactionArchive
+}
+
+// NewMockTransactionArchive creates a new mock instance.
+func NewMockTransactionArchive(ctrl *gomock.Controller) *MockTransactionArchive {
+	mock := &MockTransactionArchive{ctrl: ctrl}
+	mock.recorder = &MockTransactionArchiveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionArchive) EXPECT() *MockTransactionArchiveMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockTransactionArchive) Save(ctx context.Context, name string, write func(io.Writer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, name, write)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTransactionArchiveMockRecorder) Save(ctx, name, write interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTransactionArchive)(nil).Save), ctx, name, write)
+}
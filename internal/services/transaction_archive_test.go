@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionArchiveService_Maintain(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	expectPartitions := func(partitions *MockTransactionPartitionStore) {
+		for i := 0; i <= 2; i++ {
+			partitions.EXPECT().Create(ctx, current.AddDate(0, i, 0)).Return(nil)
+		}
+	}
+
+	t.Run("creates partitions ahead and keeps all months without retention", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		partitions := NewMockTransactionPartitionStore(ctrl)
+		expectPartitions(partitions)
+
+		svc := NewTransactionArchiveService(partitions, nil, 2, 0)
+		assert.NoError(t, svc.Maintain(ctx))
+	})
+
+	t.Run("archives and drops the months past the retention", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		partitions := NewMockTransactionPartitionStore(ctrl)
+		archive := NewMockTransactionArchive(ctrl)
+		expectPartitions(partitions)
+
+		oldest, older := current.AddDate(0, -14, 0), current.AddDate(0, -13, 0)
+		partitions.EXPECT().Months(ctx).Return([]time.Time{oldest, older, current.AddDate(0, -12, 0), current}, nil)
+
+		txn := models.TransactionDB{ID: 1, TransactionID: uuid.New(), Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("10")}
+		var saved bytes.Buffer
+		for _, month := range []time.Time{oldest, older} {
+			gomock.InOrder(
+				archive.EXPECT().Save(ctx, month.Format("transactions_2006_01"), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, write func(w io.Writer) error) error {
+						return write(&saved)
+					}),
+				partitions.EXPECT().Drop(ctx, month).Return(nil),
+			)
+			partitions.EXPECT().Scan(ctx, month, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ time.Time, fn func(txn models.TransactionDB) error) error {
+					return fn(txn)
+				})
+		}
+
+		svc := NewTransactionArchiveService(partitions, archive, 2, 12)
+		assert.NoError(t, svc.Maintain(ctx))
+
+		dec := json.NewDecoder(&saved)
+		for range 2 {
+			var archived models.TransactionDB
+			assert.NoError(t, dec.Decode(&archived))
+			assert.Equal(t, txn.TransactionID, archived.TransactionID)
+			assert.Equal(t, txn.Amount, archived.Amount)
+		}
+	})
+
+	t.Run("keeps the partition if the archive fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		partitions := NewMockTransactionPartitionStore(ctrl)
+		archive := NewMockTransactionArchive(ctrl)
+		expectPartitions(partitions)
+
+		oldest := current.AddDate(0, -14, 0)
+		partitions.EXPECT().Months(ctx).Return([]time.Time{oldest, current}, nil)
+		archive.EXPECT().Save(ctx, gomock.Any(), gomock.Any()).Return(errors.New("disk full"))
+
+		svc := NewTransactionArchiveService(partitions, archive, 2, 12)
+		assert.EqualError(t, svc.Maintain(ctx), "disk full")
+	})
+
+	t.Run("partition failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		partitions := NewMockTransactionPartitionStore(ctrl)
+		partitions.EXPECT().Create(ctx, current).Return(errors.New("default partition holds rows"))
+
+		svc := NewTransactionArchiveService(partitions, nil, 2, 12)
+		assert.Error(t, svc.Maintain(ctx))
+	})
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/avro"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// TransactionAvroSchema is the Avro schema of transaction events. The amount is a decimal
+// with the scale of money.Amount; optional fields are null when empty. Changes must stay
+// backward compatible, e.g. new fields need a default.
+const TransactionAvroSchema = `{
+	"type": "record",
+	"name": "Transaction",
+	"namespace": "gw_currency_wallet",
+	"fields": [
+		{"name": "transaction_id", "type": "string"},
+		{"name": "timestamp", "type": "long", "doc": "Unix time in seconds"},
+		{"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 2}},
+		{"name": "user_id", "type": "string"},
+		{"name": "operation", "type": "string"},
+		{"name": "reversal_of", "type": ["null", "string"], "default": null},
+		{"name": "reference", "type": ["null", "string"], "default": null},
+		{"name": "event_id", "type": "string", "default": ""},
+		{"name": "version", "type": "int", "default": 1, "doc": "Schema version of the event, 1 for records without it"},
+		{"name": "currency", "type": "string", "default": ""},
+		{"name": "to_currency", "type": ["null", "string"], "default": null},
+		{"name": "balance", "type": ["null", {"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 2}], "default": null},
+		{"name": "to_balance", "type": ["null", {"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 2}], "default": null}
+	]
+}`
+
+// MessageEncoder serializes the transaction events published to Kafka.
+type MessageEncoder interface {
+	Encode(ctx context.Context, txn models.Transaction) ([]byte, error)
+}
+
+// SchemaRegistrar registers schemas with a schema registry.
+type SchemaRegistrar interface {
+	Register(ctx context.Context, subject, schemaType, schema string) (int, error) // Returns the ID of the schema
+}
+
+// WithMessageEncoder serializes transaction events with encoder instead of as JSON.
+func WithMessageEncoder(encoder MessageEncoder) WalletOpt {
+	return func(s *WalletService) {
+		s.encoder = encoder
+	}
+}
+
+// JSONTransactionEncoder encodes transaction events as JSON, the default format.
+type JSONTransactionEncoder struct{}
+
+// Encode returns txn as JSON.
+func (JSONTransactionEncoder) Encode(_ context.Context, txn models.Transaction) ([]byte, error) {
+	return json.Marshal(txn)
+}
+
+// AvroTransactionEncoder encodes transaction events as Avro in the Confluent wire format,
+// prefixed with the ID of TransactionAvroSchema in the schema registry.
+type AvroTransactionEncoder struct {
+	schemaID int
+}
+
+// NewAvroTransactionEncoder registers TransactionAvroSchema under subjects, by convention
+// "<topic>-value" of every topic the events are published to, and returns an encoder writing
+// its ID. The registry assigns a schema the same ID under every subject. Registering at startup
+// fails fast on an unreachable registry or an incompatible schema instead of losing events later.
+func NewAvroTransactionEncoder(ctx context.Context, registry SchemaRegistrar, subjects ...string) (*AvroTransactionEncoder, error) {
+	e := &AvroTransactionEncoder{}
+	for _, subject := range subjects {
+		id, err := registry.Register(ctx, subject, "AVRO", TransactionAvroSchema)
+		if err != nil {
+			return nil, err
+		}
+		e.schemaID = id
+	}
+	return e, nil
+}
+
+// Encode returns txn as an Avro record framed with the schema ID.
+func (e *AvroTransactionEncoder) Encode(_ context.Context, txn models.Transaction) ([]byte, error) {
+	var w avro.Writer
+	w.String(txn.TransactionID)
+	w.Long(txn.Timestamp)
+	w.Decimal(int64(txn.Amount))
+	w.String(txn.UserID)
+	w.String(txn.Operation)
+	w.OptionalString(txn.ReversalOf)
+	w.OptionalString(txn.Reference)
+	w.String(txn.EventID)
+	w.Long(int64(txn.Version)) // An Avro int is encoded like a long
+	w.String(txn.Currency)
+	w.OptionalString(txn.ToCurrency)
+	w.OptionalDecimal(unscaled(txn.Balance))
+	w.OptionalDecimal(unscaled(txn.ToBalance))
+	return avro.Frame(e.schemaID, w.Data()), nil
+}
+
+// unscaled returns the minor units of an optional amount, the unscaled value of its decimal.
+func unscaled(amount *money.Amount) *int64 {
+	if amount == nil {
+		return nil
+	}
+	v := int64(*amount)
+	return &v
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/transaction_encoder.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockMessageEncoder is a mock of MessageEncoder interface.
+type MockMessageEncoder struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageEncoderMockRecorder
+}
+
+// MockMessageEncoderMockRecorder is the mock recorder for MockMessageEncoder.
+type MockMessageEncoderMockRecorder struct {
+	mock *MockMessageEncoder
+}
+
+// NewMockMessageEncoder creates a new mock instance.
+func NewMockMessageEncoder(ctrl *gomock.Controller) *MockMessageEncoder {
+	mock := &MockMessageEncoder{ctrl: ctrl}
+	mock.recorder = &MockMessageEncoderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageEncoder) EXPECT() *MockMessageEncoderMockRecorder {
+	return m.recorder
+}
+
+// Encode mocks base method.
+func (m *MockMessageEncoder) Encode(ctx context.Context, txn models.Transaction) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", ctx, txn)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockMessageEncoderMockRecorder) Encode(ctx, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockMessageEncoder)(nil).Encode), ctx, txn)
+}
+
+// MockSchemaRegistrar is a mock of SchemaRegistrar interface.
+type MockSchemaRegistrar struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaRegistrarMockRecorder
+}
+
+// MockSchemaRegistrarMockRecorder is the mock recorder for MockSchemaRegistrar.
+type MockSchemaRegistrarMockRecorder struct {
+	mock *MockSchemaRegistrar
+}
+
+// NewMockSchemaRegistrar creates a new mock instance.
+func NewMockSchemaRegistrar(ctrl *gomock.Controller) *MockSchemaRegistrar {
+	mock := &MockSchemaRegistrar{ctrl: ctrl}
+	mock.recorder = &MockSchemaRegistrarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaRegistrar) EXPECT() *MockSchemaRegistrarMockRecorder {
+	return m.recorder
+}
+
+// Register mocks base method.
+func (m *MockSchemaRegistrar) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, subject, schemaType, schema)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockSchemaRegistrarMockRecorder) Register(ctx, subject, schemaType, schema interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockSchemaRegistrar)(nil).Register), ctx, subject, schemaType, schema)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionAvroSchema_IsJSON(t *testing.T) {
+	var schema map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(TransactionAvroSchema), &schema))
+	assert.Equal(t, "Transaction", schema["name"])
+	assert.Len(t, schema["fields"], 13)
+}
+
+func TestJSONTransactionEncoder(t *testing.T) {
+	balance := money.MustParse("250.50")
+	data, err := JSONTransactionEncoder{}.Encode(context.Background(), models.Transaction{
+		TransactionID: "txn-1", Timestamp: 1700000000, Amount: money.MustParse("100.50"), UserID: "user-1", Operation: "deposit",
+		EventID: "event-1", Version: 3, Currency: "USD", Balance: &balance,
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"version":3,"transaction_id":"txn-1","timestamp":1700000000,"amount":100.50,"user_id":"user-1","operation":"deposit","event_id":"event-1","currency":"USD","balance":250.50}`, string(data))
+}
+
+func TestAvroTransactionEncoder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("encodes in the wire format", func(t *testing.T) {
+		toBalance := money.MustParse("100.50")
+		ctrl := gomock.NewController(t)
+		registry := NewMockSchemaRegistrar(ctrl)
+		registry.EXPECT().Register(ctx, "wallet.transactions-value", "AVRO", TransactionAvroSchema).Return(7, nil)
+
+		encoder, err := NewAvroTransactionEncoder(ctx, registry, "wallet.transactions-value")
+		assert.NoError(t, err)
+
+		data, err := encoder.Encode(ctx, models.Transaction{
+			TransactionID: "t1",
+			Timestamp:     1,
+			Amount:        money.MustParse("100.50"),
+			UserID:        "u1",
+			Operation:     "deposit",
+			Reference:     "R",
+			EventID:       "e1",
+			Version:       3,
+			Currency:      "USD",
+			ToCurrency:    "EUR",
+			ToBalance:     &toBalance,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{
+			0x00, 0x00, 0x00, 0x00, 0x07, // Magic byte and schema ID
+			0x04, 't', '1', // transaction_id
+			0x02,             // timestamp
+			0x04, 0x27, 0x42, // amount, 10050 minor units
+			0x04, 'u', '1', // user_id
+			0x0e, 'd', 'e', 'p', 'o', 's', 'i', 't', // operation
+			0x00,            // reversal_of: null
+			0x02, 0x02, 'R', // reference
+			0x04, 'e', '1', // event_id
+			0x06,                // version
+			0x06, 'U', 'S', 'D', // currency
+			0x02, 0x06, 'E', 'U', 'R', // to_currency
+			0x00,                   // balance: null
+			0x02, 0x04, 0x27, 0x42, // to_balance, 10050 minor units
+		}, data)
+	})
+
+	t.Run("registers every subject", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		registry := NewMockSchemaRegistrar(ctrl)
+		registry.EXPECT().Register(ctx, "large-transactions-value", "AVRO", TransactionAvroSchema).Return(7, nil)
+		registry.EXPECT().Register(ctx, "transactions-value", "AVRO", TransactionAvroSchema).Return(7, nil)
+
+		encoder, err := NewAvroTransactionEncoder(ctx, registry, "large-transactions-value", "transactions-value")
+		assert.NoError(t, err)
+		assert.Equal(t, 7, encoder.schemaID)
+	})
+
+	t.Run("registry failure fails fast", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		registry := NewMockSchemaRegistrar(ctrl)
+		registry.EXPECT().Register(ctx, "wallet.transactions-value", "AVRO", TransactionAvroSchema).
+			Return(0, errors.New("schema registry returned HTTP 409: incompatible"))
+
+		_, err := NewAvroTransactionEncoder(ctx, registry, "wallet.transactions-value")
+		assert.Error(t, err)
+	})
+}
+
+func TestWalletService_publishTransaction_Encoder(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	writer := NewMockEventPublisher(ctrl)
+	encoder := NewMockMessageEncoder(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, writer, WithMessageEncoder(encoder))
+	txn := models.Transaction{TransactionID: "txn-1", UserID: "user-1", Operation: "deposit"}
+	event := txn
+	event.Version = models.TransactionEventVersion
+	event.Currency = "USD"
+	event.EventID = transactionEventID(txn)
+
+	// Событие публикуется в формате энкодера
+	encoder.EXPECT().Encode(ctx, event).Return([]byte{0x00, 0x01}, nil)
+	writer.EXPECT().WriteMessages(ctx, kafka.Message{
+		Key:     []byte("user-1"),
+		Value:   []byte{0x00, 0x01},
+		Headers: []kafka.Header{{Key: models.EventIDHeader, Value: []byte(event.EventID)}},
+	}).Return(nil)
+	svc.publishTransaction(ctx, "USD", txn)
+
+	// Ошибка кодирования не доходит до Kafka
+	encoder.EXPECT().Encode(ctx, event).Return(nil, errors.New("encode failed"))
+	svc.publishTransaction(ctx, "USD", txn)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// DecodeTransactionEvent decodes a JSON transaction event of any version up to
+// models.TransactionEventVersion and upgrades it to the current version, so consumers handle
+// one structure whichever version was published. Newer versions are rejected: their fields
+// would be lost, so a consumer needs an update before it reads them.
+func DecodeTransactionEvent(data []byte) (models.Transaction, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return models.Transaction{}, err
+	}
+
+	switch header.Version {
+	case 0, 1:
+		var v1 models.TransactionV1
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return models.Transaction{}, err
+		}
+		return UpgradeTransactionV2(UpgradeTransactionV1(v1)), nil
+	case 2:
+		var v2 models.TransactionV2
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return models.Transaction{}, err
+		}
+		return UpgradeTransactionV2(v2), nil
+	case models.TransactionEventVersion:
+		var txn models.Transaction
+		if err := json.Unmarshal(data, &txn); err != nil {
+			return models.Transaction{}, err
+		}
+		return txn, nil
+	default:
+		return models.Transaction{}, fmt.Errorf("unsupported transaction event version %d", header.Version)
+	}
+}
+
+// UpgradeTransactionV1 converts a version 1 event to version 2. Events without an event ID,
+// published before event IDs were introduced, get the ID the event has today.
+func UpgradeTransactionV1(v1 models.TransactionV1) models.TransactionV2 {
+	v2 := models.TransactionV2{
+		Version:       2,
+		TransactionID: v1.TransactionID,
+		Timestamp:     v1.Timestamp,
+		Amount:        v1.Amount,
+		UserID:        v1.UserID,
+		Operation:     v1.Operation,
+		ReversalOf:    v1.ReversalOf,
+		Reference:     v1.Reference,
+		EventID:       v1.EventID,
+	}
+	if v2.EventID == "" {
+		v2.EventID = transactionEventID(models.Transaction{Operation: v2.Operation, TransactionID: v2.TransactionID})
+	}
+	return v2
+}
+
+// UpgradeTransactionV2 converts a version 2 event to version 3. The currency and balances
+// were not published, so they stay empty; consumers needing them look the transaction up.
+func UpgradeTransactionV2(v2 models.TransactionV2) models.Transaction {
+	return models.Transaction{
+		Version:       3,
+		TransactionID: v2.TransactionID,
+		Timestamp:     v2.Timestamp,
+		Amount:        v2.Amount,
+		UserID:        v2.UserID,
+		Operation:     v2.Operation,
+		ReversalOf:    v2.ReversalOf,
+		Reference:     v2.Reference,
+		EventID:       v2.EventID,
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTransactionEvent(t *testing.T) {
+	upgraded := models.Transaction{
+		Version:       3,
+		TransactionID: "txn-1",
+		Timestamp:     1700000000,
+		Amount:        money.MustParse("100.50"),
+		UserID:        "user-1",
+		Operation:     "deposit",
+		Reference:     "INV-42",
+	}
+	upgraded.EventID = transactionEventID(upgraded)
+	balance := money.MustParse("150.50")
+
+	tests := []struct {
+		name     string
+		data     string
+		expected models.Transaction
+		wantErr  string
+	}{
+		{
+			name:     "version 1 without event ID",
+			data:     `{"transaction_id":"txn-1","timestamp":1700000000,"amount":100.50,"user_id":"user-1","operation":"deposit","reference":"INV-42"}`,
+			expected: upgraded,
+		},
+		{
+			name:     "version 1 with event ID",
+			data:     `{"transaction_id":"txn-1","timestamp":1700000000,"amount":100.50,"user_id":"user-1","operation":"deposit","reference":"INV-42","event_id":"` + upgraded.EventID + `"}`,
+			expected: upgraded,
+		},
+		{
+			name:     "version 2",
+			data:     `{"version":2,"transaction_id":"txn-1","timestamp":1700000000,"amount":100.50,"user_id":"user-1","operation":"deposit","reference":"INV-42","event_id":"` + upgraded.EventID + `"}`,
+			expected: upgraded,
+		},
+		{
+			name: "version 3",
+			data: `{"version":3,"transaction_id":"txn-1","timestamp":1700000000,"amount":100.50,"user_id":"user-1","operation":"deposit","reference":"INV-42","event_id":"` + upgraded.EventID + `","currency":"USD","balance":150.50}`,
+			expected: func() models.Transaction {
+				txn := upgraded
+				txn.Currency = "USD"
+				txn.Balance = &balance
+				return txn
+			}(),
+		},
+		{
+			name:    "newer version",
+			data:    `{"version":99,"transaction_id":"txn-1"}`,
+			wantErr: "unsupported transaction event version 99",
+		},
+		{
+			name:    "not JSON",
+			data:    `{`,
+			wantErr: "unexpected end of JSON input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTransactionEvent([]byte(tt.data))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestDecodeTransactionEvent_RoundTrip(t *testing.T) {
+	txn := models.Transaction{Version: models.TransactionEventVersion, TransactionID: "txn-1", UserID: "user-1", Operation: "withdraw", EventID: "event-1"}
+	data, err := JSONTransactionEncoder{}.Encode(context.Background(), txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodeTransactionEvent(data)
+	assert.NoError(t, err)
+	assert.Equal(t, txn, got)
+}
+
+func TestUpgradeTransactionV1(t *testing.T) {
+	v1 := models.TransactionV1{TransactionID: "txn-1", UserID: "user-1", Operation: "withdraw"}
+	v2 := UpgradeTransactionV1(v1)
+	assert.Equal(t, 2, v2.Version)
+	assert.Equal(t, transactionEventID(models.Transaction{TransactionID: "txn-1", Operation: "withdraw"}), v2.EventID)
+
+	// An event ID that was published is kept
+	v1.EventID = "event-1"
+	assert.Equal(t, "event-1", UpgradeTransactionV1(v1).EventID)
+}
+
+func TestUpgradeTransactionV2(t *testing.T) {
+	v2 := models.TransactionV2{Version: 2, TransactionID: "txn-1", UserID: "user-1", Operation: "exchange", Amount: money.MustParse("10"), EventID: "event-1"}
+	assert.Equal(t, models.Transaction{
+		Version: 3, TransactionID: "txn-1", UserID: "user-1", Operation: "exchange", Amount: money.MustParse("10"), EventID: "event-1",
+	}, UpgradeTransactionV2(v2))
+}
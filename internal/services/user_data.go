@@ -0,0 +1,127 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// userDataPageSize is the number of transactions read per query for a user data export.
+const userDataPageSize = 1000
+
+// userDataAuthEventLimit caps the login history in a user data export.
+const userDataAuthEventLimit = 10000
+
+// UserDataProfile is the account part of a user data export. The password hash is left out.
+type UserDataProfile struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	DormantAt *time.Time `json:"dormant_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// UserData is everything stored about a user that a user data export contains.
+type UserData struct {
+	ExportedAt   time.Time
+	Profile      UserDataProfile
+	Wallets      map[string]money.Amount
+	Transactions []models.TransactionDB
+	AuthEvents   []models.AuthEventDB
+}
+
+// userDataSources are the stores a user data export is collected from.
+type userDataSources struct {
+	users        UserByIDReader
+	wallets      WalletReader
+	transactions TransactionStore
+	authEvents   AuthEventReader
+}
+
+// collect reads all data of the user, transactions oldest last as in the history.
+func (d *userDataSources) collect(ctx context.Context, userID uuid.UUID) (UserData, error) {
+	user, err := d.users.GetByID(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data := UserData{
+		ExportedAt: time.Now().UTC(),
+		Profile: UserDataProfile{
+			UserID:    user.UserID,
+			Username:  user.Username,
+			Email:     user.Email,
+			Role:      user.Role,
+			DormantAt: user.DormantAt,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+		Transactions: []models.TransactionDB{},
+	}
+
+	if data.Wallets, err = d.wallets.GetByUserID(ctx, userID); err != nil {
+		return UserData{}, err
+	}
+
+	filter := models.TransactionFilter{UserID: userID, Limit: userDataPageSize}
+	for {
+		page, err := d.transactions.List(ctx, filter)
+		if err != nil {
+			return UserData{}, err
+		}
+		data.Transactions = append(data.Transactions, page...)
+		if len(page) < userDataPageSize {
+			break
+		}
+		filter.BeforeID = page[len(page)-1].ID
+	}
+
+	if data.AuthEvents, err = d.authEvents.ListByUserID(ctx, userID, loginEventTypes, userDataAuthEventLimit); err != nil {
+		return UserData{}, err
+	}
+	if data.AuthEvents == nil {
+		data.AuthEvents = []models.AuthEventDB{}
+	}
+
+	return data, nil
+}
+
+// BuildUserDataArchive packs the user's data as a ZIP of JSON files: profile.json,
+// wallets.json, transactions.json and login_history.json.
+func BuildUserDataArchive(data UserData) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", data.Profile},
+		{"wallets.json", data.Wallets},
+		{"transactions.json", data.Transactions},
+		{"login_history.json", data.AuthEvents},
+	}
+	for _, file := range files {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: data.ExportedAt})
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportService_UserData(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockExportWriter(ctrl)
+	reader := NewMockExportReader(ctrl)
+	users := NewMockUserByIDReader(ctrl)
+	wallets := NewMockWalletReader(ctrl)
+	transactions := NewMockTransactionStore(ctrl)
+	authEvents := NewMockAuthEventReader(ctrl)
+
+	t.Run("disabled without WithUserData", func(t *testing.T) {
+		svc := NewExportService(writer, reader, nil)
+		_, err := svc.RequestExport(ctx, userID, models.ExportFormatUserDataZIP)
+		assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+	})
+
+	svc := NewExportService(writer, reader, nil, WithUserData(users, wallets, transactions, authEvents))
+
+	t.Run("request", func(t *testing.T) {
+		writer.EXPECT().Create(ctx, userID, models.ExportFormatUserDataZIP).Return(exportID, nil)
+		got, err := svc.RequestExport(ctx, userID, models.ExportFormatUserDataZIP)
+		assert.NoError(t, err)
+		assert.Equal(t, exportID, got)
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		reader.EXPECT().GetLatest(ctx, userID, models.ExportFormatUserDataZIP).Return(&models.ExportDB{ExportID: exportID}, nil)
+		export, err := svc.GetLatestExport(ctx, userID, models.ExportFormatUserDataZIP)
+		assert.NoError(t, err)
+		assert.Equal(t, exportID, export.ExportID)
+
+		reader.EXPECT().GetLatest(ctx, userID, models.ExportFormatUserDataZIP).Return(nil, sql.ErrNoRows)
+		_, err = svc.GetLatestExport(ctx, userID, models.ExportFormatUserDataZIP)
+		assert.ErrorIs(t, err, ErrExportNotFound)
+
+		reader.EXPECT().GetLatest(ctx, userID, models.ExportFormatUserDataZIP).Return(nil, errors.New("db error"))
+		_, err = svc.GetLatestExport(ctx, userID, models.ExportFormatUserDataZIP)
+		assert.EqualError(t, err, "db error")
+	})
+
+	t.Run("process", func(t *testing.T) {
+		// A full first page makes the export read the next one
+		page := make([]models.TransactionDB, userDataPageSize)
+		for i := range page {
+			page[i] = models.TransactionDB{ID: int64(userDataPageSize + 1 - i), UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("1")}
+		}
+		last := []models.TransactionDB{{ID: 1, UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("1")}}
+
+		var content []byte
+		gomock.InOrder(
+			writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(&models.ExportDB{ExportID: exportID, UserID: userID, Format: models.ExportFormatUserDataZIP}, nil),
+			users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID, Username: "alice", Email: "alice@example.com", PasswordHash: "secret-hash"}, nil),
+			wallets.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("1001")}, nil),
+			transactions.EXPECT().List(ctx, models.TransactionFilter{UserID: userID, Limit: userDataPageSize}).Return(page, nil),
+			transactions.EXPECT().List(ctx, models.TransactionFilter{UserID: userID, Limit: userDataPageSize, BeforeID: 2}).Return(last, nil),
+			authEvents.EXPECT().ListByUserID(ctx, userID, loginEventTypes, userDataAuthEventLimit).Return(nil, nil),
+			writer.EXPECT().Complete(ctx, exportID, gomock.Any()).DoAndReturn(func(_ context.Context, _ uuid.UUID, c []byte) error {
+				content = c
+				return nil
+			}),
+			writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(nil, sql.ErrNoRows),
+		)
+		assert.NoError(t, svc.ProcessPending(ctx))
+
+		files := readZIP(t, content)
+		assert.Len(t, files, 4)
+		assert.Contains(t, string(files["profile.json"]), `"username": "alice"`)
+		assert.NotContains(t, string(files["profile.json"]), "secret-hash")
+		assert.JSONEq(t, `{"USD": 1001.00}`, string(files["wallets.json"]))
+		assert.JSONEq(t, `[]`, string(files["login_history.json"]))
+
+		var txns []models.TransactionDB
+		assert.NoError(t, json.Unmarshal(files["transactions.json"], &txns))
+		assert.Len(t, txns, userDataPageSize+1)
+	})
+
+	t.Run("process failure", func(t *testing.T) {
+		gomock.InOrder(
+			writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(&models.ExportDB{ExportID: exportID, UserID: userID, Format: models.ExportFormatUserDataZIP}, nil),
+			users.EXPECT().GetByID(ctx, userID).Return(nil, errors.New("db error")),
+			writer.EXPECT().Fail(ctx, exportID, "db error").Return(nil),
+			writer.EXPECT().ClaimPending(ctx, exportProcessingTimeout).Return(nil, sql.ErrNoRows),
+		)
+		assert.NoError(t, svc.ProcessPending(ctx))
+	})
+}
+
+func readZIP(t *testing.T, content []byte) map[string][]byte {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+	}
+	return files
+}
@@ -2,30 +2,92 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
+	"encoding/base64"
 	"errors"
+	"maps"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
 	"github.com/segmentio/kafka-go"
+	"golang.org/x/sync/singleflight"
 )
 
 var (
 	// ErrInsufficientFunds is returned when a user tries to withdraw or exchange more than their balance.
 	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrExchangeRateNotFound is returned when the exchanger has no rate for the requested currency pair.
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	// ErrExchangerUnavailable is returned when the exchanger service cannot be reached.
+	ErrExchangerUnavailable = errors.New("exchanger unavailable")
+	// ErrExchangerTimeout is returned when the exchanger does not respond in time.
+	ErrExchangerTimeout = errors.New("exchanger timeout")
+	// ErrInvalidCursor is returned when a transaction history cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrWalletNotFound is returned when closing or editing a wallet the user does not have.
+	ErrWalletNotFound = errors.New("wallet not found")
+	// ErrWalletNotEmpty is returned when closing a wallet with funds left and no payout currency.
+	ErrWalletNotEmpty = errors.New("wallet not empty")
+	// ErrWalletOverdrawn is returned when closing a wallet with a negative balance.
+	ErrWalletOverdrawn = errors.New("wallet overdrawn")
+	// ErrDailyLimitExceeded is returned when a withdrawal or exchange would exceed the user's daily limit.
+	ErrDailyLimitExceeded = errors.New("daily limit exceeded")
+	// ErrMonthlyLimitExceeded is returned when a withdrawal or exchange would exceed the user's monthly limit.
+	ErrMonthlyLimitExceeded = errors.New("monthly limit exceeded")
 )
 
-// WalletWriter defines methods for writing deposits and withdrawals.
+// Limits for the number of transactions returned per history page.
+const (
+	DefaultTransactionPageLimit = 20
+	MaxTransactionPageLimit     = 100
+)
+
+// mapExchangerError converts facade errors into domain errors. Unknown errors are returned unchanged.
+func mapExchangerError(err error) error {
+	switch {
+	case errors.Is(err, facades.ErrRateNotFound):
+		return ErrExchangeRateNotFound
+	case errors.Is(err, facades.ErrExchangerUnavailable):
+		return ErrExchangerUnavailable
+	case errors.Is(err, facades.ErrExchangerTimeout):
+		return ErrExchangerTimeout
+	default:
+		return err
+	}
+}
+
+// WalletWriter defines methods for writing deposits and withdrawals. Every change is
+// posted to the ledger as a balanced transaction with the given transaction ID.
 type WalletWriter interface {
-	SaveDeposit(ctx context.Context, userID uuid.UUID, amount float64, currency string) error  // Saves a deposit for a user
-	SaveWithdraw(ctx context.Context, userID uuid.UUID, amount float64, currency string) error // Saves a withdrawal for a user
+	SaveDeposit(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error  // Saves a deposit for a user
+	SaveWithdraw(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error // Saves a withdrawal for a user; repositories.ErrInsufficientFunds if funds are insufficient
+	// Moves amount out of the fromCurrency wallet and toAmount into the toCurrency wallet atomically;
+	// fee is the part of amount posted to the fee account instead of being exchanged;
+	// repositories.ErrInsufficientFunds if the available balance is lower than amount
+	SaveExchange(ctx context.Context, transactionID, userID uuid.UUID, fromCurrency string, amount, fee money.Amount, toCurrency string, toAmount money.Amount) error
+	// Closes a wallet, crediting its balance converted at rate to toCurrency if set
+	Close(ctx context.Context, transactionID, userID uuid.UUID, currency, toCurrency string, rate float32) (balance, credited money.Amount, err error)
+	// Opens empty wallets in the currencies the user has none in; returns their currencies
+	Create(ctx context.Context, userID uuid.UUID, currencies []string) ([]string, error)
 }
 
 // WalletReader defines methods for reading user balances.
 type WalletReader interface {
-	GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]float64, error) // Returns user balances by currency
+	GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) // Returns user balances by currency
+}
+
+// OverdraftReader reads the overdraft limits of user wallets.
+type OverdraftReader interface {
+	GetOverdraftLimits(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) // Returns non-zero overdraft limits by currency
 }
 
 // ExchangeRateReader retrieves exchange rates.
@@ -34,25 +96,223 @@ type ExchangeRateReader interface {
 	GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error) // Returns exchange rate for a currency pair
 }
 
+// SourcedRateReader is an ExchangeRateReader that reports the provider each rate came from,
+// e.g. *RateProviders. Rates of other readers are attributed to defaultRateProvider.
+type SourcedRateReader interface {
+	GetSourcedExchangeRates(ctx context.Context) (rates map[string]float32, provider string, err error)                                // Returns current exchange rates with their provider
+	GetSourcedExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (rate float32, provider string, err error) // Returns exchange rate for a currency pair with its provider
+}
+
+// defaultRateProvider names the provider of rates read from a reader that does not report it.
+const defaultRateProvider = "exchanger"
+
 // ExchangeRateCacheReader caches exchange rates.
 type ExchangeRateCacheReader interface {
-	GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error)    // Returns cached exchange rate
-	SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32) error // Sets cached exchange rate
+	GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (rate float32, src models.RateSource, err error) // Returns cached exchange rate with its source
+	SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32, src models.RateSource) error       // Sets cached exchange rate
+	GetExchangeRates(ctx context.Context) (rates map[string]float32, src models.RateSource, err error)                                // Returns the cached rates of all currencies with their source
+	GetExchangeRatesForCurrencies(ctx context.Context, pairs []models.CurrencyPair) (map[models.CurrencyPair]models.PairRate, error)  // Returns the cached rates of several pairs in one round trip, leaving out misses
+	SetExchangeRates(ctx context.Context, rates map[string]float32, src models.RateSource) error                                      // Sets the cached rates of all currencies
+}
+
+// RateTTLPolicy decides how long cached exchange rates stay fresh.
+type RateTTLPolicy interface {
+	TTL() time.Duration                       // Returns the current freshness TTL
+	Observe(latency time.Duration, err error) // Adapts the TTL to the outcome of an exchanger call
+}
+
+// CurrencyLister lists the supported currencies.
+type CurrencyLister interface {
+	Codes(ctx context.Context) []string // Returns the supported currency codes
+}
+
+// CurrencyPrecision reports how many decimal places amounts in a currency have.
+type CurrencyPrecision interface {
+	Decimals(ctx context.Context, code string) int // Returns the decimal places of the currency
+}
+
+// TransactionStore persists and lists the user's transaction history.
+type TransactionStore interface {
+	Save(ctx context.Context, txn models.TransactionDB) error                                  // Appends a transaction
+	List(ctx context.Context, filter models.TransactionFilter) ([]models.TransactionDB, error) // Returns matching transactions, newest first
 }
 
-// KafkaWriter defines a Kafka writer abstraction.
-type KafkaWriter interface {
-	WriteMessages(ctx context.Context, msgs ...kafka.Message) error // Writes messages to Kafka
-	Close() error                                                   // Closes the Kafka writer
+// SpendingLimiter tracks withdrawals and exchanges against the user's limits.
+type SpendingLimiter interface {
+	// Records amount unless it exceeds a limit; returns the usage ID or the exceeded period
+	Reserve(ctx context.Context, userID uuid.UUID, currency string, amount money.Amount) (usageID int64, exceeded string, err error)
+	Release(ctx context.Context, usageID int64) error // Removes the usage of an operation that failed
+}
+
+// ExchangeReceiptRecorder queues receipts of executed conversions for the exchanger.
+type ExchangeReceiptRecorder interface {
+	Record(ctx context.Context, receipt models.ExchangeReceipt) error // Queues a receipt for delivery
+}
+
+// WebhookNotifier queues wallet events for the registered webhooks.
+type WebhookNotifier interface {
+	Notify(ctx context.Context, event models.WebhookEvent) error // Queues an event for delivery
+}
+
+// EventPublisher publishes events to a message broker: a kafka.Writer, or a NATS JetStream
+// or RabbitMQ publisher from the brokers package. Events use the kafka.Message envelope; the
+// topic is the Kafka topic, NATS subject or RabbitMQ routing key, the key identifies the event.
+type EventPublisher interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error // Publishes events, returning once the broker has them
+	Close() error                                                   // Closes the publisher
 }
 
 // WalletService handles wallet operations and Kafka publishing.
 type WalletService struct {
 	writeRepo   WalletWriter
 	readRepo    WalletReader
+	balanceRepo WalletReader
 	rateRepo    ExchangeRateReader
 	cacheRepo   ExchangeRateCacheReader
-	kafkaWriter KafkaWriter
+	kafkaWriter EventPublisher
+	balances    EventPublisher // Balance changes for the compacted balance topic, nil disables them
+	updates     BalanceNotifier
+	encoder     MessageEncoder
+	largeTopic  string                  // Topic of large transaction events, empty for the writer's topic
+	topic       string                  // Topic of the other transaction events
+	largeAmount map[string]money.Amount // Amounts above which an event is large, by currency
+	history     TransactionStore
+	limiter     SpendingLimiter
+	rateTTL     RateTTLPolicy
+	maxStale    time.Duration // Longest age of a rate served stale, zero for no bound, negative if disabled
+	holds       WalletHoldStore
+	currencies  CurrencyLister
+	precision   CurrencyPrecision
+	receipts    ExchangeReceiptRecorder
+	reversals   TransactionReversalStore
+	processed   ProcessedMessageStore
+	tx          Transactor
+	locker      WalletLocker
+	audit       AuditWriter
+	webhooks    WebhookNotifier
+	overdrafts  OverdraftReader
+	details     WalletDetailsStore
+	pots        WalletPotStore
+	quotes      ExchangeQuoteStore
+	quoteTTL    time.Duration
+	fees        ExchangeFeeReader
+	minExchange money.Amount
+	maxExchange money.Amount
+	rateHistory RateHistoryStore
+	pivot       string
+	rateFlight  singleflight.Group // Coalesces concurrent fetches of the rate of a pair
+
+	paymentRequests   PaymentRequestStore
+	users             UserReader
+	paymentRequestTTL time.Duration
+}
+
+// WalletOpt defines a functional option for WalletService.
+type WalletOpt func(*WalletService)
+
+// WithBalanceReadModel serves GetUserBalance from an alternative, eventually
+// consistent read model (e.g. the balance projection) instead of the wallets table.
+// Balances returned after money operations are still read from the wallets table.
+func WithBalanceReadModel(reader WalletReader) WalletOpt {
+	return func(s *WalletService) {
+		s.balanceRepo = reader
+	}
+}
+
+// WithTransactionHistory records every operation changing a balance in the transaction
+// history and enables ListTransactions. The operation is recorded in the same database
+// transaction as the balance change, run by tx, so the writer and the store must take part
+// in it; an operation that cannot be recorded fails.
+func WithTransactionHistory(store TransactionStore, tx Transactor) WalletOpt {
+	return func(s *WalletService) {
+		s.history = store
+		s.tx = tx
+	}
+}
+
+// WithSpendingLimits enforces the users' daily and monthly limits on withdrawals
+// and on the source currency of exchanges.
+func WithSpendingLimits(limiter SpendingLimiter) WalletOpt {
+	return func(s *WalletService) {
+		s.limiter = limiter
+	}
+}
+
+// WithRateTTL treats cached exchange rates older than the policy TTL as stale: they are
+// refreshed from the exchanger, and served flagged as stale only while the exchanger is
+// unavailable or times out. Without it, cached rates are used until the cache expires them.
+func WithRateTTL(policy RateTTLPolicy) WalletOpt {
+	return func(s *WalletService) {
+		s.rateTTL = policy
+	}
+}
+
+// WithMaxRateStaleness bounds how old a cached rate may be to be served flagged as stale
+// while the exchanger is unavailable or times out; older rates fail like the exchanger.
+// A non-positive max disables the fallback. Without it, stale rates are served until the
+// cache expires them.
+func WithMaxRateStaleness(max time.Duration) WalletOpt {
+	return func(s *WalletService) {
+		if max <= 0 {
+			max = -1
+		}
+		s.maxStale = max
+	}
+}
+
+// WithCurrencies lists every supported currency in returned balances and rates,
+// with a zero balance for currencies the user has no wallet in. Without it, only
+// existing wallets are listed.
+func WithCurrencies(currencies CurrencyLister) WalletOpt {
+	return func(s *WalletService) {
+		s.currencies = currencies
+	}
+}
+
+// WithCurrencyPrecision rounds exchanged amounts to the decimal places of the target
+// currency instead of to minor units.
+func WithCurrencyPrecision(precision CurrencyPrecision) WalletOpt {
+	return func(s *WalletService) {
+		s.precision = precision
+	}
+}
+
+// WithExchangeReceipts sends a receipt of every executed conversion, exchanges and
+// payouts on wallet closure, to the exchanger for reconciliation. Receipts are queued in
+// the database transaction of the conversion, so the recorder must take part in the one
+// run by the Transactor of WithTransactionHistory.
+func WithExchangeReceipts(recorder ExchangeReceiptRecorder) WalletOpt {
+	return func(s *WalletService) {
+		s.receipts = recorder
+	}
+}
+
+// WithWebhooks notifies the webhooks of the user and the admin webhooks of every
+// deposit, withdrawal and exchange.
+func WithWebhooks(notifier WebhookNotifier) WalletOpt {
+	return func(s *WalletService) {
+		s.webhooks = notifier
+	}
+}
+
+// WithOverdraftLimits reports the overdraft limits of the user's wallets along with
+// the balances. The limits themselves are enforced by the wallet writer on withdrawal.
+func WithOverdraftLimits(reader OverdraftReader) WalletOpt {
+	return func(s *WalletService) {
+		s.overdrafts = reader
+	}
+}
+
+// WithTransactionTopics publishes the events of transactions above the threshold of their
+// currency to largeTopic and the others to topic. Transactions in currencies without a
+// threshold are published to topic. The publisher must not have a topic of its own.
+// Without it, all events are published to the topic of the writer.
+func WithTransactionTopics(largeTopic, topic string, thresholds map[string]money.Amount) WalletOpt {
+	return func(s *WalletService) {
+		s.largeTopic = largeTopic
+		s.topic = topic
+		s.largeAmount = thresholds
+	}
 }
 
 // NewWalletService creates a new WalletService.
@@ -61,160 +321,890 @@ func NewWalletService(
 	readRepo WalletReader,
 	rateRepo ExchangeRateReader,
 	cacheRepo ExchangeRateCacheReader,
-	kafkaWriter KafkaWriter,
+	kafkaWriter EventPublisher,
+	opts ...WalletOpt,
 ) *WalletService {
-	return &WalletService{
+	s := &WalletService{
 		writeRepo:   writeRepo,
 		readRepo:    readRepo,
+		balanceRepo: readRepo,
 		rateRepo:    rateRepo,
 		cacheRepo:   cacheRepo,
 		kafkaWriter: kafkaWriter,
+		encoder:     JSONTransactionEncoder{},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// publishTransaction publishes a transaction to Kafka.
-func (s *WalletService) publishTransaction(ctx context.Context, txn models.Transaction) {
+// transactionEventNamespace is the UUID namespace of the IDs of transaction events.
+var transactionEventNamespace = uuid.MustParse("5d3c1e7a-2b4f-4c8e-9a6d-0f1e2d3c4b5a")
+
+// publishTransaction publishes a transaction in currency to Kafka. Events are keyed by the
+// user, so the operations of a user keep their order within a partition, and carry an event ID
+// derived from the transaction, so a redelivered event has the same ID.
+func (s *WalletService) publishTransaction(ctx context.Context, currency string, txn models.Transaction) {
 	if s.kafkaWriter == nil {
-		logger.Log.Warnw("Kafka writer not configured, skipping publishing", "transaction_id", txn.TransactionID)
+		logger.FromContext(ctx).Warnw("Kafka writer not configured, skipping publishing", "transaction_id", txn.TransactionID)
 		return
 	}
+	txn.Version = models.TransactionEventVersion
+	txn.Currency = currency
+	txn.EventID = transactionEventID(txn)
 
-	data, err := json.Marshal(txn)
+	data, err := s.encoder.Encode(ctx, txn)
 	if err != nil {
-		logger.Log.Errorw("Failed to marshal transaction for Kafka", "transaction_id", txn.TransactionID, "error", err)
+		logger.FromContext(ctx).Errorw("Failed to encode transaction for Kafka", "transaction_id", txn.TransactionID, "error", err)
 		return
 	}
 
 	msg := kafka.Message{
-		Key:   []byte(txn.TransactionID),
-		Value: data,
+		Topic:   s.transactionTopic(currency, txn.Amount),
+		Key:     []byte(txn.UserID),
+		Value:   data,
+		Headers: append([]kafka.Header{{Key: models.EventIDHeader, Value: []byte(txn.EventID)}}, requestid.KafkaHeaders(ctx)...),
 	}
 
 	if err := s.kafkaWriter.WriteMessages(ctx, msg); err != nil {
-		logger.Log.Errorw("Failed to publish transaction to Kafka", "transaction_id", txn.TransactionID, "error", err)
+		logger.FromContext(ctx).Errorw("Failed to publish transaction to Kafka", "transaction_id", txn.TransactionID, "topic", msg.Topic, "error", err)
 	} else {
-		logger.Log.Infow("Transaction published to Kafka", "transaction_id", txn.TransactionID, "amount", txn.Amount)
+		logger.FromContext(ctx).Infow("Transaction published to Kafka", "transaction_id", txn.TransactionID, "amount", txn.Amount, "topic", msg.Topic)
+	}
+}
+
+// eventBalances returns balances, or if nil the balances of userID read for the events of an
+// operation. They are read only if events are published; a failure is logged and returns nil,
+// publishing the events without balances.
+func (s *WalletService) eventBalances(ctx context.Context, userID uuid.UUID, balances map[string]money.Amount) map[string]money.Amount {
+	if balances != nil || (s.kafkaWriter == nil && s.balances == nil && !s.notifiesBalances(userID)) {
+		return balances
+	}
+	balances, err := s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balances for events", "userID", userID, "error", err)
+		return nil
+	}
+	return balances
+}
+
+// balanceOf returns the balance of currency in balances, nil if there is no such wallet.
+func balanceOf(balances map[string]money.Amount, currency string) *money.Amount {
+	balance, ok := balances[currency]
+	if !ok {
+		return nil
+	}
+	return &balance
+}
+
+// transactionEventID returns the ID of the event of txn, the same for every delivery of it.
+func transactionEventID(txn models.Transaction) string {
+	return uuid.NewSHA1(transactionEventNamespace, []byte(txn.Operation+":"+txn.TransactionID)).String()
+}
+
+// transactionTopic returns the topic of the event of a transaction of amount in currency.
+func (s *WalletService) transactionTopic(currency string, amount money.Amount) string {
+	if threshold, ok := s.largeAmount[currency]; ok && amount > threshold {
+		return s.largeTopic
+	}
+	return s.topic
+}
+
+// inTx runs fn, which changes balances and records the operation with recordTransaction
+// and recordReceipt, in one database transaction that is rolled back if fn fails, so that
+// the history and the receipts have every balance change and nothing else. Without a
+// Transactor, fn runs on its own.
+func (s *WalletService) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.InTx(ctx, fn)
+}
+
+// recordTransaction appends an operation to the transaction history, if any. Call it from
+// inTx along with the balance change.
+func (s *WalletService) recordTransaction(ctx context.Context, txn models.TransactionDB) error {
+	if s.history == nil {
+		return nil
+	}
+	if err := s.history.Save(ctx, txn); err != nil {
+		logger.FromContext(ctx).Errorw("failed to record transaction", "transaction_id", txn.TransactionID, "userID", txn.UserID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// recordReceipt queues the receipt of a conversion for the exchanger, if enabled. Call it
+// from inTx along with the balance change.
+func (s *WalletService) recordReceipt(ctx context.Context, receipt models.ExchangeReceipt) error {
+	if s.receipts == nil {
+		return nil
+	}
+	if err := s.receipts.Record(ctx, receipt); err != nil {
+		logger.FromContext(ctx).Errorw("failed to record exchange receipt", "transaction_id", receipt.TransactionID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// notifyWebhooks queues a completed operation for the webhooks.
+// The balance has already changed at this point, so failures are logged rather than returned.
+func (s *WalletService) notifyWebhooks(ctx context.Context, eventType string, txn models.TransactionDB) {
+	if s.webhooks == nil {
+		return
+	}
+	event := models.WebhookEvent{
+		Type:          eventType,
+		TransactionID: txn.TransactionID,
+		UserID:        txn.UserID,
+		Currency:      txn.Currency,
+		Amount:        txn.Amount,
+		ToCurrency:    txn.ToCurrency,
+		ToAmount:      txn.ToAmount,
+		Reference:     txn.Reference,
+		OccurredAt:    time.Now().UTC(),
+	}
+	if err := s.webhooks.Notify(ctx, event); err != nil {
+		logger.FromContext(ctx).Errorw("failed to notify webhooks", "type", eventType, "transaction_id", txn.TransactionID, "error", err)
+	}
+}
+
+// optionalReference returns nil for an empty reference, which is stored as NULL.
+func optionalReference(reference string) *string {
+	if reference == "" {
+		return nil
+	}
+	return &reference
+}
+
+// reserveLimit records amount against the user's limits in currency and returns the usage ID,
+// or ErrDailyLimitExceeded or ErrMonthlyLimitExceeded. Without a limiter nothing is tracked.
+func (s *WalletService) reserveLimit(ctx context.Context, userID uuid.UUID, currency string, amount money.Amount) (int64, error) {
+	if s.limiter == nil {
+		return 0, nil
+	}
+
+	usageID, exceeded, err := s.limiter.Reserve(ctx, userID, currency, amount)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to reserve limit usage", "userID", userID, "amount", amount, "currency", currency, "error", err)
+		return 0, err
+	}
+
+	switch exceeded {
+	case models.LimitPeriodDaily:
+		logger.FromContext(ctx).Warnw("daily limit exceeded", "userID", userID, "amount", amount, "currency", currency)
+		return 0, ErrDailyLimitExceeded
+	case models.LimitPeriodMonthly:
+		logger.FromContext(ctx).Warnw("monthly limit exceeded", "userID", userID, "amount", amount, "currency", currency)
+		return 0, ErrMonthlyLimitExceeded
+	}
+	return usageID, nil
+}
+
+// releaseLimit gives back the usage of an operation that failed. Failures are logged,
+// leaving the usage counted until it falls out of the window.
+func (s *WalletService) releaseLimit(ctx context.Context, usageID int64) {
+	if s.limiter == nil {
+		return
+	}
+	if err := s.limiter.Release(ctx, usageID); err != nil {
+		logger.FromContext(ctx).Errorw("failed to release limit usage", "usageID", usageID, "error", err)
 	}
 }
 
-// Deposit adds funds to a user's balance and publishes the transaction.
-func (s *WalletService) Deposit(ctx context.Context, userID uuid.UUID, amount float64, currency string) (usd, rub, eur float64, err error) {
-	if err := s.writeRepo.SaveDeposit(ctx, userID, amount, currency); err != nil {
-		logger.Log.Errorw("failed to save deposit", "userID", userID, "amount", amount, "currency", currency, "error", err)
-		return 0, 0, 0, err
+// Deposit adds funds to a user's balance and publishes the transaction. The optional
+// reference of the client is kept in the history, Kafka message and webhook event.
+func (s *WalletService) Deposit(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error) {
+	record := models.TransactionDB{
+		TransactionID: uuid.New(),
+		UserID:        userID,
+		Operation:     models.OperationDeposit,
+		Currency:      currency,
+		Amount:        amount,
+		Reference:     optionalReference(reference),
+	}
+	txnID := record.TransactionID
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		if err := s.writeRepo.SaveDeposit(ctx, txnID, userID, amount, currency); err != nil {
+			return err
+		}
+		return s.recordTransaction(ctx, record)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to save deposit", "userID", userID, "amount", amount, "currency", currency, "error", err)
+		return nil, err
 	}
 
 	balances, err := s.readRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		logger.Log.Errorw("failed to get balances after deposit", "userID", userID, "error", err)
-		return 0, 0, 0, err
+		logger.FromContext(ctx).Errorw("failed to get balances after deposit", "userID", userID, "error", err)
+		return nil, err
 	}
+	balances = s.withSupportedCurrencies(ctx, balances)
 
-	usd, rub, eur = balances[models.USD], balances[models.RUB], balances[models.EUR]
+	s.notifyWebhooks(ctx, models.WebhookEventDeposit, record)
 
 	txn := models.Transaction{
-		TransactionID: uuid.NewString(),
+		TransactionID: txnID.String(),
 		Timestamp:     time.Now().Unix(),
 		Amount:        amount,
 		UserID:        userID.String(),
 		Operation:     "deposit",
+		Reference:     reference,
+		Balance:       balanceOf(balances, currency),
 	}
-	s.publishTransaction(ctx, txn)
+	s.publishTransaction(ctx, currency, txn)
+	s.publishBalances(ctx, txnID, userID, balances, currency)
 
-	return usd, rub, eur, nil
+	return balances, nil
 }
 
-// Withdraw removes funds from a user's balance and publishes the transaction.
-func (s *WalletService) Withdraw(ctx context.Context, userID uuid.UUID, amount float64, currency string) (usd, rub, eur float64, err error) {
-	if err := s.writeRepo.SaveWithdraw(ctx, userID, amount, currency); err != nil {
-		logger.Log.Errorw("failed to save withdrawal", "userID", userID, "amount", amount, "currency", currency, "error", err)
-		return 0, 0, 0, err
+// Withdraw removes funds from a user's balance and publishes the transaction. The optional
+// reference of the client is kept in the history, Kafka message and webhook event.
+func (s *WalletService) Withdraw(ctx context.Context, userID uuid.UUID, amount money.Amount, currency, reference string) (map[string]money.Amount, error) {
+	usageID, err := s.reserveLimit(ctx, userID, currency, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.TransactionDB{
+		TransactionID: uuid.New(),
+		UserID:        userID,
+		Operation:     models.OperationWithdraw,
+		Currency:      currency,
+		Amount:        amount,
+		Reference:     optionalReference(reference),
+	}
+	txnID := record.TransactionID
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		if err := s.writeRepo.SaveWithdraw(ctx, txnID, userID, amount, currency); err != nil {
+			return err
+		}
+		return s.recordTransaction(ctx, record)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to save withdrawal", "userID", userID, "amount", amount, "currency", currency, "error", err)
+		s.releaseLimit(ctx, usageID)
+		if errors.Is(err, repositories.ErrInsufficientFunds) {
+			return nil, ErrInsufficientFunds
+		}
+		return nil, err
 	}
 
 	balances, err := s.readRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		logger.Log.Errorw("failed to get balances after withdrawal", "userID", userID, "error", err)
-		return 0, 0, 0, err
+		logger.FromContext(ctx).Errorw("failed to get balances after withdrawal", "userID", userID, "error", err)
+		return nil, err
 	}
+	balances = s.withSupportedCurrencies(ctx, balances)
 
-	usd, rub, eur = balances[models.USD], balances[models.RUB], balances[models.EUR]
+	s.notifyWebhooks(ctx, models.WebhookEventWithdraw, record)
 
 	txn := models.Transaction{
-		TransactionID: uuid.NewString(),
+		TransactionID: txnID.String(),
 		Timestamp:     time.Now().Unix(),
 		Amount:        amount,
 		UserID:        userID.String(),
 		Operation:     "withdraw",
+		Reference:     reference,
+		Balance:       balanceOf(balances, currency),
 	}
-	s.publishTransaction(ctx, txn)
+	s.publishTransaction(ctx, currency, txn)
+	s.publishBalances(ctx, txnID, userID, balances, currency)
 
-	return usd, rub, eur, nil
+	return balances, nil
 }
 
-// GetUserBalance returns the user's balance in all currencies.
-func (s *WalletService) GetUserBalance(ctx context.Context, userID uuid.UUID) (usd, rub, eur float64, err error) {
-	balances, err := s.readRepo.GetByUserID(ctx, userID)
+// GetUserBalance returns the user's balance by currency.
+func (s *WalletService) GetUserBalance(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	balances, err := s.balanceRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		logger.Log.Errorw("failed to get user balances", "userID", userID, "error", err)
-		return 0, 0, 0, err
+		logger.FromContext(ctx).Errorw("failed to get user balances", "userID", userID, "error", err)
+		return nil, err
 	}
-	usd, rub, eur = balances[models.USD], balances[models.RUB], balances[models.EUR]
-	return usd, rub, eur, nil
+	return s.withSupportedCurrencies(ctx, balances), nil
 }
 
-// GetExchangeRates returns current exchange rates for USD, RUB, and EUR.
-func (s *WalletService) GetExchangeRates(ctx context.Context) (usd, rub, eur float32, err error) {
-	rates, err := s.rateRepo.GetExchangeRates(ctx)
+// GetTotalBalance converts the user's holdings to currency at current rates, preferring
+// cached ones, and returns their sum with the per-wallet breakdown and the rates used.
+// Each converted balance is rounded to minor units before summing.
+func (s *WalletService) GetTotalBalance(ctx context.Context, userID uuid.UUID, currency string) (models.BalanceTotal, error) {
+	balances, err := s.balanceRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		logger.Log.Errorw("failed to get exchange rates", "error", err)
-		return 0, 0, 0, err
+		logger.FromContext(ctx).Errorw("failed to get user balances", "userID", userID, "error", err)
+		return models.BalanceTotal{}, err
 	}
-	usd, rub, eur = rates[models.USD], rates[models.RUB], rates[models.EUR]
-	return usd, rub, eur, nil
+
+	codes := slices.Sorted(maps.Keys(balances))
+	cached := s.cachedExchangeRates(ctx, codes, balances, currency)
+
+	total := models.BalanceTotal{Currency: currency, Holdings: []models.ConvertedBalance{}}
+	for _, code := range codes {
+		balance := balances[code]
+		if balance == 0 {
+			continue
+		}
+
+		holding := models.ConvertedBalance{Currency: code, Balance: balance, Rate: 1, Converted: balance}
+		if code != currency {
+			pr, ok := cached[code]
+			if !ok {
+				rate, src, _, err := s.pairRate(ctx, code, currency)
+				if err != nil {
+					return models.BalanceTotal{}, err
+				}
+				pr = models.PairRate{Rate: rate, Source: src}
+			}
+			holding.Rate = pr.Rate
+			if holding.Converted, err = balance.Convert(pr.Rate); err != nil {
+				logger.FromContext(ctx).Errorw("failed to convert balance", "userID", userID, "from", code, "to", currency, "rate", pr.Rate, "error", err)
+				return models.BalanceTotal{}, err
+			}
+			total.StaleRate = total.StaleRate || pr.Source.Stale
+		}
+
+		total.Holdings = append(total.Holdings, holding)
+		total.Total += holding.Converted
+	}
+	return total, nil
 }
 
-// Exchange performs currency exchange for a user and publishes the transaction.
-func (s *WalletService) Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount float64) (exchangedAmount float32, usd, rub, eur float64, err error) {
-	rate, err := s.cacheRepo.GetExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
+// cachedExchangeRates reads the cached rates from the currencies of the non-zero balances to
+// currency in one round trip and returns the fresh ones by source currency. Missing and
+// expired rates are left to pairRate, as are all of them when the read fails or there is
+// a single rate to read, which pairRate reads in one round trip anyway.
+func (s *WalletService) cachedExchangeRates(ctx context.Context, codes []string, balances map[string]money.Amount, currency string) map[string]models.PairRate {
+	var pairs []models.CurrencyPair
+	for _, code := range codes {
+		if balances[code] != 0 && code != currency {
+			pairs = append(pairs, models.CurrencyPair{From: code, To: currency})
+		}
+	}
+	if len(pairs) < 2 {
+		return nil
+	}
+
+	rates, err := s.cacheRepo.GetExchangeRatesForCurrencies(ctx, pairs)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get cached exchange rates", "to", currency, "error", err)
+		return nil
+	}
+	fresh := make(map[string]models.PairRate, len(rates))
+	for pair, rate := range rates {
+		if s.freshRate(rate.Source) {
+			rate.Source.Cached = true
+			fresh[pair.From] = rate
+		}
+	}
+	return fresh
+}
+
+// GetOverdraftLimits returns the non-zero overdraft limits of the user's wallets by currency.
+// Without WithOverdraftLimits, no wallet has an overdraft.
+func (s *WalletService) GetOverdraftLimits(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	if s.overdrafts == nil {
+		return map[string]money.Amount{}, nil
+	}
+	limits, err := s.overdrafts.GetOverdraftLimits(ctx, userID)
 	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get overdraft limits", "userID", userID, "error", err)
+		return nil, err
+	}
+	return limits, nil
+}
+
+// GetExchangeRates returns current exchange rates by currency with their source. With
+// WithCurrencies, rates of currencies that are not supported are left out. While the
+// exchanger is unavailable or times out, the rates it last returned are served from the
+// cache and marked stale, within the bound of WithMaxRateStaleness.
+func (s *WalletService) GetExchangeRates(ctx context.Context) (rates map[string]float32, src models.RateSource, err error) {
+	var cached map[string]float32
+	var cachedSrc models.RateSource
+	hit := false
+	if s.cacheRepo != nil {
+		var cacheErr error
+		cached, cachedSrc, cacheErr = s.cacheRepo.GetExchangeRates(ctx)
+		hit = cacheErr == nil
+	}
+
+	if hit && s.freshRate(cachedSrc) {
+		rates, src = cached, cachedSrc
+		src.Cached = true
+	} else if rates, src, err = s.readExchangeRates(ctx); err != nil {
+		logger.FromContext(ctx).Errorw("failed to get exchange rates", "error", err)
+		err = mapExchangerError(err)
+		if !hit || !s.fallsBackToStale(err) || !s.servableStale(cachedSrc.FetchedAt) {
+			return nil, models.RateSource{}, err
+		}
+		logger.FromContext(ctx).Warnw("serving stale exchange rates", "fetched_at", cachedSrc.FetchedAt, "provider", cachedSrc.Provider)
+		metrics.StaleRatesServed.Inc()
+		rates, src = cached, cachedSrc
+		src.Cached, src.Stale = true, true
+	} else if s.cacheRepo != nil {
+		if err := s.cacheRepo.SetExchangeRates(ctx, rates, src); err != nil {
+			logger.FromContext(ctx).Errorw("failed to cache exchange rates", "error", err)
+		}
+	}
+	if s.currencies == nil {
+		return rates, src, nil
+	}
+
+	supported := make(map[string]float32, len(rates))
+	for _, code := range s.currencies.Codes(ctx) {
+		if rate, ok := rates[code]; ok {
+			supported[code] = rate
+		}
+	}
+	return supported, src, nil
+}
+
+// readExchangeRates fetches the rates of all currencies with the provider that returned them.
+func (s *WalletService) readExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error) {
+	provider := defaultRateProvider
+	var rates map[string]float32
+	var err error
+	if sourced, ok := s.rateRepo.(SourcedRateReader); ok {
+		rates, provider, err = sourced.GetSourcedExchangeRates(ctx)
+	} else {
+		rates, err = s.rateRepo.GetExchangeRates(ctx)
+	}
+	return rates, models.RateSource{Provider: provider, FetchedAt: time.Now()}, err
+}
+
+// readExchangeRate fetches the rate for a currency pair with the provider that returned it.
+func (s *WalletService) readExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
+	provider := defaultRateProvider
+	var rate float32
+	var err error
+	if sourced, ok := s.rateRepo.(SourcedRateReader); ok {
+		rate, provider, err = sourced.GetSourcedExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
+	} else {
 		rate, err = s.rateRepo.GetExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
-		if err != nil {
-			logger.Log.Errorw("failed to get exchange rate", "from", fromCurrency, "to", toCurrency, "error", err)
-			return 0, 0, 0, 0, err
+	}
+	return rate, models.RateSource{Provider: provider, FetchedAt: time.Now()}, err
+}
+
+// withSupportedCurrencies adds a zero balance for every supported currency missing
+// from balances. The map is modified in place and may be nil.
+func (s *WalletService) withSupportedCurrencies(ctx context.Context, balances map[string]money.Amount) map[string]money.Amount {
+	if balances == nil {
+		balances = make(map[string]money.Amount)
+	}
+	if s.currencies == nil {
+		return balances
+	}
+	for _, code := range s.currencies.Codes(ctx) {
+		if _, ok := balances[code]; !ok {
+			balances[code] = money.Zero
+		}
+	}
+	return balances
+}
+
+// fallsBackToStale reports whether the exchanger error allows serving a cached rate instead.
+func (s *WalletService) fallsBackToStale(err error) bool {
+	return s.cacheRepo != nil && s.maxStale >= 0 &&
+		(errors.Is(err, ErrExchangerUnavailable) || errors.Is(err, ErrExchangerTimeout))
+}
+
+// servableStale reports whether a rate fetched at fetchedAt is recent enough to be served stale.
+func (s *WalletService) servableStale(fetchedAt time.Time) bool {
+	return s.maxStale == 0 || time.Since(fetchedAt) <= s.maxStale
+}
+
+// getExchangeRate returns the rate for a currency pair with its source, preferring the cache.
+// A cached rate past its TTL is served stale if the exchanger failed.
+func (s *WalletService) getExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
+	cached, cachedSrc, cacheErr := s.cacheRepo.GetExchangeRateForCurrency(ctx, fromCurrency, toCurrency)
+	cachedSrc.Cached = true
+	if cacheErr == nil && s.freshRate(cachedSrc) {
+		return cached, cachedSrc, nil
+	}
+
+	rate, src, err := s.fetchExchangeRate(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		if cacheErr == nil && s.fallsBackToStale(err) && s.servableStale(cachedSrc.FetchedAt) {
+			logger.FromContext(ctx).Warnw("serving stale exchange rate", "from", fromCurrency, "to", toCurrency, "rate", cached,
+				"fetched_at", cachedSrc.FetchedAt, "provider", cachedSrc.Provider)
+			metrics.StaleRatesServed.Inc()
+			cachedSrc.Stale = true
+			return cached, cachedSrc, nil
 		}
+		return 0, models.RateSource{}, err
+	}
+	return rate, src, nil
+}
 
-		if err := s.cacheRepo.SetExchangeRateForCurrency(ctx, fromCurrency, toCurrency, rate); err != nil {
-			logger.Log.Errorw("failed to cache exchange rate", "from", fromCurrency, "to", toCurrency, "rate", rate, "error", err)
+// freshRate reports whether a cached rate is within the rate TTL. Without WithRateTTL,
+// cached rates stay fresh until they expire from the cache.
+func (s *WalletService) freshRate(src models.RateSource) bool {
+	return s.rateTTL == nil || time.Since(src.FetchedAt) < s.rateTTL.TTL()
+}
+
+// fetchExchangeRate fetches the rate for a currency pair from the exchanger, then caches
+// and records it. Concurrent fetches of the same pair, e.g. many exchanges missing the
+// cache at once, share a single exchanger call. A caller whose shared call failed because
+// the request that made it was cancelled fetches the rate again itself.
+func (s *WalletService) fetchExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
+	v, err, shared := s.rateFlight.Do(fromCurrency+":"+toCurrency, func() (any, error) {
+		rate, src, err := s.loadExchangeRate(ctx, fromCurrency, toCurrency)
+		return rateFetch{rate: rate, src: src, abandoned: err != nil && ctx.Err() != nil}, err
+	})
+	fetch := v.(rateFetch)
+	if shared {
+		metrics.RateFetchesShared.Inc()
+		if err != nil && fetch.abandoned && ctx.Err() == nil {
+			return s.loadExchangeRate(ctx, fromCurrency, toCurrency)
 		}
 	}
+	return fetch.rate, fetch.src, err
+}
+
+// rateFetch is the outcome of a shared fetch of a rate. abandoned reports a failure
+// caused by the cancellation of the request that made the call.
+type rateFetch struct {
+	rate      float32
+	src       models.RateSource
+	abandoned bool
+}
 
-	if err := s.writeRepo.SaveWithdraw(ctx, userID, amount, fromCurrency); err != nil {
-		logger.Log.Errorw("failed to withdraw for exchange", "userID", userID, "amount", amount, "currency", fromCurrency, "error", err)
-		return 0, 0, 0, 0, ErrInsufficientFunds
+// loadExchangeRate fetches, caches and records the rate for a currency pair. The latency
+// of the call feeds the adaptive TTL.
+func (s *WalletService) loadExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
+	start := time.Now()
+	rate, src, err := s.readExchangeRate(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		err = mapExchangerError(err)
+	}
+	if s.rateTTL != nil {
+		s.rateTTL.Observe(time.Since(start), err)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get exchange rate", "from", fromCurrency, "to", toCurrency, "error", err)
+		return 0, models.RateSource{}, err
 	}
 
-	exchangedAmount = float32(amount) * rate
-	if err := s.writeRepo.SaveDeposit(ctx, userID, float64(exchangedAmount), toCurrency); err != nil {
-		logger.Log.Errorw("failed to deposit exchanged amount", "userID", userID, "amount", exchangedAmount, "currency", toCurrency, "error", err)
-		return exchangedAmount, 0, 0, 0, err
+	if err := s.cacheRepo.SetExchangeRateForCurrency(ctx, fromCurrency, toCurrency, rate, src); err != nil {
+		logger.FromContext(ctx).Errorw("failed to cache exchange rate", "from", fromCurrency, "to", toCurrency, "rate", rate, "error", err)
 	}
+	s.recordRate(ctx, fromCurrency, toCurrency, rate, src.FetchedAt)
+	return rate, src, nil
+}
 
-	balances, err := s.readRepo.GetByUserID(ctx, userID)
+// Exchange performs currency exchange for a user and publishes the transaction.
+// The exchanged amount is rounded half away from zero to the nearest minor unit or,
+// with WithCurrencyPrecision, to the decimal places of toCurrency. With WithExchangeFees,
+// the fee of the pair is deducted before conversion. The executed quote reports the rate,
+// fee and exchanged amount; its StaleRate reports an exchange at a cached rate past its
+// TTL while the exchanger was unavailable, and its DerivedRate an exchange at a cross rate
+// through the pivot currency of WithCrossRates. A positive minToAmount guards against rate
+// movement: if less would be credited, ErrSlippageExceeded is returned and nothing is exchanged.
+func (s *WalletService) Exchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount, minToAmount money.Amount) (executed models.ExchangeQuote, balances map[string]money.Amount, err error) {
+	quote, err := s.quoteExchange(ctx, fromCurrency, toCurrency, amount)
 	if err != nil {
-		logger.Log.Errorw("failed to get balances after exchange", "userID", userID, "error", err)
-		return exchangedAmount, 0, 0, 0, err
+		return models.ExchangeQuote{}, nil, err
 	}
+	return s.exchange(ctx, userID, quote, minToAmount)
+}
 
-	usd, rub, eur = balances[models.USD], balances[models.RUB], balances[models.EUR]
+// exchange executes the quoted exchange for the user at the quoted rate, unless it credits
+// less than a positive minToAmount.
+func (s *WalletService) exchange(ctx context.Context, userID uuid.UUID, quote models.ExchangeQuote, minToAmount money.Amount) (executed models.ExchangeQuote, balances map[string]money.Amount, err error) {
+	fromCurrency, toCurrency, amount := quote.FromCurrency, quote.ToCurrency, quote.Amount
+	if minToAmount > 0 && quote.ToAmount < minToAmount {
+		logger.FromContext(ctx).Warnw("exchange rejected by slippage guard", "userID", userID, "from", fromCurrency, "to", toCurrency,
+			"to_amount", quote.ToAmount, "min_to_amount", minToAmount, "rate", quote.Rate)
+		return models.ExchangeQuote{}, nil, ErrSlippageExceeded
+	}
+
+	usageID, err := s.reserveLimit(ctx, userID, fromCurrency, amount)
+	if err != nil {
+		return models.ExchangeQuote{}, nil, err
+	}
+
+	txnID := uuid.New()
+	exchangedAmount := quote.ToAmount
+	// The balances after the exchange are filled in by the history store
+	record := models.TransactionDB{
+		TransactionID: txnID,
+		UserID:        userID,
+		Operation:     models.OperationExchange,
+		Currency:      fromCurrency,
+		Amount:        amount,
+		ToCurrency:    &toCurrency,
+		ToAmount:      &exchangedAmount,
+		Rate:          &quote.Rate,
+		Fee:           &quote.Fee,
+	}
+	// The debit, the credit, the history entry and the receipt are committed together or not at all
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		if err := s.lockWallets(ctx,
+			models.WalletKey{UserID: userID, Currency: fromCurrency},
+			models.WalletKey{UserID: userID, Currency: toCurrency},
+		); err != nil {
+			return err
+		}
+		if err := s.writeRepo.SaveExchange(ctx, txnID, userID, fromCurrency, amount, quote.Fee, toCurrency, exchangedAmount); err != nil {
+			return err
+		}
+		if err := s.recordTransaction(ctx, record); err != nil {
+			return err
+		}
+		return s.recordReceipt(ctx, models.ExchangeReceipt{
+			TransactionID: txnID,
+			Operation:     models.OperationExchange,
+			FromCurrency:  fromCurrency,
+			ToCurrency:    toCurrency,
+			Amount:        amount,
+			ToAmount:      exchangedAmount,
+			Rate:          quote.Rate,
+			ExecutedAt:    time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to save exchange", "userID", userID, "amount", amount, "fee", quote.Fee, "from", fromCurrency, "to", toCurrency, "error", err)
+		s.releaseLimit(ctx, usageID)
+		if errors.Is(err, repositories.ErrInsufficientFunds) {
+			return models.ExchangeQuote{}, nil, ErrInsufficientFunds
+		}
+		return models.ExchangeQuote{}, nil, err
+	}
+
+	balances, err = s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balances after exchange", "userID", userID, "error", err)
+		return quote, nil, err
+	}
+	balances = s.withSupportedCurrencies(ctx, balances)
+
+	if balance, ok := balances[fromCurrency]; ok {
+		record.Balance = &balance
+	}
+	if toBalance, ok := balances[toCurrency]; ok {
+		record.ToBalance = &toBalance
+	}
+	s.notifyWebhooks(ctx, models.WebhookEventExchange, record)
 
 	txn := models.Transaction{
-		TransactionID: uuid.NewString(),
+		TransactionID: txnID.String(),
 		Timestamp:     time.Now().Unix(),
 		Amount:        amount,
 		UserID:        userID.String(),
 		Operation:     "exchange",
+		ToCurrency:    toCurrency,
+		Balance:       balanceOf(balances, fromCurrency),
+		ToBalance:     balanceOf(balances, toCurrency),
+	}
+	s.publishTransaction(ctx, fromCurrency, txn)
+	s.publishBalances(ctx, txnID, userID, balances, fromCurrency, toCurrency)
+
+	return quote, balances, nil
+}
+
+// decimals returns the decimal places of amounts in currency.
+func (s *WalletService) decimals(ctx context.Context, currency string) int {
+	if s.precision == nil {
+		return money.Scale
+	}
+	return s.precision.Decimals(ctx, currency)
+}
+
+// CloseWallet closes the user's wallet in currency. If toCurrency is set, the remaining
+// balance is converted at the current rate and credited to the toCurrency wallet in the
+// same atomic operation; otherwise the wallet must be empty. A wallet with pending holds
+// or a negative balance cannot be closed. Returns the credited amount and the balances after closing.
+func (s *WalletService) CloseWallet(ctx context.Context, userID uuid.UUID, currency, toCurrency string) (credited money.Amount, balances map[string]money.Amount, err error) {
+	balances, err = s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balances before closing wallet", "userID", userID, "error", err)
+		return 0, nil, err
+	}
+	balance, ok := balances[currency]
+	if !ok {
+		return 0, nil, ErrWalletNotFound
+	}
+	if balance < 0 {
+		return 0, nil, ErrWalletOverdrawn
+	}
+	if balance.IsPositive() && toCurrency == "" {
+		return 0, nil, ErrWalletNotEmpty
+	}
+	if s.holds != nil {
+		held, err := s.holds.HeldByUserID(ctx, userID)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get held amounts before closing wallet", "userID", userID, "error", err)
+			return 0, nil, err
+		}
+		if held[currency].IsPositive() {
+			return 0, nil, ErrWalletHasHolds
+		}
+	}
+
+	var rate float32
+	if toCurrency != "" && balance.IsPositive() {
+		if rate, _, _, err = s.pairRate(ctx, currency, toCurrency); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	txnID := uuid.New()
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		if balance, credited, err = s.writeRepo.Close(ctx, txnID, userID, currency, toCurrency, rate); err != nil {
+			return err
+		}
+		record := models.TransactionDB{
+			TransactionID: txnID,
+			UserID:        userID,
+			Operation:     models.OperationClose,
+			Currency:      currency,
+			Amount:        balance,
+		}
+		if toCurrency != "" {
+			record.ToCurrency = &toCurrency
+			record.ToAmount = &credited
+		}
+		if err := s.recordTransaction(ctx, record); err != nil {
+			return err
+		}
+		if rate == 0 {
+			return nil
+		}
+		return s.recordReceipt(ctx, models.ExchangeReceipt{
+			TransactionID: txnID,
+			Operation:     models.OperationClose,
+			FromCurrency:  currency,
+			ToCurrency:    toCurrency,
+			Amount:        balance,
+			ToAmount:      credited,
+			Rate:          rate,
+			ExecutedAt:    time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to close wallet", "userID", userID, "currency", currency, "to", toCurrency, "error", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			// The wallet was closed or funded concurrently
+			return 0, nil, ErrWalletNotFound
+		}
+		return 0, nil, err
+	}
+
+	balances, err = s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balances after closing wallet", "userID", userID, "error", err)
+		return credited, nil, err
+	}
+	balances = s.withSupportedCurrencies(ctx, balances)
+
+	closedBalance := money.Zero // The whole balance was paid out or written off
+	txn := models.Transaction{
+		TransactionID: txnID.String(),
+		Timestamp:     time.Now().Unix(),
+		Amount:        balance,
+		UserID:        userID.String(),
+		Operation:     models.OperationClose,
+		Balance:       &closedBalance,
+	}
+	if toCurrency != "" {
+		txn.ToCurrency = toCurrency
+		txn.ToBalance = balanceOf(balances, toCurrency)
+	}
+	s.publishTransaction(ctx, currency, txn)
+	// balances list the closed wallet as empty; the wallets table no longer has it, so it is dropped from the stream
+	closed := []string{currency}
+	if toCurrency != "" {
+		closed = append(closed, toCurrency)
+	}
+	s.publishBalances(ctx, txnID, userID, nil, closed...)
+
+	return credited, balances, nil
+}
+
+// CreateWallets opens empty wallets of the user in currencies, skipping the currencies
+// the user already has a wallet in. Wallets are otherwise created by the first deposit;
+// a created wallet is listed with a zero balance until then. Returns the currencies of
+// the created wallets and the balances after creating them.
+func (s *WalletService) CreateWallets(ctx context.Context, userID uuid.UUID, currencies []string) (created []string, balances map[string]money.Amount, err error) {
+	created, err = s.writeRepo.Create(ctx, userID, currencies)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to create wallets", "userID", userID, "currencies", currencies, "error", err)
+		return nil, nil, err
+	}
+	if len(created) > 0 {
+		logger.FromContext(ctx).Infow("wallets created", "userID", userID, "currencies", created)
 	}
-	s.publishTransaction(ctx, txn)
 
-	return exchangedAmount, usd, rub, eur, nil
+	balances, err = s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balances after creating wallets", "userID", userID, "error", err)
+		return created, nil, err
+	}
+	return created, s.withSupportedCurrencies(ctx, balances), nil
+}
+
+// ListTransactions returns a page of the user's transaction history, newest first,
+// and the cursor of the next page, which is empty on the last page.
+// A non-positive limit selects the default; larger limits are capped.
+func (s *WalletService) ListTransactions(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error) {
+	if s.history == nil {
+		return nil, "", nil
+	}
+
+	if cursor != "" {
+		beforeID, err := decodeTransactionCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.BeforeID = beforeID
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultTransactionPageLimit
+	}
+	if filter.Limit > MaxTransactionPageLimit {
+		filter.Limit = MaxTransactionPageLimit
+	}
+	limit := filter.Limit
+	filter.Limit++ // one extra row tells whether there is a next page
+
+	txns, err := s.history.List(ctx, filter)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list transactions", "userID", filter.UserID, "error", err)
+		return nil, "", err
+	}
+
+	var next string
+	if len(txns) > limit {
+		txns = txns[:limit]
+		next = encodeTransactionCursor(txns[limit-1].ID)
+	}
+	return txns, next, nil
+}
+
+// ListExchanges returns a page of the user's exchange history, newest first, like
+// ListTransactions. Exchanges recorded before their rate, fee and balances were kept
+// leave those fields nil.
+func (s *WalletService) ListExchanges(ctx context.Context, filter models.TransactionFilter, cursor string) ([]models.TransactionDB, string, error) {
+	filter.Operation = models.OperationExchange
+	return s.ListTransactions(ctx, filter, cursor)
+}
+
+// encodeTransactionCursor hides the row ID behind an opaque cursor.
+func encodeTransactionCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeTransactionCursor is the inverse of encodeTransactionCursor.
+func decodeTransactionCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
 }
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/segmentio/kafka-go"
+)
+
+// WithBalanceStream publishes the balance of every wallet an operation changed to writer,
+// keyed by "<userID>:<currency>" for a compacted topic, so downstream read models follow
+// the balances without querying the API. A closed wallet is published as a tombstone.
+func WithBalanceStream(writer EventPublisher) WalletOpt {
+	return func(s *WalletService) {
+		s.balances = writer
+	}
+}
+
+// BalanceUpdateBuffer is how many balance updates an event stream holds before losing the
+// oldest one.
+const BalanceUpdateBuffer = 16
+
+// BalanceNotifier delivers balance updates to the subscribers of a topic.
+type BalanceNotifier interface {
+	Publish(topic string, update models.BalanceUpdate)
+	Subscribers(topic string) int
+}
+
+// WithBalanceNotifier publishes all balances of a user after every operation that changed
+// them to notifier, with the user ID as topic, for the balance event stream.
+func WithBalanceNotifier(notifier BalanceNotifier) WalletOpt {
+	return func(s *WalletService) {
+		s.updates = notifier
+	}
+}
+
+// publishBalances publishes the balances of userID in currencies after the operation txnID.
+// balances are read from the wallets table if nil. The balance has already changed at this
+// point and the next change of the wallet supersedes the event, so failures are logged.
+func (s *WalletService) publishBalances(ctx context.Context, txnID, userID uuid.UUID, balances map[string]money.Amount, currencies ...string) {
+	notify := s.notifiesBalances(userID)
+	if s.balances == nil && !notify {
+		return
+	}
+	if balances == nil {
+		var err error
+		if balances, err = s.readRepo.GetByUserID(ctx, userID); err != nil {
+			logger.FromContext(ctx).Errorw("failed to get balances for the balance stream", "userID", userID, "transaction_id", txnID, "error", err)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	if notify {
+		s.updates.Publish(userID.String(), models.BalanceUpdate{
+			TransactionID: txnID,
+			Balances:      s.withSupportedCurrencies(ctx, maps.Clone(balances)),
+			ChangedAt:     now,
+		})
+	}
+	if s.balances == nil {
+		return
+	}
+
+	msgs := make([]kafka.Message, 0, len(currencies))
+	for _, currency := range currencies {
+		msg := kafka.Message{Key: []byte(userID.String() + ":" + currency), Headers: requestid.KafkaHeaders(ctx)}
+		if balance, ok := balances[currency]; ok {
+			data, err := json.Marshal(models.BalanceChangeEvent{
+				UserID:        userID,
+				Currency:      currency,
+				Balance:       balance,
+				TransactionID: txnID,
+				ChangedAt:     now,
+			})
+			if err != nil {
+				logger.FromContext(ctx).Errorw("failed to marshal balance change", "userID", userID, "currency", currency, "error", err)
+				return
+			}
+			msg.Value = data
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if err := s.balances.WriteMessages(ctx, msgs...); err != nil {
+		logger.FromContext(ctx).Errorw("failed to publish balance changes", "userID", userID, "transaction_id", txnID, "error", err)
+	}
+}
+
+// notifiesBalances reports whether userID has open balance streams to notify.
+func (s *WalletService) notifiesBalances(userID uuid.UUID) bool {
+	return s.updates != nil && s.updates.Subscribers(userID.String()) > 0
+}
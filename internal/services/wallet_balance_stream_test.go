@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/pubsub"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_publishBalances(t *testing.T) {
+	ctx := context.Background()
+	userID, txnID := uuid.New(), uuid.New()
+
+	t.Run("deposit publishes the new balance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		stream := NewMockEventPublisher(ctrl)
+
+		writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, money.MustParse("50"), models.USD).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("150"), models.EUR: money.Zero}, nil)
+		stream.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+			if assert.Len(t, msgs, 1) {
+				assert.Equal(t, userID.String()+":"+models.USD, string(msgs[0].Key))
+				var event models.BalanceChangeEvent
+				if err := json.Unmarshal(msgs[0].Value, &event); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, userID, event.UserID)
+				assert.Equal(t, models.USD, event.Currency)
+				assert.Equal(t, money.MustParse("150"), event.Balance)
+				assert.NotEqual(t, uuid.Nil, event.TransactionID)
+				assert.False(t, event.ChangedAt.IsZero())
+			}
+			return nil
+		})
+
+		svc := NewWalletService(writer, reader, nil, nil, nil, WithBalanceStream(stream))
+		_, err := svc.Deposit(ctx, userID, money.MustParse("50"), models.USD, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("balances read when not given, closed wallet as tombstone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		stream := NewMockEventPublisher(ctrl)
+
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("60")}, nil)
+		stream.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+			if assert.Len(t, msgs, 2) {
+				assert.Equal(t, userID.String()+":"+models.USD, string(msgs[0].Key))
+				assert.Nil(t, msgs[0].Value)
+				assert.Equal(t, userID.String()+":"+models.EUR, string(msgs[1].Key))
+				assert.Contains(t, string(msgs[1].Value), txnID.String())
+			}
+			return nil
+		})
+
+		svc := NewWalletService(nil, reader, nil, nil, nil, WithBalanceStream(stream))
+		svc.publishBalances(ctx, txnID, userID, nil, models.USD, models.EUR)
+	})
+
+	t.Run("failures are only logged", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		stream := NewMockEventPublisher(ctrl)
+
+		reader.EXPECT().GetByUserID(ctx, userID).Return(nil, errors.New("db down"))
+		stream.EXPECT().WriteMessages(ctx, gomock.Any()).Return(errors.New("broker down"))
+
+		svc := NewWalletService(nil, reader, nil, nil, nil, WithBalanceStream(stream))
+		svc.publishBalances(ctx, txnID, userID, nil, models.USD)
+		svc.publishBalances(ctx, txnID, userID, map[string]money.Amount{models.USD: money.Zero}, models.USD)
+	})
+
+	t.Run("notifier gets all balances, closed wallet still a tombstone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		stream := NewMockEventPublisher(ctrl)
+		currencies := NewMockCurrencyLister(ctrl)
+		updates := pubsub.NewBroker[models.BalanceUpdate](1)
+		sub, cancel := updates.Subscribe(userID.String())
+		defer cancel()
+
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("60")}, nil)
+		currencies.EXPECT().Codes(ctx).Return([]string{models.EUR, models.USD})
+		stream.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+			if assert.Len(t, msgs, 1) {
+				assert.Nil(t, msgs[0].Value)
+			}
+			return nil
+		})
+
+		svc := NewWalletService(nil, reader, nil, nil, nil,
+			WithBalanceStream(stream), WithBalanceNotifier(updates), WithCurrencies(currencies))
+		svc.publishBalances(ctx, txnID, userID, nil, models.USD)
+
+		update := <-sub
+		assert.Equal(t, txnID, update.TransactionID)
+		assert.Equal(t, map[string]money.Amount{models.EUR: money.MustParse("60"), models.USD: money.Zero}, update.Balances)
+		assert.False(t, update.ChangedAt.IsZero())
+	})
+
+	t.Run("disabled without a writer", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		svc.publishBalances(ctx, txnID, userID, nil, models.USD)
+	})
+}
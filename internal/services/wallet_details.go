@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+var (
+	// ErrWalletDetailsDisabled is returned when editing wallet details of a service created without WithWalletDetails.
+	ErrWalletDetailsDisabled = errors.New("wallet details disabled")
+	// ErrInvalidWalletDetails is returned when a wallet label or metadata exceeds its limits.
+	ErrInvalidWalletDetails = errors.New("invalid wallet details")
+)
+
+// WalletDetailsStore persists the client-managed labels and metadata of wallets.
+type WalletDetailsStore interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]models.WalletDetails, error)                                             // Returns the details of the wallets that have any, by currency
+	Update(ctx context.Context, userID uuid.UUID, currency string, label *string, metadata map[string]string) (models.WalletDetails, error) // Changes non-nil fields; sql.ErrNoRows if there is no wallet
+}
+
+// WithWalletDetails lets clients attach a display label and key/value metadata to their wallets.
+func WithWalletDetails(store WalletDetailsStore) WalletOpt {
+	return func(s *WalletService) {
+		s.details = store
+	}
+}
+
+// GetWalletDetails returns the labels and metadata of the user's wallets by currency.
+// Wallets without either are left out; without WithWalletDetails, the result is empty.
+func (s *WalletService) GetWalletDetails(ctx context.Context, userID uuid.UUID) (map[string]models.WalletDetails, error) {
+	if s.details == nil {
+		return map[string]models.WalletDetails{}, nil
+	}
+	details, err := s.details.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get wallet details", "userID", userID, "error", err)
+		return nil, err
+	}
+	return details, nil
+}
+
+// UpdateWalletDetails changes the label and metadata of the user's wallet in a currency.
+// A nil label or metadata is kept, an empty label removes the label and metadata replaces
+// the stored map as a whole.
+func (s *WalletService) UpdateWalletDetails(
+	ctx context.Context,
+	userID uuid.UUID,
+	currency string,
+	label *string,
+	metadata map[string]string,
+) (models.WalletDetails, error) {
+	if s.details == nil {
+		return models.WalletDetails{}, ErrWalletDetailsDisabled
+	}
+	if !validWalletDetails(label, metadata) {
+		return models.WalletDetails{}, ErrInvalidWalletDetails
+	}
+
+	details, err := s.details.Update(ctx, userID, currency, label, metadata)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WalletDetails{}, ErrWalletNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to update wallet details", "userID", userID, "currency", currency, "error", err)
+		return models.WalletDetails{}, err
+	}
+	return details, nil
+}
+
+func validWalletDetails(label *string, metadata map[string]string) bool {
+	if label != nil && utf8.RuneCountInString(*label) > models.MaxWalletLabelLength {
+		return false
+	}
+	if len(metadata) > models.MaxWalletMetadataKeys {
+		return false
+	}
+	for key, value := range metadata {
+		if key == "" ||
+			utf8.RuneCountInString(key) > models.MaxWalletMetadataKeyLength ||
+			utf8.RuneCountInString(value) > models.MaxWalletMetadataValueLength {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_details.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockWalletDetailsStore is a mock of WalletDetailsStore interface.
+type MockWalletDetailsStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletDetailsStoreMockRecorder
+}
+
+// MockWalletDetailsStoreMockRecorder is the mock recorder for MockWalletDetailsStore.
+type MockWalletDetailsStoreMockRecorder struct {
+	mock *MockWalletDetailsStore
+}
+
+// NewMockWalletDetailsStore creates a new mock instance.
+func NewMockWalletDetailsStore(ctrl *gomock.Controller) *MockWalletDetailsStore {
+	mock := &MockWalletDetailsStore{ctrl: ctrl}
+	mock.recorder = &MockWalletDetailsStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletDetailsStore) EXPECT() *MockWalletDetailsStoreMockRecorder {
+	return m.recorder
+}
+
+// GetByUserID mocks base method.
+func (m *MockWalletDetailsStore) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]models.WalletDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].(map[string]models.WalletDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockWalletDetailsStoreMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWalletDetailsStore)(nil).GetByUserID), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockWalletDetailsStore) Update(ctx context.Context, userID uuid.UUID, currency string, label *string, metadata map[string]string) (models.WalletDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, currency, label, metadata)
+	ret0, _ := ret[0].(models.WalletDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWalletDetailsStoreMockRecorder) Update(ctx, userID, currency, label, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWalletDetailsStore)(nil).Update), ctx, userID, currency, label, metadata)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_GetWalletDetails(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWalletDetailsStore(ctrl)
+
+		expected := map[string]models.WalletDetails{models.USD: {Label: "travel fund"}}
+		store.EXPECT().GetByUserID(ctx, userID).Return(expected, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithWalletDetails(store))
+		details, err := svc.GetWalletDetails(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, details)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		details, err := svc.GetWalletDetails(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, details)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWalletDetailsStore(ctrl)
+		store.EXPECT().GetByUserID(ctx, userID).Return(nil, errors.New("db error"))
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithWalletDetails(store))
+		_, err := svc.GetWalletDetails(ctx, userID)
+		assert.Error(t, err)
+	})
+}
+
+func TestWalletService_UpdateWalletDetails(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	label := "travel fund"
+	metadata := map[string]string{"trip": "japan"}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWalletDetailsStore(ctrl)
+
+		expected := models.WalletDetails{Label: label, Metadata: metadata}
+		store.EXPECT().Update(ctx, userID, models.USD, &label, metadata).Return(expected, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithWalletDetails(store))
+		details, err := svc.UpdateWalletDetails(ctx, userID, models.USD, &label, metadata)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, details)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWalletDetailsStore(ctrl)
+		store.EXPECT().Update(ctx, userID, models.EUR, &label, nil).Return(models.WalletDetails{}, sql.ErrNoRows)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithWalletDetails(store))
+		_, err := svc.UpdateWalletDetails(ctx, userID, models.EUR, &label, nil)
+		assert.ErrorIs(t, err, ErrWalletNotFound)
+	})
+
+	t.Run("invalid details", func(t *testing.T) {
+		long := strings.Repeat("a", models.MaxWalletLabelLength+1)
+		tooMany := make(map[string]string, models.MaxWalletMetadataKeys+1)
+		for i := range models.MaxWalletMetadataKeys + 1 {
+			tooMany[strings.Repeat("k", i+1)] = "v"
+		}
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithWalletDetails(NewMockWalletDetailsStore(gomock.NewController(t))))
+		for _, tc := range []struct {
+			label    *string
+			metadata map[string]string
+		}{
+			{label: &long},
+			{metadata: tooMany},
+			{metadata: map[string]string{"": "v"}},
+			{metadata: map[string]string{strings.Repeat("k", models.MaxWalletMetadataKeyLength+1): "v"}},
+			{metadata: map[string]string{"k": strings.Repeat("v", models.MaxWalletMetadataValueLength+1)}},
+		} {
+			_, err := svc.UpdateWalletDetails(ctx, userID, models.USD, tc.label, tc.metadata)
+			assert.ErrorIs(t, err, ErrInvalidWalletDetails)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		_, err := svc.UpdateWalletDetails(ctx, userID, models.USD, &label, nil)
+		assert.ErrorIs(t, err, ErrWalletDetailsDisabled)
+	})
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+var (
+	// ErrHoldsDisabled is returned by hold operations of a service created without WithHolds.
+	ErrHoldsDisabled = errors.New("holds disabled")
+	// ErrHoldNotFound is returned when the user has no hold with the requested ID.
+	ErrHoldNotFound = errors.New("hold not found")
+	// ErrHoldNotPending is returned when capturing or releasing a hold that is already captured or released.
+	ErrHoldNotPending = errors.New("hold not pending")
+	// ErrWalletHasHolds is returned when closing a wallet with pending holds.
+	ErrWalletHasHolds = errors.New("wallet has pending holds")
+)
+
+// WalletHoldStore persists holds together with the held amounts of the wallets.
+type WalletHoldStore interface {
+	Create(ctx context.Context, hold models.WalletHoldDB) (models.WalletHoldDB, error)                 // Places a pending hold; sql.ErrNoRows if funds are insufficient
+	Capture(ctx context.Context, transactionID, userID, holdID uuid.UUID) (models.WalletHoldDB, error) // Withdraws a pending hold; sql.ErrNoRows if there is none
+	Release(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error)                // Cancels a pending hold; sql.ErrNoRows if there is none
+	Get(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error)                    // Returns a hold of the user
+	HeldByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error)               // Returns the held amounts by currency
+}
+
+// WithHolds enables two-phase withdrawals: funds are first held, reducing the available
+// but not the total balance, and later captured or released.
+func WithHolds(store WalletHoldStore) WalletOpt {
+	return func(s *WalletService) {
+		s.holds = store
+	}
+}
+
+// CreateHold reserves amount in the user's currency wallet. The hold counts against the
+// user's limits right away; the usage is given back if the hold is released.
+func (s *WalletService) CreateHold(ctx context.Context, userID uuid.UUID, amount money.Amount, currency string) (models.WalletHoldDB, error) {
+	if s.holds == nil {
+		return models.WalletHoldDB{}, ErrHoldsDisabled
+	}
+
+	usageID, err := s.reserveLimit(ctx, userID, currency, amount)
+	if err != nil {
+		return models.WalletHoldDB{}, err
+	}
+
+	hold := models.WalletHoldDB{
+		HoldID:   uuid.New(),
+		UserID:   userID,
+		Currency: currency,
+		Amount:   amount,
+	}
+	if s.limiter != nil {
+		hold.LimitUsageID = &usageID
+	}
+
+	hold, err = s.holds.Create(ctx, hold)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to create hold", "userID", userID, "amount", amount, "currency", currency, "error", err)
+		s.releaseLimit(ctx, usageID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WalletHoldDB{}, ErrInsufficientFunds
+		}
+		return models.WalletHoldDB{}, err
+	}
+
+	logger.FromContext(ctx).Infow("hold created", "hold_id", hold.HoldID, "userID", userID, "amount", amount, "currency", currency)
+	return hold, nil
+}
+
+// CaptureHold withdraws the held funds and publishes the withdrawal.
+func (s *WalletService) CaptureHold(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	if s.holds == nil {
+		return models.WalletHoldDB{}, ErrHoldsDisabled
+	}
+
+	txnID := uuid.New()
+	var hold models.WalletHoldDB
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		if hold, err = s.holds.Capture(ctx, txnID, userID, holdID); err != nil {
+			return err
+		}
+		return s.recordTransaction(ctx, models.TransactionDB{
+			TransactionID: txnID,
+			UserID:        userID,
+			Operation:     models.OperationWithdraw,
+			Currency:      hold.Currency,
+			Amount:        hold.Amount,
+		})
+	})
+	if err != nil {
+		return models.WalletHoldDB{}, s.holdError(ctx, userID, holdID, err)
+	}
+
+	balances := s.eventBalances(ctx, userID, nil)
+	txn := models.Transaction{
+		TransactionID: txnID.String(),
+		Timestamp:     time.Now().Unix(),
+		Amount:        hold.Amount,
+		UserID:        userID.String(),
+		Operation:     "withdraw",
+		Balance:       balanceOf(balances, hold.Currency),
+	}
+	s.publishTransaction(ctx, hold.Currency, txn)
+	s.publishBalances(ctx, txnID, userID, balances, hold.Currency)
+
+	return hold, nil
+}
+
+// ReleaseHold cancels the hold, returning its funds to the available balance.
+func (s *WalletService) ReleaseHold(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	if s.holds == nil {
+		return models.WalletHoldDB{}, ErrHoldsDisabled
+	}
+
+	hold, err := s.holds.Release(ctx, userID, holdID)
+	if err != nil {
+		return models.WalletHoldDB{}, s.holdError(ctx, userID, holdID, err)
+	}
+
+	if hold.LimitUsageID != nil {
+		s.releaseLimit(ctx, *hold.LimitUsageID)
+	}
+
+	logger.FromContext(ctx).Infow("hold released", "hold_id", holdID, "userID", userID)
+	return hold, nil
+}
+
+// holdError tells apart missing holds from finished ones after a capture or release failed.
+func (s *WalletService) holdError(ctx context.Context, userID, holdID uuid.UUID, err error) error {
+	if !errors.Is(err, sql.ErrNoRows) {
+		logger.FromContext(ctx).Errorw("failed to finish hold", "hold_id", holdID, "userID", userID, "error", err)
+		return err
+	}
+
+	if _, err := s.holds.Get(ctx, userID, holdID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrHoldNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to get hold", "hold_id", holdID, "userID", userID, "error", err)
+		return err
+	}
+	return ErrHoldNotPending
+}
+
+// GetUserAvailableBalance returns the user's balance not held by pending holds nor saved
+// in pots excluded from spending by currency.
+// It is read from the wallets table even if GetUserBalance uses another read model.
+func (s *WalletService) GetUserAvailableBalance(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	balances, err := s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get user balances", "userID", userID, "error", err)
+		return nil, err
+	}
+
+	var held map[string]money.Amount
+	if s.holds != nil {
+		if held, err = s.holds.HeldByUserID(ctx, userID); err != nil {
+			logger.FromContext(ctx).Errorw("failed to get held amounts", "userID", userID, "error", err)
+			return nil, err
+		}
+	}
+
+	var saved map[string]money.Amount
+	if s.pots != nil {
+		if saved, err = s.pots.SavedByUserID(ctx, userID); err != nil {
+			logger.FromContext(ctx).Errorw("failed to get saved amounts", "userID", userID, "error", err)
+			return nil, err
+		}
+	}
+
+	available := make(map[string]money.Amount, len(balances))
+	for currency, balance := range balances {
+		available[currency] = balance - held[currency] - saved[currency]
+	}
+	return s.withSupportedCurrencies(ctx, available), nil
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_hold.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockWalletHoldStore is a mock of WalletHoldStore interface.
+type MockWalletHoldStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletHoldStoreMockRecorder
+}
+
+// MockWalletHoldStoreMockRecorder is the mock recorder for MockWalletHoldStore.
+type MockWalletHoldStoreMockRecorder struct {
+	mock *MockWalletHoldStore
+}
+
+// NewMockWalletHoldStore creates a new mock instance.
+func NewMockWalletHoldStore(ctrl *gomock.Controller) *MockWalletHoldStore {
+	mock := &MockWalletHoldStore{ctrl: ctrl}
+	mock.recorder = &MockWalletHoldStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletHoldStore) EXPECT() *MockWalletHoldStoreMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockWalletHoldStore) Capture(ctx context.Context, transactionID, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, transactionID, userID, holdID)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockWalletHoldStoreMockRecorder) Capture(ctx, transactionID, userID, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockWalletHoldStore)(nil).Capture), ctx, transactionID, userID, holdID)
+}
+
+// Create mocks base method.
+func (m *MockWalletHoldStore) Create(ctx context.Context, hold models.WalletHoldDB) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, hold)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletHoldStoreMockRecorder) Create(ctx, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletHoldStore)(nil).Create), ctx, hold)
+}
+
+// Get mocks base method.
+func (m *MockWalletHoldStore) Get(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, holdID)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWalletHoldStoreMockRecorder) Get(ctx, userID, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletHoldStore)(nil).Get), ctx, userID, holdID)
+}
+
+// HeldByUserID mocks base method.
+func (m *MockWalletHoldStore) HeldByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeldByUserID", ctx, userID)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeldByUserID indicates an expected call of HeldByUserID.
+func (mr *MockWalletHoldStoreMockRecorder) HeldByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeldByUserID", reflect.TypeOf((*MockWalletHoldStore)(nil).HeldByUserID), ctx, userID)
+}
+
+// Release mocks base method.
+func (m *MockWalletHoldStore) Release(ctx context.Context, userID, holdID uuid.UUID) (models.WalletHoldDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, userID, holdID)
+	ret0, _ := ret[0].(models.WalletHoldDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockWalletHoldStoreMockRecorder) Release(ctx, userID, holdID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockWalletHoldStore)(nil).Release), ctx, userID, holdID)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_CreateHold(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(3), "", nil)
+		holds.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hold models.WalletHoldDB) (models.WalletHoldDB, error) {
+			assert.NotEqual(t, uuid.Nil, hold.HoldID)
+			assert.Equal(t, userID, hold.UserID)
+			assert.Equal(t, amount, hold.Amount)
+			if assert.NotNil(t, hold.LimitUsageID) {
+				assert.Equal(t, int64(3), *hold.LimitUsageID)
+			}
+			hold.Status = models.HoldStatusPending
+			return hold, nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds), WithSpendingLimits(limiter))
+		hold, err := svc.CreateHold(ctx, userID, amount, models.USD)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusPending, hold.Status)
+	})
+
+	t.Run("insufficient funds releases the usage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(4), "", nil)
+		holds.EXPECT().Create(ctx, gomock.Any()).Return(models.WalletHoldDB{}, sql.ErrNoRows)
+		limiter.EXPECT().Release(ctx, int64(4)).Return(nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds), WithSpendingLimits(limiter))
+		_, err := svc.CreateHold(ctx, userID, amount, models.USD)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		limiter := NewMockSpendingLimiter(ctrl)
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(0), models.LimitPeriodDaily, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(NewMockWalletHoldStore(ctrl)), WithSpendingLimits(limiter))
+		_, err := svc.CreateHold(ctx, userID, amount, models.USD)
+		assert.ErrorIs(t, err, ErrDailyLimitExceeded)
+	})
+
+	t.Run("without limits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		holds.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hold models.WalletHoldDB) (models.WalletHoldDB, error) {
+			assert.Nil(t, hold.LimitUsageID)
+			return hold, nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds))
+		_, err := svc.CreateHold(ctx, userID, amount, models.USD)
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		_, err := svc.CreateHold(ctx, userID, amount, models.USD)
+		assert.ErrorIs(t, err, ErrHoldsDisabled)
+	})
+}
+
+func TestWalletService_CaptureHold(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	holdID := uuid.New()
+
+	t.Run("records the withdrawal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		history := NewMockTransactionStore(ctrl)
+
+		holds.EXPECT().Capture(ctx, gomock.Any(), userID, holdID).Return(models.WalletHoldDB{
+			HoldID: holdID, UserID: userID, Currency: models.EUR, Amount: money.MustParse("40"), Status: models.HoldStatusCaptured,
+		}, nil)
+		history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			assert.Equal(t, models.OperationWithdraw, txn.Operation)
+			assert.Equal(t, models.EUR, txn.Currency)
+			assert.Equal(t, money.MustParse("40"), txn.Amount)
+			return nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds), WithTransactionHistory(history, directTx{}))
+		hold, err := svc.CaptureHold(ctx, userID, holdID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusCaptured, hold.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		holds.EXPECT().Capture(ctx, gomock.Any(), userID, holdID).Return(models.WalletHoldDB{}, sql.ErrNoRows)
+		holds.EXPECT().Get(ctx, userID, holdID).Return(models.WalletHoldDB{}, sql.ErrNoRows)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds))
+		_, err := svc.CaptureHold(ctx, userID, holdID)
+		assert.ErrorIs(t, err, ErrHoldNotFound)
+	})
+
+	t.Run("already released", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		holds.EXPECT().Capture(ctx, gomock.Any(), userID, holdID).Return(models.WalletHoldDB{}, sql.ErrNoRows)
+		holds.EXPECT().Get(ctx, userID, holdID).Return(models.WalletHoldDB{Status: models.HoldStatusReleased}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds))
+		_, err := svc.CaptureHold(ctx, userID, holdID)
+		assert.ErrorIs(t, err, ErrHoldNotPending)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		holds.EXPECT().Capture(ctx, gomock.Any(), userID, holdID).Return(models.WalletHoldDB{}, errors.New("db error"))
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds))
+		_, err := svc.CaptureHold(ctx, userID, holdID)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestWalletService_ReleaseHold(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	holdID := uuid.New()
+
+	t.Run("gives back the limit usage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		usageID := int64(9)
+		holds.EXPECT().Release(ctx, userID, holdID).Return(models.WalletHoldDB{
+			HoldID: holdID, Status: models.HoldStatusReleased, LimitUsageID: &usageID,
+		}, nil)
+		limiter.EXPECT().Release(ctx, usageID).Return(nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds), WithSpendingLimits(limiter))
+		hold, err := svc.ReleaseHold(ctx, userID, holdID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.HoldStatusReleased, hold.Status)
+	})
+
+	t.Run("already captured", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		holds := NewMockWalletHoldStore(ctrl)
+		holds.EXPECT().Release(ctx, userID, holdID).Return(models.WalletHoldDB{}, sql.ErrNoRows)
+		holds.EXPECT().Get(ctx, userID, holdID).Return(models.WalletHoldDB{Status: models.HoldStatusCaptured}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithHolds(holds))
+		_, err := svc.ReleaseHold(ctx, userID, holdID)
+		assert.ErrorIs(t, err, ErrHoldNotPending)
+	})
+}
+
+func TestWalletService_GetUserAvailableBalance(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	reader := NewMockWalletReader(ctrl)
+	holds := NewMockWalletHoldStore(ctrl)
+
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+		models.USD: money.MustParse("100"), models.EUR: money.MustParse("50"),
+	}, nil)
+	holds.EXPECT().HeldByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("30")}, nil)
+
+	svc := NewWalletService(nil, reader, nil, nil, nil, WithHolds(holds))
+	available, err := svc.GetUserAvailableBalance(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]money.Amount{
+		models.USD: money.MustParse("70"),
+		models.EUR: money.MustParse("50"),
+	}, available)
+}
+
+func TestWalletService_CloseWallet_PendingHolds(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	reader := NewMockWalletReader(ctrl)
+	holds := NewMockWalletHoldStore(ctrl)
+
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("100")}, nil)
+	holds.EXPECT().HeldByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("10")}, nil)
+
+	svc := NewWalletService(nil, reader, nil, nil, nil, WithHolds(holds))
+	_, _, err := svc.CloseWallet(ctx, userID, models.USD, models.EUR)
+	assert.ErrorIs(t, err, ErrWalletHasHolds)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// ErrInvalidLimit is returned when a limit is negative or the daily limit is above the monthly one.
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// WalletLimitStore reads and changes the users' withdrawal and exchange limits.
+type WalletLimitStore interface {
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletLimitDB, error) // Returns the user's limits with their usage
+	Set(ctx context.Context, limit models.WalletLimitDB) error                          // Creates, replaces or, without limits, removes a limit
+	PurgeUsage(ctx context.Context, olderThan time.Duration) (int64, error)             // Deletes usage entries older than olderThan
+	// Sets the overdraft limit of the user's wallet; sql.ErrNoRows if there is no such wallet
+	SetOverdraft(ctx context.Context, userID uuid.UUID, currency string, limit money.Amount) error
+}
+
+// WalletLimitService lets admins inspect and adjust the users' withdrawal and exchange limits.
+type WalletLimitService struct {
+	store WalletLimitStore
+	users UserByIDReader
+	audit AuditWriter
+}
+
+// NewWalletLimitService creates a new WalletLimitService.
+func NewWalletLimitService(store WalletLimitStore, users UserByIDReader, audit AuditWriter) *WalletLimitService {
+	return &WalletLimitService{
+		store: store,
+		users: users,
+		audit: audit,
+	}
+}
+
+// GetLimits returns the user's limits with the usage within their windows.
+func (s *WalletLimitService) GetLimits(ctx context.Context, userID uuid.UUID) ([]models.WalletLimitDB, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	limits, err := s.store.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list wallet limits", "userID", userID, "error", err)
+		return nil, err
+	}
+	return limits, nil
+}
+
+// SetLimit lets an admin set the user's limits in a currency. Nil limits are lifted.
+// The change is recorded in the audit trail.
+func (s *WalletLimitService) SetLimit(ctx context.Context, adminID uuid.UUID, limit models.WalletLimitDB) error {
+	if (limit.DailyLimit != nil && *limit.DailyLimit < 0) ||
+		(limit.MonthlyLimit != nil && *limit.MonthlyLimit < 0) ||
+		(limit.DailyLimit != nil && limit.MonthlyLimit != nil && *limit.DailyLimit > *limit.MonthlyLimit) {
+		return ErrInvalidLimit
+	}
+
+	if err := s.checkUser(ctx, limit.UserID); err != nil {
+		return err
+	}
+
+	if err := s.store.Set(ctx, limit); err != nil {
+		logger.FromContext(ctx).Errorw("failed to set wallet limit", "adminID", adminID, "userID", limit.UserID, "currency", limit.Currency, "error", err)
+		return err
+	}
+
+	details := map[string]any{
+		"currency":      limit.Currency,
+		"daily_limit":   limit.DailyLimit,
+		"monthly_limit": limit.MonthlyLimit,
+	}
+	if err := s.audit.Save(ctx, adminID, models.AuditActionLimitsSet, &limit.UserID, details); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit wallet limit change", "adminID", adminID, "userID", limit.UserID, "error", err)
+		return err
+	}
+
+	logger.FromContext(ctx).Infow("wallet limit changed", "adminID", adminID, "userID", limit.UserID, "currency", limit.Currency,
+		"daily", limit.DailyLimit, "monthly", limit.MonthlyLimit)
+	return nil
+}
+
+// SetOverdraft lets an admin set the overdraft limit of the user's wallet in a currency,
+// so withdrawals may take its balance down to -limit. A zero limit removes the overdraft;
+// a wallet already overdrawn stays so until it is topped up. The change is audited.
+func (s *WalletLimitService) SetOverdraft(ctx context.Context, adminID, userID uuid.UUID, currency string, limit money.Amount) error {
+	if limit < 0 {
+		return ErrInvalidLimit
+	}
+
+	if err := s.checkUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.store.SetOverdraft(ctx, userID, currency, limit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWalletNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to set overdraft limit", "adminID", adminID, "userID", userID, "currency", currency, "error", err)
+		return err
+	}
+
+	details := map[string]any{
+		"currency":        currency,
+		"overdraft_limit": limit,
+	}
+	if err := s.audit.Save(ctx, adminID, models.AuditActionOverdraftSet, &userID, details); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit overdraft limit change", "adminID", adminID, "userID", userID, "error", err)
+		return err
+	}
+
+	logger.FromContext(ctx).Infow("overdraft limit changed", "adminID", adminID, "userID", userID, "currency", currency, "limit", limit)
+	return nil
+}
+
+// PurgeUsage deletes usage entries that have fallen out of every limit window.
+func (s *WalletLimitService) PurgeUsage(ctx context.Context) error {
+	deleted, err := s.store.PurgeUsage(ctx, models.LimitMonthlyWindow)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to purge wallet limit usage", "error", err)
+		return err
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).Infow("wallet limit usage purged", "deleted", deleted)
+	}
+	return nil
+}
+
+// checkUser returns ErrUserDoesNotExist if there is no such user.
+func (s *WalletLimitService) checkUser(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserDoesNotExist
+		}
+		logger.FromContext(ctx).Errorw("failed to get limited user", "userID", userID, "error", err)
+		return err
+	}
+	return nil
+}
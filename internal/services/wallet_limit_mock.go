@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_limit.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockWalletLimitStore is a mock of WalletLimitStore interface.
+type MockWalletLimitStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletLimitStoreMockRecorder
+}
+
+// MockWalletLimitStoreMockRecorder is the mock recorder for MockWalletLimitStore.
+type MockWalletLimitStoreMockRecorder struct {
+	mock *MockWalletLimitStore
+}
+
+// NewMockWalletLimitStore creates a new mock instance.
+func NewMockWalletLimitStore(ctrl *gomock.Controller) *MockWalletLimitStore {
+	mock := &MockWalletLimitStore{ctrl: ctrl}
+	mock.recorder = &MockWalletLimitStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletLimitStore) EXPECT() *MockWalletLimitStoreMockRecorder {
+	return m.recorder
+}
+
+// ListByUserID mocks base method.
+func (m *MockWalletLimitStore) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletLimitDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.WalletLimitDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockWalletLimitStoreMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockWalletLimitStore)(nil).ListByUserID), ctx, userID)
+}
+
+// PurgeUsage mocks base method.
+func (m *MockWalletLimitStore) PurgeUsage(ctx context.Context, olderThan time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUsage", ctx, olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUsage indicates an expected call of PurgeUsage.
+func (mr *MockWalletLimitStoreMockRecorder) PurgeUsage(ctx, olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUsage", reflect.TypeOf((*MockWalletLimitStore)(nil).PurgeUsage), ctx, olderThan)
+}
+
+// Set mocks base method.
+func (m *MockWalletLimitStore) Set(ctx context.Context, limit models.WalletLimitDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockWalletLimitStoreMockRecorder) Set(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWalletLimitStore)(nil).Set), ctx, limit)
+}
+
+// SetOverdraft mocks base method.
+func (m *MockWalletLimitStore) SetOverdraft(ctx context.Context, userID uuid.UUID, currency string, limit money.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraft", ctx, userID, currency, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverdraft indicates an expected call of SetOverdraft.
+func (mr *MockWalletLimitStoreMockRecorder) SetOverdraft(ctx, userID, currency, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraft", reflect.TypeOf((*MockWalletLimitStore)(nil).SetOverdraft), ctx, userID, currency, limit)
+}
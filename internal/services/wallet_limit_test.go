@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type walletLimitMocks struct {
+	store *MockWalletLimitStore
+	users *MockUserByIDReader
+	audit *MockAuditWriter
+}
+
+func newWalletLimitService(t *testing.T) (*WalletLimitService, walletLimitMocks) {
+	ctrl := gomock.NewController(t)
+	m := walletLimitMocks{
+		store: NewMockWalletLimitStore(ctrl),
+		users: NewMockUserByIDReader(ctrl),
+		audit: NewMockAuditWriter(ctrl),
+	}
+	return NewWalletLimitService(m.store, m.users, m.audit), m
+}
+
+func TestWalletLimitService_GetLimits(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("returns limits", func(t *testing.T) {
+		svc, m := newWalletLimitService(t)
+		daily := money.MustParse("100")
+		want := []models.WalletLimitDB{{UserID: userID, Currency: models.USD, DailyLimit: &daily}}
+
+		m.users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID}, nil)
+		m.store.EXPECT().ListByUserID(ctx, userID).Return(want, nil)
+
+		got, err := svc.GetLimits(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc, m := newWalletLimitService(t)
+		m.users.EXPECT().GetByID(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.GetLimits(ctx, userID)
+		assert.ErrorIs(t, err, ErrUserDoesNotExist)
+	})
+}
+
+func TestWalletLimitService_SetLimit(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	amount := func(s string) *money.Amount {
+		a := money.MustParse(s)
+		return &a
+	}
+
+	tests := []struct {
+		name    string
+		limit   models.WalletLimitDB
+		setup   func(m walletLimitMocks, limit models.WalletLimitDB)
+		wantErr error
+	}{
+		{
+			name:  "set",
+			limit: models.WalletLimitDB{UserID: userID, Currency: models.USD, DailyLimit: amount("100"), MonthlyLimit: amount("1000")},
+			setup: func(m walletLimitMocks, limit models.WalletLimitDB) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID}, nil)
+				m.store.EXPECT().Set(ctx, limit).Return(nil)
+				m.audit.EXPECT().Save(ctx, adminID, models.AuditActionLimitsSet, &userID, map[string]any{
+					"currency":      models.USD,
+					"daily_limit":   limit.DailyLimit,
+					"monthly_limit": limit.MonthlyLimit,
+				}).Return(nil)
+			},
+		},
+		{
+			name:  "lift",
+			limit: models.WalletLimitDB{UserID: userID, Currency: models.USD},
+			setup: func(m walletLimitMocks, limit models.WalletLimitDB) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID}, nil)
+				m.store.EXPECT().Set(ctx, limit).Return(nil)
+				m.audit.EXPECT().Save(ctx, adminID, models.AuditActionLimitsSet, &userID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "negative limit",
+			limit:   models.WalletLimitDB{UserID: userID, Currency: models.USD, DailyLimit: amount("-1")},
+			setup:   func(m walletLimitMocks, limit models.WalletLimitDB) {},
+			wantErr: ErrInvalidLimit,
+		},
+		{
+			name:    "daily above monthly",
+			limit:   models.WalletLimitDB{UserID: userID, Currency: models.USD, DailyLimit: amount("200"), MonthlyLimit: amount("100")},
+			setup:   func(m walletLimitMocks, limit models.WalletLimitDB) {},
+			wantErr: ErrInvalidLimit,
+		},
+		{
+			name:  "user not found",
+			limit: models.WalletLimitDB{UserID: userID, Currency: models.USD, DailyLimit: amount("100")},
+			setup: func(m walletLimitMocks, limit models.WalletLimitDB) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(nil, sql.ErrNoRows)
+			},
+			wantErr: ErrUserDoesNotExist,
+		},
+		{
+			name:  "store error",
+			limit: models.WalletLimitDB{UserID: userID, Currency: models.USD, DailyLimit: amount("100")},
+			setup: func(m walletLimitMocks, limit models.WalletLimitDB) {
+				m.users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID}, nil)
+				m.store.EXPECT().Set(ctx, limit).Return(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newWalletLimitService(t)
+			tt.setup(m, tt.limit)
+
+			err := svc.SetLimit(ctx, adminID, tt.limit)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWalletLimitService_SetOverdraft(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	userID := uuid.New()
+	limit := money.MustParse("500")
+
+	t.Run("set", func(t *testing.T) {
+		svc, m := newWalletLimitService(t)
+		m.users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID}, nil)
+		m.store.EXPECT().SetOverdraft(ctx, userID, models.USD, limit).Return(nil)
+		m.audit.EXPECT().Save(ctx, adminID, models.AuditActionOverdraftSet, &userID, map[string]any{
+			"currency":        models.USD,
+			"overdraft_limit": limit,
+		}).Return(nil)
+
+		assert.NoError(t, svc.SetOverdraft(ctx, adminID, userID, models.USD, limit))
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		svc, _ := newWalletLimitService(t)
+		assert.ErrorIs(t, svc.SetOverdraft(ctx, adminID, userID, models.USD, money.MustParse("-1")), ErrInvalidLimit)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		svc, m := newWalletLimitService(t)
+		m.users.EXPECT().GetByID(ctx, userID).Return(nil, sql.ErrNoRows)
+
+		assert.ErrorIs(t, svc.SetOverdraft(ctx, adminID, userID, models.USD, limit), ErrUserDoesNotExist)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		svc, m := newWalletLimitService(t)
+		m.users.EXPECT().GetByID(ctx, userID).Return(&models.UserDB{UserID: userID}, nil)
+		m.store.EXPECT().SetOverdraft(ctx, userID, models.EUR, limit).Return(sql.ErrNoRows)
+
+		assert.ErrorIs(t, svc.SetOverdraft(ctx, adminID, userID, models.EUR, limit), ErrWalletNotFound)
+	})
+}
+
+func TestWalletLimitService_PurgeUsage(t *testing.T) {
+	ctx := context.Background()
+
+	svc, m := newWalletLimitService(t)
+	m.store.EXPECT().PurgeUsage(ctx, models.LimitMonthlyWindow).Return(int64(3), nil)
+	assert.NoError(t, svc.PurgeUsage(ctx))
+
+	m.store.EXPECT().PurgeUsage(ctx, models.LimitMonthlyWindow).Return(int64(0), errors.New("db error"))
+	assert.Error(t, svc.PurgeUsage(ctx))
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// WalletLocker locks wallet rows in the database transaction of the request.
+type WalletLocker interface {
+	Lock(ctx context.Context, wallets []models.WalletKey) error // Locks the wallets until the transaction ends, always in the same order
+}
+
+// WithWalletLocks locks the wallets a transfer or an exchange reads and changes before it
+// changes them, so concurrent operations on the same wallets run one after another. The
+// locks are held by the transaction of WithTransactionHistory and are not taken without it.
+func WithWalletLocks(locker WalletLocker) WalletOpt {
+	return func(s *WalletService) {
+		s.locker = locker
+	}
+}
+
+// lockWallets locks the given wallets, if locking is enabled. Call it from inTx before the
+// first change, so the locks are held until the transaction ends.
+func (s *WalletService) lockWallets(ctx context.Context, wallets ...models.WalletKey) error {
+	if s.locker == nil || s.tx == nil {
+		return nil
+	}
+	return s.locker.Lock(ctx, wallets)
+}
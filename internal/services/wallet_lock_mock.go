@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_lock.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockWalletLocker is a mock of WalletLocker interface.
+type MockWalletLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletLockerMockRecorder
+}
+
+// MockWalletLockerMockRecorder is the mock recorder for MockWalletLocker.
+type MockWalletLockerMockRecorder struct {
+	mock *MockWalletLocker
+}
+
+// NewMockWalletLocker creates a new mock instance.
+func NewMockWalletLocker(ctrl *gomock.Controller) *MockWalletLocker {
+	mock := &MockWalletLocker{ctrl: ctrl}
+	mock.recorder = &MockWalletLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletLocker) EXPECT() *MockWalletLockerMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method.
+func (m *MockWalletLocker) Lock(ctx context.Context, wallets []models.WalletKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, wallets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockWalletLockerMockRecorder) Lock(ctx, wallets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockWalletLocker)(nil).Lock), ctx, wallets)
+}
@@ -7,9 +7,12 @@ package services
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
 	kafka "github.com/segmentio/kafka-go"
 )
 
@@ -36,32 +39,77 @@ func (m *MockWalletWriter) EXPECT() *MockWalletWriterMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockWalletWriter) Close(ctx context.Context, transactionID, userID uuid.UUID, currency, toCurrency string, rate float32) (money.Amount, money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, transactionID, userID, currency, toCurrency, rate)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(money.Amount)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockWalletWriterMockRecorder) Close(ctx, transactionID, userID, currency, toCurrency, rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockWalletWriter)(nil).Close), ctx, transactionID, userID, currency, toCurrency, rate)
+}
+
+// Create mocks base method.
+func (m *MockWalletWriter) Create(ctx context.Context, userID uuid.UUID, currencies []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, currencies)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletWriterMockRecorder) Create(ctx, userID, currencies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletWriter)(nil).Create), ctx, userID, currencies)
+}
+
 // SaveDeposit mocks base method.
-func (m *MockWalletWriter) SaveDeposit(ctx context.Context, userID uuid.UUID, amount float64, currency string) error {
+func (m *MockWalletWriter) SaveDeposit(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveDeposit", ctx, userID, amount, currency)
+	ret := m.ctrl.Call(m, "SaveDeposit", ctx, transactionID, userID, amount, currency)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveDeposit indicates an expected call of SaveDeposit.
-func (mr *MockWalletWriterMockRecorder) SaveDeposit(ctx, userID, amount, currency interface{}) *gomock.Call {
+func (mr *MockWalletWriterMockRecorder) SaveDeposit(ctx, transactionID, userID, amount, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeposit", reflect.TypeOf((*MockWalletWriter)(nil).SaveDeposit), ctx, userID, amount, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeposit", reflect.TypeOf((*MockWalletWriter)(nil).SaveDeposit), ctx, transactionID, userID, amount, currency)
+}
+
+// SaveExchange mocks base method.
+func (m *MockWalletWriter) SaveExchange(ctx context.Context, transactionID, userID uuid.UUID, fromCurrency string, amount, fee money.Amount, toCurrency string, toAmount money.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExchange", ctx, transactionID, userID, fromCurrency, amount, fee, toCurrency, toAmount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExchange indicates an expected call of SaveExchange.
+func (mr *MockWalletWriterMockRecorder) SaveExchange(ctx, transactionID, userID, fromCurrency, amount, fee, toCurrency, toAmount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExchange", reflect.TypeOf((*MockWalletWriter)(nil).SaveExchange), ctx, transactionID, userID, fromCurrency, amount, fee, toCurrency, toAmount)
 }
 
 // SaveWithdraw mocks base method.
-func (m *MockWalletWriter) SaveWithdraw(ctx context.Context, userID uuid.UUID, amount float64, currency string) error {
+func (m *MockWalletWriter) SaveWithdraw(ctx context.Context, transactionID, userID uuid.UUID, amount money.Amount, currency string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveWithdraw", ctx, userID, amount, currency)
+	ret := m.ctrl.Call(m, "SaveWithdraw", ctx, transactionID, userID, amount, currency)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveWithdraw indicates an expected call of SaveWithdraw.
-func (mr *MockWalletWriterMockRecorder) SaveWithdraw(ctx, userID, amount, currency interface{}) *gomock.Call {
+func (mr *MockWalletWriterMockRecorder) SaveWithdraw(ctx, transactionID, userID, amount, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithdraw", reflect.TypeOf((*MockWalletWriter)(nil).SaveWithdraw), ctx, userID, amount, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithdraw", reflect.TypeOf((*MockWalletWriter)(nil).SaveWithdraw), ctx, transactionID, userID, amount, currency)
 }
 
 // MockWalletReader is a mock of WalletReader interface.
@@ -88,10 +136,10 @@ func (m *MockWalletReader) EXPECT() *MockWalletReaderMockRecorder {
 }
 
 // GetByUserID mocks base method.
-func (m *MockWalletReader) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]float64, error) {
+func (m *MockWalletReader) GetByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
-	ret0, _ := ret[0].(map[string]float64)
+	ret0, _ := ret[0].(map[string]money.Amount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -102,6 +150,44 @@ func (mr *MockWalletReaderMockRecorder) GetByUserID(ctx, userID interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWalletReader)(nil).GetByUserID), ctx, userID)
 }
 
+// MockOverdraftReader is a mock of OverdraftReader interface.
+type MockOverdraftReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockOverdraftReaderMockRecorder
+}
+
+// MockOverdraftReaderMockRecorder is the mock recorder for MockOverdraftReader.
+type MockOverdraftReaderMockRecorder struct {
+	mock *MockOverdraftReader
+}
+
+// NewMockOverdraftReader creates a new mock instance.
+func NewMockOverdraftReader(ctrl *gomock.Controller) *MockOverdraftReader {
+	mock := &MockOverdraftReader{ctrl: ctrl}
+	mock.recorder = &MockOverdraftReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOverdraftReader) EXPECT() *MockOverdraftReaderMockRecorder {
+	return m.recorder
+}
+
+// GetOverdraftLimits mocks base method.
+func (m *MockOverdraftReader) GetOverdraftLimits(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdraftLimits", ctx, userID)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdraftLimits indicates an expected call of GetOverdraftLimits.
+func (mr *MockOverdraftReaderMockRecorder) GetOverdraftLimits(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdraftLimits", reflect.TypeOf((*MockOverdraftReader)(nil).GetOverdraftLimits), ctx, userID)
+}
+
 // MockExchangeRateReader is a mock of ExchangeRateReader interface.
 type MockExchangeRateReader struct {
 	ctrl     *gomock.Controller
@@ -155,6 +241,61 @@ func (mr *MockExchangeRateReaderMockRecorder) GetExchangeRates(ctx interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRates", reflect.TypeOf((*MockExchangeRateReader)(nil).GetExchangeRates), ctx)
 }
 
+// MockSourcedRateReader is a mock of SourcedRateReader interface.
+type MockSourcedRateReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockSourcedRateReaderMockRecorder
+}
+
+// MockSourcedRateReaderMockRecorder is the mock recorder for MockSourcedRateReader.
+type MockSourcedRateReaderMockRecorder struct {
+	mock *MockSourcedRateReader
+}
+
+// NewMockSourcedRateReader creates a new mock instance.
+func NewMockSourcedRateReader(ctrl *gomock.Controller) *MockSourcedRateReader {
+	mock := &MockSourcedRateReader{ctrl: ctrl}
+	mock.recorder = &MockSourcedRateReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSourcedRateReader) EXPECT() *MockSourcedRateReaderMockRecorder {
+	return m.recorder
+}
+
+// GetSourcedExchangeRateForCurrency mocks base method.
+func (m *MockSourcedRateReader) GetSourcedExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourcedExchangeRateForCurrency", ctx, fromCurrency, toCurrency)
+	ret0, _ := ret[0].(float32)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSourcedExchangeRateForCurrency indicates an expected call of GetSourcedExchangeRateForCurrency.
+func (mr *MockSourcedRateReaderMockRecorder) GetSourcedExchangeRateForCurrency(ctx, fromCurrency, toCurrency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourcedExchangeRateForCurrency", reflect.TypeOf((*MockSourcedRateReader)(nil).GetSourcedExchangeRateForCurrency), ctx, fromCurrency, toCurrency)
+}
+
+// GetSourcedExchangeRates mocks base method.
+func (m *MockSourcedRateReader) GetSourcedExchangeRates(ctx context.Context) (map[string]float32, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourcedExchangeRates", ctx)
+	ret0, _ := ret[0].(map[string]float32)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSourcedExchangeRates indicates an expected call of GetSourcedExchangeRates.
+func (mr *MockSourcedRateReaderMockRecorder) GetSourcedExchangeRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourcedExchangeRates", reflect.TypeOf((*MockSourcedRateReader)(nil).GetSourcedExchangeRates), ctx)
+}
+
 // MockExchangeRateCacheReader is a mock of ExchangeRateCacheReader interface.
 type MockExchangeRateCacheReader struct {
 	ctrl     *gomock.Controller
@@ -179,12 +320,13 @@ func (m *MockExchangeRateCacheReader) EXPECT() *MockExchangeRateCacheReaderMockR
 }
 
 // GetExchangeRateForCurrency mocks base method.
-func (m *MockExchangeRateCacheReader) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, error) {
+func (m *MockExchangeRateCacheReader) GetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string) (float32, models.RateSource, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetExchangeRateForCurrency", ctx, fromCurrency, toCurrency)
 	ret0, _ := ret[0].(float32)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(models.RateSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetExchangeRateForCurrency indicates an expected call of GetExchangeRateForCurrency.
@@ -193,45 +335,392 @@ func (mr *MockExchangeRateCacheReaderMockRecorder) GetExchangeRateForCurrency(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRateForCurrency", reflect.TypeOf((*MockExchangeRateCacheReader)(nil).GetExchangeRateForCurrency), ctx, fromCurrency, toCurrency)
 }
 
+// GetExchangeRates mocks base method.
+func (m *MockExchangeRateCacheReader) GetExchangeRates(ctx context.Context) (map[string]float32, models.RateSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRates", ctx)
+	ret0, _ := ret[0].(map[string]float32)
+	ret1, _ := ret[1].(models.RateSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetExchangeRates indicates an expected call of GetExchangeRates.
+func (mr *MockExchangeRateCacheReaderMockRecorder) GetExchangeRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRates", reflect.TypeOf((*MockExchangeRateCacheReader)(nil).GetExchangeRates), ctx)
+}
+
+// GetExchangeRatesForCurrencies mocks base method.
+func (m *MockExchangeRateCacheReader) GetExchangeRatesForCurrencies(ctx context.Context, pairs []models.CurrencyPair) (map[models.CurrencyPair]models.PairRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRatesForCurrencies", ctx, pairs)
+	ret0, _ := ret[0].(map[models.CurrencyPair]models.PairRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExchangeRatesForCurrencies indicates an expected call of GetExchangeRatesForCurrencies.
+func (mr *MockExchangeRateCacheReaderMockRecorder) GetExchangeRatesForCurrencies(ctx, pairs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRatesForCurrencies", reflect.TypeOf((*MockExchangeRateCacheReader)(nil).GetExchangeRatesForCurrencies), ctx, pairs)
+}
+
 // SetExchangeRateForCurrency mocks base method.
-func (m *MockExchangeRateCacheReader) SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32) error {
+func (m *MockExchangeRateCacheReader) SetExchangeRateForCurrency(ctx context.Context, fromCurrency, toCurrency string, rate float32, src models.RateSource) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetExchangeRateForCurrency", ctx, fromCurrency, toCurrency, rate)
+	ret := m.ctrl.Call(m, "SetExchangeRateForCurrency", ctx, fromCurrency, toCurrency, rate, src)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetExchangeRateForCurrency indicates an expected call of SetExchangeRateForCurrency.
-func (mr *MockExchangeRateCacheReaderMockRecorder) SetExchangeRateForCurrency(ctx, fromCurrency, toCurrency, rate interface{}) *gomock.Call {
+func (mr *MockExchangeRateCacheReaderMockRecorder) SetExchangeRateForCurrency(ctx, fromCurrency, toCurrency, rate, src interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExchangeRateForCurrency", reflect.TypeOf((*MockExchangeRateCacheReader)(nil).SetExchangeRateForCurrency), ctx, fromCurrency, toCurrency, rate, src)
+}
+
+// SetExchangeRates mocks base method.
+func (m *MockExchangeRateCacheReader) SetExchangeRates(ctx context.Context, rates map[string]float32, src models.RateSource) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExchangeRates", ctx, rates, src)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetExchangeRates indicates an expected call of SetExchangeRates.
+func (mr *MockExchangeRateCacheReaderMockRecorder) SetExchangeRates(ctx, rates, src interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExchangeRates", reflect.TypeOf((*MockExchangeRateCacheReader)(nil).SetExchangeRates), ctx, rates, src)
+}
+
+// MockRateTTLPolicy is a mock of RateTTLPolicy interface.
+type MockRateTTLPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateTTLPolicyMockRecorder
+}
+
+// MockRateTTLPolicyMockRecorder is the mock recorder for MockRateTTLPolicy.
+type MockRateTTLPolicyMockRecorder struct {
+	mock *MockRateTTLPolicy
+}
+
+// NewMockRateTTLPolicy creates a new mock instance.
+func NewMockRateTTLPolicy(ctrl *gomock.Controller) *MockRateTTLPolicy {
+	mock := &MockRateTTLPolicy{ctrl: ctrl}
+	mock.recorder = &MockRateTTLPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateTTLPolicy) EXPECT() *MockRateTTLPolicyMockRecorder {
+	return m.recorder
+}
+
+// Observe mocks base method.
+func (m *MockRateTTLPolicy) Observe(latency time.Duration, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Observe", latency, err)
+}
+
+// Observe indicates an expected call of Observe.
+func (mr *MockRateTTLPolicyMockRecorder) Observe(latency, err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockRateTTLPolicy)(nil).Observe), latency, err)
+}
+
+// TTL mocks base method.
+func (m *MockRateTTLPolicy) TTL() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TTL")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// TTL indicates an expected call of TTL.
+func (mr *MockRateTTLPolicyMockRecorder) TTL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TTL", reflect.TypeOf((*MockRateTTLPolicy)(nil).TTL))
+}
+
+// MockCurrencyLister is a mock of CurrencyLister interface.
+type MockCurrencyLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyListerMockRecorder
+}
+
+// MockCurrencyListerMockRecorder is the mock recorder for MockCurrencyLister.
+type MockCurrencyListerMockRecorder struct {
+	mock *MockCurrencyLister
+}
+
+// NewMockCurrencyLister creates a new mock instance.
+func NewMockCurrencyLister(ctrl *gomock.Controller) *MockCurrencyLister {
+	mock := &MockCurrencyLister{ctrl: ctrl}
+	mock.recorder = &MockCurrencyListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyLister) EXPECT() *MockCurrencyListerMockRecorder {
+	return m.recorder
+}
+
+// Codes mocks base method.
+func (m *MockCurrencyLister) Codes(ctx context.Context) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Codes", ctx)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Codes indicates an expected call of Codes.
+func (mr *MockCurrencyListerMockRecorder) Codes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Codes", reflect.TypeOf((*MockCurrencyLister)(nil).Codes), ctx)
+}
+
+// MockCurrencyPrecision is a mock of CurrencyPrecision interface.
+type MockCurrencyPrecision struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyPrecisionMockRecorder
+}
+
+// MockCurrencyPrecisionMockRecorder is the mock recorder for MockCurrencyPrecision.
+type MockCurrencyPrecisionMockRecorder struct {
+	mock *MockCurrencyPrecision
+}
+
+// NewMockCurrencyPrecision creates a new mock instance.
+func NewMockCurrencyPrecision(ctrl *gomock.Controller) *MockCurrencyPrecision {
+	mock := &MockCurrencyPrecision{ctrl: ctrl}
+	mock.recorder = &MockCurrencyPrecisionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyPrecision) EXPECT() *MockCurrencyPrecisionMockRecorder {
+	return m.recorder
+}
+
+// Decimals mocks base method.
+func (m *MockCurrencyPrecision) Decimals(ctx context.Context, code string) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decimals", ctx, code)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Decimals indicates an expected call of Decimals.
+func (mr *MockCurrencyPrecisionMockRecorder) Decimals(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decimals", reflect.TypeOf((*MockCurrencyPrecision)(nil).Decimals), ctx, code)
+}
+
+// MockTransactionStore is a mock of TransactionStore interface.
+type MockTransactionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionStoreMockRecorder
+}
+
+// MockTransactionStoreMockRecorder is the mock recorder for MockTransactionStore.
+type MockTransactionStoreMockRecorder struct {
+	mock *MockTransactionStore
+}
+
+// NewMockTransactionStore creates a new mock instance.
+func NewMockTransactionStore(ctrl *gomock.Controller) *MockTransactionStore {
+	mock := &MockTransactionStore{ctrl: ctrl}
+	mock.recorder = &MockTransactionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionStore) EXPECT() *MockTransactionStoreMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockTransactionStore) List(ctx context.Context, filter models.TransactionFilter) ([]models.TransactionDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]models.TransactionDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransactionStoreMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransactionStore)(nil).List), ctx, filter)
+}
+
+// Save mocks base method.
+func (m *MockTransactionStore) Save(ctx context.Context, txn models.TransactionDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTransactionStoreMockRecorder) Save(ctx, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTransactionStore)(nil).Save), ctx, txn)
+}
+
+// MockSpendingLimiter is a mock of SpendingLimiter interface.
+type MockSpendingLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpendingLimiterMockRecorder
+}
+
+// MockSpendingLimiterMockRecorder is the mock recorder for MockSpendingLimiter.
+type MockSpendingLimiterMockRecorder struct {
+	mock *MockSpendingLimiter
+}
+
+// NewMockSpendingLimiter creates a new mock instance.
+func NewMockSpendingLimiter(ctrl *gomock.Controller) *MockSpendingLimiter {
+	mock := &MockSpendingLimiter{ctrl: ctrl}
+	mock.recorder = &MockSpendingLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpendingLimiter) EXPECT() *MockSpendingLimiterMockRecorder {
+	return m.recorder
+}
+
+// Release mocks base method.
+func (m *MockSpendingLimiter) Release(ctx context.Context, usageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, usageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockSpendingLimiterMockRecorder) Release(ctx, usageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockSpendingLimiter)(nil).Release), ctx, usageID)
+}
+
+// Reserve mocks base method.
+func (m *MockSpendingLimiter) Reserve(ctx context.Context, userID uuid.UUID, currency string, amount money.Amount) (int64, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, userID, currency, amount)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockSpendingLimiterMockRecorder) Reserve(ctx, userID, currency, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockSpendingLimiter)(nil).Reserve), ctx, userID, currency, amount)
+}
+
+// MockExchangeReceiptRecorder is a mock of ExchangeReceiptRecorder interface.
+type MockExchangeReceiptRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeReceiptRecorderMockRecorder
+}
+
+// MockExchangeReceiptRecorderMockRecorder is the mock recorder for MockExchangeReceiptRecorder.
+type MockExchangeReceiptRecorderMockRecorder struct {
+	mock *MockExchangeReceiptRecorder
+}
+
+// NewMockExchangeReceiptRecorder creates a new mock instance.
+func NewMockExchangeReceiptRecorder(ctrl *gomock.Controller) *MockExchangeReceiptRecorder {
+	mock := &MockExchangeReceiptRecorder{ctrl: ctrl}
+	mock.recorder = &MockExchangeReceiptRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeReceiptRecorder) EXPECT() *MockExchangeReceiptRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockExchangeReceiptRecorder) Record(ctx context.Context, receipt models.ExchangeReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, receipt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockExchangeReceiptRecorderMockRecorder) Record(ctx, receipt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockExchangeReceiptRecorder)(nil).Record), ctx, receipt)
+}
+
+// MockWebhookNotifier is a mock of WebhookNotifier interface.
+type MockWebhookNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookNotifierMockRecorder
+}
+
+// MockWebhookNotifierMockRecorder is the mock recorder for MockWebhookNotifier.
+type MockWebhookNotifierMockRecorder struct {
+	mock *MockWebhookNotifier
+}
+
+// NewMockWebhookNotifier creates a new mock instance.
+func NewMockWebhookNotifier(ctrl *gomock.Controller) *MockWebhookNotifier {
+	mock := &MockWebhookNotifier{ctrl: ctrl}
+	mock.recorder = &MockWebhookNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookNotifier) EXPECT() *MockWebhookNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockWebhookNotifier) Notify(ctx context.Context, event models.WebhookEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockWebhookNotifierMockRecorder) Notify(ctx, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExchangeRateForCurrency", reflect.TypeOf((*MockExchangeRateCacheReader)(nil).SetExchangeRateForCurrency), ctx, fromCurrency, toCurrency, rate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockWebhookNotifier)(nil).Notify), ctx, event)
 }
 
-// MockKafkaWriter is a mock of KafkaWriter interface.
-type MockKafkaWriter struct {
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
 	ctrl     *gomock.Controller
-	recorder *MockKafkaWriterMockRecorder
+	recorder *MockEventPublisherMockRecorder
 }
 
-// MockKafkaWriterMockRecorder is the mock recorder for MockKafkaWriter.
-type MockKafkaWriterMockRecorder struct {
-	mock *MockKafkaWriter
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
 }
 
-// NewMockKafkaWriter creates a new mock instance.
-func NewMockKafkaWriter(ctrl *gomock.Controller) *MockKafkaWriter {
-	mock := &MockKafkaWriter{ctrl: ctrl}
-	mock.recorder = &MockKafkaWriterMockRecorder{mock}
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockKafkaWriter) EXPECT() *MockKafkaWriterMockRecorder {
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
 	return m.recorder
 }
 
 // Close mocks base method.
-func (m *MockKafkaWriter) Close() error {
+func (m *MockEventPublisher) Close() error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Close")
 	ret0, _ := ret[0].(error)
@@ -239,13 +728,13 @@ func (m *MockKafkaWriter) Close() error {
 }
 
 // Close indicates an expected call of Close.
-func (mr *MockKafkaWriterMockRecorder) Close() *gomock.Call {
+func (mr *MockEventPublisherMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKafkaWriter)(nil).Close))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockEventPublisher)(nil).Close))
 }
 
 // WriteMessages mocks base method.
-func (m *MockKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+func (m *MockEventPublisher) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{ctx}
 	for _, a := range msgs {
@@ -257,8 +746,8 @@ func (m *MockKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Messa
 }
 
 // WriteMessages indicates an expected call of WriteMessages.
-func (mr *MockKafkaWriterMockRecorder) WriteMessages(ctx interface{}, msgs ...interface{}) *gomock.Call {
+func (mr *MockEventPublisherMockRecorder) WriteMessages(ctx interface{}, msgs ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{ctx}, msgs...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessages", reflect.TypeOf((*MockKafkaWriter)(nil).WriteMessages), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteMessages", reflect.TypeOf((*MockEventPublisher)(nil).WriteMessages), varargs...)
 }
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+)
+
+// ErrOperatorNotAdmin is returned when a break-glass operation is run on behalf of a user who is not an admin.
+var ErrOperatorNotAdmin = errors.New("operator is not an admin")
+
+// ErrInvalidAdjustment is returned when an adjustment has a zero amount, an unsupported currency or no reason.
+var ErrInvalidAdjustment = errors.New("invalid adjustment")
+
+// CurrencyChecker reports whether a currency is supported.
+type CurrencyChecker interface {
+	IsSupported(ctx context.Context, code string) bool
+}
+
+// WalletReport describes the wallets of a user.
+type WalletReport struct {
+	User     models.UserDB
+	Balances map[string]money.Amount // Total balances by currency
+	Held     map[string]money.Amount // Amounts held by pending holds by currency
+}
+
+// WalletOpsService runs break-glass wallet operations directly on the repositories, for use
+// when the HTTP API is down. Every operation is run on behalf of an admin and audited.
+type WalletOpsService struct {
+	users      UserReader
+	reader     WalletReader
+	writer     WalletWriter
+	holds      WalletHoldStore
+	currencies CurrencyChecker
+	audit      AuditWriter
+	history    TransactionStore
+	tx         Transactor
+}
+
+// NewWalletOpsService creates a new WalletOpsService. Adjustments are recorded in history in
+// the database transaction of the balance change, run by tx.
+func NewWalletOpsService(
+	users UserReader,
+	reader WalletReader,
+	writer WalletWriter,
+	holds WalletHoldStore,
+	currencies CurrencyChecker,
+	audit AuditWriter,
+	history TransactionStore,
+	tx Transactor,
+) *WalletOpsService {
+	return &WalletOpsService{
+		users:      users,
+		reader:     reader,
+		writer:     writer,
+		holds:      holds,
+		currencies: currencies,
+		audit:      audit,
+		history:    history,
+		tx:         tx,
+	}
+}
+
+// Show returns the wallets of the user with email on behalf of the admin with operatorEmail.
+// The inspection is recorded in the audit trail.
+func (s *WalletOpsService) Show(ctx context.Context, operatorEmail, email string) (WalletReport, error) {
+	operator, user, err := s.lookup(ctx, operatorEmail, email)
+	if err != nil {
+		return WalletReport{}, err
+	}
+
+	report, err := s.report(ctx, user)
+	if err != nil {
+		return WalletReport{}, err
+	}
+
+	if err := s.audit.Save(ctx, operator.UserID, models.AuditActionWalletShow, &user.UserID, nil); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit wallet inspection", "operatorID", operator.UserID, "userID", user.UserID, "error", err)
+		return WalletReport{}, err
+	}
+	return report, nil
+}
+
+// Adjust credits a positive or debits a negative amount to the wallet of the user with email
+// on behalf of the admin with operatorEmail, and returns the wallets after the adjustment.
+// The adjustment is appended to wallet_events and the transaction history as a deposit or
+// withdrawal, and recorded in the audit trail with the reason.
+func (s *WalletOpsService) Adjust(ctx context.Context, operatorEmail, email, currency string, amount money.Amount, reason string) (WalletReport, error) {
+	if amount == 0 || reason == "" || !s.currencies.IsSupported(ctx, currency) {
+		return WalletReport{}, ErrInvalidAdjustment
+	}
+
+	operator, user, err := s.lookup(ctx, operatorEmail, email)
+	if err != nil {
+		return WalletReport{}, err
+	}
+
+	record := models.TransactionDB{
+		TransactionID: uuid.New(),
+		UserID:        user.UserID,
+		Operation:     models.OperationDeposit,
+		Currency:      currency,
+		Amount:        amount,
+	}
+	if !amount.IsPositive() {
+		record.Operation, record.Amount = models.OperationWithdraw, -amount
+	}
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if record.Operation == models.OperationDeposit {
+			err = s.writer.SaveDeposit(ctx, record.TransactionID, user.UserID, record.Amount, currency)
+		} else {
+			err = s.writer.SaveWithdraw(ctx, record.TransactionID, user.UserID, record.Amount, currency)
+		}
+		if err != nil {
+			return err
+		}
+		return s.history.Save(ctx, record)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to adjust wallet", "operatorID", operator.UserID, "userID", user.UserID, "currency", currency, "amount", amount, "error", err)
+		if errors.Is(err, repositories.ErrInsufficientFunds) {
+			return WalletReport{}, ErrInsufficientFunds
+		}
+		return WalletReport{}, err
+	}
+
+	details := map[string]any{
+		"currency": currency,
+		"amount":   amount,
+		"reason":   reason,
+	}
+	if err := s.audit.Save(ctx, operator.UserID, models.AuditActionWalletAdjust, &user.UserID, details); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit wallet adjustment", "operatorID", operator.UserID, "userID", user.UserID, "error", err)
+		return WalletReport{}, err
+	}
+	logger.FromContext(ctx).Warnw("wallet adjusted", "operatorID", operator.UserID, "userID", user.UserID, "currency", currency, "amount", amount, "reason", reason)
+
+	return s.report(ctx, user)
+}
+
+// lookup finds the operator, who must be an admin, and the user by email.
+func (s *WalletOpsService) lookup(ctx context.Context, operatorEmail, email string) (operator, user *models.UserDB, err error) {
+	if operator, err = s.userByEmail(ctx, operatorEmail); err != nil {
+		return nil, nil, err
+	}
+	if operator.Role != models.RoleAdmin {
+		return nil, nil, ErrOperatorNotAdmin
+	}
+	if user, err = s.userByEmail(ctx, email); err != nil {
+		return nil, nil, err
+	}
+	return operator, user, nil
+}
+
+func (s *WalletOpsService) userByEmail(ctx context.Context, email string) (*models.UserDB, error) {
+	user, err := s.users.GetByUsernameOrEmail(ctx, nil, &email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserDoesNotExist
+		}
+		logger.FromContext(ctx).Errorw("failed to get user by email", "email", email, "error", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *WalletOpsService) report(ctx context.Context, user *models.UserDB) (WalletReport, error) {
+	balances, err := s.reader.GetByUserID(ctx, user.UserID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get user balances", "userID", user.UserID, "error", err)
+		return WalletReport{}, err
+	}
+	held, err := s.holds.HeldByUserID(ctx, user.UserID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get held amounts", "userID", user.UserID, "error", err)
+		return WalletReport{}, err
+	}
+	return WalletReport{User: *user, Balances: balances, Held: held}, nil
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_ops.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCurrencyChecker is a mock of CurrencyChecker interface.
+type MockCurrencyChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyCheckerMockRecorder
+}
+
+// MockCurrencyCheckerMockRecorder is the mock recorder for MockCurrencyChecker.
+type MockCurrencyCheckerMockRecorder struct {
+	mock *MockCurrencyChecker
+}
+
+// NewMockCurrencyChecker creates a new mock instance.
+func NewMockCurrencyChecker(ctrl *gomock.Controller) *MockCurrencyChecker {
+	mock := &MockCurrencyChecker{ctrl: ctrl}
+	mock.recorder = &MockCurrencyCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyChecker) EXPECT() *MockCurrencyCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsSupported mocks base method.
+func (m *MockCurrencyChecker) IsSupported(ctx context.Context, code string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSupported", ctx, code)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSupported indicates an expected call of IsSupported.
+func (mr *MockCurrencyCheckerMockRecorder) IsSupported(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSupported", reflect.TypeOf((*MockCurrencyChecker)(nil).IsSupported), ctx, code)
+}
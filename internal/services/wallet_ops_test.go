@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletOpsService(t *testing.T) {
+	ctx := context.Background()
+	admin := &models.UserDB{UserID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	user := &models.UserDB{UserID: uuid.New(), Email: "john@example.com", Role: models.RoleUser}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := NewMockUserReader(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	writer := NewMockWalletWriter(ctrl)
+	holds := NewMockWalletHoldStore(ctrl)
+	currencies := NewMockCurrencyChecker(ctrl)
+	audit := NewMockAuditWriter(ctrl)
+	history := NewMockTransactionStore(ctrl)
+
+	svc := NewWalletOpsService(users, reader, writer, holds, currencies, audit, history, directTx{})
+
+	expectUsers := func() {
+		users.EXPECT().GetByUsernameOrEmail(ctx, nil, &admin.Email).Return(admin, nil)
+		users.EXPECT().GetByUsernameOrEmail(ctx, nil, &user.Email).Return(user, nil)
+	}
+	expectReport := func(balances map[string]money.Amount) {
+		reader.EXPECT().GetByUserID(ctx, user.UserID).Return(balances, nil)
+		holds.EXPECT().HeldByUserID(ctx, user.UserID).Return(map[string]money.Amount{models.USD: money.MustParse("5")}, nil)
+	}
+
+	t.Run("show", func(t *testing.T) {
+		expectUsers()
+		expectReport(map[string]money.Amount{models.USD: money.MustParse("100")})
+		audit.EXPECT().Save(ctx, admin.UserID, models.AuditActionWalletShow, &user.UserID, nil).Return(nil)
+
+		report, err := svc.Show(ctx, admin.Email, user.Email)
+		assert.NoError(t, err)
+		assert.Equal(t, *user, report.User)
+		assert.Equal(t, money.MustParse("100"), report.Balances[models.USD])
+		assert.Equal(t, money.MustParse("5"), report.Held[models.USD])
+	})
+
+	t.Run("show is not returned if it cannot be audited", func(t *testing.T) {
+		expectUsers()
+		expectReport(map[string]money.Amount{models.USD: money.MustParse("100")})
+		audit.EXPECT().Save(ctx, admin.UserID, models.AuditActionWalletShow, &user.UserID, nil).Return(errors.New("db error"))
+
+		_, err := svc.Show(ctx, admin.Email, user.Email)
+		assert.EqualError(t, err, "db error")
+	})
+
+	t.Run("credit", func(t *testing.T) {
+		currencies.EXPECT().IsSupported(ctx, models.USD).Return(true)
+		expectUsers()
+		writer.EXPECT().SaveDeposit(ctx, gomock.Any(), user.UserID, money.MustParse("10"), models.USD).Return(nil)
+		history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			assert.Equal(t, models.OperationDeposit, txn.Operation)
+			assert.Equal(t, user.UserID, txn.UserID)
+			assert.Equal(t, money.MustParse("10"), txn.Amount)
+			return nil
+		})
+		audit.EXPECT().Save(ctx, admin.UserID, models.AuditActionWalletAdjust, &user.UserID, map[string]any{
+			"currency": models.USD,
+			"amount":   money.MustParse("10"),
+			"reason":   "refund",
+		}).Return(nil)
+		expectReport(map[string]money.Amount{models.USD: money.MustParse("110")})
+
+		report, err := svc.Adjust(ctx, admin.Email, user.Email, models.USD, money.MustParse("10"), "refund")
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("110"), report.Balances[models.USD])
+	})
+
+	t.Run("debit", func(t *testing.T) {
+		currencies.EXPECT().IsSupported(ctx, models.USD).Return(true)
+		expectUsers()
+		writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), user.UserID, money.MustParse("10"), models.USD).Return(nil)
+		history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			assert.Equal(t, models.OperationWithdraw, txn.Operation)
+			assert.Equal(t, money.MustParse("10"), txn.Amount)
+			return nil
+		})
+		audit.EXPECT().Save(ctx, admin.UserID, models.AuditActionWalletAdjust, &user.UserID, gomock.Any()).Return(nil)
+		expectReport(map[string]money.Amount{models.USD: money.MustParse("90")})
+
+		report, err := svc.Adjust(ctx, admin.Email, user.Email, models.USD, money.MustParse("-10"), "chargeback")
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("90"), report.Balances[models.USD])
+	})
+
+	t.Run("debit above the available balance", func(t *testing.T) {
+		currencies.EXPECT().IsSupported(ctx, models.USD).Return(true)
+		expectUsers()
+		writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), user.UserID, money.MustParse("1000"), models.USD).Return(repositories.ErrInsufficientFunds)
+
+		_, err := svc.Adjust(ctx, admin.Email, user.Email, models.USD, money.MustParse("-1000"), "chargeback")
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("invalid adjustment", func(t *testing.T) {
+		currencies.EXPECT().IsSupported(ctx, "BTC").Return(false)
+
+		_, err := svc.Adjust(ctx, admin.Email, user.Email, "BTC", money.MustParse("10"), "refund")
+		assert.ErrorIs(t, err, ErrInvalidAdjustment)
+
+		_, err = svc.Adjust(ctx, admin.Email, user.Email, models.USD, money.Zero, "refund")
+		assert.ErrorIs(t, err, ErrInvalidAdjustment)
+
+		_, err = svc.Adjust(ctx, admin.Email, user.Email, models.USD, money.MustParse("10"), "")
+		assert.ErrorIs(t, err, ErrInvalidAdjustment)
+	})
+
+	t.Run("operator is not an admin", func(t *testing.T) {
+		users.EXPECT().GetByUsernameOrEmail(ctx, nil, &user.Email).Return(user, nil)
+
+		_, err := svc.Show(ctx, user.Email, user.Email)
+		assert.ErrorIs(t, err, ErrOperatorNotAdmin)
+	})
+
+	t.Run("user does not exist", func(t *testing.T) {
+		missing := "nobody@example.com"
+		users.EXPECT().GetByUsernameOrEmail(ctx, nil, &admin.Email).Return(admin, nil)
+		users.EXPECT().GetByUsernameOrEmail(ctx, nil, &missing).Return(nil, sql.ErrNoRows)
+
+		_, err := svc.Show(ctx, admin.Email, missing)
+		assert.ErrorIs(t, err, ErrUserDoesNotExist)
+	})
+}
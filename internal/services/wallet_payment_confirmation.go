@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// PaymentConfirmationConsumer names the consumer of payment confirmations in the processed messages.
+const PaymentConfirmationConsumer = "payment-confirmations"
+
+var (
+	// ErrPaymentConfirmationsDisabled is returned by CreditPayment of a service created without WithPaymentConfirmations.
+	ErrPaymentConfirmationsDisabled = errors.New("payment confirmations disabled")
+	// ErrInvalidPaymentConfirmation is returned for a confirmation without a payment ID, a user,
+	// a supported currency or a positive amount. It is not retried.
+	ErrInvalidPaymentConfirmation = errors.New("invalid payment confirmation")
+)
+
+// ProcessedMessageStore records the messages a consumer has processed.
+type ProcessedMessageStore interface {
+	MarkProcessed(ctx context.Context, consumer, key string) (bool, error) // Records a message; false if it was already recorded
+}
+
+// WithPaymentConfirmations lets external payments confirmed over Kafka be credited to
+// wallets. The payment ID is recorded and the wallet credited in one database
+// transaction run by tx, so the writer and the store must take part in it.
+func WithPaymentConfirmations(store ProcessedMessageStore, tx Transactor) WalletOpt {
+	return func(s *WalletService) {
+		s.processed = store
+		s.tx = tx
+	}
+}
+
+// CreditPayment deposits a confirmed external payment into the user's wallet and publishes
+// it like a deposit. Every payment ID is credited at most once: it returns false without
+// crediting a payment that was already credited.
+func (s *WalletService) CreditPayment(ctx context.Context, paymentID string, payment models.PaymentConfirmation) (bool, error) {
+	if s.processed == nil {
+		return false, ErrPaymentConfirmationsDisabled
+	}
+	if paymentID == "" || payment.UserID == uuid.Nil || !payment.Amount.IsPositive() || !s.supportedCurrency(ctx, payment.Currency) {
+		return false, ErrInvalidPaymentConfirmation
+	}
+
+	reference := payment.Reference
+	if reference == "" {
+		reference = paymentID
+	}
+	record := models.TransactionDB{
+		TransactionID: uuid.New(),
+		UserID:        payment.UserID,
+		Operation:     models.OperationDeposit,
+		Currency:      payment.Currency,
+		Amount:        payment.Amount,
+		Reference:     optionalReference(reference),
+	}
+	txnID := record.TransactionID
+	credited := false
+	err := s.tx.InTx(ctx, func(ctx context.Context) error {
+		// The transaction is retried on serialization failures: a retry may find the payment
+		// already processed by a concurrent delivery
+		marked, err := s.processed.MarkProcessed(ctx, PaymentConfirmationConsumer, paymentID)
+		credited = marked
+		if err != nil || !marked {
+			return err
+		}
+		if err := s.writeRepo.SaveDeposit(ctx, txnID, payment.UserID, payment.Amount, payment.Currency); err != nil {
+			return err
+		}
+		return s.recordTransaction(ctx, record)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to credit payment", "payment_id", paymentID, "userID", payment.UserID,
+			"amount", payment.Amount, "currency", payment.Currency, "error", err)
+		return false, err
+	}
+	if !credited {
+		logger.FromContext(ctx).Infow("payment already credited", "payment_id", paymentID, "userID", payment.UserID)
+		return false, nil
+	}
+	logger.FromContext(ctx).Infow("payment credited", "payment_id", paymentID, "userID", payment.UserID,
+		"amount", payment.Amount, "currency", payment.Currency, "transaction_id", txnID)
+
+	s.notifyWebhooks(ctx, models.WebhookEventDeposit, record)
+	balances := s.eventBalances(ctx, payment.UserID, nil)
+	s.publishTransaction(ctx, payment.Currency, models.Transaction{
+		TransactionID: txnID.String(),
+		Timestamp:     time.Now().Unix(),
+		Amount:        payment.Amount,
+		UserID:        payment.UserID.String(),
+		Operation:     models.OperationDeposit,
+		Reference:     reference,
+		Balance:       balanceOf(balances, payment.Currency),
+	})
+	s.publishBalances(ctx, txnID, payment.UserID, balances, payment.Currency)
+
+	return true, nil
+}
+
+// supportedCurrency reports whether currency is supported. Without WithCurrencies, any
+// currency is.
+func (s *WalletService) supportedCurrency(ctx context.Context, currency string) bool {
+	if currency == "" {
+		return false
+	}
+	return s.currencies == nil || slices.Contains(s.currencies.Codes(ctx), currency)
+}
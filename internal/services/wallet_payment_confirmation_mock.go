@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_payment_confirmation.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProcessedMessageStore is a mock of ProcessedMessageStore interface.
+type MockProcessedMessageStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockProcessedMessageStoreMockRecorder
+}
+
+// MockProcessedMessageStoreMockRecorder is the mock recorder for MockProcessedMessageStore.
+type MockProcessedMessageStoreMockRecorder struct {
+	mock *MockProcessedMessageStore
+}
+
+// NewMockProcessedMessageStore creates a new mock instance.
+func NewMockProcessedMessageStore(ctrl *gomock.Controller) *MockProcessedMessageStore {
+	mock := &MockProcessedMessageStore{ctrl: ctrl}
+	mock.recorder = &MockProcessedMessageStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProcessedMessageStore) EXPECT() *MockProcessedMessageStoreMockRecorder {
+	return m.recorder
+}
+
+// MarkProcessed mocks base method.
+func (m *MockProcessedMessageStore) MarkProcessed(ctx context.Context, consumer, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkProcessed", ctx, consumer, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkProcessed indicates an expected call of MarkProcessed.
+func (mr *MockProcessedMessageStoreMockRecorder) MarkProcessed(ctx, consumer, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkProcessed", reflect.TypeOf((*MockProcessedMessageStore)(nil).MarkProcessed), ctx, consumer, key)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_CreditPayment(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+	payment := models.PaymentConfirmation{UserID: userID, Currency: models.USD, Amount: amount}
+
+	// inTx runs fn like the real transactor would, without a database
+	inTx := func(tx *MockTransactor) {
+		tx.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	}
+
+	t.Run("payment is credited and published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockProcessedMessageStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+		history := NewMockTransactionStore(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		kafkaWriter := NewMockEventPublisher(ctrl)
+
+		inTx(tx)
+		store.EXPECT().MarkProcessed(ctx, PaymentConfirmationConsumer, "pay-1").Return(true, nil)
+		writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, amount, models.USD).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("150")}, nil)
+		history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			assert.Equal(t, models.OperationDeposit, txn.Operation)
+			if assert.NotNil(t, txn.Reference) {
+				assert.Equal(t, "pay-1", *txn.Reference)
+			}
+			return nil
+		})
+		kafkaWriter.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+			var event models.Transaction
+			assert.NoError(t, json.Unmarshal(msgs[0].Value, &event))
+			assert.Equal(t, models.OperationDeposit, event.Operation)
+			assert.Equal(t, userID.String(), event.UserID)
+			assert.Equal(t, models.USD, event.Currency)
+			if assert.NotNil(t, event.Balance) {
+				assert.Equal(t, money.MustParse("150"), *event.Balance)
+			}
+			return nil
+		})
+
+		svc := NewWalletService(writer, reader, nil, nil, kafkaWriter, WithTransactionHistory(history, tx), WithPaymentConfirmations(store, tx))
+		credited, err := svc.CreditPayment(ctx, "pay-1", payment)
+		assert.NoError(t, err)
+		assert.True(t, credited)
+	})
+
+	t.Run("redelivered payment is not credited again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockProcessedMessageStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+
+		inTx(tx)
+		store.EXPECT().MarkProcessed(ctx, PaymentConfirmationConsumer, "pay-1").Return(false, nil)
+
+		svc := NewWalletService(NewMockWalletWriter(ctrl), nil, nil, nil, NewMockEventPublisher(ctrl), WithPaymentConfirmations(store, tx))
+		credited, err := svc.CreditPayment(ctx, "pay-1", payment)
+		assert.NoError(t, err)
+		assert.False(t, credited)
+	})
+
+	t.Run("retried transaction finds the payment credited concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockProcessedMessageStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+
+		// The first attempt is aborted by a serialization failure, the retry finds the payment marked
+		tx.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			if err := fn(ctx); err == nil {
+				return errors.New("unexpected success")
+			}
+			return fn(ctx)
+		})
+		gomock.InOrder(
+			store.EXPECT().MarkProcessed(ctx, PaymentConfirmationConsumer, "pay-1").Return(true, nil),
+			store.EXPECT().MarkProcessed(ctx, PaymentConfirmationConsumer, "pay-1").Return(false, nil),
+		)
+		writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, amount, models.USD).Return(errors.New("serialization failure"))
+
+		svc := NewWalletService(writer, nil, nil, nil, NewMockEventPublisher(ctrl), WithPaymentConfirmations(store, tx))
+		credited, err := svc.CreditPayment(ctx, "pay-1", payment)
+		assert.NoError(t, err)
+		assert.False(t, credited)
+	})
+
+	t.Run("failed credit is rolled back", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockProcessedMessageStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+
+		inTx(tx)
+		store.EXPECT().MarkProcessed(ctx, PaymentConfirmationConsumer, "pay-1").Return(true, nil)
+		writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, amount, models.USD).Return(errors.New("db error"))
+
+		svc := NewWalletService(writer, nil, nil, nil, NewMockEventPublisher(ctrl), WithPaymentConfirmations(store, tx))
+		credited, err := svc.CreditPayment(ctx, "pay-1", payment)
+		assert.Error(t, err)
+		assert.False(t, credited)
+	})
+
+	t.Run("invalid confirmations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		currencies := NewMockCurrencyLister(ctrl)
+		currencies.EXPECT().Codes(ctx).Return([]string{models.USD, models.EUR}).AnyTimes()
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithCurrencies(currencies),
+			WithPaymentConfirmations(NewMockProcessedMessageStore(ctrl), NewMockTransactor(ctrl)))
+
+		for name, tc := range map[string]struct {
+			paymentID string
+			payment   models.PaymentConfirmation
+		}{
+			"no payment ID":        {"", payment},
+			"no user":              {"pay-1", models.PaymentConfirmation{Currency: models.USD, Amount: amount}},
+			"unsupported currency": {"pay-1", models.PaymentConfirmation{UserID: userID, Currency: "GBP", Amount: amount}},
+			"non-positive amount":  {"pay-1", models.PaymentConfirmation{UserID: userID, Currency: models.USD, Amount: money.Zero}},
+		} {
+			_, err := svc.CreditPayment(ctx, tc.paymentID, tc.payment)
+			assert.ErrorIs(t, err, ErrInvalidPaymentConfirmation, name)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := NewWalletService(nil, nil, nil, nil, nil).CreditPayment(ctx, "pay-1", payment)
+		assert.ErrorIs(t, err, ErrPaymentConfirmationsDisabled)
+	})
+}
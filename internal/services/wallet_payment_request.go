@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// PaymentRequestTTL is how long a payment request can be accepted or declined.
+const PaymentRequestTTL = 7 * 24 * time.Hour
+
+// paymentRequestListLimit is the number of latest payment requests listed.
+const paymentRequestListLimit = 100
+
+var (
+	// ErrPaymentRequestsDisabled is returned by payment request operations of a service created without WithPaymentRequests.
+	ErrPaymentRequestsDisabled = errors.New("payment requests disabled")
+	// ErrInvalidPayer is returned when a user requests money from themselves.
+	ErrInvalidPayer = errors.New("invalid payer")
+	// ErrPaymentRequestNotFound is returned when the user has no payment request with the requested ID to act on.
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	// ErrPaymentRequestNotPending is returned when accepting or declining a request that is already accepted or declined.
+	ErrPaymentRequestNotPending = errors.New("payment request not pending")
+	// ErrPaymentRequestExpired is returned when accepting or declining a request past its expiry.
+	ErrPaymentRequestExpired = errors.New("payment request expired")
+)
+
+// PaymentRequestStore persists payment requests and transfers the money of accepted ones.
+type PaymentRequestStore interface {
+	Create(ctx context.Context, request models.PaymentRequestDB) (models.PaymentRequestDB, error)             // Saves a pending request
+	Get(ctx context.Context, userID, requestID uuid.UUID) (models.PaymentRequestDB, error)                    // Returns a request the user made or was asked to pay
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]models.PaymentRequestDB, error)         // Returns the latest requests of the user, newest first
+	Accept(ctx context.Context, transactionID, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) // Transfers the amount; sql.ErrNoRows if not pending or funds are insufficient
+	Decline(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error)               // Declines a pending request; sql.ErrNoRows if there is none
+	Expire(ctx context.Context) ([]models.PaymentRequestDB, error)                                            // Expires the pending requests past their expiry
+}
+
+// WithPaymentRequests lets users request money from each other. The payer accepts a request,
+// transferring the amount, or declines it; unanswered requests expire after ttl.
+// users resolves the payer by username.
+func WithPaymentRequests(store PaymentRequestStore, users UserReader, ttl time.Duration) WalletOpt {
+	return func(s *WalletService) {
+		s.paymentRequests = store
+		s.users = users
+		s.paymentRequestTTL = ttl
+	}
+}
+
+// CreatePaymentRequest asks the user with payerUsername to pay amount to the requester and
+// notifies the payer.
+func (s *WalletService) CreatePaymentRequest(
+	ctx context.Context,
+	requesterID uuid.UUID,
+	payerUsername string,
+	amount money.Amount,
+	currency, note string,
+) (models.PaymentRequestDB, error) {
+	if s.paymentRequests == nil {
+		return models.PaymentRequestDB{}, ErrPaymentRequestsDisabled
+	}
+
+	payer, err := s.users.GetByUsernameOrEmail(ctx, &payerUsername, nil)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && payer == nil) {
+		return models.PaymentRequestDB{}, ErrUserDoesNotExist
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get payer", "username", payerUsername, "error", err)
+		return models.PaymentRequestDB{}, err
+	}
+	if payer.UserID == requesterID {
+		return models.PaymentRequestDB{}, ErrInvalidPayer
+	}
+
+	request, err := s.paymentRequests.Create(ctx, models.PaymentRequestDB{
+		RequestID:   uuid.New(),
+		RequesterID: requesterID,
+		PayerID:     payer.UserID,
+		Currency:    currency,
+		Amount:      amount,
+		Note:        optionalReference(note),
+		ExpiresAt:   time.Now().UTC().Add(s.paymentRequestTTL),
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to create payment request", "requesterID", requesterID, "payerID", payer.UserID, "error", err)
+		return models.PaymentRequestDB{}, err
+	}
+
+	logger.FromContext(ctx).Infow("payment request created", "request_id", request.RequestID, "requesterID", requesterID, "payerID", payer.UserID)
+	s.notifyPaymentRequest(ctx, models.WebhookEventPaymentRequestCreated, request.PayerID, request.RequesterID, request)
+	return request, nil
+}
+
+// ListPaymentRequests returns the latest payment requests the user made or was asked to pay.
+// Without WithPaymentRequests, the result is empty.
+func (s *WalletService) ListPaymentRequests(ctx context.Context, userID uuid.UUID) ([]models.PaymentRequestDB, error) {
+	if s.paymentRequests == nil {
+		return []models.PaymentRequestDB{}, nil
+	}
+	requests, err := s.paymentRequests.ListByUserID(ctx, userID, paymentRequestListLimit)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list payment requests", "userID", userID, "error", err)
+		return nil, err
+	}
+	return requests, nil
+}
+
+// AcceptPaymentRequest pays the request from the payer's wallet to the requester's. The
+// payment counts against the payer's limits like a withdrawal; both sides get a history
+// entry and the requester is notified.
+func (s *WalletService) AcceptPaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	if s.paymentRequests == nil {
+		return models.PaymentRequestDB{}, ErrPaymentRequestsDisabled
+	}
+
+	request, err := s.pendingPaymentRequest(ctx, payerID, requestID)
+	if err != nil {
+		return models.PaymentRequestDB{}, err
+	}
+
+	usageID, err := s.reserveLimit(ctx, payerID, request.Currency, request.Amount)
+	if err != nil {
+		return models.PaymentRequestDB{}, err
+	}
+
+	txnID := uuid.New()
+	creditID := uuid.New()
+	var accepted models.PaymentRequestDB
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		if err := s.lockWallets(ctx,
+			models.WalletKey{UserID: payerID, Currency: request.Currency},
+			models.WalletKey{UserID: request.RequesterID, Currency: request.Currency},
+		); err != nil {
+			return err
+		}
+		var err error
+		if accepted, err = s.paymentRequests.Accept(ctx, txnID, payerID, requestID); err != nil {
+			return err
+		}
+		if err := s.recordTransaction(ctx, models.TransactionDB{
+			TransactionID: txnID,
+			UserID:        payerID,
+			Operation:     models.OperationTransferOut,
+			Currency:      accepted.Currency,
+			Amount:        accepted.Amount,
+		}); err != nil {
+			return err
+		}
+		return s.recordTransaction(ctx, models.TransactionDB{
+			TransactionID: creditID,
+			UserID:        accepted.RequesterID,
+			Operation:     models.OperationTransferIn,
+			Currency:      accepted.Currency,
+			Amount:        accepted.Amount,
+		})
+	})
+	if err != nil {
+		s.releaseLimit(ctx, usageID)
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Errorw("failed to accept payment request", "request_id", requestID, "payerID", payerID, "error", err)
+			return models.PaymentRequestDB{}, err
+		}
+		// Either the request changed since it was read or the payer is short of money
+		if _, err := s.pendingPaymentRequest(ctx, payerID, requestID); err != nil {
+			return models.PaymentRequestDB{}, err
+		}
+		return models.PaymentRequestDB{}, ErrInsufficientFunds
+	}
+
+	now := time.Now().Unix()
+	payerBalances := s.eventBalances(ctx, payerID, nil)
+	requesterBalances := s.eventBalances(ctx, accepted.RequesterID, nil)
+	s.publishTransaction(ctx, accepted.Currency, models.Transaction{
+		TransactionID: txnID.String(),
+		Timestamp:     now,
+		Amount:        accepted.Amount,
+		UserID:        payerID.String(),
+		Operation:     models.OperationTransferOut,
+		Balance:       balanceOf(payerBalances, accepted.Currency),
+	})
+	s.publishTransaction(ctx, accepted.Currency, models.Transaction{
+		TransactionID: creditID.String(),
+		Timestamp:     now,
+		Amount:        accepted.Amount,
+		UserID:        accepted.RequesterID.String(),
+		Operation:     models.OperationTransferIn,
+		Balance:       balanceOf(requesterBalances, accepted.Currency),
+	})
+	s.publishBalances(ctx, txnID, payerID, payerBalances, accepted.Currency)
+	s.publishBalances(ctx, creditID, accepted.RequesterID, requesterBalances, accepted.Currency)
+
+	logger.FromContext(ctx).Infow("payment request accepted", "request_id", requestID, "payerID", payerID, "transaction_id", txnID)
+	s.notifyPaymentRequest(ctx, models.WebhookEventPaymentRequestAccepted, accepted.RequesterID, payerID, accepted)
+	return accepted, nil
+}
+
+// DeclinePaymentRequest declines the request without paying it and notifies the requester.
+func (s *WalletService) DeclinePaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	if s.paymentRequests == nil {
+		return models.PaymentRequestDB{}, ErrPaymentRequestsDisabled
+	}
+
+	declined, err := s.paymentRequests.Decline(ctx, payerID, requestID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Errorw("failed to decline payment request", "request_id", requestID, "payerID", payerID, "error", err)
+			return models.PaymentRequestDB{}, err
+		}
+		if _, err := s.pendingPaymentRequest(ctx, payerID, requestID); err != nil {
+			return models.PaymentRequestDB{}, err
+		}
+		return models.PaymentRequestDB{}, ErrPaymentRequestNotPending
+	}
+
+	logger.FromContext(ctx).Infow("payment request declined", "request_id", requestID, "payerID", payerID)
+	s.notifyPaymentRequest(ctx, models.WebhookEventPaymentRequestDeclined, declined.RequesterID, payerID, declined)
+	return declined, nil
+}
+
+// ExpirePaymentRequests expires the pending payment requests past their expiry and notifies
+// their requesters. It is run periodically as a background job.
+func (s *WalletService) ExpirePaymentRequests(ctx context.Context) error {
+	if s.paymentRequests == nil {
+		return nil
+	}
+
+	expired, err := s.paymentRequests.Expire(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to expire payment requests", "error", err)
+		return err
+	}
+	for _, request := range expired {
+		s.notifyPaymentRequest(ctx, models.WebhookEventPaymentRequestExpired, request.RequesterID, request.PayerID, request)
+	}
+	if len(expired) > 0 {
+		logger.FromContext(ctx).Infow("payment requests expired", "count", len(expired))
+	}
+	return nil
+}
+
+// pendingPaymentRequest returns the request if the user is asked to pay it and it is still
+// pending, or the error telling why it cannot be accepted or declined. A pending request past
+// its expiry is expired even before ExpirePaymentRequests marks it.
+func (s *WalletService) pendingPaymentRequest(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	request, err := s.paymentRequests.Get(ctx, payerID, requestID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && request.PayerID != payerID) {
+		return models.PaymentRequestDB{}, ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get payment request", "request_id", requestID, "payerID", payerID, "error", err)
+		return models.PaymentRequestDB{}, err
+	}
+
+	switch {
+	case request.Status == models.PaymentRequestStatusPending && request.ExpiresAt.After(time.Now()):
+		return request, nil
+	case request.Status == models.PaymentRequestStatusPending, request.Status == models.PaymentRequestStatusExpired:
+		return models.PaymentRequestDB{}, ErrPaymentRequestExpired
+	default:
+		return models.PaymentRequestDB{}, ErrPaymentRequestNotPending
+	}
+}
+
+// notifyPaymentRequest queues a payment request event for the webhooks of userID.
+func (s *WalletService) notifyPaymentRequest(ctx context.Context, eventType string, userID, counterpartyID uuid.UUID, request models.PaymentRequestDB) {
+	if s.webhooks == nil {
+		return
+	}
+	event := models.WebhookEvent{
+		Type:             eventType,
+		PaymentRequestID: &request.RequestID,
+		CounterpartyID:   &counterpartyID,
+		UserID:           userID,
+		Currency:         request.Currency,
+		Amount:           request.Amount,
+		OccurredAt:       time.Now().UTC(),
+	}
+	if request.TransactionID != nil {
+		event.TransactionID = *request.TransactionID
+	}
+	if err := s.webhooks.Notify(ctx, event); err != nil {
+		logger.FromContext(ctx).Errorw("failed to notify webhooks", "type", eventType, "request_id", request.RequestID, "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_payment_request.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockPaymentRequestStore is a mock of PaymentRequestStore interface.
+type MockPaymentRequestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRequestStoreMockRecorder
+}
+
+// MockPaymentRequestStoreMockRecorder is the mock recorder for MockPaymentRequestStore.
+type MockPaymentRequestStoreMockRecorder struct {
+	mock *MockPaymentRequestStore
+}
+
+// NewMockPaymentRequestStore creates a new mock instance.
+func NewMockPaymentRequestStore(ctrl *gomock.Controller) *MockPaymentRequestStore {
+	mock := &MockPaymentRequestStore{ctrl: ctrl}
+	mock.recorder = &MockPaymentRequestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRequestStore) EXPECT() *MockPaymentRequestStoreMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockPaymentRequestStore) Accept(ctx context.Context, transactionID, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, transactionID, payerID, requestID)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockPaymentRequestStoreMockRecorder) Accept(ctx, transactionID, payerID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockPaymentRequestStore)(nil).Accept), ctx, transactionID, payerID, requestID)
+}
+
+// Create mocks base method.
+func (m *MockPaymentRequestStore) Create(ctx context.Context, request models.PaymentRequestDB) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentRequestStoreMockRecorder) Create(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentRequestStore)(nil).Create), ctx, request)
+}
+
+// Decline mocks base method.
+func (m *MockPaymentRequestStore) Decline(ctx context.Context, payerID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", ctx, payerID, requestID)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockPaymentRequestStoreMockRecorder) Decline(ctx, payerID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockPaymentRequestStore)(nil).Decline), ctx, payerID, requestID)
+}
+
+// Expire mocks base method.
+func (m *MockPaymentRequestStore) Expire(ctx context.Context) ([]models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx)
+	ret0, _ := ret[0].([]models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockPaymentRequestStoreMockRecorder) Expire(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockPaymentRequestStore)(nil).Expire), ctx)
+}
+
+// Get mocks base method.
+func (m *MockPaymentRequestStore) Get(ctx context.Context, userID, requestID uuid.UUID) (models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, requestID)
+	ret0, _ := ret[0].(models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPaymentRequestStoreMockRecorder) Get(ctx, userID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPaymentRequestStore)(nil).Get), ctx, userID, requestID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockPaymentRequestStore) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]models.PaymentRequestDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID, limit)
+	ret0, _ := ret[0].([]models.PaymentRequestDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockPaymentRequestStoreMockRecorder) ListByUserID(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockPaymentRequestStore)(nil).ListByUserID), ctx, userID, limit)
+}
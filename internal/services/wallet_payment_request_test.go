@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_CreatePaymentRequest(t *testing.T) {
+	ctx := context.Background()
+	requesterID, payerID := uuid.New(), uuid.New()
+	amount := money.MustParse("25")
+	username := "bob"
+
+	t.Run("success notifies the payer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		users := NewMockUserReader(ctrl)
+		webhooks := NewMockWebhookNotifier(ctrl)
+
+		users.EXPECT().GetByUsernameOrEmail(ctx, &username, nil).Return(&models.UserDB{UserID: payerID}, nil)
+		store.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, request models.PaymentRequestDB) (models.PaymentRequestDB, error) {
+			assert.NotEqual(t, uuid.Nil, request.RequestID)
+			assert.Equal(t, requesterID, request.RequesterID)
+			assert.Equal(t, payerID, request.PayerID)
+			assert.Equal(t, amount, request.Amount)
+			if assert.NotNil(t, request.Note) {
+				assert.Equal(t, "dinner", *request.Note)
+			}
+			assert.WithinDuration(t, time.Now().Add(time.Hour), request.ExpiresAt, time.Minute)
+			request.Status = models.PaymentRequestStatusPending
+			return request, nil
+		})
+		webhooks.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event models.WebhookEvent) error {
+			assert.Equal(t, models.WebhookEventPaymentRequestCreated, event.Type)
+			assert.Equal(t, payerID, event.UserID)
+			assert.Equal(t, requesterID, *event.CounterpartyID)
+			assert.Equal(t, uuid.Nil, event.TransactionID)
+			return nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, users, time.Hour), WithWebhooks(webhooks))
+		request, err := svc.CreatePaymentRequest(ctx, requesterID, username, amount, models.USD, "dinner")
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusPending, request.Status)
+	})
+
+	t.Run("unknown payer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		users := NewMockUserReader(ctrl)
+		users.EXPECT().GetByUsernameOrEmail(ctx, &username, nil).Return(nil, sql.ErrNoRows)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(NewMockPaymentRequestStore(ctrl), users, time.Hour))
+		_, err := svc.CreatePaymentRequest(ctx, requesterID, username, amount, models.USD, "")
+		assert.ErrorIs(t, err, ErrUserDoesNotExist)
+	})
+
+	t.Run("requesting from oneself", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		users := NewMockUserReader(ctrl)
+		users.EXPECT().GetByUsernameOrEmail(ctx, &username, nil).Return(&models.UserDB{UserID: requesterID}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(NewMockPaymentRequestStore(ctrl), users, time.Hour))
+		_, err := svc.CreatePaymentRequest(ctx, requesterID, username, amount, models.USD, "")
+		assert.ErrorIs(t, err, ErrInvalidPayer)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		_, err := svc.CreatePaymentRequest(ctx, requesterID, username, amount, models.USD, "")
+		assert.ErrorIs(t, err, ErrPaymentRequestsDisabled)
+	})
+}
+
+func TestWalletService_AcceptPaymentRequest(t *testing.T) {
+	ctx := context.Background()
+	requesterID, payerID, requestID := uuid.New(), uuid.New(), uuid.New()
+	amount := money.MustParse("25")
+
+	pending := models.PaymentRequestDB{
+		RequestID:   requestID,
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Currency:    models.USD,
+		Amount:      amount,
+		Status:      models.PaymentRequestStatusPending,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	pastExpiry := models.PaymentRequestDB{PayerID: payerID, Status: models.PaymentRequestStatusPending, ExpiresAt: time.Now().Add(-time.Minute)}
+
+	t.Run("success transfers and records both sides", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+		history := NewMockTransactionStore(ctrl)
+		webhooks := NewMockWebhookNotifier(ctrl)
+
+		store.EXPECT().Get(ctx, payerID, requestID).Return(pending, nil)
+		limiter.EXPECT().Reserve(ctx, payerID, models.USD, amount).Return(int64(7), "", nil)
+		var txnID uuid.UUID
+		store.EXPECT().Accept(ctx, gomock.Any(), payerID, requestID).DoAndReturn(func(_ context.Context, transactionID, _, _ uuid.UUID) (models.PaymentRequestDB, error) {
+			txnID = transactionID
+			accepted := pending
+			accepted.Status = models.PaymentRequestStatusAccepted
+			accepted.TransactionID = &transactionID
+			return accepted, nil
+		})
+		var operations []string
+		history.EXPECT().Save(ctx, gomock.Any()).Times(2).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			operations = append(operations, txn.Operation+" "+txn.UserID.String())
+			return nil
+		})
+		webhooks.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event models.WebhookEvent) error {
+			assert.Equal(t, models.WebhookEventPaymentRequestAccepted, event.Type)
+			assert.Equal(t, requesterID, event.UserID)
+			assert.Equal(t, txnID, event.TransactionID)
+			return nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil,
+			WithPaymentRequests(store, nil, time.Hour), WithSpendingLimits(limiter),
+			WithTransactionHistory(history, directTx{}), WithWebhooks(webhooks))
+		accepted, err := svc.AcceptPaymentRequest(ctx, payerID, requestID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusAccepted, accepted.Status)
+		assert.Equal(t, []string{
+			models.OperationTransferOut + " " + payerID.String(),
+			models.OperationTransferIn + " " + requesterID.String(),
+		}, operations)
+	})
+
+	t.Run("locks both wallets before the transfer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		locker := NewMockWalletLocker(ctrl)
+		history := NewMockTransactionStore(ctrl)
+
+		store.EXPECT().Get(ctx, payerID, requestID).Return(pending, nil)
+		gomock.InOrder(
+			locker.EXPECT().Lock(ctx, []models.WalletKey{
+				{UserID: payerID, Currency: models.USD},
+				{UserID: requesterID, Currency: models.USD},
+			}).Return(nil),
+			store.EXPECT().Accept(ctx, gomock.Any(), payerID, requestID).Return(pending, nil),
+		)
+		history.EXPECT().Save(ctx, gomock.Any()).Times(2).Return(nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil,
+			WithPaymentRequests(store, nil, time.Hour), WithWalletLocks(locker),
+			WithTransactionHistory(history, directTx{}))
+		_, err := svc.AcceptPaymentRequest(ctx, payerID, requestID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("insufficient funds releases the usage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		store.EXPECT().Get(ctx, payerID, requestID).Return(pending, nil).Times(2)
+		limiter.EXPECT().Reserve(ctx, payerID, models.USD, amount).Return(int64(8), "", nil)
+		store.EXPECT().Accept(ctx, gomock.Any(), payerID, requestID).Return(models.PaymentRequestDB{}, sql.ErrNoRows)
+		limiter.EXPECT().Release(ctx, int64(8)).Return(nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour), WithSpendingLimits(limiter))
+		_, err := svc.AcceptPaymentRequest(ctx, payerID, requestID)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("expired before the transfer", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		gomock.InOrder(
+			store.EXPECT().Get(ctx, payerID, requestID).Return(pending, nil),
+			store.EXPECT().Get(ctx, payerID, requestID).Return(pastExpiry, nil),
+		)
+		limiter.EXPECT().Reserve(ctx, payerID, models.USD, amount).Return(int64(8), "", nil)
+		store.EXPECT().Accept(ctx, gomock.Any(), payerID, requestID).Return(models.PaymentRequestDB{}, sql.ErrNoRows)
+		limiter.EXPECT().Release(ctx, int64(8)).Return(nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour), WithSpendingLimits(limiter))
+		_, err := svc.AcceptPaymentRequest(ctx, payerID, requestID)
+		assert.ErrorIs(t, err, ErrPaymentRequestExpired)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		request  models.PaymentRequestDB
+		err      error
+		expected error
+	}{
+		{name: "not found", err: sql.ErrNoRows, expected: ErrPaymentRequestNotFound},
+		{name: "requester cannot accept", request: models.PaymentRequestDB{PayerID: requesterID, Status: models.PaymentRequestStatusPending}, expected: ErrPaymentRequestNotFound},
+		{name: "expired", request: models.PaymentRequestDB{PayerID: payerID, Status: models.PaymentRequestStatusExpired}, expected: ErrPaymentRequestExpired},
+		{name: "pending past its expiry", request: pastExpiry, expected: ErrPaymentRequestExpired},
+		{name: "already declined", request: models.PaymentRequestDB{PayerID: payerID, Status: models.PaymentRequestStatusDeclined}, expected: ErrPaymentRequestNotPending},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := NewMockPaymentRequestStore(ctrl)
+			store.EXPECT().Get(ctx, payerID, requestID).Return(tc.request, tc.err)
+
+			svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour))
+			_, err := svc.AcceptPaymentRequest(ctx, payerID, requestID)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestWalletService_DeclinePaymentRequest(t *testing.T) {
+	ctx := context.Background()
+	requesterID, payerID, requestID := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success notifies the requester", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		webhooks := NewMockWebhookNotifier(ctrl)
+
+		store.EXPECT().Decline(ctx, payerID, requestID).Return(models.PaymentRequestDB{
+			RequestID: requestID, RequesterID: requesterID, PayerID: payerID, Status: models.PaymentRequestStatusDeclined,
+		}, nil)
+		webhooks.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event models.WebhookEvent) error {
+			assert.Equal(t, models.WebhookEventPaymentRequestDeclined, event.Type)
+			assert.Equal(t, requesterID, event.UserID)
+			assert.Equal(t, payerID, *event.CounterpartyID)
+			return nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour), WithWebhooks(webhooks))
+		declined, err := svc.DeclinePaymentRequest(ctx, payerID, requestID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PaymentRequestStatusDeclined, declined.Status)
+	})
+
+	t.Run("already accepted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		store.EXPECT().Decline(ctx, payerID, requestID).Return(models.PaymentRequestDB{}, sql.ErrNoRows)
+		store.EXPECT().Get(ctx, payerID, requestID).Return(models.PaymentRequestDB{PayerID: payerID, Status: models.PaymentRequestStatusAccepted}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour))
+		_, err := svc.DeclinePaymentRequest(ctx, payerID, requestID)
+		assert.ErrorIs(t, err, ErrPaymentRequestNotPending)
+	})
+
+	t.Run("pending past its expiry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		store.EXPECT().Decline(ctx, payerID, requestID).Return(models.PaymentRequestDB{}, sql.ErrNoRows)
+		store.EXPECT().Get(ctx, payerID, requestID).Return(models.PaymentRequestDB{
+			PayerID: payerID, Status: models.PaymentRequestStatusPending, ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour))
+		_, err := svc.DeclinePaymentRequest(ctx, payerID, requestID)
+		assert.ErrorIs(t, err, ErrPaymentRequestExpired)
+	})
+}
+
+func TestWalletService_ExpirePaymentRequests(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("notifies the requesters", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		webhooks := NewMockWebhookNotifier(ctrl)
+
+		expired := []models.PaymentRequestDB{
+			{RequestID: uuid.New(), RequesterID: uuid.New(), PayerID: uuid.New(), Status: models.PaymentRequestStatusExpired},
+			{RequestID: uuid.New(), RequesterID: uuid.New(), PayerID: uuid.New(), Status: models.PaymentRequestStatusExpired},
+		}
+		store.EXPECT().Expire(ctx).Return(expired, nil)
+		for _, request := range expired {
+			webhooks.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event models.WebhookEvent) error {
+				assert.Equal(t, models.WebhookEventPaymentRequestExpired, event.Type)
+				assert.Equal(t, request.RequesterID, event.UserID)
+				return nil
+			})
+		}
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour), WithWebhooks(webhooks))
+		assert.NoError(t, svc.ExpirePaymentRequests(ctx))
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockPaymentRequestStore(ctrl)
+		store.EXPECT().Expire(ctx).Return(nil, errors.New("db error"))
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPaymentRequests(store, nil, time.Hour))
+		assert.Error(t, svc.ExpirePaymentRequests(ctx))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, NewWalletService(nil, nil, nil, nil, nil).ExpirePaymentRequests(ctx))
+	})
+}
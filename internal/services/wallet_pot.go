@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+var (
+	// ErrPotsDisabled is returned by pot operations of a service created without WithPots.
+	ErrPotsDisabled = errors.New("pots disabled")
+	// ErrPotNotFound is returned when the user has no pot with the requested ID in the wallet.
+	ErrPotNotFound = errors.New("pot not found")
+	// ErrPotNameTaken is returned when the wallet already has a pot with the requested name.
+	ErrPotNameTaken = errors.New("pot name taken")
+	// ErrInvalidPotName is returned when a pot name is blank or too long.
+	ErrInvalidPotName = errors.New("invalid pot name")
+	// ErrInvalidPotMove is returned when money is moved from a pot to itself.
+	ErrInvalidPotMove = errors.New("invalid pot move")
+)
+
+// WalletPotStore persists savings pots together with the pocketed and saved amounts of the wallets.
+type WalletPotStore interface {
+	Create(ctx context.Context, pot models.WalletPotDB) (models.WalletPotDB, error)                                        // Adds an empty pot; sql.ErrNoRows if there is no wallet or the name is taken
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletPotDB, error)                                      // Returns the pots of all wallets of the user
+	Get(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error)                                          // Returns a pot of the user
+	SetSpendable(ctx context.Context, userID, potID uuid.UUID, spendable bool) (models.WalletPotDB, error)                 // Includes or excludes a pot from the spendable balance
+	Move(ctx context.Context, userID uuid.UUID, currency string, fromPotID, toPotID *uuid.UUID, amount money.Amount) error // Moves money between pots, nil for the unallocated balance
+	Delete(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error)                                       // Removes a pot, returning its balance to the wallet
+	SavedByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error)                                  // Returns the amounts in pots excluded from spending by currency
+}
+
+// WithPots lets users set parts of their wallet balances aside in named pots. Pots
+// excluded from the spendable balance cannot be withdrawn, exchanged, held or paid out;
+// spendable pots are drawn down when spending reaches them.
+func WithPots(store WalletPotStore) WalletOpt {
+	return func(s *WalletService) {
+		s.pots = store
+	}
+}
+
+// CreatePot adds an empty pot named name to the user's wallet in currency.
+func (s *WalletService) CreatePot(ctx context.Context, userID uuid.UUID, currency, name string, spendable bool) (models.WalletPotDB, error) {
+	if s.pots == nil {
+		return models.WalletPotDB{}, ErrPotsDisabled
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > models.MaxPotNameLength {
+		return models.WalletPotDB{}, ErrInvalidPotName
+	}
+
+	pot, err := s.pots.Create(ctx, models.WalletPotDB{
+		PotID:     uuid.New(),
+		UserID:    userID,
+		Currency:  currency,
+		Name:      name,
+		Spendable: spendable,
+	})
+	if err == nil {
+		logger.FromContext(ctx).Infow("pot created", "pot_id", pot.PotID, "userID", userID, "currency", currency)
+		return pot, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		logger.FromContext(ctx).Errorw("failed to create pot", "userID", userID, "currency", currency, "error", err)
+		return models.WalletPotDB{}, err
+	}
+
+	// Either the wallet is missing or the name is taken
+	balances, err := s.readRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get balances after creating pot", "userID", userID, "error", err)
+		return models.WalletPotDB{}, err
+	}
+	if _, ok := balances[currency]; !ok {
+		return models.WalletPotDB{}, ErrWalletNotFound
+	}
+	return models.WalletPotDB{}, ErrPotNameTaken
+}
+
+// ListPots returns the pots of all wallets of the user. Without WithPots, the result is empty.
+func (s *WalletService) ListPots(ctx context.Context, userID uuid.UUID) ([]models.WalletPotDB, error) {
+	if s.pots == nil {
+		return []models.WalletPotDB{}, nil
+	}
+	pots, err := s.pots.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list pots", "userID", userID, "error", err)
+		return nil, err
+	}
+	if pots == nil {
+		pots = []models.WalletPotDB{}
+	}
+	return pots, nil
+}
+
+// SetPotSpendable includes the pot in or excludes it from the spendable balance.
+func (s *WalletService) SetPotSpendable(ctx context.Context, userID, potID uuid.UUID, spendable bool) (models.WalletPotDB, error) {
+	if s.pots == nil {
+		return models.WalletPotDB{}, ErrPotsDisabled
+	}
+	pot, err := s.pots.SetSpendable(ctx, userID, potID, spendable)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WalletPotDB{}, ErrPotNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to update pot", "pot_id", potID, "userID", userID, "error", err)
+		return models.WalletPotDB{}, err
+	}
+	return pot, nil
+}
+
+// DeletePot removes the pot, returning its balance to the unallocated balance of the wallet.
+func (s *WalletService) DeletePot(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error) {
+	if s.pots == nil {
+		return models.WalletPotDB{}, ErrPotsDisabled
+	}
+	pot, err := s.pots.Delete(ctx, userID, potID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WalletPotDB{}, ErrPotNotFound
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to delete pot", "pot_id", potID, "userID", userID, "error", err)
+		return models.WalletPotDB{}, err
+	}
+	logger.FromContext(ctx).Infow("pot deleted", "pot_id", potID, "userID", userID, "balance", pot.Balance)
+	return pot, nil
+}
+
+// MovePotMoney moves amount between pots of the user's wallet in currency. A nil pot ID
+// stands for the wallet's unallocated balance. The wallet balance does not change, so the
+// move is neither published nor recorded in the transaction history.
+// Returns the pots of the user after the move.
+func (s *WalletService) MovePotMoney(
+	ctx context.Context,
+	userID uuid.UUID,
+	currency string,
+	fromPotID, toPotID *uuid.UUID,
+	amount money.Amount,
+) ([]models.WalletPotDB, error) {
+	if s.pots == nil {
+		return nil, ErrPotsDisabled
+	}
+	if fromPotID == nil && toPotID == nil || fromPotID != nil && toPotID != nil && *fromPotID == *toPotID {
+		return nil, ErrInvalidPotMove
+	}
+
+	if err := s.pots.Move(ctx, userID, currency, fromPotID, toPotID, amount); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Errorw("failed to move pot money", "userID", userID, "currency", currency, "error", err)
+			return nil, err
+		}
+		return nil, s.potMoveError(ctx, userID, currency, fromPotID, toPotID)
+	}
+
+	logger.FromContext(ctx).Infow("pot money moved", "userID", userID, "currency", currency, "from", fromPotID, "to", toPotID, "amount", amount)
+	return s.ListPots(ctx, userID)
+}
+
+// potMoveError tells apart missing pots from insufficient funds after a move failed.
+func (s *WalletService) potMoveError(ctx context.Context, userID uuid.UUID, currency string, potIDs ...*uuid.UUID) error {
+	for _, potID := range potIDs {
+		if potID == nil {
+			continue
+		}
+		pot, err := s.pots.Get(ctx, userID, *potID)
+		if errors.Is(err, sql.ErrNoRows) || err == nil && pot.Currency != currency {
+			return ErrPotNotFound
+		}
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to get pot", "pot_id", *potID, "userID", userID, "error", err)
+			return err
+		}
+	}
+	return ErrInsufficientFunds
+}
@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_pot.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	money "github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// MockWalletPotStore is a mock of WalletPotStore interface.
+type MockWalletPotStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletPotStoreMockRecorder
+}
+
+// MockWalletPotStoreMockRecorder is the mock recorder for MockWalletPotStore.
+type MockWalletPotStoreMockRecorder struct {
+	mock *MockWalletPotStore
+}
+
+// NewMockWalletPotStore creates a new mock instance.
+func NewMockWalletPotStore(ctrl *gomock.Controller) *MockWalletPotStore {
+	mock := &MockWalletPotStore{ctrl: ctrl}
+	mock.recorder = &MockWalletPotStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletPotStore) EXPECT() *MockWalletPotStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWalletPotStore) Create(ctx context.Context, pot models.WalletPotDB) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, pot)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletPotStoreMockRecorder) Create(ctx, pot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletPotStore)(nil).Create), ctx, pot)
+}
+
+// Delete mocks base method.
+func (m *MockWalletPotStore) Delete(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, potID)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWalletPotStoreMockRecorder) Delete(ctx, userID, potID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWalletPotStore)(nil).Delete), ctx, userID, potID)
+}
+
+// Get mocks base method.
+func (m *MockWalletPotStore) Get(ctx context.Context, userID, potID uuid.UUID) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, potID)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWalletPotStoreMockRecorder) Get(ctx, userID, potID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWalletPotStore)(nil).Get), ctx, userID, potID)
+}
+
+// ListByUserID mocks base method.
+func (m *MockWalletPotStore) ListByUserID(ctx context.Context, userID uuid.UUID) ([]models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUserID indicates an expected call of ListByUserID.
+func (mr *MockWalletPotStoreMockRecorder) ListByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockWalletPotStore)(nil).ListByUserID), ctx, userID)
+}
+
+// Move mocks base method.
+func (m *MockWalletPotStore) Move(ctx context.Context, userID uuid.UUID, currency string, fromPotID, toPotID *uuid.UUID, amount money.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, userID, currency, fromPotID, toPotID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockWalletPotStoreMockRecorder) Move(ctx, userID, currency, fromPotID, toPotID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockWalletPotStore)(nil).Move), ctx, userID, currency, fromPotID, toPotID, amount)
+}
+
+// SavedByUserID mocks base method.
+func (m *MockWalletPotStore) SavedByUserID(ctx context.Context, userID uuid.UUID) (map[string]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavedByUserID", ctx, userID)
+	ret0, _ := ret[0].(map[string]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavedByUserID indicates an expected call of SavedByUserID.
+func (mr *MockWalletPotStoreMockRecorder) SavedByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavedByUserID", reflect.TypeOf((*MockWalletPotStore)(nil).SavedByUserID), ctx, userID)
+}
+
+// SetSpendable mocks base method.
+func (m *MockWalletPotStore) SetSpendable(ctx context.Context, userID, potID uuid.UUID, spendable bool) (models.WalletPotDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpendable", ctx, userID, potID, spendable)
+	ret0, _ := ret[0].(models.WalletPotDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSpendable indicates an expected call of SetSpendable.
+func (mr *MockWalletPotStoreMockRecorder) SetSpendable(ctx, userID, potID, spendable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpendable", reflect.TypeOf((*MockWalletPotStore)(nil).SetSpendable), ctx, userID, potID, spendable)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_CreatePot(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pots := NewMockWalletPotStore(ctrl)
+
+		pots.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, pot models.WalletPotDB) (models.WalletPotDB, error) {
+			assert.NotEqual(t, uuid.Nil, pot.PotID)
+			assert.Equal(t, userID, pot.UserID)
+			assert.Equal(t, "holiday", pot.Name)
+			assert.False(t, pot.Spendable)
+			return pot, nil
+		})
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(pots))
+		pot, err := svc.CreatePot(ctx, userID, models.USD, "  holiday ", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "holiday", pot.Name)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(NewMockWalletPotStore(gomock.NewController(t))))
+		_, err := svc.CreatePot(ctx, userID, models.USD, " ", false)
+		assert.ErrorIs(t, err, ErrInvalidPotName)
+		_, err = svc.CreatePot(ctx, userID, models.USD, strings.Repeat("a", models.MaxPotNameLength+1), false)
+		assert.ErrorIs(t, err, ErrInvalidPotName)
+	})
+
+	t.Run("wallet not found or name taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pots := NewMockWalletPotStore(ctrl)
+		reader := NewMockWalletReader(ctrl)
+
+		pots.EXPECT().Create(ctx, gomock.Any()).Times(2).Return(models.WalletPotDB{}, sql.ErrNoRows)
+		reader.EXPECT().GetByUserID(ctx, userID).Times(2).Return(map[string]money.Amount{models.USD: money.MustParse("10")}, nil)
+
+		svc := NewWalletService(nil, reader, nil, nil, nil, WithPots(pots))
+		_, err := svc.CreatePot(ctx, userID, models.USD, "holiday", false)
+		assert.ErrorIs(t, err, ErrPotNameTaken)
+		_, err = svc.CreatePot(ctx, userID, models.EUR, "holiday", false)
+		assert.ErrorIs(t, err, ErrWalletNotFound)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		_, err := svc.CreatePot(ctx, userID, models.USD, "holiday", false)
+		assert.ErrorIs(t, err, ErrPotsDisabled)
+
+		pots, err := svc.ListPots(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, pots)
+	})
+}
+
+func TestWalletService_MovePotMoney(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	potID := uuid.New()
+	amount := money.MustParse("25")
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pots := NewMockWalletPotStore(ctrl)
+
+		pots.EXPECT().Move(ctx, userID, models.USD, nil, &potID, amount).Return(nil)
+		pots.EXPECT().ListByUserID(ctx, userID).Return([]models.WalletPotDB{{PotID: potID, Balance: amount}}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(pots))
+		got, err := svc.MovePotMoney(ctx, userID, models.USD, nil, &potID, amount)
+		assert.NoError(t, err)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, amount, got[0].Balance)
+		}
+	})
+
+	t.Run("invalid move", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(NewMockWalletPotStore(gomock.NewController(t))))
+		_, err := svc.MovePotMoney(ctx, userID, models.USD, nil, nil, amount)
+		assert.ErrorIs(t, err, ErrInvalidPotMove)
+		same := potID
+		_, err = svc.MovePotMoney(ctx, userID, models.USD, &potID, &same, amount)
+		assert.ErrorIs(t, err, ErrInvalidPotMove)
+	})
+
+	t.Run("pot not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pots := NewMockWalletPotStore(ctrl)
+
+		pots.EXPECT().Move(ctx, userID, models.EUR, &potID, nil, amount).Return(sql.ErrNoRows)
+		pots.EXPECT().Get(ctx, userID, potID).Return(models.WalletPotDB{PotID: potID, Currency: models.USD}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(pots))
+		_, err := svc.MovePotMoney(ctx, userID, models.EUR, &potID, nil, amount)
+		assert.ErrorIs(t, err, ErrPotNotFound)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pots := NewMockWalletPotStore(ctrl)
+
+		pots.EXPECT().Move(ctx, userID, models.USD, &potID, nil, amount).Return(sql.ErrNoRows)
+		pots.EXPECT().Get(ctx, userID, potID).Return(models.WalletPotDB{PotID: potID, Currency: models.USD}, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(pots))
+		_, err := svc.MovePotMoney(ctx, userID, models.USD, &potID, nil, amount)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pots := NewMockWalletPotStore(ctrl)
+
+		pots.EXPECT().Move(ctx, userID, models.USD, nil, &potID, amount).Return(errors.New("db error"))
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(pots))
+		_, err := svc.MovePotMoney(ctx, userID, models.USD, nil, &potID, amount)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestWalletService_SetPotSpendable_DeletePot(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	potID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	pots := NewMockWalletPotStore(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, nil, WithPots(pots))
+
+	pots.EXPECT().SetSpendable(ctx, userID, potID, true).Return(models.WalletPotDB{PotID: potID, Spendable: true}, nil)
+	pot, err := svc.SetPotSpendable(ctx, userID, potID, true)
+	assert.NoError(t, err)
+	assert.True(t, pot.Spendable)
+
+	pots.EXPECT().SetSpendable(ctx, userID, potID, false).Return(models.WalletPotDB{}, sql.ErrNoRows)
+	_, err = svc.SetPotSpendable(ctx, userID, potID, false)
+	assert.ErrorIs(t, err, ErrPotNotFound)
+
+	pots.EXPECT().Delete(ctx, userID, potID).Return(models.WalletPotDB{PotID: potID, Balance: money.MustParse("5")}, nil)
+	pot, err = svc.DeletePot(ctx, userID, potID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("5"), pot.Balance)
+
+	pots.EXPECT().Delete(ctx, userID, potID).Return(models.WalletPotDB{}, sql.ErrNoRows)
+	_, err = svc.DeletePot(ctx, userID, potID)
+	assert.ErrorIs(t, err, ErrPotNotFound)
+}
+
+func TestWalletService_GetUserAvailableBalance_Pots(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	reader := NewMockWalletReader(ctrl)
+	pots := NewMockWalletPotStore(ctrl)
+
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("100")}, nil)
+	pots.EXPECT().SavedByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("40")}, nil)
+
+	svc := NewWalletService(nil, reader, nil, nil, nil, WithPots(pots))
+	available, err := svc.GetUserAvailableBalance(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]money.Amount{models.USD: money.MustParse("60")}, available)
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// ExchangeQuoteTTL is how long a locked exchange quote can be executed.
+const ExchangeQuoteTTL = 30 * time.Second
+
+var (
+	// ErrQuoteExpired is returned when a locked quote has expired, was already used or
+	// belongs to another user.
+	ErrQuoteExpired = errors.New("quote expired")
+	// ErrQuoteMismatch is returned when the currencies or amount of an exchange differ from its quote.
+	ErrQuoteMismatch = errors.New("quote mismatch")
+	// ErrFeeExceedsAmount is returned when the fee of an exchange is not less than its amount.
+	ErrFeeExceedsAmount = errors.New("fee exceeds amount")
+	// ErrExchangeAmountOutOfRange is returned when an exchange amount is outside the limits of WithExchangeAmountLimits,
+	// or too large to convert at the rate.
+	ErrExchangeAmountOutOfRange = errors.New("exchange amount out of range")
+	// ErrSlippageExceeded is returned when the rate moved so that an exchange would credit
+	// less than the minimum amount the user expects.
+	ErrSlippageExceeded = errors.New("slippage exceeded")
+)
+
+// ExchangeQuoteStore keeps locked exchange quotes until they are used or expire.
+type ExchangeQuoteStore interface {
+	Save(ctx context.Context, quote models.ExchangeQuote, ttl time.Duration) error   // Stores the quote for ttl
+	Take(ctx context.Context, quoteID uuid.UUID) (models.ExchangeQuote, bool, error) // Removes the quote, reporting whether it was stored
+}
+
+// ExchangeFeeReader reads the fees charged for exchanges.
+type ExchangeFeeReader interface {
+	Get(ctx context.Context, fromCurrency, toCurrency string) (models.ExchangeFee, error) // Returns the fee of a pair, sql.ErrNoRows if it has none
+}
+
+// WithExchangeFees charges exchanges the fee of their currency pair: the percentage and
+// fixed fee are deducted from the amount before conversion, and the rate is lowered by
+// the spread. Pairs without a fee are exchanged free of charge.
+func WithExchangeFees(fees ExchangeFeeReader) WalletOpt {
+	return func(s *WalletService) {
+		s.fees = fees
+	}
+}
+
+// WithQuoteLocking locks the rate of every quote for ttl: QuoteExchange returns a quote ID
+// that ExchangeQuoted executes once at the quoted rate, protecting users from rate
+// movement between the preview and the exchange.
+func WithQuoteLocking(store ExchangeQuoteStore, ttl time.Duration) WalletOpt {
+	return func(s *WalletService) {
+		s.quotes = store
+		s.quoteTTL = ttl
+	}
+}
+
+// WithExchangeAmountLimits rejects exchanges and quotes of less than min or more than max
+// in the source currency. A zero limit is not enforced.
+func WithExchangeAmountLimits(min, max money.Amount) WalletOpt {
+	return func(s *WalletService) {
+		s.minExchange = min
+		s.maxExchange = max
+	}
+}
+
+// QuoteExchange returns the rate, fee and resulting amount of exchanging amount from
+// fromCurrency to toCurrency, as Exchange would execute it now, without touching balances.
+// The rate is taken from the cache or the exchanger like for Exchange, less the fee.
+// With WithQuoteLocking, the quote is stored for the user and returned with its ID and expiry.
+func (s *WalletService) QuoteExchange(ctx context.Context, userID uuid.UUID, fromCurrency, toCurrency string, amount money.Amount) (models.ExchangeQuote, error) {
+	quote, err := s.quoteExchange(ctx, fromCurrency, toCurrency, amount)
+	if err != nil || s.quotes == nil {
+		return quote, err
+	}
+
+	quote.QuoteID = uuid.New()
+	quote.UserID = userID
+	quote.ExpiresAt = time.Now().UTC().Add(s.quoteTTL)
+	if err := s.quotes.Save(ctx, quote, s.quoteTTL); err != nil {
+		logger.FromContext(ctx).Errorw("failed to save exchange quote", "userID", userID, "from", fromCurrency, "to", toCurrency, "error", err)
+		return models.ExchangeQuote{}, err
+	}
+	return quote, nil
+}
+
+// ExchangeQuoted executes a locked quote of the user at its rate. The quote is used up by
+// the attempt, even if the exchange fails. Empty currencies and a zero amount are taken
+// from the quote; otherwise they must match it. Without WithQuoteLocking, every quote is
+// expired. A positive minToAmount is the least amount to credit, as for Exchange.
+func (s *WalletService) ExchangeQuoted(
+	ctx context.Context,
+	userID, quoteID uuid.UUID,
+	fromCurrency, toCurrency string,
+	amount, minToAmount money.Amount,
+) (executed models.ExchangeQuote, balances map[string]money.Amount, err error) {
+	if s.quotes == nil {
+		return models.ExchangeQuote{}, nil, ErrQuoteExpired
+	}
+
+	quote, ok, err := s.quotes.Take(ctx, quoteID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to take exchange quote", "quote_id", quoteID, "userID", userID, "error", err)
+		return models.ExchangeQuote{}, nil, err
+	}
+	if !ok || quote.UserID != userID {
+		return models.ExchangeQuote{}, nil, ErrQuoteExpired
+	}
+	if fromCurrency != "" && fromCurrency != quote.FromCurrency ||
+		toCurrency != "" && toCurrency != quote.ToCurrency ||
+		amount != 0 && amount != quote.Amount {
+		return models.ExchangeQuote{}, nil, ErrQuoteMismatch
+	}
+
+	return s.exchange(ctx, userID, quote, minToAmount)
+}
+
+// quoteExchange prices the exchange at the current rate. The quoted rate is the market
+// rate less the spread, and it converts the amount less the fee. The amount must be
+// within the limits of WithExchangeAmountLimits and small enough to convert at the rate.
+func (s *WalletService) quoteExchange(ctx context.Context, fromCurrency, toCurrency string, amount money.Amount) (models.ExchangeQuote, error) {
+	if amount < s.minExchange || s.maxExchange > 0 && amount > s.maxExchange {
+		return models.ExchangeQuote{}, ErrExchangeAmountOutOfRange
+	}
+
+	rate, src, derivedRate, err := s.pairRate(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		return models.ExchangeQuote{}, err
+	}
+
+	fee, err := s.exchangeFee(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		return models.ExchangeQuote{}, err
+	}
+	charged := fee.Fixed
+	if fee.Percent > 0 {
+		percent, err := amount.ConvertRound(float32(fee.Percent/100), s.decimals(ctx, fromCurrency))
+		if err != nil {
+			logger.FromContext(ctx).Warnw("exchange fee overflows", "from", fromCurrency, "to", toCurrency, "amount", amount, "percent", fee.Percent, "error", err)
+			return models.ExchangeQuote{}, ErrExchangeAmountOutOfRange
+		}
+		charged += percent
+	}
+	if charged >= amount {
+		return models.ExchangeQuote{}, ErrFeeExceedsAmount
+	}
+	if fee.Spread > 0 {
+		rate *= float32(1 - fee.Spread/100)
+	}
+	toAmount, err := (amount - charged).ConvertRound(rate, s.decimals(ctx, toCurrency))
+	if err != nil {
+		logger.FromContext(ctx).Warnw("exchange amount overflows", "from", fromCurrency, "to", toCurrency, "amount", amount, "rate", rate, "error", err)
+		return models.ExchangeQuote{}, ErrExchangeAmountOutOfRange
+	}
+
+	return models.ExchangeQuote{
+		FromCurrency:  fromCurrency,
+		ToCurrency:    toCurrency,
+		Amount:        amount,
+		Rate:          rate,
+		Fee:           charged,
+		ToAmount:      toAmount,
+		StaleRate:     src.Stale,
+		DerivedRate:   derivedRate,
+		RateProvider:  src.Provider,
+		RateFetchedAt: src.FetchedAt,
+		RateCached:    src.Cached,
+	}, nil
+}
+
+// exchangeFee returns the fee of the currency pair, zero if it has none.
+func (s *WalletService) exchangeFee(ctx context.Context, fromCurrency, toCurrency string) (models.ExchangeFee, error) {
+	if s.fees == nil {
+		return models.ExchangeFee{}, nil
+	}
+	fee, err := s.fees.Get(ctx, fromCurrency, toCurrency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ExchangeFee{}, nil
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to get exchange fee", "from", fromCurrency, "to", toCurrency, "error", err)
+		return models.ExchangeFee{}, err
+	}
+	return fee, nil
+}
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_quote.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockExchangeQuoteStore is a mock of ExchangeQuoteStore interface.
+type MockExchangeQuoteStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeQuoteStoreMockRecorder
+}
+
+// MockExchangeQuoteStoreMockRecorder is the mock recorder for MockExchangeQuoteStore.
+type MockExchangeQuoteStoreMockRecorder struct {
+	mock *MockExchangeQuoteStore
+}
+
+// NewMockExchangeQuoteStore creates a new mock instance.
+func NewMockExchangeQuoteStore(ctrl *gomock.Controller) *MockExchangeQuoteStore {
+	mock := &MockExchangeQuoteStore{ctrl: ctrl}
+	mock.recorder = &MockExchangeQuoteStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeQuoteStore) EXPECT() *MockExchangeQuoteStoreMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockExchangeQuoteStore) Save(ctx context.Context, quote models.ExchangeQuote, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, quote, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockExchangeQuoteStoreMockRecorder) Save(ctx, quote, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockExchangeQuoteStore)(nil).Save), ctx, quote, ttl)
+}
+
+// Take mocks base method.
+func (m *MockExchangeQuoteStore) Take(ctx context.Context, quoteID uuid.UUID) (models.ExchangeQuote, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, quoteID)
+	ret0, _ := ret[0].(models.ExchangeQuote)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockExchangeQuoteStoreMockRecorder) Take(ctx, quoteID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockExchangeQuoteStore)(nil).Take), ctx, quoteID)
+}
+
+// MockExchangeFeeReader is a mock of ExchangeFeeReader interface.
+type MockExchangeFeeReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeFeeReaderMockRecorder
+}
+
+// MockExchangeFeeReaderMockRecorder is the mock recorder for MockExchangeFeeReader.
+type MockExchangeFeeReaderMockRecorder struct {
+	mock *MockExchangeFeeReader
+}
+
+// NewMockExchangeFeeReader creates a new mock instance.
+func NewMockExchangeFeeReader(ctrl *gomock.Controller) *MockExchangeFeeReader {
+	mock := &MockExchangeFeeReader{ctrl: ctrl}
+	mock.recorder = &MockExchangeFeeReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeFeeReader) EXPECT() *MockExchangeFeeReaderMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockExchangeFeeReader) Get(ctx context.Context, fromCurrency, toCurrency string) (models.ExchangeFee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, fromCurrency, toCurrency)
+	ret0, _ := ret[0].(models.ExchangeFee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExchangeFeeReaderMockRecorder) Get(ctx, fromCurrency, toCurrency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExchangeFeeReader)(nil).Get), ctx, fromCurrency, toCurrency)
+}
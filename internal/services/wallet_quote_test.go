@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_QuoteExchange(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+
+	t.Run("not locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		svc := NewWalletService(nil, nil, nil, cache, nil)
+
+		fetchedAt := time.Now().Add(-time.Minute)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.92), models.RateSource{Provider: "grpc", FetchedAt: fetchedAt, Cached: true}, nil)
+		quote, err := svc.QuoteExchange(ctx, userID, models.USD, models.EUR, amount)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ExchangeQuote{
+			FromCurrency:  models.USD,
+			ToCurrency:    models.EUR,
+			Amount:        amount,
+			Rate:          0.92,
+			ToAmount:      money.MustParse("92"),
+			RateProvider:  "grpc",
+			RateFetchedAt: fetchedAt,
+			RateCached:    true,
+		}, quote)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		quotes := NewMockExchangeQuoteStore(ctrl)
+		svc := NewWalletService(nil, nil, nil, cache, nil, WithQuoteLocking(quotes, ExchangeQuoteTTL))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.92), models.RateSource{FetchedAt: time.Now()}, nil)
+		quotes.EXPECT().Save(ctx, gomock.Any(), ExchangeQuoteTTL).DoAndReturn(func(_ context.Context, quote models.ExchangeQuote, _ time.Duration) error {
+			assert.NotEqual(t, uuid.Nil, quote.QuoteID)
+			assert.Equal(t, userID, quote.UserID)
+			assert.Equal(t, money.MustParse("92"), quote.ToAmount)
+			assert.WithinDuration(t, time.Now().Add(ExchangeQuoteTTL), quote.ExpiresAt, time.Second)
+			return nil
+		})
+
+		quote, err := svc.QuoteExchange(ctx, userID, models.USD, models.EUR, amount)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, quote.QuoteID)
+	})
+
+	t.Run("rate not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		svc := NewWalletService(nil, nil, rates, cache, nil, WithQuoteLocking(NewMockExchangeQuoteStore(ctrl), ExchangeQuoteTTL))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(0), models.RateSource{}, errors.New("cache miss"))
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(0), ErrExchangeRateNotFound)
+		_, err := svc.QuoteExchange(ctx, userID, models.USD, models.RUB, amount)
+		assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+	})
+
+	t.Run("amount too large to convert", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		svc := NewWalletService(nil, nil, nil, cache, nil)
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(95.5), models.RateSource{FetchedAt: time.Now()}, nil)
+		_, err := svc.QuoteExchange(ctx, userID, models.USD, models.RUB, money.MustParse("90000000000000000"))
+		assert.ErrorIs(t, err, ErrExchangeAmountOutOfRange)
+	})
+}
+
+func TestWalletService_ExchangeQuoted(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	quoteID := uuid.New()
+	quote := models.ExchangeQuote{
+		QuoteID:      quoteID,
+		UserID:       userID,
+		FromCurrency: models.USD,
+		ToCurrency:   models.EUR,
+		Amount:       money.MustParse("100"),
+		Rate:         0.92,
+		ToAmount:     money.MustParse("92"),
+	}
+
+	t.Run("executes at the quoted rate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		quotes := NewMockExchangeQuoteStore(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+
+		// No rate is fetched: the cache and the exchanger are nil
+		quotes.EXPECT().Take(ctx, quoteID).Return(quote, true, nil)
+		writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, quote.Amount, money.Zero, models.EUR, quote.ToAmount).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: quote.ToAmount}, nil)
+
+		svc := NewWalletService(writer, reader, nil, nil, nil, WithQuoteLocking(quotes, ExchangeQuoteTTL))
+		executed, balances, err := svc.ExchangeQuoted(ctx, userID, quoteID, models.USD, "", 0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, quote.ToAmount, executed.ToAmount)
+		assert.Equal(t, quote.ToAmount, balances[models.EUR])
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		quotes := NewMockExchangeQuoteStore(ctrl)
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithQuoteLocking(quotes, ExchangeQuoteTTL))
+
+		quotes.EXPECT().Take(ctx, quoteID).Return(models.ExchangeQuote{}, false, nil)
+		_, _, err := svc.ExchangeQuoted(ctx, userID, quoteID, "", "", 0, 0)
+		assert.ErrorIs(t, err, ErrQuoteExpired)
+
+		quotes.EXPECT().Take(ctx, quoteID).Return(quote, true, nil)
+		_, _, err = svc.ExchangeQuoted(ctx, uuid.New(), quoteID, "", "", 0, 0)
+		assert.ErrorIs(t, err, ErrQuoteExpired)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		quotes := NewMockExchangeQuoteStore(ctrl)
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithQuoteLocking(quotes, ExchangeQuoteTTL))
+
+		quotes.EXPECT().Take(ctx, quoteID).Return(quote, true, nil)
+		_, _, err := svc.ExchangeQuoted(ctx, userID, quoteID, models.USD, models.EUR, money.MustParse("200"), 0)
+		assert.ErrorIs(t, err, ErrQuoteMismatch)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		_, _, err := svc.ExchangeQuoted(ctx, userID, quoteID, "", "", 0, 0)
+		assert.ErrorIs(t, err, ErrQuoteExpired)
+	})
+}
+
+func TestWalletService_ExchangeFees(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+	fee := models.ExchangeFee{FromCurrency: models.USD, ToCurrency: models.EUR, Percent: 1.5, Fixed: money.MustParse("0.50"), Spread: 2}
+
+	t.Run("fee deducted and posted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		fees := NewMockExchangeFeeReader(ctrl)
+		svc := NewWalletService(writer, reader, nil, cache, nil, WithExchangeFees(fees))
+
+		// 1.5% of 100 plus 0.50 is charged, the remaining 98 is converted at 0.5 less 2%
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+		fees.EXPECT().Get(ctx, models.USD, models.EUR).Return(fee, nil)
+		writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, amount, money.MustParse("2"), models.EUR, money.MustParse("48.02")).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("48.02")}, nil)
+
+		executed, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, amount, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("2"), executed.Fee)
+		assert.Equal(t, money.MustParse("48.02"), executed.ToAmount)
+		assert.InDelta(t, 0.49, executed.Rate, 1e-6)
+	})
+
+	t.Run("pair without fee", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		fees := NewMockExchangeFeeReader(ctrl)
+		svc := NewWalletService(nil, nil, nil, cache, nil, WithExchangeFees(fees))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.EUR, models.USD).Return(float32(2), models.RateSource{FetchedAt: time.Now()}, nil)
+		fees.EXPECT().Get(ctx, models.EUR, models.USD).Return(models.ExchangeFee{}, sql.ErrNoRows)
+		quote, err := svc.QuoteExchange(ctx, userID, models.EUR, models.USD, amount)
+		assert.NoError(t, err)
+		assert.Equal(t, money.Zero, quote.Fee)
+		assert.Equal(t, money.MustParse("200"), quote.ToAmount)
+	})
+
+	t.Run("fee exceeds amount", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		fees := NewMockExchangeFeeReader(ctrl)
+		svc := NewWalletService(nil, nil, nil, cache, nil, WithExchangeFees(fees))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+		fees.EXPECT().Get(ctx, models.USD, models.EUR).Return(fee, nil)
+		_, err := svc.QuoteExchange(ctx, userID, models.USD, models.EUR, money.MustParse("0.50"))
+		assert.ErrorIs(t, err, ErrFeeExceedsAmount)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		fees := NewMockExchangeFeeReader(ctrl)
+		svc := NewWalletService(nil, nil, nil, cache, nil, WithExchangeFees(fees))
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+		fees.EXPECT().Get(ctx, models.USD, models.EUR).Return(models.ExchangeFee{}, errors.New("db error"))
+		_, err := svc.QuoteExchange(ctx, userID, models.USD, models.EUR, amount)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestWalletService_ExchangeAmountLimits(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	svc := NewWalletService(nil, nil, nil, nil, nil, WithExchangeAmountLimits(money.MustParse("1"), money.MustParse("1000")))
+
+	_, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("0.99"), 0)
+	assert.ErrorIs(t, err, ErrExchangeAmountOutOfRange)
+
+	_, err = svc.QuoteExchange(ctx, userID, models.USD, models.EUR, money.MustParse("1000.01"))
+	assert.ErrorIs(t, err, ErrExchangeAmountOutOfRange)
+
+	// Without an upper limit any amount above the lower one is priced
+	ctrl := gomock.NewController(t)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+	svc = NewWalletService(nil, nil, nil, cache, nil, WithExchangeAmountLimits(money.MustParse("1"), 0))
+	quote, err := svc.QuoteExchange(ctx, userID, models.USD, models.EUR, money.MustParse("1000000"))
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("500000"), quote.ToAmount)
+}
+
+func TestWalletService_ExchangeSlippage(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+
+	t.Run("rate moved below the expected amount", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.91), models.RateSource{FetchedAt: time.Now()}, nil)
+
+		// No balance is touched: the writer is nil
+		svc := NewWalletService(nil, nil, nil, cache, nil)
+		_, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, amount, money.MustParse("92"))
+		assert.ErrorIs(t, err, ErrSlippageExceeded)
+	})
+
+	t.Run("expected amount reached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.92), models.RateSource{FetchedAt: time.Now()}, nil)
+		writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, amount, money.Zero, models.EUR, money.MustParse("92")).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("92")}, nil)
+
+		svc := NewWalletService(writer, reader, nil, cache, nil)
+		executed, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, amount, money.MustParse("92"))
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("92"), executed.ToAmount)
+	})
+}
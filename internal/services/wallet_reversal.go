@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+)
+
+var (
+	// ErrReversalsDisabled is returned by Reverse of a service created without WithReversals.
+	ErrReversalsDisabled = errors.New("reversals disabled")
+	// ErrTransactionNotFound is returned when reversing a transaction that does not exist.
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionAlreadyReversed is returned when reversing a transaction a second time.
+	ErrTransactionAlreadyReversed = errors.New("transaction already reversed")
+	// ErrTransactionNotReversible is returned when reversing a reversal or an operation that moved no money.
+	ErrTransactionNotReversible = errors.New("transaction not reversible")
+	// ErrInvalidReversal is returned when a reversal has no reason.
+	ErrInvalidReversal = errors.New("invalid reversal")
+)
+
+// TransactionReversalStore finds transactions and records their reversals.
+type TransactionReversalStore interface {
+	Get(ctx context.Context, transactionID uuid.UUID) (models.TransactionDB, error) // Returns a transaction; sql.ErrNoRows if there is none
+	SaveReversal(ctx context.Context, txn models.TransactionDB) error               // Appends a reversal; sql.ErrNoRows if the original is already reversed
+}
+
+// Transactor runs a function in a database transaction.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error // Commits if fn returns nil, rolls back otherwise
+}
+
+// WithReversals lets admins reverse transactions. The reversal is recorded and the money
+// moved back in one database transaction run by tx, so the writer and the store must
+// take part in it. Reversals are audited.
+func WithReversals(store TransactionReversalStore, tx Transactor, audit AuditWriter) WalletOpt {
+	return func(s *WalletService) {
+		s.reversals = store
+		s.tx = tx
+		s.audit = audit
+	}
+}
+
+// Reverse creates a compensating transaction for transactionID on behalf of adminID and
+// publishes it. A deposit is withdrawn, a withdrawal deposited back, and an exchange or a
+// wallet closure with payout exchanged back at the original amounts. A transaction is
+// reversed at most once; reversals cannot be reversed. Reversals do not count against
+// the user's limits.
+func (s *WalletService) Reverse(ctx context.Context, adminID, transactionID uuid.UUID, reason string) (models.TransactionDB, error) {
+	if s.reversals == nil {
+		return models.TransactionDB{}, ErrReversalsDisabled
+	}
+	if reason == "" {
+		return models.TransactionDB{}, ErrInvalidReversal
+	}
+
+	original, err := s.reversals.Get(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TransactionDB{}, ErrTransactionNotFound
+		}
+		logger.FromContext(ctx).Errorw("failed to get transaction", "transaction_id", transactionID, "error", err)
+		return models.TransactionDB{}, err
+	}
+
+	reversal := models.TransactionDB{
+		TransactionID: uuid.New(),
+		UserID:        original.UserID,
+		Operation:     models.OperationReversal,
+		Currency:      original.Currency,
+		Amount:        original.Amount,
+		ToCurrency:    original.ToCurrency,
+		ToAmount:      original.ToAmount,
+		ReversalOf:    &original.TransactionID,
+		CreatedAt:     time.Now(),
+	}
+
+	err = s.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := s.reversals.SaveReversal(ctx, reversal); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTransactionAlreadyReversed
+			}
+			return err
+		}
+		return s.compensate(ctx, reversal.TransactionID, original)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to reverse transaction", "adminID", adminID, "transaction_id", transactionID, "error", err)
+		return models.TransactionDB{}, err
+	}
+
+	details := map[string]any{
+		"transaction_id": transactionID,
+		"reversal_id":    reversal.TransactionID,
+		"reason":         reason,
+	}
+	if err := s.audit.Save(ctx, adminID, models.AuditActionTransactionReverse, &original.UserID, details); err != nil {
+		logger.FromContext(ctx).Errorw("failed to audit transaction reversal", "adminID", adminID, "transaction_id", transactionID, "error", err)
+		return models.TransactionDB{}, err
+	}
+	logger.FromContext(ctx).Warnw("transaction reversed", "adminID", adminID, "userID", original.UserID,
+		"transaction_id", transactionID, "reversal_id", reversal.TransactionID, "reason", reason)
+
+	balances := s.eventBalances(ctx, reversal.UserID, nil)
+	txn := models.Transaction{
+		TransactionID: reversal.TransactionID.String(),
+		Timestamp:     reversal.CreatedAt.Unix(),
+		Amount:        reversal.Amount,
+		UserID:        reversal.UserID.String(),
+		Operation:     models.OperationReversal,
+		ReversalOf:    transactionID.String(),
+		Balance:       balanceOf(balances, reversal.Currency),
+	}
+	currencies := []string{reversal.Currency}
+	if reversal.ToCurrency != nil {
+		txn.ToCurrency = *reversal.ToCurrency
+		txn.ToBalance = balanceOf(balances, *reversal.ToCurrency)
+		currencies = append(currencies, *reversal.ToCurrency)
+	}
+	s.publishTransaction(ctx, reversal.Currency, txn)
+	s.publishBalances(ctx, reversal.TransactionID, reversal.UserID, balances, currencies...)
+
+	return reversal, nil
+}
+
+// compensate moves the money of original back under transactionID. An exchange is
+// reversed in full, so its fee is refunded as well.
+func (s *WalletService) compensate(ctx context.Context, transactionID uuid.UUID, original models.TransactionDB) error {
+	var err error
+	switch {
+	case original.Operation == models.OperationDeposit:
+		err = s.writeRepo.SaveWithdraw(ctx, transactionID, original.UserID, original.Amount, original.Currency)
+	case original.Operation == models.OperationWithdraw:
+		err = s.writeRepo.SaveDeposit(ctx, transactionID, original.UserID, original.Amount, original.Currency)
+	case (original.Operation == models.OperationExchange || original.Operation == models.OperationClose) &&
+		original.ToCurrency != nil && original.ToAmount != nil && original.ToAmount.IsPositive():
+		err = s.writeRepo.SaveExchange(ctx, transactionID, original.UserID,
+			*original.ToCurrency, *original.ToAmount, money.Zero, original.Currency, original.Amount)
+	default:
+		return ErrTransactionNotReversible
+	}
+	if errors.Is(err, repositories.ErrInsufficientFunds) {
+		return ErrInsufficientFunds
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/wallet_reversal.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockTransactionReversalStore is a mock of TransactionReversalStore interface.
+type MockTransactionReversalStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionReversalStoreMockRecorder
+}
+
+// MockTransactionReversalStoreMockRecorder is the mock recorder for MockTransactionReversalStore.
+type MockTransactionReversalStoreMockRecorder struct {
+	mock *MockTransactionReversalStore
+}
+
+// NewMockTransactionReversalStore creates a new mock instance.
+func NewMockTransactionReversalStore(ctrl *gomock.Controller) *MockTransactionReversalStore {
+	mock := &MockTransactionReversalStore{ctrl: ctrl}
+	mock.recorder = &MockTransactionReversalStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionReversalStore) EXPECT() *MockTransactionReversalStoreMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockTransactionReversalStore) Get(ctx context.Context, transactionID uuid.UUID) (models.TransactionDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, transactionID)
+	ret0, _ := ret[0].(models.TransactionDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTransactionReversalStoreMockRecorder) Get(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTransactionReversalStore)(nil).Get), ctx, transactionID)
+}
+
+// SaveReversal mocks base method.
+func (m *MockTransactionReversalStore) SaveReversal(ctx context.Context, txn models.TransactionDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReversal", ctx, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReversal indicates an expected call of SaveReversal.
+func (mr *MockTransactionReversalStoreMockRecorder) SaveReversal(ctx, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReversal", reflect.TypeOf((*MockTransactionReversalStore)(nil).SaveReversal), ctx, txn)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// InTx mocks base method.
+func (m *MockTransactor) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockTransactorMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockTransactor)(nil).InTx), ctx, fn)
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletService_Reverse(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	userID := uuid.New()
+	transactionID := uuid.New()
+
+	// inTx runs fn like the real transactor would, without a database
+	inTx := func(tx *MockTransactor) {
+		tx.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	}
+
+	t.Run("deposit is withdrawn and published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		audit := NewMockAuditWriter(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		kafkaWriter := NewMockEventPublisher(ctrl)
+
+		amount := money.MustParse("100")
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{
+			TransactionID: transactionID, UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: amount,
+		}, nil)
+		inTx(tx)
+		var reversalID uuid.UUID
+		store.EXPECT().SaveReversal(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			reversalID = txn.TransactionID
+			assert.Equal(t, models.OperationReversal, txn.Operation)
+			assert.Equal(t, userID, txn.UserID)
+			if assert.NotNil(t, txn.ReversalOf) {
+				assert.Equal(t, transactionID, *txn.ReversalOf)
+			}
+			return nil
+		})
+		writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), userID, amount, models.USD).DoAndReturn(
+			func(_ context.Context, id, _ uuid.UUID, _ money.Amount, _ string) error {
+				assert.Equal(t, reversalID, id)
+				return nil
+			})
+		audit.EXPECT().Save(ctx, adminID, models.AuditActionTransactionReverse, &userID, gomock.Any()).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("20")}, nil)
+		kafkaWriter.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+			var event models.Transaction
+			assert.NoError(t, json.Unmarshal(msgs[0].Value, &event))
+			assert.Equal(t, models.OperationReversal, event.Operation)
+			assert.Equal(t, transactionID.String(), event.ReversalOf)
+			assert.Equal(t, models.USD, event.Currency)
+			if assert.NotNil(t, event.Balance) {
+				assert.Equal(t, money.MustParse("20"), *event.Balance)
+			}
+			return nil
+		})
+
+		svc := NewWalletService(writer, reader, nil, nil, kafkaWriter, WithReversals(store, tx, audit))
+		reversal, err := svc.Reverse(ctx, adminID, transactionID, "Duplicate payment")
+		assert.NoError(t, err)
+		assert.Equal(t, reversalID, reversal.TransactionID)
+		assert.Equal(t, amount, reversal.Amount)
+	})
+
+	t.Run("withdrawal is deposited back", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		audit := NewMockAuditWriter(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+
+		amount := money.MustParse("40")
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{
+			TransactionID: transactionID, UserID: userID, Operation: models.OperationWithdraw, Currency: models.EUR, Amount: amount,
+		}, nil)
+		inTx(tx)
+		store.EXPECT().SaveReversal(ctx, gomock.Any()).Return(nil)
+		writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, amount, models.EUR).Return(nil)
+		audit.EXPECT().Save(ctx, adminID, models.AuditActionTransactionReverse, &userID, gomock.Any()).Return(nil)
+
+		svc := NewWalletService(writer, nil, nil, nil, nil, WithReversals(store, tx, audit))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Chargeback")
+		assert.NoError(t, err)
+	})
+
+	t.Run("exchange is exchanged back", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		audit := NewMockAuditWriter(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+
+		toCurrency := models.RUB
+		toAmount := money.MustParse("9000")
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{
+			TransactionID: transactionID, UserID: userID, Operation: models.OperationExchange,
+			Currency: models.USD, Amount: money.MustParse("100"), ToCurrency: &toCurrency, ToAmount: &toAmount,
+		}, nil)
+		inTx(tx)
+		store.EXPECT().SaveReversal(ctx, gomock.Any()).Return(nil)
+		writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.RUB, toAmount, money.Zero, models.USD, money.MustParse("100")).Return(nil)
+		audit.EXPECT().Save(ctx, adminID, models.AuditActionTransactionReverse, &userID, gomock.Any()).Return(nil)
+
+		svc := NewWalletService(writer, nil, nil, nil, nil, WithReversals(store, tx, audit))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Wrong rate")
+		assert.NoError(t, err)
+	})
+
+	t.Run("already reversed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{
+			TransactionID: transactionID, UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("1"),
+		}, nil)
+		inTx(tx)
+		store.EXPECT().SaveReversal(ctx, gomock.Any()).Return(sql.ErrNoRows)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithReversals(store, tx, NewMockAuditWriter(ctrl)))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Duplicate payment")
+		assert.ErrorIs(t, err, ErrTransactionAlreadyReversed)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+		writer := NewMockWalletWriter(ctrl)
+
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{
+			TransactionID: transactionID, UserID: userID, Operation: models.OperationDeposit, Currency: models.USD, Amount: money.MustParse("100"),
+		}, nil)
+		inTx(tx)
+		store.EXPECT().SaveReversal(ctx, gomock.Any()).Return(nil)
+		writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), userID, money.MustParse("100"), models.USD).Return(repositories.ErrInsufficientFunds)
+
+		svc := NewWalletService(writer, nil, nil, nil, nil, WithReversals(store, tx, NewMockAuditWriter(ctrl)))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Chargeback")
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("reversal is not reversible", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		tx := NewMockTransactor(ctrl)
+
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{
+			TransactionID: transactionID, UserID: userID, Operation: models.OperationReversal, Currency: models.USD, Amount: money.MustParse("1"),
+		}, nil)
+		inTx(tx)
+		store.EXPECT().SaveReversal(ctx, gomock.Any()).Return(nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithReversals(store, tx, NewMockAuditWriter(ctrl)))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Undo")
+		assert.ErrorIs(t, err, ErrTransactionNotReversible)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockTransactionReversalStore(ctrl)
+		store.EXPECT().Get(ctx, transactionID).Return(models.TransactionDB{}, sql.ErrNoRows)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithReversals(store, NewMockTransactor(ctrl), NewMockAuditWriter(ctrl)))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Chargeback")
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc := NewWalletService(nil, nil, nil, nil, nil,
+			WithReversals(NewMockTransactionReversalStore(ctrl), NewMockTransactor(ctrl), NewMockAuditWriter(ctrl)))
+		_, err := svc.Reverse(ctx, adminID, transactionID, "")
+		assert.ErrorIs(t, err, ErrInvalidReversal)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := NewWalletService(nil, nil, nil, nil, nil)
+		_, err := svc.Reverse(ctx, adminID, transactionID, "Chargeback")
+		assert.ErrorIs(t, err, ErrReversalsDisabled)
+	})
+}
@@ -2,15 +2,31 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/facades"
 	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/repositories"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/requestid"
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 )
 
+// directTx runs functions like the real transactor would, without a database
+type directTx struct{}
+
+func (directTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func TestWalletService_Deposit(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -20,24 +36,63 @@ func TestWalletService_Deposit(t *testing.T) {
 
 	writer := NewMockWalletWriter(ctrl)
 	reader := NewMockWalletReader(ctrl)
-	kafka := NewMockKafkaWriter(ctrl)
+	kafka := NewMockEventPublisher(ctrl)
 
 	// Успешный депозит
-	writer.EXPECT().SaveDeposit(ctx, userID, 50000.0, models.USD).Return(nil)
-	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]float64{
-		models.USD: 50000,
-		models.RUB: 0,
-		models.EUR: 0,
+	writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, money.MustParse("50000"), models.USD).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+		models.USD: money.MustParse("50000"),
+		models.RUB: money.Zero,
+		models.EUR: money.Zero,
 	}, nil)
 	kafka.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil)
 
 	svc := NewWalletService(writer, reader, nil, nil, kafka)
-	usd, rub, eur, err := svc.Deposit(ctx, userID, 50000, models.USD)
+	balances, err := svc.Deposit(ctx, userID, money.MustParse("50000"), models.USD, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("50000"), balances[models.USD])
+	assert.Equal(t, money.Zero, balances[models.RUB])
+	assert.Equal(t, money.Zero, balances[models.EUR])
+}
+
+func TestWalletService_DepositWithReference(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	kafkaWriter := NewMockEventPublisher(ctrl)
+	history := NewMockTransactionStore(ctrl)
+	webhooks := NewMockWebhookNotifier(ctrl)
+
+	writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, money.MustParse("100"), models.USD).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("100")}, nil)
+	history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+		if assert.NotNil(t, txn.Reference) {
+			assert.Equal(t, "INV-42", *txn.Reference)
+		}
+		return nil
+	})
+	webhooks.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event models.WebhookEvent) error {
+		if assert.NotNil(t, event.Reference) {
+			assert.Equal(t, "INV-42", *event.Reference)
+		}
+		return nil
+	})
+	kafkaWriter.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+		var txn models.Transaction
+		assert.NoError(t, json.Unmarshal(msgs[0].Value, &txn))
+		assert.Equal(t, "INV-42", txn.Reference)
+		return nil
+	})
 
+	svc := NewWalletService(writer, reader, nil, nil, kafkaWriter, WithTransactionHistory(history, directTx{}), WithWebhooks(webhooks))
+	_, err := svc.Deposit(ctx, userID, money.MustParse("100"), models.USD, "INV-42")
 	assert.NoError(t, err)
-	assert.Equal(t, 50000.0, usd)
-	assert.Equal(t, 0.0, rub)
-	assert.Equal(t, 0.0, eur)
 }
 
 func TestWalletService_Withdraw(t *testing.T) {
@@ -49,24 +104,24 @@ func TestWalletService_Withdraw(t *testing.T) {
 
 	writer := NewMockWalletWriter(ctrl)
 	reader := NewMockWalletReader(ctrl)
-	kafka := NewMockKafkaWriter(ctrl)
+	kafka := NewMockEventPublisher(ctrl)
 
 	// Успешное снятие
-	writer.EXPECT().SaveWithdraw(ctx, userID, 1000.0, models.USD).Return(nil)
-	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]float64{
-		models.USD: 4000,
-		models.RUB: 0,
-		models.EUR: 0,
+	writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), userID, money.MustParse("1000"), models.USD).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+		models.USD: money.MustParse("4000"),
+		models.RUB: money.Zero,
+		models.EUR: money.Zero,
 	}, nil)
 	kafka.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil)
 
 	svc := NewWalletService(writer, reader, nil, nil, kafka)
-	usd, rub, eur, err := svc.Withdraw(ctx, userID, 1000, models.USD)
+	balances, err := svc.Withdraw(ctx, userID, money.MustParse("1000"), models.USD, "")
 
 	assert.NoError(t, err)
-	assert.Equal(t, 4000.0, usd)
-	assert.Equal(t, 0.0, rub)
-	assert.Equal(t, 0.0, eur)
+	assert.Equal(t, money.MustParse("4000"), balances[models.USD])
+	assert.Equal(t, money.Zero, balances[models.RUB])
+	assert.Equal(t, money.Zero, balances[models.EUR])
 }
 
 func TestWalletService_Exchange_Errors(t *testing.T) {
@@ -84,44 +139,78 @@ func TestWalletService_Exchange_Errors(t *testing.T) {
 	svc := NewWalletService(mockWrite, mockRead, mockRate, mockCache, nil)
 
 	// 1. Ошибка получения курса
-	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), errors.New("cache miss"))
+	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), models.RateSource{}, errors.New("cache miss"))
 	mockRate.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), errors.New("rate fetch error"))
-	_, _, _, _, err := svc.Exchange(ctx, userID, "USD", "EUR", 100)
+	_, _, err := svc.Exchange(ctx, userID, "USD", "EUR", money.MustParse("100"), 0)
 	assert.EqualError(t, err, "rate fetch error")
 
 	// 2. Ошибка списания
-	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), errors.New("cache miss"))
+	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), models.RateSource{}, errors.New("cache miss"))
 	mockRate.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0.9), nil)
-	mockCache.EXPECT().SetExchangeRateForCurrency(ctx, "USD", "EUR", float32(0.9)).Return(nil)
-	mockWrite.EXPECT().SaveWithdraw(ctx, userID, 100.0, "USD").Return(errors.New("insufficient"))
-	_, _, _, _, err = svc.Exchange(ctx, userID, "USD", "EUR", 100)
+	mockCache.EXPECT().SetExchangeRateForCurrency(ctx, "USD", "EUR", float32(0.9), gomock.Any()).Return(nil)
+	mockWrite.EXPECT().SaveExchange(ctx, gomock.Any(), userID, "USD", money.MustParse("100"), money.Zero, "EUR", money.MustParse("90")).Return(repositories.ErrInsufficientFunds)
+	_, _, err = svc.Exchange(ctx, userID, "USD", "EUR", money.MustParse("100"), 0)
 	assert.Equal(t, ErrInsufficientFunds, err)
 
-	// 3. Ошибка депозита
-	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), errors.New("cache miss"))
+	// 2a. Прочая ошибка списания не маскируется под нехватку средств
+	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), models.RateSource{}, errors.New("cache miss"))
 	mockRate.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0.9), nil)
-	mockCache.EXPECT().SetExchangeRateForCurrency(ctx, "USD", "EUR", float32(0.9)).Return(nil)
-	mockWrite.EXPECT().SaveWithdraw(ctx, userID, 100.0, "USD").Return(nil)
-	mockWrite.EXPECT().SaveDeposit(ctx, userID, float64(90.0), "EUR").Return(errors.New("deposit error"))
-	_, _, _, _, err = svc.Exchange(ctx, userID, "USD", "EUR", 100)
-	assert.EqualError(t, err, "deposit error")
-
-	// 4. Ошибка чтения баланса
-	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), errors.New("cache miss"))
+	mockCache.EXPECT().SetExchangeRateForCurrency(ctx, "USD", "EUR", float32(0.9), gomock.Any()).Return(nil)
+	mockWrite.EXPECT().SaveExchange(ctx, gomock.Any(), userID, "USD", money.MustParse("100"), money.Zero, "EUR", money.MustParse("90")).Return(errors.New("connection reset"))
+	_, _, err = svc.Exchange(ctx, userID, "USD", "EUR", money.MustParse("100"), 0)
+	assert.EqualError(t, err, "connection reset")
+
+	// 3. Ошибка чтения баланса
+	mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), models.RateSource{}, errors.New("cache miss"))
 	mockRate.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0.9), nil)
-	mockCache.EXPECT().SetExchangeRateForCurrency(ctx, "USD", "EUR", float32(0.9)).Return(nil)
-	mockWrite.EXPECT().SaveWithdraw(ctx, userID, 100.0, "USD").Return(nil)
-	mockWrite.EXPECT().SaveDeposit(ctx, userID, float64(90.0), "EUR").Return(nil)
+	mockCache.EXPECT().SetExchangeRateForCurrency(ctx, "USD", "EUR", float32(0.9), gomock.Any()).Return(nil)
+	mockWrite.EXPECT().SaveExchange(ctx, gomock.Any(), userID, "USD", money.MustParse("100"), money.Zero, "EUR", money.MustParse("90")).Return(nil)
 	mockRead.EXPECT().GetByUserID(ctx, userID).Return(nil, errors.New("read balance error"))
-	_, _, _, _, err = svc.Exchange(ctx, userID, "USD", "EUR", 100)
+	_, _, err = svc.Exchange(ctx, userID, "USD", "EUR", money.MustParse("100"), 0)
 	assert.EqualError(t, err, "read balance error")
 }
 
+func TestWalletService_Exchange_ExchangerErrors(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "rate not found", err: fmt.Errorf("%w: pair", facades.ErrRateNotFound), wantErr: ErrExchangeRateNotFound},
+		{name: "unavailable", err: fmt.Errorf("%w: refused", facades.ErrExchangerUnavailable), wantErr: ErrExchangerUnavailable},
+		{name: "timeout", err: fmt.Errorf("%w: deadline", facades.ErrExchangerTimeout), wantErr: ErrExchangerTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRate := NewMockExchangeRateReader(ctrl)
+			mockCache := NewMockExchangeRateCacheReader(ctrl)
+			svc := NewWalletService(nil, nil, mockRate, mockCache, nil)
+
+			mockCache.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), models.RateSource{}, errors.New("cache miss"))
+			mockRate.EXPECT().GetExchangeRateForCurrency(ctx, "USD", "EUR").Return(float32(0), tt.err)
+			_, _, err := svc.Exchange(ctx, userID, "USD", "EUR", money.MustParse("100"), 0)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			mockRate.EXPECT().GetExchangeRates(ctx).Return(nil, tt.err)
+			mockCache.EXPECT().GetExchangeRates(ctx).AnyTimes().Return(nil, models.RateSource{}, errors.New("cache miss"))
+			_, _, err = svc.GetExchangeRates(ctx)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestWalletService_publishTransaction(t *testing.T) {
 	ctx := context.Background()
 	txn := models.Transaction{
 		TransactionID: "txn-123",
-		Amount:        1000,
+		Amount:        money.MustParse("1000"),
 		UserID:        "user-1",
 		Operation:     "deposit",
 	}
@@ -129,20 +218,98 @@ func TestWalletService_publishTransaction(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockKafka := NewMockKafkaWriter(ctrl)
-	svc := &WalletService{kafkaWriter: mockKafka}
+	mockKafka := NewMockEventPublisher(ctrl)
+	svc := &WalletService{kafkaWriter: mockKafka, encoder: JSONTransactionEncoder{}}
 
 	// Проверяем успешный вызов
 	mockKafka.EXPECT().WriteMessages(ctx, gomock.Any()).Return(nil).Times(1)
-	svc.publishTransaction(ctx, txn)
+	svc.publishTransaction(ctx, "USD", txn)
 
 	// Проверяем ошибку публикации
 	mockKafka.EXPECT().WriteMessages(ctx, gomock.Any()).Return(errors.New("kafka error")).Times(1)
-	svc.publishTransaction(ctx, txn)
+	svc.publishTransaction(ctx, "USD", txn)
 
-	// Проверяем nil KafkaWriter — не должно паниковать
+	// Проверяем nil EventPublisher — не должно паниковать
 	svc = &WalletService{}
-	svc.publishTransaction(ctx, txn)
+	svc.publishTransaction(ctx, "USD", txn)
+}
+
+func TestWalletService_publishTransaction_Keys(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	writer := NewMockEventPublisher(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, writer)
+	txn := models.Transaction{TransactionID: "txn-1", UserID: "user-1", Operation: models.OperationTransferOut}
+
+	var eventIDs []string
+	writer.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+		// Keyed by the user for the order of their operations
+		assert.Equal(t, "user-1", string(msgs[0].Key))
+		var event models.Transaction
+		assert.NoError(t, json.Unmarshal(msgs[0].Value, &event))
+		if assert.Len(t, msgs[0].Headers, 1) {
+			assert.Equal(t, models.EventIDHeader, msgs[0].Headers[0].Key)
+			assert.Equal(t, event.EventID, string(msgs[0].Headers[0].Value))
+		}
+		eventIDs = append(eventIDs, event.EventID)
+		return nil
+	}).Times(3)
+
+	svc.publishTransaction(ctx, "USD", txn)
+	svc.publishTransaction(ctx, "USD", txn) // Redelivery
+	txn.Operation = models.OperationTransferIn
+	svc.publishTransaction(ctx, "USD", txn)
+
+	assert.NotEmpty(t, eventIDs[0])
+	assert.Equal(t, eventIDs[0], eventIDs[1], "the same transaction has the same event ID")
+	assert.NotEqual(t, eventIDs[0], eventIDs[2])
+}
+
+func TestWalletService_publishTransaction_RequestID(t *testing.T) {
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	ctrl := gomock.NewController(t)
+	writer := NewMockEventPublisher(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, writer)
+
+	writer.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+		if assert.Len(t, msgs[0].Headers, 2) {
+			assert.Equal(t, kafka.Header{Key: requestid.KafkaHeader, Value: []byte("req-1")}, msgs[0].Headers[1])
+		}
+		return nil
+	})
+	svc.publishTransaction(ctx, "USD", models.Transaction{TransactionID: "txn-1", UserID: "user-1", Operation: models.OperationDeposit})
+}
+
+func TestWalletService_publishTransaction_Topics(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	writer := NewMockEventPublisher(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, writer, WithTransactionTopics("large-transactions", "transactions",
+		map[string]money.Amount{"USD": money.MustParse("10000"), "RUB": money.MustParse("1000000")}))
+
+	tests := []struct {
+		name     string
+		currency string
+		amount   string
+		topic    string
+	}{
+		{name: "below threshold", currency: "USD", amount: "9999.99", topic: "transactions"},
+		{name: "at threshold", currency: "USD", amount: "10000", topic: "transactions"},
+		{name: "above threshold", currency: "USD", amount: "10000.01", topic: "large-transactions"},
+		{name: "threshold of the currency", currency: "RUB", amount: "10000.01", topic: "transactions"},
+		{name: "currency without threshold", currency: "EUR", amount: "1", topic: "transactions"},
+		{name: "large amount in currency without threshold", currency: "EUR", amount: "1000000000", topic: "transactions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := models.Transaction{TransactionID: "txn-1", Amount: money.MustParse(tt.amount), Operation: "deposit"}
+			writer.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+				assert.Equal(t, tt.topic, msgs[0].Topic)
+				return nil
+			})
+			svc.publishTransaction(ctx, tt.currency, txn)
+		})
+	}
 }
 
 func TestWalletService_GetUserBalance(t *testing.T) {
@@ -153,21 +320,21 @@ func TestWalletService_GetUserBalance(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockReader := NewMockWalletReader(ctrl)
-	mockReader.EXPECT().GetByUserID(ctx, userID).Return(map[string]float64{
-		models.USD: 100,
-		models.RUB: 5000,
-		models.EUR: 50,
+	mockReader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+		models.USD: money.MustParse("100"),
+		models.RUB: money.MustParse("5000"),
+		models.EUR: money.MustParse("50"),
 	}, nil)
 
-	svc := &WalletService{
-		readRepo: mockReader,
-	}
+	svc := NewWalletService(nil, mockReader, nil, nil, nil)
 
-	usd, rub, eur, err := svc.GetUserBalance(ctx, userID)
+	balances, err := svc.GetUserBalance(ctx, userID)
 	assert.NoError(t, err)
-	assert.Equal(t, 100.0, usd)
-	assert.Equal(t, 5000.0, rub)
-	assert.Equal(t, 50.0, eur)
+	assert.Equal(t, map[string]money.Amount{
+		models.USD: money.MustParse("100"),
+		models.RUB: money.MustParse("5000"),
+		models.EUR: money.MustParse("50"),
+	}, balances)
 }
 
 func TestWalletService_GetUserBalance_Error(t *testing.T) {
@@ -180,15 +347,35 @@ func TestWalletService_GetUserBalance_Error(t *testing.T) {
 	mockReader := NewMockWalletReader(ctrl)
 	mockReader.EXPECT().GetByUserID(ctx, userID).Return(nil, errors.New("db error"))
 
-	svc := &WalletService{
-		readRepo: mockReader,
-	}
+	svc := NewWalletService(nil, mockReader, nil, nil, nil)
 
-	usd, rub, eur, err := svc.GetUserBalance(ctx, userID)
+	balances, err := svc.GetUserBalance(ctx, userID)
 	assert.Error(t, err)
-	assert.Equal(t, 0.0, usd)
-	assert.Equal(t, 0.0, rub)
-	assert.Equal(t, 0.0, eur)
+	assert.Nil(t, balances)
+}
+
+func TestWalletService_GetUserBalance_ReadModel(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReader := NewMockWalletReader(ctrl)
+	mockProjection := NewMockWalletReader(ctrl)
+	mockProjection.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+		models.USD: money.MustParse("10"),
+		models.RUB: money.MustParse("20"),
+		models.EUR: money.MustParse("30"),
+	}, nil)
+
+	svc := NewWalletService(nil, mockReader, nil, nil, nil, WithBalanceReadModel(mockProjection))
+
+	balances, err := svc.GetUserBalance(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("10"), balances[models.USD])
+	assert.Equal(t, money.MustParse("20"), balances[models.RUB])
+	assert.Equal(t, money.MustParse("30"), balances[models.EUR])
 }
 
 func TestWalletService_GetExchangeRates(t *testing.T) {
@@ -208,11 +395,52 @@ func TestWalletService_GetExchangeRates(t *testing.T) {
 		rateRepo: mockRate,
 	}
 
-	usd, rub, eur, err := svc.GetExchangeRates(ctx)
+	rates, src, err := svc.GetExchangeRates(ctx)
 	assert.NoError(t, err)
-	assert.Equal(t, float32(1.0), usd)
-	assert.Equal(t, float32(95.0), rub)
-	assert.Equal(t, float32(0.92), eur)
+	assert.False(t, src.Stale)
+	assert.Equal(t, "exchanger", src.Provider)
+	assert.Equal(t, float32(1.0), rates[models.USD])
+	assert.Equal(t, float32(95.0), rates[models.RUB])
+	assert.Equal(t, float32(0.92), rates[models.EUR])
+}
+
+func TestWalletService_WithCurrencies(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reader := NewMockWalletReader(ctrl)
+	rates := NewMockExchangeRateReader(ctrl)
+	currencies := NewMockCurrencyLister(ctrl)
+	currencies.EXPECT().Codes(ctx).Return([]string{"CNY", models.EUR, models.USD}).AnyTimes()
+
+	svc := NewWalletService(nil, reader, rates, nil, nil, WithCurrencies(currencies))
+
+	t.Run("balances list every supported currency", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("10")}, nil)
+
+		balances, err := svc.GetUserBalance(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]money.Amount{
+			"CNY":      money.Zero,
+			models.EUR: money.Zero,
+			models.USD: money.MustParse("10"),
+		}, balances)
+	})
+
+	t.Run("rates of unsupported currencies are left out", func(t *testing.T) {
+		rates.EXPECT().GetExchangeRates(ctx).Return(map[string]float32{
+			models.USD: 1.0,
+			models.RUB: 95.0,
+			models.EUR: 0.92,
+		}, nil)
+
+		got, _, err := svc.GetExchangeRates(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]float32{models.USD: 1.0, models.EUR: 0.92}, got)
+	})
 }
 
 func TestWalletService_GetExchangeRates_Error(t *testing.T) {
@@ -228,9 +456,940 @@ func TestWalletService_GetExchangeRates_Error(t *testing.T) {
 		rateRepo: mockRate,
 	}
 
-	usd, rub, eur, err := svc.GetExchangeRates(ctx)
+	rates, _, err := svc.GetExchangeRates(ctx)
 	assert.Error(t, err)
-	assert.Equal(t, float32(0), usd)
-	assert.Equal(t, float32(0), rub)
-	assert.Equal(t, float32(0), eur)
+	assert.Nil(t, rates)
+}
+
+func TestWalletService_GetExchangeRates_Stale(t *testing.T) {
+	ctx := context.Background()
+	cached := map[string]float32{models.USD: 1.0, models.EUR: 0.92}
+	ttl := WithRateTTL(NewAdaptiveRateTTL(30*time.Second, 30*time.Second, 30*time.Second, time.Second))
+
+	t.Run("fresh cached rates are served", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		cache.EXPECT().GetExchangeRates(ctx).Return(cached, models.RateSource{Provider: "grpc", FetchedAt: time.Now().Add(-10 * time.Second)}, nil)
+
+		got, src, err := NewWalletService(nil, nil, NewMockExchangeRateReader(ctrl), cache, nil, ttl).GetExchangeRates(ctx)
+		assert.NoError(t, err)
+		assert.False(t, src.Stale)
+		assert.True(t, src.Cached)
+		assert.Equal(t, "grpc", src.Provider)
+		assert.Equal(t, cached, got)
+	})
+
+	t.Run("fetched rates are cached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		cache.EXPECT().GetExchangeRates(ctx).Return(nil, models.RateSource{}, errors.New("cache miss"))
+		rates.EXPECT().GetExchangeRates(ctx).Return(cached, nil)
+		cache.EXPECT().SetExchangeRates(ctx, cached, gomock.Any()).Return(errors.New("redis down"))
+
+		got, src, err := NewWalletService(nil, nil, rates, cache, nil).GetExchangeRates(ctx)
+		assert.NoError(t, err)
+		assert.False(t, src.Stale)
+		assert.False(t, src.Cached)
+		assert.Equal(t, cached, got)
+	})
+
+	t.Run("cached rates are served stale while exchanger is unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerUnavailable)
+		cache.EXPECT().GetExchangeRates(ctx).Return(cached, models.RateSource{Provider: "grpc", FetchedAt: time.Now().Add(-time.Minute)}, nil)
+
+		got, src, err := NewWalletService(nil, nil, rates, cache, nil, ttl, WithMaxRateStaleness(5*time.Minute)).GetExchangeRates(ctx)
+		assert.NoError(t, err)
+		assert.True(t, src.Stale)
+		assert.True(t, src.Cached)
+		assert.Equal(t, "grpc", src.Provider)
+		assert.Equal(t, cached, got)
+	})
+
+	t.Run("cached rates older than the max staleness are not served", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerTimeout)
+		cache.EXPECT().GetExchangeRates(ctx).Return(cached, models.RateSource{FetchedAt: time.Now().Add(-10 * time.Minute)}, nil)
+
+		_, _, err := NewWalletService(nil, nil, rates, cache, nil, ttl, WithMaxRateStaleness(5*time.Minute)).GetExchangeRates(ctx)
+		assert.ErrorIs(t, err, ErrExchangerTimeout)
+	})
+
+	t.Run("fallback disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		cache.EXPECT().GetExchangeRates(ctx).Return(cached, models.RateSource{FetchedAt: time.Now().Add(-time.Minute)}, nil)
+		rates.EXPECT().GetExchangeRates(ctx).Return(nil, facades.ErrExchangerUnavailable)
+
+		_, _, err := NewWalletService(nil, nil, rates, cache, nil, ttl, WithMaxRateStaleness(0)).GetExchangeRates(ctx)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+}
+
+func TestWalletService_Deposit_RecordsTransaction(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	history := NewMockTransactionStore(ctrl)
+
+	writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, money.MustParse("100"), models.USD).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("100")}, nil)
+	history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+		assert.NotEqual(t, uuid.Nil, txn.TransactionID)
+		assert.Equal(t, userID, txn.UserID)
+		assert.Equal(t, models.OperationDeposit, txn.Operation)
+		assert.Equal(t, models.USD, txn.Currency)
+		assert.Equal(t, money.MustParse("100"), txn.Amount)
+		assert.Nil(t, txn.ToCurrency)
+		return nil
+	})
+
+	svc := NewWalletService(writer, reader, nil, nil, nil, WithTransactionHistory(history, directTx{}))
+	balances, err := svc.Deposit(ctx, userID, money.MustParse("100"), models.USD, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("100"), balances[models.USD])
+}
+
+func TestWalletService_Deposit_HistoryFailure(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	history := NewMockTransactionStore(ctrl)
+	events := NewMockEventPublisher(ctrl)
+	tx := NewMockTransactor(ctrl)
+
+	// The deposit is rolled back with the history entry and not published
+	var rolledBack bool
+	tx.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		err := fn(ctx)
+		rolledBack = err != nil
+		return err
+	})
+	writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, money.MustParse("100"), models.USD).Return(nil)
+	history.EXPECT().Save(ctx, gomock.Any()).Return(errors.New("db error"))
+
+	svc := NewWalletService(writer, nil, nil, nil, events, WithTransactionHistory(history, tx))
+	_, err := svc.Deposit(ctx, userID, money.MustParse("100"), models.USD, "")
+
+	assert.EqualError(t, err, "db error")
+	assert.True(t, rolledBack)
+}
+
+func TestWalletService_Exchange_RecordsTransaction(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	history := NewMockTransactionStore(ctrl)
+
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("100"), money.Zero, models.EUR, money.MustParse("50")).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.Zero, models.EUR: money.MustParse("50")}, nil)
+	history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+		assert.Equal(t, models.OperationExchange, txn.Operation)
+		assert.Equal(t, models.USD, txn.Currency)
+		assert.Equal(t, money.MustParse("100"), txn.Amount)
+		if assert.NotNil(t, txn.ToCurrency) && assert.NotNil(t, txn.ToAmount) {
+			assert.Equal(t, models.EUR, *txn.ToCurrency)
+			assert.Equal(t, money.MustParse("50"), *txn.ToAmount)
+		}
+		if assert.NotNil(t, txn.Rate) && assert.NotNil(t, txn.Fee) {
+			assert.Equal(t, float32(0.5), *txn.Rate)
+			assert.Equal(t, money.Zero, *txn.Fee)
+		}
+		// Saved before the balances are read; the store fills them in
+		assert.Nil(t, txn.Balance)
+		assert.Nil(t, txn.ToBalance)
+		return nil
+	})
+	events := NewMockEventPublisher(ctrl)
+	events.EXPECT().WriteMessages(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msgs ...kafka.Message) error {
+		var event models.Transaction
+		assert.NoError(t, json.Unmarshal(msgs[0].Value, &event))
+		assert.Equal(t, models.USD, event.Currency)
+		assert.Equal(t, models.EUR, event.ToCurrency)
+		if assert.NotNil(t, event.Balance) && assert.NotNil(t, event.ToBalance) {
+			assert.Equal(t, money.Zero, *event.Balance)
+			assert.Equal(t, money.MustParse("50"), *event.ToBalance)
+		}
+		return nil
+	})
+
+	svc := NewWalletService(writer, reader, nil, cache, events, WithTransactionHistory(history, directTx{}))
+	_, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("100"), 0)
+
+	assert.NoError(t, err)
+}
+
+func TestWalletService_Exchange_RecordsReceipt(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	history := NewMockTransactionStore(ctrl)
+	receipts := NewMockExchangeReceiptRecorder(ctrl)
+
+	// The ledger, the transaction history and the receipt share the transaction ID
+	var txnID uuid.UUID
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("100"), money.Zero, models.EUR, money.MustParse("50")).
+		DoAndReturn(func(_ context.Context, transactionID, _ uuid.UUID, _ string, _, _ money.Amount, _ string, _ money.Amount) error {
+			txnID = transactionID
+			return nil
+		})
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("50")}, nil)
+	history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+		assert.Equal(t, txnID, txn.TransactionID)
+		return nil
+	})
+	receipts.EXPECT().Record(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, receipt models.ExchangeReceipt) error {
+		assert.Equal(t, txnID, receipt.TransactionID)
+		assert.Equal(t, models.OperationExchange, receipt.Operation)
+		assert.Equal(t, models.USD, receipt.FromCurrency)
+		assert.Equal(t, models.EUR, receipt.ToCurrency)
+		assert.Equal(t, money.MustParse("100"), receipt.Amount)
+		assert.Equal(t, money.MustParse("50"), receipt.ToAmount)
+		assert.Equal(t, float32(0.5), receipt.Rate)
+		return nil
+	})
+
+	svc := NewWalletService(writer, reader, nil, cache, nil, WithTransactionHistory(history, directTx{}), WithExchangeReceipts(receipts))
+	_, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("100"), 0)
+
+	assert.NoError(t, err)
+}
+
+func TestWalletService_Exchange_RolledBack(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	history := NewMockTransactionStore(ctrl)
+	receipts := NewMockExchangeReceiptRecorder(ctrl)
+	limiter := NewMockSpendingLimiter(ctrl)
+	events := NewMockEventPublisher(ctrl)
+	tx := NewMockTransactor(ctrl)
+
+	// The exchange is rolled back as a whole if any part of it fails, and not published
+	var rolledBack bool
+	tx.EXPECT().InTx(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		err := fn(ctx)
+		rolledBack = err != nil
+		return err
+	})
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+	limiter.EXPECT().Reserve(ctx, userID, models.USD, money.MustParse("100")).Return(int64(7), "", nil)
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("100"), money.Zero, models.EUR, money.MustParse("50")).Return(nil)
+	history.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+	receipts.EXPECT().Record(ctx, gomock.Any()).Return(errors.New("db error"))
+	limiter.EXPECT().Release(ctx, int64(7)).Return(nil)
+
+	svc := NewWalletService(writer, nil, nil, cache, events,
+		WithTransactionHistory(history, tx), WithExchangeReceipts(receipts), WithSpendingLimits(limiter))
+	_, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("100"), 0)
+
+	assert.EqualError(t, err, "db error")
+	assert.True(t, rolledBack)
+}
+
+func TestWalletService_NotifiesWebhooks(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	webhooks := NewMockWebhookNotifier(ctrl)
+	svc := NewWalletService(writer, reader, nil, cache, nil, WithWebhooks(webhooks))
+
+	writer.EXPECT().SaveDeposit(ctx, gomock.Any(), userID, money.MustParse("100"), models.USD).Return(nil)
+	writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), userID, money.MustParse("30"), models.USD).Return(nil)
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), models.RateSource{FetchedAt: time.Now()}, nil)
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("20"), money.Zero, models.EUR, money.MustParse("10")).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("50")}, nil).Times(3)
+
+	var events []models.WebhookEvent
+	webhooks.EXPECT().Notify(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event models.WebhookEvent) error {
+		events = append(events, event)
+		return errors.New("db error") // must not fail the operation
+	}).Times(3)
+
+	_, err := svc.Deposit(ctx, userID, money.MustParse("100"), models.USD, "")
+	assert.NoError(t, err)
+	_, err = svc.Withdraw(ctx, userID, money.MustParse("30"), models.USD, "")
+	assert.NoError(t, err)
+	_, _, err = svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("20"), 0)
+	assert.NoError(t, err)
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, models.WebhookEventDeposit, events[0].Type)
+		assert.Equal(t, models.WebhookEventWithdraw, events[1].Type)
+		assert.Equal(t, models.WebhookEventExchange, events[2].Type)
+		for _, event := range events {
+			assert.Equal(t, userID, event.UserID)
+			assert.NotEqual(t, uuid.Nil, event.TransactionID)
+		}
+		if assert.NotNil(t, events[2].ToAmount) {
+			assert.Equal(t, money.MustParse("10"), *events[2].ToAmount)
+		}
+	}
+}
+
+func TestWalletService_Exchange_RoundsToMinorUnits(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+
+	// 0.10 * 0.7 is 0.07 exactly, not 0.069999... as with float arithmetic
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.7), models.RateSource{FetchedAt: time.Now()}, nil)
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("0.10"), money.Zero, models.EUR, money.MustParse("0.07")).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("0.07")}, nil)
+
+	svc := NewWalletService(writer, reader, nil, cache, nil)
+	executed, balances, err := svc.Exchange(ctx, userID, models.USD, models.EUR, money.MustParse("0.10"), 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("0.07"), executed.ToAmount)
+	assert.Equal(t, money.MustParse("0.07"), balances[models.EUR])
+}
+
+func TestWalletService_Exchange_RoundsToCurrencyPrecision(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	precision := NewMockCurrencyPrecision(ctrl)
+
+	// 1.49 * 0.335 is 0.49915, rounded once to whole units
+	precision.EXPECT().Decimals(ctx, models.RUB).Return(0)
+	cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.RUB).Return(float32(0.335), models.RateSource{FetchedAt: time.Now()}, nil)
+	writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, money.MustParse("1.49"), money.Zero, models.RUB, money.Zero).Return(nil)
+	reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.RUB: money.Zero}, nil)
+
+	svc := NewWalletService(writer, reader, nil, cache, nil, WithCurrencyPrecision(precision))
+	executed, _, err := svc.Exchange(ctx, userID, models.USD, models.RUB, money.MustParse("1.49"), 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.Zero, executed.ToAmount)
+}
+
+func TestWalletService_ListTransactions(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	history := NewMockTransactionStore(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, nil, WithTransactionHistory(history, directTx{}))
+
+	rows := func(ids ...int64) []models.TransactionDB {
+		txns := make([]models.TransactionDB, 0, len(ids))
+		for _, id := range ids {
+			txns = append(txns, models.TransactionDB{ID: id, UserID: userID})
+		}
+		return txns
+	}
+
+	t.Run("next page", func(t *testing.T) {
+		history.EXPECT().
+			List(ctx, models.TransactionFilter{UserID: userID, Currency: models.USD, Limit: 3}).
+			Return(rows(9, 8, 7), nil)
+
+		txns, next, err := svc.ListTransactions(ctx, models.TransactionFilter{UserID: userID, Currency: models.USD, Limit: 2}, "")
+		assert.NoError(t, err)
+		assert.Len(t, txns, 2)
+		assert.NotEmpty(t, next)
+
+		history.EXPECT().
+			List(ctx, models.TransactionFilter{UserID: userID, BeforeID: 8, Limit: 3}).
+			Return(rows(7), nil)
+
+		txns, next, err = svc.ListTransactions(ctx, models.TransactionFilter{UserID: userID, Limit: 2}, next)
+		assert.NoError(t, err)
+		assert.Len(t, txns, 1)
+		assert.Empty(t, next)
+	})
+
+	t.Run("default and max limit", func(t *testing.T) {
+		history.EXPECT().
+			List(ctx, models.TransactionFilter{UserID: userID, Limit: DefaultTransactionPageLimit + 1}).
+			Return(nil, nil)
+		history.EXPECT().
+			List(ctx, models.TransactionFilter{UserID: userID, Limit: MaxTransactionPageLimit + 1}).
+			Return(nil, nil)
+
+		_, _, err := svc.ListTransactions(ctx, models.TransactionFilter{UserID: userID}, "")
+		assert.NoError(t, err)
+		_, _, err = svc.ListTransactions(ctx, models.TransactionFilter{UserID: userID, Limit: 1000}, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"!!!", encodeTransactionCursor(0), "YWJj"} {
+			_, _, err := svc.ListTransactions(ctx, models.TransactionFilter{UserID: userID}, cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		history.EXPECT().List(ctx, gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, _, err := svc.ListTransactions(ctx, models.TransactionFilter{UserID: userID}, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestWalletService_ListExchanges(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	history := NewMockTransactionStore(ctrl)
+	svc := NewWalletService(nil, nil, nil, nil, nil, WithTransactionHistory(history, directTx{}))
+
+	history.EXPECT().
+		List(ctx, models.TransactionFilter{UserID: userID, Operation: models.OperationExchange, FromCurrency: models.USD, ToCurrency: models.EUR, Limit: 3}).
+		Return([]models.TransactionDB{{ID: 5, UserID: userID, Operation: models.OperationExchange}}, nil)
+
+	txns, next, err := svc.ListExchanges(ctx, models.TransactionFilter{UserID: userID, Operation: models.OperationDeposit, FromCurrency: models.USD, ToCurrency: models.EUR, Limit: 2}, "")
+	assert.NoError(t, err)
+	assert.Len(t, txns, 1)
+	assert.Empty(t, next)
+}
+
+func TestWalletService_CloseWallet(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writer := NewMockWalletWriter(ctrl)
+	reader := NewMockWalletReader(ctrl)
+	rates := NewMockExchangeRateReader(ctrl)
+	cache := NewMockExchangeRateCacheReader(ctrl)
+	history := NewMockTransactionStore(ctrl)
+
+	svc := NewWalletService(writer, reader, rates, cache, nil, WithTransactionHistory(history, directTx{}))
+
+	t.Run("payout to another currency", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("100"), models.EUR: money.MustParse("10")}, nil)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), models.RateSource{}, errors.New("miss"))
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.5), nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.5), gomock.Any()).Return(nil)
+		writer.EXPECT().Close(ctx, gomock.Any(), userID, models.USD, models.EUR, float32(0.5)).Return(money.MustParse("100"), money.MustParse("50"), nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("60")}, nil)
+		history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			assert.Equal(t, models.OperationClose, txn.Operation)
+			assert.Equal(t, models.USD, txn.Currency)
+			assert.Equal(t, money.MustParse("100"), txn.Amount)
+			if assert.NotNil(t, txn.ToCurrency) && assert.NotNil(t, txn.ToAmount) {
+				assert.Equal(t, models.EUR, *txn.ToCurrency)
+				assert.Equal(t, money.MustParse("50"), *txn.ToAmount)
+			}
+			return nil
+		})
+
+		credited, balances, err := svc.CloseWallet(ctx, userID, models.USD, models.EUR)
+		assert.NoError(t, err)
+		assert.Equal(t, money.MustParse("50"), credited)
+		assert.Equal(t, money.Zero, balances[models.USD])
+		assert.Equal(t, money.MustParse("60"), balances[models.EUR])
+	})
+
+	t.Run("empty wallet without payout", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.RUB: money.Zero}, nil)
+		writer.EXPECT().Close(ctx, gomock.Any(), userID, models.RUB, "", float32(0)).Return(money.Zero, money.Zero, nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{}, nil)
+		history.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, txn models.TransactionDB) error {
+			assert.Nil(t, txn.ToCurrency)
+			return nil
+		})
+
+		credited, _, err := svc.CloseWallet(ctx, userID, models.RUB, "")
+		assert.NoError(t, err)
+		assert.Equal(t, money.Zero, credited)
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.RUB: money.MustParse("5")}, nil)
+
+		_, _, err := svc.CloseWallet(ctx, userID, models.RUB, "")
+		assert.ErrorIs(t, err, ErrWalletNotEmpty)
+	})
+
+	t.Run("overdrawn", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("-5")}, nil)
+
+		_, _, err := svc.CloseWallet(ctx, userID, models.USD, models.EUR)
+		assert.ErrorIs(t, err, ErrWalletOverdrawn)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{}, nil)
+
+		_, _, err := svc.CloseWallet(ctx, userID, models.RUB, "")
+		assert.ErrorIs(t, err, ErrWalletNotFound)
+	})
+
+	t.Run("changed concurrently", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.RUB: money.Zero}, nil)
+		writer.EXPECT().Close(ctx, gomock.Any(), userID, models.RUB, "", float32(0)).Return(money.Zero, money.Zero, sql.ErrNoRows)
+
+		_, _, err := svc.CloseWallet(ctx, userID, models.RUB, "")
+		assert.ErrorIs(t, err, ErrWalletNotFound)
+	})
+
+	t.Run("exchanger unavailable", func(t *testing.T) {
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.USD: money.MustParse("100")}, nil)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), models.RateSource{}, errors.New("miss"))
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), facades.ErrExchangerUnavailable)
+
+		_, _, err := svc.CloseWallet(ctx, userID, models.USD, models.EUR)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+}
+
+func TestWalletService_CreateWallets(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("created wallets are listed with zero balances", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+
+		writer.EXPECT().Create(ctx, userID, []string{models.USD, models.EUR}).Return([]string{models.EUR}, nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+			models.USD: money.MustParse("100"),
+			models.EUR: money.Zero,
+		}, nil)
+
+		svc := NewWalletService(writer, reader, nil, nil, nil)
+		created, balances, err := svc.CreateWallets(ctx, userID, []string{models.USD, models.EUR})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{models.EUR}, created)
+		assert.Equal(t, map[string]money.Amount{
+			models.USD: money.MustParse("100"),
+			models.EUR: money.Zero,
+		}, balances)
+	})
+
+	t.Run("writer error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		writer.EXPECT().Create(ctx, userID, []string{models.USD}).Return(nil, errors.New("db error"))
+
+		svc := NewWalletService(writer, NewMockWalletReader(ctrl), nil, nil, nil)
+		_, _, err := svc.CreateWallets(ctx, userID, []string{models.USD})
+		assert.Error(t, err)
+	})
+}
+
+func TestWalletService_SpendingLimits(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+
+	t.Run("withdrawal within limits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(1), "", nil)
+		writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), userID, amount, models.USD).Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{}, nil)
+
+		svc := NewWalletService(writer, reader, nil, nil, nil, WithSpendingLimits(limiter))
+		_, err := svc.Withdraw(ctx, userID, amount, models.USD, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("daily limit exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		limiter := NewMockSpendingLimiter(ctrl)
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(0), models.LimitPeriodDaily, nil)
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithSpendingLimits(limiter))
+		_, err := svc.Withdraw(ctx, userID, amount, models.USD, "")
+		assert.ErrorIs(t, err, ErrDailyLimitExceeded)
+	})
+
+	t.Run("failed withdrawal releases the usage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(7), "", nil)
+		writer.EXPECT().SaveWithdraw(ctx, gomock.Any(), userID, amount, models.USD).Return(repositories.ErrInsufficientFunds)
+		limiter.EXPECT().Release(ctx, int64(7)).Return(nil)
+
+		svc := NewWalletService(writer, nil, nil, nil, nil, WithSpendingLimits(limiter))
+		_, err := svc.Withdraw(ctx, userID, amount, models.USD, "")
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+
+	t.Run("exchange monthly limit exceeded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		limiter := NewMockSpendingLimiter(ctrl)
+
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), models.RateSource{FetchedAt: time.Now()}, nil)
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(0), models.LimitPeriodMonthly, nil)
+
+		svc := NewWalletService(nil, nil, nil, cache, nil, WithSpendingLimits(limiter))
+		_, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, amount, 0)
+		assert.ErrorIs(t, err, ErrMonthlyLimitExceeded)
+	})
+
+	t.Run("limiter error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		limiter := NewMockSpendingLimiter(ctrl)
+		limiter.EXPECT().Reserve(ctx, userID, models.USD, amount).Return(int64(0), "", errors.New("db error"))
+
+		svc := NewWalletService(nil, nil, nil, nil, nil, WithSpendingLimits(limiter))
+		_, err := svc.Withdraw(ctx, userID, amount, models.USD, "")
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestWalletService_RateTTL(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	amount := money.MustParse("100")
+
+	exchangeRate := func(t *testing.T, cache *MockExchangeRateCacheReader, rates *MockExchangeRateReader, opts ...WalletOpt) (bool, error) {
+		ctrl := gomock.NewController(t)
+		writer := NewMockWalletWriter(ctrl)
+		reader := NewMockWalletReader(ctrl)
+		writer.EXPECT().SaveExchange(ctx, gomock.Any(), userID, models.USD, amount, money.Zero, models.EUR, gomock.Any()).AnyTimes().Return(nil)
+		reader.EXPECT().GetByUserID(ctx, userID).AnyTimes().Return(map[string]money.Amount{}, nil)
+
+		policy := NewAdaptiveRateTTL(time.Minute, time.Second, time.Hour, time.Second)
+		svc := NewWalletService(writer, reader, rates, cache, nil, append(opts, WithRateTTL(policy))...)
+		executed, _, err := svc.Exchange(ctx, userID, models.USD, models.EUR, amount, 0)
+		return executed.StaleRate, err
+	}
+
+	t.Run("fresh cached rate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), models.RateSource{FetchedAt: time.Now()}, nil)
+
+		stale, err := exchangeRate(t, cache, NewMockExchangeRateReader(ctrl))
+		assert.NoError(t, err)
+		assert.False(t, stale)
+	})
+
+	t.Run("expired cached rate is refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), models.RateSource{FetchedAt: time.Now().Add(-2 * time.Minute)}, nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.8), nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.8), gomock.Any()).Return(nil)
+
+		stale, err := exchangeRate(t, cache, rates)
+		assert.NoError(t, err)
+		assert.False(t, stale)
+	})
+
+	t.Run("expired cached rate is served stale while exchanger is unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), models.RateSource{FetchedAt: time.Now().Add(-2 * time.Minute)}, nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), facades.ErrExchangerTimeout)
+
+		stale, err := exchangeRate(t, cache, rates)
+		assert.NoError(t, err)
+		assert.True(t, stale)
+	})
+
+	t.Run("cached rate older than the max staleness is not served", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), models.RateSource{FetchedAt: time.Now().Add(-10 * time.Minute)}, nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), facades.ErrExchangerUnavailable)
+
+		_, err := exchangeRate(t, cache, rates, WithMaxRateStaleness(5*time.Minute))
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+
+	t.Run("expired cached rate is not served for a missing pair", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), models.RateSource{FetchedAt: time.Now().Add(-2 * time.Minute)}, nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), facades.ErrRateNotFound)
+
+		_, err := exchangeRate(t, cache, rates)
+		assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+	})
+
+	t.Run("no cached rate while exchanger is unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), models.RateSource{}, errors.New("miss"))
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0), facades.ErrExchangerUnavailable)
+
+		_, err := exchangeRate(t, cache, rates)
+		assert.ErrorIs(t, err, ErrExchangerUnavailable)
+	})
+}
+
+func TestWalletService_GetOverdraftLimits(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("without overdrafts", func(t *testing.T) {
+		limits, err := NewWalletService(nil, nil, nil, nil, nil).GetOverdraftLimits(ctx, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, limits)
+	})
+
+	t.Run("reads limits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		overdrafts := NewMockOverdraftReader(ctrl)
+		want := map[string]money.Amount{models.USD: money.MustParse("500")}
+		overdrafts.EXPECT().GetOverdraftLimits(ctx, userID).Return(want, nil)
+
+		limits, err := NewWalletService(nil, nil, nil, nil, nil, WithOverdraftLimits(overdrafts)).GetOverdraftLimits(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, want, limits)
+	})
+
+	t.Run("reader failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		overdrafts := NewMockOverdraftReader(ctrl)
+		overdrafts.EXPECT().GetOverdraftLimits(ctx, userID).Return(nil, errors.New("db down"))
+
+		_, err := NewWalletService(nil, nil, nil, nil, nil, WithOverdraftLimits(overdrafts)).GetOverdraftLimits(ctx, userID)
+		assert.Error(t, err)
+	})
+}
+
+func TestWalletService_GetTotalBalance(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("converts holdings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		svc := NewWalletService(nil, reader, rates, cache, nil)
+
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+			models.USD: money.MustParse("100"),
+			models.EUR: money.MustParse("50"),
+			models.RUB: money.Zero,
+		}, nil)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.EUR, models.USD).Return(float32(1.08), models.RateSource{FetchedAt: time.Now()}, nil)
+
+		total, err := svc.GetTotalBalance(ctx, userID, models.USD)
+		assert.NoError(t, err)
+		assert.Equal(t, models.BalanceTotal{
+			Currency: models.USD,
+			Total:    money.MustParse("154"),
+			Holdings: []models.ConvertedBalance{
+				{Currency: models.EUR, Balance: money.MustParse("50"), Rate: 1.08, Converted: money.MustParse("54")},
+				{Currency: models.USD, Balance: money.MustParse("100"), Rate: 1, Converted: money.MustParse("100")},
+			},
+		}, total)
+	})
+
+	t.Run("rates of several holdings are read from the cache at once", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		svc := NewWalletService(nil, reader, rates, cache, nil, WithRateTTL(NewAdaptiveRateTTL(time.Minute, time.Minute, time.Minute, time.Second)))
+
+		eur := models.CurrencyPair{From: models.EUR, To: models.USD}
+		rub := models.CurrencyPair{From: models.RUB, To: models.USD}
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{
+			models.USD: money.MustParse("100"),
+			models.EUR: money.MustParse("50"),
+			models.RUB: money.MustParse("1000"),
+		}, nil)
+		cache.EXPECT().GetExchangeRatesForCurrencies(ctx, []models.CurrencyPair{eur, rub}).Return(map[models.CurrencyPair]models.PairRate{
+			eur: {Rate: 1.08, Source: models.RateSource{FetchedAt: time.Now()}},
+			rub: {Rate: 0.012, Source: models.RateSource{FetchedAt: time.Now().Add(-time.Hour)}},
+		}, nil)
+		// Устаревший курс запрашивается отдельно, как без пакетного чтения
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0.012), models.RateSource{FetchedAt: time.Now().Add(-time.Hour)}, nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0.011), nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.RUB, models.USD, float32(0.011), gomock.Any()).Return(nil)
+
+		total, err := svc.GetTotalBalance(ctx, userID, models.USD)
+		assert.NoError(t, err)
+		assert.False(t, total.StaleRate)
+		assert.Equal(t, money.MustParse("165"), total.Total)
+		assert.Equal(t, float32(1.08), total.Holdings[0].Rate)
+		assert.Equal(t, float32(0.011), total.Holdings[1].Rate)
+	})
+
+	t.Run("stale rate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		svc := NewWalletService(nil, reader, rates, cache, nil, WithRateTTL(NewAdaptiveRateTTL(time.Minute, time.Minute, time.Minute, time.Second)))
+
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.RUB: money.MustParse("1000")}, nil)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0.011), models.RateSource{FetchedAt: time.Now().Add(-time.Hour)}, nil)
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.RUB, models.USD).Return(float32(0), facades.ErrExchangerUnavailable)
+
+		total, err := svc.GetTotalBalance(ctx, userID, models.USD)
+		assert.NoError(t, err)
+		assert.True(t, total.StaleRate)
+		assert.Equal(t, money.MustParse("11"), total.Total)
+	})
+
+	t.Run("rate not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		reader := NewMockWalletReader(ctrl)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+		svc := NewWalletService(nil, reader, rates, cache, nil)
+
+		reader.EXPECT().GetByUserID(ctx, userID).Return(map[string]money.Amount{models.EUR: money.MustParse("5")}, nil)
+		cache.EXPECT().GetExchangeRateForCurrency(ctx, models.EUR, models.RUB).Return(float32(0), models.RateSource{}, errors.New("miss"))
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.EUR, models.RUB).Return(float32(0), facades.ErrRateNotFound)
+
+		_, err := svc.GetTotalBalance(ctx, userID, models.RUB)
+		assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+	})
+}
+
+func TestWalletService_FetchExchangeRate_Shared(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("concurrent fetches of a pair share one exchanger call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+
+		started, release := make(chan struct{}), make(chan struct{})
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).DoAndReturn(
+			func(context.Context, string, string) (float32, error) {
+				close(started)
+				<-release
+				return 0.9, nil
+			})
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.9), gomock.Any()).Return(nil)
+		svc := NewWalletService(nil, nil, rates, cache, nil)
+
+		results := make(chan float32, 5)
+		go func() {
+			rate, _, _ := svc.fetchExchangeRate(ctx, models.USD, models.EUR)
+			results <- rate
+		}()
+		<-started
+		for range 4 {
+			go func() {
+				rate, _, _ := svc.fetchExchangeRate(ctx, models.USD, models.EUR)
+				results <- rate
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+
+		for range 5 {
+			assert.Equal(t, float32(0.9), <-results)
+		}
+	})
+
+	t.Run("a cancelled request does not fail the others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rates := NewMockExchangeRateReader(ctrl)
+		cache := NewMockExchangeRateCacheReader(ctrl)
+
+		leaderCtx, cancel := context.WithCancel(ctx)
+		started := make(chan struct{})
+		rates.EXPECT().GetExchangeRateForCurrency(leaderCtx, models.USD, models.EUR).DoAndReturn(
+			func(ctx context.Context, _, _ string) (float32, error) {
+				close(started)
+				<-ctx.Done()
+				return 0, ctx.Err()
+			})
+		rates.EXPECT().GetExchangeRateForCurrency(ctx, models.USD, models.EUR).Return(float32(0.9), nil)
+		cache.EXPECT().SetExchangeRateForCurrency(ctx, models.USD, models.EUR, float32(0.9), gomock.Any()).Return(nil)
+		svc := NewWalletService(nil, nil, rates, cache, nil)
+
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, _, err := svc.fetchExchangeRate(leaderCtx, models.USD, models.EUR)
+			leaderErr <- err
+		}()
+		<-started
+
+		type result struct {
+			rate float32
+			err  error
+		}
+		follower := make(chan result, 1)
+		go func() {
+			rate, _, err := svc.fetchExchangeRate(ctx, models.USD, models.EUR)
+			follower <- result{rate, err}
+		}()
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		assert.ErrorIs(t, <-leaderErr, context.Canceled)
+		got := <-follower
+		assert.NoError(t, got.err)
+		assert.Equal(t, float32(0.9), got.rate)
+	})
 }
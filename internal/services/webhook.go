@@ -0,0 +1,320 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/logger"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/metrics"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+var (
+	// ErrInvalidWebhookURL is returned for callback URLs that are not absolute http(s) URLs
+	// or whose host is not a public address.
+	ErrInvalidWebhookURL = errors.New("invalid webhook url")
+	// ErrTooManyWebhooks is returned when the owner already has MaxWebhooksPerOwner webhooks.
+	ErrTooManyWebhooks = errors.New("too many webhooks")
+	// ErrWebhookNotFound is returned when the owner has no webhook with the given ID.
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+const (
+	// MaxWebhooksPerOwner is how many webhooks a user, or the admins together, may register.
+	MaxWebhooksPerOwner = 10
+	// webhookBatchSize is how many due deliveries are claimed at once.
+	webhookBatchSize = 100
+	// webhookLease is how long claimed deliveries are kept from other workers, longer than
+	// sending a batch takes at the 10 second timeout of the webhook client. Deliveries of a
+	// worker that stopped mid-batch are sent again after it.
+	webhookLease = 30 * time.Minute
+	// WebhookRetryMin is the delay before the first redelivery of an event.
+	// It doubles with every failed attempt.
+	WebhookRetryMin = 5 * time.Second
+	// WebhookRetryMax is the longest delay between redeliveries of an event.
+	WebhookRetryMax = time.Hour
+	// WebhookMaxAttempts is how many times an event is sent before the delivery is given up,
+	// about five and a half hours after the first attempt.
+	WebhookMaxAttempts = 15
+)
+
+// Webhook request headers, following the Standard Webhooks specification
+const (
+	WebhookIDHeader        = "Webhook-Id"        // Delivery ID, the same for every attempt
+	WebhookTimestampHeader = "Webhook-Timestamp" // Unix time of the attempt in seconds
+	WebhookSignatureHeader = "Webhook-Signature" // v1,<base64 HMAC-SHA256 of "id.timestamp.body">
+)
+
+// WebhookStore keeps webhooks and the deliveries of events to them.
+type WebhookStore interface {
+	Create(ctx context.Context, webhook models.WebhookDB) error                                         // Registers a webhook
+	List(ctx context.Context, ownerID *uuid.UUID) ([]models.WebhookDB, error)                           // Returns the owner's webhooks, admin ones for nil
+	Delete(ctx context.Context, ownerID *uuid.UUID, webhookID uuid.UUID) error                          // Removes a webhook, sql.ErrNoRows if missing
+	Enqueue(ctx context.Context, userID uuid.UUID, eventType string, payload []byte) (int64, error)     // Queues an event for the user's and admin webhooks
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDeliveryDB, error)   // Claims pending deliveries due for an attempt
+	MarkDelivered(ctx context.Context, deliveryID uuid.UUID) error                                      // Records the delivery
+	MarkFailed(ctx context.Context, deliveryID uuid.UUID, nextAttemptAt time.Time, reason string) error // Records a failed attempt
+	MarkAbandoned(ctx context.Context, deliveryID uuid.UUID, reason string) error                       // Gives the delivery up
+}
+
+// HTTPDoer sends HTTP requests, e.g. *http.Client.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error) // Sends a request and returns the response
+}
+
+// HostResolver resolves host names, e.g. *net.Resolver.
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) // Returns the addresses of host
+}
+
+// WebhookService manages the callback URLs of users and admins and delivers wallet events to them.
+// Events are queued in the database and posted by a background job with exponential backoff.
+// Every request is signed with the webhook secret, so receivers can verify its origin.
+type WebhookService struct {
+	store    WebhookStore
+	client   HTTPDoer
+	resolver HostResolver
+}
+
+// WebhookOpt configures optional behaviour of WebhookService.
+type WebhookOpt func(*WebhookService)
+
+// WithHostResolver sets the resolver checking webhook hosts at registration, net.DefaultResolver by default.
+func WithHostResolver(resolver HostResolver) WebhookOpt {
+	return func(s *WebhookService) {
+		s.resolver = resolver
+	}
+}
+
+// NewWebhookService creates a new WebhookService. The client should refuse non-public
+// addresses and redirects, like the one of NewWebhookClient.
+func NewWebhookService(store WebhookStore, client HTTPDoer, opts ...WebhookOpt) *WebhookService {
+	s := &WebhookService{store: store, client: client, resolver: net.DefaultResolver}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create registers a webhook of the owner, or an admin webhook receiving events of all users
+// if ownerID is nil. The host must resolve to public addresses only, so that webhooks cannot
+// reach the internal network. The returned webhook carries the generated signing secret.
+func (s *WebhookService) Create(ctx context.Context, ownerID *uuid.UUID, rawURL string) (models.WebhookDB, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return models.WebhookDB{}, ErrInvalidWebhookURL
+	}
+	if err := s.checkHost(ctx, u.Hostname()); err != nil {
+		return models.WebhookDB{}, err
+	}
+
+	existing, err := s.store.List(ctx, ownerID)
+	if err != nil {
+		logger.FromContext(ctx).Errorw("failed to list webhooks", "owner_id", ownerID, "error", err)
+		return models.WebhookDB{}, err
+	}
+	if len(existing) >= MaxWebhooksPerOwner {
+		return models.WebhookDB{}, ErrTooManyWebhooks
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return models.WebhookDB{}, err
+	}
+
+	webhook := models.WebhookDB{
+		WebhookID: uuid.New(),
+		UserID:    ownerID,
+		URL:       u.String(),
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.Create(ctx, webhook); err != nil {
+		logger.FromContext(ctx).Errorw("failed to create webhook", "owner_id", ownerID, "error", err)
+		return models.WebhookDB{}, err
+	}
+	return webhook, nil
+}
+
+// checkHost returns ErrInvalidWebhookURL unless every address of host is public.
+func (s *WebhookService) checkHost(ctx context.Context, host string) error {
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		resolved, err := s.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			logger.FromContext(ctx).Warnw("failed to resolve webhook host", "host", host, "error", err)
+			return ErrInvalidWebhookURL
+		}
+		addrs = resolved
+	}
+	if len(addrs) == 0 {
+		return ErrInvalidWebhookURL
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr) {
+			logger.FromContext(ctx).Warnw("webhook host is not public", "host", host, "address", addr)
+			return ErrInvalidWebhookURL
+		}
+	}
+	return nil
+}
+
+// List returns the webhooks of the owner, or the admin webhooks if ownerID is nil.
+func (s *WebhookService) List(ctx context.Context, ownerID *uuid.UUID) ([]models.WebhookDB, error) {
+	return s.store.List(ctx, ownerID)
+}
+
+// Delete removes a webhook of the owner, or an admin webhook if ownerID is nil.
+// Pending deliveries to it are dropped.
+func (s *WebhookService) Delete(ctx context.Context, ownerID *uuid.UUID, webhookID uuid.UUID) error {
+	err := s.store.Delete(ctx, ownerID, webhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// Notify queues the event for the webhooks of its user and the admin webhooks.
+func (s *WebhookService) Notify(ctx context.Context, event models.WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	queued, err := s.store.Enqueue(ctx, event.UserID, event.Type, payload)
+	if err != nil {
+		return err
+	}
+	if queued > 0 {
+		logger.FromContext(ctx).Infow("webhook event queued", "type", event.Type, "transaction_id", event.TransactionID, "deliveries", queued)
+	}
+	return nil
+}
+
+// DeliverPending claims the due deliveries and sends them, so that concurrent workers never
+// send the same delivery at once. A failed delivery is postponed with exponential
+// backoff, or given up after WebhookMaxAttempts, and the other deliveries go on, since
+// every webhook has its own receiver.
+func (s *WebhookService) DeliverPending(ctx context.Context) error {
+	for {
+		deliveries, err := s.store.ClaimDue(ctx, webhookBatchSize, webhookLease)
+		if err != nil {
+			logger.FromContext(ctx).Errorw("failed to claim due webhook deliveries", "error", err)
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			if err := s.deliver(ctx, delivery); err != nil {
+				if markErr := s.markFailed(ctx, delivery, err); markErr != nil {
+					return markErr
+				}
+				continue
+			}
+
+			metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookDelivered).Inc()
+			if err := s.store.MarkDelivered(ctx, delivery.DeliveryID); err != nil {
+				// The event will be delivered again; receivers drop it by Webhook-Id
+				logger.FromContext(ctx).Errorw("failed to mark webhook delivery as delivered", "delivery_id", delivery.DeliveryID, "error", err)
+				return err
+			}
+			logger.FromContext(ctx).Infow("webhook delivered", "delivery_id", delivery.DeliveryID, "webhook_id", delivery.WebhookID,
+				"attempts", delivery.Attempts+1)
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+// markFailed postpones a failed delivery, or gives it up once it ran out of attempts.
+func (s *WebhookService) markFailed(ctx context.Context, delivery models.WebhookDeliveryDB, cause error) error {
+	attempts := delivery.Attempts + 1
+	if attempts >= WebhookMaxAttempts {
+		metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookAbandoned).Inc()
+		logger.FromContext(ctx).Errorw("webhook delivery abandoned", "delivery_id", delivery.DeliveryID, "webhook_id", delivery.WebhookID,
+			"attempts", attempts, "error", cause)
+		return s.store.MarkAbandoned(ctx, delivery.DeliveryID, cause.Error())
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookFailed).Inc()
+	next := time.Now().Add(webhookBackoff(delivery.Attempts))
+	logger.FromContext(ctx).Warnw("failed to deliver webhook", "delivery_id", delivery.DeliveryID, "webhook_id", delivery.WebhookID,
+		"attempts", attempts, "next_attempt_at", next, "error", cause)
+	return s.store.MarkFailed(ctx, delivery.DeliveryID, next, cause.Error())
+}
+
+// deliver posts the signed payload of a delivery. Any 2xx response counts as delivered.
+func (s *WebhookService) deliver(ctx context.Context, delivery models.WebhookDeliveryDB) error {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	id := delivery.DeliveryID.String()
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, id)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(delivery.Secret, id, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain a bounded part of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the Webhook-Signature of a request: the base64 HMAC-SHA256 of
+// "id.timestamp.body" keyed with the webhook secret, prefixed with the scheme version.
+func SignWebhook(secret, id string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.%d.", id, timestamp)
+	mac.Write(body)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookSecret generates a random signing secret.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// webhookBackoff returns the delay before the next attempt after attempts earlier failures.
+func webhookBackoff(attempts int) time.Duration {
+	delay := WebhookRetryMin
+	for i := 0; i < attempts && delay < WebhookRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, WebhookRetryMax)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errWebhookAddressBlocked is returned when a webhook delivery would connect to a non-public address.
+var errWebhookAddressBlocked = errors.New("webhook address is not public")
+
+// nonPublicPrefixes lists shared and translated ranges that netip does not classify as
+// private but which still reach internal networks.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT, also used by cloud metadata services
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 of IPv4 addresses
+}
+
+// IsPublicIP reports whether ip is a public unicast address a webhook may be delivered to:
+// loopback, private (RFC 1918, ULA), link-local (including cloud metadata at
+// 169.254.169.254), multicast and unspecified addresses are not.
+func IsPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewWebhookClient returns the HTTP client delivering webhooks. It connects only to public
+// addresses, checked on the resolved address when dialing, so that a host resolving to an
+// internal address after its registration is refused as well. Redirects are not followed:
+// a redirect response counts as a failed delivery. Proxies from the environment are not used.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(addr.Addr()) {
+				return fmt.Errorf("%w: %s", errWebhookAddressBlocked, addr.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
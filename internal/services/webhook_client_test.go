@@ -0,0 +1,41 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:4700::1111"} {
+		assert.True(t, IsPublicIP(netip.MustParseAddr(ip)), ip)
+	}
+	for _, ip := range []string{
+		"127.0.0.1", "10.0.0.1", "172.16.5.4", "192.168.1.1", "169.254.169.254", "100.100.100.200",
+		"0.0.0.0", "224.0.0.1", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "64:ff9b::a00:1",
+	} {
+		assert.False(t, IsPublicIP(netip.MustParseAddr(ip)), ip)
+	}
+}
+
+func TestNewWebhookClient(t *testing.T) {
+	client := NewWebhookClient(time.Second)
+
+	t.Run("refuses non-public addresses when dialing", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		_, err := client.Post(server.URL, "application/json", nil)
+		assert.ErrorIs(t, err, errWebhookAddressBlocked)
+	})
+
+	t.Run("does not follow redirects", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, client.CheckRedirect(req, []*http.Request{req}), http.ErrUseLastResponse)
+	})
+}
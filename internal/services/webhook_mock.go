@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Github/gw-currency-wallet/internal/services/webhook.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	http "net/http"
+	netip "net/netip"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/sbilibin2017/gw-currency-wallet/internal/models"
+)
+
+// MockWebhookStore is a mock of WebhookStore interface.
+type MockWebhookStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStoreMockRecorder
+}
+
+// MockWebhookStoreMockRecorder is the mock recorder for MockWebhookStore.
+type MockWebhookStoreMockRecorder struct {
+	mock *MockWebhookStore
+}
+
+// NewMockWebhookStore creates a new mock instance.
+func NewMockWebhookStore(ctrl *gomock.Controller) *MockWebhookStore {
+	mock := &MockWebhookStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookStore) EXPECT() *MockWebhookStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockWebhookStore) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDeliveryDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, limit, lease)
+	ret0, _ := ret[0].([]models.WebhookDeliveryDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockWebhookStoreMockRecorder) ClaimDue(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockWebhookStore)(nil).ClaimDue), ctx, limit, lease)
+}
+
+// Create mocks base method.
+func (m *MockWebhookStore) Create(ctx context.Context, webhook models.WebhookDB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookStoreMockRecorder) Create(ctx, webhook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookStore)(nil).Create), ctx, webhook)
+}
+
+// Delete mocks base method.
+func (m *MockWebhookStore) Delete(ctx context.Context, ownerID *uuid.UUID, webhookID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ownerID, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhookStoreMockRecorder) Delete(ctx, ownerID, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookStore)(nil).Delete), ctx, ownerID, webhookID)
+}
+
+// Enqueue mocks base method.
+func (m *MockWebhookStore) Enqueue(ctx context.Context, userID uuid.UUID, eventType string, payload []byte) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, userID, eventType, payload)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockWebhookStoreMockRecorder) Enqueue(ctx, userID, eventType, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockWebhookStore)(nil).Enqueue), ctx, userID, eventType, payload)
+}
+
+// List mocks base method.
+func (m *MockWebhookStore) List(ctx context.Context, ownerID *uuid.UUID) ([]models.WebhookDB, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, ownerID)
+	ret0, _ := ret[0].([]models.WebhookDB)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhookStoreMockRecorder) List(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookStore)(nil).List), ctx, ownerID)
+}
+
+// MarkAbandoned mocks base method.
+func (m *MockWebhookStore) MarkAbandoned(ctx context.Context, deliveryID uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAbandoned", ctx, deliveryID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAbandoned indicates an expected call of MarkAbandoned.
+func (mr *MockWebhookStoreMockRecorder) MarkAbandoned(ctx, deliveryID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAbandoned", reflect.TypeOf((*MockWebhookStore)(nil).MarkAbandoned), ctx, deliveryID, reason)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockWebhookStore) MarkDelivered(ctx context.Context, deliveryID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockWebhookStoreMockRecorder) MarkDelivered(ctx, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockWebhookStore)(nil).MarkDelivered), ctx, deliveryID)
+}
+
+// MarkFailed mocks base method.
+func (m *MockWebhookStore) MarkFailed(ctx context.Context, deliveryID uuid.UUID, nextAttemptAt time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, deliveryID, nextAttemptAt, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockWebhookStoreMockRecorder) MarkFailed(ctx, deliveryID, nextAttemptAt, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockWebhookStore)(nil).MarkFailed), ctx, deliveryID, nextAttemptAt, reason)
+}
+
+// MockHTTPDoer is a mock of HTTPDoer interface.
+type MockHTTPDoer struct {
+	ctrl     *gomock.Controller
+	recorder *MockHTTPDoerMockRecorder
+}
+
+// MockHTTPDoerMockRecorder is the mock recorder for MockHTTPDoer.
+type MockHTTPDoerMockRecorder struct {
+	mock *MockHTTPDoer
+}
+
+// NewMockHTTPDoer creates a new mock instance.
+func NewMockHTTPDoer(ctrl *gomock.Controller) *MockHTTPDoer {
+	mock := &MockHTTPDoer{ctrl: ctrl}
+	mock.recorder = &MockHTTPDoerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHTTPDoer) EXPECT() *MockHTTPDoerMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockHTTPDoer) Do(req *http.Request) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", req)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockHTTPDoerMockRecorder) Do(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockHTTPDoer)(nil).Do), req)
+}
+
+// MockHostResolver is a mock of HostResolver interface.
+type MockHostResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockHostResolverMockRecorder
+}
+
+// MockHostResolverMockRecorder is the mock recorder for MockHostResolver.
+type MockHostResolverMockRecorder struct {
+	mock *MockHostResolver
+}
+
+// NewMockHostResolver creates a new mock instance.
+func NewMockHostResolver(ctrl *gomock.Controller) *MockHostResolver {
+	mock := &MockHostResolver{ctrl: ctrl}
+	mock.recorder = &MockHostResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHostResolver) EXPECT() *MockHostResolverMockRecorder {
+	return m.recorder
+}
+
+// LookupNetIP mocks base method.
+func (m *MockHostResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupNetIP", ctx, network, host)
+	ret0, _ := ret[0].([]netip.Addr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupNetIP indicates an expected call of LookupNetIP.
+func (mr *MockHostResolverMockRecorder) LookupNetIP(ctx, network, host interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupNetIP", reflect.TypeOf((*MockHostResolver)(nil).LookupNetIP), ctx, network, host)
+}
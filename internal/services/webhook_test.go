@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookService_Create(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	public := []netip.Addr{netip.MustParseAddr("93.184.216.34")}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		resolver := NewMockHostResolver(ctrl)
+		resolver.EXPECT().LookupNetIP(ctx, "ip", "example.com").Return(public, nil)
+		store.EXPECT().List(ctx, &ownerID).Return(nil, nil)
+		store.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		webhook, err := NewWebhookService(store, nil, WithHostResolver(resolver)).Create(ctx, &ownerID, "https://example.com/hook")
+		assert.NoError(t, err)
+		assert.Equal(t, &ownerID, webhook.UserID)
+		assert.Equal(t, "https://example.com/hook", webhook.URL)
+		assert.True(t, strings.HasPrefix(webhook.Secret, "whsec_"))
+	})
+
+	t.Run("admin webhook", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		resolver := NewMockHostResolver(ctrl)
+		resolver.EXPECT().LookupNetIP(ctx, "ip", "ops.example.com").Return(public, nil)
+		store.EXPECT().List(ctx, (*uuid.UUID)(nil)).Return(nil, nil)
+		store.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		webhook, err := NewWebhookService(store, nil, WithHostResolver(resolver)).Create(ctx, nil, "http://ops.example.com:8080/events")
+		assert.NoError(t, err)
+		assert.Nil(t, webhook.UserID)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		svc := NewWebhookService(NewMockWebhookStore(gomock.NewController(t)), nil)
+		for _, rawURL := range []string{"", "example.com/hook", "ftp://example.com", "https://", "://bad", "http://:8080/hook"} {
+			_, err := svc.Create(ctx, &ownerID, rawURL)
+			assert.ErrorIs(t, err, ErrInvalidWebhookURL, rawURL)
+		}
+	})
+
+	t.Run("non-public address", func(t *testing.T) {
+		svc := NewWebhookService(NewMockWebhookStore(gomock.NewController(t)), nil)
+		for _, rawURL := range []string{
+			"http://127.0.0.1:6379/", "http://10.0.0.5/hook", "http://192.168.1.1/hook", "http://0.0.0.0/hook",
+			"http://169.254.169.254/latest/meta-data/", "http://[::1]/hook", "http://[fd00::1]/hook", "http://100.100.100.200/",
+		} {
+			_, err := svc.Create(ctx, &ownerID, rawURL)
+			assert.ErrorIs(t, err, ErrInvalidWebhookURL, rawURL)
+		}
+	})
+
+	t.Run("host resolving to a non-public address", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		resolver := NewMockHostResolver(ctrl)
+		resolver.EXPECT().LookupNetIP(ctx, "ip", "internal.example.com").
+			Return([]netip.Addr{public[0], netip.MustParseAddr("10.1.2.3")}, nil)
+
+		svc := NewWebhookService(NewMockWebhookStore(ctrl), nil, WithHostResolver(resolver))
+		_, err := svc.Create(ctx, &ownerID, "https://internal.example.com/hook")
+		assert.ErrorIs(t, err, ErrInvalidWebhookURL)
+	})
+
+	t.Run("unresolvable host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		resolver := NewMockHostResolver(ctrl)
+		resolver.EXPECT().LookupNetIP(ctx, "ip", "missing.example.com").Return(nil, errors.New("no such host"))
+
+		svc := NewWebhookService(NewMockWebhookStore(ctrl), nil, WithHostResolver(resolver))
+		_, err := svc.Create(ctx, &ownerID, "https://missing.example.com/hook")
+		assert.ErrorIs(t, err, ErrInvalidWebhookURL)
+	})
+
+	t.Run("too many webhooks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		resolver := NewMockHostResolver(ctrl)
+		resolver.EXPECT().LookupNetIP(ctx, "ip", "example.com").Return(public, nil)
+		store.EXPECT().List(ctx, &ownerID).Return(make([]models.WebhookDB, MaxWebhooksPerOwner), nil)
+
+		_, err := NewWebhookService(store, nil, WithHostResolver(resolver)).Create(ctx, &ownerID, "https://example.com/hook")
+		assert.ErrorIs(t, err, ErrTooManyWebhooks)
+	})
+}
+
+func TestWebhookService_Delete(t *testing.T) {
+	ctx := context.Background()
+	ownerID := uuid.New()
+	webhookID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	store := NewMockWebhookStore(ctrl)
+	svc := NewWebhookService(store, nil)
+
+	store.EXPECT().Delete(ctx, &ownerID, webhookID).Return(nil)
+	assert.NoError(t, svc.Delete(ctx, &ownerID, webhookID))
+
+	store.EXPECT().Delete(ctx, &ownerID, webhookID).Return(sql.ErrNoRows)
+	assert.ErrorIs(t, svc.Delete(ctx, &ownerID, webhookID), ErrWebhookNotFound)
+}
+
+func TestWebhookService_Notify(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	store := NewMockWebhookStore(ctrl)
+
+	event := models.WebhookEvent{
+		Type:          models.WebhookEventDeposit,
+		TransactionID: uuid.New(),
+		UserID:        uuid.New(),
+		Currency:      models.USD,
+		Amount:        money.MustParse("100"),
+		OccurredAt:    time.Now().UTC(),
+	}
+	store.EXPECT().Enqueue(ctx, event.UserID, models.WebhookEventDeposit, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, _ string, payload []byte) (int64, error) {
+			var got models.WebhookEvent
+			assert.NoError(t, json.Unmarshal(payload, &got))
+			assert.Equal(t, event, got)
+			return 2, nil
+		})
+
+	assert.NoError(t, NewWebhookService(store, nil).Notify(ctx, event))
+}
+
+func TestWebhookService_DeliverPending(t *testing.T) {
+	ctx := context.Background()
+
+	newDelivery := func(url string, attempts int) models.WebhookDeliveryDB {
+		return models.WebhookDeliveryDB{
+			DeliveryID: uuid.New(),
+			WebhookID:  uuid.New(),
+			URL:        url,
+			Secret:     "whsec_test",
+			EventType:  models.WebhookEventDeposit,
+			Payload:    `{"type":"wallet.deposit"}`,
+			Attempts:   attempts,
+		}
+	}
+
+	t.Run("signed delivery", func(t *testing.T) {
+		var delivery models.WebhookDeliveryDB
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, delivery.DeliveryID.String(), r.Header.Get(WebhookIDHeader))
+			assert.Equal(t, SignWebhook("whsec_test", delivery.DeliveryID.String(), timestamp, body), r.Header.Get(WebhookSignatureHeader))
+			assert.Equal(t, delivery.Payload, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		delivery = newDelivery(server.URL, 0)
+		store.EXPECT().ClaimDue(ctx, webhookBatchSize, webhookLease).Return([]models.WebhookDeliveryDB{delivery}, nil)
+		store.EXPECT().MarkDelivered(ctx, delivery.DeliveryID).Return(nil)
+
+		assert.NoError(t, NewWebhookService(store, server.Client()).DeliverPending(ctx))
+	})
+
+	t.Run("failure is retried with backoff and others go on", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer working.Close()
+
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		first, second := newDelivery(failing.URL, 2), newDelivery(working.URL, 0)
+		store.EXPECT().ClaimDue(ctx, webhookBatchSize, webhookLease).Return([]models.WebhookDeliveryDB{first, second}, nil)
+		store.EXPECT().MarkFailed(ctx, first.DeliveryID, gomock.Any(), "unexpected response status 503 Service Unavailable").DoAndReturn(
+			func(_ context.Context, _ uuid.UUID, next time.Time, _ string) error {
+				assert.WithinDuration(t, time.Now().Add(4*WebhookRetryMin), next, time.Second)
+				return nil
+			})
+		store.EXPECT().MarkDelivered(ctx, second.DeliveryID).Return(nil)
+
+		assert.NoError(t, NewWebhookService(store, http.DefaultClient).DeliverPending(ctx))
+	})
+
+	t.Run("abandoned after max attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		delivery := newDelivery("http://127.0.0.1:1/hook", WebhookMaxAttempts-1)
+		store.EXPECT().ClaimDue(ctx, webhookBatchSize, webhookLease).Return([]models.WebhookDeliveryDB{delivery}, nil)
+		store.EXPECT().MarkAbandoned(ctx, delivery.DeliveryID, gomock.Any()).Return(nil)
+
+		assert.NoError(t, NewWebhookService(store, http.DefaultClient).DeliverPending(ctx))
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		store := NewMockWebhookStore(ctrl)
+		store.EXPECT().ClaimDue(ctx, webhookBatchSize, webhookLease).Return(nil, errors.New("db down"))
+
+		assert.Error(t, NewWebhookService(store, http.DefaultClient).DeliverPending(ctx))
+	})
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, WebhookRetryMin, webhookBackoff(0))
+	assert.Equal(t, 4*WebhookRetryMin, webhookBackoff(2))
+	assert.Equal(t, WebhookRetryMax, webhookBackoff(20))
+}
@@ -0,0 +1,97 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+)
+
+// UserOption adjusts a user before CreateUser inserts it.
+type UserOption func(*models.UserDB)
+
+// WithUsername sets the username and derives the email from it.
+func WithUsername(username string) UserOption {
+	return func(u *models.UserDB) {
+		u.Username = username
+		u.Email = username + "@example.com"
+	}
+}
+
+// WithRole sets the user role.
+func WithRole(role string) UserOption {
+	return func(u *models.UserDB) { u.Role = role }
+}
+
+// WithCreatedAt backdates the user.
+func WithCreatedAt(createdAt time.Time) UserOption {
+	return func(u *models.UserDB) {
+		u.CreatedAt = createdAt
+		u.UpdatedAt = createdAt
+	}
+}
+
+// CreateUser inserts a user with a unique username and email and returns the
+// stored row.
+func CreateUser(t testing.TB, db *sqlx.DB, opts ...UserOption) models.UserDB {
+	t.Helper()
+
+	now := time.Now().UTC()
+	userID := uuid.New()
+	user := models.UserDB{
+		UserID:       userID,
+		PasswordHash: "password123",
+		Role:         models.RoleUser,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	WithUsername("user-" + userID.String()[:8])(&user)
+	for _, opt := range opts {
+		opt(&user)
+	}
+
+	query := `
+		INSERT INTO users (user_id, username, email, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING user_id, username, email, password_hash, role, dormant_at, created_at, updated_at
+	`
+	var created models.UserDB
+	err := db.GetContext(context.Background(), &created, query,
+		user.UserID, user.Username, user.Email, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return created
+}
+
+// CreateWallet inserts the user's wallet in currency. A non-zero balance is posted to
+// the ledger as an opening balance funded from the external account, the same way
+// the ledger migration backfilled existing wallets, so reconciliation finds no
+// mismatch.
+func CreateWallet(t testing.TB, db *sqlx.DB, userID uuid.UUID, currency string, balance money.Amount) {
+	t.Helper()
+
+	query := `
+		WITH wallet AS (
+			INSERT INTO wallets (user_id, currency, balance)
+			VALUES ($1, $2, $3)
+			RETURNING wallet_id, user_id, currency, balance
+		),
+		posted AS (
+			INSERT INTO ledger_transactions (transaction_id, operation)
+			SELECT wallet_id, 'opening' FROM wallet WHERE balance <> 0
+			RETURNING transaction_id
+		)
+		INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+		SELECT p.transaction_id, 'wallet', w.user_id, w.currency, w.balance FROM posted p, wallet w
+		UNION ALL
+		SELECT p.transaction_id, 'external', NULL, w.currency, -w.balance FROM posted p, wallet w
+	`
+	if _, err := db.ExecContext(context.Background(), query, userID, currency, balance); err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// kafkaStartScript is copied into the container once the mapped port is known. The
+// container command waits for it, so the broker advertises an address reachable
+// from the test.
+const kafkaStartScript = "/tmp/testkit_start.sh"
+
+// Kafka starts a single-node Kafka broker in KRaft mode and returns its address.
+// Topics are created on first use.
+func Kafka(t testing.TB) string {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        KafkaImage,
+			ExposedPorts: []string{"9092/tcp"},
+			Env: map[string]string{
+				"CLUSTER_ID":                                     "testkit-kafka-cluster",
+				"KAFKA_NODE_ID":                                  "1",
+				"KAFKA_PROCESS_ROLES":                            "broker,controller",
+				"KAFKA_LISTENERS":                                "PLAINTEXT://0.0.0.0:9092,BROKER://0.0.0.0:9093,CONTROLLER://0.0.0.0:9094",
+				"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "PLAINTEXT:PLAINTEXT,BROKER:PLAINTEXT,CONTROLLER:PLAINTEXT",
+				"KAFKA_INTER_BROKER_LISTENER_NAME":               "BROKER",
+				"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+				"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9094",
+				"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+				"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+				"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+				"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+				"KAFKA_AUTO_CREATE_TOPICS_ENABLE":                "true",
+			},
+			Entrypoint: []string{"sh"},
+			Cmd: []string{"-c", fmt.Sprintf(
+				"while [ ! -f %[1]s ]; do sleep 0.1; done; sh %[1]s", kafkaStartScript)},
+			LifecycleHooks: []testcontainers.ContainerLifecycleHooks{{
+				PostStarts: []testcontainers.ContainerHook{writeKafkaStartScript},
+			}},
+			WaitingFor: wait.ForLog("Kafka Server started").WithStartupTimeout(90 * time.Second),
+		},
+		Started: true,
+	})
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start kafka: %v", err)
+	}
+
+	broker, err := kafkaBroker(ctx, container)
+	if err != nil {
+		t.Fatalf("kafka address: %v", err)
+	}
+	return broker
+}
+
+// writeKafkaStartScript advertises the mapped port and starts the broker.
+func writeKafkaStartScript(ctx context.Context, c testcontainers.Container) error {
+	broker, err := kafkaBroker(ctx, c)
+	if err != nil {
+		return err
+	}
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+
+	script := strings.Join([]string{
+		"#!/bin/sh",
+		fmt.Sprintf("export KAFKA_ADVERTISED_LISTENERS=PLAINTEXT://%s,BROKER://%s:9093", broker, inspect.Config.Hostname),
+		"exec /etc/kafka/docker/run",
+	}, "\n") + "\n"
+	return c.CopyToContainer(ctx, []byte(script), kafkaStartScript, 0o755)
+}
+
+func kafkaBroker(ctx context.Context, c testcontainers.Container) (string, error) {
+	host, err := c.Host(ctx)
+	if err != nil {
+		return "", err
+	}
+	port, err := c.MappedPort(ctx, "9092/tcp")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d", host, port.Int()), nil
+}
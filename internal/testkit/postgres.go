@@ -0,0 +1,152 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/gw-currency-wallet/migrations"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	postgresUser     = "postgres"
+	postgresPassword = "secret"
+	postgresDatabase = "testdb"
+)
+
+var upSection = regexp.MustCompile(`(?is)--\s*\+goose\s+Up(.*?)(?:--\s*\+goose\s+Down|$)`)
+
+// PostgresEnv is a running Postgres container with the migrations applied.
+type PostgresEnv struct {
+	DB       *sqlx.DB
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+}
+
+// Postgres starts a Postgres container, applies the embedded migrations and returns
+// a connection to it.
+func Postgres(t testing.TB) *sqlx.DB {
+	t.Helper()
+	return StartPostgres(t).DB
+}
+
+// StartPostgres starts a Postgres container and applies the embedded migrations.
+// Use it instead of Postgres when the test needs the connection settings, e.g. to
+// run the whole application against the database.
+func StartPostgres(t testing.TB) *PostgresEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: PostgresImage,
+			Env: map[string]string{
+				"POSTGRES_USER":     postgresUser,
+				"POSTGRES_PASSWORD": postgresPassword,
+				"POSTGRES_DB":       postgresDatabase,
+			},
+			ExposedPorts: []string{"5432/tcp"},
+			// The image restarts the server once after the init scripts, so the log
+			// line appears twice before it accepts connections for good.
+			WaitingFor: wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start postgres: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("postgres host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatalf("postgres port: %v", err)
+	}
+
+	env := &PostgresEnv{
+		Host:     host,
+		Port:     port.Int(),
+		User:     postgresUser,
+		Password: postgresPassword,
+		Database: postgresDatabase,
+	}
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", env.User, env.Password, env.Host, env.Port, env.Database)
+	env.DB, err = sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("connect to postgres: %v", err)
+	}
+	t.Cleanup(func() { env.DB.Close() })
+
+	env.DB.SetMaxOpenConns(20)
+	env.DB.SetMaxIdleConns(10)
+	env.DB.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := Migrate(ctx, env.DB, migrations.Files); err != nil {
+		t.Fatalf("migrate postgres: %v", err)
+	}
+	return env
+}
+
+// Migrate applies the Up sections of the *.sql goose migrations in fsys in file name
+// order. Each file runs as a single multi-statement query, so function bodies
+// between StatementBegin and StatementEnd need no special handling.
+func Migrate(ctx context.Context, db *sqlx.DB, fsys fs.FS) error {
+	ups, err := upSections(fsys)
+	if err != nil {
+		return err
+	}
+	for _, up := range ups {
+		// No arguments, so pgx sends the file with the simple query protocol,
+		// which accepts several statements at once.
+		if _, err := db.ExecContext(ctx, up.sql); err != nil {
+			return fmt.Errorf("migration %s: %w", up.name, err)
+		}
+	}
+	return nil
+}
+
+type migration struct {
+	name string
+	sql  string
+}
+
+// upSections returns the Up section of every *.sql migration in fsys in file name order.
+func upSections(fsys fs.FS) ([]migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	ups := make([]migration, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		m := upSection.FindStringSubmatch(string(data))
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: no goose Up section", path.Base(name))
+		}
+		ups = append(ups, migration{name: path.Base(name), sql: m[1]})
+	}
+	return ups, nil
+}
@@ -0,0 +1,59 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// RedisEnv is a running Redis container.
+type RedisEnv struct {
+	Client *redis.Client
+	Host   string
+	Port   int
+}
+
+// Redis starts a Redis container and returns a client connected to it.
+func Redis(t testing.TB) *redis.Client {
+	t.Helper()
+	return StartRedis(t).Client
+}
+
+// StartRedis starts a Redis container. Use it instead of Redis when the test needs
+// the address, e.g. to run the whole application against it.
+func StartRedis(t testing.TB) *RedisEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        RedisImage,
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForListeningPort("6379/tcp").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start redis: %v", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("redis host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		t.Fatalf("redis port: %v", err)
+	}
+
+	env := &RedisEnv{Host: host, Port: port.Int()}
+	env.Client = redis.NewClient(&redis.Options{Addr: fmt.Sprintf("%s:%d", env.Host, env.Port)})
+	t.Cleanup(func() { env.Client.Close() })
+	return env
+}
@@ -0,0 +1,11 @@
+// Package testkit starts disposable Postgres, Redis and Kafka containers for
+// integration tests and seeds them with users and wallets. Every container is
+// removed when the test that started it finishes.
+package testkit
+
+// Container images used by the helpers
+const (
+	PostgresImage = "postgres:15-alpine"
+	RedisImage    = "redis:7.0-alpine"
+	KafkaImage    = "apache/kafka:3.8.0"
+)
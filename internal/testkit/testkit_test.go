@@ -0,0 +1,126 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/models"
+	"github.com/sbilibin2017/gw-currency-wallet/internal/money"
+	"github.com/sbilibin2017/gw-currency-wallet/migrations"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpSections(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_second.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INT);\n-- +goose Down\nDROP TABLE b;\n")},
+		"000001_first.sql":  {Data: []byte("-- +goose Up\nCREATE TABLE a (id INT);\n")},
+		"README.md":         {Data: []byte("not a migration")},
+	}
+
+	ups, err := upSections(fsys)
+	assert.NoError(t, err)
+	if assert.Len(t, ups, 2) {
+		assert.Equal(t, "000001_first.sql", ups[0].name)
+		assert.Contains(t, ups[0].sql, "CREATE TABLE a")
+		assert.Equal(t, "000002_second.sql", ups[1].name)
+		assert.Contains(t, ups[1].sql, "CREATE TABLE b")
+		assert.NotContains(t, ups[1].sql, "DROP TABLE b")
+	}
+
+	_, err = upSections(fstest.MapFS{"000001_broken.sql": {Data: []byte("CREATE TABLE a (id INT);")}})
+	assert.ErrorContains(t, err, "000001_broken.sql")
+}
+
+func TestUpSections_Embedded(t *testing.T) {
+	ups, err := upSections(migrations.Files)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ups)
+}
+
+func TestPostgres_Factories(t *testing.T) {
+	db := Postgres(t)
+	ctx := context.Background()
+
+	user := CreateUser(t, db)
+	assert.NotEqual(t, uuid.Nil, user.UserID)
+	assert.Equal(t, models.RoleUser, user.Role)
+
+	admin := CreateUser(t, db, WithUsername("root"), WithRole(models.RoleAdmin))
+	assert.Equal(t, "root", admin.Username)
+	assert.Equal(t, "root@example.com", admin.Email)
+	assert.Equal(t, models.RoleAdmin, admin.Role)
+
+	longAgo := time.Now().UTC().AddDate(-2, 0, 0).Truncate(time.Second)
+	old := CreateUser(t, db, WithCreatedAt(longAgo))
+	assert.True(t, old.CreatedAt.Equal(longAgo))
+
+	CreateWallet(t, db, user.UserID, "USD", money.MustParse("100.50"))
+	CreateWallet(t, db, user.UserID, "EUR", 0)
+
+	var balance money.Amount
+	err := db.GetContext(ctx, &balance, `SELECT balance FROM wallets WHERE user_id = $1 AND currency = 'USD'`, user.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("100.50"), balance)
+
+	var ledgerBalance money.Amount
+	err = db.GetContext(ctx, &ledgerBalance, `
+		SELECT COALESCE(SUM(amount), 0) FROM ledger_entries
+		WHERE account = 'wallet' AND user_id = $1 AND currency = 'USD'`, user.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, balance, ledgerBalance)
+
+	var entries int
+	err = db.GetContext(ctx, &entries, `SELECT COUNT(*) FROM ledger_entries WHERE user_id = $1 AND currency = 'EUR'`, user.UserID)
+	assert.NoError(t, err)
+	assert.Zero(t, entries)
+
+	// Migration seeds are in place
+	var currencies int
+	err = db.GetContext(ctx, &currencies, `SELECT COUNT(*) FROM currencies`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, currencies)
+}
+
+func TestRedis(t *testing.T) {
+	rdb := Redis(t)
+	ctx := context.Background()
+
+	assert.NoError(t, rdb.Set(ctx, "key", "value", time.Minute).Err())
+	value, err := rdb.Get(ctx, "key").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
+
+func TestKafka(t *testing.T) {
+	broker := Kafka(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(broker),
+		Topic:                  "testkit",
+		AllowAutoTopicCreation: true,
+	}
+	defer writer.Close()
+
+	// The first write may race the topic creation
+	var err error
+	for range 10 {
+		if err = writer.WriteMessages(ctx, kafka.Message{Key: []byte("key"), Value: []byte("value")}); err == nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	assert.NoError(t, err)
+
+	reader := kafka.NewReader(kafka.ReaderConfig{Brokers: []string{broker}, Topic: "testkit"})
+	defer reader.Close()
+
+	msg, err := reader.ReadMessage(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", string(msg.Value))
+}
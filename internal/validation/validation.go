@@ -0,0 +1,194 @@
+// Package validation checks request bodies against the rules of their validate struct tags.
+//
+// Rules are comma separated and checked in order; a field reports only its first failing rule:
+//
+//	required            not the zero value
+//	required_without=F  not the zero value unless field F is set
+//	omitempty           skips the remaining rules if the value is the zero value
+//	min=N, max=N, len=N length of strings in characters, or of slices and maps
+//	gt=N, gte=N         numbers
+//	email               an address with a local part and a domain
+//	uuid                a UUID
+//	oneof=A B C         one of the values, separated by spaces
+//
+// Fields are named in errors by their JSON names.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// FieldError is an invalid field of a request.
+type FieldError struct {
+	// JSON name of the field
+	// default: amount
+	Field string `json:"field"`
+
+	// Rule the value does not satisfy
+	// default: gt
+	Rule string `json:"rule"`
+
+	// Parameter of the rule, if any
+	// default: 0
+	Param string `json:"param,omitempty"`
+
+	// Human-readable description of the rule
+	// default: must be greater than 0
+	Message string `json:"message"`
+}
+
+// Errors are the invalid fields of a request, in declaration order.
+type Errors []FieldError
+
+// Error implements error.
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, f := range e {
+		parts[i] = f.Field + " " + f.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Struct checks the fields of v, a struct or a pointer to one, and returns the invalid ones,
+// nil if all are valid. An invalid tag panics, like a bad route pattern.
+func Struct(v any) Errors {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: %T is not a struct", v))
+	}
+
+	var errs Errors
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		tag, ok := typ.Field(i).Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+		if err := checkField(val, i, tag); err != nil {
+			err.Field = jsonName(typ.Field(i))
+			errs = append(errs, *err)
+		}
+	}
+	return errs
+}
+
+// checkField returns the first rule of tag the i-th field of val does not satisfy.
+func checkField(val reflect.Value, i int, tag string) *FieldError {
+	field := val.Field(i)
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			if field.IsZero() {
+				return nil
+			}
+			continue
+		case "required_without":
+			other := val.FieldByName(param)
+			if !other.IsValid() {
+				panic(fmt.Sprintf("validation: unknown field %s in %s", param, val.Type()))
+			}
+			if !other.IsZero() {
+				continue
+			}
+			name, param = "required", ""
+		}
+		if ok, message := check(name, param, field); !ok {
+			return &FieldError{Rule: name, Param: param, Message: message}
+		}
+	}
+	return nil
+}
+
+// check reports whether field satisfies the rule and, if not, describes the rule.
+func check(rule, param string, field reflect.Value) (bool, string) {
+	switch rule {
+	case "required":
+		return !field.IsZero(), "is required"
+	case "min":
+		return length(field) >= number(param), "must be at least " + param + " long"
+	case "max":
+		return length(field) <= number(param), "must be at most " + param + " long"
+	case "len":
+		return length(field) == number(param), "must be " + param + " long"
+	case "gt":
+		return numeric(field) > float(param), "must be greater than " + param
+	case "gte":
+		return numeric(field) >= float(param), "must not be less than " + param
+	case "email":
+		addr, err := mail.ParseAddress(field.String())
+		return err == nil && addr.Address == field.String() && strings.Contains(addr.Address, "@"), "must be an email address"
+	case "uuid":
+		_, err := uuid.Parse(field.String())
+		return err == nil, "must be a UUID"
+	case "oneof":
+		for _, v := range strings.Fields(param) {
+			if fmt.Sprint(field.Interface()) == v {
+				return true, ""
+			}
+		}
+		return false, "must be one of " + strings.Join(strings.Fields(param), ", ")
+	default:
+		panic("validation: unknown rule " + rule)
+	}
+}
+
+// length returns the length of a string in characters, or of a slice or map.
+func length(field reflect.Value) int {
+	switch field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String())
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return field.Len()
+	default:
+		panic("validation: length of " + field.Type().String())
+	}
+}
+
+// numeric returns the value of an integer or floating-point field.
+func numeric(field reflect.Value) float64 {
+	switch {
+	case field.CanInt():
+		return float64(field.Int())
+	case field.CanUint():
+		return float64(field.Uint())
+	case field.CanFloat():
+		return field.Float()
+	default:
+		panic("validation: number of " + field.Type().String())
+	}
+}
+
+// number parses an integer rule parameter.
+func number(param string) int {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		panic("validation: invalid integer " + param)
+	}
+	return n
+}
+
+// float parses a numeric rule parameter.
+func float(param string) float64 {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic("validation: invalid number " + param)
+	}
+	return n
+}
+
+// jsonName returns the name of the field in JSON.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
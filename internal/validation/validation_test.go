@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type request struct {
+	QuoteID  string  `json:"quote_id,omitempty" validate:"omitempty,uuid"`
+	Currency string  `json:"currency" validate:"required_without=QuoteID"`
+	Amount   int64   `json:"amount" validate:"required_without=QuoteID,gte=0"`
+	Rate     float64 `json:"rate" validate:"gt=0.5"`
+	Name     string  `json:"name" validate:"required,min=2,max=4"`
+	Email    string  `json:"email,omitempty" validate:"omitempty,email"`
+	Format   string  `json:"format" validate:"oneof=csv zip"`
+	Tags     []string
+	Code     string `validate:"len=3"`
+}
+
+func valid() request {
+	return request{Currency: "USD", Amount: 10, Rate: 1, Name: "имя", Format: "csv", Code: "USD"}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *request)
+		want   Errors
+	}{
+		{name: "valid", modify: func(r *request) {}},
+		{
+			name:   "quote_without_amount",
+			modify: func(r *request) { r.QuoteID, r.Currency, r.Amount = "3fa85f64-5717-4562-b3fc-2c963f66afa6", "", 0 },
+		},
+		{
+			name:   "required_without",
+			modify: func(r *request) { r.Currency, r.Amount = "", 0 },
+			want: Errors{
+				{Field: "currency", Rule: "required", Message: "is required"},
+				{Field: "amount", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:   "numbers",
+			modify: func(r *request) { r.Amount, r.Rate = -1, 0.5 },
+			want: Errors{
+				{Field: "amount", Rule: "gte", Param: "0", Message: "must not be less than 0"},
+				{Field: "rate", Rule: "gt", Param: "0.5", Message: "must be greater than 0.5"},
+			},
+		},
+		{
+			name:   "lengths",
+			modify: func(r *request) { r.Name, r.Code = "имя12", "US" },
+			want: Errors{
+				{Field: "name", Rule: "max", Param: "4", Message: "must be at most 4 long"},
+				{Field: "Code", Rule: "len", Param: "3", Message: "must be 3 long"},
+			},
+		},
+		{
+			name:   "first_failing_rule",
+			modify: func(r *request) { r.Name = "" },
+			want:   Errors{{Field: "name", Rule: "required", Message: "is required"}},
+		},
+		{
+			name:   "formats",
+			modify: func(r *request) { r.QuoteID, r.Email, r.Format = "42", "john", "pdf" },
+			want: Errors{
+				{Field: "quote_id", Rule: "uuid", Message: "must be a UUID"},
+				{Field: "email", Rule: "email", Message: "must be an email address"},
+				{Field: "format", Rule: "oneof", Param: "csv zip", Message: "must be one of csv, zip"},
+			},
+		},
+		{
+			name:   "email_with_name",
+			modify: func(r *request) { r.Email = "John <john@example.com>" },
+			want:   Errors{{Field: "email", Rule: "email", Message: "must be an email address"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+			assert.Equal(t, tt.want, Struct(&r))
+		})
+	}
+}
+
+func TestErrors_Error(t *testing.T) {
+	errs := Errors{
+		{Field: "amount", Rule: "gt", Param: "0", Message: "must be greater than 0"},
+		{Field: "currency", Rule: "required", Message: "is required"},
+	}
+	assert.EqualError(t, errs, "amount must be greater than 0; currency is required")
+}
+
+func TestStruct_InvalidTag(t *testing.T) {
+	assert.Panics(t, func() {
+		Struct(struct {
+			Name string `validate:"unknown"`
+		}{})
+	})
+	assert.Panics(t, func() { Struct("not a struct") })
+}
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS wallet_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    operation VARCHAR(20) NOT NULL,   -- deposit, withdraw
+    amount NUMERIC(20, 2) NOT NULL,
+    balance NUMERIC(20, 2) NOT NULL,  -- balance after the operation
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS balance_projections (
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    balance NUMERIC(20, 2) NOT NULL DEFAULT 0.0,
+    last_event_id BIGINT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency)
+);
+
+CREATE TABLE IF NOT EXISTS projection_checkpoints (
+    name VARCHAR(50) PRIMARY KEY,
+    last_event_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO projection_checkpoints (name, last_event_id) VALUES ('balances', 0)
+ON CONFLICT (name) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS projection_checkpoints;
+DROP TABLE IF EXISTS balance_projections;
+DROP TABLE IF EXISTS wallet_events;
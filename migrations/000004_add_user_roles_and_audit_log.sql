@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'; -- user, admin
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID NOT NULL,           -- user who performed the action
+    action VARCHAR(50) NOT NULL,      -- e.g. impersonate
+    target_id UUID,                   -- affected user, if any
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target_id ON audit_log (target_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS exports (
+    export_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    format VARCHAR(30) NOT NULL,                      -- e.g. accounting_csv
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',    -- pending, processing, completed, failed
+    content BYTEA,                                    -- generated file, set when completed
+    error TEXT,                                       -- failure reason, set when failed
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_exports_status ON exports (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_wallet_events_user_id ON wallet_events (user_id, event_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_events_user_id;
+DROP TABLE IF EXISTS exports;
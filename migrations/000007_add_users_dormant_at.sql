@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS dormant_at TIMESTAMP; -- set when the account is flagged as dormant
+
+CREATE INDEX IF NOT EXISTS idx_wallet_events_user_created_at ON wallet_events (user_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_events_user_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS dormant_at;
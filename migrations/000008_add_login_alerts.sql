@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE auth_events ADD COLUMN IF NOT EXISTS country VARCHAR(2) NOT NULL DEFAULT ''; -- ISO 3166-1 alpha-2, empty if unknown
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    sms_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    phone VARCHAR(20),
+    security_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_preferences;
+ALTER TABLE auth_events DROP COLUMN IF EXISTS country;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS transactions (
+    id BIGSERIAL PRIMARY KEY,                -- cursor for pagination
+    transaction_id UUID NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    operation VARCHAR(20) NOT NULL,          -- deposit, withdraw, exchange, close
+    currency CHAR(3) NOT NULL,               -- source currency for exchanges
+    amount NUMERIC(20, 2) NOT NULL,
+    to_currency CHAR(3),                     -- exchanges and payouts on closure
+    to_amount NUMERIC(20, 2),                -- exchanges and payouts on closure
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions (user_id, id DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS transactions;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS wallet_limits (
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    daily_limit NUMERIC(20, 2),              -- NULL means unlimited
+    monthly_limit NUMERIC(20, 2),            -- NULL means unlimited
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency)
+);
+
+CREATE TABLE IF NOT EXISTS wallet_limit_usage (
+    usage_id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,          -- withdrawn or exchanged amount
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_limit_usage_user_id ON wallet_limit_usage (user_id, currency, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallet_limit_usage;
+DROP TABLE IF EXISTS wallet_limits;
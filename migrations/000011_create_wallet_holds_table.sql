@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS held NUMERIC(20, 2) NOT NULL DEFAULT 0.0; -- reserved by pending holds
+
+ALTER TABLE wallets ADD CONSTRAINT wallets_held_check CHECK (held >= 0 AND held <= balance);
+
+CREATE TABLE IF NOT EXISTS wallet_holds (
+    hold_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, captured, released
+    limit_usage_id BIGINT,                         -- wallet_limit_usage entry given back on release
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_holds_user_id ON wallet_holds (user_id, status);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallet_holds;
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_held_check;
+ALTER TABLE wallets DROP COLUMN IF EXISTS held;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS currencies (
+    code CHAR(3) PRIMARY KEY,                -- ISO currency code
+    name VARCHAR(50) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,   -- disabled currencies are rejected by the API
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO currencies (code, name) VALUES
+    ('USD', 'US Dollar'),
+    ('RUB', 'Russian Ruble'),
+    ('EUR', 'Euro')
+ON CONFLICT (code) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS currencies;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS exchange_receipts (
+    transaction_id UUID PRIMARY KEY,         -- idempotency key on the exchanger side
+    operation VARCHAR(20) NOT NULL,          -- exchange, close
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,          -- debited in from_currency
+    to_amount NUMERIC(20, 2) NOT NULL,       -- credited in to_currency
+    rate REAL NOT NULL,
+    executed_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,                         -- reason of the last failed delivery
+    published_at TIMESTAMP                   -- NULL until delivered
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_receipts_pending ON exchange_receipts (next_attempt_at) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS exchange_receipts;
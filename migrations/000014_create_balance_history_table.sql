@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS balance_history (
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    snapshot_date DATE NOT NULL,                -- UTC day of the snapshot
+    balance NUMERIC(20, 2) NOT NULL,            -- last balance seen that day
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, snapshot_date, currency)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS balance_history;
@@ -0,0 +1,71 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    transaction_id UUID PRIMARY KEY,
+    operation VARCHAR(20) NOT NULL,          -- opening, deposit, withdraw, exchange, close
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    entry_id BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions(transaction_id),
+    account VARCHAR(20) NOT NULL,            -- wallet, external, exchange
+    user_id UUID,                            -- owner of a wallet entry, NULL for the other accounts
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,          -- debit is positive, credit is negative
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((account = 'wallet') = (user_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_wallet ON ledger_entries (user_id, currency) WHERE account = 'wallet';
+
+-- The entries of a transaction must sum to zero in every currency. The check is deferred
+-- to commit, so all entries of a transaction can be written before it runs.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ledger_check_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM ledger_entries
+        WHERE transaction_id = NEW.transaction_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+-- Posted entries are never changed; corrections are new transactions.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION ledger_reject_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+
+-- Opening balances of the existing wallets, funded from the external account
+INSERT INTO ledger_transactions (transaction_id, operation)
+SELECT wallet_id, 'opening' FROM wallets WHERE balance <> 0;
+
+INSERT INTO ledger_entries (transaction_id, account, user_id, currency, amount)
+SELECT wallet_id, 'wallet', user_id, currency, balance FROM wallets WHERE balance <> 0
+UNION ALL
+SELECT wallet_id, 'external', NULL, currency, -balance FROM wallets WHERE balance <> 0;
+
+-- +goose Down
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP FUNCTION IF EXISTS ledger_reject_change();
+DROP FUNCTION IF EXISTS ledger_check_balanced();
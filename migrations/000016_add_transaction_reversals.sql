@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reversal_of UUID REFERENCES transactions(transaction_id); -- reversals only
+
+-- A transaction is reversed at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_reversal_of ON transactions (reversal_of);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_transactions_reversal_of;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reversal_of;
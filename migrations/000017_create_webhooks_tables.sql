@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks (
+    webhook_id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(user_id) ON DELETE CASCADE, -- NULL for admin webhooks receiving events of all users
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,                                     -- HMAC-SHA256 key of the payload signature
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    delivery_id UUID PRIMARY KEY,                 -- sent as Webhook-Id, lets receivers drop redeliveries
+    webhook_id UUID NOT NULL REFERENCES webhooks(webhook_id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL,              -- wallet.deposit, wallet.withdraw, wallet.exchange
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,                              -- reason of the last failed delivery
+    delivered_at TIMESTAMP,                       -- NULL until delivered
+    failed_at TIMESTAMP,                          -- set when the delivery is given up
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at)
+    WHERE delivered_at IS NULL AND failed_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
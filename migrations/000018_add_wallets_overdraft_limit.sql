@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS overdraft_limit NUMERIC(20, 2) NOT NULL DEFAULT 0.0; -- withdrawals may take the balance down to -overdraft_limit
+
+ALTER TABLE wallets ADD CONSTRAINT wallets_overdraft_limit_check CHECK (overdraft_limit >= 0);
+
+-- An overdrawn wallet may still carry holds placed before the overdraft
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_held_check;
+ALTER TABLE wallets ADD CONSTRAINT wallets_held_check CHECK (held >= 0 AND held <= balance + overdraft_limit);
+
+-- +goose Down
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_held_check;
+ALTER TABLE wallets ADD CONSTRAINT wallets_held_check CHECK (held >= 0 AND held <= balance);
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_overdraft_limit_check;
+ALTER TABLE wallets DROP COLUMN IF EXISTS overdraft_limit;
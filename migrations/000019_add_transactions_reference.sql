@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reference VARCHAR(128); -- client reference of deposits and withdrawals
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS reference;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS label VARCHAR(64); -- display label set by the client, e.g. "travel fund"
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb; -- client key/value metadata
+
+-- +goose Down
+ALTER TABLE wallets DROP COLUMN IF EXISTS metadata;
+ALTER TABLE wallets DROP COLUMN IF EXISTS label;
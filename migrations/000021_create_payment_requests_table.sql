@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS payment_requests (
+    request_id UUID PRIMARY KEY,
+    requester_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE, -- user asking for the money
+    payer_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,     -- user asked to pay
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL CHECK (amount > 0),
+    note VARCHAR(128),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, accepted, declined, expired
+    transaction_id UUID,                           -- transfer of an accepted request
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (payer_id <> requester_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_requester_id ON payment_requests (requester_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_payer_id ON payment_requests (payer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_pending ON payment_requests (expires_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS payment_requests;
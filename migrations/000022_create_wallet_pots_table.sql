@@ -0,0 +1,62 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS pocketed NUMERIC(20, 2) NOT NULL DEFAULT 0.0; -- set aside in pots
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS saved NUMERIC(20, 2) NOT NULL DEFAULT 0.0;    -- set aside in pots excluded from the spendable balance
+
+ALTER TABLE wallets ADD CONSTRAINT wallets_pocketed_check CHECK (saved >= 0 AND saved <= pocketed);
+
+CREATE TABLE IF NOT EXISTS wallet_pots (
+    pot_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    currency CHAR(3) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    balance NUMERIC(20, 2) NOT NULL DEFAULT 0.0 CHECK (balance >= 0), -- part of the wallet balance set aside in the pot
+    spendable BOOLEAN NOT NULL DEFAULT FALSE,                         -- whether withdrawals and exchanges may spend it
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, currency, name),
+    FOREIGN KEY (user_id, currency) REFERENCES wallets (user_id, currency) ON DELETE CASCADE
+);
+
+-- Spending is limited to balance - held - saved, so it may reach into spendable pots.
+-- When it does, the spendable pots are drawn down, newest first, until the pots fit
+-- into the wallet again.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION wallet_pots_draw_down() RETURNS TRIGGER AS $$
+DECLARE
+    excess NUMERIC(20, 2) := NEW.pocketed - GREATEST(NEW.balance - NEW.held, NEW.saved);
+BEGIN
+    IF excess <= 0 THEN
+        RETURN NULL;
+    END IF;
+
+    WITH ordered AS (
+        SELECT pot_id, balance,
+               SUM(balance) OVER (ORDER BY created_at DESC, pot_id) - balance AS ahead
+        FROM wallet_pots
+        WHERE user_id = NEW.user_id AND currency = NEW.currency AND spendable AND balance > 0
+    ),
+    drawn AS (
+        UPDATE wallet_pots p SET balance = p.balance - LEAST(o.balance, excess - o.ahead), updated_at = NOW()
+        FROM ordered o
+        WHERE p.pot_id = o.pot_id AND o.ahead < excess
+        RETURNING LEAST(o.balance, excess - o.ahead) AS amount
+    )
+    UPDATE wallets SET pocketed = pocketed - (SELECT COALESCE(SUM(amount), 0) FROM drawn)
+    WHERE wallet_id = NEW.wallet_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER wallets_pots_draw_down
+    AFTER UPDATE OF balance, held ON wallets
+    FOR EACH ROW EXECUTE FUNCTION wallet_pots_draw_down();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS wallets_pots_draw_down ON wallets;
+DROP FUNCTION IF EXISTS wallet_pots_draw_down();
+DROP TABLE IF EXISTS wallet_pots;
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_pocketed_check;
+ALTER TABLE wallets DROP COLUMN IF EXISTS saved;
+ALTER TABLE wallets DROP COLUMN IF EXISTS pocketed;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Amounts are stored with two decimal places, so a currency can have at most two
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS decimals SMALLINT NOT NULL DEFAULT 2 CHECK (decimals BETWEEN 0 AND 2); -- decimal places of amounts
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS min_amount NUMERIC(20, 2) NOT NULL DEFAULT 0.01 CHECK (min_amount > 0); -- smallest transaction amount
+
+-- +goose Down
+ALTER TABLE currencies DROP COLUMN IF EXISTS min_amount;
+ALTER TABLE currencies DROP COLUMN IF EXISTS decimals;
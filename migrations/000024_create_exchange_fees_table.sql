@@ -0,0 +1,15 @@
+-- +goose Up
+-- Pairs without a row are exchanged without a fee. Fees are posted to the ledger
+-- account 'fee'.
+CREATE TABLE IF NOT EXISTS exchange_fees (
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    percent NUMERIC(7, 4) NOT NULL DEFAULT 0 CHECK (percent >= 0 AND percent < 100), -- percentage of the amount
+    fixed NUMERIC(20, 2) NOT NULL DEFAULT 0 CHECK (fixed >= 0),                       -- fixed fee in from_currency
+    spread NUMERIC(7, 4) NOT NULL DEFAULT 0 CHECK (spread >= 0 AND spread < 100),    -- percentage the rate is lowered by
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (from_currency, to_currency)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS exchange_fees;
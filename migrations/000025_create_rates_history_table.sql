@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rates_history (
+    rate_id BIGSERIAL PRIMARY KEY,
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate REAL NOT NULL CHECK (rate > 0),             -- rate fetched from the exchanger
+    fetched_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rates_history_pair ON rates_history (from_currency, to_currency, fetched_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS rates_history;
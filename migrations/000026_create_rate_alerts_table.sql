@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS rate_alerts (
+    alert_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    direction VARCHAR(5) NOT NULL CHECK (direction IN ('above', 'below')),
+    threshold REAL NOT NULL CHECK (threshold > 0),
+    triggered_at TIMESTAMP,                          -- when the rate crossed the threshold, NULL while armed
+    triggered_rate REAL,                             -- rate that crossed the threshold
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_alerts_user ON rate_alerts (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_rate_alerts_armed ON rate_alerts (from_currency, to_currency) WHERE triggered_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS rate_alerts;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS rate REAL;                  -- rate applied, exchanges only
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee NUMERIC(20, 2);         -- fee in the source currency, exchanges only
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS balance NUMERIC(20, 2);     -- source wallet balance after the exchange
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS to_balance NUMERIC(20, 2);  -- target wallet balance after the exchange
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_operation ON transactions (user_id, operation, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_transactions_user_operation;
+ALTER TABLE transactions DROP COLUMN IF EXISTS to_balance;
+ALTER TABLE transactions DROP COLUMN IF EXISTS balance;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee;
+ALTER TABLE transactions DROP COLUMN IF EXISTS rate;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS processed_messages (
+    consumer VARCHAR(64) NOT NULL,                   -- consumer that processed the message
+    message_key VARCHAR(255) NOT NULL,               -- Kafka message key, unique per consumer
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (consumer, message_key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS processed_messages;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS dead_letter_events (
+    id UUID PRIMARY KEY,
+    message_key VARCHAR(255) NOT NULL,       -- Kafka message key, the transaction ID
+    payload TEXT NOT NULL,                   -- JSON event as it would have been published
+    attempts INT NOT NULL,                   -- failed publishing attempts
+    last_error TEXT NOT NULL,                -- reason of the last failed attempt
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    replayed_at TIMESTAMP                    -- NULL until published by an admin
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letter_events_pending ON dead_letter_events (created_at) WHERE replayed_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS dead_letter_events;
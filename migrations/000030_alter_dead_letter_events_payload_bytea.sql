@@ -0,0 +1,6 @@
+-- +goose Up
+-- Avro encoded events are binary
+ALTER TABLE dead_letter_events ALTER COLUMN payload TYPE BYTEA USING convert_to(payload, 'UTF8');
+
+-- +goose Down
+ALTER TABLE dead_letter_events ALTER COLUMN payload TYPE TEXT USING convert_from(payload, 'UTF8');
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Transaction events are routed to several topics. Events dead-lettered before were
+-- published to the writer's topic, by default large-transactions
+ALTER TABLE dead_letter_events ADD COLUMN topic TEXT NOT NULL DEFAULT 'large-transactions';
+ALTER TABLE dead_letter_events ALTER COLUMN topic DROP DEFAULT;
+
+-- +goose Down
+ALTER TABLE dead_letter_events DROP COLUMN topic;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Transaction events are keyed by the user and deduplicated by the event ID header.
+-- Events dead-lettered before were keyed by the transaction ID and have no event ID
+ALTER TABLE dead_letter_events ADD COLUMN event_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE dead_letter_events DROP COLUMN event_id;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- ID of the request that made the change, to correlate audit records with logs and events.
+-- Records made outside of a request, e.g. by jobs, have none
+ALTER TABLE audit_log ADD COLUMN request_id TEXT;
+
+-- +goose Down
+ALTER TABLE audit_log DROP COLUMN request_id;
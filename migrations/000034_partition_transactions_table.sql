@@ -0,0 +1,118 @@
+-- +goose Up
+-- The history is partitioned by month of created_at, so queries bounded in time scan only
+-- the months they cover and old months are archived by dropping their partitions.
+-- Uniqueness on a partitioned table must include created_at, so the single reversal of a
+-- transaction is guarded by transaction_reversals instead of a unique index.
+CREATE TABLE IF NOT EXISTS transaction_reversals (
+    reversal_of UUID PRIMARY KEY,            -- reversed transaction
+    transaction_id UUID NOT NULL UNIQUE,     -- the reversal
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO transaction_reversals (reversal_of, transaction_id, created_at)
+SELECT reversal_of, transaction_id, created_at FROM transactions WHERE reversal_of IS NOT NULL;
+
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+ALTER TABLE transactions_unpartitioned RENAME CONSTRAINT transactions_pkey TO transactions_unpartitioned_pkey;
+ALTER SEQUENCE transactions_id_seq RENAME TO transactions_unpartitioned_id_seq;
+DROP INDEX IF EXISTS idx_transactions_user_id;
+DROP INDEX IF EXISTS idx_transactions_user_operation;
+DROP INDEX IF EXISTS idx_transactions_reversal_of;
+
+CREATE TABLE transactions (
+    id BIGSERIAL NOT NULL,                   -- cursor for pagination
+    transaction_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    operation VARCHAR(20) NOT NULL,
+    currency CHAR(3) NOT NULL,               -- source currency for exchanges
+    amount NUMERIC(20, 2) NOT NULL,
+    to_currency CHAR(3),                     -- exchanges and payouts on closure
+    to_amount NUMERIC(20, 2),                -- exchanges and payouts on closure
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reversal_of UUID,                        -- reversals only
+    reference VARCHAR(128),                  -- client reference of deposits and withdrawals
+    rate REAL,                               -- rate applied, exchanges only
+    fee NUMERIC(20, 2),                      -- fee in the source currency, exchanges only
+    balance NUMERIC(20, 2),                  -- source wallet balance after the operation
+    to_balance NUMERIC(20, 2),               -- target wallet balance after the operation
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_operation ON transactions (user_id, operation, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_transaction_id ON transactions (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_reversal_of ON transactions (reversal_of);
+
+-- Catches rows outside of the monthly partitions, which the transaction-partitions job
+-- creates ahead of time
+CREATE TABLE IF NOT EXISTS transactions_default PARTITION OF transactions DEFAULT;
+
+-- Partitions of the months with existing rows up to the next month
+-- +goose StatementBegin
+DO $$
+DECLARE
+    month DATE := date_trunc('month', COALESCE((SELECT MIN(created_at) FROM transactions_unpartitioned), NOW()));
+BEGIN
+    WHILE month <= date_trunc('month', NOW()) + INTERVAL '1 month' LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF transactions FOR VALUES FROM (%L) TO (%L)',
+            'transactions_' || to_char(month, 'YYYY_MM'), month, (month + INTERVAL '1 month')::DATE);
+        month := month + INTERVAL '1 month';
+    END LOOP;
+END;
+$$;
+-- +goose StatementEnd
+
+INSERT INTO transactions (id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, created_at,
+    reversal_of, reference, rate, fee, balance, to_balance)
+SELECT id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, created_at,
+    reversal_of, reference, rate, fee, balance, to_balance
+FROM transactions_unpartitioned;
+
+SELECT setval('transactions_id_seq', COALESCE((SELECT MAX(id) FROM transactions), 0) + 1, false);
+
+DROP TABLE transactions_unpartitioned;
+
+-- +goose Down
+ALTER TABLE transactions RENAME TO transactions_partitioned;
+ALTER TABLE transactions_partitioned RENAME CONSTRAINT transactions_pkey TO transactions_partitioned_pkey;
+ALTER SEQUENCE transactions_id_seq RENAME TO transactions_partitioned_id_seq;
+DROP INDEX IF EXISTS idx_transactions_user_id;
+DROP INDEX IF EXISTS idx_transactions_user_operation;
+DROP INDEX IF EXISTS idx_transactions_transaction_id;
+DROP INDEX IF EXISTS idx_transactions_reversal_of;
+
+CREATE TABLE transactions (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    operation VARCHAR(20) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,
+    to_currency CHAR(3),
+    to_amount NUMERIC(20, 2),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reversal_of UUID,
+    reference VARCHAR(128),
+    rate REAL,
+    fee NUMERIC(20, 2),
+    balance NUMERIC(20, 2),
+    to_balance NUMERIC(20, 2)
+);
+
+INSERT INTO transactions (id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, created_at,
+    reversal_of, reference, rate, fee, balance, to_balance)
+SELECT id, transaction_id, user_id, operation, currency, amount, to_currency, to_amount, created_at,
+    reversal_of, reference, rate, fee, balance, to_balance
+FROM transactions_partitioned;
+
+SELECT setval('transactions_id_seq', COALESCE((SELECT MAX(id) FROM transactions), 0) + 1, false);
+
+-- Reversals of archived transactions have no original any more
+ALTER TABLE transactions ADD CONSTRAINT transactions_reversal_of_fkey
+    FOREIGN KEY (reversal_of) REFERENCES transactions(transaction_id) NOT VALID;
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_operation ON transactions (user_id, operation, id DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_reversal_of ON transactions (reversal_of);
+
+DROP TABLE transactions_partitioned;
+DROP TABLE IF EXISTS transaction_reversals;
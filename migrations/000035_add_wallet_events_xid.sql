@@ -0,0 +1,15 @@
+-- +goose Up
+-- ID of the transaction that inserted the event. An event_id is allocated when the event is
+-- inserted but becomes visible only at commit, so the balance projection follows the events
+-- in transaction order and applies only those of transactions older than every running one.
+-- Existing events are all committed and get 0.
+ALTER TABLE wallet_events ADD COLUMN xid XID8 NOT NULL DEFAULT '0';
+ALTER TABLE wallet_events ALTER COLUMN xid SET DEFAULT pg_current_xact_id();
+CREATE INDEX IF NOT EXISTS idx_wallet_events_xid_event_id ON wallet_events (xid, event_id);
+
+ALTER TABLE projection_checkpoints ADD COLUMN last_xid XID8 NOT NULL DEFAULT '0';
+
+-- +goose Down
+ALTER TABLE projection_checkpoints DROP COLUMN last_xid;
+DROP INDEX IF EXISTS idx_wallet_events_xid_event_id;
+ALTER TABLE wallet_events DROP COLUMN xid;
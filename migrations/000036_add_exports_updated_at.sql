@@ -0,0 +1,8 @@
+-- +goose Up
+-- Time of the last status change. An export left in processing by a worker that stopped
+-- mid-run is claimed again once this is older than the processing timeout.
+ALTER TABLE exports ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE exports SET updated_at = COALESCE(completed_at, created_at);
+
+-- +goose Down
+ALTER TABLE exports DROP COLUMN updated_at;
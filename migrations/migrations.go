@@ -0,0 +1,10 @@
+// Package migrations embeds the goose SQL migrations, so the service can compare
+// the live database schema against them and integration tests can apply them.
+package migrations
+
+import "embed"
+
+// Files holds the SQL migration files.
+//
+//go:embed *.sql
+var Files embed.FS